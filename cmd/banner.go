@@ -2,32 +2,49 @@ package cmd
 
 import (
 	"github.com/inovacc/omni/internal/cli/banner"
+	"github.com/inovacc/omni/pkg/figlet"
 	"github.com/spf13/cobra"
 )
 
 var bannerCmd = &cobra.Command{
-	Use:   "banner [TEXT]",
-	Short: "Generate ASCII art text banners",
+	Use:     "banner [TEXT]",
+	Aliases: []string{"figlet"},
+	Short:   "Generate ASCII art text banners",
 	Long: `Generate FIGlet-style ASCII art text banners.
 
-Supports multiple fonts and reads text from arguments or stdin.
+Supports multiple fonts and reads text from arguments or stdin. The
+banner can also be rendered as an SVG (vector) or PNG image for embedding
+in generated docs and dashboards; the format follows the --output
+extension unless --format is given.
 
-  -f, --font=NAME   font name (default "standard")
-  -w, --width=N     max output width (0 = unlimited)
-  -l, --list        list available fonts
+  -f, --font=NAME         font name (default "standard")
+  -w, --width=N           max output width (0 = unlimited)
+  -l, --list              list available fonts
+  -o, --output=FILE       write to FILE instead of stdout
+      --format=FMT        text, svg or png
+      --font-size=PX      character cell height for svg/png (default 16)
+      --color=COLOR       text color, #rrggbb or a name (default black)
+      --background=COLOR  background color (default transparent)
 
 Examples:
   omni banner "Hello World"
   omni banner -f slant "omni"
   omni banner -f small "test"
   omni banner --list
-  echo "piped" | omni banner`,
+  echo "piped" | omni banner
+  omni figlet --output banner.svg "RELEASE"
+  omni banner -o banner.png --font-size 24 --color '#0366d6' --background white "v1.2"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := banner.Options{}
 
 		opts.Font, _ = cmd.Flags().GetString("font")
 		opts.Width, _ = cmd.Flags().GetInt("width")
 		opts.List, _ = cmd.Flags().GetBool("list")
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.Format, _ = cmd.Flags().GetString("format")
+		opts.FontSize, _ = cmd.Flags().GetInt("font-size")
+		opts.Color, _ = cmd.Flags().GetString("color")
+		opts.Background, _ = cmd.Flags().GetString("background")
 
 		return banner.RunBanner(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
 	},
@@ -37,6 +54,14 @@ func init() {
 	rootCmd.AddCommand(bannerCmd)
 
 	bannerCmd.Flags().StringP("font", "f", "standard", "font name")
+	_ = bannerCmd.RegisterFlagCompletionFunc("font", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return figlet.ListFonts(), cobra.ShellCompDirectiveNoFileComp
+	})
 	bannerCmd.Flags().IntP("width", "w", 0, "max output width (0 = unlimited)")
 	bannerCmd.Flags().BoolP("list", "l", false, "list available fonts")
+	bannerCmd.Flags().StringP("output", "o", "", "write to file instead of stdout")
+	bannerCmd.Flags().String("format", "", "output format: text, svg or png (default: from --output extension)")
+	bannerCmd.Flags().Int("font-size", 16, "character cell height in pixels for svg/png")
+	bannerCmd.Flags().String("color", "", "text color for svg/png (#rrggbb or name)")
+	bannerCmd.Flags().String("background", "", "background color for svg/png (default transparent)")
 }
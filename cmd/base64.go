@@ -16,18 +16,21 @@ With no FILE, or when FILE is -, read standard input.
   -d, --decode    decode data
   -w, --wrap=N    wrap encoded lines after N characters (default 76, 0 = no wrap)
   -i, --ignore-garbage  ignore non-alphabet characters when decoding
+      --mime      stream in 76-column lines with CRLF endings (RFC 2045)
 
 Examples:
   echo "hello" | omni base64           # encode
   echo "aGVsbG8K" | omni base64 -d     # decode
   omni base64 file.bin                 # encode file
-  omni base64 -d encoded.txt           # decode file`,
+  omni base64 -d encoded.txt           # decode file
+  omni base64 --mime attachment.pdf    # MIME body part`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := base.BaseOptions{}
 
 		opts.Decode, _ = cmd.Flags().GetBool("decode")
 		opts.Wrap, _ = cmd.Flags().GetInt("wrap")
 		opts.IgnoreGarbage, _ = cmd.Flags().GetBool("ignore-garbage")
+		opts.MIME, _ = cmd.Flags().GetBool("mime")
 
 		return base.RunBase64(cmd.OutOrStdout(), args, opts)
 	},
@@ -39,4 +42,5 @@ func init() {
 	base64Cmd.Flags().BoolP("decode", "d", false, "decode data")
 	base64Cmd.Flags().IntP("wrap", "w", 76, "wrap encoded lines after N characters (0 = no wrap)")
 	base64Cmd.Flags().BoolP("ignore-garbage", "i", false, "ignore non-alphabet characters when decoding")
+	base64Cmd.Flags().Bool("mime", false, "stream in 76-column lines with CRLF endings (RFC 2045)")
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/inovacc/omni/internal/cli/bench"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
+	"github.com/inovacc/omni/internal/cli/pipe"
+	"github.com/spf13/cobra"
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench [OPTION]... -- COMMAND [ARG]...",
+	Short: "Micro-benchmark an omni command over in-memory input",
+	Long: `Run an omni subcommand in-process N times over the same input, buffered
+in memory, and report min/mean/p95/max latency and throughput. The
+command's output is counted and discarded.
+
+Input is read once from --input, or from standard input when it is a
+pipe or file. A silent non-zero exit (grep finding no match) counts as a
+completed run; any other error stops the benchmark.
+
+With --baseline the result is compared with a previous --save; a mean
+slower than --max-regress percent fails with exit code 1, for tracking
+regressions of pipeline and rg changes in CI.
+
+  -n, --iterations N      measured runs (default 10)
+  -w, --warmup N          unmeasured runs first (default 1)
+  -i, --input FILE        buffer FILE as the command's stdin ("-" = stdin)
+  -b, --baseline FILE     compare with a saved result
+      --save FILE         write this result as a baseline
+      --max-regress PCT   fail when the mean is PCT percent slower than the baseline
+  --json                  print the result as JSON
+
+Flags after COMMAND belong to it; put -- before COMMAND.
+
+Examples:
+  omni bench -i app.log -- pipeline 'grep ERROR' 'sort' 'uniq -c'
+  omni bench -n 50 -- rg -c TODO src/
+  omni bench -i big.csv --save base.json -- csv count
+  omni bench -i big.csv -b base.json --max-regress 10 -- csv count`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := bench.Options{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		opts.Iterations, _ = cmd.Flags().GetInt("iterations")
+		opts.Warmup, _ = cmd.Flags().GetInt("warmup")
+		opts.Input, _ = cmd.Flags().GetString("input")
+		opts.Baseline, _ = cmd.Flags().GetString("baseline")
+		opts.Save, _ = cmd.Flags().GetString("save")
+		opts.MaxRegress, _ = flagvalue.GetPercent(cmd.Flags(), "max-regress")
+
+		if opts.Input == "" && stdinIsData(cmd.InOrStdin()) {
+			opts.Input = "-"
+		}
+
+		// The prepared command reads and writes through these streams,
+		// swapped for every iteration.
+		in := &swapReader{}
+		out := &swapWriter{}
+
+		run, err := prepareSubcommand("bench", args, pipe.Stdio{In: in, Out: out, Err: cmd.ErrOrStderr()})
+		if err != nil {
+			return err
+		}
+
+		runFunc := func(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+			in.r, out.w = stdin, stdout
+			return run(ctx)
+		}
+
+		return bench.Run(cmd.Context(), cmd.OutOrStdout(), cmd.InOrStdin(), args, runFunc, opts)
+	},
+}
+
+// stdinIsData reports whether r is a pipe or regular file rather than a
+// terminal, so bench does not block waiting for keyboard input.
+func stdinIsData(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+type swapReader struct{ r io.Reader }
+
+func (s *swapReader) Read(p []byte) (int, error) { return s.r.Read(p) }
+
+type swapWriter struct{ w io.Writer }
+
+func (s *swapWriter) Write(p []byte) (int, error) { return s.w.Write(p) }
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().IntP("iterations", "n", 10, "measured runs")
+	benchCmd.Flags().IntP("warmup", "w", 1, "unmeasured runs first")
+	benchCmd.Flags().StringP("input", "i", "", "buffer FILE as the command's stdin (\"-\" = stdin)")
+	benchCmd.Flags().StringP("baseline", "b", "", "compare with a saved result")
+	benchCmd.Flags().String("save", "", "write this result as a baseline")
+	flagvalue.PercentP(benchCmd.Flags(), "max-regress", "", 0, "fail when the mean is PCT percent slower than the baseline")
+	// Everything after COMMAND belongs to it, not to bench
+	benchCmd.Flags().SetInterspersed(false)
+}
@@ -8,18 +8,23 @@ import (
 // brdocCmd represents the brdoc command
 var brdocCmd = &cobra.Command{
 	Use:   "brdoc",
-	Short: "Brazilian document utilities (CPF, CNPJ)",
+	Short: "Brazilian document utilities (CPF, CNPJ, CEP)",
 	Long: `Brazilian document validation, generation, and formatting.
 
 Subcommands:
   cpf     CPF (Cadastro de Pessoas Físicas) operations
   cnpj    CNPJ (Cadastro Nacional de Pessoa Jurídica) operations
+  cep     CEP (Código de Endereçamento Postal) validation and lookup
+
+Text output and errors are available in Portuguese with --lang pt-BR, or
+when LANG is pt_BR.
 
 Examples:
   omni brdoc cpf --generate           # generate a valid CPF
   omni brdoc cpf --validate 123.456.789-09
   omni brdoc cnpj --generate          # generate alphanumeric CNPJ
-  omni brdoc cnpj --generate --legacy # generate numeric-only CNPJ`,
+  omni brdoc cnpj --generate --legacy # generate numeric-only CNPJ
+  omni brdoc cep --lookup 01310-100   # resolve a postal code`,
 }
 
 // cpfCmd represents the cpf subcommand
@@ -33,11 +38,13 @@ Flags:
   -v, --validate    Validate CPF(s)
   -f, --format      Format CPF(s) as XXX.XXX.XXX-XX
   -n, --count       Number of CPFs to generate (default 1)
+  -s, --state       Generate CPF(s) for a state's fiscal region (e.g. SP)
   --json            Output as JSON
 
 Examples:
   omni brdoc cpf --generate              # generate one CPF
   omni brdoc cpf --generate -n 5         # generate 5 CPFs
+  omni brdoc cpf --generate --state SP   # ninth digit 8 (São Paulo)
   omni brdoc cpf --validate 12345678909
   omni brdoc cpf --validate 123.456.789-09
   omni brdoc cpf --format 12345678909
@@ -49,6 +56,7 @@ Examples:
 		opts.Validate, _ = cmd.Flags().GetBool("validate")
 		opts.Format, _ = cmd.Flags().GetBool("format")
 		opts.Count, _ = cmd.Flags().GetInt("count")
+		opts.State, _ = cmd.Flags().GetString("state")
 		opts.JSON, _ = cmd.Flags().GetBool("json")
 
 		return brdoc.RunCPF(cmd.OutOrStdout(), args, opts)
@@ -93,18 +101,57 @@ Examples:
 	},
 }
 
+// cepCmd represents the cep subcommand
+var cepCmd = &cobra.Command{
+	Use:   "cep CEP...",
+	Short: "CEP operations (validate, format, lookup)",
+	Long: `CEP (Código de Endereçamento Postal) operations.
+
+Validates CEPs by default. With --lookup, queries a ViaCEP-compatible
+provider and prints street, neighborhood, city and state.
+
+Flags:
+  -l, --lookup      Look up the address for CEP(s) (requires network)
+  -f, --format      Format CEP(s) as XXXXX-XXX
+  --provider-url    ViaCEP-compatible endpoint ({cep} is replaced)
+  --json            Output as JSON
+
+Examples:
+  omni brdoc cep 01310-100                  # validate
+  omni brdoc cep --format 01310100          # 01310-100
+  omni brdoc cep --lookup 01310-100 --json  # street/city/state as JSON`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := brdoc.CEPOptions{}
+
+		opts.Lookup, _ = cmd.Flags().GetBool("lookup")
+		opts.Format, _ = cmd.Flags().GetBool("format")
+		opts.JSON, _ = cmd.Flags().GetBool("json")
+
+		if u, _ := cmd.Flags().GetString("provider-url"); u != "" {
+			p := brdoc.NewViaCEPProvider()
+			p.URL = u
+			opts.Provider = p
+		}
+
+		return brdoc.RunCEP(cmd.Context(), cmd.OutOrStdout(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(brdocCmd)
 
 	// Add subcommands
 	brdocCmd.AddCommand(cpfCmd)
 	brdocCmd.AddCommand(cnpjCmd)
+	brdocCmd.AddCommand(cepCmd)
 
 	// CPF flags
 	cpfCmd.Flags().BoolP("generate", "g", false, "generate valid CPF(s)")
 	cpfCmd.Flags().BoolP("validate", "v", false, "validate CPF(s)")
 	cpfCmd.Flags().BoolP("format", "f", false, "format CPF(s)")
 	cpfCmd.Flags().IntP("count", "n", 1, "number of CPFs to generate")
+	cpfCmd.Flags().StringP("state", "s", "", "generate CPFs for this state's fiscal region (UF, e.g. SP)")
 	cpfCmd.Flags().Bool("json", false, "output as JSON")
 
 	// CNPJ flags
@@ -114,4 +161,10 @@ func init() {
 	cnpjCmd.Flags().IntP("count", "n", 1, "number of CNPJs to generate")
 	cnpjCmd.Flags().BoolP("legacy", "l", false, "generate numeric-only CNPJ")
 	cnpjCmd.Flags().Bool("json", false, "output as JSON")
+
+	// CEP flags
+	cepCmd.Flags().BoolP("lookup", "l", false, "look up the address for CEP(s)")
+	cepCmd.Flags().BoolP("format", "f", false, "format CEP(s)")
+	cepCmd.Flags().String("provider-url", "", "ViaCEP-compatible endpoint template ({cep} is replaced)")
+	cepCmd.Flags().Bool("json", false, "output as JSON")
 }
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/inovacc/omni/internal/cli/buf"
+	"github.com/inovacc/omni/internal/cli/doctor"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var bufCmd = &cobra.Command{
@@ -28,6 +32,33 @@ Examples:
   omni buf mod init buf.build/org/repo`,
 }
 
+// subscribeBufProgress reports compile and plugin progress on stderr when it
+// is a terminal, so large workspaces don't look frozen. The returned function
+// unsubscribes.
+func subscribeBufProgress(cmd *cobra.Command) func() {
+	f, ok := cmd.ErrOrStderr().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return func() {}
+	}
+
+	return buf.Subscribe(buf.ProgressListener(f))
+}
+
+// bufImageCacheDir returns the image cache directory, or "" when --no-cache
+// is set or no user cache directory is available.
+func bufImageCacheDir(cmd *cobra.Command) string {
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		return ""
+	}
+
+	dir, err := buf.DefaultImageCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return dir
+}
+
 var bufLintCmd = &cobra.Command{
 	Use:   "lint [DIR]",
 	Short: "Lint proto files",
@@ -106,6 +137,10 @@ Flags:
   -o, --output=FILE      Output file (.bin or .json)
   --exclude-path=PATH    Paths to exclude
   --error-format=FORMAT  Output format: text, json, github-actions
+  --no-cache             Always recompile instead of reusing a cached image
+
+Compiled images are cached in the omni cache directory, keyed by a hash of
+every .proto file in the workspace, so unchanged workspaces are not rebuilt.
 
 Examples:
   omni buf compile
@@ -122,6 +157,9 @@ Examples:
 		opts.Output, _ = cmd.Flags().GetString("output")
 		opts.ExcludePath, _ = cmd.Flags().GetStringSlice("exclude-path")
 		opts.ErrorFormat, _ = cmd.Flags().GetString("error-format")
+		opts.CacheDir = bufImageCacheDir(cmd)
+
+		defer subscribeBufProgress(cmd)()
 
 		return buf.RunBuild(cmd.OutOrStdout(), dir, opts)
 	},
@@ -137,6 +175,7 @@ Flags:
   --exclude-path=PATH    Paths to exclude
   --exclude-imports      Don't check imported files
   --error-format=FORMAT  Output format: text, json, github-actions
+  --no-cache             Always recompile instead of reusing cached images
 
 Breaking change rules:
   FILE_NO_DELETE      Files cannot be deleted
@@ -162,6 +201,9 @@ Examples:
 		opts.ExcludePath, _ = cmd.Flags().GetStringSlice("exclude-path")
 		opts.ExcludeImports, _ = cmd.Flags().GetBool("exclude-imports")
 		opts.ErrorFormat, _ = cmd.Flags().GetString("error-format")
+		opts.CacheDir = bufImageCacheDir(cmd)
+
+		defer subscribeBufProgress(cmd)()
 
 		return buf.RunBreaking(cmd.OutOrStdout(), dir, opts)
 	},
@@ -201,6 +243,8 @@ Examples:
 		opts.Output, _ = cmd.Flags().GetString("output")
 		opts.IncludeImports, _ = cmd.Flags().GetBool("include-imports")
 
+		defer subscribeBufProgress(cmd)()
+
 		return buf.RunGenerate(cmd.OutOrStdout(), dir, opts)
 	},
 }
@@ -282,6 +326,8 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(bufCmd)
+	doctor.RegisterCacheDir("buf-images", buf.DefaultImageCacheDir)
+	doctor.RegisterEndpoint("buf-registry", "buf.build:443")
 
 	// Add subcommands
 	bufCmd.AddCommand(bufLintCmd)
@@ -310,12 +356,14 @@ func init() {
 	bufCompileCmd.Flags().StringP("output", "o", "", "output file path")
 	bufCompileCmd.Flags().StringSlice("exclude-path", nil, "paths to exclude")
 	bufCompileCmd.Flags().String("error-format", "text", "output format (text, json, github-actions)")
+	bufCompileCmd.Flags().Bool("no-cache", false, "always recompile instead of reusing a cached image")
 
 	// buf breaking flags
 	bufBreakingCmd.Flags().String("against", "", "source to compare against (required)")
 	bufBreakingCmd.Flags().StringSlice("exclude-path", nil, "paths to exclude")
 	bufBreakingCmd.Flags().Bool("exclude-imports", false, "don't check imported files")
 	bufBreakingCmd.Flags().String("error-format", "text", "output format (text, json, github-actions)")
+	bufBreakingCmd.Flags().Bool("no-cache", false, "always recompile instead of reusing cached images")
 
 	// buf generate flags
 	bufGenerateCmd.Flags().String("template", "", "alternate buf.gen.yaml location")
@@ -12,12 +12,31 @@ var catCmd = &cobra.Command{
 	Long: `Concatenate FILE(s) to standard output.
 With no FILE, or when FILE is -, read standard input.
 
+  -A, --show-all           equivalent to -vET
+  -b, --number-nonblank    number nonempty output lines, overrides -n
+  -e                       equivalent to -vE
+  -E, --show-ends          display $ at end of each line
+  -n, --number             number all output lines
+  -s, --squeeze-blank      suppress repeated empty output lines
+  -t                       equivalent to -vT
+  -T, --show-tabs          display TAB characters as ^I
+  -v, --show-nonprinting   use ^ and M- notation, except for LFD and TAB
+      --tee FILE           also write the output to FILE
+      --json               output as JSON array of lines
+
+Input is streamed, so long lines and large files are not held in memory;
+line numbers continue across files. Unreadable files are reported and
+skipped, and the exit status is then 1.
+
 Examples:
   omni cat file.txt                 # print a file
   omni cat a.txt b.txt              # concatenate files
   omni cat -n file.txt              # number all lines
   omni cat -b file.txt              # number non-blank lines
-  echo hello | omni cat             # read from stdin`,
+  omni cat -A script.sh             # reveal tabs, line ends and control bytes
+  omni cat -s log.txt               # squeeze runs of blank lines
+  echo hello | omni cat             # read from stdin
+  omni cat --tee notes.txt          # type text, save it and echo it back`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := cat.CatOptions{}
 
@@ -48,6 +67,7 @@ Examples:
 		}
 
 		opts.JSON, _ = cmd.Flags().GetBool("json")
+		opts.Tee, _ = cmd.Flags().GetString("tee")
 
 		return cat.RunCat(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
 	},
@@ -66,4 +86,5 @@ func init() {
 	catCmd.Flags().BoolP("e", "e", false, "equivalent to -vE")
 	catCmd.Flags().BoolP("t", "t", false, "equivalent to -vT")
 	catCmd.Flags().Bool("json", false, "output as JSON array of lines")
+	catCmd.Flags().String("tee", "", "also write the output to FILE")
 }
@@ -12,13 +12,22 @@ var chmodCmd = &cobra.Command{
 	Long: `Change the mode of each FILE to MODE.
 
 MODE can be:
-  - Octal number (e.g., 755, 644)
-  - Symbolic mode (e.g., u+x, go-w, a=rw)
+  - Octal number (e.g., 755, 644, 4755)
+  - Symbolic mode (e.g., u+x, go-w, a=rw, u=rw,go=r)
 
-Symbolic mode format: [ugoa][+-=][rwx]
+Symbolic mode format: [ugoa]*([-+=]([rwxXst]*|[ugo]))+ joined by commas
   u = user, g = group, o = others, a = all
+  with no letter, all classes minus the bits set in the umask (so with
+  umask 022, +w adds only the owner's write bit)
   + = add, - = remove, = = set exactly
   r = read, w = write, x = execute
+  X = execute only for directories and already-executable files
+  s = set user/group ID, t = sticky (restricted deletion)
+  u, g, o after the operator copy that class's permissions (g=u)
+
+With -R, symbolic links met during the walk are not followed. On Windows
+only the owner write bit has an effect: it clears or sets the read-only
+attribute.
 
 Options:
   -R, --recursive  change files and directories recursively
@@ -30,7 +39,9 @@ Options:
 Examples:
   omni chmod 755 script.sh        # set octal mode
   omni chmod u+x script.sh        # add execute for the owner
-  omni chmod -R go-w dir/         # recursively remove group/other write`,
+  omni chmod -R go-w dir/         # recursively remove group/other write
+  omni chmod -R a+rX public/      # readable tree, executable dirs only
+  omni chmod u=rw,go=r notes.txt  # several clauses at once`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := chmod.ChmodOptions{}
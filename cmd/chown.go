@@ -26,11 +26,18 @@ Options:
   -h, --no-dereference  affect symbolic links instead of referenced file
       --reference   use RFILE's owner and group
       --preserve-root  fail to operate recursively on '/'
+      --from OWNER[:GROUP]  change only files currently owned by OWNER/GROUP
+
+With -R, symbolic links met during the walk are changed themselves rather
+than their targets. Ownership is a Unix concept: on Windows chown fails
+with an "unsupported" error (use icacls or takeown instead).
 
 Examples:
   omni chown root file.txt        # change the owner
   omni chown root:staff file.txt  # change owner and group
-  omni chown -R app:app /srv/app  # change recursively`,
+  omni chown -R app:app /srv/app  # change recursively
+  omni chown app: file.txt        # owner app, group app's login group
+  omni chown --from root app f    # only if currently owned by root`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := chown.ChownOptions{}
@@ -42,6 +49,7 @@ Examples:
 		opts.NoDereference, _ = cmd.Flags().GetBool("no-dereference")
 		opts.Reference, _ = cmd.Flags().GetString("reference")
 		opts.PreserveRoot, _ = cmd.Flags().GetBool("preserve-root")
+		opts.From, _ = cmd.Flags().GetString("from")
 
 		return chown.RunChown(cmd.OutOrStdout(), args, opts)
 	},
@@ -55,6 +63,9 @@ func init() {
 	chownCmd.Flags().BoolP("changes", "c", false, "like verbose but report only when a change is made")
 	chownCmd.Flags().BoolP("silent", "f", false, "suppress most error messages")
 	chownCmd.Flags().BoolP("no-dereference", "h", false, "affect symbolic links instead of referenced file")
+	// -h belongs to --no-dereference as in GNU chown, so help is --help only
+	chownCmd.Flags().Bool("help", false, "help for chown")
 	chownCmd.Flags().String("reference", "", "use RFILE's owner and group")
 	chownCmd.Flags().Bool("preserve-root", false, "fail to operate recursively on '/'")
+	chownCmd.Flags().String("from", "", "change only if current owner and/or group match OWNER[:GROUP]")
 }
@@ -11,6 +11,8 @@ var (
 	commSuppress3    bool
 	commCheckOrder   bool
 	commNoCheckOrder bool
+	commIgnoreCase   bool
+	commTotal        bool
 	commOutputDelim  string
 	commZeroTerm     bool
 )
@@ -28,15 +30,22 @@ With no options, produce three-column output:
   -1                 suppress column 1 (lines unique to FILE1)
   -2                 suppress column 2 (lines unique to FILE2)
   -3                 suppress column 3 (lines common to both)
-  --check-order      check that input is correctly sorted
+  -i, --ignore-case  compare lines without regard to case
+  --check-order      fail as soon as unsorted input is found
   --nocheck-order    do not check input order
   --output-delimiter use STR as output delimiter
+  --total            print a summary line with the counts of each column
   -z, --zero-terminated  line delimiter is NUL
 
+Both files are read in a single merge pass, so memory use stays constant.
+Unsorted input is reported on standard error and makes comm exit with
+status 1 after the output is written.
+
 Examples:
   omni comm file1.txt file2.txt        # show all columns
   omni comm -12 file1.txt file2.txt    # show only common lines
-  omni comm -3 file1.txt file2.txt     # show only unique lines`,
+  omni comm -3 file1.txt file2.txt     # show only unique lines
+  omni comm -i --total a.txt b.txt     # case-insensitive, with counts`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := comm.CommOptions{
@@ -45,6 +54,8 @@ Examples:
 			Suppress3:    commSuppress3,
 			CheckOrder:   commCheckOrder,
 			NoCheckOrder: commNoCheckOrder,
+			IgnoreCase:   commIgnoreCase,
+			Total:        commTotal,
 			OutputDelim:  commOutputDelim,
 			ZeroTerm:     commZeroTerm,
 			OutputFormat: getOutputOpts(cmd).GetFormat(),
@@ -60,8 +71,10 @@ func init() {
 	commCmd.Flags().BoolVarP(&commSuppress1, "1", "1", false, "suppress column 1")
 	commCmd.Flags().BoolVarP(&commSuppress2, "2", "2", false, "suppress column 2")
 	commCmd.Flags().BoolVarP(&commSuppress3, "3", "3", false, "suppress column 3")
+	commCmd.Flags().BoolVarP(&commIgnoreCase, "ignore-case", "i", false, "compare lines without regard to case")
 	commCmd.Flags().BoolVar(&commCheckOrder, "check-order", false, "check input is sorted")
 	commCmd.Flags().BoolVar(&commNoCheckOrder, "nocheck-order", false, "do not check input order")
+	commCmd.Flags().BoolVar(&commTotal, "total", false, "print a summary line with the column counts")
 	commCmd.Flags().StringVar(&commOutputDelim, "output-delimiter", "", "use STR as output delimiter")
 	commCmd.Flags().BoolVarP(&commZeroTerm, "zero-terminated", "z", false, "line delimiter is NUL")
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/convert"
+	"github.com/spf13/cobra"
+)
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert [OPTION]... [FILE]",
+	Short: "Convert between JSON, YAML and TOML",
+	Long: `Convert a configuration file between JSON, YAML and TOML. With no FILE, or
+when FILE is -, read standard input.
+
+  --from=FORMAT     input format: json, yaml or toml (default: from the file
+                    extension, else detected from the content)
+  --to=FORMAT       output format (default: from the --output extension)
+  -o, --output=FILE write to FILE instead of standard output
+  --indent=N        spaces per level for JSON and YAML output (default 2);
+                    0 writes compact JSON
+
+Key order is preserved in every direction. Comments are carried over from
+YAML to YAML and TOML; JSON has no comments, and TOML input comments are
+not available to the converter. TOML has no null, so null values are
+dropped with a warning. Multi-document YAML becomes one JSON value per
+document and cannot be written as TOML.
+
+Examples:
+  omni convert --to toml config.yaml            # print TOML
+  omni convert config.yaml -o config.toml       # formats from extensions
+  omni convert --from toml --to json < Cargo.toml
+  omni convert --to yaml package.json
+  kubectl get pod x -o json | omni convert --to yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := convert.Options{}
+		opts.From, _ = cmd.Flags().GetString("from")
+		opts.To, _ = cmd.Flags().GetString("to")
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.Indent, _ = cmd.Flags().GetInt("indent")
+
+		return convert.Run(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().String("from", "", "input format: json, yaml or toml (default: detected)")
+	convertCmd.Flags().String("to", "", "output format: json, yaml or toml")
+	convertCmd.Flags().StringP("output", "o", "", "write to FILE instead of standard output")
+	convertCmd.Flags().Int("indent", 2, "spaces per level for JSON and YAML output")
+}
@@ -12,15 +12,46 @@ var cpCmd = &cobra.Command{
 	Short:   "Copy files and directories",
 	Long: `Copy SOURCE to DEST, or multiple SOURCE(s) to DIRECTORY.
 
+  -f, --force         never prompt before overwriting
+      --dry-run       print what would be copied without copying
+  -i, --interactive   prompt before overwriting an existing file
+  -y, --yes           answer yes to every prompt
+      --files-from=FILE  also copy the paths listed in FILE (- for stdin),
+                         one per line or NUL-separated
+  -0, --null          read NUL-separated source paths from stdin (or --files-from)
+
+Listed paths are sources; the last operand is still the destination, which
+must be a directory when there is more than one source.
+
 Examples:
   omni cp a.txt b.txt          # copy a file
   omni cp a.txt b.txt dir/     # copy multiple files into a directory
-  omni copy src/ dest/         # copy a directory tree (alias)`,
+  omni copy src/ dest/         # copy a directory tree (alias)
+  omni cp -i a.txt b.txt dir/  # ask before overwriting dir/a.txt
+  omni find . -name "*.pdf" -print0 | omni cp -0 ~/papers/`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return copy2.RunCopy(args, copy2.CopyOptions{})
+		opts := copy2.CopyOptions{Confirm: getConfirmOpts(cmd)}
+
+		opts.Force, _ = cmd.Flags().GetBool("force")
+
+		listed, err := fileListArgs(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(listed) > 0 {
+			// Listed sources go before the destination operand
+			args = append(listed, args...)
+		}
+
+		return copy2.RunCopy(args, opts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(cpCmd)
+
+	cpCmd.Flags().BoolP("force", "f", false, "never prompt before overwriting")
+	addConfirmFlags(cpCmd, "i")
+	addFileListFlags(cpCmd)
 }
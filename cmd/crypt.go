@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/crypt"
+	"github.com/spf13/cobra"
+)
+
+// cryptCmd represents the crypt command
+var cryptCmd = &cobra.Command{
+	Use:   "crypt",
+	Short: "Key management helpers",
+	Long: `Key management helpers that complement encrypt and decrypt.
+
+Subcommands:
+  keygen    Generate an age X25519 key pair
+  split     Split a secret into Shamir shares
+  combine   Recover a secret from Shamir shares
+
+Examples:
+  omni crypt keygen -o key.txt
+  omni crypt split --shares 5 --threshold 3 master.key
+  omni crypt combine share-1.txt share-3.txt share-5.txt > master.key`,
+}
+
+// cryptKeygenCmd represents the crypt keygen command
+var cryptKeygenCmd = &cobra.Command{
+	Use:   "keygen [OPTION]... [FILE]...",
+	Short: "Generate an age X25519 key pair",
+	Long: `Generate an age X25519 identity (private key) for public-key encryption
+with 'omni encrypt -r'. The identity file has the layout of age-keygen, so
+it also works with the age tool:
+
+  # created: 2026-01-02T03:04:05Z
+  # public key: age1...
+  AGE-SECRET-KEY-1...
+
+Without -o the identity file is printed. With -o it is written to FILE
+(mode 0600), which must not exist yet, and only the public key is printed.
+With -y, print the public key of each identity in FILE or standard input
+instead.
+
+  -o, --output FILE       write the identity to FILE
+  -y, --convert           convert identity files to their public keys
+  --json                  output as JSON
+
+Examples:
+  omni crypt keygen -o key.txt
+  omni crypt keygen -y key.txt > recipients.txt
+  omni encrypt -r "$(omni crypt keygen -y key.txt)" -o out.age file`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := crypt.KeygenOptions{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.Convert, _ = cmd.Flags().GetBool("convert")
+
+		return crypt.RunKeygen(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+// cryptSplitCmd represents the crypt split command
+var cryptSplitCmd = &cobra.Command{
+	Use:   "split [OPTION]... [FILE]",
+	Short: "Split a secret into Shamir shares",
+	Long: `Split the secret in FILE or standard input into N shares using Shamir's
+Secret Sharing, so that any THRESHOLD of them recover it and fewer reveal
+nothing. Shares are hex encoded, one per line, or one file per share with
+--output-dir.
+
+  -n, --shares N          number of shares (default 5, max 255)
+  -k, --threshold K       shares needed to recover the secret (default 3)
+  -d, --output-dir DIR    write share-1.txt .. share-N.txt into DIR
+
+Examples:
+  omni crypt split --shares 5 --threshold 3 master.key
+  omni crypt split -n 3 -k 2 -d ./shares master.key
+  omni random -t hex -l 64 | omni crypt split --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := crypt.SplitOptions{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		opts.Shares, _ = cmd.Flags().GetInt("shares")
+		opts.Threshold, _ = cmd.Flags().GetInt("threshold")
+		opts.OutputDir, _ = cmd.Flags().GetString("output-dir")
+
+		return crypt.RunSplit(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+// cryptCombineCmd represents the crypt combine command
+var cryptCombineCmd = &cobra.Command{
+	Use:   "combine [OPTION]... [FILE]...",
+	Short: "Recover a secret from Shamir shares",
+	Long: `Recover a secret from shares produced by 'omni crypt split'. Shares are
+read one per line from each FILE, or from standard input.
+
+At least THRESHOLD shares from the same split are needed. Fewer shares, or
+shares mixed from different splits, cannot be detected and produce a wrong
+secret.
+
+  -o, --output FILE       write the secret to FILE (mode 0600)
+
+Examples:
+  omni crypt combine share-1.txt share-3.txt share-5.txt > master.key
+  cat shares.txt | omni crypt combine -o master.key`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := crypt.CombineOptions{}
+
+		opts.Output, _ = cmd.Flags().GetString("output")
+
+		return crypt.RunCombine(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cryptCmd)
+	cryptCmd.AddCommand(cryptKeygenCmd)
+	cryptCmd.AddCommand(cryptSplitCmd)
+	cryptCmd.AddCommand(cryptCombineCmd)
+
+	cryptKeygenCmd.Flags().StringP("output", "o", "", "write the identity to FILE")
+	cryptKeygenCmd.Flags().BoolP("convert", "y", false, "convert identity files to their public keys")
+
+	cryptSplitCmd.Flags().IntP("shares", "n", 5, "number of shares")
+	cryptSplitCmd.Flags().IntP("threshold", "k", 3, "shares needed to recover the secret")
+	cryptSplitCmd.Flags().StringP("output-dir", "d", "", "write one share file per share into DIR")
+
+	cryptCombineCmd.Flags().StringP("output", "o", "", "write the secret to file")
+}
@@ -21,6 +21,12 @@ Supports httpie-like syntax for headers and data:
 
 Methods: GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS
 
+Downloads:
+  -o, --output FILE         write the body to FILE instead of stdout
+      --expect-sha256 HEX   verify the body's SHA-256 while streaming
+      --verify-sidecar      take the expected SHA-256 from URL.sha256
+  On a mismatch nothing is printed and FILE is not created.
+
 Examples:
   omni curl https://api.example.com/users
   omni curl POST https://api.example.com/users name=John email=john@example.com
@@ -28,7 +34,9 @@ Examples:
   omni curl https://api.example.com/search q==hello
   omni curl POST https://api.example.com/upload @data.json
   omni curl -v https://api.example.com/users
-  omni curl --json https://api.example.com/users`,
+  omni curl --json https://api.example.com/users
+  omni curl -o tool.tar.gz --expect-sha256 9f86d0... https://example.com/tool.tar.gz
+  omni curl -o tool.tar.gz --verify-sidecar https://example.com/tool.tar.gz`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			return cmd.Help()
@@ -40,6 +48,9 @@ Examples:
 		opts.Form, _ = cmd.Flags().GetBool("form")
 		opts.Insecure, _ = cmd.Flags().GetBool("insecure")
 		opts.Data, _ = cmd.Flags().GetString("data")
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.ExpectSHA256, _ = cmd.Flags().GetString("expect-sha256")
+		opts.Sidecar, _ = cmd.Flags().GetBool("verify-sidecar")
 
 		followRedir, _ := cmd.Flags().GetBool("location")
 		opts.FollowRedir = followRedir
@@ -97,4 +108,7 @@ func init() {
 	curlCmd.Flags().StringP("data", "d", "", "request body data")
 	curlCmd.Flags().StringArrayP("header", "H", nil, "custom header (can be used multiple times)")
 	curlCmd.Flags().IntP("timeout", "t", 30, "request timeout in seconds")
+	curlCmd.Flags().StringP("output", "o", "", "write the body to FILE instead of stdout")
+	curlCmd.Flags().String("expect-sha256", "", "verify the body's SHA-256 digest while streaming")
+	curlCmd.Flags().Bool("verify-sidecar", false, "take the expected SHA-256 from URL.sha256")
 }
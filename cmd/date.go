@@ -20,11 +20,18 @@ FORMAT controls the output. Interpreted sequences are:
   %H   hour (00..23)
   %M   minute (00..59)
   %S   second (00..60)
+  %a   abbreviated weekday name
+  %A   full weekday name
+  %b   abbreviated month name
+  %B   full month name
+
+Names follow the output language: --lang, or LC_ALL, LC_MESSAGES and LANG.
 
 Examples:
   omni date                       # current date and time
   omni date -u                    # current time in UTC
-  omni date +%Y-%m-%d             # format as YYYY-MM-DD`,
+  omni date +%Y-%m-%d             # format as YYYY-MM-DD
+  omni date --lang pt-BR "+%A, %d de %B"   # domingo, 18 de outubro`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := date.DateOptions{}
 
@@ -17,6 +17,12 @@ var decryptCmd = &cobra.Command{
   -o, --output FILE       write output to file
   -a, --armor             input is ASCII armored (base64)
   -i, --iterations N      PBKDF2 iterations (default 100000)
+      --identity FILE     decrypt an age file with the identities in FILE
+                          (repeatable)
+
+Files encrypted to age recipients ('omni encrypt -r') are detected
+automatically, binary or armored, and need --identity instead of a
+password.
 
 Password can also be set via omni_PASSWORD environment variable.
 
@@ -24,7 +30,8 @@ Examples:
   omni decrypt -p mypassword secret.enc
   omni decrypt -p mypassword -a < secret.b64
   omni decrypt -P ~/.password -o file.txt secret.enc
-  cat secret.enc | omni_PASSWORD=pass omni decrypt`,
+  cat secret.enc | omni_PASSWORD=pass omni decrypt
+  omni decrypt --identity key.txt -o build.tar build.tar.age`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := crypt.CryptOptions{}
 
@@ -35,6 +42,7 @@ Examples:
 		opts.Armor, _ = cmd.Flags().GetBool("armor")
 		opts.Base64, _ = cmd.Flags().GetBool("base64")
 		opts.Iterations, _ = cmd.Flags().GetInt("iterations")
+		opts.Identities, _ = cmd.Flags().GetStringArray("identity")
 
 		return crypt.RunDecrypt(cmd.OutOrStdout(), args, opts)
 	},
@@ -50,4 +58,5 @@ func init() {
 	decryptCmd.Flags().BoolP("armor", "a", false, "input is ASCII armored (base64)")
 	decryptCmd.Flags().BoolP("base64", "b", false, "input is base64 (same as -a)")
 	decryptCmd.Flags().IntP("iterations", "i", 100000, "PBKDF2 iterations")
+	decryptCmd.Flags().StringArray("identity", nil, "decrypt an age file with the identities in FILE (repeatable)")
 }
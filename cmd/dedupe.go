@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/dedupe"
+	"github.com/spf13/cobra"
+)
+
+// dedupeCmd represents the dedupe command
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe [OPTION]... [DIR]...",
+	Short: "Find duplicate files by content hash",
+	Long: `Scan directories (default: the current one) for files with identical
+content. Files are grouped by size first, and only sizes shared by more
+than one file are hashed, in parallel. Ignore files (.gitignore and the
+common ignores such as .git and node_modules) are honored as in rg.
+
+Existing hard links and symlinks are not duplicates: each file is counted
+once and links are not followed. In every group the first path in sort
+order is kept; with --hardlink or --delete the others are replaced by hard
+links to it or removed.
+
+  -a, --algorithm ALGO  content hash: sha256 (default), blake2b, md5, ...
+  -m, --min-size N      ignore files smaller than N bytes (default 1)
+      --no-ignore       do not honor ignore files
+  -L, --hardlink        replace duplicates with hard links to the kept file
+  -d, --delete          delete duplicates
+  -t, --threads N       parallel hash workers (0 = auto)
+  -n, --dry-run         with -L or -d, show what would be done
+  -i, --interactive     with -L or -d, ask before each file
+  -y, --yes             answer yes to every prompt
+  -0, --null            print only the duplicates (not the kept files),
+                        each followed by NUL, without the table or summary
+  --json                print the groups and summary as JSON
+
+Examples:
+  omni dedupe ~/Downloads
+  omni dedupe -m 1048576 photos/ backup/photos/
+  omni dedupe --json . | omni jq '.groups[].files[0].path'
+  omni dedupe -L -n media/        # preview hard-linking
+  omni dedupe -d -i downloads/    # delete extras, asking for each
+  omni dedupe -0 photos/ | omni rm -0 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := dedupe.Options{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		opts.Algorithm, _ = cmd.Flags().GetString("algorithm")
+		opts.MinSize, _ = cmd.Flags().GetInt64("min-size")
+		opts.NoIgnore, _ = cmd.Flags().GetBool("no-ignore")
+		opts.Hardlink, _ = cmd.Flags().GetBool("hardlink")
+		opts.Delete, _ = cmd.Flags().GetBool("delete")
+		opts.Threads, _ = cmd.Flags().GetInt("threads")
+		opts.Null, _ = cmd.Flags().GetBool("null")
+		opts.Confirm = getConfirmOpts(cmd)
+
+		return dedupe.RunDedupe(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dedupeCmd)
+
+	dedupeCmd.Flags().StringP("algorithm", "a", "sha256", "content hash algorithm")
+	dedupeCmd.Flags().Int64P("min-size", "m", 1, "ignore files smaller than N bytes")
+	dedupeCmd.Flags().Bool("no-ignore", false, "do not honor .gitignore and common ignores")
+	dedupeCmd.Flags().BoolP("hardlink", "L", false, "replace duplicates with hard links to the kept file")
+	dedupeCmd.Flags().BoolP("delete", "d", false, "delete duplicates")
+	dedupeCmd.Flags().IntP("threads", "t", 0, "parallel hash workers (0 = auto)")
+	dedupeCmd.Flags().BoolP("dry-run", "n", false, "show what would be done without changing anything")
+	dedupeCmd.Flags().BoolP("null", "0", false, "print only the duplicate paths, separated by NUL")
+	addConfirmFlags(dedupeCmd, "i")
+}
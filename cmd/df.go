@@ -20,11 +20,23 @@ or all file systems by default.
   -x, --exclude-type=TYPE  exclude file systems of type TYPE
   -l, --local           limit listing to local file systems
   -P, --portability     use the POSIX output format
+  -T, --print-type      print file system type
+      --threshold=N%    exit non-zero if any listed file system is N% full or more
+      --inode-threshold=N%  same, for inode usage
+  --json                print results as JSON (device, type, mount, inodes)
+
+The file system column shows the backing device and "Mounted on" its mount
+point. When a threshold is reached the listing is still printed, each
+offending mount is reported on stderr and df exits with status 1, which
+makes it usable as a container health-check probe.
 
 Examples:
   omni df                         # report all file systems
   omni df -h                      # human-readable sizes
-  omni df -h /                    # disk usage for the root file system`,
+  omni df -h /                    # disk usage for the root file system
+  omni df -T -i /data             # type and inode usage
+  omni df --threshold 90% / /data # fail when a volume is 90% full
+  omni df --json --threshold 85 --inode-threshold 90%`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := df.DFOptions{}
 
@@ -36,8 +48,22 @@ Examples:
 		opts.ExcludeType, _ = cmd.Flags().GetString("exclude-type")
 		opts.Local, _ = cmd.Flags().GetBool("local")
 		opts.Portability, _ = cmd.Flags().GetBool("portability")
+		opts.PrintType, _ = cmd.Flags().GetBool("print-type")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
+		threshold, _ := cmd.Flags().GetString("threshold")
+
+		var err error
+
+		if opts.Threshold, err = df.ParseThreshold(threshold); err != nil {
+			return err
+		}
+
+		inodeThreshold, _ := cmd.Flags().GetString("inode-threshold")
+		if opts.InodeThreshold, err = df.ParseThreshold(inodeThreshold); err != nil {
+			return err
+		}
+
 		return df.RunDF(cmd.OutOrStdout(), args, opts)
 	},
 }
@@ -53,5 +79,8 @@ func init() {
 	dfCmd.Flags().StringP("exclude-type", "x", "", "exclude file systems of type TYPE")
 	dfCmd.Flags().BoolP("local", "l", false, "limit listing to local file systems")
 	dfCmd.Flags().BoolP("portability", "P", false, "use the POSIX output format")
+	dfCmd.Flags().BoolP("print-type", "T", false, "print file system type")
+	dfCmd.Flags().String("threshold", "", "exit non-zero if use% reaches N (e.g. 90%)")
+	dfCmd.Flags().String("inode-threshold", "", "exit non-zero if inode use% reaches N (e.g. 90%)")
 
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/dl"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
+	"github.com/spf13/cobra"
+)
+
+// dlCmd represents the dl command
+var dlCmd = &cobra.Command{
+	Use:   "dl [OPTION]... URL [MIRROR]...",
+	Short: "Download a file over HTTP with resume, retries and mirrors",
+	Long: `Download URL to a file, like wget or wcurl. The body is written to
+FILE.part and renamed to FILE only when the transfer is complete and, with
+--checksum, verified; a mismatched download is discarded.
+
+Transient failures (connection errors, truncated bodies, 5xx, 408 and
+429) are retried with exponential backoff, resuming with a Range request
+where the server supports it. When a URL fails for good the next MIRROR,
+then each URL in --mirror-list, is tried, continuing from the data
+already received.
+
+The output path is a template:
+  {name}  file name from the URL path (index.html when empty)
+  {stem}  {name} without its extension
+  {ext}   extension of {name}
+  {host}  URL host name
+  {date}  today as YYYY-MM-DD
+
+  -o, --output TEMPLATE     output path (default "{name}")
+  -M, --mirror-list FILE    more mirror URLs, one per line
+  -c, --continue            resume from an existing FILE.part
+  -r, --retries N           extra attempts per URL (default 3)
+      --retry-delay DUR     first retry delay, doubled each time (default 1s)
+      --checksum DIGEST     expected digest, hex or "algo:hex"
+  -a, --algorithm ALGO      digest algorithm (default sha256)
+      --limit-rate RATE     maximum bytes per second (K, M, G suffixes)
+  -H, --header "N: V"       extra request header (repeatable)
+  -k, --insecure            skip TLS verification
+  -f, --force               overwrite an existing file
+  -q, --quiet               no progress or retry notices
+  --json                    print the result as JSON
+
+Examples:
+  omni dl https://example.com/tool-1.2.tar.gz
+  omni dl -c https://example.com/big.iso          # continue an interrupted download
+  omni dl --checksum sha256:9f86d0... https://a.example/f.tgz https://b.example/f.tgz
+  omni dl -M mirrors.txt -r 5 https://example.com/f.tgz
+  omni dl -o 'downloads/{host}/{stem}-{date}.{ext}' https://example.com/report.pdf
+  omni dl --limit-rate 500K https://example.com/video.mp4`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := dl.Options{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.MirrorList, _ = cmd.Flags().GetString("mirror-list")
+		opts.Continue, _ = cmd.Flags().GetBool("continue")
+		opts.Retries, _ = cmd.Flags().GetInt("retries")
+		opts.RetryDelay, _ = flagvalue.GetDuration(cmd.Flags(), "retry-delay")
+		opts.Checksum, _ = cmd.Flags().GetString("checksum")
+		opts.Algorithm, _ = cmd.Flags().GetString("algorithm")
+		opts.LimitRate, _ = cmd.Flags().GetString("limit-rate")
+		opts.Headers, _ = cmd.Flags().GetStringArray("header")
+		opts.Insecure, _ = cmd.Flags().GetBool("insecure")
+		opts.Force, _ = cmd.Flags().GetBool("force")
+		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
+
+		return dl.Run(cmd.Context(), cmd.OutOrStdout(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dlCmd)
+
+	dlCmd.Flags().StringP("output", "o", "{name}", "output path template")
+	dlCmd.Flags().StringP("mirror-list", "M", "", "file of mirror URLs, one per line")
+	dlCmd.Flags().BoolP("continue", "c", false, "resume from an existing FILE.part")
+	dlCmd.Flags().IntP("retries", "r", 3, "extra attempts per URL after a transient failure")
+	flagvalue.DurationP(dlCmd.Flags(), "retry-delay", "", 0, "first retry delay, doubled each time (default 1s)")
+	dlCmd.Flags().String("checksum", "", "expected digest, hex or \"algo:hex\"")
+	dlCmd.Flags().StringP("algorithm", "a", "", "digest algorithm (default sha256)")
+	dlCmd.Flags().String("limit-rate", "", "maximum bytes per second (K, M, G suffixes)")
+	dlCmd.Flags().StringArrayP("header", "H", nil, "extra request header \"Name: value\" (repeatable)")
+	dlCmd.Flags().BoolP("insecure", "k", false, "skip TLS verification")
+	dlCmd.Flags().BoolP("force", "f", false, "overwrite an existing output file")
+	dlCmd.Flags().BoolP("quiet", "q", false, "no progress or retry notices")
+}
@@ -70,6 +70,7 @@ var docCategory = map[string]string{
 	"readlink": "File Operations",
 	"chmod":    "File Operations",
 	"chown":    "File Operations",
+	"dedupe":   "File Operations",
 
 	// Text Processing
 	"grep":   "Text Processing",
@@ -91,6 +92,7 @@ var docCategory = map[string]string{
 	"join":   "Text Processing",
 	"sed":    "Text Processing",
 	"awk":    "Text Processing",
+	"stats":  "Text Processing",
 
 	// System Information
 	"env":    "System Information",
@@ -104,6 +106,7 @@ var docCategory = map[string]string{
 	"ps":     "System Information",
 	"kill":   "System Information",
 	"time":   "System Information",
+	"bench":  "System Information",
 
 	// Process (runtime-aware)
 	"gops":   "Process (runtime-aware)",
@@ -112,11 +115,14 @@ var docCategory = map[string]string{
 	"javaps": "Process (runtime-aware)",
 
 	// Flow Control
-	"xargs": "Flow Control",
-	"watch": "Flow Control",
-	"yes":   "Flow Control",
-	"nohup": "Flow Control",
-	"pipe":  "Flow Control",
+	"xargs":  "Flow Control",
+	"watch":  "Flow Control",
+	"yes":    "Flow Control",
+	"nohup":  "Flow Control",
+	"pipe":   "Flow Control",
+	"lock":   "Flow Control",
+	"notify": "Flow Control",
+	"prompt": "Flow Control",
 
 	// Archive & Compression
 	"tar":   "Archive & Compression",
@@ -131,12 +137,16 @@ var docCategory = map[string]string{
 	"base64":    "Hash & Encoding",
 	"base32":    "Hash & Encoding",
 	"base58":    "Hash & Encoding",
+	"uuencode":  "Hash & Encoding",
+	"uudecode":  "Hash & Encoding",
+	"qp":        "Hash & Encoding",
 	"xxd":       "Hash & Encoding",
 
 	// Data Processing
-	"jq":     "Data Processing",
-	"yq":     "Data Processing",
-	"dotenv": "Data Processing",
+	"jq":      "Data Processing",
+	"yq":      "Data Processing",
+	"dotenv":  "Data Processing",
+	"convert": "Data Processing",
 
 	// Security & Random
 	"sbom":       "Security & Random",
@@ -156,10 +166,12 @@ var docCategory = map[string]string{
 	"diff": "Comparison",
 
 	// Tooling
-	"lint":    "Tooling",
-	"cmdtree": "Tooling",
-	"logger":  "Tooling",
-	"version": "Tooling",
+	"lint":     "Tooling",
+	"cmdtree":  "Tooling",
+	"docs":     "Tooling",
+	"logger":   "Tooling",
+	"version":  "Tooling",
+	"selftest": "Tooling",
 }
 
 // GenerateCommandReference writes the canonical omni command reference
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/inovacc/omni/internal/cli/cmddoc"
+	"github.com/inovacc/omni/internal/cli/plugin"
+	"github.com/spf13/cobra"
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate reference documentation for omni",
+	Long: `Generate reference documentation from omni's command tree.
+
+Subcommands:
+  generate    Write a man page or markdown page per command
+
+Examples:
+  omni docs generate --out ./man
+  omni docs generate --format markdown --out ./docs/cli`,
+}
+
+// docsGenerateCmd represents the docs generate command
+var docsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Write a man page or markdown page per command",
+	Long: `Write one page per command and subcommand to the output directory, for
+packaging (man pages) or a documentation site (markdown).
+
+Pages hold the command's usage, description, flags, inherited flags and
+links to its parent and subcommands. The "Examples:" section of a command's
+help text becomes an EXAMPLES section. Hidden and deprecated commands are
+skipped. Pages carry no date, so regenerating an unchanged tree produces
+identical files.
+
+Man pages are named after the command path, e.g. omni-lock-acquire.1;
+markdown pages use underscores, e.g. omni_lock_acquire.md.
+
+With --plugins, omni-<name> plugins found on PATH get a page too.
+
+Examples:
+  omni docs generate --out ./man
+  omni docs generate --format man --section 1 --out ./dist/man/man1
+  omni docs generate --format markdown --out ./docs/cli
+  omni docs generate --out ./man --plugins --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := cmddoc.Options{OutputFormat: getOutputOpts(cmd).GetFormat()}
+		opts.Format, _ = cmd.Flags().GetString("format")
+		opts.OutDir, _ = cmd.Flags().GetString("out")
+		opts.Section, _ = cmd.Flags().GetString("section")
+
+		if withPlugins, _ := cmd.Flags().GetBool("plugins"); withPlugins {
+			opts.Extra = pluginDocCommands()
+		}
+
+		return cmddoc.RunGenerate(cmd.OutOrStdout(), rootCmd, opts)
+	},
+}
+
+// pluginDocCommands describes the plugins on PATH that are not hidden by a
+// built-in command, for the documentation generator.
+func pluginDocCommands() []*cobra.Command {
+	var cmds []*cobra.Command
+
+	for _, p := range plugin.Discover(os.Getenv("PATH")) {
+		if c, _, err := rootCmd.Find([]string{p.Name}); err == nil && c != rootCmd {
+			continue
+		}
+
+		cmds = append(cmds, &cobra.Command{
+			Use:   p.Name + " [ARG]...",
+			Short: fmt.Sprintf("External plugin provided by %s", filepath.Base(p.Path)),
+			Long: fmt.Sprintf(`Run the external plugin %s found on PATH. Arguments are passed through
+verbatim; see 'omni %s --help' for the plugin's own documentation.`, filepath.Base(p.Path), p.Name),
+			Run: func(*cobra.Command, []string) {},
+		})
+	}
+
+	return cmds
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsGenerateCmd)
+
+	docsGenerateCmd.Flags().StringP("format", "f", "man", "output format: man or markdown")
+	docsGenerateCmd.Flags().StringP("out", "o", "./docs", "output directory")
+	docsGenerateCmd.Flags().String("section", "1", "man page section")
+	docsGenerateCmd.Flags().Bool("plugins", false, "also document omni-<name> plugins found on PATH")
+}
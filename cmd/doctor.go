@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/doctor"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [OPTION]...",
+	Short: "Diagnose the omni environment and configuration",
+	Long: `Run environment and configuration diagnostics and print actionable fixes.
+
+Checks:
+  config          feature flag files are consistent
+  config-file     the omni config file ($OMNI_CONFIG) parses
+  logger          command logging points at a writable directory
+  path-shadowing  no system utility on PATH resolves to omni
+  color           terminal color support
+  cache:*         cache directories (rg --pre, buf images, OSV DB) are writable
+                  and not oversized
+  net:*           registries used by omni are reachable
+
+Exits with status 1 when a check fails (or warns, with --strict).
+
+      --category LIST  only run checks in these categories
+                       (config, path, terminal, cache, network)
+      --offline        skip network checks
+      --strict         exit non-zero on warnings too
+      --timeout DUR    per-check timeout (default 5s)
+  --json               output as JSON
+
+Examples:
+  omni doctor
+  omni doctor --offline
+  omni doctor --category cache,network --json
+  omni doctor --strict                 # fail CI on any warning`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := doctor.Options{}
+
+		opts.Categories, _ = cmd.Flags().GetStringSlice("category")
+		opts.Offline, _ = cmd.Flags().GetBool("offline")
+		opts.Strict, _ = cmd.Flags().GetBool("strict")
+		opts.Timeout, _ = flagvalue.GetDuration(cmd.Flags(), "timeout")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return doctor.RunDoctor(cmd.Context(), cmd.OutOrStdout(), opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringSlice("category", nil, "only run checks in these categories (config, path, terminal, cache, network)")
+	doctorCmd.Flags().Bool("offline", false, "skip network checks")
+	doctorCmd.Flags().Bool("strict", false, "exit non-zero on warnings too")
+	flagvalue.DurationP(doctorCmd.Flags(), "timeout", "", 5*time.Second, "per-check timeout")
+}
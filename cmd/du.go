@@ -22,11 +22,15 @@ var duCmd = &cobra.Command{
       --apparent-size   print apparent sizes, rather than disk usage
   -0, --null            end each output line with NUL, not newline
   -B, --block-size=SIZE scale sizes by SIZE before printing them
+  -t, --threshold=SIZE  exclude entries smaller than SIZE if positive, or
+                        entries greater than SIZE if negative (e.g. 100M, -1G)
+  --json                print entries and grand total as JSON
 
 Examples:
   omni du                         # disk usage of the current tree
   omni du -h /var/log             # human-readable usage of a directory
-  omni du -sh .                   # single summarized total`,
+  omni du -sh .                   # single summarized total
+  omni du -a -t 100M /var         # only entries of 100 MiB or more`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := du.DUOptions{}
 
@@ -42,6 +46,13 @@ Examples:
 		opts.BlockSize, _ = cmd.Flags().GetInt64("block-size")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
+		threshold, _ := cmd.Flags().GetString("threshold")
+
+		var err error
+		if opts.Threshold, err = du.ParseThreshold(threshold); err != nil {
+			return err
+		}
+
 		return du.RunDU(cmd.OutOrStdout(), args, opts)
 	},
 }
@@ -59,5 +70,6 @@ func init() {
 	duCmd.Flags().Bool("apparent-size", false, "print apparent sizes, rather than disk usage")
 	duCmd.Flags().BoolP("null", "0", false, "end each output line with NUL, not newline")
 	duCmd.Flags().Int64P("block-size", "B", 0, "scale sizes by SIZE before printing them")
+	duCmd.Flags().StringP("threshold", "t", "", "exclude entries smaller than SIZE (larger if negative)")
 
 }
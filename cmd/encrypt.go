@@ -19,6 +19,21 @@ Uses PBKDF2 for key derivation with SHA-256.
   -o, --output FILE       write output to file
   -a, --armor             ASCII armor (base64) output
   -i, --iterations N      PBKDF2 iterations (default 100000)
+      --envelope          seal with a random data key wrapped by the password
+  -r, --recipient KEY     encrypt to an age public key (age1...) instead of
+                          a password (repeatable)
+  -R, --recipients-file FILE  encrypt to the age public keys listed in FILE
+                          (repeatable)
+
+With --envelope the data is encrypted with a fresh random key that is stored,
+wrapped by the password, in the file header. The password can later be
+changed with 'omni envelope rotate' without re-encrypting the data. decrypt
+detects envelopes automatically.
+
+With -r or -R the output is an age file (age-encryption.org/v1) that any
+one of the matching identities can decrypt, with 'omni decrypt --identity'
+or the age tool; no password is used. Create a key pair with
+'omni crypt keygen'. With -a the age file is armored.
 
 Password can also be set via omni_PASSWORD environment variable.
 
@@ -26,7 +41,10 @@ Examples:
   echo "secret" | omni encrypt -p mypassword
   omni encrypt -p mypassword -o secret.enc file.txt
   omni encrypt -P ~/.password -a file.txt
-  omni_PASSWORD=pass omni encrypt file.txt`,
+  omni_PASSWORD=pass omni encrypt file.txt
+  omni encrypt --envelope -P ~/.password -o secret.env file.txt
+  omni encrypt -r age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p -o build.tar.age build.tar
+  omni encrypt -R ci-recipients.txt -a notes.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := crypt.CryptOptions{}
 
@@ -37,6 +55,9 @@ Examples:
 		opts.Armor, _ = cmd.Flags().GetBool("armor")
 		opts.Base64, _ = cmd.Flags().GetBool("base64")
 		opts.Iterations, _ = cmd.Flags().GetInt("iterations")
+		opts.Envelope, _ = cmd.Flags().GetBool("envelope")
+		opts.Recipients, _ = cmd.Flags().GetStringArray("recipient")
+		opts.RecipientsFiles, _ = cmd.Flags().GetStringArray("recipients-file")
 
 		return crypt.RunEncrypt(cmd.OutOrStdout(), args, opts)
 	},
@@ -52,4 +73,7 @@ func init() {
 	encryptCmd.Flags().BoolP("armor", "a", false, "ASCII armor (base64) output")
 	encryptCmd.Flags().BoolP("base64", "b", false, "base64 output (same as -a)")
 	encryptCmd.Flags().IntP("iterations", "i", 100000, "PBKDF2 iterations")
+	encryptCmd.Flags().Bool("envelope", false, "seal with a random data key wrapped by the password")
+	encryptCmd.Flags().StringArrayP("recipient", "r", nil, "encrypt to an age public key instead of a password (repeatable)")
+	encryptCmd.Flags().StringArrayP("recipients-file", "R", nil, "encrypt to the age public keys listed in FILE (repeatable)")
 }
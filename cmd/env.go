@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/inovacc/omni/internal/cli/env"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
+	"github.com/inovacc/omni/internal/cli/supervise"
+	"github.com/inovacc/omni/internal/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -28,11 +35,69 @@ Examples:
 	},
 }
 
+// envRunCmd runs a command under a restart policy
+var envRunCmd = &cobra.Command{
+	Use:   "run [OPTION]... -- COMMAND [ARG]...",
+	Short: "Run a command under a restart policy",
+	Long: `Run COMMAND as a supervised process, restarting it when it exits.
+
+Restart policies:
+  no          run once (default)
+  on-failure  restart when the command exits with a non-zero status
+  always      restart whenever the command exits
+
+The delay before a restart starts at --backoff and doubles after each
+restart, up to --max-backoff. A run that lasts at least --max-backoff
+counts as healthy and resets the delay. SIGINT and SIGTERM are forwarded
+to the command and stop the supervisor; a command still running after
+--grace is killed. omni exits with the command's last exit status.
+
+When logging is enabled, each start, exit and restart is recorded along
+with the command's output.
+
+Examples:
+  omni env run --restart on-failure --max-retries 5 -- ./server --port 8080
+  omni env run --restart always --backoff 2s -- omni watch -- make
+  omni env run -e LOG_LEVEL=debug -- worker`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := supervise.Options{}
+
+		opts.Restart, _ = cmd.Flags().GetString("restart")
+		opts.MaxRetries, _ = cmd.Flags().GetInt("max-retries")
+		opts.Backoff, _ = flagvalue.GetDuration(cmd.Flags(), "backoff")
+		opts.MaxBackoff, _ = flagvalue.GetDuration(cmd.Flags(), "max-backoff")
+		opts.Grace, _ = flagvalue.GetDuration(cmd.Flags(), "grace")
+		opts.Env, _ = cmd.Flags().GetStringArray("env")
+		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
+		opts.Stdin = cmd.InOrStdin()
+		opts.Logger = logger.Get()
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+		defer signal.Stop(sigCh)
+
+		opts.Signals = sigCh
+
+		return supervise.Run(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envRunCmd)
 
 	envCmd.Flags().BoolP("null", "0", false, "end each output line with NUL, not newline")
 	envCmd.Flags().StringP("unset", "u", "", "remove variable from the environment")
 	envCmd.Flags().BoolP("ignore-environment", "i", false, "start with an empty environment")
 
+	envRunCmd.Flags().SetInterspersed(false)
+	envRunCmd.Flags().String("restart", supervise.RestartNo, "restart policy: no, on-failure or always")
+	envRunCmd.Flags().Int("max-retries", 0, "maximum number of restarts (0 = unlimited)")
+	flagvalue.DurationP(envRunCmd.Flags(), "backoff", "", supervise.DefaultBackoff, "delay before the first restart, doubled after each restart")
+	flagvalue.DurationP(envRunCmd.Flags(), "max-backoff", "", supervise.DefaultMaxBackoff, "maximum delay between restarts")
+	flagvalue.DurationP(envRunCmd.Flags(), "grace", "", supervise.DefaultGrace, "time to wait after forwarding a stop signal before killing")
+	envRunCmd.Flags().StringArrayP("env", "e", nil, "set KEY=VALUE in the command's environment (repeatable)")
+	envRunCmd.Flags().BoolP("quiet", "q", false, "suppress supervisor messages")
 }
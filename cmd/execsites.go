@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/rg"
+	"github.com/spf13/cobra"
+)
+
+// execSite is a command that starts external processes: one of the
+// sanctioned exec sites listed in docs/architecture/patterns.md.
+type execSite struct {
+	path string // command path below omni; covers its subcommands too
+	flag string // the option that starts processes, if not every run does
+	// execs reports whether a run with args, the arguments after the
+	// command path, starts processes; required with flag.
+	execs func(args []string) bool
+}
+
+func (s execSite) String() string {
+	if s.flag != "" {
+		return s.path + " " + s.flag
+	}
+
+	return s.path
+}
+
+// execSites mirrors the sanctioned-exec table of
+// docs/architecture/patterns.md; keep the two in sync. Plugins are not
+// listed: omni <name> only dispatches to omni-<name> for the arguments of
+// the omni process itself, never in-process.
+var execSites = []execSite{
+	{path: "exec"},
+	{path: "env run"},
+	{path: "for"},
+	{path: "task"},
+	{path: "terraform"},
+	{path: "git"},
+	{path: "gh"},
+	{path: "repo"},
+	{path: "buf generate"},
+	{path: "rg", flag: "--pre", execs: rgRunsPre},
+	{path: "notify"},
+}
+
+// findExecSite returns the exec site c belongs to, if a run of c with args
+// starts external processes.
+func findExecSite(c *cobra.Command, args []string) (execSite, bool) {
+	path := strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+
+	for _, site := range execSites {
+		if path != site.path && !strings.HasPrefix(path, site.path+" ") {
+			continue
+		}
+
+		if site.execs == nil || site.execs(args) {
+			return site, true
+		}
+	}
+
+	return execSite{}, false
+}
+
+// rgRunsPre reports whether rg runs a --pre preprocessor, given on the
+// command line or in $OMNI_RG_DEFAULT_FLAGS. A .omnirg file may not set it.
+func rgRunsPre(args []string) bool {
+	isPre := func(arg string) bool { return arg == "--pre" || strings.HasPrefix(arg, "--pre=") }
+
+	if i := slices.Index(args, "--"); i >= 0 {
+		args = args[:i]
+	}
+
+	return slices.ContainsFunc(args, isPre) ||
+		slices.ContainsFunc(strings.Fields(os.Getenv(rg.EnvDefaultFlags)), isPre)
+}
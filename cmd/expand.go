@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+
+	"github.com/inovacc/omni/internal/cli/expand"
+	"github.com/spf13/cobra"
+)
+
+// expandCmd represents the expand command
+var expandCmd = &cobra.Command{
+	Use:   "expand [OPTION]... [FILE]...",
+	Short: "Convert tabs to spaces",
+	Long: `Convert tabs in each FILE to spaces, writing to standard output.
+With no FILE, or when FILE is -, read standard input.
+
+  -i, --initial       do not convert tabs after non blanks
+  -t, --tabs=N        have tabs N characters apart, not 8
+  -t, --tabs=LIST     use comma separated list of tab positions; the last
+                      entry may be prefixed with '/' to repeat every N
+                      columns, or with '+' to repeat every N columns
+                      after the last explicit stop
+
+The obsolete form -N (or -N1,N2) is accepted as --tabs=N.
+
+Examples:
+  omni expand file.go                   # tabs every 8 columns
+  omni expand -t 4 file.go              # tabs every 4 columns
+  omni expand -i -t 2 file.yaml         # only leading tabs
+  omni expand -t 4,12,/8 table.txt      # stops at 4 and 12, then every 8
+  omni expand -4 file.txt               # same as -t 4`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := expand.Options{}
+		opts.Tabs, _ = cmd.Flags().GetString("tabs")
+		opts.Initial, _ = cmd.Flags().GetBool("initial")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return expand.RunExpand(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(expandCmd)
+
+	expandCmd.Flags().StringP("tabs", "t", "", "tab size N, or comma separated list of tab positions")
+	expandCmd.Flags().BoolP("initial", "i", false, "do not convert tabs after non blanks")
+
+	preprocessTabStopArgs()
+}
+
+var tabStopShortcutRegex = regexp.MustCompile(`^-(\d+(,\d+)*)$`)
+
+// preprocessTabStopArgs rewrites the obsolete expand/unexpand -N and
+// -N1,N2 forms to --tabs=N before Cobra parses them.
+func preprocessTabStopArgs() {
+	start := -1
+
+	for i := 1; i < len(os.Args); i++ {
+		if os.Args[i] == "expand" || os.Args[i] == "unexpand" {
+			start = i + 1
+			break
+		}
+
+		// Only global flags may precede the command name
+		if len(os.Args[i]) == 0 || os.Args[i][0] != '-' {
+			return
+		}
+	}
+
+	if start < 0 {
+		return
+	}
+
+	for i := start; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		if arg == "--" {
+			return
+		}
+
+		// Leave option values such as -t 4 alone
+		if prev := os.Args[i-1]; prev == "-t" || prev == "--tabs" {
+			continue
+		}
+
+		if m := tabStopShortcutRegex.FindStringSubmatch(arg); m != nil {
+			os.Args[i] = "--tabs=" + m[1]
+		}
+	}
+}
@@ -17,14 +17,21 @@ var fileCmd = &cobra.Command{
 	Short: "Determine file type",
 	Long: `Determine the type of each FILE.
 
-  -b, --brief           do not prepend filenames to output
-  -i, --mime            output MIME type strings
-  -h, --no-dereference  don't follow symlinks
-  -F, --separator       use string as separator instead of ':'
+Files are identified by content (magic bytes for images, audio, video,
+archives, documents, executables, fonts and databases; zip-based formats
+such as docx, jar and epub by their entries) and text by its kind and
+encoding, falling back to the file extension.
+
+  -b, --brief              do not prepend filenames to output
+  -i, --mime, --mime-type  output MIME type strings
+  -h, --no-dereference     don't follow symlinks
+  -F, --separator          use string as separator instead of ':'
 
 Examples:
   omni file image.png          # PNG image data
   omni file -i document.pdf    # application/pdf
+  omni file --mime-type x.gz   # application/gzip
+  omni file report.docx        # Microsoft Word 2007+
   omni file -b script.sh       # output type only
   omni file *                  # check all files`,
 	Args: cobra.MinimumNArgs(1),
@@ -46,6 +53,7 @@ func init() {
 
 	fileCmd.Flags().BoolVarP(&fileBrief, "brief", "b", false, "do not prepend filenames")
 	fileCmd.Flags().BoolVarP(&fileMimeType, "mime", "i", false, "output MIME type")
+	fileCmd.Flags().BoolVar(&fileMimeType, "mime-type", false, "output MIME type (same as --mime)")
 	fileCmd.Flags().BoolVarP(&fileNoDeref, "no-dereference", "L", false, "don't follow symlinks")
 	fileCmd.Flags().StringVarP(&fileSeparator, "separator", "F", ":", "use string as separator")
 }
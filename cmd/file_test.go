@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/pipe"
+)
+
+// TestFileMimeType checks that --mime-type is accepted as the long form of
+// -i and prints the same MIME type.
+func TestFileMimeType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(path, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := pipe.NewRegistry(rootCmd)
+
+	for _, flag := range []string{"-i", "--mime", "--mime-type"} {
+		var buf bytes.Buffer
+		if err := registry.Run(context.Background(), &buf, nil, []string{"file", "-b", flag, path}); err != nil {
+			t.Fatalf("file %s: %v", flag, err)
+		}
+
+		if got := buf.String(); got != "image/png\n" {
+			t.Errorf("file %s output = %q, want %q", flag, got, "image/png\n")
+		}
+	}
+}
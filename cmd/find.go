@@ -15,6 +15,7 @@ var knownFindFlags = map[string]bool{
 	"-mindepth": true, "-maxdepth": true, "-mtime": true, "-mmin": true,
 	"-atime": true, "-amin": true, "-empty": true, "-executable": true,
 	"-readable": true, "-writable": true, "-print0": true, "-not": true,
+	"-files-from": true,
 }
 
 var (
@@ -38,6 +39,7 @@ var (
 	findWritable   bool
 	findPrint0     bool
 	findNot        bool
+	findFilesFrom  string
 )
 
 var findCmd = &cobra.Command{
@@ -67,11 +69,17 @@ Tests:
   -writable          matches files which are writable
 
 Actions:
-  -print0            print full path with null terminator
+  -print0, --null    print full path with null terminator, for xargs -0,
+                     rm -0 and cp -0
 
 Operators:
   -not               negate the next test
 
+Input:
+  -files-from FILE   test the paths listed in FILE (- for stdin) instead of
+                     walking starting points; newline or NUL delimited,
+                     listed directories are not descended into
+
 Examples:
   omni find . -name "*.go"                    # find Go files
   omni find . -type f -size +1M               # find files larger than 1MB
@@ -79,7 +87,9 @@ Examples:
   omni find . -name "*.log" -empty            # find empty log files
   omni find . -type d -name "node_modules"    # find node_modules directories
   omni find . -maxdepth 2 -type f             # files at most 2 levels deep
-  omni find . -name "*.txt" -print0           # null-separated output`,
+  omni find . -name "*.txt" -print0           # null-separated output
+  omni find . -name "*~" -print0 | omni rm -0 # remove names with spaces safely
+  git ls-files -z | omni find -files-from - -size +100k   # filter a file list`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := find.FindOptions{
 			Name:         findName,
@@ -102,6 +112,7 @@ Examples:
 			Writable:     findWritable,
 			Print0:       findPrint0,
 			Not:          findNot,
+			FilesFrom:    findFilesFrom,
 			OutputFormat: getOutputOpts(cmd).GetFormat(),
 		}
 
@@ -131,7 +142,9 @@ func init() {
 	findCmd.Flags().BoolVarP(&findReadable, "readable", "", false, "file is readable")
 	findCmd.Flags().BoolVarP(&findWritable, "writable", "", false, "file is writable")
 	findCmd.Flags().BoolVarP(&findPrint0, "print0", "0", false, "print with null terminator")
+	findCmd.Flags().BoolVar(&findPrint0, "null", false, "same as -print0")
 	findCmd.Flags().BoolVarP(&findNot, "not", "", false, "negate next test")
+	findCmd.Flags().StringVarP(&findFilesFrom, "files-from", "", "", "test the paths listed in FILE (- for stdin) instead of walking")
 
 	// Preprocess os.Args to convert -flag to --flag for find command
 	preprocessFindArgs()
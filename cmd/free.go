@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
+
 	"github.com/inovacc/omni/internal/cli/free"
 	"github.com/spf13/cobra"
 )
@@ -16,14 +19,21 @@ in the system, as well as the buffers and caches used by the kernel.
   -k, --kibibytes     show output in kibibytes (default)
   -m, --mebibytes     show output in mebibytes
   -g, --gibibytes     show output in gibibytes
-  -h, --human         show human-readable output
+  -H, --human         show human-readable output
   -w, --wide          wide output
   -t, --total         show total for RAM + swap
+  -s, --seconds N     repeat printing every N seconds
+  -c, --count N       repeat printing N times, then exit
+
+"used" is total minus available memory, as in procps-ng; -w splits the
+buff/cache column into buffers and cache.
 
 Examples:
   omni free                       # memory usage in kibibytes
-  omni free -h                    # human-readable output
-  omni free -m -t                 # mebibytes with a RAM+swap total`,
+  omni free -H                    # human-readable output
+  omni free -m -t                 # mebibytes with a RAM+swap total
+  omni free -H -s 2               # refresh every 2 seconds until Ctrl+C
+  omni free -c 5 --json           # five JSON samples, one second apart`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := free.FreeOptions{}
 
@@ -34,9 +44,14 @@ Examples:
 		opts.Human, _ = cmd.Flags().GetBool("human")
 		opts.Wide, _ = cmd.Flags().GetBool("wide")
 		opts.Total, _ = cmd.Flags().GetBool("total")
+		opts.Seconds, _ = cmd.Flags().GetInt("seconds")
+		opts.Count, _ = cmd.Flags().GetInt("count")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
-		return free.RunFree(cmd.OutOrStdout(), opts)
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		return free.RunFree(ctx, cmd.OutOrStdout(), opts)
 	},
 }
 
@@ -50,5 +65,6 @@ func init() {
 	freeCmd.Flags().BoolP("human", "H", false, "show human-readable output")
 	freeCmd.Flags().BoolP("wide", "w", false, "wide output")
 	freeCmd.Flags().BoolP("total", "t", false, "show total for RAM + swap")
-
+	freeCmd.Flags().IntP("seconds", "s", 0, "repeat printing every N seconds")
+	freeCmd.Flags().IntP("count", "c", 0, "repeat printing N times, then exit")
 }
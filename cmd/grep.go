@@ -17,8 +17,19 @@ Examples:
   omni grep error log.txt         # print lines containing "error"
   omni grep -i warn log.txt       # case-insensitive search
   omni grep -rn TODO src/         # recursive search with line numbers
-  cat log.txt | omni grep error   # search stdin`,
-	Args: cobra.MinimumNArgs(1),
+  cat log.txt | omni grep error   # search stdin
+  omni grep -e error -e warn log  # several patterns
+  omni grep -F -f iocs.txt -r .   # hundreds of fixed strings in one pass
+
+With -e or -f, every argument is a FILE. Fixed-string pattern sets (-F)
+are matched with an Aho-Corasick automaton in a single pass per line.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("regexp") || cmd.Flags().Changed("file") {
+			return nil
+		}
+
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := grep.GrepOptions{}
 
@@ -41,10 +52,16 @@ Examples:
 		opts.AfterContext, _ = cmd.Flags().GetInt("after-context")
 		opts.MaxCount, _ = cmd.Flags().GetInt("max-count")
 		opts.Recursive, _ = cmd.Flags().GetBool("recursive")
+		opts.Patterns, _ = cmd.Flags().GetStringArray("regexp")
+		opts.PatternFiles, _ = cmd.Flags().GetStringArray("file")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
-		pattern := args[0]
-		files := args[1:]
+		var pattern string
+
+		files := args
+		if len(opts.Patterns) == 0 && len(opts.PatternFiles) == 0 {
+			pattern, files = args[0], args[1:]
+		}
 
 		return grep.RunGrep(cmd.OutOrStdout(), cmd.InOrStdin(), pattern, files, opts)
 	},
@@ -59,6 +76,8 @@ func init() {
 	grepCmd.Flags().BoolP("ignore-case", "i", false, "ignore case distinctions in patterns and data")
 	grepCmd.Flags().BoolP("word-regexp", "w", false, "match only whole words")
 	grepCmd.Flags().BoolP("line-regexp", "x", false, "match only whole lines")
+	grepCmd.Flags().StringArrayP("regexp", "e", nil, "use PATTERN for matching (repeatable)")
+	grepCmd.Flags().StringArrayP("file", "f", nil, "take patterns from FILE, one per line (repeatable)")
 
 	// Matching control
 	grepCmd.Flags().BoolP("invert-match", "v", false, "select non-matching lines")
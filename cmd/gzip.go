@@ -12,6 +12,7 @@ var (
 	gzipStdout     bool
 	gzipVerbose    bool
 	gzipLevel      int
+	gzipThreads    int
 )
 
 var gzipCmd = &cobra.Command{
@@ -25,12 +26,20 @@ var gzipCmd = &cobra.Command{
   -c, --stdout       write to stdout
   -v, --verbose      verbose mode
   -1 to -9           compression level (default 6)
+  -T, --threads=N    compress on N cores (default: all)
+
+Large inputs are compressed in parallel 1 MiB blocks, each primed with the
+end of the block before it, so the ratio stays within a fraction of a percent
+of single-threaded gzip. The output is one standard gzip stream that any
+gunzip reads, and it is the same for every --threads value. Memory use is
+bounded by the number of threads, not the size of the input.
 
 Examples:
   omni gzip file.txt           # compress to file.txt.gz
   omni gzip -d file.txt.gz     # decompress
   omni gzip -k file.txt        # keep original
-  omni gzip -c file.txt > out.gz  # write to stdout`,
+  omni gzip -c file.txt > out.gz  # write to stdout
+  omni gzip -k -T 4 release.tar   # compress on four cores`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := gzip.GzipOptions{
 			Decompress: gzipDecompress,
@@ -39,6 +48,7 @@ Examples:
 			Stdout:     gzipStdout,
 			Verbose:    gzipVerbose,
 			Level:      gzipLevel,
+			Threads:    gzipThreads,
 		}
 
 		return gzip.RunGzip(cmd.OutOrStdout(), args, opts)
@@ -95,6 +105,7 @@ func init() {
 	gzipCmd.Flags().BoolVarP(&gzipVerbose, "verbose", "v", false, "verbose mode")
 	gzipCmd.Flags().IntVarP(&gzipLevel, "fast", "1", 0, "compress faster")
 	gzipCmd.Flags().IntVarP(&gzipLevel, "best", "9", 0, "compress better")
+	gzipCmd.Flags().IntVarP(&gzipThreads, "threads", "T", 0, "compression threads (0 = one per CPU)")
 
 	gunzipCmd.Flags().BoolVarP(&gzipKeep, "keep", "k", false, "keep original files")
 	gunzipCmd.Flags().BoolVarP(&gzipForce, "force", "f", false, "force overwrite")
@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/hash"
+	"github.com/inovacc/omni/pkg/hashutil"
 	"github.com/spf13/cobra"
 )
 
@@ -13,8 +19,17 @@ var hashCmd = &cobra.Command{
 
 With no FILE, or when FILE is -, read standard input.
 
-  -a, --algorithm ALG  hash algorithm: md5, sha1, sha256 (default), sha512, crc32, crc64, blake2b
-  -c, --check          read checksums from FILE and check them
+  -a, --algorithm ALG  hash algorithm: md5, sha1, sha224, sha256 (default), sha384,
+                       sha512, crc32, crc64, blake2b (= blake2b-256), blake2b-512,
+                       blake3, and the non-cryptographic xxh64 and xxh3
+  -c, --check          read checksums from FILE and check them; GNU-style
+                       "HASH  FILE" and BSD-style "SHA256 (FILE) = HASH"
+                       lines are both read, and a BSD line names its algorithm
+      --create-manifest
+                       hash every file under each DIR into a manifest
+      --tag            print BSD-style lines
+  -j, --jobs N         files hashed in parallel by --create-manifest and
+                       --check (default: number of CPUs)
   -b, --binary         read in binary mode
   -r, --recursive      hash files recursively in directories
       --quiet          don't print OK for each verified file
@@ -24,9 +39,16 @@ With no FILE, or when FILE is -, read standard input.
 Examples:
   omni hash file.txt                    # SHA256 hash
   omni hash -a md5 file.txt             # MD5 hash
+  omni hash -a blake3 backup.tar        # fast cryptographic hash for large files
+  omni hash -a xxh3 -r ./backups        # fastest, non-cryptographic checksums
   omni hash -r ./dir                    # hash all files in directory
   omni hash -c checksums.txt            # verify checksums
-  omni hash file1 file2 > checksums.txt # create checksum file`,
+  omni hash file1 file2 > checksums.txt # create checksum file
+  omni hash --create-manifest dir/ > SUMS
+  omni hash --check SUMS                # per-file OK/FAILED, --json for a report
+  omni hash --create-manifest --tag -a blake3 -j 8 /srv/data > B3SUMS
+  omni hash compare a.bin b.bin         # compare two files by digest
+  omni hash tree --watch /etc/app       # manifest, then NDJSON change digests`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := hash.HashOptions{}
 
@@ -37,20 +59,128 @@ Examples:
 		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
 		opts.Status, _ = cmd.Flags().GetBool("status")
 		opts.Warn, _ = cmd.Flags().GetBool("warn")
+		opts.Manifest, _ = cmd.Flags().GetBool("create-manifest")
+		opts.Tag, _ = cmd.Flags().GetBool("tag")
+		opts.Jobs, _ = cmd.Flags().GetInt("jobs")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
 		return hash.RunHash(cmd.OutOrStdout(), args, opts)
 	},
 }
 
+// hashCompareCmd represents the hash compare command
+var hashCompareCmd = &cobra.Command{
+	Use:   "compare [OPTION]... FILE1 FILE2",
+	Short: "Compare two files by digest in constant time",
+	Long: `Hash FILE1 and FILE2 and report whether their digests are equal.
+
+Both files are streamed, so files of any size compare without loading them
+into memory, and the digests are compared in constant time: the time taken
+does not reveal where the files first differ. Either file may be - for
+standard input.
+
+  -a, --algorithm ALG  hash algorithm (default sha256)
+  -q, --quiet          print nothing; the exit status tells
+
+Exit status:
+  0  files are identical
+  1  files differ, or a file does not exist
+  2+ other errors (2 for bad usage, 4 for read errors)
+
+Examples:
+  omni hash compare release.tar.gz mirror.tar.gz
+  omni hash compare -q -a blake2b a.bin b.bin && echo same
+  curl -s https://example.com/key | omni hash compare - key.pem`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := hash.CompareOptions{}
+
+		opts.Algorithm, _ = cmd.Flags().GetString("algorithm")
+		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return hash.RunCompare(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+// hashTreeCmd represents the hash tree command
+var hashTreeCmd = &cobra.Command{
+	Use:   "tree [OPTION]... [DIR]",
+	Short: "Hash a directory tree and optionally watch it for changes",
+	Long: `Hash every regular file under DIR (default .) and print a manifest of
+paths relative to DIR, in the same "HASH  PATH" format as omni hash, so it can
+be verified from DIR with omni hash -c. The JSON output adds a tree digest
+that changes whenever any path or content does.
+
+With --watch the manifest is built but not printed: a "ready" event carries
+its file count and tree digest, then DIR is rescanned every --interval and
+each file whose digest changed prints one JSON event per line (NDJSON):
+
+  {"time":"...","event":"modified","path":"app.conf","hash":"...","previous":"...",
+   "size":120,"algorithm":"sha256","digest":"..."}
+
+Events are created, modified, deleted and error. Only files whose size or
+modification time changed are read again, and a file that is touched but
+not changed prints nothing. Stop watching with Ctrl-C.
+
+  -a, --algorithm ALG  hash algorithm (default sha256)
+      --watch          keep watching and print change events
+      --interval DUR   time between rescans with --watch (default 2s)
+
+Examples:
+  omni hash tree /etc/app > app.sha256
+  omni hash tree --json -a blake2b ./dist
+  omni hash tree --watch --interval 10s /etc/nginx >> /var/log/nginx-integrity.ndjson`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := hash.TreeOptions{}
+
+		opts.Algorithm, _ = cmd.Flags().GetString("algorithm")
+		opts.Watch, _ = cmd.Flags().GetBool("watch")
+		opts.Interval, _ = flagvalue.GetDuration(cmd.Flags(), "interval")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return hash.RunTree(ctx, cmd.OutOrStdout(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(hashCmd)
+	hashCmd.AddCommand(hashCompareCmd)
+	hashCmd.AddCommand(hashTreeCmd)
 
-	hashCmd.Flags().StringP("algorithm", "a", "sha256", "hash algorithm (md5, sha1, sha256, sha512, crc32, crc64, blake2b)")
+	hashCmd.Flags().StringP("algorithm", "a", "sha256", "hash algorithm (md5, sha1, sha256, sha512, crc32, crc64, blake2b, blake2b-512, blake3, xxh64, xxh3)")
+	_ = hashCmd.RegisterFlagCompletionFunc("algorithm", completeHashAlgorithms)
 	hashCmd.Flags().BoolP("check", "c", false, "read checksums from FILE and check them")
 	hashCmd.Flags().BoolP("binary", "b", false, "read in binary mode")
 	hashCmd.Flags().BoolP("recursive", "r", false, "hash files recursively")
 	hashCmd.Flags().Bool("quiet", false, "don't print OK for verified files")
 	hashCmd.Flags().Bool("status", false, "don't output anything, use status code")
 	hashCmd.Flags().BoolP("warn", "w", false, "warn about improperly formatted lines")
+	hashCmd.Flags().Bool("create-manifest", false, "hash every file under each DIR into a checksum manifest")
+	hashCmd.Flags().Bool("tag", false, "print BSD-style \"ALGO (FILE) = HASH\" lines")
+	hashCmd.Flags().IntP("jobs", "j", 0, "files hashed in parallel by --create-manifest and --check (default: number of CPUs)")
+	hashCmd.MarkFlagsMutuallyExclusive("check", "create-manifest")
+
+	hashCompareCmd.Flags().StringP("algorithm", "a", "sha256", "hash algorithm (md5, sha1, sha256, sha512, crc32, crc64, blake2b, blake2b-512, blake3, xxh64, xxh3)")
+	_ = hashCompareCmd.RegisterFlagCompletionFunc("algorithm", completeHashAlgorithms)
+	hashCompareCmd.Flags().BoolP("quiet", "q", false, "print nothing; the exit status tells")
+
+	hashTreeCmd.Flags().StringP("algorithm", "a", "sha256", "hash algorithm (md5, sha1, sha256, sha512, crc32, crc64, blake2b, blake2b-512, blake3, xxh64, xxh3)")
+	_ = hashTreeCmd.RegisterFlagCompletionFunc("algorithm", completeHashAlgorithms)
+	hashTreeCmd.Flags().Bool("watch", false, "keep watching and print change events as NDJSON")
+	flagvalue.DurationP(hashTreeCmd.Flags(), "interval", "", hash.DefaultTreeInterval, "time between rescans with --watch")
+}
+
+// completeHashAlgorithms completes -a with the algorithms hashutil supports.
+func completeHashAlgorithms(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, a := range hashutil.Algorithms() {
+		names = append(names, string(a))
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
@@ -19,19 +19,43 @@ var headCmd = &cobra.Command{
 With more than one FILE, precede each with a header giving the file name.
 With no FILE, or when FILE is -, read standard input.
 
+  -n, --lines [-]NUM   print the first NUM lines instead of the first 10;
+                       with a leading '-', print all but the last NUM lines
+  -c, --bytes [-]NUM   print the first NUM bytes; with a leading '-', print
+                       all but the last NUM bytes
+  -q, --quiet          never print headers giving file names
+  -v, --verbose        always print headers giving file names
+
+NUM may have a size suffix: b 512, K or KiB 1024, KB 1000, M or MiB
+1024*1024, MB 1000*1000, and so on for G, T, P and E.
 Numeric shortcuts are supported: -80 is equivalent to -n 80.
 
 Examples:
   omni head file.txt              # first 10 lines
   omni head -n 20 file.txt        # first 20 lines
   omni head -c 100 file.txt       # first 100 bytes
+  omni head -n -3 file.txt        # all but the last 3 lines
+  omni head -c -1K file.bin       # all but the last 1024 bytes
   omni head -5 file.txt           # numeric shortcut for -n 5
   cat file.txt | omni head        # read from stdin`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := head.HeadOptions{}
 
-		opts.Lines, _ = cmd.Flags().GetInt("lines")
-		opts.Bytes, _ = cmd.Flags().GetInt("bytes")
+		lines, _ := cmd.Flags().GetString("lines")
+		bytes, _ := cmd.Flags().GetString("bytes")
+
+		var err error
+
+		if opts.Lines, err = head.ParseCount(lines); err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("bytes") {
+			if opts.Bytes, err = head.ParseCount(bytes); err != nil {
+				return err
+			}
+		}
+
 		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
 		opts.Verbose, _ = cmd.Flags().GetBool("verbose")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
@@ -43,8 +67,8 @@ Examples:
 func init() {
 	rootCmd.AddCommand(headCmd)
 
-	headCmd.Flags().IntP("lines", "n", 10, "print the first NUM lines instead of the first 10")
-	headCmd.Flags().IntP("bytes", "c", 0, "print the first NUM bytes of each file")
+	headCmd.Flags().StringP("lines", "n", "10", "print the first NUM lines instead of the first 10; with a leading '-', all but the last NUM lines")
+	headCmd.Flags().StringP("bytes", "c", "", "print the first NUM bytes of each file; with a leading '-', all but the last NUM bytes")
 	headCmd.Flags().BoolP("quiet", "q", false, "never print headers giving file names")
 	headCmd.Flags().BoolP("verbose", "v", false, "always print headers giving file names")
 	// Preprocess os.Args to convert -NUM to -n NUM for head command
@@ -74,7 +98,13 @@ func preprocessHeadArgs() {
 	// Rewrite -NUM to -n NUM
 	newArgs := make([]string, 0, len(os.Args)+1)
 
-	for _, arg := range os.Args {
+	for i, arg := range os.Args {
+		// Leave option values such as -n -5 alone
+		if i > 0 && isCountFlag(os.Args[i-1]) {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
 		if matches := numericFlagRegex.FindStringSubmatch(arg); matches != nil {
 			newArgs = append(newArgs, "-n", matches[1])
 		} else {
@@ -84,3 +114,14 @@ func preprocessHeadArgs() {
 
 	os.Args = newArgs
 }
+
+// isCountFlag reports whether arg is a head/tail count option that takes
+// the next argument as its value.
+func isCountFlag(arg string) bool {
+	switch arg {
+	case "-n", "-c", "--lines", "--bytes":
+		return true
+	}
+
+	return false
+}
@@ -1,6 +1,13 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
+	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 	"github.com/spf13/cobra"
 )
@@ -12,6 +19,73 @@ import (
 func getOutputOpts(cmd *cobra.Command) output.Options {
 	j, _ := cmd.Flags().GetBool("json")
 	tbl, _ := cmd.Flags().GetBool("table")
+	md, _ := cmd.Flags().GetBool("markdown")
+	csv, _ := cmd.Flags().GetBool("csv")
+
+	return output.Options{JSON: j, Table: tbl, Markdown: md, CSV: csv}
+}
+
+// addConfirmFlags registers the shared --dry-run, --interactive and --yes
+// flags of destructive commands. interactiveShort is the -i style shorthand,
+// empty when the command already uses -i for something else (sed). A
+// command that already defines --dry-run keeps its own flag.
+func addConfirmFlags(cmd *cobra.Command, interactiveShort string) {
+	if cmd.Flags().Lookup("dry-run") == nil {
+		cmd.Flags().Bool("dry-run", false, "print what would be done without doing it")
+	}
+
+	cmd.Flags().BoolP("interactive", interactiveShort, false, "prompt before each destructive action (needs a terminal)")
+	cmd.Flags().BoolP("yes", "y", false, "answer yes to every prompt")
+}
+
+// getConfirmOpts reads the flags registered by addConfirmFlags.
+func getConfirmOpts(cmd *cobra.Command) confirm.Options {
+	var opts confirm.Options
+
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Interactive, _ = cmd.Flags().GetBool("interactive")
+	opts.Yes, _ = cmd.Flags().GetBool("yes")
+
+	return opts
+}
+
+// addFileListFlags registers --files-from and -0/--null, which read more
+// path operands from a list such as find -print0 writes, so names with
+// spaces or newlines survive a pipeline.
+func addFileListFlags(cmd *cobra.Command) {
+	cmd.Flags().String("files-from", "", "also operate on the paths listed in FILE (- for stdin)")
+	cmd.Flags().BoolP("null", "0", false, "read NUL-separated paths from --files-from or stdin")
+}
+
+// fileListArgs returns the paths read by the flags of addFileListFlags:
+// the --files-from list (NUL or newline separated), or with -0 a strictly
+// NUL-separated list from --files-from or, by default, stdin. It returns
+// nil when neither flag is set.
+func fileListArgs(cmd *cobra.Command) ([]string, error) {
+	from, _ := cmd.Flags().GetString("files-from")
+	null, _ := cmd.Flags().GetBool("null")
+
+	if from == "" && !null {
+		return nil, nil
+	}
+
+	if from == "" {
+		from = "-"
+	}
+
+	read := input.ReadFileList
+	if null {
+		read = input.ReadNullList
+	}
+
+	paths, err := read(from, cmd.InOrStdin())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("%s: %s", cmd.Name(), err))
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("%s: %s", cmd.Name(), err))
+	}
 
-	return output.Options{JSON: j, Table: tbl}
+	return paths, nil
 }
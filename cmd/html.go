@@ -15,6 +15,8 @@ Subcommands:
   fmt       Format/beautify HTML
   minify    Minify HTML
   validate  Validate HTML syntax
+  audit     Check accessibility and SEO (alt text, headings, lang, ids)
+  rewrite   Rewrite resource URLs (absolute, CDN prefix, inline assets)
   encode    HTML encode text (escape special characters)
   decode    HTML decode text (unescape entities)
 
@@ -22,6 +24,8 @@ Examples:
   omni html fmt file.html
   omni html minify file.html
   omni html validate file.html
+  omni html audit dist/*.html
+  omni html rewrite --base https://cdn.example.com/ index.html
   omni html encode "<script>alert('xss')</script>"
   omni html decode "&lt;div&gt;content&lt;/div&gt;"`,
 }
@@ -125,6 +129,81 @@ Examples:
 	},
 }
 
+var htmlAuditCmd = &cobra.Command{
+	Use:   "audit [FILE...]",
+	Short: "Check HTML for accessibility and SEO problems",
+	Long: `Check HTML for accessibility and SEO problems without a browser.
+
+Rules (default severity):
+  img-alt           images, image inputs and areas need alt (error)
+  heading-order     heading levels must not skip, e.g. h2 then h4 (warning)
+  html-lang         <html> must declare a lang (error)
+  meta-description  document needs a meta description (warning)
+  document-title    document needs a non-empty <title> (error)
+  duplicate-id      id attributes must be unique (error)
+
+The html-lang, meta-description and document-title rules only apply to
+full documents (with a doctype, <html> or <head>), not to fragments.
+
+Exit codes:
+  0  No issue at or above the --fail-on severity
+  1  Issues found, or error
+
+  --severity=RULE=LEVEL  set a rule's severity: error, warning, info or off (repeatable)
+  --fail-on=LEVEL        lowest severity that fails the command (default error; off never fails)
+  --list-rules           list the rules and their default severities
+  --json                 output reports as JSON
+
+Examples:
+  omni html audit index.html
+  omni html audit --fail-on warning dist/*.html
+  omni html audit --severity heading-order=off --severity meta-description=error site.html
+  omni html audit --json index.html`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := htmlfmt.AuditOptions{}
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+		opts.Severities, _ = cmd.Flags().GetStringArray("severity")
+		opts.FailOn, _ = cmd.Flags().GetString("fail-on")
+		opts.ListRules, _ = cmd.Flags().GetBool("list-rules")
+
+		return htmlfmt.RunAudit(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+var htmlRewriteCmd = &cobra.Command{
+	Use:   "rewrite [FILE...]",
+	Short: "Rewrite resource URLs in HTML",
+	Long: `Rewrite resource URLs (href, src, srcset, poster, action) in HTML.
+
+Only tags whose URLs change are re-serialized; all other markup, whitespace
+and comments are kept as they are. Absolute URLs, fragments and data URIs
+are never touched.
+
+  --base=URL         resolve relative URLs against URL
+  --prefix=URL       prepend URL to relative asset URLs (links between pages are kept)
+  --inline-max=N     inline local assets of at most N bytes as data URIs
+  --root=DIR         directory local assets are read from (default: the file's directory)
+  -i, --in-place     rewrite the given files in place
+
+Inlining runs first, so only assets that stay external get --base or --prefix.
+
+Examples:
+  omni html rewrite --base https://cdn.example.com/site/ index.html
+  omni html rewrite --prefix https://cdn.example.com/v2 -i dist/*.html
+  omni html rewrite --inline-max 4096 --root dist -i dist/index.html
+  cat page.html | omni html rewrite --base https://example.com/`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := htmlfmt.RewriteOptions{}
+		opts.Base, _ = cmd.Flags().GetString("base")
+		opts.Prefix, _ = cmd.Flags().GetString("prefix")
+		opts.InlineMax, _ = cmd.Flags().GetInt64("inline-max")
+		opts.Root, _ = cmd.Flags().GetString("root")
+		opts.InPlace, _ = cmd.Flags().GetBool("in-place")
+
+		return htmlfmt.RunRewrite(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(htmlCmd)
 	htmlCmd.AddCommand(htmlEncodeCmd)
@@ -132,6 +211,8 @@ func init() {
 	htmlCmd.AddCommand(htmlFmtCmd)
 	htmlCmd.AddCommand(htmlMinifyCmd)
 	htmlCmd.AddCommand(htmlValidateCmd)
+	htmlCmd.AddCommand(htmlRewriteCmd)
+	htmlCmd.AddCommand(htmlAuditCmd)
 
 	// html encode/decode use --json from root persistent flag
 
@@ -140,4 +221,16 @@ func init() {
 	htmlFmtCmd.Flags().Bool("sort-attrs", false, "sort attributes alphabetically")
 
 	// html validate flags (--json provided by root persistent flag)
+
+	// html audit flags
+	htmlAuditCmd.Flags().StringArray("severity", nil, "set a rule's severity as RULE=LEVEL (repeatable)")
+	htmlAuditCmd.Flags().String("fail-on", "error", "lowest severity that fails the command")
+	htmlAuditCmd.Flags().Bool("list-rules", false, "list audit rules")
+
+	// html rewrite flags
+	htmlRewriteCmd.Flags().String("base", "", "resolve relative URLs against URL")
+	htmlRewriteCmd.Flags().String("prefix", "", "prepend URL to relative asset URLs")
+	htmlRewriteCmd.Flags().Int64("inline-max", 0, "inline local assets up to N bytes as data URIs")
+	htmlRewriteCmd.Flags().String("root", "", "directory local assets are read from")
+	htmlRewriteCmd.Flags().BoolP("in-place", "i", false, "rewrite files in place")
 }
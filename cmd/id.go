@@ -21,7 +21,11 @@ or (when USER omitted) for the current user.
 Examples:
   omni id                         # full id for the current user
   omni id -u                      # effective user ID only
-  omni id -un                     # effective user name`,
+  omni id -un                     # effective user name
+
+Subcommands:
+  inspect   Detect the type of an ID and decode its components
+  new       Generate typed IDs such as cus_01h89d1yg0dpkcwar9g3reha97`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := id.IDOptions{}
 
@@ -40,13 +44,109 @@ Examples:
 	},
 }
 
+// idInspectCmd represents the id inspect command
+var idInspectCmd = &cobra.Command{
+	Use:   "inspect [OPTION]... [ID]...",
+	Short: "Detect the type of an ID and decode its components",
+	Long: `Detect the type of each ID and print the components it embeds. IDs are
+read one per line from standard input when none are given.
+
+  uuid       version, variant; v1/v6: time, node and clock sequence;
+             v7: time and random bits; v4: random bits
+  ulid       millisecond time and 80 random bits
+  ksuid      second time and 128 random bits
+  snowflake  millisecond time, 10 worker bits and 12 sequence bits
+  nanoid     random bits (21 characters of A-Za-z0-9_-)
+  typed      type prefix, ULID or UUIDv7 body (as a UUID too) and checksum,
+             as made by omni id new
+
+Detection goes by shape: 36 characters with dashes or 32 hex digits is a
+UUID, 26 Crockford base32 characters a ULID, 27 base62 characters a KSUID,
+up to 19 digits a Snowflake, 21 URL-safe characters a NanoID, and PREFIX_
+and 26 or 30 Crockford base32 characters a typed ID. Use --type
+for IDs that do not fit, such as NanoIDs of another length.
+
+Snowflake IDs carry no epoch; the default is the one omni snowflake uses
+(2020-01-01). --epoch twitter and --epoch discord select those services'
+epochs; Unix milliseconds or an RFC 3339 time set any other.
+
+With several IDs, every output line starts with the ID it belongs to.
+
+  -t, --type=TYPE    uuid, ulid, ksuid, snowflake, nanoid or typed (default: detect)
+      --epoch=EPOCH  Snowflake epoch: omni, twitter, discord, Unix ms or RFC 3339
+  --json             output as JSON
+
+Examples:
+  omni id inspect 018df9e2-b200-7abc-8def-0123456789ab
+  omni id inspect 01HQ3Z5N2M8V4W7X9Y0Z1A2B3C 2dOGAhVWBGbAHiRoLJ8ZXEKdVVw
+  omni id inspect --epoch twitter 1491029658998247427
+  grep -o 'req=[^ ]*' app.log | cut -d= -f2 | omni id inspect --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := id.InspectOptions{}
+
+		opts.Type, _ = cmd.Flags().GetString("type")
+		opts.Epoch, _ = cmd.Flags().GetString("epoch")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return id.RunInspect(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+// idNewCmd represents the id new command
+var idNewCmd = &cobra.Command{
+	Use:   "new [OPTION]... PREFIX",
+	Short: "Generate typed IDs with a type prefix",
+	Long: `Generate public IDs that say what they identify, like Stripe's cus_ and
+pi_ IDs: PREFIX_BODY, where BODY is a ULID, or with --uuid7 a UUIDv7, in 26
+lowercase Crockford base32 characters. The IDs are URL-safe, sort by
+creation time and decode with omni id inspect.
+
+PREFIX is 1 to 63 lowercase letters, digits and underscores, starting with
+a letter. Without --checksum the IDs follow the TypeID format, and a UUIDv7
+body is the UUID a database can store. --checksum appends 4 characters that
+catch typos and truncation when the ID is parsed.
+
+  -n, --count=N   generate N IDs (default 1)
+      --uuid7     UUIDv7 body instead of a ULID
+      --checksum  append a 4-character checksum
+      --monotonic strictly increasing order, even within one millisecond
+  --json          output as JSON
+
+Examples:
+  omni id new cus                   # cus_01h89d1yg0dpkcwar9g3reha97
+  omni id new --checksum inv        # inv_01h89d1yg0dpkcwar9g3reha975mwn
+  omni id new --uuid7 -n 100 --monotonic evt
+  omni id inspect cus_01h89d1yg0dpkcwar9g3reha97`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := id.NewOptions{}
+
+		opts.Count, _ = cmd.Flags().GetInt("count")
+		opts.UUID, _ = cmd.Flags().GetBool("uuid7")
+		opts.Checksum, _ = cmd.Flags().GetBool("checksum")
+		opts.Monotonic, _ = cmd.Flags().GetBool("monotonic")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return id.RunNew(cmd.OutOrStdout(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(idCmd)
+	idCmd.AddCommand(idInspectCmd)
+	idCmd.AddCommand(idNewCmd)
+
+	idNewCmd.Flags().IntP("count", "n", 1, "generate N IDs")
+	idNewCmd.Flags().Bool("uuid7", false, "UUIDv7 body instead of a ULID")
+	idNewCmd.Flags().Bool("checksum", false, "append a 4-character checksum")
+	idNewCmd.Flags().Bool("monotonic", false, "strictly increasing order, even within one millisecond")
+
+	idInspectCmd.Flags().StringP("type", "t", "", "decode as TYPE: uuid, ulid, ksuid, snowflake, nanoid, typed (default: detect)")
+	idInspectCmd.Flags().String("epoch", "", "Snowflake epoch: omni, twitter, discord, Unix ms or RFC 3339")
 
 	idCmd.Flags().BoolP("user", "u", false, "print only the effective user ID")
 	idCmd.Flags().BoolP("group", "g", false, "print only the effective group ID")
 	idCmd.Flags().BoolP("groups", "G", false, "print all group IDs")
 	idCmd.Flags().BoolP("name", "n", false, "print a name instead of a number")
 	idCmd.Flags().BoolP("real", "r", false, "print the real ID instead of the effective ID")
-
 }
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/ifstat"
+	"github.com/spf13/cobra"
+)
+
+// ifstatCmd represents the ifstat command
+var ifstatCmd = &cobra.Command{
+	Use:   "ifstat [OPTION]... [DELAY [COUNT]]",
+	Short: "Report network interface throughput",
+	Long: `Report per-interface receive and transmit rates, sampled every DELAY
+seconds (default 1). Prints COUNT reports, or runs until interrupted when
+COUNT is omitted. Loopback interfaces are hidden unless -a is given.
+
+  -i, --interface NAME  only report NAME (repeatable or comma-separated)
+  -a, --all             include loopback interfaces
+  -b, --bytes           print raw bytes per second instead of K/M/G
+
+Examples:
+  omni ifstat                     # rates every second until Ctrl+C
+  omni ifstat 5 3                 # three 5-second reports
+  omni ifstat -i eth0 -b 1 1      # one report for eth0 in bytes/s
+  omni ifstat --json 2 1          # one 2-second report as JSON`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ifstat.Options{}
+
+		opts.Interfaces, _ = cmd.Flags().GetStringSlice("interface")
+		opts.All, _ = cmd.Flags().GetBool("all")
+		opts.Bytes, _ = cmd.Flags().GetBool("bytes")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		if len(args) > 0 {
+			secs, err := strconv.ParseFloat(args[0], 64)
+			if err != nil || secs <= 0 {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ifstat: invalid delay: %s", args[0]))
+			}
+
+			opts.Interval = time.Duration(secs * float64(time.Second))
+		}
+
+		if len(args) > 1 {
+			count, err := strconv.Atoi(args[1])
+			if err != nil || count <= 0 {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ifstat: invalid count: %s", args[1]))
+			}
+
+			opts.Count = count
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		return ifstat.Run(ctx, cmd.OutOrStdout(), opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ifstatCmd)
+
+	ifstatCmd.Flags().StringSliceP("interface", "i", nil, "only report these interfaces")
+	ifstatCmd.Flags().BoolP("all", "a", false, "include loopback interfaces")
+	ifstatCmd.Flags().BoolP("bytes", "b", false, "print raw bytes per second")
+}
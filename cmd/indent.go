@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/indent"
+	"github.com/spf13/cobra"
+)
+
+// indentCmd represents the indent command
+var indentCmd = &cobra.Command{
+	Use:   "indent [OPTION]... [FILE]...",
+	Short: "Detect or convert leading indentation",
+	Long: `Report the dominant indentation of each FILE, or convert leading
+indentation between tabs and spaces. Only the indentation at the start of
+each line is changed; tabs and spaces inside a line are left alone.
+With no FILE, or when FILE is -, read standard input.
+
+  --detect            report style (tabs, spaces or none), the width of
+                      one space indentation level, and whether tab- and
+                      space-indented lines are mixed
+  --to=STYLE          convert leading indentation to tabs or spaces
+  -w, --width=N       columns per indentation level; default is the
+                      detected width, or 4
+  -i, --in-place      rewrite FILEs instead of printing the result
+
+Examples:
+  omni indent --detect *.py                 # check before formatting
+  omni indent --detect --json src/main.c
+  omni indent --to=spaces -w 2 config.yaml  # tabs -> 2 spaces
+  omni indent --to=tabs -i Makefile.inc     # rewrite in place`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := indent.Options{}
+		opts.Detect, _ = cmd.Flags().GetBool("detect")
+		opts.To, _ = cmd.Flags().GetString("to")
+		opts.Width, _ = cmd.Flags().GetInt("width")
+		opts.InPlace, _ = cmd.Flags().GetBool("in-place")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return indent.Run(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(indentCmd)
+
+	indentCmd.Flags().Bool("detect", false, "report the dominant indentation of each file")
+	indentCmd.Flags().String("to", "", "convert leading indentation to tabs or spaces")
+	indentCmd.Flags().IntP("width", "w", 0, "columns per indentation level (default: detected, or 4)")
+	indentCmd.Flags().BoolP("in-place", "i", false, "rewrite files instead of printing the result")
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/join"
 	"github.com/spf13/cobra"
 )
@@ -11,21 +14,35 @@ var joinCmd = &cobra.Command{
 	Short: "Join lines of two files on a common field",
 	Long: `For each pair of input lines with identical join fields, write a line to
 standard output. The default join field is the first, delimited by blanks.
+Both files must be sorted on their join field (e.g. with omni sort -k); they
+are merged in a single pass, so memory use stays constant however large the
+files are.
 
   -1 FIELD       join on this FIELD of file 1
   -2 FIELD       join on this FIELD of file 2
   -t CHAR        use CHAR as input and output field separator
   -i             ignore differences in case when comparing fields
-  -a FILENUM     also print unpairable lines from file FILENUM
-  -v FILENUM     print only unpairable lines from file FILENUM
-  -e EMPTY       replace missing fields with EMPTY
+  -a FILENUM     also print unpairable lines from file FILENUM (repeatable)
+  -v FILENUM     print only unpairable lines from file FILENUM (repeatable)
+  -e EMPTY       replace missing fields with EMPTY (used with -o)
+  -o FORMAT      output fields as listed: 0 (join field) or FILENUM.FIELD,
+                 separated by commas or blanks; "auto" is the default layout
+  --header       treat the first line of each file as a header and print the
+                 joined headers first
+  --check-order  fail as soon as unsorted input is found
+  --nocheck-order  do not check that the input is sorted
+  -z             line delimiter is NUL, not newline
+
+Unsorted input is reported on standard error and makes join exit with
+status 1 after the output is written.
 
 When FILE1 or FILE2 is -, read standard input.
 
 Examples:
   omni join file1.txt file2.txt           # join on first field
   omni join -1 2 -2 1 file1.txt file2.txt # join field 2 of file1 with field 1 of file2
-  omni join -t ',' data1.csv data2.csv    # join CSV files`,
+  omni join -t ',' data1.csv data2.csv    # join CSV files
+  omni join --header -a 2 -e NA -o 0,1.2,2.2 users.txt orders.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := join.JoinOptions{}
 
@@ -34,21 +51,34 @@ Examples:
 		opts.Separator, _ = cmd.Flags().GetString("t")
 		opts.IgnoreCase, _ = cmd.Flags().GetBool("i")
 		opts.Empty, _ = cmd.Flags().GetString("e")
+		opts.OutputFields, _ = cmd.Flags().GetString("o")
+		opts.Header, _ = cmd.Flags().GetBool("header")
+		opts.CheckOrder, _ = cmd.Flags().GetBool("check-order")
+		opts.NoCheckOrder, _ = cmd.Flags().GetBool("nocheck-order")
+		opts.ZeroTerm, _ = cmd.Flags().GetBool("zero-terminated")
 
-		unpaired, _ := cmd.Flags().GetInt("a")
-		switch unpaired {
-		case 1:
-			opts.Unpaired1 = true
-		case 2:
-			opts.Unpaired2 = true
+		unpaired, _ := cmd.Flags().GetIntSlice("a")
+		for _, n := range unpaired {
+			switch n {
+			case 1:
+				opts.Unpaired1 = true
+			case 2:
+				opts.Unpaired2 = true
+			default:
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("join: invalid file number: %d", n))
+			}
 		}
 
-		onlyUnpaired, _ := cmd.Flags().GetInt("v")
-		switch onlyUnpaired {
-		case 1:
-			opts.OnlyUnpaired1 = true
-		case 2:
-			opts.OnlyUnpaired2 = true
+		onlyUnpaired, _ := cmd.Flags().GetIntSlice("v")
+		for _, n := range onlyUnpaired {
+			switch n {
+			case 1:
+				opts.OnlyUnpaired1 = true
+			case 2:
+				opts.OnlyUnpaired2 = true
+			default:
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("join: invalid file number: %d", n))
+			}
 		}
 
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
@@ -65,6 +95,11 @@ func init() {
 	joinCmd.Flags().StringP("t", "t", "", "use CHAR as input and output field separator")
 	joinCmd.Flags().BoolP("i", "i", false, "ignore case when comparing fields")
 	joinCmd.Flags().StringP("e", "e", "", "replace missing fields with EMPTY")
-	joinCmd.Flags().IntP("a", "a", 0, "also print unpairable lines from file FILENUM (1 or 2)")
-	joinCmd.Flags().IntP("v", "v", 0, "print only unpairable lines from file FILENUM (1 or 2)")
+	joinCmd.Flags().StringP("o", "o", "", "output fields as listed: 0 or FILENUM.FIELD")
+	joinCmd.Flags().Bool("header", false, "treat the first line of each file as a header")
+	joinCmd.Flags().Bool("check-order", false, "fail as soon as unsorted input is found")
+	joinCmd.Flags().Bool("nocheck-order", false, "do not check that the input is sorted")
+	joinCmd.Flags().BoolP("zero-terminated", "z", false, "line delimiter is NUL, not newline")
+	joinCmd.Flags().IntSliceP("a", "a", nil, "also print unpairable lines from file FILENUM (1 or 2, repeatable)")
+	joinCmd.Flags().IntSliceP("v", "v", nil, "print only unpairable lines from file FILENUM (1 or 2, repeatable)")
 }
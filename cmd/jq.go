@@ -26,12 +26,20 @@ This is a simplified implementation supporting common operations:
   -s          slurp: read all inputs into array
   -n          null input
   --tab       use tabs for indentation
+  --syntax    query language: jq (default), jsonpath, pointer
+
+Query syntaxes:
+  jq          .store.book[0].title
+  jsonpath    $.store.book[?(@.price < 10)].title   (filters, slices, ..descent)
+  pointer     /store/book/0/title                   (RFC 6901; missing paths are errors)
 
 Examples:
   echo '{"name":"John"}' | omni jq '.name'
   echo '[1,2,3]' | omni jq '.[]'
   echo '{"a":{"b":1}}' | omni jq '.a.b'
-  omni jq -r '.name' data.json`,
+  omni jq -r '.name' data.json
+  omni jq --syntax jsonpath '$..author' books.json
+  omni jq --syntax pointer '/store/book/0/title' books.json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := jq.JqOptions{}
 
@@ -41,6 +49,7 @@ Examples:
 		opts.NullInput, _ = cmd.Flags().GetBool("null-input")
 		opts.Tab, _ = cmd.Flags().GetBool("tab")
 		opts.Sort, _ = cmd.Flags().GetBool("sort-keys")
+		opts.Syntax, _ = cmd.Flags().GetString("syntax")
 
 		return jq.RunJq(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
 	},
@@ -55,4 +64,5 @@ func init() {
 	jqCmd.Flags().BoolP("null-input", "n", false, "don't read any input")
 	jqCmd.Flags().Bool("tab", false, "use tabs for indentation")
 	jqCmd.Flags().BoolP("sort-keys", "S", false, "sort object keys")
+	jqCmd.Flags().String("syntax", "jq", "query language: jq, jsonpath, pointer")
 }
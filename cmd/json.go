@@ -35,6 +35,8 @@ Subcommands:
   fromcsv   Convert CSV to JSON array
   toxml     Convert JSON to XML
   fromxml   Convert XML to JSON
+  diff      Structural diff (text, JSON Patch or Merge Patch)
+  patch     Apply a JSON Patch or Merge Patch
 
 Examples:
   omni json fmt file.json              # beautify JSON
@@ -49,7 +51,9 @@ Examples:
   omni json tocsv file.json            # convert to CSV
   omni json fromcsv file.csv           # convert from CSV
   omni json toxml file.json            # convert to XML
-  omni json fromxml file.xml           # convert from XML`,
+  omni json fromxml file.xml           # convert from XML
+  omni json diff a.json b.json --format patch
+  omni json patch config.json changes.json`,
 }
 
 // jsonFmtCmd formats JSON
@@ -63,11 +67,21 @@ var jsonFmtCmd = &cobra.Command{
   -t, --tab          use tabs for indentation
   -s, --sort-keys    sort object keys alphabetically
   -e, --escape-html  escape HTML characters (<, >, &)
+      --canonical    print the RFC 8785 (JCS) canonical form: compact,
+                     keys sorted by UTF-16 code units, ECMAScript number
+                     formatting; duplicate keys are rejected
+      --jsonc        accept // and /* */ comments and trailing commas
+
+Numbers are written back exactly as they appear in the input, so large
+integers do not lose precision. The canonical form is byte-for-byte stable
+and is what should be hashed or signed.
 
 Examples:
   omni json fmt file.json              # beautify with 2-space indent
   omni json fmt -t file.json           # use tabs
   omni json fmt -s file.json           # sort keys
+  omni json fmt --canonical payload.json | omni sha256sum
+  omni json fmt --jsonc tsconfig.json  # strip comments and trailing commas
   echo '{"b":2,"a":1}' | omni json fmt -s`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := jsonfmt.Options{}
@@ -76,6 +90,8 @@ Examples:
 		opts.Tab, _ = cmd.Flags().GetBool("tab")
 		opts.SortKeys, _ = cmd.Flags().GetBool("sort-keys")
 		opts.EscapeHTML, _ = cmd.Flags().GetBool("escape-html")
+		opts.Canonical, _ = cmd.Flags().GetBool("canonical")
+		opts.JSONC, _ = cmd.Flags().GetBool("jsonc")
 
 		return jsonfmt.RunJSONFmt(os.Stdout, args, opts)
 	},
@@ -88,14 +104,21 @@ var jsonMinifyCmd = &cobra.Command{
 	Short:   "Compact JSON by removing whitespace",
 	Long: `Remove all unnecessary whitespace from JSON.
 
+  -s, --sort-keys    sort object keys alphabetically
+      --canonical    print the RFC 8785 (JCS) canonical form
+      --jsonc        accept // and /* */ comments and trailing commas
+
 Examples:
   omni json minify file.json
   cat file.json | omni json minify
-  omni json minify -s file.json        # also sort keys`,
+  omni json minify -s file.json        # also sort keys
+  omni json minify --jsonc settings.json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := jsonfmt.Options{Minify: true}
 
 		opts.SortKeys, _ = cmd.Flags().GetBool("sort-keys")
+		opts.Canonical, _ = cmd.Flags().GetBool("canonical")
+		opts.JSONC, _ = cmd.Flags().GetBool("jsonc")
 
 		return jsonfmt.RunJSONFmt(os.Stdout, args, opts)
 	},
@@ -113,14 +136,18 @@ Exit codes:
   1  Invalid JSON or error
 
   --json    output result as JSON
+  --jsonc   accept // and /* */ comments and trailing commas
 
 Examples:
   omni json validate file.json
   omni json validate --json file.json
+  omni json validate --jsonc .vscode/settings.json
   echo '{"valid": true}' | omni json validate`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := jsonfmt.Options{Validate: true}
 
+		opts.JSONC, _ = cmd.Flags().GetBool("jsonc")
+
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
 		return jsonfmt.RunJSONFmt(cmd.OutOrStdout(), args, opts)
@@ -189,6 +216,71 @@ Examples:
 	},
 }
 
+// jsonDiffCmd compares two JSON documents
+var jsonDiffCmd = &cobra.Command{
+	Use:   "diff FILE1 FILE2",
+	Short: "Structural diff of two JSON documents",
+	Long: `Compare two JSON documents structurally.
+
+Formats:
+  text   one change per line: "+ /path: value", "- /path: value",
+         "~ /path: old -> new" (default)
+  patch  RFC 6902 JSON Patch turning FILE1 into FILE2
+  merge  RFC 7396 JSON Merge Patch turning FILE1 into FILE2 (arrays are
+         replaced whole; null values cannot be expressed)
+
+Paths are RFC 6901 JSON Pointers. Either file may be "-" for stdin. The
+patch can be applied with "omni json patch".
+
+  --format=FMT     text, patch or merge
+  -i, --indent=STR indentation for patch output (default "  ")
+
+Examples:
+  omni json diff old.json new.json
+  omni json diff a.json b.json --format patch > changes.json
+  omni json diff a.json b.json --format merge
+  curl -s $URL | omni json diff - expected.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := jsonfmt.DiffOptions{}
+		opts.Format, _ = cmd.Flags().GetString("format")
+		opts.Indent, _ = cmd.Flags().GetString("indent")
+
+		return jsonfmt.RunDiff(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+// jsonPatchCmd applies a JSON Patch or Merge Patch
+var jsonPatchCmd = &cobra.Command{
+	Use:   "patch FILE PATCH",
+	Short: "Apply a JSON Patch or Merge Patch to a document",
+	Long: `Apply an RFC 6902 JSON Patch (or, with --merge, an RFC 7396 JSON Merge
+Patch) to a JSON document and print the result.
+
+All operations are applied or none: if one fails (including a failed
+"test" operation) the command exits with an error and the document is not
+written. Either file may be "-" for stdin.
+
+  --merge          PATCH is a JSON Merge Patch
+  -i, --indent=STR indentation string (default "  ")
+  --in-place       write the result back to FILE
+
+Examples:
+  omni json patch config.json changes.json
+  omni json patch --merge config.json overrides.json
+  omni json diff a.json b.json --format patch | omni json patch a.json -
+  omni json patch --in-place deploy.json fix.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := jsonfmt.PatchOptions{}
+		opts.Merge, _ = cmd.Flags().GetBool("merge")
+		opts.Indent, _ = cmd.Flags().GetString("indent")
+		opts.InPlace, _ = cmd.Flags().GetBool("in-place")
+
+		return jsonfmt.RunPatch(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
 // jsonKeysCmd lists JSON keys
 var jsonKeysCmd = &cobra.Command{
 	Use:   "keys [FILE]",
@@ -549,15 +641,24 @@ func init() {
 	jsonCmd.AddCommand(jsonFromCSVCmd)
 	jsonCmd.AddCommand(jsonToXMLCmd)
 	jsonCmd.AddCommand(jsonFromXMLCmd)
+	jsonCmd.AddCommand(jsonDiffCmd)
+	jsonCmd.AddCommand(jsonPatchCmd)
 
 	// fmt flags
 	jsonFmtCmd.Flags().StringP("indent", "i", "  ", "indentation string")
 	jsonFmtCmd.Flags().BoolP("tab", "t", false, "use tabs for indentation")
 	jsonFmtCmd.Flags().BoolP("sort-keys", "s", false, "sort object keys")
 	jsonFmtCmd.Flags().BoolP("escape-html", "e", false, "escape HTML characters")
+	jsonFmtCmd.Flags().Bool("canonical", false, "print the RFC 8785 (JCS) canonical form")
+	jsonFmtCmd.Flags().Bool("jsonc", false, "accept comments and trailing commas")
 
 	// minify flags
 	jsonMinifyCmd.Flags().BoolP("sort-keys", "s", false, "sort object keys")
+	jsonMinifyCmd.Flags().Bool("canonical", false, "print the RFC 8785 (JCS) canonical form")
+	jsonMinifyCmd.Flags().Bool("jsonc", false, "accept comments and trailing commas")
+
+	// validate flags
+	jsonValidateCmd.Flags().Bool("jsonc", false, "accept comments and trailing commas")
 
 	// validate/stats/keys use --json from root persistent flag
 
@@ -589,6 +690,15 @@ func init() {
 	jsonToXMLCmd.Flags().String("item-tag", "item", "tag for array items")
 	jsonToXMLCmd.Flags().String("attr-prefix", "-", "prefix for attributes")
 
+	// diff flags
+	jsonDiffCmd.Flags().String("format", "text", "output format: text, patch (RFC 6902) or merge (RFC 7396)")
+	jsonDiffCmd.Flags().StringP("indent", "i", "  ", "indentation for patch output")
+
+	// patch flags
+	jsonPatchCmd.Flags().Bool("merge", false, "the patch is a JSON Merge Patch")
+	jsonPatchCmd.Flags().StringP("indent", "i", "  ", "indentation string")
+	jsonPatchCmd.Flags().Bool("in-place", false, "write the result back to FILE")
+
 	// fromxml flags
 	jsonFromXMLCmd.Flags().String("attr-prefix", "-", "prefix for attributes in JSON")
 	jsonFromXMLCmd.Flags().String("text-key", "#text", "key for text content")
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"os"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/kill"
 	"github.com/spf13/cobra"
 )
@@ -16,7 +20,9 @@ var killCmd = &cobra.Command{
   -v, --verbose        report successful signals
   -j, --json           output as JSON
 
-Signal can be specified by name (e.g., HUP, KILL, TERM) or number.
+Signal can be specified by name (e.g., HUP, SIGKILL, term) or number,
+either with -s or as the -SIGNAL shorthand. To kill processes by name
+instead of PID, use pgrep/pkill.
 Common signals:
    1) SIGHUP       2) SIGINT       3) SIGQUIT
    9) SIGKILL     15) SIGTERM (default)
@@ -25,6 +31,7 @@ Examples:
   omni kill 1234           # send SIGTERM to process 1234
   omni kill -9 1234        # send SIGKILL to process 1234
   omni kill -s HUP 1234    # send SIGHUP to process 1234
+  omni kill -HUP $(omni pgrep nginx)  # reload nginx by name
   omni kill -l             # list all signal names
   omni kill -l -j          # list signals as JSON
   omni kill -j 1234        # kill with JSON output`,
@@ -47,4 +54,37 @@ func init() {
 	killCmd.Flags().BoolP("list", "l", false, "list signal names")
 	killCmd.Flags().BoolP("verbose", "v", false, "report successful signals")
 
+	preprocessSignalArgs()
+}
+
+// preprocessSignalArgs converts the traditional -SIGNAL shorthand (kill -9,
+// pkill -HUP) to -s SIGNAL before Cobra parses it as a flag cluster.
+func preprocessSignalArgs() {
+	for i := 1; i < len(os.Args)-1; i++ {
+		if os.Args[i] != "kill" && os.Args[i] != "pkill" {
+			// Only global flags may precede the command name
+			if strings.HasPrefix(os.Args[i], "-") {
+				continue
+			}
+
+			return
+		}
+
+		arg := os.Args[i+1]
+		if len(arg) < 2 || arg[0] != '-' || strings.HasPrefix(arg, "--") {
+			return
+		}
+
+		if _, err := kill.ParseSignal(arg[1:]); err != nil && !cmderr.IsUnsupported(err) {
+			return
+		}
+
+		newArgs := make([]string, 0, len(os.Args)+1)
+		newArgs = append(newArgs, os.Args[:i+1]...)
+		newArgs = append(newArgs, "-s", arg[1:])
+		newArgs = append(newArgs, os.Args[i+2:]...)
+		os.Args = newArgs
+
+		return
+	}
 }
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"github.com/inovacc/omni/internal/cli/ksuid"
+	"github.com/inovacc/omni/internal/cli/ulid"
+	"github.com/inovacc/omni/pkg/idgen"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +26,10 @@ Structure: 4-byte timestamp + 16-byte random payload
 Examples:
   omni ksuid                  # generate one KSUID
   omni ksuid -n 5             # generate 5 KSUIDs
-  omni ksuid --json           # JSON output`,
+  omni ksuid --json           # JSON output
+
+Subcommands:
+  time      Print the timestamp embedded in KSUIDs`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := ksuid.Options{}
 
@@ -35,8 +40,38 @@ Examples:
 	},
 }
 
+// ksuidTimeCmd represents the ksuid time command
+var ksuidTimeCmd = &cobra.Command{
+	Use:   "time [OPTION]... [KSUID]...",
+	Short: "Print the timestamp embedded in KSUIDs",
+	Long: `Print the creation time embedded in each KSUID, in UTC, local time and
+Unix seconds. KSUID timestamps have one-second resolution. IDs are read one
+per line from standard input when none are given.
+
+With several IDs, every output line starts with the ID it belongs to.
+
+  -f, --format=FMT  all (default), utc, local, unix or unix-ms
+  --json            output as JSON
+
+Examples:
+  omni ksuid time 2dOGAhVWBGbAHiRoLJ8ZXEKdVVw
+  omni ksuid time -f local 2dOGAhVWBGbAHiRoLJ8ZXEKdVVw
+  omni ksuid -n 3 | omni ksuid time --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ulid.TimeOptions{Kind: idgen.KindKSUID}
+
+		opts.Format, _ = cmd.Flags().GetString("format")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return ulid.RunTime(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(ksuidCmd)
+	ksuidCmd.AddCommand(ksuidTimeCmd)
+
+	ksuidTimeCmd.Flags().StringP("format", "f", "all", "output format: all, utc, local, unix, unix-ms")
 
 	ksuidCmd.Flags().IntP("count", "n", 1, "generate N KSUIDs")
 }
@@ -7,11 +7,20 @@ import (
 
 // lnCmd represents the ln command
 var lnCmd = &cobra.Command{
-	Use:   "ln [OPTION]... TARGET LINK_NAME",
+	Use:   "ln [OPTION]... TARGET [LINK_NAME]",
 	Short: "Make links between files",
 	Long: `Create a link to TARGET with the name LINK_NAME.
 Create hard links by default, symbolic links with --symbolic.
 
+If LINK_NAME is an existing directory the link is created inside it; with
+a single TARGET it is created in the current directory; with several
+TARGETs the last operand must be a directory. A symbolic link's TARGET is
+stored as given, so it is resolved relative to the link (use -r to
+compute that path for you).
+
+On Windows, symbolic links need Developer Mode or an elevated prompt;
+hard links work for files on the same volume.
+
   -s, --symbolic     make symbolic links instead of hard links
   -f, --force        remove existing destination files
   -n, --no-dereference  treat LINK_NAME as a normal file if it is a symlink
@@ -22,8 +31,11 @@ Create hard links by default, symbolic links with --symbolic.
 Examples:
   omni ln target.txt link.txt       # create a hard link
   omni ln -s target.txt link.txt    # create a symbolic link
-  omni ln -sf target.txt link.txt   # replace an existing symlink`,
-	Args: cobra.MinimumNArgs(2),
+  omni ln -sf target.txt link.txt   # replace an existing symlink
+  omni ln -sfn releases/v2 current  # repoint a symlink to a directory
+  omni ln -sr lib/libfoo.so.1 lib/libfoo.so
+  omni ln -s /opt/tool/bin/tool     # link into the current directory`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := ln.LnOptions{}
 
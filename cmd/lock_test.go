@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/pipe"
+)
+
+// TestLockAcquireRunsCommandWithContext holds a lock around free, which
+// derives a signal context from cmd.Context() and so needs the one lock
+// passes down.
+func TestLockAcquireRunsCommandWithContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "l.lock")
+
+	var buf bytes.Buffer
+
+	err := pipe.NewRegistry(rootCmd).Run(context.Background(), &buf, nil, []string{"lock", "acquire", path, "--", "free"})
+	if err != nil {
+		t.Fatalf("lock acquire -- free: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Mem:") {
+		t.Errorf("output = %q", buf.String())
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/flags"
+	"github.com/inovacc/omni/internal/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +29,13 @@ To disable logging:
 To view all log files:
   omni logger --viewer
 
+Secrets are redacted before arguments, output and errors reach the log: the
+values of flags such as --password, --token, --api-key and --key (also in
+their short forms, like encrypt -p), passwords in URLs, NAME=value assignments with secret-looking names, bearer tokens,
+private keys and well-known token formats (AWS, GitHub, Slack, JWT, age).
+Add your own rules, kept until --redact-reset:
+  omni logger --redact-flag db-pass --redact-pattern 'INTERNAL-[0-9]+'
+
 Environment variables set:
   OMNI_LOG_ENABLED - Set to "true" to enable logging
   OMNI_LOG_PATH    - Path to the log file`,
@@ -33,6 +44,9 @@ Environment variables set:
 		disable, _ := cmd.Flags().GetBool("disable")
 		status, _ := cmd.Flags().GetBool("status")
 		viewer, _ := cmd.Flags().GetBool("viewer")
+		redactFlags, _ := cmd.Flags().GetStringArray("redact-flag")
+		redactPatterns, _ := cmd.Flags().GetStringArray("redact-pattern")
+		redactReset, _ := cmd.Flags().GetBool("redact-reset")
 
 		if err := flags.IgnoreCommand("logger"); err != nil {
 			return err
@@ -46,6 +60,16 @@ Environment variables set:
 			return printStatus(cmd)
 		}
 
+		if redactReset || len(redactFlags) > 0 || len(redactPatterns) > 0 {
+			if err := updateRedactRules(cmd, redactFlags, redactPatterns, redactReset); err != nil {
+				return err
+			}
+
+			if logPath == "" && !disable {
+				return nil
+			}
+		}
+
 		if disable {
 			return flags.DisableFeature("logger")
 		}
@@ -68,6 +92,68 @@ func printStatus(cmd *cobra.Command) error {
 
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Logging: Enabled\n")
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Log path: %s\n", logPath)
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Redaction: %d default flags, %d default patterns\n",
+		len(logger.DefaultRedactFlags), len(logger.DefaultRedactPatterns))
+
+	rules, err := logger.LoadRedactRules()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Custom redaction rules: invalid (%v)\n", err)
+		return nil
+	}
+
+	for _, f := range rules.Flags {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Redacted flag: --%s\n", f)
+	}
+
+	for _, p := range rules.Patterns {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Redacted pattern: %s\n", p)
+	}
+
+	return nil
+}
+
+// updateRedactRules adds flags and patterns to the saved redaction rules,
+// after dropping the saved ones when reset is set.
+func updateRedactRules(cmd *cobra.Command, names, patterns []string, reset bool) error {
+	var rules logger.RedactRules
+
+	if !reset {
+		var err error
+		if rules, err = logger.LoadRedactRules(); err != nil {
+			return fmt.Errorf("saved redaction rules are invalid (use --redact-reset): %w", err)
+		}
+	}
+
+	for _, name := range names {
+		name = strings.TrimLeft(strings.TrimSpace(name), "-")
+		if name == "" || strings.ContainsAny(name, " \t=") {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("logger: invalid flag name %q", name))
+		}
+
+		if !slices.Contains(rules.Flags, name) {
+			rules.Flags = append(rules.Flags, name)
+		}
+	}
+
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("logger: invalid pattern: %s", err))
+		}
+
+		if strings.ContainsAny(p, "\n") {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "logger: a pattern must be a single line")
+		}
+
+		if !slices.Contains(rules.Patterns, p) {
+			rules.Patterns = append(rules.Patterns, p)
+		}
+	}
+
+	if err := logger.SaveRedactRules(rules); err != nil {
+		return fmt.Errorf("failed to save redaction rules: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Redaction rules: %d custom flags, %d custom patterns\n", len(rules.Flags), len(rules.Patterns))
 
 	return nil
 }
@@ -173,4 +259,7 @@ func init() {
 	loggerCmd.Flags().BoolP("disable", "d", false, "Disable logging (unset environment variables)")
 	loggerCmd.Flags().BoolP("status", "s", false, "Show current logging status")
 	loggerCmd.Flags().BoolP("viewer", "v", false, "View all log files sorted by time")
+	loggerCmd.Flags().StringArray("redact-flag", nil, "also redact the value of this flag in logs (repeatable)")
+	loggerCmd.Flags().StringArray("redact-pattern", nil, "also redact text matching this regular expression in logs (repeatable)")
+	loggerCmd.Flags().Bool("redact-reset", false, "drop the custom redaction rules")
 }
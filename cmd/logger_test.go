@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/inovacc/omni/internal/logger"
+)
+
+// TestRedactSecretShorthands checks the short forms of secret-bearing
+// flags against the commands' real flag sets.
+func TestRedactSecretShorthands(t *testing.T) {
+	tests := []struct {
+		cmdName string
+		args    []string
+		want    []string
+	}{
+		{"encrypt", []string{"-p", "hunter2", "in.txt"}, []string{"-p", logger.Redacted, "in.txt"}},
+		{"decrypt", []string{"-phunter2"}, []string{"-p" + logger.Redacted}},
+		{"hmac", []string{"-k", "s3cret"}, []string{"-k", logger.Redacted}},
+		{"hmac", []string{"--key=s3cret"}, []string{"--key=" + logger.Redacted}},
+	}
+
+	for _, tt := range tests {
+		c, _, err := rootCmd.Find([]string{tt.cmdName})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := logger.DefaultRedactor().WithFlags(c.Flags()).Args(tt.args); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Args(%q) = %q, want %q", tt.cmdName, tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/flagvalue"
+	"github.com/inovacc/omni/internal/cli/logs"
+	"github.com/inovacc/omni/internal/flags"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Inspect omni command logs",
+	Long: `Inspect the JSON command logs written when logging is enabled
+with 'omni logger --path DIR'.
+
+Subcommands:
+  stats    Per-command run counts, error rates and P50/P95 durations`,
+}
+
+var logsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report per-command metrics from the log directory",
+	Long: `Aggregate the command_end records in the log directory and report, per
+command, the number of runs, errors, error rate and P50/P95/max durations.
+
+Useful in CI to see which omni commands fail most often or run slowest.
+
+Flags:
+  -d, --dir DIR       log directory (default: the configured logger path)
+      --since DUR     only include executions newer than DUR (e.g. 24h)
+  -c, --command NAME  only include this command
+      --sort KEY      sort by count (default), errors, rate, p50 or p95
+  -n, --limit N       show at most N commands
+      --json          output as JSON
+
+Examples:
+  omni logs stats
+  omni logs stats --since 24h --sort rate
+  omni logs stats --dir ./ci-logs --json
+  omni logs stats -c grep`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := logs.StatsOptions{}
+
+		opts.Dir, _ = cmd.Flags().GetString("dir")
+		opts.Since, _ = flagvalue.GetDuration(cmd.Flags(), "since")
+		opts.Command, _ = cmd.Flags().GetString("command")
+		opts.Sort, _ = cmd.Flags().GetString("sort")
+		opts.Limit, _ = cmd.Flags().GetInt("limit")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		if opts.Dir == "" {
+			opts.Dir = flags.GetFeatureData("logger")
+		}
+
+		return logs.RunStats(cmd.OutOrStdout(), opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.AddCommand(logsStatsCmd)
+
+	logsStatsCmd.Flags().StringP("dir", "d", "", "log directory (default: configured logger path)")
+	flagvalue.DurationP(logsStatsCmd.Flags(), "since", "", 0, "only include executions newer than this duration")
+	logsStatsCmd.Flags().StringP("command", "c", "", "only include this command")
+	logsStatsCmd.Flags().String("sort", "count", "sort by: count, errors, rate, p50, p95")
+	logsStatsCmd.Flags().IntP("limit", "n", 0, "show at most N commands")
+}
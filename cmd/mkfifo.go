@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/mkfifo"
+	"github.com/spf13/cobra"
+)
+
+// mkfifoCmd represents the mkfifo command
+var mkfifoCmd = &cobra.Command{
+	Use:   "mkfifo [OPTION]... NAME...",
+	Short: "Make FIFOs (named pipes)",
+	Long: `Create named pipes (FIFOs) with the given NAMEs.
+
+  -m, --mode MODE   set file permission bits to MODE (octal), not a=rw - umask
+
+FIFOs are a Unix feature; on Windows mkfifo fails with an "unsupported"
+error.
+
+Examples:
+  omni mkfifo /tmp/events          # create a named pipe
+  omni mkfifo -m 600 ctl.pipe      # owner-only pipe`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := mkfifo.Options{}
+
+		opts.Mode, _ = cmd.Flags().GetString("mode")
+
+		return mkfifo.RunMkfifo(args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mkfifoCmd)
+
+	mkfifoCmd.Flags().StringP("mode", "m", "", "set file permission bits to MODE, not a=rw - umask")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/mktemp"
+	"github.com/spf13/cobra"
+)
+
+// mktempCmd represents the mktemp command
+var mktempCmd = &cobra.Command{
+	Use:   "mktemp [OPTION]... [TEMPLATE]",
+	Short: "Create a temporary file or directory",
+	Long: `Create a temporary file or directory, safely, and print its name.
+
+TEMPLATE must end in at least 3 consecutive 'X's, which are replaced with
+random letters and digits. With no TEMPLATE, tmp.XXXXXXXXXX is created in
+the temporary directory: --tmpdir, else $TMPDIR (%TMP% on Windows), else
+the system default. A TEMPLATE operand is relative to the current
+directory unless --tmpdir is given; --template always uses the temporary
+directory.
+
+Files are created with mode 0600 and directories with 0700, and an
+existing name (including a planted symlink) is never reused.
+
+  -d, --directory        create a directory, not a file
+  -u, --dry-run          do not create anything; merely print a name (unsafe)
+  -p, --tmpdir DIR       interpret TEMPLATE relative to DIR
+  -t, --template T       create T inside the temporary directory
+      --suffix SUFF      append SUFF to TEMPLATE
+  --json                 print {"path": ..., "directory": ...}
+
+Examples:
+  omni mktemp                          # /tmp/tmp.aB3dE5fG7h
+  omni mktemp -d                       # temporary directory
+  omni mktemp -t build-XXXXXX          # /tmp/build-Q1w2E3
+  omni mktemp --suffix .json           # /tmp/tmp.k9L8m7N6b5.json
+  omni mktemp -p ./out report.XXXX     # ./out/report.Zx81`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := mktemp.Options{}
+
+		opts.Directory, _ = cmd.Flags().GetBool("directory")
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		opts.TmpDir, _ = cmd.Flags().GetString("tmpdir")
+		opts.Template, _ = cmd.Flags().GetString("template")
+		opts.Suffix, _ = cmd.Flags().GetString("suffix")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return mktemp.RunMktemp(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mktempCmd)
+
+	mktempCmd.Flags().BoolP("directory", "d", false, "create a directory, not a file")
+	mktempCmd.Flags().BoolP("dry-run", "u", false, "do not create anything; merely print a name (unsafe)")
+	mktempCmd.Flags().StringP("tmpdir", "p", "", "interpret TEMPLATE relative to DIR")
+	mktempCmd.Flags().StringP("template", "t", "", "create TEMPLATE inside the temporary directory")
+	mktempCmd.Flags().String("suffix", "", "append SUFF to TEMPLATE")
+}
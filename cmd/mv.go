@@ -12,15 +12,28 @@ var mvCmd = &cobra.Command{
 	Short:   "Move (rename) files",
 	Long: `Rename SOURCE to DEST, or move SOURCE(s) to DIRECTORY.
 
+  -f, --force         never prompt before overwriting
+      --dry-run       print what would be moved without moving
+  -i, --interactive   prompt before overwriting an existing file
+  -y, --yes           answer yes to every prompt
+
 Examples:
   omni mv old.txt new.txt      # rename a file
   omni mv a.txt b.txt dir/     # move multiple files into a directory
-  omni move src dest           # move/rename (alias)`,
+  omni move src dest           # move/rename (alias)
+  omni mv --dry-run *.txt dir/ # preview the moves`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return copy2.RunMove(args, copy2.MoveOptions{})
+		opts := copy2.MoveOptions{Confirm: getConfirmOpts(cmd)}
+
+		opts.Force, _ = cmd.Flags().GetBool("force")
+
+		return copy2.RunMove(args, opts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(mvCmd)
+
+	mvCmd.Flags().BoolP("force", "f", false, "never prompt before overwriting")
+	addConfirmFlags(mvCmd, "i")
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/notify"
+	"github.com/spf13/cobra"
+)
+
+// notifyCmd represents the notify command
+var notifyCmd = &cobra.Command{
+	Use:   "notify [OPTION]... MESSAGE...",
+	Short: "Show a desktop notification",
+	Long: `Show MESSAGE as a desktop notification, so long-running Taskfile jobs can
+tell you when they finish.
+
+The native notifier is notify-send on Linux and the BSDs, osascript on
+macOS and a PowerShell toast on Windows. Without one (for example over
+SSH, or on Linux with no graphical session), or when it fails, omni writes
+an OSC 9 notification to the terminal, which iTerm2, Windows Terminal,
+WezTerm and others show as a desktop notification, followed by a bell.
+
+Backends (--backend):
+  auto       native notifier, else the terminal (default)
+  native     native notifier only; fail if there is none
+  terminal   OSC 9 escape sequence and bell
+  bell       bell only
+
+Terminal output goes to stderr, so notify never pollutes piped stdout.
+
+Examples:
+  omni notify "build done"
+  omni notify --title CI "tests passed"
+  omni notify -u critical --title deploy "rollout failed"
+  omni notify --backend terminal "done"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := notify.Options{OutputFormat: getOutputOpts(cmd).GetFormat()}
+		opts.Title, _ = cmd.Flags().GetString("title")
+		opts.Urgency, _ = cmd.Flags().GetString("urgency")
+		opts.Backend, _ = cmd.Flags().GetString("backend")
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return notify.Run(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), strings.Join(args, " "), opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+
+	notifyCmd.Flags().StringP("title", "t", "omni", "notification title")
+	notifyCmd.Flags().StringP("urgency", "u", "normal", "urgency: low, normal or critical (notify-send only)")
+	notifyCmd.Flags().String("backend", notify.BackendAuto, "backend: auto, native, terminal or bell")
+}
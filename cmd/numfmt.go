@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/numfmt"
+	"github.com/spf13/cobra"
+)
+
+// numfmtCmd represents the numfmt command
+var numfmtCmd = &cobra.Command{
+	Use:   "numfmt [OPTION]... [NUMBER]...",
+	Short: "Convert numbers to and from human-readable sizes",
+	Long: `Reformat NUMBER(s), or the numbers in a column of standard input, between
+plain and human-readable forms such as 1536 <-> 1.5K.
+
+  --from=UNIT         scale of the input: none, si, iec, iec-i or auto
+  --to=UNIT           scale of the output: none, si, iec or iec-i
+  --from-unit=N       size of one input unit (default 1)
+  --to-unit=N         size of one output unit (default 1)
+  --field=FIELDS      convert these fields (N, N-M, N-, -M, lists; default 1)
+  -d, --delimiter=X   use X as the field delimiter instead of blanks
+  --header[=N]        print the first N input lines unchanged (default 1)
+  --invalid=MODE      on bad input: abort (default), fail, warn or ignore
+  --padding=N         pad output to N characters; negative N left-aligns
+  --round=METHOD      up, down, from-zero (default), towards-zero, nearest
+  --suffix=SUFFIX     strip SUFFIX from input numbers and add it to output
+  -z, --zero-terminated  line delimiter is NUL, not newline
+
+Scales:
+  si      powers of 1000: 1k = 1000, 1M = 1000000, ...
+  iec     powers of 1024: 1K = 1024, 1M = 1048576, ...
+  iec-i   powers of 1024 with an "i": 1Ki = 1024, 1Mi = 1048576, ...
+  auto    input only: 1K = 1000, 1Ki = 1024
+
+Scaled output below 10 keeps one decimal (1.5K), larger values are whole
+numbers (15K). Conversions follow the same rules as the pipeline "numfmt"
+stage, so both give identical results.
+
+Examples:
+  omni numfmt --to=iec 1536                      # 1.5K
+  omni numfmt --from=iec 1.5K                    # 1536
+  omni numfmt --from=auto 2Mi 2M                 # 2097152 and 2000000
+  omni du -b . | omni numfmt --to=iec            # humanize the first column
+  omni ls -l | omni numfmt --header --field 5 --to=iec
+  omni numfmt --to=si --field 3 -d, < sizes.csv  # third CSV column`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := numfmt.Options{}
+		opts.From, _ = cmd.Flags().GetString("from")
+		opts.To, _ = cmd.Flags().GetString("to")
+		opts.FromUnit, _ = cmd.Flags().GetFloat64("from-unit")
+		opts.ToUnit, _ = cmd.Flags().GetFloat64("to-unit")
+		opts.Field, _ = cmd.Flags().GetString("field")
+		opts.Delimiter, _ = cmd.Flags().GetString("delimiter")
+		opts.Header, _ = cmd.Flags().GetInt("header")
+		opts.Invalid, _ = cmd.Flags().GetString("invalid")
+		opts.Padding, _ = cmd.Flags().GetInt("padding")
+		opts.Round, _ = cmd.Flags().GetString("round")
+		opts.Suffix, _ = cmd.Flags().GetString("suffix")
+		opts.ZeroTerm, _ = cmd.Flags().GetBool("zero-terminated")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return numfmt.Run(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(numfmtCmd)
+
+	numfmtCmd.Flags().String("from", "none", "scale of the input: none, si, iec, iec-i or auto")
+	numfmtCmd.Flags().String("to", "none", "scale of the output: none, si, iec or iec-i")
+	numfmtCmd.Flags().Float64("from-unit", 1, "size of one input unit")
+	numfmtCmd.Flags().Float64("to-unit", 1, "size of one output unit")
+	numfmtCmd.Flags().String("field", "1", "convert these fields (N, N-M, N-, -M, lists)")
+	numfmtCmd.Flags().StringP("delimiter", "d", "", "use X as the field delimiter instead of blanks")
+	numfmtCmd.Flags().Int("header", 0, "print the first N input lines unchanged")
+	numfmtCmd.Flags().Lookup("header").NoOptDefVal = "1"
+	numfmtCmd.Flags().String("invalid", "abort", "on bad input: abort, fail, warn or ignore")
+	numfmtCmd.Flags().Int("padding", 0, "pad output to N characters; negative N left-aligns")
+	numfmtCmd.Flags().String("round", "from-zero", "up, down, from-zero, towards-zero or nearest")
+	numfmtCmd.Flags().String("suffix", "", "strip SUFFIX from input numbers and add it to output")
+	numfmtCmd.Flags().BoolP("zero-terminated", "z", false, "line delimiter is NUL, not newline")
+}
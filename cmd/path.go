@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	pathutil "github.com/inovacc/omni/internal/cli/path"
 	"github.com/spf13/cobra"
 )
@@ -9,25 +10,41 @@ import (
 var pathCmd = &cobra.Command{
 	Use:   "path",
 	Short: "Path manipulation utilities",
-	Long: `Clean, resolve, and manipulate file paths using OS-native conventions.
+	Long: `Clean, resolve, and manipulate file paths.
+
+By default paths follow the conventions of the running OS. --style=unix or
+--style=windows accepts either separator on input and prints "/" or "\"
+respectively, so a Taskfile can build paths for the other platform.
 
 Examples:
-  omni path clean a/b/../c        # normalize a path
-  omni path abs ./file.txt        # resolve to an absolute path`,
+  omni path clean a/b/../c                    # normalize a path
+  omni path abs ./file.txt                    # resolve to an absolute path
+  omni path resolve -m build/../dist/app      # realpath -m
+  omni path relative /srv/app /srv/app/bin/x  # bin/x
+  omni path join dist "{{.OS}}" app.exe       # join and clean
+  omni path split --json src/main.go          # dir, base, stem, ext
+  omni path clean --style=windows a/b/../c    # a\c`,
 }
 
 // pathCleanCmd represents the path clean subcommand
 var pathCleanCmd = &cobra.Command{
 	Use:   "clean [path...]",
 	Short: "Return the shortest equivalent path with OS separators",
-	Long: `Clean returns the shortest path name equivalent to path by purely lexical processing. It applies OS-native separators.
+	Long: `Clean returns the shortest path name equivalent to path by purely lexical processing. It applies OS-native separators unless --style is given.
 
 Examples:
   omni path clean a/b/../c        # prints "a/c"
-  omni path clean ./foo/          # prints "foo"`,
-	Args:  cobra.MinimumNArgs(1),
+  omni path clean ./foo/          # prints "foo"
+  omni path clean --style=unix 'a\b\..\c'  # prints "a/c"`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		opts := pathutil.CleanOptions{OutputFormat: getOutputOpts(cmd).GetFormat()}
+		style, err := pathStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := pathutil.CleanOptions{Style: style, OutputFormat: getOutputOpts(cmd).GetFormat()}
+
 		return pathutil.RunClean(cmd.OutOrStdout(), args, opts)
 	},
 }
@@ -41,16 +58,165 @@ var pathAbsCmd = &cobra.Command{
 Examples:
   omni path abs file.txt          # resolve relative to the working dir
   omni path abs ../sibling        # resolve a parent-relative path`,
-	Args:  cobra.MinimumNArgs(1),
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		opts := pathutil.AbsOptions{OutputFormat: getOutputOpts(cmd).GetFormat()}
+		style, err := pathStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := pathutil.AbsOptions{Style: style, OutputFormat: getOutputOpts(cmd).GetFormat()}
+
 		return pathutil.RunAbs(cmd.OutOrStdout(), args, opts)
 	},
 }
 
+// pathResolveCmd represents the path resolve subcommand
+var pathResolveCmd = &cobra.Command{
+	Use:   "resolve [OPTION]... PATH...",
+	Short: "Resolve symlinks, . and .. (like realpath)",
+	Long: `Resolve prints the absolute path with every symlink expanded and "." and
+".." removed, like realpath. All components must exist unless -m is given.
+
+  -e, --canonicalize-existing  all components of the path must exist (default)
+  -m, --canonicalize-missing   no path components need exist or be a directory
+  -s, --no-symlinks            don't expand symlinks
+      --relative-to=DIR        print the resolved path relative to DIR
+  -z, --zero                   end each output line with NUL, not newline
+
+Examples:
+  omni path resolve ./link                  # follow the link
+  omni path resolve -m out/../dist/new.bin  # path need not exist
+  omni path resolve --relative-to=. ~/src   # relative to the working dir`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style, err := pathStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		existing, _ := cmd.Flags().GetBool("canonicalize-existing")
+		missing, _ := cmd.Flags().GetBool("canonicalize-missing")
+
+		if existing && missing {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "path resolve: -e and -m are mutually exclusive")
+		}
+
+		opts := pathutil.ResolveCmdOptions{}
+		opts.Style = style
+		opts.NoSymlinks, _ = cmd.Flags().GetBool("no-symlinks")
+		opts.RelativeTo, _ = cmd.Flags().GetString("relative-to")
+		opts.Zero, _ = cmd.Flags().GetBool("zero")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		if missing {
+			opts.Mode = pathutil.ResolveMissing
+		}
+
+		return pathutil.RunResolve(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+// pathRelativeCmd represents the path relative subcommand
+var pathRelativeCmd = &cobra.Command{
+	Use:   "relative BASE TARGET...",
+	Short: "Print each TARGET relative to BASE",
+	Long: `Relative prints each TARGET as a path relative to BASE, by purely lexical
+processing. When one path is relative and the other absolute, both are
+made absolute first. Use "path resolve --relative-to" to follow symlinks.
+
+Examples:
+  omni path relative /srv/app /srv/app/bin/tool   # bin/tool
+  omni path relative /srv/app /srv/lib            # ../lib
+  omni path relative --style=windows C:/a C:/b/c  # ..\b\c`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style, err := pathStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := pathutil.RelativeOptions{Style: style, OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		return pathutil.RunRelative(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+// pathJoinCmd represents the path join subcommand
+var pathJoinCmd = &cobra.Command{
+	Use:   "join ELEMENT...",
+	Short: "Join path elements into a single cleaned path",
+	Long: `Join joins any number of path elements with the separator and cleans the
+result. Empty elements are ignored.
+
+Examples:
+  omni path join a b c.txt                  # a/b/c.txt
+  omni path join /opt/app ../lib            # /opt/lib
+  omni path join --style=windows C: tools x # C:tools\x`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style, err := pathStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := pathutil.JoinOptions{Style: style, OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		return pathutil.RunJoin(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+// pathSplitCmd represents the path split subcommand
+var pathSplitCmd = &cobra.Command{
+	Use:   "split PATH...",
+	Short: "Split a path into its components",
+	Long: `Split prints the components of each cleaned PATH, one per line. A rooted
+path starts with its root ("/" or "C:\"). With --json, the directory, base
+name, stem and extension are reported as well.
+
+Examples:
+  omni path split /usr/local/bin            # /, usr, local, bin
+  omni path split --json src/app/main.go    # dir src/app, stem main, ext .go`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style, err := pathStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := pathutil.SplitOptions{Style: style, OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		return pathutil.RunSplit(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+// pathStyle reads the persistent --style flag.
+func pathStyle(cmd *cobra.Command) (pathutil.Style, error) {
+	s, _ := cmd.Flags().GetString("style")
+
+	style, err := pathutil.ParseStyle(s)
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, "path: "+err.Error())
+	}
+
+	return style, nil
+}
+
 func init() {
 	rootCmd.AddCommand(pathCmd)
 
 	pathCmd.AddCommand(pathCleanCmd)
 	pathCmd.AddCommand(pathAbsCmd)
+	pathCmd.AddCommand(pathResolveCmd)
+	pathCmd.AddCommand(pathRelativeCmd)
+	pathCmd.AddCommand(pathJoinCmd)
+	pathCmd.AddCommand(pathSplitCmd)
+
+	pathCmd.PersistentFlags().String("style", "native", "path syntax: native, unix or windows")
+
+	pathResolveCmd.Flags().BoolP("canonicalize-existing", "e", false, "all components of the path must exist")
+	pathResolveCmd.Flags().BoolP("canonicalize-missing", "m", false, "no path components need exist or be a directory")
+	pathResolveCmd.Flags().BoolP("no-symlinks", "s", false, "don't expand symlinks")
+	pathResolveCmd.Flags().String("relative-to", "", "print the resolved path relative to DIR")
+	pathResolveCmd.Flags().BoolP("zero", "z", false, "end each output line with NUL, not newline")
 }
@@ -336,10 +336,10 @@ Supported commands include all omni commands:
 				input += "\n"
 			}
 
-			return pipe.RunWithInput(cmd.OutOrStdout(), input, args, opts, registry)
+			return pipe.RunWithInputContext(cmd.Context(), cmd.OutOrStdout(), input, args, opts, registry)
 		}
 
-		return pipe.Run(cmd.OutOrStdout(), args, opts, registry)
+		return pipe.RunContext(cmd.Context(), cmd.OutOrStdout(), args, opts, registry)
 	},
 }
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pipe"
 )
 
 // TestPipeRegistryHasSignVerify asserts that the sign and verify commands are
@@ -134,3 +135,57 @@ func TestPipeSbomDispatchesToGlue(t *testing.T) {
 		t.Errorf("sbom output missing SPDX-2.3 marker:\n%s", buf.String())
 	}
 }
+
+// TestPipeRgStringArrayFlags runs rg through pipe twice. Resetting its
+// -e/-f string array flags with Set appended a literal "[]", which rg then
+// opened as a pattern file.
+func TestPipeRgStringArrayFlags(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if err := os.WriteFile("f.txt", []byte("needle\nhay\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := pipe.NewRegistry(rootCmd)
+
+	for range 2 {
+		var buf bytes.Buffer
+		if err := pipe.Run(&buf, []string{"rg --no-filename needle f.txt"}, pipe.Options{}, registry); err != nil {
+			t.Fatalf("pipe rg: %v", err)
+		}
+
+		if got := strings.TrimSpace(buf.String()); got != "needle" {
+			t.Errorf("pipe rg output = %q, want needle", got)
+		}
+	}
+}
+
+// TestRegistryRgTypeAddTwice runs rg --type-add twice in-process: each run
+// sees only its own type definitions, not a bogus "[]" one or the previous
+// run's.
+func TestRegistryRgTypeAddTwice(t *testing.T) {
+	t.Setenv("OMNI_CONFIG", filepath.Join(t.TempDir(), "none.yaml"))
+	t.Chdir(t.TempDir())
+
+	for name, content := range map[string]string{"a.html": "class=a\n", "b.md": "class=b\n"} {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := pipe.NewRegistry(rootCmd)
+
+	for _, tc := range []struct{ def, want string }{
+		{"web:*.html", "a.html:class=a"},
+		{"web:*.md", "b.md:class=b"},
+	} {
+		var buf bytes.Buffer
+		if err := registry.Run(context.Background(), &buf, nil, []string{"rg", "--type-add", tc.def, "-t", "web", "class"}); err != nil {
+			t.Fatalf("rg --type-add %s: %v", tc.def, err)
+		}
+
+		if got := strings.TrimSpace(buf.String()); got != tc.want {
+			t.Errorf("rg --type-add %s: output = %q, want %q", tc.def, got, tc.want)
+		}
+	}
+}
@@ -26,17 +26,47 @@ Available stages:
   uniq               Remove consecutive duplicate lines (-i)
   cut -dDELIM -fN    Extract fields (-d delimiter, -f fields)
   tr FROM TO         Translate characters
-  sed s/PAT/REPL/g   Regex substitution
+  sed s/PAT/REPL/g   Regex substitution; REPL takes & and \1..\9 for the
+                     match and its groups, \U \L \u \l \E for case
   rev                Reverse each line
   nl                 Number each line
   tee FILE           Copy output to file and next stage
   tac                Reverse line order
-  wc                 Count lines/words/chars (-l, -w, -c)
+  wc                 Count lines/words/chars/bytes (-l, -w, -m, -c, -L)
+  numfmt --to=iec    Humanize numbers in a column (--from, --field, -d, ...)
+  csvcut -f N,M      Extract CSV fields, honoring quotes (-d delimiter, "tab")
+  csvfilter -f N PAT Keep CSV rows whose field N matches regex PAT
+                     (-i, -v, -H keep header row, -d delimiter)
+  join FILE          Join lines with the lines of FILE on a key field
+                     (-t delimiter or "tab", -1 N, -2 N, -j N, -a 1|2,
+                     -v 1|2, -i); unlike join(1), neither input needs
+                     sorting
+  gzip [-1..-9]      Compress the stream with gzip (-d to decompress)
+  gunzip             Decompress gzip input
+  decompress         Decompress gzip or bzip2 input, detected from its
+                     leading bytes; other input passes through (alias zcat)
+  encrypt -r KEY     Encrypt the stream to age recipients (-r KEY, -R FILE
+                     with recipients, -a armor)
+  decrypt -i FILE    Decrypt age input with the identities in FILE
+
+The compression stages work on bytes, not lines: put decompress first and
+gzip last. zstd and xz input is recognized but not supported by this build.
+The same goes for decrypt and encrypt, which keep plaintext off disk: it
+only ever passes between the stages in memory.
+
+Within a stage, single quotes keep text literally, and a backslash only
+escapes quotes, whitespace and backslashes, so \d or \1 reach the stage.
 
 Examples:
   omni pipeline 'grep error' 'sort' 'uniq' 'head 10' < log.txt
   omni pipeline -f access.log 'grep 404' 'cut -d" " -f1' 'sort' 'uniq'
-  omni pipeline -v 'grep -i warning' 'sort -rn' 'head 5'`,
+  omni pipeline -v 'grep -i warning' 'sort -rn' 'head 5'
+  omni pipeline -f sizes.txt 'sort -rn' 'head 5' 'numfmt --to=iec'
+  omni pipeline -f users.csv 'csvfilter -H -f 4 ^active$' 'csvcut -f 1,3'
+  omni pipeline -f orders.csv 'join -t , -1 2 customers.csv' 'sort'
+  omni pipeline -f names.txt 'sed "s/(\w+) (\w+)/\2, \u\1/"'
+  omni pipeline -f app.log.gz 'decompress' 'grep ERROR' 'sed s/secret/***/g' 'gzip' > errors.gz
+  omni pipeline -f secrets.age 'decrypt -i key.txt' 'grep prod' 'encrypt -R team.txt' > prod.age`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := pipeline.Options{}
 		opts.File, _ = cmd.Flags().GetString("file")
@@ -14,21 +14,31 @@ var pkillCmd = &cobra.Command{
 By default, sends SIGTERM to matching processes. Use -l to list
 matching processes without killing them (pgrep behavior).
 
+Processes are read from the same provider as ps. The pattern is a
+regular expression matched against the process name, or against the
+full command line with -f. pkill never selects itself.
+
 Options:
-  -signal     signal to send (default: TERM)
+  -s SIGNAL   signal to send by name or number (default: TERM)
+  -SIGNAL     shorthand for -s SIGNAL (e.g. -9, -HUP, -SIGUSR1)
   -x          match process name exactly
   -f          match against full command line
-  -n          select only the newest matching process
-  -o          select only the oldest matching process
+  -n          select only the newest matching process (by start time)
+  -o          select only the oldest matching process (by start time)
   -c          count matching processes
   -l          list matching processes (pgrep mode)
-  -u USER     match only processes owned by USER
+  -u USER     match only processes owned by USER (names or UIDs, comma-separated)
   -P PID      match only processes with parent PID
+  -t TERM     match only processes on terminal TERM
   -i          case insensitive matching
+  -v          report each process killed
+
+Exits 1 when no process matched or none could be signalled.
 
 Examples:
   omni pkill firefox           # kill all firefox processes
   omni pkill -9 chrome         # send SIGKILL to chrome
+  omni pkill -HUP nginx        # send SIGHUP to nginx
   omni pkill -l python         # list python processes (pgrep)
   omni pkill -f "node server"  # match full command line
   omni pkill -n -l java        # show newest java process
@@ -49,6 +59,7 @@ Examples:
 		opts.ListOnly, _ = cmd.Flags().GetBool("list")
 		opts.User, _ = cmd.Flags().GetString("user")
 		opts.Parent, _ = cmd.Flags().GetInt("parent")
+		opts.Terminal, _ = cmd.Flags().GetString("terminal")
 		opts.Verbose, _ = cmd.Flags().GetBool("verbose")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 		opts.IgnoreCase, _ = cmd.Flags().GetBool("ignore-case")
@@ -57,25 +68,37 @@ Examples:
 	},
 }
 
-// pgrepCmd is an alias for pkill -l
+// pgrepCmd lists matching processes using the pkill selection logic
 var pgrepCmd = &cobra.Command{
 	Use:   "pgrep [OPTIONS] PATTERN",
 	Short: "Find processes by name or pattern",
-	Long: `List processes matching a pattern. Alias for pkill -l.
+	Long: `List the PIDs of processes matching a pattern, one per line.
+
+Selection works exactly like pkill, so a pgrep command line can be
+turned into a pkill one once the output looks right.
 
 Options:
   -x          match process name exactly
   -f          match against full command line
-  -n          select only the newest matching process
-  -o          select only the oldest matching process
+  -n          select only the newest matching process (by start time)
+  -o          select only the oldest matching process (by start time)
   -c          count matching processes
-  -u USER     match only processes owned by USER
+  -l          list PID and process name
+  -a          list PID and full command line
+  -d DELIM    separate PIDs with DELIM (default: newline)
+  -u USER     match only processes owned by USER (names or UIDs, comma-separated)
   -P PID      match only processes with parent PID
+  -t TERM     match only processes on terminal TERM
   -i          case insensitive matching
 
+Exits 1 when no process matched.
+
 Examples:
-  omni pgrep firefox           # find firefox processes
-  omni pgrep -f "node server"  # match full command line
+  omni pgrep firefox           # PIDs of firefox processes
+  omni pgrep -l python         # PID and name
+  omni pgrep -a -f "node server"  # PID and command line
+  omni pgrep -n -u alice java  # alice's newest java process
+  omni pgrep -d, nginx         # comma-separated PIDs
   omni pgrep -c python         # count python processes`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
@@ -85,6 +108,10 @@ Examples:
 		opts := pkill.Options{
 			ListOnly: true, // pgrep always lists
 		}
+		listName, _ := cmd.Flags().GetBool("list-name")
+		opts.PIDsOnly = !listName
+		opts.ListFull, _ = cmd.Flags().GetBool("list-full")
+		opts.Delimiter, _ = cmd.Flags().GetString("delimiter")
 		opts.Exact, _ = cmd.Flags().GetBool("exact")
 		opts.Full, _ = cmd.Flags().GetBool("full")
 		opts.Newest, _ = cmd.Flags().GetBool("newest")
@@ -92,6 +119,7 @@ Examples:
 		opts.Count, _ = cmd.Flags().GetBool("count")
 		opts.User, _ = cmd.Flags().GetString("user")
 		opts.Parent, _ = cmd.Flags().GetInt("parent")
+		opts.Terminal, _ = cmd.Flags().GetString("terminal")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 		opts.IgnoreCase, _ = cmd.Flags().GetBool("ignore-case")
 
@@ -111,8 +139,9 @@ func init() {
 	pkillCmd.Flags().BoolP("oldest", "o", false, "select only the oldest process")
 	pkillCmd.Flags().BoolP("count", "c", false, "count matching processes")
 	pkillCmd.Flags().BoolP("list", "l", false, "list matching processes (pgrep mode)")
-	pkillCmd.Flags().StringP("user", "u", "", "match only processes owned by user")
+	pkillCmd.Flags().StringP("user", "u", "", "match only processes owned by user(s)")
 	pkillCmd.Flags().IntP("parent", "P", 0, "match only processes with parent PID")
+	pkillCmd.Flags().StringP("terminal", "t", "", "match only processes on terminal")
 	pkillCmd.Flags().BoolP("verbose", "v", false, "verbose output")
 	pkillCmd.Flags().BoolP("ignore-case", "i", false, "case insensitive matching")
 
@@ -122,7 +151,11 @@ func init() {
 	pgrepCmd.Flags().BoolP("newest", "n", false, "select only the newest process")
 	pgrepCmd.Flags().BoolP("oldest", "o", false, "select only the oldest process")
 	pgrepCmd.Flags().BoolP("count", "c", false, "count matching processes")
-	pgrepCmd.Flags().StringP("user", "u", "", "match only processes owned by user")
+	pgrepCmd.Flags().BoolP("list-name", "l", false, "list PID and process name")
+	pgrepCmd.Flags().BoolP("list-full", "a", false, "list PID and full command line")
+	pgrepCmd.Flags().StringP("delimiter", "d", "", "separator between PIDs (default: newline)")
+	pgrepCmd.Flags().StringP("user", "u", "", "match only processes owned by user(s)")
 	pgrepCmd.Flags().IntP("parent", "P", 0, "match only processes with parent PID")
+	pgrepCmd.Flags().StringP("terminal", "t", "", "match only processes on terminal")
 	pgrepCmd.Flags().BoolP("ignore-case", "i", false, "case insensitive matching")
 }
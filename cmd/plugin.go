@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/plugin"
+	"github.com/spf13/cobra"
+)
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage external omni-<name> plugins",
+	Long: `Extend omni with external commands.
+
+Any executable named omni-<name> on PATH can be run as 'omni <name>'.
+Arguments after the name are passed through verbatim, stdio is inherited and
+the plugin's exit code becomes omni's. Built-in commands always win.
+
+Plugins receive these environment variables:
+  OMNI_PLUGIN_NAME   the subcommand name
+  OMNI_EXECUTABLE    path of the invoking omni binary
+  OMNI_OUTPUT        "json" when --json was given, otherwise "text"
+
+With OMNI_OUTPUT=json a plugin should write NDJSON to stdout: one JSON value
+per line and nothing else. Diagnostics go to stderr.
+
+Subcommands:
+  list    List plugins found on PATH
+
+Examples:
+  omni plugin list
+  omni plugin list --json
+  omni hello --json            # runs omni-hello --json`,
+}
+
+// pluginListCmd represents the plugin list command
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plugins found on PATH",
+	Long: `List omni-<name> executables found on PATH, in the order they resolve.
+Warns about plugins hidden by a built-in command or shadowing another plugin
+of the same name further down PATH.
+
+Examples:
+  omni plugin list
+  omni plugin list --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := plugin.ListOptions{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+		for _, c := range rootCmd.Commands() {
+			opts.Builtins = append(opts.Builtins, c.Name())
+			opts.Builtins = append(opts.Builtins, c.Aliases...)
+		}
+
+		return plugin.RunList(cmd.OutOrStdout(), opts)
+	},
+}
+
+// reservedNames are commands cobra adds at execution time, which plugins
+// must not take over.
+var reservedNames = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// dispatchPlugin runs the omni-<name> plugin when args do not start with a
+// built-in command. It reports whether a plugin handled the invocation.
+func dispatchPlugin(args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || slices.Contains(reservedNames, args[0]) {
+		return false, nil
+	}
+
+	if c, _, err := rootCmd.Find(args); err == nil && c != rootCmd {
+		return false, nil
+	}
+
+	p, ok := plugin.Find(os.Getenv("PATH"), args[0])
+	if !ok {
+		return false, nil
+	}
+
+	return true, plugin.Exec(context.Background(), p, args[1:], plugin.ExecOptions{
+		JSON:   slices.Contains(args[1:], "--json"),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+}
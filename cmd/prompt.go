@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/prompt"
+	"github.com/spf13/cobra"
+)
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Ask for input in scripts and Taskfiles",
+	Long: `Ask the user for a value and print the answer on stdout.
+
+Prompts go to stderr, so the answer can be captured with $(...). Without a
+terminal on stdin the answer is read as one line of stdin instead: piped
+input answers the prompt, and an empty line or end of input (for example
+stdin from /dev/null in CI) selects --default. With no default that is an
+error, so scripts never hang on a prompt.
+
+Subcommands:
+  text        Ask for a line of text
+  password    Ask for a secret without echoing it
+  confirm     Ask a yes/no question
+  select      Choose one of a list of options
+
+Examples:
+  name=$(omni prompt text "Your name" --default guest)
+  token=$(omni prompt password "API token")
+  omni prompt confirm "Deploy to production?" && task deploy
+  env=$(omni prompt select "Environment" --options dev,staging,prod)`,
+}
+
+// promptTextCmd represents the prompt text command
+var promptTextCmd = &cobra.Command{
+	Use:   "text [MESSAGE]",
+	Short: "Ask for a line of text",
+	Long: `Ask for a line of text and print it. Leading and trailing spaces are
+removed; an empty answer selects --default.
+
+Examples:
+  omni prompt text "Your name"
+  omni prompt text "Branch" --default main
+  omni prompt text "Ticket" --required
+  echo alice | omni prompt text "Your name"`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPrompt(cmd, prompt.KindText, args)
+	},
+}
+
+// promptPasswordCmd represents the prompt password command
+var promptPasswordCmd = &cobra.Command{
+	Use:   "password [MESSAGE]",
+	Short: "Ask for a secret without echoing it",
+	Long: `Ask for a secret and print it. The answer is not echoed on a terminal and
+is printed exactly as typed.
+
+Examples:
+  token=$(omni prompt password "API token")
+  export DB_PASSWORD="$(omni prompt password "Database password" --required)"`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPrompt(cmd, prompt.KindPassword, args)
+	},
+}
+
+// promptConfirmCmd represents the prompt confirm command
+var promptConfirmCmd = &cobra.Command{
+	Use:   "confirm [MESSAGE]",
+	Short: "Ask a yes/no question",
+	Long: `Ask a yes/no question and print yes or no. The exit status is 0 for yes and
+1 for no, so the prompt can guard a command with &&. --default takes yes or
+no.
+
+Examples:
+  omni prompt confirm "Deploy to production?" && task deploy
+  omni prompt confirm "Run migrations?" --default yes
+  omni prompt confirm --default no < /dev/null   # prints no, exits 1`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPrompt(cmd, prompt.KindConfirm, args)
+	},
+}
+
+// promptSelectCmd represents the prompt select command
+var promptSelectCmd = &cobra.Command{
+	Use:   "select [MESSAGE] --options A,B,...",
+	Short: "Choose one of a list of options",
+	Long: `Choose one of --options and print it. On a terminal an interactive fuzzy
+picker is shown; otherwise the answer read from stdin may be an option or
+its 1-based number.
+
+Examples:
+  omni prompt select "Environment" --options dev,staging,prod
+  omni prompt select --options dev,prod --default dev
+  echo 2 | omni prompt select --options dev,prod   # prints prod`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPrompt(cmd, prompt.KindSelect, args)
+	},
+}
+
+func runPrompt(cmd *cobra.Command, kind string, args []string) error {
+	opts := prompt.Options{
+		Message:      strings.Join(args, " "),
+		OutputFormat: getOutputOpts(cmd).GetFormat(),
+		Stdin:        cmd.InOrStdin(),
+		Stderr:       cmd.ErrOrStderr(),
+	}
+	opts.Default, _ = cmd.Flags().GetString("default")
+	opts.Required, _ = cmd.Flags().GetBool("required")
+	opts.Options, _ = cmd.Flags().GetStringSlice("options")
+
+	return prompt.Run(cmd.OutOrStdout(), kind, opts)
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.AddCommand(promptTextCmd, promptPasswordCmd, promptConfirmCmd, promptSelectCmd)
+
+	for _, c := range []*cobra.Command{promptTextCmd, promptPasswordCmd, promptConfirmCmd, promptSelectCmd} {
+		c.Flags().StringP("default", "d", "", "answer for empty input and when there is no terminal")
+	}
+
+	promptTextCmd.Flags().Bool("required", false, "reject an empty answer")
+	promptPasswordCmd.Flags().Bool("required", false, "reject an empty answer")
+	promptSelectCmd.Flags().StringSlice("options", nil, "comma-separated options to choose from")
+}
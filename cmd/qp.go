@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/base"
+	"github.com/spf13/cobra"
+)
+
+// qpCmd represents the qp command
+var qpCmd = &cobra.Command{
+	Use:     "qp [OPTION]... [FILE]",
+	Aliases: []string{"quoted-printable"},
+	Short:   "Quoted-printable encode or decode data",
+	Long: `Quoted-printable (RFC 2045) encode or decode FILE, or standard input, to
+standard output. Encoded lines are soft-wrapped at 76 characters and use
+CRLF line endings, as in MIME mail bodies. Both directions stream.
+
+With no FILE, or when FILE is -, read standard input.
+
+  -d, --decode    decode data
+  -b, --binary    treat input as binary: encode line breaks as =0D=0A
+
+Examples:
+  echo "café = 3€" | omni qp                # caf=C3=A9 =3D 3=E2=82=AC
+  omni qp -d body.qp
+  omni qp -b data.bin`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := base.QPOptions{}
+
+		opts.Decode, _ = cmd.Flags().GetBool("decode")
+		opts.Binary, _ = cmd.Flags().GetBool("binary")
+
+		return base.RunQP(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(qpCmd)
+
+	qpCmd.Flags().BoolP("decode", "d", false, "decode data")
+	qpCmd.Flags().BoolP("binary", "b", false, "treat input as binary: encode line breaks too")
+}
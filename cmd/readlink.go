@@ -11,7 +11,8 @@ var readlinkCmd = &cobra.Command{
 	Short: "Print resolved symbolic links or canonical file names",
 	Long: `Print value of a symbolic link or canonical file name.
 
-  -f, --canonicalize            canonicalize by following every symlink
+  -f, --canonicalize            canonicalize by following every symlink; all
+                                but the last component must exist
   -e, --canonicalize-existing   canonicalize, all components must exist
   -m, --canonicalize-missing    canonicalize without requirements on existence
   -n, --no-newline              do not output the trailing delimiter
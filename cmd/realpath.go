@@ -7,20 +7,45 @@ import (
 
 // realpathCmd represents the realpath command
 var realpathCmd = &cobra.Command{
-	Use:   "realpath [path...]",
+	Use:   "realpath [OPTION]... FILE...",
 	Short: "Print the resolved path",
-	Long: `Print the resolved absolute file name; all components must exist.
+	Long: `Print the resolved absolute file name. Symlinks are expanded and "."
+and ".." are removed; all components must exist unless -m is given.
+
+  -e, --canonicalize-existing  all components of the path must exist (default)
+  -m, --canonicalize-missing   no path components need exist or be a directory
+  -s, --strip, --no-symlinks   don't expand symlinks
+      --relative-to=DIR        print the resolved path relative to DIR
+  -z, --zero                   end each output line with NUL, not newline
 
 Examples:
-  omni realpath ./file.txt        # resolve to an absolute, real path
-  omni realpath ../dir            # resolve a relative directory`,
-	Args:  cobra.MinimumNArgs(1),
+  omni realpath ./file.txt                 # resolve to an absolute, real path
+  omni realpath -m build/out/app.bin       # path need not exist yet
+  omni realpath --relative-to=. /tmp/x     # relative to the working directory
+  omni realpath -s ./link                  # absolute, without following links`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		opts := realpath.RealpathOptions{OutputFormat: getOutputOpts(cmd).GetFormat()}
+		opts := realpath.RealpathOptions{}
+		opts.Existing, _ = cmd.Flags().GetBool("canonicalize-existing")
+		opts.Missing, _ = cmd.Flags().GetBool("canonicalize-missing")
+		opts.NoSymlinks, _ = cmd.Flags().GetBool("strip")
+		noSymlinks, _ := cmd.Flags().GetBool("no-symlinks")
+		opts.NoSymlinks = opts.NoSymlinks || noSymlinks
+		opts.RelativeTo, _ = cmd.Flags().GetString("relative-to")
+		opts.Zero, _ = cmd.Flags().GetBool("zero")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
 		return realpath.RunRealpath(cmd.OutOrStdout(), args, opts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(realpathCmd)
+
+	realpathCmd.Flags().BoolP("canonicalize-existing", "e", false, "all components of the path must exist")
+	realpathCmd.Flags().BoolP("canonicalize-missing", "m", false, "no path components need exist or be a directory")
+	realpathCmd.Flags().BoolP("strip", "s", false, "don't expand symlinks")
+	realpathCmd.Flags().Bool("no-symlinks", false, "don't expand symlinks (same as --strip)")
+	realpathCmd.Flags().String("relative-to", "", "print the resolved path relative to DIR")
+	realpathCmd.Flags().BoolP("zero", "z", false, "end each output line with NUL, not newline")
 }
@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/inovacc/omni/internal/cli/doctor"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/rg"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var rgCmd = &cobra.Command{
@@ -16,6 +22,13 @@ and automatically skips hidden files/directories and binary files.
 
 This is inspired by ripgrep (https://github.com/BurntSushi/ripgrep).
 
+With --files, rg takes no pattern and lists the files it would search;
+--type-list prints the known file types. --files-from FILE (- for stdin)
+searches exactly the files listed in FILE, one per line or NUL delimited,
+instead of walking directories; ignore and hidden rules do not apply to the
+list, but -t and -g do. With -0/--null the paths printed by --files and -l
+end with a NUL byte instead of a newline, for xargs -0, rm -0 and cp -0.
+
 Examples:
   # Search for pattern in current directory
   omni rg "pattern"
@@ -35,18 +48,37 @@ Examples:
   # Show only filenames with matches
   omni rg -l "TODO"
 
-  # Count matches per file
+  # Count matching lines per file
   omni rg -c "pattern"
 
+  # Count individual matches per file (same as -c -o)
+  omni rg --count-matches "pattern"
+
+  # Count matches spanning lines
+  omni rg -U --count-matches "(?s)begin.*?end"
+
   # Include hidden files
   omni rg --hidden "pattern"
 
+  # Skip files larger than 1 MiB
+  omni rg --max-filesize 1M "pattern"
+
   # Don't respect gitignore
   omni rg --no-ignore "pattern"
 
   # Search for literal string (no regex)
   omni rg -F "func()"
 
+  # Several patterns; with -e or -f every argument is a PATH
+  omni rg -e TODO -e FIXME src/
+
+  # Scan for hundreds of fixed indicators in one pass (Aho-Corasick)
+  omni rg -F -f iocs.txt /var/log
+
+  # Structural search of Go code; every argument is a PATH
+  omni rg --go-pattern 'fmt.Errorf($_)'
+  omni rg --go-pattern 'if $err != nil { return $*_ }' ./internal
+
   # JSON output
   omni rg --json "pattern"
 
@@ -59,10 +91,67 @@ Examples:
   # Control parallelism
   omni rg --threads 4 "pattern"
 
+  # Search PDFs through an external converter (output cached by mtime)
+  omni rg --pre pdftotext-stdout --pre-glob "*.pdf" "invoice"
+
+  # Search gzip files and office documents in-process
+  omni rg -z "pattern"
+
+  # List the files that would be searched
+  omni rg --files -t go
+  omni rg -l0 "deprecated" | omni rm -0 --dry-run
+
+  # Search a file list from find (or find -print0) without walking again
+  omni find . -mtime -1 -type f | omni rg --files-from - "TODO"
+  omni find . -name "*.go" -print0 | omni rg -x - -F "panic("
+
+  # Output that diffs cleanly against ripgrep's, with custom separators
+  omni rg --heading -C 2 --context-separator '~~' "pattern"
+  omni rg --no-heading --field-match-separator '\t' "pattern"
+
+  # Search without the configured defaults
+  omni rg --no-defaults "pattern"
+
+  # Define a file type for one search, or save it to the omni config
+  omni rg --type-add 'web:*.html,*.css' -t web "class="
+  omni rg --type-add 'web:*.html,*.css' --type-save
+
 File Types:
   go, js, ts, py, rust, c, cpp, java, rb, php, sh, json, yaml, toml,
   xml, html, css, md, sql, proto, dockerfile, make, txt
 
+  --type-list prints every type with its globs. --type-add NAME:GLOB[,GLOB]
+  adds globs to a type (NAME:include:TYPE[,TYPE] copies other types), and
+  --type-clear NAME removes a type's globs first so it can be redefined.
+  With --type-save, the --type-add and --type-clear arguments update the
+  type-add list in the rg section of the omni config file ($OMNI_CONFIG,
+  default <user config dir>/omni/config.yaml), which every search loads.
+
+Default Flags:
+  Default flags are applied before the command line, lowest precedence
+  first: the default-flags list in the rg section of the omni config,
+  $OMNI_RG_DEFAULT_FLAGS (split like a shell command line), then the
+  nearest .omnirg file found from the first search path upwards (one
+  argument per line, # comments). A flag given on the command line wins
+  over its defaults; repeatable flags such as -g, -t and --type-add add to
+  them instead. .omnirg may not set --pre, --files-from or --type-save.
+  --no-defaults ignores all three sources.
+
+    # omni config                      # .omnirg
+    rg:                                --glob=!vendor/**
+      default-flags:                   --glob=!*.min.js
+        - --smart-case                 --type-add=proto:*.proto
+        - --hidden
+
+Output Layout:
+  On a terminal, matches are grouped under a file name heading with a blank
+  line between files; otherwise (or with --no-heading) every line is
+  prefixed with its path, and with context the files are separated by the
+  context separator. --heading forces grouping. A single file argument is
+  searched without printing its name unless --with-filename is given, and
+  -I hides file names everywhere. Separator arguments accept escapes such
+  as \t and \x1f.
+
 Gitignore Support:
   rg respects multiple ignore sources (in order of precedence):
   - ~/.config/git/ignore (global gitignore)
@@ -71,9 +160,55 @@ Gitignore Support:
   - .ignore files (ripgrep-specific, same hierarchy)
 
   Supports negation patterns (!pattern) to re-include files.
-  Supports directory-only patterns (pattern/).`,
-	Args: cobra.MinimumNArgs(1),
+  Supports directory-only patterns (pattern/).
+
+  Compiled rules are cached under the user cache directory (omni/rg-ignore),
+  keyed by the content hashes of the ignore files, so large rule sets are
+  not recompiled on every run; the least recently used entries go when the
+  cache passes 64 MiB. --no-ignore-cache bypasses the cache.
+
+Go Patterns:
+  --go-pattern PATTERN parses each .go file and matches code by structure
+  rather than text, ignoring formatting and comments. PATTERN is a Go
+  expression, statement list or declaration in which $_ matches any one
+  expression or statement, $*_ matches any number of list elements (call
+  arguments, statements, parameters), and a named wildcard such as $x must
+  match the same code everywhere it appears. Matches print as
+  path:line:column:line; --json includes the full matched code. Files that
+  fail to parse are reported and make rg exit with status 2.
+
+Preprocessing:
+  --pre COMMAND runs COMMAND with the file path as its only argument (and
+  the file on stdin) and searches its stdout instead of the file. Output is
+  cached under the user cache directory, keyed by path, size and mtime;
+  the least recently used entries go when the cache passes 512 MiB.
+  Known types (.gz, .docx, .pptx, .odt) are always handled in-process.
+  -z enables only the in-process handlers. Preprocessed text is capped at
+  256 MiB per file, so a decompression bomb fails instead of filling memory.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if rgPatternless(cmd) {
+			return nil
+		}
+
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := rg.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		if noDefaults, _ := cmd.Flags().GetBool("no-defaults"); !noDefaults {
+			sources, err := rg.LoadDefaults(cfg, rgSearchRoot(cmd, args))
+			if err != nil {
+				return err
+			}
+
+			if err := rg.ApplyDefaults(cmd.Flags(), sources); err != nil {
+				return err
+			}
+		}
+
 		opts := rg.Options{}
 
 		opts.IgnoreCase, _ = cmd.Flags().GetBool("ignore-case")
@@ -81,25 +216,36 @@ Gitignore Support:
 		opts.WordRegexp, _ = cmd.Flags().GetBool("word-regexp")
 		opts.LineNumber, _ = cmd.Flags().GetBool("line-number")
 		opts.Count, _ = cmd.Flags().GetBool("count")
+		opts.CountMatches, _ = cmd.Flags().GetBool("count-matches")
 		opts.FilesWithMatch, _ = cmd.Flags().GetBool("files-with-matches")
+		opts.Null, _ = cmd.Flags().GetBool("null")
 		opts.InvertMatch, _ = cmd.Flags().GetBool("invert-match")
 		opts.Context, _ = cmd.Flags().GetInt("context")
 		opts.Before, _ = cmd.Flags().GetInt("before-context")
 		opts.After, _ = cmd.Flags().GetInt("after-context")
 		opts.Types, _ = cmd.Flags().GetStringSlice("type")
 		opts.TypesNot, _ = cmd.Flags().GetStringSlice("type-not")
+		opts.TypeAdd, _ = cmd.Flags().GetStringArray("type-add")
+		opts.TypeClear, _ = cmd.Flags().GetStringArray("type-clear")
 		opts.Glob, _ = cmd.Flags().GetStringSlice("glob")
 		opts.Hidden, _ = cmd.Flags().GetBool("hidden")
 		opts.NoIgnore, _ = cmd.Flags().GetBool("no-ignore")
 		opts.MaxCount, _ = cmd.Flags().GetInt("max-count")
 		opts.MaxDepth, _ = cmd.Flags().GetInt("max-depth")
+		opts.MaxFilesize, _ = flagvalue.GetByteSize(cmd.Flags(), "max-filesize")
 		opts.FollowSymlinks, _ = cmd.Flags().GetBool("follow")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 		opts.JSONStream, _ = cmd.Flags().GetBool("json-stream")
 		opts.NoHeading, _ = cmd.Flags().GetBool("no-heading")
+		opts.NoFilename, _ = cmd.Flags().GetBool("no-filename")
+		opts.WithFilename, _ = cmd.Flags().GetBool("with-filename")
 		opts.OnlyMatching, _ = cmd.Flags().GetBool("only-matching")
 		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
 		opts.Fixed, _ = cmd.Flags().GetBool("fixed-strings")
+		opts.Patterns, _ = cmd.Flags().GetStringArray("regexp")
+		opts.PatternFiles, _ = cmd.Flags().GetStringArray("file")
+		opts.FilesFrom, _ = cmd.Flags().GetString("files-from")
+		opts.GoPattern, _ = cmd.Flags().GetString("go-pattern")
 		opts.Threads, _ = cmd.Flags().GetInt("threads")
 
 		// New ripgrep-compatible options
@@ -112,9 +258,66 @@ Gitignore Support:
 		opts.ByteOffset, _ = cmd.Flags().GetBool("byte-offset")
 		opts.Stats, _ = cmd.Flags().GetBool("stats")
 		opts.Passthru, _ = cmd.Flags().GetBool("passthru")
-
-		pattern := args[0]
-		paths := args[1:]
+		opts.Pre, _ = cmd.Flags().GetString("pre")
+		opts.PreGlob, _ = cmd.Flags().GetStringSlice("pre-glob")
+		opts.SearchZip, _ = cmd.Flags().GetBool("search-zip")
+
+		contextSep, _ := cmd.Flags().GetString("context-separator")
+		opts.ContextSeparator = rg.UnescapeSeparator(contextSep)
+		opts.NoContextSeparator, _ = cmd.Flags().GetBool("no-context-separator")
+		matchSep, _ := cmd.Flags().GetString("field-match-separator")
+		opts.FieldMatchSeparator = rg.UnescapeSeparator(matchSep)
+		contextFieldSep, _ := cmd.Flags().GetString("field-context-separator")
+		opts.FieldContextSeparator = rg.UnescapeSeparator(contextFieldSep)
+
+		// Like ripgrep, group by file only when writing to a terminal
+		if heading, _ := cmd.Flags().GetBool("heading"); heading {
+			opts.NoHeading = false
+		} else if !cmd.Flags().Changed("no-heading") {
+			opts.NoHeading = !isTerminalOutput(cmd)
+		}
+
+		if noCache, _ := cmd.Flags().GetBool("no-pre-cache"); opts.Pre != "" && !noCache {
+			opts.PreCacheDir, _ = rg.DefaultPreCacheDir()
+		}
+
+		if noCache, _ := cmd.Flags().GetBool("no-ignore-cache"); !opts.NoIgnore && !noCache {
+			opts.IgnoreCacheDir, _ = rg.DefaultIgnoreCacheDir()
+		}
+
+		files, _ := cmd.Flags().GetBool("files")
+		typeList, _ := cmd.Flags().GetBool("type-list")
+
+		if typeSave, _ := cmd.Flags().GetBool("type-save"); typeSave {
+			path, err := rg.SaveTypes(opts.TypeAdd, opts.TypeClear)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "rg: saved type definitions to %s\n", path)
+
+			if len(args) == 0 && !files && !typeList {
+				return nil
+			}
+		}
+
+		opts.ConfigTypes = cfg.TypeAdd
+
+		switch {
+		case typeList:
+			return rg.RunTypeList(cmd.OutOrStdout(), opts)
+		case files:
+			return rg.RunFiles(cmd.Context(), cmd.OutOrStdout(), args, opts)
+		case opts.GoPattern != "":
+			return rg.RunGoPattern(cmd.Context(), cmd.OutOrStdout(), args, opts)
+		}
+
+		var pattern string
+
+		paths := args
+		if len(opts.Patterns) == 0 && len(opts.PatternFiles) == 0 {
+			pattern, paths = args[0], args[1:]
+		}
 
 		return rg.Run(cmd.Context(), cmd.OutOrStdout(), pattern, paths, opts)
 	},
@@ -122,20 +325,34 @@ Gitignore Support:
 
 func init() {
 	rootCmd.AddCommand(rgCmd)
+	doctor.RegisterCacheDir("rg-pre", rg.DefaultPreCacheDir)
+	doctor.RegisterCacheDir("rg-ignore", rg.DefaultIgnoreCacheDir)
 
 	// Case sensitivity
 	rgCmd.Flags().BoolP("ignore-case", "i", false, "case insensitive search")
 	rgCmd.Flags().BoolP("smart-case", "S", false, "smart case (insensitive if pattern is all lowercase)")
 	rgCmd.Flags().BoolP("word-regexp", "w", false, "only match whole words")
 	rgCmd.Flags().BoolP("fixed-strings", "F", false, "treat pattern as literal string")
+	rgCmd.Flags().StringArrayP("regexp", "e", nil, "search for PATTERN; repeat to search for several")
+	rgCmd.Flags().StringArrayP("file", "f", nil, "search for the patterns in FILE, one per line (- for stdin)")
+	rgCmd.Flags().String("go-pattern", "", "search Go files for code matching a structural PATTERN ($x, $*x wildcards)")
 
 	// Output control
 	rgCmd.Flags().BoolP("line-number", "n", false, "show line numbers")
-	rgCmd.Flags().BoolP("count", "c", false, "only show count of matches per file")
+	rgCmd.Flags().BoolP("count", "c", false, "only show count of matching lines per file")
+	rgCmd.Flags().Bool("count-matches", false, "only show count of individual matches per file")
 	rgCmd.Flags().BoolP("files-with-matches", "l", false, "only show file names with matches")
+	rgCmd.Flags().BoolP("null", "0", false, "end file paths printed by --files and -l with NUL")
 	rgCmd.Flags().BoolP("invert-match", "v", false, "show non-matching lines")
 	rgCmd.Flags().BoolP("only-matching", "o", false, "show only matching part of line")
-	rgCmd.Flags().BoolP("no-heading", "H", false, "don't group matches by file name")
+	rgCmd.Flags().BoolP("no-heading", "H", false, "don't group matches by file name (default when not a terminal)")
+	rgCmd.Flags().Bool("heading", false, "group matches under file name headings (default on a terminal)")
+	rgCmd.Flags().BoolP("no-filename", "I", false, "never print file names")
+	rgCmd.Flags().Bool("with-filename", false, "print file names even when searching a single file")
+	rgCmd.Flags().String("context-separator", "--", "separator between non-adjacent context groups")
+	rgCmd.Flags().Bool("no-context-separator", false, "print nothing between context groups")
+	rgCmd.Flags().String("field-match-separator", ":", "separator between fields of matching lines")
+	rgCmd.Flags().String("field-context-separator", "-", "separator between fields of context lines")
 	rgCmd.Flags().BoolP("quiet", "q", false, "quiet mode, exit on first match")
 	rgCmd.Flags().Bool("json-stream", false, "output results as streaming NDJSON (one JSON object per line)")
 
@@ -148,12 +365,25 @@ func init() {
 	rgCmd.Flags().StringSliceP("type", "t", nil, "only search files of TYPE (go, js, py, etc.)")
 	rgCmd.Flags().StringSliceP("type-not", "T", nil, "exclude files of TYPE")
 	rgCmd.Flags().StringSliceP("glob", "g", nil, "include/exclude files matching GLOB (prefix with ! to exclude)")
+	rgCmd.Flags().StringArray("type-add", nil, "add a file type: NAME:GLOB[,GLOB...] or NAME:include:TYPE[,TYPE...]")
+	rgCmd.Flags().StringArray("type-clear", nil, "clear the globs of file type NAME")
+
+	for _, name := range []string{"type", "type-not", "type-clear"} {
+		_ = rgCmd.RegisterFlagCompletionFunc(name, completeRgTypes)
+	}
+	rgCmd.Flags().Bool("type-save", false, "save --type-add/--type-clear to the omni config")
+	rgCmd.Flags().Bool("type-list", false, "list all file types and their globs")
+	rgCmd.Flags().Bool("files", false, "list the files that would be searched, without searching")
+	rgCmd.Flags().StringP("files-from", "x", "", "search only the files listed in FILE, newline or NUL delimited (- for stdin)")
 
 	// Directory control
 	rgCmd.Flags().Bool("hidden", false, "search hidden files and directories")
 	rgCmd.Flags().Bool("no-ignore", false, "don't respect gitignore files")
+	rgCmd.Flags().Bool("no-defaults", false, "ignore default flags from the omni config, $OMNI_RG_DEFAULT_FLAGS and .omnirg")
+	rgCmd.Flags().Bool("no-ignore-cache", false, "compile ignore rules from scratch instead of using the cache")
 	rgCmd.Flags().IntP("max-count", "m", 0, "limit matches per file")
 	rgCmd.Flags().Int("max-depth", 0, "limit directory traversal depth")
+	flagvalue.ByteSizeP(rgCmd.Flags(), "max-filesize", "", 0, "skip files larger than SIZE (e.g. 512K, 1M)")
 	rgCmd.Flags().BoolP("follow", "L", false, "follow symbolic links")
 
 	// Performance
@@ -171,4 +401,50 @@ func init() {
 	rgCmd.Flags().BoolP("byte-offset", "b", false, "show byte offset of each line (not yet implemented)")
 	rgCmd.Flags().Bool("stats", false, "show search statistics")
 	rgCmd.Flags().Bool("passthru", false, "show all lines, highlighting matches")
+
+	// Preprocessing
+	rgCmd.Flags().String("pre", "", "search the output of COMMAND run on each file")
+	rgCmd.Flags().StringSlice("pre-glob", nil, "only preprocess files matching GLOB")
+	rgCmd.Flags().Bool("no-pre-cache", false, "don't cache --pre output")
+	rgCmd.Flags().BoolP("search-zip", "z", false, "search gzip files and office documents (in-process)")
+}
+
+// rgPatternless reports whether every rg argument is a path because the
+// patterns come from flags or no search is done.
+func rgPatternless(cmd *cobra.Command) bool {
+	files, _ := cmd.Flags().GetBool("files")
+	typeList, _ := cmd.Flags().GetBool("type-list")
+	typeSave, _ := cmd.Flags().GetBool("type-save")
+
+	return files || typeList || typeSave || cmd.Flags().Changed("regexp") || cmd.Flags().Changed("file") || cmd.Flags().Changed("go-pattern")
+}
+
+// rgSearchRoot returns the first path rg searches, where the lookup of the
+// per-repository defaults file starts.
+func rgSearchRoot(cmd *cobra.Command, args []string) string {
+	paths := args
+	if !rgPatternless(cmd) && len(paths) > 0 {
+		paths = paths[1:]
+	}
+
+	if len(paths) == 0 || paths[0] == "-" {
+		return "."
+	}
+
+	return paths[0]
+}
+
+// isTerminalOutput reports whether the command writes to a terminal.
+func isTerminalOutput(cmd *cobra.Command) bool {
+	f, ok := cmd.OutOrStdout().(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// completeRgTypes completes file type names, including types from the
+// omni config and --type-add flags given earlier on the command line.
+func completeRgTypes(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	typeAdd, _ := cmd.Flags().GetStringArray("type-add")
+
+	return rg.CompleteTypes(typeAdd), cobra.ShellCompDirectiveNoFileComp
 }
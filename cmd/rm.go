@@ -16,19 +16,42 @@ Protected paths (system directories, SSH keys, credentials, etc.) cannot be
 deleted without explicit override flags. Use --force for non-critical
 protected paths, or --no-preserve-root for critical system paths.
 
+  -r, --recursive         remove directories and their contents recursively
+  -f, --force             ignore nonexistent files, never prompt
+      --no-preserve-root  do not treat protected paths specially
+      --dry-run           print what would be removed without removing it
+  -i, --interactive       prompt before every removal (needs a terminal)
+  -y, --yes               answer yes to every prompt
+      --files-from=FILE   also remove the paths listed in FILE (- for stdin),
+                          one per line or NUL-separated
+  -0, --null              read NUL-separated paths from stdin (or --files-from)
+
+Paths read with -0 may contain spaces and newlines, so the output of
+find -print0, rg --files -0, tree -0 or dedupe -0 can be removed safely.
+
 Examples:
   omni rm file.txt             # remove a file
   omni rm -r dir/              # remove a directory recursively
   omni rm -f missing.txt      # ignore nonexistent files
-  omni remove a.txt b.txt     # remove multiple files (alias)`,
+  omni remove a.txt b.txt     # remove multiple files (alias)
+  omni rm -r --dry-run build/ # preview what would be removed
+  omni rm -i *.log            # confirm each file
+  omni find . -name "*.tmp" -print0 | omni rm -0`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		recursive, _ := cmd.Flags().GetBool("recursive")
 		force, _ := cmd.Flags().GetBool("force")
 		noPreserveRoot, _ := cmd.Flags().GetBool("no-preserve-root")
-		return rm.RunRm(args, rm.RmOptions{
+
+		listed, err := fileListArgs(cmd)
+		if err != nil {
+			return err
+		}
+
+		return rm.RunRm(append(args, listed...), rm.RmOptions{
 			Recursive:      recursive,
 			Force:          force,
 			NoPreserveRoot: noPreserveRoot,
+			Confirm:        getConfirmOpts(cmd),
 		})
 	},
 }
@@ -39,4 +62,6 @@ func init() {
 	rmCmd.Flags().BoolP("recursive", "r", false, "remove directories and their contents recursively")
 	rmCmd.Flags().BoolP("force", "f", false, "ignore nonexistent files and arguments, never prompt")
 	rmCmd.Flags().Bool("no-preserve-root", false, "do not treat protected paths specially (dangerous)")
+	addConfirmFlags(rmCmd, "i")
+	addFileListFlags(rmCmd)
 }
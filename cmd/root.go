@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/flags"
+	"github.com/inovacc/omni/internal/i18n"
 	"github.com/inovacc/omni/internal/logger"
 	"github.com/spf13/cobra"
 )
@@ -42,6 +44,8 @@ Examples:
 
 		log := logger.Init(cmd.Name())
 		if log.IsActive() {
+			log.SetFlags(cmd.Flags())
+
 			// Wrap stdout/stderr to capture output
 			stdout, stderr := log.StartExecution(cmd.Name(), args, cmd.OutOrStdout(), cmd.ErrOrStderr())
 			cmd.SetOut(stdout)
@@ -73,6 +77,11 @@ func Execute() {
 		finalize(err)
 	}()
 
+	if handled, perr := dispatchPlugin(os.Args[1:]); handled {
+		err = perr
+		return
+	}
+
 	err = rootCmd.Execute()
 }
 
@@ -88,21 +97,97 @@ func finalize(err error) {
 	}
 
 	if err != nil {
-		// Print error unless it's a silent exit (e.g. grep no-match)
+		// Print error unless it's a silent exit (e.g. grep no-match). With
+		// --error-format json a silent exit that carries a classification
+		// (a partial failure) still gets an envelope.
 		var silent *cmderr.SilentError
-		if !errors.As(err, &silent) {
-			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+
+		isSilent := errors.As(err, &silent)
+
+		switch {
+		case errorFormat() == "json":
+			if !isSilent || silent.Err != nil {
+				_ = cmderr.WriteJSON(os.Stderr, commandPath(), err)
+			}
+		case !isSilent:
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", i18n.T("error.prefix"), localizeError(err))
 		}
 
 		os.Exit(cmderr.ExitCodeFor(err))
 	}
 }
 
+// errorFormat returns the --error-format value, falling back to
+// $OMNI_ERROR_FORMAT so wrappers can opt in without touching every call.
+func errorFormat() string {
+	if format, _ := rootCmd.PersistentFlags().GetString("error-format"); format != "" {
+		return format
+	}
+
+	return os.Getenv("OMNI_ERROR_FORMAT")
+}
+
+// errorClasses are the sentinels whose ": <sentinel>" suffix, added by
+// cmderr.Wrap, is translated in text error output.
+var errorClasses = []error{
+	cmderr.ErrInvalidInput, cmderr.ErrNotFound, cmderr.ErrPermission, cmderr.ErrNetwork, cmderr.ErrIO,
+	cmderr.ErrConflict, cmderr.ErrTimeout, cmderr.ErrUnsupported, cmderr.ErrPartial,
+}
+
+// localizeError returns the error message with its class suffix in the
+// selected language. The rest of the message is already localized by the
+// command, or stays in English.
+func localizeError(err error) string {
+	msg := err.Error()
+
+	for _, class := range errorClasses {
+		if !errors.Is(err, class) {
+			continue
+		}
+
+		if rest, ok := strings.CutSuffix(msg, ": "+class.Error()); ok {
+			return rest + ": " + i18n.T("error."+cmderr.Code(class))
+		}
+	}
+
+	return msg
+}
+
+// initLang selects the output language from --lang, or from LC_ALL,
+// LC_MESSAGES and LANG.
+func initLang() {
+	lang, _ := rootCmd.PersistentFlags().GetString("lang")
+	if lang == "" {
+		_ = i18n.SetLang(i18n.Detect())
+		return
+	}
+
+	if err := i18n.SetLang(lang); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "omni: %s; using %s\n", err, i18n.Lang())
+	}
+}
+
+// commandPath names the command that failed, for the JSON error envelope.
+func commandPath() string {
+	c, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil || c == nil {
+		return rootCmd.Name()
+	}
+
+	return c.CommandPath()
+}
+
 func init() {
+	cobra.OnInitialize(initLang)
+
 	rootCmd.Version = rootVersion()
 	rootCmd.SilenceErrors = true
 	rootCmd.SilenceUsage = true
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.PersistentFlags().Bool("json", false, "output as JSON")
 	rootCmd.PersistentFlags().Bool("table", false, "output as aligned table")
+	rootCmd.PersistentFlags().Bool("markdown", false, "output tables as Markdown")
+	rootCmd.PersistentFlags().Bool("csv", false, "output tables as CSV")
+	rootCmd.PersistentFlags().String("lang", "", "output language, e.g. pt-BR (default from LC_ALL, LC_MESSAGES or LANG)")
+	rootCmd.PersistentFlags().String("error-format", "", "error output on stderr: text or json (default $OMNI_ERROR_FORMAT or text)")
 }
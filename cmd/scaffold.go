@@ -13,14 +13,17 @@ import (
 	"github.com/inovacc/omni/internal/cli/scaffolding"
 	scaffoldcobra "github.com/inovacc/omni/internal/cli/scaffolding/cobra"
 	"github.com/inovacc/omni/internal/cli/scaffolding/handler"
+	scaffoldgrpc "github.com/inovacc/omni/internal/cli/scaffolding/grpc"
+	scaffoldlicense "github.com/inovacc/omni/internal/cli/scaffolding/license"
 	scaffoldmcp "github.com/inovacc/omni/internal/cli/scaffolding/mcp"
 	"github.com/inovacc/omni/internal/cli/scaffolding/repository"
 	"github.com/inovacc/omni/internal/cli/scaffolding/testgen"
 )
 
 var scaffoldCmd = &cobra.Command{
-	Use:   "scaffold",
-	Short: "Code scaffolding utilities",
+	Use:     "scaffold",
+	Aliases: []string{"generate"},
+	Short:   "Code scaffolding utilities",
 	Long: `scaffold provides code generation utilities for scaffolding applications.
 
 Subcommands:
@@ -29,8 +32,11 @@ Subcommands:
   cobra config  Manage cobra generator configuration
   handler       Generate HTTP handler
   repository    Generate database repository
-  test          Generate tests for a Go source file
+  test          Generate tests for a Go source file or package
   mcp           Generate MCP server with tools, resources, and debug logging
+  grpc          Generate gRPC service (buf config, stubs, server, health checks)
+  license       Write a LICENSE file from the SPDX catalog
+  license-headers  Stamp/update SPDX license headers across a tree
 
 Configuration:
   Default values can be set in ~/.cobra.yaml (compatible with cobra-cli).
@@ -43,7 +49,11 @@ Examples:
   omni scaffold handler user --method GET,POST --framework chi
   omni scaffold repository user --entity User --table users
   omni scaffold test internal/cli/foo/foo.go
-  omni scaffold mcp myserver --transport sse --addr :9090`,
+  omni generate tests ./pkg/foo
+  omni scaffold mcp myserver --transport sse --addr :9090
+  omni generate grpc --module example.com/svc --proto api/v1/service.proto
+  omni generate license MIT --author "Jane Doe"
+  omni generate license-headers --license MIT --author "Jane Doe" --apply`,
 }
 
 var scaffoldCobraCmd = &cobra.Command{
@@ -413,12 +423,19 @@ Examples:
 }
 
 var scaffoldTestCmd = &cobra.Command{
-	Use:   "test <file.go>",
-	Short: "Generate tests for a Go source file",
-	Long: `Generate test stubs for exported functions in a Go source file.
-
-Parses the input file and generates test functions for all exported
-functions and methods.
+	Use:     "test <file.go|dir>",
+	Aliases: []string{"tests"},
+	Short:   "Generate tests for a Go source file or package",
+	Long: `Generate test stubs for the exported functions and methods of a Go source
+file, or of every non-test Go file in a package directory.
+
+Table-driven tests call the function with fields from an empty test
+table, so the skeleton compiles and passes until cases are added.
+Functions returning an error get a wantErr field and an error-path case
+to fill in. Functions that already have a test (TestName, TestType_Name
+or TestTypeName, optionally with a _suffix) are skipped, and tests for a
+file with an existing _test.go file are appended to it. Generic functions
+are skipped.
 
   --table           Generate table-driven tests (default: true)
   --parallel        Add t.Parallel() calls
@@ -428,6 +445,7 @@ functions and methods.
 
 Examples:
   omni scaffold test internal/cli/foo/foo.go
+  omni generate tests ./pkg/foo
   omni scaffold test pkg/service/user.go --parallel
   omni scaffold test handler.go --table=false
   omni scaffold test service.go --benchmark --mock`,
@@ -491,6 +509,139 @@ Examples:
 	},
 }
 
+var scaffoldGRPCCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Generate gRPC service",
+	Long: `Generate a Go gRPC service around a proto definition.
+
+If the proto file exists, its services and RPCs drive the generated server;
+otherwise a starter service with a Ping RPC is written. Stubs are generated
+with the embedded buf toolchain (omni buf generate), which runs the
+protoc-gen-go and protoc-gen-go-grpc plugins.
+
+Generates:
+  - buf.yaml, buf.gen.yaml       buf module and generation config
+  - <proto>                      starter service (only if missing)
+  - go.mod                       module file
+  - cmd/server/main.go           server with health checks and reflection
+  - internal/server/<svc>.go     service implementation stubs
+  - gen/go/...                   generated stubs (buf generate)
+
+  -m, --module       Go module path (required)
+  --proto            Proto file path (default: api/v1/service.proto)
+  -d, --dir          Project directory (default: ".")
+  --addr             Default listen address (default: ":50051")
+  --generate         Run buf generate after scaffolding (default: true)
+  -f, --force        Overwrite existing files
+
+Examples:
+  omni generate grpc --module example.com/svc
+  omni generate grpc --module example.com/svc --proto api/v1/service.proto
+  omni generate grpc -m example.com/orders --proto proto/orders.proto --generate=false`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		opts := scaffoldgrpc.GRPCOptions{}
+		opts.Module, _ = cmd.Flags().GetString("module")
+		opts.Proto, _ = cmd.Flags().GetString("proto")
+		opts.Dir, _ = cmd.Flags().GetString("dir")
+		opts.Addr, _ = cmd.Flags().GetString("addr")
+		opts.Generate, _ = cmd.Flags().GetBool("generate")
+		opts.Force, _ = cmd.Flags().GetBool("force")
+
+		return scaffoldgrpc.RunGRPCInit(cmd.OutOrStdout(), afero.NewOsFs(), opts, scaffolding.Options{JSON: jsonOutput})
+	},
+}
+
+var scaffoldLicenseCmd = &cobra.Command{
+	Use:   "license [SPDX-ID]",
+	Short: "Write a LICENSE file from the SPDX catalog",
+	Long: `Write a LICENSE file for an SPDX license identifier.
+
+Identifiers are matched case-insensitively; common aliases (apache, bsd-3,
+gpl-3.0, ...) are accepted. Full text is bundled for short permissive
+licenses; for the rest the catalog links to the canonical SPDX page.
+
+  -a, --author       Copyright holder
+  --year             Copyright year (default: current year)
+  -o, --output       Output path (default: LICENSE, "-" for stdout)
+  -f, --force        Overwrite an existing file
+  --list             List the SPDX catalog
+
+Examples:
+  omni generate license MIT --author "Jane Doe"
+  omni generate license Apache-2.0 -a "ACME Corp" -o LICENSE.txt
+  omni generate license ISC -o -
+  omni generate license --list`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		list, _ := cmd.Flags().GetBool("list")
+
+		if list {
+			return scaffoldlicense.RunList(cmd.OutOrStdout(), scaffolding.Options{JSON: jsonOutput})
+		}
+
+		if len(args) == 0 {
+			return fmt.Errorf("scaffold: license identifier is required (see --list)")
+		}
+
+		opts := scaffoldlicense.LicenseOptions{}
+		opts.Author, _ = cmd.Flags().GetString("author")
+		opts.Year, _ = cmd.Flags().GetInt("year")
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.Force, _ = cmd.Flags().GetBool("force")
+
+		return scaffoldlicense.RunLicense(cmd.OutOrStdout(), afero.NewOsFs(), args[0], opts, scaffolding.Options{JSON: jsonOutput})
+	},
+}
+
+var scaffoldLicenseHeadersCmd = &cobra.Command{
+	Use:   "license-headers [DIR]",
+	Short: "Stamp or update SPDX license headers in source files",
+	Long: `Stamp or update SPDX-License-Identifier headers across a source tree.
+
+Walks DIR (default: current directory) respecting .gitignore, skips files
+marked "Code generated ... DO NOT EDIT." and preserves shebang lines.
+Existing headers are updated in place; a copyright line is only rewritten
+when it is the "Copyright (c) YEAR NAME" line directly above the SPDX line,
+so third-party notices stay as they are. Without --apply, only reports.
+
+  -l, --license      SPDX identifier to stamp (required)
+  -a, --author       Copyright holder (adds a Copyright line)
+  --year             Copyright year (default: current year)
+  --apply            Write changes
+  --check            Exit non-zero when files need changes (CI gate)
+  -e, --ext          Restrict to extensions (comma-separated, e.g. go,py)
+  --no-ignore        Do not respect .gitignore
+
+Examples:
+  omni generate license-headers --license MIT --author "Jane Doe"
+  omni generate license-headers . -l Apache-2.0 -a "ACME" --apply
+  omni generate license-headers -l MIT --check`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		opts := scaffoldlicense.HeaderOptions{}
+		opts.License, _ = cmd.Flags().GetString("license")
+		opts.Author, _ = cmd.Flags().GetString("author")
+		opts.Year, _ = cmd.Flags().GetInt("year")
+		opts.Apply, _ = cmd.Flags().GetBool("apply")
+		opts.Check, _ = cmd.Flags().GetBool("check")
+		opts.Extensions, _ = cmd.Flags().GetStringSlice("ext")
+		opts.NoIgnore, _ = cmd.Flags().GetBool("no-ignore")
+
+		root := "."
+		if len(args) > 0 {
+			root = args[0]
+		}
+
+		return scaffoldlicense.RunLicenseHeaders(cmd.OutOrStdout(), root, opts, scaffolding.Options{JSON: jsonOutput})
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(scaffoldCmd)
 	scaffoldCmd.AddCommand(scaffoldCobraCmd)
@@ -498,6 +649,9 @@ func init() {
 	scaffoldCmd.AddCommand(scaffoldRepositoryCmd)
 	scaffoldCmd.AddCommand(scaffoldTestCmd)
 	scaffoldCmd.AddCommand(scaffoldMCPCmd)
+	scaffoldCmd.AddCommand(scaffoldGRPCCmd)
+	scaffoldCmd.AddCommand(scaffoldLicenseCmd)
+	scaffoldCmd.AddCommand(scaffoldLicenseHeadersCmd)
 	scaffoldCobraCmd.AddCommand(scaffoldCobraInitCmd)
 	scaffoldCobraCmd.AddCommand(scaffoldCobraAddCmd)
 	scaffoldCobraCmd.AddCommand(scaffoldCobraAddToolsCmd)
@@ -511,7 +665,7 @@ func init() {
 	scaffoldCobraInitCmd.Flags().StringP("name", "n", "", "application name (defaults to directory name)")
 	scaffoldCobraInitCmd.Flags().StringP("description", "d", "", "application description")
 	scaffoldCobraInitCmd.Flags().StringP("author", "a", "", "author name")
-	scaffoldCobraInitCmd.Flags().StringP("license", "l", "", "license type (MIT, Apache-2.0, BSD-3)")
+	scaffoldCobraInitCmd.Flags().StringP("license", "l", "", "license type (SPDX identifier, e.g. MIT, Apache-2.0, BSD-3-Clause)")
 	scaffoldCobraInitCmd.Flags().Bool("viper", false, "include viper for configuration")
 	scaffoldCobraInitCmd.Flags().Bool("service", false, "include OS service pattern (kardianos/service)")
 	scaffoldCobraInitCmd.Flags().Bool("daemon", false, "include self-daemonizing PID-file pattern with server start/stop/restart/status/install/uninstall (mutually exclusive with --service)")
@@ -565,4 +719,30 @@ func init() {
 	scaffoldMCPCmd.Flags().StringP("module", "m", "", "Go module path (auto-detected from go.mod)")
 	scaffoldMCPCmd.Flags().String("transport", "stdio", "transport type: stdio, sse, http-stream")
 	scaffoldMCPCmd.Flags().String("addr", ":8080", "listen address (for sse/http-stream)")
+
+	// Flags for grpc
+	scaffoldGRPCCmd.Flags().StringP("module", "m", "", "Go module path (required)")
+	scaffoldGRPCCmd.Flags().String("proto", "api/v1/service.proto", "proto file path relative to the project directory")
+	scaffoldGRPCCmd.Flags().StringP("dir", "d", ".", "project directory")
+	scaffoldGRPCCmd.Flags().String("addr", ":50051", "default listen address")
+	scaffoldGRPCCmd.Flags().Bool("generate", true, "run buf generate after scaffolding")
+	scaffoldGRPCCmd.Flags().BoolP("force", "f", false, "overwrite existing files")
+	_ = scaffoldGRPCCmd.MarkFlagRequired("module")
+
+	// Flags for license
+	scaffoldLicenseCmd.Flags().StringP("author", "a", "", "copyright holder")
+	scaffoldLicenseCmd.Flags().Int("year", 0, "copyright year (default: current year)")
+	scaffoldLicenseCmd.Flags().StringP("output", "o", "LICENSE", "output path (\"-\" for stdout)")
+	scaffoldLicenseCmd.Flags().BoolP("force", "f", false, "overwrite an existing file")
+	scaffoldLicenseCmd.Flags().Bool("list", false, "list the SPDX license catalog")
+
+	// Flags for license-headers
+	scaffoldLicenseHeadersCmd.Flags().StringP("license", "l", "", "SPDX identifier to stamp (required)")
+	scaffoldLicenseHeadersCmd.Flags().StringP("author", "a", "", "copyright holder")
+	scaffoldLicenseHeadersCmd.Flags().Int("year", 0, "copyright year (default: current year)")
+	scaffoldLicenseHeadersCmd.Flags().Bool("apply", false, "write changes (default: report only)")
+	scaffoldLicenseHeadersCmd.Flags().Bool("check", false, "exit non-zero when files need header changes")
+	scaffoldLicenseHeadersCmd.Flags().StringSliceP("ext", "e", nil, "restrict to file extensions (comma-separated)")
+	scaffoldLicenseHeadersCmd.Flags().Bool("no-ignore", false, "do not respect .gitignore files")
+	_ = scaffoldLicenseHeadersCmd.MarkFlagRequired("license")
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/doctor"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/scan"
 	"github.com/spf13/cobra"
 )
@@ -131,13 +133,14 @@ func scanOptsFromFlags(cmd *cobra.Command) scan.Options {
 	opts.DBSigPath, _ = cmd.Flags().GetString("db-sig")
 	opts.FailOn, _ = cmd.Flags().GetString("fail-on")
 	opts.OutputFormat = getOutputOpts(cmd).GetFormat()
-	opts.MaxDBAge, _ = cmd.Flags().GetDuration("max-db-age")
+	opts.MaxDBAge, _ = flagvalue.GetDuration(cmd.Flags(), "max-db-age")
 	opts.Online, _ = cmd.Flags().GetBool("online")
 	return opts
 }
 
 func init() {
 	rootCmd.AddCommand(scanCmd)
+	doctor.RegisterCacheDir("osv-db", defaultCacheDir)
 	scanCmd.AddCommand(scanSourceCmd)
 	scanCmd.AddCommand(scanDBCmd)
 	scanDBCmd.AddCommand(scanDBUpdateCmd)
@@ -148,7 +151,7 @@ func init() {
 	scanCmd.PersistentFlags().String("db-key", "", "minisign public key (*.pub) used to verify the bundle")
 	scanCmd.PersistentFlags().String("db-sig", "", "detached signature path (default: <db>.minisig)")
 	scanCmd.PersistentFlags().String("fail-on", "", "fail on a finding >= LEVEL (none|low|medium|high|critical)")
-	scanCmd.PersistentFlags().Duration("max-db-age", time.Duration(0), "fail if the DB is older than this (0 disables)")
+	flagvalue.DurationP(scanCmd.PersistentFlags(), "max-db-age", "", time.Duration(0), "fail if the DB is older than this (0 disables)")
 	scanCmd.PersistentFlags().Bool("online", false, "enable OSV-API enrichment over net/http (opt-in)")
 
 	// db update download flags (db-key is inherited from scanCmd's persistent flags).
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pipe"
+	"github.com/inovacc/omni/internal/cli/script"
+	"github.com/spf13/cobra"
+)
+
+// scriptCmd represents the script command
+var scriptCmd = &cobra.Command{
+	Use:   "script",
+	Short: "Run cross-platform omni scripts",
+	Long: `Run scripts written in a small, restricted language that calls omni
+commands in-process, so the same script works under bash, PowerShell and cmd.exe.
+
+Language:
+  let x = expr / x = expr       assignment (strings, integers, booleans, lists)
+  if cond { } else if { } else { }
+  for name in expr { }          lists, lines of a string, or 0..n-1
+  while cond { }                with break and continue
+  cmd arg "a ${x}" 'lit' $list  any other line runs an omni command
+  cmd | cmd < in > out >> log   pipes and redirections
+  $(cmd)  $?(cmd)               captured output / success as a boolean
+  try cmd                       ignore a failure; $status holds the exit code
+  cd DIR   exit [N]   fail MSG
+  len str int lines split fields join trim upper lower basename dirname
+  contains hasprefix hassuffix replace matches exists isdir isfile glob
+  env setenv read write range print
+
+Predefined variables: args (script arguments), status, os, arch.
+A failing command stops the script unless prefixed with try.
+
+Subcommands:
+  run     Run a script
+  check   Parse a script without running it
+
+Examples:
+  omni script run build.omni
+  omni script run release.omni v1.2.0
+  omni script run -c 'for f in glob("*.log") { gzip $f }'
+  omni script check build.omni`,
+}
+
+var scriptRunCmd = &cobra.Command{
+	Use:   "run [OPTION]... FILE [ARG]...",
+	Short: "Run a script",
+	Long: `Run an omni script. Arguments after FILE are available as the list args;
+options must come before FILE.
+
+Commands that start external processes (exec, env run, for, task,
+terraform, git, gh, repo, buf generate, notify, and rg --pre) are refused
+unless --allow-exec is given, also when another command such as lock, time
+or pipe runs them.
+
+  -c, --command SCRIPT   run SCRIPT instead of a file; all arguments go to args
+      --allow-exec       allow commands that start external processes
+      --var NAME=VALUE   predefine a variable (repeatable)
+
+Examples:
+  omni script run build.omni
+  omni script run --var region=eu-west-1 deploy.omni staging
+  omni script run -c 'echo "hello ${args[0]}"' world
+  omni script run --allow-exec ci.omni`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := script.Options{Stdin: cmd.InOrStdin(), Vars: map[string]string{}}
+
+		opts.Code, _ = cmd.Flags().GetString("command")
+		allowExec, _ := cmd.Flags().GetBool("allow-exec")
+		vars, _ := cmd.Flags().GetStringArray("var")
+
+		for _, v := range vars {
+			name, value, ok := strings.Cut(v, "=")
+			if !ok || name == "" {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("script: --var %q: expected NAME=VALUE", v))
+			}
+
+			opts.Vars[name] = value
+		}
+
+		ctx := pipe.WithGate(cmd.Context(), func(cmdArgs []string) error {
+			return checkScriptCommand(cmdArgs, allowExec)
+		})
+
+		return script.RunScript(ctx, cmd.OutOrStdout(), pipe.NewRegistry(rootCmd), args, opts)
+	},
+}
+
+// checkScriptCommand refuses omni script itself and, without allowExec,
+// commands that start external processes. It gates every command a script
+// runs in-process, including those run by lock, time, bench or pipe.
+func checkScriptCommand(cmdArgs []string, allowExec bool) error {
+	c, rest, err := rootCmd.Find(cmdArgs)
+	if err != nil || c == rootCmd {
+		return nil
+	}
+
+	top := c
+	for top.Parent() != rootCmd {
+		top = top.Parent()
+	}
+
+	if top == scriptCmd {
+		return cmderr.Wrap(cmderr.ErrUnsupported, "script: scripts cannot run omni script")
+	}
+
+	if site, ok := findExecSite(c, rest); ok && !allowExec {
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("script: %s starts external processes; pass --allow-exec", site))
+	}
+
+	return nil
+}
+
+var scriptCheckCmd = &cobra.Command{
+	Use:   "check FILE...",
+	Short: "Parse a script without running it",
+	Long: `Parse scripts and report syntax errors without running them.
+
+Examples:
+  omni script check build.omni
+  omni script check scripts/*.omni`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range args {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("script: %s", name))
+			}
+
+			if err := script.Check(name, string(data)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scriptCmd)
+	scriptCmd.AddCommand(scriptRunCmd)
+	scriptCmd.AddCommand(scriptCheckCmd)
+
+	scriptRunCmd.Flags().StringP("command", "c", "", "run SCRIPT instead of a file")
+	scriptRunCmd.Flags().Bool("allow-exec", false, "allow commands that start external processes")
+	scriptRunCmd.Flags().StringArray("var", nil, "predefine a variable (NAME=VALUE)")
+	scriptRunCmd.Flags().SetInterspersed(false)
+}
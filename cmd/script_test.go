@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pipe"
+	"github.com/inovacc/omni/internal/cli/rg"
+	"github.com/inovacc/omni/internal/cli/script"
+)
+
+// TestInProcessCommandContext runs free, which derives a signal context
+// from cmd.Context(), through script and pipe: both dispatch it in-process
+// and must hand it a context.
+func TestInProcessCommandContext(t *testing.T) {
+	registry := pipe.NewRegistry(rootCmd)
+
+	var buf bytes.Buffer
+	if err := script.RunScript(context.Background(), &buf, registry, nil, script.Options{Code: "free"}); err != nil {
+		t.Fatalf("script free: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Mem:") {
+		t.Errorf("script free output = %q", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := pipe.Run(&buf, []string{"free"}, pipe.Options{}, registry); err != nil {
+		t.Fatalf("pipe free: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Mem:") {
+		t.Errorf("pipe free output = %q", buf.String())
+	}
+}
+
+// TestInProcessKeepsErrorFormat runs failing commands in-process under
+// --error-format json: dispatching them must not reset the flags of the
+// outer invocation, which decide how its error is reported.
+func TestInProcessKeepsErrorFormat(t *testing.T) {
+	flags := rootCmd.PersistentFlags()
+	if err := flags.Set("error-format", "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = flags.Set("error-format", "")
+		flags.Lookup("error-format").Changed = false
+	})
+
+	registry := pipe.NewRegistry(rootCmd)
+
+	for _, args := range [][]string{
+		{"script", "run", "-c", "echo hi\nlet x = $y"},
+		{"time", "--", "cat", filepath.Join(t.TempDir(), "missing")},
+		{"script", "run", "-c", "time -- cat --error-format text missing"},
+	} {
+		if err := registry.Run(context.Background(), io.Discard, nil, args); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+
+		if got := errorFormat(); got != "json" {
+			t.Errorf("after %q: error format = %q, want json", args, got)
+		}
+	}
+}
+
+// TestScriptRefusesExecSites runs exec sites from a script without
+// --allow-exec, directly and through commands that run other commands.
+func TestScriptRefusesExecSites(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	for _, code := range []string{
+		"git status",
+		"gh pr-diff 1",
+		"notify hi",
+		"rg --pre cat x",
+		"lock acquire " + filepath.Join(dir, "l.lock") + " -- git status",
+		"time git status",
+		"bench -- git status",
+		`pipe 'echo status' 'git $OUT'`,
+	} {
+		run, err := pipe.PrepareCommand(rootCmd, []string{"script", "run", "-c", code}, pipe.Stdio{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := run(context.Background()); err == nil || !strings.Contains(err.Error(), "pass --allow-exec") {
+			t.Errorf("script %q: error = %v, want refusal", code, err)
+		}
+	}
+
+	t.Setenv(rg.EnvDefaultFlags, "")
+
+	for _, tc := range []struct {
+		args      []string
+		allowExec bool
+	}{
+		{[]string{"rg", "--pre-glob", "*.pdf", "x"}, false},
+		{[]string{"rg", "x", "--", "--pre"}, false},
+		{[]string{"free"}, false},
+		{[]string{"git", "status"}, true},
+	} {
+		if err := checkScriptCommand(tc.args, tc.allowExec); err != nil {
+			t.Errorf("checkScriptCommand(%q, %v) error = %v", tc.args, tc.allowExec, err)
+		}
+	}
+
+	t.Setenv(rg.EnvDefaultFlags, "--pre=cat")
+
+	if err := checkScriptCommand([]string{"rg", "x"}, false); !cmderr.IsPermission(err) {
+		t.Errorf("rg with --pre in $%s: error = %v, want ErrPermission", rg.EnvDefaultFlags, err)
+	}
+}
@@ -16,6 +16,9 @@ transformations on an input stream (a file or input from a pipeline).
   -i[SUFFIX]     edit files in place (makes backup if SUFFIX supplied)
   -n             suppress automatic printing of pattern space
   -E, -r         use extended regular expressions
+  --dry-run      with -i, list the files that would change without writing
+  --interactive  with -i, ask before rewriting each changed file
+  -y, --yes      answer yes to every prompt
 
 Supported commands:
   s/regexp/replacement/flags  substitute
@@ -23,12 +26,23 @@ Supported commands:
   p                           print pattern space
   q                           quit
 
+Without -E the regexp is a basic regex: \( \) group and \{ \} \+ \? \|
+are operators, as in GNU sed. In the replacement:
+  &, \0     the whole match (\& is a literal &)
+  \1..\9    the text of a capture group
+  \U, \L    upper- or lower-case the rest, until \E
+  \u, \l    upper- or lower-case the next character
+  \n, \t    newline and tab
+
 Examples:
   omni sed 's/old/new/' file.txt        # replace first occurrence
   omni sed 's/old/new/g' file.txt       # replace all occurrences
   omni sed -i.bak 's/foo/bar/g' file    # in-place edit with backup
+  omni sed -i --dry-run 's/v1/v2/' *.md # which files would change
   omni sed '/pattern/d' file.txt        # delete matching lines
-  omni sed -n '/pattern/p' file.txt     # print only matching lines`,
+  omni sed -n '/pattern/p' file.txt     # print only matching lines
+  omni sed 's/\(\w*\) \(\w*\)/\2 \1/' f # swap the first two words
+  omni sed -E 's/\w+/\u&/g' file.txt    # capitalize every word`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := sed.SedOptions{}
 
@@ -38,6 +52,7 @@ Examples:
 		opts.InPlaceExt, _ = cmd.Flags().GetString("in-place-suffix")
 		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
 		opts.Extended, _ = cmd.Flags().GetBool("regexp-extended")
+		opts.Confirm = getConfirmOpts(cmd)
 
 		return sed.RunSed(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
 	},
@@ -52,4 +67,5 @@ func init() {
 	sedCmd.Flags().BoolP("quiet", "n", false, "suppress automatic printing of pattern space")
 	sedCmd.Flags().BoolP("regexp-extended", "E", false, "use extended regular expressions")
 	sedCmd.Flags().BoolP("r", "r", false, "use extended regular expressions (alias)")
+	addConfirmFlags(sedCmd, "")
 }
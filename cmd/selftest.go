@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/inovacc/omni/internal/cli/pipe"
+	"github.com/inovacc/omni/internal/cli/selftest"
+	"github.com/spf13/cobra"
+)
+
+// selftestCmd represents the selftest command
+var selftestCmd = &cobra.Command{
+	Use:   "selftest [OPTION]...",
+	Short: "Check that this omni binary behaves correctly on this machine",
+	Long: `Run a curated set of conformance cases against the commands of this
+omni binary, in-process, and report pass/fail per module.
+
+Use it to validate a deployed binary on an unusual OS, architecture or
+filesystem before relying on it. Every case runs in its own temporary
+directory, which is removed afterwards; no case uses the network.
+
+Modules:
+  text       cat, head, tail, sort, wc, cut, tr, sed, grep, rev, tac, seq
+  fs         mkdir, touch, cp, mv, rm, ls, find, basename, dirname
+  encoding   base64, hex, url
+  hash       sha256sum
+  data       jq, yaml
+  archive    gzip, zcat
+  id         uuid, ulid, ksuid, snowflake
+
+Exits with status 1 when a case fails.
+
+  -m, --module LIST   only run these modules
+      --list          list the cases instead of running them
+  -v, --verbose       show every case, not just failures
+  --json              output as JSON
+
+Examples:
+  omni selftest
+  omni selftest -m text,fs -v
+  omni selftest --json > selftest.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := selftest.Options{Executor: executeInProcess}
+
+		opts.Modules, _ = cmd.Flags().GetStringSlice("module")
+		opts.List, _ = cmd.Flags().GetBool("list")
+		opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return selftest.RunSelftest(ctx, cmd.OutOrStdout(), opts)
+	},
+}
+
+// executeInProcess runs an omni command line on the command tree of this
+// process.
+func executeInProcess(ctx context.Context, stdin io.Reader, stdout io.Writer, args []string) error {
+	run, err := pipe.PrepareCommand(rootCmd, args, pipe.Stdio{In: stdin, Out: stdout})
+	if err != nil {
+		return err
+	}
+
+	return run(ctx)
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+
+	selftestCmd.Flags().StringSliceP("module", "m", nil, "only run these modules")
+	selftestCmd.Flags().Bool("list", false, "list the cases instead of running them")
+	selftestCmd.Flags().BoolP("verbose", "v", false, "show every case, not just failures")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/selftest"
+)
+
+// TestSelftestInProcess runs the built-in conformance cases against the real
+// command tree, twice, so a flag value left over from the first run would
+// fail a case in the second.
+func TestSelftestInProcess(t *testing.T) {
+	for range 2 {
+		var buf bytes.Buffer
+
+		err := selftest.RunSelftest(context.Background(), &buf, selftest.Options{Executor: executeInProcess})
+		if err != nil {
+			t.Fatalf("RunSelftest() error = %v\n%s", err, buf.String())
+		}
+	}
+}
+
+func TestExecuteInProcessRejectsGroups(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := executeInProcess(context.Background(), nil, &buf, []string{"hex"}); err == nil {
+		t.Error("executeInProcess(hex) error = nil, want not runnable")
+	}
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"regexp"
+
 	"github.com/inovacc/omni/internal/cli/seq"
 	"github.com/spf13/cobra"
 )
@@ -20,13 +23,20 @@ var seqCmd = &cobra.Command{
   -f, --format=FORMAT     use printf style floating-point FORMAT
   -w, --equal-width       equalize width by padding with leading zeros
 
+FORMAT must contain exactly one %a, %e, %f or %g directive (flags, width
+and precision allowed); other text is printed as is and %% prints a
+percent sign. -f and -w cannot be combined. Negative operands need no
+special quoting.
+
 Examples:
   omni seq 5               # print 1 2 3 4 5
   omni seq 2 5             # print 2 3 4 5
   omni seq 1 2 10          # print 1 3 5 7 9
   omni seq -w 1 10         # print 01 02 ... 10
   omni seq -s ', ' 1 5     # print 1, 2, 3, 4, 5
-  omni seq 0.5 0.1 1.0     # print 0.5 0.6 0.7 0.8 0.9 1.0`,
+  omni seq 0.5 0.1 1.0     # print 0.5 0.6 0.7 0.8 0.9 1.0
+  omni seq 10 -2 0         # print 10 8 6 4 2 0
+  omni seq -f 'img%03g.png' 3  # print img001.png img002.png img003.png`,
 	Args: cobra.RangeArgs(1, 3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := seq.SeqOptions{
@@ -46,4 +56,53 @@ func init() {
 	seqCmd.Flags().StringVarP(&seqSeparator, "separator", "s", "", "use STRING to separate numbers")
 	seqCmd.Flags().StringVarP(&seqFormat, "format", "f", "", "use printf style FORMAT")
 	seqCmd.Flags().BoolVarP(&seqEqualWidth, "equal-width", "w", false, "equalize width with leading zeros")
+
+	preprocessSeqArgs()
+}
+
+var seqNegativeRegex = regexp.MustCompile(`^-(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// preprocessSeqArgs inserts "--" before the first negative operand of seq
+// (seq -5 5, seq 10 -1 1) so Cobra does not parse it as a flag.
+func preprocessSeqArgs() {
+	start := -1
+
+	for i := 1; i < len(os.Args); i++ {
+		if os.Args[i] == "seq" {
+			start = i + 1
+			break
+		}
+
+		// Only global flags may precede the command name
+		if len(os.Args[i]) == 0 || os.Args[i][0] != '-' {
+			return
+		}
+	}
+
+	if start < 0 {
+		return
+	}
+
+	for i := start; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		if arg == "--" {
+			return
+		}
+
+		// Leave option values such as -s -1 alone
+		prev := os.Args[i-1]
+		if prev == "-s" || prev == "-f" || prev == "--separator" || prev == "--format" {
+			continue
+		}
+
+		if seqNegativeRegex.MatchString(arg) {
+			newArgs := make([]string, 0, len(os.Args)+1)
+			newArgs = append(newArgs, os.Args[:i]...)
+			newArgs = append(newArgs, "--")
+			newArgs = append(newArgs, os.Args[i:]...)
+			os.Args = newArgs
+
+			return
+		}
+	}
 }
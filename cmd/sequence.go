@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/sequence"
+	"github.com/spf13/cobra"
+)
+
+// sequenceCmd represents the sequence command
+var sequenceCmd = &cobra.Command{
+	Use:   "sequence",
+	Short: "Namespaced sequential IDs with persistent counters",
+	Long: `Hand out human-friendly sequential reference numbers (INV-000123) from
+per-namespace counters persisted in a local database. Every call takes an
+exclusive file lock, so concurrent scripts never receive the same number.
+
+The database is $OMNI_SEQUENCE_DB, or sequences.db in the user config
+directory; --db overrides both.
+
+Subcommands:
+  next      Reserve the next value(s) of a namespace
+  current   Show the last value handed out
+  set       Set a counter so the next value is VALUE+1
+  reset     Remove a counter so it starts at 1 again
+  list      List all counters
+
+Examples:
+  omni sequence next invoice --prefix INV- --pad 6
+  omni sequence next ticket -n 10
+  omni sequence set invoice 1000
+  omni sequence list --json`,
+}
+
+// sequenceNextCmd represents the sequence next command
+var sequenceNextCmd = &cobra.Command{
+	Use:   "next [OPTION]... NAMESPACE",
+	Short: "Reserve the next value(s) of a namespace",
+	Long: `Increment the NAMESPACE counter and print the new value. A new namespace
+starts at 1. With -n, N consecutive values are reserved atomically.
+
+  -n, --count N           reserve N values (default 1)
+      --prefix STRING     text printed before the number
+      --pad N             zero pad the number to N digits
+      --db FILE           counter database
+
+Examples:
+  omni sequence next invoice --prefix INV- --pad 6   # INV-000001
+  omni sequence next build -n 3
+  omni sequence next invoice --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := sequenceOptions(cmd)
+
+		opts.Count, _ = cmd.Flags().GetInt("count")
+		opts.Prefix, _ = cmd.Flags().GetString("prefix")
+		opts.Pad, _ = cmd.Flags().GetInt("pad")
+
+		return sequence.RunNext(cmd.OutOrStdout(), args[0], opts)
+	},
+}
+
+// sequenceCurrentCmd represents the sequence current command
+var sequenceCurrentCmd = &cobra.Command{
+	Use:   "current [OPTION]... NAMESPACE",
+	Short: "Show the last value handed out",
+	Long: `Print the last value handed out for NAMESPACE without advancing it
+(0 for an unused namespace).
+
+      --prefix STRING     text printed before the number
+      --pad N             zero pad the number to N digits
+      --db FILE           counter database
+
+Examples:
+  omni sequence current invoice
+  omni sequence current invoice --prefix INV- --pad 6`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := sequenceOptions(cmd)
+
+		opts.Prefix, _ = cmd.Flags().GetString("prefix")
+		opts.Pad, _ = cmd.Flags().GetInt("pad")
+
+		return sequence.RunCurrent(cmd.OutOrStdout(), args[0], opts)
+	},
+}
+
+// sequenceSetCmd represents the sequence set command
+var sequenceSetCmd = &cobra.Command{
+	Use:   "set [OPTION]... NAMESPACE VALUE",
+	Short: "Set a counter so the next value is VALUE+1",
+	Long: `Set the NAMESPACE counter to VALUE, so the next value handed out is
+VALUE+1. Use it to continue numbering from an existing system.
+
+      --db FILE           counter database
+
+Examples:
+  omni sequence set invoice 1000`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sequence.RunSet(cmd.OutOrStdout(), args[0], args[1], sequenceOptions(cmd))
+	},
+}
+
+// sequenceResetCmd represents the sequence reset command
+var sequenceResetCmd = &cobra.Command{
+	Use:   "reset [OPTION]... NAMESPACE",
+	Short: "Remove a counter so it starts at 1 again",
+	Long: `Remove the NAMESPACE counter; the next value handed out is 1.
+
+      --db FILE           counter database
+
+Examples:
+  omni sequence reset scratch`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sequence.RunReset(cmd.OutOrStdout(), args[0], sequenceOptions(cmd))
+	},
+}
+
+// sequenceListCmd represents the sequence list command
+var sequenceListCmd = &cobra.Command{
+	Use:   "list [OPTION]...",
+	Short: "List all counters",
+	Long: `List every namespace with the last value handed out.
+
+      --db FILE           counter database
+
+Examples:
+  omni sequence list
+  omni sequence list --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sequence.RunList(cmd.OutOrStdout(), sequenceOptions(cmd))
+	},
+}
+
+func sequenceOptions(cmd *cobra.Command) sequence.Options {
+	opts := sequence.Options{OutputFormat: getOutputOpts(cmd).GetFormat()}
+
+	opts.DB, _ = cmd.Flags().GetString("db")
+
+	return opts
+}
+
+func init() {
+	rootCmd.AddCommand(sequenceCmd)
+	sequenceCmd.AddCommand(sequenceNextCmd)
+	sequenceCmd.AddCommand(sequenceCurrentCmd)
+	sequenceCmd.AddCommand(sequenceSetCmd)
+	sequenceCmd.AddCommand(sequenceResetCmd)
+	sequenceCmd.AddCommand(sequenceListCmd)
+
+	sequenceCmd.PersistentFlags().String("db", "", "counter database (default $OMNI_SEQUENCE_DB or the user config dir)")
+
+	sequenceNextCmd.Flags().IntP("count", "n", 1, "reserve N values")
+
+	for _, c := range []*cobra.Command{sequenceNextCmd, sequenceCurrentCmd} {
+		c.Flags().String("prefix", "", "text printed before the number")
+		c.Flags().Int("pad", 0, "zero pad the number to N digits")
+	}
+}
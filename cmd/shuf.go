@@ -11,6 +11,9 @@ var (
 	shufHeadCount  int
 	shufRepeat     bool
 	shufZeroTerm   bool
+	shufOutput     string
+	shufSeed       string
+	shufRandSource string
 )
 
 var shufCmd = &cobra.Command{
@@ -23,13 +26,23 @@ var shufCmd = &cobra.Command{
   -n, --head-count    output at most COUNT lines
   -r, --repeat        output lines can be repeated (with -n)
   -z, --zero-terminated  line delimiter is NUL, not newline
+  -o, --output FILE   write result to FILE instead of standard output
+      --seed STRING   seed the generator from STRING for reproducible output
+      --random-source FILE  seed the generator from the first 32 bytes of FILE
+
+By default the generator is seeded from the operating system's secure
+random source. With -n, input is sampled without holding more than COUNT
+lines in memory, and -i ranges are never expanded in full.
 
 Examples:
   omni shuf file.txt              # shuffle lines of file
   omni shuf -e a b c d            # shuffle arguments
   omni shuf -i 1-10               # shuffle numbers 1-10
   omni shuf -n 5 file.txt         # output 5 random lines
-  omni shuf -rn 10 -e yes no      # 10 random picks with repetition`,
+  omni shuf -rn 10 -e yes no      # 10 random picks with repetition
+  omni shuf -n 5 -i 1-1000000000  # 5 distinct numbers from a huge range
+  omni shuf --seed test -i 1-5    # same permutation on every run
+  omni shuf -o list.txt list.txt  # shuffle a file in place`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := shuf.ShufOptions{
 			Echo:         shufEcho,
@@ -37,6 +50,9 @@ Examples:
 			HeadCount:    shufHeadCount,
 			Repeat:       shufRepeat,
 			ZeroTerm:     shufZeroTerm,
+			Output:       shufOutput,
+			Seed:         shufSeed,
+			RandomSource: shufRandSource,
 			OutputFormat: getOutputOpts(cmd).GetFormat(),
 		}
 
@@ -52,4 +68,7 @@ func init() {
 	shufCmd.Flags().IntVarP(&shufHeadCount, "head-count", "n", 0, "output at most COUNT lines")
 	shufCmd.Flags().BoolVarP(&shufRepeat, "repeat", "r", false, "output lines can be repeated")
 	shufCmd.Flags().BoolVarP(&shufZeroTerm, "zero-terminated", "z", false, "line delimiter is NUL")
+	shufCmd.Flags().StringVarP(&shufOutput, "output", "o", "", "write result to FILE instead of standard output")
+	shufCmd.Flags().StringVar(&shufSeed, "seed", "", "seed the generator from STRING for reproducible output")
+	shufCmd.Flags().StringVar(&shufRandSource, "random-source", "", "seed the generator from the first 32 bytes of FILE")
 }
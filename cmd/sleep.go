@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/inovacc/omni/internal/cli/sleep"
 	"github.com/spf13/cobra"
 )
@@ -13,20 +15,36 @@ var sleepCmd = &cobra.Command{
   m   minutes
   h   hours
   d   days
+  w   weeks
+
+NUMBER may be a decimal. Units can be combined and spelled out, as in
+1h30m, 1d12h or "2 minutes"; several operands are added together.
 
-NUMBER may be a decimal.
+With --progress the remaining time is shown on stderr: a bar on a terminal,
+otherwise a line at every tenth of the wait.
 
 Examples:
   omni sleep 5           # sleep 5 seconds
   omni sleep 0.5         # sleep 0.5 seconds
   omni sleep 1m          # sleep 1 minute
-  omni sleep 1h 30m      # sleep 1.5 hours`,
+  omni sleep 1h 30m      # sleep 1.5 hours
+  omni sleep 1h30m --progress`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return sleep.RunSleep(args)
+		opts := sleep.Options{}
+		opts.Progress, _ = cmd.Flags().GetBool("progress")
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return sleep.RunSleep(ctx, cmd.ErrOrStderr(), args, opts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(sleepCmd)
+
+	sleepCmd.Flags().Bool("progress", false, "show the remaining time on stderr")
 }
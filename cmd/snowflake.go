@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/inovacc/omni/internal/cli/snowflake"
 	"github.com/spf13/cobra"
 )
@@ -22,29 +26,81 @@ Features:
 - Distributed generation (with worker IDs)
 - ~4 million IDs per second per worker
 
-  -n, --count=N     generate N Snowflake IDs (default 1)
-  -w, --worker=N    worker ID (0-1023, default 0)
-  --json            output as JSON
+  -n, --count=N          generate N Snowflake IDs (default 1)
+  -w, --worker=N         worker ID (0-1023, default 0)
+  --worker-lock=DIR      lease the lowest worker ID no other process holds in DIR
+  --coordinator=ADDR     lease the worker ID from a coordinator (host:port or unix:PATH)
+  --json                 output as JSON
+
+Worker IDs:
+  Two processes generating with the same worker ID in the same millisecond
+  can produce the same ID. Without -w, the worker ID is read from
+  $OMNI_SNOWFLAKE_WORKER_ID when it is set. --worker-lock leases an ID by
+  locking a file in a shared directory, for processes on one host;
+  --coordinator leases one from 'omni snowflake coordinator', for several
+  hosts. A leased ID is held until the command exits.
 
 Examples:
-  omni snowflake                 # generate one Snowflake ID
-  omni snowflake -n 5            # generate 5 IDs
-  omni snowflake -w 42           # use worker ID 42
-  omni snowflake --json          # JSON output`,
+  omni snowflake                                # generate one Snowflake ID
+  omni snowflake -n 5                           # generate 5 IDs
+  omni snowflake -w 42                          # use worker ID 42
+  omni snowflake --worker-lock /run/omni-sf     # lease a free worker ID on this host
+  omni snowflake --coordinator 10.0.0.5:7420    # lease a worker ID from a coordinator
+  omni snowflake --json                         # JSON output`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := snowflake.Options{}
 
 		opts.Count, _ = cmd.Flags().GetInt("count")
 		opts.WorkerID, _ = cmd.Flags().GetInt64("worker")
+		opts.WorkerEnv = !cmd.Flags().Changed("worker")
+		opts.WorkerLock, _ = cmd.Flags().GetString("worker-lock")
+		opts.Coordinator, _ = cmd.Flags().GetString("coordinator")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
 		return snowflake.RunSnowflake(cmd.OutOrStdout(), opts)
 	},
 }
 
+// snowflakeCoordinatorCmd represents the snowflake coordinator command
+var snowflakeCoordinatorCmd = &cobra.Command{
+	Use:   "coordinator [OPTION]...",
+	Short: "Lease Snowflake worker IDs to other processes",
+	Long: `Run a server that leases Snowflake worker IDs, so generators on
+several hosts never share one.
+
+Each 'omni snowflake --coordinator ADDR' (or idgen.CoordinatorWorkerID
+client) gets the lowest free worker ID and holds it while its connection
+stays open; the ID is freed when the client exits. Leases are kept in
+memory only, so restart the coordinator only when no client is running.
+Runs until interrupted.
+
+  --listen=ADDR     host:port or unix:PATH (default 127.0.0.1:7420)
+
+Examples:
+  omni snowflake coordinator                          # listen on 127.0.0.1:7420
+  omni snowflake coordinator --listen :7420           # listen on all interfaces
+  omni snowflake coordinator --listen unix:/run/sf.sock`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := snowflake.CoordinatorOptions{}
+		opts.Listen, _ = cmd.Flags().GetString("listen")
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return snowflake.RunCoordinator(ctx, cmd.OutOrStdout(), opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(snowflakeCmd)
+	snowflakeCmd.AddCommand(snowflakeCoordinatorCmd)
 
 	snowflakeCmd.Flags().IntP("count", "n", 1, "generate N Snowflake IDs")
 	snowflakeCmd.Flags().Int64P("worker", "w", 0, "worker ID (0-1023)")
+	snowflakeCmd.Flags().String("worker-lock", "", "lease the lowest worker ID no other process holds in DIR")
+	snowflakeCmd.Flags().String("coordinator", "", "lease the worker ID from a coordinator (host:port or unix:PATH)")
+	snowflakeCmd.MarkFlagsMutuallyExclusive("worker", "worker-lock", "coordinator")
+
+	snowflakeCoordinatorCmd.Flags().String("listen", snowflake.DefaultCoordinatorAddr, "address to listen on (host:port or unix:PATH)")
 }
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/stats"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [OPTION]... [FILE]...",
+	Short: "Descriptive statistics over a stream of numbers",
+	Long: `Read one number per line from FILE(s) or standard input and print count,
+sum, mean, standard deviation, min, median, p90, p95, p99, max and a
+histogram sparkline.
+
+The number is taken from a field of each line (the first by default), so
+log lines can be summarized without cutting them first. Blank lines are
+ignored and lines without a number are counted as skipped; --strict makes
+them an error instead.
+
+  -f, --field=N       take the number from field N (default 1)
+  -d, --delimiter=X   use X as the field delimiter instead of blanks
+  --header[=N]        skip the first N lines of each input (default 1)
+  --durations         values are durations (12ms, 1.5s, 2m); bare numbers
+                      are seconds and results print as durations
+  --bins=N            number of histogram buckets (default 10)
+  --strict            fail on a line without a number
+
+The standard deviation is the sample one and percentiles interpolate
+between the closest values, so the median of 1 2 3 4 is 2.5. In the
+sparkline each block is one bucket between min and max; a blank block is an
+empty bucket.
+
+Examples:
+  seq 1 100 | omni stats
+  omni rg -o 'took [0-9]+ms' app.log | omni stats -f 2 --durations
+  omni stats -f 3 -d, --header latency.csv
+  omni stats --json < samples.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := stats.Options{}
+		opts.Field, _ = cmd.Flags().GetInt("field")
+		opts.Delimiter, _ = cmd.Flags().GetString("delimiter")
+		opts.Header, _ = cmd.Flags().GetInt("header")
+		opts.Durations, _ = cmd.Flags().GetBool("durations")
+		opts.Bins, _ = cmd.Flags().GetInt("bins")
+		opts.Strict, _ = cmd.Flags().GetBool("strict")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return stats.Run(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().IntP("field", "f", 1, "take the number from field N")
+	statsCmd.Flags().StringP("delimiter", "d", "", "use X as the field delimiter instead of blanks")
+	statsCmd.Flags().Int("header", 0, "skip the first N lines of each input")
+	statsCmd.Flags().Lookup("header").NoOptDefVal = "1"
+	statsCmd.Flags().Bool("durations", false, "values are durations such as 12ms or 1.5s")
+	statsCmd.Flags().Int("bins", 10, "number of histogram buckets")
+	statsCmd.Flags().Bool("strict", false, "fail on a line without a number")
+}
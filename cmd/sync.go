@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/dirsync"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync [OPTION]... SRC DST",
+	Short: "Synchronize a directory tree into another (local rsync)",
+	Long: `One-way incremental synchronization of local directories.
+
+Only entries that differ are copied. Files are compared by size and
+modification time, or by SHA-256 content with --checksum. Copied files
+keep their source mode and mtime and are written via a temporary file
+renamed into place, so an interrupted sync never leaves partial files.
+
+As with rsync, a trailing slash on SRC copies its contents into DST;
+without it, DST/$(basename SRC) is created. DST is created if missing.
+Only local paths are supported.
+
+  -c, --checksum        compare file content (SHA-256) instead of size+mtime
+      --delete          delete DST entries that do not exist in SRC
+  -e, --exclude PAT     skip entries matching PAT (repeatable); PAT matches
+                        the base name or the path relative to SRC, and a
+                        trailing '/' matches directories only
+  -n, --dry-run         show what would change without changing anything
+  -i, --interactive     show the change set and ask before applying it
+  -y, --yes             answer yes to the prompt
+  -v, --verbose         list changes as they are applied
+  -t, --threads N       parallel copy workers (0 = auto)
+  --json                print the change set and summary as JSON
+
+Changes are listed as '+ path' (added), '~ path' (modified) and
+'- path' (deleted).
+
+Examples:
+  omni sync src/ /mnt/backup/src            # mirror contents
+  omni sync -n --delete src/ backup/        # preview, including deletions
+  omni sync -e node_modules/ -e '*.log' app/ /media/usb/app
+  omni sync --checksum --json docs/ out/
+  omni sync -i --delete src/ /mnt/backup/src  # review, then confirm`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := dirsync.Options{}
+
+		opts.Checksum, _ = cmd.Flags().GetBool("checksum")
+		opts.Delete, _ = cmd.Flags().GetBool("delete")
+		opts.Exclude, _ = cmd.Flags().GetStringArray("exclude")
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+		opts.Threads, _ = cmd.Flags().GetInt("threads")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+		opts.Confirm = getConfirmOpts(cmd)
+
+		return dirsync.RunSync(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolP("checksum", "c", false, "compare file content (SHA-256) instead of size+mtime")
+	syncCmd.Flags().Bool("delete", false, "delete DST entries that do not exist in SRC")
+	syncCmd.Flags().StringArrayP("exclude", "e", nil, "skip entries matching glob pattern (repeatable)")
+	syncCmd.Flags().BoolP("dry-run", "n", false, "show what would change without changing anything")
+	syncCmd.Flags().BoolP("verbose", "v", false, "list changes as they are applied")
+	syncCmd.Flags().IntP("threads", "t", 0, "parallel copy workers (0 = auto)")
+	addConfirmFlags(syncCmd, "i")
+}
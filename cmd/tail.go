@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/tail"
 	"github.com/spf13/cobra"
 )
@@ -20,23 +21,47 @@ var tailCmd = &cobra.Command{
 With more than one FILE, precede each with a header giving the file name.
 With no FILE, or when FILE is -, read standard input.
 
+  -n, --lines [+]NUM      output the last NUM lines instead of the last 10;
+                          with a leading '+', start with line NUM
+  -c, --bytes [+]NUM      output the last NUM bytes; with a leading '+',
+                          start with byte NUM
+  -f, --follow            output appended data as the file grows
+  -q, --quiet             never output headers giving file names
+  -v, --verbose           always output headers giving file names
+      --sleep-interval D  with -f, wait D between checks (default 1s)
+
+NUM may have a size suffix: b 512, K or KiB 1024, KB 1000, M or MiB
+1024*1024, MB 1000*1000, and so on for G, T, P and E.
 Numeric shortcuts are supported: -80 is equivalent to -n 80.
 
 Examples:
   omni tail file.txt              # last 10 lines
   omni tail -n 20 file.txt        # last 20 lines
   omni tail -f file.txt           # follow appended data
+  omni tail -n +2 data.csv        # skip the header line
+  omni tail -c +513 file.bin      # skip the first 512 bytes
   omni tail -5 file.txt           # numeric shortcut for -n 5
   cat file.txt | omni tail        # read from stdin`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := tail.TailOptions{}
 
-		opts.Lines, _ = cmd.Flags().GetInt("lines")
-		opts.Bytes, _ = cmd.Flags().GetInt("bytes")
+		lines, _ := cmd.Flags().GetString("lines")
+		bytes, _ := cmd.Flags().GetString("bytes")
+
+		var err error
+
+		if cmd.Flags().Changed("bytes") {
+			if opts.Bytes, opts.FromStart, err = tail.ParseCount(bytes); err != nil {
+				return err
+			}
+		} else if opts.Lines, opts.FromStart, err = tail.ParseCount(lines); err != nil {
+			return err
+		}
+
 		opts.Follow, _ = cmd.Flags().GetBool("follow")
 		opts.Quiet, _ = cmd.Flags().GetBool("quiet")
 		opts.Verbose, _ = cmd.Flags().GetBool("verbose")
-		opts.Sleep, _ = cmd.Flags().GetDuration("sleep-interval")
+		opts.Sleep, _ = flagvalue.GetDuration(cmd.Flags(), "sleep-interval")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
 		return tail.RunTail(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
@@ -46,12 +71,12 @@ Examples:
 func init() {
 	rootCmd.AddCommand(tailCmd)
 
-	tailCmd.Flags().IntP("lines", "n", 10, "output the last NUM lines, instead of the last 10")
-	tailCmd.Flags().IntP("bytes", "c", 0, "output the last NUM bytes")
+	tailCmd.Flags().StringP("lines", "n", "10", "output the last NUM lines, instead of the last 10; or use +NUM to output starting with line NUM")
+	tailCmd.Flags().StringP("bytes", "c", "", "output the last NUM bytes; or use +NUM to output starting with byte NUM")
 	tailCmd.Flags().BoolP("follow", "f", false, "output appended data as the file grows")
 	tailCmd.Flags().BoolP("quiet", "q", false, "never output headers giving file names")
 	tailCmd.Flags().BoolP("verbose", "v", false, "always output headers giving file names")
-	tailCmd.Flags().Duration("sleep-interval", time.Second, "with -f, sleep for approximately N seconds between iterations")
+	flagvalue.DurationP(tailCmd.Flags(), "sleep-interval", "", time.Second, "with -f, sleep for approximately N seconds between iterations")
 	// Preprocess os.Args to convert -NUM to -n NUM for tail command
 	preprocessTailArgs()
 }
@@ -79,7 +104,13 @@ func preprocessTailArgs() {
 	// Rewrite -NUM to -n NUM
 	newArgs := make([]string, 0, len(os.Args)+1)
 
-	for _, arg := range os.Args {
+	for i, arg := range os.Args {
+		// Leave option values such as -n -5 alone
+		if i > 0 && isCountFlag(os.Args[i-1]) {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
 		if matches := tailNumericFlagRegex.FindStringSubmatch(arg); matches != nil {
 			newArgs = append(newArgs, "-n", matches[1])
 		} else {
@@ -19,6 +19,13 @@ var tarCmd = &cobra.Command{
   -z, --gzip             filter through gzip
   -C, --directory=DIR    change to directory DIR
       --strip-components=N  strip N leading path components
+      --reproducible     byte-identical archives for identical inputs
+
+With --reproducible every entry gets the same time (SOURCE_DATE_EPOCH, or
+1980-01-01 when unset), uid/gid 0 without owner names, and permissions 0755
+for directories and executables and 0644 otherwise. Directories are always
+stored in sorted order, so two runs over the same files produce the same
+bytes, which signed release artifacts and attestations rely on.
 
 Examples:
   omni tar -cvf archive.tar dir/        # create tar archive
@@ -26,7 +33,8 @@ Examples:
   omni tar -xvf archive.tar             # extract tar archive
   omni tar -xzvf archive.tar.gz         # extract gzipped tar
   omni tar -tvf archive.tar             # list contents
-  omni tar -xvf archive.tar -C /dest    # extract to directory`,
+  omni tar -xvf archive.tar -C /dest    # extract to directory
+  SOURCE_DATE_EPOCH=$(git log -1 --format=%ct) omni tar --reproducible -czf dist.tar.gz dist/`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := archive.ArchiveOptions{}
 
@@ -39,6 +47,7 @@ Examples:
 		opts.Directory, _ = cmd.Flags().GetString("directory")
 		opts.StripComponents, _ = cmd.Flags().GetInt("strip-components")
 		opts.JSON, _ = cmd.Flags().GetBool("json")
+		opts.Reproducible, _ = cmd.Flags().GetBool("reproducible")
 
 		return archive.RunTar(cmd.OutOrStdout(), args, opts)
 	},
@@ -56,4 +65,5 @@ func init() {
 	tarCmd.Flags().StringP("directory", "C", "", "change to directory DIR")
 	tarCmd.Flags().Int("strip-components", 0, "strip N leading path components")
 	tarCmd.Flags().Bool("json", false, "output as JSON (for list mode)")
+	tarCmd.Flags().Bool("reproducible", false, "normalize times, owners and permissions for byte-identical archives")
 }
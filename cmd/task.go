@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 
 	"github.com/inovacc/omni/internal/cli/task"
@@ -22,7 +24,7 @@ Examples:
   # List available tasks
   omni task --list
 
-  # Run the default task
+  # Run the default task (or pick one interactively when there is none)
   omni task
 
   # Run a specific task
@@ -43,9 +45,31 @@ Examples:
   # Show task summary
   omni task --summary build
 
+  # Prefix each output line with the task name
+  omni task --output prefixed build test
+
+  # Buffer each task's output and print it in one block
+  omni task --output group build
+
+  # Use only cached remote includes, without network access
+  omni task --offline build
+
 Taskfile Format:
   version: '3'
 
+  output: prefixed   # interleaved (default), group or prefixed
+  dotenv: ['.env']   # loaded relative to the Taskfile; env: takes precedence
+
+  includes:
+    lib: ./lib                # local Taskfile or directory
+    ci:                       # remote Taskfile, pinned by sha256
+      taskfile: https://example.com/ci/Taskfile.yml
+      checksum: sha256:<hex digest>
+    go:                       # Taskfile in a git repository, pinned by commit
+      git: https://github.com/acme/tasks.git
+      commit: <full commit hash>
+      path: go/Taskfile.yml
+
   vars:
     BUILD_DIR: ./build
 
@@ -72,11 +96,14 @@ Taskfile Format:
 Supported Features:
   - Task dependencies (deps)
   - Variable expansion ({{.VAR}})
-  - Task includes (includes)
+  - Task includes (includes), local or remote over HTTPS and git; remote
+    includes must be pinned and are cached under the user cache directory
   - Status checks for up-to-date detection
   - Deferred commands
   - Task aliases
   - External commands (with --allow-external)
+  - dotenv files at Taskfile and task level (env: overrides .env values)
+  - Output styles (output: interleaved|group|prefixed, group begin/end/error_only)
 
 Limitations:
   - Dynamic variables (sh:) are not supported`,
@@ -92,6 +119,8 @@ Limitations:
 		opts.Silent, _ = cmd.Flags().GetBool("silent")
 		opts.Summary, _ = cmd.Flags().GetBool("summary")
 		opts.AllowExternal, _ = cmd.Flags().GetBool("allow-external")
+		opts.Output, _ = cmd.Flags().GetString("output")
+		opts.Offline, _ = cmd.Flags().GetBool("offline")
 
 		// Create context that cancels on SIGINT/SIGTERM
 		ctx, cancel := context.WithCancel(context.Background())
@@ -106,6 +135,25 @@ Limitations:
 
 		return task.Run(ctx, cmd.OutOrStdout(), args, opts)
 	},
+	ValidArgsFunction: completeTaskNames,
+}
+
+// completeTaskNames completes task names from the Taskfile selected by
+// --taskfile and --dir, skipping tasks already on the command line.
+func completeTaskNames(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	taskfile, _ := cmd.Flags().GetString("taskfile")
+	dir, _ := cmd.Flags().GetString("dir")
+
+	var names []string
+
+	for _, c := range task.CompleteTasks(taskfile, dir) {
+		name, _, _ := strings.Cut(c, "\t")
+		if !slices.Contains(args, name) {
+			names = append(names, c)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
 
 func init() {
@@ -120,6 +168,8 @@ func init() {
 	taskCmd.Flags().BoolP("silent", "s", false, "suppress output")
 	taskCmd.Flags().Bool("summary", false, "show task summary")
 	taskCmd.Flags().Bool("allow-external", false, "allow external (non-omni) commands")
+	taskCmd.Flags().Bool("offline", false, "resolve remote includes from the cache only")
+	taskCmd.Flags().StringP("output", "o", "", "output style: interleaved, group or prefixed (overrides Taskfile)")
 
 	// Register the command runner factory
 	task.CommandRunnerFactory = func(dir string, allowExternal bool) task.CommandRunner {
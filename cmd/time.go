@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pipe"
 	"github.com/inovacc/omni/internal/cli/timecmd"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 	"github.com/spf13/cobra"
@@ -11,18 +16,31 @@ import (
 
 // timeCmd represents the time command
 var timeCmd = &cobra.Command{
-	Use:   "time",
-	Short: "Time a simple command or give resource usage",
-	Long: `The time utility executes and times the specified utility. After the
-utility finishes, time writes to the standard error stream, the total
-time elapsed.
+	Use:   "time [OPTION]... [--] COMMAND [ARG]...",
+	Short: "Time an omni command and report its resource usage",
+	Long: `Run an omni subcommand in-process and report its resource usage on
+standard error once it finishes:
 
-Note: Since omni doesn't execute external commands, this command
-provides timing utilities and can measure internal operations.
+  real     wall-clock time
+  user     CPU time spent in user mode
+  sys      CPU time spent in the kernel
+  maxrss   peak resident memory of the omni process
+  stage N  per-stage timings, for commands that report them (pipeline)
+
+The command's output and exit status are passed through unchanged. Flags
+after COMMAND belong to it; use -- when COMMAND itself starts with a dash.
+With --json the report is a JSON object, convenient for benchmarks in CI.
+
+  -o, --output FILE   write the report to FILE instead of standard error
+
+With no COMMAND, prints the current time.
 
 Examples:
-  omni time sleep 2    # Time a sleep operation
-  omni time            # Just show current time info`,
+  omni time sleep 2                        # time a sleep
+  omni time wc -l big.log                  # time wc
+  omni time pipeline -f app.log 'grep ERROR' 'sort' 'uniq'
+  omni time --json -o bench.json -- hash -a sha256 big.iso
+  omni time                                # show current time info`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		outFmt := getOutputOpts(cmd).GetFormat()
 		w := cmd.OutOrStdout()
@@ -45,28 +63,50 @@ Examples:
 			return nil
 		}
 
-		// If first arg is "sleep", do a timed sleep
-		if args[0] == "sleep" && len(args) > 1 {
-			duration, err := time.ParseDuration(args[1] + "s")
+		run, err := prepareSubcommand("time", args, pipe.Stdio{In: cmd.InOrStdin(), Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()})
+		if err != nil {
+			return err
+		}
+
+		var reportW io.Writer = cmd.ErrOrStderr()
+
+		if path, _ := cmd.Flags().GetString("output"); path != "" {
+			f, err := os.Create(path)
 			if err != nil {
-				duration, err = time.ParseDuration(args[1])
-				if err != nil {
-					return fmt.Errorf("invalid duration: %s", args[1])
-				}
+				return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("time: %s", err))
 			}
 
-			_, err = timecmd.RunTimeJSON(cmd.ErrOrStderr(), outFmt, func() error {
-				time.Sleep(duration)
-				return nil
-			})
+			defer func() { _ = f.Close() }()
 
+			reportW = f
+		}
+
+		report, runErr := timecmd.Measure(cmd.Context(), args, run)
+
+		if err := timecmd.WriteReport(reportW, outFmt, report); err != nil {
 			return err
 		}
 
-		return fmt.Errorf("time: cannot execute external commands, use 'time sleep N' for timing")
+		return runErr
 	},
 }
 
+// prepareSubcommand resolves an omni command for time or bench (name) and
+// parses its flags, returning a function that runs it in-process with
+// stdio. Usage errors are reported before anything is timed.
+func prepareSubcommand(name string, args []string, stdio pipe.Stdio) (func(ctx context.Context) error, error) {
+	run, err := pipe.PrepareCommand(rootCmd, args, stdio)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return run, nil
+}
+
 func init() {
 	rootCmd.AddCommand(timeCmd)
+
+	timeCmd.Flags().StringP("output", "o", "", "write the report to FILE instead of standard error")
+	// Everything after COMMAND belongs to it, not to time
+	timeCmd.Flags().SetInterspersed(false)
 }
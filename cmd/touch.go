@@ -7,18 +7,53 @@ import (
 
 // touchCmd represents the touch command
 var touchCmd = &cobra.Command{
-	Use:   "touch [file...]",
+	Use:   "touch [OPTION]... FILE...",
 	Short: "Update the access and modification times of each FILE to the current time",
-	Long: `Update the access and modification times of each FILE to the current time. A FILE argument that does not exist is created empty.
+	Long: `Update the access and modification times of each FILE to the current time. A FILE argument that does not exist is created empty, unless -c is supplied.
+
+  -a                     change only the access time
+  -c, --no-create        do not create any files
+  -d, --date STRING      parse STRING and use it instead of current time
+  -m                     change only the modification time
+  -r, --reference FILE   use this file's times instead of current time
+  -t STAMP               use [[CC]YY]MMDDhhmm[.ss] instead of current time
+
+DATE may be an absolute date (2024-03-15, "2024-03-15 14:30",
+2024-03-15T14:30:00Z, RFC 1123), @SECONDS since the epoch, now, today,
+yesterday, tomorrow, "N unit[s] [ago]" (second, minute, hour, day, week,
+month, year) or a duration such as -90m. Dates without a zone are local
+time. With -r, relative dates are applied to the reference file's time.
 
 Examples:
-  omni touch newfile.txt          # create an empty file or update its time
-  omni touch a.txt b.txt c.txt    # touch multiple files`,
+  omni touch newfile.txt                  # create an empty file or update its time
+  omni touch a.txt b.txt c.txt            # touch multiple files
+  omni touch -d "2024-01-01 00:00" f.txt  # set an explicit time
+  omni touch -d "2 days ago" old.log      # backdate a file
+  omni touch -r src.txt dst.txt           # copy times from another file
+  omni touch -m -t 202401011200 f.txt     # set only the modification time
+  omni touch -c maybe.txt                 # update only if it exists`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return stat.RunTouch(args, stat.TouchOptions{})
+		opts := stat.TouchOptions{}
+
+		opts.AccessOnly, _ = cmd.Flags().GetBool("access")
+		opts.ModifyOnly, _ = cmd.Flags().GetBool("modify")
+		opts.NoCreate, _ = cmd.Flags().GetBool("no-create")
+		opts.Date, _ = cmd.Flags().GetString("date")
+		opts.Reference, _ = cmd.Flags().GetString("reference")
+		opts.Stamp, _ = cmd.Flags().GetString("stamp")
+
+		return stat.RunTouch(args, opts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(touchCmd)
+
+	touchCmd.Flags().BoolP("access", "a", false, "change only the access time")
+	touchCmd.Flags().BoolP("modify", "m", false, "change only the modification time")
+	touchCmd.Flags().BoolP("no-create", "c", false, "do not create any files")
+	touchCmd.Flags().StringP("date", "d", "", "parse STRING and use it instead of current time")
+	touchCmd.Flags().StringP("reference", "r", "", "use this file's times instead of current time")
+	touchCmd.Flags().StringP("stamp", "t", "", "use [[CC]YY]MMDDhhmm[.ss] instead of current time")
 }
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/tree"
+	"github.com/inovacc/omni/internal/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -23,9 +28,11 @@ Examples:
   omni tree -s                       # show statistics
   omni tree --json                   # output as JSON
   omni tree --json-stream            # streaming NDJSON output
+  omni tree -0 src/ | xargs -0 ls -ld  # NUL-separated paths
   omni tree -t 8                     # use 8 parallel workers
   omni tree --max-files 10000        # cap at 10000 items
-  omni tree --compare a.json b.json  # compare two snapshots`,
+  omni tree --compare a.json b.json  # compare two snapshots
+  omni tree snapshot --every 1h /etc # scheduled snapshots with drift`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := tree.TreeOptions{}
 
@@ -50,6 +57,7 @@ Examples:
 		opts.MaxHashSize, _ = cmd.Flags().GetInt64("max-hash-size")
 		opts.Threads, _ = cmd.Flags().GetInt("threads")
 		opts.DetectMoves, _ = cmd.Flags().GetBool("detect-moves")
+		opts.Null, _ = cmd.Flags().GetBool("null")
 
 		compareFiles, _ := cmd.Flags().GetStringSlice("compare")
 		if len(compareFiles) == 2 {
@@ -65,8 +73,78 @@ Examples:
 	},
 }
 
+var treeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [DIR...]",
+	Short: "Take, prune and diff scheduled directory snapshots",
+	Long: `Take JSON tree snapshots (with file hashes) of each directory, prune old
+snapshots by retention rules, and report drift between the latest snapshot
+and a pinned baseline. With --every the command runs as a daemon, taking
+a snapshot each interval until interrupted.
+
+Snapshots live in the store directory, one subdirectory per snapshot root.
+They use the same format as "omni tree --json", so any two of them can be
+compared with "omni tree --compare". Each snapshot, prune and drift result
+is also written to the command log when logging is enabled.
+
+Retention rules keep the newest snapshot of each period; a snapshot kept by
+any rule is kept, and the newest snapshot and the baseline are never
+pruned. Without rules nothing is pruned.
+
+  --store=DIR         snapshot directory (default <cache>/omni/twig-snapshots)
+  --every=DURATION    run as a daemon, snapshotting each interval (e.g. 30m, 6h)
+  --keep-last=N       keep the N most recent snapshots
+  --keep-daily=N      keep one snapshot per day for the last N days
+  --keep-weekly=N     keep one snapshot per ISO week for the last N weeks
+  --set-baseline      pin the new snapshot as the drift baseline
+  --fail-on-drift     exit 1 if the snapshot drifted from the baseline (one-shot)
+  -i, --ignore=PATS   patterns to ignore (comma-separated)
+  --detect-moves      detect moved files in drift (default true)
+  --json              one JSON report per directory and cycle, one per line
+
+Examples:
+  omni tree snapshot --set-baseline /etc/app
+  omni tree snapshot --fail-on-drift /etc/app
+  omni tree snapshot --every 1h --keep-last 24 --keep-daily 7 --keep-weekly 4 /etc/app /srv/www
+  omni tree snapshot --store /var/lib/omni/snapshots --json -i "*.log,tmp" .`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := tree.SnapshotOptions{}
+
+		opts.Store, _ = cmd.Flags().GetString("store")
+		opts.Every, _ = flagvalue.GetDuration(cmd.Flags(), "every")
+		opts.KeepLast, _ = cmd.Flags().GetInt("keep-last")
+		opts.KeepDaily, _ = cmd.Flags().GetInt("keep-daily")
+		opts.KeepWeekly, _ = cmd.Flags().GetInt("keep-weekly")
+		opts.SetBaseline, _ = cmd.Flags().GetBool("set-baseline")
+		opts.FailOnDrift, _ = cmd.Flags().GetBool("fail-on-drift")
+		opts.DetectMoves, _ = cmd.Flags().GetBool("detect-moves")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+		opts.Logger = logger.Get()
+
+		ignoreStr, _ := cmd.Flags().GetString("ignore")
+		if ignoreStr != "" {
+			opts.Ignore = strings.Split(ignoreStr, ",")
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return tree.RunSnapshot(ctx, cmd.OutOrStdout(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(treeCmd)
+	treeCmd.AddCommand(treeSnapshotCmd)
+
+	treeSnapshotCmd.Flags().String("store", "", "snapshot directory (default <cache>/omni/twig-snapshots)")
+	flagvalue.DurationP(treeSnapshotCmd.Flags(), "every", "", 0, "run as a daemon, snapshotting each interval")
+	treeSnapshotCmd.Flags().Int("keep-last", 0, "keep the N most recent snapshots")
+	treeSnapshotCmd.Flags().Int("keep-daily", 0, "keep one snapshot per day for the last N days")
+	treeSnapshotCmd.Flags().Int("keep-weekly", 0, "keep one snapshot per week for the last N weeks")
+	treeSnapshotCmd.Flags().Bool("set-baseline", false, "pin the new snapshot as the drift baseline")
+	treeSnapshotCmd.Flags().Bool("fail-on-drift", false, "exit 1 if the snapshot drifted from the baseline")
+	treeSnapshotCmd.Flags().StringP("ignore", "i", "", "patterns to ignore (comma-separated)")
+	treeSnapshotCmd.Flags().Bool("detect-moves", true, "detect moved files in drift")
 
 	treeCmd.Flags().BoolP("all", "a", false, "show hidden files")
 	treeCmd.Flags().Bool("dirs-only", false, "show only directories")
@@ -86,4 +164,5 @@ func init() {
 	treeCmd.Flags().IntP("threads", "t", 0, "number of parallel workers (0 = auto, 1 = sequential)")
 	treeCmd.Flags().StringSlice("compare", nil, "compare two JSON tree snapshots")
 	treeCmd.Flags().Bool("detect-moves", true, "detect moved files when comparing (default true)")
+	treeCmd.Flags().BoolP("null", "0", false, "print the scanned paths separated by NUL instead of a tree")
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/inovacc/omni/internal/cli/ulid"
+	"github.com/inovacc/omni/pkg/idgen"
 	"github.com/spf13/cobra"
 )
 
@@ -21,27 +22,113 @@ Structure: 48-bit timestamp (ms) + 80-bit randomness
 
   -n, --count=N   generate N ULIDs (default 1)
   -l, --lower     output in lowercase
+  --monotonic     strictly increasing order, even within one millisecond
   --json          output as JSON
 
+With --monotonic, ULIDs created in the same millisecond reuse the random
+part of the previous one incremented by one, so a batch sorts in the order
+it was generated (useful for bulk inserts keyed by ULID).
+
 Examples:
   omni ulid                   # generate one ULID
   omni ulid -n 5              # generate 5 ULIDs
+  omni ulid -n 1000 --monotonic  # 1000 strictly ordered ULIDs
   omni ulid -l                # lowercase output
-  omni ulid --json            # JSON output`,
+  omni ulid --json            # JSON output
+
+Subcommands:
+  bounds    Print the smallest and largest ID for a time range
+  time      Print the timestamp embedded in ULIDs`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := ulid.Options{}
 
 		opts.Count, _ = cmd.Flags().GetInt("count")
 		opts.Lower, _ = cmd.Flags().GetBool("lower")
+		opts.Monotonic, _ = cmd.Flags().GetBool("monotonic")
 		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
 
 		return ulid.RunULID(cmd.OutOrStdout(), opts)
 	},
 }
 
+// ulidBoundsCmd represents the ulid bounds command
+var ulidBoundsCmd = &cobra.Command{
+	Use:   "bounds --from TIME [--to TIME] [OPTION]...",
+	Short: "Print the smallest and largest ID for a time range",
+	Long: `Print the minimum and maximum ID whose embedded timestamp lies within
+the inclusive range [--from, --to]. Every ID generated in that range sorts
+between the two, so they can be used for range scans over ID-keyed tables:
+
+  SELECT * FROM events WHERE id BETWEEN :min AND :max
+
+TIME is RFC 3339, YYYY-MM-DD, Unix seconds or "now". A date-only --to covers
+the whole day. KSUIDs have one-second resolution, so the range is widened to
+whole seconds.
+
+      --from TIME   range start (required)
+      --to TIME     range end (default now)
+  -k, --kind KIND   id kind: ulid, uuidv7, ksuid (default ulid)
+  -l, --lower       output in lowercase (ulid, uuidv7)
+  --json            output as JSON
+
+Examples:
+  omni ulid bounds --from 2024-03-01 --to 2024-03-31
+  omni ulid bounds --from 2024-03-01T12:00:00Z --to now --json
+  omni ulid bounds --kind uuidv7 --from 1709294400 --to 1709298000`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ulid.BoundsOptions{}
+
+		opts.From, _ = cmd.Flags().GetString("from")
+		opts.To, _ = cmd.Flags().GetString("to")
+		opts.Kind, _ = cmd.Flags().GetString("kind")
+		opts.Lower, _ = cmd.Flags().GetBool("lower")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return ulid.RunBounds(cmd.OutOrStdout(), opts)
+	},
+}
+
+// ulidTimeCmd represents the ulid time command
+var ulidTimeCmd = &cobra.Command{
+	Use:   "time [OPTION]... [ULID]...",
+	Short: "Print the timestamp embedded in ULIDs",
+	Long: `Print the creation time embedded in each ULID, in UTC, local time and
+Unix seconds. IDs are read one per line from standard input when none are
+given. Decoding is case insensitive.
+
+With several IDs, every output line starts with the ID it belongs to.
+
+  -f, --format=FMT  all (default), utc, local, unix or unix-ms
+  --json            output as JSON
+
+Examples:
+  omni ulid time 01HQ3Z5N2M8V4W7X9Y0Z1A2B3C
+  omni ulid time -f unix-ms 01HQ3Z5N2M8V4W7X9Y0Z1A2B3C
+  cat ids.txt | omni ulid time -f utc`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ulid.TimeOptions{Kind: idgen.KindULID}
+
+		opts.Format, _ = cmd.Flags().GetString("format")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return ulid.RunTime(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(ulidCmd)
+	ulidCmd.AddCommand(ulidBoundsCmd)
+	ulidCmd.AddCommand(ulidTimeCmd)
+
+	ulidTimeCmd.Flags().StringP("format", "f", "all", "output format: all, utc, local, unix, unix-ms")
+
+	ulidBoundsCmd.Flags().String("from", "", "range start (RFC 3339, YYYY-MM-DD, Unix seconds or now)")
+	ulidBoundsCmd.Flags().String("to", "", "range end (default now)")
+	ulidBoundsCmd.Flags().StringP("kind", "k", "ulid", "id kind: ulid, uuidv7, ksuid")
+	ulidBoundsCmd.Flags().BoolP("lower", "l", false, "output in lowercase")
 
 	ulidCmd.Flags().IntP("count", "n", 1, "generate N ULIDs")
 	ulidCmd.Flags().BoolP("lower", "l", false, "output in lowercase")
+	ulidCmd.Flags().Bool("monotonic", false, "strictly increasing order, even within one millisecond")
 }
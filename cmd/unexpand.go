@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/expand"
+	"github.com/spf13/cobra"
+)
+
+// unexpandCmd represents the unexpand command
+var unexpandCmd = &cobra.Command{
+	Use:   "unexpand [OPTION]... [FILE]...",
+	Short: "Convert spaces to tabs",
+	Long: `Convert blanks in each FILE to tabs, writing to standard output.
+With no FILE, or when FILE is -, read standard input.
+
+  -a, --all           convert all blanks, instead of just initial blanks
+      --first-only    convert only leading sequences of blanks (overrides -a)
+  -t, --tabs=N        have tabs N characters apart instead of 8 (enables -a)
+  -t, --tabs=LIST     use comma separated list of tab positions; the last
+                      entry may be prefixed with '/' or '+' as in expand
+
+A single space before a tab stop is never converted. The obsolete form -N
+is accepted as --tabs=N.
+
+Examples:
+  omni unexpand file.txt                # leading blanks, tabs every 8
+  omni unexpand -a file.txt             # all blanks
+  omni unexpand --first-only -t 4 a.py  # leading blanks, tabs every 4`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := expand.Options{}
+		opts.Tabs, _ = cmd.Flags().GetString("tabs")
+		opts.All, _ = cmd.Flags().GetBool("all")
+		opts.FirstOnly, _ = cmd.Flags().GetBool("first-only")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return expand.RunUnexpand(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unexpandCmd)
+
+	unexpandCmd.Flags().BoolP("all", "a", false, "convert all blanks, instead of just initial blanks")
+	unexpandCmd.Flags().Bool("first-only", false, "convert only leading sequences of blanks (overrides -a)")
+	unexpandCmd.Flags().StringP("tabs", "t", "", "tab size N, or comma separated list of tab positions (enables -a)")
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/inovacc/omni/internal/cli/sleep"
+	"github.com/spf13/cobra"
+)
+
+var untilCmd = &cobra.Command{
+	Use:   "until TIME",
+	Short: "Sleep until a wall-clock time",
+	Long: `Pause until TIME. A clock time without a date means its next occurrence,
+so "omni until 08:00" run at 23:00 waits for the morning. A time that has
+already passed returns at once.
+
+TIME may be:
+  14:30, 14:30:15, 2:30pm, 9am, noon, midnight
+  today 18:00, tomorrow 9am, tomorrow
+  2026-10-20 09:00, 2026-10-20T09:00:00Z
+  +20m, in 1h30m          relative to now
+
+A trailing UTC, numeric offset (+02:00) or IANA zone name sets the time
+zone of TIME; otherwise --tz does, and by default the local zone.
+
+Examples:
+  omni until 14:30
+  omni until 9am America/New_York
+  omni until tomorrow 06:00 --progress
+  omni until 02:00 --tz UTC && task backup`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := sleep.Options{}
+		opts.Progress, _ = cmd.Flags().GetBool("progress")
+		opts.TZ, _ = cmd.Flags().GetString("tz")
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return sleep.RunUntil(ctx, cmd.ErrOrStderr(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(untilCmd)
+
+	untilCmd.Flags().Bool("progress", false, "show the remaining time on stderr")
+	untilCmd.Flags().String("tz", "", "time zone of TIME, e.g. UTC or Europe/Berlin (default: local)")
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/inovacc/omni/internal/cli/base"
+	"github.com/spf13/cobra"
+)
+
+// uuencodeCmd represents the uuencode command
+var uuencodeCmd = &cobra.Command{
+	Use:   "uuencode [OPTION]... [FILE] NAME",
+	Short: "Encode a file in uuencode format",
+	Long: `Encode FILE, or standard input, to standard output in uuencode format,
+recording NAME as the file name to create when decoded. The file's
+permission bits are recorded too (0644 for standard input).
+
+Encoding streams, so large files can be piped straight to a mailer.
+
+  -m, --base64    use base64 ("begin-base64") instead of traditional uuencoding
+
+Examples:
+  omni uuencode report.pdf report.pdf > report.uu
+  tar cf - src | omni uuencode -m src.tar | mail -s src user@example.com`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := base.UUOptions{}
+		opts.Base64, _ = cmd.Flags().GetBool("base64")
+
+		return base.RunUUEncode(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+// uudecodeCmd represents the uudecode command
+var uudecodeCmd = &cobra.Command{
+	Use:   "uudecode [OPTION]... [FILE]...",
+	Short: "Decode a file created by uuencode",
+	Long: `Decode uuencoded FILEs, or standard input. Text before the begin line,
+such as mail headers, is skipped; both the traditional and the base64
+("begin-base64") forms are read.
+
+The content is written to the file name recorded in the begin line, in
+the current directory (directory parts are dropped), with the recorded
+permissions. A recorded name of /dev/stdout, or -o -, writes to standard
+output. A truncated input leaves no file behind.
+
+  -o, --output-file FILE   write to FILE instead of the recorded name
+
+Examples:
+  omni uudecode report.uu
+  omni uudecode -o - msg.uu | omni tar tf -`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := base.UUOptions{}
+		opts.Output, _ = cmd.Flags().GetString("output-file")
+
+		return base.RunUUDecode(cmd.OutOrStdout(), args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uuencodeCmd)
+	rootCmd.AddCommand(uudecodeCmd)
+
+	uuencodeCmd.Flags().BoolP("base64", "m", false, "use base64 instead of traditional uuencoding")
+	uudecodeCmd.Flags().StringP("output-file", "o", "", "write to FILE instead of the recorded name (\"-\" = stdout)")
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"github.com/inovacc/omni/internal/cli/ulid"
 	"github.com/inovacc/omni/internal/cli/uuid"
+	"github.com/inovacc/omni/pkg/idgen"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +28,10 @@ Examples:
   omni uuid -v 7             # generate time-ordered UUID v7
   omni uuid -n 5             # generate 5 UUIDs
   omni uuid -u               # uppercase output
-  omni uuid -x               # no dashes (32 hex chars)`,
+  omni uuid -x               # no dashes (32 hex chars)
+
+Subcommands:
+  time      Print the timestamp embedded in v1, v6 and v7 UUIDs`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := uuid.UUIDOptions{}
 
@@ -40,8 +45,38 @@ Examples:
 	},
 }
 
+// uuidTimeCmd represents the uuid time command
+var uuidTimeCmd = &cobra.Command{
+	Use:   "time [OPTION]... [UUID]...",
+	Short: "Print the timestamp embedded in v1, v6 and v7 UUIDs",
+	Long: `Print the creation time embedded in each time-based UUID (version 1, 6
+or 7), in UTC, local time and Unix seconds. Dashes are optional. IDs are
+read one per line from standard input when none are given.
+
+With several IDs, every output line starts with the ID it belongs to.
+
+  -f, --format=FMT  all (default), utc, local, unix or unix-ms
+  --json            output as JSON
+
+Examples:
+  omni uuid time 018df9e2-b200-7000-8000-000000000000
+  omni uuid -v 7 | omni uuid time -f unix-ms
+  omni uuid time --json 018df9e2-b200-7000-8000-000000000000`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ulid.TimeOptions{Kind: idgen.KindUUIDv7}
+
+		opts.Format, _ = cmd.Flags().GetString("format")
+		opts.OutputFormat = getOutputOpts(cmd).GetFormat()
+
+		return ulid.RunTime(cmd.OutOrStdout(), cmd.InOrStdin(), args, opts)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(uuidCmd)
+	uuidCmd.AddCommand(uuidTimeCmd)
+
+	uuidTimeCmd.Flags().StringP("format", "f", "all", "output format: all, utc, local, unix, unix-ms")
 
 	uuidCmd.Flags().IntP("version", "v", 4, "UUID version (4 or 7)")
 	uuidCmd.Flags().IntP("count", "n", 1, "generate N UUIDs")
@@ -18,11 +18,24 @@ With no FILE, or when FILE is -, read standard input.
 The options below may be used to select which counts are printed, always in
 the following order: newline, word, character, byte, maximum line length.
 
+  -l, --lines             print the newline counts
+  -w, --words             print the word counts
+  -m, --chars             print the character counts (UTF-8 aware)
+  -c, --bytes             print the byte counts
+  -L, --max-line-length   print the maximum display width (tabs to 8,
+                          wide characters count 2)
+  --json                  print one object per file, plus the total
+
+Unreadable files are reported on stderr and skipped; the total covers the
+remaining files and the exit status is 1.
+
 Examples:
   omni wc file.txt                # lines, words, and bytes
   omni wc -l file.txt             # line count only
   omni wc -w file.txt             # word count only
-  cat file.txt | omni wc -c       # byte count from stdin`,
+  cat file.txt | omni wc -c       # byte count from stdin
+  omni wc -mL notes.md            # characters and widest line
+  omni wc --json *.go             # per-file counts and total as JSON`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := wc.WCOptions{}
 
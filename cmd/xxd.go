@@ -24,6 +24,14 @@ Output Modes:
 Reverse Mode:
   -r, --reverse  Convert hex dump back to binary
 
+Interactive Mode:
+  --view       Browse the input in a scrolling viewer instead of dumping it.
+               Only the visible rows are read, so large files open instantly.
+               Keys: j/k scroll, space/ctrl+b page, g/G top/bottom,
+               :OFFSET go to offset (decimal, 0x hex, negative from end),
+               /TEXT search ASCII, x HEX search bytes, n/N next/previous
+               match, b toggle bits view, q quit
+
 Examples:
   # Basic hex dump
   omni xxd file.bin
@@ -50,7 +58,11 @@ Examples:
   omni xxd -s 100 file.bin
 
   # Custom columns and grouping
-  omni xxd -c 8 -g 1 file.bin`,
+  omni xxd -c 8 -g 1 file.bin
+
+  # Inspect a large binary interactively
+  omni xxd --view firmware.img
+  omni xxd --view -s 0x1000 disk.img`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := xxd.Options{
 			Columns:   16,
@@ -73,6 +85,7 @@ Examples:
 		opts.Include, _ = cmd.Flags().GetBool("include")
 		opts.Uppercase, _ = cmd.Flags().GetBool("uppercase")
 		opts.Bits, _ = cmd.Flags().GetBool("bits")
+		opts.View, _ = cmd.Flags().GetBool("view")
 
 		return xxd.Run(cmd.OutOrStdout(), os.Stdin, args, opts)
 	},
@@ -90,4 +103,5 @@ func init() {
 	xxdCmd.Flags().BoolP("include", "i", false, "output in C include file style")
 	xxdCmd.Flags().BoolP("uppercase", "u", false, "use uppercase hex letters")
 	xxdCmd.Flags().BoolP("bits", "b", false, "binary digit dump (bits instead of hex)")
+	xxdCmd.Flags().Bool("view", false, "browse the input in an interactive viewer")
 }
@@ -16,10 +16,17 @@ var yesCmd = &cobra.Command{
 	Short: "Output a string repeatedly until killed",
 	Long: `Repeatedly output a line with all specified STRING(s), or 'y'.
 
+  -n, --count N    stop after N lines
+      --rate N     output at most N lines per second (may be fractional)
+
+Output stops quietly when the reading end of a pipe is closed.
+
 Examples:
   omni yes              # outputs 'y' forever
   omni yes hello        # outputs 'hello' forever
-  omni yes | head -5    # outputs 5 'y' lines`,
+  omni yes | head -5    # outputs 5 'y' lines
+  omni yes -n 3 ok      # outputs 'ok' three times
+  omni yes --rate 2 ping  # two lines per second, e.g. to feed a slow consumer`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -31,10 +38,17 @@ Examples:
 			cancel()
 		}()
 
-		return yes.RunYes(ctx, cmd.OutOrStdout(), args)
+		opts := yes.Options{}
+		opts.Count, _ = cmd.Flags().GetInt("count")
+		opts.Rate, _ = cmd.Flags().GetFloat64("rate")
+
+		return yes.Run(ctx, cmd.OutOrStdout(), args, opts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(yesCmd)
+
+	yesCmd.Flags().IntP("count", "n", 0, "stop after N lines")
+	yesCmd.Flags().Float64("rate", 0, "output at most N lines per second")
 }
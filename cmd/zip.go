@@ -14,11 +14,18 @@ var zipCmd = &cobra.Command{
   -v, --verbose     verbose output
   -r, --recursive   recurse into directories (default for directories)
   -C, --directory   change to directory before adding files
+  --reproducible    byte-identical archives for identical inputs
+
+With --reproducible every entry gets the same time (SOURCE_DATE_EPOCH, or
+1980-01-01 when unset) and permissions 0755 for directories and executables
+and 0644 otherwise, and entries are stored in sorted order, so two runs over
+the same files produce the same bytes.
 
 Examples:
   omni zip archive.zip file1.txt file2.txt   # create zip
   omni zip archive.zip dir/                   # zip directory
-  omni zip -v archive.zip file.txt           # verbose output`,
+  omni zip -v archive.zip file.txt           # verbose output
+  omni zip --reproducible dist.zip dist/     # same bytes on every run`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return cmd.Help()
@@ -31,6 +38,7 @@ Examples:
 
 		opts.Verbose, _ = cmd.Flags().GetBool("verbose")
 		opts.Directory, _ = cmd.Flags().GetString("directory")
+		opts.Reproducible, _ = cmd.Flags().GetBool("reproducible")
 
 		return archive.RunZip(cmd.OutOrStdout(), args[1:], opts)
 	},
@@ -42,4 +50,5 @@ func init() {
 	zipCmd.Flags().BoolP("verbose", "v", false, "verbose output")
 	zipCmd.Flags().BoolP("recursive", "r", false, "recurse into directories")
 	zipCmd.Flags().StringP("directory", "C", "", "change to directory before adding")
+	zipCmd.Flags().Bool("reproducible", false, "normalize times and permissions for byte-identical archives")
 }
@@ -53,7 +53,7 @@ var categoryMap = map[string]string{
 	"tr": "text", "wc": "text", "nl": "text", "paste": "text", "tac": "text",
 	"column": "text", "fold": "text", "join": "text", "shuf": "text", "split": "text",
 	"rev": "text", "comm": "text", "cmp": "text", "strings": "text", "diff": "text",
-	"expand": "text", "unexpand": "text",
+	"expand": "text", "unexpand": "text", "stats": "text",
 
 	// System Info
 	"env": "sys", "whoami": "sys", "id": "sys", "uname": "sys", "uptime": "sys",
@@ -70,7 +70,7 @@ var categoryMap = map[string]string{
 	"base64": "hash", "base32": "hash", "base58": "hash",
 
 	// Data Processing
-	"jq": "data", "yq": "data", "json": "data", "dotenv": "data",
+	"jq": "data", "yq": "data", "json": "data", "dotenv": "data", "convert": "data",
 
 	// Security
 	"encrypt": "security", "decrypt": "security", "uuid": "security",
@@ -83,12 +83,12 @@ var categoryMap = map[string]string{
 	"scaffold": "codegen",
 
 	// Utilities
-	"time": "util", "sleep": "util", "seq": "util", "xargs": "util",
-	"watch": "util", "tee": "util", "true": "util", "false": "util", "test": "util",
+	"time": "util", "sleep": "util", "until": "util", "seq": "util", "xargs": "util",
+	"watch": "util", "notify": "util", "prompt": "util", "tee": "util", "true": "util", "false": "util", "test": "util",
 
 	// Tooling
 	"lint": "tools", "logger": "tools", "cmdtree": "tools", "aicontext": "tools",
-	"version": "tools",
+	"docs": "tools", "version": "tools", "selftest": "tools",
 }
 
 var categoryNames = map[string]string{
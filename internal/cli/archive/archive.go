@@ -3,7 +3,9 @@ package archive
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +23,7 @@ type ArchiveOptions struct {
 	Gzip            bool   // -z: use gzip compression
 	StripComponents int    // --strip-components: strip N leading path components
 	JSON            bool   // --json: output as JSON (for list mode)
+	Reproducible    bool   // --reproducible: normalize entry metadata for byte-identical archives
 }
 
 // ArchiveEntry represents a file entry in an archive
@@ -82,6 +85,44 @@ func createArchive(w io.Writer, sources []string, opts ArchiveOptions) error {
 	return createTarArchive(w, outFile, sources, opts, isTarGz)
 }
 
+// reproducibleEpoch is the time of every entry of a reproducible archive
+// when SOURCE_DATE_EPOCH is not set: the earliest time a zip entry can hold.
+var reproducibleEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// reproducibleModTime returns the entry time for --reproducible: the
+// SOURCE_DATE_EPOCH environment variable (seconds since the Unix epoch, see
+// reproducible-builds.org) when set, otherwise reproducibleEpoch.
+func reproducibleModTime() (time.Time, error) {
+	v := os.Getenv("SOURCE_DATE_EPOCH")
+	if v == "" {
+		return reproducibleEpoch, nil
+	}
+
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || secs < 0 {
+		return time.Time{}, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("archive: invalid SOURCE_DATE_EPOCH %q", v))
+	}
+
+	return time.Unix(secs, 0).UTC(), nil
+}
+
+// reproducibleMode maps a file mode to the permissions stored by
+// --reproducible: 0755 for directories and files executable by anyone,
+// 0777 for symlinks and 0644 for everything else. Only the type bits of
+// mode are kept.
+func reproducibleMode(mode fs.FileMode) fs.FileMode {
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		return fs.ModeSymlink | 0o777
+	case mode.IsDir():
+		return fs.ModeDir | 0o755
+	case mode&0o111 != 0:
+		return mode.Type() | 0o755
+	}
+
+	return mode.Type() | 0o644
+}
+
 func extractArchive(w io.Writer, opts ArchiveOptions) error {
 	if opts.File == "" {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "archive: no input file specified (-f)")
@@ -3,12 +3,16 @@ package archive
 import (
 	"archive/tar"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 // TestRoundTrip_TarGz creates a gzip-compressed tar from a directory tree,
@@ -390,3 +394,158 @@ func TestRefuseWriteThroughSymlink_RejectsSymlink(t *testing.T) {
 		t.Error("expected rejection when a path segment is a symlink")
 	}
 }
+
+// buildReproTree writes the same small tree under a fresh directory each
+// time, with the given permission for the regular file and mtime for every
+// entry, so two calls differ only in metadata --reproducible must erase.
+func buildReproTree(t *testing.T, filePerm os.FileMode, mtime time.Time) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "tree")
+
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []struct {
+		rel  string
+		perm os.FileMode
+	}{
+		{"a.txt", filePerm},
+		{"run.sh", 0o755},
+		{"sub/b.txt", filePerm},
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(root, e.rel)
+		if err := os.WriteFile(path, []byte("content of "+e.rel), e.perm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chmod(path, e.perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, rel := range []string{"a.txt", "run.sh", "sub/b.txt", "sub", "."} {
+		if err := os.Chtimes(filepath.Join(root, rel), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func archiveDigest(t *testing.T, dir, name string, opts ArchiveOptions) string {
+	t.Helper()
+
+	opts.Create = true
+	opts.File = filepath.Join(dir, name)
+	opts.Directory = dir
+
+	if err := RunArchive(&bytes.Buffer{}, []string{"tree"}, opts); err != nil {
+		t.Fatalf("create %s error = %v", name, err)
+	}
+
+	data, err := os.ReadFile(opts.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreateReproducible_IdenticalDigests(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+
+	for _, name := range []string{"out.tar", "out.tar.gz", "out.zip"} {
+		t.Run(name, func(t *testing.T) {
+			first := buildReproTree(t, 0o644, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+			second := buildReproTree(t, 0o600, time.Date(2024, 6, 7, 8, 9, 10, 0, time.Local))
+
+			a := archiveDigest(t, first, name, ArchiveOptions{Reproducible: true})
+			b := archiveDigest(t, second, name, ArchiveOptions{Reproducible: true})
+
+			if a != b {
+				t.Errorf("reproducible digests differ: %s != %s", a, b)
+			}
+
+			if archiveDigest(t, second, name, ArchiveOptions{Reproducible: true}) != b {
+				t.Error("second run over the same tree changed the digest")
+			}
+
+			if plain := archiveDigest(t, second, name, ArchiveOptions{}); plain == a {
+				t.Error("plain archive unexpectedly matches the reproducible one")
+			}
+		})
+	}
+}
+
+func TestCreateReproducible_TarHeaders(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	dir := buildReproTree(t, 0o600, time.Now())
+	archiveDigest(t, dir, "out.tar", ArchiveOptions{Reproducible: true})
+
+	f, err := os.Open(filepath.Join(dir, "out.tar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	want := map[string]int64{
+		"tree/":          0o755,
+		"tree/a.txt":     0o644,
+		"tree/run.sh":    0o755,
+		"tree/sub/":      0o755,
+		"tree/sub/b.txt": 0o644,
+	}
+
+	var names []string
+
+	tr := tar.NewReader(f)
+
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+
+		names = append(names, h.Name)
+
+		if mode, ok := want[h.Name]; !ok || h.Mode != mode {
+			t.Errorf("%s: mode %o, want %o", h.Name, h.Mode, mode)
+		}
+
+		if !h.ModTime.Equal(time.Unix(1700000000, 0)) {
+			t.Errorf("%s: mtime %v", h.Name, h.ModTime)
+		}
+
+		if h.Uid != 0 || h.Gid != 0 || h.Uname != "" || h.Gname != "" {
+			t.Errorf("%s: owner %d:%d %q:%q", h.Name, h.Uid, h.Gid, h.Uname, h.Gname)
+		}
+	}
+
+	if got := strings.Join(names, " "); got != "tree/ tree/a.txt tree/run.sh tree/sub/ tree/sub/b.txt" {
+		t.Errorf("entry order = %s", got)
+	}
+}
+
+func TestCreateReproducible_InvalidEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+
+	dir := buildReproTree(t, 0o644, time.Now())
+
+	err := RunArchive(&bytes.Buffer{}, []string{"tree"}, ArchiveOptions{
+		Create:       true,
+		File:         filepath.Join(dir, "out.zip"),
+		Directory:    dir,
+		Reproducible: true,
+	})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunArchive() error = %v, want invalid input", err)
+	}
+}
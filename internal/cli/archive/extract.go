@@ -0,0 +1,585 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/mimetype"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Format is an archive or compression format recognized by DetectFormat.
+type Format string
+
+const (
+	FormatTar    Format = "tar"
+	FormatTarGz  Format = "tar.gz"
+	FormatTarBz2 Format = "tar.bz2"
+	FormatTarXz  Format = "tar.xz"
+	FormatTarZst Format = "tar.zst"
+	FormatZip    Format = "zip"
+	FormatGzip   Format = "gzip"  // single gzip-compressed file
+	FormatBzip2  Format = "bzip2" // single bzip2-compressed file
+	FormatXz     Format = "xz"    // single xz-compressed file
+	FormatZstd   Format = "zstd"  // single zstd-compressed file
+	Format7z     Format = "7z"    // recognized, but not extracted
+)
+
+// Symlink policies for ExtractOptions.Symlinks.
+const (
+	SymlinksKeep   = "keep"   // create links whose target stays inside the destination
+	SymlinksSkip   = "skip"   // ignore link entries
+	SymlinksReject = "reject" // fail on the first link entry
+)
+
+// ExtractOptions configures the extract command behavior
+type ExtractOptions struct {
+	Directory       string        // -C: destination directory
+	StripComponents int           // --strip-components: strip N leading path components
+	Include         []string      // --include: only extract entries matching these globs
+	Exclude         []string      // --exclude: skip entries matching these globs
+	Symlinks        string        // --symlinks: keep, skip or reject
+	Verbose         bool          // -v: print each extracted entry
+	OutputFormat    output.Format // output format (text, json, table)
+}
+
+// ExtractResult summarizes an extraction.
+type ExtractResult struct {
+	Archive     string `json:"archive"`
+	Format      Format `json:"format"`
+	Destination string `json:"destination"`
+	Files       int    `json:"files"`
+	Dirs        int    `json:"dirs"`
+	Links       int    `json:"links"`
+	Skipped     int    `json:"skipped"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// DetectFormat identifies the format of an archive from its leading bytes.
+// header should hold at least the first 512 bytes when available; tar is
+// recognized by its ustar magic at offset 257.
+func DetectFormat(header []byte) (Format, bool) {
+	t := mimetype.Detect(header)
+
+	switch {
+	case t.Is("application/zip"):
+		// Formats built on zip (jar, docx, epub, ...) extract as zip
+		return FormatZip, true
+	case t.MIME == "application/gzip":
+		return FormatGzip, true
+	case t.MIME == "application/x-bzip2":
+		return FormatBzip2, true
+	case t.MIME == "application/x-xz":
+		return FormatXz, true
+	case t.MIME == "application/zstd":
+		return FormatZstd, true
+	case t.MIME == "application/x-7z-compressed":
+		return Format7z, true
+	case t.MIME == "application/x-tar":
+		return FormatTar, true
+	}
+
+	return "", false
+}
+
+func isTarHeader(header []byte) bool {
+	return len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar"))
+}
+
+// RunExtract extracts file into opts.Directory, detecting its format by magic
+// bytes rather than by extension.
+func RunExtract(w io.Writer, file string, opts ExtractOptions) error {
+	switch opts.Symlinks {
+	case "":
+		opts.Symlinks = SymlinksKeep
+	case SymlinksKeep, SymlinksSkip, SymlinksReject:
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: invalid --symlinks %q (want keep, skip or reject)", opts.Symlinks))
+	}
+
+	for _, p := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: bad pattern %q", p))
+		}
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("extract: %s", file))
+		}
+
+		return fmt.Errorf("extract: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	destDir := opts.Directory
+	if destDir == "" {
+		destDir = "."
+	}
+
+	cleanDest, err := filepath.Abs(filepath.Clean(destDir))
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, "extract: resolve destination: "+err.Error())
+	}
+
+	if err := os.MkdirAll(cleanDest, 0o755); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+	}
+
+	br := bufio.NewReaderSize(f, 4096)
+	header, _ := br.Peek(512)
+
+	format, ok := DetectFormat(header)
+	if !ok {
+		return cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("extract: %s: unrecognized archive format", file))
+	}
+
+	x := &extractor{w: w, dest: cleanDest, opts: opts}
+	x.result = ExtractResult{Archive: file, Format: format, Destination: cleanDest}
+
+	switch format {
+	case FormatZip:
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+
+		err = x.zip(f, info.Size())
+		if err != nil {
+			return err
+		}
+	case FormatTar:
+		if err := x.tar(br); err != nil {
+			return err
+		}
+	case FormatGzip, FormatBzip2, FormatXz, FormatZstd:
+		var r io.Reader
+
+		switch format {
+		case FormatGzip:
+			gr, err := gzip.NewReader(br)
+			if err != nil {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: %s: %s", file, err))
+			}
+
+			defer func() {
+				_ = gr.Close()
+			}()
+
+			r = gr
+		case FormatBzip2:
+			r = bzip2.NewReader(br)
+		case FormatXz:
+			xr, err := xz.NewReader(br)
+			if err != nil {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: %s: %s", file, err))
+			}
+
+			r = xr
+		case FormatZstd:
+			zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: %s: %s", file, err))
+			}
+
+			defer zr.Close()
+
+			r = zr
+		}
+
+		inner := bufio.NewReaderSize(r, 4096)
+		if peek, _ := inner.Peek(512); isTarHeader(peek) {
+			x.result.Format = map[Format]Format{
+				FormatGzip:  FormatTarGz,
+				FormatBzip2: FormatTarBz2,
+				FormatXz:    FormatTarXz,
+				FormatZstd:  FormatTarZst,
+			}[format]
+
+			if err := x.tar(inner); err != nil {
+				return err
+			}
+		} else if err := x.single(inner, decompressedName(file)); err != nil {
+			return err
+		}
+	default:
+		return cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("extract: %s: %s archives are not supported", file, format))
+	}
+
+	out := output.New(w, opts.OutputFormat)
+	if out.IsJSON() {
+		return out.Print(x.result)
+	}
+
+	return nil
+}
+
+// decompressedName is the output name for a single compressed file.
+func decompressedName(file string) string {
+	base := filepath.Base(file)
+
+	for _, ext := range []string{".gz", ".tgz", ".bz2", ".tbz2", ".xz", ".txz", ".zst", ".tzst"} {
+		if name, ok := strings.CutSuffix(base, ext); ok && name != "" {
+			if ext == ".tgz" || ext == ".tbz2" || ext == ".txz" || ext == ".tzst" {
+				return name + ".tar"
+			}
+
+			return name
+		}
+	}
+
+	return base + ".out"
+}
+
+// extractor writes archive entries below dest, enforcing the same
+// containment rules as tar -x and unzip.
+type extractor struct {
+	w      io.Writer
+	dest   string
+	opts   ExtractOptions
+	result ExtractResult
+}
+
+// entryKind is the type of an archive entry.
+type entryKind int
+
+const (
+	kindFile entryKind = iota
+	kindDir
+	kindSymlink
+	kindHardlink
+)
+
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: %s", err))
+		}
+
+		var kind entryKind
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			kind = kindFile
+		case tar.TypeDir:
+			kind = kindDir
+		case tar.TypeSymlink:
+			kind = kindSymlink
+		case tar.TypeLink:
+			kind = kindHardlink
+		default:
+			// Devices, FIFOs and PAX/GNU metadata are never materialized
+			x.result.Skipped++
+			continue
+		}
+
+		if err := x.entry(header.Name, kind, os.FileMode(header.Mode), header.Linkname, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func (x *extractor) zip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: %s", err))
+	}
+
+	for _, f := range zr.File {
+		kind := kindFile
+
+		switch {
+		case f.FileInfo().IsDir():
+			kind = kindDir
+		case f.Mode()&os.ModeSymlink != 0:
+			kind = kindSymlink
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("extract: %s: %s", f.Name, err))
+		}
+
+		var linkname string
+
+		if kind == kindSymlink {
+			// Zip stores the link target as the entry content
+			data, err := io.ReadAll(io.LimitReader(rc, 4096))
+			if err != nil {
+				_ = rc.Close()
+				return fmt.Errorf("extract: %w", err)
+			}
+
+			linkname = string(data)
+		}
+
+		err = x.entry(f.Name, kind, f.Mode(), linkname, rc)
+		_ = rc.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// single writes one decompressed stream to dest/name.
+func (x *extractor) single(r io.Reader, name string) error {
+	return x.entry(name, kindFile, 0o644, "", r)
+}
+
+// stripName removes the leading components of an entry name; it returns
+// false when nothing is left.
+func stripName(name string, n int) (string, bool) {
+	name = strings.TrimPrefix(filepath.ToSlash(name), "./")
+	if n <= 0 {
+		return name, name != "" && name != "."
+	}
+
+	parts := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	if len(parts) <= n {
+		return "", false
+	}
+
+	return strings.Join(parts[n:], "/"), true
+}
+
+// matchEntry reports whether pattern matches name, one of its parent
+// directories, or, for patterns without a slash, its base name.
+func matchEntry(pattern, name string) bool {
+	name = strings.TrimSuffix(name, "/")
+
+	if !strings.Contains(pattern, "/") {
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
+		}
+	}
+
+	for p := name; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(strings.TrimSuffix(pattern, "/"), p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (x *extractor) selected(name string) bool {
+	for _, p := range x.opts.Exclude {
+		if matchEntry(p, name) {
+			return false
+		}
+	}
+
+	if len(x.opts.Include) == 0 {
+		return true
+	}
+
+	for _, p := range x.opts.Include {
+		if matchEntry(p, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (x *extractor) entry(rawName string, kind entryKind, mode os.FileMode, linkname string, content io.Reader) error {
+	name, ok := stripName(rawName, x.opts.StripComponents)
+	if !ok || !x.selected(name) {
+		x.result.Skipped++
+		return nil
+	}
+
+	target, err := secureJoin(x.dest, filepath.FromSlash(name))
+	if err != nil {
+		return err
+	}
+
+	if x.opts.Verbose && !output.New(x.w, x.opts.OutputFormat).IsJSON() {
+		_, _ = fmt.Fprintln(x.w, name)
+	}
+
+	switch kind {
+	case kindDir:
+		if err := refuseWriteThroughSymlink(x.dest, target); err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(target, dirMode(mode)); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+		}
+
+		x.result.Dirs++
+	case kindFile:
+		return x.writeFile(target, mode, content)
+	case kindSymlink, kindHardlink:
+		return x.link(name, target, kind, linkname)
+	}
+
+	return nil
+}
+
+func (x *extractor) writeFile(target string, mode os.FileMode, content io.Reader) error {
+	if err := refuseWriteThroughSymlink(x.dest, target); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+	}
+
+	// Remove a read-only file left by an earlier extraction so it can be
+	// replaced
+	if info, err := os.Lstat(target); err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o200 == 0 {
+		_ = os.Remove(target)
+	}
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|oNoFollow, fileMode(mode))
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+	}
+
+	// Bound the copy so a decompression bomb cannot fill the disk
+	// (archive-05), as in extractTarArchive.
+	remaining := extractByteCap - x.result.Bytes
+	n, err := io.Copy(out, io.LimitReader(content, remaining+1))
+	_ = out.Close()
+
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+	}
+
+	x.result.Bytes += n
+	if x.result.Bytes > extractByteCap {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "extract: extraction exceeds maximum allowed size")
+	}
+
+	x.result.Files++
+
+	return nil
+}
+
+func (x *extractor) link(name, target string, kind entryKind, linkname string) error {
+	switch x.opts.Symlinks {
+	case SymlinksSkip:
+		x.result.Skipped++
+		return nil
+	case SymlinksReject:
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("extract: %s is a link and --symlinks=reject", name))
+	}
+
+	if err := refuseWriteThroughSymlink(x.dest, target); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+	}
+
+	_ = os.Remove(target)
+
+	if kind == kindHardlink {
+		// Both endpoints must be inside the destination (archive-04)
+		stripped, ok := stripName(linkname, x.opts.StripComponents)
+		if !ok {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "extract: hardlink target stripped away: "+linkname)
+		}
+
+		linkTarget, err := secureJoin(x.dest, filepath.FromSlash(stripped))
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "extract: hardlink target escapes destination: "+linkname)
+		}
+
+		if err := os.Link(linkTarget, target); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+		}
+
+		x.result.Links++
+
+		return nil
+	}
+
+	// Symlink targets must resolve inside the destination (archive-03)
+	if filepath.IsAbs(linkname) || path.IsAbs(linkname) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "extract: symlink target is absolute: "+linkname)
+	}
+
+	resolved := filepath.Clean(filepath.Join(filepath.Dir(target), filepath.FromSlash(linkname)))
+	if resolved != x.dest && !strings.HasPrefix(resolved, x.dest+string(os.PathSeparator)) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "extract: symlink target escapes destination: "+linkname)
+	}
+
+	if err := os.Symlink(filepath.FromSlash(linkname), target); err != nil {
+		// Creating symlinks on Windows needs Developer Mode or an elevated
+		// shell; skip them rather than failing the whole extraction
+		if runtime.GOOS == "windows" {
+			x.result.Skipped++
+			return nil
+		}
+
+		return cmderr.Wrap(cmderr.ErrIO, "extract: "+err.Error())
+	}
+
+	x.result.Links++
+
+	return nil
+}
+
+// fileMode maps an archived mode to the mode of the extracted file.
+// Set-id and sticky bits are dropped. Windows only honors the owner write
+// bit, as the read-only attribute.
+func fileMode(mode os.FileMode) os.FileMode {
+	perm := mode.Perm()
+
+	if runtime.GOOS == "windows" {
+		if perm&0o200 == 0 {
+			return 0o444
+		}
+
+		return 0o666
+	}
+
+	if perm == 0 {
+		return 0o644
+	}
+
+	return perm
+}
+
+// dirMode is fileMode for directories; the owner always keeps rwx so the
+// rest of the archive can be written into them.
+func dirMode(mode os.FileMode) os.FileMode {
+	if runtime.GOOS == "windows" {
+		return 0o777
+	}
+
+	return mode.Perm() | 0o700
+}
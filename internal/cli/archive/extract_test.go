@@ -0,0 +1,399 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+type testEntry struct {
+	name     string
+	body     string
+	dir      bool
+	linkname string // symlink target
+}
+
+var sampleEntries = []testEntry{
+	{name: "proj/", dir: true},
+	{name: "proj/README.md", body: "readme"},
+	{name: "proj/src/main.go", body: "package main"},
+	{name: "proj/src/util.go", body: "package util"},
+	{name: "proj/docs/guide.txt", body: "guide"},
+	{name: "proj/link", linkname: "README.md"},
+}
+
+func buildTar(t *testing.T, entries []testEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+
+		switch {
+		case e.dir:
+			hdr.Typeflag, hdr.Mode, hdr.Size = tar.TypeDir, 0o755, 0
+		case e.linkname != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.linkname, 0
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+
+		if hdr.Typeflag == tar.TypeReg {
+			_, _ = tw.Write([]byte(e.body))
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	_, _ = gw.Write(data)
+
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func xzBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	xw, err := xz.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = xw.Write(data)
+
+	if err := xw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = zw.Close() }()
+
+	return zw.EncodeAll(data, nil)
+}
+
+func buildZip(t *testing.T, entries []testEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+
+		body := e.body
+
+		switch {
+		case e.dir:
+			hdr.SetMode(os.ModeDir | 0o755)
+		case e.linkname != "":
+			hdr.SetMode(os.ModeSymlink | 0o777)
+			body = e.linkname
+		default:
+			hdr.SetMode(0o644)
+		}
+
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = fw.Write([]byte(body))
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// writeArchive writes data under a misleading name, so only magic-byte
+// detection can pick the right format.
+func writeArchive(t *testing.T, data []byte) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), "download.bin")
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func listTree(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+
+	_ = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || p == dir || info.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, p)
+		files = append(files, filepath.ToSlash(rel))
+
+		return nil
+	})
+
+	sort.Strings(files)
+
+	return files
+}
+
+func TestDetectFormat(t *testing.T) {
+	tarData := buildTar(t, sampleEntries[:2])
+
+	tests := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{"tar", tarData, FormatTar},
+		{"gzip", gzipBytes(t, tarData), FormatGzip},
+		{"zip", buildZip(t, sampleEntries[:2]), FormatZip},
+		{"bzip2", []byte("BZh91AY&SY"), FormatBzip2},
+		{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x00}, FormatXz},
+		{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, FormatZstd},
+		{"7z", []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c, 0x00}, Format7z},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectFormat(tt.data)
+			if !ok || got != tt.want {
+				t.Errorf("DetectFormat() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := DetectFormat([]byte("plain text")); ok {
+		t.Error("DetectFormat(text) should fail")
+	}
+}
+
+func TestRunExtract_Formats(t *testing.T) {
+	want := []string{"proj/README.md", "proj/docs/guide.txt", "proj/link", "proj/src/main.go", "proj/src/util.go"}
+	if runtime.GOOS == "windows" {
+		want = []string{"proj/README.md", "proj/docs/guide.txt", "proj/src/main.go", "proj/src/util.go"}
+	}
+
+	tarData := buildTar(t, sampleEntries)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		format Format
+	}{
+		{"tar", tarData, FormatTar},
+		{"tar.gz", gzipBytes(t, tarData), FormatTarGz},
+		{"tar.xz", xzBytes(t, tarData), FormatTarXz},
+		{"tar.zst", zstdBytes(t, tarData), FormatTarZst},
+		{"zip", buildZip(t, sampleEntries), FormatZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := t.TempDir()
+
+			var buf bytes.Buffer
+			if err := RunExtract(&buf, writeArchive(t, tt.data), ExtractOptions{Directory: dest, OutputFormat: output.FormatJSON}); err != nil {
+				t.Fatalf("RunExtract() error = %v", err)
+			}
+
+			var res ExtractResult
+			if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+				t.Fatalf("bad JSON %q: %v", buf.String(), err)
+			}
+
+			if res.Format != tt.format || res.Files != 4 {
+				t.Errorf("result = %+v", res)
+			}
+
+			if got := listTree(t, dest); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("extracted %v, want %v", got, want)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dest, "proj", "src", "main.go"))
+			if err != nil || string(data) != "package main" {
+				t.Errorf("main.go = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestRunExtract_Filters(t *testing.T) {
+	archivePath := writeArchive(t, gzipBytes(t, buildTar(t, sampleEntries)))
+
+	tests := []struct {
+		name string
+		opts ExtractOptions
+		want []string
+	}{
+		{"strip", ExtractOptions{StripComponents: 1, Symlinks: SymlinksSkip}, []string{"README.md", "docs/guide.txt", "src/main.go", "src/util.go"}},
+		{"include dir", ExtractOptions{Include: []string{"proj/src"}}, []string{"proj/src/main.go", "proj/src/util.go"}},
+		{"include base glob", ExtractOptions{Include: []string{"*.md"}}, []string{"proj/README.md"}},
+		{"exclude wins", ExtractOptions{Include: []string{"*.go"}, Exclude: []string{"util.go"}}, []string{"proj/src/main.go"}},
+		{"strip and include", ExtractOptions{StripComponents: 1, Include: []string{"docs/*"}}, []string{"docs/guide.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Directory = t.TempDir()
+
+			if err := RunExtract(&bytes.Buffer{}, archivePath, tt.opts); err != nil {
+				t.Fatalf("RunExtract() error = %v", err)
+			}
+
+			if got := listTree(t, tt.opts.Directory); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("extracted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunExtract_SymlinkPolicy(t *testing.T) {
+	archivePath := writeArchive(t, buildTar(t, sampleEntries))
+
+	err := RunExtract(&bytes.Buffer{}, archivePath, ExtractOptions{Directory: t.TempDir(), Symlinks: SymlinksReject})
+	if !cmderr.IsPermission(err) {
+		t.Errorf("reject: err = %v, want permission error", err)
+	}
+
+	dest := t.TempDir()
+	if err := RunExtract(&bytes.Buffer{}, archivePath, ExtractOptions{Directory: dest, Symlinks: SymlinksSkip}); err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+
+	if _, err := os.Lstat(filepath.Join(dest, "proj", "link")); !os.IsNotExist(err) {
+		t.Errorf("skip: link was created (%v)", err)
+	}
+
+	err = RunExtract(&bytes.Buffer{}, archivePath, ExtractOptions{Directory: t.TempDir(), Symlinks: "follow"})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad policy: err = %v, want invalid input", err)
+	}
+}
+
+func TestRunExtract_RejectsEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []testEntry
+	}{
+		{"dotdot", []testEntry{{name: "../evil.txt", body: "x"}}},
+		{"symlink", []testEntry{{name: "link", linkname: "../../etc"}}},
+		{"absolute symlink", []testEntry{{name: "link", linkname: "/etc/passwd"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, data := range [][]byte{buildTar(t, tt.entries), buildZip(t, tt.entries)} {
+				err := RunExtract(&bytes.Buffer{}, writeArchive(t, data), ExtractOptions{Directory: t.TempDir()})
+				if !cmderr.IsInvalidInput(err) {
+					t.Errorf("err = %v, want invalid input", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunExtract_SingleCompressedFile(t *testing.T) {
+	for ext, compress := range map[string]func(*testing.T, []byte) []byte{
+		".gz":  gzipBytes,
+		".xz":  xzBytes,
+		".zst": zstdBytes,
+	} {
+		p := filepath.Join(t.TempDir(), "notes.txt"+ext)
+		if err := os.WriteFile(p, compress(t, []byte("hello")), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		dest := t.TempDir()
+		if err := RunExtract(&bytes.Buffer{}, p, ExtractOptions{Directory: dest}); err != nil {
+			t.Fatalf("%s: RunExtract() error = %v", ext, err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dest, "notes.txt"))
+		if err != nil || string(data) != "hello" {
+			t.Errorf("%s: notes.txt = %q, %v", ext, data, err)
+		}
+	}
+}
+
+func TestRunExtract_Unsupported(t *testing.T) {
+	err := RunExtract(&bytes.Buffer{}, writeArchive(t, []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c, 0x00, 0x04}), ExtractOptions{Directory: t.TempDir()})
+	if !cmderr.IsUnsupported(err) || !strings.Contains(err.Error(), "7z archives are not supported") {
+		t.Errorf("7z: err = %v, want unsupported", err)
+	}
+
+	err = RunExtract(&bytes.Buffer{}, writeArchive(t, []byte("just text")), ExtractOptions{Directory: t.TempDir()})
+	if !cmderr.IsUnsupported(err) {
+		t.Errorf("text: err = %v, want unsupported", err)
+	}
+
+	err = RunExtract(&bytes.Buffer{}, filepath.Join(t.TempDir(), "missing.tar"), ExtractOptions{})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("missing: err = %v, want not found", err)
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		if got := fileMode(0o555); got != 0o444 {
+			t.Errorf("fileMode(0555) = %o, want 0444", got)
+		}
+
+		return
+	}
+
+	if got := fileMode(os.ModeSetuid | 0o4755); got != 0o755 {
+		t.Errorf("fileMode(setuid) = %o, want 0755", got)
+	}
+
+	if got := dirMode(0o500); got != 0o700 {
+		t.Errorf("dirMode(0500) = %o, want 0700", got)
+	}
+}
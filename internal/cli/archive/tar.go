@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 )
@@ -91,6 +92,15 @@ func refuseWriteThroughSymlink(cleanDest, target string) error {
 }
 
 func createTarArchive(w io.Writer, outFile *os.File, sources []string, opts ArchiveOptions, useGzip bool) error {
+	var mtime time.Time
+
+	if opts.Reproducible {
+		var err error
+		if mtime, err = reproducibleModTime(); err != nil {
+			return err
+		}
+	}
+
 	var tw *tar.Writer
 
 	if useGzip {
@@ -155,6 +165,10 @@ func createTarArchive(w io.Writer, outFile *os.File, sources []string, opts Arch
 				header.Linkname = link
 			}
 
+			if opts.Reproducible {
+				header = reproducibleTarHeader(header, info, mtime)
+			}
+
 			if err := tw.WriteHeader(header); err != nil {
 				return err
 			}
@@ -188,6 +202,29 @@ func createTarArchive(w io.Writer, outFile *os.File, sources []string, opts Arch
 	return nil
 }
 
+// reproducibleTarHeader returns a header for the same entry that keeps
+// only what identifies its content: the slash-separated name (with a
+// trailing slash for directories), type, size and link target. Times are
+// mtime, owners are root with no names and permissions are normalized by
+// reproducibleMode, so the header does not depend on who created the
+// archive, when, or with which umask.
+func reproducibleTarHeader(h *tar.Header, info os.FileInfo, mtime time.Time) *tar.Header {
+	name := filepath.ToSlash(h.Name)
+	if info.IsDir() && !strings.HasSuffix(name, "/") {
+		name += "/"
+	}
+
+	return &tar.Header{
+		Typeflag: h.Typeflag,
+		Name:     name,
+		Linkname: filepath.ToSlash(h.Linkname),
+		Size:     h.Size,
+		Mode:     int64(reproducibleMode(info.Mode()).Perm()),
+		ModTime:  mtime,
+		Format:   tar.FormatPAX,
+	}
+}
+
 func extractTarArchive(w io.Writer, opts ArchiveOptions) error {
 	f, err := os.Open(opts.File)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 )
@@ -33,6 +34,15 @@ func containedTarget(destDir, name string) (string, error) {
 }
 
 func createZipArchive(w io.Writer, outFile *os.File, sources []string, opts ArchiveOptions) error {
+	var mtime time.Time
+
+	if opts.Reproducible {
+		var err error
+		if mtime, err = reproducibleModTime(); err != nil {
+			return err
+		}
+	}
+
 	zw := zip.NewWriter(outFile)
 
 	defer func() {
@@ -81,6 +91,10 @@ func createZipArchive(w io.Writer, outFile *os.File, sources []string, opts Arch
 				header.Method = zip.Deflate
 			}
 
+			if opts.Reproducible {
+				header = reproducibleZipHeader(header, info, mtime)
+			}
+
 			writer, err := zw.CreateHeader(header)
 			if err != nil {
 				return err
@@ -114,6 +128,20 @@ func createZipArchive(w io.Writer, outFile *os.File, sources []string, opts Arch
 	return nil
 }
 
+// reproducibleZipHeader returns a header for the same entry with a
+// slash-separated name, mtime as its only timestamp and permissions
+// normalized by reproducibleMode; see reproducibleTarHeader.
+func reproducibleZipHeader(h *zip.FileHeader, info os.FileInfo, mtime time.Time) *zip.FileHeader {
+	out := &zip.FileHeader{
+		Name:     filepath.ToSlash(h.Name),
+		Method:   h.Method,
+		Modified: mtime,
+	}
+	out.SetMode(reproducibleMode(info.Mode()))
+
+	return out
+}
+
 func extractZipArchive(w io.Writer, opts ArchiveOptions) error {
 	r, err := zip.OpenReader(opts.File)
 	if err != nil {
@@ -3,6 +3,8 @@ package banner
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
@@ -14,6 +16,12 @@ type Options struct {
 	Font  string // -f: font name (default "standard")
 	Width int    // -w: max width (0 = unlimited)
 	List  bool   // -l: list available fonts
+
+	Output     string // -o: write to this file instead of stdout
+	Format     string // --format: text, svg or png (default: from the output extension)
+	FontSize   int    // --font-size: cell height in pixels for svg/png
+	Color      string // --color: text color for svg/png
+	Background string // --background: background color for svg/png
 }
 
 // RunBanner generates an ASCII art banner from text.
@@ -54,12 +62,109 @@ func RunBanner(w io.Writer, r io.Reader, args []string, opts Options) error {
 		renderOpts = append(renderOpts, figlet.WithWidth(opts.Width))
 	}
 
-	result, err := figlet.Render(text, renderOpts...)
+	format, err := outputFormat(opts)
+	if err != nil {
+		return err
+	}
+
+	imageOpts, err := imageOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	lines, err := figlet.RenderLines(text, renderOpts...)
 	if err != nil {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("banner: %s", err))
 	}
 
-	_, _ = fmt.Fprintln(w, result)
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("banner: %s", err))
+		}
+
+		if err := writeBanner(f, format, lines, imageOpts); err != nil {
+			_ = f.Close()
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("banner: write %s: %s", opts.Output, err))
+		}
+
+		if err := f.Close(); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("banner: %s", err))
+		}
+
+		return nil
+	}
+
+	if err := writeBanner(w, format, lines, imageOpts); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("banner: %s", err))
+	}
 
 	return nil
 }
+
+// outputFormat returns the requested format, falling back to the output
+// file extension and then to text.
+func outputFormat(opts Options) (string, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(opts.Output)) {
+		case ".svg":
+			format = "svg"
+		case ".png":
+			format = "png"
+		default:
+			format = "text"
+		}
+	}
+
+	switch format {
+	case "text", "svg", "png":
+		return format, nil
+	default:
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("banner: unknown format %q (want text, svg or png)", opts.Format))
+	}
+}
+
+func imageOptions(opts Options) ([]figlet.ImageOption, error) {
+	var imageOpts []figlet.ImageOption
+
+	if opts.FontSize < 0 {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "banner: --font-size must be positive")
+	}
+
+	if opts.FontSize > 0 {
+		imageOpts = append(imageOpts, figlet.WithFontSize(opts.FontSize))
+	}
+
+	if opts.Color != "" {
+		c, err := figlet.ParseColor(opts.Color)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("banner: --color: %s", err))
+		}
+
+		imageOpts = append(imageOpts, figlet.WithColor(c))
+	}
+
+	if opts.Background != "" {
+		c, err := figlet.ParseColor(opts.Background)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("banner: --background: %s", err))
+		}
+
+		imageOpts = append(imageOpts, figlet.WithBackground(c))
+	}
+
+	return imageOpts, nil
+}
+
+func writeBanner(w io.Writer, format string, lines []string, imageOpts []figlet.ImageOption) error {
+	switch format {
+	case "svg":
+		return figlet.WriteSVG(w, lines, imageOpts...)
+	case "png":
+		return figlet.WritePNG(w, lines, imageOpts...)
+	default:
+		_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+		return err
+	}
+}
@@ -2,6 +2,8 @@ package banner
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -116,3 +118,53 @@ func TestRunBannerWidth(t *testing.T) {
 		}
 	}
 }
+
+func TestRunBannerImage(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("svg from extension", func(t *testing.T) {
+		path := filepath.Join(dir, "banner.svg")
+
+		var buf bytes.Buffer
+
+		err := RunBanner(&buf, strings.NewReader(""), []string{"RELEASE"}, Options{Output: path, Color: "#0366d6"})
+		if err != nil {
+			t.Fatalf("RunBanner() error = %v", err)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("stdout should be empty when writing a file, got %q", buf.String())
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(string(data), "<svg") || !strings.Contains(string(data), "#0366d6") {
+			t.Errorf("unexpected SVG:\n%s", data)
+		}
+	})
+
+	t.Run("png to stdout", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunBanner(&buf, strings.NewReader(""), []string{"Hi"}, Options{Format: "png", FontSize: 8})
+		if err != nil {
+			t.Fatalf("RunBanner() error = %v", err)
+		}
+
+		if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")) {
+			t.Error("output is not a PNG")
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, opts := range []Options{{Format: "gif"}, {Format: "svg", Color: "nope"}, {Format: "png", FontSize: -1}} {
+			var buf bytes.Buffer
+			if err := RunBanner(&buf, strings.NewReader(""), []string{"x"}, opts); err == nil {
+				t.Errorf("RunBanner(%+v) should fail", opts)
+			}
+		}
+	})
+}
@@ -16,6 +16,7 @@ type BaseOptions struct {
 	Decode        bool // -d: decode data
 	Wrap          int  // -w: wrap encoded lines after N characters (0 = no wrap)
 	IgnoreGarbage bool // -i: ignore non-alphabet characters when decoding
+	MIME          bool // --mime: stream base64 in 76-column CRLF lines (RFC 2045)
 }
 
 // RunBase64 encodes or decodes base64 data
@@ -43,6 +44,10 @@ func RunBase64(w io.Writer, args []string, opts BaseOptions) error {
 		input = f
 	}
 
+	if opts.MIME {
+		return streamBase64MIME(w, input, opts.Decode)
+	}
+
 	data, err := io.ReadAll(input)
 	if err != nil {
 		return fmt.Errorf("base64: %w", err)
@@ -67,6 +72,26 @@ func RunBase64(w io.Writer, args []string, opts BaseOptions) error {
 	return nil
 }
 
+// streamBase64MIME encodes or decodes without buffering the whole input,
+// as MIME bodies are often large attachments.
+func streamBase64MIME(w io.Writer, input io.Reader, decode bool) error {
+	if decode {
+		if _, err := io.Copy(w, pkgenc.NewBase64Decoder(input)); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("base64: %s", err))
+		}
+
+		return nil
+	}
+
+	enc := pkgenc.NewBase64Encoder(w, pkgenc.MIMELineLength, "\r\n")
+
+	if _, err := io.Copy(enc, input); err != nil {
+		return fmt.Errorf("base64: %w", err)
+	}
+
+	return enc.Close()
+}
+
 // RunBase32 encodes or decodes base32 data
 func RunBase32(w io.Writer, args []string, opts BaseOptions) error {
 	if opts.Wrap == 0 {
@@ -547,3 +547,37 @@ func TestWrapStringExtended(t *testing.T) {
 		}
 	})
 }
+
+func TestRunBase64MIME(t *testing.T) {
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte{0x00, 0x7f, 0xff, 'a'}, 100)
+
+	src := filepath.Join(dir, "attachment.bin")
+	if err := os.WriteFile(src, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var enc bytes.Buffer
+	if err := RunBase64(&enc, []string{src}, BaseOptions{MIME: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(enc.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 || len(lines[0]) != pkgenc.MIMELineLength {
+		t.Fatalf("encoded lines = %q", lines)
+	}
+
+	encoded := filepath.Join(dir, "attachment.b64")
+	if err := os.WriteFile(encoded, enc.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var dec bytes.Buffer
+	if err := RunBase64(&dec, []string{encoded}, BaseOptions{MIME: true, Decode: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(dec.Bytes(), data) {
+		t.Error("MIME round trip mismatch")
+	}
+}
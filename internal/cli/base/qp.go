@@ -0,0 +1,47 @@
+package base
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	pkgenc "github.com/inovacc/omni/pkg/encoding"
+)
+
+// QPOptions configures the qp (quoted-printable) command
+type QPOptions struct {
+	Decode bool // -d: decode data
+	Binary bool // -b: encode line breaks too, for binary input
+}
+
+// RunQP streams FILE (or stdin) through a quoted-printable encoder or
+// decoder.
+func RunQP(w io.Writer, args []string, opts QPOptions) error {
+	src := "-"
+	if len(args) > 0 {
+		src = args[0]
+	}
+
+	in, err := openInput("qp", src)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = in.Close() }()
+
+	if opts.Decode {
+		if _, err := io.Copy(w, pkgenc.NewQPDecoder(in)); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("qp: %s", err))
+		}
+
+		return nil
+	}
+
+	enc := pkgenc.NewQPEncoder(w, opts.Binary)
+
+	if _, err := io.Copy(enc, in); err != nil {
+		return fmt.Errorf("qp: %w", err)
+	}
+
+	return enc.Close()
+}
@@ -0,0 +1,41 @@
+package base
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunQP(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "body.txt")
+
+	if err := os.WriteFile(src, []byte("Grüße = "+strings.Repeat("x", 80)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var enc bytes.Buffer
+	if err := RunQP(&enc, []string{src}, QPOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(enc.String(), "Gr=C3=BC=C3=9Fe =3D ") || !strings.Contains(enc.String(), "=\r\n") {
+		t.Errorf("encoded = %q", enc.String())
+	}
+
+	encoded := filepath.Join(dir, "body.qp")
+	if err := os.WriteFile(encoded, enc.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var dec bytes.Buffer
+	if err := RunQP(&dec, []string{encoded}, QPOptions{Decode: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if dec.String() != "Grüße = "+strings.Repeat("x", 80) {
+		t.Errorf("decoded = %q", dec.String())
+	}
+}
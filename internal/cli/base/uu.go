@@ -0,0 +1,179 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	pkgenc "github.com/inovacc/omni/pkg/encoding"
+)
+
+// defaultUUMode is recorded for standard input, which has no file mode.
+const defaultUUMode fs.FileMode = 0o644
+
+// UUOptions configures the uuencode and uudecode commands
+type UUOptions struct {
+	Base64 bool   // -m: use the base64 form ("begin-base64")
+	Output string // -o: uudecode output file ("-" = stdout), overriding the encoded name
+}
+
+// RunUUEncode streams FILE (or stdin) to w in uuencode format, recording
+// NAME as the file name. args is [FILE] NAME.
+func RunUUEncode(w io.Writer, args []string, opts UUOptions) error {
+	var src, name string
+
+	switch len(args) {
+	case 1:
+		src, name = "-", args[0]
+	case 2:
+		src, name = args[0], args[1]
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "uuencode: usage: uuencode [-m] [FILE] NAME")
+	}
+
+	in, err := openInput("uuencode", src)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = in.Close() }()
+
+	mode := defaultUUMode
+	if f, ok := in.(*os.File); ok {
+		if info, err := f.Stat(); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	enc := pkgenc.NewUUEncoder(w, pkgenc.UUHeader{Name: name, Mode: mode, Base64: opts.Base64})
+
+	if _, err := io.Copy(enc, in); err != nil {
+		return fmt.Errorf("uuencode: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// RunUUDecode decodes each FILE (or stdin). The content is written to the
+// file named in its begin line (directory parts are dropped, so input
+// cannot write outside the current directory) with the recorded mode,
+// or to -o. A file named /dev/stdout or -o - goes to w.
+func RunUUDecode(w io.Writer, args []string, opts UUOptions) error {
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	for _, src := range args {
+		if err := uudecodeOne(w, src, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func uudecodeOne(w io.Writer, src string, opts UUOptions) error {
+	in, err := openInput("uudecode", src)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = in.Close() }()
+
+	dec, err := pkgenc.NewUUDecoder(in)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("uudecode: %s: %s", displayName(src), errMessage(err)))
+	}
+
+	dest := opts.Output
+	if dest == "" {
+		dest = dec.Header.Name
+		if dest != "/dev/stdout" && dest != "-" {
+			dest = filepath.Base(filepath.Clean(dest))
+			if dest == "." || dest == ".." || dest == string(filepath.Separator) {
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("uudecode: %s: invalid file name %q", displayName(src), dec.Header.Name))
+			}
+		}
+	}
+
+	if dest == "-" || dest == "/dev/stdout" {
+		if _, err := io.Copy(w, dec); err != nil {
+			return decodeError(src, err)
+		}
+
+		return nil
+	}
+
+	// Decode to a temporary file so a truncated input never replaces dest
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".uu-*")
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("uudecode: %s", err))
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = io.Copy(tmp, dec)
+	if cerr := tmp.Close(); err == nil && cerr != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("uudecode: %s", cerr))
+	}
+
+	if err != nil {
+		return decodeError(src, err)
+	}
+
+	if err := os.Chmod(tmp.Name(), dec.Header.Mode); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("uudecode: %s", err))
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("uudecode: %s", err))
+	}
+
+	return nil
+}
+
+func decodeError(src string, err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("uudecode: %s", err))
+	}
+
+	return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("uudecode: %s: %s", displayName(src), errMessage(err)))
+}
+
+// errMessage drops the "uudecode: " prefix pkg/encoding errors carry, as
+// the command adds its own.
+func errMessage(err error) string {
+	return strings.TrimPrefix(err.Error(), "uudecode: ")
+}
+
+func displayName(src string) string {
+	if src == "-" {
+		return "standard input"
+	}
+
+	return src
+}
+
+// openInput opens FILE, or stdin for "-".
+func openInput(cmd, name string) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("%s: %s", cmd, err))
+		}
+
+		return nil, fmt.Errorf("%s: %w", cmd, err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestRunUUEncodeDecode(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	data := bytes.Repeat([]byte("binary\x00\xff data "), 20)
+	if err := os.WriteFile("in.bin", data, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b64 := range []bool{false, true} {
+		var enc bytes.Buffer
+		if err := RunUUEncode(&enc, []string{"in.bin", "../sub/out.bin"}, UUOptions{Base64: b64}); err != nil {
+			t.Fatal(err)
+		}
+
+		wantBegin := "begin 640 ../sub/out.bin\n"
+		if b64 {
+			wantBegin = "begin-base64 640 ../sub/out.bin\n"
+		}
+
+		if !strings.HasPrefix(enc.String(), wantBegin) {
+			t.Fatalf("encoded = %q", enc.String())
+		}
+
+		if err := os.WriteFile("msg.uu", enc.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		// The directory part of the recorded name is dropped
+		if err := RunUUDecode(&bytes.Buffer{}, []string{"msg.uu"}, UUOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := os.ReadFile(filepath.Join(dir, "out.bin"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, data) {
+			t.Errorf("base64=%v: round trip mismatch", b64)
+		}
+
+		if info, _ := os.Stat("out.bin"); info.Mode().Perm() != 0o640 {
+			t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+		}
+
+		var out bytes.Buffer
+		if err := RunUUDecode(&out, []string{"msg.uu"}, UUOptions{Output: "-"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(out.Bytes(), data) {
+			t.Errorf("base64=%v: -o - output mismatch", b64)
+		}
+
+		_ = os.Remove("out.bin")
+	}
+}
+
+func TestRunUUDecodeErrors(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	files := map[string]string{
+		"plain.txt": "no uuencoded data here\n",
+		"cut.uu":    "begin 644 cut.bin\n#0V%T\n",
+		"dots.uu":   "begin 644 ..\n`\nend\n",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		err := RunUUDecode(&bytes.Buffer{}, []string{name}, UUOptions{})
+		if !errors.Is(err, cmderr.ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want invalid input", name, err)
+		}
+	}
+
+	// A truncated input leaves nothing behind
+	if _, err := os.Stat("cut.bin"); !os.IsNotExist(err) {
+		t.Error("truncated decode created cut.bin")
+	}
+
+	if err := RunUUEncode(&bytes.Buffer{}, nil, UUOptions{}); !errors.Is(err, cmderr.ErrInvalidInput) {
+		t.Errorf("uuencode without NAME: err = %v", err)
+	}
+
+	if err := RunUUEncode(&bytes.Buffer{}, []string{"missing", "x"}, UUOptions{}); !errors.Is(err, cmderr.ErrNotFound) {
+		t.Errorf("uuencode missing file: err = %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+// Package bench runs an omni subcommand repeatedly over the same in-memory
+// input and reports latency percentiles and throughput, optionally compared
+// against a saved baseline.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/du"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// RunFunc runs the benchmarked command once, reading stdin and writing
+// stdout.
+type RunFunc func(ctx context.Context, stdin io.Reader, stdout io.Writer) error
+
+// Options configures the bench command
+type Options struct {
+	Iterations   int           // --iterations: measured runs (default 10)
+	Warmup       int           // --warmup: unmeasured runs before timing
+	Input        string        // --input: file buffered as stdin ("-" = stdin)
+	Baseline     string        // --baseline: previous result to compare against
+	Save         string        // --save: write the result as a new baseline
+	MaxRegress   float64       // --max-regress: fail when mean regresses more than this percent
+	OutputFormat output.Format // output format
+}
+
+// Result is one benchmark run. Durations are in nanoseconds.
+type Result struct {
+	Command     []string   `json:"command"`
+	Iterations  int        `json:"iterations"`
+	InputBytes  int64      `json:"input_bytes"`
+	OutputBytes int64      `json:"output_bytes"`
+	Min         int64      `json:"min_ns"`
+	Mean        int64      `json:"mean_ns"`
+	P95         int64      `json:"p95_ns"`
+	Max         int64      `json:"max_ns"`
+	Throughput  float64    `json:"throughput_bytes_per_sec,omitempty"`
+	Baseline    *Deviation `json:"baseline,omitempty"`
+}
+
+// Deviation compares a result with its baseline
+type Deviation struct {
+	Mean        int64   `json:"mean_ns"`
+	P95         int64   `json:"p95_ns"`
+	MeanPercent float64 `json:"mean_change_percent"`
+	P95Percent  float64 `json:"p95_change_percent"`
+	Regressed   bool    `json:"regressed"`
+}
+
+// Run benchmarks command through run and prints the report. stdin is read
+// only when opts.Input is "-".
+func Run(ctx context.Context, w io.Writer, stdin io.Reader, command []string, run RunFunc, opts Options) error {
+	if opts.Iterations == 0 {
+		opts.Iterations = 10
+	}
+
+	if opts.Iterations < 0 || opts.Warmup < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "bench: iterations and warmup must not be negative")
+	}
+
+	data, err := loadInput(opts.Input, stdin)
+	if err != nil {
+		return err
+	}
+
+	var baseline *Result
+	if opts.Baseline != "" {
+		if baseline, err = loadBaseline(opts.Baseline); err != nil {
+			return err
+		}
+	}
+
+	input := bytes.NewReader(data)
+	out := &countingWriter{}
+
+	for range opts.Warmup {
+		input.Reset(data)
+
+		if err := runOnce(ctx, run, input, io.Discard); err != nil {
+			return err
+		}
+	}
+
+	samples := make([]time.Duration, opts.Iterations)
+
+	for i := range samples {
+		input.Reset(data)
+		out.n = 0
+
+		start := time.Now()
+		err := runOnce(ctx, run, input, out)
+		samples[i] = time.Since(start)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	result := summarize(command, samples, int64(len(data)), out.n)
+
+	if baseline != nil {
+		result.Baseline = compare(result, baseline, opts.MaxRegress)
+	}
+
+	if opts.Save != "" {
+		if err := saveResult(opts.Save, result); err != nil {
+			return err
+		}
+	}
+
+	if err := printResult(w, result, opts); err != nil {
+		return err
+	}
+
+	if result.Baseline != nil && result.Baseline.Regressed {
+		return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("bench: mean regressed %.1f%% (limit %.1f%%)",
+			result.Baseline.MeanPercent, opts.MaxRegress))
+	}
+
+	return nil
+}
+
+// runOnce runs the command, treating a silent exit (grep with no match)
+// as a completed run.
+func runOnce(ctx context.Context, run RunFunc, stdin io.Reader, stdout io.Writer) error {
+	err := run(ctx, stdin, stdout)
+
+	var silent *cmderr.SilentError
+	if errors.As(err, &silent) && silent.Err == nil {
+		return nil
+	}
+
+	return err
+}
+
+func loadInput(name string, stdin io.Reader) ([]byte, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "-":
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("bench: read stdin: %s", err))
+		}
+
+		return data, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fileError(err)
+	}
+
+	return data, nil
+}
+
+func loadBaseline(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fileError(err)
+	}
+
+	var r Result
+	if err := json.Unmarshal(data, &r); err != nil || r.Mean <= 0 {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("bench: %s is not a bench result", path))
+	}
+
+	return &r, nil
+}
+
+func saveResult(path string, r Result) error {
+	// A baseline is the measurement itself, not a comparison against an
+	// older one.
+	r.Baseline = nil
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("bench: %s", err))
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fileError(err)
+	}
+
+	return nil
+}
+
+func fileError(err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("bench: %s", err))
+	case errors.Is(err, os.ErrPermission):
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("bench: %s", err))
+	default:
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("bench: %s", err))
+	}
+}
+
+func summarize(command []string, samples []time.Duration, inBytes, outBytes int64) Result {
+	r := Result{
+		Command:     command,
+		Iterations:  len(samples),
+		InputBytes:  inBytes,
+		OutputBytes: outBytes,
+	}
+
+	if len(samples) == 0 {
+		return r
+	}
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	mean := total / time.Duration(len(sorted))
+
+	r.Min = int64(sorted[0])
+	r.Max = int64(sorted[len(sorted)-1])
+	r.Mean = int64(mean)
+	r.P95 = int64(percentile(sorted, 95))
+
+	if inBytes > 0 && mean > 0 {
+		r.Throughput = float64(inBytes) / mean.Seconds()
+	}
+
+	return r
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	return sorted[max(rank-1, 0)]
+}
+
+func compare(r Result, base *Result, maxRegress float64) *Deviation {
+	d := &Deviation{
+		Mean:        base.Mean,
+		P95:         base.P95,
+		MeanPercent: change(r.Mean, base.Mean),
+		P95Percent:  change(r.P95, base.P95),
+	}
+
+	d.Regressed = maxRegress > 0 && d.MeanPercent > maxRegress
+
+	return d
+}
+
+func change(now, before int64) float64 {
+	if before <= 0 {
+		return 0
+	}
+
+	return (float64(now) - float64(before)) / float64(before) * 100
+}
+
+func printResult(w io.Writer, r Result, opts Options) error {
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(r)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "command     %s\n", strings.Join(r.Command, " "))
+	fmt.Fprintf(&b, "iterations  %d (warmup %d)\n", r.Iterations, opts.Warmup)
+	fmt.Fprintf(&b, "input       %s\n", du.FormatHumanSize(r.InputBytes))
+	fmt.Fprintf(&b, "output      %s\n", du.FormatHumanSize(r.OutputBytes))
+	fmt.Fprintf(&b, "min         %s\n", time.Duration(r.Min))
+	fmt.Fprintf(&b, "mean        %s\n", time.Duration(r.Mean))
+	fmt.Fprintf(&b, "p95         %s\n", time.Duration(r.P95))
+	fmt.Fprintf(&b, "max         %s\n", time.Duration(r.Max))
+
+	if r.Throughput > 0 {
+		fmt.Fprintf(&b, "throughput  %s/s\n", du.FormatHumanSize(int64(r.Throughput)))
+	}
+
+	if d := r.Baseline; d != nil {
+		fmt.Fprintf(&b, "baseline    mean %s -> %s (%+.1f%%), p95 %s -> %s (%+.1f%%)\n",
+			time.Duration(d.Mean), time.Duration(r.Mean), d.MeanPercent,
+			time.Duration(d.P95), time.Duration(r.P95), d.P95Percent)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("bench: write: %s", err))
+	}
+
+	return nil
+}
+
+// countingWriter discards what the command writes, keeping only the size.
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
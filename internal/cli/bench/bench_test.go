@@ -0,0 +1,131 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// upper is a RunFunc that uppercases stdin, like a tiny pipeline stage.
+func upper(_ context.Context, stdin io.Reader, stdout io.Writer) error {
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+
+	_, err = stdout.Write(bytes.ToUpper(data))
+
+	return err
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.txt")
+	_ = os.WriteFile(input, []byte("hello world\n"), 0o644)
+
+	calls := 0
+	run := func(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+		calls++
+		return upper(ctx, stdin, stdout)
+	}
+
+	var buf bytes.Buffer
+
+	opts := Options{Iterations: 4, Warmup: 2, Input: input, Save: filepath.Join(dir, "base.json"), OutputFormat: output.FormatJSON}
+	if err := Run(context.Background(), &buf, nil, []string{"upper"}, run, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 6 {
+		t.Errorf("run called %d times, want 6 (2 warmup + 4 measured)", calls)
+	}
+
+	var r Result
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	// Every iteration must see the whole input again.
+	if r.Iterations != 4 || r.InputBytes != 12 || r.OutputBytes != 12 {
+		t.Errorf("result = %+v", r)
+	}
+
+	if r.Min > r.Mean || r.Mean > r.Max || r.P95 > r.Max {
+		t.Errorf("inconsistent latencies: %+v", r)
+	}
+
+	if _, err := os.Stat(opts.Save); err != nil {
+		t.Errorf("baseline not saved: %v", err)
+	}
+}
+
+func TestRunBaseline(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+
+	// A baseline of 1ns: any real run is a large regression.
+	data, _ := json.Marshal(Result{Command: []string{"upper"}, Iterations: 1, Mean: 1, P95: 1})
+	_ = os.WriteFile(base, data, 0o644)
+
+	var buf bytes.Buffer
+
+	err := Run(context.Background(), &buf, strings.NewReader("abc"), []string{"upper"}, upper,
+		Options{Iterations: 2, Input: "-", Baseline: base})
+	if err != nil {
+		t.Fatalf("without --max-regress the comparison is informational, got %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "baseline    mean 1ns ->") {
+		t.Errorf("missing baseline line:\n%s", buf.String())
+	}
+
+	err = Run(context.Background(), io.Discard, strings.NewReader("abc"), []string{"upper"}, upper,
+		Options{Iterations: 2, Input: "-", Baseline: base, MaxRegress: 10})
+	if !cmderr.IsConflict(err) {
+		t.Errorf("regression: err = %v, want conflict", err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	noMatch := func(context.Context, io.Reader, io.Writer) error { return cmderr.SilentExit(1) }
+	if err := Run(context.Background(), io.Discard, nil, []string{"grep"}, noMatch, Options{Iterations: 2}); err != nil {
+		t.Errorf("silent exit should count as a run, got %v", err)
+	}
+
+	failing := func(context.Context, io.Reader, io.Writer) error { return cmderr.Wrap(cmderr.ErrIO, "boom") }
+	if err := Run(context.Background(), io.Discard, nil, []string{"x"}, failing, Options{}); !cmderr.IsIO(err) {
+		t.Errorf("failing command: err = %v, want its error", err)
+	}
+
+	if err := Run(context.Background(), io.Discard, nil, []string{"x"}, upper, Options{Input: "/nonexistent/in"}); !cmderr.IsNotFound(err) {
+		t.Errorf("missing input: err = %v, want not found", err)
+	}
+
+	if err := Run(context.Background(), io.Discard, nil, []string{"x"}, upper, Options{Iterations: -1}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("negative iterations: err = %v, want invalid input", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 20)
+	for i := range samples {
+		samples[i] = time.Duration(i + 1)
+	}
+
+	if got := percentile(samples, 95); got != 19 {
+		t.Errorf("p95 of 1..20 = %d, want 19", got)
+	}
+
+	if got := percentile(samples[:1], 95); got != 1 {
+		t.Errorf("p95 of one sample = %d, want 1", got)
+	}
+}
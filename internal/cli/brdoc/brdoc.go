@@ -10,6 +10,7 @@ import (
 
 	"github.com/inovacc/brdoc"
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/i18n"
 )
 
 // Options configures brdoc command behavior
@@ -18,8 +19,9 @@ type Options struct {
 	Validate bool // Validate a document
 	Format   bool // Format a document
 	Count    int  // Number of documents to generate
-	Legacy   bool // Use legacy numeric-only CNPJ format
-	JSON     bool // Output as JSON
+	Legacy   bool   // Use legacy numeric-only CNPJ format
+	State    string // Generate CPFs for this UF's fiscal region
+	JSON     bool   // Output as JSON
 }
 
 // CPFResult represents CPF operation result
@@ -103,7 +105,11 @@ func generateCPF(w io.Writer, opts Options) error {
 	if opts.JSON {
 		result := CPFListResult{Count: count}
 		for i := 0; i < count; i++ {
-			cpf := cpfHandler.Generate()
+			cpf, err := nextCPF(opts)
+			if err != nil {
+				return err
+			}
+
 			formatted, _ := cpfHandler.Format(cpf)
 			state := cpfHandler.CheckOrigin(cpf)
 			result.CPFs = append(result.CPFs, CPFResult{
@@ -116,7 +122,11 @@ func generateCPF(w io.Writer, opts Options) error {
 	}
 
 	for i := 0; i < count; i++ {
-		cpf := cpfHandler.Generate()
+		cpf, err := nextCPF(opts)
+		if err != nil {
+			return err
+		}
+
 		formatted, _ := cpfHandler.Format(cpf)
 		_, _ = fmt.Fprintln(w, formatted)
 	}
@@ -124,9 +134,18 @@ func generateCPF(w io.Writer, opts Options) error {
 	return nil
 }
 
+// nextCPF generates one CPF, constrained to opts.State when set
+func nextCPF(opts Options) (string, error) {
+	if opts.State != "" {
+		return GenerateCPFForState(opts.State)
+	}
+
+	return cpfHandler.Generate(), nil
+}
+
 func validateCPF(w io.Writer, args []string, opts Options) error {
 	if len(args) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "cpf: no document provided")
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cpf.no_document"))
 	}
 
 	if opts.JSON {
@@ -157,15 +176,15 @@ func validateCPF(w io.Writer, args []string, opts Options) error {
 	for _, arg := range args {
 		if cpfHandler.Validate(arg) {
 			state := cpfHandler.CheckOrigin(arg)
-			_, _ = fmt.Fprintf(w, "%s: valid (state: %s)\n", arg, state)
+			_, _ = fmt.Fprintln(w, i18n.T("brdoc.valid_state", arg, state))
 		} else {
-			_, _ = fmt.Fprintf(w, "%s: invalid\n", arg)
+			_, _ = fmt.Fprintln(w, i18n.T("brdoc.invalid", arg))
 			allValid = false
 		}
 	}
 
 	if !allValid {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "cpf: one or more CPFs are invalid")
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cpf.some_invalid"))
 	}
 
 	return nil
@@ -173,7 +192,7 @@ func validateCPF(w io.Writer, args []string, opts Options) error {
 
 func formatCPF(w io.Writer, args []string, opts Options) error {
 	if len(args) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "cpf: no document provided")
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cpf.no_document"))
 	}
 
 	if opts.JSON {
@@ -243,7 +262,7 @@ func generateCNPJ(w io.Writer, opts Options) error {
 
 func validateCNPJ(w io.Writer, args []string, opts Options) error {
 	if len(args) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "cnpj: no document provided")
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cnpj.no_document"))
 	}
 
 	if opts.JSON {
@@ -272,15 +291,15 @@ func validateCNPJ(w io.Writer, args []string, opts Options) error {
 
 	for _, arg := range args {
 		if cnpjHandler.Validate(arg) {
-			_, _ = fmt.Fprintf(w, "%s: valid\n", arg)
+			_, _ = fmt.Fprintln(w, i18n.T("brdoc.valid", arg))
 		} else {
-			_, _ = fmt.Fprintf(w, "%s: invalid\n", arg)
+			_, _ = fmt.Fprintln(w, i18n.T("brdoc.invalid", arg))
 			allValid = false
 		}
 	}
 
 	if !allValid {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "cnpj: one or more CNPJs are invalid")
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cnpj.some_invalid"))
 	}
 
 	return nil
@@ -288,7 +307,7 @@ func validateCNPJ(w io.Writer, args []string, opts Options) error {
 
 func formatCNPJ(w io.Writer, args []string, opts Options) error {
 	if len(args) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "cnpj: no document provided")
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cnpj.no_document"))
 	}
 
 	if opts.JSON {
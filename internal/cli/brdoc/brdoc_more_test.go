@@ -193,3 +193,63 @@ func TestFormatRoundTrips(t *testing.T) {
 		t.Errorf("FormatCNPJ(%q) returned empty", cnpj)
 	}
 }
+
+// TestGenerateCPFForState checks the ninth digit tracks the UF's fiscal region.
+func TestGenerateCPFForState(t *testing.T) {
+	tests := []struct {
+		uf     string
+		region byte
+	}{
+		{"SP", '8'},
+		{"rs", '0'},
+		{"MT", '1'},
+		{"RJ", '7'},
+		{"SC", '9'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uf, func(t *testing.T) {
+			for range 20 {
+				cpf, err := GenerateCPFForState(tt.uf)
+				if err != nil {
+					t.Fatalf("GenerateCPFForState(%s) error = %v", tt.uf, err)
+				}
+
+				if !ValidateCPF(cpf) {
+					t.Fatalf("generated invalid CPF %s", cpf)
+				}
+
+				if cpf[8] != tt.region {
+					t.Fatalf("CPF %s ninth digit = %c, want %c", cpf, cpf[8], tt.region)
+				}
+			}
+		})
+	}
+
+	if _, err := GenerateCPFForState("XX"); err == nil {
+		t.Error("expected error for unknown state")
+	}
+}
+
+// TestRunCPFGenerateState covers --state through RunCPF.
+func TestRunCPFGenerateState(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunCPF(&buf, nil, Options{Generate: true, Count: 3, State: "SP", JSON: true}); err != nil {
+		t.Fatalf("RunCPF() error = %v", err)
+	}
+
+	var result CPFListResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	for _, c := range result.CPFs {
+		if c.State != CPFState("00000000800") {
+			t.Errorf("CPF %s state = %q, want São Paulo region", c.CPF, c.State)
+		}
+	}
+
+	if err := RunCPF(&buf, nil, Options{Generate: true, State: "ZZ"}); err == nil {
+		t.Error("expected error for unknown state")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/i18n"
 )
 
 func TestGenerateCPF(t *testing.T) {
@@ -384,3 +386,22 @@ func TestCNPJUniqueness(t *testing.T) {
 		seen[cnpj] = true
 	}
 }
+
+func TestValidateCPFLocalized(t *testing.T) {
+	if err := i18n.SetLang("pt-BR"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = i18n.SetLang(i18n.Default) })
+
+	var buf bytes.Buffer
+
+	err := RunCPF(&buf, []string{"11111111111"}, Options{Validate: true})
+	if err == nil || !strings.Contains(err.Error(), "um ou mais CPFs são inválidos") {
+		t.Errorf("error = %v, want the Portuguese message", err)
+	}
+
+	if got := buf.String(); got != "11111111111: inválido\n" {
+		t.Errorf("output = %q", got)
+	}
+}
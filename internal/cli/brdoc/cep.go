@@ -0,0 +1,355 @@
+package brdoc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/i18n"
+)
+
+const (
+	// defaultViaCEPURL is the public ViaCEP endpoint; "{cep}" is replaced by
+	// the 8 cleaned digits.
+	defaultViaCEPURL = "https://viacep.com.br/ws/{cep}/json/"
+	// cepLookupTimeout bounds each provider request so a hung API does not
+	// block the CLI.
+	cepLookupTimeout = 15 * time.Second
+)
+
+// CEPOptions configures the cep subcommand
+type CEPOptions struct {
+	Lookup   bool        // Query the provider for the address
+	Format   bool        // Format CEP(s) as XXXXX-XXX
+	JSON     bool        // Output as JSON
+	Provider CEPProvider // Lookup provider (default: ViaCEP)
+}
+
+// CEPAddress is the address returned by a CEP lookup
+type CEPAddress struct {
+	CEP          string `json:"cep"`
+	Street       string `json:"street,omitempty"`
+	Complement   string `json:"complement,omitempty"`
+	Neighborhood string `json:"neighborhood,omitempty"`
+	City         string `json:"city,omitempty"`
+	State        string `json:"state,omitempty"`
+	IBGE         string `json:"ibge,omitempty"`
+	DDD          string `json:"ddd,omitempty"`
+}
+
+// CEPResult represents a CEP operation result
+type CEPResult struct {
+	CEP     string      `json:"cep"`
+	Valid   bool        `json:"valid"`
+	Address *CEPAddress `json:"address,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// CEPListResult represents multiple CEP results
+type CEPListResult struct {
+	Count int         `json:"count"`
+	CEPs  []CEPResult `json:"ceps"`
+}
+
+// CEPProvider resolves a CEP to an address. Implementations receive the
+// cleaned 8-digit CEP.
+type CEPProvider interface {
+	Lookup(ctx context.Context, cep string) (*CEPAddress, error)
+}
+
+// HTTPDoer is the subset of *http.Client used by ViaCEPProvider, so tests
+// can inject a fake transport.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ViaCEPProvider looks up CEPs against a ViaCEP-compatible JSON API.
+type ViaCEPProvider struct {
+	// URL is the endpoint template; "{cep}" is replaced by the CEP digits.
+	URL string
+	// Client performs the HTTP request (default: http.Client with timeout).
+	Client HTTPDoer
+}
+
+// NewViaCEPProvider returns a provider for the public ViaCEP API
+func NewViaCEPProvider() *ViaCEPProvider {
+	return &ViaCEPProvider{
+		URL:    defaultViaCEPURL,
+		Client: &http.Client{Timeout: cepLookupTimeout},
+	}
+}
+
+// viaCEPResponse mirrors the ViaCEP JSON payload. ViaCEP reports unknown
+// CEPs with HTTP 200 and {"erro": true} (older deployments used the string
+// "true"), so Erro is decoded loosely.
+type viaCEPResponse struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Localidade  string `json:"localidade"`
+	UF          string `json:"uf"`
+	IBGE        string `json:"ibge"`
+	DDD         string `json:"ddd"`
+	Erro        any    `json:"erro"`
+}
+
+// Lookup queries the provider for the given CEP
+func (p *ViaCEPProvider) Lookup(ctx context.Context, cep string) (*CEPAddress, error) {
+	endpoint := p.URL
+	if endpoint == "" {
+		endpoint = defaultViaCEPURL
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: cepLookupTimeout}
+	}
+
+	u := strings.ReplaceAll(endpoint, "{cep}", cep)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("cep: %v", err))
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("cep: lookup %s: %v", cep, err))
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, cmderr.Wrap(cmderr.ErrNotFound, i18n.T("cep.not_found", cep))
+	case resp.StatusCode == http.StatusBadRequest:
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("cep: %s rejected by provider", cep))
+	case resp.StatusCode != http.StatusOK:
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("cep: lookup %s: HTTP %d", cep, resp.StatusCode))
+	}
+
+	var body viaCEPResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("cep: decode response: %v", err))
+	}
+
+	if isViaCEPError(body.Erro) {
+		return nil, cmderr.Wrap(cmderr.ErrNotFound, i18n.T("cep.not_found", cep))
+	}
+
+	return &CEPAddress{
+		CEP:          FormatCEP(cep),
+		Street:       body.Logradouro,
+		Complement:   body.Complemento,
+		Neighborhood: body.Bairro,
+		City:         body.Localidade,
+		State:        body.UF,
+		IBGE:         body.IBGE,
+		DDD:          body.DDD,
+	}, nil
+}
+
+func isViaCEPError(v any) bool {
+	switch e := v.(type) {
+	case bool:
+		return e
+	case string:
+		return e == "true"
+	default:
+		return false
+	}
+}
+
+// RunCEP executes CEP operations. Without --lookup or --format it validates.
+func RunCEP(ctx context.Context, w io.Writer, args []string, opts CEPOptions) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cep.no_document"))
+	}
+
+	if opts.Lookup {
+		return lookupCEP(ctx, w, args, opts)
+	}
+
+	if opts.Format {
+		return formatCEP(w, args, opts)
+	}
+
+	return validateCEP(w, args, opts)
+}
+
+func validateCEP(w io.Writer, args []string, opts CEPOptions) error {
+	allValid := true
+
+	var results []CEPResult
+
+	for _, arg := range args {
+		result := CEPResult{CEP: arg, Valid: ValidateCEP(arg)}
+		if !result.Valid {
+			result.Error = "invalid CEP"
+			allValid = false
+		}
+
+		results = append(results, result)
+	}
+
+	if opts.JSON {
+		if err := encodeCEPResults(w, results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Valid {
+				_, _ = fmt.Fprintln(w, i18n.T("brdoc.valid", r.CEP))
+			} else {
+				_, _ = fmt.Fprintln(w, i18n.T("brdoc.invalid", r.CEP))
+			}
+		}
+	}
+
+	if !allValid {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cep.some_invalid"))
+	}
+
+	return nil
+}
+
+func formatCEP(w io.Writer, args []string, opts CEPOptions) error {
+	var results []CEPResult
+
+	for _, arg := range args {
+		if !ValidateCEP(arg) {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cep.invalid", arg))
+		}
+
+		results = append(results, CEPResult{CEP: FormatCEP(arg), Valid: true})
+	}
+
+	if opts.JSON {
+		return encodeCEPResults(w, results)
+	}
+
+	for _, r := range results {
+		_, _ = fmt.Fprintln(w, r.CEP)
+	}
+
+	return nil
+}
+
+func lookupCEP(ctx context.Context, w io.Writer, args []string, opts CEPOptions) error {
+	provider := opts.Provider
+	if provider == nil {
+		provider = NewViaCEPProvider()
+	}
+
+	var (
+		results  []CEPResult
+		firstErr error
+	)
+
+	for _, arg := range args {
+		result := CEPResult{CEP: arg}
+
+		if !ValidateCEP(arg) {
+			result.Error = "invalid CEP"
+			if firstErr == nil {
+				firstErr = cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cep.invalid", arg))
+			}
+
+			results = append(results, result)
+
+			continue
+		}
+
+		result.Valid = true
+
+		addr, err := provider.Lookup(ctx, cleanDoc(arg))
+		if err != nil {
+			result.Error = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			result.CEP = addr.CEP
+			result.Address = addr
+		}
+
+		results = append(results, result)
+	}
+
+	if opts.JSON {
+		if err := encodeCEPResults(w, results); err != nil {
+			return err
+		}
+
+		return firstErr
+	}
+
+	for _, r := range results {
+		if r.Address == nil {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", r.CEP, r.Error)
+			continue
+		}
+
+		a := r.Address
+		_, _ = fmt.Fprintf(w, "%s: %s, %s, %s - %s\n", a.CEP, a.Street, a.Neighborhood, a.City, a.State)
+	}
+
+	return firstErr
+}
+
+func encodeCEPResults(w io.Writer, results []CEPResult) error {
+	if len(results) == 1 {
+		return json.NewEncoder(w).Encode(results[0])
+	}
+
+	return json.NewEncoder(w).Encode(CEPListResult{Count: len(results), CEPs: results})
+}
+
+// ValidateCEP reports whether cep is a well-formed CEP: 8 digits, optionally
+// written as XXXXX-XXX or XX.XXX-XXX. All-zero CEPs are rejected.
+func ValidateCEP(cep string) bool {
+	clean := cleanDoc(strings.TrimSpace(cep))
+	if len(clean) != 8 {
+		return false
+	}
+
+	for _, c := range clean {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return clean != "00000000"
+}
+
+// FormatCEP formats a CEP as XXXXX-XXX. Malformed input is returned unchanged.
+func FormatCEP(cep string) string {
+	if !ValidateCEP(cep) {
+		return cep
+	}
+
+	clean := cleanDoc(strings.TrimSpace(cep))
+
+	return clean[:5] + "-" + clean[5:]
+}
+
+// LookupCEP resolves a CEP using the given provider (ViaCEP when nil)
+func LookupCEP(ctx context.Context, provider CEPProvider, cep string) (*CEPAddress, error) {
+	if !ValidateCEP(cep) {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, i18n.T("cep.invalid", cep))
+	}
+
+	if provider == nil {
+		provider = NewViaCEPProvider()
+	}
+
+	return provider.Lookup(ctx, cleanDoc(strings.TrimSpace(cep)))
+}
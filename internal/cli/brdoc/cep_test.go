@@ -0,0 +1,163 @@
+package brdoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+type fakeCEPProvider struct {
+	addrs map[string]*CEPAddress
+	calls []string
+}
+
+func (f *fakeCEPProvider) Lookup(_ context.Context, cep string) (*CEPAddress, error) {
+	f.calls = append(f.calls, cep)
+
+	if a, ok := f.addrs[cep]; ok {
+		return a, nil
+	}
+
+	return nil, cmderr.Wrap(cmderr.ErrNotFound, "cep: "+cep+" not found")
+}
+
+func TestValidateCEP(t *testing.T) {
+	tests := []struct {
+		cep   string
+		valid bool
+	}{
+		{"01310-100", true},
+		{"01310100", true},
+		{"01.310-100", true},
+		{" 01310-100 ", true},
+		{"00000-000", false},
+		{"0131010", false},
+		{"013101000", false},
+		{"0131A-100", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			if got := ValidateCEP(tt.cep); got != tt.valid {
+				t.Errorf("ValidateCEP(%q) = %v, want %v", tt.cep, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestFormatCEP(t *testing.T) {
+	if got := FormatCEP("01310100"); got != "01310-100" {
+		t.Errorf("FormatCEP() = %q, want 01310-100", got)
+	}
+
+	if got := FormatCEP("bogus"); got != "bogus" {
+		t.Errorf("FormatCEP(invalid) = %q, want input unchanged", got)
+	}
+}
+
+func TestRunCEPValidate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunCEP(context.Background(), &buf, []string{"01310-100"}, CEPOptions{}); err != nil {
+		t.Fatalf("RunCEP() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "valid") {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	buf.Reset()
+
+	err := RunCEP(context.Background(), &buf, []string{"01310-100", "123"}, CEPOptions{JSON: true})
+	if !cmderr.IsInvalidInput(err) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+
+	var list CEPListResult
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if list.Count != 2 || !list.CEPs[0].Valid || list.CEPs[1].Valid {
+		t.Errorf("unexpected results: %+v", list)
+	}
+}
+
+func TestRunCEPNoArgs(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunCEP(context.Background(), &buf, nil, CEPOptions{}); err == nil {
+		t.Error("expected error with no args")
+	}
+}
+
+func TestRunCEPLookupFakeProvider(t *testing.T) {
+	p := &fakeCEPProvider{addrs: map[string]*CEPAddress{
+		"01310100": {CEP: "01310-100", Street: "Avenida Paulista", City: "São Paulo", State: "SP"},
+	}}
+
+	var buf bytes.Buffer
+
+	err := RunCEP(context.Background(), &buf, []string{"01310-100"}, CEPOptions{Lookup: true, JSON: true, Provider: p})
+	if err != nil {
+		t.Fatalf("RunCEP() error = %v", err)
+	}
+
+	if len(p.calls) != 1 || p.calls[0] != "01310100" {
+		t.Errorf("provider calls = %v, want cleaned CEP", p.calls)
+	}
+
+	var res CEPResult
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if res.Address == nil || res.Address.City != "São Paulo" || res.Address.State != "SP" {
+		t.Errorf("unexpected address: %+v", res.Address)
+	}
+
+	buf.Reset()
+
+	err = RunCEP(context.Background(), &buf, []string{"99999-999"}, CEPOptions{Lookup: true, Provider: p})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestViaCEPProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ws/01310100/json/":
+			_, _ = w.Write([]byte(`{"cep":"01310-100","logradouro":"Avenida Paulista","bairro":"Bela Vista","localidade":"São Paulo","uf":"SP","ibge":"3550308","ddd":"11"}`))
+		case "/ws/99999999/json/":
+			_, _ = w.Write([]byte(`{"erro": true}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	p := &ViaCEPProvider{URL: srv.URL + "/ws/{cep}/json/", Client: srv.Client()}
+
+	addr, err := LookupCEP(context.Background(), p, "01310-100")
+	if err != nil {
+		t.Fatalf("LookupCEP() error = %v", err)
+	}
+
+	if addr.Street != "Avenida Paulista" || addr.Neighborhood != "Bela Vista" || addr.DDD != "11" {
+		t.Errorf("unexpected address: %+v", addr)
+	}
+
+	if _, err := LookupCEP(context.Background(), p, "99999-999"); !cmderr.IsNotFound(err) {
+		t.Errorf("expected not found for erro payload, got %v", err)
+	}
+
+	if _, err := LookupCEP(context.Background(), p, "abc"); !cmderr.IsInvalidInput(err) {
+		t.Errorf("expected invalid input, got %v", err)
+	}
+}
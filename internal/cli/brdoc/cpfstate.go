@@ -0,0 +1,97 @@
+package brdoc
+
+import (
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/i18n"
+)
+
+// fiscalRegions maps each UF to the Receita Federal fiscal region encoded in
+// the ninth CPF digit. This is the same table CheckOrigin reads in reverse.
+var fiscalRegions = map[string]int{
+	"RS": 0,
+	"DF": 1, "GO": 1, "MT": 1, "MS": 1, "TO": 1,
+	"PA": 2, "AM": 2, "AC": 2, "AP": 2, "RO": 2, "RR": 2,
+	"CE": 3, "MA": 3, "PI": 3,
+	"PE": 4, "RN": 4, "PB": 4, "AL": 4,
+	"BA": 5, "SE": 5,
+	"MG": 6,
+	"RJ": 7, "ES": 7,
+	"SP": 8,
+	"PR": 9, "SC": 9,
+}
+
+// FiscalRegion returns the ninth-digit fiscal region for a UF (e.g. "SP" → 8).
+func FiscalRegion(uf string) (int, error) {
+	region, ok := fiscalRegions[strings.ToUpper(strings.TrimSpace(uf))]
+	if !ok {
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput,
+			i18n.T("cpf.unknown_state", uf, strings.Join(States(), ", ")))
+	}
+
+	return region, nil
+}
+
+// States returns the supported UF codes in alphabetical order
+func States() []string {
+	states := make([]string, 0, len(fiscalRegions))
+	for uf := range fiscalRegions {
+		states = append(states, uf)
+	}
+
+	sort.Strings(states)
+
+	return states
+}
+
+// GenerateCPFForState generates a valid CPF whose ninth digit matches the
+// fiscal region of the given UF, so CPFState reports that region.
+func GenerateCPFForState(uf string) (string, error) {
+	region, err := FiscalRegion(uf)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		digits := make([]int, 0, 11)
+		for range 8 {
+			digits = append(digits, rand.IntN(10))
+		}
+
+		digits = append(digits, region)
+		digits = append(digits, cpfCheckDigit(digits))
+		digits = append(digits, cpfCheckDigit(digits))
+
+		var sb strings.Builder
+		for _, d := range digits {
+			sb.WriteByte(byte('0' + d))
+		}
+
+		// Rejects the all-equal-digits sequences the validator refuses.
+		if cpf := sb.String(); cpfHandler.Validate(cpf) {
+			return cpf, nil
+		}
+	}
+}
+
+// cpfCheckDigit computes the mod-11 verifier for the given prefix (9 digits
+// for the first verifier, 10 for the second).
+func cpfCheckDigit(digits []int) int {
+	sum := 0
+	weight := len(digits) + 1
+
+	for _, d := range digits {
+		sum += d * weight
+		weight--
+	}
+
+	r := sum % 11
+	if r < 2 {
+		return 0
+	}
+
+	return 11 - r
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,6 +18,7 @@ type Options struct {
 	ExcludePath []string // Paths to exclude
 	Path        []string // Specific paths to process
 	Config      string   // Custom config file path
+	CacheDir    string   // Image cache directory (empty disables caching)
 }
 
 // LintOptions configures buf lint
@@ -178,6 +180,10 @@ func LoadGenerateConfig(dir string) (*GenerateConfig, error) {
 func FindProtoFiles(dir string, excludePaths []string) ([]string, error) {
 	var files []string
 
+	start := time.Now()
+
+	emit(Event{Kind: EventScanStart, Dir: dir})
+
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -213,6 +219,9 @@ func FindProtoFiles(dir string, excludePaths []string) ([]string, error) {
 
 		return nil
 	})
+
+	emit(Event{Kind: EventScanDone, Dir: dir, Files: len(files), Duration: time.Since(start), Err: err})
+
 	if err != nil {
 		return nil, err
 	}
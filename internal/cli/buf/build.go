@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/bufbuild/protocompile"
 	"github.com/bufbuild/protocompile/linker"
@@ -46,15 +47,12 @@ func RunBuild(w io.Writer, dir string, opts BuildOptions) error {
 		relFiles[i] = filepath.ToSlash(rel)
 	}
 
-	// Compile proto files
-	compiled, compileErrs := compileProtos(absDir, relFiles)
-	if compileErrs != nil {
-		return fmt.Errorf("buf build: %w", compileErrs)
+	// Compile proto files (or reuse a cached image)
+	fds, compileErr := getImage(absDir, relFiles, opts.CacheDir)
+	if compileErr != nil {
+		return fmt.Errorf("buf build: %w", compileErr)
 	}
 
-	// Build FileDescriptorSet
-	fds := buildFileDescriptorSet(compiled)
-
 	if opts.Output != "" {
 		if err := writeFileDescriptorSet(fds, opts.Output); err != nil {
 			if errors.Is(err, os.ErrPermission) {
@@ -96,7 +94,7 @@ func RunBreaking(w io.Writer, dir string, opts BreakingOptions) error {
 		return fmt.Errorf("buf breaking: %w", err)
 	}
 	currentRel := toRelSlash(absDir, currentFiles)
-	currentLinked, compileErr := compileProtos(absDir, currentRel)
+	currentFDS, compileErr := getImage(absDir, currentRel, opts.CacheDir)
 	if compileErr != nil {
 		return fmt.Errorf("buf breaking: current: %w", compileErr)
 	}
@@ -107,14 +105,11 @@ func RunBreaking(w io.Writer, dir string, opts BreakingOptions) error {
 		return fmt.Errorf("buf breaking: %w", err)
 	}
 	againstRel := toRelSlash(absAgainst, againstFiles)
-	againstLinked, compileErr := compileProtos(absAgainst, againstRel)
+	againstFDS, compileErr := getImage(absAgainst, againstRel, opts.CacheDir)
 	if compileErr != nil {
 		return fmt.Errorf("buf breaking: against: %w", compileErr)
 	}
 
-	currentFDS := buildFileDescriptorSet(currentLinked)
-	againstFDS := buildFileDescriptorSet(againstLinked)
-
 	// Detect breaking changes
 	issues := detectBreakingChanges(currentFDS, againstFDS, opts.ExcludeImports)
 
@@ -150,7 +145,13 @@ func compileProtos(dir string, relFiles []string) (linker.Files, error) {
 		Reporter: rep,
 	}
 
+	start := time.Now()
+
+	emit(Event{Kind: EventCompileStart, Dir: dir, Files: len(relFiles)})
+
 	linked, err := compiler.Compile(context.Background(), relFiles...)
+	emit(Event{Kind: EventCompileDone, Dir: dir, Files: len(relFiles), Duration: time.Since(start), Err: err})
+
 	if err != nil {
 		if len(errs) > 0 {
 			var msgs []string
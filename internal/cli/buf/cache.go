@@ -0,0 +1,163 @@
+package buf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// imageCacheVersion is mixed into every cache key; bump it whenever the
+// compiler or the image layout changes so stale entries are never reused.
+const imageCacheVersion = "omni-buf-image-v1"
+
+// DefaultImageCacheDir returns <os.UserCacheDir>/omni/buf-images.
+func DefaultImageCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "omni", "buf-images"), nil
+}
+
+// getImage compiles relFiles in dir into a FileDescriptorSet. When cacheDir
+// is set, the result is stored under a key derived from the content of every
+// .proto file in dir plus the requested file list, and reused on the next
+// call as long as nothing changed.
+func getImage(dir string, relFiles []string, cacheDir string) (*descriptorpb.FileDescriptorSet, error) {
+	if cacheDir == "" {
+		return compileImage(dir, relFiles)
+	}
+
+	key, err := imageCacheKey(dir, relFiles)
+	if err != nil {
+		return compileImage(dir, relFiles)
+	}
+
+	path := filepath.Join(cacheDir, key+".binpb")
+
+	if fds, ok := readCachedImage(path); ok {
+		emit(Event{Kind: EventCacheHit, Dir: dir, Files: len(relFiles)})
+		return fds, nil
+	}
+
+	fds, err := compileImage(dir, relFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failing cache write must never fail the build
+	_ = writeCachedImage(path, fds)
+
+	return fds, nil
+}
+
+// compileImage compiles and links relFiles without consulting the cache.
+func compileImage(dir string, relFiles []string) (*descriptorpb.FileDescriptorSet, error) {
+	linked, err := compileProtos(dir, relFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildFileDescriptorSet(linked), nil
+}
+
+// imageCacheKey hashes the digests of all .proto files under dir (imports of
+// excluded files still affect the image) and the requested file list.
+func imageCacheKey(dir string, relFiles []string) (string, error) {
+	all, err := FindProtoFiles(dir, nil)
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(all)
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n", imageCacheVersion)
+
+	for _, path := range all {
+		digest, err := fileDigest(path)
+		if err != nil {
+			return "", err
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		_, _ = fmt.Fprintf(h, "file %s %s\n", filepath.ToSlash(rel), digest)
+	}
+
+	requested := append([]string(nil), relFiles...)
+	sort.Strings(requested)
+	_, _ = fmt.Fprintf(h, "build %s\n", strings.Join(requested, ","))
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readCachedImage(path string) (*descriptorpb.FileDescriptorSet, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, fds); err != nil {
+		return nil, false
+	}
+
+	return fds, true
+}
+
+// writeCachedImage stores fds atomically so concurrent runs never observe a
+// partially written entry.
+func writeCachedImage(path string, fds *descriptorpb.FileDescriptorSet) error {
+	data, err := proto.Marshal(fds)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".image-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
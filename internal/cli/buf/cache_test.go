@@ -0,0 +1,105 @@
+package buf
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageCache(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := t.TempDir()
+	protoPath := filepath.Join(dir, "a.proto")
+
+	write := func(content string) {
+		t.Helper()
+
+		if err := os.WriteFile(protoPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("syntax = \"proto3\";\npackage a.v1;\nmessage A {}\n")
+
+	var compiles, hits int
+
+	defer Subscribe(func(e Event) {
+		switch e.Kind {
+		case EventCompileStart:
+			compiles++
+		case EventCacheHit:
+			hits++
+		}
+	})()
+
+	build := func() {
+		t.Helper()
+
+		if err := RunBuild(io.Discard, dir, BuildOptions{Options: Options{CacheDir: cacheDir}}); err != nil {
+			t.Fatalf("RunBuild() error = %v", err)
+		}
+	}
+
+	build()
+	build()
+
+	if compiles != 1 || hits != 1 {
+		t.Fatalf("compiles=%d hits=%d, want 1 and 1", compiles, hits)
+	}
+
+	// Changing a file invalidates the entry
+	write("syntax = \"proto3\";\npackage a.v1;\nmessage A { string id = 1; }\n")
+	build()
+
+	if compiles != 2 {
+		t.Errorf("changed workspace should recompile, compiles=%d", compiles)
+	}
+
+	// Corrupt entries are ignored and rebuilt
+	entries, _ := filepath.Glob(filepath.Join(cacheDir, "*.binpb"))
+	if len(entries) != 2 {
+		t.Fatalf("cache entries = %d, want 2", len(entries))
+	}
+
+	for _, e := range entries {
+		_ = os.WriteFile(e, []byte("garbage"), 0644)
+	}
+
+	build()
+
+	if compiles != 3 {
+		t.Errorf("corrupt cache should recompile, compiles=%d", compiles)
+	}
+
+	// No cache dir: always compiles
+	if err := RunBuild(io.Discard, dir, BuildOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if compiles != 4 || hits != 1 {
+		t.Errorf("compiles=%d hits=%d after uncached build", compiles, hits)
+	}
+}
+
+func TestImageCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.proto"), []byte("syntax = \"proto3\";\n"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "b.proto"), []byte("syntax = \"proto3\";\n"), 0644)
+
+	k1, err := imageCacheKey(dir, []string{"a.proto", "b.proto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k2, _ := imageCacheKey(dir, []string{"b.proto", "a.proto"})
+	k3, _ := imageCacheKey(dir, []string{"a.proto"})
+
+	if k1 != k2 {
+		t.Error("key should not depend on file order")
+	}
+
+	if k1 == k3 {
+		t.Error("key should depend on the requested files")
+	}
+}
@@ -0,0 +1,122 @@
+package buf
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventKind identifies a stage of a long-running buf operation.
+type EventKind int
+
+const (
+	// EventScanStart is emitted before the workspace is walked for proto files.
+	EventScanStart EventKind = iota
+	// EventScanDone is emitted after the walk; Files holds the count found.
+	EventScanDone
+	// EventCompileStart is emitted before proto files are compiled into an image.
+	EventCompileStart
+	// EventCompileDone is emitted after compilation with its Duration and Err.
+	EventCompileDone
+	// EventPluginStart is emitted before a generation plugin runs.
+	EventPluginStart
+	// EventPluginDone is emitted after a generation plugin finished.
+	EventPluginDone
+	// EventCacheHit is emitted when an image is served from the image cache
+	// instead of being compiled.
+	EventCacheHit
+)
+
+// String returns a stable name for the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventScanStart:
+		return "scan_start"
+	case EventScanDone:
+		return "scan_done"
+	case EventCompileStart:
+		return "compile_start"
+	case EventCompileDone:
+		return "compile_done"
+	case EventPluginStart:
+		return "plugin_start"
+	case EventPluginDone:
+		return "plugin_done"
+	case EventCacheHit:
+		return "cache_hit"
+	}
+
+	return "unknown"
+}
+
+// Event describes progress of a buf operation.
+type Event struct {
+	Kind     EventKind
+	Dir      string        // Workspace directory
+	Files    int           // Number of proto files involved
+	Plugin   string        // Plugin name (plugin events only)
+	Duration time.Duration // Elapsed time (Done events only)
+	Err      error         // Failure, if any (Done events only)
+}
+
+// Listener receives buf events. Listeners are called synchronously on the
+// goroutine doing the work and must return quickly.
+type Listener func(Event)
+
+var (
+	listenersMu sync.RWMutex
+	listeners   = map[int]Listener{}
+	nextID      int
+)
+
+// Subscribe registers l for all buf events and returns a function that
+// removes it again.
+func Subscribe(l Listener) (unsubscribe func()) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+
+	id := nextID
+	nextID++
+	listeners[id] = l
+
+	return func() {
+		listenersMu.Lock()
+		defer listenersMu.Unlock()
+
+		delete(listeners, id)
+	}
+}
+
+// emit delivers e to every subscribed listener.
+func emit(e Event) {
+	listenersMu.RLock()
+	defer listenersMu.RUnlock()
+
+	for _, l := range listeners {
+		l(e)
+	}
+}
+
+// ProgressListener returns a Listener that writes one status line per
+// completed stage to w, e.g. for rendering progress on stderr.
+func ProgressListener(w io.Writer) Listener {
+	return func(e Event) {
+		switch e.Kind {
+		case EventScanStart, EventScanDone, EventCacheHit:
+			// Scans and cache hits are fast; only the stages below are worth reporting
+		case EventCompileStart:
+			_, _ = fmt.Fprintf(w, "buf: compiling %d file(s)...\n", e.Files)
+		case EventCompileDone:
+			if e.Err == nil {
+				_, _ = fmt.Fprintf(w, "buf: compiled %d file(s) in %s\n", e.Files, e.Duration.Round(time.Millisecond))
+			}
+		case EventPluginStart:
+			_, _ = fmt.Fprintf(w, "buf: running %s...\n", e.Plugin)
+		case EventPluginDone:
+			if e.Err == nil {
+				_, _ = fmt.Fprintf(w, "buf: %s finished in %s\n", e.Plugin, e.Duration.Round(time.Millisecond))
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package buf
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubscribeBuildEvents(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.proto"), []byte("syntax = \"proto3\";\npackage a.v1;\nmessage A {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []EventKind
+
+	unsubscribe := Subscribe(func(e Event) {
+		kinds = append(kinds, e.Kind)
+
+		if e.Kind == EventCompileDone && (e.Files != 1 || e.Err != nil) {
+			t.Errorf("unexpected compile event: %+v", e)
+		}
+	})
+
+	if err := RunBuild(io.Discard, dir, BuildOptions{}); err != nil {
+		t.Fatalf("RunBuild() error = %v", err)
+	}
+
+	want := []EventKind{EventScanStart, EventScanDone, EventCompileStart, EventCompileDone}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("events = %v, want %v", kinds, want)
+	}
+
+	unsubscribe()
+
+	kinds = nil
+	if err := RunBuild(io.Discard, dir, BuildOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(kinds) != 0 {
+		t.Errorf("listener called after unsubscribe: %v", kinds)
+	}
+}
+
+func TestProgressListener(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := ProgressListener(&buf)
+	l(Event{Kind: EventScanDone, Files: 3})
+	l(Event{Kind: EventCompileStart, Files: 3})
+	l(Event{Kind: EventCompileDone, Files: 3, Duration: 1500 * time.Millisecond})
+	l(Event{Kind: EventPluginStart, Plugin: "protoc-gen-go"})
+
+	want := "buf: compiling 3 file(s)...\nbuf: compiled 3 file(s) in 1.5s\nbuf: running protoc-gen-go...\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	if !strings.Contains(EventCompileDone.String(), "compile") {
+		t.Errorf("EventKind.String() = %q", EventCompileDone.String())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // RunGenerate generates code from proto files
@@ -69,7 +70,14 @@ func RunGenerate(w io.Writer, dir string, opts GenerateOptions) error {
 
 		if plugin.Local != "" {
 			// Run local plugin
-			if err := runLocalPlugin(w, dir, files, plugin, outDir); err != nil {
+			start := time.Now()
+
+			emit(Event{Kind: EventPluginStart, Dir: dir, Files: len(files), Plugin: plugin.Local})
+
+			err := runLocalPlugin(w, dir, files, plugin, outDir)
+			emit(Event{Kind: EventPluginDone, Dir: dir, Files: len(files), Plugin: plugin.Local, Duration: time.Since(start), Err: err})
+
+			if err != nil {
 				return fmt.Errorf("buf: plugin %s failed: %w", plugin.Local, err)
 			}
 
@@ -2,12 +2,12 @@ package cat
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/input"
@@ -15,13 +15,14 @@ import (
 
 // CatOptions configures the cat command behavior
 type CatOptions struct {
-	NumberAll      bool // -n: number all output lines
-	NumberNonBlank bool // -b: number non-blank output lines
-	ShowEnds       bool // -E: display $ at end of each line
-	ShowTabs       bool // -T: display TAB characters as ^I
-	SqueezeBlank   bool // -s: suppress repeated empty output lines
-	ShowNonPrint   bool // -v: use ^ and M- notation, except for LFD and TAB
-	JSON           bool // --json: output as JSON array of lines
+	NumberAll      bool   // -n: number all output lines
+	NumberNonBlank bool   // -b: number non-blank output lines
+	ShowEnds       bool   // -E: display $ at end of each line
+	ShowTabs       bool   // -T: display TAB characters as ^I
+	SqueezeBlank   bool   // -s: suppress repeated empty output lines
+	ShowNonPrint   bool   // -v: use ^ and M- notation, except for LFD and TAB
+	JSON           bool   // --json: output as JSON array of lines
+	Tee            string // --tee: also write the output to this file
 }
 
 // CatLine represents a line for JSON output
@@ -31,161 +32,271 @@ type CatLine struct {
 }
 
 // RunCat executes the cat command with the given options
-// r is the default input reader (used when args is empty or contains "-")
+// r is the default input reader (used when args is empty or contains "-").
+// Files that cannot be opened are reported on stderr and skipped; the exit
+// status is then 1.
 func RunCat(w io.Writer, r io.Reader, args []string, opts CatOptions) error {
-	sources, err := input.Open(args, r)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("cat: %s", err))
-		}
-		if errors.Is(err, os.ErrPermission) {
-			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("cat: %s", err))
+	if opts.Tee != "" {
+		f, err := os.Create(opts.Tee)
+		if err != nil {
+			return openError(err)
 		}
-		return fmt.Errorf("cat: %w", err)
+
+		defer func() { _ = f.Close() }()
+
+		w = io.MultiWriter(w, f)
 	}
-	defer input.CloseAll(sources)
 
-	var allLines []CatLine
+	names := args
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	// Line numbers and blank-line squeezing carry over between files
+	state := &catState{opts: opts, atStart: true}
+
+	var (
+		allLines []CatLine
+		failed   bool
+	)
+
+	for _, name := range names {
+		src, err := input.OpenOne([]string{name}, r)
+		if err != nil {
+			failed = true
+
+			_, _ = fmt.Fprintf(os.Stderr, "cat: %s\n", err)
+
+			continue
+		}
 
-	for _, src := range sources {
 		if opts.JSON {
-			lines, err := catReaderJSON(src.Reader, opts)
-			if err != nil {
-				return err
-			}
+			var lines []CatLine
 
+			lines, err = state.jsonLines(src.Reader)
 			allLines = append(allLines, lines...)
 		} else {
-			if err := catReader(w, src.Reader, src.Name, opts); err != nil {
-				return err
-			}
+			err = state.copy(w, src.Reader)
+		}
+
+		input.MustClose(&src)
+
+		if err != nil {
+			return err
 		}
 	}
 
 	if opts.JSON {
-		return json.NewEncoder(w).Encode(allLines)
+		if err := json.NewEncoder(w).Encode(allLines); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return cmderr.PartialFailure(1, "cat: some operands failed")
 	}
 
 	return nil
 }
 
-func catReaderJSON(r io.Reader, opts CatOptions) ([]CatLine, error) {
-	scanner := bufio.NewScanner(r)
-	lineNum := 0
-	prevBlank := false
+func openError(err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("cat: %s", err))
+	case errors.Is(err, os.ErrPermission):
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("cat: %s", err))
+	}
 
-	var lines []CatLine
+	return fmt.Errorf("cat: %w", err)
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		isBlank := len(strings.TrimSpace(line)) == 0
+// catState formats lines as they stream past, one buffer at a time, so
+// neither long lines nor large files are held in memory.
+type catState struct {
+	opts     CatOptions
+	lineNum  int
+	blankRun int
+	atStart  bool
+}
 
-		if opts.SqueezeBlank && isBlank && prevBlank {
-			continue
+func (c *catState) plain() bool {
+	o := c.opts
+	return !o.NumberAll && !o.NumberNonBlank && !o.ShowEnds && !o.ShowTabs && !o.SqueezeBlank && !o.ShowNonPrint
+}
+
+func (c *catState) copy(w io.Writer, r io.Reader) error {
+	if c.plain() {
+		if _, err := io.Copy(w, r); err != nil {
+			return fmt.Errorf("cat: %w", err)
 		}
 
-		prevBlank = isBlank
+		return nil
+	}
+
+	bw := bufio.NewWriter(w)
+	br := bufio.NewReaderSize(r, 64*1024)
 
-		output := line
-		if opts.ShowTabs {
-			output = strings.ReplaceAll(output, "\t", "^I")
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if len(chunk) > 0 {
+			c.writeChunk(bw, chunk)
 		}
 
-		if opts.ShowNonPrint {
-			output = showNonPrintable(output)
+		switch {
+		case err == nil, errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == io.EOF:
+			if ferr := bw.Flush(); ferr != nil {
+				return fmt.Errorf("cat: write error: %w", ferr)
+			}
+
+			return nil
+		default:
+			_ = bw.Flush()
+			return fmt.Errorf("cat: %w", err)
 		}
+	}
+}
 
-		if opts.ShowEnds {
-			output += "$"
+// writeChunk formats part of a line; only a chunk ending in '\n' ends it.
+func (c *catState) writeChunk(w *bufio.Writer, chunk []byte) {
+	if c.atStart {
+		blank := len(chunk) == 1 && chunk[0] == '\n'
+		if blank {
+			c.blankRun++
+			if c.opts.SqueezeBlank && c.blankRun > 1 {
+				return
+			}
+		} else {
+			c.blankRun = 0
 		}
 
-		catLine := CatLine{Content: output}
+		if c.opts.NumberAll && !c.opts.NumberNonBlank || c.opts.NumberNonBlank && !blank {
+			c.lineNum++
+			_, _ = fmt.Fprintf(w, "%6d\t", c.lineNum)
+		}
+	}
+
+	body, eol := bytes.CutSuffix(chunk, []byte{'\n'})
+	c.writeBody(w, body)
 
-		if opts.NumberAll || (opts.NumberNonBlank && !isBlank) {
-			lineNum++
-			catLine.Number = lineNum
+	if eol {
+		if c.opts.ShowEnds {
+			_ = w.WriteByte('$')
 		}
 
-		lines = append(lines, catLine)
+		_ = w.WriteByte('\n')
 	}
 
-	return lines, scanner.Err()
+	c.atStart = eol
 }
 
-func catReader(w io.Writer, r io.Reader, _ string, opts CatOptions) error {
-	scanner := bufio.NewScanner(r)
-	lineNum := 0
-	prevBlank := false
+func (c *catState) writeBody(w *bufio.Writer, body []byte) {
+	if !c.opts.ShowNonPrint && !c.opts.ShowTabs {
+		_, _ = w.Write(body)
+		return
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		isBlank := len(strings.TrimSpace(line)) == 0
+	for _, b := range body {
+		writeByte(w, b, c.opts)
+	}
+}
 
-		// Squeeze blank lines
-		if opts.SqueezeBlank && isBlank && prevBlank {
-			continue
+// writeByte writes b, escaped as GNU cat does: ^X for control characters,
+// ^? for DEL and an M- prefix for bytes with the high bit set.
+func writeByte(w *bufio.Writer, b byte, opts CatOptions) {
+	if b == '\t' && !opts.ShowTabs {
+		_ = w.WriteByte(b)
+		return
+	}
+
+	if !opts.ShowNonPrint {
+		if b == '\t' {
+			_, _ = w.WriteString("^I")
+		} else {
+			_ = w.WriteByte(b)
 		}
 
-		prevBlank = isBlank
+		return
+	}
 
-		// Process line content
-		output := line
+	if b >= 128 {
+		_, _ = w.WriteString("M-")
+		b -= 128
+	}
 
-		// Show tabs as ^I
-		if opts.ShowTabs {
-			output = strings.ReplaceAll(output, "\t", "^I")
-		}
+	switch {
+	case b < 32:
+		_ = w.WriteByte('^')
+		_ = w.WriteByte(b + 64)
+	case b == 127:
+		_, _ = w.WriteString("^?")
+	default:
+		_ = w.WriteByte(b)
+	}
+}
 
-		// Show non-printing characters
-		if opts.ShowNonPrint {
-			output = showNonPrintable(output)
-		}
+func (c *catState) jsonLines(r io.Reader) ([]CatLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+
+	var lines []CatLine
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		isBlank := line == ""
 
-		// Add line number
-		if opts.NumberAll || (opts.NumberNonBlank && !isBlank) {
-			lineNum++
-			output = fmt.Sprintf("%6d\t%s", lineNum, output)
+		if isBlank {
+			c.blankRun++
+			if c.opts.SqueezeBlank && c.blankRun > 1 {
+				continue
+			}
+		} else {
+			c.blankRun = 0
 		}
 
-		// Add $ at end of line
-		if opts.ShowEnds {
+		output := escape(line, c.opts)
+		if c.opts.ShowEnds {
 			output += "$"
 		}
 
-		_, err := fmt.Fprintln(w, output)
-		if err != nil {
-			return fmt.Errorf("cat: write error: %w", err)
+		catLine := CatLine{Content: output}
+
+		if c.opts.NumberAll && !c.opts.NumberNonBlank || c.opts.NumberNonBlank && !isBlank {
+			c.lineNum++
+			catLine.Number = c.lineNum
 		}
+
+		lines = append(lines, catLine)
 	}
 
-	return scanner.Err()
+	return lines, scanner.Err()
 }
 
-func showNonPrintable(s string) string {
-	var result strings.Builder
+func catReaderJSON(r io.Reader, opts CatOptions) ([]CatLine, error) {
+	return (&catState{opts: opts, atStart: true}).jsonLines(r)
+}
 
-	for _, r := range s {
-		switch {
-		case r == '\t':
-			result.WriteRune(r) // Tabs handled separately with -T
-		case r < 32:
-			// Control characters
-			result.WriteString(fmt.Sprintf("^%c", r+64))
-		case r == 127:
-			result.WriteString("^?")
-		case r > 127:
-			// High-bit characters (M- notation)
-			if r < 160 {
-				result.WriteString(fmt.Sprintf("M-^%c", r-128+64))
-			} else {
-				result.WriteString(fmt.Sprintf("M-%c", r-128))
-			}
-		default:
-			result.WriteRune(r)
-		}
+// escape applies -v and -T notation to a line.
+func escape(s string, opts CatOptions) string {
+	if !opts.ShowNonPrint && !opts.ShowTabs {
+		return s
+	}
+
+	var buf bytes.Buffer
+
+	w := bufio.NewWriter(&buf)
+	for i := range len(s) {
+		writeByte(w, s[i], opts)
 	}
 
-	return result.String()
+	_ = w.Flush()
+
+	return buf.String()
+}
+
+func showNonPrintable(s string) string {
+	return escape(s, CatOptions{ShowNonPrint: true})
 }
 
 // Cat copies from reader to writer (simple version for compatibility)
@@ -3,8 +3,12 @@ package cat
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 // TestCatReaderJSON exercises the JSON line-collection path including numbering,
@@ -99,8 +103,9 @@ func TestShowNonPrintable(t *testing.T) {
 		{"tab passthrough", "\t", "\t"},
 		{"control SOH", "\x01", "^A"},
 		{"del", "\x7f", "^?"},
-		{"meta control", string(rune(0x80)), "M-^@"},       // 0x80: r<160 -> M-^<r-128+64>
-		{"meta high", string(rune(0x00E9)), "M-i"},          // é U+00E9 (233): r>=160 -> M-<233-128=105='i'>
+		{"meta control", "\x80", "M-^@"},
+		{"meta high", "\xe9", "M-i"},
+		{"utf-8 bytes", "é", "M-CM-)"}, // U+00E9 is 0xC3 0xA9
 		{"printable", "abc", "abc"},
 	}
 
@@ -129,3 +134,70 @@ func TestRunCatShowAll(t *testing.T) {
 		t.Errorf("missing line number: %q", out)
 	}
 }
+
+// TestRunCatStreaming checks that formatting keeps bytes, long lines and
+// numbering intact across buffer and file boundaries.
+func TestRunCatStreaming(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	_ = os.WriteFile(a, []byte("one\n\n"), 0o644)
+	_ = os.WriteFile(b, []byte("\ntwo"), 0o644)
+
+	var buf bytes.Buffer
+	if err := RunCat(&buf, nil, []string{a, b}, CatOptions{NumberAll: true, SqueezeBlank: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "     1\tone\n     2\t\n     3\ttwo"; buf.String() != want {
+		t.Errorf("RunCat() = %q, want %q", buf.String(), want)
+	}
+
+	long := strings.Repeat("x", 200*1024)
+
+	buf.Reset()
+	if err := RunCat(&buf, strings.NewReader(long+"\n"), nil, CatOptions{ShowEnds: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != long+"$\n" {
+		t.Errorf("RunCat() long line: got %d bytes", buf.Len())
+	}
+
+	buf.Reset()
+	if err := RunCat(&buf, strings.NewReader("a\n \n \n"), nil, CatOptions{SqueezeBlank: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "a\n \n \n" {
+		t.Errorf("RunCat() squeezed whitespace-only lines: %q", buf.String())
+	}
+}
+
+// TestRunCatTee checks that --tee copies the output to a file and that
+// missing inputs are skipped with exit status 1.
+func TestRunCatTee(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+
+	var buf bytes.Buffer
+
+	err := RunCat(&buf, strings.NewReader("hello\n"), []string{"-", filepath.Join(dir, "missing")}, CatOptions{Tee: out, NumberAll: true})
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("RunCat() error = %v, want exit status 1", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "     1\thello\n" || buf.String() != string(data) {
+		t.Errorf("tee = %q, stdout = %q", data, buf.String())
+	}
+
+	err = RunCat(&buf, strings.NewReader(""), nil, CatOptions{Tee: filepath.Join(dir, "no", "such", "out.txt")})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("RunCat() bad tee path error = %v, want not found", err)
+	}
+}
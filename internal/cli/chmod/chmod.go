@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -49,15 +50,15 @@ func RunChmod(w io.Writer, args []string, opts ChmodOptions) error {
 		return fmt.Errorf("chmod: cannot stat '%s': %w", opts.Reference, err)
 		}
 
-		newMode = info.Mode().Perm()
+		newMode = info.Mode() & modeBits
 	case isOctalMode(modeStr):
 		// Octal mode (e.g., 755, 0644)
 		mode, err := strconv.ParseUint(modeStr, 8, 32)
-		if err != nil {
+		if err != nil || mode > 0o7777 {
 			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("chmod: invalid mode: '%s'", modeStr))
 		}
 
-		newMode = fs.FileMode(mode)
+		newMode = fromOctal(uint32(mode))
 	default:
 		// All-digit strings that aren't valid octal (e.g., "999") are invalid
 		if isAllDigits(modeStr) {
@@ -75,10 +76,14 @@ func RunChmod(w io.Writer, args []string, opts ChmodOptions) error {
 		}
 	}
 
+	failed := false
+
 	for _, file := range files {
 		if opts.Recursive {
 			err := filepath.WalkDir(file, func(path string, d fs.DirEntry, err error) error {
 				if err != nil {
+					failed = true
+
 					if !opts.Silent {
 						_, _ = fmt.Fprintf(os.Stderr, "chmod: cannot access '%s': %v\n", path, err)
 					}
@@ -86,13 +91,28 @@ func RunChmod(w io.Writer, args []string, opts ChmodOptions) error {
 					return nil
 				}
 
-				return chmodFile(w, path, newMode, isSymbolic, symbolicOp, opts)
+				// Like GNU chmod, symlinks met during the walk are not followed
+				if path != file && d.Type()&fs.ModeSymlink != 0 {
+					return nil
+				}
+
+				if err := chmodFile(w, path, newMode, isSymbolic, symbolicOp, opts); err != nil {
+					failed = true
+
+					if !opts.Silent {
+						_, _ = fmt.Fprintf(os.Stderr, "chmod: %v\n", err)
+					}
+				}
+
+				return nil
 			})
 			if err != nil {
 				return err
 			}
 		} else {
 			if err := chmodFile(w, file, newMode, isSymbolic, symbolicOp, opts); err != nil {
+				failed = true
+
 				if !opts.Silent {
 					_, _ = fmt.Fprintf(os.Stderr, "chmod: %v\n", err)
 				}
@@ -100,21 +120,68 @@ func RunChmod(w io.Writer, args []string, opts ChmodOptions) error {
 		}
 	}
 
+	if failed {
+		return cmderr.PartialFailure(1, "chmod: some operands failed")
+	}
+
 	return nil
 }
 
+// modeBits are the mode bits chmod can change: permissions plus the
+// set-user-ID, set-group-ID and sticky bits.
+const modeBits = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// fromOctal converts a numeric mode such as 4755 to an fs.FileMode.
+func fromOctal(m uint32) fs.FileMode {
+	mode := fs.FileMode(m) & fs.ModePerm
+
+	if m&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+
+	if m&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+
+	if m&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+
+	return mode
+}
+
+// toOctal converts an fs.FileMode to its numeric form.
+func toOctal(mode fs.FileMode) uint32 {
+	m := uint32(mode.Perm())
+
+	if mode&fs.ModeSetuid != 0 {
+		m |= 0o4000
+	}
+
+	if mode&fs.ModeSetgid != 0 {
+		m |= 0o2000
+	}
+
+	if mode&fs.ModeSticky != 0 {
+		m |= 0o1000
+	}
+
+	return m
+}
+
 func chmodFile(w io.Writer, path string, newMode fs.FileMode, isSymbolic bool, symbolicOp func(fs.FileMode) fs.FileMode, opts ChmodOptions) error {
 	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("cannot access '%s': %w", path, err)
 	}
 
-	oldMode := info.Mode().Perm()
+	oldMode := info.Mode() & modeBits
 
 	var targetMode fs.FileMode
 
 	if isSymbolic {
-		targetMode = symbolicOp(oldMode)
+		// The directory bit lets X tell directories apart
+		targetMode = symbolicOp(oldMode|info.Mode()&fs.ModeDir) & modeBits
 	} else {
 		targetMode = newMode
 	}
@@ -124,7 +191,7 @@ func chmodFile(w io.Writer, path string, newMode fs.FileMode, isSymbolic bool, s
 	}
 
 	if opts.Verbose || (opts.Changes && oldMode != targetMode) {
-		_, _ = fmt.Fprintf(w, "mode of '%s' changed from %04o to %04o\n", path, oldMode, targetMode)
+		_, _ = fmt.Fprintf(w, "mode of '%s' changed from %04o to %04o\n", path, toOctal(oldMode), toOctal(targetMode))
 	}
 
 	return nil
@@ -151,118 +218,141 @@ func isAllDigits(s string) bool {
 	return len(s) > 0
 }
 
-//nolint:unparam // error kept for API consistency with parseOctalMode
+// symbolicClause matches one comma-separated part of a symbolic mode:
+// who letters followed by one or more operator and permission groups.
+var symbolicClause = regexp.MustCompile(`^[ugoa]*([-+=]([rwxXst]*|[ugo]))+$`)
+
+// umask returns the mask applied to clauses without who letters; a var so
+// tests can fix it.
+var umask = currentUmask
+
+// parseSymbolicMode parses a symbolic mode like u+x, go-w, a=rw, +X,
+// u+s or g=u. The returned function expects the current mode including
+// fs.ModeDir, which X uses.
 func parseSymbolicMode(mode string) (func(fs.FileMode) fs.FileMode, error) {
-	// Parse symbolic mode like u+x, go-w, a=rw, +x, etc.
+	parts := strings.Split(mode, ",")
+
+	for _, part := range parts {
+		if !symbolicClause.MatchString(part) {
+			return nil, fmt.Errorf("invalid mode clause %q", part)
+		}
+	}
+
+	mask := umask()
+
 	return func(current fs.FileMode) fs.FileMode {
 		result := current
 
-		// Split by comma for multiple operations
-		parts := strings.SplitSeq(mode, ",")
-
-		for part := range parts {
-			result = applySymbolicPart(result, part)
+		for _, part := range parts {
+			result = applySymbolicPart(result, part, mask)
 		}
 
 		return result
 	}, nil
 }
 
-func applySymbolicPart(mode fs.FileMode, part string) fs.FileMode {
-	// Parse who (u, g, o, a or empty for all)
-	who := ""
+// whoMask returns the bits a who letter (u, g, o, a) governs.
+func whoMask(c byte) fs.FileMode {
+	switch c {
+	case 'u':
+		return 0o700 | fs.ModeSetuid
+	case 'g':
+		return 0o070 | fs.ModeSetgid
+	case 'o':
+		return 0o007 | fs.ModeSticky
+	default:
+		return modeBits
+	}
+}
+
+// applySymbolicPart applies one clause to mode. Without who letters the
+// clause applies to all classes but, as in POSIX, leaves the bits set in
+// umask alone: with umask 022, +w only adds the owner's write bit.
+func applySymbolicPart(mode fs.FileMode, part string, umask fs.FileMode) fs.FileMode {
+	// Parse who (u, g, o, a or empty for all but the umask)
+	var who fs.FileMode
 
 	i := 0
-	for i < len(part) && (part[i] == 'u' || part[i] == 'g' || part[i] == 'o' || part[i] == 'a') {
-		who += string(part[i])
+	for i < len(part) && strings.IndexByte("ugoa", part[i]) >= 0 {
+		who |= whoMask(part[i])
 		i++
 	}
 
-	if who == "" || who == "a" {
-		who = "ugo"
+	masked := who == 0
+	if masked {
+		who = modeBits
 	}
 
-	if i >= len(part) {
-		return mode
-	}
+	// Each operator applies to the permissions up to the next operator
+	for i < len(part) {
+		op := part[i]
+		i++
+
+		j := i
+		for j < len(part) && strings.IndexByte("+-=", part[j]) < 0 {
+			j++
+		}
 
-	// Parse operator (+, -, =)
-	op := part[i]
-	i++
+		bits := permBits(mode, part[i:j]) & who
+		if masked {
+			bits &^= umask
+		}
+
+		i = j
+
+		switch op {
+		case '+':
+			mode |= bits
+		case '-':
+			mode &^= bits
+		case '=':
+			mask := who
+			if mode.IsDir() {
+				// Directories keep their set-id bits unless named explicitly
+				mask &^= fs.ModeSetuid | fs.ModeSetgid
+			}
 
-	if i >= len(part) {
-		return mode
+			mode = (mode &^ mask) | bits
+		}
 	}
 
-	// Parse permissions (r, w, x, X, s, t)
-	perms := part[i:]
+	return mode
+}
 
-	// Calculate permission bits
+// permBits returns the bits named by perms for every class; the caller masks
+// them to the classes being changed.
+func permBits(mode fs.FileMode, perms string) fs.FileMode {
 	var bits fs.FileMode
 
-	for _, p := range perms {
-		switch p {
+	for i := range len(perms) {
+		switch perms[i] {
 		case 'r':
-			if strings.Contains(who, "u") {
-				bits |= 0400
-			}
-
-			if strings.Contains(who, "g") {
-				bits |= 0040
-			}
-
-			if strings.Contains(who, "o") {
-				bits |= 0004
-			}
+			bits |= 0o444
 		case 'w':
-			if strings.Contains(who, "u") {
-				bits |= 0200
-			}
-
-			if strings.Contains(who, "g") {
-				bits |= 0020
-			}
-
-			if strings.Contains(who, "o") {
-				bits |= 0002
-			}
+			bits |= 0o222
 		case 'x':
-			if strings.Contains(who, "u") {
-				bits |= 0100
-			}
-
-			if strings.Contains(who, "g") {
-				bits |= 0010
-			}
-
-			if strings.Contains(who, "o") {
-				bits |= 0001
+			bits |= 0o111
+		case 'X':
+			if mode.IsDir() || mode&0o111 != 0 {
+				bits |= 0o111
 			}
+		case 's':
+			bits |= fs.ModeSetuid | fs.ModeSetgid
+		case 't':
+			bits |= fs.ModeSticky
+		case 'u':
+			bits |= replicate((mode >> 6) & 7)
+		case 'g':
+			bits |= replicate((mode >> 3) & 7)
+		case 'o':
+			bits |= replicate(mode & 7)
 		}
 	}
 
-	switch op {
-	case '+':
-		mode |= bits
-	case '-':
-		mode &^= bits
-	case '=':
-		// Clear existing bits for the specified users, then set new ones
-		var clearMask fs.FileMode
-		if strings.Contains(who, "u") {
-			clearMask |= 0700
-		}
-
-		if strings.Contains(who, "g") {
-			clearMask |= 0070
-		}
-
-		if strings.Contains(who, "o") {
-			clearMask |= 0007
-		}
-
-		mode = (mode &^ clearMask) | bits
-	}
+	return bits
+}
 
-	return mode
+// replicate copies a 3-bit permission triple to user, group and other.
+func replicate(p fs.FileMode) fs.FileMode {
+	return p<<6 | p<<3 | p
 }
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 // TestIsAllDigits covers the pure isAllDigits helper on all platforms.
@@ -60,10 +62,10 @@ func TestRunChmodErrors(t *testing.T) {
 		_ = os.WriteFile(f, []byte("x"), 0644)
 
 		var buf bytes.Buffer
-		// parseSymbolicMode never errors, so an odd symbolic string is a no-op
-		// rather than an error; assert it does not error out.
-		if err := RunChmod(&buf, []string{"u+x", f}, ChmodOptions{}); err != nil {
-			t.Errorf("unexpected error: %v", err)
+		for _, mode := range []string{"u+q", "x+u", "u", "u+x,", "g=uo"} {
+			if err := RunChmod(&buf, []string{mode, f}, ChmodOptions{}); !cmderr.IsInvalidInput(err) {
+				t.Errorf("RunChmod(%q) error = %v, want invalid input", mode, err)
+			}
 		}
 	})
 
@@ -150,10 +152,20 @@ func TestRunChmodExecutes(t *testing.T) {
 
 	t.Run("nonexistent target silent", func(t *testing.T) {
 		var buf bytes.Buffer
-		// Non-recursive on a missing file: chmodFile errors but RunChmod swallows
-		// it (writes to stderr) and returns nil.
-		if err := RunChmod(&buf, []string{"644", filepath.Join(dir, "ghost.txt")}, ChmodOptions{Silent: true}); err != nil {
-			t.Errorf("expected nil (errors logged), got %v", err)
+		// Non-recursive on a missing file: the error is not printed with -f
+		// but the exit status is still 1.
+		if err := RunChmod(&buf, []string{"644", filepath.Join(dir, "ghost.txt")}, ChmodOptions{Silent: true}); cmderr.ExitCodeFor(err) != 1 {
+			t.Errorf("expected exit status 1, got %v", err)
 		}
 	})
 }
+
+// TestOctalSpecialBits checks the set-id and sticky bits survive the
+// numeric round trip.
+func TestOctalSpecialBits(t *testing.T) {
+	for _, m := range []uint32{0o755, 0o4755, 0o2750, 0o1777, 0o7000} {
+		if got := toOctal(fromOctal(m)); got != m {
+			t.Errorf("toOctal(fromOctal(%04o)) = %04o", m, got)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package chmod
+
+import "io/fs"
+
+// currentUmask returns 0: the platform has no umask.
+func currentUmask() fs.FileMode {
+	return 0
+}
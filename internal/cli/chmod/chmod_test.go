@@ -205,11 +205,20 @@ func TestApplySymbolicPart(t *testing.T) {
 		{"a+x", 0644, "a+x", 0755},
 		{"go-rwx", 0777, "go-rwx", 0700},
 		{"u=rwx", 0000, "u=rwx", 0700},
+		{"multiple ops", 0644, "u+x-w", 0544},
+		{"X on file without x", 0644, "a+X", 0644},
+		{"X on executable", 0744, "a+X", 0755},
+		{"X on directory", fs.ModeDir | 0700, "go+X", fs.ModeDir | 0711},
+		{"copy user", 0750, "o=u", 0757},
+		{"setuid", 0755, "u+s", fs.ModeSetuid | 0755},
+		{"setgid", 0755, "g+s", fs.ModeSetgid | 0755},
+		{"sticky", fs.ModeDir | 0777, "+t", fs.ModeDir | fs.ModeSticky | 0777},
+		{"equals clears setuid", fs.ModeSetuid | 0755, "u=rwx", 0755},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := applySymbolicPart(tt.initial, tt.part)
+			got := applySymbolicPart(tt.initial, tt.part, 0)
 			if got != tt.expected {
 				t.Errorf("applySymbolicPart(%o, %q) = %o, want %o", tt.initial, tt.part, got, tt.expected)
 			}
@@ -217,6 +226,61 @@ func TestApplySymbolicPart(t *testing.T) {
 	}
 }
 
+func TestApplySymbolicPartUmask(t *testing.T) {
+	tests := []struct {
+		initial  fs.FileMode
+		part     string
+		expected fs.FileMode
+	}{
+		{0111, "+w", 0311},
+		{0111, "a+w", 0333},
+		{0777, "-w", 0577},
+		{0000, "=rwx", 0755},
+		{0644, "+x", 0755},
+		{fs.ModeDir | 0777, "+t", fs.ModeDir | fs.ModeSticky | 0777},
+	}
+
+	for _, tt := range tests {
+		if got := applySymbolicPart(tt.initial, tt.part, 0o022); got != tt.expected {
+			t.Errorf("applySymbolicPart(%o, %q) with umask 022 = %o, want %o", tt.initial, tt.part, got, tt.expected)
+		}
+	}
+}
+
+func TestRunChmodUmask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	old := umask
+	umask = func() fs.FileMode { return 0o022 }
+
+	t.Cleanup(func() { umask = old })
+
+	f := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(f, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chmod(f, 0o111); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RunChmod(&buf, []string{"+w", f}, ChmodOptions{}); err != nil {
+		t.Fatalf("RunChmod() error = %v", err)
+	}
+
+	info, err := os.Stat(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := info.Mode().Perm(); got != 0o311 {
+		t.Errorf("chmod +w on 111 with umask 022 = %o, want 311", got)
+	}
+}
+
 func TestParseSymbolicMode(t *testing.T) {
 	tests := []struct {
 		mode     string
@@ -226,6 +290,7 @@ func TestParseSymbolicMode(t *testing.T) {
 		{"u+x", 0644, 0744},
 		{"u+x,g+x", 0644, 0754},
 		{"a=r", 0777, 0444},
+		{"u=rw,go=r", 0777, 0644},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,17 @@
+//go:build unix
+
+package chmod
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// currentUmask returns the process umask. It can only be read by setting
+// it, so the old value is put straight back.
+func currentUmask() fs.FileMode {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+
+	return fs.FileMode(mask) & 0o777 //nolint:gosec // a umask fits in nine bits
+}
@@ -34,6 +34,10 @@ func RunChown(w io.Writer, args []string, opts ChownOptions) error {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "chown: missing operand")
 	}
 
+	if !chownSupported {
+		return cmderr.Wrap(cmderr.ErrUnsupported, "chown: file ownership is not supported on Windows; use icacls or takeown")
+	}
+
 	ownerGroup := args[0]
 	files := args[1:]
 
@@ -43,6 +47,24 @@ func RunChown(w io.Writer, args []string, opts ChownOptions) error {
 		return fmt.Errorf("chown: %w", err)
 	}
 
+	fromUID, fromGID := -1, -1
+
+	if opts.From != "" {
+		if fromUID, fromGID, err = parseOwnerGroup(opts.From, ""); err != nil {
+			return fmt.Errorf("chown: --from: %w", err)
+		}
+	}
+
+	failed := false
+
+	report := func(err error) {
+		failed = true
+
+		if !opts.Silent {
+			_, _ = fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+		}
+	}
+
 	for _, file := range files {
 		if opts.PreserveRoot && opts.Recursive && (file == "/" || filepath.Clean(file) == "/") {
 			return cmderr.Wrap(cmderr.ErrPermission, "chown: it is dangerous to operate recursively on '/'")
@@ -51,27 +73,35 @@ func RunChown(w io.Writer, args []string, opts ChownOptions) error {
 		if opts.Recursive {
 			err := filepath.WalkDir(file, func(path string, d fs.DirEntry, err error) error {
 				if err != nil {
-					if !opts.Silent {
-						_, _ = fmt.Fprintf(os.Stderr, "chown: cannot access '%s': %v\n", path, err)
-					}
-
+					report(fmt.Errorf("cannot access '%s': %w", path, err))
 					return nil
 				}
 
-				return chownFile(w, path, uid, gid, opts)
+				// Symlinks met during the walk are changed themselves, never
+				// their targets, as with GNU chown -R -P
+				fileOpts := opts
+				if path != file && d.Type()&fs.ModeSymlink != 0 {
+					fileOpts.NoDereference = true
+				}
+
+				if err := chownFile(w, path, uid, gid, fromUID, fromGID, fileOpts); err != nil {
+					report(err)
+				}
+
+				return nil
 			})
 			if err != nil {
 				return err
 			}
-		} else {
-			if err := chownFile(w, file, uid, gid, opts); err != nil {
-				if !opts.Silent {
-					_, _ = fmt.Fprintf(os.Stderr, "chown: %v\n", err)
-				}
-			}
+		} else if err := chownFile(w, file, uid, gid, fromUID, fromGID, opts); err != nil {
+			report(err)
 		}
 	}
 
+	if failed {
+		return cmderr.PartialFailure(1, "chown: some operands failed")
+	}
+
 	return nil
 }
 
@@ -92,10 +122,16 @@ func parseOwnerGroup(spec string, reference string) (int, int, error) {
 	gid := -1
 
 	// Parse owner:group or owner.group
-	var owner, group string
+	var (
+		owner, group string
+		loginGroup   bool
+	)
+
 	if idx := strings.IndexAny(spec, ":."); idx != -1 {
 		owner = spec[:idx]
 		group = spec[idx+1:]
+		// "OWNER:" also sets the group to OWNER's login group
+		loginGroup = group == "" && owner != ""
 	} else {
 		owner = spec
 	}
@@ -112,6 +148,15 @@ func parseOwnerGroup(spec string, reference string) (int, int, error) {
 
 			uid, _ = strconv.Atoi(u.Uid)
 		}
+
+		if loginGroup {
+			u, err := user.LookupId(strconv.Itoa(uid))
+			if err != nil {
+				return -1, -1, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("chown: cannot find login group of '%s'", owner))
+			}
+
+			gid, _ = strconv.Atoi(u.Gid)
+		}
 	}
 
 	// Parse group
@@ -131,8 +176,22 @@ func parseOwnerGroup(spec string, reference string) (int, int, error) {
 	return uid, gid, nil
 }
 
-func chownFile(w io.Writer, path string, uid, gid int, opts ChownOptions) error {
-	var err error
+func chownFile(w io.Writer, path string, uid, gid, fromUID, fromGID int, opts ChownOptions) error {
+	stat := os.Stat
+	if opts.NoDereference {
+		stat = os.Lstat
+	}
+
+	info, err := stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access '%s': %w", path, err)
+	}
+
+	oldUID, oldGID, _ := getFileOwner(info)
+
+	if (fromUID != -1 && fromUID != oldUID) || (fromGID != -1 && fromGID != oldGID) {
+		return nil
+	}
 
 	if opts.NoDereference {
 		err = os.Lchown(path, uid, gid)
@@ -144,18 +203,22 @@ func chownFile(w io.Writer, path string, uid, gid int, opts ChownOptions) error
 		return fmt.Errorf("changing ownership of '%s': %w", path, err)
 	}
 
-	if opts.Verbose {
-		ownerStr := fmt.Sprintf("%d", uid)
-		if uid == -1 {
-			ownerStr = ""
-		}
+	newUID, newGID := oldUID, oldGID
+	if uid != -1 {
+		newUID = uid
+	}
 
-		groupStr := fmt.Sprintf("%d", gid)
-		if gid == -1 {
-			groupStr = ""
-		}
+	if gid != -1 {
+		newGID = gid
+	}
+
+	changed := newUID != oldUID || newGID != oldGID
 
-		_, _ = fmt.Fprintf(w, "ownership of '%s' changed to %s:%s\n", path, ownerStr, groupStr)
+	switch {
+	case changed && (opts.Verbose || opts.Changes):
+		_, _ = fmt.Fprintf(w, "changed ownership of '%s' from %d:%d to %d:%d\n", path, oldUID, oldGID, newUID, newGID)
+	case opts.Verbose:
+		_, _ = fmt.Fprintf(w, "ownership of '%s' retained as %d:%d\n", path, newUID, newGID)
 	}
 
 	return nil
@@ -2,10 +2,16 @@ package chown
 
 import (
 	"bytes"
+	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 func TestRunChown(t *testing.T) {
@@ -202,3 +208,68 @@ func TestLchown(t *testing.T) {
 		t.Logf("Lchown() error (may be expected): %v", err)
 	}
 }
+
+func TestRunChown_Own(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping chown tests on Windows")
+	}
+
+	// Giving a file to its current owner needs no privileges
+	file := filepath.Join(t.TempDir(), "own.txt")
+	_ = os.WriteFile(file, []byte("content"), 0644)
+
+	spec := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+
+	var buf bytes.Buffer
+	if err := RunChown(&buf, []string{spec, file}, ChownOptions{Changes: true}); err != nil {
+		t.Fatalf("RunChown() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("-c reported an unchanged file: %q", buf.String())
+	}
+
+	if err := RunChown(&buf, []string{spec, file}, ChownOptions{Verbose: true}); err != nil {
+		t.Fatalf("RunChown() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "retained") {
+		t.Errorf("-v output = %q", buf.String())
+	}
+
+	buf.Reset()
+
+	// --from that does not match leaves the file alone
+	if err := RunChown(&buf, []string{spec, file}, ChownOptions{Verbose: true, From: fmt.Sprint(os.Getuid() + 1)}); err != nil {
+		t.Fatalf("RunChown() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("--from mismatch still processed the file: %q", buf.String())
+	}
+
+	err := RunChown(&buf, []string{spec, filepath.Join(t.TempDir(), "missing"), file}, ChownOptions{Silent: true})
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("RunChown() missing file error = %v, want exit status 1", err)
+	}
+}
+
+func TestParseOwnerGroup_LoginGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping chown tests on Windows")
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		t.Skipf("current user: %v", err)
+	}
+
+	uid, gid, err := parseOwnerGroup(u.Uid+":", "")
+	if err != nil {
+		t.Fatalf("parseOwnerGroup() error = %v", err)
+	}
+
+	if strconv.Itoa(uid) != u.Uid || strconv.Itoa(gid) != u.Gid {
+		t.Errorf("parseOwnerGroup(%q) = %d, %d; want %s, %s", u.Uid+":", uid, gid, u.Uid, u.Gid)
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"syscall"
 )
 
+// chownSupported reports whether the platform has Unix file ownership.
+const chownSupported = true
+
 // getFileOwner returns the UID and GID of a file
 func getFileOwner(info os.FileInfo) (int, int, error) {
 	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
@@ -6,6 +6,9 @@ import (
 	"os"
 )
 
+// chownSupported reports whether the platform has Unix file ownership.
+const chownSupported = false
+
 // getFileOwner returns the UID and GID of a file (not supported on Windows)
 func getFileOwner(info os.FileInfo) (int, int, error) {
 	// Windows doesn't have Unix-style UID/GID
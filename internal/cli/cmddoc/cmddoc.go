@@ -0,0 +1,473 @@
+// Package cmddoc renders man pages and markdown reference pages from a cobra
+// command tree, one page per command.
+package cmddoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Output formats
+const (
+	FormatMan      = "man"
+	FormatMarkdown = "markdown"
+)
+
+// Options configures the docs generate command behavior
+type Options struct {
+	Format       string           // man or markdown
+	OutDir       string           // directory the pages are written to
+	Section      string           // man section (default: 1)
+	Extra        []*cobra.Command // commands documented as children of the root, e.g. plugins
+	OutputFormat output.Format    // output format (text, json)
+}
+
+// Result represents the pages written by RunGenerate
+type Result struct {
+	Format string   `json:"format"`
+	OutDir string   `json:"out_dir"`
+	Files  []string `json:"files"`
+}
+
+// page is one command's documentation, split into the parts both formats
+// render.
+type page struct {
+	cmd         *cobra.Command
+	path        string // full command path, e.g. "omni lock acquire"
+	description string
+	examples    string
+	parent      *page
+	children    []*page
+}
+
+// RunGenerate writes one page per available command under root to
+// opts.OutDir. Hidden and deprecated commands, help and completion are
+// skipped. A command's examples come from its Example field and from the
+// "Examples:" section of its Long text, which is left out of the
+// description. Pages carry no date, so regenerating an unchanged tree
+// produces identical files.
+func RunGenerate(w io.Writer, root *cobra.Command, opts Options) error {
+	if opts.Format == "" {
+		opts.Format = FormatMan
+	}
+
+	if opts.Format == "md" {
+		opts.Format = FormatMarkdown
+	}
+
+	if opts.Format != FormatMan && opts.Format != FormatMarkdown {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("docs: unknown format %q (want man or markdown)", opts.Format))
+	}
+
+	if opts.OutDir == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "docs: output directory is required")
+	}
+
+	if opts.Section == "" {
+		opts.Section = "1"
+	}
+
+	if err := os.MkdirAll(opts.OutDir, 0o755); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("docs: %v", err))
+	}
+
+	tree := buildPage(root, nil)
+
+	for _, c := range opts.Extra {
+		if documented(c) && !hasChild(tree, c.Name()) {
+			tree.children = append(tree.children, buildPage(c, tree))
+		}
+	}
+
+	result := Result{Format: opts.Format, OutDir: opts.OutDir, Files: []string{}}
+
+	var walk func(p *page) error
+
+	walk = func(p *page) error {
+		name, data := renderPage(p, opts)
+		file := filepath.Join(opts.OutDir, name)
+
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("docs: %v", err))
+		}
+
+		result.Files = append(result.Files, file)
+
+		for _, child := range p.children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(tree); err != nil {
+		return err
+	}
+
+	if opts.OutputFormat == output.FormatJSON {
+		return json.NewEncoder(w).Encode(result)
+	}
+
+	kind := "man pages"
+	if opts.Format == FormatMarkdown {
+		kind = "markdown pages"
+	}
+
+	_, _ = fmt.Fprintf(w, "Generated %d %s in %s\n", len(result.Files), kind, opts.OutDir)
+
+	return nil
+}
+
+// buildPage collects the documentation of c and its documented subcommands.
+func buildPage(c *cobra.Command, parent *page) *page {
+	p := &page{cmd: c, parent: parent}
+
+	p.path = c.Name()
+	if parent != nil {
+		p.path = parent.path + " " + c.Name()
+	}
+
+	long := c.Long
+	if strings.TrimSpace(long) == "" {
+		long = c.Short
+	}
+
+	p.description, p.examples = splitExamples(long)
+
+	if c.Example != "" {
+		p.examples = strings.TrimSpace(dedent(c.Example) + "\n" + p.examples)
+	}
+
+	for _, child := range c.Commands() {
+		if documented(child) {
+			p.children = append(p.children, buildPage(child, p))
+		}
+	}
+
+	return p
+}
+
+// documented reports whether c gets a page of its own.
+func documented(c *cobra.Command) bool {
+	return c.IsAvailableCommand() && c.Name() != "completion"
+}
+
+func hasChild(p *page, name string) bool {
+	for _, child := range p.children {
+		if child.cmd.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitExamples separates the "Examples:" section of a Long text from the
+// rest. The section runs from its heading to the next unindented line.
+func splitExamples(long string) (description, examples string) {
+	lines := strings.Split(strings.Trim(long, "\n"), "\n")
+
+	var desc, ex []string
+
+	inExamples := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case line == trimmed && (strings.EqualFold(trimmed, "Examples:") || strings.EqualFold(trimmed, "Example:")):
+			inExamples = true
+
+			continue
+		case inExamples && trimmed != "" && line == trimmed:
+			inExamples = false
+		}
+
+		if inExamples {
+			ex = append(ex, line)
+		} else {
+			desc = append(desc, line)
+		}
+	}
+
+	// Only blank lines are trimmed: a leading indent may be layout
+	description = strings.TrimRight(strings.TrimLeft(strings.Join(desc, "\n"), "\n"), " \t\n")
+	examples = strings.TrimSpace(dedent(strings.Join(ex, "\n")))
+
+	return description, examples
+}
+
+// dedent removes the indentation common to all non-blank lines of s.
+func dedent(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		} else {
+			lines[i] = strings.TrimLeft(line, " \t")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// paragraphs splits text at blank lines. A paragraph with an indented line
+// is preformatted: lists and tables in Long texts rely on their layout.
+func paragraphs(text string) (paras []string, preformatted []bool) {
+	for para := range strings.SplitSeq(text, "\n\n") {
+		para = strings.Trim(para, "\n")
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+
+		pre := false
+
+		for line := range strings.SplitSeq(para, "\n") {
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+				pre = true
+
+				break
+			}
+		}
+
+		paras = append(paras, para)
+		preformatted = append(preformatted, pre)
+	}
+
+	return paras, preformatted
+}
+
+// renderPage returns the file name and contents of p's page.
+func renderPage(p *page, opts Options) (string, []byte) {
+	if opts.Format == FormatMarkdown {
+		return strings.ReplaceAll(p.path, " ", "_") + ".md", renderMarkdown(p)
+	}
+
+	return strings.ReplaceAll(p.path, " ", "-") + "." + opts.Section, renderMan(p, opts.Section)
+}
+
+// flagSets returns the visible local and inherited flags of c.
+func flagSets(c *cobra.Command) (local, inherited *pflag.FlagSet) {
+	return visible(c.NonInheritedFlags()), visible(c.InheritedFlags())
+}
+
+func visible(fs *pflag.FlagSet) *pflag.FlagSet {
+	out := pflag.NewFlagSet("", pflag.ContinueOnError)
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden {
+			out.AddFlag(f)
+		}
+	})
+
+	return out
+}
+
+// renderMarkdown renders p in the layout of cobra's markdown generator.
+func renderMarkdown(p *page) []byte {
+	var b bytes.Buffer
+
+	c := p.cmd
+
+	fmt.Fprintf(&b, "## %s\n\n%s\n\n", p.path, c.Short)
+
+	if p.description != "" {
+		b.WriteString("### Synopsis\n\n")
+
+		paras, pre := paragraphs(p.description)
+		for i, para := range paras {
+			if pre[i] {
+				fmt.Fprintf(&b, "```\n%s\n```\n\n", para)
+			} else {
+				fmt.Fprintf(&b, "%s\n\n", para)
+			}
+		}
+	}
+
+	if c.Runnable() {
+		fmt.Fprintf(&b, "```\n%s\n```\n\n", useLine(p))
+	}
+
+	if p.examples != "" {
+		fmt.Fprintf(&b, "### Examples\n\n```\n%s\n```\n\n", p.examples)
+	}
+
+	local, inherited := flagSets(c)
+
+	if local.HasFlags() {
+		fmt.Fprintf(&b, "### Options\n\n```\n%s```\n\n", local.FlagUsages())
+	}
+
+	if inherited.HasFlags() {
+		fmt.Fprintf(&b, "### Options inherited from parent commands\n\n```\n%s```\n\n", inherited.FlagUsages())
+	}
+
+	if p.parent != nil || len(p.children) > 0 {
+		b.WriteString("### SEE ALSO\n\n")
+
+		if p.parent != nil {
+			fmt.Fprintf(&b, "* [%s](%s.md)\t - %s\n", p.parent.path, strings.ReplaceAll(p.parent.path, " ", "_"), p.parent.cmd.Short)
+		}
+
+		for _, child := range p.children {
+			fmt.Fprintf(&b, "* [%s](%s.md)\t - %s\n", child.path, strings.ReplaceAll(child.path, " ", "_"), child.cmd.Short)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
+
+// renderMan renders p as a roff man page.
+func renderMan(p *page, section string) []byte {
+	var b bytes.Buffer
+
+	c := p.cmd
+	name := strings.ReplaceAll(p.path, " ", "-")
+
+	fmt.Fprintf(&b, ".TH \"%s\" \"%s\" \"\" \"omni\" \"Omni Manual\"\n", strings.ToUpper(name), section)
+	b.WriteString(".nh\n.ad l\n")
+
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", roff(name), roff(c.Short))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n\\fB%s\\fP\n", roff(useLine(p)))
+
+	if p.description != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+
+		paras, pre := paragraphs(p.description)
+		for i, para := range paras {
+			if pre[i] {
+				fmt.Fprintf(&b, ".PP\n.nf\n%s\n.fi\n", roffLines(para))
+			} else {
+				fmt.Fprintf(&b, ".PP\n%s\n", roffLines(para))
+			}
+		}
+	}
+
+	local, inherited := flagSets(c)
+
+	if local.HasFlags() {
+		b.WriteString(".SH OPTIONS\n")
+		manFlags(&b, local)
+	}
+
+	if inherited.HasFlags() {
+		b.WriteString(".SH OPTIONS INHERITED FROM PARENT COMMANDS\n")
+		manFlags(&b, inherited)
+	}
+
+	if p.examples != "" {
+		fmt.Fprintf(&b, ".SH EXAMPLES\n.PP\n.RS\n.nf\n%s\n.fi\n.RE\n", roffLines(p.examples))
+	}
+
+	var refs []string
+
+	if p.parent != nil {
+		refs = append(refs, manRef(p.parent, section))
+	}
+
+	for _, child := range p.children {
+		refs = append(refs, manRef(child, section))
+	}
+
+	if len(refs) > 0 {
+		fmt.Fprintf(&b, ".SH SEE ALSO\n%s\n", strings.Join(refs, ", "))
+	}
+
+	return b.Bytes()
+}
+
+func manRef(p *page, section string) string {
+	return fmt.Sprintf("\\fB%s\\fP(%s)", roff(strings.ReplaceAll(p.path, " ", "-")), section)
+}
+
+func manFlags(b *bytes.Buffer, fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		varname, usage := pflag.UnquoteUsage(f)
+
+		b.WriteString(".TP\n")
+
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			fmt.Fprintf(b, "\\fB\\-%s\\fP, ", roff(f.Shorthand))
+		}
+
+		fmt.Fprintf(b, "\\fB\\-\\-%s\\fP", roff(f.Name))
+
+		if varname != "" {
+			fmt.Fprintf(b, " \\fI%s\\fP", roff(varname))
+		}
+
+		b.WriteString("\n" + roff(usage))
+
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "[]" && f.DefValue != "0" && f.DefValue != "0s" {
+			fmt.Fprintf(b, " (default %s)", roff(f.DefValue))
+		}
+
+		b.WriteString("\n")
+	})
+}
+
+// useLine is the command's usage line without cobra's "[flags]" suffix
+// for commands that take none.
+func useLine(p *page) string {
+	line := p.cmd.UseLine()
+
+	if p.parent == nil {
+		return line
+	}
+
+	// UseLine is based on the cobra tree; Extra commands are not in it
+	if !strings.HasPrefix(line, p.parent.path+" ") {
+		line = p.parent.path + " " + line
+	}
+
+	return line
+}
+
+// roff escapes text for use inside a roff line.
+func roff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+
+	return strings.ReplaceAll(s, "-", `\-`)
+}
+
+// roffLines escapes a block of text, protecting lines that would otherwise
+// be read as requests.
+func roffLines(s string) string {
+	lines := strings.Split(roff(s), "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
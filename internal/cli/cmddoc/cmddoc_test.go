@@ -0,0 +1,170 @@
+package cmddoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func testTree() *cobra.Command {
+	root := &cobra.Command{Use: "tool", Short: "A test tool"}
+	root.PersistentFlags().Bool("json", false, "output as JSON")
+
+	parent := &cobra.Command{
+		Use:   "lock",
+		Short: "Lock things",
+		Long: `Lock things.
+
+Subcommands:
+  acquire    Take a lock
+
+Examples:
+  tool lock acquire a.lock`,
+	}
+
+	acquire := &cobra.Command{
+		Use:   "acquire FILE",
+		Short: "Take a lock",
+		Long: `Take a lock on FILE.
+.dot lines and back\slashes are escaped.
+
+Examples:
+  tool lock acquire a.lock
+  tool lock acquire -n a.lock`,
+		Run: func(*cobra.Command, []string) {},
+	}
+	acquire.Flags().BoolP("nonblock", "n", false, "fail at once")
+	acquire.Flags().Duration("timeout", 0, "give up after this long")
+
+	hidden := &cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}}
+
+	root.AddCommand(parent, hidden)
+	parent.AddCommand(acquire)
+
+	return root
+}
+
+func TestRunGenerate(t *testing.T) {
+	t.Run("man pages", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var buf bytes.Buffer
+		if err := RunGenerate(&buf, testTree(), Options{Format: FormatMan, OutDir: dir}); err != nil {
+			t.Fatalf("RunGenerate() error = %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "Generated 3 man pages") {
+			t.Errorf("output = %q", buf.String())
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "tool-secret.1")); err == nil {
+			t.Error("hidden command should not get a page")
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "tool-lock-acquire.1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		page := string(data)
+
+		for _, want := range []string{
+			`.TH "TOOL-LOCK-ACQUIRE" "1"`,
+			`tool\-lock\-acquire \- Take a lock`,
+			`\fBtool lock acquire FILE [flags]\fP`,
+			`\&.dot lines and back\eslashes are escaped.`,
+			`\fB\-n\fP, \fB\-\-nonblock\fP`,
+			".SH OPTIONS INHERITED FROM PARENT COMMANDS",
+			".SH EXAMPLES\n.PP\n.RS\n.nf\ntool lock acquire a.lock\ntool lock acquire \\-n a.lock\n.fi",
+			`\fBtool\-lock\fP(1)`,
+		} {
+			if !strings.Contains(page, want) {
+				t.Errorf("page missing %q:\n%s", want, page)
+			}
+		}
+
+		if strings.Contains(page, "default 0s") {
+			t.Error("zero default should not be shown")
+		}
+
+		if strings.Contains(page, "Examples:") {
+			t.Error("examples heading should be left out of the description")
+		}
+	})
+
+	t.Run("markdown with extra commands", func(t *testing.T) {
+		dir := t.TempDir()
+		extra := &cobra.Command{Use: "hello [ARG]...", Short: "A plugin", Run: func(*cobra.Command, []string) {}}
+
+		var buf bytes.Buffer
+
+		opts := Options{Format: "md", OutDir: dir, Extra: []*cobra.Command{extra}, OutputFormat: output.FormatJSON}
+		if err := RunGenerate(&buf, testTree(), opts); err != nil {
+			t.Fatalf("RunGenerate() error = %v", err)
+		}
+
+		var result Result
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		if result.Format != FormatMarkdown || len(result.Files) != 4 {
+			t.Errorf("result = %+v", result)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "tool_hello.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(data), "```\ntool hello [ARG]...\n```") {
+			t.Errorf("plugin page missing usage:\n%s", data)
+		}
+
+		data, err = os.ReadFile(filepath.Join(dir, "tool_lock.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, want := range []string{
+			"## tool lock\n",
+			"```\nSubcommands:\n  acquire    Take a lock\n```",
+			"### Examples\n\n```\ntool lock acquire a.lock\n```",
+			"* [tool lock acquire](tool_lock_acquire.md)",
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("page missing %q:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		a, b := t.TempDir(), t.TempDir()
+
+		for _, dir := range []string{a, b} {
+			if err := RunGenerate(&bytes.Buffer{}, testTree(), Options{OutDir: dir}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		x, _ := os.ReadFile(filepath.Join(a, "tool.1"))
+		y, _ := os.ReadFile(filepath.Join(b, "tool.1"))
+
+		if len(x) == 0 || !bytes.Equal(x, y) {
+			t.Error("regenerated pages differ")
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := RunGenerate(&bytes.Buffer{}, testTree(), Options{Format: "pdf", OutDir: t.TempDir()}); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
@@ -17,24 +17,53 @@ var (
 	ErrConflict     = errors.New("conflict")
 	ErrTimeout      = errors.New("timeout")
 	ErrUnsupported  = errors.New("unsupported")
+	ErrPartial      = errors.New("partial failure")
+
+	// ErrNetwork is a subclass of ErrIO, so IsIO also reports true for
+	// network failures while ExitCodeFor gives them their own code.
+	ErrNetwork error = &subclass{msg: "network error", parent: ErrIO}
 )
 
+// subclass is a sentinel that also matches its parent with errors.Is.
+type subclass struct {
+	msg    string
+	parent error
+}
+
+func (e *subclass) Error() string { return e.msg }
+
+func (e *subclass) Unwrap() error { return e.parent }
+
 // SilentError is an error that should set an exit code without printing a message.
 // Cobra's SilenceErrors must be checked, or the root command must handle this type.
+// Err optionally classifies the failure for machine-readable error output.
 type SilentError struct {
 	Code int
+	Err  error
 }
 
 func (e *SilentError) Error() string {
 	return ""
 }
 
+func (e *SilentError) Unwrap() error {
+	return e.Err
+}
+
 // SilentExit returns a SilentError with the given exit code.
 // Use for commands like grep that exit non-zero on "no match" without printing errors.
 func SilentExit(code int) error {
 	return &SilentError{Code: code}
 }
 
+// PartialFailure reports that some operands of a multi-file command failed
+// after their errors were already printed. code is the exit status: 1 for
+// commands whose POSIX counterpart fixes it, ExitCodeFor(ErrPartial)
+// otherwise. The failure is still reported as "partial" by --error-format json.
+func PartialFailure(code int, msg string) error {
+	return &SilentError{Code: code, Err: Wrap(ErrPartial, msg)}
+}
+
 // ExitError wraps an error with a specific exit code.
 type ExitError struct {
 	Err  error
@@ -84,6 +113,12 @@ func IsTimeout(err error) bool { return errors.Is(err, ErrTimeout) }
 // IsUnsupported reports whether err is, or wraps, ErrUnsupported.
 func IsUnsupported(err error) bool { return errors.Is(err, ErrUnsupported) }
 
+// IsPartial reports whether err is, or wraps, ErrPartial.
+func IsPartial(err error) bool { return errors.Is(err, ErrPartial) }
+
+// IsNetwork reports whether err is, or wraps, ErrNetwork.
+func IsNetwork(err error) bool { return errors.Is(err, ErrNetwork) }
+
 // ExitCodeFor maps an error to an exit code.
 // Returns 0 for nil errors.
 func ExitCodeFor(err error) int {
@@ -113,12 +148,16 @@ func ExitCodeFor(err error) int {
 		return 2
 	case errors.Is(err, ErrPermission):
 		return 3
+	case errors.Is(err, ErrNetwork):
+		return 8
 	case errors.Is(err, ErrIO):
 		return 4
 	case errors.Is(err, ErrTimeout):
 		return 5
 	case errors.Is(err, ErrUnsupported):
 		return 6
+	case errors.Is(err, ErrPartial):
+		return 7
 	default:
 		return 1
 	}
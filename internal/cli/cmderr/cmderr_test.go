@@ -23,6 +23,8 @@ func TestExitCodeFor(t *testing.T) {
 		{"raw ErrIO", cmderr.ErrIO, 4},
 		{"raw ErrTimeout", cmderr.ErrTimeout, 5},
 		{"raw ErrUnsupported", cmderr.ErrUnsupported, 6},
+		{"raw ErrPartial", cmderr.ErrPartial, 7},
+		{"raw ErrNetwork", cmderr.ErrNetwork, 8},
 		{"wrapped ErrNotFound", cmderr.Wrap(cmderr.ErrNotFound, "missing"), 1},
 		{"wrapped ErrConflict", cmderr.Wrap(cmderr.ErrConflict, "conflict"), 1},
 		{"wrapped ErrInvalidInput", cmderr.Wrap(cmderr.ErrInvalidInput, "bad flag"), 2},
@@ -30,6 +32,9 @@ func TestExitCodeFor(t *testing.T) {
 		{"wrapped ErrIO", cmderr.Wrap(cmderr.ErrIO, "io"), 4},
 		{"wrapped ErrTimeout", cmderr.Wrap(cmderr.ErrTimeout, "timeout"), 5},
 		{"wrapped ErrUnsupported", cmderr.Wrap(cmderr.ErrUnsupported, "nope"), 6},
+		{"wrapped ErrPartial", cmderr.Wrap(cmderr.ErrPartial, "some failed"), 7},
+		{"wrapped ErrNetwork", cmderr.Wrap(cmderr.ErrNetwork, "dial"), 8},
+		{"partial failure keeps its code", cmderr.PartialFailure(1, "cat: some operands failed"), 1},
 		{"fmt-wrapped not found", fmt.Errorf("file: %w", cmderr.ErrNotFound), 1},
 		{"unclassified", errors.New("something went wrong"), 1},
 		{"explicit code via WithExitCode", cmderr.WithExitCode(errors.New("custom"), 42), 42},
@@ -58,6 +63,8 @@ func TestWrap(t *testing.T) {
 		{"ErrConflict", cmderr.ErrConflict, "conflict"},
 		{"ErrTimeout", cmderr.ErrTimeout, "timeout"},
 		{"ErrUnsupported", cmderr.ErrUnsupported, "unsupported"},
+		{"ErrPartial", cmderr.ErrPartial, "partial failure"},
+		{"ErrNetwork", cmderr.ErrNetwork, "network error"},
 	}
 
 	for _, tt := range sentinels {
@@ -188,6 +195,9 @@ func TestIsClassHelpers(t *testing.T) {
 		{"IsConflict", cmderr.IsConflict, cmderr.ErrConflict},
 		{"IsTimeout", cmderr.IsTimeout, cmderr.ErrTimeout},
 		{"IsUnsupported", cmderr.IsUnsupported, cmderr.ErrUnsupported},
+		{"IsPartial", cmderr.IsPartial, cmderr.ErrPartial},
+		{"IsNetwork", cmderr.IsNetwork, cmderr.ErrNetwork},
+		{"IsIO network", cmderr.IsIO, cmderr.ErrNetwork},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
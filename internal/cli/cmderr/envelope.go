@@ -0,0 +1,86 @@
+package cmderr
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Stable error codes reported by --error-format json. Wrappers should
+// branch on these rather than on messages or exit codes.
+const (
+	CodeUsage       = "usage"
+	CodeNotFound    = "not_found"
+	CodePermission  = "permission"
+	CodeIO          = "io"
+	CodeConflict    = "conflict"
+	CodeTimeout     = "timeout"
+	CodeUnsupported = "unsupported"
+	CodePartial     = "partial"
+	CodeNetwork     = "network"
+	CodeError       = "error"
+)
+
+// Code returns the stable error code for err, or "" for nil.
+func Code(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrInvalidInput):
+		return CodeUsage
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrPermission):
+		return CodePermission
+	case errors.Is(err, ErrNetwork):
+		return CodeNetwork
+	case errors.Is(err, ErrIO):
+		return CodeIO
+	case errors.Is(err, ErrConflict):
+		return CodeConflict
+	case errors.Is(err, ErrTimeout):
+		return CodeTimeout
+	case errors.Is(err, ErrUnsupported):
+		return CodeUnsupported
+	case errors.Is(err, ErrPartial):
+		return CodePartial
+	default:
+		return CodeError
+	}
+}
+
+// Envelope is the JSON document written to stderr by --error-format json.
+type Envelope struct {
+	Error Detail `json:"error"`
+}
+
+// Detail describes a failed command.
+type Detail struct {
+	Code     string `json:"code"`
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+	Command  string `json:"command,omitempty"`
+}
+
+// NewEnvelope classifies err for machine-readable output. The message of a
+// silent error is taken from its classification, if any.
+func NewEnvelope(command string, err error) Envelope {
+	msg := err.Error()
+
+	var silent *SilentError
+	if errors.As(err, &silent) && silent.Err != nil {
+		msg = silent.Err.Error()
+	}
+
+	return Envelope{Error: Detail{
+		Code:     Code(err),
+		ExitCode: ExitCodeFor(err),
+		Message:  msg,
+		Command:  command,
+	}}
+}
+
+// WriteJSON writes the envelope for err as a single JSON line.
+func WriteJSON(w io.Writer, command string, err error) error {
+	return json.NewEncoder(w).Encode(NewEnvelope(command, err))
+}
@@ -0,0 +1,55 @@
+package cmderr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{cmderr.Wrap(cmderr.ErrInvalidInput, "bad flag"), cmderr.CodeUsage},
+		{cmderr.Wrap(cmderr.ErrNotFound, "missing"), cmderr.CodeNotFound},
+		{cmderr.Wrap(cmderr.ErrPermission, "denied"), cmderr.CodePermission},
+		{cmderr.Wrap(cmderr.ErrIO, "disk"), cmderr.CodeIO},
+		{cmderr.Wrap(cmderr.ErrNetwork, "dial"), cmderr.CodeNetwork},
+		{cmderr.PartialFailure(1, "cat: some operands failed"), cmderr.CodePartial},
+		{errors.New("boom"), cmderr.CodeError},
+	}
+
+	for _, tt := range tests {
+		if got := cmderr.Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cmderr.WriteJSON(&buf, "omni cat", cmderr.PartialFailure(1, "cat: some operands failed")); err != nil {
+		t.Fatal(err)
+	}
+
+	var env cmderr.Envelope
+	if err := json.Unmarshal(buf.Bytes(), &env); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+
+	want := cmderr.Detail{
+		Code:     cmderr.CodePartial,
+		ExitCode: 1,
+		Message:  "cat: some operands failed: partial failure",
+		Command:  "omni cat",
+	}
+
+	if env.Error != want {
+		t.Errorf("envelope = %+v, want %+v", env.Error, want)
+	}
+}
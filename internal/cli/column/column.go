@@ -11,6 +11,7 @@ import (
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/input"
+	"github.com/inovacc/omni/pkg/textwidth"
 )
 
 // ColumnOptions configures the column command behavior
@@ -144,9 +145,7 @@ func columnTable(w io.Writer, lines []string, opts ColumnOptions) error {
 
 	for _, row := range rows {
 		for i, field := range row {
-			if len(field) > colWidths[i] {
-				colWidths[i] = len(field)
-			}
+			colWidths[i] = max(colWidths[i], textwidth.String(field))
 		}
 	}
 
@@ -154,8 +153,8 @@ func columnTable(w io.Writer, lines []string, opts ColumnOptions) error {
 	if opts.ColumnHeaders != "" {
 		headers := strings.Split(opts.ColumnHeaders, ",")
 		for i, header := range headers {
-			if i < maxCols && len(header) > colWidths[i] {
-				colWidths[i] = len(header)
+			if i < maxCols {
+				colWidths[i] = max(colWidths[i], textwidth.String(header))
 			}
 		}
 		// Print headers
@@ -166,9 +165,9 @@ func columnTable(w io.Writer, lines []string, opts ColumnOptions) error {
 
 			if i < maxCols {
 				if opts.Right {
-					_, _ = fmt.Fprintf(w, "%*s", colWidths[i], header)
+					_, _ = fmt.Fprint(w, textwidth.PadLeft(header, colWidths[i]))
 				} else {
-					_, _ = fmt.Fprintf(w, "%-*s", colWidths[i], header)
+					_, _ = fmt.Fprint(w, textwidth.PadRight(header, colWidths[i]))
 				}
 			}
 		}
@@ -189,13 +188,13 @@ func columnTable(w io.Writer, lines []string, opts ColumnOptions) error {
 			}
 
 			if opts.Right {
-				_, _ = fmt.Fprintf(w, "%*s", colWidths[i], field)
+				_, _ = fmt.Fprint(w, textwidth.PadLeft(field, colWidths[i]))
 			} else {
 				// Don't pad the last column
 				if i == maxCols-1 {
 					_, _ = fmt.Fprint(w, field)
 				} else {
-					_, _ = fmt.Fprintf(w, "%-*s", colWidths[i], field)
+					_, _ = fmt.Fprint(w, textwidth.PadRight(field, colWidths[i]))
 				}
 			}
 		}
@@ -215,9 +214,7 @@ func columnFill(w io.Writer, lines []string, opts ColumnOptions) error {
 	// Find maximum width
 	maxWidth := 0
 	for _, line := range lines {
-		if len(line) > maxWidth {
-			maxWidth = len(line)
-		}
+		maxWidth = max(maxWidth, textwidth.String(line))
 	}
 
 	// Add padding
@@ -234,7 +231,7 @@ func columnFill(w io.Writer, lines []string, opts ColumnOptions) error {
 				_, _ = fmt.Fprintln(w)
 			}
 
-			_, _ = fmt.Fprintf(w, "%-*s", colWidth, lines[i])
+			_, _ = fmt.Fprint(w, textwidth.PadRight(lines[i], colWidth))
 		}
 
 		_, _ = fmt.Fprintln(w)
@@ -247,7 +244,7 @@ func columnFill(w io.Writer, lines []string, opts ColumnOptions) error {
 					if col == numCols-1 || idx+numRows >= len(lines) {
 						_, _ = fmt.Fprint(w, lines[idx])
 					} else {
-						_, _ = fmt.Fprintf(w, "%-*s", colWidth, lines[idx])
+						_, _ = fmt.Fprint(w, textwidth.PadRight(lines[idx], colWidth))
 					}
 				}
 			}
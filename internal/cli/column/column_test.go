@@ -191,6 +191,20 @@ func TestColumnTable(t *testing.T) {
 			t.Errorf("columnTable() output = %q", buf.String())
 		}
 	})
+
+	t.Run("wide characters align by display width", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := columnTable(&buf, []string{"東京 13", "Rio 6", "😀 1"}, ColumnOptions{Separator: " ", OutputSep: "  "})
+		if err != nil {
+			t.Fatalf("columnTable() error = %v", err)
+		}
+
+		want := "東京  13\nRio   6\n😀    1\n"
+		if buf.String() != want {
+			t.Errorf("columnTable() = %q, want %q", buf.String(), want)
+		}
+	})
 }
 
 func TestColumnFill(t *testing.T) {
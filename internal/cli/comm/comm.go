@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
@@ -16,8 +17,10 @@ type CommOptions struct {
 	Suppress1    bool          // -1: suppress column 1 (lines unique to FILE1)
 	Suppress2    bool          // -2: suppress column 2 (lines unique to FILE2)
 	Suppress3    bool          // -3: suppress column 3 (lines common to both)
-	CheckOrder   bool          // --check-order: check input is correctly sorted
+	CheckOrder   bool          // --check-order: fail as soon as the input is unsorted
 	NoCheckOrder bool          // --nocheck-order: do not check input order
+	IgnoreCase   bool          // -i: compare lines case-insensitively
+	Total        bool          // --total: print a summary line with the column counts
 	OutputDelim  string        // --output-delimiter: use STR as output delimiter
 	ZeroTerm     bool          // -z: line delimiter is NUL
 	OutputFormat output.Format // output format (text/json/table)
@@ -30,52 +33,31 @@ type CommResult struct {
 	Common        []string `json:"common"`
 }
 
-// RunComm compares two sorted files line by line
+// RunComm compares two sorted files line by line. Both inputs are read in
+// a single merge pass, so memory use does not grow with file size (except
+// for --json, which collects the columns). Unless --nocheck-order is given,
+// unsorted input is reported on standard error and makes comm exit with
+// status 1 once the output is complete; --check-order stops immediately.
 func RunComm(w io.Writer, args []string, opts CommOptions) error {
 	if len(args) < 2 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "comm: missing operand")
 	}
 
-	file1, file2 := args[0], args[1]
-
-	// Open files
-	var r1, r2 io.Reader
-
-	if file1 == "-" {
-		r1 = os.Stdin
-	} else {
-		f, err := os.Open(file1)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("comm: %s", err))
-			}
-			return fmt.Errorf("comm: %w", err)
-		}
-
-		defer func() { _ = f.Close() }()
-
-		r1 = f
+	if args[0] == "-" && args[1] == "-" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "comm: both files cannot be stdin")
 	}
 
-	if file2 == "-" {
-		if file1 == "-" {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, "comm: both files cannot be stdin")
-		}
-
-		r2 = os.Stdin
-	} else {
-		f, err := os.Open(file2)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("comm: %s", err))
-			}
-			return fmt.Errorf("comm: %w", err)
-		}
-
-		defer func() { _ = f.Close() }()
+	r1, err := openInput(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r1.Close() }()
 
-		r2 = f
+	r2, err := openInput(args[1])
+	if err != nil {
+		return err
 	}
+	defer func() { _ = r2.Close() }()
 
 	// Set default delimiter
 	delim := "\t"
@@ -88,141 +70,164 @@ func RunComm(w io.Writer, args []string, opts CommOptions) error {
 		lineDelim = 0
 	}
 
-	scanner1 := bufio.NewScanner(r1)
-	scanner2 := bufio.NewScanner(r2)
-
-	if opts.ZeroTerm {
-		scanner1.Split(splitFunc(lineDelim))
-		scanner2.Split(splitFunc(lineDelim))
-	}
-
-	var line1, line2 string
-
-	has1 := scanner1.Scan()
-	has2 := scanner2.Scan()
-
-	if has1 {
-		line1 = scanner1.Text()
-	}
-
-	if has2 {
-		line2 = scanner2.Text()
-	}
+	in1 := newInput(r1, 1, lineDelim, opts)
+	in2 := newInput(r2, 2, lineDelim, opts)
 
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
+	bw := bufio.NewWriter(w)
 
 	var (
-		prevLine1, prevLine2 string
-		result               CommResult
+		result CommResult
+		counts [3]int
 	)
 
-	for has1 || has2 {
-		// Check sort order if requested
-		if opts.CheckOrder && !opts.NoCheckOrder {
-			if has1 && prevLine1 != "" && line1 < prevLine1 {
-				return cmderr.Wrap(cmderr.ErrConflict, "comm: file 1 is not in sorted order")
+	emit := func(column int, line string) {
+		counts[column-1]++
+
+		if jsonMode {
+			switch column {
+			case 1:
+				result.UniqueToFile1 = append(result.UniqueToFile1, line)
+			case 2:
+				result.UniqueToFile2 = append(result.UniqueToFile2, line)
+			default:
+				result.Common = append(result.Common, line)
 			}
 
-			if has2 && prevLine2 != "" && line2 < prevLine2 {
-				return cmderr.Wrap(cmderr.ErrConflict, "comm: file 2 is not in sorted order")
-			}
+			return
 		}
 
-		if !has1 {
-			// Only file2 has lines
-			if jsonMode {
-				result.UniqueToFile2 = append(result.UniqueToFile2, line2)
-			} else {
-				printCommLine(w, opts, delim, 2, line2)
-			}
+		printCommLine(bw, opts, delim, lineDelim, column, line)
+	}
 
-			prevLine2 = line2
-			has2 = scanner2.Scan()
+	for err = errors.Join(in1.next(), in2.next()); err == nil && (in1.ok || in2.ok); {
+		switch c := compare(in1, in2, opts.IgnoreCase); {
+		case c < 0:
+			emit(1, in1.line)
+			err = in1.next()
+		case c > 0:
+			emit(2, in2.line)
+			err = in2.next()
+		default:
+			emit(3, in1.line)
+			err = errors.Join(in1.next(), in2.next())
+		}
+	}
 
-			if has2 {
-				line2 = scanner2.Text()
-			}
-		} else if !has2 {
-			// Only file1 has lines
-			if jsonMode {
-				result.UniqueToFile1 = append(result.UniqueToFile1, line1)
-			} else {
-				printCommLine(w, opts, delim, 1, line1)
-			}
+	if err != nil {
+		_ = bw.Flush()
+		return err
+	}
 
-			prevLine1 = line1
-			has1 = scanner1.Scan()
+	if jsonMode {
+		if err := f.Print(result); err != nil {
+			return err
+		}
+	} else {
+		if opts.Total {
+			_, _ = fmt.Fprintf(bw, "%d%s%d%s%d%stotal%c", counts[0], delim, counts[1], delim, counts[2], delim, lineDelim)
+		}
 
-			if has1 {
-				line1 = scanner1.Text()
-			}
-		} else if line1 < line2 {
-			// Line unique to file1
-			if jsonMode {
-				result.UniqueToFile1 = append(result.UniqueToFile1, line1)
-			} else {
-				printCommLine(w, opts, delim, 1, line1)
-			}
+		if err := bw.Flush(); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("comm: write: %s", err))
+		}
+	}
 
-			prevLine1 = line1
-			has1 = scanner1.Scan()
+	if in1.unsorted || in2.unsorted {
+		return cmderr.SilentExit(1)
+	}
 
-			if has1 {
-				line1 = scanner1.Text()
-			}
-		} else if line1 > line2 {
-			// Line unique to file2
-			if jsonMode {
-				result.UniqueToFile2 = append(result.UniqueToFile2, line2)
-			} else {
-				printCommLine(w, opts, delim, 2, line2)
-			}
+	return nil
+}
 
-			prevLine2 = line2
-			has2 = scanner2.Scan()
+// input is one side of the merge with its current line and order state.
+type input struct {
+	scanner  *bufio.Scanner
+	num      int
+	opts     CommOptions
+	line     string
+	ok       bool
+	seen     bool
+	unsorted bool
+}
 
-			if has2 {
-				line2 = scanner2.Text()
-			}
-		} else {
-			// Lines are equal
-			if jsonMode {
-				result.Common = append(result.Common, line1)
-			} else {
-				printCommLine(w, opts, delim, 3, line1)
-			}
+func newInput(r io.Reader, num int, delim byte, opts CommOptions) *input {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	scanner.Split(splitFunc(delim))
 
-			prevLine1 = line1
-			prevLine2 = line2
-			has1 = scanner1.Scan()
-			has2 = scanner2.Scan()
+	return &input{scanner: scanner, num: num, opts: opts}
+}
 
-			if has1 {
-				line1 = scanner1.Text()
-			}
+// next advances to the following line and checks that it does not sort
+// before the previous one.
+func (in *input) next() error {
+	prev, hadPrev := in.line, in.seen
 
-			if has2 {
-				line2 = scanner2.Text()
-			}
+	in.ok = in.scanner.Scan()
+	if !in.ok {
+		if err := in.scanner.Err(); err != nil {
+			return fmt.Errorf("comm: %w", err)
 		}
-	}
 
-	if err := scanner1.Err(); err != nil {
-		return fmt.Errorf("comm: %w", err)
+		return nil
 	}
 
-	if err := scanner2.Err(); err != nil {
-		return fmt.Errorf("comm: %w", err)
+	in.line = in.scanner.Text()
+	in.seen = true
+
+	if !hadPrev || in.opts.NoCheckOrder || in.unsorted || compareLines(in.line, prev, in.opts.IgnoreCase) >= 0 {
+		return nil
 	}
 
-	if jsonMode {
-		return f.Print(result)
+	if in.opts.CheckOrder {
+		return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("comm: file %d is not in sorted order", in.num))
 	}
 
+	in.unsorted = true
+	_, _ = fmt.Fprintf(os.Stderr, "comm: file %d is not in sorted order\n", in.num)
+
 	return nil
 }
 
+// compare orders the current lines; an exhausted input sorts last.
+func compare(in1, in2 *input, fold bool) int {
+	switch {
+	case !in1.ok:
+		return 1
+	case !in2.ok:
+		return -1
+	default:
+		return compareLines(in1.line, in2.line, fold)
+	}
+}
+
+func compareLines(a, b string, fold bool) int {
+	if fold {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+
+	return strings.Compare(a, b)
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("comm: %s", err))
+		}
+
+		return nil, fmt.Errorf("comm: %w", err)
+	}
+
+	return f, nil
+}
+
 // splitFunc returns a split function for the given delimiter
 func splitFunc(delim byte) bufio.SplitFunc {
 	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
@@ -244,33 +249,37 @@ func splitFunc(delim byte) bufio.SplitFunc {
 	}
 }
 
-func printCommLine(w io.Writer, opts CommOptions, delim string, column int, line string) {
+func printCommLine(w *bufio.Writer, opts CommOptions, delim string, lineDelim byte, column int, line string) {
+	var prefix string
+
 	switch column {
 	case 1:
-		if !opts.Suppress1 {
-			_, _ = fmt.Fprintln(w, line)
+		if opts.Suppress1 {
+			return
 		}
 	case 2:
-		if !opts.Suppress2 {
-			prefix := ""
-			if !opts.Suppress1 {
-				prefix = delim
-			}
+		if opts.Suppress2 {
+			return
+		}
 
-			_, _ = fmt.Fprintln(w, prefix+line)
+		if !opts.Suppress1 {
+			prefix = delim
 		}
 	case 3:
-		if !opts.Suppress3 {
-			prefix := ""
-			if !opts.Suppress1 {
-				prefix += delim
-			}
+		if opts.Suppress3 {
+			return
+		}
 
-			if !opts.Suppress2 {
-				prefix += delim
-			}
+		if !opts.Suppress1 {
+			prefix += delim
+		}
 
-			_, _ = fmt.Fprintln(w, prefix+line)
+		if !opts.Suppress2 {
+			prefix += delim
 		}
 	}
+
+	_, _ = w.WriteString(prefix)
+	_, _ = w.WriteString(line)
+	_ = w.WriteByte(lineDelim)
 }
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 func TestRunComm(t *testing.T) {
@@ -241,3 +243,71 @@ func TestSplitFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestRunCommOptions(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "a.txt")
+	file2 := filepath.Join(dir, "b.txt")
+
+	_ = os.WriteFile(file1, []byte("Apple\nbanana\ncherry\n"), 0o644)
+	_ = os.WriteFile(file2, []byte("apple\nCherry\ndate\n"), 0o644)
+
+	t.Run("ignore case", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunComm(&buf, []string{file1, file2}, CommOptions{IgnoreCase: true, Suppress1: true, Suppress2: true}); err != nil {
+			t.Fatalf("RunComm() error = %v", err)
+		}
+
+		if buf.String() != "Apple\ncherry\n" {
+			t.Errorf("-i -12 = %q, want Apple and cherry", buf.String())
+		}
+	})
+
+	t.Run("total", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunComm(&buf, []string{file1, file2}, CommOptions{IgnoreCase: true, Total: true, Suppress3: true}); err != nil {
+			t.Fatalf("RunComm() error = %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if got := lines[len(lines)-1]; got != "1\t1\t2\ttotal" {
+			t.Errorf("--total line = %q", got)
+		}
+	})
+
+	t.Run("zero terminated", func(t *testing.T) {
+		z1 := filepath.Join(dir, "z1")
+		z2 := filepath.Join(dir, "z2")
+		_ = os.WriteFile(z1, []byte("a\x00b\x00"), 0o644)
+		_ = os.WriteFile(z2, []byte("b\x00"), 0o644)
+
+		var buf bytes.Buffer
+		if err := RunComm(&buf, []string{z1, z2}, CommOptions{ZeroTerm: true}); err != nil {
+			t.Fatalf("RunComm() error = %v", err)
+		}
+
+		if buf.String() != "a\x00\t\tb\x00" {
+			t.Errorf("-z output = %q", buf.String())
+		}
+	})
+
+	t.Run("unsorted input warns and exits 1", func(t *testing.T) {
+		unsorted := filepath.Join(dir, "unsorted.txt")
+		_ = os.WriteFile(unsorted, []byte("b\na\n"), 0o644)
+
+		var buf bytes.Buffer
+
+		err := RunComm(&buf, []string{unsorted, file2}, CommOptions{})
+		if cmderr.ExitCodeFor(err) != 1 {
+			t.Errorf("default order check: got %v, want exit status 1", err)
+		}
+
+		if buf.Len() == 0 {
+			t.Error("default order check should still produce output")
+		}
+
+		if err := RunComm(&buf, []string{unsorted, file2}, CommOptions{NoCheckOrder: true}); err != nil {
+			t.Errorf("--nocheck-order: got %v", err)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package confirm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"golang.org/x/term"
+)
+
+// Options configures the shared --dry-run, --interactive and --yes behavior
+// of destructive commands
+type Options struct {
+	DryRun      bool // --dry-run: print what would be done without doing it
+	Interactive bool // -i/--interactive: ask before each destructive action
+	Yes         bool // -y/--yes: answer yes to every prompt
+
+	Stdin     io.Reader // prompt answers (default os.Stdin)
+	Stdout    io.Writer // dry-run preview (default os.Stdout)
+	Stderr    io.Writer // prompts (default os.Stderr)
+	AssumeTTY bool      // treat Stdin as a terminal even when it is not
+}
+
+// Confirmer decides, action by action, whether a destructive command goes
+// ahead. A nil *Confirmer approves everything, so callers that were not
+// given one keep their previous behavior.
+type Confirmer struct {
+	name   string
+	opts   Options
+	in     *bufio.Reader
+	all    bool
+	isTerm bool
+}
+
+// New returns a Confirmer for the command name ("rm", "sed", ...), used as
+// the prefix of previews, prompts and errors.
+func New(name string, opts Options) *Confirmer {
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
+
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+
+	isTerm := opts.AssumeTTY
+	if f, ok := opts.Stdin.(*os.File); ok && !isTerm {
+		isTerm = term.IsTerminal(int(f.Fd()))
+	}
+
+	return &Confirmer{name: name, opts: opts, in: bufio.NewReader(opts.Stdin), isTerm: isTerm}
+}
+
+// DryRun reports whether actions are only previewed.
+func (c *Confirmer) DryRun() bool {
+	return c != nil && c.opts.DryRun
+}
+
+// Proceed reports whether an action that needs no confirmation should run.
+// In dry-run mode it prints "would ACTION" and returns false.
+func (c *Confirmer) Proceed(action string) bool {
+	if c.DryRun() {
+		_, _ = fmt.Fprintf(c.opts.Stdout, "%s: would %s\n", c.name, action)
+		return false
+	}
+
+	return true
+}
+
+// Confirm reports whether a destructive action should run. In dry-run mode
+// it prints the action and returns false; with --interactive it asks on a
+// terminal, where "a" approves the remaining actions and "q" aborts the
+// command. Asking without a terminal is an error unless --yes is given, so
+// scripts never hang on a prompt.
+func (c *Confirmer) Confirm(action string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	if !c.Proceed(action) {
+		return false, nil
+	}
+
+	if !c.opts.Interactive || c.opts.Yes || c.all {
+		return true, nil
+	}
+
+	if !c.isTerm {
+		return false, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: --interactive needs a terminal on stdin; use --yes to proceed without prompting", c.name))
+	}
+
+	_, _ = fmt.Fprintf(c.opts.Stderr, "%s: %s? [y/N/a/q] ", c.name, action)
+
+	answer, err := c.in.ReadString('\n')
+	if err != nil && answer == "" {
+		return false, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: aborted", c.name))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	case "a", "all":
+		c.all = true
+		return true, nil
+	case "q", "quit":
+		return false, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: aborted", c.name))
+	default:
+		return false, nil
+	}
+}
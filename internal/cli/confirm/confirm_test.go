@@ -0,0 +1,83 @@
+package confirm
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestConfirmNil(t *testing.T) {
+	var c *Confirmer
+
+	if ok, err := c.Confirm("remove 'x'"); !ok || err != nil {
+		t.Errorf("nil Confirm() = %v, %v", ok, err)
+	}
+
+	if !c.Proceed("remove 'x'") || c.DryRun() {
+		t.Error("nil Confirmer should approve everything")
+	}
+}
+
+func TestConfirmDryRun(t *testing.T) {
+	var out bytes.Buffer
+
+	c := New("rm", Options{DryRun: true, Interactive: true, Stdout: &out})
+
+	if ok, err := c.Confirm("remove 'a.txt'"); ok || err != nil {
+		t.Errorf("Confirm() = %v, %v; want false, nil", ok, err)
+	}
+
+	if c.Proceed("remove 'b.txt'") {
+		t.Error("Proceed() in dry-run = true")
+	}
+
+	if got, want := out.String(), "rm: would remove 'a.txt'\nrm: would remove 'b.txt'\n"; got != want {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+}
+
+func TestConfirmInteractive(t *testing.T) {
+	var prompts bytes.Buffer
+
+	c := New("rm", Options{Interactive: true, Stdin: strings.NewReader("n\ny\na\n"), Stderr: &prompts, AssumeTTY: true})
+
+	var got []bool
+
+	for _, f := range []string{"a", "b", "c", "d"} {
+		ok, err := c.Confirm("remove '" + f + "'")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, ok)
+	}
+
+	// n, y, then a approves c and d without asking again
+	if want := []bool{false, true, true, true}; !slices.Equal(got, want) {
+		t.Errorf("answers = %v, want %v", got, want)
+	}
+
+	if n := strings.Count(prompts.String(), "[y/N/a/q]"); n != 3 {
+		t.Errorf("prompted %d times, want 3", n)
+	}
+
+	c = New("rm", Options{Interactive: true, Stdin: strings.NewReader("q\n"), Stderr: &prompts, AssumeTTY: true})
+	if _, err := c.Confirm("remove 'a'"); !cmderr.IsInvalidInput(err) {
+		t.Errorf("quit error = %v", err)
+	}
+}
+
+func TestConfirmNoTerminal(t *testing.T) {
+	c := New("rm", Options{Interactive: true, Stdin: strings.NewReader("y\n")})
+	if _, err := c.Confirm("remove 'a'"); !cmderr.IsInvalidInput(err) {
+		t.Errorf("prompt without terminal error = %v", err)
+	}
+
+	c = New("rm", Options{Interactive: true, Yes: true, Stdin: strings.NewReader("")})
+	if ok, err := c.Confirm("remove 'a'"); !ok || err != nil {
+		t.Errorf("--yes Confirm() = %v, %v", ok, err)
+	}
+}
@@ -81,7 +81,7 @@ func (m Member) StatusName() string {
 // cmderr sentinel, mirroring internal/cli/vault's classifyVaultError shape.
 func classifyConsulError(err error, statusCode int, op string) error {
 	if err != nil {
-		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("consul: %s: %v", op, err))
+		return cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("consul: %s: %v", op, err))
 	}
 
 	switch statusCode {
@@ -0,0 +1,242 @@
+// Package convert translates configuration files between JSON, YAML and
+// TOML through a shared ordered node model, so key order survives every
+// conversion and comments survive wherever the target format has them.
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// Format names a supported document format
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	TOML Format = "toml"
+)
+
+// Options configures the convert command behavior
+type Options struct {
+	From   string // --from: input format (json, yaml, toml); detected when empty
+	To     string // --to: output format; taken from the --output extension when empty
+	Output string // -o: write to FILE instead of standard output
+	Indent int    // --indent: spaces per level; 0 writes compact JSON (YAML uses at least 2)
+}
+
+// ParseFormat validates a format name, accepting common aliases.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json", "jsonc":
+		return JSON, nil
+	case "yaml", "yml":
+		return YAML, nil
+	case "toml":
+		return TOML, nil
+	}
+
+	return "", fmt.Errorf("unknown format %q (want json, yaml or toml)", s)
+}
+
+// FormatFromPath detects the format from a file extension.
+func FormatFromPath(path string) (Format, bool) {
+	f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	return f, err == nil
+}
+
+var (
+	tomlHeader = regexp.MustCompile(`^\[\[?\s*[A-Za-z0-9_."' -]+\s*\]\]?\s*(#.*)?$`)
+	tomlKeyVal = regexp.MustCompile(`(?m)^\s*[A-Za-z0-9_."'-]+\s*=`)
+	yamlKey    = regexp.MustCompile(`(?m)^\s*[^#\s][^:=]*:(\s|$)`)
+)
+
+// Sniff guesses the format of data: a leading { or [ means JSON unless
+// the first line is a TOML table header, key = value lines mean TOML, and
+// anything else is taken as YAML.
+func Sniff(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return JSON
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		// A one-line input such as ["a"] is JSON, not an empty TOML table
+		if len(firstLine) < len(trimmed) && tomlHeader.Match(bytes.TrimSpace(firstLine)) {
+			return TOML
+		}
+
+		return JSON
+	case tomlKeyVal.Match(trimmed) && !yamlKey.Match(trimmed):
+		return TOML
+	}
+
+	return YAML
+}
+
+// Run converts the FILE (or r) from one format to another.
+func Run(w io.Writer, r io.Reader, args []string, opts Options) error {
+	if len(args) > 1 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "convert: at most one input file")
+	}
+
+	name := "-"
+	if len(args) == 1 {
+		name = args[0]
+	}
+
+	data, err := readInput(name, r)
+	if err != nil {
+		return err
+	}
+
+	from, err := resolveFormat(opts.From, name, "--from")
+	if err != nil {
+		return err
+	}
+
+	if from == "" {
+		from = Sniff(data)
+	}
+
+	to, err := resolveFormat(opts.To, opts.Output, "--to")
+	if err != nil {
+		return err
+	}
+
+	if to == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "convert: specify --to or an --output file with a .json, .yaml or .toml extension")
+	}
+
+	docs, err := Read(data, from)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("convert: %s: parse %s: %s", displayName(name), from, err))
+	}
+
+	if opts.Indent < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "convert: --indent must not be negative")
+	}
+
+	out, err := Write(docs, to, opts.Indent)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("convert: %s: %s", to, err))
+	}
+
+	if opts.Output != "" && opts.Output != "-" {
+		if err := os.WriteFile(opts.Output, out, 0o644); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("convert: %s", err))
+		}
+
+		return nil
+	}
+
+	if _, err := w.Write(out); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("convert: write: %s", err))
+	}
+
+	return nil
+}
+
+// Read parses data in the given format into one node per document.
+func Read(data []byte, f Format) ([]*Node, error) {
+	switch f {
+	case JSON:
+		return readJSON(data)
+	case YAML:
+		return readYAML(data)
+	case TOML:
+		return readTOML(data)
+	}
+
+	return nil, fmt.Errorf("unknown format %q", f)
+}
+
+// Write renders docs in the given format. JSON output puts each document
+// on its own (compact when indent is 0), YAML separates documents with
+// "---", and TOML accepts a single document only.
+func Write(docs []*Node, f Format, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch f {
+	case JSON:
+		for _, doc := range docs {
+			if err := writeJSON(&buf, doc, strings.Repeat(" ", indent)); err != nil {
+				return nil, err
+			}
+		}
+	case YAML:
+		if err := writeYAML(&buf, docs, indent); err != nil {
+			return nil, err
+		}
+	case TOML:
+		if len(docs) != 1 {
+			return nil, errors.New("TOML holds a single document; split the input first")
+		}
+
+		if err := writeTOML(&buf, docs[0]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", f)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resolveFormat uses the explicit flag value, or the extension of path.
+func resolveFormat(flag, path, flagName string) (Format, error) {
+	if flag != "" {
+		f, err := ParseFormat(flag)
+		if err != nil {
+			return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("convert: %s: %s", flagName, err))
+		}
+
+		return f, nil
+	}
+
+	if f, ok := FormatFromPath(path); ok {
+		return f, nil
+	}
+
+	return "", nil
+}
+
+func readInput(name string, r io.Reader) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if name == "-" {
+		data, err = io.ReadAll(r)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("convert: %s", err))
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("convert: %s", err))
+	}
+
+	return data, nil
+}
+
+func displayName(name string) string {
+	if name == "-" {
+		return "standard input"
+	}
+
+	return name
+}
@@ -0,0 +1,260 @@
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func convertString(t *testing.T, in string, from, to Format, indent int) string {
+	t.Helper()
+
+	docs, err := Read([]byte(in), from)
+	if err != nil {
+		t.Fatalf("Read(%s) error = %v", from, err)
+	}
+
+	out, err := Write(docs, to, indent)
+	if err != nil {
+		t.Fatalf("Write(%s) error = %v", to, err)
+	}
+
+	return string(out)
+}
+
+func TestYAMLToTOML(t *testing.T) {
+	in := `# service settings
+name: api
+port: 8080 # listen port
+server:
+  host: localhost
+  tls: true
+tags: [a, b]
+`
+	got := convertString(t, in, YAML, TOML, 2)
+
+	want := `# service settings
+name = "api"
+port = 8080 # listen port
+tags = ["a", "b"]
+
+[server]
+host = "localhost"
+tls = true
+`
+	if got != want {
+		t.Errorf("YAML -> TOML =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTOMLKeyOrder(t *testing.T) {
+	in := `zeta = 1
+alpha = "x"
+
+[database.conn]
+port = 5432
+
+[[products]]
+name = "Hammer"
+`
+	got := convertString(t, in, TOML, JSON, 0)
+
+	want := `{"zeta":1,"alpha":"x","database":{"conn":{"port":5432}},"products":[{"name":"Hammer"}]}` + "\n"
+	if got != want {
+		t.Errorf("TOML -> JSON = %q, want %q", got, want)
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	in := `{"b": 1, "a": {"list": [1, 2.5, null], "text": "line1\nline2"}}`
+	got := convertString(t, in, JSON, YAML, 2)
+
+	want := `b: 1
+a:
+  list:
+    - 1
+    - 2.5
+    - null
+  text: |-
+    line1
+    line2
+`
+	if got != want {
+		t.Errorf("JSON -> YAML =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONNumbersRoundTrip(t *testing.T) {
+	in := `{"big":12345678901234567890,"f":1.50,"e":1e3}`
+	got := convertString(t, in, JSON, JSON, 0)
+
+	if got != `{"big":12345678901234567890,"f":1.50,"e":1e3}`+"\n" {
+		t.Errorf("JSON -> JSON = %q", got)
+	}
+}
+
+func TestYAMLMergeAndAliases(t *testing.T) {
+	in := `base: &base
+  a: 1
+  b: 2
+child:
+  <<: *base
+  b: 3
+copy: *base
+`
+	got := convertString(t, in, YAML, JSON, 0)
+
+	want := `{"base":{"a":1,"b":2},"child":{"a":1,"b":3},"copy":{"a":1,"b":2}}` + "\n"
+	if got != want {
+		t.Errorf("YAML -> JSON = %q, want %q", got, want)
+	}
+}
+
+func TestMultipleDocuments(t *testing.T) {
+	got := convertString(t, "a: 1\n---\nb: 2\n", YAML, JSON, 0)
+	if got != "{\"a\":1}\n{\"b\":2}\n" {
+		t.Errorf("YAML -> JSON = %q", got)
+	}
+
+	docs, err := Read([]byte("{\"a\":1}\n{\"b\":2}\n"), JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Write(docs, TOML, 2); err == nil {
+		t.Error("Write(TOML) of two documents should fail")
+	}
+}
+
+func TestTOMLNull(t *testing.T) {
+	docs, err := Read([]byte(`{"a": [1, null]}`), JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Write(docs, TOML, 2); err == nil {
+		t.Error("Write(TOML) of an array with null should fail")
+	}
+}
+
+func TestTOMLDatetimes(t *testing.T) {
+	in := `when = 1979-05-27T07:32:00Z
+day = 1979-05-27
+at = 07:30:00
+`
+	if got := convertString(t, in, TOML, TOML, 2); got != in {
+		t.Errorf("TOML -> TOML =\n%s\nwant\n%s", got, in)
+	}
+
+	got := convertString(t, in, TOML, YAML, 2)
+	if !strings.Contains(got, "at: \"07:30:00\"") && !strings.Contains(got, "at: 07:30:00\n") {
+		t.Errorf("TOML -> YAML local time = %q", got)
+	}
+
+	if strings.Contains(got, "!!timestamp 07:30:00") {
+		t.Errorf("local time tagged as timestamp: %q", got)
+	}
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Format
+	}{
+		{`{"a": 1}`, JSON},
+		{`["a", "b"]`, JSON},
+		{"[server]\nhost = \"x\"\n", TOML},
+		{"name = \"x\"\nport = 1\n", TOML},
+		{"name: x\nport: 1\n", YAML},
+		{"- a\n- b\n", YAML},
+	}
+
+	for _, tt := range tests {
+		if got := Sniff([]byte(tt.in)); got != tt.want {
+			t.Errorf("Sniff(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"yml": YAML, "JSON": JSON, "jsonc": JSON, "toml": TOML} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %s, %v", in, got, err)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) should fail")
+	}
+
+	if f, ok := FormatFromPath("conf/app.yml"); !ok || f != YAML {
+		t.Errorf("FormatFromPath() = %s, %v", f, ok)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(src, []byte("name: api\nport: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("output extension picks the format", func(t *testing.T) {
+		dst := filepath.Join(dir, "app.toml")
+
+		var buf bytes.Buffer
+		if err := Run(&buf, nil, []string{src}, Options{Output: dst, Indent: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != "name = \"api\"\nport = 8080\n" {
+			t.Errorf("output = %q", data)
+		}
+	})
+
+	t.Run("stdin with sniffing", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Run(&buf, strings.NewReader(`{"a":true}`), nil, Options{To: "yaml", Indent: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != "a: true\n" {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+
+	t.Run("missing --to", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Run(&buf, nil, []string{src}, Options{}); !cmderr.IsInvalidInput(err) {
+			t.Errorf("Run() error = %v, want invalid input", err)
+		}
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Run(&buf, strings.NewReader("{"), nil, Options{From: "json", To: "yaml"})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("Run() error = %v, want invalid input", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Run(&buf, nil, []string{filepath.Join(dir, "nope.json")}, Options{To: "yaml"})
+		if !cmderr.IsNotFound(err) {
+			t.Errorf("Run() error = %v, want not found", err)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// readJSON parses data keeping object member order and number literals.
+func readJSON(data []byte) ([]*Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var docs []*Node
+
+	for {
+		n, err := readJSONValue(dec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, n)
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.New("empty input")
+	}
+
+	return docs, nil
+}
+
+func readJSONValue(dec *json.Decoder) (*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			n := &Node{Kind: Array}
+
+			for dec.More() {
+				item, err := readJSONValue(dec)
+				if err != nil {
+					return nil, unexpectedEOF(err)
+				}
+
+				n.Items = append(n.Items, item)
+			}
+
+			_, err := dec.Token()
+
+			return n, unexpectedEOF(err)
+		}
+
+		n := &Node{Kind: Object}
+
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+
+			value, err := readJSONValue(dec)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+
+			n.Set(key.(string), value)
+		}
+
+		_, err := dec.Token()
+
+		return n, unexpectedEOF(err)
+	case string:
+		return &Node{Kind: String, Value: t}, nil
+	case json.Number:
+		return &Node{Kind: Number, Value: t.String()}, nil
+	case bool:
+		return &Node{Kind: Bool, Value: strconv.FormatBool(t)}, nil
+	default:
+		return &Node{Kind: Null}, nil
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// writeJSON writes n as indented JSON; comments have no JSON form and are
+// dropped.
+func writeJSON(w *bytes.Buffer, n *Node, indent string) error {
+	if err := writeJSONValue(w, n, indent, 0); err != nil {
+		return err
+	}
+
+	w.WriteByte('\n')
+
+	return nil
+}
+
+func writeJSONValue(w *bytes.Buffer, n *Node, indent string, depth int) error {
+	newline := func(d int) {
+		if indent != "" {
+			w.WriteByte('\n')
+			w.WriteString(strings.Repeat(indent, d))
+		}
+	}
+
+	colon := ": "
+	if indent == "" {
+		colon = ":"
+	}
+
+	switch n.Kind {
+	case Null:
+		w.WriteString("null")
+	case Bool:
+		w.WriteString(n.Value)
+	case Number:
+		s, err := jsonNumber(n)
+		if err != nil {
+			return err
+		}
+
+		w.WriteString(s)
+	case String, Datetime:
+		writeJSONString(w, n.Value)
+	case Array:
+		if len(n.Items) == 0 {
+			w.WriteString("[]")
+			return nil
+		}
+
+		w.WriteByte('[')
+
+		for i, item := range n.Items {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+
+			newline(depth + 1)
+
+			if err := writeJSONValue(w, item, indent, depth+1); err != nil {
+				return err
+			}
+		}
+
+		newline(depth)
+		w.WriteByte(']')
+	case Object:
+		if len(n.Members) == 0 {
+			w.WriteString("{}")
+			return nil
+		}
+
+		w.WriteByte('{')
+
+		for i, m := range n.Members {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+
+			newline(depth + 1)
+			writeJSONString(w, m.Key)
+			w.WriteString(colon)
+
+			if err := writeJSONValue(w, m.Value, indent, depth+1); err != nil {
+				return err
+			}
+		}
+
+		newline(depth)
+		w.WriteByte('}')
+	}
+
+	return nil
+}
+
+// jsonNumber returns n as a valid JSON number, rewriting literals such as
+// YAML's .5 or TOML's 1_000 and rejecting infinities and NaN.
+func jsonNumber(n *Node) (string, error) {
+	if json.Valid([]byte(n.Value)) {
+		return n.Value, nil
+	}
+
+	if i, err := strconv.ParseInt(strings.ReplaceAll(n.Value, "_", ""), 0, 64); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+
+	f, err := n.Float()
+	if err != nil {
+		return "", fmt.Errorf("invalid number %q", n.Value)
+	}
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("JSON cannot represent %s", n.Value)
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
+
+func writeJSONString(w *bytes.Buffer, s string) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	// Encode appends a newline
+	w.Truncate(w.Len() - 1)
+}
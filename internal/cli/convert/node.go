@@ -0,0 +1,91 @@
+package convert
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a Node
+type Kind int
+
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Datetime
+	Array
+	Object
+)
+
+// Node is the format-neutral document model every reader produces and
+// every writer consumes. Objects keep their members in document order, and
+// comments are carried along for the formats that have them.
+type Node struct {
+	Kind        Kind
+	Value       string    // scalar text: "true", a number literal, a string or a datetime
+	Items       []*Node   // array elements
+	Members     []*Member // object members in document order
+	Comment     string    // comment lines before the node, without markers
+	LineComment string    // comment on the same line, without marker
+}
+
+// Member is one key of an object
+type Member struct {
+	Key   string
+	Value *Node
+}
+
+// Get returns the member value for key, or nil.
+func (n *Node) Get(key string) *Node {
+	for _, m := range n.Members {
+		if m.Key == key {
+			return m.Value
+		}
+	}
+
+	return nil
+}
+
+// Set adds or replaces the member key, keeping its position.
+func (n *Node) Set(key string, value *Node) {
+	for _, m := range n.Members {
+		if m.Key == key {
+			m.Value = value
+			return
+		}
+	}
+
+	n.Members = append(n.Members, &Member{Key: key, Value: value})
+}
+
+// Float parses a Number node, accepting the special values the formats
+// spell differently (inf, .inf, nan, .NaN).
+func (n *Node) Float() (float64, error) {
+	switch strings.ToLower(strings.TrimPrefix(n.Value, "+")) {
+	case "inf", ".inf":
+		return math.Inf(1), nil
+	case "-inf", "-.inf":
+		return math.Inf(-1), nil
+	case "nan", ".nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	return strconv.ParseFloat(strings.ReplaceAll(n.Value, "_", ""), 64)
+}
+
+// IsInt reports whether a Number node holds an integer literal.
+func (n *Node) IsInt() bool {
+	_, err := strconv.ParseInt(n.Value, 10, 64)
+	return err == nil
+}
+
+// commentLines splits a comment into its lines.
+func commentLines(c string) []string {
+	if c == "" {
+		return nil
+	}
+
+	return strings.Split(c, "\n")
+}
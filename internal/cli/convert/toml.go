@@ -0,0 +1,424 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// readTOML parses data into a single document. The TOML decoder does not
+// expose comments, but it does report keys in document order, which is
+// used to restore the order of every table.
+func readTOML(data []byte) ([]*Node, error) {
+	var m map[string]any
+
+	md, err := toml.Decode(string(data), &m)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make(map[string]int)
+
+	// Implicit parent tables ([a] for [a.b]) take the position of their
+	// first descendant, so every prefix is registered
+	for i, k := range md.Keys() {
+		for n := 1; n <= len(k); n++ {
+			path := strings.Join(k[:n], "\x00")
+			if _, ok := order[path]; !ok {
+				order[path] = i
+			}
+		}
+	}
+
+	return []*Node{fromTOML(m, "", order)}, nil
+}
+
+func fromTOML(v any, path string, order map[string]int) *Node {
+	switch val := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+
+		pos := func(k string) int {
+			if i, ok := order[childPath(path, k)]; ok {
+				return i
+			}
+
+			return math.MaxInt
+		}
+
+		sort.SliceStable(keys, func(i, j int) bool {
+			pi, pj := pos(keys[i]), pos(keys[j])
+			if pi != pj {
+				return pi < pj
+			}
+
+			return keys[i] < keys[j]
+		})
+
+		n := &Node{Kind: Object}
+		for _, k := range keys {
+			n.Set(k, fromTOML(val[k], childPath(path, k), order))
+		}
+
+		return n
+	case []map[string]any:
+		n := &Node{Kind: Array}
+		for _, item := range val {
+			n.Items = append(n.Items, fromTOML(item, path, order))
+		}
+
+		return n
+	case []any:
+		n := &Node{Kind: Array}
+		for _, item := range val {
+			n.Items = append(n.Items, fromTOML(item, path, order))
+		}
+
+		return n
+	case string:
+		return &Node{Kind: String, Value: val}
+	case bool:
+		return &Node{Kind: Bool, Value: strconv.FormatBool(val)}
+	case int64:
+		return &Node{Kind: Number, Value: strconv.FormatInt(val, 10)}
+	case float64:
+		return &Node{Kind: Number, Value: formatFloat(val)}
+	case time.Time:
+		return &Node{Kind: Datetime, Value: formatTOMLTime(val)}
+	default:
+		return &Node{Kind: String, Value: fmt.Sprint(val)}
+	}
+}
+
+func childPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "\x00" + key
+}
+
+func formatTOMLTime(t time.Time) string {
+	switch t.Location().String() {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// formatFloat spells a float so that it stays a float in every format.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// tomlWriter emits TOML keeping member order: within each table, plain
+// keys come first (TOML requires it), then sub-tables and arrays of
+// tables, each group in document order.
+type tomlWriter struct {
+	buf   *bytes.Buffer
+	warnW func(string) // reports dropped values
+	first bool         // nothing written yet, so no blank line before a header
+}
+
+func writeTOML(w *bytes.Buffer, root *Node) error {
+	if root.Kind != Object {
+		return errors.New("a TOML document must be a table")
+	}
+
+	tw := &tomlWriter{buf: w, first: true, warnW: func(msg string) {
+		_, _ = fmt.Fprintf(os.Stderr, "convert: %s\n", msg)
+	}}
+
+	writeCommentLines(w, root.Comment)
+
+	return tw.table(root, nil, false)
+}
+
+func (tw *tomlWriter) table(t *Node, path []string, arrayElem bool) error {
+	var inline, tables []*Member
+
+	for _, m := range t.Members {
+		if isTable(m.Value) || isTableArray(m.Value) {
+			tables = append(tables, m)
+		} else {
+			inline = append(inline, m)
+		}
+	}
+
+	if len(path) > 0 && (arrayElem || len(inline) > 0 || len(tables) == 0 || t.Comment != "") {
+		if !tw.first {
+			tw.buf.WriteByte('\n')
+		}
+
+		writeCommentLines(tw.buf, t.Comment)
+
+		header := tomlKeyPath(path)
+		if arrayElem {
+			tw.buf.WriteString("[[" + header + "]]")
+		} else {
+			tw.buf.WriteString("[" + header + "]")
+		}
+
+		writeLineComment(tw.buf, t.LineComment)
+		tw.buf.WriteByte('\n')
+
+		tw.first = false
+	}
+
+	for _, m := range inline {
+		if m.Value.Kind == Null {
+			tw.warnW(fmt.Sprintf("dropping null value at %s (TOML has no null)", tomlKeyPath(append(path, m.Key))))
+			continue
+		}
+
+		s, err := tw.value(m.Value, append(path, m.Key))
+		if err != nil {
+			return err
+		}
+
+		writeCommentLines(tw.buf, m.Value.Comment)
+		tw.buf.WriteString(tomlKey(m.Key) + " = " + s)
+		writeLineComment(tw.buf, m.Value.LineComment)
+		tw.buf.WriteByte('\n')
+
+		tw.first = false
+	}
+
+	for _, m := range tables {
+		child := append(append([]string(nil), path...), m.Key)
+
+		if m.Value.Kind == Object {
+			if err := tw.table(m.Value, child, false); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for i, item := range m.Value.Items {
+			if i == 0 && item.Comment == "" {
+				item.Comment = m.Value.Comment
+			}
+
+			if err := tw.table(item, child, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// value renders an inline TOML value.
+func (tw *tomlWriter) value(n *Node, path []string) (string, error) {
+	switch n.Kind {
+	case Null:
+		return "", fmt.Errorf("TOML has no null (at %s)", tomlKeyPath(path))
+	case Bool:
+		return n.Value, nil
+	case Number:
+		return tomlNumber(n)
+	case String:
+		return tomlString(n.Value), nil
+	case Datetime:
+		if isTOMLDatetime(n.Value) {
+			return n.Value, nil
+		}
+
+		return tomlString(n.Value), nil
+	case Array:
+		parts := make([]string, 0, len(n.Items))
+
+		for _, item := range n.Items {
+			if item.Kind == Null {
+				return "", fmt.Errorf("TOML has no null (in array %s)", tomlKeyPath(path))
+			}
+
+			s, err := tw.value(item, path)
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, s)
+		}
+
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	default:
+		parts := make([]string, 0, len(n.Members))
+
+		for _, m := range n.Members {
+			if m.Value.Kind == Null {
+				tw.warnW(fmt.Sprintf("dropping null value at %s (TOML has no null)", tomlKeyPath(append(path, m.Key))))
+				continue
+			}
+
+			s, err := tw.value(m.Value, append(path, m.Key))
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, tomlKey(m.Key)+" = "+s)
+		}
+
+		if len(parts) == 0 {
+			return "{}", nil
+		}
+
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	}
+}
+
+func isTable(n *Node) bool {
+	return n.Kind == Object
+}
+
+// isTableArray reports whether n is written as [[array.of.tables]].
+func isTableArray(n *Node) bool {
+	if n.Kind != Array || len(n.Items) == 0 {
+		return false
+	}
+
+	for _, item := range n.Items {
+		if item.Kind != Object {
+			return false
+		}
+	}
+
+	return true
+}
+
+func tomlNumber(n *Node) (string, error) {
+	if n.IsInt() {
+		return n.Value, nil
+	}
+
+	if i, err := strconv.ParseInt(strings.ReplaceAll(n.Value, "_", ""), 0, 64); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+
+	f, err := n.Float()
+	if err != nil {
+		return "", fmt.Errorf("invalid number %q", n.Value)
+	}
+
+	return formatFloat(f), nil
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(k string) string {
+	if bareKey.MatchString(k) {
+		return k
+	}
+
+	return tomlString(k)
+}
+
+func tomlKeyPath(path []string) string {
+	parts := make([]string, len(path))
+	for i, k := range path {
+		parts[i] = tomlKey(k)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				_, _ = fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+var tomlDatetimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+func isTOMLDatetime(s string) bool {
+	for _, layout := range tomlDatetimeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeCommentLines(w *bytes.Buffer, c string) {
+	for _, l := range commentLines(c) {
+		if l == "" {
+			w.WriteString("#\n")
+		} else {
+			w.WriteString("# " + l + "\n")
+		}
+	}
+}
+
+func writeLineComment(w *bytes.Buffer, c string) {
+	if c != "" {
+		w.WriteString(" # " + strings.ReplaceAll(c, "\n", " "))
+	}
+}
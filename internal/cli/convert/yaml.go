@@ -0,0 +1,308 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readYAML parses every document in data. Aliases are expanded and merge
+// keys (<<) are applied, so the result no longer depends on anchors.
+func readYAML(data []byte) ([]*Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var docs []*Node
+
+	for {
+		var doc yaml.Node
+
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := fromYAML(&doc, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, n)
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.New("empty input")
+	}
+
+	return docs, nil
+}
+
+// maxAliasDepth stops alias expansion from recursing forever on
+// self-referencing anchors.
+const maxAliasDepth = 100
+
+func fromYAML(y *yaml.Node, depth int) (*Node, error) {
+	if depth > maxAliasDepth {
+		return nil, errors.New("aliases nested too deeply")
+	}
+
+	var n *Node
+
+	switch y.Kind {
+	case yaml.DocumentNode:
+		if len(y.Content) == 0 {
+			return &Node{Kind: Null}, nil
+		}
+
+		inner, err := fromYAML(y.Content[0], depth)
+		if err != nil {
+			return nil, err
+		}
+
+		inner.Comment = joinComments(stripComment(y.HeadComment), inner.Comment)
+
+		return inner, nil
+	case yaml.AliasNode:
+		return fromYAML(y.Alias, depth+1)
+	case yaml.SequenceNode:
+		n = &Node{Kind: Array}
+
+		for _, c := range y.Content {
+			item, err := fromYAML(c, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			n.Items = append(n.Items, item)
+		}
+	case yaml.MappingNode:
+		n = &Node{Kind: Object}
+
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			k, v := y.Content[i], y.Content[i+1]
+
+			value, err := fromYAML(v, depth)
+			if err != nil {
+				return nil, err
+			}
+
+			if k.Tag == "!!merge" {
+				if err := mergeInto(n, value); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			value.Comment = joinComments(stripComment(k.HeadComment), value.Comment)
+			if value.LineComment == "" {
+				value.LineComment = stripComment(k.LineComment)
+			}
+
+			n.Set(k.Value, value)
+		}
+	default:
+		n = yamlScalar(y)
+	}
+
+	n.Comment = joinComments(n.Comment, stripComment(y.HeadComment))
+	if y.LineComment != "" {
+		n.LineComment = stripComment(y.LineComment)
+	}
+
+	return n, nil
+}
+
+// mergeInto applies a "<<" merge at its position in the mapping. Keys
+// already present win over merged ones, so earlier merges take precedence
+// over later ones, and a key written after the merge replaces the merged
+// value in place.
+func mergeInto(n, m *Node) error {
+	sources := []*Node{m}
+	if m.Kind == Array {
+		sources = m.Items
+	}
+
+	for _, src := range sources {
+		if src.Kind != Object {
+			return errors.New("merge key value is not a mapping")
+		}
+
+		for _, member := range src.Members {
+			if n.Get(member.Key) == nil {
+				n.Set(member.Key, member.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+func yamlScalar(y *yaml.Node) *Node {
+	switch y.ShortTag() {
+	case "!!null":
+		return &Node{Kind: Null}
+	case "!!bool":
+		var b bool
+		_ = y.Decode(&b)
+
+		return &Node{Kind: Bool, Value: strconv.FormatBool(b)}
+	case "!!int":
+		if i, err := strconv.ParseInt(strings.ReplaceAll(y.Value, "_", ""), 0, 64); err == nil {
+			return &Node{Kind: Number, Value: strconv.FormatInt(i, 10)}
+		}
+
+		return &Node{Kind: Number, Value: y.Value}
+	case "!!float":
+		return &Node{Kind: Number, Value: y.Value}
+	case "!!timestamp":
+		return &Node{Kind: Datetime, Value: y.Value}
+	default:
+		return &Node{Kind: String, Value: y.Value}
+	}
+}
+
+// writeYAML writes the documents, separated by "---" when there is more
+// than one.
+func writeYAML(w *bytes.Buffer, docs []*Node, indent int) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(max(indent, 2))
+
+	for _, doc := range docs {
+		y, err := toYAML(doc)
+		if err != nil {
+			return err
+		}
+
+		if err := enc.Encode(y); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
+
+func toYAML(n *Node) (*yaml.Node, error) {
+	y := &yaml.Node{HeadComment: yamlComment(n.Comment), LineComment: yamlComment(n.LineComment)}
+
+	switch n.Kind {
+	case Null:
+		y.Kind, y.Tag, y.Value = yaml.ScalarNode, "!!null", "null"
+	case Bool:
+		y.Kind, y.Tag, y.Value = yaml.ScalarNode, "!!bool", n.Value
+	case Number:
+		y.Kind, y.Value = yaml.ScalarNode, yamlNumber(n)
+		y.Tag = "!!float"
+
+		if n.IsInt() {
+			y.Tag = "!!int"
+		}
+	case String:
+		y.Kind, y.Tag, y.Value = yaml.ScalarNode, "!!str", n.Value
+		if strings.Contains(n.Value, "\n") {
+			y.Style = yaml.LiteralStyle
+		}
+	case Datetime:
+		// YAML timestamps always have a date; a TOML local time is a string
+		y.Kind, y.Tag, y.Value = yaml.ScalarNode, "!!timestamp", n.Value
+		if !strings.Contains(n.Value, "-") {
+			y.Tag = "!!str"
+		}
+	case Array:
+		y.Kind, y.Tag = yaml.SequenceNode, "!!seq"
+
+		for _, item := range n.Items {
+			c, err := toYAML(item)
+			if err != nil {
+				return nil, err
+			}
+
+			y.Content = append(y.Content, c)
+		}
+	case Object:
+		y.Kind, y.Tag = yaml.MappingNode, "!!map"
+
+		for _, m := range n.Members {
+			v, err := toYAML(m.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			// Comments belong on the key in a mapping
+			k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.Key, HeadComment: v.HeadComment}
+			v.HeadComment = ""
+
+			if v.Kind != yaml.ScalarNode {
+				k.LineComment, v.LineComment = v.LineComment, ""
+			}
+
+			y.Content = append(y.Content, k, v)
+		}
+	default:
+		return nil, fmt.Errorf("unknown node kind %d", n.Kind)
+	}
+
+	return y, nil
+}
+
+// yamlNumber spells the special float values the YAML way.
+func yamlNumber(n *Node) string {
+	switch strings.ToLower(strings.TrimPrefix(n.Value, "+")) {
+	case "inf", ".inf":
+		return ".inf"
+	case "-inf", "-.inf":
+		return "-.inf"
+	case "nan", ".nan", "-nan":
+		return ".nan"
+	}
+
+	return strings.ReplaceAll(n.Value, "_", "")
+}
+
+// stripComment removes the "#" markers yaml.v3 keeps on comments.
+func stripComment(c string) string {
+	if c == "" {
+		return ""
+	}
+
+	lines := strings.Split(c, "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		l = strings.TrimPrefix(l, "#")
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func joinComments(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "\n" + b
+	}
+}
+
+func yamlComment(c string) string {
+	lines := commentLines(c)
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = "#"
+		} else {
+			lines[i] = "# " + l
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -8,14 +8,17 @@ import (
 	"path/filepath"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
 )
 
 // CopyOptions configures the copy command behavior
 type CopyOptions struct {
-	Recursive bool // -r/-R: copy directories recursively
+	Recursive bool            // -r/-R: copy directories recursively
+	Force     bool            // -f: never prompt before overwriting
+	Confirm   confirm.Options // --dry-run, -i/--interactive, -y/--yes
 }
 
-func RunCopy(args []string, _ CopyOptions) error {
+func RunCopy(args []string, opts CopyOptions) error {
 	if len(args) < 2 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "cp: missing file operand")
 	}
@@ -30,12 +33,23 @@ func RunCopy(args []string, _ CopyOptions) error {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("cp: target '%s' is not a directory", dest))
 	}
 
+	c := newConfirmer("cp", opts.Force, opts.Confirm)
+
 	for _, src := range srcs {
 		target := dest
 		if destIsDir {
 			target = filepath.Join(dest, filepath.Base(src))
 		}
 
+		ok, err := confirmTarget(c, "copy", src, target)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
 		if err := copyFile(src, target); err != nil {
 			if errors.Is(err, os.ErrNotExist) {
 				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("cp: %s", err))
@@ -51,9 +65,12 @@ func RunCopy(args []string, _ CopyOptions) error {
 }
 
 // MoveOptions configures the move command behavior
-type MoveOptions struct{}
+type MoveOptions struct {
+	Force   bool            // -f: never prompt before overwriting
+	Confirm confirm.Options // --dry-run, -i/--interactive, -y/--yes
+}
 
-func RunMove(args []string, _ MoveOptions) error {
+func RunMove(args []string, opts MoveOptions) error {
 	if len(args) < 2 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "mv: missing file operand")
 	}
@@ -68,12 +85,23 @@ func RunMove(args []string, _ MoveOptions) error {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mv: target '%s' is not a directory", dest))
 	}
 
+	c := newConfirmer("mv", opts.Force, opts.Confirm)
+
 	for _, src := range srcs {
 		target := dest
 		if destIsDir {
 			target = filepath.Join(dest, filepath.Base(src))
 		}
 
+		ok, err := confirmTarget(c, "move", src, target)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
 		if err := os.Rename(src, target); err != nil {
 			if errors.Is(err, os.ErrNotExist) {
 				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("mv: %s", err))
@@ -88,6 +116,25 @@ func RunMove(args []string, _ MoveOptions) error {
 	return nil
 }
 
+// newConfirmer returns the Confirmer for cp or mv; -f never prompts
+func newConfirmer(name string, force bool, opts confirm.Options) *confirm.Confirmer {
+	if force {
+		opts.Interactive = false
+	}
+
+	return confirm.New(name, opts)
+}
+
+// confirmTarget previews or, when target already exists, asks before
+// overwriting it. Like cp -i and mv -i, new targets are never prompted for.
+func confirmTarget(c *confirm.Confirmer, verb, src, target string) (bool, error) {
+	if _, err := os.Lstat(target); err == nil {
+		return c.Confirm(fmt.Sprintf("%s '%s' over '%s'", verb, src, target))
+	}
+
+	return c.Proceed(fmt.Sprintf("%s '%s' to '%s'", verb, src, target)), nil
+}
+
 func copyFile(src, dst string) error {
 	sourceFileStat, err := os.Stat(src)
 	if err != nil {
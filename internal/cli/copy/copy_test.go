@@ -1,12 +1,16 @@
 package copy
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
 )
 
 func TestRunCopy_NonRegularSourceIsInvalidInput(t *testing.T) {
@@ -253,3 +257,69 @@ func TestCopyFile(t *testing.T) {
 		}
 	})
 }
+
+func TestRunCopyConfirm(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	dst := filepath.Join(dir, "dst.txt")
+	_ = os.WriteFile(src, []byte("new"), 0644)
+	_ = os.WriteFile(dst, []byte("old"), 0644)
+
+	var out bytes.Buffer
+
+	if err := RunCopy([]string{src, dst}, CopyOptions{Confirm: confirm.Options{DryRun: true, Stdout: &out}}); err != nil {
+		t.Fatalf("RunCopy(dry-run) error = %v", err)
+	}
+
+	if want := "cp: would copy '" + src + "' over '" + dst + "'\n"; out.String() != want {
+		t.Errorf("dry-run output = %q, want %q", out.String(), want)
+	}
+
+	ask := func(answer string) confirm.Options {
+		return confirm.Options{Interactive: true, Stdin: strings.NewReader(answer), Stderr: io.Discard, AssumeTTY: true}
+	}
+
+	if err := RunCopy([]string{src, dst}, CopyOptions{Confirm: ask("n\n")}); err != nil {
+		t.Fatalf("RunCopy(declined) error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(dst); string(data) != "old" {
+		t.Errorf("declined overwrite changed dst to %q", data)
+	}
+
+	// --force wins over --interactive, like GNU cp.
+	if err := RunCopy([]string{src, dst}, CopyOptions{Force: true, Confirm: ask("")}); err != nil {
+		t.Fatalf("RunCopy(force) error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(dst); string(data) != "new" {
+		t.Errorf("forced overwrite left dst = %q", data)
+	}
+}
+
+func TestRunMoveConfirm(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	dst := filepath.Join(dir, "dst.txt")
+	_ = os.WriteFile(src, []byte("new"), 0644)
+	_ = os.WriteFile(dst, []byte("old"), 0644)
+
+	var out bytes.Buffer
+
+	if err := RunMove([]string{src, dst}, MoveOptions{Confirm: confirm.Options{DryRun: true, Stdout: &out}}); err != nil {
+		t.Fatalf("RunMove(dry-run) error = %v", err)
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		t.Error("dry run moved the source")
+	}
+
+	opts := MoveOptions{Confirm: confirm.Options{Interactive: true, Stdin: strings.NewReader("y\n"), Stderr: io.Discard, AssumeTTY: true}}
+	if err := RunMove([]string{src, dst}, opts); err != nil {
+		t.Fatalf("RunMove(confirmed) error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(dst); string(data) != "new" {
+		t.Errorf("confirmed move left dst = %q", data)
+	}
+}
@@ -0,0 +1,233 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/cryptutil"
+)
+
+// KeygenOptions configures the crypt keygen command behavior
+type KeygenOptions struct {
+	Output       string        // -o: write the identity file to FILE (mode 0600)
+	Convert      bool          // -y: print the recipients of the identity files instead
+	OutputFormat output.Format // output format (text, json)
+}
+
+// KeygenResult is the crypt keygen output for JSON
+type KeygenResult struct {
+	PublicKey string `json:"publicKey"`
+	Identity  string `json:"identity,omitempty"`
+	File      string `json:"file,omitempty"`
+}
+
+// RunKeygen generates an age X25519 identity. Without -o the identity file
+// is written to w; with -o it is written to FILE, which must not exist yet,
+// and only the public key is printed. With -y it instead prints the
+// recipient of every identity in the files of args, or in r.
+func RunKeygen(w io.Writer, r io.Reader, args []string, opts KeygenOptions) error {
+	f := output.New(w, opts.OutputFormat)
+
+	if opts.Convert {
+		ids, err := readIdentities(r, args)
+		if err != nil {
+			return fmt.Errorf("crypt keygen: %w", err)
+		}
+
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = id.Recipient().String()
+		}
+
+		if f.IsJSON() {
+			return f.Print(keys)
+		}
+
+		_, _ = fmt.Fprintln(w, strings.Join(keys, "\n"))
+
+		return nil
+	}
+
+	if len(args) > 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "crypt keygen: unexpected arguments (use -y to convert identity files)")
+	}
+
+	id, err := cryptutil.GenerateX25519Identity()
+	if err != nil {
+		return fmt.Errorf("crypt keygen: %w", err)
+	}
+
+	file := cryptutil.FormatIdentityFile(id, time.Now().UTC())
+	res := KeygenResult{PublicKey: id.Recipient().String()}
+
+	if opts.Output != "" {
+		// O_EXCL: never overwrite an existing key; 0o600: the identity is a secret.
+		out, err := os.OpenFile(opts.Output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("crypt keygen: %s already exists", opts.Output))
+			}
+
+			return fmt.Errorf("crypt keygen: %w", err)
+		}
+
+		if _, err := io.WriteString(out, file); err != nil {
+			_ = out.Close()
+			return fmt.Errorf("crypt keygen: %w", err)
+		}
+
+		if err := out.Close(); err != nil {
+			return fmt.Errorf("crypt keygen: %w", err)
+		}
+
+		res.File = opts.Output
+	} else {
+		res.Identity = id.String()
+	}
+
+	switch {
+	case f.IsJSON():
+		return f.Print(res)
+	case opts.Output != "":
+		_, _ = fmt.Fprintf(w, "Public key: %s\n", res.PublicKey)
+	default:
+		_, _ = io.WriteString(w, file)
+	}
+
+	return nil
+}
+
+// usesRecipients reports whether encrypt should encrypt to age recipients.
+func usesRecipients(opts CryptOptions) bool {
+	return len(opts.Recipients) > 0 || len(opts.RecipientsFiles) > 0
+}
+
+// ageEncrypt encrypts input to the recipients of opts. With -a/-b the
+// result is an armored age file.
+func ageEncrypt(w io.Writer, input io.Reader, opts CryptOptions) error {
+	if opts.Password != "" || opts.PasswordFile != "" || opts.KeyFile != "" || opts.Envelope {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "encrypt: --recipient cannot be combined with a password, key file or --envelope")
+	}
+
+	var recipients []*cryptutil.X25519Recipient
+
+	for _, s := range opts.Recipients {
+		r, err := cryptutil.ParseX25519Recipient(strings.TrimSpace(s))
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("encrypt: %s", err))
+		}
+
+		recipients = append(recipients, r)
+	}
+
+	for _, path := range opts.RecipientsFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("encrypt: %s", path))
+			}
+
+			return fmt.Errorf("encrypt: %w", err)
+		}
+
+		recs, err := cryptutil.ParseRecipients(bytes.NewReader(data))
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("encrypt: %s: %s", path, err))
+		}
+
+		recipients = append(recipients, recs...)
+	}
+
+	out := w
+
+	var armor io.WriteCloser
+	if opts.Base64 || opts.Armor {
+		armor = cryptutil.AgeArmor(w)
+		out = armor
+	}
+
+	enc, err := cryptutil.AgeEncrypt(out, recipients...)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if _, err := io.Copy(enc, input); err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if armor != nil {
+		if err := armor.Close(); err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ageDecrypt decrypts the age file in input with the identity files of opts.
+func ageDecrypt(w io.Writer, input []byte, opts CryptOptions) error {
+	if len(opts.Identities) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "decrypt: input is encrypted to age recipients; use --identity FILE")
+	}
+
+	ids, err := readIdentities(nil, opts.Identities)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	r, err := cryptutil.AgeDecrypt(bytes.NewReader(input), ids...)
+	if err != nil {
+		if errors.Is(err, cryptutil.ErrNoIdentityMatch) {
+			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("decrypt: %s", err))
+		}
+
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("decrypt: %s", err))
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	return nil
+}
+
+// readIdentities reads the identity files in paths, or r when paths is
+// empty.
+func readIdentities(r io.Reader, paths []string) ([]*cryptutil.X25519Identity, error) {
+	if len(paths) == 0 {
+		return cryptutil.ParseIdentities(r)
+	}
+
+	var ids []*cryptutil.X25519Identity
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, cmderr.Wrap(cmderr.ErrNotFound, path)
+			}
+
+			return nil, err
+		}
+
+		found, err := cryptutil.ParseIdentities(bytes.NewReader(data))
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: %s", path, err))
+		}
+
+		ids = append(ids, found...)
+	}
+
+	return ids, nil
+}
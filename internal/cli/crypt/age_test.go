@@ -0,0 +1,156 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestAgeKeygenEncryptDecrypt(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key.txt")
+	plain := filepath.Join(dir, "plain.txt")
+
+	if err := os.WriteFile(plain, []byte("artifact\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := RunKeygen(&out, nil, nil, KeygenOptions{Output: key}); err != nil {
+		t.Fatalf("RunKeygen: %v", err)
+	}
+
+	pub, ok := strings.CutPrefix(strings.TrimSpace(out.String()), "Public key: ")
+	if !ok || !strings.HasPrefix(pub, "age1") {
+		t.Fatalf("RunKeygen output = %q", out.String())
+	}
+
+	fi, err := os.Stat(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runtime.GOOS != "windows" && fi.Mode().Perm() != 0o600 {
+		t.Errorf("identity file mode = %v, want 0600", fi.Mode().Perm())
+	}
+
+	if err := RunKeygen(&out, nil, nil, KeygenOptions{Output: key}); !cmderr.IsConflict(err) {
+		t.Errorf("keygen over an existing file: err = %v, want conflict", err)
+	}
+
+	out.Reset()
+
+	if err := RunKeygen(&out, nil, []string{key}, KeygenOptions{Convert: true}); err != nil || strings.TrimSpace(out.String()) != pub {
+		t.Errorf("keygen -y = %q, %v, want %q", out.String(), err, pub)
+	}
+
+	recipients := filepath.Join(dir, "recipients.txt")
+	if err := os.WriteFile(recipients, []byte("# ci\n"+pub+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, opts := range map[string]CryptOptions{
+		"recipient":       {Recipients: []string{pub}},
+		"recipients file": {RecipientsFiles: []string{recipients}},
+		"armored":         {Recipients: []string{pub}, Armor: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sealed := filepath.Join(t.TempDir(), "plain.age")
+			opts.Output = sealed
+
+			if err := RunEncrypt(&bytes.Buffer{}, []string{plain}, opts); err != nil {
+				t.Fatalf("RunEncrypt: %v", err)
+			}
+
+			data, _ := os.ReadFile(sealed)
+			if want := map[bool]string{false: "age-encryption.org/v1\n", true: "-----BEGIN AGE"}[opts.Armor]; !strings.HasPrefix(string(data), want) {
+				t.Errorf("sealed file starts with %q", data[:min(len(data), 30)])
+			}
+
+			var got bytes.Buffer
+			if err := RunDecrypt(&got, []string{sealed}, CryptOptions{Identities: []string{key}}); err != nil || got.String() != "artifact\n" {
+				t.Errorf("RunDecrypt = %q, %v", got.String(), err)
+			}
+
+			if err := RunDecrypt(&got, []string{sealed}, CryptOptions{Password: "x"}); !cmderr.IsInvalidInput(err) {
+				t.Errorf("decrypt without --identity: err = %v, want invalid input", err)
+			}
+		})
+	}
+}
+
+func TestAgeErrors(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.txt")
+	other := filepath.Join(dir, "other.txt")
+	sealed := filepath.Join(dir, "plain.age")
+
+	_ = os.WriteFile(plain, []byte("x"), 0o600)
+
+	var key bytes.Buffer
+	if err := RunKeygen(&key, nil, nil, KeygenOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunKeygen(&bytes.Buffer{}, nil, nil, KeygenOptions{Output: other}); err != nil {
+		t.Fatal(err)
+	}
+
+	var pub bytes.Buffer
+	if err := RunKeygen(&pub, strings.NewReader(key.String()), nil, KeygenOptions{Convert: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := strings.TrimSpace(pub.String())
+
+	if err := RunEncrypt(&bytes.Buffer{}, []string{plain}, CryptOptions{Recipients: []string{r}, Output: sealed}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunDecrypt(&bytes.Buffer{}, []string{sealed}, CryptOptions{Identities: []string{other}}); !cmderr.IsPermission(err) {
+		t.Errorf("decrypt with the wrong identity: err = %v, want permission", err)
+	}
+
+	if err := RunDecrypt(&bytes.Buffer{}, []string{sealed}, CryptOptions{Identities: []string{filepath.Join(dir, "missing")}}); !cmderr.IsNotFound(err) {
+		t.Errorf("missing identity file: err = %v, want not found", err)
+	}
+
+	if err := RunEncrypt(&bytes.Buffer{}, []string{plain}, CryptOptions{Recipients: []string{"age1bogus"}}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad recipient: err = %v, want invalid input", err)
+	}
+
+	if err := RunEncrypt(&bytes.Buffer{}, []string{plain}, CryptOptions{Recipients: []string{r}, Password: "p"}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("recipient and password: err = %v, want invalid input", err)
+	}
+
+	if err := RunDecrypt(&bytes.Buffer{}, []string{plain}, CryptOptions{Identities: []string{other}}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("--identity on a non-age file: err = %v, want invalid input", err)
+	}
+}
+
+func TestKeygenJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := RunKeygen(&out, nil, nil, KeygenOptions{OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatal(err)
+	}
+
+	var res KeygenResult
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if !strings.HasPrefix(res.PublicKey, "age1") || !strings.HasPrefix(res.Identity, "AGE-SECRET-KEY-1") {
+		t.Errorf("result = %+v", res)
+	}
+
+	if err := RunKeygen(&out, nil, []string{"x"}, KeygenOptions{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("keygen with arguments: err = %v, want invalid input", err)
+	}
+}
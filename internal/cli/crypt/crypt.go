@@ -1,6 +1,7 @@
 package crypt
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -21,13 +22,25 @@ type CryptOptions struct {
 	Output       string // -o: output file
 	Base64       bool   // -b: base64 encode/decode
 	Armor        bool   // -a: ASCII armor output (same as -b)
+	Envelope     bool   // --envelope: seal with a random data key wrapped by the password
+
+	Recipients      []string // -r: encrypt to these age recipients instead of a password
+	RecipientsFiles []string // -R: encrypt to the age recipients listed in these files
+	Identities      []string // --identity: decrypt age files with the identities in these files
 }
 
 // RunEncrypt encrypts data using AES-256-GCM
 func RunEncrypt(w io.Writer, args []string, opts CryptOptions) error {
-	password, err := getPassword(opts)
-	if err != nil {
-		return fmt.Errorf("encrypt: %w", err)
+	var (
+		password string
+		err      error
+	)
+
+	if !usesRecipients(opts) {
+		password, err = getPassword(opts)
+		if err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
 	}
 
 	// Read input
@@ -56,7 +69,23 @@ func RunEncrypt(w io.Writer, args []string, opts CryptOptions) error {
 	}
 
 	// Encrypt using pkg/cryptutil
-	output, err := cryptutil.Encrypt(input, password, cryptOpts...)
+	var output []byte
+
+	switch {
+	case usesRecipients(opts):
+		var buf bytes.Buffer
+
+		if err := ageEncrypt(&buf, bytes.NewReader(input), opts); err != nil {
+			return err
+		}
+
+		output = buf.Bytes()
+	case opts.Envelope:
+		output, err = sealEnvelope(input, password, opts)
+	default:
+		output, err = cryptutil.Encrypt(input, password, cryptOpts...)
+	}
+
 	if err != nil {
 		return fmt.Errorf("encrypt: %w", err)
 	}
@@ -78,7 +107,7 @@ func RunEncrypt(w io.Writer, args []string, opts CryptOptions) error {
 		outWriter = f
 	}
 
-	if opts.Base64 || opts.Armor {
+	if (opts.Base64 || opts.Armor) && !usesRecipients(opts) {
 		_, _ = fmt.Fprintln(outWriter, string(output))
 	} else {
 		_, _ = outWriter.Write(output)
@@ -89,13 +118,12 @@ func RunEncrypt(w io.Writer, args []string, opts CryptOptions) error {
 
 // RunDecrypt decrypts data using AES-256-GCM
 func RunDecrypt(w io.Writer, args []string, opts CryptOptions) error {
-	password, err := getPassword(opts)
-	if err != nil {
-		return fmt.Errorf("decrypt: %w", err)
-	}
-
 	// Read input
-	var input []byte
+	var (
+		input []byte
+		err   error
+	)
+
 	if len(args) == 0 || args[0] == "-" {
 		input, err = io.ReadAll(os.Stdin)
 	} else {
@@ -109,6 +137,26 @@ func RunDecrypt(w io.Writer, args []string, opts CryptOptions) error {
 		return fmt.Errorf("decrypt: %w", err)
 	}
 
+	// age files are decrypted with identities, not a password
+	if cryptutil.IsAge(input) {
+		var buf bytes.Buffer
+
+		if err := ageDecrypt(&buf, input, opts); err != nil {
+			return err
+		}
+
+		return writePlaintext(w, buf.Bytes(), opts)
+	}
+
+	if len(opts.Identities) > 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "decrypt: --identity given but the input is not an age encrypted file")
+	}
+
+	password, err := getPassword(opts)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
 	// Build options for cryptutil
 	var cryptOpts []cryptutil.Option
 	if opts.Iterations > 0 {
@@ -124,13 +172,26 @@ func RunDecrypt(w io.Writer, args []string, opts CryptOptions) error {
 		input = []byte(strings.TrimSpace(string(input)))
 	}
 
-	// Decrypt using pkg/cryptutil
-	plaintext, err := cryptutil.Decrypt(input, password, cryptOpts...)
+	// Decrypt using pkg/cryptutil; envelopes are detected automatically
+	var plaintext []byte
+	if raw, _, ok := decodeEnvelope(input); ok {
+		plaintext, err = cryptutil.OpenEnvelope(raw, cryptutil.NewPassphraseWrapper(password, opts.Iterations))
+		if errors.Is(err, cryptutil.ErrUnwrap) {
+			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("decrypt: %s", err))
+		}
+	} else {
+		plaintext, err = cryptutil.Decrypt(input, password, cryptOpts...)
+	}
+
 	if err != nil {
 		return fmt.Errorf("decrypt: %w", err)
 	}
 
-	// Write output
+	return writePlaintext(w, plaintext, opts)
+}
+
+// writePlaintext writes decrypted data to w, or to the -o file
+func writePlaintext(w io.Writer, plaintext []byte, opts CryptOptions) error {
 	var outWriter = w
 
 	if opts.Output != "" {
@@ -0,0 +1,86 @@
+package crypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestEnvelopeEncryptRotateDecrypt(t *testing.T) {
+	for _, armor := range []bool{false, true} {
+		dir := t.TempDir()
+		plain := filepath.Join(dir, "plain.txt")
+		sealed := filepath.Join(dir, "secret.env")
+		opened := filepath.Join(dir, "opened.txt")
+
+		if err := os.WriteFile(plain, []byte("envelope data\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		err := RunEncrypt(&bytes.Buffer{}, []string{plain}, CryptOptions{
+			Password: "old", Output: sealed, Armor: armor, Envelope: true, Iterations: 1000,
+		})
+		if err != nil {
+			t.Fatalf("RunEncrypt: %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := RunRotate(&out, []string{sealed}, RotateOptions{
+			Old: CryptOptions{Password: "old"},
+			New: CryptOptions{Password: "new", Iterations: 1000},
+		}); err != nil {
+			t.Fatalf("RunRotate: %v", err)
+		}
+
+		if !strings.Contains(out.String(), "rotated") {
+			t.Errorf("RunRotate output = %q", out.String())
+		}
+
+		err = RunDecrypt(&bytes.Buffer{}, []string{sealed}, CryptOptions{Password: "old", Output: opened})
+		if !cmderr.IsPermission(err) {
+			t.Errorf("decrypt with old password: err = %v, want permission error", err)
+		}
+
+		if err := RunDecrypt(&bytes.Buffer{}, []string{sealed}, CryptOptions{Password: "new", Output: opened}); err != nil {
+			t.Fatalf("RunDecrypt: %v", err)
+		}
+
+		got, _ := os.ReadFile(opened)
+		if string(got) != "envelope data\n" {
+			t.Errorf("armor=%v: decrypted %q", armor, got)
+		}
+
+		var info bytes.Buffer
+		if err := RunInspect(&info, []string{sealed}, InspectOptions{OutputFormat: output.FormatJSON}); err != nil {
+			t.Fatalf("RunInspect: %v", err)
+		}
+
+		if !strings.Contains(info.String(), `"scheme": "pbkdf2-sha256+aes256gcm"`) {
+			t.Errorf("RunInspect output = %s", info.String())
+		}
+	}
+}
+
+func TestRunRotateNotEnvelope(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.txt")
+
+	if err := os.WriteFile(plain, []byte("not sealed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := RotateOptions{Old: CryptOptions{Password: "a"}, New: CryptOptions{Password: "b"}}
+
+	if err := RunRotate(&bytes.Buffer{}, []string{plain}, opts); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunRotate(plain) = %v, want invalid input", err)
+	}
+
+	if err := RunRotate(&bytes.Buffer{}, []string{filepath.Join(dir, "missing")}, opts); !cmderr.IsNotFound(err) {
+		t.Errorf("RunRotate(missing) = %v, want not found", err)
+	}
+}
@@ -0,0 +1,73 @@
+package crypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestSplitCombine(t *testing.T) {
+	var out bytes.Buffer
+
+	secret := "master-key\n"
+	if err := RunSplit(&out, strings.NewReader(secret), nil, SplitOptions{Shares: 5, Threshold: 3}); err != nil {
+		t.Fatalf("RunSplit() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("RunSplit() wrote %d shares, want 5", len(lines))
+	}
+
+	var got bytes.Buffer
+	if err := RunCombine(&got, strings.NewReader(lines[4]+"\n\n"+lines[0]+"\n"+lines[2]), nil, CombineOptions{}); err != nil {
+		t.Fatalf("RunCombine() error = %v", err)
+	}
+
+	if got.String() != secret {
+		t.Errorf("RunCombine() = %q, want %q", got.String(), secret)
+	}
+}
+
+func TestSplitOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	shareDir := filepath.Join(dir, "shares")
+
+	if err := RunSplit(&bytes.Buffer{}, strings.NewReader("s3cret"), nil, SplitOptions{Shares: 3, Threshold: 2, OutputDir: shareDir}); err != nil {
+		t.Fatalf("RunSplit() error = %v", err)
+	}
+
+	out := filepath.Join(dir, "secret")
+	args := []string{filepath.Join(shareDir, "share-1.txt"), filepath.Join(shareDir, "share-3.txt")}
+
+	if err := RunCombine(&bytes.Buffer{}, nil, args, CombineOptions{Output: out}); err != nil {
+		t.Fatalf("RunCombine() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "s3cret" {
+		t.Errorf("recovered %q, want %q", data, "s3cret")
+	}
+}
+
+func TestSplitCombineErrors(t *testing.T) {
+	if err := RunSplit(&bytes.Buffer{}, strings.NewReader("x"), nil, SplitOptions{Shares: 2, Threshold: 3}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunSplit(n < k) error = %v", err)
+	}
+
+	if err := RunCombine(&bytes.Buffer{}, strings.NewReader("zz\n"), nil, CombineOptions{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunCombine(bad hex) error = %v", err)
+	}
+
+	if err := RunCombine(&bytes.Buffer{}, nil, []string{filepath.Join(t.TempDir(), "missing")}, CombineOptions{}); !cmderr.IsNotFound(err) {
+		t.Errorf("RunCombine(missing) error = %v", err)
+	}
+}
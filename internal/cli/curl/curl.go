@@ -11,11 +11,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/hashutil"
 )
 
 const (
@@ -83,6 +86,9 @@ type Options struct {
 	Timeout      time.Duration     // Request timeout
 	FollowRedir  bool              // Follow redirects
 	Insecure     bool              // Skip TLS verification
+	Output       string            // -o: write the body to this file instead of w
+	ExpectSHA256 string            // --expect-sha256: required SHA-256 of the body
+	Sidecar      bool              // --verify-sidecar: take the expected digest from URL.sha256
 	OutputFormat output.Format     // global output format
 }
 
@@ -93,8 +99,14 @@ type Response struct {
 	Headers    map[string][]string `json:"headers"`
 	Body       string              `json:"body"`
 	Duration   float64             `json:"duration_ms"`
+	File       string              `json:"file,omitempty"`
+	SHA256     string              `json:"sha256,omitempty"`
+	Verified   bool                `json:"verified,omitempty"`
 }
 
+// maxSidecarSize bounds the checksum sidecar download.
+const maxSidecarSize = 64 << 10
+
 // Run executes an HTTP request
 func Run(w io.Writer, args []string, opts Options) error {
 	if len(args) == 0 {
@@ -205,6 +217,22 @@ func Run(w io.Writer, args []string, opts Options) error {
 		_, _ = fmt.Fprintln(w, ">")
 	}
 
+	// Resolve the expected digest before downloading so a missing sidecar
+	// fails fast
+	expected := opts.ExpectSHA256
+	if expected == "" && opts.Sidecar {
+		expected, err = fetchSidecar(client, req.URL)
+		if err != nil {
+			return err
+		}
+	}
+
+	if expected != "" {
+		if expected, err = hashutil.NormalizeDigest(expected, hashutil.SHA256); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("curl: --expect-sha256: %s", err))
+		}
+	}
+
 	// Execute request
 	start := time.Now()
 
@@ -214,19 +242,36 @@ func Run(w io.Writer, args []string, opts Options) error {
 		if errors.As(err, &netErr) && netErr.Timeout() {
 			return cmderr.Wrap(cmderr.ErrTimeout, fmt.Sprintf("curl: %s", err))
 		}
-		return fmt.Errorf("curl: %w", err)
+		return cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("curl: %s", err))
 	}
 
 	defer func() { _ = resp.Body.Close() }()
 
-	duration := time.Since(start)
+	// Hash the body while it streams; mismatched content is never printed
+	// or left on disk
+	hasher := hashutil.NewTeeHasher(nil, hashutil.SHA256)
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("curl: reading response: %w", err)
+	var respBody []byte
+
+	if opts.Output != "" {
+		if err := saveBody(hasher.Reader(resp.Body), opts.Output, hasher, expected); err != nil {
+			return err
+		}
+	} else {
+		respBody, err = io.ReadAll(hasher.Reader(resp.Body))
+		if err != nil {
+			return fmt.Errorf("curl: reading response: %w", err)
+		}
+
+		if expected != "" {
+			if err := hasher.Verify(expected); err != nil {
+				return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("curl: %s: %s", req.URL, err))
+			}
+		}
 	}
 
+	duration := time.Since(start)
+
 	// Print response details if verbose
 	if opts.Verbose {
 		_, _ = fmt.Fprintf(w, "< %s\n", resp.Status)
@@ -248,6 +293,9 @@ func Run(w io.Writer, args []string, opts Options) error {
 			Headers:    resp.Header,
 			Body:       string(respBody),
 			Duration:   float64(duration.Milliseconds()),
+			File:       opts.Output,
+			SHA256:     hasher.Sum(),
+			Verified:   expected != "",
 		}
 
 		enc := json.NewEncoder(w)
@@ -256,6 +304,19 @@ func Run(w io.Writer, args []string, opts Options) error {
 		return enc.Encode(response)
 	}
 
+	if opts.Output != "" {
+		if opts.Verbose {
+			verified := ""
+			if expected != "" {
+				verified = " (verified)"
+			}
+
+			_, _ = fmt.Fprintf(w, "* saved %d bytes to %s, sha256 %s%s\n", hasher.Size(), opts.Output, hasher.Sum(), verified)
+		}
+
+		return nil
+	}
+
 	// Pretty print JSON response if Content-Type is JSON
 	contentType := resp.Header.Get("Content-Type")
 	if strings.Contains(contentType, "application/json") {
@@ -388,3 +449,69 @@ func parseArgs(args []string, opts Options) (string, map[string]string, string,
 
 	return urlStr, headers, dataStr, nil
 }
+
+// saveBody streams body into a temporary file next to dest and renames it
+// into place only when the checksum matches, so a failed verification never
+// leaves a partial or tampered file behind.
+func saveBody(body io.Reader, dest string, hasher *hashutil.TeeHasher, expected string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".part-*")
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("curl: %s", err))
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = io.Copy(tmp, body)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("curl: writing %s: %s", dest, err))
+	}
+
+	if expected != "" {
+		if err := hasher.Verify(expected); err != nil {
+			return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("curl: %s: %s (download discarded)", dest, err))
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("curl: %s", err))
+	}
+
+	return nil
+}
+
+// fetchSidecar downloads u + ".sha256" and returns the digest it lists for
+// the file named by u.
+func fetchSidecar(client *http.Client, u *url.URL) (string, error) {
+	sidecar := *u
+	sidecar.Path += ".sha256"
+	sidecar.RawPath = ""
+	sidecar.RawQuery = ""
+	sidecar.Fragment = ""
+
+	resp, err := client.Get(sidecar.String())
+	if err != nil {
+		return "", fmt.Errorf("curl: checksum sidecar: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("curl: checksum sidecar %s: %s", sidecar.String(), resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSidecarSize))
+	if err != nil {
+		return "", fmt.Errorf("curl: checksum sidecar: %w", err)
+	}
+
+	digest, err := hashutil.ParseSidecar(data, path.Base(u.Path))
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("curl: %s: %s", sidecar.String(), err))
+	}
+
+	return digest, nil
+}
@@ -6,9 +6,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 func TestRun(t *testing.T) {
@@ -306,3 +311,97 @@ func TestRunNoRedirect(t *testing.T) {
 		t.Errorf("StatusCode = %d, want %d (should not follow redirect)", response.StatusCode, http.StatusFound)
 	}
 }
+
+func TestRunChecksum(t *testing.T) {
+	const (
+		body   = "hello"
+		digest = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tool.tar.gz":
+			_, _ = w.Write([]byte(body))
+		case "/tool.tar.gz.sha256":
+			_, _ = w.Write([]byte(digest + "  tool.tar.gz\n"))
+		case "/bad.bin":
+			_, _ = w.Write([]byte(body))
+		case "/bad.bin.sha256":
+			_, _ = w.Write([]byte(strings.Repeat("0", 64) + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("stdout verified", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Run(&buf, []string{server.URL + "/tool.tar.gz"}, Options{Method: "GET", ExpectSHA256: digest}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if strings.TrimSpace(buf.String()) != body {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+
+	t.Run("stdout mismatch prints nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Run(&buf, []string{server.URL + "/tool.tar.gz"}, Options{Method: "GET", ExpectSHA256: strings.Repeat("1", 64)})
+		if !cmderr.IsConflict(err) {
+			t.Errorf("err = %v, want conflict", err)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("mismatched body printed: %q", buf.String())
+		}
+	})
+
+	t.Run("file via sidecar", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "tool.tar.gz")
+
+		var buf bytes.Buffer
+		if err := Run(&buf, []string{server.URL + "/tool.tar.gz"}, Options{Method: "GET", Output: dest, Sidecar: true, OutputFormat: output.FormatJSON}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(dest)
+		if err != nil || string(data) != body {
+			t.Errorf("file = %q, %v", data, err)
+		}
+
+		var resp Response
+		if err := json.Unmarshal(buf.Bytes(), &resp); err != nil || !resp.Verified || resp.SHA256 != digest {
+			t.Errorf("response = %+v, %v", resp, err)
+		}
+	})
+
+	t.Run("file mismatch is discarded", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "bad.bin")
+
+		err := Run(&bytes.Buffer{}, []string{server.URL + "/bad.bin"}, Options{Method: "GET", Output: dest, Sidecar: true})
+		if !cmderr.IsConflict(err) {
+			t.Errorf("err = %v, want conflict", err)
+		}
+
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("files left behind: %v", entries)
+		}
+	})
+
+	t.Run("missing sidecar", func(t *testing.T) {
+		err := Run(&bytes.Buffer{}, []string{server.URL + "/nosidecar"}, Options{Method: "GET", Sidecar: true})
+		if !cmderr.IsNotFound(err) {
+			t.Errorf("err = %v, want not found", err)
+		}
+	})
+
+	t.Run("malformed digest", func(t *testing.T) {
+		err := Run(&bytes.Buffer{}, []string{server.URL + "/tool.tar.gz"}, Options{Method: "GET", ExpectSHA256: "abc"})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("err = %v, want invalid input", err)
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/textwidth"
 )
 
 // CutOptions configures the cut command behavior
@@ -166,27 +167,29 @@ func cutFields(line string, opts CutOptions) (string, error) {
 	return strings.Join(selected, opts.OutputDelim), nil
 }
 
+// cutChars selects display clusters rather than runes, so an accent or
+// an emoji ZWJ sequence is never split from its base character.
 func cutChars(line string, spec string, complement bool) (string, error) {
-	runes := []rune(line)
+	chars := textwidth.Clusters(line)
 
-	ranges, err := parseRanges(spec, len(runes))
+	ranges, err := parseRanges(spec, len(chars))
 	if err != nil {
 		return "", err
 	}
 
 	if complement {
-		ranges = complementRanges(ranges, len(runes))
+		ranges = complementRanges(ranges, len(chars))
 	}
 
-	var result []rune
+	var result strings.Builder
 
 	for _, idx := range ranges {
-		if idx > 0 && idx <= len(runes) {
-			result = append(result, runes[idx-1])
+		if idx > 0 && idx <= len(chars) {
+			result.WriteString(chars[idx-1])
 		}
 	}
 
-	return string(result), nil
+	return result.String(), nil
 }
 
 func cutBytes(line string, spec string, complement bool) (string, error) {
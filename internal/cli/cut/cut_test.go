@@ -33,6 +33,24 @@ func TestRunCut(t *testing.T) {
 		}
 	})
 
+	t.Run("cut characters keeps clusters whole", func(t *testing.T) {
+		file := filepath.Join(tmpDir, "clusters.txt")
+		if err := os.WriteFile(file, []byte("cafe\u0301👍🏽日本\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+
+		err := RunCut(&buf, nil, []string{file}, CutOptions{Characters: "4-6"})
+		if err != nil {
+			t.Fatalf("RunCut() error = %v", err)
+		}
+
+		if buf.String() != "e\u0301👍🏽日\n" {
+			t.Errorf("RunCut() = %q, want %q", buf.String(), "e\u0301👍🏽日\n")
+		}
+	})
+
 	t.Run("cut fields with custom delimiter", func(t *testing.T) {
 		file := filepath.Join(tmpDir, "comma.txt")
 		if err := os.WriteFile(file, []byte("one,two,three\n"), 0644); err != nil {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/i18n"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
@@ -33,7 +34,8 @@ type DateResult struct {
 	UTC       bool   `json:"utc"`
 }
 
-// RunDate prints the current date and time
+// RunDate prints the current date and time. Month and weekday names
+// follow the language selected with i18n.SetLang.
 func RunDate(w io.Writer, opts DateOptions) error {
 	now := time.Now()
 	if opts.UTC {
@@ -51,7 +53,7 @@ func RunDate(w io.Writer, opts DateOptions) error {
 	if f.IsJSON() {
 		zone, _ := now.Zone()
 		result := DateResult{
-			Formatted: now.Format(format),
+			Formatted: i18n.FormatTime(now, format),
 			Unix:      now.Unix(),
 			UnixNano:  now.UnixNano(),
 			Year:      now.Year(),
@@ -72,7 +74,7 @@ func RunDate(w io.Writer, opts DateOptions) error {
 		return nil
 	}
 
-	if _, err := fmt.Fprintln(w, now.Format(format)); err != nil {
+	if _, err := fmt.Fprintln(w, i18n.FormatTime(now, format)); err != nil {
 		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("date: write: %s", err))
 	}
 
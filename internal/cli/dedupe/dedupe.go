@@ -0,0 +1,486 @@
+// Package dedupe finds duplicate files by content and optionally replaces
+// the extra copies with hard links or deletes them.
+package dedupe
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
+	"github.com/inovacc/omni/internal/cli/du"
+	"github.com/inovacc/omni/internal/cli/rg"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+// defaultWorkers bounds concurrent hashing; disk throughput, not CPU, is
+// usually the limit.
+const defaultWorkers = 4
+
+// Options configures the dedupe command behavior
+type Options struct {
+	Algorithm    string          // -a/--algorithm: content hash (default sha256)
+	MinSize      int64           // -m/--min-size: ignore smaller files (default 1, skipping empty files)
+	NoIgnore     bool            // --no-ignore: do not honor .gitignore and common ignores
+	Hardlink     bool            // -L/--hardlink: replace duplicates with hard links to the kept file
+	Delete       bool            // -d/--delete: delete duplicates
+	Threads      int             // -t/--threads: parallel hash workers (0 = auto)
+	Null         bool            // -0/--null: print only the duplicate paths, each followed by NUL
+	Confirm      confirm.Options // --dry-run, -i/--interactive, -y/--yes
+	OutputFormat output.Format   // output format
+}
+
+// Group is a set of files with identical content. The first file is the one
+// kept; the others are its duplicates.
+type Group struct {
+	Size  int64  `json:"size"`
+	Hash  string `json:"hash"`
+	Files []File `json:"files"`
+}
+
+// File is one member of a duplicate group.
+type File struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // keep, duplicate, linked, deleted, skipped
+}
+
+// Result is the outcome of a dedupe run
+type Result struct {
+	Scanned     int      `json:"scanned"`
+	Groups      []Group  `json:"groups"`
+	Duplicates  int      `json:"duplicates"`
+	Reclaimable int64    `json:"reclaimable_bytes"`
+	Action      string   `json:"action,omitempty"` // hardlink or delete
+	DryRun      bool     `json:"dry_run,omitempty"`
+	Applied     int      `json:"applied"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// candidate is a scanned regular file.
+type candidate struct {
+	path string
+	info fs.FileInfo
+}
+
+// RunDedupe scans the given directories (default ".") and reports files
+// with identical content, grouped by size and then by hash.
+func RunDedupe(w io.Writer, args []string, opts Options) error {
+	if opts.Hardlink && opts.Delete {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "dedupe: --hardlink and --delete are mutually exclusive")
+	}
+
+	algo := hashutil.Algorithm(opts.Algorithm)
+	if algo == "" {
+		algo = hashutil.SHA256
+	}
+
+	if !slices.Contains(hashutil.Algorithms(), algo) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("dedupe: unknown algorithm %q", opts.Algorithm))
+	}
+
+	if opts.MinSize == 0 {
+		opts.MinSize = 1
+	}
+
+	roots := args
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	result := &Result{}
+
+	files, err := scan(roots, opts, result)
+	if err != nil {
+		return err
+	}
+
+	result.Scanned = len(files)
+	result.Groups = findDuplicates(files, algo, opts.Threads, result)
+
+	for _, g := range result.Groups {
+		result.Duplicates += len(g.Files) - 1
+		result.Reclaimable += g.Size * int64(len(g.Files)-1)
+	}
+
+	if opts.Hardlink || opts.Delete {
+		if err := apply(w, result, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := printResult(w, result, opts); err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		return cmderr.PartialFailure(cmderr.ExitCodeFor(cmderr.ErrPartial), fmt.Sprintf("dedupe: %d files failed", len(result.Errors)))
+	}
+
+	return nil
+}
+
+// scan walks the roots, honoring ignore files unless opts.NoIgnore, and
+// returns the regular files of at least opts.MinSize bytes. Symlinks are
+// not followed, and a file reached twice (overlapping roots) or already
+// hard-linked to a scanned file is listed once.
+func scan(roots []string, opts Options, result *Result) ([]candidate, error) {
+	var files []candidate
+
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("dedupe: %s", err))
+			}
+
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dedupe: %s", err))
+		}
+
+		if !info.IsDir() {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("dedupe: %s is not a directory", root))
+		}
+
+		var gitignore *rg.GitignoreSet
+		if !opts.NoIgnore {
+			gitignore = rg.NewGitignoreSet(root)
+			gitignore.AddCommonIgnores()
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				result.Errors = append(result.Errors, walkErr.Error())
+				return nil
+			}
+
+			if path != root && gitignore != nil && gitignore.ShouldIgnore(path, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			fi, err := d.Info()
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				return nil
+			}
+
+			if fi.Size() >= opts.MinSize {
+				files = append(files, candidate{path: path, info: fi})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dedupe: %s", err))
+		}
+	}
+
+	return files, nil
+}
+
+// findDuplicates groups files by size, hashes only the sizes shared by
+// more than one file, and returns the groups of identical content, largest
+// reclaimable space first.
+func findDuplicates(files []candidate, algo hashutil.Algorithm, threads int, result *Result) []Group {
+	bySize := make(map[int64][]candidate)
+	for _, f := range files {
+		bySize[f.info.Size()] = append(bySize[f.info.Size()], f)
+	}
+
+	var toHash []candidate
+
+	for _, same := range bySize {
+		same = distinctFiles(same)
+		if len(same) > 1 {
+			toHash = append(toHash, same...)
+		}
+	}
+
+	hashes := hashAll(toHash, algo, threads, result)
+
+	type key struct {
+		size int64
+		hash string
+	}
+
+	byContent := make(map[key][]string)
+
+	for i, f := range toHash {
+		if hashes[i] != "" {
+			k := key{f.info.Size(), hashes[i]}
+			byContent[k] = append(byContent[k], f.path)
+		}
+	}
+
+	var groups []Group
+
+	for k, paths := range byContent {
+		if len(paths) < 2 {
+			continue
+		}
+
+		slices.Sort(paths)
+
+		g := Group{Size: k.size, Hash: k.hash}
+		for i, p := range paths {
+			status := "duplicate"
+			if i == 0 {
+				status = "keep"
+			}
+
+			g.Files = append(g.Files, File{Path: p, Status: status})
+		}
+
+		groups = append(groups, g)
+	}
+
+	slices.SortFunc(groups, func(a, b Group) int {
+		wa, wb := a.Size*int64(len(a.Files)-1), b.Size*int64(len(b.Files)-1)
+		return cmp.Or(cmp.Compare(wb, wa), strings.Compare(a.Files[0].Path, b.Files[0].Path))
+	})
+
+	return groups
+}
+
+// distinctFiles drops paths that name a file already in the list, such as
+// existing hard links, which take no extra space.
+func distinctFiles(files []candidate) []candidate {
+	out := files[:0:0]
+
+	for _, f := range files {
+		if !slices.ContainsFunc(out, func(o candidate) bool { return os.SameFile(o.info, f.info) }) {
+			out = append(out, f)
+		}
+	}
+
+	return out
+}
+
+// hashAll hashes files in parallel. A file that cannot be read gets an
+// empty hash and an entry in result.Errors.
+func hashAll(files []candidate, algo hashutil.Algorithm, threads int, result *Result) []string {
+	hashes := make([]string, len(files))
+	errs := make([]error, len(files))
+
+	workers := threads
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+
+	for range min(workers, len(files)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(files) {
+					return
+				}
+
+				hashes[i], errs[i] = hashutil.HashFile(files[i].path, algo)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+
+	return hashes
+}
+
+// apply hard-links or deletes every duplicate, asking first when
+// requested. Each group's first file is kept.
+func apply(w io.Writer, result *Result, opts Options) error {
+	result.Action = "delete"
+	if opts.Hardlink {
+		result.Action = "hardlink"
+	}
+
+	result.DryRun = opts.Confirm.DryRun
+
+	if opts.Confirm.Stdout == nil {
+		opts.Confirm.Stdout = w
+	}
+
+	if opts.OutputFormat == output.FormatJSON || opts.Null {
+		// The JSON result or path list already describes what would happen
+		opts.Confirm.Stdout = io.Discard
+	}
+
+	c := confirm.New("dedupe", opts.Confirm)
+
+	for gi := range result.Groups {
+		g := &result.Groups[gi]
+		keep := g.Files[0].Path
+
+		for fi := range g.Files[1:] {
+			f := &g.Files[fi+1]
+
+			action := fmt.Sprintf("delete '%s' (duplicate of '%s')", f.Path, keep)
+			if opts.Hardlink {
+				action = fmt.Sprintf("replace '%s' with a hard link to '%s'", f.Path, keep)
+			}
+
+			ok, err := c.Confirm(action)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				if !c.DryRun() {
+					f.Status = "skipped"
+				}
+
+				continue
+			}
+
+			if opts.Hardlink {
+				err = replaceWithLink(keep, f.Path)
+				f.Status = "linked"
+			} else {
+				err = os.Remove(f.Path)
+				f.Status = "deleted"
+			}
+
+			if err != nil {
+				f.Status = "failed"
+				result.Errors = append(result.Errors, err.Error())
+
+				continue
+			}
+
+			result.Applied++
+		}
+	}
+
+	return nil
+}
+
+// replaceWithLink atomically replaces path with a hard link to target: the
+// link is created under a temporary name and renamed over path, so path
+// never goes missing.
+func replaceWithLink(target, path string) error {
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".dedupe-"+strconv.Itoa(os.Getpid()))
+
+	if err := os.Link(target, tmp); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+func printResult(w io.Writer, result *Result, opts Options) error {
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		if result.Groups == nil {
+			result.Groups = []Group{}
+		}
+
+		return f.Print(result)
+	}
+
+	if len(result.Errors) > 0 {
+		for _, e := range result.Errors {
+			_, _ = fmt.Fprintf(os.Stderr, "dedupe: %s\n", e)
+		}
+	}
+
+	if opts.Null {
+		return printNull(w, result)
+	}
+
+	if len(result.Groups) > 0 {
+		rows := [][]string{{"GROUP", "SIZE", "HASH", "STATUS", "PATH"}}
+
+		for i, g := range result.Groups {
+			for _, file := range g.Files {
+				rows = append(rows, []string{
+					strconv.Itoa(i + 1), du.FormatHumanSize(g.Size), shortHash(g.Hash), file.Status, file.Path,
+				})
+			}
+		}
+
+		if err := output.NewTable(w).Print(rows); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(w)
+	}
+
+	_, _ = fmt.Fprintf(w, "%d files scanned, %d duplicate groups, %d duplicates, %s reclaimable\n",
+		result.Scanned, len(result.Groups), result.Duplicates, du.FormatHumanSize(result.Reclaimable))
+
+	if result.Action != "" && !result.DryRun {
+		verb := "deleted"
+		if result.Action == "hardlink" {
+			verb = "linked"
+		}
+
+		_, _ = fmt.Fprintf(w, "%d %s\n", result.Applied, verb)
+	}
+
+	return nil
+}
+
+// printNull writes the path of every file that is not kept, each followed
+// by a NUL byte and nothing else, so the list can be piped to rm -0 or
+// xargs -0 whatever characters the names contain.
+func printNull(w io.Writer, result *Result) error {
+	bw := bufio.NewWriter(w)
+
+	for _, g := range result.Groups {
+		for _, file := range g.Files[1:] {
+			_, _ = bw.WriteString(file.Path)
+			_ = bw.WriteByte(0)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dedupe: write: %s", err))
+	}
+
+	return nil
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+
+	return h
+}
@@ -0,0 +1,207 @@
+package dedupe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// tree creates files under a temp dir and returns its path.
+func tree(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func runJSON(t *testing.T, dir string, opts Options) Result {
+	t.Helper()
+
+	opts.OutputFormat = output.FormatJSON
+
+	var buf bytes.Buffer
+	if err := RunDedupe(&buf, []string{dir}, opts); err != nil {
+		t.Fatalf("RunDedupe() error = %v", err)
+	}
+
+	var r Result
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatalf("bad JSON %q: %v", buf.String(), err)
+	}
+
+	return r
+}
+
+func TestRunDedupeGroups(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"a.txt":          "same content",
+		"sub/b.txt":      "same content",
+		"sub/c.txt":      "same content",
+		"d.txt":          "same length!", // same size, different content
+		"big1.bin":       strings.Repeat("x", 100),
+		"big2.bin":       strings.Repeat("x", 100),
+		"unique.txt":     "only one",
+		"empty1":         "",
+		"empty2":         "",
+		"node_modules/x": "same content", // ignored
+	})
+
+	r := runJSON(t, dir, Options{})
+
+	if r.Scanned != 7 {
+		t.Errorf("Scanned = %d, want 7", r.Scanned)
+	}
+
+	if len(r.Groups) != 2 {
+		t.Fatalf("groups = %+v, want 2", r.Groups)
+	}
+
+	// Largest reclaimable space first
+	if g := r.Groups[0]; g.Size != 100 || len(g.Files) != 2 {
+		t.Errorf("first group = %+v", g)
+	}
+
+	g := r.Groups[1]
+	if len(g.Files) != 3 || g.Files[0].Path != filepath.Join(dir, "a.txt") || g.Files[0].Status != "keep" {
+		t.Errorf("second group = %+v", g)
+	}
+
+	if r.Duplicates != 3 || r.Reclaimable != 100+2*12 {
+		t.Errorf("Duplicates = %d, Reclaimable = %d", r.Duplicates, r.Reclaimable)
+	}
+
+	if r = runJSON(t, dir, Options{NoIgnore: true}); len(r.Groups[1].Files) != 4 {
+		t.Errorf("--no-ignore group = %+v, want node_modules/x included", r.Groups[1])
+	}
+}
+
+func TestRunDedupeSkipsHardlinks(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "data"})
+	if err := os.Link(filepath.Join(dir, "a"), filepath.Join(dir, "b")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	if r := runJSON(t, dir, Options{}); len(r.Groups) != 0 {
+		t.Errorf("hard links reported as duplicates: %+v", r.Groups)
+	}
+}
+
+func TestRunDedupeHardlink(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "data", "b": "data", "c": "data"})
+
+	r := runJSON(t, dir, Options{Hardlink: true})
+	if r.Applied != 2 || r.Action != "hardlink" {
+		t.Fatalf("Applied = %d, Action = %q", r.Applied, r.Action)
+	}
+
+	a, _ := os.Stat(filepath.Join(dir, "a"))
+	for _, name := range []string{"b", "c"} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || !os.SameFile(a, fi) {
+			t.Errorf("%s is not a hard link to a (err %v)", name, err)
+		}
+	}
+
+	if r = runJSON(t, dir, Options{}); len(r.Groups) != 0 {
+		t.Errorf("second run still finds duplicates: %+v", r.Groups)
+	}
+}
+
+func TestRunDedupeDelete(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "data", "b": "data"})
+
+	var buf bytes.Buffer
+	opts := Options{Delete: true, Confirm: confirm.Options{DryRun: true}}
+
+	if err := RunDedupe(&buf, []string{dir}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "dedupe: would delete '"+filepath.Join(dir, "b")+"'") {
+		t.Errorf("dry-run output = %q", buf.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "b")); err != nil {
+		t.Errorf("dry run removed b: %v", err)
+	}
+
+	if r := runJSON(t, dir, Options{Delete: true}); r.Applied != 1 || r.Groups[0].Files[1].Status != "deleted" {
+		t.Errorf("delete result = %+v", r)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "b")); !os.IsNotExist(err) {
+		t.Errorf("b still exists: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "a")); err != nil {
+		t.Errorf("kept file removed: %v", err)
+	}
+}
+
+func TestRunDedupeText(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "data", "b": "data"})
+
+	var buf bytes.Buffer
+	if err := RunDedupe(&buf, []string{dir}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"GROUP", "keep", "duplicate", "2 files scanned, 1 duplicate groups, 1 duplicates, 4 reclaimable"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDedupeNull(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "data", "b c": "data", "d\ne": "data", "other": "diff"})
+
+	var buf bytes.Buffer
+	if err := RunDedupe(&buf, []string{dir}, Options{Null: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(dir, "b c") + "\x00" + filepath.Join(dir, "d\ne") + "\x00"
+	if buf.String() != want {
+		t.Errorf("RunDedupe(Null) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunDedupeErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := RunDedupe(&buf, []string{t.TempDir()}, Options{Hardlink: true, Delete: true})
+	if !errors.Is(err, cmderr.ErrInvalidInput) {
+		t.Errorf("--hardlink --delete error = %v", err)
+	}
+
+	err = RunDedupe(&buf, []string{t.TempDir()}, Options{Algorithm: "nope"})
+	if !errors.Is(err, cmderr.ErrInvalidInput) {
+		t.Errorf("bad algorithm error = %v", err)
+	}
+
+	err = RunDedupe(&buf, []string{filepath.Join(t.TempDir(), "missing")}, Options{})
+	if !errors.Is(err, cmderr.ErrNotFound) {
+		t.Errorf("missing dir error = %v", err)
+	}
+}
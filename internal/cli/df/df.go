@@ -3,23 +3,31 @@ package df
 import (
 	"fmt"
 	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/du"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/tablewriter"
 )
 
 // DFOptions configures the df command behavior
 type DFOptions struct {
-	HumanReadable bool          // -h: print sizes in human readable format
-	Inodes        bool          // -i: list inode information instead of block usage
-	BlockSize     int64         // -B: scale sizes by SIZE
-	Total         bool          // --total: produce a grand total
-	Type          string        // -t: limit listing to file systems of given TYPE
-	ExcludeType   string        // -x: exclude file systems of given TYPE
-	Local         bool          // -l: limit listing to local file systems
-	Portability   bool          // -P: use POSIX output format
-	OutputFormat  output.Format // output format (text/json/table)
+	HumanReadable  bool          // -h: print sizes in human readable format
+	Inodes         bool          // -i: list inode information instead of block usage
+	BlockSize      int64         // -B: scale sizes by SIZE
+	Total          bool          // --total: produce a grand total
+	Type           string        // -t: limit listing to file systems of given TYPE
+	ExcludeType    string        // -x: exclude file systems of given TYPE
+	Local          bool          // -l: limit listing to local file systems
+	Portability    bool          // -P: use POSIX output format
+	PrintType      bool          // -T: print file system type
+	Threshold      int           // --threshold: fail when space use reaches N percent (0 = off)
+	InodeThreshold int           // --inode-threshold: fail when inode use reaches N percent (0 = off)
+	OutputFormat   output.Format // output format (text/json/table)
 }
 
 // DFInfo represents disk free space information
@@ -31,11 +39,20 @@ type DFInfo struct {
 	Available  uint64 `json:"available"`
 	UsePercent int    `json:"usePercent"`
 	MountedOn  string `json:"mountedOn"`
+	Path       string `json:"path"`
 	// Inode info
 	Inodes      uint64 `json:"inodes,omitempty"`
 	IUsed       uint64 `json:"iused,omitempty"`
 	IFree       uint64 `json:"ifree,omitempty"`
 	IUsePercent int    `json:"iusePercent,omitempty"`
+	// Exceeded is set when --threshold or --inode-threshold was reached
+	Exceeded bool `json:"exceeded,omitempty"`
+}
+
+// remoteTypes lists file system types excluded by -l/--local
+var remoteTypes = []string{
+	"nfs", "nfs4", "cifs", "smb", "smbfs", "smb3", "ncpfs", "afs",
+	"9p", "ceph", "glusterfs", "lustre", "sshfs", "fuse.sshfs", "webdav", "davfs",
 }
 
 // RunDF executes the df command
@@ -49,10 +66,14 @@ func RunDF(w io.Writer, args []string, opts DFOptions) error {
 	}
 
 	// Validate -t type filter (non-empty string must be a non-whitespace token)
-	if opts.Type != "" && len(opts.Type) == 0 {
+	if opts.Type != "" && strings.TrimSpace(opts.Type) == "" {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("df: invalid filesystem type: %q", opts.Type))
 	}
 
+	if opts.Threshold < 0 || opts.Threshold > 100 || opts.InodeThreshold < 0 || opts.InodeThreshold > 100 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "df: threshold must be between 0 and 100 percent")
+	}
+
 	paths := args
 	if len(paths) == 0 {
 		paths = []string{"/"}
@@ -60,49 +81,31 @@ func RunDF(w io.Writer, args []string, opts DFOptions) error {
 
 	f := output.New(w, opts.OutputFormat)
 
-	var jsonResults []DFInfo
+	var infos []DFInfo
 
-	if f.IsJSON() {
-		// Skip header for JSON output, collect results
-		for _, path := range paths {
-			info, err := getDiskInfo(path)
-			if err != nil {
-				continue
+	for _, path := range paths {
+		info, err := getDiskInfo(path)
+		if err != nil {
+			if !f.IsJSON() {
+				_, _ = fmt.Fprintf(w, "df: %s: %v\n", path, err)
 			}
 
-			jsonResults = append(jsonResults, info)
+			continue
 		}
 
-		return f.Print(jsonResults)
-	}
+		if !selected(info, opts) {
+			continue
+		}
 
-	// Print header
-	switch {
-	case opts.Inodes:
-		_, _ = fmt.Fprintf(w, "%-20s %10s %10s %10s %5s %s\n",
-			"Filesystem", "Inodes", "IUsed", "IFree", "IUse%", "Mounted on")
-	case opts.HumanReadable:
-		_, _ = fmt.Fprintf(w, "%-20s %6s %6s %6s %5s %s\n",
-			"Filesystem", "Size", "Used", "Avail", "Use%", "Mounted on")
-	default:
-		_, _ = fmt.Fprintf(w, "%-20s %10s %10s %10s %5s %s\n",
-			"Filesystem", "1K-blocks", "Used", "Available", "Use%", "Mounted on")
+		info.Exceeded = exceeded(info, opts)
+		infos = append(infos, info)
 	}
 
 	var total DFInfo
 
 	total.Filesystem = "total"
 
-	for _, path := range paths {
-		info, err := getDiskInfo(path)
-		if err != nil {
-			_, _ = fmt.Fprintf(w, "df: %s: %v\n", path, err)
-			continue
-		}
-
-		printDFInfo(w, info, opts)
-
-		// Accumulate totals
+	for _, info := range infos {
 		total.Size += info.Size
 		total.Used += info.Used
 		total.Available += info.Available
@@ -111,53 +114,187 @@ func RunDF(w io.Writer, args []string, opts DFOptions) error {
 		total.IFree += info.IFree
 	}
 
-	if opts.Total && len(paths) > 1 {
-		if total.Size > 0 {
-			total.UsePercent = int(float64(total.Used) / float64(total.Size) * 100)
+	if total.Size > 0 {
+		total.UsePercent = int(float64(total.Used) / float64(total.Size) * 100)
+	}
+
+	if total.Inodes > 0 {
+		total.IUsePercent = int(float64(total.IUsed) / float64(total.Inodes) * 100)
+	}
+
+	total.Type = "-"
+	total.MountedOn = "-"
+	showTotal := opts.Total && len(paths) > 1
+
+	if f.IsJSON() {
+		results := infos
+		if showTotal {
+			results = append(results, total)
 		}
 
-		if total.Inodes > 0 {
-			total.IUsePercent = int(float64(total.IUsed) / float64(total.Inodes) * 100)
+		if err := f.Print(results); err != nil {
+			return err
 		}
 
-		total.MountedOn = "-"
-		printDFInfo(w, total, opts)
+		return thresholdError(infos, opts)
 	}
 
-	return nil
+	table := newDFTable(opts)
+
+	for _, info := range infos {
+		table.Append(dfRow(info, opts)...)
+	}
+
+	if showTotal {
+		table.Append(dfRow(total, opts)...)
+	}
+
+	if err := f.PrintTable(table); err != nil {
+		return err
+	}
+
+	return thresholdError(infos, opts)
 }
 
-func printDFInfo(w io.Writer, info DFInfo, opts DFOptions) {
+// ParseThreshold parses a --threshold value such as "90%" or "90".
+func ParseThreshold(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if err != nil || n < 0 || n > 100 {
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("df: invalid threshold %q (want a percentage from 0 to 100)", s))
+	}
+
+	return n, nil
+}
+
+// selected applies the -t, -x and -l filters
+func selected(info DFInfo, opts DFOptions) bool {
+	if opts.Type != "" && !matchesType(info.Type, opts.Type) {
+		return false
+	}
+
+	if opts.ExcludeType != "" && matchesType(info.Type, opts.ExcludeType) {
+		return false
+	}
+
+	if opts.Local && slices.Contains(remoteTypes, strings.ToLower(info.Type)) {
+		return false
+	}
+
+	return true
+}
+
+// matchesType reports whether fsType is one of the comma-separated types
+func matchesType(fsType, list string) bool {
+	for t := range strings.SplitSeq(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), fsType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func exceeded(info DFInfo, opts DFOptions) bool {
+	if opts.Threshold > 0 && info.UsePercent >= opts.Threshold {
+		return true
+	}
+
+	return opts.InodeThreshold > 0 && info.Inodes > 0 && info.IUsePercent >= opts.InodeThreshold
+}
+
+// thresholdError reports every file system over a threshold on stderr and
+// returns a conflict error so health checks can rely on the exit code. The
+// listing has already been written by the time this runs.
+func thresholdError(infos []DFInfo, opts DFOptions) error {
+	var over []string
+
+	for _, info := range infos {
+		if !info.Exceeded {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s: %d%% used", info.MountedOn, info.UsePercent)
+		if opts.InodeThreshold > 0 && info.Inodes > 0 {
+			msg += fmt.Sprintf(", %d%% inodes used", info.IUsePercent)
+		}
+
+		over = append(over, msg)
+	}
+
+	if len(over) == 0 {
+		return nil
+	}
+
+	for _, msg := range over {
+		_, _ = fmt.Fprintf(os.Stderr, "df: threshold exceeded: %s\n", msg)
+	}
+
+	return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("df: %d file system(s) over threshold", len(over)))
+}
+
+// newDFTable returns an empty table with the columns opts selects.
+func newDFTable(opts DFOptions) *tablewriter.Table {
+	cols := []tablewriter.Column{{Header: "Filesystem"}}
+	if opts.PrintType {
+		cols = append(cols, tablewriter.Column{Header: "Type"})
+	}
+
+	number := func(header string) tablewriter.Column {
+		return tablewriter.Column{Header: header, Align: tablewriter.AlignRight, NoShrink: true}
+	}
+
+	switch {
+	case opts.Inodes:
+		cols = append(cols, number("Inodes"), number("IUsed"), number("IFree"), number("IUse%"))
+	case opts.HumanReadable:
+		cols = append(cols, number("Size"), number("Used"), number("Avail"), number("Use%"))
+	default:
+		cols = append(cols, number("1K-blocks"), number("Used"), number("Available"), number("Use%"))
+	}
+
+	cols = append(cols, tablewriter.Column{Header: "Mounted on"})
+
+	return tablewriter.New(cols...)
+}
+
+// dfRow returns the cells of info for the table newDFTable(opts) returns.
+func dfRow(info DFInfo, opts DFOptions) []string {
+	row := []string{info.Filesystem}
+	if opts.PrintType {
+		row = append(row, info.Type)
+	}
+
 	switch {
 	case opts.Inodes:
-		_, _ = fmt.Fprintf(w, "%-20s %10d %10d %10d %4d%% %s\n",
-			info.Filesystem,
-			info.Inodes,
-			info.IUsed,
-			info.IFree,
-			info.IUsePercent,
-			info.MountedOn)
+		row = append(row,
+			strconv.FormatUint(info.Inodes, 10),
+			strconv.FormatUint(info.IUsed, 10),
+			strconv.FormatUint(info.IFree, 10),
+			fmt.Sprintf("%d%%", info.IUsePercent))
 	case opts.HumanReadable:
-		_, _ = fmt.Fprintf(w, "%-20s %6s %6s %6s %4d%% %s\n",
-			info.Filesystem,
+		row = append(row,
 			du.FormatHumanSize(int64(info.Size)),
 			du.FormatHumanSize(int64(info.Used)),
 			du.FormatHumanSize(int64(info.Available)),
-			info.UsePercent,
-			info.MountedOn)
+			fmt.Sprintf("%d%%", info.UsePercent))
 	default:
-		blocks := info.Size / uint64(opts.BlockSize)
-		usedBlocks := info.Used / uint64(opts.BlockSize)
-		availBlocks := info.Available / uint64(opts.BlockSize)
-
-		_, _ = fmt.Fprintf(w, "%-20s %10d %10d %10d %4d%% %s\n",
-			info.Filesystem,
-			blocks,
-			usedBlocks,
-			availBlocks,
-			info.UsePercent,
-			info.MountedOn)
+		blockSize := opts.BlockSize
+		if blockSize <= 0 {
+			blockSize = 1024
+		}
+
+		row = append(row,
+			strconv.FormatUint(info.Size/uint64(blockSize), 10),
+			strconv.FormatUint(info.Used/uint64(blockSize), 10),
+			strconv.FormatUint(info.Available/uint64(blockSize), 10),
+			fmt.Sprintf("%d%%", info.UsePercent))
 	}
+
+	return append(row, info.MountedOn)
 }
 
 // GetDiskFree returns disk space information for a path
@@ -0,0 +1,25 @@
+//go:build darwin
+
+package df
+
+import "syscall"
+
+// mountInfo returns the device, file system type and mount point that
+// statfs(2) already reports on Darwin.
+func mountInfo(_ string, stat *syscall.Statfs_t) (source, fsType, mountPoint string) {
+	return cString(stat.Mntfromname[:]), cString(stat.Fstypename[:]), cString(stat.Mntonname[:])
+}
+
+func cString(b []int8) string {
+	buf := make([]byte, 0, len(b))
+
+	for _, c := range b {
+		if c == 0 {
+			break
+		}
+
+		buf = append(buf, byte(c))
+	}
+
+	return string(buf)
+}
@@ -0,0 +1,101 @@
+//go:build linux
+
+package df
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// mountInfo resolves the device, file system type and mount point for path
+// from /proc/self/mountinfo, picking the longest mount point containing it.
+// When the table cannot be read, path stands in for the source and mount.
+func mountInfo(path string, _ *syscall.Statfs_t) (source, fsType, mountPoint string) {
+	source, mountPoint = path, path
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return source, fsType, mountPoint
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return source, fsType, mountPoint
+	}
+
+	defer func() { _ = f.Close() }()
+
+	best := -1
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		// id parent major:minor root mountpoint options [optional...] - fstype source superoptions
+		fields := strings.Fields(scanner.Text())
+
+		sep := -1
+
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+
+		if len(fields) < 5 || sep < 0 || sep+2 >= len(fields) {
+			continue
+		}
+
+		mnt := unescapeMount(fields[4])
+		if !containsPath(mnt, abs) || len(mnt) < best {
+			continue
+		}
+
+		best = len(mnt)
+		mountPoint = mnt
+		fsType = fields[sep+1]
+		source = unescapeMount(fields[sep+2])
+	}
+
+	return source, fsType, mountPoint
+}
+
+// containsPath reports whether path lies on or below mount point mnt
+func containsPath(mnt, path string) bool {
+	if mnt == "/" || mnt == path {
+		return true
+	}
+
+	return strings.HasPrefix(path, mnt+"/")
+}
+
+// unescapeMount decodes the octal escapes (\040 for space etc.) used in mountinfo
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+
+				continue
+			}
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
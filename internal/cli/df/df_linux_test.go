@@ -0,0 +1,44 @@
+//go:build linux
+
+package df
+
+import "testing"
+
+func TestUnescapeMount(t *testing.T) {
+	if got := unescapeMount(`/mnt/my\040disk`); got != "/mnt/my disk" {
+		t.Errorf("unescapeMount() = %q", got)
+	}
+
+	if got := unescapeMount(`/plain`); got != "/plain" {
+		t.Errorf("unescapeMount() = %q", got)
+	}
+}
+
+func TestContainsPath(t *testing.T) {
+	tests := []struct {
+		mnt, path string
+		want      bool
+	}{
+		{"/", "/anything", true},
+		{"/data", "/data", true},
+		{"/data", "/data/x", true},
+		{"/data", "/database", false},
+	}
+
+	for _, tt := range tests {
+		if got := containsPath(tt.mnt, tt.path); got != tt.want {
+			t.Errorf("containsPath(%q, %q) = %v, want %v", tt.mnt, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMountInfoRoot(t *testing.T) {
+	_, fsType, mnt := mountInfo("/", nil)
+	if mnt != "/" {
+		t.Errorf("mountInfo(/) mount point = %q, want /", mnt)
+	}
+
+	if fsType == "" {
+		t.Error("mountInfo(/) should report a file system type")
+	}
+}
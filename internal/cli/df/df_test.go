@@ -2,8 +2,13 @@ package df
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/tablewriter"
 )
 
 func TestRunDF(t *testing.T) {
@@ -139,37 +144,164 @@ func TestPrintDFInfo(t *testing.T) {
 	}
 
 	t.Run("default format", func(t *testing.T) {
-		var buf bytes.Buffer
-		printDFInfo(&buf, info, DFOptions{BlockSize: 1024})
-
-		output := buf.String()
+		output := strings.Join(dfRow(info, DFOptions{BlockSize: 1024}), " ")
 		if !strings.Contains(output, "test") {
-			t.Errorf("printDFInfo() should contain filesystem name: %s", output)
+			t.Errorf("dfRow() should contain filesystem name: %s", output)
 		}
 
 		if !strings.Contains(output, "/test") {
-			t.Errorf("printDFInfo() should contain mount point: %s", output)
+			t.Errorf("dfRow() should contain mount point: %s", output)
 		}
 	})
 
 	t.Run("human readable format", func(t *testing.T) {
-		var buf bytes.Buffer
-		printDFInfo(&buf, info, DFOptions{HumanReadable: true})
-
-		output := buf.String()
+		output := strings.Join(dfRow(info, DFOptions{HumanReadable: true}), " ")
 		// Should have human readable sizes like 1.0G or 512M
 		if !strings.Contains(output, "G") && !strings.Contains(output, "M") {
-			t.Errorf("printDFInfo() -h should have human readable sizes: %s", output)
+			t.Errorf("dfRow() -h should have human readable sizes: %s", output)
 		}
 	})
 
 	t.Run("inodes format", func(t *testing.T) {
-		var buf bytes.Buffer
-		printDFInfo(&buf, info, DFOptions{Inodes: true})
-
-		output := buf.String()
+		output := strings.Join(dfRow(info, DFOptions{Inodes: true}), " ")
 		if !strings.Contains(output, "1000") {
-			t.Errorf("printDFInfo() -i should show inode count: %s", output)
+			t.Errorf("dfRow() -i should show inode count: %s", output)
 		}
 	})
 }
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"90%", 90, false},
+		{"90", 90, false},
+		{" 75% ", 75, false},
+		{"100%", 100, false},
+		{"101%", 0, true},
+		{"-1", 0, true},
+		{"ninety", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseThreshold(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseThreshold(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if err != nil && !cmderr.IsInvalidInput(err) {
+			t.Errorf("ParseThreshold(%q) error = %v, want invalid input", tt.in, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseThreshold(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	info := DFInfo{MountedOn: "/data", UsePercent: 91, Inodes: 100, IUsePercent: 40}
+
+	tests := []struct {
+		name string
+		opts DFOptions
+		want bool
+	}{
+		{"disabled", DFOptions{}, false},
+		{"space reached", DFOptions{Threshold: 91}, true},
+		{"space below", DFOptions{Threshold: 95}, false},
+		{"inodes reached", DFOptions{InodeThreshold: 40}, true},
+		{"inodes below", DFOptions{InodeThreshold: 50}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exceeded(info, tt.opts); got != tt.want {
+				t.Errorf("exceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := exceeded(DFInfo{IUsePercent: 0}, DFOptions{InodeThreshold: 1}); got {
+		t.Error("file systems without inode data must not trip the inode threshold")
+	}
+
+	info.Exceeded = true
+	if err := thresholdError([]DFInfo{info}, DFOptions{Threshold: 90}); !cmderr.IsConflict(err) {
+		t.Errorf("thresholdError() = %v, want conflict", err)
+	}
+
+	if err := thresholdError([]DFInfo{{UsePercent: 99}}, DFOptions{}); err != nil {
+		t.Errorf("thresholdError() = %v, want nil", err)
+	}
+}
+
+func TestRunDFThreshold(t *testing.T) {
+	var buf bytes.Buffer
+
+	// 100% can only be reached by a full file system; the listing must be
+	// printed either way.
+	err := RunDF(&buf, []string{"."}, DFOptions{Threshold: 100, OutputFormat: output.FormatJSON})
+	if err != nil && !cmderr.IsConflict(err) {
+		t.Fatalf("RunDF() error = %v", err)
+	}
+
+	var infos []DFInfo
+	if err := json.Unmarshal(buf.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(infos) != 1 || infos[0].Path != "." || infos[0].MountedOn == "" {
+		t.Errorf("unexpected JSON: %+v", infos)
+	}
+
+	if err := RunDF(&bytes.Buffer{}, nil, DFOptions{Threshold: 150}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunDF(threshold 150) = %v, want invalid input", err)
+	}
+}
+
+func TestSelected(t *testing.T) {
+	ext4 := DFInfo{Type: "ext4"}
+	nfs := DFInfo{Type: "nfs4"}
+
+	tests := []struct {
+		name string
+		info DFInfo
+		opts DFOptions
+		want bool
+	}{
+		{"no filter", ext4, DFOptions{}, true},
+		{"type match", ext4, DFOptions{Type: "xfs,ext4"}, true},
+		{"type mismatch", ext4, DFOptions{Type: "xfs"}, false},
+		{"exclude", ext4, DFOptions{ExcludeType: "EXT4"}, false},
+		{"local keeps ext4", ext4, DFOptions{Local: true}, true},
+		{"local drops nfs", nfs, DFOptions{Local: true}, false},
+	}
+
+	for _, tt := range tests {
+		if got := selected(tt.info, tt.opts); got != tt.want {
+			t.Errorf("%s: selected() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrintType(t *testing.T) {
+	var buf bytes.Buffer
+
+	opts := DFOptions{PrintType: true, BlockSize: 1024}
+	table := newDFTable(opts)
+	table.Append(dfRow(DFInfo{Filesystem: "/dev/sda1", Type: "ext4", MountedOn: "/"}, opts)...)
+
+	if err := table.Render(&buf, tablewriter.Text); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "Type") || !strings.Contains(out, "ext4") {
+		t.Errorf("-T output missing type column:\n%s", out)
+	}
+}
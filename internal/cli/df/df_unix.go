@@ -40,13 +40,17 @@ func getDiskInfo(path string) (DFInfo, error) {
 		iusePercent = int(float64(stat.Files-stat.Ffree) / float64(stat.Files) * 100)
 	}
 
+	source, fsType, mountPoint := mountInfo(path, &stat)
+
 	return DFInfo{
-		Filesystem:  path,
+		Filesystem:  source,
+		Type:        fsType,
 		Size:        total,
 		Used:        used,
 		Available:   free,
 		UsePercent:  usePercent,
-		MountedOn:   path,
+		MountedOn:   mountPoint,
+		Path:        path,
 		Inodes:      stat.Files,
 		IUsed:       stat.Files - stat.Ffree,
 		IFree:       stat.Ffree,
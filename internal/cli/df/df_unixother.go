@@ -0,0 +1,11 @@
+//go:build unix && !linux && !darwin
+
+package df
+
+import "syscall"
+
+// mountInfo falls back to the queried path where no mount table lookup is
+// implemented.
+func mountInfo(path string, _ *syscall.Statfs_t) (source, fsType, mountPoint string) {
+	return path, "", path
+}
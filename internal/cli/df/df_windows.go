@@ -8,6 +8,7 @@ import (
 	"unsafe"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"golang.org/x/sys/windows"
 )
 
 // getDiskInfo returns disk usage information for a path on Windows
@@ -40,13 +41,17 @@ func getDiskInfo(path string) (DFInfo, error) {
 		usePercent = int(float64(used) / float64(totalBytes) * 100)
 	}
 
+	volume, fsType := volumeInfo(path)
+
 	return DFInfo{
-		Filesystem: path,
+		Filesystem: volume,
+		Type:       fsType,
 		Size:       totalBytes,
 		Used:       used,
 		Available:  freeBytesAvailable,
 		UsePercent: usePercent,
-		MountedOn:  path,
+		MountedOn:  volume,
+		Path:       path,
 		// Windows doesn't expose inode info in the same way
 		Inodes:      0,
 		IUsed:       0,
@@ -54,3 +59,25 @@ func getDiskInfo(path string) (DFInfo, error) {
 		IUsePercent: 0,
 	}, nil
 }
+
+// volumeInfo returns the volume root (e.g. C:\) containing path and its
+// file system name (NTFS, ReFS, FAT32...). Path is returned unchanged when
+// the volume cannot be resolved.
+func volumeInfo(path string) (volume, fsType string) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return path, ""
+	}
+
+	root := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumePathName(pathPtr, &root[0], uint32(len(root))); err != nil {
+		return path, ""
+	}
+
+	fsName := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumeInformation(&root[0], nil, 0, nil, nil, nil, &fsName[0], uint32(len(fsName))); err != nil {
+		return windows.UTF16ToString(root), ""
+	}
+
+	return windows.UTF16ToString(root), windows.UTF16ToString(fsName)
+}
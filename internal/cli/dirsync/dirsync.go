@@ -0,0 +1,528 @@
+// Package dirsync implements one-way synchronization of a local directory
+// tree into another, in the spirit of a minimal rsync.
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/twig/comparer"
+	"github.com/inovacc/omni/pkg/twig/models"
+	"github.com/inovacc/omni/pkg/twig/scanner"
+)
+
+// defaultWorkers bounds concurrent copies; disk throughput, not CPU, is the limit.
+const defaultWorkers = 4
+
+// Options configures the sync command behavior
+type Options struct {
+	Delete       bool          // --delete: remove destination entries missing from the source
+	Checksum     bool          // -c/--checksum: compare SHA-256 instead of size+mtime
+	Exclude      []string      // --exclude: glob patterns to skip (name or relative path)
+	DryRun       bool          // -n/--dry-run: print the change set without applying it
+	Verbose      bool          // -v/--verbose: list changes as they are applied
+	Threads      int           // -t/--threads: parallel copy workers (0 = auto)
+	OutputFormat output.Format // output format (text/json)
+
+	Confirm confirm.Options // -i/--interactive, -y/--yes: confirm the change set before applying it
+}
+
+// Summary counts the entries in a change set
+type Summary struct {
+	Added    int   `json:"added"`
+	Removed  int   `json:"removed"`
+	Modified int   `json:"modified"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// Result is the outcome of a sync run
+type Result struct {
+	Source  string            `json:"source"`
+	Dest    string            `json:"dest"`
+	DryRun  bool              `json:"dry_run"`
+	Changes []comparer.Change `json:"changes"`
+	Summary Summary           `json:"summary"`
+	Errors  []string          `json:"errors,omitempty"`
+	entries map[string]*entry
+}
+
+// entry is a scanned filesystem object keyed by its slash-separated relative path
+type entry struct {
+	node   *models.Node
+	isDir  bool
+	isLink bool
+}
+
+// RunSync synchronizes the directory SRC into DST.
+// As with rsync, a trailing slash on SRC copies its contents; without one the
+// directory itself is created inside DST.
+func RunSync(w io.Writer, args []string, opts Options) error {
+	if len(args) != 2 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "sync: expected SRC and DST operands")
+	}
+
+	src, dst := args[0], args[1]
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	if !info.IsDir() {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sync: %s: not a directory", src))
+	}
+
+	if !strings.HasSuffix(src, "/") && !strings.HasSuffix(src, string(filepath.Separator)) {
+		abs, err := filepath.Abs(src)
+		if err != nil {
+			return classifyError(err)
+		}
+
+		dst = filepath.Join(dst, filepath.Base(abs))
+	}
+
+	result, err := Plan(src, dst, opts)
+	if err != nil {
+		return err
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	verbose := (opts.Verbose || opts.DryRun) && !f.IsJSON()
+
+	if !opts.DryRun {
+		ok, err := confirmChanges(src, dst, result, opts)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			_, _ = fmt.Fprintln(os.Stderr, "sync: nothing changed")
+			return nil
+		}
+
+		apply(w, src, dst, result, opts, verbose)
+	} else if verbose {
+		for _, c := range result.Changes {
+			printChange(w, c)
+		}
+	}
+
+	if f.IsJSON() {
+		if err := f.Print(result); err != nil {
+			return err
+		}
+	} else if verbose {
+		_, _ = fmt.Fprintf(w, "\n%d added, %d removed, %d modified, %d bytes\n",
+			result.Summary.Added, result.Summary.Removed, result.Summary.Modified, result.Summary.Bytes)
+	}
+
+	if len(result.Errors) > 0 {
+		return cmderr.PartialFailure(cmderr.ExitCodeFor(cmderr.ErrPartial), fmt.Sprintf("sync: %d entries failed", len(result.Errors)))
+	}
+
+	return nil
+}
+
+// confirmChanges shows the change set on standard error and asks once
+// before applying it when --interactive is given.
+func confirmChanges(src, dst string, result *Result, opts Options) (bool, error) {
+	if len(result.Changes) == 0 || !opts.Confirm.Interactive {
+		return true, nil
+	}
+
+	c := confirm.New("sync", opts.Confirm)
+
+	if !opts.Confirm.Yes {
+		for _, ch := range result.Changes {
+			printChange(os.Stderr, ch)
+		}
+	}
+
+	return c.Confirm(fmt.Sprintf("apply %d added, %d removed, %d modified from '%s' to '%s'",
+		result.Summary.Added, result.Summary.Removed, result.Summary.Modified, src, dst))
+}
+
+// Plan scans src and dst and returns the change set that would make dst
+// match src. A missing dst is treated as empty.
+func Plan(src, dst string, opts Options) (*Result, error) {
+	cfg := &scanner.ScanConfig{
+		MaxDepth:   -1,
+		ShowHidden: true,
+		ShowHash:   opts.Checksum,
+		Parallel:   opts.Threads,
+	}
+
+	ctx := context.Background()
+
+	srcRoot, err := scanner.NewScanner(cfg).Scan(ctx, src)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	srcEntries := make(map[string]*entry)
+	srcTree := snapshot(srcRoot, "", opts, srcEntries)
+
+	dstEntries := make(map[string]*entry)
+	dstTree := &models.JSONNode{IsDir: true}
+
+	if info, err := os.Stat(dst); err == nil {
+		if !info.IsDir() {
+			return nil, cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("sync: %s: not a directory", dst))
+		}
+
+		dstRoot, err := scanner.NewScanner(cfg).Scan(ctx, dst)
+		if err != nil {
+			return nil, classifyError(err)
+		}
+
+		dstTree = snapshot(dstRoot, "", opts, dstEntries)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, classifyError(err)
+	}
+
+	// Destination is the "old" side: added entries must be copied,
+	// removed ones exist only in the destination.
+	cmp := comparer.Compare(dstTree, srcTree, comparer.CompareConfig{})
+
+	result := &Result{
+		Source:  src,
+		Dest:    dst,
+		DryRun:  opts.DryRun,
+		entries: srcEntries,
+	}
+
+	for _, c := range cmp.Changes {
+		switch c.Type {
+		case comparer.Removed:
+			if !opts.Delete {
+				continue
+			}
+
+			result.Summary.Removed++
+		case comparer.Added:
+			result.Summary.Added++
+			result.Summary.Bytes += size(srcEntries[c.Path])
+		case comparer.Modified:
+			result.Summary.Modified++
+			result.Summary.Bytes += size(srcEntries[c.Path])
+		}
+
+		result.Changes = append(result.Changes, c)
+	}
+
+	// The comparer ignores directories when looking for modifications, so a
+	// file replaced by a directory (or vice versa) is detected here.
+	var replaced []string
+
+	for p, s := range srcEntries {
+		if d, ok := dstEntries[p]; ok && d.isDir != s.isDir {
+			replaced = append(replaced, p)
+		}
+	}
+
+	sort.Strings(replaced)
+
+	for _, p := range replaced {
+		result.Changes = append(result.Changes, comparer.Change{Type: comparer.Modified, Path: p, IsDir: srcEntries[p].isDir})
+		result.Summary.Modified++
+		result.Summary.Bytes += size(srcEntries[p])
+	}
+
+	if result.Changes == nil {
+		result.Changes = []comparer.Change{}
+	}
+
+	return result, nil
+}
+
+// snapshot converts a scanned tree into the comparer's JSON form, pruning
+// excluded entries and recording each entry by relative path. Files carry
+// either their SHA-256 (checksum mode) or a size+mtime fingerprint.
+func snapshot(n *models.Node, rel string, opts Options, out map[string]*entry) *models.JSONNode {
+	jn := &models.JSONNode{Name: path.Base(rel), IsDir: n.IsDir}
+	if rel == "" {
+		jn.Name = ""
+	}
+
+	e := &entry{node: n, isDir: n.IsDir}
+
+	if !n.IsDir {
+		jn.Hash = n.Hash
+
+		if n.FileInfo != nil && n.FileInfo.Mode()&os.ModeSymlink != 0 {
+			e.isLink = true
+			target, _ := os.Readlink(n.Path)
+			jn.Hash = "symlink:" + target
+		} else if !opts.Checksum && n.FileInfo != nil {
+			jn.Hash = fmt.Sprintf("%d:%d", n.FileInfo.Size(), n.FileInfo.ModTime().Unix())
+		}
+	}
+
+	if rel != "" {
+		out[rel] = e
+	}
+
+	for _, child := range n.Children {
+		childRel := child.Name
+		if rel != "" {
+			childRel = rel + "/" + child.Name
+		}
+
+		if excluded(childRel, child.IsDir, opts.Exclude) {
+			continue
+		}
+
+		jn.Children = append(jn.Children, snapshot(child, childRel, opts, out))
+	}
+
+	return jn
+}
+
+// excluded reports whether rel matches one of the patterns. Patterns match
+// the base name or the whole relative path; a trailing slash restricts the
+// pattern to directories.
+func excluded(rel string, isDir bool, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "/") {
+			if !isDir {
+				continue
+			}
+
+			p = strings.TrimSuffix(p, "/")
+		}
+
+		p = filepath.ToSlash(p)
+
+		if ok, _ := path.Match(p, path.Base(rel)); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(p, rel); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func size(e *entry) int64 {
+	if e == nil || e.isDir || e.isLink || e.node.FileInfo == nil {
+		return 0
+	}
+
+	return e.node.FileInfo.Size()
+}
+
+// apply executes the change set: deletions first, then directory creation in
+// path order, then file copies across a worker pool.
+func apply(w io.Writer, src, dst string, result *Result, opts Options, verbose bool) {
+	var mu sync.Mutex
+
+	report := func(c comparer.Change, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			msg := fmt.Sprintf("sync: %s: %s", c.Path, err)
+			result.Errors = append(result.Errors, msg)
+			_, _ = fmt.Fprintln(os.Stderr, msg)
+
+			return
+		}
+
+		if verbose {
+			printChange(w, c)
+		}
+	}
+
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		report(comparer.Change{Path: "."}, err)
+		return
+	}
+
+	var dirs, files []comparer.Change
+
+	for _, c := range result.Changes {
+		target := filepath.Join(dst, filepath.FromSlash(c.Path))
+
+		switch {
+		case c.Type == comparer.Removed:
+			report(c, os.RemoveAll(target))
+		case c.Type == comparer.Modified && c.IsDir:
+			// A file stands where the directory goes.
+			if err := os.Remove(target); err != nil {
+				report(c, err)
+				continue
+			}
+
+			dirs = append(dirs, c)
+		case c.Type == comparer.Modified && isDir(target):
+			// A directory stands where the file goes; only --delete may clear it.
+			if !opts.Delete {
+				report(c, errors.New("cannot replace directory with a file without --delete"))
+				continue
+			}
+
+			if err := os.RemoveAll(target); err != nil {
+				report(c, err)
+				continue
+			}
+
+			files = append(files, c)
+		case c.IsDir:
+			dirs = append(dirs, c)
+		default:
+			files = append(files, c)
+		}
+	}
+
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+
+	for _, c := range dirs {
+		mode := os.FileMode(0o755)
+		if e := result.entries[c.Path]; e != nil && e.node.FileInfo != nil {
+			mode = e.node.FileInfo.Mode().Perm()
+		}
+
+		report(c, os.MkdirAll(filepath.Join(dst, filepath.FromSlash(c.Path)), mode))
+	}
+
+	workers := opts.Threads
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+
+	for range min(workers, len(files)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(files) {
+					return
+				}
+
+				c := files[i]
+				report(c, copyEntry(result.entries[c.Path],
+					filepath.Join(src, filepath.FromSlash(c.Path)),
+					filepath.Join(dst, filepath.FromSlash(c.Path))))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// copyEntry copies one file or symlink. Regular files are written to a
+// temporary sibling and renamed into place, so an interrupted sync never
+// leaves a truncated file under the final name. The source mtime is kept so
+// the next size+mtime comparison sees the files as equal.
+func copyEntry(e *entry, from, to string) error {
+	if e != nil && e.isLink {
+		target, err := os.Readlink(from)
+		if err != nil {
+			return err
+		}
+
+		_ = os.Remove(to)
+
+		return os.Symlink(target, to)
+	}
+
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(to), "."+filepath.Base(to)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+
+	_ = os.Chmod(tmpName, info.Mode().Perm())
+
+	if err := os.Chtimes(tmpName, info.ModTime(), info.ModTime()); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, to); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
+
+func isDir(p string) bool {
+	info, err := os.Lstat(p)
+	return err == nil && info.IsDir()
+}
+
+func printChange(w io.Writer, c comparer.Change) {
+	p := c.Path
+	if c.IsDir {
+		p += "/"
+	}
+
+	switch c.Type {
+	case comparer.Added:
+		_, _ = fmt.Fprintf(w, "+ %s\n", p)
+	case comparer.Removed:
+		_, _ = fmt.Fprintf(w, "- %s\n", p)
+	case comparer.Modified:
+		_, _ = fmt.Fprintf(w, "~ %s\n", p)
+	}
+}
+
+// classifyError maps scanner and filesystem errors to cmderr sentinels.
+func classifyError(err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, scanner.ErrPathNotFound):
+		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("sync: %s", err))
+	case errors.Is(err, os.ErrPermission), errors.Is(err, scanner.ErrPermissionDenied):
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("sync: %s", err))
+	default:
+		return fmt.Errorf("sync: %w", err)
+	}
+}
@@ -0,0 +1,338 @@
+package dirsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func newTrees(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src") + "/"
+	dst := filepath.Join(dir, "dst")
+
+	writeFile(t, filepath.Join(src, "a.txt"), "alpha")
+	writeFile(t, filepath.Join(src, "sub", "b.txt"), "bravo")
+	writeFile(t, filepath.Join(src, "sub", "deep", "c.txt"), "charlie")
+
+	return src, dst
+}
+
+func TestRunSyncCopiesTree(t *testing.T) {
+	src, dst := newTrees(t)
+
+	var buf bytes.Buffer
+	if err := RunSync(&buf, []string{src, dst}, Options{}); err != nil {
+		t.Fatalf("RunSync() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("RunSync() should be quiet by default, got %q", buf.String())
+	}
+
+	for rel, want := range map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo", "sub/deep/c.txt": "charlie"} {
+		if got := readFile(t, filepath.Join(dst, rel)); got != want {
+			t.Errorf("%s = %q, want %q", rel, got, want)
+		}
+	}
+
+	srcInfo, _ := os.Stat(filepath.Join(src, "a.txt"))
+	dstInfo, _ := os.Stat(filepath.Join(dst, "a.txt"))
+
+	if !srcInfo.ModTime().Equal(dstInfo.ModTime()) {
+		t.Errorf("mtime not preserved: %v vs %v", dstInfo.ModTime(), srcInfo.ModTime())
+	}
+}
+
+func TestRunSyncNoTrailingSlash(t *testing.T) {
+	src, dst := newTrees(t)
+
+	if err := RunSync(&bytes.Buffer{}, []string{strings.TrimSuffix(src, "/"), dst}, Options{}); err != nil {
+		t.Fatalf("RunSync() error = %v", err)
+	}
+
+	if !exists(filepath.Join(dst, "src", "a.txt")) {
+		t.Error("source directory should be created inside destination")
+	}
+}
+
+func TestRunSyncIncremental(t *testing.T) {
+	src, dst := newTrees(t)
+
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Plan(src, dst, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Changes) != 0 {
+		t.Fatalf("second run should be a no-op, got %+v", result.Changes)
+	}
+
+	writeFile(t, filepath.Join(src, "a.txt"), "alpha, changed")
+	writeFile(t, filepath.Join(src, "new.txt"), "new")
+
+	var buf bytes.Buffer
+	if err := RunSync(&buf, []string{src, dst}, Options{Verbose: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "+ new.txt") || !strings.Contains(out, "~ a.txt") {
+		t.Errorf("verbose output = %q", out)
+	}
+
+	if strings.Contains(out, "b.txt") {
+		t.Errorf("unchanged file listed: %q", out)
+	}
+
+	if got := readFile(t, filepath.Join(dst, "a.txt")); got != "alpha, changed" {
+		t.Errorf("a.txt = %q", got)
+	}
+}
+
+func TestRunSyncDelete(t *testing.T) {
+	src, dst := newTrees(t)
+
+	writeFile(t, filepath.Join(dst, "stale.txt"), "old")
+	writeFile(t, filepath.Join(dst, "olddir", "x.txt"), "old")
+
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exists(filepath.Join(dst, "stale.txt")) {
+		t.Fatal("extra files must be kept without --delete")
+	}
+
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, Options{Delete: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists(filepath.Join(dst, "stale.txt")) || exists(filepath.Join(dst, "olddir")) {
+		t.Error("--delete should remove entries missing from the source")
+	}
+}
+
+func TestRunSyncExclude(t *testing.T) {
+	src, dst := newTrees(t)
+
+	writeFile(t, filepath.Join(src, "build.log"), "log")
+	writeFile(t, filepath.Join(dst, "keep.log"), "dst only")
+
+	opts := Options{Exclude: []string{"*.log", "sub/deep/"}, Delete: true}
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists(filepath.Join(dst, "build.log")) {
+		t.Error("excluded file was copied")
+	}
+
+	if exists(filepath.Join(dst, "sub", "deep")) {
+		t.Error("excluded directory was copied")
+	}
+
+	if !exists(filepath.Join(dst, "keep.log")) {
+		t.Error("excluded destination file must not be deleted")
+	}
+}
+
+func TestRunSyncDryRun(t *testing.T) {
+	src, dst := newTrees(t)
+
+	var buf bytes.Buffer
+	if err := RunSync(&buf, []string{src, dst}, Options{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists(dst) {
+		t.Error("dry run must not touch the destination")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"+ a.txt\n", "+ sub/\n", "+ sub/deep/c.txt\n", "5 added, 0 removed, 0 modified, 17 bytes\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunSyncChecksum(t *testing.T) {
+	src, dst := newTrees(t)
+
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same size and mtime, different content: only --checksum notices.
+	target := filepath.Join(dst, "a.txt")
+	info, _ := os.Stat(target)
+	writeFile(t, target, "ALPHA")
+
+	if err := os.Chtimes(target, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	quick, err := Plan(src, dst, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(quick.Changes) != 0 {
+		t.Errorf("size+mtime comparison should see no change, got %+v", quick.Changes)
+	}
+
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, Options{Checksum: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFile(t, target); got != "alpha" {
+		t.Errorf("a.txt = %q, want alpha", got)
+	}
+}
+
+func TestRunSyncTypeChange(t *testing.T) {
+	src, dst := newTrees(t)
+
+	writeFile(t, filepath.Join(dst, "sub"), "a file where a directory belongs")
+
+	if err := RunSync(&bytes.Buffer{}, []string{src, dst}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFile(t, filepath.Join(dst, "sub", "b.txt")); got != "bravo" {
+		t.Errorf("sub/b.txt = %q", got)
+	}
+}
+
+func TestRunSyncJSON(t *testing.T) {
+	src, dst := newTrees(t)
+
+	var buf bytes.Buffer
+	if err := RunSync(&buf, []string{src, dst}, Options{DryRun: true, OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatal(err)
+	}
+
+	var result Result
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if !result.DryRun || result.Summary.Added != 5 || result.Summary.Bytes != int64(len("alphabravocharlie")) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestRunSyncErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	err := RunSync(&bytes.Buffer{}, []string{filepath.Join(dir, "missing") + "/", filepath.Join(dir, "dst")}, Options{})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("missing source: got %v, want not found", err)
+	}
+
+	file := filepath.Join(dir, "file")
+	writeFile(t, file, "x")
+
+	if err := RunSync(&bytes.Buffer{}, []string{file, dir}, Options{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("file source: got %v, want invalid input", err)
+	}
+
+	if err := RunSync(&bytes.Buffer{}, []string{dir}, Options{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("one operand: got %v, want invalid input", err)
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	tests := []struct {
+		rel      string
+		isDir    bool
+		patterns []string
+		want     bool
+	}{
+		{"a.log", false, []string{"*.log"}, true},
+		{"sub/a.log", false, []string{"*.log"}, true},
+		{"sub/a.txt", false, []string{"sub/*.txt"}, true},
+		{"a.txt", false, []string{"sub/*.txt"}, false},
+		{"cache", false, []string{"cache/"}, false},
+		{"cache", true, []string{"cache/"}, true},
+		{"x", false, nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := excluded(tt.rel, tt.isDir, tt.patterns); got != tt.want {
+			t.Errorf("excluded(%q, %v, %v) = %v, want %v", tt.rel, tt.isDir, tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestRunSyncInteractive(t *testing.T) {
+	src, dst := newTrees(t)
+
+	ask := func(answer string) confirm.Options {
+		return confirm.Options{Interactive: true, Stdin: strings.NewReader(answer), Stderr: io.Discard, AssumeTTY: true}
+	}
+
+	var buf bytes.Buffer
+	if err := RunSync(&buf, []string{src, dst}, Options{Confirm: ask("n\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists(dst) {
+		t.Error("declined sync touched the destination")
+	}
+
+	if err := RunSync(&buf, []string{src, dst}, Options{Confirm: confirm.Options{Interactive: true, Stdin: strings.NewReader("")}}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("interactive without a terminal: err = %v, want invalid input", err)
+	}
+
+	if err := RunSync(&buf, []string{src, dst}, Options{Confirm: ask("y\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFile(t, filepath.Join(dst, "sub", "deep", "c.txt")); got != "charlie" {
+		t.Errorf("confirmed sync: c.txt = %q", got)
+	}
+}
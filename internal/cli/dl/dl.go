@@ -0,0 +1,312 @@
+// Package dl implements a wget/wcurl-style file downloader on top of
+// pkg/download: resumable, retried, mirror-aware and checksum-verified.
+package dl
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/du"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/download"
+	"github.com/inovacc/omni/pkg/hashutil"
+	"golang.org/x/term"
+)
+
+const (
+	// defaultTemplate names the output after the last URL path element.
+	defaultTemplate = "{name}"
+
+	// headerTimeout bounds the wait for response headers. There is no
+	// overall timeout: large downloads legitimately take long.
+	headerTimeout = 30 * time.Second
+
+	// progressInterval throttles progress redraws.
+	progressInterval = 200 * time.Millisecond
+)
+
+// Options configures the dl command behavior
+type Options struct {
+	Output       string        // -o/--output: output path template (default "{name}")
+	MirrorList   string        // -M/--mirror-list: file of mirror URLs, one per line
+	Continue     bool          // -c/--continue: resume from an existing FILE.part
+	Retries      int           // -r/--retries: extra attempts per URL
+	RetryDelay   time.Duration // --retry-delay: first backoff delay
+	Checksum     string        // --checksum: expected digest, hex or "algo:hex"
+	Algorithm    string        // -a/--algorithm: digest algorithm (default sha256)
+	LimitRate    string        // --limit-rate: bytes per second, with K/M/G suffix
+	Headers      []string      // -H/--header: "Name: value"
+	Insecure     bool          // -k/--insecure: skip TLS verification
+	Force        bool          // -f/--force: overwrite an existing output file
+	Quiet        bool          // -q/--quiet: no progress or retry notices
+	OutputFormat output.Format // output format
+}
+
+// Run downloads args[0], falling back to args[1:] and the mirror list.
+func Run(ctx context.Context, w io.Writer, args []string, opts Options) error {
+	urls := args
+
+	if opts.MirrorList != "" {
+		mirrors, err := readMirrorList(opts.MirrorList)
+		if err != nil {
+			return err
+		}
+
+		urls = append(urls, mirrors...)
+	}
+
+	if len(urls) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "dl: URL required")
+	}
+
+	tmpl := opts.Output
+	if tmpl == "" {
+		tmpl = defaultTemplate
+	}
+
+	dest, err := download.ExpandTemplate(tmpl, urls[0], time.Now())
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("dl: %s", err))
+	}
+
+	if !opts.Force {
+		if _, err := os.Stat(dest); err == nil {
+			return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("dl: %s already exists (use --force to overwrite)", dest))
+		}
+	}
+
+	if dir := filepath.Dir(dest); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dl: %s", err))
+		}
+	}
+
+	dopts, err := buildOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	f := output.New(w, opts.OutputFormat)
+
+	var prog *progress
+	if !opts.Quiet && !f.IsJSON() && isTerminal(os.Stderr) {
+		prog = &progress{w: os.Stderr, start: time.Now()}
+		dopts.Progress = prog.update
+	}
+
+	if !opts.Quiet {
+		dopts.Log = func(format string, args ...any) {
+			prog.clear()
+			msg := strings.TrimPrefix(fmt.Sprintf(format, args...), "download: ")
+			_, _ = fmt.Fprintf(os.Stderr, "dl: %s\n", msg)
+		}
+	}
+
+	res, err := download.File(ctx, urls, dest, dopts)
+
+	prog.clear()
+
+	if err != nil {
+		return classify(err)
+	}
+
+	if f.IsJSON() {
+		return f.Print(res)
+	}
+
+	if res.Resumed > 0 {
+		_, _ = fmt.Fprintf(w, "%s: %s (resumed at %s) from %s\n", res.Path, du.FormatHumanSize(res.Size), du.FormatHumanSize(res.Resumed), res.URL)
+	} else {
+		_, _ = fmt.Fprintf(w, "%s: %s from %s\n", res.Path, du.FormatHumanSize(res.Size), res.URL)
+	}
+
+	status := "computed"
+	if res.Verified {
+		status = "verified"
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s\n", res.Digest, status)
+
+	return nil
+}
+
+// buildOptions translates command options into download options.
+func buildOptions(opts Options) (download.Options, error) {
+	dopts := download.Options{
+		Retries:   opts.Retries,
+		Backoff:   opts.RetryDelay,
+		Checksum:  opts.Checksum,
+		Algorithm: hashutil.Algorithm(strings.ToLower(opts.Algorithm)),
+		Resume:    opts.Continue,
+		Header:    http.Header{},
+	}
+
+	if opts.Retries < 0 {
+		return dopts, cmderr.Wrap(cmderr.ErrInvalidInput, "dl: --retries must not be negative")
+	}
+
+	rate, err := parseRate(opts.LimitRate)
+	if err != nil {
+		return dopts, err
+	}
+
+	dopts.RateLimit = rate
+
+	for _, h := range opts.Headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return dopts, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("dl: invalid header %q (want \"Name: value\")", h))
+		}
+
+		dopts.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = headerTimeout
+
+	if opts.Insecure {
+		_, _ = fmt.Fprintln(os.Stderr, "dl: warning: TLS certificate verification disabled (--insecure)")
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in via -k/--insecure
+	}
+
+	dopts.Client = &http.Client{Transport: transport}
+
+	return dopts, nil
+}
+
+// classify maps download errors onto omni's exit-code categories.
+func classify(err error) error {
+	msg := "dl: " + strings.TrimPrefix(err.Error(), "download: ")
+
+	var (
+		statusErr *download.StatusError
+		netErr    net.Error
+		pathErr   *os.PathError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return err
+	case errors.Is(err, download.ErrInvalidInput):
+		return cmderr.Wrap(cmderr.ErrInvalidInput, strings.Replace(msg, "invalid input: ", "", 1))
+	case errors.Is(err, hashutil.ErrMismatch):
+		return cmderr.Wrap(cmderr.ErrConflict, msg+" (download discarded)")
+	case errors.As(err, &statusErr):
+		if statusErr.Code == http.StatusNotFound || statusErr.Code == http.StatusGone {
+			return cmderr.Wrap(cmderr.ErrNotFound, msg)
+		}
+
+		return cmderr.Wrap(cmderr.ErrNetwork, msg)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return cmderr.Wrap(cmderr.ErrTimeout, msg)
+	case errors.As(err, &pathErr):
+		return cmderr.Wrap(cmderr.ErrIO, msg)
+	default:
+		return cmderr.Wrap(cmderr.ErrNetwork, msg)
+	}
+}
+
+// readMirrorList reads mirror URLs, one per line; blank lines and lines
+// starting with '#' are skipped.
+func readMirrorList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("dl: %s", err))
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dl: %s", err))
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var urls []string
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		urls = append(urls, line)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dl: %s: %s", path, err))
+	}
+
+	return urls, nil
+}
+
+// parseRate parses a transfer rate in bytes per second with an optional
+// size suffix (K, M, G are powers of 1024), as in curl --limit-rate.
+func parseRate(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := flagvalue.ParseByteSize(s)
+	if err != nil || n <= 0 {
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("dl: invalid --limit-rate %q", s))
+	}
+
+	return n, nil
+}
+
+// progress draws a single, self-overwriting status line.
+type progress struct {
+	w     io.Writer
+	start time.Time
+	last  time.Time
+	drawn bool
+}
+
+func (p *progress) update(done, total int64) {
+	now := time.Now()
+	if now.Sub(p.last) < progressInterval && done != total {
+		return
+	}
+
+	p.last = now
+
+	var rate string
+	if secs := now.Sub(p.start).Seconds(); secs > 0 {
+		rate = du.FormatHumanSize(int64(float64(done)/secs)) + "/s"
+	}
+
+	line := du.FormatHumanSize(done)
+	if total > 0 {
+		line = fmt.Sprintf("%s / %s  %3d%%", line, du.FormatHumanSize(total), done*100/total)
+	}
+
+	_, _ = fmt.Fprintf(p.w, "\r\033[K%s  %s", line, rate)
+	p.drawn = true
+}
+
+// clear erases the progress line so other output starts on a clean line.
+func (p *progress) clear() {
+	if p == nil || !p.drawn {
+		return
+	}
+
+	_, _ = fmt.Fprint(p.w, "\r\033[K")
+	p.drawn = false
+}
+
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,202 @@
+package dl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/download"
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+const body = "hello, mirror\n"
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.txt" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if got := r.Header.Get("X-Token"); got != "" && got != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		http.ServeContent(w, r, "f", time.Time{}, strings.NewReader(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestRun(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "{name}")
+
+	var buf bytes.Buffer
+
+	opts := Options{
+		Output:   dest,
+		Checksum: hashutil.HashString(body, hashutil.SHA256),
+		Headers:  []string{"X-Token: secret"},
+		Quiet:    true,
+	}
+
+	if err := Run(context.Background(), &buf, []string{srv.URL + "/pkg/tool.txt"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "tool.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != body {
+		t.Errorf("content = %q", data)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "tool.txt") || !strings.Contains(out, "verified") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	srv := newServer(t)
+	dest := filepath.Join(t.TempDir(), "out", "{host}-{name}")
+
+	var buf bytes.Buffer
+
+	opts := Options{Output: dest, Quiet: true, OutputFormat: output.FormatJSON}
+	if err := Run(context.Background(), &buf, []string{srv.URL + "/a.txt"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var res download.Result
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+
+	if filepath.Base(res.Path) != "127.0.0.1-a.txt" || res.Size != int64(len(body)) {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestRunMirrorList(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	list := filepath.Join(dir, "mirrors.txt")
+
+	if err := os.WriteFile(list, []byte("# fallbacks\n\n"+srv.URL+"/good.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	opts := Options{Output: filepath.Join(dir, "file.txt"), MirrorList: list, Quiet: true}
+	if err := Run(context.Background(), &buf, []string{srv.URL + "/missing.txt"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "/good.txt") {
+		t.Errorf("output = %q, want the mirror URL", buf.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+
+	existing := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(existing, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		opts Options
+		want error
+	}{
+		{"no URL", nil, Options{}, cmderr.ErrInvalidInput},
+		{"scheme", []string{"ftp://example.com/f"}, Options{Output: filepath.Join(dir, "f")}, cmderr.ErrInvalidInput},
+		{"not found", []string{srv.URL + "/missing.txt"}, Options{Output: filepath.Join(dir, "m")}, cmderr.ErrNotFound},
+		{"forbidden", []string{srv.URL + "/x"}, Options{Output: filepath.Join(dir, "x"), Headers: []string{"X-Token: bad"}}, cmderr.ErrNetwork},
+		{"mismatch", []string{srv.URL + "/x"}, Options{Output: filepath.Join(dir, "y"), Checksum: strings.Repeat("0", 64)}, cmderr.ErrConflict},
+		{"exists", []string{srv.URL + "/x"}, Options{Output: existing}, cmderr.ErrConflict},
+		{"bad header", []string{srv.URL + "/x"}, Options{Output: filepath.Join(dir, "z"), Headers: []string{"nocolon"}}, cmderr.ErrInvalidInput},
+		{"bad rate", []string{srv.URL + "/x"}, Options{Output: filepath.Join(dir, "z"), LimitRate: "fast"}, cmderr.ErrInvalidInput},
+		{"no mirror list", []string{srv.URL + "/x"}, Options{MirrorList: filepath.Join(dir, "nope")}, cmderr.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Quiet = true
+
+			err := Run(context.Background(), &bytes.Buffer{}, tt.args, tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "y")); !os.IsNotExist(err) {
+		t.Error("mismatched download was kept")
+	}
+}
+
+func TestRunForce(t *testing.T) {
+	srv := newServer(t)
+	dest := filepath.Join(t.TempDir(), "f.txt")
+
+	if err := os.WriteFile(dest, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{Output: dest, Force: true, Quiet: true}
+	if err := Run(context.Background(), &bytes.Buffer{}, []string{srv.URL + "/f.txt"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(dest); string(data) != body {
+		t.Errorf("content = %q", data)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"", 0, true},
+		{"500", 500, true},
+		{"100K", 100 << 10, true},
+		{"1.5m", 3 << 19, true},
+		{"2G", 2 << 30, true},
+		{"0", 0, false},
+		{"-1K", 0, false},
+		{"fast", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRate(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseRate(%q) = %d, %v", tt.in, got, err)
+		}
+	}
+}
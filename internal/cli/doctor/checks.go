@@ -0,0 +1,375 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/inovacc/omni/internal/config"
+	"golang.org/x/term"
+)
+
+// maxCacheSize is the cache size above which doctor suggests a cleanup.
+const maxCacheSize = 1 << 30
+
+// shadowCandidates are system utilities that omni reimplements. A PATH entry
+// resolving one of them to omni changes the behavior of every script that
+// calls it.
+var shadowCandidates = []string{
+	"awk", "base64", "basename", "cat", "chmod", "chown", "cp", "cut", "date",
+	"df", "diff", "dirname", "du", "echo", "env", "find", "grep", "gzip", "head",
+	"kill", "ln", "ls", "mkdir", "mv", "nl", "ps", "pwd", "readlink", "realpath",
+	"rm", "sed", "seq", "sort", "stat", "tac", "tail", "tar", "touch", "tr",
+	"uname", "uniq", "wc", "which", "xargs",
+}
+
+// configDir returns the directory holding omni's feature flag files.
+func configDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "omni"), nil
+}
+
+func checkFeatureFlags(context.Context) Result {
+	dir, err := configDir()
+	if err != nil {
+		return Result{Status: StatusFail, Message: err.Error(), Fix: "set XDG_CACHE_HOME or HOME"}
+	}
+
+	return inspectFeatureDir(dir)
+}
+
+// inspectFeatureDir validates the OMNI_<FEATURE>_ENABLED/_DISABLED files in dir.
+func inspectFeatureDir(dir string) Result {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return Result{Status: StatusOK, Message: "no feature flags configured"}
+	}
+
+	if err != nil {
+		return Result{Status: StatusFail, Message: err.Error(), Fix: "check the permissions of " + dir}
+	}
+
+	state := make(map[string][]string)
+
+	var unknown []string
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "OMNI_") {
+			continue
+		}
+
+		feature, suffix := splitFlagName(name)
+		if suffix == "" {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		state[feature] = append(state[feature], suffix)
+	}
+
+	var conflicts []string
+
+	for feature, suffixes := range state {
+		if len(suffixes) > 1 {
+			conflicts = append(conflicts, feature)
+		}
+	}
+
+	sort.Strings(conflicts)
+
+	switch {
+	case len(conflicts) > 0:
+		return Result{
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("features both enabled and disabled: %s (enabled wins)", strings.Join(conflicts, ", ")),
+			Fix:     fmt.Sprintf("remove the stale OMNI_<FEATURE>_DISABLED file in %s", dir),
+		}
+	case len(unknown) > 0:
+		return Result{
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("unrecognized flag files are ignored: %s", strings.Join(unknown, ", ")),
+			Fix:     "flag files must end in _ENABLED or _DISABLED; remove them from " + dir,
+		}
+	default:
+		return Result{Status: StatusOK, Message: fmt.Sprintf("%d feature flag(s) in %s", len(state), dir)}
+	}
+}
+
+func splitFlagName(name string) (feature, suffix string) {
+	for _, s := range []string{"_ENABLED", "_DISABLED"} {
+		if f, ok := strings.CutSuffix(name, s); ok && f != "OMNI" {
+			return strings.TrimPrefix(f, "OMNI_"), s
+		}
+	}
+
+	return "", ""
+}
+
+func checkConfigFile(context.Context) Result {
+	path, err := config.Path()
+	if err != nil {
+		return Result{Status: StatusSkip, Message: err.Error()}
+	}
+
+	return inspectConfigFile(path)
+}
+
+// inspectConfigFile checks that the omni config file, if present, parses.
+func inspectConfigFile(path string) Result {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return Result{Status: StatusOK, Message: "no config file at " + path}
+	}
+
+	if _, err := config.LoadFile(path); err != nil {
+		return Result{Status: StatusFail, Message: err.Error(), Fix: "fix the YAML syntax in " + path + " or remove the file"}
+	}
+
+	return Result{Status: StatusOK, Message: "config file " + path + " is valid"}
+}
+
+func checkLogger(context.Context) Result {
+	dir, err := configDir()
+	if err != nil {
+		return Result{Status: StatusSkip, Message: err.Error()}
+	}
+
+	return inspectLogger(dir)
+}
+
+// inspectLogger checks that an enabled logger points at a writable directory.
+func inspectLogger(dir string) Result {
+	data, err := os.ReadFile(filepath.Join(dir, "OMNI_LOGGER_ENABLED"))
+	if err != nil {
+		return Result{Status: StatusOK, Message: "command logging disabled"}
+	}
+
+	logDir := strings.TrimSpace(string(data))
+	if logDir == "" {
+		return Result{
+			Status:  StatusFail,
+			Message: "logging enabled without a log path",
+			Fix:     `run: eval "$(omni logger --path DIR)" or omni logger --disable`,
+		}
+	}
+
+	if err := probeWritable(logDir); err != nil {
+		return Result{
+			Status:  StatusFail,
+			Message: fmt.Sprintf("log directory %s is not writable: %s", logDir, err),
+			Fix:     "fix its permissions or choose another: omni logger --path DIR",
+		}
+	}
+
+	return Result{Status: StatusOK, Message: "logging to " + logDir}
+}
+
+func checkPathShadowing(context.Context) Result {
+	self, err := os.Executable()
+	if err != nil {
+		return Result{Status: StatusSkip, Message: "cannot locate the omni executable: " + err.Error()}
+	}
+
+	return inspectPath(os.Getenv("PATH"), self, shadowCandidates)
+}
+
+// inspectPath reports utilities that resolve to the omni binary self via
+// pathEnv, and omni binaries on PATH other than self.
+func inspectPath(pathEnv, self string, names []string) Result {
+	self = resolvePath(self)
+
+	var shadowed []string
+
+	for _, name := range names {
+		if p := lookPath(pathEnv, name); p != "" && resolvePath(p) == self {
+			shadowed = append(shadowed, name)
+		}
+	}
+
+	if len(shadowed) > 0 {
+		return Result{
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("%s resolve to omni, shadowing the system utilities", strings.Join(shadowed, ", ")),
+			Fix:     "remove the aliasing links from PATH, or call them as 'omni <cmd>' in scripts",
+		}
+	}
+
+	if p := lookPath(pathEnv, "omni"); p != "" && resolvePath(p) != self {
+		return Result{
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("'omni' on PATH is %s, not this binary (%s)", p, self),
+			Fix:     "remove the stale binary or reorder PATH",
+		}
+	}
+
+	return Result{Status: StatusOK, Message: "no system utilities shadowed"}
+}
+
+// lookPath is exec.LookPath over an explicit PATH value.
+func lookPath(pathEnv, name string) string {
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+
+		p := filepath.Join(dir, name)
+
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		if runtime.GOOS == "windows" || info.Mode()&0o111 != 0 {
+			return p
+		}
+	}
+
+	return ""
+}
+
+func resolvePath(p string) string {
+	if r, err := filepath.EvalSymlinks(p); err == nil {
+		p = r
+	}
+
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+
+	return p
+}
+
+func checkColor(context.Context) Result {
+	return colorSupport(os.Getenv, term.IsTerminal(int(os.Stdout.Fd())))
+}
+
+// colorSupport describes the color capability of the terminal.
+func colorSupport(getenv func(string) string, isTerminal bool) Result {
+	switch {
+	case getenv("NO_COLOR") != "":
+		return Result{Status: StatusOK, Message: "colors disabled by NO_COLOR"}
+	case !isTerminal:
+		return Result{Status: StatusSkip, Message: "stdout is not a terminal; colors are off unless forced"}
+	case getenv("TERM") == "dumb":
+		return Result{Status: StatusWarn, Message: "TERM=dumb disables colors", Fix: "set TERM=xterm-256color"}
+	}
+
+	colorterm := strings.ToLower(getenv("COLORTERM"))
+	termName := getenv("TERM")
+
+	switch {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return Result{Status: StatusOK, Message: "24-bit color"}
+	case strings.Contains(termName, "256color"):
+		return Result{Status: StatusOK, Message: "256 colors"}
+	case termName == "" && runtime.GOOS != "windows":
+		return Result{Status: StatusWarn, Message: "TERM is not set", Fix: "set TERM=xterm-256color"}
+	default:
+		return Result{Status: StatusOK, Message: "16 colors"}
+	}
+}
+
+// checkCacheDir reports the size of a cache directory and whether it is writable.
+func checkCacheDir(dir string, limit int64) Result {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return Result{Status: StatusOK, Message: dir + " (not created yet)"}
+	}
+
+	if err != nil {
+		return Result{Status: StatusFail, Message: err.Error(), Fix: "check the permissions of " + dir}
+	}
+
+	if !info.IsDir() {
+		return Result{Status: StatusFail, Message: dir + " is not a directory", Fix: "remove it: omni rm " + dir}
+	}
+
+	var size int64
+
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		if fi, err := d.Info(); err == nil {
+			size += fi.Size()
+		}
+
+		return nil
+	})
+
+	if err := probeWritable(dir); err != nil {
+		return Result{
+			Status:  StatusFail,
+			Message: fmt.Sprintf("%s is not writable: %s", dir, err),
+			Fix:     "fix its permissions or remove it: omni rm -rf " + dir,
+		}
+	}
+
+	msg := fmt.Sprintf("%s in %s", formatSize(size), dir)
+	if size > limit {
+		return Result{Status: StatusWarn, Message: msg, Fix: "clear it: omni rm -rf " + dir}
+	}
+
+	return Result{Status: StatusOK, Message: msg}
+}
+
+// probeWritable creates and removes a temporary file in dir.
+func probeWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".omni-doctor-*")
+	if err != nil {
+		return err
+	}
+
+	_ = f.Close()
+
+	return os.Remove(f.Name())
+}
+
+func formatSize(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// checkReachable dials addr over TCP.
+func checkReachable(ctx context.Context, addr string) Result {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return Result{
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("%s unreachable: %s", addr, err),
+			Fix:     "check network, proxy and firewall settings, or pass --offline",
+		}
+	}
+
+	_ = conn.Close()
+
+	return Result{Status: StatusOK, Message: addr + " reachable"}
+}
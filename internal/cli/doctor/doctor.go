@@ -0,0 +1,133 @@
+// Package doctor implements `omni doctor`, which diagnoses the environment
+// omni runs in: feature flag configuration, cache directories, PATH
+// shadowing of system utilities, terminal color support and network
+// reachability. Checks live in a Registry; other modules contribute their
+// own with Register, RegisterCacheDir and RegisterEndpoint.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Options configures the doctor command behavior
+type Options struct {
+	Offline      bool          // --offline: skip network checks
+	Strict       bool          // --strict: exit non-zero on warnings too
+	Categories   []string      // --category: only run checks in these categories
+	Timeout      time.Duration // --timeout: per-check timeout
+	Registry     *Registry     // checks to run (default: Default())
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// Report is the doctor output for JSON
+type Report struct {
+	Results []Result `json:"results"`
+	OK      int      `json:"ok"`
+	Warn    int      `json:"warn"`
+	Fail    int      `json:"fail"`
+	Skip    int      `json:"skip"`
+}
+
+// RunDoctor runs the registered checks concurrently and prints their results
+// in registration order. It exits with status 1 when a check fails (or warns,
+// with Strict).
+func RunDoctor(ctx context.Context, w io.Writer, opts Options) error {
+	reg := opts.Registry
+	if reg == nil {
+		reg = Default()
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	var checks []Check
+
+	for _, c := range reg.Checks() {
+		if len(opts.Categories) > 0 && !slices.Contains(opts.Categories, c.Category) {
+			continue
+		}
+
+		checks = append(checks, c)
+	}
+
+	if len(checks) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("doctor: no checks in categories %v", opts.Categories))
+	}
+
+	report := Report{Results: make([]Result, len(checks))}
+
+	var wg sync.WaitGroup
+
+	for i, c := range checks {
+		if c.Network && opts.Offline {
+			report.Results[i] = Result{Check: c.Name, Category: c.Category, Status: StatusSkip, Message: "skipped (--offline)"}
+			continue
+		}
+
+		wg.Go(func() {
+			cctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+
+			r := c.Run(cctx)
+			r.Check, r.Category = c.Name, c.Category
+			report.Results[i] = r
+		})
+	}
+
+	wg.Wait()
+
+	for _, r := range report.Results {
+		switch r.Status {
+		case StatusOK:
+			report.OK++
+		case StatusWarn:
+			report.Warn++
+		case StatusFail:
+			report.Fail++
+		default:
+			report.Skip++
+		}
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		if err := f.Print(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(w, report)
+	}
+
+	if report.Fail > 0 || (opts.Strict && report.Warn > 0) {
+		return cmderr.SilentExit(1)
+	}
+
+	return nil
+}
+
+func printReport(w io.Writer, report Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STATUS\tCHECK\tMESSAGE")
+
+	for _, r := range report.Results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Status, r.Check, r.Message)
+
+		if r.Fix != "" && (r.Status == StatusWarn || r.Status == StatusFail) {
+			_, _ = fmt.Fprintf(tw, "\t\tfix: %s\n", r.Fix)
+		}
+	}
+
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(w, "\n%d ok, %d warning(s), %d failure(s), %d skipped\n", report.OK, report.Warn, report.Fail, report.Skip)
+}
@@ -0,0 +1,258 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func staticCheck(name, category string, status Status) Check {
+	return Check{Name: name, Category: category, Run: func(context.Context) Result {
+		return Result{Status: status, Message: name + " ran", Fix: "do something"}
+	}}
+}
+
+func TestRunDoctor(t *testing.T) {
+	reg := &Registry{}
+	reg.Register(staticCheck("a", "config", StatusOK))
+	reg.Register(staticCheck("b", "cache", StatusWarn))
+	reg.Register(Check{Name: "c", Category: "network", Network: true, Run: func(context.Context) Result {
+		t.Error("network check ran with Offline")
+		return Result{}
+	}})
+
+	var buf bytes.Buffer
+	if err := RunDoctor(context.Background(), &buf, Options{Registry: reg, Offline: true}); err != nil {
+		t.Fatalf("RunDoctor() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"ok      a", "warn    b", "fix: do something", "skip    c", "1 ok, 1 warning(s), 0 failure(s), 1 skipped"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	err := RunDoctor(context.Background(), &bytes.Buffer{}, Options{Registry: reg, Offline: true, Strict: true})
+	var silent *cmderr.SilentError
+	if !errors.As(err, &silent) {
+		t.Errorf("Strict with warnings: err = %v, want silent exit", err)
+	}
+}
+
+func TestRunDoctorJSONAndCategories(t *testing.T) {
+	reg := &Registry{}
+	reg.Register(staticCheck("a", "config", StatusOK))
+	reg.Register(staticCheck("b", "cache", StatusFail))
+
+	var buf bytes.Buffer
+	err := RunDoctor(context.Background(), &buf, Options{Registry: reg, OutputFormat: output.FormatJSON})
+
+	var silent *cmderr.SilentError
+	if !errors.As(err, &silent) {
+		t.Errorf("failing check: err = %v, want silent exit", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if len(report.Results) != 2 || report.Fail != 1 || report.Results[1].Check != "b" || report.Results[1].Category != "cache" {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	if err := RunDoctor(context.Background(), &bytes.Buffer{}, Options{Registry: reg, Categories: []string{"config"}}); err != nil {
+		t.Errorf("config only: err = %v", err)
+	}
+
+	if err := RunDoctor(context.Background(), &bytes.Buffer{}, Options{Registry: reg, Categories: []string{"nope"}}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("unknown category: err = %v, want invalid input", err)
+	}
+}
+
+func TestInspectFeatureDir(t *testing.T) {
+	dir := t.TempDir()
+
+	if r := inspectFeatureDir(filepath.Join(dir, "missing")); r.Status != StatusOK {
+		t.Errorf("missing dir: %+v", r)
+	}
+
+	touch(t, filepath.Join(dir, "OMNI_LOGGER_ENABLED"))
+
+	if r := inspectFeatureDir(dir); r.Status != StatusOK || !strings.HasPrefix(r.Message, "1 feature") {
+		t.Errorf("one flag: %+v", r)
+	}
+
+	touch(t, filepath.Join(dir, "OMNI_LOGGER_DISABLED"))
+
+	if r := inspectFeatureDir(dir); r.Status != StatusWarn || !strings.Contains(r.Message, "LOGGER") {
+		t.Errorf("conflict: %+v", r)
+	}
+}
+
+func TestInspectConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if r := inspectConfigFile(path); r.Status != StatusOK {
+		t.Errorf("missing file: %+v", r)
+	}
+
+	if err := os.WriteFile(path, []byte("rg:\n  type-add: [web:*.html]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := inspectConfigFile(path); r.Status != StatusOK {
+		t.Errorf("valid file: %+v", r)
+	}
+
+	if err := os.WriteFile(path, []byte("rg: [unclosed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := inspectConfigFile(path); r.Status != StatusFail || r.Fix == "" {
+		t.Errorf("invalid file: %+v", r)
+	}
+}
+
+func TestInspectLogger(t *testing.T) {
+	dir := t.TempDir()
+
+	if r := inspectLogger(dir); r.Status != StatusOK {
+		t.Errorf("disabled: %+v", r)
+	}
+
+	flag := filepath.Join(dir, "OMNI_LOGGER_ENABLED")
+	touch(t, flag)
+
+	if r := inspectLogger(dir); r.Status != StatusFail || r.Fix == "" {
+		t.Errorf("empty path: %+v", r)
+	}
+
+	if err := os.WriteFile(flag, []byte(dir+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := inspectLogger(dir); r.Status != StatusOK {
+		t.Errorf("writable path: %+v", r)
+	}
+}
+
+func TestInspectPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses symlinks")
+	}
+
+	dir := t.TempDir()
+	self := filepath.Join(dir, "omni")
+	bin := filepath.Join(dir, "bin")
+
+	if err := os.WriteFile(self, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Mkdir(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := inspectPath(bin+string(os.PathListSeparator)+dir, self, []string{"ls", "cat"}); r.Status != StatusOK {
+		t.Errorf("clean PATH: %+v", r)
+	}
+
+	if err := os.Symlink(self, filepath.Join(bin, "ls")); err != nil {
+		t.Fatal(err)
+	}
+
+	r := inspectPath(bin, self, []string{"ls", "cat"})
+	if r.Status != StatusWarn || !strings.HasPrefix(r.Message, "ls resolve") {
+		t.Errorf("shadowed ls: %+v", r)
+	}
+
+	other := filepath.Join(bin, "omni")
+	if err := os.WriteFile(other, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := inspectPath(bin, self, nil); r.Status != StatusWarn || !strings.Contains(r.Message, other) {
+		t.Errorf("stale omni: %+v", r)
+	}
+}
+
+func TestColorSupport(t *testing.T) {
+	tests := []struct {
+		env    map[string]string
+		isTerm bool
+		want   Status
+		msg    string
+	}{
+		{map[string]string{"NO_COLOR": "1"}, true, StatusOK, "NO_COLOR"},
+		{nil, false, StatusSkip, "not a terminal"},
+		{map[string]string{"TERM": "dumb"}, true, StatusWarn, "dumb"},
+		{map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, true, StatusOK, "24-bit"},
+		{map[string]string{"TERM": "xterm-256color"}, true, StatusOK, "256"},
+	}
+
+	for _, tt := range tests {
+		r := colorSupport(func(k string) string { return tt.env[k] }, tt.isTerm)
+		if r.Status != tt.want || !strings.Contains(r.Message, tt.msg) {
+			t.Errorf("colorSupport(%v, %v) = %+v", tt.env, tt.isTerm, r)
+		}
+	}
+}
+
+func TestCheckCacheDir(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "blob"), make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := checkCacheDir(dir, 1<<20); r.Status != StatusOK || !strings.HasPrefix(r.Message, "2.0 KiB") {
+		t.Errorf("small cache: %+v", r)
+	}
+
+	if r := checkCacheDir(dir, 1024); r.Status != StatusWarn || !strings.Contains(r.Fix, dir) {
+		t.Errorf("large cache: %+v", r)
+	}
+
+	if r := checkCacheDir(filepath.Join(dir, "none"), 1024); r.Status != StatusOK {
+		t.Errorf("missing cache: %+v", r)
+	}
+}
+
+func TestCheckReachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+
+	addr := ln.Addr().String()
+
+	if r := checkReachable(context.Background(), addr); r.Status != StatusOK {
+		t.Errorf("listening: %+v", r)
+	}
+
+	_ = ln.Close()
+
+	if r := checkReachable(context.Background(), addr); r.Status != StatusWarn {
+		t.Errorf("closed: %+v", r)
+	}
+}
+
+func touch(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,112 @@
+package doctor
+
+import (
+	"context"
+	"sync"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Result is the outcome of one check. Fix is an actionable suggestion shown
+// for warnings and failures.
+type Result struct {
+	Check    string `json:"check"`
+	Category string `json:"category"`
+	Status   Status `json:"status"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// Check is a single diagnostic. Run only needs to fill Status, Message and
+// Fix; the registry sets Check and Category.
+type Check struct {
+	Name     string
+	Category string
+	Network  bool // skipped with --offline
+	Run      func(ctx context.Context) Result
+}
+
+// Registry holds the checks run by doctor, in registration order.
+type Registry struct {
+	mu     sync.Mutex
+	checks []Check
+}
+
+// NewRegistry returns a registry with the built-in checks.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.Register(Check{Name: "config", Category: "config", Run: checkFeatureFlags})
+	r.Register(Check{Name: "config-file", Category: "config", Run: checkConfigFile})
+	r.Register(Check{Name: "logger", Category: "config", Run: checkLogger})
+	r.Register(Check{Name: "path-shadowing", Category: "path", Run: checkPathShadowing})
+	r.Register(Check{Name: "color", Category: "terminal", Run: checkColor})
+
+	return r
+}
+
+// Register adds c to the registry.
+func (r *Registry) Register(c Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, c)
+}
+
+// Checks returns a copy of the registered checks.
+func (r *Registry) Checks() []Check {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Check(nil), r.checks...)
+}
+
+// defaultRegistry is the registry used by `omni doctor`.
+var defaultRegistry = NewRegistry()
+
+// Default returns the registry used by `omni doctor`.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds c to the default registry.
+func Register(c Check) {
+	defaultRegistry.Register(c)
+}
+
+// RegisterCacheDir adds a health check for a cache directory owned by
+// another module. dir is resolved when the check runs.
+func RegisterCacheDir(name string, dir func() (string, error)) {
+	Register(Check{
+		Name:     "cache:" + name,
+		Category: "cache",
+		Run: func(context.Context) Result {
+			d, err := dir()
+			if err != nil {
+				return Result{Status: StatusWarn, Message: err.Error(), Fix: "set XDG_CACHE_HOME or HOME"}
+			}
+
+			return checkCacheDir(d, maxCacheSize)
+		},
+	})
+}
+
+// RegisterEndpoint adds a reachability check for a network service used by
+// another module. addr is a host:port to dial.
+func RegisterEndpoint(name, addr string) {
+	Register(Check{
+		Name:     "net:" + name,
+		Category: "network",
+		Network:  true,
+		Run: func(ctx context.Context) Result {
+			return checkReachable(ctx, addr)
+		},
+	})
+}
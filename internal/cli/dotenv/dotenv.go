@@ -1,7 +1,6 @@
 package dotenv
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +9,7 @@ import (
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	pkgdotenv "github.com/inovacc/omni/pkg/dotenv"
 )
 
 // ShellType represents a shell type for export format
@@ -194,122 +194,27 @@ func ParseDotenvFile(path string, opts DotenvOptions) ([]EnvVar, error) {
 
 // ParseDotenv parses .env content from a reader
 func ParseDotenv(r io.Reader, opts DotenvOptions) ([]EnvVar, error) {
-	var vars []EnvVar
+	parsed, err := pkgdotenv.Parse(r, pkgdotenv.Options{Expand: opts.Expand})
 
-	scanner := bufio.NewScanner(r)
-	lineNum := 0
-
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-
-		// Skip empty lines and comments
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Parse key=value
-		key, value, err := parseDotenvLine(line)
-		if err != nil {
-			continue
-		}
-
-		// Expand variables if requested
-		if opts.Expand {
-			value = expandEnvVars(value, vars)
-		}
-
-		vars = append(vars, EnvVar{Key: key, Value: value})
+	vars := make([]EnvVar, len(parsed))
+	for i, v := range parsed {
+		vars[i] = EnvVar{Key: v.Key, Value: v.Value}
 	}
 
-	return vars, scanner.Err()
+	return vars, err
 }
 
 func parseDotenvLine(line string) (string, string, error) {
-	// Handle export prefix
-	if after, ok := strings.CutPrefix(line, "export "); ok {
-		line = after
-		line = strings.TrimSpace(line)
-	}
-
-	// Find the = separator
-	before, after, ok := strings.Cut(line, "=")
-	if !ok {
-		return "", "", cmderr.Wrap(cmderr.ErrInvalidInput, "dotenv: invalid line: no '=' found")
-	}
-
-	key := strings.TrimSpace(before)
-	value := after
-
-	// Validate key
-	if key == "" {
-		return "", "", cmderr.Wrap(cmderr.ErrInvalidInput, "dotenv: empty key")
+	key, value, err := pkgdotenv.ParseLine(line)
+	if err != nil {
+		return "", "", cmderr.Wrap(cmderr.ErrInvalidInput, err.Error())
 	}
 
-	// Parse value (handle quotes)
-	value = parseValue(value)
-
 	return key, value, nil
 }
 
 func parseValue(value string) string {
-	value = strings.TrimSpace(value)
-
-	// Handle quoted values
-	if len(value) >= 2 {
-		if (value[0] == '"' && value[len(value)-1] == '"') ||
-			(value[0] == '\'' && value[len(value)-1] == '\'') {
-			quote := value[0]
-			value = value[1 : len(value)-1]
-
-			// Handle escape sequences in double quotes
-			if quote == '"' {
-				value = unescapeDoubleQuoted(value)
-			}
-
-			return value
-		}
-	}
-
-	// Unquoted value - remove inline comments
-	if idx := strings.Index(value, " #"); idx != -1 {
-		value = strings.TrimSpace(value[:idx])
-	}
-
-	return value
-}
-
-func unescapeDoubleQuoted(s string) string {
-	replacer := strings.NewReplacer(
-		`\\`, `\`,
-		`\"`, `"`,
-		`\n`, "\n",
-		`\r`, "\r",
-		`\t`, "\t",
-	)
-
-	return replacer.Replace(s)
-}
-
-func expandEnvVars(value string, vars []EnvVar) string {
-	// Build a map of current variables
-	varMap := make(map[string]string)
-	for _, v := range vars {
-		varMap[v.Key] = v.Value
-	}
-
-	// Expand ${VAR} and $VAR patterns
-	result := os.Expand(value, func(key string) string {
-		// First check our parsed vars
-		if v, ok := varMap[key]; ok {
-			return v
-		}
-		// Then check environment
-		return os.Getenv(key)
-	})
-
-	return result
+	return pkgdotenv.ParseValue(value)
 }
 
 // LoadDotenv loads .env file(s) into the current process environment
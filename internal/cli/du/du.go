@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
@@ -25,6 +27,7 @@ type DUOptions struct {
 	OneFileSystem  bool          // -x: skip directories on different file systems
 	ApparentSize   bool          // --apparent-size: print apparent sizes rather than disk usage
 	NullTerminator bool          // -0: end each output line with NUL, not newline
+	Threshold      int64         // -t: exclude entries smaller than SIZE, or larger if negative
 	OutputFormat   output.Format // output format (text/json/table)
 }
 
@@ -119,11 +122,7 @@ func duPath(w io.Writer, path string, opts DUOptions, _ int, terminator string,
 		size := info.Size()
 
 		if opts.All || opts.SummarizeOnly {
-			if jsonMode {
-				results = append(results, DUResult{Path: path, Size: size})
-			} else {
-				printDUSize(w, size, path, opts, terminator)
-			}
+			results = record(w, results, size, path, opts, terminator, jsonMode)
 		}
 
 		return size, results, nil
@@ -162,11 +161,7 @@ func duPath(w io.Writer, path string, opts DUOptions, _ int, terminator string,
 
 			relDepth := len(filepath.SplitList(rel))
 			if opts.MaxDepth == 0 || relDepth <= opts.MaxDepth {
-				if jsonMode {
-					results = append(results, DUResult{Path: p, Size: size})
-				} else {
-					printDUSize(w, size, p, opts, terminator)
-				}
+				results = record(w, results, size, p, opts, terminator, jsonMode)
 			}
 		}
 
@@ -192,23 +187,81 @@ func duPath(w io.Writer, path string, opts DUOptions, _ int, terminator string,
 
 			relDepth := len(filepath.SplitList(rel))
 			if opts.MaxDepth == 0 || relDepth <= opts.MaxDepth {
-				if jsonMode {
-					results = append(results, DUResult{Path: dir, Size: dirSize})
-				} else {
-					printDUSize(w, dirSize, dir, opts, terminator)
-				}
+				results = record(w, results, dirSize, dir, opts, terminator, jsonMode)
 			}
 		}
 	}
 
 	// Always print/record the root path
+	results = record(w, results, totalSize, path, opts, terminator, jsonMode)
+
+	return totalSize, results, nil
+}
+
+// record prints one entry, or collects it in JSON mode, unless --threshold
+// filters it out.
+func record(w io.Writer, results []DUResult, size int64, path string, opts DUOptions, terminator string, jsonMode bool) []DUResult {
+	if !withinThreshold(size, opts.Threshold) {
+		return results
+	}
+
 	if jsonMode {
-		results = append(results, DUResult{Path: path, Size: totalSize})
-	} else {
-		printDUSize(w, totalSize, path, opts, terminator)
+		return append(results, DUResult{Path: path, Size: size})
 	}
 
-	return totalSize, results, nil
+	printDUSize(w, size, path, opts, terminator)
+
+	return results
+}
+
+// withinThreshold applies GNU du -t semantics: a positive threshold hides
+// entries smaller than it, a negative one hides entries larger than its
+// absolute value.
+func withinThreshold(size, threshold int64) bool {
+	switch {
+	case threshold > 0:
+		return size >= threshold
+	case threshold < 0:
+		return size <= -threshold
+	default:
+		return true
+	}
+}
+
+// ParseThreshold parses a --threshold SIZE such as "100M", "-1G" or "4096".
+// Suffixes K, M, G, T and P are powers of 1024; a trailing "B" or "iB" is
+// accepted.
+func ParseThreshold(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	str := strings.TrimSpace(s)
+
+	sign := int64(1)
+	if strings.HasPrefix(str, "-") {
+		sign = -1
+		str = str[1:]
+	}
+
+	str = strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(str), "B"), "I")
+
+	mult := int64(1)
+	if n := len(str); n > 0 {
+		if i := strings.IndexByte("KMGTP", str[n-1]); i >= 0 {
+			str = str[:n-1]
+			for range i + 1 {
+				mult *= 1024
+			}
+		}
+	}
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || n < 0 {
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("du: invalid --threshold argument %q", s))
+	}
+
+	return sign * n * mult, nil
 }
 
 func calculateDirSize(path string) int64 {
@@ -236,3 +236,60 @@ func TestCalculateDirSize(t *testing.T) {
 		t.Errorf("calculateDirSize() = %d, want >= 10", size)
 	}
 }
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"4096", 4096, false},
+		{"1K", 1024, false},
+		{"100M", 100 << 20, false},
+		{"2GiB", 2 << 30, false},
+		{"-1G", -(1 << 30), false},
+		{"1X", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseThreshold(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseThreshold(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseThreshold(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRunDUThreshold(t *testing.T) {
+	dir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(dir, "small.txt"), []byte("x"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "big.txt"), bytes.Repeat([]byte("x"), 4096), 0644)
+
+	var buf bytes.Buffer
+	if err := RunDU(&buf, []string{dir}, DUOptions{All: true, ByteCount: true, Threshold: 1024}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "small.txt") || !strings.Contains(out, "big.txt") {
+		t.Errorf("positive threshold should hide small entries:\n%s", out)
+	}
+
+	buf.Reset()
+
+	if err := RunDU(&buf, []string{dir}, DUOptions{All: true, ByteCount: true, Threshold: -1024}); err != nil {
+		t.Fatal(err)
+	}
+
+	out = buf.String()
+	if !strings.Contains(out, "small.txt") || strings.Contains(out, "big.txt") {
+		t.Errorf("negative threshold should hide large entries:\n%s", out)
+	}
+}
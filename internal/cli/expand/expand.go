@@ -0,0 +1,316 @@
+// Package expand converts tabs to spaces (expand) and spaces to tabs
+// (unexpand) using GNU-style tab stop lists.
+package expand
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/input"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// TabStops is a set of tab stop columns (0-based). Explicit stops come
+// first; past the last one, stops repeat every Repeat columns, either as
+// multiples of Repeat ("/N") or counted from the last explicit stop ("+N").
+type TabStops struct {
+	Stops    []int // explicit stops, ascending
+	Repeat   int   // 0 means no stops past the last explicit one
+	FromLast bool  // count repeats from the last explicit stop ("+N")
+}
+
+// ParseTabStops parses a -t list: "N" (every N columns), "N1,N2,..."
+// (explicit stops, separated by commas or blanks), optionally ending in
+// "/N" (then every multiple of N) or "+N" (then every N after the last
+// stop). An empty spec means every 8 columns.
+func ParseTabStops(spec string) (TabStops, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return TabStops{Repeat: 8}, nil
+	}
+
+	items := strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' })
+
+	var ts TabStops
+
+	for i, item := range items {
+		prefix := item[0]
+		if prefix == '/' || prefix == '+' {
+			if i != len(items)-1 {
+				return TabStops{}, fmt.Errorf("%q specifier only allowed with the last value", string(prefix))
+			}
+
+			item = item[1:]
+		}
+
+		n, err := strconv.Atoi(item)
+		if err != nil || n <= 0 {
+			return TabStops{}, fmt.Errorf("tab size contains invalid character(s): %q", items[i])
+		}
+
+		switch {
+		case prefix == '/' || prefix == '+':
+			ts.Repeat, ts.FromLast = n, prefix == '+'
+		case len(items) == 1:
+			ts.Repeat = n
+		default:
+			if len(ts.Stops) > 0 && n <= ts.Stops[len(ts.Stops)-1] {
+				return TabStops{}, errors.New("tab sizes must be ascending")
+			}
+
+			ts.Stops = append(ts.Stops, n)
+		}
+	}
+
+	return ts, nil
+}
+
+// Next returns the first tab stop after column col. ok is false when col
+// is past the last explicit stop and no repeat was given.
+func (t TabStops) Next(col int) (next int, ok bool) {
+	for _, s := range t.Stops {
+		if s > col {
+			return s, true
+		}
+	}
+
+	if t.Repeat <= 0 {
+		return 0, false
+	}
+
+	if t.FromLast && len(t.Stops) > 0 {
+		last := t.Stops[len(t.Stops)-1]
+		return last + ((col-last)/t.Repeat+1)*t.Repeat, true
+	}
+
+	return (col/t.Repeat + 1) * t.Repeat, true
+}
+
+// IsStop reports whether col is a tab stop.
+func (t TabStops) IsStop(col int) bool {
+	if col <= 0 {
+		return false
+	}
+
+	next, ok := t.Next(col - 1)
+
+	return ok && next == col
+}
+
+// Options configures the expand and unexpand commands
+type Options struct {
+	Tabs         string        // -t: tab stops (N, list, /N or +N suffix)
+	Initial      bool          // expand -i: only convert leading tabs
+	All          bool          // unexpand -a: convert all blanks, not just leading ones
+	FirstOnly    bool          // unexpand --first-only: convert only leading blanks (overrides -a)
+	OutputFormat output.Format // output format (text/json)
+}
+
+// Result represents expand/unexpand output for JSON mode
+type Result struct {
+	Lines []string `json:"lines"`
+	Count int      `json:"count"`
+}
+
+// RunExpand converts tabs in each FILE (or r) to spaces.
+func RunExpand(w io.Writer, r io.Reader, args []string, opts Options) error {
+	ts, err := ParseTabStops(opts.Tabs)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("expand: %s", err))
+	}
+
+	return run(w, r, args, opts, "expand", func(line string) string {
+		return ExpandLine(line, ts, opts.Initial)
+	})
+}
+
+// RunUnexpand converts blanks in each FILE (or r) to tabs. As in GNU
+// unexpand, giving -t implies -a unless --first-only is set.
+func RunUnexpand(w io.Writer, r io.Reader, args []string, opts Options) error {
+	ts, err := ParseTabStops(opts.Tabs)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unexpand: %s", err))
+	}
+
+	all := (opts.All || opts.Tabs != "") && !opts.FirstOnly
+
+	return run(w, r, args, opts, "unexpand", func(line string) string {
+		return UnexpandLine(line, ts, all)
+	})
+}
+
+func run(w io.Writer, r io.Reader, args []string, opts Options, name string, convert func(string) string) error {
+	sources, err := input.Open(args, r)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("%s: %s", name, err))
+		}
+
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer input.CloseAll(sources)
+
+	f := output.New(w, opts.OutputFormat)
+	bw := bufio.NewWriter(w)
+
+	var lines []string
+
+	for _, src := range sources {
+		br := bufio.NewReader(src.Reader)
+
+		for {
+			line, readErr := br.ReadString('\n')
+			if line != "" {
+				body, eol := strings.CutSuffix(line, "\n")
+				out := convert(body)
+
+				if f.IsJSON() {
+					lines = append(lines, out)
+				} else {
+					_, _ = bw.WriteString(out)
+					if eol {
+						_ = bw.WriteByte('\n')
+					}
+				}
+			}
+
+			if readErr == io.EOF {
+				break
+			}
+
+			if readErr != nil {
+				return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("%s: %s: %s", name, src.Name, readErr))
+			}
+		}
+	}
+
+	if f.IsJSON() {
+		return f.Print(Result{Lines: lines, Count: len(lines)})
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("%s: write: %s", name, err))
+	}
+
+	return nil
+}
+
+// ExpandLine replaces tabs with spaces up to the next tab stop. Past the
+// last explicit stop a tab becomes a single space. With initial, only tabs
+// before the first non-blank character are expanded.
+func ExpandLine(line string, ts TabStops, initial bool) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+
+	var b strings.Builder
+
+	col := 0
+	leading := true
+
+	for _, r := range line {
+		switch r {
+		case '\t':
+			next, ok := ts.Next(col)
+			if !ok {
+				next = col + 1
+			}
+
+			if initial && !leading {
+				b.WriteRune('\t')
+			} else {
+				b.WriteString(strings.Repeat(" ", next-col))
+			}
+
+			col = next
+
+			continue
+		case '\b':
+			if col > 0 {
+				col--
+			}
+		case ' ':
+			col++
+		default:
+			col++
+			leading = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// UnexpandLine replaces runs of blanks that reach a tab stop with tabs.
+// Only leading blanks are converted unless all is set. A single space
+// before a tab stop is kept as a space.
+func UnexpandLine(line string, ts TabStops, all bool) string {
+	if !strings.Contains(line, " ") {
+		return line
+	}
+
+	var (
+		b       strings.Builder
+		pending []rune
+	)
+
+	col := 0
+	converting := true
+
+	flush := func() {
+		b.WriteString(string(pending))
+		pending = pending[:0]
+	}
+
+	for _, r := range line {
+		if converting && (r == ' ' || r == '\t') {
+			if r == ' ' {
+				col++
+			} else if next, ok := ts.Next(col); ok {
+				col = next
+			} else {
+				col++
+			}
+
+			pending = append(pending, r)
+
+			if ts.IsStop(col) {
+				if len(pending) > 1 || pending[0] == '\t' {
+					b.WriteRune('\t')
+					pending = pending[:0]
+				} else {
+					flush()
+				}
+			}
+
+			continue
+		}
+
+		flush()
+
+		if !all {
+			converting = false
+		}
+
+		if r == '\b' {
+			if col > 0 {
+				col--
+			}
+		} else {
+			col++
+		}
+
+		b.WriteRune(r)
+	}
+
+	flush()
+
+	return b.String()
+}
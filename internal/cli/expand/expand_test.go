@@ -0,0 +1,140 @@
+package expand
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestParseTabStops(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int // first stops after column 0
+		wantErr bool
+	}{
+		{"", []int{8, 16, 24}, false},
+		{"4", []int{4, 8, 12}, false},
+		{"2,5,9", []int{2, 5, 9}, false},
+		{"2 5", []int{2, 5}, false},
+		{"3,/8", []int{3, 8, 16}, false},
+		{"3,+8", []int{3, 11, 19}, false},
+		{"5,3", nil, true},
+		{"x", nil, true},
+		{"0", nil, true},
+		{"/4,8", nil, true},
+	}
+
+	for _, tt := range tests {
+		ts, err := ParseTabStops(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTabStops(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+
+		if tt.wantErr {
+			continue
+		}
+
+		col := 0
+		for i, want := range tt.want {
+			next, ok := ts.Next(col)
+			if !ok || next != want {
+				t.Errorf("ParseTabStops(%q) stop %d = %d (%v), want %d", tt.spec, i, next, ok, want)
+				break
+			}
+
+			col = next
+		}
+	}
+}
+
+func TestExpandLine(t *testing.T) {
+	every4, _ := ParseTabStops("4")
+	list, _ := ParseTabStops("2,6")
+
+	tests := []struct {
+		name    string
+		line    string
+		ts      TabStops
+		initial bool
+		want    string
+	}{
+		{"leading", "\tx", every4, false, "    x"},
+		{"mid", "ab\tc", every4, false, "ab  c"},
+		{"initial only", "\tx\ty", every4, true, "    x\ty"},
+		{"list", "\ta\tb\tc", list, false, "  a   b c"},
+		{"no tabs", "plain", every4, false, "plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandLine(tt.line, tt.ts, tt.initial); got != tt.want {
+				t.Errorf("ExpandLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnexpandLine(t *testing.T) {
+	every4, _ := ParseTabStops("4")
+
+	tests := []struct {
+		name string
+		line string
+		all  bool
+		want string
+	}{
+		{"leading", "        x", false, "\t\tx"},
+		{"leading partial", "      x", false, "\t  x"},
+		{"inner kept", "    ab      c", false, "\tab      c"},
+		{"inner all", "    ab      c", true, "\tab\t\tc"},
+		{"single space", "abc d", true, "abc d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnexpandLine(tt.line, every4, tt.all); got != tt.want {
+				t.Errorf("UnexpandLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunExpandUnexpand(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunExpand(&buf, strings.NewReader("\ta\nb\tc"), nil, Options{Tabs: "4"}); err != nil {
+		t.Fatalf("RunExpand() error = %v", err)
+	}
+
+	if got, want := buf.String(), "    a\nb   c"; got != want {
+		t.Errorf("RunExpand() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	// -t implies -a
+	if err := RunUnexpand(&buf, strings.NewReader("    a   b\n"), nil, Options{Tabs: "4"}); err != nil {
+		t.Fatalf("RunUnexpand() error = %v", err)
+	}
+
+	if got, want := buf.String(), "\ta\tb\n"; got != want {
+		t.Errorf("RunUnexpand() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	if err := RunUnexpand(&buf, strings.NewReader("    a   b\n"), nil, Options{Tabs: "4", FirstOnly: true}); err != nil {
+		t.Fatalf("RunUnexpand() error = %v", err)
+	}
+
+	if got, want := buf.String(), "\ta   b\n"; got != want {
+		t.Errorf("RunUnexpand(--first-only) = %q, want %q", got, want)
+	}
+
+	err := RunExpand(&buf, strings.NewReader(""), nil, Options{Tabs: "8,4"})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunExpand(bad tabs) error = %v, want invalid input", err)
+	}
+}
@@ -2,8 +2,6 @@ package file
 
 import (
 	"bufio"
-	"bytes"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
@@ -12,6 +10,7 @@ import (
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/mimetype"
 )
 
 // FileOptions configures the file command behavior
@@ -148,9 +147,9 @@ func detectByContent(path string) (string, string) {
 
 	defer func() { _ = f.Close() }()
 
-	// Read first 512 bytes for magic detection
-	buf := make([]byte, 512)
-	n, _ := f.Read(buf)
+	// Read the leading bytes for magic detection
+	buf := make([]byte, mimetype.ReadLimit)
+	n, _ := io.ReadFull(f, buf)
 	buf = buf[:n]
 
 	if n == 0 {
@@ -170,120 +169,22 @@ func detectByContent(path string) (string, string) {
 	return "data", "application/octet-stream"
 }
 
+// checkMagic identifies binary formats by their signature. Text formats,
+// including those pkg/mimetype recognizes by content, are left to
+// detectTextType.
 func checkMagic(buf []byte) (string, string, bool) {
-	magics := []struct {
-		magic    []byte
-		fileType string
-		mimeType string
-	}{
-		// Images
-		{[]byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}, "PNG image data", "image/png"},
-		{[]byte{0xFF, 0xD8, 0xFF}, "JPEG image data", "image/jpeg"},
-		{[]byte{'G', 'I', 'F', '8', '7', 'a'}, "GIF image data, version 87a", "image/gif"},
-		{[]byte{'G', 'I', 'F', '8', '9', 'a'}, "GIF image data, version 89a", "image/gif"},
-		{[]byte{'B', 'M'}, "BMP image data", "image/bmp"},
-		{[]byte{'R', 'I', 'F', 'F'}, "RIFF data", "application/octet-stream"}, // Could be WAV, AVI, WebP
-
-		// Archives
-		{[]byte{0x1F, 0x8B}, "gzip compressed data", "application/gzip"},
-		{[]byte{'P', 'K', 0x03, 0x04}, "Zip archive data", "application/zip"},
-		{[]byte{'P', 'K', 0x05, 0x06}, "Zip archive data (empty)", "application/zip"},
-		{[]byte{0x42, 0x5A, 0x68}, "bzip2 compressed data", "application/x-bzip2"},
-		{[]byte{0xFD, '7', 'z', 'X', 'Z', 0x00}, "XZ compressed data", "application/x-xz"},
-		{[]byte{0x5D, 0x00, 0x00}, "LZMA compressed data", "application/x-lzma"},
-		{[]byte{'R', 'a', 'r', '!', 0x1A, 0x07}, "RAR archive data", "application/x-rar-compressed"},
-		{[]byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}, "7-zip archive data", "application/x-7z-compressed"},
-
-		// Documents
-		{[]byte{'%', 'P', 'D', 'F'}, "PDF document", "application/pdf"},
-		{[]byte{0xD0, 0xCF, 0x11, 0xE0}, "Microsoft Office document", "application/msword"},
-
-		// Executables
-		{[]byte{0x7F, 'E', 'L', 'F'}, "ELF", "application/x-executable"},
-		{[]byte{'M', 'Z'}, "PE32 executable", "application/x-dosexec"},
-		{[]byte{0xCA, 0xFE, 0xBA, 0xBE}, "Mach-O universal binary", "application/x-mach-binary"},
-		{[]byte{0xCF, 0xFA, 0xED, 0xFE}, "Mach-O 64-bit executable", "application/x-mach-binary"},
-		{[]byte{0xCE, 0xFA, 0xED, 0xFE}, "Mach-O 32-bit executable", "application/x-mach-binary"},
-
-		// Audio/Video
-		{[]byte{0x00, 0x00, 0x00}, "MPEG video", "video/mpeg"}, // Simplified
-		{[]byte{'I', 'D', '3'}, "Audio file with ID3", "audio/mpeg"},
-		{[]byte{0xFF, 0xFB}, "MPEG audio", "audio/mpeg"},
-		{[]byte{'O', 'g', 'g', 'S'}, "Ogg data", "application/ogg"},
-		{[]byte{'f', 'L', 'a', 'C'}, "FLAC audio", "audio/flac"},
-
-		// Other
-		{[]byte{0x00, 0x61, 0x73, 0x6D}, "WebAssembly binary", "application/wasm"},
-		{[]byte{'S', 'Q', 'L', 'i', 't', 'e'}, "SQLite database", "application/x-sqlite3"},
+	t := mimetype.Detect(buf)
+	if t == mimetype.OctetStream || t == mimetype.Empty || t.IsText() {
+		return "", "", false
 	}
 
-	for _, m := range magics {
-		if bytes.HasPrefix(buf, m.magic) {
-			fileType := m.fileType
-
-			// Special handling for ELF
-			if m.fileType == "ELF" && len(buf) > 16 {
-				fileType = describeELF(buf)
-			}
-
-			return fileType, m.mimeType, true
-		}
-	}
-
-	// Check for tar (magic at offset 257)
-	if len(buf) > 262 && string(buf[257:262]) == "ustar" {
-		return "POSIX tar archive", "application/x-tar", true
-	}
-
-	return "", "", false
+	return t.Description, t.MIME, true
 }
 
+// describeELF describes an ELF header the way file(1) does, e.g.
+// "ELF 64-bit LSB executable".
 func describeELF(buf []byte) string {
-	if len(buf) < 20 {
-		return "ELF"
-	}
-
-	var result strings.Builder
-
-	result.WriteString("ELF ")
-
-	// 32 or 64 bit
-	switch buf[4] {
-	case 1:
-		result.WriteString("32-bit ")
-	case 2:
-		result.WriteString("64-bit ")
-	}
-
-	// Endianness
-	switch buf[5] {
-	case 1:
-		result.WriteString("LSB ")
-	case 2:
-		result.WriteString("MSB ")
-	}
-
-	// Type
-	var elfType uint16
-
-	if buf[5] == 1 { // Little endian
-		elfType = binary.LittleEndian.Uint16(buf[16:18])
-	} else {
-		elfType = binary.BigEndian.Uint16(buf[16:18])
-	}
-
-	switch elfType {
-	case 1:
-		result.WriteString("relocatable")
-	case 2:
-		result.WriteString("executable")
-	case 3:
-		result.WriteString("shared object")
-	case 4:
-		result.WriteString("core file")
-	}
-
-	return result.String()
+	return mimetype.Detect(buf).Description
 }
 
 func isText(buf []byte) bool {
@@ -369,13 +270,19 @@ func detectTextType(path string, buf []byte) (string, string) {
 		return "Rust source, ASCII text", "text/x-rust"
 	}
 
-	// Check content patterns
-	if strings.HasPrefix(content, "<?xml") {
-		return "XML document", "application/xml"
+	// Check content patterns (XML, HTML, PEM, JSON, ...), then the wider
+	// extension table
+	detected := mimetype.Detect(buf)
+	if detected.MIME != "text/plain" && detected.IsText() {
+		return detected.Description, detected.MIME
 	}
 
-	if strings.HasPrefix(content, "<!DOCTYPE html") || strings.HasPrefix(content, "<html") {
-		return "HTML document, ASCII text", "text/html"
+	if byExt, ok := mimetype.ByExtension(ext); ok && byExt.IsText() && byExt != mimetype.PlainText {
+		if detected.MIME == "text/plain" {
+			return byExt.Description + ", " + detected.Description, byExt.MIME
+		}
+
+		return byExt.Description, byExt.MIME
 	}
 
 	if strings.HasPrefix(content, "{") || strings.HasPrefix(content, "[") {
@@ -396,5 +303,9 @@ func detectTextType(path string, buf []byte) (string, string) {
 		}
 	}
 
+	if detected.MIME == "text/plain" {
+		return detected.Description, detected.MIME
+	}
+
 	return "ASCII text", "text/plain"
 }
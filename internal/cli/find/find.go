@@ -2,6 +2,7 @@
 package find
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
@@ -37,6 +39,7 @@ type FindOptions struct {
 	Readable     bool          // -readable
 	Writable     bool          // -writable
 	Print0       bool          // -print0 (null separator)
+	FilesFrom    string        // -files-from FILE: test the listed paths instead of walking ("-" for stdin)
 	OutputFormat output.Format // output format
 	// Logical operators
 	Not bool // -not (negate next condition)
@@ -57,7 +60,7 @@ func RunFind(w io.Writer, paths []string, opts FindOptions) error {
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
 
-	if len(paths) == 0 {
+	if len(paths) == 0 && opts.FilesFrom == "" {
 		paths = []string{"."}
 	}
 
@@ -161,6 +164,130 @@ func RunFind(w io.Writer, paths []string, opts FindOptions) error {
 
 	var results []FindResult
 
+	// matches applies the tests to one entry
+	matches := func(path string, d fs.DirEntry) bool {
+		match := true
+
+		// Type filter
+		if opts.Type != "" && match {
+			match = matchType(d, opts.Type)
+		}
+
+		// Name filter
+		if namePattern != nil && match {
+			match = namePattern.MatchString(d.Name())
+		}
+
+		// Path filter
+		if pathPattern != nil && match {
+			match = pathPattern.MatchString(path)
+		}
+
+		// Size filter
+		if sizeFilter != nil && match {
+			if info, err := d.Info(); err == nil {
+				match = sizeFilter(info.Size())
+			} else {
+				match = false
+			}
+		}
+
+		// Time filters
+		if mtimeFilter != nil && match {
+			if info, err := d.Info(); err == nil {
+				match = mtimeFilter(info.ModTime())
+			} else {
+				match = false
+			}
+		}
+
+		if atimeFilter != nil && match {
+			if info, err := d.Info(); err == nil {
+				// Note: Go doesn't expose atime directly, using mtime as fallback
+				match = atimeFilter(info.ModTime())
+			} else {
+				match = false
+			}
+		}
+
+		// Empty filter
+		if opts.Empty && match {
+			match = isEmpty(path, d)
+		}
+
+		// Permission filters
+		if opts.Readable && match {
+			match = isReadable(path)
+		}
+
+		if opts.Writable && match {
+			match = isWritable(path)
+		}
+
+		if opts.Executable && match {
+			match = isExecutable(d)
+		}
+
+		// Apply NOT
+		if opts.Not {
+			match = !match
+		}
+
+		return match
+	}
+
+	// emit prints or collects one matching entry
+	emit := func(path string, d fs.DirEntry) {
+		if jsonMode {
+			info, _ := d.Info()
+
+			result := FindResult{
+				Path:  path,
+				Name:  d.Name(),
+				IsDir: d.IsDir(),
+			}
+			if info != nil {
+				result.Size = info.Size()
+				result.Mode = info.Mode().String()
+				result.ModTime = info.ModTime()
+			}
+
+			results = append(results, result)
+		} else {
+			_, _ = fmt.Fprint(w, path, separator)
+		}
+	}
+
+	if opts.FilesFrom != "" {
+		if len(paths) > 0 {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "find: --files-from cannot be combined with starting points")
+		}
+
+		listed, err := input.ReadFileList(opts.FilesFrom, os.Stdin)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("find: --files-from: %s", err))
+			}
+
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("find: --files-from: %s", err))
+		}
+
+		// Listed entries are tested as given, without descending into
+		// directories, so a list from a previous walk is not walked again
+		for _, path := range listed {
+			info, err := os.Lstat(path)
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "find: %q: %v\n", path, err)
+				continue
+			}
+
+			d := fs.FileInfoToDirEntry(info)
+			if matches(path, d) {
+				emit(path, d)
+			}
+		}
+	}
+
 	for _, root := range paths {
 		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
@@ -190,93 +317,8 @@ func RunFind(w io.Writer, paths []string, opts FindOptions) error {
 				return nil
 			}
 
-			// Apply filters
-			match := true
-
-			// Type filter
-			if opts.Type != "" && match {
-				match = matchType(d, opts.Type)
-			}
-
-			// Name filter
-			if namePattern != nil && match {
-				match = namePattern.MatchString(d.Name())
-			}
-
-			// Path filter
-			if pathPattern != nil && match {
-				match = pathPattern.MatchString(path)
-			}
-
-			// Size filter
-			if sizeFilter != nil && match {
-				if info, err := d.Info(); err == nil {
-					match = sizeFilter(info.Size())
-				} else {
-					match = false
-				}
-			}
-
-			// Time filters
-			if mtimeFilter != nil && match {
-				if info, err := d.Info(); err == nil {
-					match = mtimeFilter(info.ModTime())
-				} else {
-					match = false
-				}
-			}
-
-			if atimeFilter != nil && match {
-				if info, err := d.Info(); err == nil {
-					// Note: Go doesn't expose atime directly, using mtime as fallback
-					match = atimeFilter(info.ModTime())
-				} else {
-					match = false
-				}
-			}
-
-			// Empty filter
-			if opts.Empty && match {
-				match = isEmpty(path, d)
-			}
-
-			// Permission filters
-			if opts.Readable && match {
-				match = isReadable(path)
-			}
-
-			if opts.Writable && match {
-				match = isWritable(path)
-			}
-
-			if opts.Executable && match {
-				match = isExecutable(d)
-			}
-
-			// Apply NOT
-			if opts.Not {
-				match = !match
-			}
-
-			if match {
-				if jsonMode {
-					info, _ := d.Info()
-
-					result := FindResult{
-						Path:  path,
-						Name:  d.Name(),
-						IsDir: d.IsDir(),
-					}
-					if info != nil {
-						result.Size = info.Size()
-						result.Mode = info.Mode().String()
-						result.ModTime = info.ModTime()
-					}
-
-					results = append(results, result)
-				} else {
-					_, _ = fmt.Fprint(w, path, separator)
-				}
+			if matches(path, d) {
+				emit(path, d)
 			}
 
 			return nil
@@ -392,3 +392,43 @@ func TestIsReadable(t *testing.T) {
 		t.Error("isReadable() should return false for nonexistent file")
 	}
 }
+
+func TestRunFindFilesFrom(t *testing.T) {
+	dir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "b.txt"), []byte(""), 0644)
+	_ = os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	_ = os.WriteFile(filepath.Join(dir, "sub", "c.go"), []byte("package c"), 0644)
+
+	// The list names the directory but not its contents: it must not be walked
+	list := strings.Join([]string{filepath.Join(dir, "a.go"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "sub")}, "\n")
+	listFile := filepath.Join(t.TempDir(), "list")
+
+	if err := os.WriteFile(listFile, []byte(list+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RunFind(&buf, nil, FindOptions{FilesFrom: listFile, Type: "f"}); err != nil {
+		t.Fatalf("RunFind() error = %v", err)
+	}
+
+	if got, want := buf.String(), filepath.Join(dir, "a.go")+"\n"+filepath.Join(dir, "b.txt")+"\n"; got != want {
+		t.Errorf("RunFind(-type f) = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	if err := RunFind(&buf, nil, FindOptions{FilesFrom: listFile, Name: "*.go"}); err != nil {
+		t.Fatalf("RunFind() error = %v", err)
+	}
+
+	if got := buf.String(); got != filepath.Join(dir, "a.go")+"\n" {
+		t.Errorf("RunFind(-name *.go) = %q", got)
+	}
+
+	if err := RunFind(&buf, []string{dir}, FindOptions{FilesFrom: listFile}); err == nil {
+		t.Error("expected error combining -files-from with starting points")
+	}
+}
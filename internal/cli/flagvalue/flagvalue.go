@@ -0,0 +1,259 @@
+// Package flagvalue provides pflag.Value types shared by commands for
+// quantities people type with units: byte sizes ("64MiB", "1.5G"),
+// durations with days and weeks ("2d4h"), and percentages ("12.5%").
+// Each type comes with the parse function commands use for values that
+// arrive some other way (a prefixed count, a config file), so a size or
+// duration means the same thing everywhere.
+package flagvalue
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/pkg/timeparse"
+	"github.com/spf13/pflag"
+)
+
+// sizeUnits maps the lower-cased size suffixes to their multipliers. K, M,
+// G, ... and their KiB forms are powers of 1024; KB, MB, ... are powers of
+// 1000, as in GNU coreutils. A lone lower-case "b" is handled separately.
+var sizeUnits = map[string]float64{"": 1}
+
+func init() {
+	for i, letter := range "kmgtpe" {
+		binary := math.Pow(1024, float64(i+1))
+		decimal := math.Pow(1000, float64(i+1))
+
+		sizeUnits[string(letter)] = binary
+		sizeUnits[string(letter)+"i"] = binary
+		sizeUnits[string(letter)+"ib"] = binary
+		sizeUnits[string(letter)+"b"] = decimal
+	}
+}
+
+// ParseByteSize parses a byte count with an optional unit suffix: K, M, G,
+// T, P or E (or KiB, MiB, ...) for powers of 1024, KB, MB, ... for powers
+// of 1000, B for bytes and a lower-case b for 512-byte blocks. Suffixes
+// other than b are case-insensitive and fractions are allowed ("1.5M");
+// the result is rounded down to a whole byte.
+func ParseByteSize(s string) (int64, error) {
+	in := strings.TrimSpace(s)
+
+	end := len(in)
+	for end > 0 && (in[end-1] >= 'a' && in[end-1] <= 'z' || in[end-1] >= 'A' && in[end-1] <= 'Z') {
+		end--
+	}
+
+	num, suffix := in[:end], in[end:]
+
+	mult, ok := sizeUnits[strings.ToLower(suffix)]
+
+	switch suffix {
+	case "b":
+		mult, ok = 512, true
+	case "B":
+		mult, ok = 1, true
+	}
+
+	v, err := strconv.ParseFloat(num, 64)
+	if !ok || err != nil || num == "" || strings.ContainsAny(num, "+-eExXpP") || math.IsNaN(v) {
+		return 0, fmt.Errorf("invalid size %q (use a number with an optional K, M, G, T, P or E suffix, e.g. 64MiB)", s)
+	}
+
+	v *= mult
+	if v >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q out of range", s)
+	}
+
+	return int64(v), nil
+}
+
+// FormatByteSize formats n in the largest binary unit that divides it
+// exactly, so the result parses back to n: 65536 is "64KiB".
+func FormatByteSize(n int64) string {
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+	unit := ""
+	for i := 0; n != 0 && n%1024 == 0 && i < len(units); i++ {
+		n /= 1024
+		unit = units[i]
+	}
+
+	return strconv.FormatInt(n, 10) + unit
+}
+
+// ParseDuration parses a non-negative duration in the forms of
+// timeparse.ParseDuration: Go durations plus days and weeks ("2d4h",
+// "1w"), with a bare number read as seconds.
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := timeparse.ParseDuration(s, time.Second)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30s, 1h30m or 2d4h)", s)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %q: must not be negative", s)
+	}
+
+	return d, nil
+}
+
+// ParsePercent parses a non-negative percentage, with or without a
+// trailing "%": "12.5%" and "12.5" are both 12.5.
+func ParsePercent(s string) (float64, error) {
+	in := strings.TrimSuffix(strings.TrimSpace(s), "%")
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(in), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("invalid percentage %q (use e.g. 5 or 12.5%%)", s)
+	}
+
+	if v < 0 {
+		return 0, fmt.Errorf("invalid percentage %q: must not be negative", s)
+	}
+
+	return v, nil
+}
+
+// ByteSize is a pflag.Value holding a byte count parsed by ParseByteSize.
+type ByteSize int64
+
+// Set implements pflag.Value.
+func (b *ByteSize) Set(s string) error {
+	n, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+
+	*b = ByteSize(n)
+
+	return nil
+}
+
+// String implements pflag.Value.
+func (b *ByteSize) String() string { return FormatByteSize(int64(*b)) }
+
+// Type implements pflag.Value.
+func (b *ByteSize) Type() string { return "size" }
+
+// Duration is a pflag.Value holding a duration parsed by ParseDuration.
+type Duration time.Duration
+
+// Set implements pflag.Value.
+func (d *Duration) Set(s string) error {
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// String implements pflag.Value. Zero is "0", so help output omits a zero
+// default as it does for plain numbers.
+func (d *Duration) String() string {
+	if *d == 0 {
+		return "0"
+	}
+
+	return timeparse.FormatDuration(time.Duration(*d))
+}
+
+// Type implements pflag.Value.
+func (d *Duration) Type() string { return "duration" }
+
+// Percent is a pflag.Value holding a percentage parsed by ParsePercent.
+type Percent float64
+
+// Set implements pflag.Value.
+func (p *Percent) Set(s string) error {
+	v, err := ParsePercent(s)
+	if err != nil {
+		return err
+	}
+
+	*p = Percent(v)
+
+	return nil
+}
+
+// String implements pflag.Value.
+func (p *Percent) String() string {
+	if *p == 0 {
+		return "0"
+	}
+
+	return strconv.FormatFloat(float64(*p), 'f', -1, 64) + "%"
+}
+
+// Type implements pflag.Value.
+func (p *Percent) Type() string { return "percent" }
+
+// ByteSizeP defines a byte size flag with the given default.
+func ByteSizeP(fs *pflag.FlagSet, name, shorthand string, value int64, usage string) {
+	v := ByteSize(value)
+	fs.VarP(&v, name, shorthand, usage)
+}
+
+// DurationP defines a duration flag with the given default.
+func DurationP(fs *pflag.FlagSet, name, shorthand string, value time.Duration, usage string) {
+	v := Duration(value)
+	fs.VarP(&v, name, shorthand, usage)
+}
+
+// PercentP defines a percentage flag with the given default.
+func PercentP(fs *pflag.FlagSet, name, shorthand string, value float64, usage string) {
+	v := Percent(value)
+	fs.VarP(&v, name, shorthand, usage)
+}
+
+// GetByteSize returns the value of a flag defined with ByteSizeP.
+func GetByteSize(fs *pflag.FlagSet, name string) (int64, error) {
+	v, err := lookup[*ByteSize](fs, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(*v), nil
+}
+
+// GetDuration returns the value of a flag defined with DurationP.
+func GetDuration(fs *pflag.FlagSet, name string) (time.Duration, error) {
+	v, err := lookup[*Duration](fs, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(*v), nil
+}
+
+// GetPercent returns the value of a flag defined with PercentP.
+func GetPercent(fs *pflag.FlagSet, name string) (float64, error) {
+	v, err := lookup[*Percent](fs, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(*v), nil
+}
+
+func lookup[T pflag.Value](fs *pflag.FlagSet, name string) (T, error) {
+	var zero T
+
+	f := fs.Lookup(name)
+	if f == nil {
+		return zero, fmt.Errorf("flag accessed but not defined: %s", name)
+	}
+
+	v, ok := f.Value.(T)
+	if !ok {
+		return zero, fmt.Errorf("flag %s has type %s", name, f.Value.Type())
+	}
+
+	return v, nil
+}
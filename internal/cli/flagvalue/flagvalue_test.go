@@ -0,0 +1,141 @@
+package flagvalue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"100", 100},
+		{"100B", 100},
+		{"2b", 1024},
+		{"1K", 1024},
+		{"1k", 1024},
+		{"64MiB", 64 << 20},
+		{"64mib", 64 << 20},
+		{"3Gi", 3 << 30},
+		{"1KB", 1000},
+		{"2MB", 2000000},
+		{"1.5M", 3 << 19},
+		{" 1T ", 1 << 40},
+		{"1.0001", 1},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "K", "-1", "+1", "1e3", "0x10", "12X", "1KiBs", "lots", "NaN", "8E", "1.5.2M"} {
+		if got, err := ParseByteSize(in); err == nil {
+			t.Errorf("ParseByteSize(%q) = %d, want error", in, got)
+		}
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := map[int64]string{0: "0", 100: "100", 1024: "1KiB", 1536: "1536", 64 << 20: "64MiB", 1 << 60: "1EiB"}
+
+	for n, want := range tests {
+		if got := FormatByteSize(n); got != want {
+			t.Errorf("FormatByteSize(%d) = %q, want %q", n, got, want)
+		}
+
+		if back, err := ParseByteSize(want); err != nil || back != n {
+			t.Errorf("ParseByteSize(%q) = %d, %v, want %d", want, back, err, n)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"0":      0,
+		"30":     30 * time.Second,
+		"1.5h":   90 * time.Minute,
+		"2d4h":   52 * time.Hour,
+		"1w":     7 * 24 * time.Hour,
+		"250ms":  250 * time.Millisecond,
+		"1h 30m": 90 * time.Minute,
+	}
+
+	for in, want := range tests {
+		if got, err := ParseDuration(in); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "-1s", "soon", "3 fortnights"} {
+		if got, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	tests := map[string]float64{"0": 0, "5": 5, "12.5%": 12.5, " 150 % ": 150}
+
+	for in, want := range tests {
+		if got, err := ParsePercent(in); err != nil || got != want {
+			t.Errorf("ParsePercent(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "%", "-5%", "five", "Inf"} {
+		if got, err := ParsePercent(in); err == nil {
+			t.Errorf("ParsePercent(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	ByteSizeP(fs, "max-filesize", "", 0, "")
+	DurationP(fs, "every", "e", time.Second, "")
+	PercentP(fs, "max-regress", "", 0, "")
+	fs.Int("count", 0, "")
+
+	if f := fs.Lookup("every"); f.DefValue != "1s" || f.Value.Type() != "duration" {
+		t.Errorf("every default = %q (%s)", f.DefValue, f.Value.Type())
+	}
+
+	if err := fs.Parse([]string{"--max-filesize", "64M", "-e", "2d", "--max-regress=10%"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := GetByteSize(fs, "max-filesize"); err != nil || v != 64<<20 {
+		t.Errorf("GetByteSize() = %d, %v", v, err)
+	}
+
+	if v, err := GetDuration(fs, "every"); err != nil || v != 48*time.Hour {
+		t.Errorf("GetDuration() = %v, %v", v, err)
+	}
+
+	if v, err := GetPercent(fs, "max-regress"); err != nil || v != 10 {
+		t.Errorf("GetPercent() = %v, %v", v, err)
+	}
+
+	if got := fs.Lookup("max-regress").Value.String(); got != "10%" {
+		t.Errorf("Percent.String() = %q", got)
+	}
+
+	if _, err := GetByteSize(fs, "count"); err == nil {
+		t.Error("GetByteSize() of an int flag error = nil")
+	}
+
+	if _, err := GetDuration(fs, "missing"); err == nil {
+		t.Error("GetDuration() of an undefined flag error = nil")
+	}
+
+	if err := fs.Parse([]string{"--max-filesize", "huge"}); err == nil {
+		t.Error("Parse() with an invalid size error = nil")
+	}
+}
@@ -1,10 +1,14 @@
 package free
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"time"
 
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/sysinfo"
 )
 
 // FreeOptions configures the free command behavior
@@ -17,137 +21,130 @@ type FreeOptions struct {
 	Wide         bool          // -w: wide output
 	Total        bool          // -t: show total for RAM + swap
 	Seconds      int           // -s: continuously display every N seconds
-	Count        int           // -c: display N times, then exit
+	Count        int           // -c: display N times, then exit (1s apart unless -s)
 	OutputFormat output.Format // output format (text/json/table)
 }
 
 // MemInfo contains memory information
 type MemInfo struct {
 	MemTotal     uint64 `json:"memTotal"`
+	MemUsed      uint64 `json:"memUsed"`
 	MemFree      uint64 `json:"memFree"`
+	Shared       uint64 `json:"shared"`
 	MemAvailable uint64 `json:"memAvailable"`
 	Buffers      uint64 `json:"buffers"`
 	Cached       uint64 `json:"cached"`
 	SwapTotal    uint64 `json:"swapTotal"`
+	SwapUsed     uint64 `json:"swapUsed"`
 	SwapFree     uint64 `json:"swapFree"`
 }
 
-// RunFree displays amount of free and used memory in the system
-func RunFree(w io.Writer, opts FreeOptions) error {
-	info, err := getMemInfo()
-	if err != nil {
-		return err
+// RunFree displays amount of free and used memory in the system. With -s or
+// -c it keeps sampling until the count is reached or ctx is cancelled.
+func RunFree(ctx context.Context, w io.Writer, opts FreeOptions) error {
+	if opts.Seconds < 0 || opts.Count < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "free: seconds and count must not be negative")
+	}
+
+	interval := time.Duration(opts.Seconds) * time.Second
+	if interval == 0 && opts.Count > 1 {
+		interval = time.Second // procps: -c without -s samples every second
 	}
 
 	f := output.New(w, opts.OutputFormat)
-	if f.IsJSON() {
-		return f.Print(info)
+
+	for i := 1; ; i++ {
+		info, err := getMemInfo(ctx)
+		if err != nil {
+			return err
+		}
+
+		if f.IsJSON() {
+			if err := f.Print(info); err != nil {
+				return err
+			}
+		} else {
+			printFree(w, info, opts)
+		}
+
+		if interval == 0 || (opts.Count > 0 && i >= opts.Count) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		if !f.IsJSON() {
+			_, _ = fmt.Fprintln(w)
+		}
 	}
+}
 
-	// Determine unit divisor and suffix
+func printFree(w io.Writer, info MemInfo, opts FreeOptions) {
+	// Determine unit divisor; 0 means format each value individually
 	var divisor uint64 = 1024 // Default kibibytes
 
-	suffix := ""
-
 	switch {
+	case opts.Human:
+		divisor = 0
 	case opts.Bytes:
 		divisor = 1
-		suffix = "B"
 	case opts.Mebibytes:
 		divisor = 1024 * 1024
-		suffix = "Mi"
 	case opts.Gibibytes:
 		divisor = 1024 * 1024 * 1024
-		suffix = "Gi"
-	case opts.Human:
-		// Will format each value individually
-		divisor = 0
-	default:
-		suffix = "Ki"
 	}
 
-	// Calculate values using saturating subtraction so inconsistent or
-	// partially-read source data (e.g. MemTotal == 0) cannot underflow uint64
-	// and print a nonsensical ~1.8e19 value.
-	memUsed := subSat(subSat(subSat(info.MemTotal, info.MemFree), info.Buffers), info.Cached)
-	swapUsed := subSat(info.SwapTotal, info.SwapFree)
-
-	// Print header
+	width := 12
 	if opts.Human {
-		_, _ = fmt.Fprintf(w, "%15s %10s %10s %10s %10s %10s\n",
-			"", "total", "used", "free", "shared", "available")
-	} else {
-		_, _ = fmt.Fprintf(w, "%15s %12s %12s %12s %12s %12s\n",
-			"", "total", "used", "free", "shared", "available")
+		width = 10
 	}
 
-	// Print memory line
-	if opts.Human {
-		_, _ = fmt.Fprintf(w, "%-15s %10s %10s %10s %10s %10s\n",
-			"Mem:",
-			formatBytes(info.MemTotal),
-			formatBytes(memUsed),
-			formatBytes(info.MemFree),
-			formatBytes(0), // shared not easily available
-			formatBytes(info.MemAvailable))
-	} else {
-		_, _ = fmt.Fprintf(w, "%-15s %12d %12d %12d %12d %12d\n",
-			"Mem:",
-			info.MemTotal/divisor,
-			memUsed/divisor,
-			info.MemFree/divisor,
-			0, // shared
-			info.MemAvailable/divisor)
-	}
+	cell := func(v uint64) string {
+		if divisor == 0 {
+			return fmt.Sprintf(" %*s", width, formatBytes(v))
+		}
 
-	// Print swap line
-	if opts.Human {
-		_, _ = fmt.Fprintf(w, "%-15s %10s %10s %10s\n",
-			"Swap:",
-			formatBytes(info.SwapTotal),
-			formatBytes(swapUsed),
-			formatBytes(info.SwapFree))
-	} else {
-		_, _ = fmt.Fprintf(w, "%-15s %12d %12d %12d\n",
-			"Swap:",
-			info.SwapTotal/divisor,
-			swapUsed/divisor,
-			info.SwapFree/divisor)
+		return fmt.Sprintf(" %*d", width, v/divisor)
 	}
 
-	// Print total if requested
-	if opts.Total {
-		totalMem := info.MemTotal + info.SwapTotal
-		totalUsed := memUsed + swapUsed
-		totalFree := info.MemFree + info.SwapFree
-
-		if opts.Human {
-			_, _ = fmt.Fprintf(w, "%-15s %10s %10s %10s\n",
-				"Total:",
-				formatBytes(totalMem),
-				formatBytes(totalUsed),
-				formatBytes(totalFree))
-		} else {
-			_, _ = fmt.Fprintf(w, "%-15s %12d %12d %12d\n",
-				"Total:",
-				totalMem/divisor,
-				totalUsed/divisor,
-				totalFree/divisor)
+	row := func(label string, values ...uint64) {
+		line := fmt.Sprintf("%-15s", label)
+		for _, v := range values {
+			line += cell(v)
 		}
+
+		_, _ = fmt.Fprintln(w, line)
 	}
 
-	_ = suffix // Suppress unused warning
+	// Print header
+	header := fmt.Sprintf("%15s", "")
+
+	columns := []string{"total", "used", "free", "shared", "buff/cache", "available"}
+	if opts.Wide {
+		columns = []string{"total", "used", "free", "shared", "buffers", "cache", "available"}
+	}
 
-	return nil
-}
+	for _, c := range columns {
+		header += fmt.Sprintf(" %*s", width, c)
+	}
+
+	_, _ = fmt.Fprintln(w, header)
+
+	if opts.Wide {
+		row("Mem:", info.MemTotal, info.MemUsed, info.MemFree, info.Shared, info.Buffers, info.Cached, info.MemAvailable)
+	} else {
+		row("Mem:", info.MemTotal, info.MemUsed, info.MemFree, info.Shared, info.Buffers+info.Cached, info.MemAvailable)
+	}
+
+	row("Swap:", info.SwapTotal, info.SwapUsed, info.SwapFree)
 
-// subSat returns a-b, saturating at zero instead of wrapping around when b > a.
-// This guards against uint64 underflow from inconsistent memory-source data.
-func subSat(a, b uint64) uint64 {
-	if b > a {
-		return 0
+	if opts.Total {
+		row("Total:", info.MemTotal+info.SwapTotal, info.MemUsed+info.SwapUsed, info.MemFree+info.SwapFree)
 	}
-	return a - b
 }
 
 func formatBytes(bytes uint64) string {
@@ -167,5 +164,25 @@ func formatBytes(bytes uint64) string {
 
 // GetMemInfo returns system memory information
 func GetMemInfo() (MemInfo, error) {
-	return getMemInfo()
+	return getMemInfo(context.Background())
+}
+
+func getMemInfo(ctx context.Context) (MemInfo, error) {
+	m, err := sysinfo.GetMemory(ctx)
+	if err != nil {
+		return MemInfo{}, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("free: %v", err))
+	}
+
+	return MemInfo{
+		MemTotal:     m.Total,
+		MemUsed:      m.Used,
+		MemFree:      m.Free,
+		Shared:       m.Shared,
+		MemAvailable: m.Available,
+		Buffers:      m.Buffers,
+		Cached:       m.Cached,
+		SwapTotal:    m.SwapTotal,
+		SwapUsed:     m.SwapUsed,
+		SwapFree:     m.SwapFree,
+	}, nil
 }
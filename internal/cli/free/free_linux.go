@@ -1,79 +0,0 @@
-//go:build linux
-
-package free
-
-import (
-	"bufio"
-	"fmt"
-	"os"
-	"strconv"
-	"strings"
-
-	"golang.org/x/sys/unix"
-
-	"github.com/inovacc/omni/internal/cli/cmderr"
-)
-
-func getMemInfo() (MemInfo, error) {
-	var info MemInfo
-
-	// Try reading from /proc/meminfo first (Linux)
-	file, err := os.Open("/proc/meminfo")
-	if err == nil {
-		defer func() {
-			_ = file.Close()
-		}()
-
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			fields := strings.Fields(line)
-			if len(fields) < 2 {
-				continue
-			}
-
-			value, _ := strconv.ParseUint(fields[1], 10, 64)
-			value *= 1024 // Convert from KB to bytes
-
-			switch fields[0] {
-			case "MemTotal:":
-				info.MemTotal = value
-			case "MemFree:":
-				info.MemFree = value
-			case "MemAvailable:":
-				info.MemAvailable = value
-			case "Buffers:":
-				info.Buffers = value
-			case "Cached:":
-				info.Cached = value
-			case "SwapTotal:":
-				info.SwapTotal = value
-			case "SwapFree:":
-				info.SwapFree = value
-			}
-		}
-
-		return info, nil
-	}
-
-	// Fallback to sysinfo (works on Linux but less detailed).
-	// Use golang.org/x/sys/unix instead of the standard syscall package so the
-	// Sysinfo types/calls resolve without relying on Linux-only symbols that
-	// break cross-compilation under other unix GOOS values.
-	var sysinfo unix.Sysinfo_t
-	if err := unix.Sysinfo(&sysinfo); err != nil {
-		return info, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("free: sysinfo: %v", err))
-	}
-
-	unit := uint64(sysinfo.Unit)
-	info.MemTotal = sysinfo.Totalram * unit
-	info.MemFree = sysinfo.Freeram * unit
-	info.MemAvailable = info.MemFree // Approximation
-	info.Buffers = sysinfo.Bufferram * unit
-	info.Cached = 0 // Not available from sysinfo
-	info.SwapTotal = sysinfo.Totalswap * unit
-	info.SwapFree = sysinfo.Freeswap * unit
-
-	return info, nil
-}
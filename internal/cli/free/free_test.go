@@ -2,15 +2,18 @@ package free
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 func TestRunFree(t *testing.T) {
 	t.Run("default output", func(t *testing.T) {
 		var buf bytes.Buffer
 
-		err := RunFree(&buf, FreeOptions{})
+		err := RunFree(context.Background(), &buf, FreeOptions{})
 		if err != nil {
 			t.Fatalf("RunFree() error = %v", err)
 		}
@@ -28,7 +31,7 @@ func TestRunFree(t *testing.T) {
 	t.Run("human readable", func(t *testing.T) {
 		var buf bytes.Buffer
 
-		err := RunFree(&buf, FreeOptions{Human: true})
+		err := RunFree(context.Background(), &buf, FreeOptions{Human: true})
 		if err != nil {
 			t.Fatalf("RunFree() error = %v", err)
 		}
@@ -43,7 +46,7 @@ func TestRunFree(t *testing.T) {
 	t.Run("bytes mode", func(t *testing.T) {
 		var buf bytes.Buffer
 
-		err := RunFree(&buf, FreeOptions{Bytes: true})
+		err := RunFree(context.Background(), &buf, FreeOptions{Bytes: true})
 		if err != nil {
 			t.Fatalf("RunFree() error = %v", err)
 		}
@@ -57,7 +60,7 @@ func TestRunFree(t *testing.T) {
 	t.Run("mebibytes mode", func(t *testing.T) {
 		var buf bytes.Buffer
 
-		err := RunFree(&buf, FreeOptions{Mebibytes: true})
+		err := RunFree(context.Background(), &buf, FreeOptions{Mebibytes: true})
 		if err != nil {
 			t.Fatalf("RunFree() error = %v", err)
 		}
@@ -71,7 +74,7 @@ func TestRunFree(t *testing.T) {
 	t.Run("gibibytes mode", func(t *testing.T) {
 		var buf bytes.Buffer
 
-		err := RunFree(&buf, FreeOptions{Gibibytes: true})
+		err := RunFree(context.Background(), &buf, FreeOptions{Gibibytes: true})
 		if err != nil {
 			t.Fatalf("RunFree() error = %v", err)
 		}
@@ -82,10 +85,60 @@ func TestRunFree(t *testing.T) {
 		}
 	})
 
+	t.Run("wide", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := RunFree(context.Background(), &buf, FreeOptions{Wide: true}); err != nil {
+			t.Fatalf("RunFree() error = %v", err)
+		}
+
+		header := strings.Fields(strings.SplitN(buf.String(), "\n", 2)[0])
+		want := []string{"total", "used", "free", "shared", "buffers", "cache", "available"}
+
+		if strings.Join(header, " ") != strings.Join(want, " ") {
+			t.Errorf("wide header = %v, want %v", header, want)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunFree(context.Background(), &buf, FreeOptions{Seconds: 1, Count: 2})
+		if err != nil {
+			t.Fatalf("RunFree() error = %v", err)
+		}
+
+		if n := strings.Count(buf.String(), "Mem:"); n != 2 {
+			t.Errorf("RunFree() with -c 2 printed %d samples, want 2", n)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := RunFree(ctx, &buf, FreeOptions{Seconds: 60}); err != nil {
+			t.Fatalf("RunFree() error = %v", err)
+		}
+
+		if n := strings.Count(buf.String(), "Mem:"); n != 1 {
+			t.Errorf("cancelled RunFree() printed %d samples, want 1", n)
+		}
+	})
+
+	t.Run("negative count", func(t *testing.T) {
+		err := RunFree(context.Background(), &bytes.Buffer{}, FreeOptions{Count: -1})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("RunFree() error = %v, want invalid input", err)
+		}
+	})
+
 	t.Run("with total", func(t *testing.T) {
 		var buf bytes.Buffer
 
-		err := RunFree(&buf, FreeOptions{Total: true})
+		err := RunFree(context.Background(), &buf, FreeOptions{Total: true})
 		if err != nil {
 			t.Fatalf("RunFree() error = %v", err)
 		}
@@ -135,4 +188,8 @@ func TestGetMemInfo(t *testing.T) {
 	if info.MemFree > info.MemTotal {
 		t.Error("GetMemInfo() MemFree should not exceed MemTotal")
 	}
+
+	if info.MemUsed > info.MemTotal {
+		t.Error("GetMemInfo() MemUsed should not exceed MemTotal")
+	}
 }
@@ -2,14 +2,17 @@ package grep
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/search/ahocorasick"
 	pkggrep "github.com/inovacc/omni/pkg/search/grep"
 )
 
@@ -34,6 +37,8 @@ type GrepOptions struct {
 	AfterContext   int           // -A: print NUM lines of trailing context
 	MaxCount       int           // -m: stop after NUM matches
 	Recursive      bool          // -r/-R: search recursively
+	Patterns       []string      // -e: patterns searched in addition to PATTERN
+	PatternFiles   []string      // -f: files with one pattern per line ("-" for stdin)
 	OutputFormat   output.Format // output format
 }
 
@@ -58,13 +63,32 @@ type GrepOutput struct {
 
 // RunGrep executes the grep command
 // r is the default input reader (used when args is empty or contains "-")
+// pattern may be empty when the patterns come from -e or -f
 func RunGrep(w io.Writer, r io.Reader, pattern string, args []string, opts GrepOptions) error {
-	if pattern == "" {
+	patterns := opts.Patterns
+	if pattern != "" {
+		patterns = append([]string{pattern}, patterns...)
+	}
+
+	if len(patterns) == 0 && len(opts.PatternFiles) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "grep: no pattern specified")
 	}
 
+	if len(opts.PatternFiles) > 0 {
+		fromFiles, err := pkggrep.ReadPatternFiles(opts.PatternFiles, r)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("grep: %v", err))
+			}
+
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("grep: %v", err))
+		}
+
+		patterns = append(patterns, fromFiles...)
+	}
+
 	// Compile the pattern
-	re, err := compilePattern(pattern, opts)
+	m, err := compileMatcher(patterns, opts)
 	if err != nil {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("grep: %v", err))
 	}
@@ -98,7 +122,7 @@ func RunGrep(w io.Writer, r io.Reader, pattern string, args []string, opts GrepO
 			filename = "(standard input)"
 		}
 
-		matches, hasMatch, results, err := grepReader(w, src.Reader, filename, re, opts, showFilename, jsonMode)
+		matches, hasMatch, results, err := grepReader(w, src.Reader, filename, m, opts, showFilename, jsonMode)
 		if err != nil {
 			return err
 		}
@@ -127,7 +151,7 @@ func RunGrep(w io.Writer, r io.Reader, pattern string, args []string, opts GrepO
 		}
 
 		grepOut := GrepOutput{
-			Pattern:        pattern,
+			Pattern:        strings.Join(patterns, "\n"),
 			Files:          fileList,
 			Matches:        allResults,
 			TotalCount:     totalMatches,
@@ -152,7 +176,22 @@ func RunGrep(w io.Writer, r io.Reader, pattern string, args []string, opts GrepO
 	return nil
 }
 
-func compilePattern(pattern string, opts GrepOptions) (*regexp.Regexp, error) {
+// matcher selects lines. Sets of fixed strings are scanned with an
+// Aho-Corasick automaton; re is still used to extract the matched text.
+type matcher struct {
+	re *regexp.Regexp
+	ac *ahocorasick.Matcher
+}
+
+func (m matcher) match(line string) bool {
+	if m.ac != nil {
+		return m.ac.Match(line)
+	}
+
+	return m.re.MatchString(line)
+}
+
+func compileMatcher(patterns []string, opts GrepOptions) (matcher, error) {
 	pkgOpts := pkggrep.Options{
 		IgnoreCase:     opts.IgnoreCase,
 		InvertMatch:    false, // not used for pattern compilation
@@ -162,10 +201,27 @@ func compilePattern(pattern string, opts GrepOptions) (*regexp.Regexp, error) {
 		ExtendedRegexp: opts.ExtendedRegexp,
 	}
 
-	return pkggrep.CompilePattern(pattern, pkgOpts)
+	re, err := pkggrep.CompilePatterns(patterns, pkgOpts)
+	if err != nil {
+		return matcher{}, err
+	}
+
+	m := matcher{re: re}
+
+	if opts.FixedStrings && !opts.WordRegexp && !opts.LineRegexp && len(patterns) > 1 {
+		var acOpts []ahocorasick.Option
+		if opts.IgnoreCase {
+			acOpts = append(acOpts, ahocorasick.WithIgnoreCase())
+		}
+
+		m.ac = ahocorasick.New(patterns, acOpts...)
+	}
+
+	return m, nil
 }
 
-func grepReader(w io.Writer, r io.Reader, filename string, re *regexp.Regexp, opts GrepOptions, showFilename bool, jsonMode bool) (int, bool, []GrepResult, error) {
+func grepReader(w io.Writer, r io.Reader, filename string, m matcher, opts GrepOptions, showFilename bool, jsonMode bool) (int, bool, []GrepResult, error) {
+	re := m.re
 	scanner := bufio.NewScanner(r)
 	lineNum := 0
 	matchCount := 0
@@ -181,7 +237,7 @@ func grepReader(w io.Writer, r io.Reader, filename string, re *regexp.Regexp, op
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
-		matches := re.MatchString(line)
+		matches := m.match(line)
 
 		if opts.InvertMatch {
 			matches = !matches
@@ -615,6 +615,66 @@ func TestRunGrep(t *testing.T) {
 	})
 }
 
+func TestRunGrep_MultiplePatterns(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "log.txt")
+
+	if err := os.WriteFile(file, []byte("alpha.one\nbeta\nGAMMA two\ndelta\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	patternFile := filepath.Join(dir, "patterns")
+	if err := os.WriteFile(patternFile, []byte("alpha.one\ngamma\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    GrepOptions
+		want    string
+	}{
+		{"pattern and -e", "beta", GrepOptions{Patterns: []string{"^del"}}, "beta\ndelta\n"},
+		{"fixed -e set", "", GrepOptions{FixedStrings: true, Patterns: []string{"l.h", "two"}}, "GAMMA two\n"},
+		{"fixed -f set ignore case", "", GrepOptions{FixedStrings: true, IgnoreCase: true, PatternFiles: []string{patternFile}}, "alpha.one\nGAMMA two\n"},
+		{"-f set count", "", GrepOptions{IgnoreCase: true, Count: true, PatternFiles: []string{patternFile}}, "2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := RunGrep(&buf, nil, tt.pattern, []string{file}, tt.opts); err != nil {
+				t.Fatalf("RunGrep() error = %v", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("RunGrep() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("patterns from stdin", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunGrep(&buf, strings.NewReader("delta\n"), "", []string{file}, GrepOptions{PatternFiles: []string{"-"}})
+		if err != nil {
+			t.Fatalf("RunGrep() error = %v", err)
+		}
+
+		if buf.String() != "delta\n" {
+			t.Errorf("RunGrep() = %q", buf.String())
+		}
+	})
+
+	t.Run("missing pattern file", func(t *testing.T) {
+		err := RunGrep(&bytes.Buffer{}, nil, "", []string{file}, GrepOptions{PatternFiles: []string{filepath.Join(dir, "nope")}})
+		if !cmderr.IsNotFound(err) {
+			t.Errorf("RunGrep() error = %v, want not found", err)
+		}
+	})
+}
+
 func TestGrep(t *testing.T) {
 	t.Run("simple grep", func(t *testing.T) {
 		lines := []string{"hello world", "foo bar", "hello again"}
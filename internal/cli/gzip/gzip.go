@@ -33,6 +33,7 @@ type GzipOptions struct {
 	Stdout     bool // -c: write to stdout
 	Verbose    bool // -v: verbose
 	Level      int  // -1 to -9: compression level
+	Threads    int  // --threads: compression workers (0 = one per CPU)
 }
 
 // RunGzip compresses or decompresses files
@@ -47,7 +48,7 @@ func RunGzip(w io.Writer, args []string, opts GzipOptions) error {
 			return gunzipReader(w, os.Stdin)
 		}
 
-		return gzipReader(w, os.Stdin, opts.Level)
+		return gzipReader(w, os.Stdin, opts.Level, opts.Threads)
 	}
 
 	for _, path := range args {
@@ -65,19 +66,10 @@ func RunGzip(w io.Writer, args []string, opts GzipOptions) error {
 	return nil
 }
 
-func gzipReader(w io.Writer, r io.Reader, level int) error {
-	gw, err := gzip.NewWriterLevel(w, level)
-	if err != nil {
-		return err
-	}
-
-	_, err = io.Copy(gw, r)
-	if err != nil {
-		_ = gw.Close()
-		return err
-	}
-
-	return gw.Close()
+// gzipReader compresses r to w on up to threads goroutines; see
+// compressParallel.
+func gzipReader(w io.Writer, r io.Reader, level, threads int) error {
+	return compressParallel(w, r, level, threads)
 }
 
 func gunzipReader(w io.Writer, r io.Reader) error {
@@ -129,7 +121,7 @@ func gzipFile(w io.Writer, path string, opts GzipOptions) error {
 			_, _ = fmt.Fprintf(os.Stderr, "%s:\t", path)
 		}
 
-		return gzipReader(w, inFile, opts.Level)
+		return gzipReader(w, inFile, opts.Level, opts.Threads)
 	}
 
 	// Check if output exists
@@ -149,7 +141,7 @@ func gzipFile(w io.Writer, path string, opts GzipOptions) error {
 
 	defer func() { _ = outFile.Close() }()
 
-	if err := gzipReader(outFile, inFile, opts.Level); err != nil {
+	if err := gzipReader(outFile, inFile, opts.Level, opts.Threads); err != nil {
 		return err
 	}
 
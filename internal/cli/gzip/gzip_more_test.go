@@ -87,7 +87,7 @@ func TestGzipReaderRoundTrip(t *testing.T) {
 	content := bytes.Repeat([]byte("abcd"), 1000)
 	for _, level := range []int{1, 5, 9} {
 		var comp bytes.Buffer
-		if err := gzipReader(&comp, bytes.NewReader(content), level); err != nil {
+		if err := gzipReader(&comp, bytes.NewReader(content), level, 0); err != nil {
 			t.Fatalf("level %d gzipReader: %v", level, err)
 		}
 		var decomp bytes.Buffer
@@ -186,7 +186,7 @@ func TestGunzipForceOverwrite(t *testing.T) {
 	// Build a real .gz file.
 	gzPath := filepath.Join(dir, "data.txt.gz")
 	var comp bytes.Buffer
-	if err := gzipReader(&comp, bytes.NewReader([]byte("decompressed\n")), 6); err != nil {
+	if err := gzipReader(&comp, bytes.NewReader([]byte("decompressed\n")), 6, 0); err != nil {
 		t.Fatal(err)
 	}
 	if err := os.WriteFile(gzPath, comp.Bytes(), 0o644); err != nil {
@@ -220,7 +220,7 @@ func TestDecompressBombGuard(t *testing.T) {
 	defer func() { decompressByteCap = orig }()
 
 	var comp bytes.Buffer
-	if err := gzipReader(&comp, bytes.NewReader(bytes.Repeat([]byte("z"), 1000)), 9); err != nil {
+	if err := gzipReader(&comp, bytes.NewReader(bytes.Repeat([]byte("z"), 1000)), 9, 0); err != nil {
 		t.Fatal(err)
 	}
 	if err := gunzipReader(&bytes.Buffer{}, &comp); err == nil {
@@ -322,7 +322,7 @@ func TestGzipReader(t *testing.T) {
 
 	input := bytes.NewBufferString("test data")
 
-	err := gzipReader(&buf, input, gzip.DefaultCompression)
+	err := gzipReader(&buf, input, gzip.DefaultCompression, 0)
 	if err != nil {
 		t.Fatalf("gzipReader() error = %v", err)
 	}
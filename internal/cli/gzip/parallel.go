@@ -0,0 +1,210 @@
+package gzip
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"runtime"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// blockSize is the amount of input compressed as one unit by a worker.
+// Each block but the first is primed with the last 32 KiB of the block
+// before it (the whole deflate window), so splitting costs only a few bytes
+// of sync marker per block, as in pigz and pgzip.
+const blockSize = 1 << 20
+
+// windowSize is the deflate history a block can refer back to.
+const windowSize = 32 << 10
+
+// block is one unit of work: its input, the history it may refer to, and
+// where the worker delivers the compressed bytes.
+type block struct {
+	data []byte
+	dict []byte
+	last bool
+	out  chan blockResult
+	err  error // read error; no compression is done for this block
+}
+
+type blockResult struct {
+	data       []byte
+	compressed []byte
+	err        error
+}
+
+// compressParallel writes r to w as a single gzip member, compressing
+// blockSize pieces on up to threads goroutines. Blocks are cut at fixed
+// offsets and each is primed with the tail of the previous one, so the
+// output depends only on the input and level, never on threads: the same
+// file compresses to the same bytes on any machine. An input of one block
+// or less compresses exactly as compress/gzip does.
+//
+// At most threads blocks are in flight, which bounds memory to about
+// (threads+2) × blockSize of input plus its compressed form, whatever the
+// size of r.
+func compressParallel(w io.Writer, r io.Reader, level, threads int) error {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("gzip: invalid compression level: %d", level))
+	}
+
+	if threads < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("gzip: invalid thread count: %d", threads))
+	}
+
+	if threads == 0 {
+		threads = runtime.GOMAXPROCS(0)
+	}
+
+	if err := writeHeader(w, level); err != nil {
+		return err
+	}
+
+	pending := make(chan *block, threads)
+	stop := make(chan struct{})
+
+	defer close(stop)
+
+	go readBlocks(r, level, pending, stop)
+
+	var (
+		crc  uint32
+		size uint32
+	)
+
+	for b := range pending {
+		if b.err != nil {
+			return b.err
+		}
+
+		res := <-b.out
+		if res.err != nil {
+			return res.err
+		}
+
+		crc = crc32.Update(crc, crc32.IEEETable, res.data)
+		size += uint32(len(res.data)) //nolint:gosec // ISIZE is the input size modulo 2^32 (RFC 1952)
+
+		if _, err := w.Write(res.compressed); err != nil {
+			return err
+		}
+	}
+
+	var trailer [8]byte
+
+	binary.LittleEndian.PutUint32(trailer[0:4], crc)
+	binary.LittleEndian.PutUint32(trailer[4:8], size)
+
+	_, err := w.Write(trailer[:])
+
+	return err
+}
+
+// writeHeader writes the fixed gzip header compress/gzip writes when no
+// name, comment or time is set.
+func writeHeader(w io.Writer, level int) error {
+	header := [10]byte{0: 0x1f, 1: 0x8b, 2: 8, 9: 255}
+
+	switch level {
+	case gzip.BestCompression:
+		header[8] = 2
+	case gzip.BestSpeed:
+		header[8] = 4
+	}
+
+	_, err := w.Write(header[:])
+
+	return err
+}
+
+// readBlocks cuts r into blocks, starts a worker for each and queues the
+// blocks in input order. The queue holds at most cap(pending) blocks, so
+// reading stays ahead of writing by a bounded amount. It reads one block
+// ahead to know which block is the last.
+func readBlocks(r io.Reader, level int, pending chan<- *block, stop <-chan struct{}) {
+	defer close(pending)
+
+	queue := func(b *block) bool {
+		select {
+		case pending <- b:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+
+	read := func() ([]byte, error) {
+		buf := make([]byte, blockSize)
+
+		n, err := io.ReadFull(r, buf)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = nil
+		}
+
+		return buf[:n], err
+	}
+
+	current, err := read()
+	if err != nil {
+		queue(&block{err: err})
+		return
+	}
+
+	var dict []byte
+
+	for {
+		var next []byte
+
+		if len(current) == blockSize {
+			if next, err = read(); err != nil {
+				queue(&block{err: err})
+				return
+			}
+		}
+
+		b := &block{data: current, dict: dict, last: len(next) == 0, out: make(chan blockResult, 1)}
+
+		go compressBlock(b, level)
+
+		if !queue(b) || b.last {
+			return
+		}
+
+		dict = current[len(current)-windowSize:]
+		current = next
+	}
+}
+
+// compressBlock deflates one block. All blocks but the last end with a
+// sync flush, which leaves the stream byte-aligned so the next block's
+// output can follow it directly; the last block ends the stream.
+func compressBlock(b *block, level int) {
+	var buf bytes.Buffer
+
+	buf.Grow(len(b.data)/2 + 64)
+
+	fw, err := flate.NewWriterDict(&buf, level, b.dict)
+	if err != nil {
+		b.out <- blockResult{err: err}
+		return
+	}
+
+	if _, err := fw.Write(b.data); err != nil {
+		b.out <- blockResult{err: err}
+		return
+	}
+
+	if b.last {
+		err = fw.Close()
+	} else {
+		err = fw.Flush()
+	}
+
+	b.out <- blockResult{data: b.data, compressed: buf.Bytes(), err: err}
+}
@@ -0,0 +1,134 @@
+package gzip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"testing"
+	"testing/iotest"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// testInput is compressible but not trivially so: random words from a small
+// vocabulary, which makes matches cross block boundaries.
+func testInput(n int) []byte {
+	words := []string{"alpha ", "bravo ", "charlie ", "delta ", "echo\n", "foxtrot ", "golf "}
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var buf bytes.Buffer
+	for buf.Len() < n {
+		buf.WriteString(words[rng.IntN(len(words))])
+	}
+
+	return buf.Bytes()[:n]
+}
+
+func TestCompressParallel_RoundTrip(t *testing.T) {
+	sizes := map[string]int{
+		"empty":            0,
+		"small":            100,
+		"one block":        blockSize,
+		"one block plus 1": blockSize + 1,
+		"several blocks":   3*blockSize + 123,
+	}
+
+	for name, size := range sizes {
+		t.Run(name, func(t *testing.T) {
+			in := testInput(size)
+
+			var reference []byte
+
+			for _, threads := range []int{1, 2, 8} {
+				var out bytes.Buffer
+				if err := compressParallel(&out, bytes.NewReader(in), gzip.DefaultCompression, threads); err != nil {
+					t.Fatalf("threads=%d: error = %v", threads, err)
+				}
+
+				gr, err := gzip.NewReader(bytes.NewReader(out.Bytes()))
+				if err != nil {
+					t.Fatalf("threads=%d: invalid gzip: %v", threads, err)
+				}
+
+				got, err := io.ReadAll(gr)
+				if err != nil {
+					t.Fatalf("threads=%d: decompress: %v", threads, err)
+				}
+
+				if !bytes.Equal(got, in) {
+					t.Fatalf("threads=%d: round trip mismatch: %d bytes, want %d", threads, len(got), len(in))
+				}
+
+				if reference == nil {
+					reference = out.Bytes()
+				} else if !bytes.Equal(out.Bytes(), reference) {
+					t.Errorf("threads=%d: output differs from threads=1", threads)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressParallel_SingleBlockMatchesStdlib(t *testing.T) {
+	in := testInput(200 << 10)
+
+	for _, level := range []int{gzip.DefaultCompression, gzip.BestSpeed, gzip.BestCompression, gzip.NoCompression} {
+		var want bytes.Buffer
+
+		gw, _ := gzip.NewWriterLevel(&want, level)
+		_, _ = gw.Write(in)
+		_ = gw.Close()
+
+		var got bytes.Buffer
+		if err := compressParallel(&got, bytes.NewReader(in), level, 4); err != nil {
+			t.Fatalf("level %d: error = %v", level, err)
+		}
+
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Errorf("level %d: output differs from compress/gzip", level)
+		}
+	}
+}
+
+func TestCompressParallel_Errors(t *testing.T) {
+	in := testInput(10)
+
+	if err := compressParallel(io.Discard, bytes.NewReader(in), 12, 1); !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad level: error = %v", err)
+	}
+
+	if err := compressParallel(io.Discard, bytes.NewReader(in), 6, -1); !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad threads: error = %v", err)
+	}
+
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(bytes.NewReader(testInput(2*blockSize+5)), iotest.ErrReader(readErr))
+
+	if err := compressParallel(io.Discard, r, 6, 2); !errors.Is(err, readErr) {
+		t.Errorf("read error: error = %v, want %v", err, readErr)
+	}
+
+	w := &failingWriter{after: 3}
+	if err := compressParallel(w, bytes.NewReader(testInput(4*blockSize)), 6, 2); !errors.Is(err, errWriteFailed) {
+		t.Errorf("write error: error = %v", err)
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// failingWriter fails every write after the first after writes.
+type failingWriter struct {
+	after int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.after == 0 {
+		return 0, errWriteFailed
+	}
+
+	f.after--
+
+	return len(p), nil
+}
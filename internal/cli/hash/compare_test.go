@@ -0,0 +1,113 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestRunCompare(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	a := write("a", "hello world\n")
+	b := write("b", "hello world\n")
+	c := write("c", "hello there\n")
+
+	t.Run("identical", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunCompare(&buf, nil, []string{a, b}, CompareOptions{}); err != nil {
+			t.Fatalf("RunCompare() error = %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "are identical") {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+
+	t.Run("differ", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunCompare(&buf, nil, []string{a, c}, CompareOptions{Algorithm: "blake2b"})
+		if cmderr.ExitCodeFor(err) != 1 {
+			t.Errorf("RunCompare() error = %v, want exit status 1", err)
+		}
+
+		if !strings.Contains(buf.String(), "differ") {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunCompare(&buf, nil, []string{a, c}, CompareOptions{Quiet: true}); err == nil {
+			t.Error("RunCompare() should fail for differing files")
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("quiet output = %q", buf.String())
+		}
+	})
+
+	t.Run("stdin json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunCompare(&buf, strings.NewReader("hello world\n"), []string{"-", b}, CompareOptions{OutputFormat: output.FormatJSON})
+		if err != nil {
+			t.Fatalf("RunCompare() error = %v", err)
+		}
+
+		var res CompareResult
+		if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		if !res.Equal || res.Hash1 != res.Hash2 || res.Algorithm != "sha256" {
+			t.Errorf("result = %+v", res)
+		}
+	})
+}
+
+func TestRunCompareErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	a := filepath.Join(dir, "a")
+	if err := os.WriteFile(a, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		opts  CompareOptions
+		check func(error) bool
+	}{
+		{"one file", []string{a}, CompareOptions{}, cmderr.IsInvalidInput},
+		{"both stdin", []string{"-", "-"}, CompareOptions{}, cmderr.IsInvalidInput},
+		{"bad algorithm", []string{a, a}, CompareOptions{Algorithm: "rot13"}, cmderr.IsInvalidInput},
+		{"missing file", []string{a, filepath.Join(dir, "missing")}, CompareOptions{}, cmderr.IsNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RunCompare(&bytes.Buffer{}, strings.NewReader(""), tt.args, tt.opts)
+			if !tt.check(err) {
+				t.Errorf("RunCompare() error = %v", err)
+			}
+		})
+	}
+}
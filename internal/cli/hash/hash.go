@@ -1,14 +1,11 @@
 package hash
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
-	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 	"github.com/inovacc/omni/pkg/hashutil"
 )
@@ -23,6 +20,9 @@ type HashOptions struct {
 	Status       bool          // --status: don't output anything, status code shows success
 	Warn         bool          // -w: warn about improperly formatted checksum lines
 	Recursive    bool          // -r: hash files recursively in directories
+	Manifest     bool          // --create-manifest: hash directory trees into a checksum manifest
+	Tag          bool          // --tag: print BSD-style "ALGO (FILE) = HASH" lines
+	Jobs         int           // -j: files hashed in parallel by --create-manifest and --check
 	OutputFormat output.Format // output format (text, json, table)
 }
 
@@ -50,6 +50,10 @@ func RunHash(w io.Writer, args []string, opts HashOptions) error {
 		return verifyChecksums(w, args, opts)
 	}
 
+	if opts.Manifest {
+		return createManifest(w, args, opts)
+	}
+
 	return computeHashes(w, args, opts)
 }
 
@@ -173,6 +177,11 @@ func hashFile(w io.Writer, path string, opts HashOptions) error {
 		return err
 	}
 
+	if opts.Tag {
+		_, _ = fmt.Fprintf(w, "%s (%s) = %s\n", hashutil.ManifestTag(algo), path, hashStr)
+		return nil
+	}
+
 	mode := " "
 	if opts.Binary {
 		mode = "*"
@@ -183,94 +192,6 @@ func hashFile(w io.Writer, path string, opts HashOptions) error {
 	return nil
 }
 
-func verifyChecksums(w io.Writer, args []string, opts HashOptions) error {
-	if len(args) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "hash: no checksum file specified")
-	}
-
-	algo := hashutil.Algorithm(opts.Algorithm)
-
-	var failed, notFound, malformed int
-
-	for _, checksumFile := range args {
-		f, err := os.Open(checksumFile)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("hash: %s", err))
-			}
-			return fmt.Errorf("hash: %w", err)
-		}
-
-		content, err := io.ReadAll(f)
-		_ = f.Close()
-
-		if err != nil {
-			return fmt.Errorf("hash: %w", err)
-		}
-
-		lines := strings.SplitSeq(string(content), "\n")
-		for line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
-
-			parts := strings.SplitN(line, " ", 2)
-			if len(parts) != 2 {
-				if opts.Warn {
-					_, _ = fmt.Fprintf(os.Stderr, "hash: %s: improperly formatted checksum line\n", line)
-				}
-
-				malformed++
-
-				continue
-			}
-
-			expectedHash := parts[0]
-			filename := strings.TrimLeft(parts[1], " *")
-
-			actualHash, err := hashutil.HashFile(filename, algo)
-			if err != nil {
-				if !opts.Status {
-					_, _ = fmt.Fprintf(w, "%s: FAILED open or read\n", filename)
-				}
-
-				notFound++
-
-				continue
-			}
-
-			if actualHash == expectedHash {
-				if !opts.Quiet && !opts.Status {
-					_, _ = fmt.Fprintf(w, "%s: OK\n", filename)
-				}
-			} else {
-				if !opts.Status {
-					_, _ = fmt.Fprintf(w, "%s: FAILED\n", filename)
-				}
-
-				failed++
-			}
-		}
-	}
-
-	if failed > 0 || notFound > 0 {
-		if !opts.Status {
-			if failed > 0 {
-				_, _ = fmt.Fprintf(os.Stderr, "hash: WARNING: %d computed checksum did NOT match\n", failed)
-			}
-
-			if notFound > 0 {
-				_, _ = fmt.Fprintf(os.Stderr, "hash: WARNING: %d listed file could not be read\n", notFound)
-			}
-		}
-
-		return cmderr.Wrap(cmderr.ErrConflict, "hash: verification failed")
-	}
-
-	return nil
-}
-
 // Convenience functions for specific algorithms
 
 // RunMD5Sum computes MD5 hashes (md5sum compatibility)
@@ -464,3 +464,40 @@ func TestRunSHA512Sum(t *testing.T) {
 		t.Logf("SHA512 hash length: %d (expected 128)", len(parts[0]))
 	}
 }
+
+func TestRunHashFastAlgorithms(t *testing.T) {
+	dir := t.TempDir()
+
+	testFile := filepath.Join(dir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for algo, want := range map[string]string{
+		"blake3":      "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+		"blake2b-512": "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		"xxh64":       "44bc2cf5ad770999",
+		"xxh3":        "78af5f94892f3950",
+	} {
+		var buf bytes.Buffer
+
+		if err := RunHash(&buf, []string{testFile}, HashOptions{Algorithm: algo}); err != nil {
+			t.Fatalf("RunHash(%s) error = %v", algo, err)
+		}
+
+		if got := buf.String(); got != want+"  "+testFile+"\n" {
+			t.Errorf("RunHash(%s) = %q, want digest %s", algo, got, want)
+		}
+
+		sums := filepath.Join(dir, algo+".sums")
+		if err := os.WriteFile(sums, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		buf.Reset()
+
+		if err := RunHash(&buf, []string{sums}, HashOptions{Algorithm: algo, Check: true}); err != nil {
+			t.Errorf("RunHash(%s, -c) error = %v\n%s", algo, err, buf.String())
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package hash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+// CheckResult is the verification of one checksum line
+type CheckResult struct {
+	Path      string `json:"path"`
+	Algorithm string `json:"algorithm"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual,omitempty"`
+	Status    string `json:"status"` // OK, FAILED or MISSING
+	Error     string `json:"error,omitempty"`
+}
+
+// ChecksResult is the JSON output of hash --check
+type ChecksResult struct {
+	Files     []CheckResult `json:"files"`
+	OK        int           `json:"ok"`
+	Failed    int           `json:"failed"`
+	Missing   int           `json:"missing"`
+	Malformed int           `json:"malformed"`
+}
+
+// createManifest hashes every file under the paths in args (default ".")
+// with opts.Jobs workers and prints a manifest, GNU style or, with --tag,
+// BSD style. Unreadable files are reported on stderr and make it fail
+// after the manifest of the rest is printed.
+func createManifest(w io.Writer, args []string, opts HashOptions) error {
+	algo := hashutil.Algorithm(strings.ToLower(opts.Algorithm))
+	if !slices.Contains(hashutil.Algorithms(), algo) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("hash: unsupported algorithm %q", opts.Algorithm))
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	m, err := hashutil.CreateManifest(context.Background(), args, hashutil.ManifestOptions{Algorithm: algo, Workers: opts.Jobs})
+	if m == nil {
+		return fmt.Errorf("hash: %w", err)
+	}
+
+	if err != nil {
+		for _, e := range unjoin(err) {
+			_, _ = fmt.Fprintf(os.Stderr, "hash: %v\n", e)
+		}
+	}
+
+	if f := output.New(w, opts.OutputFormat); f.IsJSON() {
+		res := HashesResult{Hashes: []HashResult{}}
+		for _, e := range m.Entries {
+			res.Hashes = append(res.Hashes, HashResult{Path: e.Path, Hash: e.Digest, Algorithm: string(e.Algorithm), Size: e.Size})
+		}
+
+		res.Count = len(res.Hashes)
+
+		if perr := f.Print(res); perr != nil {
+			return perr
+		}
+	} else {
+		style := hashutil.StyleGNU
+		if opts.Tag {
+			style = hashutil.StyleBSD
+		}
+
+		if werr := hashutil.WriteManifest(w, m, style); werr != nil {
+			return fmt.Errorf("hash: %w", werr)
+		}
+	}
+
+	if err != nil {
+		return cmderr.PartialFailure(1, "hash: some files could not be read")
+	}
+
+	return nil
+}
+
+// verifyChecksums checks every line of the checksum files in args, GNU or
+// BSD style, hashing opts.Jobs files in parallel. GNU-style lines use
+// opts.Algorithm; BSD-style lines name their own.
+func verifyChecksums(w io.Writer, args []string, opts HashOptions) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "hash: no checksum file specified")
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	res := ChecksResult{Files: []CheckResult{}}
+
+	for _, checksumFile := range args {
+		file, err := os.Open(checksumFile)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("hash: %s", err))
+			}
+
+			return fmt.Errorf("hash: %w", err)
+		}
+
+		m, err := hashutil.ParseManifest(file)
+		_ = file.Close()
+
+		if err != nil {
+			return fmt.Errorf("hash: %s: %w", checksumFile, err)
+		}
+
+		res.Malformed += len(m.Malformed)
+
+		if opts.Warn {
+			for _, n := range m.Malformed {
+				_, _ = fmt.Fprintf(os.Stderr, "hash: %s: %d: improperly formatted checksum line\n", checksumFile, n)
+			}
+		}
+
+		if len(m.Entries) == 0 {
+			if !opts.Status {
+				_, _ = fmt.Fprintf(os.Stderr, "hash: %s: no properly formatted checksum lines found\n", checksumFile)
+			}
+
+			continue
+		}
+
+		results, err := hashutil.VerifyManifest(context.Background(), m, hashutil.ManifestOptions{Algorithm: hashutil.Algorithm(opts.Algorithm), Workers: opts.Jobs})
+		if err != nil {
+			return fmt.Errorf("hash: %w", err)
+		}
+
+		for _, r := range results {
+			cr := CheckResult{Path: r.Path, Algorithm: string(r.Algorithm), Expected: r.Digest, Actual: r.Actual, Status: string(r.Status)}
+
+			switch r.Status {
+			case hashutil.StatusOK:
+				res.OK++
+
+				if !opts.Quiet && !opts.Status && !f.IsJSON() {
+					_, _ = fmt.Fprintf(w, "%s: OK\n", r.Path)
+				}
+			case hashutil.StatusFailed:
+				res.Failed++
+
+				if !opts.Status && !f.IsJSON() {
+					_, _ = fmt.Fprintf(w, "%s: FAILED\n", r.Path)
+				}
+			default:
+				res.Missing++
+				cr.Error = r.Err.Error()
+
+				if !opts.Status && !f.IsJSON() {
+					_, _ = fmt.Fprintf(w, "%s: FAILED open or read\n", r.Path)
+				}
+			}
+
+			res.Files = append(res.Files, cr)
+		}
+	}
+
+	if f.IsJSON() && !opts.Status {
+		if err := f.Print(res); err != nil {
+			return err
+		}
+	}
+
+	if res.Failed > 0 || res.Missing > 0 {
+		if !opts.Status && !f.IsJSON() {
+			for _, warning := range checkWarnings(res.Failed, res.Missing) {
+				_, _ = fmt.Fprintln(os.Stderr, "hash: WARNING: "+warning)
+			}
+		}
+
+		return cmderr.Wrap(cmderr.ErrConflict, "hash: verification failed")
+	}
+
+	return nil
+}
+
+// checkWarnings returns the --check summary lines for failed mismatches
+// and missing files, with the nouns in the plural as sha256sum has them.
+func checkWarnings(failed, missing int) []string {
+	var warnings []string
+
+	if failed > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d computed %s did NOT match", failed, plural(failed, "checksum", "checksums")))
+	}
+
+	if missing > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d listed %s could not be read", missing, plural(missing, "file", "files")))
+	}
+
+	return warnings
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+
+	return many
+}
+
+// unjoin returns the errors joined in err by errors.Join.
+func unjoin(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+
+	return []error{err}
+}
@@ -0,0 +1,125 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+func TestCreateManifestAndCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "a\n", "sub/b.txt": "b\n"})
+
+	for _, tag := range []bool{false, true} {
+		var sums bytes.Buffer
+		if err := RunHash(&sums, []string{dir}, HashOptions{Algorithm: "sha512", Manifest: true, Tag: tag, Jobs: 2}); err != nil {
+			t.Fatalf("--create-manifest error = %v", err)
+		}
+
+		if lines := strings.Count(sums.String(), "\n"); lines != 2 {
+			t.Fatalf("manifest has %d lines:\n%s", lines, sums.String())
+		}
+
+		if tag != strings.HasPrefix(sums.String(), "SHA512 (") {
+			t.Errorf("tag=%v: manifest = %q", tag, sums.String())
+		}
+
+		sumsFile := filepath.Join(t.TempDir(), "SUMS")
+		if err := os.WriteFile(sumsFile, sums.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var out bytes.Buffer
+		if err := RunHash(&out, []string{sumsFile}, HashOptions{Algorithm: "sha512", Check: true}); err != nil {
+			t.Fatalf("--check error = %v\n%s", err, out.String())
+		}
+
+		if strings.Count(out.String(), ": OK\n") != 2 {
+			t.Errorf("check output = %q", out.String())
+		}
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"ok": "ok", "bad": "changed"})
+
+	sums := hashutil.HashString("ok", hashutil.SHA256) + "  " + filepath.Join(dir, "ok") + "\n" +
+		"MD5 (" + filepath.Join(dir, "bad") + ") = " + hashutil.HashString("original", hashutil.MD5) + "\n" +
+		hashutil.HashString("x", hashutil.SHA256) + "  " + filepath.Join(dir, "gone") + "\n" +
+		"garbage\n"
+
+	sumsFile := filepath.Join(t.TempDir(), "SUMS")
+	if err := os.WriteFile(sumsFile, []byte(sums), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+
+	err := RunHash(&out, []string{sumsFile}, HashOptions{Algorithm: "sha256", Check: true, OutputFormat: output.FormatJSON})
+	if !cmderr.IsConflict(err) {
+		t.Errorf("RunHash() error = %v, want conflict", err)
+	}
+
+	var res ChecksResult
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+
+	if res.OK != 1 || res.Failed != 1 || res.Missing != 1 || res.Malformed != 1 || len(res.Files) != 3 {
+		t.Errorf("result = %+v", res)
+	}
+
+	if bad := res.Files[1]; bad.Status != "FAILED" || bad.Algorithm != "md5" || bad.Actual != hashutil.HashString("changed", hashutil.MD5) {
+		t.Errorf("bad file = %+v", bad)
+	}
+
+	if res.Files[2].Status != "MISSING" || res.Files[2].Error == "" {
+		t.Errorf("missing file = %+v", res.Files[2])
+	}
+}
+
+func TestCreateManifestErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"f": "x"})
+
+	if err := RunHash(&bytes.Buffer{}, []string{dir}, HashOptions{Algorithm: "rot13", Manifest: true}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("unknown algorithm: error = %v", err)
+	}
+
+	var out bytes.Buffer
+
+	err := RunHash(&out, []string{dir, filepath.Join(dir, "missing")}, HashOptions{Algorithm: "sha256", Manifest: true})
+	if !errors.Is(err, cmderr.ErrPartial) {
+		t.Errorf("missing path: error = %v", err)
+	}
+
+	if !strings.HasSuffix(out.String(), "  "+filepath.ToSlash(filepath.Join(dir, "f"))+"\n") {
+		t.Errorf("manifest of the readable files not printed: %q", out.String())
+	}
+}
+
+func TestCheckWarnings(t *testing.T) {
+	tests := []struct {
+		failed, missing int
+		want            []string
+	}{
+		{0, 0, nil},
+		{1, 1, []string{"1 computed checksum did NOT match", "1 listed file could not be read"}},
+		{2, 3, []string{"2 computed checksums did NOT match", "3 listed files could not be read"}},
+	}
+
+	for _, tt := range tests {
+		if got := checkWarnings(tt.failed, tt.missing); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("checkWarnings(%d, %d) = %q, want %q", tt.failed, tt.missing, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package hash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+// DefaultTreeInterval is how often hash tree --watch rescans by default.
+const DefaultTreeInterval = 2 * time.Second
+
+// TreeOptions configures the hash tree command behavior
+type TreeOptions struct {
+	Algorithm    string        // -a: hash algorithm (default sha256)
+	Watch        bool          // --watch: keep watching and print change events
+	Interval     time.Duration // --interval: time between rescans in watch mode
+	OutputFormat output.Format // output format (text, json)
+}
+
+// TreeFile is one file of a tree manifest
+type TreeFile struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// TreeManifest is the JSON output of hash tree
+type TreeManifest struct {
+	Root      string     `json:"root"`
+	Algorithm string     `json:"algorithm"`
+	Digest    string     `json:"digest"`
+	Files     []TreeFile `json:"files"`
+	Count     int        `json:"count"`
+}
+
+// TreeEvent is one line of the NDJSON stream of hash tree --watch. Event is
+// "ready" once the initial manifest is built, then "created", "modified",
+// "deleted" or "error". Digest is the tree digest after the event.
+type TreeEvent struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	Path      string    `json:"path,omitempty"`
+	Hash      string    `json:"hash,omitempty"`
+	Previous  string    `json:"previous,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	Count     int       `json:"count,omitempty"`
+	Algorithm string    `json:"algorithm"`
+	Digest    string    `json:"digest"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// treeFile is the state of one file between scans.
+type treeFile struct {
+	hash  string
+	size  int64
+	mtime time.Time
+}
+
+// RunTree hashes every regular file under the directory in args (default
+// ".") and prints a manifest of paths relative to it, in the format of
+// hash and sha256sum. With Watch set it instead prints a "ready" event and
+// then rescans each Interval until ctx is cancelled, printing one JSON
+// event per file whose digest changed.
+func RunTree(ctx context.Context, w io.Writer, args []string, opts TreeOptions) error {
+	if len(args) > 1 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "hash tree: expected one directory")
+	}
+
+	root := "."
+	if len(args) == 1 {
+		root = args[0]
+	}
+
+	if opts.Algorithm == "" {
+		opts.Algorithm = "sha256"
+	}
+
+	algo := hashutil.Algorithm(strings.ToLower(opts.Algorithm))
+	if !slices.Contains(hashutil.Algorithms(), algo) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("hash tree: unsupported algorithm %q", opts.Algorithm))
+	}
+
+	if opts.Interval < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "hash tree: --interval must not be negative")
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = DefaultTreeInterval
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return treeError(err)
+	}
+
+	if !info.IsDir() {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("hash tree: %s: not a directory", root))
+	}
+
+	files, errs, err := scanTree(root, algo, nil)
+	if err != nil {
+		return treeError(err)
+	}
+
+	if opts.Watch {
+		return watchTree(ctx, w, root, algo, files, errs, opts.Interval)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(errs)) {
+		_, _ = fmt.Fprintf(os.Stderr, "hash tree: %s: %v\n", path, errs[path])
+	}
+
+	paths := slices.Sorted(maps.Keys(files))
+
+	if f := output.New(w, opts.OutputFormat); f.IsJSON() {
+		m := TreeManifest{Root: root, Algorithm: string(algo), Digest: treeDigest(files, algo), Files: []TreeFile{}}
+		for _, p := range paths {
+			m.Files = append(m.Files, TreeFile{Path: p, Hash: files[p].hash, Size: files[p].size})
+		}
+
+		m.Count = len(m.Files)
+
+		return f.Print(m)
+	}
+
+	for _, p := range paths {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", files[p].hash, p)
+	}
+
+	return nil
+}
+
+// watchTree rescans root each interval and prints the changes as NDJSON.
+// Read errors are reported once per file until the file is readable again;
+// meanwhile the file keeps its last known digest.
+func watchTree(ctx context.Context, w io.Writer, root string, algo hashutil.Algorithm, files map[string]treeFile, errs map[string]error, interval time.Duration) error {
+	enc := json.NewEncoder(w)
+
+	emit := func(ev TreeEvent) error {
+		ev.Time = time.Now().UTC()
+		ev.Algorithm = string(algo)
+
+		return enc.Encode(ev)
+	}
+
+	digest := treeDigest(files, algo)
+
+	for _, path := range slices.Sorted(maps.Keys(errs)) {
+		if err := emit(TreeEvent{Event: "error", Path: path, Digest: digest, Error: errs[path].Error()}); err != nil {
+			return err
+		}
+	}
+
+	if err := emit(TreeEvent{Event: "ready", Count: len(files), Digest: digest}); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var rootErr error
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cur, curErrs, err := scanTree(root, algo, files)
+		if err != nil {
+			// The directory itself is gone or unreadable: say so once and
+			// keep the last manifest until it is back.
+			if rootErr == nil || rootErr.Error() != err.Error() {
+				if err := emit(TreeEvent{Event: "error", Path: ".", Digest: digest, Error: err.Error()}); err != nil {
+					return err
+				}
+			}
+
+			rootErr = err
+
+			continue
+		}
+
+		rootErr = nil
+
+		// Files behind a read error, or inside an unreadable directory,
+		// are unknown rather than deleted.
+		for path, prev := range files {
+			if _, ok := cur[path]; !ok && underError(path, curErrs) {
+				cur[path] = prev
+			}
+		}
+
+		events := diffTree(files, cur)
+		digest = treeDigest(cur, algo)
+
+		for _, path := range slices.Sorted(maps.Keys(curErrs)) {
+			if old, ok := errs[path]; ok && old.Error() == curErrs[path].Error() {
+				continue
+			}
+
+			if err := emit(TreeEvent{Event: "error", Path: path, Digest: digest, Error: curErrs[path].Error()}); err != nil {
+				return err
+			}
+		}
+
+		for _, ev := range events {
+			ev.Digest = digest
+			if err := emit(ev); err != nil {
+				return err
+			}
+		}
+
+		files, errs = cur, curErrs
+	}
+}
+
+// underError reports whether path, or a directory above it, is in errs.
+func underError(path string, errs map[string]error) bool {
+	for p := path; ; {
+		if _, ok := errs[p]; ok {
+			return true
+		}
+
+		i := strings.LastIndexByte(p, '/')
+		if i < 0 {
+			return false
+		}
+
+		p = p[:i]
+	}
+}
+
+// diffTree returns the created, modified and deleted files between two
+// scans, ordered by path.
+func diffTree(prev, cur map[string]treeFile) []TreeEvent {
+	var events []TreeEvent
+
+	for path, f := range cur {
+		old, ok := prev[path]
+
+		switch {
+		case !ok:
+			events = append(events, TreeEvent{Event: "created", Path: path, Hash: f.hash, Size: f.size})
+		case old.hash != f.hash:
+			events = append(events, TreeEvent{Event: "modified", Path: path, Hash: f.hash, Previous: old.hash, Size: f.size})
+		}
+	}
+
+	for path, old := range prev {
+		if _, ok := cur[path]; !ok {
+			events = append(events, TreeEvent{Event: "deleted", Path: path, Previous: old.hash})
+		}
+	}
+
+	slices.SortFunc(events, func(a, b TreeEvent) int { return strings.Compare(a.Path, b.Path) })
+
+	return events
+}
+
+// scanTree hashes the regular files under root, keyed by slash-separated
+// path relative to root. A file whose size and modification time match its
+// entry in prev keeps that digest without being read again. Files and
+// directories that cannot be read are returned in errs instead; only an
+// unreadable root is an error.
+func scanTree(root string, algo hashutil.Algorithm, prev map[string]treeFile) (map[string]treeFile, map[string]error, error) {
+	files := map[string]treeFile{}
+	errs := map[string]error{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if err != nil {
+			if rel == "." {
+				return err
+			}
+
+			if !errors.Is(err, fs.ErrNotExist) {
+				errs[rel] = err
+			}
+
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			// Removed between listing and stat.
+			if !errors.Is(err, fs.ErrNotExist) {
+				errs[rel] = err
+			}
+
+			return nil
+		}
+
+		if old, ok := prev[rel]; ok && old.size == info.Size() && old.mtime.Equal(info.ModTime()) {
+			files[rel] = old
+			return nil
+		}
+
+		sum, err := hashutil.HashFile(path, algo)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				errs[rel] = errors.Unwrap(err)
+			}
+
+			return nil
+		}
+
+		files[rel] = treeFile{hash: sum, size: info.Size(), mtime: info.ModTime()}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return files, errs, nil
+}
+
+// treeDigest hashes the manifest of files, so two trees with the same
+// paths and contents have the same digest.
+func treeDigest(files map[string]treeFile, algo hashutil.Algorithm) string {
+	var b strings.Builder
+
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		_, _ = fmt.Fprintf(&b, "%s  %s\n", files[p].hash, p)
+	}
+
+	return hashutil.HashString(b.String(), algo)
+}
+
+// treeError classifies an error reading the tree root.
+func treeError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("hash tree: %s", err))
+	case errors.Is(err, fs.ErrPermission):
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("hash tree: %s", err))
+	default:
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("hash tree: %s", err))
+	}
+}
@@ -0,0 +1,245 @@
+package hash
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRunTree(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"b.conf": "b\n", "sub/a.conf": "a\n"})
+
+	var buf bytes.Buffer
+	if err := RunTree(context.Background(), &buf, []string{dir}, TreeOptions{}); err != nil {
+		t.Fatalf("RunTree() error = %v", err)
+	}
+
+	want := hashutil.HashString("b\n", hashutil.SHA256) + "  b.conf\n" +
+		hashutil.HashString("a\n", hashutil.SHA256) + "  sub/a.conf\n"
+	if buf.String() != want {
+		t.Errorf("manifest = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	if err := RunTree(context.Background(), &buf, []string{dir}, TreeOptions{Algorithm: "MD5", OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("RunTree() error = %v", err)
+	}
+
+	var m TreeManifest
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if m.Algorithm != "md5" || m.Count != 2 || m.Files[1].Path != "sub/a.conf" || m.Files[1].Size != 2 {
+		t.Errorf("manifest = %+v", m)
+	}
+
+	md5Manifest := hashutil.HashString("b\n", hashutil.MD5) + "  b.conf\n" +
+		hashutil.HashString("a\n", hashutil.MD5) + "  sub/a.conf\n"
+	if m.Digest != hashutil.HashString(md5Manifest, hashutil.MD5) {
+		t.Errorf("digest = %q", m.Digest)
+	}
+}
+
+func TestRunTreeDigest(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	writeTree(t, a, map[string]string{"x": "1", "y/z": "2"})
+	writeTree(t, b, map[string]string{"y/z": "2", "x": "1"})
+
+	digest := func(dir string) string {
+		var buf bytes.Buffer
+		if err := RunTree(context.Background(), &buf, []string{dir}, TreeOptions{OutputFormat: output.FormatJSON}); err != nil {
+			t.Fatalf("RunTree() error = %v", err)
+		}
+
+		var m TreeManifest
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+
+		return m.Digest
+	}
+
+	if digest(a) != digest(b) {
+		t.Error("equal trees have different digests")
+	}
+
+	writeTree(t, b, map[string]string{"x": "3"})
+
+	if digest(a) == digest(b) {
+		t.Error("different trees have the same digest")
+	}
+}
+
+func TestRunTreeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"f": "x"})
+
+	tests := []struct {
+		name  string
+		args  []string
+		opts  TreeOptions
+		check func(error) bool
+	}{
+		{"missing", []string{filepath.Join(dir, "nope")}, TreeOptions{}, cmderr.IsNotFound},
+		{"file", []string{filepath.Join(dir, "f")}, TreeOptions{}, cmderr.IsInvalidInput},
+		{"two dirs", []string{dir, dir}, TreeOptions{}, cmderr.IsInvalidInput},
+		{"algorithm", []string{dir}, TreeOptions{Algorithm: "rot13"}, cmderr.IsInvalidInput},
+		{"interval", []string{dir}, TreeOptions{Watch: true, Interval: -time.Second}, cmderr.IsInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RunTree(context.Background(), io.Discard, tt.args, tt.opts); !tt.check(err) {
+				t.Errorf("RunTree() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDiffTree(t *testing.T) {
+	prev := map[string]treeFile{"a": {hash: "1"}, "b": {hash: "2"}, "c": {hash: "3"}}
+	cur := map[string]treeFile{"a": {hash: "1"}, "b": {hash: "9", size: 4}, "d": {hash: "4"}}
+
+	var got []string
+	for _, ev := range diffTree(prev, cur) {
+		got = append(got, ev.Event+" "+ev.Path+" "+ev.Previous+">"+ev.Hash)
+	}
+
+	want := "modified b 2>9,deleted c 3>,created d >4"
+	if strings.Join(got, ",") != want {
+		t.Errorf("diffTree() = %v, want %s", got, want)
+	}
+}
+
+func TestUnderError(t *testing.T) {
+	errs := map[string]error{"etc/ssl": os.ErrPermission}
+
+	for path, want := range map[string]bool{"etc/ssl": true, "etc/ssl/certs/ca.pem": true, "etc/sslx": false, "etc": false} {
+		if got := underError(path, errs); got != want {
+			t.Errorf("underError(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRunTreeWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"keep": "same", "app.conf": "v1", "old": "x"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pr, pw := io.Pipe()
+
+	done := make(chan error, 1)
+
+	go func() {
+		err := RunTree(ctx, pw, []string{dir}, TreeOptions{Watch: true, Interval: 10 * time.Millisecond})
+		_ = pw.CloseWithError(err)
+		done <- err
+	}()
+
+	lines := bufio.NewScanner(pr)
+
+	next := func() TreeEvent {
+		t.Helper()
+
+		if !lines.Scan() {
+			t.Fatalf("event stream ended: %v", lines.Err())
+		}
+
+		var ev TreeEvent
+		if err := json.Unmarshal(lines.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid event %q: %v", lines.Text(), err)
+		}
+
+		return ev
+	}
+
+	ready := next()
+	if ready.Event != "ready" || ready.Count != 3 || ready.Algorithm != "sha256" {
+		t.Fatalf("first event = %+v, want ready", ready)
+	}
+
+	// Touching a file without changing it is not an event.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "keep"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTree(t, dir, map[string]string{"app.conf": "version 2", "new/f": "n"})
+
+	if err := os.Remove(filepath.Join(dir, "old")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A scan may catch a write halfway, so wait for the final state.
+	want := map[string]bool{"modified app.conf": false, "created new/f": false, "deleted old": false}
+	pending := len(want)
+
+	var got []string
+
+	deadline := time.After(5 * time.Second)
+	for pending > 0 {
+		select {
+		case <-deadline:
+			t.Fatalf("timed out, events so far: %v", got)
+		default:
+		}
+
+		ev := next()
+		key := ev.Event + " " + ev.Path
+		got = append(got, key)
+
+		if key == "modified app.conf" && ev.Hash != hashutil.HashString("version 2", hashutil.SHA256) {
+			continue
+		}
+
+		if seen, ok := want[key]; ok && !seen {
+			want[key] = true
+			pending--
+		}
+
+		if ev.Path == "keep" {
+			t.Errorf("touched file reported: %+v", ev)
+		}
+
+		if ev.Digest == ready.Digest {
+			t.Errorf("event %s kept the initial digest", ev.Event)
+		}
+	}
+
+	cancel()
+
+	go func() { _, _ = io.Copy(io.Discard, pr) }()
+
+	if err := <-done; err != nil {
+		t.Errorf("RunTree() error = %v", err)
+	}
+}
@@ -2,20 +2,23 @@ package head
 
 import (
 	"bufio"
-	"errors"
+	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 // HeadOptions configures the head command behavior
 type HeadOptions struct {
-	Lines        int           // -n: number of lines to print
-	Bytes        int           // -c: number of bytes to print
+	Lines        int           // -n: number of lines to print; negative prints all but the last -Lines
+	Bytes        int           // -c: number of bytes to print; negative prints all but the last -Bytes
 	Quiet        bool          // -q: never print headers
 	Verbose      bool          // -v: always print headers
 	OutputFormat output.Format // output format (text/json/table)
@@ -31,25 +34,20 @@ type HeadResult struct {
 const DefaultHeadLines = 10
 
 // RunHead executes the head command
-// r is the default input reader (used when args is empty or contains "-")
+// r is the default input reader (used when args is empty or contains "-").
+// Files that cannot be opened are reported on stderr and skipped; the exit
+// status is then 1.
 func RunHead(w io.Writer, r io.Reader, args []string, opts HeadOptions) error {
 	if opts.Lines == 0 && opts.Bytes == 0 {
 		opts.Lines = DefaultHeadLines
 	}
 
-	sources, err := input.Open(args, r)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("head: %s", err))
-		}
-		if errors.Is(err, os.ErrPermission) {
-			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("head: %s", err))
-		}
-		return fmt.Errorf("head: %w", err)
+	names := args
+	if len(names) == 0 {
+		names = []string{"-"}
 	}
-	defer input.CloseAll(sources)
 
-	showHeaders := len(sources) > 1 || opts.Verbose
+	showHeaders := len(names) > 1 || opts.Verbose
 	if opts.Quiet {
 		showHeaders = false
 	}
@@ -57,94 +55,210 @@ func RunHead(w io.Writer, r io.Reader, args []string, opts HeadOptions) error {
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
 
-	var results []HeadResult
+	var (
+		results []HeadResult
+		failed  bool
+		printed bool
+	)
+
+	for _, name := range names {
+		src, err := input.OpenOne([]string{name}, r)
+		if err != nil {
+			failed = true
+
+			_, _ = fmt.Fprintf(os.Stderr, "head: %s\n", err)
+
+			continue
+		}
 
-	for i, src := range sources {
 		if jsonMode {
-			lines, err := headLinesJSON(src.Reader, opts.Lines)
+			var buf bytes.Buffer
+
+			err = head(&buf, src.Reader, opts)
+			input.MustClose(&src)
+
 			if err != nil {
-				return err
+				return fmt.Errorf("head: %s: %w", src.Name, err)
 			}
 
-			results = append(results, HeadResult{File: src.Name, Lines: lines})
+			results = append(results, HeadResult{File: src.Name, Lines: splitLines(buf.Bytes())})
 
 			continue
 		}
 
 		if showHeaders {
-			if i > 0 {
+			if printed {
 				_, _ = fmt.Fprintln(w)
 			}
 
 			_, _ = fmt.Fprintf(w, "==> %s <==\n", src.Name)
 		}
 
-		if opts.Bytes > 0 {
-			if err := headBytes(w, src.Reader, opts.Bytes); err != nil {
-				return err
-			}
-		} else {
-			if err := headLines(w, src.Reader, opts.Lines); err != nil {
-				return err
-			}
+		printed = true
+
+		err = head(w, src.Reader, opts)
+		input.MustClose(&src)
+
+		if err != nil {
+			return fmt.Errorf("head: %s: %w", src.Name, err)
 		}
 	}
 
 	if jsonMode {
-		return f.Print(results)
+		if err := f.Print(results); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return cmderr.PartialFailure(1, "head: some operands failed")
 	}
 
 	return nil
 }
 
-func headLinesJSON(r io.Reader, n int) ([]string, error) {
-	scanner := bufio.NewScanner(r)
+func head(w io.Writer, r io.Reader, opts HeadOptions) error {
+	switch {
+	case opts.Bytes > 0:
+		return headBytes(w, r, int64(opts.Bytes))
+	case opts.Bytes < 0:
+		return headAllButLastBytes(w, r, -opts.Bytes)
+	case opts.Lines < 0:
+		return headAllButLastLines(w, r, -opts.Lines)
+	default:
+		return headLines(w, r, opts.Lines)
+	}
+}
 
-	var lines []string
+// splitLines splits output into lines without their terminators.
+func splitLines(data []byte) []string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, max(len(data), bufio.MaxScanTokenSize))
 
-	count := 0
+	var lines []string
 
 	for scanner.Scan() {
-		if count >= n {
-			break
-		}
-
 		lines = append(lines, scanner.Text())
-		count++
 	}
 
-	return lines, scanner.Err()
+	return lines
 }
 
+// headLines copies the first n lines, keeping their original terminators.
 func headLines(w io.Writer, r io.Reader, n int) error {
-	scanner := bufio.NewScanner(r)
+	br := bufio.NewReader(r)
 
-	count := 0
-	for scanner.Scan() {
-		if count >= n {
-			break
+	for range n {
+		line, err := br.ReadBytes('\n')
+		if _, werr := w.Write(line); werr != nil {
+			return werr
 		}
 
-		_, _ = fmt.Fprintln(w, scanner.Text())
-		count++
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
 	}
 
-	return scanner.Err()
+	return nil
 }
 
-func headBytes(w io.Writer, r io.Reader, n int) error {
-	buf := make([]byte, n)
+// headAllButLastLines copies every line except the last n, holding back
+// only n lines at a time.
+func headAllButLastLines(w io.Writer, r io.Reader, n int) error {
+	br := bufio.NewReader(r)
+	ring := make([][]byte, 0, n+1)
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			ring = append(ring, line)
+
+			if len(ring) > n {
+				if _, werr := w.Write(ring[0]); werr != nil {
+					return werr
+				}
+
+				ring = ring[1:]
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
 
-	read, err := io.ReadFull(r, buf)
-	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-		return err
+		if err != nil {
+			return err
+		}
 	}
+}
 
-	_, err = w.Write(buf[:read])
+func headBytes(w io.Writer, r io.Reader, n int64) error {
+	_, err := io.CopyN(w, r, n)
+	if err == io.EOF {
+		return nil
+	}
 
 	return err
 }
 
+// headAllButLastBytes copies everything except the last n bytes.
+func headAllButLastBytes(w io.Writer, r io.Reader, n int) error {
+	buf := make([]byte, 0, n+32*1024)
+	chunk := make([]byte, 32*1024)
+
+	for {
+		m, err := r.Read(chunk)
+		buf = append(buf, chunk[:m]...)
+
+		if excess := len(buf) - n; excess > 0 {
+			if _, werr := w.Write(buf[:excess]); werr != nil {
+				return werr
+			}
+
+			buf = append(buf[:0], buf[excess:]...)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ParseCount parses a -n or -c argument: a number with an optional leading
+// '-' (all but the last NUM) and an optional size suffix as read by
+// flagvalue.ParseByteSize: b (512), K (1024), KB (1000), MiB (1024^2), ...
+func ParseCount(s string) (int, error) {
+	num := strings.TrimPrefix(strings.TrimPrefix(s, "+"), "-")
+
+	n, err := parseSize(num)
+	if err != nil {
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("head: invalid number: %q", s))
+	}
+
+	if strings.HasPrefix(s, "-") {
+		return -n, nil
+	}
+
+	return n, nil
+}
+
+func parseSize(s string) (int, error) {
+	n, err := flagvalue.ParseByteSize(s)
+	if err != nil || n > math.MaxInt {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	return int(n), nil
+}
+
 // Head returns the first n lines from a slice (for compatibility)
 func Head(lines []string, n int) []string {
 	if n > len(lines) {
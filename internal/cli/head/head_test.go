@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 func TestRunHead(t *testing.T) {
@@ -492,3 +494,74 @@ func TestHead(t *testing.T) {
 		}
 	})
 }
+
+func TestRunHead_AllButLast(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(file, []byte("a\nb\nc\nd"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts HeadOptions
+		want string
+	}{
+		{"lines", HeadOptions{Lines: -2}, "a\nb\n"},
+		{"lines zero", HeadOptions{Lines: -10}, ""},
+		{"bytes", HeadOptions{Bytes: -2}, "a\nb\nc"},
+		{"bytes more than file", HeadOptions{Bytes: -100}, ""},
+		{"keeps missing newline", HeadOptions{Lines: 4}, "a\nb\nc\nd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RunHead(&buf, nil, []string{file}, tt.opts); err != nil {
+				t.Fatalf("RunHead() error = %v", err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("RunHead() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunHead_SkipsMissingFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data.txt")
+	_ = os.WriteFile(file, []byte("one\n"), 0644)
+
+	var buf bytes.Buffer
+
+	err := RunHead(&buf, nil, []string{"/nonexistent/file.txt", file}, HeadOptions{Lines: 1})
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("RunHead() error = %v, want exit status 1", err)
+	}
+
+	if want := "==> " + file + " <==\none\n"; buf.String() != want {
+		t.Errorf("RunHead() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"10", 10, false},
+		{"-3", -3, false},
+		{"+4", 4, false},
+		{"2K", 2048, false},
+		{"-1b", -512, false},
+		{"x", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCount(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCount(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
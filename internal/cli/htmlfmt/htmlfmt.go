@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
@@ -25,6 +26,29 @@ type ValidateOptions struct {
 	OutputFormat output.Format // Output format
 }
 
+// RewriteOptions configures URL rewriting
+type RewriteOptions struct {
+	Base      string // Resolve relative URLs against this absolute URL
+	Prefix    string // Prepend this prefix (e.g. a CDN) to relative asset URLs
+	InlineMax int64  // Inline local assets up to this many bytes as data URIs (0 = off)
+	Root      string // Directory local assets are read from (default: the input file's directory)
+	InPlace   bool   // Rewrite the given files in place
+}
+
+// AuditOptions configures the accessibility and SEO audit
+type AuditOptions struct {
+	OutputFormat output.Format // Output format
+	Severities   []string      // rule=severity overrides (severity off disables the rule)
+	FailOn       string        // Lowest severity that makes the command fail (default: error)
+	ListRules    bool          // List the rules and their default severities
+}
+
+// AuditFileReport is the audit report of one input
+type AuditFileReport struct {
+	File string `json:"file"`
+	pkghtml.AuditReport
+}
+
 // ValidateResult is an alias for the pkg type
 type ValidateResult = pkghtml.ValidateResult
 
@@ -99,6 +123,221 @@ func RunValidate(w io.Writer, r io.Reader, args []string, opts ValidateOptions)
 	return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("htmlfmt: parse: %s", result.Error))
 }
 
+// RunAudit checks HTML for accessibility and SEO problems. Every issue is
+// printed; the command fails when an issue reaches the --fail-on severity.
+func RunAudit(w io.Writer, r io.Reader, args []string, opts AuditOptions) error {
+	f := output.New(w, opts.OutputFormat)
+
+	if opts.ListRules {
+		if f.IsJSON() {
+			return f.Print(pkghtml.AuditRules)
+		}
+
+		for _, rule := range pkghtml.AuditRules {
+			_, _ = fmt.Fprintf(w, "%-18s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+		}
+
+		return nil
+	}
+
+	auditOpts, err := auditOptions(opts.Severities)
+	if err != nil {
+		return err
+	}
+
+	failOn := pkghtml.SeverityError
+	if opts.FailOn != "" {
+		if failOn, err = pkghtml.ParseSeverity(opts.FailOn); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("html audit: --fail-on: %s", err))
+		}
+	}
+
+	var reports []AuditFileReport
+
+	// As with getInput, a first argument that is a file means every
+	// argument names a file; otherwise the arguments are literal HTML
+	if len(args) > 0 && isFile(args[0]) {
+		for _, path := range args {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return wrapInputErr("html audit", err)
+			}
+
+			reports = append(reports, AuditFileReport{File: path, AuditReport: pkghtml.Audit(string(content), auditOpts...)})
+		}
+	} else {
+		input, err := getInput(args, r)
+		if err != nil {
+			return wrapInputErr("html audit", err)
+		}
+
+		reports = append(reports, AuditFileReport{File: "-", AuditReport: pkghtml.Audit(input, auditOpts...)})
+	}
+
+	failed := false
+	errs, warnings, infos := 0, 0, 0
+
+	for _, rep := range reports {
+		if failOn != pkghtml.SeverityOff && rep.Max().Rank() >= failOn.Rank() {
+			failed = true
+		}
+
+		errs += rep.Errors
+		warnings += rep.Warnings
+		infos += rep.Infos
+	}
+
+	if f.IsJSON() {
+		if err := f.Print(reports); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("html audit: write: %s", err))
+		}
+	} else {
+		for _, rep := range reports {
+			for _, is := range rep.Issues {
+				_, _ = fmt.Fprintf(w, "%s:%d: %s: %s [%s]\n", rep.File, is.Line, is.Severity, is.Message, is.Rule)
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%d error(s), %d warning(s), %d info\n", errs, warnings, infos); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("html audit: write: %s", err))
+		}
+	}
+
+	if failed {
+		return cmderr.SilentExit(1)
+	}
+
+	return nil
+}
+
+// auditOptions parses rule=severity overrides.
+func auditOptions(specs []string) ([]pkghtml.AuditOption, error) {
+	var opts []pkghtml.AuditOption
+
+	for _, spec := range specs {
+		rule, level, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("html audit: --severity %q: want RULE=LEVEL", spec))
+		}
+
+		if !pkghtml.IsAuditRule(rule) {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("html audit: unknown rule %q (see --list-rules)", rule))
+		}
+
+		s, err := pkghtml.ParseSeverity(level)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("html audit: --severity %s: %s", rule, err))
+		}
+
+		opts = append(opts, pkghtml.WithRuleSeverity(rule, s))
+	}
+
+	return opts, nil
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// RunRewrite rewrites resource URLs (href, src, srcset, poster, action)
+func RunRewrite(w io.Writer, r io.Reader, args []string, opts RewriteOptions) error {
+	if opts.Base != "" && opts.Prefix != "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "html rewrite: --base and --prefix are mutually exclusive")
+	}
+
+	if opts.Base == "" && opts.Prefix == "" && opts.InlineMax <= 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "html rewrite: nothing to do (use --base, --prefix or --inline-max)")
+	}
+
+	if opts.InPlace {
+		if len(args) == 0 {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "html rewrite: --in-place requires files")
+		}
+
+		for _, path := range args {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return wrapInputErr("html rewrite", err)
+			}
+
+			out, err := rewrite(string(content), filepath.Dir(path), opts)
+			if err != nil {
+				return err
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				return wrapInputErr("html rewrite", err)
+			}
+
+			if err := os.WriteFile(path, []byte(out), info.Mode().Perm()); err != nil {
+				return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("html rewrite: write: %s", err))
+			}
+		}
+
+		return nil
+	}
+
+	input, err := getInput(args, r)
+	if err != nil {
+		return wrapInputErr("html rewrite", err)
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		if _, err := os.Stat(args[0]); err == nil {
+			dir = filepath.Dir(args[0])
+		}
+	}
+
+	out, err := rewrite(input, dir, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, out); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("html rewrite: write: %s", err))
+	}
+
+	return nil
+}
+
+// rewrite applies inlining first so only assets that stay external get the
+// base URL or prefix.
+func rewrite(input, dir string, opts RewriteOptions) (string, error) {
+	var fns []pkghtml.RewriteFunc
+
+	if opts.InlineMax > 0 {
+		root := opts.Root
+		if root == "" {
+			root = dir
+		}
+
+		fns = append(fns, pkghtml.InlineAssets(root, opts.InlineMax))
+	}
+
+	if opts.Base != "" {
+		fn, err := pkghtml.AbsoluteURLs(opts.Base)
+		if err != nil {
+			return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("html rewrite: %s", err))
+		}
+
+		fns = append(fns, fn)
+	}
+
+	if opts.Prefix != "" {
+		fns = append(fns, pkghtml.PrefixURLs(opts.Prefix))
+	}
+
+	out, err := pkghtml.RewriteURLs(input, pkghtml.ChainRewrites(fns...))
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("html rewrite: %s", err))
+	}
+
+	return out, nil
+}
+
 // wrapInputErr classifies input-reading errors into cmderr sentinels.
 func wrapInputErr(cmd string, err error) error {
 	if errors.Is(err, os.ErrNotExist) {
@@ -2,11 +2,16 @@ package htmlfmt
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
 	pkghtml "github.com/inovacc/omni/pkg/htmlfmt"
 )
 
@@ -298,3 +303,129 @@ func TestSortAttributes(t *testing.T) {
 	// The actual sorting is tested in the integration tests above
 	t.Log("sortAttributes function exists and is tested via integration")
 }
+
+func TestRunRewrite(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := RunRewrite(&buf, strings.NewReader(`<img src="a.png"><a href="/x">x</a>`), nil, RewriteOptions{Base: "https://cdn.example.com/"})
+	if err != nil {
+		t.Fatalf("RunRewrite() error = %v", err)
+	}
+
+	want := `<img src="https://cdn.example.com/a.png"><a href="https://cdn.example.com/x">x</a>` + "\n"
+	if buf.String() != want {
+		t.Errorf("RunRewrite() = %q, want %q", buf.String(), want)
+	}
+
+	for name, opts := range map[string]RewriteOptions{
+		"nothing to do":   {},
+		"base and prefix": {Base: "https://a.example.com", Prefix: "https://b.example.com"},
+		"relative base":   {Base: "cdn/"},
+		"in-place stdin":  {Base: "https://a.example.com", InPlace: true},
+	} {
+		if err := RunRewrite(&buf, strings.NewReader("<p></p>"), nil, opts); !cmderr.IsInvalidInput(err) {
+			t.Errorf("%s: error = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestRunRewriteInPlace(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "index.html")
+
+	if err := os.WriteFile(filepath.Join(dir, "logo.svg"), []byte("<svg/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(page, []byte(`<img src="logo.svg"><script src="app.js"></script>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := RewriteOptions{Prefix: "https://cdn.example.com/v1", InlineMax: 1024, InPlace: true}
+	if err := RunRewrite(io.Discard, nil, []string{page}, opts); err != nil {
+		t.Fatalf("RunRewrite() error = %v", err)
+	}
+
+	got, _ := os.ReadFile(page)
+	if !strings.Contains(string(got), `src="data:image/svg+xml;base64,`) || !strings.Contains(string(got), `src="https://cdn.example.com/v1/app.js"`) {
+		t.Errorf("unexpected rewritten file: %s", got)
+	}
+}
+
+func TestRunAudit(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.html")
+	bad := filepath.Join(dir, "bad.html")
+
+	_ = os.WriteFile(good, []byte(`<p>ok</p><img src="a.png" alt="a">`), 0o644)
+	_ = os.WriteFile(bad, []byte("<h1>x</h1>\n<h3>y</h3>\n<img src=\"a.png\">"), 0o644)
+
+	t.Run("clean", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunAudit(&buf, nil, []string{good}, AuditOptions{}); err != nil {
+			t.Fatalf("RunAudit() error = %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "0 error(s), 0 warning(s)") {
+			t.Errorf("unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("issues fail", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunAudit(&buf, nil, []string{good, bad}, AuditOptions{})
+		if cmderr.ExitCodeFor(err) != 1 {
+			t.Fatalf("RunAudit() error = %v, want exit 1", err)
+		}
+
+		out := buf.String()
+		if !strings.Contains(out, bad+":3: error: <img> has no alt attribute [img-alt]") ||
+			!strings.Contains(out, bad+":2: warning:") {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("severity overrides", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		opts := AuditOptions{Severities: []string{"img-alt=info"}}
+		if err := RunAudit(&buf, nil, []string{bad}, opts); err != nil {
+			t.Fatalf("warnings and infos should not fail by default: %v", err)
+		}
+
+		opts.FailOn = "warning"
+		if err := RunAudit(&buf, nil, []string{bad}, opts); cmderr.ExitCodeFor(err) != 1 {
+			t.Errorf("--fail-on warning should fail, got %v", err)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		_ = RunAudit(&buf, nil, []string{bad}, AuditOptions{OutputFormat: output.FormatJSON})
+
+		var reports []AuditFileReport
+		if err := json.Unmarshal(buf.Bytes(), &reports); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+
+		if len(reports) != 1 || reports[0].File != bad || reports[0].Errors != 1 || reports[0].Warnings != 1 {
+			t.Errorf("unexpected report: %+v", reports)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, opts := range []AuditOptions{
+			{Severities: []string{"img-alt"}},
+			{Severities: []string{"nope=error"}},
+			{Severities: []string{"img-alt=fatal"}},
+			{FailOn: "fatal"},
+		} {
+			err := RunAudit(io.Discard, nil, []string{good}, opts)
+			if !errors.Is(err, cmderr.ErrInvalidInput) {
+				t.Errorf("RunAudit(%+v) error = %v, want invalid input", opts, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package id
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+// InspectOptions configures the id inspect command behavior
+type InspectOptions struct {
+	Type         string        // -t: decode as this type instead of detecting it
+	Epoch        string        // --epoch: Snowflake epoch (omni, twitter, discord or Unix ms)
+	OutputFormat output.Format // output format (text/json/table)
+}
+
+// InspectResult represents the decoded components of one ID for JSON
+type InspectResult struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Version    *int   `json:"version,omitempty"`
+	Variant    string `json:"variant,omitempty"`
+	Time       string `json:"time,omitempty"`
+	UnixMilli  int64  `json:"unixMs,omitempty"`
+	Precision  string `json:"precision,omitempty"`
+	Node       string `json:"node,omitempty"`
+	ClockSeq   *int   `json:"clockSeq,omitempty"`
+	Worker     *int64 `json:"worker,omitempty"`
+	Sequence   *int64 `json:"sequence,omitempty"`
+	Random     string `json:"random,omitempty"`
+	RandomBits int    `json:"randomBits,omitempty"`
+	Prefix     string `json:"prefix,omitempty"`
+	Body       string `json:"body,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+	Checksum   bool   `json:"checksum,omitempty"`
+}
+
+// snowflakeEpochs maps the --epoch presets to Unix milliseconds.
+var snowflakeEpochs = map[string]int64{
+	"omni":    1577836800000,
+	"twitter": 1288834974657,
+	"discord": 1420070400000,
+}
+
+// RunInspect decodes each ID argument, or each line of r when no argument
+// is given, and prints its type and components.
+func RunInspect(w io.Writer, r io.Reader, args []string, opts InspectOptions) error {
+	var parseOpts []idgen.ParseOption
+
+	if opts.Type != "" {
+		t, err := idgen.ParseType(opts.Type)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("id inspect: %s", err))
+		}
+
+		parseOpts = append(parseOpts, idgen.WithType(t))
+	}
+
+	if opts.Epoch != "" {
+		epoch, err := parseEpoch(opts.Epoch)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("id inspect: --epoch: %s", err))
+		}
+
+		parseOpts = append(parseOpts, idgen.WithSnowflakeEpoch(epoch))
+	}
+
+	ids := args
+	if len(ids) == 0 {
+		var err error
+		if ids, err = readIDs(r); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("id inspect: %s", err))
+		}
+	}
+
+	if len(ids) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "id inspect: missing ID")
+	}
+
+	results := make([]InspectResult, 0, len(ids))
+
+	for _, s := range ids {
+		info, err := idgen.Parse(s, parseOpts...)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("id inspect: %s", err))
+		}
+
+		results = append(results, newInspectResult(info))
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(results)
+	}
+
+	for _, res := range results {
+		// With several IDs every line starts with the ID it belongs to
+		prefix := ""
+		if len(results) > 1 {
+			prefix = res.ID + "\t"
+		}
+
+		for _, field := range inspectFields(res) {
+			_, _ = fmt.Fprintf(w, "%s%s\t%s\n", prefix, field[0], field[1])
+		}
+	}
+
+	return nil
+}
+
+func newInspectResult(info idgen.Info) InspectResult {
+	res := InspectResult{
+		ID:         info.ID,
+		Type:       string(info.Type),
+		Variant:    info.Variant,
+		Node:       info.Node,
+		Random:     info.Random,
+		RandomBits: info.RandomBits,
+	}
+
+	if info.HasTime() {
+		res.Time = info.Time.UTC().Format(time.RFC3339Nano)
+		res.UnixMilli = info.Time.UnixMilli()
+		res.Precision = info.Precision.String()
+	}
+
+	switch info.Type {
+	case idgen.TypeUUID:
+		res.Version = &info.Version
+
+		if info.Node != "" {
+			res.ClockSeq = &info.ClockSeq
+		}
+	case idgen.TypeSnowflake:
+		res.Worker, res.Sequence = &info.Worker, &info.Sequence
+	case idgen.TypeTyped:
+		res.Prefix, res.Body, res.Checksum = info.Prefix, string(info.Body), info.Checksum
+
+		if t, err := idgen.ParseTypedID(info.ID); err == nil && info.Body == idgen.KindUUIDv7 {
+			res.UUID = t.UUID()
+		}
+	}
+
+	return res
+}
+
+// inspectFields lists the text output lines of res as name/value pairs.
+func inspectFields(res InspectResult) [][2]string {
+	fields := [][2]string{{"type", res.Type}}
+
+	if res.Prefix != "" {
+		fields = append(fields, [2]string{"prefix", res.Prefix}, [2]string{"body", res.Body})
+
+		if res.UUID != "" {
+			fields = append(fields, [2]string{"uuid", res.UUID})
+		}
+
+		if res.Checksum {
+			fields = append(fields, [2]string{"checksum", "ok"})
+		}
+	}
+
+	if res.Version != nil {
+		fields = append(fields, [2]string{"version", strconv.Itoa(*res.Version)})
+	}
+
+	if res.Variant != "" {
+		fields = append(fields, [2]string{"variant", res.Variant})
+	}
+
+	if res.Time != "" {
+		fields = append(fields,
+			[2]string{"time", res.Time},
+			[2]string{"unix-ms", strconv.FormatInt(res.UnixMilli, 10)},
+			[2]string{"precision", res.Precision},
+		)
+	}
+
+	if res.Node != "" {
+		fields = append(fields, [2]string{"node", res.Node}, [2]string{"clock-seq", strconv.Itoa(*res.ClockSeq)})
+	}
+
+	if res.Worker != nil {
+		fields = append(fields,
+			[2]string{"worker", strconv.FormatInt(*res.Worker, 10)},
+			[2]string{"sequence", strconv.FormatInt(*res.Sequence, 10)},
+		)
+	}
+
+	switch {
+	case res.Random != "":
+		fields = append(fields, [2]string{"random", fmt.Sprintf("%s (%d bits)", res.Random, res.RandomBits)})
+	case res.RandomBits > 0:
+		fields = append(fields, [2]string{"random", fmt.Sprintf("%d bits", res.RandomBits)})
+	}
+
+	return fields
+}
+
+// parseEpoch parses a Snowflake epoch preset, Unix milliseconds or RFC 3339.
+func parseEpoch(s string) (time.Time, error) {
+	if ms, ok := snowflakeEpochs[strings.ToLower(s)]; ok {
+		return time.UnixMilli(ms), nil
+	}
+
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid epoch %q (use omni, twitter, discord, Unix milliseconds or RFC 3339)", s)
+	}
+
+	return t, nil
+}
+
+// readIDs reads one ID per non-blank line.
+func readIDs(r io.Reader) ([]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	var ids []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if s := strings.TrimSpace(scanner.Text()); s != "" {
+			ids = append(ids, s)
+		}
+	}
+
+	return ids, scanner.Err()
+}
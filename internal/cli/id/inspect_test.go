@@ -0,0 +1,101 @@
+package id
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestRunInspect(t *testing.T) {
+	t.Run("uuid v7", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := RunInspect(&buf, nil, []string{"018df9e2-b200-7abc-8def-0123456789ab"}, InspectOptions{}); err != nil {
+			t.Fatalf("RunInspect() error = %v", err)
+		}
+
+		want := "type\tuuid\nversion\t7\nvariant\tRFC 9562\ntime\t2024-03-01T12:00:00Z\nunix-ms\t1709294400000\nprecision\t1ms\nrandom\t2af0def0123456789ab (74 bits)\n"
+		if buf.String() != want {
+			t.Errorf("RunInspect() =\n%s\nwant\n%s", buf.String(), want)
+		}
+	})
+
+	t.Run("several ids from stdin", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		in := strings.NewReader("V1StGXR8_Z5jdHi6B-myT\n\n5382242279129089\n")
+		if err := RunInspect(&buf, in, nil, InspectOptions{}); err != nil {
+			t.Fatalf("RunInspect() error = %v", err)
+		}
+
+		out := buf.String()
+		for _, line := range []string{"V1StGXR8_Z5jdHi6B-myT\ttype\tnanoid\n", "V1StGXR8_Z5jdHi6B-myT\trandom\t126 bits\n", "5382242279129089\tworker\t", "5382242279129089\tsequence\t"} {
+			if !strings.Contains(out, line) {
+				t.Errorf("output missing %q:\n%s", line, out)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		opts := InspectOptions{Epoch: "twitter", OutputFormat: output.FormatJSON}
+		if err := RunInspect(&buf, nil, []string{"1491029658998247427", "c232ab00-9414-11ec-b3c8-9f6bdeced846"}, opts); err != nil {
+			t.Fatalf("RunInspect() error = %v", err)
+		}
+
+		var results []InspectResult
+		if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+			t.Fatalf("JSON unmarshal error = %v", err)
+		}
+
+		if len(results) != 2 {
+			t.Fatalf("got %d results, want 2", len(results))
+		}
+
+		if sf := results[0]; sf.Time != "2022-02-08T12:42:27.353Z" || sf.Worker == nil || sf.Sequence == nil {
+			t.Errorf("snowflake result = %+v", sf)
+		}
+
+		if v1 := results[1]; v1.Version == nil || *v1.Version != 1 || v1.Node != "9f:6b:de:ce:d8:46" || v1.ClockSeq == nil {
+			t.Errorf("uuid v1 result = %+v", v1)
+		}
+	})
+
+	t.Run("forced type", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := RunInspect(&buf, nil, []string{"abc"}, InspectOptions{Type: "nanoid"}); err != nil {
+			t.Fatalf("RunInspect() error = %v", err)
+		}
+
+		if !strings.HasPrefix(buf.String(), "type\tnanoid\n") {
+			t.Errorf("RunInspect() = %q", buf.String())
+		}
+	})
+}
+
+func TestRunInspectErrors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		opts InspectOptions
+	}{
+		"missing id":   {nil, InspectOptions{}},
+		"unknown type": {[]string{"abc"}, InspectOptions{Type: "guid"}},
+		"bad epoch":    {[]string{"1"}, InspectOptions{Epoch: "yesterday"}},
+		"unrecognized": {[]string{"not-an-id!"}, InspectOptions{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := RunInspect(&bytes.Buffer{}, strings.NewReader(""), tt.args, tt.opts)
+			if !cmderr.IsInvalidInput(err) {
+				t.Errorf("RunInspect() error = %v, want invalid input", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package id
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+// NewOptions configures the id new command behavior
+type NewOptions struct {
+	Count        int           // -n: generate N IDs
+	UUID         bool          // --uuid7: UUIDv7 body instead of a ULID
+	Checksum     bool          // --checksum: append a 4-character checksum
+	Monotonic    bool          // --monotonic: strictly increasing order, even within one millisecond
+	OutputFormat output.Format // output format (text/json/table)
+}
+
+// NewResult is the id new output for JSON
+type NewResult struct {
+	IDs   []string `json:"ids"`
+	Count int      `json:"count"`
+}
+
+// RunNew generates typed IDs with the type prefix in args, like
+// cus_01h89d1yg0dpkcwar9g3reha97.
+func RunNew(w io.Writer, args []string, opts NewOptions) error {
+	if len(args) != 1 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "id new: expected one PREFIX")
+	}
+
+	if opts.Count < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("id new: count must be non-negative, got %d", opts.Count))
+	}
+
+	if opts.Count == 0 {
+		opts.Count = 1
+	}
+
+	genOpts := []idgen.TypedIDOption{}
+	if opts.UUID {
+		genOpts = append(genOpts, idgen.WithBodyKind(idgen.KindUUIDv7))
+	}
+
+	if opts.Checksum {
+		genOpts = append(genOpts, idgen.WithChecksum())
+	}
+
+	if opts.Monotonic {
+		genOpts = append(genOpts, idgen.WithMonotonic())
+	}
+
+	g, err := idgen.NewTypedIDGenerator(args[0], genOpts...)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("id new: %s", err))
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	res := NewResult{IDs: make([]string, 0, opts.Count)}
+
+	for range opts.Count {
+		id, err := g.Next()
+		if err != nil {
+			return fmt.Errorf("id new: %w", err)
+		}
+
+		if f.IsJSON() {
+			res.IDs = append(res.IDs, id.String())
+			continue
+		}
+
+		if _, err := fmt.Fprintln(w, id); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("id new: write failed: %v", err))
+		}
+	}
+
+	if f.IsJSON() {
+		res.Count = len(res.IDs)
+		return f.Print(res)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package id
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+func TestRunNew(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunNew(&buf, []string{"cus"}, NewOptions{Count: 3, Checksum: true, Monotonic: true}); err != nil {
+		t.Fatalf("RunNew() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d IDs: %q", len(lines), buf.String())
+	}
+
+	for i, line := range lines {
+		id, err := idgen.ParseTypedID(line)
+		if err != nil || id.Prefix != "cus" || !id.Checksum {
+			t.Errorf("ID %q: %+v, %v", line, id, err)
+		}
+
+		if i > 0 && line <= lines[i-1] {
+			t.Errorf("%q does not sort after %q", line, lines[i-1])
+		}
+	}
+
+	buf.Reset()
+
+	if err := RunNew(&buf, []string{"evt"}, NewOptions{UUID: true, OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("RunNew() error = %v", err)
+	}
+
+	var res NewResult
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil || res.Count != 1 {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+
+	if id, err := idgen.ParseTypedID(res.IDs[0]); err != nil || id.Kind != idgen.KindUUIDv7 {
+		t.Errorf("ID %q: %+v, %v", res.IDs[0], id, err)
+	}
+
+	for _, args := range [][]string{{"Cus"}, {}, {"a", "b"}} {
+		if err := RunNew(&buf, args, NewOptions{}); !cmderr.IsInvalidInput(err) {
+			t.Errorf("RunNew(%q) error = %v", args, err)
+		}
+	}
+}
+
+func TestRunInspectTyped(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunInspect(&buf, nil, []string{"prefix_01h455vb4pex5vsknk084sn02q"}, InspectOptions{}); err != nil {
+		t.Fatalf("RunInspect() error = %v", err)
+	}
+
+	for _, line := range []string{"type\ttyped\n", "prefix\tprefix\n", "body\tuuidv7\n", "uuid\t01890a5d-ac96-774b-bcce-b302099a8057\n"} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("output missing %q:\n%s", line, buf.String())
+		}
+	}
+}
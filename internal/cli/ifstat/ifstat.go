@@ -0,0 +1,154 @@
+// Package ifstat reports per-interface network throughput by sampling the
+// interface counters from pkg/sysinfo over an interval.
+package ifstat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/du"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/sysinfo"
+)
+
+// Options configures the ifstat command behavior
+type Options struct {
+	Interval     time.Duration // -n: time between samples (default 1s)
+	Count        int           // -c: number of reports, 0 = until interrupted
+	Interfaces   []string      // -i: only report these interfaces
+	All          bool          // -a: include loopback interfaces
+	Bytes        bool          // -b: print raw bytes per second
+	OutputFormat output.Format // output format (text/json/table)
+}
+
+// Report is one interval's worth of rates
+type Report struct {
+	Time       time.Time      `json:"time"`
+	Interval   float64        `json:"interval"`
+	Interfaces []sysinfo.Rate `json:"interfaces"`
+}
+
+// sample reads the interface counters; replaced in tests.
+var sample = sysinfo.GetInterfaces
+
+// Run samples the interface counters every opts.Interval and prints the
+// per-second rates until opts.Count reports were printed or ctx is
+// cancelled. The first report appears after one interval.
+func Run(ctx context.Context, w io.Writer, opts Options) error {
+	if opts.Interval == 0 {
+		opts.Interval = time.Second
+	}
+
+	if opts.Interval < 0 || opts.Count < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "ifstat: interval and count must not be negative")
+	}
+
+	prev, err := sample(ctx)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("ifstat: %v", err))
+	}
+
+	prev = filter(prev, opts)
+
+	if len(opts.Interfaces) > 0 {
+		for _, name := range opts.Interfaces {
+			if !slices.ContainsFunc(prev, func(i sysinfo.Interface) bool { return i.Name == name }) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("ifstat: %s: no such interface", name))
+			}
+		}
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	last := time.Now()
+
+	for n := 1; opts.Count == 0 || n <= opts.Count; n++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+
+		cur, err := sample(ctx)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("ifstat: %v", err))
+		}
+
+		cur = filter(cur, opts)
+		now := time.Now()
+		elapsed := now.Sub(last)
+
+		report := Report{
+			Time:       now,
+			Interval:   elapsed.Seconds(),
+			Interfaces: sysinfo.Rates(prev, cur, elapsed),
+		}
+
+		if f.IsJSON() {
+			if err := f.Print(report); err != nil {
+				return err
+			}
+		} else {
+			printReport(w, report, opts, n == 1)
+		}
+
+		prev, last = cur, now
+	}
+
+	return nil
+}
+
+// filter applies -i and drops loopback interfaces unless -a is given.
+func filter(ifaces []sysinfo.Interface, opts Options) []sysinfo.Interface {
+	var out []sysinfo.Interface
+
+	for _, i := range ifaces {
+		if len(opts.Interfaces) > 0 {
+			if !slices.Contains(opts.Interfaces, i.Name) {
+				continue
+			}
+		} else if !opts.All && isLoopback(i.Name) {
+			continue
+		}
+
+		out = append(out, i)
+	}
+
+	return out
+}
+
+func isLoopback(name string) bool {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return name == "lo"
+	}
+
+	return iface.Flags&net.FlagLoopback != 0
+}
+
+func printReport(w io.Writer, r Report, opts Options, header bool) {
+	if header {
+		_, _ = fmt.Fprintf(w, "%-16s %10s %10s %10s %10s\n", "Interface", "RX/s", "TX/s", "RXpkt/s", "TXpkt/s")
+	}
+
+	for _, rate := range r.Interfaces {
+		_, _ = fmt.Fprintf(w, "%-16s %10s %10s %10.1f %10.1f\n",
+			rate.Name,
+			formatRate(rate.RxBytesPerSec, opts),
+			formatRate(rate.TxBytesPerSec, opts),
+			rate.RxPktsPerSec,
+			rate.TxPktsPerSec)
+	}
+}
+
+func formatRate(v float64, opts Options) string {
+	if opts.Bytes {
+		return fmt.Sprintf("%.0f", v)
+	}
+
+	return du.FormatHumanSize(int64(v))
+}
@@ -0,0 +1,115 @@
+package ifstat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/sysinfo"
+)
+
+// fakeCounters makes sample return counters that grow by step bytes and
+// packets per call.
+func fakeCounters(t *testing.T, step uint64) {
+	t.Helper()
+
+	var calls uint64
+
+	orig := sample
+	sample = func(context.Context) ([]sysinfo.Interface, error) {
+		calls++
+
+		return []sysinfo.Interface{
+			{Name: "eth0", RxBytes: calls * step, TxBytes: calls * step / 2, RxPackets: calls * 10, TxPackets: calls * 5},
+			{Name: "lo", RxBytes: calls * step, TxBytes: calls * step},
+		}, nil
+	}
+
+	t.Cleanup(func() { sample = orig })
+}
+
+func TestRun(t *testing.T) {
+	fakeCounters(t, 1<<20)
+
+	var buf bytes.Buffer
+	if err := Run(context.Background(), &buf, Options{Interval: 10 * time.Millisecond, Count: 2, Bytes: true}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two reports, got:\n%s", buf.String())
+	}
+
+	if !strings.HasPrefix(lines[0], "Interface") || !strings.HasPrefix(lines[1], "eth0") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	if strings.Contains(buf.String(), "lo ") {
+		t.Errorf("loopback should be hidden without -a:\n%s", buf.String())
+	}
+}
+
+func TestRunInterfaceFilter(t *testing.T) {
+	fakeCounters(t, 1000)
+
+	var buf bytes.Buffer
+	if err := Run(context.Background(), &buf, Options{Interval: time.Millisecond, Count: 1, Interfaces: []string{"lo"}}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "lo ") || strings.Contains(buf.String(), "eth0") {
+		t.Errorf("-i lo should report only lo:\n%s", buf.String())
+	}
+
+	err := Run(context.Background(), &buf, Options{Interval: time.Millisecond, Count: 1, Interfaces: []string{"nope0"}})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("unknown interface: got %v, want not found", err)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	fakeCounters(t, 1000)
+
+	var buf bytes.Buffer
+	if err := Run(context.Background(), &buf, Options{Interval: 5 * time.Millisecond, Count: 1, OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(report.Interfaces) != 1 || report.Interfaces[0].Name != "eth0" || report.Interfaces[0].RxBytesPerSec <= 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	fakeCounters(t, 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	if err := Run(ctx, &buf, Options{Interval: time.Hour}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("cancelled Run() should print nothing, got %q", buf.String())
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	err := Run(context.Background(), &bytes.Buffer{}, Options{Count: -1})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("negative count: got %v, want invalid input", err)
+	}
+}
@@ -0,0 +1,337 @@
+// Package indent detects the dominant indentation of files and converts
+// leading indentation between tabs and spaces, leaving the rest of each
+// line alone.
+package indent
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Options configures the indent command behavior
+type Options struct {
+	Detect       bool          // --detect: report the dominant indentation instead of converting
+	To           string        // --to: convert leading indentation to "tabs" or "spaces"
+	Width        int           // -w: columns per indentation level (0 = detect, falling back to 4)
+	InPlace      bool          // -i: rewrite the given files instead of printing them
+	OutputFormat output.Format // output format (text/json)
+}
+
+// Report describes the indentation found in one input
+type Report struct {
+	File        string `json:"file"`
+	Style       string `json:"style"`           // tabs, spaces or none
+	Width       int    `json:"width,omitempty"` // spaces per level, for space indentation
+	Mixed       bool   `json:"mixed"`           // both tab- and space-indented lines are present
+	TabLines    int    `json:"tabLines"`
+	SpaceLines  int    `json:"spaceLines"`
+	MixedLines  int    `json:"mixedLines"` // lines whose indentation mixes tabs and spaces
+	TotalLines  int    `json:"totalLines"`
+	IndentLines int    `json:"indentedLines"`
+}
+
+// candidateWidths are the indentation widths Detect chooses between.
+var candidateWidths = []int{2, 4, 8, 3}
+
+// Run detects or converts the indentation of each FILE, or of r when no
+// file is given.
+func Run(w io.Writer, r io.Reader, args []string, opts Options) error {
+	if !opts.Detect && opts.To != "tabs" && opts.To != "spaces" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "indent: specify --detect or --to=tabs|spaces")
+	}
+
+	if opts.Width < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "indent: width must not be negative")
+	}
+
+	if opts.InPlace && (opts.Detect || len(args) == 0) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "indent: --in-place requires --to and at least one file")
+	}
+
+	names := args
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	var reports []Report
+
+	for _, name := range names {
+		data, err := readInput(name, r)
+		if err != nil {
+			return err
+		}
+
+		report := Detect(data)
+		report.File = displayName(name)
+
+		if opts.Detect {
+			reports = append(reports, report)
+			continue
+		}
+
+		width := opts.Width
+		if width == 0 {
+			width = report.Width
+		}
+
+		if width == 0 {
+			width = 4
+		}
+
+		converted := Convert(data, opts.To == "tabs", width)
+
+		if opts.InPlace {
+			if bytes.Equal(converted, data) {
+				continue
+			}
+
+			info, err := os.Stat(name)
+			if err != nil {
+				return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("indent: %s", err))
+			}
+
+			if err := os.WriteFile(name, converted, info.Mode().Perm()); err != nil {
+				return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("indent: write: %s", err))
+			}
+
+			continue
+		}
+
+		if _, err := w.Write(converted); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("indent: write: %s", err))
+		}
+	}
+
+	if !opts.Detect {
+		return nil
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(reports)
+	}
+
+	for _, rep := range reports {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", rep.File, describe(rep))
+	}
+
+	return nil
+}
+
+// Detect counts tab- and space-indented lines and guesses the width of a
+// space indentation level from the changes in indentation between
+// consecutive lines, which is robust against continuation lines and
+// alignment that a plain GCD would be thrown off by.
+func Detect(data []byte) Report {
+	var (
+		rep     Report
+		prev    int
+		changes = map[int]int{}
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		rep.TotalLines++
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		hasTab := strings.Contains(lead, "\t")
+		hasSpace := strings.Contains(lead, " ")
+
+		switch {
+		case hasTab && hasSpace:
+			rep.MixedLines++
+			// Tabs followed by alignment spaces still count as tab indentation
+			if strings.Trim(strings.TrimLeft(lead, "\t"), " ") == "" {
+				rep.TabLines++
+			}
+		case hasTab:
+			rep.TabLines++
+		case hasSpace:
+			rep.SpaceLines++
+		}
+
+		if lead != "" {
+			rep.IndentLines++
+		}
+
+		if !hasTab {
+			if d := len(lead) - prev; d != 0 {
+				changes[abs(d)]++
+			}
+
+			prev = len(lead)
+		}
+	}
+
+	rep.Mixed = rep.TabLines > 0 && rep.SpaceLines > 0
+
+	switch {
+	case rep.TabLines == 0 && rep.SpaceLines == 0:
+		rep.Style = "none"
+	case rep.TabLines >= rep.SpaceLines:
+		rep.Style = "tabs"
+	default:
+		rep.Style = "spaces"
+	}
+
+	if rep.SpaceLines > 0 {
+		rep.Width = guessWidth(changes)
+	}
+
+	return rep
+}
+
+// guessWidth picks the candidate width that explains the most indentation
+// changes; a change of 8 also counts towards 4 and 2, and so on.
+func guessWidth(changes map[int]int) int {
+	best, bestScore := 0, 0
+
+	for _, width := range candidateWidths {
+		score := 0
+
+		for d, n := range changes {
+			if d%width == 0 {
+				score += n
+			}
+		}
+
+		// Prefer the widest width that explains as many changes: 4 over 2
+		// when every change is a multiple of 4
+		if score > bestScore || (score == bestScore && score > 0 && width > best && width != 3) {
+			best, bestScore = width, score
+		}
+	}
+
+	return best
+}
+
+// Convert rewrites the leading indentation of every line as tabs (toTabs)
+// or spaces, treating a tab as width columns. With toTabs, leftover
+// columns that do not fill a whole level stay as spaces.
+func Convert(data []byte, toTabs bool, width int) []byte {
+	var out bytes.Buffer
+
+	out.Grow(len(data))
+
+	for len(data) > 0 {
+		line := data
+		rest := []byte(nil)
+
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, rest = data[:i+1], data[i+1:]
+		}
+
+		data = rest
+
+		n := 0
+		col := 0
+
+		for ; n < len(line) && (line[n] == ' ' || line[n] == '\t'); n++ {
+			if line[n] == '\t' {
+				col = (col/width + 1) * width
+			} else {
+				col++
+			}
+		}
+
+		// Leave whitespace-only lines as they are
+		if n == len(line) || line[n] == '\n' || line[n] == '\r' {
+			out.Write(line)
+			continue
+		}
+
+		if toTabs {
+			out.WriteString(strings.Repeat("\t", col/width))
+			out.WriteString(strings.Repeat(" ", col%width))
+		} else {
+			out.WriteString(strings.Repeat(" ", col))
+		}
+
+		out.Write(line[n:])
+	}
+
+	return out.Bytes()
+}
+
+func describe(r Report) string {
+	var s string
+
+	switch r.Style {
+	case "none":
+		return "no indentation"
+	case "spaces":
+		s = "spaces"
+		if r.Width > 0 {
+			s = fmt.Sprintf("spaces, width %d", r.Width)
+		}
+	default:
+		s = "tabs"
+	}
+
+	s += fmt.Sprintf(" (%d tab-indented, %d space-indented", r.TabLines, r.SpaceLines)
+	if r.MixedLines > 0 {
+		s += fmt.Sprintf(", %d mixed", r.MixedLines)
+	}
+
+	s += " lines)"
+
+	if r.Mixed {
+		s += " [mixed]"
+	}
+
+	return s
+}
+
+func readInput(name string, r io.Reader) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if name == "-" {
+		data, err = io.ReadAll(r)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("indent: %s", err))
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("indent: %s", err))
+	}
+
+	return data, nil
+}
+
+func displayName(name string) string {
+	if name == "-" {
+		return "standard input"
+	}
+
+	return name
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
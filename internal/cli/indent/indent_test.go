@@ -0,0 +1,100 @@
+package indent
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		style string
+		width int
+		mixed bool
+	}{
+		{"two spaces", "a:\n  b:\n    c: 1\n  d: 2\n", "spaces", 2, false},
+		{"four spaces", "def f():\n    if x:\n        y()\n    return\n", "spaces", 4, false},
+		{"tabs", "func f() {\n\tif x {\n\t\ty()\n\t}\n}\n", "tabs", 0, false},
+		{"tabs with alignment", "f(\n\ta,\n\t  b)\n", "tabs", 0, false},
+		{"mixed", "x\n\ta\n\tb\n    c\n", "tabs", 4, true},
+		{"none", "a\nb\n\n", "none", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := Detect([]byte(tt.in))
+			if rep.Style != tt.style || rep.Width != tt.width || rep.Mixed != tt.mixed {
+				t.Errorf("Detect() = %+v, want style %s width %d mixed %v", rep, tt.style, tt.width, tt.mixed)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	if got, want := string(Convert([]byte("a\n\tb\n\t\tc d\t e\n"), false, 2)), "a\n  b\n    c d\t e\n"; got != want {
+		t.Errorf("Convert(spaces) = %q, want %q", got, want)
+	}
+
+	if got, want := string(Convert([]byte("    a\n      b\n  \n"), true, 4)), "\ta\n\t  b\n  \n"; got != want {
+		t.Errorf("Convert(tabs) = %q, want %q", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Run(&buf, strings.NewReader("a\n  b\n    c\n"), nil, Options{To: "tabs"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got, want := buf.String(), "a\n\tb\n\t\tc\n"; got != want {
+		t.Errorf("Run(--to=tabs) = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	if err := Run(&buf, strings.NewReader("a\n    b\n"), nil, Options{Detect: true, OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("Run(--detect) error = %v", err)
+	}
+
+	var reports []Report
+	if err := json.Unmarshal(buf.Bytes(), &reports); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if len(reports) != 1 || reports[0].Style != "spaces" || reports[0].Width != 4 {
+		t.Errorf("Run(--detect --json) = %+v", reports)
+	}
+
+	if err := Run(&buf, nil, nil, Options{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("Run() without mode error = %v, want invalid input", err)
+	}
+}
+
+func TestRunInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.txt")
+	if err := os.WriteFile(path, []byte("x\n\ty\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(&bytes.Buffer{}, nil, []string{path}, Options{To: "spaces", Width: 2, InPlace: true}); err != nil {
+		t.Fatalf("Run(-i) error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "x\n  y\n" {
+		t.Errorf("file = %q", data)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
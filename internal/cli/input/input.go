@@ -1,9 +1,11 @@
 package input
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 // Source represents an input source (file or reader)
@@ -108,3 +110,66 @@ func CloseAll(sources []Source) {
 		_ = sources[i].Close()
 	}
 }
+
+// ReadFileList reads a list of paths from name ("-" for defaultReader), as
+// written by find or find -print0: the list is NUL delimited when it
+// contains a NUL byte and newline delimited otherwise. Empty entries are
+// skipped.
+func ReadFileList(name string, defaultReader io.Reader) ([]string, error) {
+	data, err := readList(name, defaultReader)
+	if err != nil {
+		return nil, err
+	}
+
+	sep := "\n"
+	if bytes.IndexByte(data, 0) >= 0 {
+		sep = "\x00"
+	}
+
+	return splitList(data, sep), nil
+}
+
+// ReadNullList reads a NUL delimited list of paths from name ("-" for
+// defaultReader), as written by find -print0 or xargs -0 input. Unlike
+// ReadFileList it never splits on newlines, so a single path containing a
+// newline survives. Empty entries are skipped.
+func ReadNullList(name string, defaultReader io.Reader) ([]string, error) {
+	data, err := readList(name, defaultReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitList(data, "\x00"), nil
+}
+
+func readList(name string, defaultReader io.Reader) ([]byte, error) {
+	src, err := openOne(name, defaultReader)
+	if err != nil {
+		return nil, err
+	}
+
+	defer MustClose(&src)
+
+	data, err := io.ReadAll(src.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s': %w", src.Name, err)
+	}
+
+	return data, nil
+}
+
+func splitList(data []byte, sep string) []string {
+	var paths []string
+
+	for _, entry := range strings.Split(string(data), sep) {
+		if sep == "\n" {
+			entry = strings.TrimSuffix(entry, "\r")
+		}
+
+		if entry != "" {
+			paths = append(paths, entry)
+		}
+	}
+
+	return paths
+}
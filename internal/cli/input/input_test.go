@@ -181,3 +181,52 @@ func TestSourceClose(t *testing.T) {
 		t.Error("closer should have been called")
 	}
 }
+
+func TestReadFileList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"newline", "a.go\nb c.go\r\n\nd.go", "a.go|b c.go|d.go"},
+		{"nul", "a.go\x00with\nnewline.go\x00", "a.go|with\nnewline.go"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFileList("-", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Join(got, "|") != tt.want {
+				t.Errorf("ReadFileList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "list")
+	if err := os.WriteFile(path, []byte("x\ny\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := ReadFileList(path, nil); err != nil || strings.Join(got, "|") != "x|y" {
+		t.Errorf("ReadFileList(file) = %q, %v", got, err)
+	}
+
+	if _, err := ReadFileList("/nonexistent/list", nil); err == nil {
+		t.Error("expected error for nonexistent list")
+	}
+}
+
+func TestReadNullList(t *testing.T) {
+	got, err := ReadNullList("-", strings.NewReader("one\ntwo\x00three\x00\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(got, "|") != "one\ntwo|three" {
+		t.Errorf("ReadNullList() = %q", got)
+	}
+}
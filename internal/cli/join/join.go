@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
@@ -19,9 +20,11 @@ type JoinOptions struct {
 	Separator     string        // -t: use CHAR as input and output field separator
 	OutputFields  string        // -o: output format specification
 	IgnoreCase    bool          // -i: ignore case when comparing fields
-	CheckOrder    bool          // --check-order: check input is sorted
+	CheckOrder    bool          // --check-order: fail as soon as the input is unsorted
 	NoCheckOrder  bool          // --nocheck-order: do not check input is sorted
 	Empty         string        // -e: replace missing fields with EMPTY
+	Header        bool          // --header: treat the first line of each file as a header
+	ZeroTerm      bool          // -z: line delimiter is NUL
 	Unpaired1     bool          // -a 1: print unpairable lines from file 1
 	Unpaired2     bool          // -a 2: print unpairable lines from file 2
 	OnlyUnpaired1 bool          // -v 1: print only unpairable lines from file 1
@@ -36,12 +39,20 @@ type JoinResult struct {
 	Count int        `json:"count"`
 }
 
-// RunJoin joins lines of two files on a common field
+// RunJoin joins lines of two files on a common field. Like GNU join it
+// expects both files sorted on the join field and merges them in one pass,
+// holding only the lines that share the current key in memory. Unsorted
+// input is reported on standard error and makes join exit with status 1
+// at the end, unless --nocheck-order is given; --check-order stops at once.
 func RunJoin(w io.Writer, args []string, opts JoinOptions) error {
 	if len(args) != 2 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "join: missing operand")
 	}
 
+	if args[0] == "-" && args[1] == "-" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "join: both files cannot be standard input")
+	}
+
 	// Default to field 1 (1-indexed)
 	if opts.Field1 <= 0 {
 		opts.Field1 = 1
@@ -51,38 +62,40 @@ func RunJoin(w io.Writer, args []string, opts JoinOptions) error {
 		opts.Field2 = 1
 	}
 
+	format, err := parseOutputFormat(opts.OutputFields)
+	if err != nil {
+		return err
+	}
+
 	// Default separator is whitespace
 	sep := opts.Separator
 	if sep == "" {
 		sep = " "
 	}
 
-	// Read file 1
-	lines1, err := readJoinFile(args[0], sep)
+	lineDelim := byte('\n')
+	if opts.ZeroTerm {
+		lineDelim = 0
+	}
+
+	r1, err := openInput(args[0])
 	if err != nil {
 		return err
 	}
+	defer func() { _ = r1.Close() }()
 
-	// Read file 2
-	lines2, err := readJoinFile(args[1], sep)
+	r2, err := openInput(args[1])
 	if err != nil {
 		return err
 	}
+	defer func() { _ = r2.Close() }()
 
-	// Build index for file 2
-	index2 := make(map[string][]joinLine)
-
-	for _, line := range lines2 {
-		key := line.key(opts.Field2 - 1)
-		if opts.IgnoreCase {
-			key = strings.ToLower(key)
-		}
-
-		index2[key] = append(index2[key], line)
-	}
+	in1 := newInput(r1, 1, opts.Field1-1, sep, lineDelim, opts)
+	in2 := newInput(r2, 2, opts.Field2-1, sep, lineDelim, opts)
 
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
+	bw := bufio.NewWriter(w)
 
 	var rows [][]string
 
@@ -92,43 +105,84 @@ func RunJoin(w io.Writer, args []string, opts JoinOptions) error {
 			return
 		}
 
-		_, _ = fmt.Fprintln(w, strings.Join(fields, sep))
+		_, _ = bw.WriteString(strings.Join(fields, sep))
+		_ = bw.WriteByte(lineDelim)
 	}
 
-	// Track matched lines from file 2
-	matched2 := make(map[int]bool)
+	pairs := !opts.OnlyUnpaired1 && !opts.OnlyUnpaired2
+	show1 := opts.Unpaired1 || opts.OnlyUnpaired1
+	show2 := opts.Unpaired2 || opts.OnlyUnpaired2
 
-	// Process file 1
-	for _, line1 := range lines1 {
-		key := line1.key(opts.Field1 - 1)
-		if opts.IgnoreCase {
-			key = strings.ToLower(key)
+	if opts.Header {
+		h1, ok1, err1 := in1.readLine()
+		h2, ok2, err2 := in2.readLine()
+
+		if err := errors.Join(err1, err2); err != nil {
+			return err
 		}
 
-		matches, found := index2[key]
-		if found {
-			for _, line2 := range matches {
-				matched2[line2.index] = true
-				if !opts.OnlyUnpaired1 && !opts.OnlyUnpaired2 {
-					emit(joinedFields(line1, line2, opts))
-				}
-			}
-		} else if opts.Unpaired1 || opts.OnlyUnpaired1 {
-			emit(line1.fields)
+		switch {
+		case ok1 && ok2:
+			emit(joinedFields(&h1, &h2, format, opts))
+		case ok1:
+			emit(unpairedFields(&h1, nil, format, opts))
+		case ok2:
+			emit(unpairedFields(nil, &h2, format, opts))
 		}
 	}
 
-	// Output unmatched lines from file 2
-	if opts.Unpaired2 || opts.OnlyUnpaired2 {
-		for _, line2 := range lines2 {
-			if !matched2[line2.index] {
-				emit(line2.fields)
+	g1, err1 := in1.group()
+	g2, err2 := in2.group()
+	err = errors.Join(err1, err2)
+
+	for err == nil && (len(g1) > 0 || len(g2) > 0) {
+		switch c := compareGroups(g1, g2, in1, in2, opts.IgnoreCase); {
+		case c < 0:
+			if show1 {
+				for i := range g1 {
+					emit(unpairedFields(&g1[i], nil, format, opts))
+				}
+			}
+
+			g1, err = in1.group()
+		case c > 0:
+			if show2 {
+				for i := range g2 {
+					emit(unpairedFields(nil, &g2[i], format, opts))
+				}
 			}
+
+			g2, err = in2.group()
+		default:
+			if pairs {
+				for i := range g1 {
+					for j := range g2 {
+						emit(joinedFields(&g1[i], &g2[j], format, opts))
+					}
+				}
+			}
+
+			g1, err1 = in1.group()
+			g2, err2 = in2.group()
+			err = errors.Join(err1, err2)
 		}
 	}
 
+	if err != nil {
+		_ = bw.Flush()
+		return err
+	}
+
 	if jsonMode {
-		return f.Print(JoinResult{Rows: rows, Count: len(rows)})
+		if err := f.Print(JoinResult{Rows: rows, Count: len(rows)}); err != nil {
+			return err
+		}
+	} else if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("join: write: %s", err))
+	}
+
+	if in1.unsorted || in2.unsorted {
+		return cmderr.SilentExit(1)
 	}
 
 	return nil
@@ -136,7 +190,6 @@ func RunJoin(w io.Writer, args []string, opts JoinOptions) error {
 
 type joinLine struct {
 	fields []string
-	index  int
 }
 
 func (j joinLine) key(fieldIdx int) string {
@@ -147,52 +200,172 @@ func (j joinLine) key(fieldIdx int) string {
 	return j.fields[fieldIdx]
 }
 
-func readJoinFile(path string, sep string) ([]joinLine, error) {
-	var r io.Reader
-	if path == "-" {
-		r = os.Stdin
+// input reads one file a group of equal-key lines at a time.
+type input struct {
+	scanner  *bufio.Scanner
+	num      int
+	field    int
+	sep      string
+	opts     JoinOptions
+	pending  *joinLine // first line of the next group, already read
+	lastKey  string
+	seen     bool
+	unsorted bool
+}
+
+func newInput(r io.Reader, num, field int, sep string, delim byte, opts JoinOptions) *input {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	scanner.Split(splitFunc(delim))
+
+	return &input{scanner: scanner, num: num, field: field, sep: sep, opts: opts}
+}
+
+func (in *input) readLine() (joinLine, bool, error) {
+	if !in.scanner.Scan() {
+		if err := in.scanner.Err(); err != nil {
+			return joinLine{}, false, fmt.Errorf("join: %w", err)
+		}
+
+		return joinLine{}, false, nil
+	}
+
+	text := in.scanner.Text()
+
+	var fields []string
+	if in.sep == " " {
+		fields = strings.Fields(text)
+	} else {
+		fields = strings.Split(text, in.sep)
+	}
+
+	return joinLine{fields: fields}, true, nil
+}
+
+// group returns the next run of lines sharing a join key, or nil at the end
+// of the file.
+func (in *input) group() ([]joinLine, error) {
+	var first joinLine
+
+	if in.pending != nil {
+		first, in.pending = *in.pending, nil
 	} else {
-		f, err := os.Open(path)
+		line, ok, err := in.readLine()
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		first = line
+	}
+
+	key := first.key(in.field)
+	if err := in.checkOrder(key); err != nil {
+		return nil, err
+	}
+
+	group := []joinLine{first}
+
+	for {
+		line, ok, err := in.readLine()
 		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("join: %s", err))
-			}
-			return nil, fmt.Errorf("join: %w", err)
+			return nil, err
+		}
+
+		if !ok {
+			return group, nil
 		}
 
-		defer func() {
-			_ = f.Close()
-		}()
+		if compareKeys(line.key(in.field), key, in.opts.IgnoreCase) != 0 {
+			in.pending = &line
+			return group, nil
+		}
 
-		r = f
+		group = append(group, line)
 	}
+}
 
-	var lines []joinLine
+// checkOrder verifies that a new group's key does not sort before the
+// previous group's key.
+func (in *input) checkOrder(key string) error {
+	defer func() { in.lastKey, in.seen = key, true }()
 
-	scanner := bufio.NewScanner(r)
-	idx := 0
+	if !in.seen || in.opts.NoCheckOrder || in.unsorted || compareKeys(key, in.lastKey, in.opts.IgnoreCase) >= 0 {
+		return nil
+	}
+
+	if in.opts.CheckOrder {
+		return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("join: file %d is not in sorted order", in.num))
+	}
+
+	in.unsorted = true
+	_, _ = fmt.Fprintf(os.Stderr, "join: file %d is not in sorted order\n", in.num)
+
+	return nil
+}
+
+// compareGroups orders two groups by key; an exhausted input sorts last.
+func compareGroups(g1, g2 []joinLine, in1, in2 *input, fold bool) int {
+	switch {
+	case len(g1) == 0:
+		return 1
+	case len(g2) == 0:
+		return -1
+	default:
+		return compareKeys(g1[0].key(in1.field), g2[0].key(in2.field), fold)
+	}
+}
+
+func compareKeys(a, b string, fold bool) int {
+	if fold {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+
+	return strings.Compare(a, b)
+}
+
+// outputField is one -o entry: file 0 is the join field, otherwise
+// field (1-based) of file 1 or 2.
+type outputField struct {
+	file  int
+	field int
+}
+
+// parseOutputFormat parses -o: "auto", or a list of 0 and FILENUM.FIELD
+// separated by commas or blanks. An empty spec means the default layout.
+func parseOutputFormat(spec string) ([]outputField, error) {
+	if spec == "" || spec == "auto" {
+		return nil, nil
+	}
 
-	for scanner.Scan() {
-		text := scanner.Text()
+	var format []outputField
 
-		var fields []string
-		if sep == " " {
-			fields = strings.Fields(text)
-		} else {
-			fields = strings.Split(text, sep)
+	for _, item := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if item == "0" {
+			format = append(format, outputField{})
+			continue
 		}
 
-		lines = append(lines, joinLine{fields: fields, index: idx})
-		idx++
+		file, field, ok := strings.Cut(item, ".")
+		n, err := strconv.Atoi(field)
+
+		if !ok || (file != "1" && file != "2") || err != nil || n < 1 {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("join: invalid field specifier: %q", item))
+		}
+
+		format = append(format, outputField{file: int(file[0] - '0'), field: n})
 	}
 
-	return lines, scanner.Err()
+	return format, nil
 }
 
-// joinedFields builds the output fields for a joined pair: the join field
-// first, then the remaining fields of file 1, then the remaining fields of
-// file 2.
-func joinedFields(line1, line2 joinLine, opts JoinOptions) []string {
+// joinedFields builds the output fields for a joined pair: by default the
+// join field first, then the remaining fields of file 1, then the remaining
+// fields of file 2.
+func joinedFields(line1, line2 *joinLine, format []outputField, opts JoinOptions) []string {
+	if format != nil {
+		return formatFields(line1, line2, format, opts)
+	}
+
 	var parts []string
 
 	// Add join field
@@ -215,3 +388,100 @@ func joinedFields(line1, line2 joinLine, opts JoinOptions) []string {
 
 	return parts
 }
+
+// unpairedFields formats a line without a partner (line1 or line2 is nil).
+// Without -o the line is printed with its join field first.
+func unpairedFields(line1, line2 *joinLine, format []outputField, opts JoinOptions) []string {
+	if format != nil {
+		return formatFields(line1, line2, format, opts)
+	}
+
+	line, field := line1, opts.Field1-1
+	if line == nil {
+		line, field = line2, opts.Field2-1
+	}
+
+	if field >= len(line.fields) {
+		return line.fields
+	}
+
+	parts := []string{line.fields[field]}
+
+	for i, f := range line.fields {
+		if i != field {
+			parts = append(parts, f)
+		}
+	}
+
+	return parts
+}
+
+// formatFields applies an -o list; fields missing from a line, or from the
+// absent side of an unpaired line, become -e EMPTY.
+func formatFields(line1, line2 *joinLine, format []outputField, opts JoinOptions) []string {
+	parts := make([]string, 0, len(format))
+
+	for _, of := range format {
+		var (
+			value string
+			found bool
+		)
+
+		switch {
+		case of.file == 0 && line1 != nil:
+			value, found = line1.key(opts.Field1-1), opts.Field1 <= len(line1.fields)
+		case of.file == 0 && line2 != nil:
+			value, found = line2.key(opts.Field2-1), opts.Field2 <= len(line2.fields)
+		case of.file == 1 && line1 != nil:
+			value, found = line1.key(of.field-1), of.field <= len(line1.fields)
+		case of.file == 2 && line2 != nil:
+			value, found = line2.key(of.field-1), of.field <= len(line2.fields)
+		}
+
+		if !found {
+			value = opts.Empty
+		}
+
+		parts = append(parts, value)
+	}
+
+	return parts
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("join: %s", err))
+		}
+
+		return nil, fmt.Errorf("join: %w", err)
+	}
+
+	return f, nil
+}
+
+// splitFunc returns a split function for lines ending in delim.
+func splitFunc(delim byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		for i, b := range data {
+			if b == delim {
+				return i + 1, data[:i], nil
+			}
+		}
+
+		if atEOF {
+			return len(data), data, nil
+		}
+
+		return 0, nil, nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	outpkg "github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
@@ -270,3 +271,77 @@ func TestRunJoin_JSON(t *testing.T) {
 		t.Fatalf("text path changed unexpectedly: %q", txt.String())
 	}
 }
+
+func TestRunJoinStreaming(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return p
+	}
+
+	people := write("people", "id name\n1 alice\n2 bob\n2 bobby\n4 dave\n")
+	orders := write("orders", "id item\n2 pen\n3 ink\n4 pad\n4 cup\n")
+
+	run := func(opts JoinOptions) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		if err := RunJoin(&buf, []string{people, orders}, opts); err != nil {
+			t.Fatalf("RunJoin(%+v) error = %v", opts, err)
+		}
+
+		return buf.String()
+	}
+
+	t.Run("header and many-to-many", func(t *testing.T) {
+		got := run(JoinOptions{Header: true})
+		want := "id name item\n2 bob pen\n2 bobby pen\n4 dave pad\n4 dave cup\n"
+
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("unpaired in merge order", func(t *testing.T) {
+		got := run(JoinOptions{Header: true, Unpaired1: true, Unpaired2: true})
+		want := "id name item\n1 alice\n2 bob pen\n2 bobby pen\n3 ink\n4 dave pad\n4 dave cup\n"
+
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("output format with empty", func(t *testing.T) {
+		got := run(JoinOptions{Header: true, Unpaired2: true, OutputFields: "0,1.2,2.2", Empty: "-"})
+		want := "id name item\n2 bob pen\n2 bobby pen\n3 - ink\n4 dave pad\n4 dave cup\n"
+
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("invalid output format", func(t *testing.T) {
+		err := RunJoin(&bytes.Buffer{}, []string{people, orders}, JoinOptions{OutputFields: "3.1"})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("got %v, want invalid input", err)
+		}
+	})
+
+	t.Run("unsorted input", func(t *testing.T) {
+		unsorted := write("unsorted", "b x\na y\n")
+
+		err := RunJoin(&bytes.Buffer{}, []string{unsorted, orders}, JoinOptions{CheckOrder: true})
+		if !cmderr.IsConflict(err) {
+			t.Errorf("--check-order: got %v, want conflict", err)
+		}
+
+		err = RunJoin(&bytes.Buffer{}, []string{unsorted, orders}, JoinOptions{})
+		if cmderr.ExitCodeFor(err) != 1 {
+			t.Errorf("default order check: got %v, want exit status 1", err)
+		}
+	})
+}
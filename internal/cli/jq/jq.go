@@ -14,20 +14,31 @@ import (
 
 // JqOptions configures the jq command behavior
 type JqOptions struct {
-	Raw        bool // -r: output raw strings (no quotes)
-	Compact    bool // -c: compact output (no pretty print)
-	Slurp      bool // -s: read entire input into array
-	NullInput  bool // -n: don't read any input
-	Tab        bool // --tab: use tabs for indentation
-	Sort       bool // -S: sort object keys
-	Color      bool // -C: colorize output (not implemented)
-	Monochrome bool // -M: monochrome output
+	Raw        bool   // -r: output raw strings (no quotes)
+	Compact    bool   // -c: compact output (no pretty print)
+	Slurp      bool   // -s: read entire input into array
+	NullInput  bool   // -n: don't read any input
+	Tab        bool   // --tab: use tabs for indentation
+	Sort       bool   // -S: sort object keys
+	Color      bool   // -C: colorize output (not implemented)
+	Monochrome bool   // -M: monochrome output
+	Syntax     string // --syntax: query language (jq, jsonpath, pointer)
 }
 
 // RunJq executes jq-like JSON processing
 // r is the default input reader (used when no files are specified)
 func RunJq(w io.Writer, r io.Reader, args []string, opts JqOptions) error {
-	filter := "."
+	syntax, err := jsonutil.ParseSyntax(opts.Syntax)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("jq: %s", err))
+	}
+
+	// Identity in each syntax
+	filter := map[jsonutil.Syntax]string{
+		jsonutil.SyntaxJQ:       ".",
+		jsonutil.SyntaxJSONPath: "$",
+		jsonutil.SyntaxPointer:  "",
+	}[syntax]
 
 	var files []string
 
@@ -106,7 +117,11 @@ func RunJq(w io.Writer, r io.Reader, args []string, opts JqOptions) error {
 	}
 
 	for _, input := range inputs {
-		results, err := ApplyJqFilter(input, filter)
+		results, err := jsonutil.Apply(input, syntax, filter)
+		if errors.Is(err, jsonutil.ErrPointerNotFound) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("jq: %s", err))
+		}
+
 		if err != nil {
 			return fmt.Errorf("jq: %w", err)
 		}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 func TestRunJq(t *testing.T) {
@@ -439,3 +441,44 @@ func TestRunJq(t *testing.T) {
 		}
 	})
 }
+
+func TestRunJqSyntax(t *testing.T) {
+	input := `{"items":[{"name":"a","price":5},{"name":"b","price":15}],"a/b":1}`
+
+	tests := []struct {
+		name   string
+		syntax string
+		args   []string
+		want   string
+	}{
+		{"jsonpath filter", "jsonpath", []string{"$.items[?(@.price < 10)].name"}, "a\n"},
+		{"jsonpath identity", "jsonpath", nil, "{\"a/b\":1,\"items\":[{\"name\":\"a\",\"price\":5},{\"name\":\"b\",\"price\":15}]}\n"},
+		{"pointer", "pointer", []string{"/items/1/name"}, "b\n"},
+		{"pointer escaped", "pointer", []string{"/a~1b"}, "1\n"},
+		{"jq explicit", "jq", []string{".items[0].name"}, "a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := RunJq(&buf, strings.NewReader(input), tt.args, JqOptions{Syntax: tt.syntax, Raw: true, Compact: true})
+			if err != nil {
+				t.Fatalf("RunJq() error = %v", err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("RunJq() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := RunJq(&buf, strings.NewReader(input), []string{"/missing"}, JqOptions{Syntax: "pointer"}); !cmderr.IsNotFound(err) {
+		t.Errorf("missing pointer error = %v, want ErrNotFound", err)
+	}
+
+	if err := RunJq(&buf, strings.NewReader(input), nil, JqOptions{Syntax: "xpath"}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("unknown syntax error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/jsonutil"
 )
 
 // Options configures the json format command behavior
@@ -22,6 +23,8 @@ type Options struct {
 	EscapeHTML   bool          // -e: escape HTML characters
 	OutputFormat output.Format // output format (for validate mode)
 	Tab          bool          // -t: use tabs for indentation
+	Canonical    bool          // --canonical: RFC 8785 (JCS) canonical output
+	JSONC        bool          // --jsonc: accept comments and trailing commas
 }
 
 // Result represents the JSON output for validate mode
@@ -95,9 +98,25 @@ func processReader(w io.Writer, r io.Reader, filename string, opts Options, json
 		return fmt.Errorf("json: %w", err)
 	}
 
+	if opts.JSONC {
+		data = jsonutil.StripJSONC(data)
+	}
+
+	if opts.Canonical && !opts.Validate {
+		canonical, err := jsonutil.Canonicalize(data)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("json: %s: %v", filename, err))
+		}
+
+		_, _ = w.Write(canonical)
+		_, _ = fmt.Fprintln(w)
+
+		return nil
+	}
+
 	// Parse JSON
-	var v any
-	if err := json.Unmarshal(data, &v); err != nil {
+	v, err := decode(data)
+	if err != nil {
 		if opts.Validate {
 			if jsonMode {
 				return f.Print(Result{Valid: false, Error: err.Error(), File: filename})
@@ -148,6 +167,24 @@ func processReader(w io.Writer, r io.Reader, filename string, opts Options, json
 	return nil
 }
 
+// decode parses data keeping numbers as json.Number, so integers beyond
+// 2^53 and literals such as 1.10 are written back unchanged.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+
+	return v, nil
+}
+
 // sortKeys recursively sorts object keys
 func sortKeys(v any) any {
 	switch val := v.(type) {
@@ -228,8 +265,16 @@ func SortKeys(data []byte) ([]byte, error) {
 
 // Format formats JSON with custom options
 func Format(data []byte, opts Options) ([]byte, error) {
-	var v any
-	if err := json.Unmarshal(data, &v); err != nil {
+	if opts.JSONC {
+		data = jsonutil.StripJSONC(data)
+	}
+
+	if opts.Canonical {
+		return jsonutil.Canonicalize(data)
+	}
+
+	v, err := decode(data)
+	if err != nil {
 		return nil, err
 	}
 
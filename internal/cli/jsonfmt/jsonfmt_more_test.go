@@ -139,3 +139,44 @@ func TestBeautifyAndMinifyHelpers(t *testing.T) {
 		t.Error("expected Beautify error on bad JSON")
 	}
 }
+
+// TestRunJSONFmtCanonicalAndJSONC covers --canonical, --jsonc and number
+// preservation.
+func TestRunJSONFmtCanonicalAndJSONC(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.jsonc")
+	if err := os.WriteFile(src, []byte("{\n  // note\n  \"b\": 1.50,\n  \"a\": 12345678901234567890, /* big */\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RunJSONFmt(&buf, []string{src}, Options{}); err == nil {
+		t.Error("expected error for JSONC input without --jsonc")
+	}
+
+	buf.Reset()
+
+	if err := RunJSONFmt(&buf, []string{src}, Options{JSONC: true, Minify: true}); err != nil {
+		t.Fatalf("RunJSONFmt(--jsonc): %v", err)
+	}
+
+	if got, want := buf.String(), "{\"a\":12345678901234567890,\"b\":1.50}\n"; got != want {
+		t.Errorf("RunJSONFmt(--jsonc) = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	if err := RunJSONFmt(&buf, []string{src}, Options{JSONC: true, Canonical: true}); err != nil {
+		t.Fatalf("RunJSONFmt(--canonical): %v", err)
+	}
+
+	if got, want := buf.String(), "{\"a\":12345678901234567000,\"b\":1.5}\n"; got != want {
+		t.Errorf("RunJSONFmt(--canonical) = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	if err := RunJSONFmt(&buf, []string{src}, Options{JSONC: true, Validate: true}); err != nil {
+		t.Fatalf("RunJSONFmt(--jsonc validate): %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package jsonfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/jsonutil"
+	pkgdiff "github.com/inovacc/omni/pkg/textutil/diff"
+)
+
+// DiffOptions configures the json diff command
+type DiffOptions struct {
+	Format string // --format: text (default), patch (RFC 6902) or merge (RFC 7396)
+	Indent string // -i: indentation for patch output (default "  ")
+}
+
+// PatchOptions configures the json patch command
+type PatchOptions struct {
+	Merge   bool   // --merge: the patch is an RFC 7396 merge patch
+	Indent  string // -i: indentation string (default "  ")
+	InPlace bool   // --in-place: write the result back to the document file
+}
+
+// RunDiff compares two JSON documents. The text format lists one change
+// per line; patch and merge print a document that turns the first input
+// into the second.
+func RunDiff(w io.Writer, r io.Reader, args []string, opts DiffOptions) error {
+	if len(args) != 2 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "json diff: requires exactly two files")
+	}
+
+	a, err := readDocument(args[0], r)
+	if err != nil {
+		return err
+	}
+
+	b, err := readDocument(args[1], r)
+	if err != nil {
+		return err
+	}
+
+	indent := opts.Indent
+	if indent == "" {
+		indent = "  "
+	}
+
+	switch opts.Format {
+	case "", "text":
+		for _, op := range pkgdiff.JSONPatch(a, b) {
+			_, _ = fmt.Fprintln(w, describeOp(a, op))
+		}
+
+		return nil
+	case "patch":
+		ops := pkgdiff.JSONPatch(a, b)
+		if ops == nil {
+			ops = []pkgdiff.PatchOp{}
+		}
+
+		return writeDocument(w, ops, indent)
+	case "merge":
+		return writeDocument(w, pkgdiff.MergePatch(a, b), indent)
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("json diff: unknown format %q (want text, patch or merge)", opts.Format))
+	}
+}
+
+// describeOp renders a patch operation for the text format.
+func describeOp(doc any, op pkgdiff.PatchOp) string {
+	path := op.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	switch op.Op {
+	case "add":
+		return fmt.Sprintf("+ %s: %s", path, compact(op.Value))
+	case "remove":
+		old, _ := jsonutil.ResolvePointer(doc, op.Path)
+		return fmt.Sprintf("- %s: %s", path, compact(old))
+	default:
+		old, _ := jsonutil.ResolvePointer(doc, op.Path)
+		return fmt.Sprintf("~ %s: %s -> %s", path, compact(old), compact(op.Value))
+	}
+}
+
+func compact(v any) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// RunPatch applies a JSON Patch (or, with Merge, a JSON Merge Patch) to a
+// document. A failing operation, including a failed test, leaves the
+// document untouched.
+func RunPatch(w io.Writer, r io.Reader, args []string, opts PatchOptions) error {
+	if len(args) != 2 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "json patch: requires a document and a patch")
+	}
+
+	if opts.InPlace && args[0] == "-" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "json patch: --in-place requires a document file")
+	}
+
+	doc, err := readDocument(args[0], r)
+	if err != nil {
+		return err
+	}
+
+	patchData, err := readInput(args[1], r)
+	if err != nil {
+		return err
+	}
+
+	var result any
+
+	if opts.Merge {
+		patch, err := decode(patchData)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("json patch: %s: invalid JSON: %s", args[1], err))
+		}
+
+		result = pkgdiff.ApplyMergePatch(doc, patch)
+	} else {
+		ops, err := pkgdiff.ParseJSONPatch(patchData)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("json patch: %s: %s", args[1], err))
+		}
+
+		if result, err = pkgdiff.ApplyJSONPatch(doc, ops); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("json patch: %s", err))
+		}
+	}
+
+	indent := opts.Indent
+	if indent == "" {
+		indent = "  "
+	}
+
+	if !opts.InPlace {
+		return writeDocument(w, result, indent)
+	}
+
+	var buf bytes.Buffer
+	if err := writeDocument(&buf, result, indent); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(args[0])
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("json patch: %s", err))
+	}
+
+	if err := os.WriteFile(args[0], buf.Bytes(), info.Mode().Perm()); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("json patch: write: %s", err))
+	}
+
+	return nil
+}
+
+func readInput(path string, r io.Reader) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(r)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("json: %v", err))
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("json: %v", err))
+	}
+
+	return data, nil
+}
+
+// readDocument reads and decodes a JSON file, or stdin for "-".
+func readDocument(path string, r io.Reader) (any, error) {
+	data, err := readInput(path, r)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := decode(data)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("json: %s: invalid JSON: %s", path, err))
+	}
+
+	return v, nil
+}
+
+func writeDocument(w io.Writer, v any, indent string) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+
+	if err := enc.Encode(v); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("json: write: %s", err))
+	}
+
+	return nil
+}
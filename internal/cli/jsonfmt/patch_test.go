@@ -0,0 +1,125 @@
+package jsonfmt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func writeJSON(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	a := writeJSON(t, dir, "a.json", `{"name":"app","replicas":2,"ports":[80,443],"debug":true}`)
+	b := writeJSON(t, dir, "b.json", `{"name":"app","replicas":3,"ports":[80],"env":{"LOG":"info"}}`)
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunDiff(&buf, nil, []string{a, b}, DiffOptions{}); err != nil {
+			t.Fatalf("RunDiff() error = %v", err)
+		}
+
+		want := "- /debug: true\n+ /env: {\"LOG\":\"info\"}\n- /ports/1: 443\n~ /replicas: 2 -> 3\n"
+		if buf.String() != want {
+			t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+		}
+	})
+
+	t.Run("patch round trip", func(t *testing.T) {
+		var patch bytes.Buffer
+		if err := RunDiff(&patch, nil, []string{a, b}, DiffOptions{Format: "patch"}); err != nil {
+			t.Fatalf("RunDiff() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := RunPatch(&out, &patch, []string{a, "-"}, PatchOptions{Indent: ""}); err != nil {
+			t.Fatalf("RunPatch() error = %v", err)
+		}
+
+		var same bytes.Buffer
+		_ = RunDiff(&same, &out, []string{"-", b}, DiffOptions{})
+
+		if same.Len() != 0 {
+			t.Errorf("patched document differs from target:\n%s", same.String())
+		}
+	})
+
+	t.Run("merge round trip", func(t *testing.T) {
+		var patch bytes.Buffer
+		if err := RunDiff(&patch, nil, []string{a, b}, DiffOptions{Format: "merge"}); err != nil {
+			t.Fatalf("RunDiff() error = %v", err)
+		}
+
+		if !strings.Contains(patch.String(), `"debug": null`) {
+			t.Errorf("merge patch should delete debug:\n%s", patch.String())
+		}
+
+		var out bytes.Buffer
+		if err := RunPatch(&out, &patch, []string{a, "-"}, PatchOptions{Merge: true}); err != nil {
+			t.Fatalf("RunPatch() error = %v", err)
+		}
+
+		var same bytes.Buffer
+		_ = RunDiff(&same, &out, []string{"-", b}, DiffOptions{})
+
+		if same.Len() != 0 {
+			t.Errorf("patched document differs from target:\n%s", same.String())
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := RunDiff(&buf, nil, []string{a, b}, DiffOptions{Format: "yaml"}); !errors.Is(err, cmderr.ErrInvalidInput) {
+			t.Errorf("unknown format error = %v", err)
+		}
+
+		if err := RunDiff(&buf, nil, []string{a, filepath.Join(dir, "missing.json")}, DiffOptions{}); !cmderr.IsNotFound(err) {
+			t.Errorf("missing file error = %v", err)
+		}
+
+		bad := writeJSON(t, dir, "bad.json", `{"a":`)
+		if err := RunDiff(&buf, nil, []string{a, bad}, DiffOptions{}); !errors.Is(err, cmderr.ErrInvalidInput) {
+			t.Errorf("invalid JSON error = %v", err)
+		}
+	})
+}
+
+func TestRunPatchInPlace(t *testing.T) {
+	dir := t.TempDir()
+	doc := writeJSON(t, dir, "doc.json", `{"version":1}`)
+	good := writeJSON(t, dir, "good.json", `[{"op":"test","path":"/version","value":1},{"op":"replace","path":"/version","value":2}]`)
+	failing := writeJSON(t, dir, "failing.json", `[{"op":"replace","path":"/version","value":3},{"op":"test","path":"/version","value":1}]`)
+
+	if err := RunPatch(&bytes.Buffer{}, nil, []string{doc, good}, PatchOptions{InPlace: true}); err != nil {
+		t.Fatalf("RunPatch() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(doc)
+	if string(data) != "{\n  \"version\": 2\n}\n" {
+		t.Errorf("document = %q", data)
+	}
+
+	// The failing test operation leaves the file untouched
+	if err := RunPatch(&bytes.Buffer{}, nil, []string{doc, failing}, PatchOptions{InPlace: true}); !errors.Is(err, cmderr.ErrInvalidInput) {
+		t.Fatalf("RunPatch() error = %v, want invalid input", err)
+	}
+
+	if after, _ := os.ReadFile(doc); !bytes.Equal(after, data) {
+		t.Errorf("document changed after a failed patch: %q", after)
+	}
+}
@@ -52,24 +52,12 @@ func RunKill(w io.Writer, args []string, opts KillOptions) error {
 	sig := defaultSignal()
 
 	if opts.Signal != "" {
-		sigName := strings.ToUpper(strings.TrimPrefix(opts.Signal, "SIG"))
-
-		s, ok := signalMap[sigName]
-		switch {
-		case ok:
-			sig = s
-		case isPlatformUnsupportedSignal(sigName):
-			return cmderr.Wrap(cmderr.ErrUnsupported,
-				fmt.Sprintf("kill: signal SIG%s not supported on windows (INT/KILL/TERM only)", sigName))
-		default:
-			// Try parsing as a number
-			sigNum, err := strconv.Atoi(opts.Signal)
-			if err != nil {
-				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("kill: unknown signal: %s", opts.Signal))
-			}
-
-			sig = syscall.Signal(sigNum)
+		s, err := ParseSignal(opts.Signal)
+		if err != nil {
+			return fmt.Errorf("kill: %w", err)
 		}
+
+		sig = s
 	}
 
 	var results []KillResult
@@ -187,6 +175,30 @@ func listSignalsJSON(_ io.Writer, f *output.Formatter) error {
 	return f.Print(signals)
 }
 
+// ParseSignal resolves a signal given by name (TERM, SIGTERM, term) or by
+// number. POSIX names the platform cannot deliver yield cmderr.ErrUnsupported;
+// anything else unrecognised yields cmderr.ErrInvalidInput.
+func ParseSignal(spec string) (syscall.Signal, error) {
+	name := strings.ToUpper(spec)
+	name = strings.TrimPrefix(name, "SIG")
+
+	if s, ok := signalMap[name]; ok {
+		return s, nil
+	}
+
+	if isPlatformUnsupportedSignal(name) {
+		return 0, cmderr.Wrap(cmderr.ErrUnsupported,
+			fmt.Sprintf("signal SIG%s not supported on windows (INT/KILL/TERM only)", name))
+	}
+
+	n, err := strconv.Atoi(spec)
+	if err != nil || n < 0 {
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unknown signal: %s", spec))
+	}
+
+	return syscall.Signal(n), nil
+}
+
 // Kill sends a signal to a process
 func Kill(pid int, sig syscall.Signal) error {
 	process, err := os.FindProcess(pid)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 )
@@ -21,16 +22,22 @@ type LnOptions struct {
 	Relative    bool // -r: create symbolic links relative to link location
 }
 
-// RunLn creates links between files
+// RunLn creates links between files.
+// With one operand a link to it is created in the current directory; with
+// two, LINK_NAME may be an existing directory to create the link in.
 func RunLn(w io.Writer, args []string, opts LnOptions) error {
-	if len(args) < 2 {
+	if len(args) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "ln: missing file operand")
 	}
 
+	if len(args) == 1 {
+		return createLink(w, args[0], filepath.Base(args[0]), opts)
+	}
+
+	dest := args[len(args)-1]
+
 	// Handle multiple sources -> directory case
 	if len(args) > 2 {
-		dest := args[len(args)-1]
-
 		info, err := os.Stat(dest)
 		if err != nil || !info.IsDir() {
 			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ln: target '%s' is not a directory", dest))
@@ -46,8 +53,27 @@ func RunLn(w io.Writer, args []string, opts LnOptions) error {
 		return nil
 	}
 
-	// Two arguments: source and link name
-	return createLink(w, args[0], args[1], opts)
+	// Two arguments: source and link name, or a directory to link into
+	if isTargetDir(dest, opts) {
+		dest = filepath.Join(dest, filepath.Base(args[0]))
+	}
+
+	return createLink(w, args[0], dest, opts)
+}
+
+// isTargetDir reports whether dest is a directory to create the link in.
+// With -n a symlink to a directory is treated as a plain file, so -sfn can
+// replace it.
+func isTargetDir(dest string, opts LnOptions) bool {
+	if opts.NoClobber {
+		if info, err := os.Lstat(dest); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return false
+		}
+	}
+
+	info, err := os.Stat(dest)
+
+	return err == nil && info.IsDir()
 }
 
 func createLink(w io.Writer, target, linkName string, opts LnOptions) error {
@@ -77,12 +103,7 @@ func createLink(w io.Writer, target, linkName string, opts LnOptions) error {
 		actualTarget := target
 
 		if opts.Relative {
-			linkDir := filepath.Dir(linkName)
-
-			relTarget, relErr := filepath.Rel(linkDir, target)
-			if relErr == nil {
-				actualTarget = relTarget
-			}
+			actualTarget = relativeTarget(target, linkName)
 		}
 
 		err = os.Symlink(actualTarget, linkName)
@@ -92,9 +113,12 @@ func createLink(w io.Writer, target, linkName string, opts LnOptions) error {
 
 	if err != nil {
 		if errors.Is(err, os.ErrPermission) {
-			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("ln: failed to create link '%s': %s", linkName, err))
+			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("ln: failed to create link '%s': %s%s", linkName, err, windowsHint(opts)))
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("ln: failed to create link '%s': %s", linkName, err))
 		}
-		return fmt.Errorf("ln: failed to create link '%s': %w", linkName, err)
+		return fmt.Errorf("ln: failed to create link '%s': %w%s", linkName, err, windowsHint(opts))
 	}
 
 	if opts.Verbose {
@@ -108,6 +132,37 @@ func createLink(w io.Writer, target, linkName string, opts LnOptions) error {
 	return nil
 }
 
+// relativeTarget returns target relative to the directory of linkName, or
+// target unchanged when no relative path exists (e.g. another volume).
+func relativeTarget(target, linkName string) string {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return target
+	}
+
+	absLinkDir, err := filepath.Abs(filepath.Dir(linkName))
+	if err != nil {
+		return target
+	}
+
+	rel, err := filepath.Rel(absLinkDir, absTarget)
+	if err != nil {
+		return target
+	}
+
+	return rel
+}
+
+// windowsHint explains why symlink creation fails for ordinary Windows
+// accounts.
+func windowsHint(opts LnOptions) string {
+	if runtime.GOOS != "windows" || !opts.Symbolic {
+		return ""
+	}
+
+	return " (creating symbolic links on Windows needs Developer Mode or an elevated prompt; use a hard link for files)"
+}
+
 // Symlink creates a symbolic link
 func Symlink(target, linkName string) error {
 	return os.Symlink(target, linkName)
@@ -227,3 +227,63 @@ func TestLink(t *testing.T) {
 		t.Error("Link() should create hard link")
 	}
 }
+
+func TestRunLn_TargetDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping symlink tests on Windows")
+	}
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	_ = os.WriteFile("file.txt", []byte("content"), 0644)
+	_ = os.Mkdir("d1", 0755)
+	_ = os.Mkdir("d2", 0755)
+
+	var buf bytes.Buffer
+
+	// An existing directory as LINK_NAME receives the link
+	if err := RunLn(&buf, []string{"file.txt", "d1"}, LnOptions{}); err != nil {
+		t.Fatalf("RunLn() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join("d1", "file.txt")); err != nil {
+		t.Errorf("link not created inside directory: %v", err)
+	}
+
+	// -r makes the target relative to the link's directory
+	if err := RunLn(&buf, []string{"file.txt", filepath.Join("d2", "rel")}, LnOptions{Symbolic: true, Relative: true}); err != nil {
+		t.Fatalf("RunLn() error = %v", err)
+	}
+
+	if got, _ := os.Readlink(filepath.Join("d2", "rel")); got != filepath.Join("..", "file.txt") {
+		t.Errorf("relative target = %q", got)
+	}
+
+	// -sfn replaces a symlink to a directory instead of linking inside it
+	_ = os.Symlink("d1", "current")
+
+	if err := RunLn(&buf, []string{"d2", "current"}, LnOptions{Symbolic: true, Force: true, NoClobber: true}); err != nil {
+		t.Fatalf("RunLn() error = %v", err)
+	}
+
+	if got, _ := os.Readlink("current"); got != "d2" {
+		t.Errorf("current -> %q, want d2", got)
+	}
+
+	if _, err := os.Lstat(filepath.Join("d1", "d2")); !os.IsNotExist(err) {
+		t.Error("-n still linked inside the old directory")
+	}
+
+	// One operand links into the current directory
+	_ = os.Mkdir("sub", 0755)
+	_ = os.WriteFile(filepath.Join("sub", "tool"), []byte("x"), 0644)
+
+	if err := RunLn(&buf, []string{filepath.Join(dir, "sub", "tool")}, LnOptions{Symbolic: true}); err != nil {
+		t.Fatalf("RunLn() error = %v", err)
+	}
+
+	if _, err := os.Lstat("tool"); err != nil {
+		t.Errorf("single operand: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Package lock serializes omni commands across processes with an advisory
+// lock file, so concurrent CI jobs and Taskfile invocations can take turns
+// on a shared resource.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/command"
+	"github.com/inovacc/omni/internal/logger"
+	"github.com/inovacc/omni/pkg/flock"
+)
+
+// Options configures lock acquire.
+type Options struct {
+	Timeout time.Duration  // --timeout: give up after waiting this long (0 = wait indefinitely)
+	NoWait  bool           // -n: fail at once if the lock is held
+	Shared  bool           // -s: take a shared lock instead of an exclusive one
+	Verbose bool           // -v: report waiting and acquisition on stderr
+	Stdin   io.Reader      // standard input for the command
+	Logger  *logger.Logger // receives acquire and release events
+}
+
+// RunAcquire takes the lock on path, runs the omni command args through
+// runner while holding it, and releases it when the command returns. The
+// command's error, and so its exit status, is returned unchanged.
+//
+// The lock file is created if needed and left in place afterwards; the
+// lock itself dies with the process, so a killed job never leaves it held.
+func RunAcquire(ctx context.Context, stdout, stderr io.Writer, runner command.Command, path string, args []string, opts Options) error {
+	if path == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "lock acquire: missing lock file")
+	}
+
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "lock acquire: no command specified (usage: lock acquire FILE -- COMMAND [ARG]...)")
+	}
+
+	if opts.Timeout < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "lock acquire: --timeout must not be negative")
+	}
+
+	l := flock.New(path)
+
+	start := time.Now()
+
+	if err := acquire(ctx, stderr, l, opts); err != nil {
+		return err
+	}
+
+	acquired := time.Now()
+	waited := acquired.Sub(start)
+
+	defer func() {
+		_ = l.Unlock()
+
+		opts.Logger.LogRaw("lock_release", "path", path, "held_ms", time.Since(acquired).Milliseconds())
+	}()
+
+	if opts.Verbose {
+		_, _ = fmt.Fprintf(stderr, "lock: acquired %s after %s\n", path, waited.Round(time.Millisecond))
+	}
+
+	opts.Logger.LogRaw("lock_acquire", "path", path, "shared", opts.Shared, "wait_ms", waited.Milliseconds(), "cmd", args)
+
+	return runner.Run(ctx, stdout, opts.Stdin, args)
+}
+
+// acquire takes l according to opts, mapping a busy lock to ErrConflict and
+// an expired wait to ErrTimeout.
+func acquire(ctx context.Context, stderr io.Writer, l *flock.Lock, opts Options) error {
+	try, wait := l.TryLock, l.Lock
+	if opts.Shared {
+		try, wait = l.TryRLock, l.RLock
+	}
+
+	ok, err := try()
+	if err != nil {
+		return lockError(l.Path(), err)
+	}
+
+	if ok {
+		return nil
+	}
+
+	if opts.NoWait {
+		return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("lock acquire: %s is held by another process", l.Path()))
+	}
+
+	if opts.Verbose {
+		_, _ = fmt.Fprintf(stderr, "lock: waiting for %s\n", l.Path())
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	err = wait(ctx)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded) && opts.Timeout > 0:
+		return cmderr.Wrap(cmderr.ErrTimeout, fmt.Sprintf("lock acquire: timed out after %s waiting for %s", opts.Timeout, l.Path()))
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("lock acquire: %s: %w", l.Path(), err)
+	default:
+		return lockError(l.Path(), err)
+	}
+}
+
+func lockError(path string, err error) error {
+	switch {
+	case errors.Is(err, flock.ErrUnsupported):
+		return cmderr.Wrap(cmderr.ErrUnsupported, "lock acquire: file locking is not supported on this platform")
+	case errors.Is(err, os.ErrNotExist):
+		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("lock acquire: %s: directory does not exist", path))
+	case errors.Is(err, os.ErrPermission):
+		return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("lock acquire: %s: permission denied", path))
+	default:
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("lock acquire: %v", err))
+	}
+}
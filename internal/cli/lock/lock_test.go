@@ -0,0 +1,165 @@
+package lock
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/command"
+	"github.com/inovacc/omni/pkg/flock"
+)
+
+// recorder is a runner that records its arguments and whether the lock was
+// held while it ran.
+type recorder struct {
+	path string
+	args []string
+	held bool
+	err  error
+}
+
+func (r *recorder) Run(_ context.Context, w io.Writer, _ io.Reader, args []string) error {
+	r.args = args
+
+	// A second holder cannot take the lock while the command runs
+	ok, _ := flock.New(r.path).TryLock()
+	r.held = !ok
+
+	_, _ = io.WriteString(w, strings.Join(args, " "))
+
+	return r.err
+}
+
+func TestRunAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.lock")
+	rec := &recorder{path: path}
+
+	var out bytes.Buffer
+
+	if err := RunAcquire(context.Background(), &out, io.Discard, rec, path, []string{"echo", "hi"}, Options{}); err != nil {
+		t.Fatalf("RunAcquire() = %v", err)
+	}
+
+	if !rec.held {
+		t.Error("the lock was not held while the command ran")
+	}
+
+	if out.String() != "echo hi" {
+		t.Errorf("output = %q, want the command's output", out.String())
+	}
+
+	if ok, _ := flock.New(path).TryLock(); !ok {
+		t.Error("the lock was not released after the command")
+	}
+}
+
+func TestRunAcquireCommandError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.lock")
+	rec := &recorder{path: path, err: cmderr.WithExitCode(cmderr.ErrIO, 7)}
+
+	err := RunAcquire(context.Background(), io.Discard, io.Discard, rec, path, []string{"false"}, Options{})
+	if got := cmderr.ExitCodeFor(err); got != 7 {
+		t.Errorf("exit code = %d (%v), want the command's 7", got, err)
+	}
+
+	if ok, _ := flock.New(path).TryLock(); !ok {
+		t.Error("the lock was not released after a failing command")
+	}
+}
+
+func TestRunAcquireBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.lock")
+
+	holder := flock.New(path)
+	if ok, _ := holder.TryLock(); !ok {
+		t.Fatal("holder.TryLock() failed")
+	}
+	defer func() { _ = holder.Unlock() }()
+
+	tests := []struct {
+		name  string
+		opts  Options
+		check func(error) bool
+	}{
+		{"no wait", Options{NoWait: true}, cmderr.IsConflict},
+		{"timeout", Options{Timeout: 30 * time.Millisecond}, cmderr.IsTimeout},
+		{"shared timeout", Options{Shared: true, Timeout: 30 * time.Millisecond}, cmderr.IsTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{path: path}
+
+			err := RunAcquire(context.Background(), io.Discard, io.Discard, rec, path, []string{"echo"}, tt.opts)
+			if !tt.check(err) {
+				t.Errorf("RunAcquire() error = %v", err)
+			}
+
+			if rec.args != nil {
+				t.Error("the command ran without the lock")
+			}
+		})
+	}
+}
+
+func TestRunAcquireWaits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.lock")
+
+	holder := flock.New(path)
+	if ok, _ := holder.TryLock(); !ok {
+		t.Fatal("holder.TryLock() failed")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		_ = holder.Unlock()
+	}()
+
+	var stderr bytes.Buffer
+
+	rec := &recorder{path: path}
+
+	err := RunAcquire(context.Background(), io.Discard, &stderr, rec, path, []string{"echo"}, Options{Timeout: 5 * time.Second, Verbose: true})
+	if err != nil {
+		t.Fatalf("RunAcquire() = %v", err)
+	}
+
+	for _, want := range []string{"lock: waiting for " + path, "lock: acquired " + path} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr %q does not contain %q", stderr.String(), want)
+		}
+	}
+}
+
+func TestRunAcquireInvalid(t *testing.T) {
+	dir := t.TempDir()
+	run := command.CommandFunc(func(context.Context, io.Writer, io.Reader, []string) error { return nil })
+
+	tests := []struct {
+		name  string
+		path  string
+		args  []string
+		opts  Options
+		check func(error) bool
+	}{
+		{"no file", "", []string{"echo"}, Options{}, cmderr.IsInvalidInput},
+		{"no command", filepath.Join(dir, "a.lock"), nil, Options{}, cmderr.IsInvalidInput},
+		{"negative timeout", filepath.Join(dir, "a.lock"), []string{"echo"}, Options{Timeout: -time.Second}, cmderr.IsInvalidInput},
+		{"missing directory", filepath.Join(dir, "missing", "a.lock"), []string{"echo"}, Options{}, cmderr.IsNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RunAcquire(context.Background(), io.Discard, io.Discard, run, tt.path, tt.args, tt.opts)
+			if !tt.check(err) {
+				t.Errorf("RunAcquire() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package logs reports on the JSON command logs written by the omni logger.
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/logger"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/tablewriter"
+)
+
+// StatsOptions configures the logs stats command
+type StatsOptions struct {
+	Dir     string        // Log directory (default: configured logger path)
+	Since   time.Duration // Only include executions newer than this (0 = all)
+	Command string        // Only include this command
+	Sort    string        // Sort key: count, errors, rate, p50, p95
+	Limit   int           // Show at most N commands (0 = all)
+
+	OutputFormat output.Format // output format (text/json/table)
+}
+
+// RunStats aggregates the log directory and prints per-command metrics.
+func RunStats(w io.Writer, opts StatsOptions) error {
+	if opts.Dir == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "logs stats: no log directory (configure with 'omni logger --path' or pass --dir)")
+	}
+
+	info, err := os.Stat(opts.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("logs stats: log directory does not exist: %s", opts.Dir))
+		}
+
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("logs stats: %s", err))
+	}
+
+	if !info.IsDir() {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("logs stats: not a directory: %s", opts.Dir))
+	}
+
+	statsOpts := logger.StatsOptions{Command: opts.Command}
+	if opts.Since > 0 {
+		statsOpts.Since = time.Now().Add(-opts.Since)
+	}
+
+	report, err := logger.Stats(opts.Dir, statsOpts)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("logs stats: %s", err))
+	}
+
+	if err := sortCommands(report.Commands, opts.Sort); err != nil {
+		return err
+	}
+
+	if opts.Limit > 0 && len(report.Commands) > opts.Limit {
+		report.Commands = report.Commands[:opts.Limit]
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(report)
+	}
+
+	if len(report.Commands) == 0 {
+		_, _ = fmt.Fprintf(w, "No command executions found in %s\n", opts.Dir)
+		return nil
+	}
+
+	if err := f.PrintTable(statsTable(report.Commands, opts.Sort)); err != nil {
+		return err
+	}
+
+	// The summary line would break Markdown and CSV documents.
+	if format := f.Format(); format == output.FormatMarkdown || format == output.FormatCSV {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d executions across %d log files", report.Entries, report.Files)
+	if report.Skipped > 0 {
+		_, _ = fmt.Fprintf(w, " (%d unreadable lines skipped)", report.Skipped)
+	}
+
+	_, _ = fmt.Fprintln(w)
+
+	return nil
+}
+
+// statsTable lays the per-command metrics out as a table, marking the
+// column sortKey orders by.
+func statsTable(cmds []logger.CommandStats, sortKey string) *tablewriter.Table {
+	sorted := map[string]string{"": "RUNS", "count": "RUNS", "errors": "ERRORS", "rate": "ERROR%", "p50": "P50", "p95": "P95"}[sortKey]
+
+	t := tablewriter.New()
+	for _, h := range []string{"COMMAND", "RUNS", "ERRORS", "ERROR%", "P50", "P95", "MAX"} {
+		col := tablewriter.Column{Header: h}
+		if h != "COMMAND" {
+			col.Align = tablewriter.AlignRight
+			col.NoShrink = true
+		}
+
+		if h == sorted {
+			col.Sort = tablewriter.Descending
+		}
+
+		t.Columns = append(t.Columns, col)
+	}
+
+	for _, c := range cmds {
+		t.Append(c.Command, strconv.Itoa(c.Count), strconv.Itoa(c.Errors), fmt.Sprintf("%.1f%%", c.ErrorRate*100),
+			formatMs(c.P50Ms), formatMs(c.P95Ms), formatMs(c.MaxMs))
+	}
+
+	return t
+}
+
+// sortCommands orders the report by the given key, highest first.
+// The aggregator already sorts by count.
+func sortCommands(cmds []logger.CommandStats, key string) error {
+	var less func(a, b logger.CommandStats) bool
+
+	switch key {
+	case "", "count":
+		return nil
+	case "errors":
+		less = func(a, b logger.CommandStats) bool { return a.Errors > b.Errors }
+	case "rate":
+		less = func(a, b logger.CommandStats) bool { return a.ErrorRate > b.ErrorRate }
+	case "p50":
+		less = func(a, b logger.CommandStats) bool { return a.P50Ms > b.P50Ms }
+	case "p95":
+		less = func(a, b logger.CommandStats) bool { return a.P95Ms > b.P95Ms }
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("logs stats: invalid sort key %q (want count, errors, rate, p50 or p95)", key))
+	}
+
+	sort.SliceStable(cmds, func(i, j int) bool { return less(cmds[i], cmds[j]) })
+
+	return nil
+}
+
+// formatMs renders a millisecond duration compactly (e.g. 850ms, 1.2s)
+func formatMs(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
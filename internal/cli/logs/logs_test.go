@@ -0,0 +1,81 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/logger"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+const sampleLog = `{"msg":"command_end","cmd":"grep","status":"success","duration_ms":10,"start_time":"2026-01-01T00:00:00Z"}
+{"msg":"command_end","cmd":"grep","status":"error","duration_ms":30,"start_time":"2026-01-01T00:00:00Z"}
+{"msg":"command_end","cmd":"ls","status":"success","duration_ms":1500,"start_time":"2026-01-01T00:00:00Z"}
+{"msg":"command_end","cmd":"cat","status":"error","duration_ms":5,"start_time":"2026-01-01T00:00:00Z"}
+`
+
+func writeLogs(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "abc-grep.log"), []byte(sampleLog), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestRunStats(t *testing.T) {
+	dir := writeLogs(t)
+
+	var buf bytes.Buffer
+	if err := RunStats(&buf, StatsOptions{Dir: dir}); err != nil {
+		t.Fatalf("RunStats() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"COMMAND", "grep", "50.0%", "1.5s", "4 executions across 1 log files"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunStatsJSONSortLimit(t *testing.T) {
+	dir := writeLogs(t)
+
+	var buf bytes.Buffer
+	if err := RunStats(&buf, StatsOptions{Dir: dir, Sort: "rate", Limit: 1, OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatal(err)
+	}
+
+	var report logger.StatsReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if len(report.Commands) != 1 || report.Commands[0].Command != "cat" {
+		t.Errorf("unexpected commands: %+v", report.Commands)
+	}
+}
+
+func TestRunStatsErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunStats(&buf, StatsOptions{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("no dir: expected invalid input, got %v", err)
+	}
+
+	if err := RunStats(&buf, StatsOptions{Dir: filepath.Join(t.TempDir(), "x")}); !cmderr.IsNotFound(err) {
+		t.Errorf("missing dir: expected not found, got %v", err)
+	}
+
+	if err := RunStats(&buf, StatsOptions{Dir: writeLogs(t), Sort: "bogus"}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad sort: expected invalid input, got %v", err)
+	}
+}
@@ -8,11 +8,13 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/tablewriter"
 )
 
 // Options configures the ls command behavior
@@ -277,7 +279,30 @@ func printEntries(w io.Writer, entries []Entry, opts Options) {
 	}
 }
 
+// printLongFormat prints one row per entry. Plain listings have no header
+// row and single-space columns, like ls -l; Markdown and CSV output get a
+// header.
 func printLongFormat(w io.Writer, entries []Entry, opts Options) {
+	f := output.New(w, opts.OutputFormat)
+
+	var cols []tablewriter.Column
+	if opts.Inode {
+		cols = append(cols, tablewriter.Column{Header: "INODE", Align: tablewriter.AlignRight, Width: 8, NoShrink: true})
+	}
+
+	cols = append(cols,
+		tablewriter.Column{Header: "MODE", NoShrink: true},
+		tablewriter.Column{Header: "SIZE", Align: tablewriter.AlignRight, Width: 8, NoShrink: true},
+		tablewriter.Column{Header: "MODIFIED", NoShrink: true},
+		tablewriter.Column{Header: "NAME", NoShrink: true}) // names are never cut, as in ls
+
+	t := tablewriter.New(cols...)
+	t.NoHeader = f.Format() == output.FormatText || f.Format() == output.FormatTable
+
+	if f.Format() == output.FormatText {
+		t.Gap = " "
+	}
+
 	for _, e := range entries {
 		name := e.Name
 		if opts.Classify {
@@ -298,12 +323,15 @@ func printLongFormat(w io.Writer, entries []Entry, opts Options) {
 			timeStr = e.ModTime.Format("Jan _2  2006")
 		}
 
+		var row []string
 		if opts.Inode {
-			_, _ = fmt.Fprintf(w, "%8d ", e.Inode)
+			row = append(row, strconv.FormatUint(e.Inode, 10))
 		}
 
-		_, _ = fmt.Fprintf(w, "%s %8s %s %s\n", e.Mode, size, timeStr, name)
+		t.Append(append(row, e.Mode, size, timeStr, name)...)
 	}
+
+	_ = f.PrintTable(t)
 }
 
 func printOnePerLine(w io.Writer, entries []Entry, opts Options) {
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
@@ -590,3 +591,32 @@ func TestRun(t *testing.T) {
 		}
 	})
 }
+
+// TestLongFormatGolden pins the plain ls -l layout scripts parse: single
+// spaces between columns and the size right-aligned in eight.
+func TestLongFormatGolden(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+
+	if err := os.WriteFile(path, []byte("0123456789abcdef"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mtime := time.Date(2020, time.March, 4, 10, 40, 0, 0, time.Local)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Run(&buf, []string{dir}, Options{LongFormat: true}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got, want := buf.String(), "-rw-r--r--       16 Mar  4  2020 a.txt\n"; got != want {
+		t.Errorf("ls -l = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+// Package mkfifo creates named pipes (FIFOs).
+package mkfifo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// DefaultMode is the permission of a new FIFO before the umask.
+const DefaultMode fs.FileMode = 0o666
+
+// Options configures the mkfifo command behavior
+type Options struct {
+	Mode string // -m: octal permission bits, set exactly (not masked by umask)
+}
+
+// RunMkfifo creates a FIFO for each name. Names that already exist are an
+// error. On Windows, which has no filesystem FIFOs, it fails with an
+// unsupported error.
+func RunMkfifo(args []string, opts Options) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "mkfifo: missing operand")
+	}
+
+	mode := DefaultMode
+
+	if opts.Mode != "" {
+		m, err := strconv.ParseUint(opts.Mode, 8, 32)
+		if err != nil || m > 0o777 {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mkfifo: invalid mode '%s'", opts.Mode))
+		}
+
+		mode = fs.FileMode(m)
+	}
+
+	for _, path := range args {
+		if err := mkfifo(path, uint32(mode)); err != nil {
+			return wrapError(path, err)
+		}
+
+		// Like GNU mkfifo, an explicit -m is applied without the umask
+		if opts.Mode != "" {
+			if err := os.Chmod(path, mode); err != nil {
+				return wrapError(path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func wrapError(path string, err error) error {
+	msg := fmt.Sprintf("mkfifo: cannot create fifo '%s': %s", path, err)
+
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		return cmderr.Wrap(cmderr.ErrUnsupported, "mkfifo: named pipes are not supported on this platform")
+	case errors.Is(err, os.ErrExist):
+		return cmderr.Wrap(cmderr.ErrConflict, msg)
+	case errors.Is(err, os.ErrNotExist):
+		return cmderr.Wrap(cmderr.ErrNotFound, msg)
+	case errors.Is(err, os.ErrPermission):
+		return cmderr.Wrap(cmderr.ErrPermission, msg)
+	}
+
+	return fmt.Errorf("mkfifo: cannot create fifo '%s': %w", path, err)
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package mkfifo
+
+import "errors"
+
+// mkfifo is unsupported: Windows named pipes live in \\.\pipe\ and are not
+// files.
+func mkfifo(string, uint32) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,55 @@
+package mkfifo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestRunMkfifo(t *testing.T) {
+	dir := t.TempDir()
+	fifo := filepath.Join(dir, "pipe")
+
+	err := RunMkfifo([]string{fifo}, Options{Mode: "600"})
+	if runtime.GOOS == "windows" {
+		if !cmderr.IsUnsupported(err) {
+			t.Errorf("RunMkfifo() error = %v, want unsupported", err)
+		}
+
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("RunMkfifo() error = %v", err)
+	}
+
+	info, err := os.Lstat(fifo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode()&os.ModeNamedPipe == 0 || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want named pipe 0600", info.Mode())
+	}
+
+	if err := RunMkfifo([]string{fifo}, Options{}); !cmderr.IsConflict(err) {
+		t.Errorf("existing: error = %v, want conflict", err)
+	}
+
+	if err := RunMkfifo([]string{filepath.Join(dir, "no", "pipe")}, Options{}); !cmderr.IsNotFound(err) {
+		t.Errorf("missing dir: error = %v, want not found", err)
+	}
+}
+
+func TestRunMkfifoErrors(t *testing.T) {
+	if err := RunMkfifo(nil, Options{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("no operand: error = %v", err)
+	}
+
+	if err := RunMkfifo([]string{"x"}, Options{Mode: "u+rw"}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad mode: error = %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package mktemp creates unique temporary files and directories.
+package mktemp
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// DefaultTemplate is used when no template is given.
+const DefaultTemplate = "tmp.XXXXXXXXXX"
+
+// minX is the minimum number of trailing X characters in a template.
+const minX = 3
+
+// maxAttempts bounds retries when a generated name already exists.
+const maxAttempts = 100
+
+const nameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Options configures the mktemp command behavior
+type Options struct {
+	Directory    bool          // -d: create a directory instead of a file
+	DryRun       bool          // -u: only print a name, create nothing (unsafe)
+	TmpDir       string        // -p: create relative to this directory
+	Template     string        // -t: template created in the temporary directory
+	Suffix       string        // --suffix: append this to the template
+	OutputFormat output.Format // output format (text/json)
+}
+
+// Result is the JSON output of mktemp
+type Result struct {
+	Path      string `json:"path"`
+	Directory bool   `json:"directory"`
+}
+
+// RunMktemp creates a temporary file (mode 0600) or directory (mode 0700)
+// and prints its path.
+//
+// With no template, or with Options.Template, the name is created in
+// TmpDir, falling back to $TMPDIR (%TMP% on Windows) and then the system
+// default. A template operand is relative to the current directory unless
+// TmpDir is set, as with GNU mktemp.
+func RunMktemp(w io.Writer, args []string, opts Options) error {
+	if len(args) > 1 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mktemp: too many templates: %s", strings.Join(args, " ")))
+	}
+
+	template := opts.Template
+	inTmpDir := true
+
+	switch {
+	case len(args) == 1 && opts.Template != "":
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "mktemp: use either a TEMPLATE operand or --template, not both")
+	case len(args) == 1:
+		template = args[0]
+		inTmpDir = opts.TmpDir != ""
+	case template == "":
+		template = DefaultTemplate
+	}
+
+	if inTmpDir {
+		if filepath.IsAbs(template) {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mktemp: invalid template, %q; with a temporary directory, it may not be absolute", template))
+		}
+
+		if opts.Template != "" && strings.ContainsAny(template, `/\`) {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mktemp: invalid template, %q, contains directory separator", template))
+		}
+
+		dir := opts.TmpDir
+		if dir == "" {
+			dir = os.TempDir()
+		}
+
+		template = filepath.Join(dir, template)
+	}
+
+	path, err := Create(template, opts.Suffix, opts.Directory, opts.DryRun)
+	if err != nil {
+		return err
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(Result{Path: path, Directory: opts.Directory})
+	}
+
+	_, _ = fmt.Fprintln(w, path)
+
+	return nil
+}
+
+// Create replaces the trailing X characters of template's last element
+// with random letters and digits and creates the result exclusively, so an
+// existing file or symlink is never reused. With dryRun nothing is created.
+func Create(template, suffix string, dir, dryRun bool) (string, error) {
+	base := filepath.Base(template)
+
+	n := len(base) - len(strings.TrimRight(base, "X"))
+	if n < minX {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mktemp: too few X's in template %q", template))
+	}
+
+	if strings.ContainsAny(suffix, `/\`) {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("mktemp: invalid suffix %q, contains directory separator", suffix))
+	}
+
+	prefix := template[:len(template)-n]
+
+	for range maxAttempts {
+		name, err := randomName(n)
+		if err != nil {
+			return "", fmt.Errorf("mktemp: %w", err)
+		}
+
+		path := prefix + name + suffix
+
+		if dryRun {
+			if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+				return path, nil
+			}
+
+			continue
+		}
+
+		if dir {
+			err = os.Mkdir(path, 0o700)
+		} else {
+			var f *os.File
+
+			f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+			if err == nil {
+				err = f.Close()
+			}
+		}
+
+		switch {
+		case err == nil:
+			return path, nil
+		case errors.Is(err, os.ErrExist):
+			continue
+		case errors.Is(err, os.ErrNotExist):
+			return "", cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("mktemp: failed to create %s via template %q: %s", kind(dir), template, err))
+		case errors.Is(err, os.ErrPermission):
+			return "", cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("mktemp: failed to create %s via template %q: %s", kind(dir), template, err))
+		default:
+			return "", fmt.Errorf("mktemp: failed to create %s via template %q: %w", kind(dir), template, err)
+		}
+	}
+
+	return "", cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("mktemp: failed to create %s via template %q: names exhausted", kind(dir), template))
+}
+
+func kind(dir bool) string {
+	if dir {
+		return "directory"
+	}
+
+	return "file"
+}
+
+func randomName(n int) (string, error) {
+	limit := big.NewInt(int64(len(nameChars)))
+
+	b := make([]byte, n)
+	for i := range b {
+		v, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = nameChars[v.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,133 @@
+package mktemp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestRunMktemp(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("TMP", tmp)
+
+	t.Run("default file in TMPDIR", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunMktemp(&buf, nil, Options{}); err != nil {
+			t.Fatalf("RunMktemp() error = %v", err)
+		}
+
+		path := strings.TrimSpace(buf.String())
+		if filepath.Dir(path) != tmp || !strings.HasPrefix(filepath.Base(path), "tmp.") || len(filepath.Base(path)) != len(DefaultTemplate) {
+			t.Errorf("path = %q", path)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			t.Fatalf("stat %q: %v", path, err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+			t.Errorf("mode = %o, want 0600", info.Mode().Perm())
+		}
+	})
+
+	t.Run("directory with template and suffix", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunMktemp(&buf, nil, Options{Directory: true, Template: "build-XXXXXX", Suffix: ".d", OutputFormat: output.FormatJSON}); err != nil {
+			t.Fatalf("RunMktemp() error = %v", err)
+		}
+
+		var res Result
+		if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+			t.Fatalf("bad JSON %q: %v", buf.String(), err)
+		}
+
+		base := filepath.Base(res.Path)
+		if !res.Directory || !strings.HasPrefix(base, "build-") || !strings.HasSuffix(base, ".d") || len(base) != len("build-XXXXXX.d") {
+			t.Errorf("result = %+v", res)
+		}
+
+		if info, err := os.Stat(res.Path); err != nil || !info.IsDir() {
+			t.Errorf("stat %q: %v", res.Path, err)
+		}
+	})
+
+	t.Run("operand is relative to the current directory", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Chdir(dir)
+
+		var buf bytes.Buffer
+		if err := RunMktemp(&buf, []string{"work.XXX"}, Options{}); err != nil {
+			t.Fatalf("RunMktemp() error = %v", err)
+		}
+
+		path := strings.TrimSpace(buf.String())
+		if filepath.Dir(path) != "." {
+			t.Errorf("path = %q, want one in the current directory", path)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("dry run creates nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunMktemp(&buf, nil, Options{DryRun: true, TmpDir: tmp}); err != nil {
+			t.Fatalf("RunMktemp() error = %v", err)
+		}
+
+		if _, err := os.Lstat(strings.TrimSpace(buf.String())); !os.IsNotExist(err) {
+			t.Errorf("dry run created %q", buf.String())
+		}
+	})
+
+	t.Run("invalid templates", func(t *testing.T) {
+		for _, opts := range []Options{
+			{Template: "fewXX"},
+			{Template: "a/bXXXX"},
+			{Suffix: "/x"},
+		} {
+			if err := RunMktemp(&bytes.Buffer{}, nil, opts); !cmderr.IsInvalidInput(err) {
+				t.Errorf("RunMktemp(%+v) error = %v, want invalid input", opts, err)
+			}
+		}
+
+		if err := RunMktemp(&bytes.Buffer{}, []string{"a", "b"}, Options{}); !cmderr.IsInvalidInput(err) {
+			t.Errorf("two templates: error = %v", err)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		err := RunMktemp(&bytes.Buffer{}, nil, Options{TmpDir: filepath.Join(tmp, "nope")})
+		if !cmderr.IsNotFound(err) {
+			t.Errorf("error = %v, want not found", err)
+		}
+	})
+}
+
+func TestCreateUnique(t *testing.T) {
+	dir := t.TempDir()
+	seen := map[string]bool{}
+
+	for range 50 {
+		path, err := Create(filepath.Join(dir, "u.XXXX"), "", false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if seen[path] {
+			t.Fatalf("duplicate name %q", path)
+		}
+
+		seen[path] = true
+	}
+}
@@ -75,7 +75,7 @@ type Alloc struct {
 // cmderr sentinel, mirroring internal/cli/vault's classifyVaultError shape.
 func classifyNomadError(err error, statusCode int, op string) error {
 	if err != nil {
-		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("nomad: %s: %v", op, err))
+		return cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("nomad: %s: %v", op, err))
 	}
 
 	switch statusCode {
@@ -0,0 +1,228 @@
+// Package notify shows a desktop notification through the platform's native
+// notifier, falling back to a terminal notification when there is none.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Backends for Options.Backend.
+const (
+	BackendAuto     = "auto"     // native notifier, else the terminal
+	BackendNative   = "native"   // native notifier only
+	BackendTerminal = "terminal" // OSC 9 escape sequence plus bell
+	BackendBell     = "bell"     // bell only
+)
+
+// nativeTimeout bounds how long a native notifier may take.
+const nativeTimeout = 10 * time.Second
+
+// Options configures the notify command behavior
+type Options struct {
+	Title        string        // --title: notification title (default: omni)
+	Urgency      string        // --urgency: low, normal or critical (notify-send only)
+	Backend      string        // --backend: auto, native, terminal or bell
+	OutputFormat output.Format // output format (text, json)
+}
+
+// Result describes the notification that was shown
+type Result struct {
+	Backend string `json:"backend"` // notify-send, osascript, toast, osc9 or bell
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// Seams for tests.
+var (
+	goos      = runtime.GOOS
+	getenv    = os.Getenv
+	lookPath  = osexec.LookPath
+	runNative = func(ctx context.Context, env []string, name string, args ...string) error {
+		cmd := osexec.CommandContext(ctx, name, args...) //nolint:gosec // sanctioned exec exception (see Run)
+		cmd.Env = append(os.Environ(), env...)
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("%w: %s", err, msg)
+			}
+
+			return err
+		}
+
+		return nil
+	}
+)
+
+// Run shows message as a notification. The native notifiers are
+// notify-send on Linux and the BSDs, osascript on macOS and a PowerShell
+// toast on Windows; they are skipped on Linux when there is no graphical
+// session. Without a native notifier, or when it fails, the notification
+// is written to term as an OSC 9 escape sequence, which terminals such as
+// iTerm2, Windows Terminal and WezTerm turn into a desktop notification,
+// followed by a bell for the others.
+//
+// Sanctioned exec exception: the native notifiers have no pure-Go
+// equivalent. The title and message are passed as argv or through the
+// environment, never in a script. See docs/architecture/patterns.md §
+// "No-exec invariant: scope & sanctioned exceptions".
+func Run(ctx context.Context, w, term io.Writer, message string, opts Options) error {
+	if strings.TrimSpace(message) == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "notify: missing message")
+	}
+
+	if opts.Title == "" {
+		opts.Title = "omni"
+	}
+
+	switch opts.Urgency {
+	case "":
+		opts.Urgency = "normal"
+	case "low", "normal", "critical":
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("notify: invalid urgency %q (want low, normal or critical)", opts.Urgency))
+	}
+
+	if opts.Backend == "" {
+		opts.Backend = BackendAuto
+	}
+
+	result := Result{Title: opts.Title, Message: message}
+
+	switch opts.Backend {
+	case BackendAuto, BackendNative:
+		name, err := notifyNative(ctx, message, opts)
+		if err == nil {
+			result.Backend = name
+
+			break
+		}
+
+		if opts.Backend == BackendNative {
+			return err
+		}
+
+		result.Backend = "osc9"
+		writeTerminal(term, message, opts.Title, true)
+	case BackendTerminal:
+		result.Backend = "osc9"
+		writeTerminal(term, message, opts.Title, true)
+	case BackendBell:
+		result.Backend = "bell"
+		writeTerminal(term, message, opts.Title, false)
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("notify: invalid backend %q (want auto, native, terminal or bell)", opts.Backend))
+	}
+
+	if opts.OutputFormat == output.FormatJSON {
+		return json.NewEncoder(w).Encode(result)
+	}
+
+	return nil
+}
+
+// notifyNative runs the platform's notifier and returns its name.
+func notifyNative(ctx context.Context, message string, opts Options) (string, error) {
+	var (
+		name, bin string
+		args, env []string
+	)
+
+	switch goos {
+	case "darwin":
+		name, bin = "osascript", "osascript"
+		args = []string{
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			opts.Title, message,
+		}
+	case "windows":
+		name, bin = "toast", "powershell.exe"
+		args = []string{"-NoProfile", "-NonInteractive", "-Command", toastScript}
+		env = []string{"OMNI_NOTIFY_TITLE=" + opts.Title, "OMNI_NOTIFY_MESSAGE=" + message}
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		if getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "" {
+			return "", cmderr.Wrap(cmderr.ErrUnsupported, "notify: no graphical session")
+		}
+
+		name, bin = "notify-send", "notify-send"
+		args = []string{"--urgency", opts.Urgency, "--app-name", "omni", "--", opts.Title, message}
+	default:
+		return "", cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("notify: no native notifier on %s", goos))
+	}
+
+	path, err := lookPath(bin)
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("notify: %s: command not found", bin))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, nativeTimeout)
+	defer cancel()
+
+	if err := runNative(ctx, env, path, args...); err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("notify: %s: %v", bin, err))
+	}
+
+	return name, nil
+}
+
+// toastScript shows a Windows toast with the title and message taken from
+// the environment, so neither is ever parsed as PowerShell. It posts as
+// PowerShell, whose app ID is registered on every Windows install.
+const toastScript = `$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:OMNI_NOTIFY_TITLE)) | Out-Null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:OMNI_NOTIFY_MESSAGE)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show($toast)`
+
+// writeTerminal writes a bell to term, preceded by an OSC 9 notification
+// when osc9 is set. Inside tmux the sequence is wrapped for passthrough.
+func writeTerminal(term io.Writer, message, title string, osc9 bool) {
+	if !osc9 {
+		_, _ = io.WriteString(term, "\a")
+
+		return
+	}
+
+	text := sanitize(message)
+	if title != "omni" {
+		text = sanitize(title) + ": " + text
+	}
+
+	seq := "\x1b]9;" + text + "\a"
+	if getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+
+	_, _ = io.WriteString(term, seq+"\a")
+}
+
+// sanitize drops control characters, which would end or corrupt the
+// escape sequence.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r >= 0x80 && r < 0xa0 {
+			if r == '\n' || r == '\t' {
+				return ' '
+			}
+
+			return -1
+		}
+
+		return r
+	}, s)
+}
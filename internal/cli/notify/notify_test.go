@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// fake replaces the platform seams for one test.
+func fake(t *testing.T, os string, env map[string]string, runErr error) *[][]string {
+	t.Helper()
+
+	oldGOOS, oldGetenv, oldLookPath, oldRun := goos, getenv, lookPath, runNative
+
+	t.Cleanup(func() {
+		goos, getenv, lookPath, runNative = oldGOOS, oldGetenv, oldLookPath, oldRun
+	})
+
+	var calls [][]string
+
+	goos = os
+	getenv = func(k string) string { return env[k] }
+	lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	runNative = func(_ context.Context, env []string, name string, args ...string) error {
+		calls = append(calls, append(append([]string{name}, args...), env...))
+
+		return runErr
+	}
+
+	return &calls
+}
+
+func TestRun(t *testing.T) {
+	t.Run("notify-send", func(t *testing.T) {
+		calls := fake(t, "linux", map[string]string{"DISPLAY": ":0"}, nil)
+
+		var out, term bytes.Buffer
+
+		opts := Options{Title: "CI", Urgency: "critical", OutputFormat: output.FormatJSON}
+		if err := Run(context.Background(), &out, &term, "build done", opts); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		want := []string{"/usr/bin/notify-send", "--urgency", "critical", "--app-name", "omni", "--", "CI", "build done"}
+		if len(*calls) != 1 || !slices.Equal((*calls)[0], want) {
+			t.Errorf("calls = %q, want %q", *calls, want)
+		}
+
+		var result Result
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil || result.Backend != "notify-send" {
+			t.Errorf("result = %s (%v)", out.String(), err)
+		}
+
+		if term.Len() != 0 {
+			t.Errorf("terminal output = %q, want none", term.String())
+		}
+	})
+
+	t.Run("osascript passes text as argv", func(t *testing.T) {
+		calls := fake(t, "darwin", nil, nil)
+
+		if err := Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, `say "hi"`, Options{}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		call := (*calls)[0]
+		if call[0] != "/usr/bin/osascript" || !slices.Equal(call[len(call)-2:], []string{"omni", `say "hi"`}) {
+			t.Errorf("call = %q", call)
+		}
+	})
+
+	t.Run("toast passes text through the environment", func(t *testing.T) {
+		calls := fake(t, "windows", nil, nil)
+
+		if err := Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, "done'; rm", Options{Title: "CI"}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		call := (*calls)[0]
+		if !slices.Contains(call, "OMNI_NOTIFY_MESSAGE=done'; rm") || strings.Contains(toastScript, "done") {
+			t.Errorf("call = %q", call)
+		}
+	})
+
+	t.Run("falls back to the terminal without a display", func(t *testing.T) {
+		calls := fake(t, "linux", map[string]string{}, nil)
+
+		var term bytes.Buffer
+		if err := Run(context.Background(), &bytes.Buffer{}, &term, "build\ndone", Options{Title: "CI"}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if len(*calls) != 0 {
+			t.Errorf("native notifier should not run, got %q", *calls)
+		}
+
+		if got, want := term.String(), "\x1b]9;CI: build done\a\a"; got != want {
+			t.Errorf("terminal output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("falls back when the notifier fails", func(t *testing.T) {
+		fake(t, "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "TMUX": "1"}, errors.New("no dbus"))
+
+		var term bytes.Buffer
+		if err := Run(context.Background(), &bytes.Buffer{}, &term, "done", Options{}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if got, want := term.String(), "\x1bPtmux;\x1b\x1b]9;done\a\x1b\\\a"; got != want {
+			t.Errorf("terminal output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("native backend reports failure", func(t *testing.T) {
+		fake(t, "linux", map[string]string{"DISPLAY": ":0"}, errors.New("no dbus"))
+
+		err := Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, "done", Options{Backend: BackendNative})
+		if !cmderr.IsIO(err) {
+			t.Errorf("err = %v, want an I/O error", err)
+		}
+	})
+
+	t.Run("bell", func(t *testing.T) {
+		calls := fake(t, "linux", map[string]string{"DISPLAY": ":0"}, nil)
+
+		var term bytes.Buffer
+		if err := Run(context.Background(), &bytes.Buffer{}, &term, "done", Options{Backend: BackendBell}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if term.String() != "\a" || len(*calls) != 0 {
+			t.Errorf("terminal output = %q, calls = %q", term.String(), *calls)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		fake(t, "linux", nil, nil)
+
+		for _, tc := range []struct {
+			message string
+			opts    Options
+		}{
+			{"", Options{}},
+			{"done", Options{Urgency: "high"}},
+			{"done", Options{Backend: "smoke"}},
+		} {
+			if err := Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, tc.message, tc.opts); !cmderr.IsInvalidInput(err) {
+				t.Errorf("Run(%q, %+v) = %v, want invalid input", tc.message, tc.opts, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Package numfmt converts numbers to and from human-readable sizes, either
+// given as arguments or in selected fields of the input lines, using the
+// shared scaling rules of pkg/units.
+package numfmt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/units"
+)
+
+// Options configures the numfmt command behavior
+type Options struct {
+	From         string        // --from: input scale (none, si, iec, iec-i, auto)
+	To           string        // --to: output scale (none, si, iec, iec-i)
+	FromUnit     float64       // --from-unit: size of one input unit
+	ToUnit       float64       // --to-unit: size of one output unit
+	Round        string        // --round: up, down, from-zero, towards-zero, nearest
+	Suffix       string        // --suffix: suffix to strip from input and add to output
+	Padding      int           // --padding: pad output to N characters (negative = left-align)
+	Field        string        // --field: fields to convert (N, N-M, N-, -M, lists; default 1)
+	Delimiter    string        // -d: field delimiter instead of blanks
+	Header       int           // --header: print the first N input lines unchanged
+	Invalid      string        // --invalid: abort, fail, warn or ignore
+	ZeroTerm     bool          // -z: line delimiter is NUL
+	OutputFormat output.Format // output format (text/json)
+}
+
+// Result is one converted line for JSON output
+type Result struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Run converts each NUMBER in args, or the selected fields of every line
+// read from r when args is empty. Lines that fail to convert abort the run
+// by default; --invalid=fail prints them unchanged and exits with status 2
+// at the end, warn only reports them and ignore passes them through.
+func Run(w io.Writer, r io.Reader, args []string, opts Options) error {
+	conv, selected, err := parseOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	invalid := opts.Invalid
+	if invalid == "" {
+		invalid = "abort"
+	}
+
+	switch invalid {
+	case "abort", "fail", "warn", "ignore":
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: invalid --invalid mode %q", opts.Invalid))
+	}
+
+	delim := byte('\n')
+	if opts.ZeroTerm {
+		delim = 0
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	bw := bufio.NewWriter(w)
+
+	var (
+		results []Result
+		failed  bool
+	)
+
+	emit := func(in string, header bool) error {
+		out := in
+
+		var convErr error
+		if !header {
+			out, convErr = conv.ConvertFields(in, opts.Delimiter, selected)
+		}
+
+		if convErr != nil {
+			switch invalid {
+			case "abort":
+				_ = bw.Flush()
+				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: %v", convErr))
+			case "fail":
+				failed = true
+				_, _ = fmt.Fprintf(os.Stderr, "numfmt: %v\n", convErr)
+			case "warn":
+				_, _ = fmt.Fprintf(os.Stderr, "numfmt: %v\n", convErr)
+			}
+		}
+
+		if f.IsJSON() {
+			res := Result{Input: in, Output: out}
+			if convErr != nil {
+				res.Error = convErr.Error()
+			}
+
+			results = append(results, res)
+
+			return nil
+		}
+
+		_, _ = bw.WriteString(out)
+		_ = bw.WriteByte(delim)
+
+		return nil
+	}
+
+	if len(args) > 0 {
+		for _, arg := range args {
+			if err := emit(arg, false); err != nil {
+				return err
+			}
+		}
+	} else {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		scanner.Split(splitOn(delim))
+
+		for line := 1; scanner.Scan(); line++ {
+			if err := emit(scanner.Text(), line <= opts.Header); err != nil {
+				return err
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("numfmt: %s", err))
+		}
+	}
+
+	if f.IsJSON() {
+		if err := f.Print(results); err != nil {
+			return err
+		}
+	} else if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("numfmt: write: %s", err))
+	}
+
+	if failed {
+		return cmderr.SilentExit(2)
+	}
+
+	return nil
+}
+
+func parseOptions(opts Options) (units.Converter, func(int) bool, error) {
+	from, err := units.ParseScale(opts.From)
+	if err != nil {
+		return units.Converter{}, nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: %v", err))
+	}
+
+	to, err := units.ParseScale(opts.To)
+	if err != nil {
+		return units.Converter{}, nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: %v", err))
+	}
+
+	round, err := units.ParseRoundMode(opts.Round)
+	if err != nil {
+		return units.Converter{}, nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: %v", err))
+	}
+
+	conv := units.Converter{
+		From:     from,
+		To:       to,
+		FromUnit: opts.FromUnit,
+		ToUnit:   opts.ToUnit,
+		Round:    round,
+		Suffix:   opts.Suffix,
+		Padding:  opts.Padding,
+	}
+
+	if err := conv.Validate(); err != nil {
+		return units.Converter{}, nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: %v", err))
+	}
+
+	if opts.Header < 0 {
+		return units.Converter{}, nil, cmderr.Wrap(cmderr.ErrInvalidInput, "numfmt: --header must not be negative")
+	}
+
+	selected, err := units.ParseFields(opts.Field)
+	if err != nil {
+		return units.Converter{}, nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("numfmt: %v", err))
+	}
+
+	return conv, selected, nil
+}
+
+// splitOn is a bufio.SplitFunc for lines ending in delim.
+func splitOn(delim byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		for i, b := range data {
+			if b == delim {
+				return i + 1, data[:i], nil
+			}
+		}
+
+		if atEOF {
+			return len(data), data, nil
+		}
+
+		return 0, nil, nil
+	}
+}
@@ -0,0 +1,102 @@
+package numfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestRunArgs(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Run(&buf, nil, []string{"1024", "1536", "1048576"}, Options{To: "iec"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got, want := buf.String(), "1.0K\n1.5K\n1.0M\n"; got != want {
+		t.Errorf("Run() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+
+	if err := Run(&buf, nil, []string{"1.5Ki", "2M"}, Options{From: "auto"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got, want := buf.String(), "1536\n2000000\n"; got != want {
+		t.Errorf("--from=auto = %q, want %q", got, want)
+	}
+}
+
+func TestRunFields(t *testing.T) {
+	in := "Filesystem Size Used\n/dev/sda1 10737418240 5368709120\n/dev/sdb1 1048576 512\n"
+
+	var buf bytes.Buffer
+	if err := Run(&buf, strings.NewReader(in), nil, Options{To: "iec", Field: "2-", Header: 1}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "Filesystem Size Used\n/dev/sda1 10G 5.0G\n/dev/sdb1 1.0M 512\n"
+	if buf.String() != want {
+		t.Errorf("Run() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	if err := Run(&buf, strings.NewReader("a,2048,x\n"), nil, Options{To: "si", Field: "2", Delimiter: ","}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if buf.String() != "a,2.1k,x\n" {
+		t.Errorf("comma-delimited = %q", buf.String())
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	in := "100\nabc\n300\n"
+
+	err := Run(&bytes.Buffer{}, strings.NewReader(in), nil, Options{})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("default --invalid=abort: got %v, want invalid input", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = Run(&buf, strings.NewReader(in), nil, Options{Invalid: "fail"})
+	if cmderr.ExitCodeFor(err) != 2 {
+		t.Errorf("--invalid=fail: got %v, want exit status 2", err)
+	}
+
+	if buf.String() != in {
+		t.Errorf("--invalid=fail should pass lines through, got %q", buf.String())
+	}
+
+	if err := Run(&bytes.Buffer{}, strings.NewReader(in), nil, Options{Invalid: "ignore"}); err != nil {
+		t.Errorf("--invalid=ignore: got %v", err)
+	}
+
+	for _, opts := range []Options{{To: "auto"}, {From: "metric"}, {Round: "sideways"}, {Field: "0"}, {Invalid: "maybe"}} {
+		if err := Run(&bytes.Buffer{}, nil, []string{"1"}, opts); !cmderr.IsInvalidInput(err) {
+			t.Errorf("Run(%+v) = %v, want invalid input", opts, err)
+		}
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Run(&buf, nil, []string{"2048"}, Options{To: "iec-i", OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(results) != 1 || results[0].Output != "2.0Ki" {
+		t.Errorf("results = %+v", results)
+	}
+}
@@ -1,9 +1,12 @@
 package path
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
@@ -40,6 +43,7 @@ func Abs(p string) (string, error) {
 
 // CleanOptions configures the path clean command behavior
 type CleanOptions struct {
+	Style        Style         // --style: native, unix or windows separators
 	OutputFormat output.Format // output format (text, json, table)
 }
 
@@ -61,7 +65,7 @@ func RunClean(w io.Writer, args []string, opts CleanOptions) error {
 	var results []CleanResult
 
 	for _, arg := range args {
-		cleaned := filepath.Clean(arg)
+		cleaned := CleanStyle(arg, opts.Style)
 		if jsonMode {
 			results = append(results, CleanResult{Original: arg, Cleaned: cleaned})
 		} else {
@@ -78,6 +82,7 @@ func RunClean(w io.Writer, args []string, opts CleanOptions) error {
 
 // AbsOptions configures the path abs command behavior
 type AbsOptions struct {
+	Style        Style         // --style: native, unix or windows separators
 	OutputFormat output.Format // output format (text, json, table)
 }
 
@@ -104,6 +109,8 @@ func RunAbs(w io.Writer, args []string, opts AbsOptions) error {
 			return fmt.Errorf("path abs: %w", err)
 		}
 
+		abs = ToStyle(abs, opts.Style)
+
 		if jsonMode {
 			results = append(results, AbsResult{Original: arg, Absolute: abs})
 		} else {
@@ -117,3 +124,240 @@ func RunAbs(w io.Writer, args []string, opts AbsOptions) error {
 
 	return nil
 }
+
+// ResolveCmdOptions configures the path resolve command behavior
+type ResolveCmdOptions struct {
+	ResolveOptions
+	Zero         bool          // -z: end each output line with NUL, not newline
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// ResolveResult represents resolve output for JSON
+type ResolveResult struct {
+	Original string `json:"original"`
+	Resolved string `json:"resolved"`
+}
+
+// RunResolve prints each argument with symlinks, "." and ".." resolved.
+func RunResolve(w io.Writer, args []string, opts ResolveCmdOptions) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "path resolve: missing operand")
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	jsonMode := f.IsJSON()
+
+	var results []ResolveResult
+
+	for _, arg := range args {
+		resolved, err := ResolveWith(arg, opts.ResolveOptions)
+		if err != nil {
+			return ResolveError("path resolve", err)
+		}
+
+		if jsonMode {
+			results = append(results, ResolveResult{Original: arg, Resolved: resolved})
+		} else {
+			_, _ = fmt.Fprint(w, resolved, lineEnd(opts.Zero))
+		}
+	}
+
+	if jsonMode {
+		return f.Print(results)
+	}
+
+	return nil
+}
+
+// ResolveError maps a Resolve error to the command error taxonomy.
+func ResolveError(name string, err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("%s: %s", name, describeErr(err)))
+	}
+
+	return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("%s: %s", name, describeErr(err)))
+}
+
+// RelativeOptions configures the path relative command behavior
+type RelativeOptions struct {
+	Style        Style         // --style: native, unix or windows separators
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// RelativeResult represents relative output for JSON
+type RelativeResult struct {
+	Base     string `json:"base"`
+	Target   string `json:"target"`
+	Relative string `json:"relative"`
+}
+
+// RunRelative prints each TARGET relative to BASE (args[0]). The paths
+// are compared lexically; use path resolve --relative-to to follow
+// symlinks first.
+func RunRelative(w io.Writer, args []string, opts RelativeOptions) error {
+	if len(args) < 2 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "path relative: usage: path relative BASE TARGET...")
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	jsonMode := f.IsJSON()
+	base := args[0]
+
+	var results []RelativeResult
+
+	for _, target := range args[1:] {
+		rel, err := RelStyle(base, target, opts.Style)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("path relative: %s", err))
+		}
+
+		if jsonMode {
+			results = append(results, RelativeResult{Base: base, Target: target, Relative: rel})
+		} else {
+			_, _ = fmt.Fprintln(w, rel)
+		}
+	}
+
+	if jsonMode {
+		return f.Print(results)
+	}
+
+	return nil
+}
+
+// JoinOptions configures the path join command behavior
+type JoinOptions struct {
+	Style        Style         // --style: native, unix or windows separators
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// JoinResult represents join output for JSON
+type JoinResult struct {
+	Elements []string `json:"elements"`
+	Joined   string   `json:"joined"`
+}
+
+// RunJoin joins the arguments into a single cleaned path.
+func RunJoin(w io.Writer, args []string, opts JoinOptions) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "path join: missing operand")
+	}
+
+	joined := JoinStyle(args, opts.Style)
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(JoinResult{Elements: args, Joined: joined})
+	}
+
+	_, _ = fmt.Fprintln(w, joined)
+
+	return nil
+}
+
+// SplitOptions configures the path split command behavior
+type SplitOptions struct {
+	Style        Style         // --style: native, unix or windows separators
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// SplitResult represents split output for JSON
+type SplitResult struct {
+	Path       string   `json:"path"`
+	Volume     string   `json:"volume,omitempty"`
+	Dir        string   `json:"dir"`
+	Base       string   `json:"base"`
+	Stem       string   `json:"stem"`
+	Ext        string   `json:"ext"`
+	Components []string `json:"components"`
+}
+
+// Split breaks p into its directory, base name, stem, extension and
+// components. A rooted path's first component is its root ("/", "C:\").
+func Split(p string, style Style) SplitResult {
+	vol, rooted, names := Parts(p, style)
+
+	sep := "/"
+	if style == StyleWindows || (style.native() && filepath.Separator == '\\') {
+		sep = `\`
+	}
+
+	root := vol
+	if rooted {
+		root += sep
+	}
+
+	res := SplitResult{Path: p, Volume: vol}
+
+	if root != "" {
+		res.Components = append(res.Components, root)
+	}
+
+	res.Components = append(res.Components, names...)
+
+	switch len(names) {
+	case 0:
+		res.Dir, res.Base = orDot(root), orDot(root)
+	case 1:
+		res.Dir, res.Base = orDot(root), names[0]
+	default:
+		res.Dir, res.Base = root+strings.Join(names[:len(names)-1], sep), names[len(names)-1]
+	}
+
+	if len(names) > 0 {
+		res.Ext = filepath.Ext(res.Base)
+		if res.Ext == res.Base {
+			res.Ext = ""
+		}
+
+		res.Stem = strings.TrimSuffix(res.Base, res.Ext)
+	}
+
+	return res
+}
+
+// RunSplit prints the components of each path, one per line.
+func RunSplit(w io.Writer, args []string, opts SplitOptions) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "path split: missing operand")
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	jsonMode := f.IsJSON()
+
+	var results []SplitResult
+
+	for _, arg := range args {
+		res := Split(arg, opts.Style)
+		if jsonMode {
+			results = append(results, res)
+			continue
+		}
+
+		for _, c := range res.Components {
+			_, _ = fmt.Fprintln(w, c)
+		}
+	}
+
+	if jsonMode {
+		return f.Print(results)
+	}
+
+	return nil
+}
+
+func orDot(s string) string {
+	if s == "" {
+		return "."
+	}
+
+	return s
+}
+
+func lineEnd(zero bool) string {
+	if zero {
+		return "\x00"
+	}
+
+	return "\n"
+}
@@ -0,0 +1,177 @@
+package path
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveMode selects which path components must exist when resolving
+// symlinks, following the GNU realpath/readlink options.
+type ResolveMode int
+
+const (
+	// ResolveExisting requires every component to exist (realpath
+	// default, -e).
+	ResolveExisting ResolveMode = iota
+	// ResolveParents requires every component but the last to exist
+	// (readlink -f).
+	ResolveParents
+	// ResolveMissing resolves the components that exist and appends the
+	// rest lexically (-m).
+	ResolveMissing
+)
+
+// maxSymlinks bounds symlink expansion so link loops fail instead of
+// spinning forever.
+const maxSymlinks = 255
+
+// ResolveOptions configures ResolveWith
+type ResolveOptions struct {
+	Mode       ResolveMode // which components must exist
+	NoSymlinks bool        // -s: make absolute and clean lexically, do not expand symlinks
+	RelativeTo string      // --relative-to: print the result relative to DIR
+	Style      Style       // separator style of the printed path
+}
+
+// Resolve returns the absolute path of p with every symlink expanded and
+// "." and ".." removed. Unlike filepath.EvalSymlinks, ".." is applied
+// after the preceding component has been resolved, as realpath does, and
+// mode controls how missing components are handled.
+func Resolve(p string, mode ResolveMode) (string, error) {
+	if p == "" {
+		return "", errors.New("empty path")
+	}
+
+	if !filepath.IsAbs(p) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		p = wd + string(filepath.Separator) + p
+	}
+
+	vol := filepath.VolumeName(p)
+	resolved := vol + string(filepath.Separator)
+	pending := splitComponents(p[len(vol):])
+	links := 0
+	missing := false
+
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+
+		if name == ".." {
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+
+		if missing {
+			resolved = next
+			continue
+		}
+
+		info, err := os.Lstat(next)
+		if err != nil {
+			switch {
+			case mode == ResolveMissing:
+			case mode == ResolveParents && len(pending) == 0 && errors.Is(err, os.ErrNotExist):
+			default:
+				return "", err
+			}
+
+			missing = true
+			resolved = next
+
+			continue
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			if len(pending) > 0 && !info.IsDir() && mode != ResolveMissing {
+				return "", &os.PathError{Op: "resolve", Path: next, Err: errors.New("not a directory")}
+			}
+
+			resolved = next
+
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", &os.PathError{Op: "resolve", Path: p, Err: errors.New("too many levels of symbolic links")}
+		}
+
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+
+		if filepath.IsAbs(target) {
+			tvol := filepath.VolumeName(target)
+			resolved = tvol + string(filepath.Separator)
+			target = target[len(tvol):]
+		}
+
+		pending = append(splitComponents(target), pending...)
+	}
+
+	return resolved, nil
+}
+
+// ResolveWith resolves p as the realpath and path resolve commands do.
+func ResolveWith(p string, opts ResolveOptions) (string, error) {
+	resolve := func(p string) (string, error) {
+		if opts.NoSymlinks {
+			return filepath.Abs(p)
+		}
+
+		return Resolve(p, opts.Mode)
+	}
+
+	result, err := resolve(p)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.RelativeTo != "" {
+		base, err := resolve(opts.RelativeTo)
+		if err != nil {
+			return "", err
+		}
+
+		if result, err = filepath.Rel(base, result); err != nil {
+			return "", err
+		}
+	}
+
+	return ToStyle(result, opts.Style), nil
+}
+
+// splitComponents splits p on separators, dropping empty and "." names.
+func splitComponents(p string) []string {
+	var names []string
+
+	for _, name := range strings.FieldsFunc(p, func(r rune) bool { return r < 0x80 && os.IsPathSeparator(uint8(r)) }) {
+		if name != "." {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// describeErr strips the operation from path errors so messages read
+// "PATH: reason" like the coreutils tools.
+func describeErr(err error) string {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		return fmt.Sprintf("%s: %s", pe.Path, pe.Err)
+	}
+
+	return err.Error()
+}
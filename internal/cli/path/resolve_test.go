@@ -0,0 +1,192 @@
+package path
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestResolve(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	real := filepath.Join(tmpDir, "real")
+	if err := os.MkdirAll(filepath.Join(real, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(real, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("parents mode allows a missing last component", func(t *testing.T) {
+		got, err := Resolve(filepath.Join(real, "sub", "..", "new"), ResolveParents)
+		if err != nil || got != filepath.Join(real, "new") {
+			t.Errorf("Resolve() = %q, %v", got, err)
+		}
+
+		if _, err := Resolve(filepath.Join(real, "nope", "new"), ResolveParents); !os.IsNotExist(err) {
+			t.Errorf("Resolve(missing parent) error = %v, want not exist", err)
+		}
+	})
+
+	t.Run("existing mode", func(t *testing.T) {
+		if _, err := Resolve(filepath.Join(real, "new"), ResolveExisting); err == nil {
+			t.Error("Resolve(-e) expected error for missing path")
+		}
+
+		if got, err := Resolve(file, ResolveExisting); err != nil || got != file {
+			t.Errorf("Resolve(-e) = %q, %v", got, err)
+		}
+	})
+
+	t.Run("missing mode", func(t *testing.T) {
+		got, err := Resolve(filepath.Join(real, "a", "b", "..", "c"), ResolveMissing)
+		if err != nil || got != filepath.Join(real, "a", "c") {
+			t.Errorf("Resolve(-m) = %q, %v", got, err)
+		}
+	})
+
+	t.Run("file used as directory", func(t *testing.T) {
+		if _, err := Resolve(filepath.Join(file, "x"), ResolveParents); err == nil {
+			t.Error("Resolve() expected error for a file used as a directory")
+		}
+	})
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	link := filepath.Join(tmpDir, "link")
+	if err := os.Symlink("real/sub", link); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("dot-dot after symlink is physical", func(t *testing.T) {
+		got, err := Resolve(link+string(filepath.Separator)+"..", ResolveParents)
+		if err != nil || got != real {
+			t.Errorf("Resolve(link/..) = %q, %v, want %q", got, err, real)
+		}
+	})
+
+	t.Run("symlink loop", func(t *testing.T) {
+		loop := filepath.Join(tmpDir, "loop")
+		_ = os.Symlink("loop", loop)
+
+		if _, err := Resolve(loop, ResolveMissing); err == nil || !strings.Contains(err.Error(), "too many levels") {
+			t.Errorf("Resolve(loop) error = %v", err)
+		}
+	})
+
+	t.Run("relative to", func(t *testing.T) {
+		got, err := ResolveWith(filepath.Join(link, "x"), ResolveOptions{Mode: ResolveParents, RelativeTo: real})
+		if err != nil || got != filepath.Join("sub", "x") {
+			t.Errorf("ResolveWith(--relative-to) = %q, %v", got, err)
+		}
+	})
+}
+
+func TestStyles(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"clean unix", CleanStyle(`a\b\..\c`, StyleUnix), "a/c"},
+		{"clean windows", CleanStyle("C:/a/./b/../c/", StyleWindows), `C:\a\c`},
+		{"clean unc", CleanStyle(`//srv/share/a/../b`, StyleWindows), `\\srv\share\b`},
+		{"clean empty", CleanStyle("", StyleUnix), "."},
+		{"join unix", JoinStyle([]string{"a", "", `b\c`, "../d"}, StyleUnix), "a/b/d"},
+		{"join drive", JoinStyle([]string{"C:", "x"}, StyleWindows), `C:x`},
+		{"join rooted drive", JoinStyle([]string{`C:\`, "x"}, StyleWindows), `C:\x`},
+		{"to windows", ToStyle("a/b", StyleWindows), `a\b`},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if _, err := ParseStyle("mac"); err == nil {
+		t.Error("ParseStyle(mac) expected error")
+	}
+}
+
+func TestRelStyle(t *testing.T) {
+	tests := []struct {
+		base, target string
+		style        Style
+		want         string
+		wantErr      bool
+	}{
+		{"/srv/app", "/srv/app/bin/x", StyleUnix, "bin/x", false},
+		{"/srv/app", "/srv/lib", StyleUnix, "../lib", false},
+		{"/srv/app", "/srv/app", StyleUnix, ".", false},
+		{`C:\A`, "c:/a/b", StyleWindows, "b", false},
+		{`C:\a`, `D:\a`, StyleWindows, "", true},
+		{"/a", "b", StyleUnix, "", true},
+		{"../x", "y", StyleUnix, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := RelStyle(tt.base, tt.target, tt.style)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("RelStyle(%q, %q) = %q, %v; want %q", tt.base, tt.target, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	res := Split("/usr/local/lib/libfoo.so.1", StyleUnix)
+	if res.Dir != "/usr/local/lib" || res.Base != "libfoo.so.1" || res.Ext != ".1" || res.Stem != "libfoo.so" {
+		t.Errorf("Split() = %+v", res)
+	}
+
+	if strings.Join(res.Components, ",") != "/,usr,local,lib,libfoo.so.1" {
+		t.Errorf("Split().Components = %v", res.Components)
+	}
+
+	res = Split(".bashrc", StyleUnix)
+	if res.Dir != "." || res.Ext != "" || res.Stem != ".bashrc" {
+		t.Errorf("Split(.bashrc) = %+v", res)
+	}
+
+	res = Split(`C:\x\main.go`, StyleWindows)
+	if res.Volume != "C:" || res.Dir != `C:\x` || res.Components[0] != `C:\` {
+		t.Errorf("Split(windows) = %+v", res)
+	}
+}
+
+func TestRunRelativeJoin(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunRelative(&buf, []string{"/a/b", "/a/c", "/a/b/d"}, RelativeOptions{Style: StyleUnix}); err != nil {
+		t.Fatalf("RunRelative() error = %v", err)
+	}
+
+	if buf.String() != "../c\nd\n" {
+		t.Errorf("RunRelative() = %q", buf.String())
+	}
+
+	if err := RunRelative(&buf, []string{"/a"}, RelativeOptions{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunRelative(one arg) error = %v", err)
+	}
+
+	buf.Reset()
+
+	if err := RunJoin(&buf, []string{"x", "y"}, JoinOptions{Style: StyleWindows}); err != nil {
+		t.Fatalf("RunJoin() error = %v", err)
+	}
+
+	if buf.String() != "x\\y\n" {
+		t.Errorf("RunJoin() = %q", buf.String())
+	}
+}
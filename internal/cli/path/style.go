@@ -0,0 +1,208 @@
+package path
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Style selects the path syntax used by the path subcommands. The native
+// style uses the rules of the running OS; unix and windows accept either
+// separator on input and print "/" or "\" respectively, so Taskfiles can
+// build paths for the other platform.
+type Style string
+
+const (
+	StyleNative  Style = "native"
+	StyleUnix    Style = "unix"
+	StyleWindows Style = "windows"
+)
+
+// ParseStyle validates a --style value; the empty string means native.
+func ParseStyle(s string) (Style, error) {
+	switch Style(s) {
+	case "", StyleNative:
+		return StyleNative, nil
+	case StyleUnix, StyleWindows:
+		return Style(s), nil
+	}
+
+	return "", fmt.Errorf("invalid style %q (want native, unix or windows)", s)
+}
+
+func (s Style) native() bool {
+	return s == "" || s == StyleNative
+}
+
+// ToStyle converts the separators of p without otherwise changing it.
+func ToStyle(p string, style Style) string {
+	switch style {
+	case StyleUnix:
+		return strings.ReplaceAll(p, `\`, "/")
+	case StyleWindows:
+		return strings.ReplaceAll(p, "/", `\`)
+	}
+
+	return p
+}
+
+// CleanStyle is filepath.Clean for the given style.
+func CleanStyle(p string, style Style) string {
+	if style.native() {
+		return filepath.Clean(p)
+	}
+
+	vol, rest := splitVolume(strings.ReplaceAll(p, `\`, "/"), style)
+
+	switch {
+	case rest != "":
+		rest = path.Clean(rest)
+	case strings.HasPrefix(vol, "//"):
+		rest = "/"
+	default:
+		rest = "."
+	}
+
+	return ToStyle(vol+rest, style)
+}
+
+// JoinStyle is filepath.Join for the given style.
+func JoinStyle(elems []string, style Style) string {
+	if style.native() {
+		return filepath.Join(elems...)
+	}
+
+	var b strings.Builder
+
+	for _, e := range elems {
+		if e == "" {
+			continue
+		}
+
+		// A bare drive stays drive-relative, as in filepath.Join on Windows
+		if prev := b.String(); prev != "" {
+			if vol, rest := splitVolume(prev, style); vol != "" && rest == "" && len(vol) == 2 {
+				b.WriteString(e)
+				continue
+			}
+
+			b.WriteByte('/')
+		}
+
+		b.WriteString(e)
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+
+	return CleanStyle(b.String(), style)
+}
+
+// RelStyle is filepath.Rel for the given style. Paths are compared
+// lexically; in the native style a relative and an absolute path are
+// both made absolute first.
+func RelStyle(base, target string, style Style) (string, error) {
+	if style.native() {
+		if filepath.IsAbs(base) != filepath.IsAbs(target) {
+			var err error
+
+			if base, err = filepath.Abs(base); err != nil {
+				return "", err
+			}
+
+			if target, err = filepath.Abs(target); err != nil {
+				return "", err
+			}
+		}
+
+		return filepath.Rel(base, target)
+	}
+
+	bv, brooted, bparts := Parts(base, style)
+	tv, trooted, tparts := Parts(target, style)
+
+	if !sameName(bv, tv, style) || brooted != trooted {
+		return "", fmt.Errorf("cannot make %s relative to %s", target, base)
+	}
+
+	i := 0
+	for i < len(bparts) && i < len(tparts) && sameName(bparts[i], tparts[i], style) {
+		i++
+	}
+
+	var rel []string
+
+	for _, name := range bparts[i:] {
+		if name == ".." {
+			return "", fmt.Errorf("cannot make %s relative to %s", target, base)
+		}
+
+		rel = append(rel, "..")
+	}
+
+	rel = append(rel, tparts[i:]...)
+	if len(rel) == 0 {
+		return ".", nil
+	}
+
+	return ToStyle(strings.Join(rel, "/"), style), nil
+}
+
+// Parts splits a cleaned p into its volume (drive letter or UNC share),
+// whether it is rooted, and its names.
+func Parts(p string, style Style) (vol string, rooted bool, names []string) {
+	cleaned := CleanStyle(p, style)
+	sep := "/"
+
+	if style.native() {
+		vol = filepath.VolumeName(cleaned)
+		sep = string(filepath.Separator)
+	} else {
+		cleaned = strings.ReplaceAll(cleaned, `\`, "/")
+		vol, _ = splitVolume(cleaned, style)
+	}
+
+	rest := cleaned[len(vol):]
+	rooted = strings.HasPrefix(rest, sep)
+
+	for _, name := range strings.Split(rest, sep) {
+		if name != "" && name != "." {
+			names = append(names, name)
+		}
+	}
+
+	return vol, rooted, names
+}
+
+// splitVolume separates a Windows drive ("C:") or UNC share
+// ("//host/share") from a slash-separated path.
+func splitVolume(p string, style Style) (vol, rest string) {
+	if style != StyleWindows {
+		return "", p
+	}
+
+	if len(p) >= 2 && p[1] == ':' && (p[0]|0x20 >= 'a' && p[0]|0x20 <= 'z') {
+		return p[:2], p[2:]
+	}
+
+	if strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "///") {
+		parts := strings.SplitN(p[2:], "/", 3)
+		if len(parts) >= 2 && parts[0] != "" && parts[1] != "" {
+			vol = "//" + parts[0] + "/" + parts[1]
+			return vol, p[len(vol):]
+		}
+	}
+
+	return "", p
+}
+
+// sameName compares path names, ignoring case for Windows paths.
+func sameName(a, b string, style Style) bool {
+	if style == StyleWindows || (style.native() && filepath.Separator == '\\') {
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
@@ -0,0 +1,258 @@
+// Package pick implements an interactive fuzzy picker TUI.
+//
+// It is shared by commands that need the user to choose one entry from a
+// list (for example `omni task` without arguments).
+package pick
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// ErrCanceled is returned when the user quits the picker without choosing.
+var ErrCanceled = errors.New("pick: canceled")
+
+// Item is one selectable entry.
+type Item struct {
+	Title       string // Text matched against the query
+	Description string // Shown dimmed next to the title
+}
+
+// Options configures the picker.
+type Options struct {
+	Prompt string // Prompt shown before the query (default "> ")
+	Height int    // Maximum visible rows (default 10)
+}
+
+// IsInteractive reports whether stdin and stderr are terminals, i.e. whether
+// a picker can be shown.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Run shows the picker on stderr and returns the index of the chosen item.
+// It returns ErrCanceled when the user presses Esc or Ctrl+C.
+func Run(items []Item, opts Options) (int, error) {
+	if len(items) == 0 {
+		return -1, cmderr.Wrap(cmderr.ErrInvalidInput, "pick: no items")
+	}
+
+	if opts.Prompt == "" {
+		opts.Prompt = "> "
+	}
+
+	if opts.Height <= 0 {
+		opts.Height = 10
+	}
+
+	m := newModel(items, opts)
+
+	final, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return -1, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("pick: %s", err))
+	}
+
+	result, ok := final.(model)
+	if !ok || result.chosen < 0 {
+		return -1, ErrCanceled
+	}
+
+	return result.chosen, nil
+}
+
+// Filter returns the indices of items whose title fuzzy-matches query, best
+// match first. An empty query returns every item in its original order.
+func Filter(items []Item, query string) []int {
+	type scored struct {
+		idx   int
+		score int
+	}
+
+	var matches []scored
+
+	for i, it := range items {
+		if s, ok := Score(it.Title, query); ok {
+			matches = append(matches, scored{i, s})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	out := make([]int, len(matches))
+	for i, m := range matches {
+		out[i] = m.idx
+	}
+
+	return out
+}
+
+// Score reports whether every rune of query appears in s in order
+// (case-insensitive) and returns a score that favors consecutive runs,
+// matches at word starts and shorter candidates.
+func Score(s, query string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	src := []rune(strings.ToLower(s))
+	q := []rune(strings.ToLower(query))
+
+	score, qi, prev := 0, 0, -2
+
+	for i, r := range src {
+		if qi == len(q) {
+			break
+		}
+
+		if r != q[qi] {
+			continue
+		}
+
+		score++
+
+		if i == prev+1 {
+			score += 3
+		}
+
+		if i == 0 || !unicode.IsLetter(src[i-1]) && !unicode.IsDigit(src[i-1]) {
+			score += 2
+		}
+
+		prev = i
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, false
+	}
+
+	return score*100 - len(src), true
+}
+
+// model is the bubbletea model for the picker.
+//
+//nolint:recvcheck // bubbletea interface requires value receivers for Init/Update/View
+type model struct {
+	items    []Item
+	opts     Options
+	query    string
+	filtered []int
+	cursor   int
+	offset   int
+	chosen   int
+}
+
+func newModel(items []Item, opts Options) model {
+	return model{
+		items:    items,
+		opts:     opts,
+		filtered: Filter(items, ""),
+		chosen:   -1,
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		if len(m.filtered) > 0 {
+			m.chosen = m.filtered[m.cursor]
+		}
+
+		return m, tea.Quit
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.query); len(r) > 0 {
+			m.setQuery(string(r[:len(r)-1]))
+		}
+	case tea.KeyCtrlU:
+		m.setQuery("")
+	case tea.KeyRunes, tea.KeySpace:
+		m.setQuery(m.query + string(key.Runes))
+	}
+
+	// Keep the cursor inside the visible window
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+
+	if m.cursor >= m.offset+m.opts.Height {
+		m.offset = m.cursor - m.opts.Height + 1
+	}
+
+	return m, nil
+}
+
+func (m *model) setQuery(q string) {
+	m.query = q
+	m.filtered = Filter(m.items, q)
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m model) View() string {
+	if m.chosen >= 0 {
+		return ""
+	}
+
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	width := 0
+	for _, idx := range m.filtered {
+		width = max(width, len([]rune(m.items[idx].Title)))
+	}
+
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "%s%s\n", m.opts.Prompt, m.query)
+
+	end := min(m.offset+m.opts.Height, len(m.filtered))
+	for i := m.offset; i < end; i++ {
+		it := m.items[m.filtered[i]]
+
+		line := it.Title
+		if it.Description != "" {
+			line = fmt.Sprintf("%-*s  %s", width, it.Title, descStyle.Render(it.Description))
+		}
+
+		if i == m.cursor {
+			sb.WriteString(cursorStyle.Render("▸ ") + line + "\n")
+		} else {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	_, _ = fmt.Fprintf(&sb, "%s\n", descStyle.Render(fmt.Sprintf("%d/%d  ↑/↓ move • enter select • esc cancel", len(m.filtered), len(m.items))))
+
+	return sb.String()
+}
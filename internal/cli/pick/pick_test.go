@@ -0,0 +1,93 @@
+package pick
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var testItems = []Item{
+	{Title: "build", Description: "Build the project"},
+	{Title: "test:unit"},
+	{Title: "lint"},
+	{Title: "docker:build"},
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		s, query string
+		want     bool
+	}{
+		{"build", "", true},
+		{"build", "bld", true},
+		{"build", "BUI", true},
+		{"build", "dl", false},
+		{"test:unit", "tu", true},
+		{"lint", "lintx", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := Score(tt.s, tt.query); ok != tt.want {
+			t.Errorf("Score(%q, %q) matched = %v, want %v", tt.s, tt.query, ok, tt.want)
+		}
+	}
+
+	// Consecutive prefix matches beat scattered ones
+	prefix, _ := Score("build", "bu")
+	scattered, _ := Score("docker:build", "bu")
+
+	if prefix <= scattered {
+		t.Errorf("prefix score %d should beat %d", prefix, scattered)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	if got := Filter(testItems, ""); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+		t.Errorf("Filter(empty) = %v", got)
+	}
+
+	if got := Filter(testItems, "build"); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("Filter(build) = %v", got)
+	}
+
+	if got := Filter(testItems, "zzz"); len(got) != 0 {
+		t.Errorf("Filter(zzz) = %v", got)
+	}
+}
+
+func TestModelUpdate(t *testing.T) {
+	m := newModel(testItems, Options{Prompt: "> ", Height: 2})
+
+	send := func(msg tea.KeyMsg) {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("li")})
+
+	if len(m.filtered) != 1 || m.filtered[0] != 2 {
+		t.Fatalf("filtered = %v, want [2]", m.filtered)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyBackspace})
+	send(tea.KeyMsg{Type: tea.KeyBackspace})
+	send(tea.KeyMsg{Type: tea.KeyDown})
+	send(tea.KeyMsg{Type: tea.KeyDown})
+
+	if m.cursor != 2 || m.offset != 1 {
+		t.Errorf("cursor=%d offset=%d, want 2 and 1", m.cursor, m.offset)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.chosen != 2 {
+		t.Errorf("chosen = %d, want 2", m.chosen)
+	}
+}
+
+func TestRunNoItems(t *testing.T) {
+	if _, err := Run(nil, Options{}); err == nil {
+		t.Error("Run(nil) should fail")
+	}
+}
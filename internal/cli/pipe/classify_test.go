@@ -2,6 +2,7 @@ package pipe
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -23,7 +24,7 @@ func TestExecuteCommand_UnknownCommand_IsInvalidInput(t *testing.T) {
 	registry := NewRegistry(root)
 
 	var stdout bytes.Buffer
-	err := executeCommand(registry, []string{"definitely-not-a-command"}, nil, &stdout)
+	err := executeCommand(context.Background(), registry, []string{"definitely-not-a-command"}, nil, &stdout)
 	if !errors.Is(err, cmderr.ErrInvalidInput) {
 		t.Fatalf("unknown command: want ErrInvalidInput, got %v", err)
 	}
@@ -42,7 +43,7 @@ func TestExecuteCobraCommand_BadFlag_IsInvalidInput(t *testing.T) {
 	registry := NewRegistry(root)
 
 	var stdout bytes.Buffer
-	err := executeCobraCommand(registry, []string{"real", "--no-such-flag"}, nil, &stdout)
+	err := executeCobraCommand(context.Background(), registry, []string{"real", "--no-such-flag"}, nil, &stdout)
 	if !errors.Is(err, cmderr.ErrInvalidInput) {
 		t.Fatalf("bad flag: want ErrInvalidInput, got %v", err)
 	}
@@ -7,12 +7,11 @@ import (
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
-	"github.com/spf13/pflag"
 )
 
 // executeCommand executes a single omni command.
 // It tries the unified command.Registry first (if available), then falls back to Cobra dispatch.
-func executeCommand(registry *CommandRegistry, cmdParts []string, stdin io.Reader, stdout io.Writer) error {
+func executeCommand(ctx context.Context, registry *CommandRegistry, cmdParts []string, stdin io.Reader, stdout io.Writer) error {
 	if registry == nil {
 		return fmt.Errorf("command registry not initialized")
 	}
@@ -23,65 +22,46 @@ func executeCommand(registry *CommandRegistry, cmdParts []string, stdin io.Reade
 	// Try unified Registry first
 	if registry.Unified != nil {
 		if cmd, ok := registry.Unified.Get(cmdName); ok {
+			if err := checkGate(ctx, cmdParts); err != nil {
+				return err
+			}
+
 			r := stdin
 			if r == nil {
 				r = strings.NewReader("")
 			}
-			return cmd.Run(context.Background(), stdout, r, cmdArgs)
+			return cmd.Run(ctx, stdout, r, cmdArgs)
 		}
 	}
 
 	// Fall back to Cobra dispatch
-	return executeCobraCommand(registry, cmdParts, stdin, stdout)
+	return executeCobraCommand(ctx, registry, cmdParts, stdin, stdout)
 }
 
 // executeCobraCommand dispatches via Cobra's command tree.
-func executeCobraCommand(registry *CommandRegistry, cmdParts []string, stdin io.Reader, stdout io.Writer) error {
+func executeCobraCommand(ctx context.Context, registry *CommandRegistry, cmdParts []string, stdin io.Reader, stdout io.Writer) error {
 	if registry.RootCmd == nil {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unknown command: %s", cmdParts[0]))
 	}
 
-	// Find the command
-	cmd, args, err := registry.RootCmd.Find(cmdParts)
+	run, err := PrepareCommand(registry.RootCmd, cmdParts, Stdio{In: stdin, Out: stdout, Err: stdout})
 	if err != nil {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unknown command: %s", cmdParts[0]))
+		return err
 	}
 
-	// Check if we found a valid command (not root)
-	if cmd == registry.RootCmd {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unknown command: %s", cmdParts[0]))
-	}
-
-	// Set up input/output using Cobra's built-in methods
-	if stdin != nil {
-		cmd.SetIn(stdin)
-	}
-
-	cmd.SetOut(stdout)
-	cmd.SetErr(stdout)
-
-	// Reset flags to defaults
-	cmd.Flags().VisitAll(func(f *pflag.Flag) {
-		_ = f.Value.Set(f.DefValue)
-	})
-
-	// Parse flags
-	if err := cmd.ParseFlags(args); err != nil {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("parsing flags: %s", err))
-	}
-
-	// Get remaining args after flags
-	cmdArgs := cmd.Flags().Args()
+	return run(ctx)
+}
 
-	// Execute directly via RunE
-	if cmd.RunE != nil {
-		return cmd.RunE(cmd, cmdArgs)
+// Run executes a single omni command in-process, which makes a
+// CommandRegistry usable wherever a command.Command is expected.
+func (r *CommandRegistry) Run(ctx context.Context, w io.Writer, in io.Reader, args []string) error {
+	if err := ctx.Err(); err != nil {
+		return cmderr.Wrap(cmderr.ErrTimeout, err.Error())
 	}
 
-	if cmd.Run != nil {
-		cmd.Run(cmd, cmdArgs)
-		return nil
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "no command given")
 	}
 
-	return fmt.Errorf("command %s has no run function", cmdParts[0])
+	return executeCommand(ctx, r, args, in, w)
 }
@@ -0,0 +1,221 @@
+package pipe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Stdio holds the streams of a command run in-process. A nil In reads
+// nothing; a nil Out or Err discards what is written to it.
+type Stdio struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// Gate decides whether the command line args, a command name followed by
+// its arguments, may run in-process.
+type Gate func(args []string) error
+
+type gateKey struct{}
+
+// WithGate returns a context under which every command dispatched
+// in-process, directly or by another command such as lock, time or pipe,
+// must first pass gate. script uses it to refuse commands that start
+// external processes.
+func WithGate(ctx context.Context, gate Gate) context.Context {
+	return context.WithValue(ctx, gateKey{}, gate)
+}
+
+// checkGate runs the gate of ctx, if any, on args.
+func checkGate(ctx context.Context, args []string) error {
+	if gate, ok := ctx.Value(gateKey{}).(Gate); ok {
+		return gate(args)
+	}
+
+	return nil
+}
+
+// PrepareCommand resolves args on the command tree of root and parses the
+// command's flags the way Cobra would, returning a function that runs it
+// in-process with the given streams. pipe, script, lock, time, bench and
+// selftest all dispatch commands through it.
+//
+// Every local flag of the command is reset to its default first, since the
+// flag values of an earlier run stay in the shared flag sets. Inherited
+// flags such as --json and --error-format start from the values of the
+// invocation that dispatches the command; whatever args sets them to holds
+// only while the command runs, so the outer invocation keeps its own. The
+// root pre-run
+// hooks are skipped, so the run is neither logged nor timed as a command
+// of its own, and usage errors are reported before anything runs. The
+// returned function may be called more than once with the same flags.
+func PrepareCommand(root *cobra.Command, args []string, stdio Stdio) (func(ctx context.Context) error, error) {
+	if len(args) == 0 {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "no command given")
+	}
+
+	c, rest, err := root.Find(args)
+	if err != nil || c == root {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unknown command: %s", args[0]))
+	}
+
+	if c.RunE == nil && c.Run == nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%q is not a runnable command", c.CommandPath()))
+	}
+
+	c.InitDefaultHelpFlag()
+	resetFlags(c.LocalFlags())
+
+	inherited := c.InheritedFlags()
+	outer := saveFlags(inherited)
+
+	argv, err := parseCommand(c, rest)
+	own := saveFlags(inherited)
+
+	restoreFlags(inherited, outer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		if err := checkGate(ctx, args); err != nil {
+			return err
+		}
+
+		restoreFlags(inherited, own)
+		defer restoreFlags(inherited, outer)
+
+		in, out, errW := stdio.In, stdio.Out, stdio.Err
+		if in == nil {
+			in = strings.NewReader("")
+		}
+
+		if out == nil {
+			out = io.Discard
+		}
+
+		if errW == nil {
+			errW = io.Discard
+		}
+
+		c.SetIn(in)
+		c.SetOut(out)
+		c.SetErr(errW)
+		// Commands like free and hash derive their signal context from it
+		c.SetContext(ctx)
+
+		defer func() {
+			c.SetIn(nil)
+			c.SetOut(nil)
+			c.SetErr(nil)
+		}()
+
+		if help, _ := c.Flags().GetBool("help"); help {
+			return c.Help()
+		}
+
+		if c.RunE != nil {
+			return c.RunE(c, argv)
+		}
+
+		c.Run(c, argv)
+
+		return nil
+	}, nil
+}
+
+// parseCommand parses the flags in args for c and validates the result,
+// returning the positional arguments.
+func parseCommand(c *cobra.Command, args []string) ([]string, error) {
+	argv := args
+	if !c.DisableFlagParsing {
+		if err := c.ParseFlags(args); err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: %s", c.Name(), err))
+		}
+
+		argv = c.Flags().Args()
+	}
+
+	if help, _ := c.Flags().GetBool("help"); !help {
+		for _, validate := range []func() error{
+			func() error { return c.ValidateArgs(argv) },
+			c.ValidateRequiredFlags,
+			c.ValidateFlagGroups,
+		} {
+			if err := validate(); err != nil {
+				return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: %s", c.Name(), err))
+			}
+		}
+	}
+
+	return argv, nil
+}
+
+// flagState is the value of a flag and whether it was set.
+type flagState struct {
+	value   []string
+	changed bool
+}
+
+// saveFlags records the state of every flag in fs.
+func saveFlags(fs *pflag.FlagSet) map[string]flagState {
+	saved := make(map[string]flagState)
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		st := flagState{changed: f.Changed}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			st.value = sv.GetSlice()
+		} else {
+			st.value = []string{f.Value.String()}
+		}
+
+		saved[f.Name] = st
+	})
+
+	return saved
+}
+
+// restoreFlags puts the flags of fs back in the state saveFlags recorded.
+func restoreFlags(fs *pflag.FlagSet, saved map[string]flagState) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		st, ok := saved[f.Name]
+		if !ok {
+			return
+		}
+
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(st.value)
+		} else {
+			_ = f.Value.Set(st.value[0])
+		}
+
+		f.Changed = st.changed
+	})
+}
+
+// resetFlags sets every flag in fs back to its default and marks it unset.
+// Slice flags are replaced rather than Set, since Set appends to them.
+func resetFlags(fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var def []string
+			if s := strings.Trim(f.DefValue, "[]"); s != "" {
+				def = strings.Split(s, ",")
+			}
+
+			_ = sv.Replace(def)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+
+		f.Changed = false
+	})
+}
@@ -0,0 +1,89 @@
+package pipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/spf13/cobra"
+)
+
+type ctxKey struct{}
+
+func TestPrepareCommand(t *testing.T) {
+	root := &cobra.Command{Use: "omni"}
+	root.PersistentFlags().Bool("verbose", false, "")
+
+	show := &cobra.Command{
+		Use:  "show",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			patterns, _ := cmd.Flags().GetStringArray("regexp")
+			globs, _ := cmd.Flags().GetStringSlice("glob")
+			n, _ := cmd.Flags().GetInt("n")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), patterns, globs, n, verbose, cmd.Flags().Changed("n"), args, cmd.Context().Value(ctxKey{}))
+
+			return nil
+		},
+	}
+	show.Flags().StringArrayP("regexp", "e", nil, "")
+	show.Flags().StringSlice("glob", []string{"*.go", "*.md"}, "")
+	show.Flags().Int("n", 3, "")
+
+	root.AddCommand(show, &cobra.Command{Use: "group"})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "ctx")
+
+	run1 := func(args ...string) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		run, err := PrepareCommand(root, args, Stdio{Out: &out})
+		if err != nil {
+			t.Fatalf("PrepareCommand(%q) error = %v", args, err)
+		}
+
+		if err := run(ctx); err != nil {
+			t.Fatalf("run(%q) error = %v", args, err)
+		}
+
+		return out.String()
+	}
+
+	if got, want := run1("show", "-e", "a", "-e", "b", "--glob", "x", "--n", "5", "--verbose", "arg"), "[a b] [x] 5 true true [arg] ctx\n"; got != want {
+		t.Errorf("first run = %q, want %q", got, want)
+	}
+
+	// Every flag is back at its default, slices included
+	if got, want := run1("show"), "[] [*.go *.md] 3 false false [] ctx\n"; got != want {
+		t.Errorf("second run = %q, want %q", got, want)
+	}
+
+	// Inherited flags start from the outer invocation's values and get them
+	// back once the command has run
+	if err := root.PersistentFlags().Set("verbose", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := run1("show"), "[] [*.go *.md] 3 true false [] ctx\n"; got != want {
+		t.Errorf("run under --verbose = %q, want %q", got, want)
+	}
+
+	if got, want := run1("show", "--verbose=false"), "[] [*.go *.md] 3 false false [] ctx\n"; got != want {
+		t.Errorf("run with --verbose=false = %q, want %q", got, want)
+	}
+
+	if verbose, _ := root.PersistentFlags().GetBool("verbose"); !verbose || !root.PersistentFlags().Changed("verbose") {
+		t.Error("outer --verbose was not restored")
+	}
+
+	for _, args := range [][]string{{"nope"}, {"group"}, {"show", "--bogus"}, {"show", "a", "b"}, {}} {
+		if _, err := PrepareCommand(root, args, Stdio{}); !cmderr.IsInvalidInput(err) {
+			t.Errorf("PrepareCommand(%q) error = %v, want ErrInvalidInput", args, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package pipe
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 
@@ -37,8 +38,8 @@ type CommandResult struct {
 // CommandRegistry holds references to available commands.
 // It tries the unified command.Registry first, then falls back to Cobra dispatch.
 type CommandRegistry struct {
-	RootCmd  *cobra.Command
-	Unified  *command.Registry
+	RootCmd *cobra.Command
+	Unified *command.Registry
 }
 
 // NewRegistry creates a new command registry with Cobra fallback only.
@@ -53,6 +54,11 @@ func NewRegistryWithUnified(rootCmd *cobra.Command, unified *command.Registry) *
 
 // Run executes a pipeline of commands
 func Run(w io.Writer, args []string, opts Options, registry *CommandRegistry) error {
+	return RunContext(context.Background(), w, args, opts, registry)
+}
+
+// RunContext is Run with a context, which every command receives.
+func RunContext(ctx context.Context, w io.Writer, args []string, opts Options, registry *CommandRegistry) error {
 	if len(args) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "pipe: no commands provided")
 	}
@@ -94,7 +100,7 @@ func Run(w io.Writer, args []string, opts Options, registry *CommandRegistry) er
 				cmdInput = &input
 			}
 
-			err := executeCommand(registry, cmdParts, cmdInput, &output)
+			err := executeCommand(ctx, registry, cmdParts, cmdInput, &output)
 			if err != nil {
 				cmdResult.Error = err.Error()
 				result.Success = false
@@ -149,6 +155,12 @@ func Run(w io.Writer, args []string, opts Options, registry *CommandRegistry) er
 
 // RunWithInput executes a pipeline with initial input
 func RunWithInput(w io.Writer, input string, args []string, opts Options, registry *CommandRegistry) error {
+	return RunWithInputContext(context.Background(), w, input, args, opts, registry)
+}
+
+// RunWithInputContext is RunWithInput with a context, which every command
+// receives.
+func RunWithInputContext(ctx context.Context, w io.Writer, input string, args []string, opts Options, registry *CommandRegistry) error {
 	if len(args) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "pipe: no commands provided")
 	}
@@ -188,7 +200,7 @@ func RunWithInput(w io.Writer, input string, args []string, opts Options, regist
 				cmdInput = inputBuf
 			}
 
-			err := executeCommand(registry, cmdParts, cmdInput, &output)
+			err := executeCommand(ctx, registry, cmdParts, cmdInput, &output)
 			if err != nil {
 				cmdResult.Error = err.Error()
 				result.Success = false
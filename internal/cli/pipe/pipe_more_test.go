@@ -211,7 +211,7 @@ func TestExecuteCommand_CobraFallback(t *testing.T) {
 	reg := NewRegistry(newTestCobraRoot())
 
 	var out strings.Builder
-	err := executeCommand(reg, []string{"ccat"}, strings.NewReader("piped"), &out)
+	err := executeCommand(context.Background(), reg, []string{"ccat"}, strings.NewReader("piped"), &out)
 	if err != nil {
 		t.Fatalf("executeCommand cobra err=%v", err)
 	}
@@ -223,7 +223,7 @@ func TestExecuteCommand_CobraFallback(t *testing.T) {
 func TestExecuteCommand_Errors(t *testing.T) {
 	t.Run("nil registry", func(t *testing.T) {
 		var out strings.Builder
-		if err := executeCommand(nil, []string{"x"}, nil, &out); err == nil {
+		if err := executeCommand(context.Background(), nil, []string{"x"}, nil, &out); err == nil {
 			t.Error("expected error for nil registry")
 		}
 	})
@@ -231,7 +231,7 @@ func TestExecuteCommand_Errors(t *testing.T) {
 	t.Run("unknown command cobra", func(t *testing.T) {
 		reg := NewRegistry(newTestCobraRoot())
 		var out strings.Builder
-		if err := executeCommand(reg, []string{"nope"}, nil, &out); err == nil {
+		if err := executeCommand(context.Background(), reg, []string{"nope"}, nil, &out); err == nil {
 			t.Error("expected unknown command error")
 		}
 	})
@@ -239,7 +239,7 @@ func TestExecuteCommand_Errors(t *testing.T) {
 	t.Run("nil root cobra", func(t *testing.T) {
 		reg := NewRegistry(nil)
 		var out strings.Builder
-		if err := executeCommand(reg, []string{"x"}, nil, &out); err == nil {
+		if err := executeCommand(context.Background(), reg, []string{"x"}, nil, &out); err == nil {
 			t.Error("expected error for nil root")
 		}
 	})
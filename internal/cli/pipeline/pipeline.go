@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/timecmd"
 	pkgpipeline "github.com/inovacc/omni/pkg/pipeline"
 )
 
@@ -26,7 +27,7 @@ func Run(ctx context.Context, w io.Writer, r io.Reader, args []string, opts Opti
 	// Parse stage definitions
 	stages, err := pkgpipeline.ParseAll(args)
 	if err != nil {
-		return err
+		return classify(err)
 	}
 
 	// Determine input source
@@ -54,5 +55,27 @@ func Run(ctx context.Context, w io.Writer, r io.Reader, args []string, opts Opti
 
 	p := pkgpipeline.New(stages...)
 
-	return p.Run(ctx, input, w)
+	// Report per-stage timings when running under "omni time"
+	rec := timecmd.RecorderFrom(ctx)
+	if rec == nil {
+		return classify(p.Run(ctx, input, w))
+	}
+
+	err = p.WithTimings().Run(ctx, input, w)
+
+	for _, t := range p.Timings() {
+		rec.Record(t.Name, t.Elapsed, t.Busy)
+	}
+
+	return classify(err)
+}
+
+// classify maps compression formats this build cannot handle (zstd, xz)
+// to the unsupported exit code.
+func classify(err error) error {
+	if errors.Is(err, pkgpipeline.ErrUnsupportedFormat) {
+		return cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("pipeline: %s", err))
+	}
+
+	return err
 }
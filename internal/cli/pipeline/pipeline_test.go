@@ -3,8 +3,12 @@ package pipeline
 import (
 	"bytes"
 	"context"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/timecmd"
 )
 
 func TestRun(t *testing.T) {
@@ -110,3 +114,37 @@ func TestRunFileNotFound(t *testing.T) {
 		t.Error("expected error for missing file")
 	}
 }
+
+func TestRunUnsupportedCompression(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Run(context.Background(), &buf, strings.NewReader(""), []string{"zstd"}, Options{})
+	if !errors.Is(err, cmderr.ErrUnsupported) {
+		t.Errorf("Run(zstd) error = %v, want cmderr.ErrUnsupported", err)
+	}
+
+	zstd := strings.NewReader("\x28\xb5\x2f\xfd\x00\x00")
+	err = Run(context.Background(), &buf, zstd, []string{"decompress", "sort"}, Options{})
+	if !errors.Is(err, cmderr.ErrUnsupported) {
+		t.Errorf("Run(decompress) on zstd input error = %v, want cmderr.ErrUnsupported", err)
+	}
+}
+
+func TestRunRecordsStageTimings(t *testing.T) {
+	rec := &timecmd.Recorder{}
+	ctx := timecmd.WithRecorder(context.Background(), rec)
+
+	var buf bytes.Buffer
+	if err := Run(ctx, &buf, strings.NewReader("b\na\n"), []string{"sort", "uniq"}, Options{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if buf.String() != "a\nb\n" {
+		t.Errorf("Run() output = %q", buf.String())
+	}
+
+	stages := rec.Stages()
+	if len(stages) != 2 || stages[0].Name != "sort" || stages[1].Name != "uniq" {
+		t.Errorf("recorded stages = %+v", stages)
+	}
+}
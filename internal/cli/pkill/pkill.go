@@ -6,13 +6,15 @@ import (
 	"io"
 	"os"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/kill"
+	"github.com/inovacc/omni/internal/cli/ps"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 // Options configures the pkill command behavior
@@ -24,7 +26,10 @@ type Options struct {
 	Oldest       bool          // -o: select only the oldest process
 	Count        bool          // -c: count matching processes
 	ListOnly     bool          // -l: list matching processes (don't kill)
-	User         string        // -u: only match processes owned by user
+	PIDsOnly     bool          // pgrep default: print only PIDs
+	ListFull     bool          // -a: list PID and full command line
+	Delimiter    string        // -d: separator between PIDs (default newline)
+	User         string        // -u: only match processes owned by user(s), comma-separated
 	Parent       int           // -P: only match processes with given parent PID
 	Terminal     string        // -t: only match processes on terminal
 	Verbose      bool          // -v: verbose output
@@ -34,28 +39,15 @@ type Options struct {
 
 // Result represents the result of a pkill operation
 type Result struct {
-	PID     int    `json:"pid"`
-	Name    string `json:"name"`
-	Cmdline string `json:"cmdline,omitempty"`
-	Signal  int    `json:"signal,omitempty"`
-	Matched bool   `json:"matched"`
-	Killed  bool   `json:"killed,omitempty"`
-	Error   string `json:"error,omitempty"`
-}
-
-// signalMap maps signal names to syscall.Signal
-var signalMap = map[string]syscall.Signal{
-	"HUP":  syscall.SIGHUP,
-	"INT":  syscall.SIGINT,
-	"QUIT": syscall.SIGQUIT,
-	"ILL":  syscall.SIGILL,
-	"TRAP": syscall.SIGTRAP,
-	"ABRT": syscall.SIGABRT,
-	"KILL": syscall.SIGKILL,
-	"SEGV": syscall.SIGSEGV,
-	"PIPE": syscall.SIGPIPE,
-	"ALRM": syscall.SIGALRM,
-	"TERM": syscall.SIGTERM,
+	PID       int    `json:"pid"`
+	Name      string `json:"name"`
+	Cmdline   string `json:"cmdline,omitempty"`
+	User      string `json:"user,omitempty"`
+	StartTime int64  `json:"start_time,omitempty"`
+	Signal    int    `json:"signal,omitempty"`
+	Matched   bool   `json:"matched"`
+	Killed    bool   `json:"killed,omitempty"`
+	Error     string `json:"error,omitempty"`
 }
 
 // Run executes the pkill command
@@ -88,90 +80,21 @@ func Run(w io.Writer, pattern string, opts Options) error {
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
 
-	// Get signal
-	sig := syscall.SIGTERM
-
-	if opts.Signal != "" {
-		sigName := strings.ToUpper(strings.TrimPrefix(opts.Signal, "SIG"))
-		if s, ok := signalMap[sigName]; ok {
-			sig = s
-		} else {
-			sigNum, err := strconv.Atoi(opts.Signal)
-			if err != nil {
-				return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("pkill: invalid signal: %s", opts.Signal))
-			}
+	sig, err := parseSignal(opts.Signal)
+	if err != nil {
+		return err
+	}
 
-			sig = syscall.Signal(sigNum)
-		}
+	if opts.User != "" && runtime.GOOS == "windows" {
+		return cmderr.Wrap(cmderr.ErrUnsupported, "pkill: user filter not supported on windows")
 	}
 
-	// Get all processes
-	procs, err := process.Processes()
+	procs, err := ps.GetProcessList(ps.Options{All: true})
 	if err != nil {
-		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("pkill: failed to get processes: %s", err))
+		return fmt.Errorf("pkill: %w", err)
 	}
 
-	var matched []Result
-
-	for _, p := range procs {
-		name, err := p.Name()
-		if err != nil {
-			continue
-		}
-
-		// Get cmdline for full matching
-		cmdline := ""
-
-		if opts.Full {
-			cmd, err := p.Cmdline()
-			if err == nil {
-				cmdline = cmd
-			}
-		}
-
-		// Match
-		matchTarget := name
-		if opts.Full && cmdline != "" {
-			matchTarget = cmdline
-		}
-
-		if !re.MatchString(matchTarget) {
-			continue
-		}
-
-		// Filter by user
-		if opts.User != "" {
-			username, err := p.Username()
-			if err != nil || !strings.EqualFold(username, opts.User) {
-				continue
-			}
-		}
-
-		// Filter by parent PID
-		if opts.Parent > 0 {
-			ppid, err := p.Ppid()
-			if err != nil || int(ppid) != opts.Parent {
-				continue
-			}
-		}
-
-		// Filter by terminal
-		if opts.Terminal != "" {
-			terminal, err := p.Terminal()
-			if err != nil || !strings.Contains(terminal, opts.Terminal) {
-				continue
-			}
-		}
-
-		result := Result{
-			PID:     int(p.Pid),
-			Name:    name,
-			Cmdline: cmdline,
-			Matched: true,
-		}
-
-		matched = append(matched, result)
-	}
+	matched := selectProcesses(procs, re, os.Getpid(), opts)
 
 	if len(matched) == 0 {
 		if jsonMode {
@@ -182,29 +105,6 @@ func Run(w io.Writer, pattern string, opts Options) error {
 		return cmderr.SilentExit(1)
 	}
 
-	// Select newest/oldest if requested
-	if opts.Newest {
-		// Keep only the process with highest PID (newest)
-		newest := matched[0]
-		for _, m := range matched[1:] {
-			if m.PID > newest.PID {
-				newest = m
-			}
-		}
-
-		matched = []Result{newest}
-	} else if opts.Oldest {
-		// Keep only the process with lowest PID (oldest)
-		oldest := matched[0]
-		for _, m := range matched[1:] {
-			if m.PID < oldest.PID {
-				oldest = m
-			}
-		}
-
-		matched = []Result{oldest}
-	}
-
 	// Count mode
 	if opts.Count {
 		if jsonMode {
@@ -222,19 +122,16 @@ func Run(w io.Writer, pattern string, opts Options) error {
 			return f.Print(matched)
 		}
 
-		for _, m := range matched {
-			if opts.Full {
-				_, _ = fmt.Fprintf(w, "%d %s\n", m.PID, m.Cmdline)
-			} else {
-				_, _ = fmt.Fprintf(w, "%d %s\n", m.PID, m.Name)
-			}
-		}
+		printList(w, matched, opts)
 
 		return nil
 	}
 
 	// Kill mode
-	var results []Result
+	var (
+		results []Result
+		killed  bool
+	)
 
 	for _, m := range matched {
 		m.Signal = int(sig)
@@ -248,14 +145,20 @@ func Run(w io.Writer, pattern string, opts Options) error {
 		}
 
 		if err := proc.Signal(sig); err != nil {
-			if errors.Is(err, os.ErrPermission) {
+			if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EPERM) {
 				m.Error = cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("pkill: kill %d", m.PID)).Error()
 			} else {
 				m.Error = err.Error()
 			}
+
+			if !jsonMode {
+				_, _ = fmt.Fprintf(os.Stderr, "pkill: killing pid %d failed: %s\n", m.PID, m.Error)
+			}
 		} else {
 			m.Killed = true
-			if opts.Verbose {
+			killed = true
+
+			if opts.Verbose && !jsonMode {
 				_, _ = fmt.Fprintf(w, "pkill: killed %d (%s)\n", m.PID, m.Name)
 			}
 		}
@@ -264,8 +167,167 @@ func Run(w io.Writer, pattern string, opts Options) error {
 	}
 
 	if jsonMode {
-		return f.Print(results)
+		if err := f.Print(results); err != nil {
+			return err
+		}
+	}
+
+	// Like procps, succeed if at least one process was signalled
+	if !killed {
+		return cmderr.SilentExit(1)
 	}
 
 	return nil
 }
+
+// parseSignal resolves -signal via the kill command's signal table, so
+// names, SIG-prefixed names and numbers behave identically in kill and pkill.
+func parseSignal(spec string) (syscall.Signal, error) {
+	if spec == "" {
+		return syscall.SIGTERM, nil
+	}
+
+	sig, err := kill.ParseSignal(spec)
+	if err != nil {
+		if cmderr.IsUnsupported(err) {
+			return 0, fmt.Errorf("pkill: %w", err)
+		}
+
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("pkill: invalid signal: %s", spec))
+	}
+
+	return sig, nil
+}
+
+// selectProcesses applies the pattern and filters to the process table and
+// the -n/-o selection. The calling process (self) is never selected, so
+// "pkill -f omni" cannot kill itself.
+func selectProcesses(procs []ps.Info, re *regexp.Regexp, self int, opts Options) []Result {
+	var matched []Result
+
+	for _, p := range procs {
+		if p.PID == self {
+			continue
+		}
+
+		name := processName(p)
+
+		matchTarget := name
+		if opts.Full && p.Command != "" {
+			matchTarget = p.Command
+		}
+
+		if !re.MatchString(matchTarget) {
+			continue
+		}
+
+		if opts.User != "" && !matchesUser(p, opts.User) {
+			continue
+		}
+
+		if opts.Parent > 0 && p.PPID != opts.Parent {
+			continue
+		}
+
+		if opts.Terminal != "" && !strings.Contains(p.TTY, strings.TrimPrefix(opts.Terminal, "/dev/")) {
+			continue
+		}
+
+		matched = append(matched, Result{
+			PID:       p.PID,
+			Name:      name,
+			Cmdline:   p.Command,
+			User:      p.User,
+			StartTime: p.StartTime,
+			Matched:   true,
+		})
+	}
+
+	if len(matched) == 0 || (!opts.Newest && !opts.Oldest) {
+		return matched
+	}
+
+	pick := matched[0]
+
+	for _, m := range matched[1:] {
+		if opts.Newest && newer(m, pick) || opts.Oldest && newer(pick, m) {
+			pick = m
+		}
+	}
+
+	return []Result{pick}
+}
+
+// newer orders processes by start time, falling back to PID when start
+// times are equal or unknown.
+func newer(a, b Result) bool {
+	if a.StartTime != b.StartTime {
+		return a.StartTime > b.StartTime
+	}
+
+	return a.PID > b.PID
+}
+
+// processName returns the executable name, deriving it from the command
+// line when the provider does not report one.
+func processName(p ps.Info) string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	fields := strings.Fields(p.Command)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	name := fields[0]
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+// matchesUser reports whether p is owned by one of the comma-separated
+// user names or numeric UIDs in users.
+func matchesUser(p ps.Info, users string) bool {
+	for u := range strings.SplitSeq(users, ",") {
+		u = strings.TrimSpace(u)
+
+		if strings.EqualFold(u, p.User) {
+			return true
+		}
+
+		if uid, err := strconv.Atoi(u); err == nil && uid == p.UID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printList(w io.Writer, matched []Result, opts Options) {
+	if opts.PIDsOnly && !opts.ListFull {
+		delim := opts.Delimiter
+		if delim == "" {
+			delim = "\n"
+		}
+
+		pids := make([]string, len(matched))
+		for i, m := range matched {
+			pids[i] = strconv.Itoa(m.PID)
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\n", strings.Join(pids, delim))
+
+		return
+	}
+
+	for _, m := range matched {
+		if opts.Full || opts.ListFull {
+			_, _ = fmt.Fprintf(w, "%d %s\n", m.PID, m.Cmdline)
+		} else {
+			_, _ = fmt.Fprintf(w, "%d %s\n", m.PID, m.Name)
+		}
+	}
+}
@@ -5,39 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"regexp"
+	"slices"
 	"strings"
-	"syscall"
 	"testing"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/ps"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
-func TestSignalMap(t *testing.T) {
-	expectedSignals := map[string]syscall.Signal{
-		"HUP":  syscall.SIGHUP,
-		"INT":  syscall.SIGINT,
-		"QUIT": syscall.SIGQUIT,
-		"KILL": syscall.SIGKILL,
-		"TERM": syscall.SIGTERM,
-		"ABRT": syscall.SIGABRT,
-	}
-
-	for name, expected := range expectedSignals {
-		t.Run(name, func(t *testing.T) {
-			sig, ok := signalMap[name]
-			if !ok {
-				t.Errorf("signal %q not found in signalMap", name)
-				return
-			}
-
-			if sig != expected {
-				t.Errorf("signalMap[%q] = %v, want %v", name, sig, expected)
-			}
-		})
-	}
-}
-
 func TestRun_EmptyPattern(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -235,7 +211,7 @@ func TestRun_SignalParsing(t *testing.T) {
 	}{
 		{"term", "TERM", true},
 		{"kill", "KILL", true},
-		{"hup", "HUP", true},
+		{"int", "INT", true},
 		{"sig_prefix", "SIGTERM", true},
 		{"numeric", "15", true},
 		{"invalid", "INVALID", false},
@@ -302,3 +278,68 @@ func TestPatternCompilation(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectProcesses(t *testing.T) {
+	procs := []ps.Info{
+		{PID: 10, Name: "worker", User: "alice", UID: 1000, TTY: "pts/0", Command: "/usr/bin/worker --queue a", StartTime: 300},
+		{PID: 20, Name: "worker", User: "bob", UID: 1001, TTY: "?", Command: "/usr/bin/worker --queue b", StartTime: 100},
+		{PID: 30, Name: "worker", User: "alice", UID: 1000, TTY: "pts/1", Command: "/usr/bin/worker --queue c", StartTime: 200},
+		{PID: 40, Command: "/opt/tools/server -p 80", User: "root", StartTime: 50},
+		{PID: 99, Name: "worker", User: "alice", UID: 1000, Command: "worker", StartTime: 999},
+	}
+
+	pids := func(results []Result) []int {
+		var out []int
+		for _, r := range results {
+			out = append(out, r.PID)
+		}
+
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		want    []int
+	}{
+		{"all_but_self", "worker", Options{}, []int{10, 20, 30}},
+		{"newest", "worker", Options{Newest: true}, []int{10}},
+		{"oldest", "worker", Options{Oldest: true}, []int{20}},
+		{"user_name", "worker", Options{User: "bob"}, []int{20}},
+		{"user_uid_list", "worker", Options{User: "nobody,1000"}, []int{10, 30}},
+		{"user_newest", "worker", Options{User: "alice", Newest: true}, []int{10}},
+		{"terminal", "worker", Options{Terminal: "/dev/pts/1"}, []int{30}},
+		{"name_from_cmdline", "^server$", Options{}, []int{40}},
+		{"name_ignores_args", "queue", Options{}, nil},
+		{"full", "queue c", Options{Full: true}, []int{30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pids(selectProcesses(procs, regexp.MustCompile(tt.pattern), 99, tt.opts))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectProcesses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintList(t *testing.T) {
+	var buf bytes.Buffer
+
+	matched := []Result{{PID: 1, Name: "a"}, {PID: 2, Name: "b"}}
+
+	printList(&buf, matched, Options{PIDsOnly: true, Delimiter: ","})
+
+	if got := buf.String(); got != "1,2\n" {
+		t.Errorf("printList() = %q, want %q", got, "1,2\n")
+	}
+
+	buf.Reset()
+	printList(&buf, matched, Options{})
+
+	if got := buf.String(); got != "1 a\n2 b\n" {
+		t.Errorf("printList() = %q, want %q", got, "1 a\n2 b\n")
+	}
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package pkill
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestParseSignal(t *testing.T) {
+	expectedSignals := map[string]syscall.Signal{
+		"":        syscall.SIGTERM,
+		"HUP":     syscall.SIGHUP,
+		"INT":     syscall.SIGINT,
+		"QUIT":    syscall.SIGQUIT,
+		"KILL":    syscall.SIGKILL,
+		"TERM":    syscall.SIGTERM,
+		"ABRT":    syscall.SIGABRT,
+		"SIGUSR1": syscall.SIGUSR1,
+		"usr2":    syscall.SIGUSR2,
+		"9":       syscall.SIGKILL,
+	}
+
+	for name, expected := range expectedSignals {
+		t.Run(name, func(t *testing.T) {
+			sig, err := parseSignal(name)
+			if err != nil {
+				t.Fatalf("parseSignal(%q) error = %v", name, err)
+			}
+
+			if sig != expected {
+				t.Errorf("parseSignal(%q) = %v, want %v", name, sig, expected)
+			}
+		})
+	}
+}
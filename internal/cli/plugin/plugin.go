@@ -0,0 +1,245 @@
+// Package plugin discovers and runs external omni subcommands. Any
+// executable named omni-<name> on PATH becomes `omni <name>`, in the style of
+// kubectl and git.
+//
+// Contract for plugin authors:
+//
+//   - Arguments after the plugin name are passed through verbatim, including
+//     global flags such as --json.
+//   - stdin, stdout and stderr are inherited; the plugin's exit code becomes
+//     omni's exit code (see docs/EXIT-CODES.md for the meaning of 1-6).
+//   - The environment is inherited, plus OMNI_PLUGIN_NAME (the subcommand
+//     name), OMNI_EXECUTABLE (the path of the invoking omni binary, for
+//     calling back into omni) and OMNI_OUTPUT (text or json).
+//   - When OMNI_OUTPUT is json, structured output is NDJSON: one JSON value
+//     per line on stdout, no other text. Diagnostics go to stderr.
+//
+// Built-in commands always win over plugins of the same name. Go plugins
+// (-buildmode=plugin) are not supported: they require cgo and a toolchain
+// and dependency set identical to the omni build.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Prefix is the executable name prefix that marks an omni plugin.
+const Prefix = "omni-"
+
+// Plugin is an omni-<name> executable found on PATH.
+type Plugin struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Shadowed []string `json:"shadowed,omitempty"` // later PATH entries with the same name
+	Builtin  bool     `json:"overridden_by_builtin,omitempty"`
+}
+
+// ListOptions configures the plugin list command behavior
+type ListOptions struct {
+	Builtins     []string      // names of built-in commands, which win over plugins
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// ExecOptions configures how a plugin is run
+type ExecOptions struct {
+	JSON   bool // OMNI_OUTPUT=json
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Discover returns the plugins on pathEnv sorted by name. When several
+// directories hold the same plugin, the first one on PATH is used and the
+// others are listed in Shadowed.
+func Discover(pathEnv string) []Plugin {
+	byName := make(map[string]*Plugin)
+
+	var names []string
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			if p, seen := byName[name]; seen {
+				if p.Path != path {
+					p.Shadowed = append(p.Shadowed, path)
+				}
+
+				continue
+			}
+
+			byName[name] = &Plugin{Name: name, Path: path}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	plugins := make([]Plugin, 0, len(names))
+	for _, n := range names {
+		plugins = append(plugins, *byName[n])
+	}
+
+	return plugins
+}
+
+// Find returns the plugin called name on pathEnv.
+func Find(pathEnv, name string) (Plugin, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return Plugin{}, false
+	}
+
+	for _, p := range Discover(pathEnv) {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Plugin{}, false
+}
+
+// pluginName returns the subcommand name for an executable file name.
+func pluginName(file string) (string, bool) {
+	rest, ok := strings.CutPrefix(file, Prefix)
+	if !ok {
+		return "", false
+	}
+
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(rest))
+		if !slices.Contains(windowsExts(), ext) {
+			return "", false
+		}
+
+		rest = strings.TrimSuffix(rest, filepath.Ext(rest))
+	}
+
+	return rest, rest != ""
+}
+
+func windowsExts() []string {
+	exts := strings.Split(strings.ToLower(os.Getenv("PATHEXT")), ";")
+	if len(exts) == 1 && exts[0] == "" {
+		return []string{".com", ".exe", ".bat", ".cmd"}
+	}
+
+	return exts
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
+
+// RunList prints the plugins found on PATH
+func RunList(w io.Writer, opts ListOptions) error {
+	plugins := Discover(os.Getenv("PATH"))
+
+	for i := range plugins {
+		plugins[i].Builtin = slices.Contains(opts.Builtins, plugins[i].Name)
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(plugins)
+	}
+
+	if len(plugins) == 0 {
+		_, _ = fmt.Fprintf(w, "no plugins found (add %s<name> executables to PATH)\n", Prefix)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tPATH")
+
+	for _, p := range plugins {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", p.Name, p.Path)
+
+		if p.Builtin {
+			_, _ = fmt.Fprintf(tw, "\t  warning: overridden by the built-in '%s' command\n", p.Name)
+		}
+
+		for _, s := range p.Shadowed {
+			_, _ = fmt.Fprintf(tw, "\t  warning: shadows %s\n", s)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// Exec runs p with args and returns its exit status as an error: nil on
+// success, a cmderr.SilentError carrying the exit code otherwise.
+//
+// Sanctioned exec exception: the plugin executable is the command being
+// run. See docs/architecture/patterns.md § "No-exec invariant: scope &
+// sanctioned exceptions".
+func Exec(ctx context.Context, p Plugin, args []string, opts ExecOptions) error {
+	cmd := osexec.CommandContext(ctx, p.Path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = opts.Stdin, opts.Stdout, opts.Stderr
+
+	format := "text"
+	if opts.JSON {
+		format = "json"
+	}
+
+	self, _ := os.Executable()
+
+	cmd.Env = append(os.Environ(),
+		"OMNI_PLUGIN_NAME="+p.Name,
+		"OMNI_EXECUTABLE="+self,
+		"OMNI_OUTPUT="+format,
+	)
+
+	err := cmd.Run()
+
+	var exitErr *osexec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			return fmt.Errorf("plugin %s: %w", p.Name, err)
+		}
+
+		return cmderr.SilentExit(code)
+	}
+
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("plugin %s: %s", p.Name, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func writeScript(t *testing.T, path, body string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+
+	writeScript(t, filepath.Join(first, "omni-hello"), "")
+	writeScript(t, filepath.Join(second, "omni-hello"), "")
+	writeScript(t, filepath.Join(second, "omni-abc"), "")
+
+	// Not plugins: no exec bit, bare prefix, wrong prefix
+	if err := os.WriteFile(filepath.Join(first, "omni-data"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	writeScript(t, filepath.Join(first, "omni-"), "")
+	writeScript(t, filepath.Join(first, "other-tool"), "")
+
+	path := strings.Join([]string{first, second, first}, string(os.PathListSeparator))
+
+	plugins := Discover(path)
+	if len(plugins) != 2 || plugins[0].Name != "abc" || plugins[1].Name != "hello" {
+		t.Fatalf("Discover() = %+v", plugins)
+	}
+
+	hello := plugins[1]
+	if hello.Path != filepath.Join(first, "omni-hello") || len(hello.Shadowed) != 1 || hello.Shadowed[0] != filepath.Join(second, "omni-hello") {
+		t.Errorf("hello = %+v", hello)
+	}
+
+	if _, ok := Find(path, "abc"); !ok {
+		t.Error("Find(abc) not found")
+	}
+
+	for _, name := range []string{"data", "", "../omni-abc"} {
+		if _, ok := Find(path, name); ok {
+			t.Errorf("Find(%q) found a plugin", name)
+		}
+	}
+}
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, "omni-echo"), `echo "$OMNI_PLUGIN_NAME $OMNI_OUTPUT $*"; read line; echo "in=$line"; exit ${EXIT:-0}`+"\n")
+
+	p, ok := Find(dir, "echo")
+	if !ok {
+		t.Fatal("plugin not found")
+	}
+
+	var out bytes.Buffer
+
+	err := Exec(context.Background(), p, []string{"a b", "--json"}, ExecOptions{
+		JSON:   true,
+		Stdin:  strings.NewReader("hi\n"),
+		Stdout: &out,
+		Stderr: &out,
+	})
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	if out.String() != "echo json a b --json\nin=hi\n" {
+		t.Errorf("output = %q", out.String())
+	}
+
+	t.Setenv("EXIT", "4")
+
+	err = Exec(context.Background(), p, nil, ExecOptions{Stdin: strings.NewReader(""), Stdout: &out, Stderr: &out})
+
+	var silent *cmderr.SilentError
+	if !errors.As(err, &silent) || silent.Code != 4 {
+		t.Errorf("Exec() exit 4: err = %v", err)
+	}
+}
+
+func TestRunList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, "omni-ls"), "")
+	t.Setenv("PATH", dir)
+
+	var buf bytes.Buffer
+	if err := RunList(&buf, ListOptions{Builtins: []string{"ls"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "overridden by the built-in 'ls' command") {
+		t.Errorf("RunList() = %q", buf.String())
+	}
+}
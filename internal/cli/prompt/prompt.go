@@ -0,0 +1,339 @@
+// Package prompt asks the user for a value on the terminal and prints the
+// answer, so Taskfiles and scripts can gather input portably.
+//
+// Prompts are written to stderr and the answer to stdout. Without a
+// terminal on stdin, the answer is read as one line of stdin instead, and
+// an empty answer or end of input falls back to the default, so scripts
+// never hang waiting for a user who is not there.
+package prompt
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pick"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Kinds of prompt.
+const (
+	KindText     = "text"
+	KindPassword = "password"
+	KindConfirm  = "confirm"
+	KindSelect   = "select"
+)
+
+// Options configures the prompt command behavior
+type Options struct {
+	Message      string        // question shown to the user
+	Default      string        // answer used for empty input and without a terminal
+	Required     bool          // --required: reject an empty answer
+	Options      []string      // --options: choices of a select prompt
+	OutputFormat output.Format // output format (text, json)
+
+	Stdin     io.Reader // answers (default os.Stdin)
+	Stderr    io.Writer // prompts (default os.Stderr)
+	AssumeTTY bool      // treat Stdin as a terminal even when it is not
+}
+
+// Result is the JSON form of an answer
+type Result struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// maxAttempts bounds how often an invalid answer is asked again on a
+// terminal.
+const maxAttempts = 3
+
+// Seams for tests.
+var (
+	readPassword = term.ReadPassword
+	runPicker    = pick.Run
+)
+
+// prompter reads answers from one input.
+type prompter struct {
+	opts   Options
+	in     *bufio.Reader
+	fd     int
+	isTerm bool
+}
+
+func newPrompter(opts Options) *prompter {
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
+
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+
+	p := &prompter{opts: opts, in: bufio.NewReader(opts.Stdin), fd: -1, isTerm: opts.AssumeTTY}
+
+	if f, ok := opts.Stdin.(*os.File); ok {
+		p.fd = int(f.Fd())
+		p.isTerm = p.isTerm || term.IsTerminal(p.fd)
+	}
+
+	return p
+}
+
+// Run asks a prompt of the given kind and writes the answer to w. A
+// confirm prompt prints yes or no and fails with exit status 1 on no, so
+// it can guard a command with &&.
+func Run(w io.Writer, kind string, opts Options) error {
+	p := newPrompter(opts)
+
+	var (
+		value string
+		err   error
+	)
+
+	switch kind {
+	case KindText:
+		value, err = p.text(false)
+	case KindPassword:
+		value, err = p.text(true)
+	case KindConfirm:
+		value, err = p.confirm()
+	case KindSelect:
+		value, err = p.choose()
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("prompt: unknown prompt kind %q", kind))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if opts.OutputFormat == output.FormatJSON {
+		if err := json.NewEncoder(w).Encode(Result{Kind: kind, Value: value}); err != nil {
+			return err
+		}
+	} else {
+		_, _ = fmt.Fprintln(w, value)
+	}
+
+	if kind == KindConfirm && value == "no" {
+		return cmderr.SilentExit(1)
+	}
+
+	return nil
+}
+
+// text asks for a free-form answer. A password is not echoed on a
+// terminal.
+func (p *prompter) text(secret bool) (string, error) {
+	label := p.opts.Message
+	if label == "" {
+		label = "Value"
+		if secret {
+			label = "Password"
+		}
+	}
+
+	if p.opts.Default != "" && !secret {
+		label += " [" + p.opts.Default + "]"
+	}
+
+	for range maxAttempts {
+		answer, err := p.ask(label+": ", secret)
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "" {
+			answer = p.opts.Default
+		}
+
+		if answer != "" || !p.opts.Required {
+			return answer, nil
+		}
+
+		if !p.isTerm {
+			break
+		}
+
+		p.say("A value is required.\n")
+	}
+
+	return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: a value is required")
+}
+
+// confirm asks a yes/no question and returns "yes" or "no".
+func (p *prompter) confirm() (string, error) {
+	def, err := parseDefaultBool(p.opts.Default)
+	if err != nil {
+		return "", err
+	}
+
+	label := p.opts.Message
+	if label == "" {
+		label = "Continue?"
+	}
+
+	switch def {
+	case "yes":
+		label += " [Y/n] "
+	case "no":
+		label += " [y/N] "
+	default:
+		label += " [y/n] "
+	}
+
+	for range maxAttempts {
+		answer, err := p.ask(label, false)
+		if err != nil {
+			return "", err
+		}
+
+		switch strings.ToLower(answer) {
+		case "y", "yes", "true", "1":
+			return "yes", nil
+		case "n", "no", "false", "0":
+			return "no", nil
+		case "":
+			if def != "" {
+				return def, nil
+			}
+
+			if !p.isTerm {
+				return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: no terminal and no --default")
+			}
+		}
+
+		if !p.isTerm {
+			break
+		}
+
+		p.say("Please answer yes or no.\n")
+	}
+
+	return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: expected yes or no")
+}
+
+// choose asks for one of opts.Options. On a terminal it shows the fuzzy
+// picker; otherwise the answer may be an option or its 1-based number.
+func (p *prompter) choose() (string, error) {
+	choices := p.opts.Options
+	if len(choices) == 0 {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: select needs --options")
+	}
+
+	if p.opts.Default != "" && !slices.Contains(choices, p.opts.Default) {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("prompt: default %q is not one of the options", p.opts.Default))
+	}
+
+	if p.isTerm {
+		items := make([]pick.Item, len(choices))
+		for i, c := range choices {
+			items[i] = pick.Item{Title: c}
+		}
+
+		label := "> "
+		if p.opts.Message != "" {
+			label = p.opts.Message + " "
+		}
+
+		idx, err := runPicker(items, pick.Options{Prompt: label})
+		if errors.Is(err, pick.ErrCanceled) {
+			return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: canceled")
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		return choices[idx], nil
+	}
+
+	answer, err := p.ask("", false)
+	if err != nil {
+		return "", err
+	}
+
+	if answer == "" && p.opts.Default != "" {
+		return p.opts.Default, nil
+	}
+
+	if slices.Contains(choices, answer) {
+		return answer, nil
+	}
+
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1], nil
+	}
+
+	if answer == "" {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: no terminal and no --default")
+	}
+
+	return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("prompt: %q is not one of the options", answer))
+}
+
+// ask shows label on a terminal and reads one answer line. Without a
+// terminal an empty input is a valid, empty answer.
+func (p *prompter) ask(label string, secret bool) (string, error) {
+	if p.isTerm {
+		p.say(label)
+	}
+
+	if secret && p.isTerm && p.fd >= 0 {
+		b, err := readPassword(p.fd)
+
+		p.say("\n")
+
+		if err != nil {
+			return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("prompt: %v", err))
+		}
+
+		return string(b), nil
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("prompt: %v", err))
+	}
+
+	if errors.Is(err, io.EOF) && line == "" && p.isTerm {
+		// Ctrl+D at the prompt
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, "prompt: canceled")
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if !secret {
+		line = strings.TrimSpace(line)
+	}
+
+	return line, nil
+}
+
+func (p *prompter) say(s string) {
+	_, _ = io.WriteString(p.opts.Stderr, s)
+}
+
+// parseDefaultBool normalizes the default of a confirm prompt to "yes",
+// "no" or "".
+func parseDefaultBool(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", nil
+	case "y", "yes", "true", "1":
+		return "yes", nil
+	case "n", "no", "false", "0":
+		return "no", nil
+	}
+
+	return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("prompt: invalid confirm default %q (want yes or no)", s))
+}
@@ -0,0 +1,146 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pick"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		stdin   string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{name: "text from stdin", kind: KindText, stdin: "  alice \n", want: "alice\n"},
+		{name: "text default on empty input", kind: KindText, stdin: "", opts: Options{Default: "bob"}, want: "bob\n"},
+		{name: "text empty allowed", kind: KindText, stdin: "\n", want: "\n"},
+		{name: "text required", kind: KindText, stdin: "\n", opts: Options{Required: true}, wantErr: true},
+		{name: "password keeps spaces", kind: KindPassword, stdin: " s3cret \n", want: " s3cret \n"},
+		{name: "confirm yes", kind: KindConfirm, stdin: "Y\n", want: "yes\n"},
+		{name: "confirm default", kind: KindConfirm, stdin: "", opts: Options{Default: "yes"}, want: "yes\n"},
+		{name: "confirm without default", kind: KindConfirm, stdin: "", wantErr: true},
+		{name: "confirm garbage", kind: KindConfirm, stdin: "maybe\n", wantErr: true},
+		{name: "confirm bad default", kind: KindConfirm, opts: Options{Default: "perhaps"}, wantErr: true},
+		{name: "select by name", kind: KindSelect, stdin: "b\n", opts: Options{Options: []string{"a", "b", "c"}}, want: "b\n"},
+		{name: "select by number", kind: KindSelect, stdin: "3\n", opts: Options{Options: []string{"a", "b", "c"}}, want: "c\n"},
+		{name: "select default", kind: KindSelect, stdin: "", opts: Options{Options: []string{"a", "b"}, Default: "b"}, want: "b\n"},
+		{name: "select unknown", kind: KindSelect, stdin: "z\n", opts: Options{Options: []string{"a", "b"}}, wantErr: true},
+		{name: "select default not an option", kind: KindSelect, opts: Options{Options: []string{"a"}, Default: "z"}, wantErr: true},
+		{name: "select without options", kind: KindSelect, stdin: "a\n", wantErr: true},
+		{name: "unknown kind", kind: "color", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+
+			tt.opts.Stdin = strings.NewReader(tt.stdin)
+			tt.opts.Stderr = &errOut
+
+			err := Run(&out, tt.kind, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !cmderr.IsInvalidInput(err) {
+					t.Errorf("error = %v, want invalid input", err)
+				}
+
+				return
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+
+			if errOut.Len() != 0 {
+				t.Errorf("no prompt expected without a terminal, got %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunConfirmNo(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Run(&out, KindConfirm, Options{Stdin: strings.NewReader("n\n"), Stderr: &bytes.Buffer{}})
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("error = %v, want exit status 1", err)
+	}
+
+	if out.String() != "no\n" {
+		t.Errorf("output = %q, want no", out.String())
+	}
+}
+
+func TestRunTerminal(t *testing.T) {
+	t.Run("asks again on an invalid answer", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+
+		opts := Options{Message: "Deploy?", Stdin: strings.NewReader("what\ny\n"), Stderr: &errOut, AssumeTTY: true}
+		if err := Run(&out, KindConfirm, opts); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if want := "Deploy? [y/n] Please answer yes or no.\nDeploy? [y/n] "; errOut.String() != want {
+			t.Errorf("prompts = %q, want %q", errOut.String(), want)
+		}
+
+		if out.String() != "yes\n" {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+
+	t.Run("text shows the default", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+
+		opts := Options{Message: "Name", Default: "bob", Stdin: strings.NewReader("\n"), Stderr: &errOut, AssumeTTY: true}
+		if err := Run(&out, KindText, opts); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if errOut.String() != "Name [bob]: " || out.String() != "bob\n" {
+			t.Errorf("prompts = %q, output = %q", errOut.String(), out.String())
+		}
+	})
+
+	t.Run("end of input cancels", func(t *testing.T) {
+		opts := Options{Default: "bob", Stdin: strings.NewReader(""), Stderr: &bytes.Buffer{}, AssumeTTY: true}
+		if err := Run(&bytes.Buffer{}, KindText, opts); !cmderr.IsInvalidInput(err) {
+			t.Errorf("error = %v, want invalid input", err)
+		}
+	})
+
+	t.Run("select uses the picker", func(t *testing.T) {
+		old := runPicker
+		t.Cleanup(func() { runPicker = old })
+
+		var prompt string
+
+		runPicker = func(items []pick.Item, opts pick.Options) (int, error) {
+			prompt = opts.Prompt
+
+			return len(items) - 1, nil
+		}
+
+		var out bytes.Buffer
+
+		opts := Options{Message: "Env?", Options: []string{"dev", "prod"}, OutputFormat: output.FormatJSON, Stderr: &bytes.Buffer{}, AssumeTTY: true}
+		if err := Run(&out, KindSelect, opts); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if prompt != "Env? " || out.String() != `{"kind":"select","value":"prod"}`+"\n" {
+			t.Errorf("prompt = %q, output = %q", prompt, out.String())
+		}
+	})
+}
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/google/gops/goprocess"
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/tablewriter"
 )
 
 // Options configure the ps command behavior
@@ -41,6 +43,8 @@ type Info struct {
 	Start     string  `json:"start,omitempty"`
 	Time      string  `json:"time"` // CPU time
 	Command   string  `json:"command"`
+	Name      string  `json:"name,omitempty"`       // executable name without arguments
+	StartTime int64   `json:"start_time,omitempty"` // Unix seconds; 0 when unknown
 	IsGo      bool    `json:"is_go"`                // true if this is a Go process
 	GoVersion string  `json:"go_version,omitempty"` // Go version if IsGo
 	BuildInfo string  `json:"build_info,omitempty"` // Go build path if IsGo
@@ -88,12 +92,7 @@ func Run(w io.Writer, opts Options) error {
 		return f.Print(processes)
 	}
 
-	// Print output
-	if opts.Long || opts.Full || opts.Aux {
-		return printLong(w, processes, opts)
-	}
-
-	return printSimple(w, processes, opts)
+	return f.PrintTable(psTable(processes, opts))
 }
 
 // enrichWithGoInfo detects Go processes and adds Go-specific information
@@ -151,60 +150,104 @@ func sortProcesses(procs []Info, sortBy string) {
 	}
 }
 
-func printSimple(w io.Writer, procs []Info, opts Options) error {
-	if !opts.NoHeaders {
-		_, _ = fmt.Fprintf(w, "%5s %-8s %8s %s\n", "PID", "TTY", "TIME", "CMD")
-	}
+// sortColumns maps --sort keys to the header of the column they order
+// by and the direction: PID ascending, the others highest first.
+var sortColumns = map[string]struct {
+	header string
+	order  tablewriter.Sort
+}{
+	"pid":  {"PID", tablewriter.Ascending},
+	"cpu":  {"%CPU", tablewriter.Descending},
+	"mem":  {"%MEM", tablewriter.Descending},
+	"time": {"TIME", tablewriter.Descending},
+}
 
-	for _, p := range procs {
-		cmd := p.Command
-		if len(cmd) > 60 {
-			cmd = cmd[:60]
+// newPSTable returns an empty table with the given headers, each column
+// at least as wide as in widths. Headers listed in right are right-aligned
+// numbers, which are never shrunk to fit the terminal; the column sortBy
+// names gets a sort indicator. Plain text keeps the single-space columns
+// of the classic ps output that scripts split on.
+func newPSTable(headers []string, widths []int, right string, sortBy string, noHeader bool, format output.Format) *tablewriter.Table {
+	sorted := sortColumns[strings.ToLower(sortBy)]
+	numeric := strings.Fields(right)
+
+	cols := make([]tablewriter.Column, len(headers))
+	for i, h := range headers {
+		cols[i] = tablewriter.Column{Header: h, Width: widths[i]}
+
+		for _, n := range numeric {
+			if n == h {
+				cols[i].Align = tablewriter.AlignRight
+				cols[i].NoShrink = true
+			}
 		}
 
-		_, _ = fmt.Fprintf(w, "%5d %-8s %8s %s\n", p.PID, p.TTY, p.Time, cmd)
+		if h == sorted.header {
+			cols[i].Sort = sorted.order
+		}
 	}
 
-	return nil
-}
+	t := tablewriter.New(cols...)
+	t.NoHeader = noHeader
 
-func printLong(w io.Writer, procs []Info, opts Options) error {
-	if !opts.NoHeaders {
-		if opts.Aux {
-			_, _ = fmt.Fprintf(w, "%-8s %5s %4s %4s %8s %8s %-8s %-4s %-5s %8s %s\n",
-				"USER", "PID", "%CPU", "%MEM", "VSZ", "RSS", "TTY", "STAT", "START", "TIME", "COMMAND")
-		} else if opts.Long {
-			_, _ = fmt.Fprintf(w, "F S %5s %5s %5s  C PRI NI %8s %8s %-8s %8s %s\n",
-				"UID", "PID", "PPID", "SZ", "RSS", "TTY", "TIME", "CMD")
-		} else {
-			_, _ = fmt.Fprintf(w, "%-8s %5s %5s  C %-5s %8s %s\n",
-				"UID", "PID", "PPID", "STIME", "TIME", "CMD")
-		}
+	if format == output.FormatText {
+		t.Gap = " "
 	}
 
-	for _, p := range procs {
-		cmd := p.Command
-		if len(cmd) > 50 {
-			cmd = cmd[:50]
+	return t
+}
+
+// psTable lays procs out in the format opts selects: BSD aux, -l, -f or
+// the default PID/TTY/TIME/CMD listing.
+func psTable(procs []Info, opts Options) *tablewriter.Table {
+	var t *tablewriter.Table
+
+	switch {
+	case opts.Aux:
+		t = newPSTable([]string{"USER", "PID", "%CPU", "%MEM", "VSZ", "RSS", "TTY", "STAT", "START", "TIME", "COMMAND"},
+			[]int{8, 5, 4, 4, 8, 8, 8, 4, 5, 8, 0},
+			"PID %CPU %MEM VSZ RSS TIME", opts.Sort, opts.NoHeaders, opts.OutputFormat)
+
+		for _, p := range procs {
+			t.Append(p.User, strconv.Itoa(p.PID), formatPercent(p.CPU), formatPercent(p.MEM),
+				strconv.FormatInt(p.VSZ, 10), strconv.FormatInt(p.RSS, 10), p.TTY, p.Stat, p.Start, p.Time, p.Command)
 		}
+	case opts.Long:
+		t = newPSTable([]string{"F", "S", "UID", "PID", "PPID", "C", "PRI", "NI", "SZ", "RSS", "TTY", "TIME", "CMD"},
+			[]int{1, 1, 5, 5, 5, 2, 3, 2, 8, 8, 8, 8, 0},
+			"UID PID PPID C PRI NI SZ RSS TIME", opts.Sort, opts.NoHeaders, opts.OutputFormat)
 
-		if opts.Aux {
-			_, _ = fmt.Fprintf(w, "%-8s %5d %4.1f %4.1f %8d %8d %-8s %-4s %-5s %8s %s\n",
-				p.User, p.PID, p.CPU, p.MEM, p.VSZ, p.RSS, p.TTY, p.Stat, p.Start, p.Time, cmd)
-		} else if opts.Long {
+		for _, p := range procs {
 			stat := "?"
 			if len(p.Stat) > 0 {
 				stat = p.Stat[:1]
 			}
-			_, _ = fmt.Fprintf(w, "0 %s %5d %5d %5d  0  80  0 %8d %8d %-8s %8s %s\n",
-				stat, p.UID, p.PID, p.PPID, p.VSZ, p.RSS, p.TTY, p.Time, cmd)
-		} else {
-			_, _ = fmt.Fprintf(w, "%-8d %5d %5d  0 %-5s %8s %s\n",
-				p.UID, p.PID, p.PPID, p.Start, p.Time, cmd)
+
+			t.Append("0", stat, strconv.Itoa(p.UID), strconv.Itoa(p.PID), strconv.Itoa(p.PPID), "0", "80", "0",
+				strconv.FormatInt(p.VSZ, 10), strconv.FormatInt(p.RSS, 10), p.TTY, p.Time, p.Command)
+		}
+	case opts.Full:
+		t = newPSTable([]string{"UID", "PID", "PPID", "C", "STIME", "TIME", "CMD"},
+			[]int{8, 5, 5, 2, 5, 8, 0},
+			"PID PPID C TIME", opts.Sort, opts.NoHeaders, opts.OutputFormat)
+
+		for _, p := range procs {
+			t.Append(strconv.Itoa(p.UID), strconv.Itoa(p.PID), strconv.Itoa(p.PPID), "0", p.Start, p.Time, p.Command)
+		}
+	default:
+		t = newPSTable([]string{"PID", "TTY", "TIME", "CMD"}, []int{5, 8, 8, 0},
+			"PID TIME", opts.Sort, opts.NoHeaders, opts.OutputFormat)
+
+		for _, p := range procs {
+			t.Append(strconv.Itoa(p.PID), p.TTY, p.Time, p.Command)
 		}
 	}
 
-	return nil
+	return t
+}
+
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
 }
 
 // RunTop shows top N processes sorted by resource usage
@@ -244,33 +287,24 @@ func RunTop(w io.Writer, opts Options, n int) error {
 	}
 
 	// Print top-style output
-	return printTopOutput(w, processes)
+	return f.PrintTable(topTable(processes, opts.Sort, opts.OutputFormat))
 }
 
-func printTopOutput(w io.Writer, procs []Info) error {
-	// Header
-	_, _ = fmt.Fprintf(w, "%5s %-10s %6s %6s %10s %10s  %s\n",
-		"PID", "USER", "%CPU", "%MEM", "VSZ", "RSS", "COMMAND")
+// topTable lays procs out top-style; Go processes are marked [go].
+func topTable(procs []Info, sortBy string, format output.Format) *tablewriter.Table {
+	t := newPSTable([]string{"PID", "USER", "%CPU", "%MEM", "VSZ", "RSS", "COMMAND"},
+		[]int{5, 10, 6, 6, 10, 10, 0},
+		"PID %CPU %MEM VSZ RSS", sortBy, false, format)
 
 	for _, p := range procs {
 		cmd := p.Command
-		if len(cmd) > 50 {
-			cmd = cmd[:50] + "..."
-		}
-
-		user := p.User
-		if len(user) > 10 {
-			user = user[:10]
-		}
-
-		goMarker := ""
 		if p.IsGo {
-			goMarker = " [go]"
+			cmd = "[go] " + cmd
 		}
 
-		_, _ = fmt.Fprintf(w, "%5d %-10s %6.1f %6.1f %10d %10d  %s%s\n",
-			p.PID, user, p.CPU, p.MEM, p.VSZ, p.RSS, cmd, goMarker)
+		t.Append(strconv.Itoa(p.PID), p.User, formatPercent(p.CPU), formatPercent(p.MEM),
+			strconv.FormatInt(p.VSZ, 10), strconv.FormatInt(p.RSS, 10), cmd)
 	}
 
-	return nil
+	return t
 }
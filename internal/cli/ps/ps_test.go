@@ -445,3 +445,59 @@ func TestFilterGoProcesses_NoneGo(t *testing.T) {
 		t.Errorf("expected 0 Go processes, got %d", len(result))
 	}
 }
+
+// TestPSTableGolden pins the plain ps layouts scripts parse: single spaces
+// between columns, the classic column widths, and one word per header even
+// when sorted.
+func TestPSTableGolden(t *testing.T) {
+	procs := []Info{
+		{PID: 1, PPID: 0, UID: 0, User: "root", CPU: 1.5, MEM: 0.2, VSZ: 23984, RSS: 9484, TTY: "?", Stat: "S", Start: "02:06", Time: "1:57", Command: "/sbin/init"},
+		{PID: 4242, PPID: 1, UID: 1000, User: "alice", CPU: 12, MEM: 3.4, VSZ: 812000, RSS: 65000, TTY: "pts/0", Stat: "R", Start: "10:40", Time: "0:03", Command: "vim notes.txt"},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"default", Options{Sort: "cpu"}, "" +
+			"  PID TTY          TIME CMD\n" +
+			"    1 ?            1:57 /sbin/init\n" +
+			" 4242 pts/0        0:03 vim notes.txt\n"},
+		{"aux", Options{Aux: true, Sort: "cpu"}, "" +
+			"USER       PID %CPU %MEM      VSZ      RSS TTY      STAT START     TIME COMMAND\n" +
+			"root         1  1.5  0.2    23984     9484 ?        S    02:06     1:57 /sbin/init\n" +
+			"alice     4242 12.0  3.4   812000    65000 pts/0    R    10:40     0:03 vim notes.txt\n"},
+		{"full", Options{Full: true, Sort: "pid"}, "" +
+			"UID        PID  PPID  C STIME     TIME CMD\n" +
+			"0            1     0  0 02:06     1:57 /sbin/init\n" +
+			"1000      4242     1  0 10:40     0:03 vim notes.txt\n"},
+		{"long", Options{Long: true}, "" +
+			"F S   UID   PID  PPID  C PRI NI       SZ      RSS TTY          TIME CMD\n" +
+			"0 S     0     1     0  0  80  0    23984     9484 ?            1:57 /sbin/init\n" +
+			"0 R  1000  4242     1  0  80  0   812000    65000 pts/0        0:03 vim notes.txt\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.New(&buf, output.FormatText).PrintTable(psTable(procs, tt.opts)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("ps output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	// Markdown keeps the sort indicator
+	var buf bytes.Buffer
+	if err := output.New(&buf, output.FormatMarkdown).PrintTable(psTable(procs, Options{Sort: "pid"})); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "PID ▲") {
+		t.Errorf("markdown output lacks the sort indicator:\n%s", buf.String())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 )
@@ -32,6 +33,7 @@ func GetProcessList(opts Options) ([]Info, error) {
 	}
 
 	currentUID := os.Getuid()
+	bootTime := readBootTime()
 
 	for _, entry := range entries {
 		if !entry.IsDir() {
@@ -49,7 +51,7 @@ func GetProcessList(opts Options) ([]Info, error) {
 			continue
 		}
 
-		proc, err := readProcInfo(pid)
+		proc, err := readProcInfo(pid, bootTime)
 		if err != nil {
 			continue // Process may have exited
 		}
@@ -70,8 +72,8 @@ func GetProcessList(opts Options) ([]Info, error) {
 	return processes, nil
 }
 
-func readProcInfo(pid int) (Info, error) {
-	proc := Info{PID: pid}
+func readProcInfo(pid int, bootTime int64) (Info, error) {
+	proc := Info{PID: pid, Start: "?"}
 	procPath := filepath.Join("/proc", strconv.Itoa(pid))
 
 	// Read stat file
@@ -94,6 +96,7 @@ func readProcInfo(pid int) (Info, error) {
 	}
 
 	proc.Command = statStr[start+1 : end]
+	proc.Name = proc.Command
 	fields := strings.Fields(statStr[end+2:])
 
 	if len(fields) >= 2 {
@@ -114,6 +117,13 @@ func readProcInfo(pid int) (Info, error) {
 		proc.Time = formatCPUTime(totalTicks)
 	}
 
+	if len(fields) >= 20 && bootTime > 0 {
+		// Start time in clock ticks after boot
+		startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
+		proc.StartTime = bootTime + startTicks/100
+		proc.Start = formatStart(time.Unix(proc.StartTime, 0))
+	}
+
 	if len(fields) >= 21 {
 		// VSZ is in pages, convert to KB (assuming 4KB pages)
 		vsize, _ := strconv.ParseInt(fields[20], 10, 64)
@@ -172,9 +182,6 @@ func readProcInfo(pid int) (Info, error) {
 	proc.CPU = 0.0 // Would need to sample over time for accurate value
 	proc.MEM = 0.0 // Would need total memory for accurate value
 
-	// Start time (simplified)
-	proc.Start = "?"
-
 	return proc, nil
 }
 
@@ -196,6 +203,35 @@ func formatTTY(ttyNr int) string {
 	}
 }
 
+// readBootTime returns the system boot time in Unix seconds from /proc/stat,
+// or 0 if it cannot be determined.
+func readBootTime() int64 {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return 0
+	}
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "btime "); ok {
+			bt, _ := strconv.ParseInt(strings.TrimSpace(rest), 10, 64)
+			return bt
+		}
+	}
+
+	return 0
+}
+
+// formatStart renders a start time the way ps does: HH:MM for today,
+// MonDD otherwise.
+func formatStart(t time.Time) string {
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04")
+	}
+
+	return t.Format("Jan02")
+}
+
 func formatCPUTime(ticks int64) string {
 	// Assuming 100 ticks per second (CLK_TCK)
 	totalSeconds := ticks / 100
@@ -9,6 +9,7 @@ import (
 	"unsafe"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"golang.org/x/sys/windows"
 )
 
 var (
@@ -91,13 +92,16 @@ func GetProcessList(opts Options) ([]Info, error) {
 		}
 
 		{
+			name := syscall.UTF16ToString(entry.ExeFile[:])
 			proc := Info{
-				PID:     int(entry.ProcessID),
-				PPID:    int(entry.ParentProcessID),
-				Command: syscall.UTF16ToString(entry.ExeFile[:]),
-				TTY:     "?",
-				Stat:    "R",
-				Time:    "0:00",
+				PID:       int(entry.ProcessID),
+				PPID:      int(entry.ParentProcessID),
+				Command:   name,
+				Name:      name,
+				StartTime: processStartTime(entry.ProcessID),
+				TTY:       "?",
+				Stat:      "R",
+				Time:      "0:00",
 				// User field not supported on windows via Toolhelp32 snapshot API.
 				// Locked message format (pins POLISH-09 audit): "ps: field user not supported on windows"
 				User: "?",
@@ -117,3 +121,21 @@ func GetProcessList(opts Options) ([]Info, error) {
 
 	return processes, nil
 }
+
+// processStartTime returns the creation time of pid in Unix seconds, or 0
+// when the process cannot be opened (e.g. protected system processes).
+func processStartTime(pid uint32) int64 {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return 0
+	}
+
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return 0
+	}
+
+	return creation.Nanoseconds() / 1e9
+}
@@ -7,12 +7,13 @@ import (
 	"path/filepath"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	pathutil "github.com/inovacc/omni/internal/cli/path"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 // ReadlinkOptions configures the readlink command behavior
 type ReadlinkOptions struct {
-	Canonicalize         bool          // -f: canonicalize by following every symlink, all components must exist
+	Canonicalize         bool          // -f: canonicalize by following every symlink; all but the last component must exist
 	CanonicalizeExisting bool          // -e: like -f, but fail if any component doesn't exist
 	CanonicalizeMissing  bool          // -m: like -f, but allow missing components
 	NoNewline            bool          // -n: do not output trailing newline
@@ -96,33 +97,16 @@ func RunReadlink(w io.Writer, args []string, opts ReadlinkOptions) error {
 	return nil
 }
 
+// canonicalize resolves path with the existence rules of -f, -e or -m.
 func canonicalize(path string, opts ReadlinkOptions) (string, error) {
-	if opts.CanonicalizeMissing {
-		// Allow missing components - resolve what exists
-		return filepath.Abs(path)
+	switch {
+	case opts.CanonicalizeExisting:
+		return pathutil.Resolve(path, pathutil.ResolveExisting)
+	case opts.CanonicalizeMissing:
+		return pathutil.Resolve(path, pathutil.ResolveMissing)
+	default:
+		return pathutil.Resolve(path, pathutil.ResolveParents)
 	}
-
-	if opts.CanonicalizeExisting {
-		// All components must exist
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return "", err
-		}
-		// Verify path exists
-		if _, err := os.Stat(absPath); err != nil {
-			return "", err
-		}
-
-		return filepath.EvalSymlinks(absPath)
-	}
-
-	// Default -f: follow symlinks, all components must exist
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return "", err
-	}
-
-	return filepath.EvalSymlinks(absPath)
 }
 
 // Readlink reads the target of a symbolic link
@@ -1,18 +1,21 @@
 package realpath
 
 import (
-	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	pathutil "github.com/inovacc/omni/internal/cli/path"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 // RealpathOptions configures the realpath command behavior
 type RealpathOptions struct {
+	Existing     bool          // -e: all components of the path must exist (the default)
+	Missing      bool          // -m: no path components need exist or be a directory
+	NoSymlinks   bool          // -s: don't expand symlinks
+	RelativeTo   string        // --relative-to: print the resolved path relative to DIR
+	Zero         bool          // -z: end each output line with NUL, not newline
 	OutputFormat output.Format // output format (text, json, table)
 }
 
@@ -22,35 +25,43 @@ type RealpathResult struct {
 	Resolved string `json:"resolved"`
 }
 
-// RunRealpath prints the resolved absolute path for each argument
+// RunRealpath prints the resolved absolute path for each argument. Every
+// component must exist unless Missing is set.
 func RunRealpath(w io.Writer, args []string, opts RealpathOptions) error {
 	if len(args) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "realpath: missing operand")
 	}
 
+	if opts.Existing && opts.Missing {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "realpath: -e and -m are mutually exclusive")
+	}
+
+	resolveOpts := pathutil.ResolveOptions{NoSymlinks: opts.NoSymlinks, RelativeTo: opts.RelativeTo}
+
+	if opts.Missing {
+		resolveOpts.Mode = pathutil.ResolveMissing
+	}
+
+	eol := "\n"
+	if opts.Zero {
+		eol = "\x00"
+	}
+
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
 
 	var results []RealpathResult
 
 	for _, arg := range args {
-		absPath, err := filepath.Abs(arg)
-		if err != nil {
-			return fmt.Errorf("realpath: %w", err)
-		}
-
-		resolved, err := filepath.EvalSymlinks(absPath)
+		resolved, err := pathutil.ResolveWith(arg, resolveOpts)
 		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("realpath: %s", err))
-			}
-			return fmt.Errorf("realpath: %w", err)
+			return pathutil.ResolveError("realpath", err)
 		}
 
 		if jsonMode {
 			results = append(results, RealpathResult{Original: arg, Resolved: resolved})
 		} else {
-			_, _ = fmt.Fprintln(w, resolved)
+			_, _ = fmt.Fprint(w, resolved, eol)
 		}
 	}
 
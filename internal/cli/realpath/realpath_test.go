@@ -359,3 +359,40 @@ func TestRunRealpath(t *testing.T) {
 		}
 	})
 }
+
+func TestRunRealpathModes(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missing := filepath.Join(tmpDir, "a", "b")
+
+	var buf bytes.Buffer
+
+	if err := RunRealpath(&buf, []string{missing}, RealpathOptions{}); err == nil {
+		t.Error("RunRealpath() expected error for a missing path")
+	}
+
+	if err := RunRealpath(&buf, []string{missing}, RealpathOptions{Missing: true, Zero: true}); err != nil {
+		t.Fatalf("RunRealpath(-m) error = %v", err)
+	}
+
+	if buf.String() != missing+"\x00" {
+		t.Errorf("RunRealpath(-m -z) = %q", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := RunRealpath(&buf, []string{missing}, RealpathOptions{Missing: true, RelativeTo: tmpDir}); err != nil {
+		t.Fatalf("RunRealpath(--relative-to) error = %v", err)
+	}
+
+	if got := strings.TrimSpace(buf.String()); got != filepath.Join("a", "b") {
+		t.Errorf("RunRealpath(--relative-to) = %q", got)
+	}
+
+	if err := RunRealpath(&buf, []string{missing}, RealpathOptions{Missing: true, Existing: true}); err == nil {
+		t.Error("RunRealpath(-e -m) expected error")
+	}
+}
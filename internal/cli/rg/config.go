@@ -0,0 +1,79 @@
+package rg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/config"
+	pkgrg "github.com/inovacc/omni/pkg/search/rg"
+)
+
+// configSection is the omni config section holding rg settings.
+const configSection = "rg"
+
+// Config is the rg section of the omni config file
+type Config struct {
+	TypeAdd      []string `yaml:"type-add,omitempty"`      // type definitions applied before --type-add
+	DefaultFlags []string `yaml:"default-flags,omitempty"` // flags applied before the command line, one argument per element
+}
+
+// LoadConfig reads the rg section of the omni config file.
+func LoadConfig() (Config, error) {
+	var c Config
+
+	f, err := config.Load()
+	if err != nil {
+		return c, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: %s", err))
+	}
+
+	if err := f.Section(configSection, &c); err != nil {
+		return c, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: %s", err))
+	}
+
+	return c, nil
+}
+
+// SaveTypes updates the type definitions in the omni config: definitions
+// of the types in clear are removed, then add is appended, skipping
+// definitions already present. It returns the path of the config file.
+func SaveTypes(add, clear []string) (string, error) {
+	types := pkgrg.DefaultTypes()
+	for _, spec := range add {
+		if err := types.Add(spec); err != nil {
+			return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: %s", err))
+		}
+	}
+
+	f, err := config.Load()
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: %s", err))
+	}
+
+	var c Config
+	if err := f.Section(configSection, &c); err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: %s", err))
+	}
+
+	c.TypeAdd = slices.DeleteFunc(c.TypeAdd, func(spec string) bool {
+		name, _, _ := strings.Cut(spec, ":")
+		return slices.Contains(clear, strings.TrimSpace(name))
+	})
+
+	for _, spec := range add {
+		if !slices.Contains(c.TypeAdd, spec) {
+			c.TypeAdd = append(c.TypeAdd, spec)
+		}
+	}
+
+	if err := f.SetSection(configSection, c); err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("rg: %s", err))
+	}
+
+	if err := f.Save(); err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("rg: %s", err))
+	}
+
+	return f.Path(), nil
+}
@@ -0,0 +1,249 @@
+package rg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/spf13/pflag"
+)
+
+// EnvDefaultFlags names the environment variable holding default rg flags.
+const EnvDefaultFlags = "OMNI_RG_DEFAULT_FLAGS"
+
+// RepoConfigName is the per-repository defaults file, looked up from the
+// search root towards the filesystem root.
+const RepoConfigName = ".omnirg"
+
+// repoRestricted lists flags a per-repository file may not set: a cloned
+// repository must not be able to run commands, read stdin or write the
+// user's config just by being searched.
+var repoRestricted = []string{"pre", "files-from", "type-save"}
+
+// DefaultSource is one set of default flags and where it came from.
+type DefaultSource struct {
+	Origin string   // "config", "$OMNI_RG_DEFAULT_FLAGS" or the .omnirg path
+	Args   []string // flags, one argument per element
+	Repo   bool     // from a per-repository file
+}
+
+// LoadDefaults collects the default flags that apply to a search rooted at
+// root, lowest precedence first: the default-flags list of the rg config
+// section, $OMNI_RG_DEFAULT_FLAGS, then the nearest .omnirg file.
+func LoadDefaults(cfg Config, root string) ([]DefaultSource, error) {
+	var sources []DefaultSource
+
+	if len(cfg.DefaultFlags) > 0 {
+		sources = append(sources, DefaultSource{Origin: "config", Args: cfg.DefaultFlags})
+	}
+
+	if env := os.Getenv(EnvDefaultFlags); strings.TrimSpace(env) != "" {
+		args, err := splitArgs(env)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: $%s: %s", EnvDefaultFlags, err))
+		}
+
+		sources = append(sources, DefaultSource{Origin: "$" + EnvDefaultFlags, Args: args})
+	}
+
+	path := findRepoConfig(root)
+	if path == "" {
+		return sources, nil
+	}
+
+	args, err := readRepoConfig(path)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("rg: %s", err))
+	}
+
+	if len(args) > 0 {
+		sources = append(sources, DefaultSource{Origin: path, Args: args, Repo: true})
+	}
+
+	return sources, nil
+}
+
+// ApplyDefaults merges default flags into fs after the command line has
+// been parsed. Flags given on the command line win over defaults, except
+// repeatable flags (-g, -t, --type-add, ...), whose defaults come before
+// the command line values. Later sources override earlier ones the same way.
+func ApplyDefaults(fs *pflag.FlagSet, sources []DefaultSource) error {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *pflag.Flag) { explicit[f.Name] = true })
+
+	// Set repeatable flags aside so the defaults are collected first.
+	given := make(map[string][]string)
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			given[f.Name] = sv.GetSlice()
+			_ = sv.Replace(nil)
+		}
+	})
+
+	var err error
+
+	for _, src := range sources {
+		if err = applySource(fs, src, explicit); err != nil {
+			break
+		}
+	}
+
+	for name, values := range given {
+		sv := fs.Lookup(name).Value.(pflag.SliceValue)
+		_ = sv.Replace(append(sv.GetSlice(), values...))
+	}
+
+	return err
+}
+
+func applySource(fs *pflag.FlagSet, src DefaultSource, explicit map[string]bool) error {
+	err := fs.ParseAll(src.Args, func(f *pflag.Flag, value string) error {
+		if src.Repo && slices.Contains(repoRestricted, f.Name) {
+			return fmt.Errorf("--%s is not allowed in %s", f.Name, RepoConfigName)
+		}
+
+		if _, repeatable := f.Value.(pflag.SliceValue); explicit[f.Name] && !repeatable {
+			return nil
+		}
+
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid argument %q for --%s: %w", value, f.Name, err)
+		}
+
+		f.Changed = true
+
+		return nil
+	})
+	if err == nil && len(fs.Args()) > 0 {
+		err = fmt.Errorf("unexpected argument %q (defaults may only contain flags)", fs.Args()[0])
+	}
+
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: default flags from %s: %s", src.Origin, err))
+	}
+
+	return nil
+}
+
+// findRepoConfig returns the nearest .omnirg in root or one of its parents.
+func findRepoConfig(root string) string {
+	dir, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		path := filepath.Join(dir, RepoConfigName)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// readRepoConfig reads a ripgrep-style config file: one argument per line,
+// blank lines and lines starting with # ignored.
+func readRepoConfig(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var args []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		args = append(args, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return args, nil
+}
+
+// splitArgs splits s into words like a POSIX shell without expansion:
+// single quotes are literal, double quotes and backslashes escape.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+
+	if inWord {
+		args = append(args, word.String())
+	}
+
+	return args, nil
+}
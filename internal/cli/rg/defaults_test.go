@@ -0,0 +1,159 @@
+package rg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/spf13/pflag"
+)
+
+// newDefaultsFlagSet returns a flag set with a few rg flags, parsed from args.
+func newDefaultsFlagSet(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+
+	fs := pflag.NewFlagSet("rg", pflag.ContinueOnError)
+	fs.BoolP("smart-case", "S", false, "")
+	fs.BoolP("ignore-case", "i", false, "")
+	fs.IntP("max-count", "m", 0, "")
+	fs.StringSliceP("glob", "g", nil, "")
+	fs.StringArray("type-add", nil, "")
+	fs.String("pre", "", "")
+
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	return fs
+}
+
+func TestApplyDefaults(t *testing.T) {
+	fs := newDefaultsFlagSet(t, "-m", "5", "-g", "*.go", "pattern")
+
+	sources := []DefaultSource{
+		{Origin: "config", Args: []string{"--smart-case", "--max-count=1", "--glob=!vendor/"}},
+		{Origin: ".omnirg", Args: []string{"-g", "!*.min.js", "--type-add", "web:*.html"}, Repo: true},
+	}
+
+	if err := ApplyDefaults(fs, sources); err != nil {
+		t.Fatalf("ApplyDefaults() error = %v", err)
+	}
+
+	if v, _ := fs.GetBool("smart-case"); !v || !fs.Changed("smart-case") {
+		t.Error("--smart-case default not applied")
+	}
+
+	if v, _ := fs.GetInt("max-count"); v != 5 {
+		t.Errorf("max-count = %d, want the command line value 5", v)
+	}
+
+	globs, _ := fs.GetStringSlice("glob")
+	if want := []string{"!vendor/", "!*.min.js", "*.go"}; !reflect.DeepEqual(globs, want) {
+		t.Errorf("glob = %q, want %q", globs, want)
+	}
+
+	types, _ := fs.GetStringArray("type-add")
+	if want := []string{"web:*.html"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("type-add = %q, want %q", types, want)
+	}
+}
+
+func TestApplyDefaultsErrors(t *testing.T) {
+	tests := map[string]DefaultSource{
+		"unknown flag":    {Origin: "config", Args: []string{"--no-such-flag"}},
+		"positional":      {Origin: "config", Args: []string{"pattern"}},
+		"bad value":       {Origin: "config", Args: []string{"--max-count=lots"}},
+		"restricted flag": {Origin: ".omnirg", Args: []string{"--pre=sh"}, Repo: true},
+		"missing value":   {Origin: "config", Args: []string{"--glob=a", "--pre"}},
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := newDefaultsFlagSet(t, "-g", "*.go")
+
+			err := ApplyDefaults(fs, []DefaultSource{src})
+			if !cmderr.IsInvalidInput(err) {
+				t.Errorf("ApplyDefaults() error = %v, want invalid input", err)
+			}
+
+			// The command line values survive a failed merge.
+			if globs, _ := fs.GetStringSlice("glob"); len(globs) == 0 || globs[len(globs)-1] != "*.go" {
+				t.Errorf("glob = %q after error", globs)
+			}
+		})
+	}
+
+	t.Run("pre allowed outside repo files", func(t *testing.T) {
+		fs := newDefaultsFlagSet(t)
+
+		if err := ApplyDefaults(fs, []DefaultSource{{Origin: "config", Args: []string{"--pre=conv"}}}); err != nil {
+			t.Errorf("ApplyDefaults() error = %v", err)
+		}
+	})
+}
+
+func TestLoadDefaults(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	rc := "# team defaults\n\n--glob=!vendor/\n  --smart-case  \n"
+	if err := os.WriteFile(filepath.Join(root, RepoConfigName), []byte(rc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvDefaultFlags, `-g '!*.min.js' --type-add "web:*.html,*.css"`)
+
+	sources, err := LoadDefaults(Config{DefaultFlags: []string{"--hidden"}}, sub)
+	if err != nil {
+		t.Fatalf("LoadDefaults() error = %v", err)
+	}
+
+	want := []DefaultSource{
+		{Origin: "config", Args: []string{"--hidden"}},
+		{Origin: "$" + EnvDefaultFlags, Args: []string{"-g", "!*.min.js", "--type-add", "web:*.html,*.css"}},
+		{Origin: filepath.Join(root, RepoConfigName), Args: []string{"--glob=!vendor/", "--smart-case"}, Repo: true},
+	}
+
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("LoadDefaults() = %+v, want %+v", sources, want)
+	}
+
+	t.Run("unterminated quote", func(t *testing.T) {
+		t.Setenv(EnvDefaultFlags, `-g '*.go`)
+
+		if _, err := LoadDefaults(Config{}, sub); !cmderr.IsInvalidInput(err) {
+			t.Errorf("LoadDefaults() error = %v, want invalid input", err)
+		}
+	})
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  -S   --hidden ", []string{"-S", "--hidden"}},
+		{`-g '!*.min.js'`, []string{"-g", "!*.min.js"}},
+		{`--type-add "web:*.html, *.css"`, []string{"--type-add", "web:*.html, *.css"}},
+		{`a\ b "c\"d" ''`, []string{"a b", `c"d`, ""}},
+	}
+
+	for _, tt := range tests {
+		got, err := splitArgs(tt.in)
+		if err != nil {
+			t.Errorf("splitArgs(%q) error = %v", tt.in, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
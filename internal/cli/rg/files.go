@@ -0,0 +1,215 @@
+package rg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/input"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	pkgrg "github.com/inovacc/omni/pkg/search/rg"
+)
+
+// FilesResult represents rg --files output for JSON
+type FilesResult struct {
+	Files []string `json:"files"`
+	Count int      `json:"count"`
+}
+
+// TypeDef is one file type for rg --type-list JSON output
+type TypeDef struct {
+	Name  string   `json:"name"`
+	Globs []string `json:"globs"`
+}
+
+// resolveTypes builds the file type set from the built-ins, ConfigTypes,
+// TypeClear and TypeAdd, in that order, and checks that every -t/-T name
+// is defined.
+func resolveTypes(opts *Options) error {
+	types := pkgrg.DefaultTypes()
+
+	for _, spec := range opts.ConfigTypes {
+		if err := types.Add(spec); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: config: %s", err))
+		}
+	}
+
+	for _, name := range opts.TypeClear {
+		types.Clear(name)
+	}
+
+	for _, spec := range opts.TypeAdd {
+		if err := types.Add(spec); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: %s", err))
+		}
+	}
+
+	for _, name := range append(append([]string(nil), opts.Types...), opts.TypesNot...) {
+		if !types.Has(name) {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: unrecognized file type: %s (see --type-list)", name))
+		}
+	}
+
+	opts.types = types
+
+	return nil
+}
+
+// CompleteTypes returns the file type names for shell completion: the
+// built-ins, the types from the omni config and typeAdd. Definitions that
+// do not parse are skipped rather than reported.
+func CompleteTypes(typeAdd []string) []string {
+	types := pkgrg.DefaultTypes()
+
+	if cfg, err := LoadConfig(); err == nil {
+		for _, spec := range cfg.TypeAdd {
+			_ = types.Add(spec)
+		}
+	}
+
+	for _, spec := range typeAdd {
+		_ = types.Add(spec)
+	}
+
+	return types.Names()
+}
+
+// RunFiles prints the files rg would search under paths, applying the same
+// hidden, ignore, type, glob and depth rules as a search. Unreadable paths
+// are reported on standard error and make rg exit with status 2.
+func RunFiles(ctx context.Context, w io.Writer, paths []string, opts Options) error {
+	if err := resolveTypes(&opts); err != nil {
+		return err
+	}
+
+	files, failed, err := gatherFiles(ctx, paths, opts)
+	if err != nil {
+		return err
+	}
+
+	f := output.New(w, opts.OutputFormat)
+
+	if f.IsJSON() {
+		if files == nil {
+			files = []string{}
+		}
+
+		if err := f.Print(FilesResult{Files: files, Count: len(files)}); err != nil {
+			return err
+		}
+	} else {
+		term := "\n"
+		if opts.Null {
+			term = "\x00"
+		}
+
+		for _, file := range files {
+			_, _ = fmt.Fprint(w, file, term)
+		}
+	}
+
+	if failed {
+		return cmderr.SilentExit(2)
+	}
+
+	return nil
+}
+
+// printFileName prints a path on its own line for -l, colored like a
+// heading, or with --null followed by a NUL byte and never colored so it
+// can be split reliably.
+func printFileName(w io.Writer, path string, opts Options) {
+	if opts.Null {
+		_, _ = fmt.Fprint(w, path, "\x00")
+		return
+	}
+
+	useColor, scheme := colorSettings(opts)
+	_, _ = fmt.Fprintln(w, FormatPath(path, scheme, useColor))
+}
+
+// gatherFiles returns the files a search of paths would read: explicit file
+// arguments as given, the walk of each directory, and the --files-from
+// list. Paths that cannot be read are reported on stderr and set failed.
+func gatherFiles(ctx context.Context, paths []string, opts Options) (files []string, failed bool, err error) {
+	if opts.FilesFrom != "" {
+		listed, err := readFilesFrom(opts.FilesFrom)
+		if err != nil {
+			return nil, false, err
+		}
+
+		files = filterListed(os.Stderr, listed, opts)
+	} else if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "rg: %s: %v\n", path, err)
+			failed = true
+
+			continue
+		}
+
+		if !info.IsDir() {
+			// Explicit file arguments are listed as given, like ripgrep does
+			files = append(files, path)
+			continue
+		}
+
+		var gitignore *IgnoreMatcher
+
+		if !opts.NoIgnore {
+			gitignore = newIgnoreMatcher(path, opts.IgnoreCacheDir)
+		}
+
+		if err := collectFiles(ctx, path, opts, gitignore, &files, 0); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return files, failed, nil
+}
+
+// readFilesFrom reads the --files-from list ("-" for stdin)
+func readFilesFrom(name string) ([]string, error) {
+	listed, err := input.ReadFileList(name, os.Stdin)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("rg: --files-from: %v", err))
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("rg: --files-from: %v", err))
+	}
+
+	return listed, nil
+}
+
+// RunTypeList prints every file type with its globs, including the ones
+// from the omni config and TypeAdd.
+func RunTypeList(w io.Writer, opts Options) error {
+	if err := resolveTypes(&opts); err != nil {
+		return err
+	}
+
+	defs := make([]TypeDef, 0, len(opts.types.Names()))
+	for _, name := range opts.types.Names() {
+		defs = append(defs, TypeDef{Name: name, Globs: opts.types.Globs(name)})
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(defs)
+	}
+
+	for _, d := range defs {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", d.Name, strings.Join(d.Globs, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package rg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func setupFilesTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"a.go", "b.log", ".hidden.go", "site/index.html", "site/style.css", "site/app.js"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte("x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".ignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func listFiles(t *testing.T, dir string, opts Options) []string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := RunFiles(context.Background(), &buf, []string{dir}, opts); err != nil {
+		t.Fatalf("RunFiles() error = %v", err)
+	}
+
+	var got []string
+
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line != "" {
+			rel, _ := filepath.Rel(dir, line)
+			got = append(got, filepath.ToSlash(rel))
+		}
+	}
+
+	return got
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := setupFilesTree(t)
+
+	t.Run("honors hidden and ignore rules", func(t *testing.T) {
+		got := strings.Join(listFiles(t, dir, Options{}), " ")
+		if got != "a.go site/app.js site/index.html site/style.css" {
+			t.Errorf("files = %s", got)
+		}
+	})
+
+	t.Run("type and glob filters", func(t *testing.T) {
+		got := strings.Join(listFiles(t, dir, Options{TypesNot: []string{"go"}, Glob: []string{"!*.js"}}), " ")
+		if got != "site/index.html site/style.css" {
+			t.Errorf("files = %s", got)
+		}
+	})
+
+	t.Run("custom types", func(t *testing.T) {
+		opts := Options{
+			ConfigTypes: []string{"web:*.html,*.css"},
+			TypeClear:   []string{"web"},
+			TypeAdd:     []string{"web:*.js"},
+			Types:       []string{"web"},
+		}
+
+		got := strings.Join(listFiles(t, dir, opts), " ")
+		if got != "site/app.js" {
+			t.Errorf("files = %s", got)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunFiles(context.Background(), &buf, []string{dir}, Options{Types: []string{"go"}, Null: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := buf.String(), filepath.Join(dir, "a.go")+"\x00"; got != want {
+			t.Errorf("files = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunFiles(context.Background(), &buf, []string{dir}, Options{Types: []string{"go"}, OutputFormat: output.FormatJSON}); err != nil {
+			t.Fatal(err)
+		}
+
+		var res FilesResult
+		if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Count != 1 || filepath.Base(res.Files[0]) != "a.go" {
+			t.Errorf("result = %+v", res)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RunFiles(context.Background(), &buf, []string{dir}, Options{Types: []string{"nope"}})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("unknown type error = %v", err)
+		}
+
+		err = RunFiles(context.Background(), &buf, []string{filepath.Join(dir, "missing")}, Options{})
+		if code := cmderr.ExitCodeFor(err); code != 2 {
+			t.Errorf("missing path error = %v", err)
+		}
+	})
+}
+
+func TestRunTypeList(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunTypeList(&buf, Options{TypeAdd: []string{"web:*.html,*.css"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"go: *.go\n", "web: *.css, *.html\n", "make: *.mk, GNUmakefile, Makefile, makefile\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("type list missing %q", want)
+		}
+	}
+
+	if err := RunTypeList(&buf, Options{TypeAdd: []string{"web"}}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("bad --type-add error = %v", err)
+	}
+}
+
+func TestSaveTypes(t *testing.T) {
+	t.Setenv("OMNI_CONFIG", filepath.Join(t.TempDir(), "omni", "config.yaml"))
+
+	if _, err := SaveTypes([]string{"web:*.html", "tmpl:*.tmpl"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SaveTypes([]string{"web:*.htm", "tmpl:*.tmpl"}, []string{"web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(cfg.TypeAdd, " "); got != "tmpl:*.tmpl web:*.htm" {
+		t.Errorf("saved types = %s", got)
+	}
+
+	if _, err := SaveTypes([]string{"bad"}, nil); !cmderr.IsInvalidInput(err) {
+		t.Errorf("SaveTypes(bad) error = %v", err)
+	}
+}
+
+func TestCompleteTypes(t *testing.T) {
+	t.Setenv("OMNI_CONFIG", filepath.Join(t.TempDir(), "omni", "config.yaml"))
+
+	names := CompleteTypes([]string{"tmpl:*.tmpl"})
+
+	for _, want := range []string{"go", "tmpl"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("CompleteTypes() missing %q", want)
+		}
+	}
+}
+
+func TestFilesFrom(t *testing.T) {
+	dir := setupFilesTree(t)
+
+	// Listed files bypass hidden and ignore rules; directories are skipped
+	var list []string
+	for _, name := range []string{"a.go", ".hidden.go", "b.log", "site"} {
+		list = append(list, filepath.Join(dir, name))
+	}
+
+	listFile := filepath.Join(t.TempDir(), "list")
+	if err := os.WriteFile(listFile, []byte(strings.Join(list, "\x00")+"\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := func(opts Options) string {
+		var buf bytes.Buffer
+
+		opts.FilesFrom = listFile
+		if err := RunFiles(context.Background(), &buf, nil, opts); err != nil {
+			t.Fatalf("RunFiles() error = %v", err)
+		}
+
+		var got []string
+		for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+			got = append(got, filepath.Base(line))
+		}
+
+		return strings.Join(got, " ")
+	}
+
+	if got := files(Options{}); got != "a.go .hidden.go b.log" {
+		t.Errorf("files = %s", got)
+	}
+
+	if got := files(Options{Types: []string{"go"}}); got != "a.go .hidden.go" {
+		t.Errorf("files -t go = %s", got)
+	}
+
+	for _, threads := range []int{1, 4} {
+		var buf bytes.Buffer
+		if err := Run(context.Background(), &buf, "x", nil, Options{FilesFrom: listFile, NoHeading: true, Threads: threads}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if n := strings.Count(buf.String(), ":x\n"); n != 3 {
+			t.Errorf("threads=%d: %d matches, want 3:\n%s", threads, n, buf.String())
+		}
+	}
+
+	err := Run(context.Background(), &bytes.Buffer{}, "", nil, Options{FilesFrom: "-", PatternFiles: []string{"-"}})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("both from stdin error = %v", err)
+	}
+
+	err = RunFiles(context.Background(), &bytes.Buffer{}, nil, Options{FilesFrom: filepath.Join(dir, "missing")})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("missing list error = %v", err)
+	}
+}
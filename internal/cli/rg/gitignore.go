@@ -28,30 +28,65 @@ type Gitignore = pkgrg.Gitignore
 // GitignoreSet is an alias for the pkg type
 type GitignoreSet = pkgrg.GitignoreSet
 
+// IgnoreMatcher is an alias for the pkg type
+type IgnoreMatcher = pkgrg.IgnoreMatcher
+
 // NewGitignoreSet creates a new GitignoreSet loading patterns from multiple sources
 func NewGitignoreSet(searchDir string) *GitignoreSet {
 	absSearchDir, _ := filepath.Abs(searchDir)
 	gs := pkgrg.NewGitignoreSet(absSearchDir)
 
-	// 1. Load global gitignore (~/.config/git/ignore)
-	if globalPath := getGlobalGitignorePath(); globalPath != "" {
-		if gi := loadGitignoreFile(globalPath, ""); gi != nil {
+	for _, f := range ignoreFiles(searchDir) {
+		if gi := loadGitignoreFile(f.Path, f.BasePath); gi != nil {
 			gs.AddGitignore(gi)
 		}
 	}
 
+	return gs
+}
+
+// newIgnoreMatcher compiles the ignore files that apply to searchDir, with
+// the common ignores added. With a cacheDir, compiled rules are reused
+// across runs until one of the files changes.
+func newIgnoreMatcher(searchDir, cacheDir string) *IgnoreMatcher {
+	m, err := pkgrg.LoadIgnoreMatcher(searchDir, ignoreFiles(searchDir), cacheDir)
+	if err != nil {
+		// Unreadable files are skipped, as NewGitignoreSet does
+		m = NewGitignoreSet(searchDir).Compile()
+	}
+
+	m.AddCommonIgnores()
+
+	return m
+}
+
+// DefaultIgnoreCacheDir returns <os.UserCacheDir>/omni/rg-ignore.
+func DefaultIgnoreCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "omni", "rg-ignore"), nil
+}
+
+// ignoreFiles lists the ignore files that may apply to searchDir, lowest
+// precedence first.
+func ignoreFiles(searchDir string) []pkgrg.IgnoreFile {
+	var files []pkgrg.IgnoreFile
+
+	// 1. Global gitignore (~/.config/git/ignore)
+	if globalPath := getGlobalGitignorePath(); globalPath != "" {
+		files = append(files, pkgrg.IgnoreFile{Path: globalPath})
+	}
+
 	// 2. Find git root and load .git/info/exclude
 	if gitRoot := findGitRoot(searchDir); gitRoot != "" {
-		excludePath := filepath.Join(gitRoot, ".git", "info", "exclude")
-		if gi := loadGitignoreFile(excludePath, gitRoot); gi != nil {
-			gs.AddGitignore(gi)
-		}
+		files = append(files, pkgrg.IgnoreFile{Path: filepath.Join(gitRoot, ".git", "info", "exclude"), BasePath: gitRoot})
 	}
 
-	// 3. Load .gitignore and .ignore files walking up from searchDir
-	loadIgnoreFilesFromHierarchy(gs, searchDir)
-
-	return gs
+	// 3. .gitignore and .ignore files walking up from searchDir
+	return append(files, hierarchyIgnoreFiles(searchDir)...)
 }
 
 // getGlobalGitignorePath returns the path to the global gitignore file
@@ -110,11 +145,12 @@ func findGitRoot(dir string) string {
 	return ""
 }
 
-// loadIgnoreFilesFromHierarchy loads .gitignore and .ignore files from dir up to root
-func loadIgnoreFilesFromHierarchy(gs *GitignoreSet, dir string) {
+// hierarchyIgnoreFiles lists the .gitignore and .ignore files from the
+// filesystem root down to dir (later files override earlier)
+func hierarchyIgnoreFiles(dir string) []pkgrg.IgnoreFile {
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
-		return
+		return nil
 	}
 
 	// Collect directories from root to dir (we want root patterns first)
@@ -133,20 +169,16 @@ func loadIgnoreFilesFromHierarchy(gs *GitignoreSet, dir string) {
 		current = parent
 	}
 
-	// Load patterns from root to dir (later files override earlier)
-	for _, d := range dirs {
-		// Load .gitignore
-		gitignorePath := filepath.Join(d, ".gitignore")
-		if gi := loadGitignoreFile(gitignorePath, d); gi != nil {
-			gs.AddGitignore(gi)
-		}
+	files := make([]pkgrg.IgnoreFile, 0, 2*len(dirs))
 
-		// Load .ignore (ripgrep-specific)
-		ignorePath := filepath.Join(d, ".ignore")
-		if gi := loadGitignoreFile(ignorePath, d); gi != nil {
-			gs.AddGitignore(gi)
-		}
+	for _, d := range dirs {
+		files = append(files,
+			pkgrg.IgnoreFile{Path: filepath.Join(d, ".gitignore"), BasePath: d},
+			pkgrg.IgnoreFile{Path: filepath.Join(d, ".ignore"), BasePath: d}, // ripgrep-specific
+		)
 	}
+
+	return files
 }
 
 // loadGitignoreFile loads patterns from a single ignore file
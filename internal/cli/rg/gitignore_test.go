@@ -348,6 +348,43 @@ build/
 	}
 }
 
+// TestIgnoreMatcherHierarchy checks that the cached compiled matcher sees
+// the same hierarchy as NewGitignoreSet, on both a cold and a warm cache.
+func TestIgnoreMatcherHierarchy(t *testing.T) {
+	root := t.TempDir()
+	subDir := filepath.Join(root, "src")
+	cacheDir := filepath.Join(t.TempDir(), "rg-ignore")
+
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\nbuild/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(subDir, ".ignore"), []byte("!debug.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gs := NewGitignoreSet(subDir)
+	gs.AddCommonIgnores()
+
+	for _, run := range []string{"cold", "warm"} {
+		m := newIgnoreMatcher(subDir, cacheDir)
+
+		for _, p := range []string{"error.log", "debug.log", "build", "node_modules", "main.go"} {
+			if got, want := m.Match(p, true), gs.Match(p, true); got != want {
+				t.Errorf("%s cache: Match(%q) = %v, want %v", run, p, got, want)
+			}
+		}
+	}
+
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 1 {
+		t.Errorf("expected one cache entry, got %d", len(entries))
+	}
+}
+
 func TestCommonIgnores(t *testing.T) {
 	gs := &GitignoreSet{Gitignores: make([]*Gitignore, 0)}
 	gs.AddCommonIgnores()
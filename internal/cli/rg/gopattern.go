@@ -0,0 +1,241 @@
+package rg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/search/gopattern"
+)
+
+// goFileResult is the outcome of matching one Go file.
+type goFileResult struct {
+	fr  FileResult
+	err error
+}
+
+// RunGoPattern searches the Go files under paths for code matching the
+// structural pattern opts.GoPattern (see package gopattern) instead of a
+// regular expression. Matches are reported at the line and column where
+// they start; the JSON Match field holds the whole matched code, which may
+// span lines. Files that do not parse are reported on standard error and
+// make rg exit with status 2.
+func RunGoPattern(ctx context.Context, w io.Writer, paths []string, opts Options) error {
+	pattern, err := gopattern.Compile(opts.GoPattern)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: --go-pattern: %v", err))
+	}
+
+	if err := resolveTypes(&opts); err != nil {
+		return err
+	}
+
+	listed, failed, err := gatherFiles(ctx, paths, opts)
+	if err != nil {
+		return err
+	}
+
+	files := listed[:0]
+
+	for _, file := range listed {
+		if filepath.Ext(file) == ".go" {
+			files = append(files, file)
+		}
+	}
+
+	// Like a text search, a lone file argument is searched without its name
+	opts.hideFilename = opts.NoFilename
+	if len(paths) == 1 && opts.FilesFrom == "" && !opts.WithFilename {
+		if info, err := os.Stat(paths[0]); err == nil && !info.IsDir() {
+			opts.hideFilename = true
+		}
+	}
+
+	results := matchGoFiles(ctx, pattern, files, opts)
+
+	if err := ctx.Err(); err != nil {
+		return cmderr.Wrap(cmderr.ErrTimeout, fmt.Sprintf("rg: %v", err))
+	}
+
+	result := Result{Files: make([]FileResult, 0)}
+	pr := newPrinter(w, opts)
+
+	var streamEnc *json.Encoder
+	if opts.JSONStream {
+		streamEnc = json.NewEncoder(w)
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "rg: %v\n", r.err)
+			failed = true
+
+			continue
+		}
+
+		fr := r.fr
+		if fr.Count == 0 {
+			continue
+		}
+
+		result.Files = append(result.Files, fr)
+		result.TotalFiles++
+		result.TotalMatch += fr.Count
+		result.TotalMatchCount += fr.MatchCount
+
+		switch {
+		case opts.Quiet:
+		case streamEnc != nil:
+			writeGoStream(streamEnc, fr)
+		case output.New(w, opts.OutputFormat).IsJSON():
+		case opts.FilesWithMatch:
+			printFileName(w, fr.Path, opts)
+		case isCountMode(opts):
+			printCount(w, fr, opts)
+		default:
+			var block bytes.Buffer
+
+			lineOpts := opts
+			lineOpts.LineNumber, lineOpts.ShowColumn = true, true
+
+			for _, m := range fr.Matches {
+				printLineWithColor(&block, fr.Path, m.LineNumber, m.Column, -1, m.Line, lineOpts, false, nil, "", false)
+			}
+
+			pr.flush(fr.Path, block.Bytes())
+		}
+
+		if opts.Quiet {
+			break
+		}
+	}
+
+	switch {
+	case streamEnc != nil:
+		//nolint:errchkjson // StreamSummary is a concrete type, not any
+		_ = streamEnc.Encode(StreamMessage{
+			Type: "summary",
+			Data: StreamSummary{
+				TotalFiles:      result.TotalFiles,
+				TotalMatches:    result.TotalMatch,
+				TotalMatchCount: result.TotalMatchCount,
+			},
+		})
+	case output.New(w, opts.OutputFormat).IsJSON():
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return cmderr.SilentExit(2)
+	}
+
+	return nil
+}
+
+// matchGoFiles matches pattern against every file on opts.Threads workers,
+// returning the results in the order of files.
+func matchGoFiles(ctx context.Context, pattern *gopattern.Pattern, files []string, opts Options) []goFileResult {
+	results := make([]goFileResult, len(files))
+
+	numWorkers := opts.Threads
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	sem := make(chan struct{}, numWorkers)
+
+	var wg sync.WaitGroup
+
+	for i, path := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			results[i] = matchGoFile(pattern, path, opts)
+		})
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// matchGoFile parses one file and converts the pattern's matches into
+// rg matches, one per match, at most opts.MaxCount of them.
+func matchGoFile(pattern *gopattern.Pattern, path string, opts Options) goFileResult {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return goFileResult{err: err}
+	}
+
+	found, err := pattern.MatchSource(path, src)
+	if err != nil {
+		return goFileResult{err: err}
+	}
+
+	if opts.MaxCount > 0 && len(found) > opts.MaxCount {
+		found = found[:opts.MaxCount]
+	}
+
+	lines := strings.Split(string(src), "\n")
+	fr := FileResult{Path: path, Matches: make([]Match, 0, len(found))}
+
+	for _, m := range found {
+		line := strings.TrimSuffix(lines[m.Pos.Line-1], "\r")
+
+		fr.Matches = append(fr.Matches, Match{
+			Path:       path,
+			LineNumber: m.Pos.Line,
+			Column:     m.Pos.Column,
+			ByteOffset: int64(m.Pos.Offset),
+			Line:       line,
+			Match:      m.Text,
+		})
+	}
+
+	fr.Count = len(fr.Matches)
+	fr.MatchCount = fr.Count
+
+	return goFileResult{fr: fr}
+}
+
+func writeGoStream(enc *json.Encoder, fr FileResult) {
+	//nolint:errchkjson // StreamBegin is a concrete type
+	_ = enc.Encode(StreamMessage{Type: "begin", Data: StreamBegin{Path: fr.Path}})
+
+	for _, m := range fr.Matches {
+		//nolint:errchkjson // StreamMatch is a concrete type
+		_ = enc.Encode(StreamMessage{
+			Type: "match",
+			Data: StreamMatch{
+				Path:       m.Path,
+				LineNumber: m.LineNumber,
+				Column:     m.Column,
+				Lines:      StreamLines{Text: m.Line},
+				Match:      m.Match,
+			},
+		})
+	}
+
+	//nolint:errchkjson // StreamEnd is a concrete type
+	_ = enc.Encode(StreamMessage{Type: "end", Data: StreamEnd{Path: fr.Path, Count: fr.Count, MatchCount: fr.MatchCount}})
+}
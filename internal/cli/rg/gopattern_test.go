@@ -0,0 +1,131 @@
+package rg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func setupGoTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.go":  "package a\n\nimport \"fmt\"\n\nfunc f(err error) error {\n\treturn fmt.Errorf(\"f: %w\", err)\n}\n\nfunc g() error { return fmt.Errorf(\"g\") }\n",
+		"b.go":  "package a\n\n// fmt.Errorf(\"only in a comment\")\nvar s = \"fmt.Errorf(x)\"\n",
+		"c.txt": "fmt.Errorf(\"not go\")\n",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestRunGoPattern(t *testing.T) {
+	dir := setupGoTree(t)
+
+	var buf bytes.Buffer
+
+	err := RunGoPattern(context.Background(), &buf, []string{dir}, Options{GoPattern: "fmt.Errorf($_)", NoHeading: true})
+	if err != nil {
+		t.Fatalf("RunGoPattern() = %v", err)
+	}
+
+	want := filepath.Join(dir, "a.go") + `:9:25:func g() error { return fmt.Errorf("g") }` + "\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunGoPatternModes(t *testing.T) {
+	dir := setupGoTree(t)
+	a := filepath.Join(dir, "a.go")
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"files with matches", Options{FilesWithMatch: true}, a + "\n"},
+		{"count", Options{Count: true}, a + ":2\n"},
+		{"max count", Options{Count: true, MaxCount: 1}, a + ":1\n"},
+		{"quiet", Options{Quiet: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.opts.GoPattern = "fmt.Errorf($*_)"
+
+			if err := RunGoPattern(context.Background(), &buf, []string{dir}, tt.opts); err != nil {
+				t.Fatalf("RunGoPattern() = %v", err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunGoPatternJSON(t *testing.T) {
+	dir := setupGoTree(t)
+
+	var buf bytes.Buffer
+
+	opts := Options{GoPattern: "fmt.Errorf($_, err)", OutputFormat: output.FormatJSON}
+	if err := RunGoPattern(context.Background(), &buf, []string{dir}, opts); err != nil {
+		t.Fatalf("RunGoPattern() = %v", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if result.TotalMatch != 1 || len(result.Files) != 1 {
+		t.Fatalf("result = %+v, want one match", result)
+	}
+
+	m := result.Files[0].Matches[0]
+	if m.LineNumber != 6 || m.Column != 9 || m.Match != `fmt.Errorf("f: %w", err)` {
+		t.Errorf("match = %+v", m)
+	}
+}
+
+func TestRunGoPatternErrors(t *testing.T) {
+	dir := setupGoTree(t)
+
+	err := RunGoPattern(context.Background(), &bytes.Buffer{}, []string{dir}, Options{GoPattern: "fmt.Errorf("})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("invalid pattern error = %v, want invalid input", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package a\nfunc {\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	err = RunGoPattern(context.Background(), &buf, []string{dir}, Options{GoPattern: "fmt.Errorf($_)", NoHeading: true})
+	if got := cmderr.ExitCodeFor(err); got != 2 {
+		t.Errorf("exit code with an unparsable file = %d (%v), want 2", got, err)
+	}
+
+	if !strings.Contains(buf.String(), "a.go:9:25:") {
+		t.Errorf("the other files should still be searched, got %q", buf.String())
+	}
+}
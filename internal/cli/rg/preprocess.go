@@ -0,0 +1,413 @@
+package rg
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxPreprocessBytes caps the searchable text of one preprocessed file,
+// which is held in memory, so a small decompression bomb (CWE-409) or a
+// runaway --pre command cannot exhaust it. It is a var only so tests can
+// lower it.
+var maxPreprocessBytes int64 = 256 << 20
+
+// maxPreCacheBytes bounds the --pre cache directory; the least recently
+// used entries are removed when a new one takes it over the limit. It is a
+// var only so tests can lower it.
+var maxPreCacheBytes int64 = 512 << 20
+
+var errPreprocessTooLarge = errors.New("output exceeds maximum allowed size")
+
+// cappedWriter fails once more than n bytes are written to it.
+type cappedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *cappedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > c.n {
+		return 0, errPreprocessTooLarge
+	}
+
+	c.n -= int64(len(p))
+
+	return c.w.Write(p)
+}
+
+// Preprocessor transforms a file into searchable text before it is matched,
+// e.g. to search PDFs, office documents or compressed files.
+type Preprocessor interface {
+	// Name identifies the preprocessor in cache keys and error messages.
+	Name() string
+	// Match reports whether the preprocessor handles path.
+	Match(path string) bool
+	// Process writes the searchable text of path to w.
+	Process(ctx context.Context, path string, w io.Writer) error
+}
+
+// builtinPreprocessors are in-process implementations for known types. They
+// are preferred over the --pre command so common formats never require
+// running an external program.
+var builtinPreprocessors = []Preprocessor{
+	gzipPreprocessor{},
+	officePreprocessor{name: "docx", ext: ".docx", parts: []string{"word/document.xml"}, paragraph: "p"},
+	officePreprocessor{name: "pptx", ext: ".pptx", parts: []string{"ppt/slides/slide*.xml"}, paragraph: "p"},
+	officePreprocessor{name: "odt", ext: ".odt", parts: []string{"content.xml"}, paragraph: "p"},
+}
+
+// DefaultPreCacheDir returns <os.UserCacheDir>/omni/rg-pre.
+func DefaultPreCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "omni", "rg-pre"), nil
+}
+
+// preprocessorFor selects the preprocessor for path, or nil to search the
+// file as is. Custom preprocessors win over built-ins, which win over --pre.
+func preprocessorFor(path string, opts Options) Preprocessor {
+	if opts.Pre == "" && !opts.SearchZip && len(opts.Preprocessors) == 0 {
+		return nil
+	}
+
+	if len(opts.PreGlob) > 0 && !matchesGlob(path, opts.PreGlob) {
+		return nil
+	}
+
+	for _, p := range opts.Preprocessors {
+		if p.Match(path) {
+			return p
+		}
+	}
+
+	if opts.Pre != "" || opts.SearchZip {
+		for _, p := range builtinPreprocessors {
+			if p.Match(path) {
+				return p
+			}
+		}
+	}
+
+	if opts.Pre == "" {
+		return nil
+	}
+
+	var p Preprocessor = commandPreprocessor{command: opts.Pre}
+	if opts.PreCacheDir != "" {
+		p = cachedPreprocessor{Preprocessor: p, dir: opts.PreCacheDir}
+	}
+
+	return p
+}
+
+// openForSearch opens path for searching, running it through its
+// preprocessor first when one applies.
+func openForSearch(ctx context.Context, path string, opts Options) (io.ReadCloser, error) {
+	p := preprocessorFor(path, opts)
+	if p == nil {
+		return os.Open(path)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Process(ctx, path, &cappedWriter{w: &buf, n: maxPreprocessBytes}); err != nil {
+		return nil, fmt.Errorf("preprocessor %s: %w", p.Name(), err)
+	}
+
+	return io.NopCloser(&buf), nil
+}
+
+// commandPreprocessor runs an external command with the file path as its only
+// argument (and the file on stdin) and searches its stdout, like ripgrep --pre.
+type commandPreprocessor struct {
+	command string
+}
+
+func (p commandPreprocessor) Name() string { return p.command }
+
+func (p commandPreprocessor) Match(string) bool { return true }
+
+func (p commandPreprocessor) Process(ctx context.Context, path string, w io.Writer) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = in.Close() }()
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.command, path)
+	cmd.Stdin = in
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// cachedPreprocessor stores the output of an expensive preprocessor keyed by
+// the file's path, size and modification time. The cache is an LRU bounded
+// by maxPreCacheBytes: a hit refreshes an entry's modification time, and
+// writing an entry removes the oldest ones beyond the bound.
+type cachedPreprocessor struct {
+	Preprocessor
+
+	dir string
+}
+
+func (c cachedPreprocessor) Process(ctx context.Context, path string, w io.Writer) error {
+	key, err := preCacheKey(c.Name(), path)
+	if err != nil {
+		return c.Preprocessor.Process(ctx, path, w)
+	}
+
+	cachePath := filepath.Join(c.dir, key)
+
+	if data, err := os.ReadFile(cachePath); err == nil {
+		now := time.Now()
+		_ = os.Chtimes(cachePath, now, now)
+
+		_, err = w.Write(data)
+
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := c.Preprocessor.Process(ctx, path, &cappedWriter{w: &buf, n: maxPreprocessBytes}); err != nil {
+		return err
+	}
+
+	// A failing cache write must never fail the search
+	if writeCacheFile(cachePath, buf.Bytes()) == nil {
+		pruneCache(c.dir, maxPreCacheBytes)
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+func preCacheKey(name, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%d\n%d\n", name, abs, info.Size(), info.ModTime().UnixNano())
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeCacheFile writes data atomically so concurrent searches never read a
+// partially written entry.
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pre-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// pruneCache removes the least recently used entries of dir until the
+// entries take at most limit bytes. Errors are ignored: another search may
+// be pruning the same directory.
+func pruneCache(dir string, limit int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var (
+		infos []os.FileInfo
+		total int64
+	)
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".pre-") {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, info)
+		total += info.Size()
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().Before(infos[j].ModTime()) })
+
+	for _, info := range infos {
+		if total <= limit {
+			return
+		}
+
+		if os.Remove(filepath.Join(dir, info.Name())) == nil {
+			total -= info.Size()
+		}
+	}
+}
+
+// gzipPreprocessor decompresses .gz files.
+type gzipPreprocessor struct{}
+
+func (gzipPreprocessor) Name() string { return "gzip" }
+
+func (gzipPreprocessor) Match(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".gz")
+}
+
+func (gzipPreprocessor) Process(_ context.Context, path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = zr.Close() }()
+
+	_, err = io.Copy(w, zr)
+
+	return err
+}
+
+// officePreprocessor extracts the text of zipped XML documents (OOXML and
+// OpenDocument), writing one line per paragraph.
+type officePreprocessor struct {
+	name      string
+	ext       string
+	parts     []string // Glob patterns of XML parts holding the text
+	paragraph string   // Local name of the paragraph element
+}
+
+func (p officePreprocessor) Name() string { return p.name }
+
+func (p officePreprocessor) Match(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), p.ext)
+}
+
+func (p officePreprocessor) Process(_ context.Context, name string, w io.Writer) error {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = zr.Close() }()
+
+	var files []*zip.File
+
+	for _, f := range zr.File {
+		for _, pattern := range p.parts {
+			if ok, _ := path.Match(pattern, f.Name); ok {
+				files = append(files, f)
+				break
+			}
+		}
+	}
+
+	// slide2.xml must come before slide10.xml
+	sort.Slice(files, func(i, j int) bool {
+		if len(files[i].Name) != len(files[j].Name) {
+			return len(files[i].Name) < len(files[j].Name)
+		}
+
+		return files[i].Name < files[j].Name
+	})
+
+	for _, f := range files {
+		if err := p.extract(f, w); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p officePreprocessor) extract(f *zip.File, w io.Writer) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	dec := xml.NewDecoder(rc)
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			if _, err := w.Write(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if t.Name.Local == p.paragraph {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
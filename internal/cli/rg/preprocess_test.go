@@ -0,0 +1,217 @@
+package rg
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_SearchZip(t *testing.T) {
+	dir := t.TempDir()
+
+	var gz bytes.Buffer
+
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte("first\ncompressed needle\n"))
+	_ = zw.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, "log.gz"), gz.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc bytes.Buffer
+
+	dw := zip.NewWriter(&doc)
+	f, _ := dw.Create("word/document.xml")
+	_, _ = f.Write([]byte(`<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>intro</w:t></w:r></w:p><w:p><w:r><w:t>docx </w:t></w:r><w:r><w:t>needle</w:t></w:r></w:p></w:body></w:document>`))
+	_ = dw.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, "report.docx"), doc.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	if err := Run(context.Background(), &buf, "needle", []string{dir}, Options{Threads: 1, LineNumber: true, NoHeading: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("compressed files searched without -z: %q", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := Run(context.Background(), &buf, "needle", []string{dir}, Options{Threads: 2, LineNumber: true, NoHeading: true, SearchZip: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "log.gz:2:compressed needle") || !strings.Contains(out, "report.docx:2:docx needle") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRun_PreCommandCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := filepath.Join(dir, "upper.sh")
+
+	writeTree(t, dir, map[string]string{
+		"data/a.txt": "hello world\n",
+		"data/b.md":  "hello markdown\n",
+	})
+
+	body := "#!/bin/sh\necho x >> " + calls + "\ntr a-z A-Z < \"$1\"\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{
+		Threads:     1,
+		NoHeading:   true,
+		Pre:         script,
+		PreGlob:     []string{"*.txt"},
+		PreCacheDir: filepath.Join(dir, "cache"),
+	}
+
+	for range 2 {
+		var buf strings.Builder
+		if err := Run(context.Background(), &buf, "HELLO", []string{filepath.Join(dir, "data")}, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(buf.String(), "a.txt:HELLO WORLD") || strings.Contains(buf.String(), "b.md") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	}
+
+	data, _ := os.ReadFile(calls)
+	if n := strings.Count(string(data), "x"); n != 1 {
+		t.Errorf("preprocessor ran %d times, want 1 (cached)", n)
+	}
+}
+
+func TestPreprocessorFor(t *testing.T) {
+	custom := gzipPreprocessor{}
+
+	tests := []struct {
+		name string
+		path string
+		opts Options
+		want string // preprocessor name, empty for none
+	}{
+		{"disabled", "a.gz", Options{}, ""},
+		{"builtin via -z", "a.gz", Options{SearchZip: true}, "gzip"},
+		{"builtin preferred over --pre", "a.docx", Options{Pre: "conv"}, "docx"},
+		{"command", "a.pdf", Options{Pre: "conv"}, "conv"},
+		{"no command for unknown with -z", "a.pdf", Options{SearchZip: true}, ""},
+		{"glob excludes", "a.pdf", Options{Pre: "conv", PreGlob: []string{"*.txt"}}, ""},
+		{"custom", "x.gz", Options{Preprocessors: []Preprocessor{custom}}, "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if p := preprocessorFor(tt.path, tt.opts); p != nil {
+				got = p.Name()
+			}
+
+			if got != tt.want {
+				t.Errorf("preprocessorFor(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenForSearchCapsOutput(t *testing.T) {
+	old := maxPreprocessBytes
+	maxPreprocessBytes = 1 << 10
+
+	t.Cleanup(func() { maxPreprocessBytes = old })
+
+	var gz bytes.Buffer
+
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write(make([]byte, 1<<20))
+	_ = zw.Close()
+
+	path := filepath.Join(t.TempDir(), "bomb.gz")
+	if err := os.WriteFile(path, gz.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := openForSearch(context.Background(), path, Options{SearchZip: true}); !errors.Is(err, errPreprocessTooLarge) {
+		t.Errorf("openForSearch() error = %v, want %v", err, errPreprocessTooLarge)
+	}
+}
+
+// sizedPreprocessor writes size bytes for any file.
+type sizedPreprocessor struct{ size int }
+
+func (sizedPreprocessor) Name() string { return "sized" }
+
+func (sizedPreprocessor) Match(string) bool { return true }
+
+func (p sizedPreprocessor) Process(_ context.Context, _ string, w io.Writer) error {
+	_, err := w.Write(make([]byte, p.size))
+	return err
+}
+
+func TestCachedPreprocessorEvictsLeastRecentlyUsed(t *testing.T) {
+	old := maxPreCacheBytes
+	maxPreCacheBytes = 250
+
+	t.Cleanup(func() { maxPreCacheBytes = old })
+
+	dir := t.TempDir()
+	cache := cachedPreprocessor{Preprocessor: sizedPreprocessor{size: 100}, dir: filepath.Join(dir, "cache")}
+
+	process := func(name string) string {
+		t.Helper()
+
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := cache.Process(context.Background(), path, io.Discard); err != nil {
+			t.Fatal(err)
+		}
+
+		key, _ := preCacheKey(cache.Name(), path)
+
+		return filepath.Join(cache.dir, key)
+	}
+
+	a := process("a")
+	b := process("b")
+
+	// Make a the most recently used entry, then add a third
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(a, past, past)
+	_ = os.Chtimes(b, past.Add(-time.Minute), past.Add(-time.Minute))
+
+	process("a")
+	c := process("c")
+
+	for path, want := range map[string]bool{a: true, b: false, c: true} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s cached = %v, want %v", filepath.Base(path), err == nil, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package rg
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// printer writes the text output of each searched file as one block, so
+// blocks from parallel workers never interleave. In heading mode a block
+// starts with the file path and blocks are separated by a blank line; in
+// no-heading mode with context, blocks are separated by the context
+// separator, as in ripgrep.
+type printer struct {
+	w       io.Writer
+	opts    Options
+	mu      sync.Mutex
+	printed bool
+}
+
+func newPrinter(w io.Writer, opts Options) *printer {
+	return &printer{w: w, opts: opts}
+}
+
+// flush writes the output block of path; empty blocks are skipped.
+func (p *printer) flush(path string, block []byte) {
+	if len(block) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if lineMode(p.opts) {
+		switch {
+		case !p.printed:
+		case headingMode(p.opts):
+			_, _ = fmt.Fprintln(p.w)
+		case hasContext(p.opts):
+			printContextSeparator(p.w, p.opts)
+		}
+
+		if headingMode(p.opts) {
+			useColor, scheme := colorSettings(p.opts)
+			_, _ = fmt.Fprintln(p.w, FormatPath(path, scheme, useColor))
+		}
+	}
+
+	p.printed = true
+	_, _ = p.w.Write(block)
+}
+
+// lineMode reports whether matching lines are printed, as opposed to
+// counts, file names or JSON.
+func lineMode(opts Options) bool {
+	return !isCountMode(opts) && !opts.FilesWithMatch && !opts.Quiet && !opts.JSONStream &&
+		opts.OutputFormat != output.FormatJSON
+}
+
+// headingMode reports whether matches are grouped under file name headings.
+func headingMode(opts Options) bool {
+	return !opts.NoHeading && !opts.hideFilename
+}
+
+func hasContext(opts Options) bool {
+	return opts.Context > 0 || opts.Before > 0 || opts.After > 0
+}
+
+// colorSettings returns whether to color output and the scheme to use,
+// with --colors applied.
+func colorSettings(opts Options) (bool, ColorScheme) {
+	scheme := DefaultScheme()
+	for _, spec := range opts.Colors {
+		_ = ApplyColorSpec(&scheme, spec)
+	}
+
+	return ShouldUseColor(ParseColorMode(opts.Color)), scheme
+}
+
+// fieldSeparator returns the separator between the path, line number,
+// column and text of a matching or context line.
+func fieldSeparator(opts Options, isContext bool) string {
+	if isContext {
+		if opts.FieldContextSeparator != "" {
+			return opts.FieldContextSeparator
+		}
+
+		return "-"
+	}
+
+	if opts.FieldMatchSeparator != "" {
+		return opts.FieldMatchSeparator
+	}
+
+	return ":"
+}
+
+// UnescapeSeparator interprets the escapes ripgrep accepts in separator
+// arguments (\t, \n, \x7F, \\ and so on); invalid escapes are kept as
+// written.
+func UnescapeSeparator(s string) string {
+	if u, err := strconv.Unquote(`"` + s + `"`); err == nil {
+		return u
+	}
+
+	return s
+}
@@ -0,0 +1,155 @@
+package rg
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// printerTree holds two files so output order is fixed with Threads: 1.
+func printerTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("x\nfoo 1\ny\nz\nw\nfoo 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("foo 3\nq\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestOutputLayout(t *testing.T) {
+	dir := printerTree(t)
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+
+	tests := []struct {
+		name  string
+		paths []string
+		opts  Options
+		want  string
+	}{
+		{
+			name:  "heading",
+			paths: []string{dir},
+			opts:  Options{LineNumber: true},
+			want:  a + "\n2:foo 1\n6:foo 2\n\n" + b + "\n1:foo 3\n",
+		},
+		{
+			name:  "heading with context",
+			paths: []string{dir},
+			opts:  Options{LineNumber: true, Context: 1, ContextSeparator: "~~"},
+			want:  a + "\n1-x\n2:foo 1\n3-y\n~~\n5-w\n6:foo 2\n\n" + b + "\n1:foo 3\n2-q\n",
+		},
+		{
+			name:  "no heading with context",
+			paths: []string{dir},
+			opts:  Options{LineNumber: true, NoHeading: true, After: 1},
+			want:  a + ":2:foo 1\n" + a + "-3-y\n--\n" + a + ":6:foo 2\n--\n" + b + ":1:foo 3\n" + b + "-2-q\n",
+		},
+		{
+			name:  "no context separator",
+			paths: []string{dir},
+			opts:  Options{NoHeading: true, Before: 1, NoContextSeparator: true},
+			want:  a + "-x\n" + a + ":foo 1\n" + a + "-w\n" + a + ":foo 2\n" + b + ":foo 3\n",
+		},
+		{
+			name:  "field separators",
+			paths: []string{dir},
+			opts:  Options{LineNumber: true, NoHeading: true, After: 1, FieldMatchSeparator: "\t", FieldContextSeparator: "|", MaxCount: 1},
+			want:  a + "\t2\tfoo 1\n" + a + "|3|y\n--\n" + b + "\t1\tfoo 3\n" + b + "|2|q\n",
+		},
+		{
+			name:  "single file hides name",
+			paths: []string{a},
+			opts:  Options{LineNumber: true, NoHeading: true},
+			want:  "2:foo 1\n6:foo 2\n",
+		},
+		{
+			name:  "single file with filename",
+			paths: []string{a},
+			opts:  Options{NoHeading: true, WithFilename: true},
+			want:  a + ":foo 1\n" + a + ":foo 2\n",
+		},
+		{
+			name:  "single file count",
+			paths: []string{a},
+			opts:  Options{Count: true},
+			want:  "2\n",
+		},
+		{
+			name:  "no filename",
+			paths: []string{dir},
+			opts:  Options{NoFilename: true},
+			want:  "foo 1\nfoo 2\nfoo 3\n",
+		},
+		{
+			name:  "files with matches ignores heading",
+			paths: []string{dir},
+			opts:  Options{FilesWithMatch: true},
+			want:  a + "\n" + b + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.opts.Threads = 1
+			tt.opts.Color = "never"
+
+			if err := Run(context.Background(), &buf, "foo", tt.paths, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOutputLayoutParallel checks that parallel workers print context and
+// keep each file's lines together under its heading.
+func TestOutputLayoutParallel(t *testing.T) {
+	dir := printerTree(t)
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+
+	var buf bytes.Buffer
+
+	opts := Options{Threads: 4, LineNumber: true, After: 1, Color: "never"}
+	if err := Run(context.Background(), &buf, "foo", []string{dir}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	blockA := a + "\n2:foo 1\n3-y\n--\n6:foo 2\n"
+	blockB := b + "\n1:foo 3\n2-q\n"
+
+	got := buf.String()
+	if got != blockA+"\n"+blockB && got != blockB+"\n"+blockA {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+}
+
+func TestUnescapeSeparator(t *testing.T) {
+	tests := map[string]string{
+		"--":    "--",
+		`\t`:    "\t",
+		`\x1f`:  "\x1f",
+		`a\\b`:  `a\b`,
+		`\q`:    `\q`,
+		`say"x`: `say"x`,
+	}
+
+	for in, want := range tests {
+		if got := UnescapeSeparator(in); got != want {
+			t.Errorf("UnescapeSeparator(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,19 +2,24 @@ package rg
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/search/ahocorasick"
+	pkggrep "github.com/inovacc/omni/pkg/search/grep"
 	pkgrg "github.com/inovacc/omni/pkg/search/rg"
 )
 
@@ -24,26 +29,39 @@ type Options struct {
 	SmartCase      bool          // -S: smart case (case insensitive if pattern is lowercase)
 	WordRegexp     bool          // -w: match whole words only
 	LineNumber     bool          // -n: show line numbers (default true)
-	Count          bool          // -c: only show count of matches
+	Count          bool          // -c: only show count of matching lines
+	CountMatches   bool          // --count-matches: only show count of individual matches
 	FilesWithMatch bool          // -l: only show file names with matches
+	Null           bool          // -0/--null: end paths printed by -l and --files with NUL
 	InvertMatch    bool          // -v: show non-matching lines
 	Context        int           // -C: lines of context (before and after)
 	Before         int           // -B: lines before match
 	After          int           // -A: lines after match
 	Types          []string      // -t: file types to include
 	TypesNot       []string      // -T: file types to exclude
+	TypeAdd        []string      // --type-add: NAME:GLOB[,GLOB...] or NAME:include:TYPE[,TYPE...]
+	TypeClear      []string      // --type-clear: drop the globs of a type before TypeAdd applies
+	ConfigTypes    []string      // type definitions from the omni config, applied before TypeClear
 	Glob           []string      // -g: glob patterns to include
 	Hidden         bool          // --hidden: search hidden files
 	NoIgnore       bool          // --no-ignore: don't respect gitignore
+	IgnoreCacheDir string        // cache for compiled ignore rules (empty = no cache)
 	MaxCount       int           // -m: max matches per file
 	MaxDepth       int           // --max-depth: max directory depth
+	MaxFilesize    int64         // --max-filesize: skip files larger than this many bytes (0 = no limit)
 	FollowSymlinks bool          // -L: follow symlinks
 	OutputFormat   output.Format // output format
 	JSONStream     bool          // --json-stream: streaming NDJSON output
 	NoHeading      bool          // --no-heading: no file name headings
+	NoFilename     bool          // -I/--no-filename: never print file names
+	WithFilename   bool          // --with-filename: print file names even for a single file
 	OnlyMatching   bool          // -o: only show matching part
 	Quiet          bool          // -q: quiet mode, exit on first match
 	Fixed          bool          // -F: treat pattern as literal string
+	Patterns       []string      // -e: patterns searched in addition to PATTERN
+	PatternFiles   []string      // -f: files with one pattern per line ("-" for stdin)
+	FilesFrom      string        // --files-from: file listing the files to search ("-" for stdin)
+	GoPattern      string        // --go-pattern: structural Go pattern searched instead of a regex
 	Threads        int           // --threads: number of worker threads (0 = auto)
 
 	// New options for ripgrep compatibility
@@ -56,6 +74,23 @@ type Options struct {
 	ByteOffset bool     // -b/--byte-offset: show byte offset (not implemented)
 	Stats      bool     // --stats: show search statistics
 	Passthru   bool     // --passthru: show all lines, highlighting matches
+
+	// Separators (empty selects the ripgrep default)
+	ContextSeparator      string // --context-separator: between non-adjacent context groups (default "--")
+	NoContextSeparator    bool   // --no-context-separator: print nothing between context groups
+	FieldMatchSeparator   string // --field-match-separator: between fields of matching lines (default ":")
+	FieldContextSeparator string // --field-context-separator: between fields of context lines (default "-")
+
+	// Preprocessing
+	Pre           string         // --pre: command whose output is searched instead of the file
+	PreGlob       []string       // --pre-glob: only preprocess files matching these globs
+	PreCacheDir   string         // cache for --pre output keyed by mtime (empty = no cache)
+	SearchZip     bool           // -z/--search-zip: search compressed files and documents in-process
+	Preprocessors []Preprocessor // custom preprocessors, checked before the built-ins
+
+	types        *pkgrg.TypeSet       // file types resolved from TypeAdd and TypeClear
+	hideFilename bool                 // set by Run for -I or a single file argument
+	fixedSet     *ahocorasick.Matcher // selects lines for sets of fixed strings
 }
 
 // Match represents a single match result
@@ -70,16 +105,18 @@ type Match struct {
 
 // FileResult represents matches in a single file
 type FileResult struct {
-	Path    string  `json:"path"`
-	Matches []Match `json:"matches"`
-	Count   int     `json:"count"`
+	Path       string  `json:"path"`
+	Matches    []Match `json:"matches"`
+	Count      int     `json:"count"`       // Matching lines
+	MatchCount int     `json:"match_count"` // Individual matches
 }
 
 // Result represents the complete search result
 type Result struct {
-	Files      []FileResult `json:"files"`
-	TotalFiles int          `json:"total_files"`
-	TotalMatch int          `json:"total_matches"`
+	Files           []FileResult `json:"files"`
+	TotalFiles      int          `json:"total_files"`
+	TotalMatch      int          `json:"total_matches"`     // Matching lines
+	TotalMatchCount int          `json:"total_match_count"` // Individual matches
 }
 
 // resultInternal is used during parallel search with mutex protection
@@ -124,60 +161,138 @@ type StreamContext struct {
 // StreamEnd is sent at the end of searching a file
 type StreamEnd struct {
 	Path       string `json:"path"`
-	MatchCount int    `json:"match_count"`
+	Count      int    `json:"count"`       // Matching lines
+	MatchCount int    `json:"match_count"` // Individual matches
 }
 
 // StreamSummary is sent at the end of all searching
 type StreamSummary struct {
-	TotalFiles   int `json:"total_files"`
-	TotalMatches int `json:"total_matches"`
+	TotalFiles      int `json:"total_files"`
+	TotalMatches    int `json:"total_matches"`
+	TotalMatchCount int `json:"total_match_count"`
 }
 
 // fileTypeExtensions references the pkg-level map
 var fileTypeExtensions = pkgrg.FileTypeExtensions
 
-// Run executes the rg command
+// Run executes the rg command. pattern may be empty when the patterns
+// come from opts.Patterns or opts.PatternFiles.
 func Run(ctx context.Context, w io.Writer, pattern string, paths []string, opts Options) error {
-	if pattern == "" {
+	patterns := opts.Patterns
+	if pattern != "" {
+		patterns = append([]string{pattern}, patterns...)
+	}
+
+	if len(patterns) == 0 && len(opts.PatternFiles) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "rg: no pattern provided")
 	}
 
-	if len(paths) == 0 {
+	if opts.FilesFrom == "-" && slices.Contains(opts.PatternFiles, "-") {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "rg: --files-from and -f cannot both read standard input")
+	}
+
+	if len(opts.PatternFiles) > 0 {
+		fromFiles, err := pkggrep.ReadPatternFiles(opts.PatternFiles, os.Stdin)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("rg: %v", err))
+			}
+
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("rg: %v", err))
+		}
+
+		patterns = append(patterns, fromFiles...)
+	}
+
+	// Several patterns are searched as one; pattern now stands for all of
+	// them in the smart-case and highlighting checks
+	pattern = strings.Join(patterns, "\n")
+
+	var listed []string
+
+	if opts.FilesFrom != "" {
+		var err error
+
+		if listed, err = readFilesFrom(opts.FilesFrom); err != nil {
+			return err
+		}
+	} else if len(paths) == 0 {
 		paths = []string{"."}
 	}
 
+	if err := resolveTypes(&opts); err != nil {
+		return err
+	}
+
 	// For literal/fixed patterns without regex features, we can use a fast path
-	useLiteralSearch := opts.Fixed && !opts.WordRegexp && !opts.InvertMatch
+	useLiteralSearch := opts.Fixed && !opts.WordRegexp && !opts.InvertMatch && len(patterns) == 1
 
 	// Build regex pattern (needed even for literal if we need to highlight matches)
-	regexPattern := pattern
-	if opts.Fixed {
-		regexPattern = regexp.QuoteMeta(pattern)
+	exprs := make([]string, len(patterns))
+	for i, p := range patterns {
+		exprs[i] = p
+		if opts.Fixed {
+			exprs[i] = regexp.QuoteMeta(p)
+		}
+
+		if len(patterns) > 1 {
+			exprs[i] = "(?:" + exprs[i] + ")"
+		}
+	}
+
+	regexPattern := strings.Join(exprs, "|")
+	if len(patterns) == 0 {
+		// An empty pattern file matches nothing
+		regexPattern = `[^\x00-\x{10FFFF}]`
 	}
 
 	if opts.WordRegexp {
-		regexPattern = `\b` + regexPattern + `\b`
+		regexPattern = `\b(?:` + regexPattern + `)\b`
 	}
 
+	caseInsensitive := opts.IgnoreCase || (opts.SmartCase && pattern == strings.ToLower(pattern))
+
 	flags := ""
-	if opts.IgnoreCase {
-		flags = "(?i)"
-	} else if opts.SmartCase && pattern == strings.ToLower(pattern) {
+	if caseInsensitive {
 		flags = "(?i)"
 	}
 
+	// In multiline mode ^ and $ still anchor at line boundaries
+	if opts.Multiline {
+		flags += "(?m)"
+	}
+
 	re, err := regexp.Compile(flags + regexPattern)
 	if err != nil {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("rg: invalid pattern: %v", err))
 	}
 
+	// Sets of fixed strings select lines through one Aho-Corasick pass
+	if opts.Fixed && !opts.WordRegexp && len(patterns) > 1 {
+		var acOpts []ahocorasick.Option
+		if caseInsensitive {
+			acOpts = append(acOpts, ahocorasick.WithIgnoreCase())
+		}
+
+		opts.fixedSet = ahocorasick.New(patterns, acOpts...)
+	}
+
 	// Prepare literal pattern for fast search
 	literalPattern := pattern
-	if opts.IgnoreCase || (opts.SmartCase && pattern == strings.ToLower(pattern)) {
+	if caseInsensitive {
 		literalPattern = strings.ToLower(pattern)
 	}
 
+	// Like ripgrep, a lone file argument is searched without printing its name
+	opts.hideFilename = opts.NoFilename
+	if len(paths) == 1 && opts.FilesFrom == "" && !opts.WithFilename {
+		if info, err := os.Stat(paths[0]); err == nil && !info.IsDir() {
+			opts.hideFilename = true
+		}
+	}
+
 	jsonMode := output.New(w, opts.OutputFormat).IsJSON()
+	pr := newPrinter(w, opts)
 
 	result := &resultInternal{
 		Result: Result{
@@ -212,7 +327,7 @@ func Run(ctx context.Context, w io.Writer, pattern string, paths []string, opts
 		}
 
 		// Load gitignore patterns for this specific path
-		var gitignore *GitignoreSet
+		var gitignore *IgnoreMatcher
 
 		if !opts.NoIgnore {
 			searchDir := path
@@ -220,18 +335,17 @@ func Run(ctx context.Context, w io.Writer, pattern string, paths []string, opts
 				searchDir = filepath.Dir(path)
 			}
 
-			gitignore = NewGitignoreSet(searchDir)
-			gitignore.AddCommonIgnores()
+			gitignore = newIgnoreMatcher(searchDir, opts.IgnoreCacheDir)
 		}
 
 		if info.IsDir() {
 			if numWorkers > 1 {
-				err = searchDirParallel(ctx, w, path, re, pattern, literalPattern, useLiteralSearch, opts, gitignore, result, numWorkers, streamEnc, &streamMu)
+				err = searchDirParallel(ctx, pr, path, re, pattern, literalPattern, useLiteralSearch, opts, gitignore, result, numWorkers, streamEnc, &streamMu)
 			} else {
-				err = searchDir(ctx, w, path, re, pattern, literalPattern, useLiteralSearch, opts, gitignore, result, 0, streamEnc, &streamMu)
+				err = searchDir(ctx, pr, path, re, pattern, literalPattern, useLiteralSearch, opts, gitignore, result, 0, streamEnc, &streamMu)
 			}
 		} else {
-			err = searchFile(ctx, w, path, re, pattern, literalPattern, useLiteralSearch, opts, result, streamEnc, &streamMu)
+			err = printFile(ctx, pr, path, re, pattern, literalPattern, useLiteralSearch, opts, result, streamEnc, &streamMu)
 		}
 
 		if err != nil {
@@ -245,6 +359,26 @@ func Run(ctx context.Context, w io.Writer, pattern string, paths []string, opts
 		}
 	}
 
+	// Files from --files-from are searched as listed, without walking
+	// directories or applying ignore rules; -t and -g still apply
+	if len(listed) > 0 && !(opts.Quiet && result.TotalMatch > 0) {
+		files := filterListed(w, listed, opts)
+
+		if numWorkers > 1 {
+			_ = searchFilesParallel(ctx, pr, files, re, pattern, literalPattern, useLiteralSearch, opts, result, numWorkers, streamEnc, &streamMu)
+		} else {
+			for _, path := range files {
+				if err := printFile(ctx, pr, path, re, pattern, literalPattern, useLiteralSearch, opts, result, streamEnc, &streamMu); err != nil && !opts.Quiet {
+					_, _ = fmt.Fprintf(w, "rg: %v\n", err)
+				}
+
+				if opts.Quiet && result.TotalMatch > 0 {
+					break
+				}
+			}
+		}
+	}
+
 	// Output results
 	if opts.JSONStream {
 		// Write summary
@@ -253,8 +387,9 @@ func Run(ctx context.Context, w io.Writer, pattern string, paths []string, opts
 		_ = streamEnc.Encode(StreamMessage{
 			Type: "summary",
 			Data: StreamSummary{
-				TotalFiles:   result.TotalFiles,
-				TotalMatches: result.TotalMatch,
+				TotalFiles:      result.TotalFiles,
+				TotalMatches:    result.TotalMatch,
+				TotalMatchCount: result.TotalMatchCount,
 			},
 		})
 
@@ -269,10 +404,35 @@ func Run(ctx context.Context, w io.Writer, pattern string, paths []string, opts
 	return nil
 }
 
-// searchDirParallel performs parallel directory traversal and search
-func searchDirParallel(ctx context.Context, w io.Writer, dir string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, gitignore *GitignoreSet, result *resultInternal, numWorkers int, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
-	jsonMode := output.New(w, opts.OutputFormat).IsJSON()
+// filterListed keeps the regular files of a --files-from list that pass the
+// type and glob filters. Directories are skipped and missing files reported.
+func filterListed(w io.Writer, listed []string, opts Options) []string {
+	files := make([]string, 0, len(listed))
 
+	for _, path := range listed {
+		info, err := os.Stat(path)
+		if err != nil {
+			if !opts.Quiet {
+				_, _ = fmt.Fprintf(w, "rg: %s: %v\n", path, err)
+			}
+
+			continue
+		}
+
+		if info.IsDir() || !fileTypeMatches(path, opts) || !matchesGlob(path, opts.Glob) || tooLarge(info.Size(), opts) {
+			continue
+		}
+
+		files = append(files, path)
+	}
+
+	return files
+}
+
+// searchDirParallel performs parallel directory traversal and search. In
+// text mode each worker renders a whole file, context included, and the
+// collector hands the rendered block to the printer.
+func searchDirParallel(ctx context.Context, pr *printer, dir string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, gitignore *IgnoreMatcher, result *resultInternal, numWorkers int, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
 	// Collect all files to search
 	var files []string
 
@@ -281,6 +441,15 @@ func searchDirParallel(ctx context.Context, w io.Writer, dir string, re *regexp.
 		return err
 	}
 
+	return searchFilesParallel(ctx, pr, files, re, pattern, literalPattern, useLiteral, opts, result, numWorkers, streamEnc, streamMu)
+}
+
+// searchFilesParallel searches a fixed list of files with numWorkers workers.
+func searchFilesParallel(ctx context.Context, pr *printer, files []string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, result *resultInternal, numWorkers int, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
+	w := pr.w
+	jsonMode := output.New(w, opts.OutputFormat).IsJSON()
+	textMode := !jsonMode && !opts.JSONStream
+
 	if len(files) == 0 {
 		return nil
 	}
@@ -288,6 +457,7 @@ func searchDirParallel(ctx context.Context, w io.Writer, dir string, re *regexp.
 	// Create work channel and result channel
 	fileCh := make(chan string, numWorkers*2)
 	resultCh := make(chan FileResult, numWorkers*2)
+	blockCh := make(chan renderedFile, numWorkers*2)
 	errCh := make(chan error, numWorkers)
 
 	// Start workers
@@ -302,7 +472,25 @@ func searchDirParallel(ctx context.Context, w io.Writer, dir string, re *regexp.
 				default:
 				}
 
-				fr, err := searchFileSingle(path, re, pattern, literalPattern, useLiteral, opts)
+				if textMode {
+					var buf bytes.Buffer
+
+					err := searchFile(ctx, &buf, path, re, pattern, literalPattern, useLiteral, opts, result, nil, nil)
+					if err != nil {
+						select {
+						case errCh <- fmt.Errorf("%s: %w", path, err):
+						default:
+						}
+					}
+
+					if buf.Len() > 0 {
+						blockCh <- renderedFile{path: path, block: buf.Bytes()}
+					}
+
+					continue
+				}
+
+				fr, err := searchFileSingle(ctx, path, re, pattern, literalPattern, useLiteral, opts)
 				if err != nil {
 					select {
 					case errCh <- fmt.Errorf("%s: %w", path, err):
@@ -323,18 +511,22 @@ func searchDirParallel(ctx context.Context, w io.Writer, dir string, re *regexp.
 	// Start result collector goroutine
 	var collectorWg sync.WaitGroup
 
+	collectorWg.Go(func() {
+		for rf := range blockCh {
+			pr.flush(rf.path, rf.block)
+		}
+	})
+
 	collectorWg.Go(func() {
 		for fr := range resultCh {
 			result.mu.Lock()
 			result.Files = append(result.Files, fr)
 			result.TotalFiles++
 			result.TotalMatch += fr.Count
+			result.TotalMatchCount += fr.MatchCount
 			result.mu.Unlock()
 
-			// Output results
-			if !jsonMode && !opts.JSONStream {
-				outputFileResult(w, fr, opts, re, pattern, useLiteral)
-			} else if opts.JSONStream && streamEnc != nil {
+			if opts.JSONStream && streamEnc != nil {
 				streamMu.Lock()
 
 				_ = streamEnc.Encode(StreamMessage{Type: "begin", Data: StreamBegin{Path: fr.Path}})
@@ -352,7 +544,7 @@ func searchDirParallel(ctx context.Context, w io.Writer, dir string, re *regexp.
 					})
 				}
 
-				_ = streamEnc.Encode(StreamMessage{Type: "end", Data: StreamEnd{Path: fr.Path, MatchCount: fr.Count}})
+				_ = streamEnc.Encode(StreamMessage{Type: "end", Data: StreamEnd{Path: fr.Path, Count: fr.Count, MatchCount: fr.MatchCount}})
 
 				streamMu.Unlock()
 			}
@@ -379,6 +571,7 @@ feed:
 	// Wait for workers to finish
 	wg.Wait()
 	close(resultCh)
+	close(blockCh)
 
 	// Wait for collector to finish
 	collectorWg.Wait()
@@ -395,7 +588,7 @@ feed:
 }
 
 // collectFiles recursively collects all searchable files
-func collectFiles(ctx context.Context, dir string, opts Options, gitignore *GitignoreSet, files *[]string, depth int) error {
+func collectFiles(ctx context.Context, dir string, opts Options, gitignore *IgnoreMatcher, files *[]string, depth int) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -432,7 +625,7 @@ func collectFiles(ctx context.Context, dir string, opts Options, gitignore *Giti
 		}
 
 		// Check file type filters
-		if !matchesFileType(path, opts.Types, opts.TypesNot) {
+		if !fileTypeMatches(path, opts) {
 			continue
 		}
 
@@ -441,35 +634,49 @@ func collectFiles(ctx context.Context, dir string, opts Options, gitignore *Giti
 			continue
 		}
 
+		if opts.MaxFilesize > 0 {
+			if info, err := entry.Info(); err != nil || tooLarge(info.Size(), opts) {
+				continue
+			}
+		}
+
 		*files = append(*files, path)
 	}
 
 	return nil
 }
 
+// tooLarge reports whether a file of size bytes exceeds --max-filesize.
+func tooLarge(size int64, opts Options) bool {
+	return opts.MaxFilesize > 0 && size > opts.MaxFilesize
+}
+
 // errSkipBinary signals that a file was skipped because it's binary
 var errSkipBinary = fmt.Errorf("binary file skipped")
 
 // searchFileSingle searches a single file and returns results (used by parallel search)
-func searchFileSingle(path string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options) (*FileResult, error) {
-	file, err := os.Open(path)
+func searchFileSingle(ctx context.Context, path string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options) (*FileResult, error) {
+	rc, err := openForSearch(ctx, path, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	defer func() { _ = file.Close() }()
+	defer func() { _ = rc.Close() }()
 
-	// Check if binary
-	buf := make([]byte, 512)
+	file := bufio.NewReader(rc)
 
-	n, _ := file.Read(buf)
-	if n > 0 && isBinary(buf[:n]) {
+	// Check if binary
+	if head, _ := file.Peek(512); len(head) > 0 && isBinary(head) {
 		return nil, errSkipBinary
 	}
 
-	// Reset file position
-	if _, err := file.Seek(0, 0); err != nil {
-		return nil, err
+	if opts.Multiline && isCountMode(opts) {
+		counts, err := countMultiline(file, re, opts)
+		if err != nil || counts.Lines == 0 {
+			return nil, err
+		}
+
+		return &FileResult{Path: path, Count: counts.Lines, MatchCount: counts.Matches}, nil
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -478,6 +685,7 @@ func searchFileSingle(path string, re *regexp.Regexp, pattern, literalPattern st
 		lineNum    int
 		matches    []Match
 		matchCount int
+		occurrence int
 	)
 
 	caseInsensitive := opts.IgnoreCase || (opts.SmartCase && pattern == strings.ToLower(pattern))
@@ -502,7 +710,7 @@ func searchFileSingle(path string, re *regexp.Regexp, pattern, literalPattern st
 			found = matchStart >= 0
 		} else {
 			// Regex search
-			loc := re.FindStringIndex(line)
+			loc := findIndex(line, re, opts)
 			found = loc != nil
 
 			if found {
@@ -518,10 +726,14 @@ func searchFileSingle(path string, re *regexp.Regexp, pattern, literalPattern st
 			matchCount++
 
 			if opts.MaxCount > 0 && matchCount > opts.MaxCount {
+				matchCount--
+
 				break
 			}
 
-			if !opts.Count && !opts.FilesWithMatch && !opts.Quiet {
+			occurrence += countLineMatches(line, re, literalPattern, useLiteral, caseInsensitive, opts)
+
+			if !isCountMode(opts) && !opts.FilesWithMatch && !opts.Quiet {
 				match := Match{
 					Path:       path,
 					LineNumber: lineNum,
@@ -551,41 +763,30 @@ func searchFileSingle(path string, re *regexp.Regexp, pattern, literalPattern st
 	}
 
 	return &FileResult{
-		Path:    path,
-		Matches: matches,
-		Count:   matchCount,
+		Path:       path,
+		Matches:    matches,
+		Count:      matchCount,
+		MatchCount: occurrence,
 	}, nil
 }
 
-// outputFileResult outputs results for a single file
-func outputFileResult(w io.Writer, fr FileResult, opts Options, re *regexp.Regexp, pattern string, useLiteral bool) {
-	if opts.FilesWithMatch {
-		colorMode := ParseColorMode(opts.Color)
-		useColor := ShouldUseColor(colorMode)
-		scheme := DefaultScheme()
-		_, _ = fmt.Fprintln(w, FormatPath(fr.Path, scheme, useColor))
-
-		return
-	}
+// renderedFile is the text output of one searched file.
+type renderedFile struct {
+	path  string
+	block []byte
+}
 
-	if opts.Count {
-		colorMode := ParseColorMode(opts.Color)
-		useColor := ShouldUseColor(colorMode)
-		scheme := DefaultScheme()
-		_, _ = fmt.Fprintf(w, "%s%s%d\n",
-			FormatPath(fr.Path, scheme, useColor),
-			FormatSeparator(":", scheme, useColor),
-			fr.Count)
+// printFile searches one file and hands its rendered output to the printer.
+func printFile(ctx context.Context, pr *printer, path string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, result *resultInternal, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
+	var buf bytes.Buffer
 
-		return
-	}
+	err := searchFile(ctx, &buf, path, re, pattern, literalPattern, useLiteral, opts, result, streamEnc, streamMu)
+	pr.flush(path, buf.Bytes())
 
-	for _, m := range fr.Matches {
-		printLineWithColor(w, m.Path, m.LineNumber, m.Column, m.ByteOffset, m.Line, opts, false, re, pattern, useLiteral)
-	}
+	return err
 }
 
-func searchDir(ctx context.Context, w io.Writer, dir string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, gitignore *GitignoreSet, result *resultInternal, depth int, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
+func searchDir(ctx context.Context, pr *printer, dir string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, gitignore *IgnoreMatcher, result *resultInternal, depth int, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -615,9 +816,9 @@ func searchDir(ctx context.Context, w io.Writer, dir string, re *regexp.Regexp,
 
 		if entry.IsDir() {
 			if opts.FollowSymlinks || entry.Type()&os.ModeSymlink == 0 {
-				if err := searchDir(ctx, w, path, re, pattern, literalPattern, useLiteral, opts, gitignore, result, depth+1, streamEnc, streamMu); err != nil {
+				if err := searchDir(ctx, pr, path, re, pattern, literalPattern, useLiteral, opts, gitignore, result, depth+1, streamEnc, streamMu); err != nil {
 					if !opts.Quiet {
-						_, _ = fmt.Fprintf(w, "rg: %s: %v\n", path, err)
+						_, _ = fmt.Fprintf(pr.w, "rg: %s: %v\n", path, err)
 					}
 				}
 			}
@@ -626,7 +827,7 @@ func searchDir(ctx context.Context, w io.Writer, dir string, re *regexp.Regexp,
 		}
 
 		// Check file type filters
-		if !matchesFileType(path, opts.Types, opts.TypesNot) {
+		if !fileTypeMatches(path, opts) {
 			continue
 		}
 
@@ -635,9 +836,15 @@ func searchDir(ctx context.Context, w io.Writer, dir string, re *regexp.Regexp,
 			continue
 		}
 
-		if err := searchFile(ctx, w, path, re, pattern, literalPattern, useLiteral, opts, result, streamEnc, streamMu); err != nil {
+		if opts.MaxFilesize > 0 {
+			if info, err := entry.Info(); err != nil || tooLarge(info.Size(), opts) {
+				continue
+			}
+		}
+
+		if err := printFile(ctx, pr, path, re, pattern, literalPattern, useLiteral, opts, result, streamEnc, streamMu); err != nil {
 			if !opts.Quiet {
-				_, _ = fmt.Fprintf(w, "rg: %s: %v\n", path, err)
+				_, _ = fmt.Fprintf(pr.w, "rg: %s: %v\n", path, err)
 			}
 		}
 
@@ -652,24 +859,46 @@ func searchDir(ctx context.Context, w io.Writer, dir string, re *regexp.Regexp,
 func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp, pattern, literalPattern string, useLiteral bool, opts Options, result *resultInternal, streamEnc *json.Encoder, streamMu *sync.Mutex) error {
 	jsonMode := output.New(w, opts.OutputFormat).IsJSON()
 
-	file, err := os.Open(path)
+	rc, err := openForSearch(ctx, path, opts)
 	if err != nil {
 		return err
 	}
 
-	defer func() { _ = file.Close() }()
+	defer func() { _ = rc.Close() }()
 
-	// Check if binary
-	buf := make([]byte, 512)
+	file := bufio.NewReader(rc)
 
-	n, _ := file.Read(buf)
-	if n > 0 && isBinary(buf[:n]) {
+	// Check if binary
+	if head, _ := file.Peek(512); len(head) > 0 && isBinary(head) {
 		return nil // Skip binary files
 	}
 
-	// Reset file position
-	if _, err := file.Seek(0, 0); err != nil {
-		return err
+	if opts.Multiline && isCountMode(opts) {
+		counts, err := countMultiline(file, re, opts)
+		if err != nil || counts.Lines == 0 {
+			return err
+		}
+
+		fr := FileResult{Path: path, Count: counts.Lines, MatchCount: counts.Matches}
+
+		result.mu.Lock()
+		result.Files = append(result.Files, fr)
+		result.TotalFiles++
+		result.TotalMatch += fr.Count
+		result.TotalMatchCount += fr.MatchCount
+		result.mu.Unlock()
+
+		if opts.JSONStream && streamEnc != nil {
+			streamMu.Lock()
+			//nolint:errchkjson // StreamEnd is a concrete type
+			_ = streamEnc.Encode(StreamMessage{Type: "end", Data: StreamEnd{Path: path, Count: fr.Count, MatchCount: fr.MatchCount}})
+
+			streamMu.Unlock()
+		} else if !jsonMode {
+			printCount(w, fr, opts)
+		}
+
+		return nil
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -685,6 +914,7 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 		byteOffset      int64
 		matches         []Match
 		matchCount      int
+		occurrence      int
 		beforeLines     []contextLine
 		afterNeeded     int
 		lastPrintedLine int
@@ -732,7 +962,7 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 			found = matchStart >= 0
 		} else {
 			// Regex search
-			loc := re.FindStringIndex(line)
+			loc := findIndex(line, re, opts)
 			found = loc != nil
 
 			if found {
@@ -747,15 +977,21 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 		if found {
 			matchCount++
 
-			result.mu.Lock()
-			result.TotalMatch++
-			result.mu.Unlock()
-
 			if opts.MaxCount > 0 && matchCount > opts.MaxCount {
+				matchCount--
+
 				break
 			}
 
-			if !opts.Count && !opts.FilesWithMatch && !opts.Quiet {
+			n := countLineMatches(line, re, literalPattern, useLiteral, caseInsensitive, opts)
+			occurrence += n
+
+			result.mu.Lock()
+			result.TotalMatch++
+			result.TotalMatchCount += n
+			result.mu.Unlock()
+
+			if !isCountMode(opts) && !opts.FilesWithMatch && !opts.Quiet {
 				match := Match{
 					Path:       path,
 					LineNumber: lineNum,
@@ -822,7 +1058,7 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 			}
 		} else {
 			// Handle after context
-			if afterNeeded > 0 && !jsonMode && !opts.JSONStream && !opts.Count && !opts.FilesWithMatch {
+			if afterNeeded > 0 && !jsonMode && !opts.JSONStream && !isCountMode(opts) && !opts.FilesWithMatch {
 				printLineWithColor(w, path, lineNum, 0, lineByteOffset, line, opts, true, re, pattern, useLiteral)
 				lastPrintedLine = lineNum
 
@@ -844,28 +1080,20 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 		result.TotalFiles++
 
 		fileResult := FileResult{
-			Path:    path,
-			Matches: matches,
-			Count:   matchCount,
+			Path:       path,
+			Matches:    matches,
+			Count:      matchCount,
+			MatchCount: occurrence,
 		}
 		result.Files = append(result.Files, fileResult)
 		result.mu.Unlock()
 
 		if opts.FilesWithMatch && !jsonMode && !opts.JSONStream {
-			colorMode := ParseColorMode(opts.Color)
-			useColor := ShouldUseColor(colorMode)
-			scheme := DefaultScheme()
-			_, _ = fmt.Fprintln(w, FormatPath(path, scheme, useColor))
+			printFileName(w, path, opts)
 		}
 
-		if opts.Count && !jsonMode && !opts.JSONStream {
-			colorMode := ParseColorMode(opts.Color)
-			useColor := ShouldUseColor(colorMode)
-			scheme := DefaultScheme()
-			_, _ = fmt.Fprintf(w, "%s%s%d\n",
-				FormatPath(path, scheme, useColor),
-				FormatSeparator(":", scheme, useColor),
-				matchCount)
+		if isCountMode(opts) && !jsonMode && !opts.JSONStream {
+			printCount(w, fileResult, opts)
 		}
 	}
 
@@ -873,7 +1101,7 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 	if opts.JSONStream && streamEnc != nil {
 		streamMu.Lock()
 		//nolint:errchkjson // StreamEnd is a concrete type
-		_ = streamEnc.Encode(StreamMessage{Type: "end", Data: StreamEnd{Path: path, MatchCount: matchCount}})
+		_ = streamEnc.Encode(StreamMessage{Type: "end", Data: StreamEnd{Path: path, Count: matchCount, MatchCount: occurrence}})
 
 		streamMu.Unlock()
 	}
@@ -881,29 +1109,94 @@ func searchFile(ctx context.Context, w io.Writer, path string, re *regexp.Regexp
 	return scanner.Err()
 }
 
-func printContextSeparator(w io.Writer, opts Options) {
-	colorMode := ParseColorMode(opts.Color)
-	useColor := ShouldUseColor(colorMode)
-	scheme := DefaultScheme()
-	_, _ = fmt.Fprintln(w, FormatSeparator("--", scheme, useColor))
+// isCountMode reports whether only per-file counts are printed.
+func isCountMode(opts Options) bool {
+	return opts.Count || opts.CountMatches
 }
 
-func printLineWithColor(w io.Writer, path string, lineNum, column int, byteOffset int64, line string, opts Options, isContext bool, re *regexp.Regexp, pattern string, useLiteral bool) {
-	sep := ":"
-	if isContext {
-		sep = "-"
+// countsMatches reports whether counts are individual matches rather than
+// matching lines. Like ripgrep, -c combined with -o behaves as --count-matches.
+func countsMatches(opts Options) bool {
+	return opts.CountMatches || (opts.Count && opts.OnlyMatching)
+}
+
+// countLineMatches returns the number of individual matches on a line that
+// was selected. An inverted match selects the whole line, counting once.
+func countLineMatches(line string, re *regexp.Regexp, literalPattern string, useLiteral, caseInsensitive bool, opts Options) int {
+	if opts.InvertMatch {
+		return 1
+	}
+
+	if useLiteral {
+		if caseInsensitive {
+			line = strings.ToLower(line)
+		}
+
+		return strings.Count(line, literalPattern)
+	}
+
+	return len(re.FindAllStringIndex(line, -1))
+}
+
+// countMultiline counts matches over the whole file so they may span lines.
+// It is only used in count mode; -m limits the number of matching lines.
+func countMultiline(r io.Reader, re *regexp.Regexp, opts Options) (pkggrep.Counts, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return pkggrep.Counts{}, err
+	}
+
+	counts := pkggrep.CountMultiline(re, data, opts.InvertMatch)
+	if opts.MaxCount > 0 && counts.Lines > opts.MaxCount {
+		counts.Lines = opts.MaxCount
+	}
+
+	return counts, nil
+}
+
+// printCount prints the per-file count selected by -c / --count-matches,
+// without the path when file names are hidden.
+func printCount(w io.Writer, fr FileResult, opts Options) {
+	count := fr.Count
+	if countsMatches(opts) {
+		count = fr.MatchCount
+	}
+
+	if opts.hideFilename {
+		_, _ = fmt.Fprintf(w, "%d\n", count)
+
+		return
 	}
 
-	// Determine if we should use colors
-	colorMode := ParseColorMode(opts.Color)
-	useColor := ShouldUseColor(colorMode)
-	scheme := DefaultScheme()
+	useColor, scheme := colorSettings(opts)
+	_, _ = fmt.Fprintf(w, "%s%s%d\n",
+		FormatPath(fr.Path, scheme, useColor),
+		FormatSeparator(fieldSeparator(opts, false), scheme, useColor),
+		count)
+}
 
-	// Apply custom color specs
-	for _, spec := range opts.Colors {
-		_ = ApplyColorSpec(&scheme, spec)
+// printContextSeparator prints the line between non-adjacent context groups
+// and, without headings, between files.
+func printContextSeparator(w io.Writer, opts Options) {
+	if opts.NoContextSeparator {
+		return
 	}
 
+	sep := opts.ContextSeparator
+	if sep == "" {
+		sep = "--"
+	}
+
+	useColor, scheme := colorSettings(opts)
+	_, _ = fmt.Fprintln(w, FormatSeparator(sep, scheme, useColor))
+}
+
+// printLineWithColor prints a matching or context line as
+// [path] [byte offset] [line[ column]] text, joined by the field separator.
+// The path is only printed without headings.
+func printLineWithColor(w io.Writer, path string, lineNum, column int, byteOffset int64, line string, opts Options, isContext bool, re *regexp.Regexp, pattern string, useLiteral bool) {
+	useColor, scheme := colorSettings(opts)
+
 	// Handle trim
 	if opts.Trim {
 		line = strings.TrimSpace(line)
@@ -926,123 +1219,55 @@ func printLineWithColor(w io.Writer, path string, lineNum, column int, byteOffse
 		}
 	}
 
-	// Build output
-	if opts.NoHeading {
-		pathStr := path
-		if useColor {
-			pathStr = FormatPath(path, scheme, useColor)
-		}
-
-		sepStr := sep
-		if useColor {
-			sepStr = FormatSeparator(sep, scheme, useColor)
-		}
+	var fields []string
 
-		// Build byte offset string if enabled
-		byteOffsetStr := ""
-		if opts.ByteOffset && byteOffset >= 0 {
-			byteOffsetStr = fmt.Sprintf("%d", byteOffset)
-			if useColor {
-				byteOffsetStr = FormatByteOffset(byteOffset, scheme, useColor)
-			}
-		}
-
-		if opts.ByteOffset && byteOffset >= 0 {
-			// With byte offset: path:byteoffset:linenum:col:line
-			if opts.LineNumber && lineNum > 0 {
-				lineNumStr := fmt.Sprintf("%d", lineNum)
-				if useColor {
-					lineNumStr = FormatLineNumber(lineNum, scheme, useColor)
-				}
-
-				if opts.ShowColumn && column > 0 {
-					colStr := fmt.Sprintf("%d", column)
-					if useColor {
-						colStr = FormatColumn(column, scheme, useColor)
-					}
+	if opts.NoHeading && !opts.hideFilename {
+		fields = append(fields, FormatPath(path, scheme, useColor))
+	}
 
-					_, _ = fmt.Fprintf(w, "%s%s%s%s%s%s%s%s%s\n", pathStr, sepStr, byteOffsetStr, sepStr, lineNumStr, sepStr, colStr, sepStr, highlightedLine)
-				} else {
-					_, _ = fmt.Fprintf(w, "%s%s%s%s%s%s%s\n", pathStr, sepStr, byteOffsetStr, sepStr, lineNumStr, sepStr, highlightedLine)
-				}
-			} else {
-				_, _ = fmt.Fprintf(w, "%s%s%s%s%s\n", pathStr, sepStr, byteOffsetStr, sepStr, highlightedLine)
-			}
-		} else if opts.LineNumber && lineNum > 0 {
-			lineNumStr := fmt.Sprintf("%d", lineNum)
-			if useColor {
-				lineNumStr = FormatLineNumber(lineNum, scheme, useColor)
-			}
+	if opts.ByteOffset && byteOffset >= 0 {
+		fields = append(fields, FormatByteOffset(byteOffset, scheme, useColor))
+	}
 
-			if opts.ShowColumn && column > 0 {
-				colStr := fmt.Sprintf("%d", column)
-				if useColor {
-					colStr = FormatColumn(column, scheme, useColor)
-				}
+	if opts.LineNumber && lineNum > 0 {
+		fields = append(fields, FormatLineNumber(lineNum, scheme, useColor))
 
-				_, _ = fmt.Fprintf(w, "%s%s%s%s%s%s%s\n", pathStr, sepStr, lineNumStr, sepStr, colStr, sepStr, highlightedLine)
-			} else {
-				_, _ = fmt.Fprintf(w, "%s%s%s%s%s\n", pathStr, sepStr, lineNumStr, sepStr, highlightedLine)
-			}
-		} else {
-			_, _ = fmt.Fprintf(w, "%s%s%s\n", pathStr, sepStr, highlightedLine)
-		}
-	} else {
-		sepStr := sep
-		if useColor {
-			sepStr = FormatSeparator(sep, scheme, useColor)
+		if opts.ShowColumn && column > 0 {
+			fields = append(fields, FormatColumn(column, scheme, useColor))
 		}
+	}
 
-		// Build byte offset string if enabled
-		byteOffsetStr := ""
-		if opts.ByteOffset && byteOffset >= 0 {
-			byteOffsetStr = fmt.Sprintf("%d", byteOffset)
-			if useColor {
-				byteOffsetStr = FormatByteOffset(byteOffset, scheme, useColor)
-			}
-		}
+	sep := FormatSeparator(fieldSeparator(opts, isContext), scheme, useColor)
 
-		if opts.ByteOffset && byteOffset >= 0 {
-			// With byte offset: byteoffset:linenum:col:line
-			if opts.LineNumber && lineNum > 0 {
-				lineNumStr := fmt.Sprintf("%d", lineNum)
-				if useColor {
-					lineNumStr = FormatLineNumber(lineNum, scheme, useColor)
-				}
+	var b strings.Builder
 
-				if opts.ShowColumn && column > 0 {
-					colStr := fmt.Sprintf("%d", column)
-					if useColor {
-						colStr = FormatColumn(column, scheme, useColor)
-					}
+	for _, f := range fields {
+		b.WriteString(f)
+		b.WriteString(sep)
+	}
 
-					_, _ = fmt.Fprintf(w, "%s%s%s%s%s%s%s\n", byteOffsetStr, sepStr, lineNumStr, sepStr, colStr, sepStr, highlightedLine)
-				} else {
-					_, _ = fmt.Fprintf(w, "%s%s%s%s%s\n", byteOffsetStr, sepStr, lineNumStr, sepStr, highlightedLine)
-				}
-			} else {
-				_, _ = fmt.Fprintf(w, "%s%s%s\n", byteOffsetStr, sepStr, highlightedLine)
-			}
-		} else if opts.LineNumber && lineNum > 0 {
-			lineNumStr := fmt.Sprintf("%d", lineNum)
-			if useColor {
-				lineNumStr = FormatLineNumber(lineNum, scheme, useColor)
-			}
+	b.WriteString(highlightedLine)
+	_, _ = fmt.Fprintln(w, b.String())
+}
 
-			if opts.ShowColumn && column > 0 {
-				colStr := fmt.Sprintf("%d", column)
-				if useColor {
-					colStr = FormatColumn(column, scheme, useColor)
-				}
+// findIndex locates the first match on line, through the Aho-Corasick
+// automaton for sets of fixed strings.
+func findIndex(line string, re *regexp.Regexp, opts Options) []int {
+	if opts.fixedSet != nil {
+		return opts.fixedSet.FindIndex(line)
+	}
 
-				_, _ = fmt.Fprintf(w, "%s%s%s%s%s\n", lineNumStr, sepStr, colStr, sepStr, highlightedLine)
-			} else {
-				_, _ = fmt.Fprintf(w, "%s%s%s\n", lineNumStr, sepStr, highlightedLine)
-			}
-		} else {
-			_, _ = fmt.Fprintln(w, highlightedLine)
-		}
+	return re.FindStringIndex(line)
+}
+
+// fileTypeMatches applies the -t/-T filters using the types resolved by
+// resolveTypes, falling back to the built-in types.
+func fileTypeMatches(path string, opts Options) bool {
+	if opts.types == nil {
+		return matchesFileType(path, opts.Types, opts.TypesNot)
 	}
+
+	return opts.types.Matches(path, opts.Types, opts.TypesNot)
 }
 
 func matchesFileType(path string, include, exclude []string) bool {
@@ -153,3 +153,33 @@ func TestRunSerialHiddenAndDepth(t *testing.T) {
 		t.Errorf("hidden file should be searched with --hidden:\n%s", buf2.String())
 	}
 }
+
+func TestRunMaxFilesize(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"small.txt": "match\n",
+		"large.txt": "match\n" + strings.Repeat("padding\n", 200),
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, threads := range []int{1, 4} {
+		var buf bytes.Buffer
+		if err := Run(context.Background(), &buf, "match", []string{dir}, Options{Threads: threads, FilesWithMatch: true, MaxFilesize: 1024}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := strings.TrimSpace(buf.String()); filepath.Base(got) != "small.txt" {
+			t.Errorf("threads=%d: files with matches = %q, want only small.txt", threads, got)
+		}
+	}
+
+	if got := strings.Join(listFiles(t, dir, Options{MaxFilesize: 1024}), " "); got != "small.txt" {
+		t.Errorf("--files = %s", got)
+	}
+}
@@ -2,10 +2,13 @@ package rg
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 func TestPatternToRegex_Table(t *testing.T) {
@@ -166,3 +169,72 @@ func TestRun_NoMatchAndInvert(t *testing.T) {
 		t.Errorf("invert output should contain non-matching line: %q", buf.String())
 	}
 }
+
+func TestRun_CountModes(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a.txt": "foo foo foo\nbar\nfoo\nbegin\nx\nend\n",
+	})
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		want    string
+	}{
+		{"count lines", "foo", Options{Count: true}, ":2\n"},
+		{"count matches", "foo", Options{CountMatches: true}, ":4\n"},
+		{"count with only-matching", "foo", Options{Count: true, OnlyMatching: true}, ":4\n"},
+		{"literal count matches", "foo", Options{CountMatches: true, Fixed: true}, ":4\n"},
+		{"invert count matches", "foo", Options{CountMatches: true, InvertMatch: true}, ":4\n"},
+		{"max count", "foo", Options{Count: true, MaxCount: 1}, ":1\n"},
+		{"multiline lines", `(?s)begin.*?end`, Options{Count: true, Multiline: true}, ":3\n"},
+		{"multiline matches", `(?s)begin.*?end|foo`, Options{CountMatches: true, Multiline: true}, ":5\n"},
+	}
+
+	for _, tt := range tests {
+		for _, threads := range []int{1, 2} {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.opts.Threads = threads
+
+				var buf strings.Builder
+				if err := Run(context.Background(), &buf, tt.pattern, []string{dir}, tt.opts); err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+
+				if !strings.HasSuffix(buf.String(), tt.want) {
+					t.Errorf("Run() = %q, want suffix %q", buf.String(), tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestRun_CountJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "foo foo\nbar\nfoo\n"})
+
+	var buf strings.Builder
+	if err := Run(context.Background(), &buf, "foo", []string{dir}, Options{OutputFormat: output.FormatJSON, Threads: 1}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var res Result
+	if err := json.Unmarshal([]byte(buf.String()), &res); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if res.TotalMatch != 2 || res.TotalMatchCount != 3 || res.Files[0].Count != 2 || res.Files[0].MatchCount != 3 {
+		t.Errorf("unexpected counts: %+v", res)
+	}
+
+	buf.Reset()
+
+	if err := Run(context.Background(), &buf, "foo", []string{dir}, Options{JSONStream: true, Threads: 2}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"count":2,"match_count":3`) || !strings.Contains(buf.String(), `"total_match_count":3`) {
+		t.Errorf("stream output missing counts:\n%s", buf.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -255,6 +256,74 @@ func test() {
 	}
 }
 
+func TestMultiplePatterns(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "log.txt")
+
+	if err := os.WriteFile(file, []byte("evil.example hit\nclean line\nBAD-HASH 1a2b\nnothing (x)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	patternFile := filepath.Join(dir, "iocs.list")
+	if err := os.WriteFile(patternFile, []byte("evil.example\r\nbad-hash\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		want    string
+	}{
+		{"regexp alternation", "", Options{Patterns: []string{"^evil", "c.ean"}}, "evil.example hit\nclean line\n"},
+		{"pattern plus -e", "clean", Options{Patterns: []string{`\(x\)`}}, "clean line\nnothing (x)\n"},
+		{"fixed set", "", Options{Fixed: true, Patterns: []string{"(x)", "evil.example"}}, "evil.example hit\nnothing (x)\n"},
+		{"fixed set from file", "", Options{Fixed: true, IgnoreCase: true, PatternFiles: []string{patternFile}}, "evil.example hit\nBAD-HASH 1a2b\n"},
+		{"fixed set inverted", "", Options{Fixed: true, InvertMatch: true, Patterns: []string{"hit", "line"}}, "BAD-HASH 1a2b\nnothing (x)\n"},
+		{"word set", "", Options{Fixed: true, WordRegexp: true, Patterns: []string{"hi", "line"}}, "clean line\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.opts.Threads = 1
+
+			if err := Run(context.Background(), &buf, tt.pattern, []string{file}, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Run() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty pattern file", func(t *testing.T) {
+		empty := filepath.Join(dir, "empty.list")
+		if err := os.WriteFile(empty, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+
+		if err := Run(context.Background(), &buf, "", []string{file}, Options{PatternFiles: []string{empty}}); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("an empty pattern file should match nothing, got %q", buf.String())
+		}
+	})
+
+	t.Run("missing pattern file", func(t *testing.T) {
+		err := Run(context.Background(), io.Discard, "", []string{file}, Options{PatternFiles: []string{filepath.Join(dir, "nope")}})
+		if !cmderr.IsNotFound(err) {
+			t.Errorf("Run() error = %v, want not found", err)
+		}
+	})
+}
+
 func TestParallelSearch(t *testing.T) {
 	dir := t.TempDir()
 
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
 	"github.com/inovacc/omni/internal/cli/safepath"
 )
 
@@ -14,6 +15,8 @@ type RmOptions struct {
 	Recursive      bool // -r/-R: remove directories and their contents recursively
 	Force          bool // -f: ignore nonexistent files, never prompt
 	NoPreserveRoot bool // --no-preserve-root: allow deleting protected paths
+
+	Confirm confirm.Options // --dry-run, -i/--interactive, -y/--yes
 }
 
 func RunRm(args []string, opts RmOptions) error {
@@ -42,12 +45,35 @@ func RunRm(args []string, opts RmOptions) error {
 		}
 	}
 
+	// -f never prompts
+	if opts.Force {
+		opts.Confirm.Interactive = false
+	}
+
+	c := confirm.New("rm", opts.Confirm)
+
 	for _, path := range args {
-		var err error
-		if opts.Recursive {
-			err = os.RemoveAll(path)
-		} else {
-			err = os.Remove(path)
+		info, err := os.Lstat(path)
+		if err == nil {
+			action := fmt.Sprintf("remove '%s'", path)
+			if info.IsDir() && opts.Recursive {
+				action = fmt.Sprintf("remove directory '%s' recursively", path)
+			}
+
+			ok, cerr := c.Confirm(action)
+			if cerr != nil {
+				return cerr
+			}
+
+			if !ok {
+				continue
+			}
+
+			if opts.Recursive {
+				err = os.RemoveAll(path)
+			} else {
+				err = os.Remove(path)
+			}
 		}
 
 		if err != nil {
@@ -1,9 +1,15 @@
 package rm
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
 )
 
 func TestRunRm(t *testing.T) {
@@ -149,3 +155,50 @@ func TestRunRmdir(t *testing.T) {
 		}
 	})
 }
+
+func TestRunRmConfirm(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.txt")
+	drop := filepath.Join(dir, "drop.txt")
+	_ = os.WriteFile(keep, []byte("k"), 0644)
+	_ = os.WriteFile(drop, []byte("d"), 0644)
+
+	var out bytes.Buffer
+
+	err := RunRm([]string{keep}, RmOptions{Confirm: confirm.Options{DryRun: true, Stdout: &out}})
+	if err != nil {
+		t.Fatalf("RunRm(dry-run) error = %v", err)
+	}
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Error("dry run removed the file")
+	}
+
+	if want := "rm: would remove '" + keep + "'\n"; out.String() != want {
+		t.Errorf("dry-run output = %q, want %q", out.String(), want)
+	}
+
+	interactive := confirm.Options{
+		Interactive: true,
+		Stdin:       strings.NewReader("n\ny\n"),
+		Stderr:      io.Discard,
+		AssumeTTY:   true,
+	}
+
+	if err := RunRm([]string{keep, drop}, RmOptions{Confirm: interactive}); err != nil {
+		t.Fatalf("RunRm(interactive) error = %v", err)
+	}
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Error("declined file was removed")
+	}
+
+	if _, err := os.Stat(drop); !os.IsNotExist(err) {
+		t.Error("confirmed file was not removed")
+	}
+
+	err = RunRm([]string{keep}, RmOptions{Confirm: confirm.Options{Interactive: true, Stdin: strings.NewReader("")}})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("interactive without a terminal: err = %v, want invalid input", err)
+	}
+}
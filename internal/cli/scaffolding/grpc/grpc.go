@@ -0,0 +1,446 @@
+// Package grpc scaffolds a Go gRPC service around a proto definition: buf
+// configuration, server wiring with health checks and reflection, and stubs
+// generated through the embedded buf toolchain.
+package grpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/spf13/afero"
+
+	"github.com/inovacc/omni/internal/cli/buf"
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/scaffolding"
+	grpctpl "github.com/inovacc/omni/internal/cli/scaffolding/grpc/templates"
+)
+
+// GRPCOptions configures gRPC service generation
+type GRPCOptions struct {
+	Module   string // Go module path (required)
+	Proto    string // Proto file path relative to Dir (default: api/v1/service.proto)
+	Dir      string // Project directory (default: ".")
+	Addr     string // Default listen address (default: ":50051")
+	Generate bool   // Run buf generate after scaffolding
+	Force    bool   // Overwrite existing generated files
+}
+
+// GRPCResult represents the result of gRPC service generation
+type GRPCResult struct {
+	Status       string   `json:"status"`
+	Module       string   `json:"module"`
+	Proto        string   `json:"proto"`
+	Services     []string `json:"services"`
+	FilesCreated []string `json:"files_created"`
+	FilesSkipped []string `json:"files_skipped,omitempty"`
+	Generated    bool     `json:"generated"`
+	GenerateErr  string   `json:"generate_error,omitempty"`
+}
+
+// runBufGenerate runs the embedded buf generate; tests replace it because
+// real plugin execution needs protoc-gen-go and protoc-gen-go-grpc.
+var runBufGenerate = func(w io.Writer, dir string) error {
+	return buf.RunGenerate(w, dir, buf.GenerateOptions{})
+}
+
+var (
+	modulePathRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~\-]*(/[A-Za-z0-9._~\-]+)*$`)
+	versionDirRe = regexp.MustCompile(`^v\d+((alpha|beta)\d*)?$`)
+)
+
+// RunGRPCInit scaffolds a gRPC service project
+func RunGRPCInit(w io.Writer, fs afero.Fs, opts GRPCOptions, genOpts scaffolding.Options) error {
+	if opts.Module == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "scaffold: --module is required")
+	}
+
+	if !modulePathRe.MatchString(opts.Module) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: invalid module path %q", opts.Module))
+	}
+
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+
+	if opts.Proto == "" {
+		opts.Proto = path.Join("api", "v1", "service.proto")
+	}
+
+	opts.Proto = filepath.ToSlash(filepath.Clean(opts.Proto))
+	if filepath.IsAbs(opts.Proto) || strings.HasPrefix(opts.Proto, "../") || opts.Proto == ".." {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: --proto %q must be relative to the project directory", opts.Proto))
+	}
+
+	if path.Ext(opts.Proto) != ".proto" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: --proto %q must be a .proto file", opts.Proto))
+	}
+
+	if opts.Addr == "" {
+		opts.Addr = ":50051"
+	}
+
+	data, err := buildTemplateData(fs, opts)
+	if err != nil {
+		return err
+	}
+
+	result := GRPCResult{Status: "created", Module: opts.Module, Proto: opts.Proto}
+	for _, s := range data.Services {
+		result.Services = append(result.Services, s.FullName)
+	}
+
+	write := func(rel, tmpl string, tplData any, goSource bool) error {
+		full := filepath.Join(opts.Dir, filepath.FromSlash(rel))
+
+		if !opts.Force {
+			if exists, _ := afero.Exists(fs, full); exists {
+				result.FilesSkipped = append(result.FilesSkipped, rel)
+				return nil
+			}
+		}
+
+		content, err := render(tmpl, tplData, goSource)
+		if err != nil {
+			return err
+		}
+
+		if err := fs.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to create directory for %s: %v", rel, err))
+		}
+
+		if err := afero.WriteFile(fs, full, content, 0o644); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to write %s: %v", rel, err))
+		}
+
+		result.FilesCreated = append(result.FilesCreated, rel)
+
+		return nil
+	}
+
+	if data.DefaultRPCs {
+		if err := write(opts.Proto, grpctpl.ProtoTemplate, data, false); err != nil {
+			return err
+		}
+	}
+
+	files := []struct {
+		rel, tmpl string
+		goSource  bool
+	}{
+		{"buf.yaml", grpctpl.BufYAMLTemplate, false},
+		{"buf.gen.yaml", grpctpl.BufGenYAMLTemplate, false},
+		{"go.mod", grpctpl.GoModTemplate, false},
+		{"cmd/server/main.go", grpctpl.MainTemplate, true},
+	}
+
+	for _, f := range files {
+		if err := write(f.rel, f.tmpl, data, f.goSource); err != nil {
+			return err
+		}
+	}
+
+	for _, svc := range data.Services {
+		rel := path.Join("internal", "server", svc.File+".go")
+		if err := write(rel, grpctpl.ServerTemplate, grpctpl.ServiceFile{Data: data, Service: svc}, true); err != nil {
+			return err
+		}
+	}
+
+	if opts.Generate {
+		var genOut bytes.Buffer
+		if err := runBufGenerate(&genOut, opts.Dir); err != nil {
+			result.GenerateErr = err.Error()
+		} else {
+			result.Generated = true
+		}
+	}
+
+	if genOpts.JSON {
+		return json.NewEncoder(w).Encode(result)
+	}
+
+	_, _ = fmt.Fprintf(w, "Created gRPC service: %s\n", opts.Module)
+	_, _ = fmt.Fprintf(w, "Proto: %s (package %s)\n", opts.Proto, data.Package)
+	_, _ = fmt.Fprintf(w, "Services: %s\n", strings.Join(result.Services, ", "))
+
+	_, _ = fmt.Fprintln(w, "\nFiles created:")
+	for _, f := range result.FilesCreated {
+		_, _ = fmt.Fprintf(w, "  - %s\n", f)
+	}
+
+	if len(result.FilesSkipped) > 0 {
+		_, _ = fmt.Fprintln(w, "\nFiles skipped (already exist, use --force to overwrite):")
+		for _, f := range result.FilesSkipped {
+			_, _ = fmt.Fprintf(w, "  - %s\n", f)
+		}
+	}
+
+	stubDir := path.Join("gen", "go", data.ProtoDir)
+
+	switch {
+	case result.Generated:
+		_, _ = fmt.Fprintf(w, "\nGenerated stubs in %s\n", stubDir)
+	case result.GenerateErr != "":
+		_, _ = fmt.Fprintf(w, "\nWarning: stub generation failed: %s\n", result.GenerateErr)
+	}
+
+	_, _ = fmt.Fprintln(w, "\nNext steps:")
+	step := 1
+
+	if !result.Generated {
+		_, _ = fmt.Fprintln(w, "  1. Install protoc, protoc-gen-go and protoc-gen-go-grpc, then run: omni buf generate")
+		step++
+	}
+
+	_, _ = fmt.Fprintf(w, "  %d. go get google.golang.org/grpc google.golang.org/protobuf && go mod tidy\n", step)
+	_, _ = fmt.Fprintf(w, "  %d. go run ./cmd/server --addr %s\n", step+1, opts.Addr)
+
+	return nil
+}
+
+// buildTemplateData derives template data from an existing proto file, or
+// from defaults when the proto does not exist yet.
+func buildTemplateData(fs afero.Fs, opts GRPCOptions) (grpctpl.TemplateData, error) {
+	appName := path.Base(opts.Module)
+	protoDir := path.Dir(opts.Proto)
+
+	data := grpctpl.TemplateData{
+		Module:     opts.Module,
+		AppName:    appName,
+		ProtoPath:  opts.Proto,
+		ProtoDir:   protoDir,
+		Addr:       opts.Addr,
+		BufVersion: "v1",
+	}
+
+	src, err := afero.ReadFile(fs, filepath.Join(opts.Dir, filepath.FromSlash(opts.Proto)))
+	if err != nil {
+		// No proto yet: scaffold a starter service with a Ping RPC.
+		data.DefaultRPCs = true
+		data.Package = defaultProtoPackage(appName, protoDir)
+		data.GoPackage = path.Join(opts.Module, "gen", "go", protoDir)
+		data.GoPkgName = goPackageName(protoDir)
+
+		name := exportedName(appName) + "Service"
+		data.Services = []grpctpl.Service{{
+			Name:     name,
+			FullName: data.Package + "." + name,
+			Impl:     name,
+			File:     snakeCase(name),
+			Methods: []grpctpl.Method{{
+				Name:   "Ping",
+				Input:  data.GoPkgName + ".PingRequest",
+				Output: data.GoPkgName + ".PingResponse",
+			}},
+		}}
+		data.NeedsCtx = true
+
+		return data, nil
+	}
+
+	pf, err := buf.ParseProtoFile(string(src))
+	if err != nil {
+		return data, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: parse %s: %v", opts.Proto, err))
+	}
+
+	if len(pf.Services) == 0 {
+		return data, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: %s declares no services", opts.Proto))
+	}
+
+	data.Package = pf.Package
+	data.GoPackage = path.Join(opts.Module, "gen", "go", protoDir)
+	data.GoPkgName = goPackageName(protoDir)
+
+	for _, o := range pf.Options {
+		if o.Name != "go_package" {
+			continue
+		}
+
+		importPath, name, hasName := strings.Cut(strings.Trim(o.Value, `"`), ";")
+		data.GoPackage = importPath
+
+		if hasName {
+			data.GoPkgName = name
+		} else {
+			data.GoPkgName = goPackageName(importPath)
+		}
+	}
+
+	for _, ps := range pf.Services {
+		svc := grpctpl.Service{
+			Name:     ps.Name,
+			FullName: qualify(pf.Package, ps.Name),
+			Impl:     ps.Name,
+			File:     snakeCase(ps.Name),
+		}
+
+		for _, pm := range ps.Methods {
+			m := grpctpl.Method{
+				Name:            pm.Name,
+				Input:           goType(pm.InputType, pf.Package, data.GoPkgName, &data),
+				Output:          goType(pm.OutputType, pf.Package, data.GoPkgName, &data),
+				ClientStreaming: pm.ClientStreaming,
+				ServerStreaming: pm.ServerStreaming,
+			}
+
+			if m.ClientStreaming || m.ServerStreaming {
+				data.NeedsGRPC = true
+			} else {
+				data.NeedsCtx = true
+			}
+
+			data.NeedsStatus = true
+			svc.Methods = append(svc.Methods, m)
+		}
+
+		data.Services = append(data.Services, svc)
+	}
+
+	return data, nil
+}
+
+// goType maps a proto message reference to the Go type expression used in
+// the generated server signature.
+func goType(protoType, protoPkg, goPkg string, data *grpctpl.TemplateData) string {
+	t := strings.TrimPrefix(protoType, ".")
+
+	if t == "google.protobuf.Empty" {
+		data.NeedsEmpty = true
+		return "emptypb.Empty"
+	}
+
+	if protoPkg != "" {
+		t = strings.TrimPrefix(t, protoPkg+".")
+	}
+
+	// Nested messages (Outer.Inner) are generated as Outer_Inner.
+	return goPkg + "." + strings.ReplaceAll(t, ".", "_")
+}
+
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+
+	return pkg + "." + name
+}
+
+// defaultProtoPackage builds e.g. "svc.v1" for module .../svc and api/v1.
+func defaultProtoPackage(appName, protoDir string) string {
+	pkg := strings.ToLower(sanitizeIdent(appName))
+
+	if v := path.Base(protoDir); versionDirRe.MatchString(v) {
+		pkg += "." + v
+	}
+
+	return pkg
+}
+
+// goPackageName derives a Go package name from an import path or proto dir:
+// "api/v1" becomes "apiv1", "foo" stays "foo".
+func goPackageName(p string) string {
+	base := path.Base(p)
+
+	if versionDirRe.MatchString(base) {
+		if parent := path.Base(path.Dir(p)); parent != "." && parent != "/" {
+			base = parent + base
+		}
+	}
+
+	name := strings.ToLower(sanitizeIdent(base))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "pb" + name
+	}
+
+	return name
+}
+
+func sanitizeIdent(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// exportedName converts "my-svc" to "MySvc".
+func exportedName(s string) string {
+	var b strings.Builder
+
+	upper := true
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	if b.Len() == 0 {
+		return "Api"
+	}
+
+	return b.String()
+}
+
+// snakeCase converts "UserService" to "user_service".
+func snakeCase(s string) string {
+	var b strings.Builder
+
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func render(tmpl string, data any, goSource bool) ([]byte, error) {
+	t, err := template.New("").Parse(tmpl)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: failed to parse template: %v", err))
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to render template: %v", err))
+	}
+
+	if !goSource {
+		return out.Bytes(), nil
+	}
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: generated code does not parse: %v", err))
+	}
+
+	return formatted, nil
+}
@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/inovacc/omni/internal/cli/scaffolding"
+)
+
+func stubGenerate(t *testing.T, err error) *[]string {
+	t.Helper()
+
+	var calls []string
+
+	orig := runBufGenerate
+	runBufGenerate = func(_ io.Writer, dir string) error {
+		calls = append(calls, dir)
+		return err
+	}
+
+	t.Cleanup(func() { runBufGenerate = orig })
+
+	return &calls
+}
+
+func TestRunGRPCInitDefaultProto(t *testing.T) {
+	calls := stubGenerate(t, nil)
+	fs := afero.NewMemMapFs()
+
+	var buf bytes.Buffer
+
+	err := RunGRPCInit(&buf, fs, GRPCOptions{Module: "example.com/svc", Dir: "/proj", Generate: true}, scaffolding.Options{JSON: true})
+	if err != nil {
+		t.Fatalf("RunGRPCInit() error = %v", err)
+	}
+
+	var res GRPCResult
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if !res.Generated || len(*calls) != 1 || (*calls)[0] != "/proj" {
+		t.Errorf("buf generate not invoked on project dir: %+v, calls=%v", res, *calls)
+	}
+
+	if len(res.Services) != 1 || res.Services[0] != "svc.v1.SvcService" {
+		t.Errorf("services = %v", res.Services)
+	}
+
+	proto, _ := afero.ReadFile(fs, "/proj/api/v1/service.proto")
+	for _, want := range []string{"package svc.v1;", `option go_package = "example.com/svc/gen/go/api/v1;apiv1";`, "service SvcService"} {
+		if !strings.Contains(string(proto), want) {
+			t.Errorf("proto missing %q:\n%s", want, proto)
+		}
+	}
+
+	main, _ := afero.ReadFile(fs, "/proj/cmd/server/main.go")
+	for _, want := range []string{"apiv1.RegisterSvcServiceServer", `SetServingStatus("svc.v1.SvcService"`, "reflection.Register"} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.go missing %q:\n%s", want, main)
+		}
+	}
+
+	impl, _ := afero.ReadFile(fs, "/proj/internal/server/svc_service.go")
+	if !strings.Contains(string(impl), "func (s *SvcService) Ping(ctx context.Context, req *apiv1.PingRequest) (*apiv1.PingResponse, error)") {
+		t.Errorf("unexpected server impl:\n%s", impl)
+	}
+
+	for _, f := range []string{"buf.yaml", "buf.gen.yaml", "go.mod"} {
+		if ok, _ := afero.Exists(fs, filepath.Join("/proj", f)); !ok {
+			t.Errorf("%s not created", f)
+		}
+	}
+}
+
+func TestRunGRPCInitExistingProto(t *testing.T) {
+	stubGenerate(t, errors.New("protoc not found in PATH"))
+	fs := afero.NewMemMapFs()
+
+	proto := `syntax = "proto3";
+
+package acme.orders.v2;
+
+option go_package = "example.com/orders/gen/ordersv2;ordersv2";
+
+import "google/protobuf/empty.proto";
+
+service OrderService {
+  rpc Get(GetRequest) returns (Order);
+  rpc Watch(WatchRequest) returns (stream Order);
+  rpc Upload(stream Order) returns (google.protobuf.Empty);
+  rpc Chat(stream Order) returns (stream Order);
+}
+
+message GetRequest { string id = 1; }
+message WatchRequest { string id = 1; }
+message Order { string id = 1; }
+`
+	_ = afero.WriteFile(fs, "/p/proto/orders.proto", []byte(proto), 0o644)
+
+	var buf bytes.Buffer
+
+	err := RunGRPCInit(&buf, fs, GRPCOptions{Module: "example.com/orders", Dir: "/p", Proto: "proto/orders.proto", Generate: true}, scaffolding.Options{})
+	if err != nil {
+		t.Fatalf("RunGRPCInit() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "stub generation failed") {
+		t.Errorf("expected generation warning, got:\n%s", buf.String())
+	}
+
+	impl, _ := afero.ReadFile(fs, "/p/internal/server/order_service.go")
+	for _, want := range []string{
+		"ordersv2 \"example.com/orders/gen/ordersv2\"",
+		"Get(ctx context.Context, req *ordersv2.GetRequest) (*ordersv2.Order, error)",
+		"Watch(req *ordersv2.WatchRequest, stream grpc.ServerStreamingServer[ordersv2.Order]) error",
+		"Upload(stream grpc.ClientStreamingServer[ordersv2.Order, emptypb.Empty]) error",
+		"Chat(stream grpc.BidiStreamingServer[ordersv2.Order, ordersv2.Order]) error",
+	} {
+		if !strings.Contains(string(impl), want) {
+			t.Errorf("server impl missing %q:\n%s", want, impl)
+		}
+	}
+
+	// The existing proto must not be rewritten.
+	got, _ := afero.ReadFile(fs, "/p/proto/orders.proto")
+	if string(got) != proto {
+		t.Error("existing proto was modified")
+	}
+}
+
+func TestRunGRPCInitSkipsExisting(t *testing.T) {
+	stubGenerate(t, nil)
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/p/go.mod", []byte("module keep\n"), 0o644)
+
+	var buf bytes.Buffer
+	if err := RunGRPCInit(&buf, fs, GRPCOptions{Module: "example.com/svc", Dir: "/p"}, scaffolding.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := afero.ReadFile(fs, "/p/go.mod"); string(data) != "module keep\n" {
+		t.Errorf("go.mod overwritten without --force: %q", data)
+	}
+}
+
+func TestRunGRPCInitValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GRPCOptions
+	}{
+		{"missing module", GRPCOptions{}},
+		{"bad module", GRPCOptions{Module: "bad module"}},
+		{"escaping proto", GRPCOptions{Module: "example.com/x", Proto: "../x.proto"}},
+		{"not a proto", GRPCOptions{Module: "example.com/x", Proto: "api/x.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RunGRPCInit(&buf, afero.NewMemMapFs(), tt.opts, scaffolding.Options{}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
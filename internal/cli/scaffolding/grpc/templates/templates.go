@@ -0,0 +1,226 @@
+package templates
+
+// TemplateData contains all data needed for gRPC service template rendering
+type TemplateData struct {
+	Module      string    // Go module path (e.g., example.com/svc)
+	AppName     string    // Application name (last module segment)
+	ProtoPath   string    // Proto file path relative to the project root
+	ProtoDir    string    // Directory of the proto file
+	Package     string    // Proto package (e.g., svc.v1)
+	GoPackage   string    // Go import path of the generated stubs
+	GoPkgName   string    // Go package name of the generated stubs
+	Addr        string    // Default listen address
+	Services    []Service // Services declared in the proto file
+	NeedsEmpty  bool      // Any method uses google.protobuf.Empty
+	NeedsGRPC   bool      // Any method streams (server impl imports grpc)
+	NeedsCtx    bool      // Any method is unary (server impl imports context)
+	NeedsStatus bool      // Any method is an Unimplemented stub
+	BufVersion  string    // buf.yaml / buf.gen.yaml version
+	DefaultRPCs bool      // Proto was generated by the scaffolder
+}
+
+// Service describes one gRPC service
+type Service struct {
+	Name     string   // Service name (e.g., SvcService)
+	FullName string   // Fully-qualified name used by the health server
+	Impl     string   // Implementation struct name
+	File     string   // Implementation file base name
+	Methods  []Method // RPC methods
+}
+
+// Method describes one RPC method with its Go signature parts
+type Method struct {
+	Name            string
+	Input           string // Go type expression for the request
+	Output          string // Go type expression for the response
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// BufYAMLTemplate generates buf.yaml
+const BufYAMLTemplate = `version: {{.BufVersion}}
+lint:
+  use:
+    - STANDARD
+breaking:
+  use:
+    - FILE
+`
+
+// BufGenYAMLTemplate generates buf.gen.yaml
+const BufGenYAMLTemplate = `version: {{.BufVersion}}
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+    opt:
+      - paths=source_relative
+  - local: protoc-gen-go-grpc
+    out: gen/go
+    opt:
+      - paths=source_relative
+`
+
+// ProtoTemplate generates a starter service definition
+const ProtoTemplate = `syntax = "proto3";
+
+package {{.Package}};
+
+option go_package = "{{.GoPackage}};{{.GoPkgName}}";
+{{range .Services}}
+// {{.Name}} is the {{$.AppName}} API.
+service {{.Name}} {
+  // Ping checks the service is reachable.
+  rpc Ping(PingRequest) returns (PingResponse);
+}
+{{end}}
+// PingRequest is the request for Ping.
+message PingRequest {
+  string message = 1;
+}
+
+// PingResponse is the response for Ping.
+message PingResponse {
+  string message = 1;
+}
+`
+
+// GoModTemplate generates go.mod
+const GoModTemplate = `module {{.Module}}
+
+go 1.25
+`
+
+// MainTemplate generates cmd/server/main.go
+const MainTemplate = `package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	{{.GoPkgName}} "{{.GoPackage}}"
+	"{{.Module}}/internal/server"
+)
+
+func main() {
+	addr := flag.String("addr", "{{.Addr}}", "listen address")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	if err := run(*addr, logger); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(addr string, logger *slog.Logger) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer()
+{{range .Services}}
+	{{$.GoPkgName}}.Register{{.Name}}Server(srv, server.New{{.Impl}}())
+{{- end}}
+
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+{{- range .Services}}
+	healthSrv.SetServingStatus("{{.FullName}}", healthpb.HealthCheckResponse_SERVING)
+{{- end}}
+
+	reflection.Register(srv)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("shutting down")
+		healthSrv.Shutdown()
+		srv.GracefulStop()
+	}()
+
+	logger.Info("gRPC server listening", "addr", lis.Addr().String())
+
+	return srv.Serve(lis)
+}
+`
+
+// ServerTemplate generates internal/server/<service>.go. It is executed once
+// per service with a ServiceFile value.
+const ServerTemplate = `package server
+
+import (
+{{- if .Data.NeedsCtx}}
+	"context"
+{{end}}
+{{- if .Data.NeedsGRPC}}
+	"google.golang.org/grpc"
+{{- end}}
+{{- if .Data.NeedsStatus}}
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+{{- end}}
+{{- if .Data.NeedsEmpty}}
+	"google.golang.org/protobuf/types/known/emptypb"
+{{- end}}
+
+	{{.Data.GoPkgName}} "{{.Data.GoPackage}}"
+)
+
+// {{.Service.Impl}} implements {{.Data.GoPkgName}}.{{.Service.Name}}Server.
+type {{.Service.Impl}} struct {
+	{{.Data.GoPkgName}}.Unimplemented{{.Service.Name}}Server
+}
+
+// New{{.Service.Impl}} returns a {{.Service.Name}} implementation.
+func New{{.Service.Impl}}() *{{.Service.Impl}} {
+	return &{{.Service.Impl}}{}
+}
+{{range .Service.Methods}}
+{{- if and .ClientStreaming .ServerStreaming}}
+// {{.Name}} implements the {{.Name}} bidirectional-streaming RPC.
+func (s *{{$.Service.Impl}}) {{.Name}}(stream grpc.BidiStreamingServer[{{.Input}}, {{.Output}}]) error {
+	return status.Error(codes.Unimplemented, "{{.Name}} not implemented")
+}
+{{- else if .ClientStreaming}}
+// {{.Name}} implements the {{.Name}} client-streaming RPC.
+func (s *{{$.Service.Impl}}) {{.Name}}(stream grpc.ClientStreamingServer[{{.Input}}, {{.Output}}]) error {
+	return status.Error(codes.Unimplemented, "{{.Name}} not implemented")
+}
+{{- else if .ServerStreaming}}
+// {{.Name}} implements the {{.Name}} server-streaming RPC.
+func (s *{{$.Service.Impl}}) {{.Name}}(req *{{.Input}}, stream grpc.ServerStreamingServer[{{.Output}}]) error {
+	return status.Error(codes.Unimplemented, "{{.Name}} not implemented")
+}
+{{- else if and $.Data.DefaultRPCs (eq .Name "Ping")}}
+// {{.Name}} echoes the request message.
+func (s *{{$.Service.Impl}}) {{.Name}}(ctx context.Context, req *{{.Input}}) (*{{.Output}}, error) {
+	return &{{.Output}}{Message: req.GetMessage()}, nil
+}
+{{- else}}
+// {{.Name}} implements the {{.Name}} RPC.
+func (s *{{$.Service.Impl}}) {{.Name}}(ctx context.Context, req *{{.Input}}) (*{{.Output}}, error) {
+	return nil, status.Error(codes.Unimplemented, "{{.Name}} not implemented")
+}
+{{- end}}
+{{end}}`
+
+// ServiceFile is the data for ServerTemplate
+type ServiceFile struct {
+	Data    TemplateData
+	Service Service
+}
@@ -0,0 +1,468 @@
+// Package license generates LICENSE files from the SPDX catalog and stamps
+// SPDX-License-Identifier headers across a source tree.
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/rg"
+	"github.com/inovacc/omni/internal/cli/scaffolding"
+)
+
+// headerScanLines bounds how far into a file an existing header is searched.
+const headerScanLines = 20
+
+// LicenseOptions configures LICENSE generation
+type LicenseOptions struct {
+	Author string // Copyright holder
+	Year   int    // Copyright year (default: current year)
+	Output string // Output path (default: "LICENSE"; "-" for stdout)
+	Force  bool   // Overwrite an existing file
+}
+
+// LicenseResult represents the result of LICENSE generation
+type LicenseResult struct {
+	Status  string `json:"status"`
+	License string `json:"license"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+}
+
+// HeaderOptions configures license header stamping
+type HeaderOptions struct {
+	License    string   // SPDX identifier to stamp
+	Author     string   // Copyright holder (omitted from header when empty)
+	Year       int      // Copyright year (default: current year)
+	Apply      bool     // Write changes (default: report only)
+	Check      bool     // Fail when any file needs a header change
+	Extensions []string // Restrict to these extensions (e.g. .go,.py)
+	NoIgnore   bool     // Do not respect .gitignore files
+}
+
+// HeaderFile is the per-file outcome of header stamping
+type HeaderFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // added, updated, ok, skipped
+	Reason string `json:"reason,omitempty"`
+}
+
+// HeaderResult summarises a header stamping run
+type HeaderResult struct {
+	License   string       `json:"license"`
+	Applied   bool         `json:"applied"`
+	Added     int          `json:"added"`
+	Updated   int          `json:"updated"`
+	Unchanged int          `json:"unchanged"`
+	Skipped   int          `json:"skipped"`
+	Files     []HeaderFile `json:"files"`
+}
+
+// commentStyle describes how a header is commented in a given file type
+type commentStyle struct {
+	start, line, end string
+}
+
+var (
+	slashStyle = commentStyle{line: "// "}
+	hashStyle  = commentStyle{line: "# "}
+	dashStyle  = commentStyle{line: "-- "}
+	cStyle     = commentStyle{start: "/*", line: " * ", end: " */"}
+	xmlStyle   = commentStyle{start: "<!--", line: "  ", end: "-->"}
+)
+
+var styleByExt = map[string]commentStyle{
+	".go": slashStyle, ".c": slashStyle, ".h": slashStyle, ".cc": slashStyle,
+	".cpp": slashStyle, ".hpp": slashStyle, ".cs": slashStyle, ".java": slashStyle,
+	".js": slashStyle, ".jsx": slashStyle, ".mjs": slashStyle, ".cjs": slashStyle,
+	".ts": slashStyle, ".tsx": slashStyle, ".rs": slashStyle, ".swift": slashStyle,
+	".kt": slashStyle, ".kts": slashStyle, ".scala": slashStyle, ".proto": slashStyle,
+	".dart": slashStyle, ".groovy": slashStyle,
+	".py": hashStyle, ".sh": hashStyle, ".bash": hashStyle, ".zsh": hashStyle,
+	".rb": hashStyle, ".pl": hashStyle, ".yaml": hashStyle, ".yml": hashStyle,
+	".toml": hashStyle, ".r": hashStyle, ".ps1": hashStyle, ".tf": hashStyle,
+	".nix": hashStyle,
+	".sql": dashStyle, ".lua": dashStyle, ".hs": dashStyle,
+	".css": cStyle, ".scss": cStyle, ".less": cStyle,
+	".html": xmlStyle, ".htm": xmlStyle, ".xml": xmlStyle, ".vue": xmlStyle,
+}
+
+var styleByName = map[string]commentStyle{
+	"Dockerfile": hashStyle,
+	"Makefile":   hashStyle,
+}
+
+var (
+	spdxRe      = regexp.MustCompile(`SPDX-License-Identifier:\s*(\S+)`)
+	generatedRe = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+)
+
+// RunLicense writes the full text of an SPDX license
+func RunLicense(w io.Writer, fs afero.Fs, id string, opts LicenseOptions, genOpts scaffolding.Options) error {
+	l, err := scaffolding.LookupLicense(id)
+	if err != nil {
+		return err
+	}
+
+	year := opts.Year
+	if year == 0 {
+		year = time.Now().Year()
+	}
+
+	text, err := scaffolding.RenderLicense(l, scaffolding.LicenseData{Year: year, Author: opts.Author})
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == "-" {
+		_, _ = io.WriteString(w, text)
+		return nil
+	}
+
+	path := opts.Output
+	if path == "" {
+		path = "LICENSE"
+	}
+
+	if !opts.Force {
+		if exists, _ := afero.Exists(fs, path); exists {
+			return cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("scaffold: %s already exists (use --force to overwrite)", path))
+		}
+	}
+
+	if err := afero.WriteFile(fs, path, []byte(text), 0o644); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to write %s: %v", path, err))
+	}
+
+	if genOpts.JSON {
+		return json.NewEncoder(w).Encode(LicenseResult{Status: "created", License: l.ID, Name: l.Name, Path: path})
+	}
+
+	_, _ = fmt.Fprintf(w, "Created %s (%s)\n", path, l.ID)
+
+	return nil
+}
+
+// RunList prints the SPDX license catalog
+func RunList(w io.Writer, genOpts scaffolding.Options) error {
+	licenses := scaffolding.Licenses()
+
+	if genOpts.JSON {
+		return json.NewEncoder(w).Encode(licenses)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tOSI\tTEXT\tNAME")
+
+	for _, l := range licenses {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", l.ID, yesNo(l.OSIApproved), yesNo(l.HasText), l.Name)
+	}
+
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// RunLicenseHeaders stamps or updates SPDX license headers under root.
+// Without opts.Apply it only reports what would change.
+func RunLicenseHeaders(w io.Writer, root string, opts HeaderOptions, genOpts scaffolding.Options) error {
+	l, err := scaffolding.LookupLicense(opts.License)
+	if err != nil {
+		return err
+	}
+
+	if opts.Year == 0 {
+		opts.Year = time.Now().Year()
+	}
+
+	if root == "" {
+		root = "."
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("scaffold: %s: no such directory", root))
+		}
+
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
+	}
+
+	if !info.IsDir() {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: %s is not a directory", root))
+	}
+
+	var gitignore *rg.GitignoreSet
+	if !opts.NoIgnore {
+		gitignore = rg.NewGitignoreSet(root)
+		gitignore.AddCommonIgnores()
+	}
+
+	exts := make(map[string]bool, len(opts.Extensions))
+	for _, e := range opts.Extensions {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e != "" && !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+
+		exts[e] = true
+	}
+
+	result := HeaderResult{License: l.ID, Applied: opts.Apply}
+
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if path != root && gitignore != nil && gitignore.ShouldIgnore(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		style, ok := styleFor(path)
+		if !ok {
+			return nil
+		}
+
+		if len(exts) > 0 && !exts[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		hf, err := stampFile(path, style, l.ID, opts)
+		if err != nil {
+			return err
+		}
+
+		switch hf.Status {
+		case "added":
+			result.Added++
+		case "updated":
+			result.Updated++
+		case "ok":
+			result.Unchanged++
+		default:
+			result.Skipped++
+		}
+
+		result.Files = append(result.Files, hf)
+
+		return nil
+	})
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
+	}
+
+	if genOpts.JSON {
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range result.Files {
+			if f.Status == "ok" {
+				continue
+			}
+
+			if f.Reason != "" {
+				_, _ = fmt.Fprintf(w, "%-8s %s (%s)\n", f.Status, f.Path, f.Reason)
+			} else {
+				_, _ = fmt.Fprintf(w, "%-8s %s\n", f.Status, f.Path)
+			}
+		}
+
+		verb := "would change"
+		if opts.Apply {
+			verb = "changed"
+		}
+
+		_, _ = fmt.Fprintf(w, "%d added, %d updated, %d unchanged, %d skipped (%s)\n",
+			result.Added, result.Updated, result.Unchanged, result.Skipped, verb)
+	}
+
+	if opts.Check && !opts.Apply && result.Added+result.Updated > 0 {
+		return cmderr.Wrap(cmderr.ErrConflict,
+			fmt.Sprintf("scaffold: %d file(s) missing or outdated %s header", result.Added+result.Updated, l.ID))
+	}
+
+	return nil
+}
+
+func styleFor(path string) (commentStyle, bool) {
+	if s, ok := styleByName[filepath.Base(path)]; ok {
+		return s, true
+	}
+
+	s, ok := styleByExt[strings.ToLower(filepath.Ext(path))]
+
+	return s, ok
+}
+
+// stampFile inspects one file and, when opts.Apply is set, rewrites it.
+func stampFile(path string, style commentStyle, id string, opts HeaderOptions) (HeaderFile, error) {
+	hf := HeaderFile{Path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return hf, err
+	}
+
+	content := string(data)
+	lines := strings.SplitAfter(content, "\n")
+
+	for i := 0; i < len(lines) && i < 5; i++ {
+		if generatedRe.MatchString(strings.TrimRight(lines[i], "\r\n")) {
+			hf.Status, hf.Reason = "skipped", "generated"
+			return hf, nil
+		}
+	}
+
+	updated, status := updateHeader(lines, style, id, opts)
+	if status == "" {
+		updated, status = insertHeader(lines, style, id, opts), "added"
+	}
+
+	hf.Status = status
+	if status == "ok" || !opts.Apply {
+		return hf, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return hf, err
+	}
+
+	return hf, os.WriteFile(path, []byte(updated), info.Mode().Perm())
+}
+
+// updateHeader rewrites an existing SPDX line (and the copyright line
+// directly above it, when an author is given). It returns "" when no header
+// exists.
+func updateHeader(lines []string, style commentStyle, id string, opts HeaderOptions) (string, string) {
+	limit := min(len(lines), headerScanLines)
+
+	for i := range limit {
+		m := spdxRe.FindStringSubmatchIndex(lines[i])
+		if m == nil {
+			continue
+		}
+
+		out := make([]string, len(lines))
+		copy(out, lines)
+
+		changed := false
+
+		if lines[i][m[2]:m[3]] != id {
+			out[i] = lines[i][:m[2]] + id + lines[i][m[3]:]
+			changed = true
+		}
+
+		// Only a copyright line in the form this tool writes, directly above
+		// the SPDX line, is ours to update; any other one, like a
+		// third-party notice in vendored code, is left alone.
+		if j := i - 1; opts.Author != "" && j >= 0 && isOwnCopyright(lines[j], style) && !strings.Contains(lines[j], opts.Author) {
+			out[j] = style.line + copyrightLine(opts) + lineEnding(lines[j])
+			changed = true
+		}
+
+		if !changed {
+			return "", "ok"
+		}
+
+		return strings.Join(out, ""), "updated"
+	}
+
+	return "", ""
+}
+
+// insertHeader places a new header at the top of the file, after any
+// shebang or XML declaration that must stay on the first line.
+func insertHeader(lines []string, style commentStyle, id string, opts HeaderOptions) string {
+	nl := "\n"
+	if len(lines) > 0 && strings.HasSuffix(lines[0], "\r\n") {
+		nl = "\r\n"
+	}
+
+	var header strings.Builder
+
+	if style.start != "" {
+		header.WriteString(style.start + nl)
+	}
+
+	if opts.Author != "" {
+		header.WriteString(strings.TrimRight(style.line+copyrightLine(opts), " ") + nl)
+	}
+
+	header.WriteString(style.line + "SPDX-License-Identifier: " + id + nl)
+
+	if style.end != "" {
+		header.WriteString(style.end + nl)
+	}
+
+	header.WriteString(nl)
+
+	var prefix []string
+
+	rest := lines
+	if len(rest) > 0 && (strings.HasPrefix(rest[0], "#!") || strings.HasPrefix(rest[0], "<?xml")) {
+		prefix = append(prefix, rest[0])
+		if !strings.HasSuffix(rest[0], "\n") {
+			prefix = append(prefix, nl)
+		}
+
+		rest = rest[1:]
+	}
+
+	return strings.Join(prefix, "") + header.String() + strings.Join(rest, "")
+}
+
+// ownCopyrightRe matches the text of a copyright line as copyrightLine
+// writes it.
+var ownCopyrightRe = regexp.MustCompile(`^Copyright \(c\) \d{4}(-\d{4})? \S`)
+
+// isOwnCopyright reports whether line is a copyright comment in the form
+// license-headers writes.
+func isOwnCopyright(line string, style commentStyle) bool {
+	text := strings.TrimSpace(line)
+	text = strings.TrimSpace(strings.TrimPrefix(text, strings.TrimSpace(style.line)))
+
+	return ownCopyrightRe.MatchString(text)
+}
+
+func copyrightLine(opts HeaderOptions) string {
+	return fmt.Sprintf("Copyright (c) %d %s", opts.Year, opts.Author)
+}
+
+func lineEnding(s string) string {
+	if strings.HasSuffix(s, "\r\n") {
+		return "\r\n"
+	}
+
+	if strings.HasSuffix(s, "\n") {
+		return "\n"
+	}
+
+	return ""
+}
@@ -0,0 +1,226 @@
+package license
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/scaffolding"
+)
+
+func TestRunLicense(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		wantErr  bool
+		contains string
+	}{
+		{"MIT", "MIT", false, "Copyright (c) 2024 Jane Doe"},
+		{"alias", "bsd-2", false, "BSD 2-Clause License"},
+		{"case-insensitive", "isc", false, "ISC License"},
+		{"no placeholders", "Unlicense", false, "public domain"},
+		{"no bundled text", "GPL-3.0-only", true, ""},
+		{"unknown", "NOPE-1.0", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+
+			var buf bytes.Buffer
+
+			err := RunLicense(&buf, fs, tt.id, LicenseOptions{Author: "Jane Doe", Year: 2024}, scaffolding.Options{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunLicense() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			data, _ := afero.ReadFile(fs, "LICENSE")
+			if !strings.Contains(string(data), tt.contains) {
+				t.Errorf("LICENSE missing %q:\n%s", tt.contains, data)
+			}
+		})
+	}
+}
+
+func TestRunLicenseExistingFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "LICENSE", []byte("old"), 0o644)
+
+	var buf bytes.Buffer
+
+	err := RunLicense(&buf, fs, "MIT", LicenseOptions{Author: "X"}, scaffolding.Options{})
+	if !cmderr.IsConflict(err) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+
+	if err := RunLicense(&buf, fs, "MIT", LicenseOptions{Author: "X", Force: true}, scaffolding.Options{}); err != nil {
+		t.Fatalf("RunLicense(--force) error = %v", err)
+	}
+}
+
+func TestRunLicenseStdout(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunLicense(&buf, afero.NewMemMapFs(), "MIT", LicenseOptions{Author: "X", Output: "-"}, scaffolding.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "MIT License") {
+		t.Errorf("stdout output = %q", buf.String())
+	}
+}
+
+func TestRunList(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RunList(&buf, scaffolding.Options{JSON: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var list []scaffolding.License
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if len(list) < 30 {
+		t.Errorf("catalog has %d entries, want a broad SPDX list", len(list))
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunLicenseHeaders(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	writeFile(t, filepath.Join(dir, "run.sh"), "#!/bin/sh\necho hi\n")
+	writeFile(t, filepath.Join(dir, "old.go"), "// Copyright (c) 2020 Jane Doe\n// SPDX-License-Identifier: Apache-2.0\n\npackage old\n")
+	writeFile(t, filepath.Join(dir, "gen.go"), "// Code generated by protoc. DO NOT EDIT.\n\npackage gen\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "# readme\n")
+	writeFile(t, filepath.Join(dir, "vendor", "dep.go"), "package dep\n")
+	writeFile(t, filepath.Join(dir, ".gitignore"), "vendor/\n")
+
+	opts := HeaderOptions{License: "MIT", Author: "Jane Doe", Year: 2024}
+
+	// Dry run reports without touching files.
+	var buf bytes.Buffer
+	if err := RunLicenseHeaders(&buf, dir, opts, scaffolding.Options{}); err != nil {
+		t.Fatalf("dry run error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(data) != "package main\n" {
+		t.Fatal("dry run modified main.go")
+	}
+
+	opts.Check = true
+	if err := RunLicenseHeaders(&buf, dir, opts, scaffolding.Options{}); !cmderr.IsConflict(err) {
+		t.Fatalf("--check expected conflict, got %v", err)
+	}
+
+	opts.Check = false
+	opts.Apply = true
+
+	buf.Reset()
+
+	if err := RunLicenseHeaders(&buf, dir, opts, scaffolding.Options{JSON: true}); err != nil {
+		t.Fatalf("apply error = %v", err)
+	}
+
+	var res HeaderResult
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if res.Added != 2 || res.Updated != 1 || res.Skipped != 1 {
+		t.Errorf("unexpected counts: %+v", res)
+	}
+
+	for _, f := range res.Files {
+		if strings.Contains(f.Path, "vendor") {
+			t.Errorf("gitignored file was processed: %s", f.Path)
+		}
+	}
+
+	goSrc, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if want := "// Copyright (c) 2024 Jane Doe\n// SPDX-License-Identifier: MIT\n\npackage main\n"; string(goSrc) != want {
+		t.Errorf("main.go = %q, want %q", goSrc, want)
+	}
+
+	sh, _ := os.ReadFile(filepath.Join(dir, "run.sh"))
+	if !strings.HasPrefix(string(sh), "#!/bin/sh\n# Copyright") {
+		t.Errorf("shebang not preserved: %q", sh)
+	}
+
+	old, _ := os.ReadFile(filepath.Join(dir, "old.go"))
+	if !strings.Contains(string(old), "SPDX-License-Identifier: MIT") || !strings.Contains(string(old), "2020 Jane Doe") {
+		t.Errorf("old.go not updated in place: %q", old)
+	}
+
+	// Second run is idempotent.
+	buf.Reset()
+
+	if err := RunLicenseHeaders(&buf, dir, opts, scaffolding.Options{JSON: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	res = HeaderResult{}
+	_ = json.Unmarshal(buf.Bytes(), &res)
+
+	if res.Added+res.Updated != 0 {
+		t.Errorf("second run changed files: %+v", res)
+	}
+}
+
+func TestRunLicenseHeadersErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunLicenseHeaders(&buf, t.TempDir(), HeaderOptions{License: "bogus"}, scaffolding.Options{}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("expected invalid input, got %v", err)
+	}
+
+	if err := RunLicenseHeaders(&buf, filepath.Join(t.TempDir(), "missing"), HeaderOptions{License: "MIT"}, scaffolding.Options{}); !cmderr.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestRunLicenseHeadersKeepsForeignCopyright(t *testing.T) {
+	dir := t.TempDir()
+
+	foreign := "// Copyright 2019 The Go Authors. All rights reserved.\n// Use of this source code is governed by a BSD-style license.\n//\n// SPDX-License-Identifier: BSD-3-Clause\n\npackage dep\n"
+	writeFile(t, filepath.Join(dir, "dep.go"), foreign)
+	writeFile(t, filepath.Join(dir, "own.go"), "// Copyright (c) 2020 Old Owner\n// SPDX-License-Identifier: MIT\n\npackage own\n")
+
+	opts := HeaderOptions{License: "BSD-3-Clause", Author: "Jane Doe", Year: 2024, Apply: true}
+
+	var buf bytes.Buffer
+	if err := RunLicenseHeaders(&buf, dir, opts, scaffolding.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "dep.go")); string(data) != foreign {
+		t.Errorf("foreign copyright rewritten: %q", data)
+	}
+
+	own, _ := os.ReadFile(filepath.Join(dir, "own.go"))
+	if want := "// Copyright (c) 2024 Jane Doe\n// SPDX-License-Identifier: BSD-3-Clause\n\npackage own\n"; string(own) != want {
+		t.Errorf("own.go = %q, want %q", own, want)
+	}
+}
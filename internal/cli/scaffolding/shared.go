@@ -2,7 +2,6 @@ package scaffolding
 
 import (
 	"fmt"
-	"strings"
 	"text/template"
 	"time"
 
@@ -38,21 +37,17 @@ func WriteTemplate(fs afero.Fs, path string, tmpl string, data any) error {
 }
 
 // WriteLicense writes a LICENSE file with the given type and author.
-// Supported types: MIT, Apache-2.0, BSD-3.
+// licenseType is any SPDX identifier in the catalog with bundled text, or an
+// alias such as MIT, Apache-2.0 or BSD-3.
 func WriteLicense(fs afero.Fs, path, licenseType, author string) error {
-	year := time.Now().Year()
-
-	var content string
-
-	switch strings.ToLower(licenseType) {
-	case "mit":
-		content = fmt.Sprintf(MITLicense, year, author)
-	case "apache-2.0", "apache":
-		content = fmt.Sprintf(ApacheLicense, year, author)
-	case "bsd-3", "bsd":
-		content = fmt.Sprintf(BSDLicense, year, author)
-	default:
-		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: unknown license type: %s", licenseType))
+	l, err := LookupLicense(licenseType)
+	if err != nil {
+		return err
+	}
+
+	content, err := RenderLicense(l, LicenseData{Year: time.Now().Year(), Author: author})
+	if err != nil {
+		return err
 	}
 
 	return afero.WriteFile(fs, path, []byte(content), 0o644)
@@ -0,0 +1,176 @@
+package scaffolding
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// License is one entry of the SPDX license catalog.
+//
+// Every entry can be used for SPDX-License-Identifier headers. Text is only
+// embedded for short permissive licenses; long copyleft texts (GPL, MPL, ...)
+// are not bundled to keep the binary lean — RenderLicense points at the
+// canonical SPDX page for those instead.
+type License struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	OSIApproved bool   `json:"osiApproved"`
+	HasText     bool   `json:"hasText"`
+	URL         string `json:"url"`
+	text        string
+}
+
+// LicenseData is the template data for rendering license texts.
+type LicenseData struct {
+	Year   int
+	Author string
+}
+
+// spdxLicenses is the catalog, keyed by canonical SPDX identifier.
+var spdxLicenses = map[string]License{}
+
+// licenseAliases maps lowercase shorthands and deprecated SPDX ids to
+// canonical ids. Canonical ids themselves match case-insensitively.
+var licenseAliases = map[string]string{
+	"mit":       "MIT",
+	"apache":    "Apache-2.0",
+	"apache2":   "Apache-2.0",
+	"bsd":       "BSD-3-Clause",
+	"bsd-3":     "BSD-3-Clause",
+	"bsd3":      "BSD-3-Clause",
+	"bsd-2":     "BSD-2-Clause",
+	"bsd2":      "BSD-2-Clause",
+	"gpl-2.0":   "GPL-2.0-only",
+	"gpl-2.0+":  "GPL-2.0-or-later",
+	"gpl-3.0":   "GPL-3.0-only",
+	"gpl-3.0+":  "GPL-3.0-or-later",
+	"gpl2":      "GPL-2.0-only",
+	"gpl3":      "GPL-3.0-only",
+	"lgpl-2.1":  "LGPL-2.1-only",
+	"lgpl-2.1+": "LGPL-2.1-or-later",
+	"lgpl-3.0":  "LGPL-3.0-only",
+	"lgpl-3.0+": "LGPL-3.0-or-later",
+	"agpl-3.0":  "AGPL-3.0-only",
+	"agpl-3.0+": "AGPL-3.0-or-later",
+	"mpl":       "MPL-2.0",
+	"boost":     "BSL-1.0",
+	"cc0":       "CC0-1.0",
+	"unlicense": "Unlicense",
+}
+
+func init() {
+	add := func(id, name string, osi bool, text string) {
+		spdxLicenses[id] = License{
+			ID:          id,
+			Name:        name,
+			OSIApproved: osi,
+			HasText:     text != "",
+			URL:         "https://spdx.org/licenses/" + id + ".html",
+			text:        text,
+		}
+	}
+
+	add("0BSD", "BSD Zero Clause License", true, ZeroBSDLicense)
+	add("AFL-3.0", "Academic Free License v3.0", true, "")
+	add("AGPL-3.0-only", "GNU Affero General Public License v3.0 only", true, "")
+	add("AGPL-3.0-or-later", "GNU Affero General Public License v3.0 or later", true, "")
+	add("Apache-1.1", "Apache License 1.1", true, "")
+	add("Apache-2.0", "Apache License 2.0", true, ApacheLicense)
+	add("Artistic-2.0", "Artistic License 2.0", true, "")
+	add("BSD-1-Clause", "BSD 1-Clause License", true, "")
+	add("BSD-2-Clause", `BSD 2-Clause "Simplified" License`, true, BSD2License)
+	add("BSD-3-Clause", `BSD 3-Clause "New" or "Revised" License`, true, BSDLicense)
+	add("BSD-3-Clause-Clear", "BSD 3-Clause Clear License", false, "")
+	add("BSD-4-Clause", `BSD 4-Clause "Original" or "Old" License`, false, "")
+	add("BSL-1.0", "Boost Software License 1.0", true, BSL1License)
+	add("CC-BY-4.0", "Creative Commons Attribution 4.0 International", false, "")
+	add("CC-BY-SA-4.0", "Creative Commons Attribution Share Alike 4.0 International", false, "")
+	add("CC0-1.0", "Creative Commons Zero v1.0 Universal", false, "")
+	add("CDDL-1.0", "Common Development and Distribution License 1.0", true, "")
+	add("EPL-1.0", "Eclipse Public License 1.0", true, "")
+	add("EPL-2.0", "Eclipse Public License 2.0", true, "")
+	add("EUPL-1.2", "European Union Public License 1.2", true, "")
+	add("GPL-2.0-only", "GNU General Public License v2.0 only", true, "")
+	add("GPL-2.0-or-later", "GNU General Public License v2.0 or later", true, "")
+	add("GPL-3.0-only", "GNU General Public License v3.0 only", true, "")
+	add("GPL-3.0-or-later", "GNU General Public License v3.0 or later", true, "")
+	add("ISC", "ISC License", true, ISCLicense)
+	add("LGPL-2.1-only", "GNU Lesser General Public License v2.1 only", true, "")
+	add("LGPL-2.1-or-later", "GNU Lesser General Public License v2.1 or later", true, "")
+	add("LGPL-3.0-only", "GNU Lesser General Public License v3.0 only", true, "")
+	add("LGPL-3.0-or-later", "GNU Lesser General Public License v3.0 or later", true, "")
+	add("MIT", "MIT License", true, MITLicense)
+	add("MIT-0", "MIT No Attribution", true, MIT0License)
+	add("MPL-2.0", "Mozilla Public License 2.0", true, "")
+	add("MS-PL", "Microsoft Public License", true, "")
+	add("NCSA", "University of Illinois/NCSA Open Source License", true, "")
+	add("OFL-1.1", "SIL Open Font License 1.1", true, "")
+	add("PostgreSQL", "PostgreSQL License", true, "")
+	add("Unlicense", "The Unlicense", true, UnlicenseLicense)
+	add("UPL-1.0", "Universal Permissive License v1.0", true, "")
+	add("Vim", "Vim License", false, "")
+	add("WTFPL", "Do What The F*ck You Want To Public License", false, "")
+	add("Zlib", "zlib License", true, ZlibLicense)
+}
+
+// LookupLicense resolves an SPDX identifier (case-insensitive) or a common
+// alias such as "apache" or "bsd-3" to its catalog entry.
+func LookupLicense(id string) (License, error) {
+	key := strings.TrimSpace(id)
+
+	if canonical, ok := licenseAliases[strings.ToLower(key)]; ok {
+		key = canonical
+	}
+
+	if l, ok := spdxLicenses[key]; ok {
+		return l, nil
+	}
+
+	for canonical, l := range spdxLicenses {
+		if strings.EqualFold(canonical, key) {
+			return l, nil
+		}
+	}
+
+	return License{}, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: unknown license type: %s", id))
+}
+
+// Licenses returns the catalog sorted by SPDX identifier.
+func Licenses() []License {
+	out := make([]License, 0, len(spdxLicenses))
+	for _, l := range spdxLicenses {
+		out = append(out, l)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].ID) < strings.ToLower(out[j].ID)
+	})
+
+	return out
+}
+
+// RenderLicense renders the full license text with the given data.
+// Licenses without embedded text return ErrUnsupported.
+func RenderLicense(l License, data LicenseData) (string, error) {
+	if !l.HasText {
+		return "", cmderr.Wrap(cmderr.ErrUnsupported,
+			fmt.Sprintf("scaffold: license text for %s is not bundled; see %s", l.ID, l.URL))
+	}
+
+	t, err := template.New(l.ID).Parse(l.text)
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: failed to parse license template: %v", err))
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to render license: %v", err))
+	}
+
+	return buf.String(), nil
+}
@@ -2,11 +2,15 @@ package templates
 
 // FuncInfo contains information about a function to generate tests for
 type FuncInfo struct {
-	Name       string
-	Receiver   string // Empty for functions, type name for methods
-	Params     []Param
-	Results    []string
-	IsExported bool
+	Name         string
+	Receiver     string // Empty for functions, type name for methods
+	Params       []Param
+	Results      []string
+	IsExported   bool
+	TestName     string  // Test function name without the Test prefix
+	Wants        []Param // Table fields for the non-error results
+	ReturnsError bool    // Last result is error
+	Call         string  // Call expression using the table fields, e.g. tt.recv.Do(tt.ctx, tt.args...)
 }
 
 // Param represents a function parameter
@@ -18,6 +22,7 @@ type Param struct {
 // TemplateData contains all data needed for test template rendering
 type TemplateData struct {
 	Package   string
+	Imports   []string // Import specs for the test file, e.g. "testing" or foo "example.com/foo"
 	Functions []FuncInfo
 	Parallel  bool
 	Mock      bool
@@ -25,65 +30,84 @@ type TemplateData struct {
 	Fuzz      bool
 }
 
-// TableDrivenTestTemplate generates table-driven tests
-const TableDrivenTestTemplate = `package {{.Package}}
+// TestFileHeader starts a new test file
+const TestFileHeader = `package {{.Package}}
 
 import (
-	"testing"
+{{- range .Imports}}
+	{{.}}
+{{- end}}
 )
-{{range $fn := .Functions}}
-func Test{{if $fn.Receiver}}{{$fn.Receiver}}_{{end}}{{$fn.Name}}(t *testing.T) {
+`
+
+// TableDrivenTestTemplate generates table-driven tests
+const TableDrivenTestTemplate = TestFileHeader + TableDrivenFuncsTemplate
+
+// TableDrivenFuncsTemplate generates the table-driven test functions. The
+// table starts empty, so the skeleton compiles and passes until cases are
+// added; functions returning error get a wantErr column and an error-path
+// case to fill in.
+const TableDrivenFuncsTemplate = `{{range $fn := .Functions}}
+func Test{{$fn.TestName}}(t *testing.T) {
 {{- if $.Parallel}}
 	t.Parallel()
 {{end}}
 	tests := []struct {
-		name    string
+		name string
+{{- if $fn.Receiver}}
+		recv {{$fn.Receiver}}
+{{- end}}
 {{- range $fn.Params}}
 		{{.Name}} {{.Type}}
 {{- end}}
-{{- if gt (len $fn.Results) 0}}
-{{- range $i, $r := $fn.Results}}
-		want{{if gt (len $fn.Results) 1}}{{$i}}{{end}} {{$r}}
-{{- end}}
+{{- range $fn.Wants}}
+		{{.Name}} {{.Type}}
 {{- end}}
-{{- if gt (len $fn.Results) 1}}
+{{- if $fn.ReturnsError}}
 		wantErr bool
 {{- end}}
 	}{
 		// TODO: Add test cases
-		{
-			name: "basic test",
-		},
+		// {name: "valid input"},
+{{- if $fn.ReturnsError}}
+		// {name: "invalid input", wantErr: true},
+{{- end}}
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 {{- if $.Parallel}}
 			t.Parallel()
+{{end}}
+			{{range $i, $w := $fn.Wants}}{{if $i}}, {{end}}got{{if $i}}{{$i}}{{end}}{{end}}{{if $fn.ReturnsError}}{{if $fn.Wants}}, {{end}}err{{end}}{{if or $fn.Wants $fn.ReturnsError}} := {{end}}{{$fn.Call}}
+{{- if $fn.ReturnsError}}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("{{$fn.Name}}() error = %v, wantErr %v", err, tt.wantErr)
+			}
+{{- if $fn.Wants}}
+
+			if tt.wantErr {
+				return
+			}
 {{- end}}
-{{- if $fn.Receiver}}
-			// TODO: Initialize receiver
-			// var r {{$fn.Receiver}}
 {{- end}}
-			// TODO: Call function and verify results
-			// got := {{if $fn.Receiver}}r.{{end}}{{$fn.Name}}({{range $i, $p := $fn.Params}}{{if $i}}, {{end}}tt.{{$p.Name}}{{end}})
-			// if got != tt.want {
-			// 	t.Errorf("{{$fn.Name}}() = %v, want %v", got, tt.want)
-			// }
+{{- range $i, $w := $fn.Wants}}
+{{if or $i $fn.ReturnsError}}
+{{end}}			if !reflect.DeepEqual(got{{if $i}}{{$i}}{{end}}, tt.{{$w.Name}}) {
+				t.Errorf("{{$fn.Name}}() = %v, want %v", got{{if $i}}{{$i}}{{end}}, tt.{{$w.Name}})
+			}
+{{- end}}
 		})
 	}
 }
-{{end}}
-`
+{{end}}`
 
 // SimpleTestTemplate generates simple tests
-const SimpleTestTemplate = `package {{.Package}}
+const SimpleTestTemplate = TestFileHeader + SimpleFuncsTemplate
 
-import (
-	"testing"
-)
-{{range .Functions}}
-func Test{{if .Receiver}}{{.Receiver}}_{{end}}{{.Name}}(t *testing.T) {
+// SimpleFuncsTemplate generates the simple test functions
+const SimpleFuncsTemplate = `{{range .Functions}}
+func Test{{.TestName}}(t *testing.T) {
 {{- if $.Parallel}}
 	t.Parallel()
 {{end}}
@@ -99,12 +123,11 @@ func Test{{if .Receiver}}{{.Receiver}}_{{end}}{{.Name}}(t *testing.T) {
 	// }
 	t.Skip("TODO: Implement test")
 }
-{{end}}
-`
+{{end}}`
 
 // BenchmarkTestTemplate generates benchmark tests
 const BenchmarkTestTemplate = `{{range .Functions}}
-func Benchmark{{if .Receiver}}{{.Receiver}}_{{end}}{{.Name}}(b *testing.B) {
+func Benchmark{{.TestName}}(b *testing.B) {
 {{- if .Receiver}}
 	// TODO: Initialize receiver
 	// var r {{.Receiver}}
@@ -1,14 +1,23 @@
 package testgen
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/spf13/afero"
 
@@ -26,186 +35,587 @@ type TestOptions struct {
 	Fuzz      bool // Include fuzz tests (Go 1.18+)
 }
 
-// TestResult represents the result of test generation
+// TestResult represents the result of test generation for one source file
 type TestResult struct {
-	Status     string   `json:"status"`
+	Status     string   `json:"status"` // created, updated or skipped
 	SourceFile string   `json:"source_file"`
-	TestFile   string   `json:"test_file"`
+	TestFile   string   `json:"test_file,omitempty"`
 	Functions  []string `json:"functions"`
+	Covered    []string `json:"covered,omitempty"` // functions that already had tests
 }
 
-// RunTestInit generates tests for a Go source file
+// PackageResult represents the result of test generation for a package
+// directory
+type PackageResult struct {
+	Dir   string       `json:"dir"`
+	Files []TestResult `json:"files"`
+}
+
+// sourceFile is a parsed Go source file and its testable functions
+type sourceFile struct {
+	path      string
+	pkg       string
+	functions []testtpl.FuncInfo
+	imports   []string // import specs the generated tests need
+}
+
+// RunTestInit generates test skeletons for the exported functions and
+// methods of a Go source file, or of every non-test Go file in a package
+// directory. Functions that already have a Test function in the package
+// are skipped, and tests for a file that already has a _test.go file are
+// appended to it. Generic functions are skipped.
 func RunTestInit(w io.Writer, fs afero.Fs, sourcePath string, opts TestOptions, genOpts scaffolding.Options) error {
 	if sourcePath == "" {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "scaffold: source file path is required")
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("scaffold: file not found: %s", sourcePath))
+	info, err := fs.Stat(sourcePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("scaffold: file not found: %s", sourcePath))
+		}
+
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
 	}
 
-	// Parse the Go source file
-	fset := token.NewFileSet()
+	dir, sources := filepath.Dir(sourcePath), []string{sourcePath}
+
+	if info.IsDir() {
+		dir = sourcePath
 
-	node, err := parser.ParseFile(fset, sourcePath, nil, parser.ParseComments)
+		if sources, err = packageSources(fs, dir); err != nil {
+			return err
+		}
+	}
+
+	covered, err := existingTests(fs, dir)
 	if err != nil {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: failed to parse %s: %v", sourcePath, err))
+		return err
 	}
 
-	// Extract functions
-	var functions []testtpl.FuncInfo
+	var (
+		results []TestResult
+		found   int
+	)
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		fn, ok := n.(*ast.FuncDecl)
-		if !ok {
-			return true
+	for _, src := range sources {
+		sf, err := parseSource(fs, src)
+		if err != nil {
+			return err
 		}
 
-		if !fn.Name.IsExported() {
-			return true
+		found += len(sf.functions)
+
+		result, err := generate(fs, sf, covered, opts)
+		if err != nil {
+			return err
 		}
 
-		funcInfo := testtpl.FuncInfo{
-			Name:       fn.Name.Name,
-			IsExported: true,
+		if len(sf.functions) > 0 {
+			results = append(results, result)
 		}
+	}
 
-		// Get receiver type if method
-		if fn.Recv != nil && len(fn.Recv.List) > 0 {
-			if t, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
-				if ident, ok := t.X.(*ast.Ident); ok {
-					funcInfo.Receiver = ident.Name
-				}
-			} else if ident, ok := fn.Recv.List[0].Type.(*ast.Ident); ok {
-				funcInfo.Receiver = ident.Name
+	if found == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: no exported functions found in %s", sourcePath))
+	}
+
+	if genOpts.JSON {
+		if !info.IsDir() {
+			return json.NewEncoder(w).Encode(results[0])
+		}
+
+		return json.NewEncoder(w).Encode(PackageResult{Dir: dir, Files: results})
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+
+		printResult(w, r)
+	}
+
+	return nil
+}
+
+// packageSources lists the non-test Go files of dir
+func packageSources(fs afero.Fs, dir string) ([]string, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
+	}
+
+	var sources []string
+
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			sources = append(sources, filepath.Join(dir, name))
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: no Go source files in %s", dir))
+	}
+
+	return sources, nil
+}
+
+// existingTests returns the names of the Test functions already in dir
+func existingTests(fs afero.Fs, dir string) (map[string]bool, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
+	}
+
+	names := map[string]bool{}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+
+		src, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
+		}
+
+		// A test file that does not parse covers nothing
+		file, err := parser.ParseFile(token.NewFileSet(), path, src, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Test") {
+				names[fn.Name.Name] = true
 			}
 		}
+	}
 
-		// Get parameters
-		if fn.Type.Params != nil {
-			for _, param := range fn.Type.Params.List {
-				paramType := exprToString(param.Type)
+	return names, nil
+}
 
-				for _, name := range param.Names {
-					funcInfo.Params = append(funcInfo.Params, testtpl.Param{
-						Name: name.Name,
-						Type: paramType,
-					})
-				}
+// isCovered reports whether a test for fn exists: TestName, TestRecv_Name
+// or TestRecvName, or any of them followed by an underscore suffix.
+func isCovered(fn testtpl.FuncInfo, tests map[string]bool) bool {
+	candidates := []string{"Test" + fn.TestName}
+	if fn.Receiver != "" {
+		candidates = append(candidates, "Test"+fn.Receiver+fn.Name)
+	}
 
-				if len(param.Names) == 0 {
-					funcInfo.Params = append(funcInfo.Params, testtpl.Param{
-						Name: "arg",
-						Type: paramType,
-					})
-				}
+	for name := range tests {
+		for _, c := range candidates {
+			if name == c || strings.HasPrefix(name, c+"_") {
+				return true
 			}
 		}
+	}
+
+	return false
+}
+
+// parseSource extracts the exported, non-generic functions and methods of
+// a Go source file
+func parseSource(fs afero.Fs, path string) (*sourceFile, error) {
+	src, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
+	}
+
+	fset := token.NewFileSet()
 
-		// Get results
-		if fn.Type.Results != nil {
-			for _, result := range fn.Type.Results.List {
-				funcInfo.Results = append(funcInfo.Results, exprToString(result.Type))
+	file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: failed to parse %s: %v", path, err))
+	}
+
+	sf := &sourceFile{path: path, pkg: file.Name.Name}
+	used := map[string]bool{}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || fn.Type.TypeParams != nil {
+			continue
+		}
+
+		info, ok := funcInfo(fn)
+		if !ok {
+			continue
+		}
+
+		ast.Inspect(fn.Type, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
 			}
+
+			return true
+		})
+
+		sf.functions = append(sf.functions, info)
+	}
+
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
 		}
 
-		functions = append(functions, funcInfo)
+		if used[name] {
+			sf.imports = append(sf.imports, importSpec(spec))
+		}
+	}
 
-		return true
-	})
+	return sf, nil
+}
 
-	if len(functions) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: no exported functions found in %s", sourcePath))
+// funcInfo describes fn for the templates. Methods on generic types are
+// not supported.
+func funcInfo(fn *ast.FuncDecl) (testtpl.FuncInfo, bool) {
+	info := testtpl.FuncInfo{Name: fn.Name.Name, IsExported: true, TestName: fn.Name.Name}
+
+	call := fn.Name.Name
+
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		typ := fn.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+
+		id, ok := typ.(*ast.Ident)
+		if !ok {
+			return info, false
+		}
+
+		info.Receiver = id.Name
+		info.TestName = id.Name + "_" + fn.Name.Name
+		call = "tt.recv." + call
+	}
+
+	var args []string
+
+	for i, field := range expandFields(fn.Type.Params) {
+		name := fieldName(field.name, i)
+		typ := types.ExprString(field.typ)
+		arg := "tt." + name
+
+		if ellipsis, ok := field.typ.(*ast.Ellipsis); ok {
+			typ = "[]" + types.ExprString(ellipsis.Elt)
+			arg += "..."
+		}
+
+		info.Params = append(info.Params, testtpl.Param{Name: name, Type: typ})
+		args = append(args, arg)
+	}
+
+	info.Call = call + "(" + strings.Join(args, ", ") + ")"
+
+	results := expandFields(fn.Type.Results)
+
+	for i, field := range results {
+		typ := types.ExprString(field.typ)
+		info.Results = append(info.Results, typ)
+
+		if i == len(results)-1 && typ == "error" {
+			info.ReturnsError = true
+
+			continue
+		}
+
+		want := "want"
+		if n := len(info.Wants); n > 0 {
+			want += strconv.Itoa(n)
+		}
+
+		info.Wants = append(info.Wants, testtpl.Param{Name: want, Type: typ})
+	}
+
+	// The table holds a pointer so receivers with locks are not copied
+	if info.Receiver != "" {
+		info.Receiver = "*" + info.Receiver
+	}
+
+	return info, true
+}
+
+type field struct {
+	name string
+	typ  ast.Expr
+}
+
+// expandFields lists one entry per parameter or result, splitting grouped
+// names such as (a, b int)
+func expandFields(list *ast.FieldList) []field {
+	if list == nil {
+		return nil
+	}
+
+	var fields []field
+
+	for _, f := range list.List {
+		if len(f.Names) == 0 {
+			fields = append(fields, field{typ: f.Type})
+
+			continue
+		}
+
+		for _, name := range f.Names {
+			fields = append(fields, field{name: name.Name, typ: f.Type})
+		}
+	}
+
+	return fields
+}
+
+// reservedFields are the names the table and test body use themselves
+var reservedFields = []string{"name", "recv", "tt", "t", "tests", "err", "wantErr", "reflect", "testing"}
+
+// fieldName returns the table field for the i-th parameter
+func fieldName(name string, i int) string {
+	if name == "" || name == "_" {
+		return "arg" + strconv.Itoa(i)
+	}
+
+	if slices.Contains(reservedFields, name) || strings.HasPrefix(name, "want") || strings.HasPrefix(name, "got") {
+		return name + "Arg"
+	}
+
+	return name
+}
+
+// importName guesses the package name of an import path: its last
+// element without a major version suffix or go- prefix
+func importName(importPath string) string {
+	name := path.Base(importPath)
+
+	if strings.HasPrefix(name, "v") {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = path.Base(path.Dir(importPath))
+		}
+	}
+
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func importSpec(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name + " " + spec.Path.Value
+	}
+
+	return spec.Path.Value
+}
+
+// generate writes the tests for the uncovered functions of sf, creating
+// its _test.go file or appending to it
+func generate(fs afero.Fs, sf *sourceFile, covered map[string]bool, opts TestOptions) (TestResult, error) {
+	result := TestResult{Status: "skipped", SourceFile: sf.path, Functions: []string{}}
+
+	var todo []testtpl.FuncInfo
+
+	for _, fn := range sf.functions {
+		if isCovered(fn, covered) {
+			result.Covered = append(result.Covered, displayName(fn))
+
+			continue
+		}
+
+		todo = append(todo, fn)
+		result.Functions = append(result.Functions, displayName(fn))
+	}
+
+	if len(todo) == 0 {
+		return result, nil
 	}
 
-	// Prepare template data
 	data := testtpl.TemplateData{
-		Package:   node.Name.Name,
-		Functions: functions,
+		Package:   sf.pkg,
+		Functions: todo,
 		Parallel:  opts.Parallel,
 		Mock:      opts.Mock,
 		Benchmark: opts.Benchmark,
 		Fuzz:      opts.Fuzz,
 	}
 
-	// Generate test file path
-	testPath := strings.TrimSuffix(sourcePath, ".go") + "_test.go"
+	funcsTpl := testtpl.SimpleFuncsTemplate
+	imports := []string{`"testing"`}
 
-	// Select template
-	var tpl string
 	if opts.Table {
-		tpl = testtpl.TableDrivenTestTemplate
-	} else {
-		tpl = testtpl.SimpleTestTemplate
+		funcsTpl = testtpl.TableDrivenFuncsTemplate
+
+		if slices.ContainsFunc(todo, func(fn testtpl.FuncInfo) bool { return len(fn.Wants) > 0 }) {
+			imports = append(imports, `"reflect"`)
+		}
+
+		imports = append(imports, sf.imports...)
 	}
 
-	// Add benchmark tests if requested
 	if opts.Benchmark {
-		tpl += testtpl.BenchmarkTestTemplate
+		funcsTpl += testtpl.BenchmarkTestTemplate
+	}
+
+	testPath := strings.TrimSuffix(sf.path, ".go") + "_test.go"
+
+	existing, err := afero.ReadFile(fs, testPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return result, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: %v", err))
 	}
 
-	// Write test file
-	if err := scaffolding.WriteTemplate(fs, testPath, tpl, data); err != nil {
-		return fmt.Errorf("scaffold: failed to create %s: %w", testPath, err)
+	var content []byte
+
+	if existing == nil {
+		data.Imports = imports
+		content, err = render(testtpl.TestFileHeader+funcsTpl, data)
+		result.Status = "created"
+	} else {
+		content, err = appendTests(testPath, existing, funcsTpl, imports, data)
+		result.Status = "updated"
 	}
 
-	if genOpts.JSON {
-		var funcNames []string
+	if err != nil {
+		return result, err
+	}
 
-		for _, f := range functions {
-			funcNames = append(funcNames, f.Name)
-		}
+	if err := afero.WriteFile(fs, testPath, content, 0o644); err != nil {
+		return result, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to create %s: %v", testPath, err))
+	}
 
-		result := TestResult{
-			Status:     "created",
-			SourceFile: sourcePath,
-			TestFile:   testPath,
-			Functions:  funcNames,
+	result.TestFile = testPath
+
+	return result, nil
+}
+
+// appendTests adds the rendered tests to an existing test file, importing
+// whatever it does not import yet
+func appendTests(testPath string, existing []byte, funcsTpl string, imports []string, data testtpl.TemplateData) ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), testPath, existing, parser.ImportsOnly)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: failed to parse %s: %v", testPath, err))
+	}
+
+	if file.Name.Name != data.Package {
+		return nil, cmderr.Wrap(cmderr.ErrConflict, fmt.Sprintf("scaffold: %s is in package %s, not %s", testPath, file.Name.Name, data.Package))
+	}
+
+	have := map[string]bool{}
+	for _, spec := range file.Imports {
+		have[spec.Path.Value] = true
+	}
+
+	var missing []string
+
+	for _, spec := range imports {
+		quoted := spec[strings.Index(spec, `"`):]
+		if !have[quoted] {
+			missing = append(missing, spec)
 		}
+	}
 
-		return json.NewEncoder(w).Encode(result)
+	funcs, err := render(funcsTpl, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	// A separate import block after the file's own imports leaves them and
+	// their comments untouched
+	end := file.Name.End()
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			end = gen.End()
+		}
 	}
 
-	_, _ = fmt.Fprintf(w, "Created test file: %s\n", testPath)
-	_, _ = fmt.Fprintf(w, "Source file: %s\n", sourcePath)
-	_, _ = fmt.Fprintf(w, "Package: %s\n", node.Name.Name)
+	offset := int(end) - int(file.FileStart)
+	buf.Write(existing[:offset])
 
-	_, _ = fmt.Fprintln(w, "\nTests generated for:")
+	if len(missing) > 0 {
+		buf.WriteString("\n\nimport (\n")
 
-	for _, f := range functions {
-		if f.Receiver != "" {
-			_, _ = fmt.Fprintf(w, "  - %s.%s\n", f.Receiver, f.Name)
-		} else {
-			_, _ = fmt.Fprintf(w, "  - %s\n", f.Name)
+		for _, spec := range missing {
+			buf.WriteString("\t" + spec + "\n")
 		}
+
+		buf.WriteString(")")
 	}
 
-	return nil
+	buf.Write(existing[offset:])
+	buf.WriteString("\n")
+	buf.Write(funcs)
+
+	return formatSource(testPath, buf.Bytes())
+}
+
+// render executes a test template and formats the result
+func render(tpl string, data testtpl.TemplateData) ([]byte, error) {
+	t, err := template.New("").Parse(tpl)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: failed to parse template: %v", err))
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scaffold: failed to render tests: %v", err))
+	}
+
+	if !strings.HasPrefix(tpl, testtpl.TestFileHeader) {
+		return buf.Bytes(), nil
+	}
+
+	return formatSource("test file", buf.Bytes())
 }
 
-// exprToString converts an AST expression to string representation
-func exprToString(expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.StarExpr:
-		return "*" + exprToString(t.X)
-	case *ast.SelectorExpr:
-		return exprToString(t.X) + "." + t.Sel.Name
-	case *ast.ArrayType:
-		return "[]" + exprToString(t.Elt)
-	case *ast.MapType:
-		return "map[" + exprToString(t.Key) + "]" + exprToString(t.Value)
-	case *ast.InterfaceType:
-		return "interface{}"
-	case *ast.FuncType:
-		return "func"
-	case *ast.ChanType:
-		return "chan " + exprToString(t.Value)
-	case *ast.Ellipsis:
-		return "..." + exprToString(t.Elt)
+func formatSource(name string, src []byte) ([]byte, error) {
+	out, err := format.Source(src)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("scaffold: generated %s does not parse: %v", name, err))
+	}
+
+	return out, nil
+}
+
+func displayName(fn testtpl.FuncInfo) string {
+	if fn.Receiver != "" {
+		return strings.TrimPrefix(fn.Receiver, "*") + "." + fn.Name
+	}
+
+	return fn.Name
+}
+
+func printResult(w io.Writer, r TestResult) {
+	switch r.Status {
+	case "created":
+		_, _ = fmt.Fprintf(w, "Created test file: %s\n", r.TestFile)
+	case "updated":
+		_, _ = fmt.Fprintf(w, "Updated test file: %s\n", r.TestFile)
 	default:
-		return "any"
+		_, _ = fmt.Fprintf(w, "Skipped: %s (all exported functions have tests)\n", r.SourceFile)
+	}
+
+	if r.Status != "skipped" {
+		_, _ = fmt.Fprintf(w, "Source file: %s\n", r.SourceFile)
+
+		_, _ = fmt.Fprintln(w, "\nTests generated for:")
+
+		for _, name := range r.Functions {
+			_, _ = fmt.Fprintf(w, "  - %s\n", name)
+		}
+	}
+
+	if len(r.Covered) > 0 && r.Status != "skipped" {
+		_, _ = fmt.Fprintf(w, "\nAlready tested: %s\n", strings.Join(r.Covered, ", "))
 	}
 }
@@ -2,8 +2,10 @@ package testgen
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -65,4 +67,98 @@ func Multiply(x, y int) int {
 			t.Error("Expected error for nonexistent file")
 		}
 	})
+
+	t.Run("package directory skips covered functions", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/pkg/foo.go", `package foo
+
+import "io"
+
+func Add(a, b int) int { return a + b }
+
+func Copy(w io.Writer, data ...[]byte) (int, error) { return 0, nil }
+
+func Map[T any](v T) T { return v }
+
+type Store struct{}
+
+func (s *Store) Get(key string) (string, bool) { return key, true }
+
+func unexported() {}
+`)
+		writeFile(t, fs, "/pkg/foo_test.go", `package foo
+
+import "testing"
+
+func TestAdd(t *testing.T) {}
+`)
+
+		var buf bytes.Buffer
+		if err := RunTestInit(&buf, fs, "/pkg", TestOptions{Table: true}, scaffolding.Options{JSON: true}); err != nil {
+			t.Fatalf("RunTestInit() error = %v", err)
+		}
+
+		var result PackageResult
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		if len(result.Files) != 1 {
+			t.Fatalf("Files = %d, want 1", len(result.Files))
+		}
+
+		r := result.Files[0]
+		if r.Status != "updated" {
+			t.Errorf("Status = %q, want updated", r.Status)
+		}
+
+		if got := strings.Join(r.Covered, ","); got != "Add" {
+			t.Errorf("Covered = %q, want Add", got)
+		}
+
+		data, _ := afero.ReadFile(fs, "/pkg/foo_test.go")
+		out := string(data)
+
+		for _, want := range []string{"func TestAdd(", "func TestCopy(", "func TestStore_Get(", `"io"`, "wantErr bool", "tt.data...", "tt.recv.Get(tt.key)"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("test file missing %q:\n%s", want, out)
+			}
+		}
+
+		for _, unwanted := range []string{"TestMap", "Testunexported", "func TestAdd(t *testing.T) {\n\ttests"} {
+			if strings.Contains(out, unwanted) {
+				t.Errorf("test file should not contain %q", unwanted)
+			}
+		}
+
+		// A second run finds everything covered
+		buf.Reset()
+
+		if err := RunTestInit(&buf, fs, "/pkg", TestOptions{Table: true}, scaffolding.Options{}); err != nil {
+			t.Fatalf("RunTestInit() error = %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "Skipped") {
+			t.Errorf("second run output = %q, want Skipped", buf.String())
+		}
+	})
+
+	t.Run("package mismatch", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/pkg/foo.go", "package foo\n\nfunc Add(a, b int) int { return a + b }\n")
+		writeFile(t, fs, "/pkg/foo_test.go", "package bar\n")
+
+		var buf bytes.Buffer
+		if err := RunTestInit(&buf, fs, "/pkg/foo.go", TestOptions{}, scaffolding.Options{}); err == nil {
+			t.Error("expected error for a test file in another package")
+		}
+	})
+}
+
+func writeFile(t *testing.T, fs afero.Fs, path, content string) {
+	t.Helper()
+
+	if err := afero.WriteFile(fs, path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
 }
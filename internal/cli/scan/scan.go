@@ -277,7 +277,7 @@ func fetch(url string) ([]byte, error) {
 
 	resp, err := client.Do(req) //nolint:gosec // URL is operator-provided (--url) and SSRF-guarded above.
 	if err != nil {
-		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("scan db update: fetch %s: %v", url, err))
+		return nil, cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("scan db update: fetch %s: %v", url, err))
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
@@ -0,0 +1,501 @@
+package script
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// Values are string, int64, bool or []any.
+
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	default:
+		return v != nil
+	}
+}
+
+func toStr(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = toStr(item)
+		}
+
+		return strings.Join(parts, " ")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toInt(v any) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+
+		return 0, nil
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("not an integer: %q", v))
+		}
+
+		return n, nil
+	default:
+		return 0, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("not an integer: %s", toStr(v)))
+	}
+}
+
+func stringList(s []string) []any {
+	list := make([]any, len(s))
+	for i, v := range s {
+		list[i] = v
+	}
+
+	return list
+}
+
+// iterate returns the items a for loop visits: list elements, the lines of
+// a string, or 0..n-1 for an integer.
+func iterate(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case string:
+		return stringList(splitLines(v))
+	case int64:
+		list := make([]any, 0, max(v, 0))
+		for i := range v {
+			list = append(list, i)
+		}
+
+		return list
+	default:
+		return nil
+	}
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(s, "\r\n")
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+
+	return lines
+}
+
+func index(v, i any) (any, error) {
+	n, err := toInt(i)
+	if err != nil {
+		return nil, err
+	}
+
+	var length int64
+
+	switch v := v.(type) {
+	case []any:
+		length = int64(len(v))
+	case string:
+		length = int64(len(v))
+	default:
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("cannot index %s", toStr(v)))
+	}
+
+	if n < 0 {
+		n += length
+	}
+
+	if n < 0 || n >= length {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("index %d out of range (length %d)", n, length))
+	}
+
+	if list, ok := v.([]any); ok {
+		return list[n], nil
+	}
+
+	return v.(string)[n : n+1], nil
+}
+
+func binaryOp(op string, l, r any) (any, error) {
+	switch op {
+	case "==", "!=":
+		eq := equal(l, r)
+		if op == "!=" {
+			eq = !eq
+		}
+
+		return eq, nil
+	case "<", "<=", ">", ">=":
+		c := compare(l, r)
+
+		switch op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "+":
+		if ll, ok := l.([]any); ok {
+			if rl, ok := r.([]any); ok {
+				return append(append([]any{}, ll...), rl...), nil
+			}
+
+			return append(append([]any{}, ll...), r), nil
+		}
+
+		_, lint := l.(int64)
+		_, rint := r.(int64)
+
+		if lint && rint {
+			return l.(int64) + r.(int64), nil
+		}
+
+		return toStr(l) + toStr(r), nil
+	}
+
+	a, err := toInt(l)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := toInt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/", "%":
+		if b == 0 {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "division by zero")
+		}
+
+		if op == "/" {
+			return a / b, nil
+		}
+
+		return a % b, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+func equal(l, r any) bool {
+	ll, lok := l.([]any)
+	rl, rok := r.([]any)
+
+	if lok || rok {
+		if !lok || !rok || len(ll) != len(rl) {
+			return false
+		}
+
+		for i := range ll {
+			if !equal(ll[i], rl[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	_, lb := l.(bool)
+	_, rb := r.(bool)
+
+	if lb || rb {
+		return truthy(l) == truthy(r)
+	}
+
+	return compare(l, r) == 0
+}
+
+// compare orders numerically when both sides are integers, else as strings.
+func compare(l, r any) int {
+	a, aerr := toInt(l)
+	b, berr := toInt(r)
+
+	if aerr == nil && berr == nil {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(toStr(l), toStr(r))
+}
+
+type builtin func(in *interp, args []any) (any, error)
+
+// builtins are the functions callable from expressions.
+var builtins = map[string]builtin{
+	"len": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		if list, ok := args[0].([]any); ok {
+			return int64(len(list)), nil
+		}
+
+		return int64(len(toStr(args[0]))), nil
+	},
+	"str": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		return toStr(args[0]), nil
+	},
+	"int": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		return toInt(args[0])
+	},
+	"lines": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		return stringList(splitLines(toStr(args[0]))), nil
+	},
+	"split": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+
+		return stringList(strings.Split(toStr(args[0]), toStr(args[1]))), nil
+	},
+	"fields": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		return stringList(strings.Fields(toStr(args[0]))), nil
+	},
+	"join": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+
+		list, ok := args[0].([]any)
+		if !ok {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "first argument must be a list")
+		}
+
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = toStr(item)
+		}
+
+		return strings.Join(parts, toStr(args[1])), nil
+	},
+	"trim":      stringFunc(strings.TrimSpace),
+	"upper":     stringFunc(strings.ToUpper),
+	"lower":     stringFunc(strings.ToLower),
+	"basename":  stringFunc(filepath.Base),
+	"dirname":   stringFunc(filepath.Dir),
+	"contains":  predicate2(strings.Contains),
+	"hasprefix": predicate2(strings.HasPrefix),
+	"hassuffix": predicate2(strings.HasSuffix),
+	"replace": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 3); err != nil {
+			return nil, err
+		}
+
+		return strings.ReplaceAll(toStr(args[0]), toStr(args[1]), toStr(args[2])), nil
+	},
+	"matches": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+
+		re, err := regexp.Compile(toStr(args[1]))
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, err.Error())
+		}
+
+		return re.MatchString(toStr(args[0])), nil
+	},
+	"exists": statFunc(func(os.FileInfo) bool { return true }),
+	"isdir":  statFunc(os.FileInfo.IsDir),
+	"isfile": statFunc(func(fi os.FileInfo) bool { return fi.Mode().IsRegular() }),
+	"glob": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		matches, err := filepath.Glob(toStr(args[0]))
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, err.Error())
+		}
+
+		return stringList(matches), nil
+	},
+	"env": func(_ *interp, args []any) (any, error) {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "expected 1 or 2 arguments")
+		}
+
+		if v, ok := os.LookupEnv(toStr(args[0])); ok {
+			return v, nil
+		}
+
+		if len(args) == 2 {
+			return args[1], nil
+		}
+
+		return "", nil
+	},
+	"setenv": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+
+		return nil, os.Setenv(toStr(args[0]), toStr(args[1]))
+	},
+	"read": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(toStr(args[0]))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, cmderr.Wrap(cmderr.ErrNotFound, toStr(args[0]))
+			}
+
+			return nil, cmderr.Wrap(cmderr.ErrIO, err.Error())
+		}
+
+		return string(data), nil
+	},
+	"write": func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(toStr(args[0]), []byte(toStr(args[1])), 0o644); err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, err.Error())
+		}
+
+		return nil, nil
+	},
+	"range": func(_ *interp, args []any) (any, error) {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "expected 1 or 2 arguments")
+		}
+
+		var lo, hi int64
+
+		hi, err := toInt(args[len(args)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) == 2 {
+			if lo, err = toInt(args[0]); err != nil {
+				return nil, err
+			}
+		}
+
+		list := make([]any, 0, max(hi-lo, 0))
+		for i := lo; i < hi; i++ {
+			list = append(list, i)
+		}
+
+		return list, nil
+	},
+	"print": func(in *interp, args []any) (any, error) {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = toStr(a)
+		}
+
+		_, _ = fmt.Fprintln(in.w, strings.Join(parts, " "))
+
+		return nil, nil
+	},
+}
+
+func arity(args []any, n int) error {
+	if len(args) != n {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("expected %d argument(s), got %d", n, len(args)))
+	}
+
+	return nil
+}
+
+func stringFunc(fn func(string) string) builtin {
+	return func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		return fn(toStr(args[0])), nil
+	}
+}
+
+func predicate2(fn func(s, t string) bool) builtin {
+	return func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+
+		return fn(toStr(args[0]), toStr(args[1])), nil
+	}
+}
+
+func statFunc(fn func(os.FileInfo) bool) builtin {
+	return func(_ *interp, args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+
+		fi, err := os.Stat(toStr(args[0]))
+
+		return err == nil && fn(fi), nil
+	}
+}
@@ -0,0 +1,1041 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- AST ---
+
+type stmt interface{ stmtLine() int }
+
+type expr interface{}
+
+type pos struct{ line int }
+
+func (p pos) stmtLine() int { return p.line }
+
+type assignStmt struct {
+	pos
+	name  string
+	value expr
+}
+
+type exprStmt struct {
+	pos
+	x expr
+}
+
+type ifStmt struct {
+	pos
+	cond expr
+	then []stmt
+	els  []stmt
+}
+
+type forStmt struct {
+	pos
+	name string
+	list expr
+	body []stmt
+}
+
+type whileStmt struct {
+	pos
+	cond expr
+	body []stmt
+}
+
+type branchStmt struct {
+	pos
+	keyword string // break or continue
+}
+
+type exitStmt struct {
+	pos
+	code expr // nil for 0
+}
+
+type failStmt struct {
+	pos
+	msg expr
+}
+
+type cdStmt struct {
+	pos
+	dir word
+}
+
+type cmdStmt struct {
+	pos
+	pipe *pipeline
+	try  bool
+}
+
+// pipeline is cmd | cmd ... with optional redirections.
+type pipeline struct {
+	cmds   [][]word
+	input  *word // < file
+	output *word // > file or >> file
+	append bool
+}
+
+// word is one command argument, built from literal and substituted parts.
+// A word that is exactly one unquoted variable expands a list into several
+// arguments.
+type word struct {
+	parts []expr
+	splat bool
+}
+
+type literal struct{ value any }
+
+type varRef struct {
+	name string
+	env  bool // $name: fall back to the process environment
+}
+
+type listExpr struct{ elems []expr }
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	op   string
+	l, r expr
+}
+
+type callExpr struct {
+	name string
+	args []expr
+}
+
+type indexExpr struct {
+	x, index expr
+}
+
+// captureExpr is $(cmd): the output of a pipeline, or with test, $?(cmd):
+// whether it succeeded.
+type captureExpr struct {
+	pipe *pipeline
+	test bool
+}
+
+// interpExpr is a double-quoted string with substitutions.
+type interpExpr struct{ parts []expr }
+
+// --- Parser ---
+
+type parser struct {
+	src  string
+	name string
+	pos  int
+	line int
+}
+
+type parseError struct {
+	file string
+	line int
+	msg  string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.file, e.line, e.msg)
+}
+
+// parse parses a script into statements.
+func parse(name, src string) ([]stmt, error) {
+	p := &parser{src: src, name: name, line: 1}
+
+	return p.block(true)
+}
+
+func (p *parser) errorf(format string, a ...any) error {
+	return &parseError{file: p.name, line: p.line, msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *parser) peekAt(off int) byte {
+	if p.pos+off >= len(p.src) {
+		return 0
+	}
+
+	return p.src[p.pos+off]
+}
+
+func (p *parser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+
+	if c == '\n' {
+		p.line++
+	}
+
+	return c
+}
+
+// skipSpace skips blanks within a line.
+func (p *parser) skipSpace() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r':
+			p.next()
+		case '\\':
+			// Line continuation
+			if p.peekAt(1) == '\n' {
+				p.next()
+				p.next()
+
+				continue
+			}
+
+			return
+		default:
+			return
+		}
+	}
+}
+
+// skipBlank skips blanks, newlines, statement separators and comments.
+func (p *parser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n', ';':
+			p.next()
+		case '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// peekIdent returns the identifier at the current position without consuming it.
+func (p *parser) peekIdent() string {
+	if !isIdentStart(p.peek()) {
+		return ""
+	}
+
+	end := p.pos
+	for end < len(p.src) && isIdentChar(p.src[end]) {
+		end++
+	}
+
+	return p.src[p.pos:end]
+}
+
+// keyword reports whether the next token is the keyword kw followed by a
+// non-identifier character.
+func (p *parser) keyword(kw string) bool {
+	return p.peekIdent() == kw
+}
+
+func (p *parser) ident() (string, error) {
+	id := p.peekIdent()
+	if id == "" {
+		return "", p.errorf("expected identifier")
+	}
+
+	p.pos += len(id)
+
+	return id, nil
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipSpace()
+
+	if p.peek() != c {
+		return p.errorf("expected %q, found %s", c, p.describe())
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *parser) describe() string {
+	switch {
+	case p.eof():
+		return "end of file"
+	case p.peek() == '\n':
+		return "end of line"
+	default:
+		return strconv.Quote(string(p.peek()))
+	}
+}
+
+// endStmt checks that nothing but a separator, comment or block end follows.
+func (p *parser) endStmt() error {
+	p.skipSpace()
+
+	switch p.peek() {
+	case 0, '\n', ';', '#', '}':
+		return nil
+	default:
+		return p.errorf("unexpected %s", p.describe())
+	}
+}
+
+func (p *parser) block(top bool) ([]stmt, error) {
+	var stmts []stmt
+
+	for {
+		p.skipBlank()
+
+		if p.eof() {
+			if !top {
+				return nil, p.errorf("missing '}'")
+			}
+
+			return stmts, nil
+		}
+
+		if p.peek() == '}' {
+			if top {
+				return nil, p.errorf("unexpected '}'")
+			}
+
+			p.next()
+
+			return stmts, nil
+		}
+
+		s, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+
+		stmts = append(stmts, s)
+	}
+}
+
+func (p *parser) body() ([]stmt, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	return p.block(false)
+}
+
+func (p *parser) stmt() (stmt, error) {
+	at := pos{line: p.line}
+	id := p.peekIdent()
+
+	switch id {
+	case "if":
+		return p.ifStmt(at)
+	case "for":
+		p.pos += len(id)
+		p.skipSpace()
+
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+
+		if !p.keyword("in") {
+			return nil, p.errorf("expected 'in' after for %s", name)
+		}
+
+		p.pos += 2
+
+		list, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := p.body()
+		if err != nil {
+			return nil, err
+		}
+
+		return &forStmt{pos: at, name: name, list: list, body: body}, nil
+	case "while":
+		p.pos += len(id)
+
+		cond, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := p.body()
+		if err != nil {
+			return nil, err
+		}
+
+		return &whileStmt{pos: at, cond: cond, body: body}, nil
+	case "break", "continue":
+		p.pos += len(id)
+		return &branchStmt{pos: at, keyword: id}, p.endStmt()
+	case "exit":
+		p.pos += len(id)
+		s := &exitStmt{pos: at}
+
+		if p.endStmt() != nil {
+			code, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			s.code = code
+		}
+
+		return s, p.endStmt()
+	case "fail":
+		p.pos += len(id)
+
+		msg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return &failStmt{pos: at, msg: msg}, p.endStmt()
+	case "let":
+		p.pos += len(id)
+		p.skipSpace()
+
+		return p.assign(at)
+	case "cd":
+		p.pos += len(id)
+		p.skipSpace()
+
+		w, err := p.word(stmtTerm)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(w.parts) == 0 {
+			return nil, p.errorf("cd: missing directory")
+		}
+
+		return &cdStmt{pos: at, dir: w}, p.endStmt()
+	case "try":
+		p.pos += len(id)
+		p.skipSpace()
+
+		pl, err := p.pipeline(stmtTerm)
+		if err != nil {
+			return nil, err
+		}
+
+		return &cmdStmt{pos: at, pipe: pl, try: true}, nil
+	}
+
+	if id != "" {
+		rest := p.src[p.pos+len(id):]
+		trimmed := strings.TrimLeft(rest, " \t")
+
+		switch {
+		case strings.HasPrefix(trimmed, "=") && !strings.HasPrefix(trimmed, "=="):
+			return p.assign(at)
+		case strings.HasPrefix(rest, "("):
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			return &exprStmt{pos: at, x: x}, p.endStmt()
+		}
+	}
+
+	pl, err := p.pipeline(stmtTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cmdStmt{pos: at, pipe: pl}, nil
+}
+
+func (p *parser) assign(at pos) (stmt, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect('='); err != nil {
+		return nil, err
+	}
+
+	value, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &assignStmt{pos: at, name: name, value: value}, p.endStmt()
+}
+
+func (p *parser) ifStmt(at pos) (stmt, error) {
+	p.pos += len("if")
+
+	cond, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	then, err := p.body()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ifStmt{pos: at, cond: cond, then: then}
+
+	// Look ahead for else on the same or a following line
+	save, saveLine := p.pos, p.line
+	p.skipBlank()
+
+	if !p.keyword("else") {
+		p.pos, p.line = save, saveLine
+		return s, nil
+	}
+
+	p.pos += len("else")
+	p.skipSpace()
+
+	if p.keyword("if") {
+		elif, err := p.ifStmt(pos{line: p.line})
+		if err != nil {
+			return nil, err
+		}
+
+		s.els = []stmt{elif}
+
+		return s, nil
+	}
+
+	if s.els, err = p.body(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// --- Commands ---
+
+// terminator reports whether c ends a command at the word boundary.
+type terminator func(c byte) bool
+
+func stmtTerm(c byte) bool {
+	return c == 0 || c == '\n' || c == ';' || c == '}' || c == '#'
+}
+
+func captureTerm(c byte) bool {
+	return c == 0 || c == '\n' || c == ')'
+}
+
+func (p *parser) pipeline(end terminator) (*pipeline, error) {
+	pl := &pipeline{}
+
+	var cmd []word
+
+	for {
+		p.skipSpace()
+		c := p.peek()
+
+		switch {
+		case end(c):
+			if len(cmd) == 0 {
+				if len(pl.cmds) == 0 {
+					return nil, p.errorf("expected a command")
+				}
+
+				return nil, p.errorf("missing command after '|'")
+			}
+
+			pl.cmds = append(pl.cmds, cmd)
+
+			return pl, nil
+		case c == '|':
+			p.next()
+
+			if p.peek() == '|' {
+				return nil, p.errorf("'||' is not supported in commands; use if $?(...)")
+			}
+
+			if len(cmd) == 0 {
+				return nil, p.errorf("missing command before '|'")
+			}
+
+			pl.cmds = append(pl.cmds, cmd)
+			cmd = nil
+		case c == '>' || c == '<':
+			p.next()
+
+			appendMode := false
+			if c == '>' && p.peek() == '>' {
+				p.next()
+
+				appendMode = true
+			}
+
+			p.skipSpace()
+
+			w, err := p.word(end)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(w.parts) == 0 {
+				return nil, p.errorf("missing file after %q", c)
+			}
+
+			if c == '<' {
+				pl.input = &w
+			} else {
+				pl.output, pl.append = &w, appendMode
+			}
+		default:
+			w, err := p.word(end)
+			if err != nil {
+				return nil, err
+			}
+
+			cmd = append(cmd, w)
+		}
+	}
+}
+
+// word parses one shell-style argument.
+func (p *parser) word(end terminator) (word, error) {
+	var (
+		w   word
+		lit strings.Builder
+	)
+
+	flush := func() {
+		if lit.Len() > 0 {
+			w.parts = append(w.parts, &literal{value: lit.String()})
+			lit.Reset()
+		}
+	}
+
+	unquotedVars := 0
+
+	for !p.eof() {
+		c := p.peek()
+
+		// '#' only starts a comment at the beginning of a word
+		started := lit.Len() > 0 || len(w.parts) > 0
+		if c == ' ' || c == '\t' || c == '\r' || c == '|' || c == '>' || c == '<' || (end(c) && (c != '#' || !started)) {
+			break
+		}
+
+		switch c {
+		case '"':
+			flush()
+
+			x, err := p.doubleQuoted()
+			if err != nil {
+				return w, err
+			}
+
+			w.parts = append(w.parts, x)
+		case '\'':
+			s, err := p.singleQuoted()
+			if err != nil {
+				return w, err
+			}
+
+			lit.WriteString(s)
+
+			// '' is an explicit empty argument
+			if s == "" {
+				w.parts = append(w.parts, &literal{value: ""})
+			}
+		case '$':
+			flush()
+
+			x, err := p.dollar(true)
+			if err != nil {
+				return w, err
+			}
+
+			if _, ok := x.(*varRef); ok {
+				unquotedVars++
+			}
+
+			w.parts = append(w.parts, x)
+		case '\\':
+			p.next()
+
+			if p.eof() {
+				return w, p.errorf("trailing backslash")
+			}
+
+			lit.WriteByte(p.next())
+		default:
+			lit.WriteByte(p.next())
+		}
+	}
+
+	flush()
+
+	w.splat = len(w.parts) == 1 && unquotedVars == 1
+
+	return w, nil
+}
+
+func (p *parser) singleQuoted() (string, error) {
+	p.next()
+
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' {
+		p.next()
+	}
+
+	if p.eof() {
+		return "", p.errorf("unterminated string")
+	}
+
+	s := p.src[start:p.pos]
+	p.next()
+
+	return s, nil
+}
+
+func (p *parser) doubleQuoted() (expr, error) {
+	p.next()
+
+	var (
+		parts []expr
+		lit   strings.Builder
+	)
+
+	for {
+		if p.eof() {
+			return nil, p.errorf("unterminated string")
+		}
+
+		c := p.peek()
+
+		switch c {
+		case '"':
+			p.next()
+
+			if lit.Len() > 0 || len(parts) == 0 {
+				parts = append(parts, &literal{value: lit.String()})
+			}
+
+			if len(parts) == 1 {
+				if l, ok := parts[0].(*literal); ok {
+					return l, nil
+				}
+			}
+
+			return &interpExpr{parts: parts}, nil
+		case '\\':
+			p.next()
+
+			if p.eof() {
+				return nil, p.errorf("unterminated string")
+			}
+
+			switch e := p.next(); e {
+			case 'n':
+				lit.WriteByte('\n')
+			case 't':
+				lit.WriteByte('\t')
+			case 'r':
+				lit.WriteByte('\r')
+			case '0':
+				lit.WriteByte(0)
+			case '\n':
+				// Line continuation inside a string
+			default:
+				lit.WriteByte(e)
+			}
+		case '$':
+			if !isIdentStart(p.peekAt(1)) && p.peekAt(1) != '{' && p.peekAt(1) != '(' && p.peekAt(1) != '?' {
+				lit.WriteByte(p.next())
+				continue
+			}
+
+			if lit.Len() > 0 {
+				parts = append(parts, &literal{value: lit.String()})
+				lit.Reset()
+			}
+
+			x, err := p.dollar(true)
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, x)
+		default:
+			lit.WriteByte(p.next())
+		}
+	}
+}
+
+// dollar parses $name, ${expr}, $(pipeline) or $?(pipeline).
+func (p *parser) dollar(env bool) (expr, error) {
+	p.next()
+
+	switch c := p.peek(); {
+	case c == '(' || (c == '?' && p.peekAt(1) == '('):
+		test := c == '?'
+		if test {
+			p.next()
+		}
+
+		p.next()
+
+		pl, err := p.pipeline(captureTerm)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+
+		return &captureExpr{pipe: pl, test: test}, nil
+	case c == '{':
+		p.next()
+
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+
+		if v, ok := x.(*varRef); ok {
+			v.env = env
+		}
+
+		return x, nil
+	case isIdentStart(c):
+		name, _ := p.ident()
+		return &varRef{name: name, env: env}, nil
+	default:
+		return nil, p.errorf("expected variable or $( after '$'")
+	}
+}
+
+// --- Expressions ---
+
+var binaryPrec = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+func (p *parser) expr() (expr, error) {
+	return p.binary(1)
+}
+
+// peekOp returns the binary operator at the current position.
+func (p *parser) peekOp() string {
+	p.skipSpace()
+
+	if p.pos+1 < len(p.src) {
+		if two := p.src[p.pos : p.pos+2]; binaryPrec[two] > 0 {
+			return two
+		}
+	}
+
+	if one := string(p.peek()); binaryPrec[one] > 0 {
+		return one
+	}
+
+	return ""
+}
+
+func (p *parser) binary(minPrec int) (expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peekOp()
+
+		prec := binaryPrec[op]
+		if op == "" || prec < minPrec {
+			return left, nil
+		}
+
+		p.pos += len(op)
+
+		right, err := p.binary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpr{op: op, l: left, r: right}
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	p.skipSpace()
+
+	if c := p.peek(); c == '!' || c == '-' {
+		p.next()
+
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpr{op: string(c), x: x}, nil
+	}
+
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == '[' {
+		p.next()
+
+		idx, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+
+		x = &indexExpr{x: x, index: idx}
+	}
+
+	return x, nil
+}
+
+func (p *parser) primary() (expr, error) {
+	p.skipSpace()
+
+	switch c := p.peek(); {
+	case c == '(':
+		p.next()
+
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return x, p.expect(')')
+	case c == '[':
+		p.next()
+
+		var elems []expr
+
+		for {
+			p.skipBlank()
+
+			if p.peek() == ']' {
+				p.next()
+				return &listExpr{elems: elems}, nil
+			}
+
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			elems = append(elems, x)
+
+			p.skipBlank()
+
+			if p.peek() == ',' {
+				p.next()
+			} else if p.peek() != ']' {
+				return nil, p.errorf("expected ',' or ']' in list")
+			}
+		}
+	case c == '"':
+		return p.doubleQuoted()
+	case c == '\'':
+		s, err := p.singleQuoted()
+		return &literal{value: s}, err
+	case c == '$':
+		return p.dollar(false)
+	case c >= '0' && c <= '9':
+		start := p.pos
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.next()
+		}
+
+		n, err := strconv.ParseInt(p.src[start:p.pos], 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %s", p.src[start:p.pos])
+		}
+
+		return &literal{value: n}, nil
+	case isIdentStart(c):
+		name, _ := p.ident()
+
+		switch name {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		}
+
+		if p.peek() != '(' {
+			return &varRef{name: name}, nil
+		}
+
+		p.next()
+
+		call := &callExpr{name: name}
+
+		for {
+			p.skipBlank()
+
+			if p.peek() == ')' {
+				p.next()
+				return call, nil
+			}
+
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			call.args = append(call.args, x)
+
+			p.skipBlank()
+
+			if p.peek() == ',' {
+				p.next()
+			} else if p.peek() != ')' {
+				return nil, p.errorf("expected ',' or ')' in call to %s", name)
+			}
+		}
+	default:
+		return nil, p.errorf("expected expression, found %s", p.describe())
+	}
+}
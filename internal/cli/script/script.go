@@ -0,0 +1,605 @@
+// Package script implements `omni script`, a small restricted language for
+// cross-platform automation. Scripts call omni subcommands in-process, so
+// they behave the same under bash, PowerShell or cmd.exe:
+//
+//	# build.omni
+//	let dirs = ["bin", "dist"]
+//	for d in dirs {
+//	    mkdir -p $d
+//	}
+//	let n = int($(find src -name "*.go" | wc -l))
+//	if n == 0 {
+//	    fail "no Go sources"
+//	}
+//	echo "found ${n} files" > dist/summary.txt
+//
+// Statements are assignments (let x = expr, or x = expr), if/else, for NAME
+// in expr, while, break, continue, cd, exit [code], fail msg, function calls
+// and commands. Any other line is a command: shell-style words with "..."
+// (interpolated) and '...' (literal) quoting, $var and ${expr} substitution,
+// $(cmd) output capture, pipes and < > >> redirection. A failing command
+// stops the script unless prefixed with try; $status holds the last exit
+// code. Expressions support strings, integers, booleans, lists, the usual
+// arithmetic, comparison and logical operators, indexing and the built-in
+// functions listed in builtins.go. $?(cmd) is true when cmd succeeds.
+//
+// No external processes are started: commands go to the Runner, which
+// dispatches omni subcommands only.
+package script
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/command"
+)
+
+// Options configures the script command behavior
+type Options struct {
+	Code  string            // -c: script source instead of a file
+	Stdin io.Reader         // standard input of the first command in a pipeline
+	Vars  map[string]string // --var NAME=VALUE: predefined variables
+}
+
+// errBreak and errContinue unwind loops.
+var (
+	errBreak    = errors.New("break outside loop")
+	errContinue = errors.New("continue outside loop")
+)
+
+// exitSignal unwinds the script on exit.
+type exitSignal struct{ code int }
+
+func (e *exitSignal) Error() string { return fmt.Sprintf("exit %d", e.code) }
+
+// RunScript runs the script in args[0] (or opts.Code) with the remaining
+// args available as the list variable args. Commands are dispatched through
+// runner.
+func RunScript(ctx context.Context, w io.Writer, runner command.Command, args []string, opts Options) error {
+	name, src := "-c", opts.Code
+
+	if src == "" {
+		if len(args) == 0 {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "script: no script file given")
+		}
+
+		name, args = args[0], args[1:]
+
+		data, err := os.ReadFile(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("script: %s", name))
+			}
+
+			return fmt.Errorf("script: %w", err)
+		}
+
+		src = string(data)
+	}
+
+	prog, err := parse(name, stripShebang(src))
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("script: %s", err))
+	}
+
+	in := newInterp(name, runner, w, opts)
+	in.vars["args"] = stringList(args)
+
+	// cd inside the script must not leak into the caller
+	if wd, err := os.Getwd(); err == nil {
+		defer func() { _ = os.Chdir(wd) }()
+	}
+
+	err = in.block(ctx, prog)
+
+	var exit *exitSignal
+
+	switch {
+	case errors.As(err, &exit):
+		if exit.code != 0 {
+			return cmderr.SilentExit(exit.code)
+		}
+
+		return nil
+	case errors.Is(err, errBreak), errors.Is(err, errContinue):
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("script: %s", err))
+	}
+
+	return err
+}
+
+// Check parses a script without running it.
+func Check(name, src string) error {
+	if _, err := parse(name, stripShebang(src)); err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("script: %s", err))
+	}
+
+	return nil
+}
+
+func stripShebang(src string) string {
+	if strings.HasPrefix(src, "#!") {
+		// Keep the newline so line numbers stay right
+		if i := strings.IndexByte(src, '\n'); i >= 0 {
+			return src[i:]
+		}
+
+		return ""
+	}
+
+	return src
+}
+
+type interp struct {
+	name   string
+	runner command.Command
+	w      io.Writer
+	stdin  io.Reader
+	vars   map[string]any
+}
+
+func newInterp(name string, runner command.Command, w io.Writer, opts Options) *interp {
+	in := &interp{
+		name:   name,
+		runner: runner,
+		w:      w,
+		stdin:  opts.Stdin,
+		vars: map[string]any{
+			"status": int64(0),
+			"os":     runtime.GOOS,
+			"arch":   runtime.GOARCH,
+		},
+	}
+
+	if in.stdin == nil {
+		in.stdin = strings.NewReader("")
+	}
+
+	for k, v := range opts.Vars {
+		in.vars[k] = v
+	}
+
+	return in
+}
+
+// runtimeError wraps err with the script position, keeping its class.
+func (in *interp) runtimeError(s stmt, err error) error {
+	var exit *exitSignal
+	if errors.Is(err, errBreak) || errors.Is(err, errContinue) || errors.As(err, &exit) {
+		return err
+	}
+
+	var silent *cmderr.SilentError
+	if errors.As(err, &silent) {
+		// grep-style "no match": report it like any other failure
+		return cmderr.WithExitCode(fmt.Errorf("script: %s:%d: command exited with status %d", in.name, s.stmtLine(), silent.Code), silent.Code)
+	}
+
+	if strings.HasPrefix(err.Error(), "script: ") {
+		return err
+	}
+
+	return fmt.Errorf("script: %s:%d: %w", in.name, s.stmtLine(), err)
+}
+
+func (in *interp) block(ctx context.Context, stmts []stmt) error {
+	for _, s := range stmts {
+		if err := ctx.Err(); err != nil {
+			return cmderr.Wrap(cmderr.ErrTimeout, fmt.Sprintf("script: %s", err))
+		}
+
+		if err := in.stmt(ctx, s); err != nil {
+			return in.runtimeError(s, err)
+		}
+	}
+
+	return nil
+}
+
+func (in *interp) stmt(ctx context.Context, s stmt) error {
+	switch s := s.(type) {
+	case *assignStmt:
+		v, err := in.eval(ctx, s.value)
+		if err != nil {
+			return err
+		}
+
+		in.vars[s.name] = v
+	case *exprStmt:
+		_, err := in.eval(ctx, s.x)
+		return err
+	case *ifStmt:
+		cond, err := in.eval(ctx, s.cond)
+		if err != nil {
+			return err
+		}
+
+		if truthy(cond) {
+			return in.block(ctx, s.then)
+		}
+
+		return in.block(ctx, s.els)
+	case *forStmt:
+		v, err := in.eval(ctx, s.list)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range iterate(v) {
+			in.vars[s.name] = item
+
+			if err := in.loopBody(ctx, s.body); err != nil {
+				if errors.Is(err, errBreak) {
+					return nil
+				}
+
+				return err
+			}
+		}
+	case *whileStmt:
+		for {
+			cond, err := in.eval(ctx, s.cond)
+			if err != nil {
+				return err
+			}
+
+			if !truthy(cond) {
+				return nil
+			}
+
+			if err := in.loopBody(ctx, s.body); err != nil {
+				if errors.Is(err, errBreak) {
+					return nil
+				}
+
+				return err
+			}
+		}
+	case *branchStmt:
+		if s.keyword == "break" {
+			return errBreak
+		}
+
+		return errContinue
+	case *exitStmt:
+		code := int64(0)
+
+		if s.code != nil {
+			v, err := in.eval(ctx, s.code)
+			if err != nil {
+				return err
+			}
+
+			if code, err = toInt(v); err != nil {
+				return fmt.Errorf("exit: %w", err)
+			}
+		}
+
+		return &exitSignal{code: int(code)}
+	case *failStmt:
+		v, err := in.eval(ctx, s.msg)
+		if err != nil {
+			return err
+		}
+
+		return cmderr.WithExitCode(fmt.Errorf("script: %s:%d: %s", in.name, s.line, toStr(v)), 1)
+	case *cdStmt:
+		args, err := in.expandWord(ctx, s.dir)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 1 {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "cd: expected one directory")
+		}
+
+		if err := os.Chdir(args[0]); err != nil {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("cd: %s", args[0]))
+		}
+	case *cmdStmt:
+		err := in.runPipeline(ctx, s.pipe, in.w)
+		in.vars["status"] = int64(cmderr.ExitCodeFor(err))
+
+		if s.try {
+			return nil
+		}
+
+		return err
+	default:
+		return fmt.Errorf("unknown statement %T", s)
+	}
+
+	return nil
+}
+
+// loopBody runs one loop iteration, absorbing continue.
+func (in *interp) loopBody(ctx context.Context, body []stmt) error {
+	if err := ctx.Err(); err != nil {
+		return cmderr.Wrap(cmderr.ErrTimeout, fmt.Sprintf("script: %s", err))
+	}
+
+	err := in.block(ctx, body)
+	if errors.Is(err, errContinue) {
+		return nil
+	}
+
+	return err
+}
+
+// runPipeline runs the commands of pl, feeding each one's output to the
+// next, and writes the last output to w (or the > redirection).
+func (in *interp) runPipeline(ctx context.Context, pl *pipeline, w io.Writer) error {
+	input := in.stdin
+
+	if pl.input != nil {
+		name, err := in.expandSingle(ctx, *pl.input)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("< %s", name))
+		}
+
+		defer func() { _ = f.Close() }()
+
+		input = f
+	}
+
+	out := w
+
+	if pl.output != nil {
+		name, err := in.expandSingle(ctx, *pl.output)
+		if err != nil {
+			return err
+		}
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if pl.append {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+
+		f, err := os.OpenFile(name, flags, 0o644)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("> %s: %s", name, err))
+		}
+
+		defer func() { _ = f.Close() }()
+
+		out = f
+	}
+
+	for i, words := range pl.cmds {
+		var args []string
+
+		for _, wd := range words {
+			expanded, err := in.expandWord(ctx, wd)
+			if err != nil {
+				return err
+			}
+
+			args = append(args, expanded...)
+		}
+
+		if len(args) == 0 {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "empty command")
+		}
+
+		if i == len(pl.cmds)-1 {
+			return in.runner.Run(ctx, out, input, args)
+		}
+
+		var buf bytes.Buffer
+		if err := in.runner.Run(ctx, &buf, input, args); err != nil {
+			return err
+		}
+
+		input = &buf
+	}
+
+	return nil
+}
+
+// expandWord evaluates a command word into zero or more arguments.
+func (in *interp) expandWord(ctx context.Context, wd word) ([]string, error) {
+	if wd.splat {
+		v, err := in.eval(ctx, wd.parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if list, ok := v.([]any); ok {
+			out := make([]string, len(list))
+			for i, item := range list {
+				out[i] = toStr(item)
+			}
+
+			return out, nil
+		}
+
+		return []string{toStr(v)}, nil
+	}
+
+	var sb strings.Builder
+
+	for _, part := range wd.parts {
+		v, err := in.eval(ctx, part)
+		if err != nil {
+			return nil, err
+		}
+
+		sb.WriteString(toStr(v))
+	}
+
+	return []string{sb.String()}, nil
+}
+
+func (in *interp) expandSingle(ctx context.Context, wd word) (string, error) {
+	args, err := in.expandWord(ctx, wd)
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) != 1 {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, "redirection needs exactly one file name")
+	}
+
+	return args[0], nil
+}
+
+func (in *interp) eval(ctx context.Context, x expr) (any, error) {
+	switch x := x.(type) {
+	case *literal:
+		return x.value, nil
+	case *varRef:
+		if v, ok := in.vars[x.name]; ok {
+			return v, nil
+		}
+
+		if x.env {
+			if v, ok := os.LookupEnv(x.name); ok {
+				return v, nil
+			}
+		}
+
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("undefined variable %s", x.name))
+	case *listExpr:
+		list := make([]any, 0, len(x.elems))
+
+		for _, e := range x.elems {
+			v, err := in.eval(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, v)
+		}
+
+		return list, nil
+	case *interpExpr:
+		var sb strings.Builder
+
+		for _, part := range x.parts {
+			v, err := in.eval(ctx, part)
+			if err != nil {
+				return nil, err
+			}
+
+			sb.WriteString(toStr(v))
+		}
+
+		return sb.String(), nil
+	case *unaryExpr:
+		v, err := in.eval(ctx, x.x)
+		if err != nil {
+			return nil, err
+		}
+
+		if x.op == "!" {
+			return !truthy(v), nil
+		}
+
+		n, err := toInt(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return -n, nil
+	case *binaryExpr:
+		return in.binary(ctx, x)
+	case *indexExpr:
+		v, err := in.eval(ctx, x.x)
+		if err != nil {
+			return nil, err
+		}
+
+		iv, err := in.eval(ctx, x.index)
+		if err != nil {
+			return nil, err
+		}
+
+		return index(v, iv)
+	case *callExpr:
+		fn, ok := builtins[x.name]
+		if !ok {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("unknown function %s", x.name))
+		}
+
+		args := make([]any, 0, len(x.args))
+
+		for _, a := range x.args {
+			v, err := in.eval(ctx, a)
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, v)
+		}
+
+		v, err := fn(in, args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", x.name, err)
+		}
+
+		return v, nil
+	case *captureExpr:
+		var buf bytes.Buffer
+
+		err := in.runPipeline(ctx, x.pipe, &buf)
+
+		if x.test {
+			in.vars["status"] = int64(cmderr.ExitCodeFor(err))
+			return err == nil, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.TrimRight(buf.String(), "\r\n"), nil
+	default:
+		return nil, fmt.Errorf("unknown expression %T", x)
+	}
+}
+
+func (in *interp) binary(ctx context.Context, x *binaryExpr) (any, error) {
+	l, err := in.eval(ctx, x.l)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit logical operators
+	switch x.op {
+	case "&&":
+		if !truthy(l) {
+			return false, nil
+		}
+
+		r, err := in.eval(ctx, x.r)
+
+		return truthy(r), err
+	case "||":
+		if truthy(l) {
+			return true, nil
+		}
+
+		r, err := in.eval(ctx, x.r)
+
+		return truthy(r), err
+	}
+
+	r, err := in.eval(ctx, x.r)
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryOp(x.op, l, r)
+}
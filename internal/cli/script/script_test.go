@@ -0,0 +1,236 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/command"
+)
+
+// fakeRunner implements echo, upper (stdin to upper case), cat, false and
+// nomatch (grep-style silent exit 1), and records every command line.
+type fakeRunner struct {
+	calls [][]string
+}
+
+func (f *fakeRunner) Run(_ context.Context, w io.Writer, r io.Reader, args []string) error {
+	f.calls = append(f.calls, args)
+
+	switch args[0] {
+	case "echo":
+		_, _ = io.WriteString(w, strings.Join(args[1:], " ")+"\n")
+	case "upper":
+		data, _ := io.ReadAll(r)
+		_, _ = io.WriteString(w, strings.ToUpper(string(data)))
+	case "cat":
+		_, _ = io.Copy(w, r)
+	case "false":
+		return cmderr.Wrap(cmderr.ErrNotFound, "false")
+	case "nomatch":
+		return cmderr.SilentExit(1)
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "unknown command: "+args[0])
+	}
+
+	return nil
+}
+
+var _ command.Command = (*fakeRunner)(nil)
+
+func runCode(t *testing.T, code string, args ...string) (string, *fakeRunner, error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	runner := &fakeRunner{}
+	err := RunScript(context.Background(), &buf, runner, args, Options{Code: code})
+
+	return buf.String(), runner, err
+}
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		args []string
+		want string
+	}{
+		{"echo", `echo hello world`, nil, "hello world\n"},
+		{"variables", "let name = \"omni\"\necho \"hi ${name}!\" $name", nil, "hi omni! omni\n"},
+		{"single quotes", `echo '$name stays'`, nil, "$name stays\n"},
+		{"arithmetic", "x = 2 + 3 * 4\necho $x", nil, "14\n"},
+		{"string concat", "x = \"a\" + 1\necho $x", nil, "a1\n"},
+		{"if else", "x = 3\nif x > 2 {\n echo big\n} else {\n echo small\n}", nil, "big\n"},
+		{"else if", "x = 2\nif x > 2 {\n echo big\n} else if x == 2 {\n echo two\n}", nil, "two\n"},
+		{"for list", "for d in [\"a\", \"b\"] {\n echo $d\n}", nil, "a\nb\n"},
+		{"for int", "for i in 3 {\n echo $i\n}", nil, "0\n1\n2\n"},
+		{"while break continue", "i = 0\nwhile true {\n i = i + 1\n if i == 2 { continue }\n if i > 3 { break }\n echo $i\n}", nil, "1\n3\n"},
+		{"args splat", `echo $args`, []string{"x", "y"}, "x y\n"},
+		{"pipe", `echo abc | upper`, nil, "ABC\n"},
+		{"capture", "v = $(echo a b | upper)\necho \"[${v}]\"", nil, "[A B]\n"},
+		{"capture test", "if $?(false) { echo yes } else { echo no }", nil, "no\n"},
+		{"builtins", "print(len(split(\"a,b,c\", \",\")), upper(\"x\"), join([1, 2], \"-\"))", nil, "3 X 1-2\n"},
+		{"index", "l = [1, 2, 3]\necho $(echo ${l[-1]})", nil, "3\n"},
+		{"comments", "# comment\necho a#b # trailing", nil, "a#b\n"},
+		{"try status", "try false\necho $status\ntry nomatch\necho $status", nil, "1\n1\n"},
+		{"logical", "if !false && (1 < 2 || x) { echo ok }", nil, "ok\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := runCode(t, tt.code, tt.args...)
+			if err != nil {
+				t.Fatalf("RunScript() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunScriptSplatKeepsArguments(t *testing.T) {
+	_, runner, err := runCode(t, `echo $args "${args}"`, "a b", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"echo", "a b", "c", "a b c"}
+	if got := runner.calls[0]; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestRunScriptErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		check func(error) bool
+		msg   string
+	}{
+		{"parse error", "if x {\necho", cmderr.IsInvalidInput, "-c:"},
+		{"unterminated string", `echo "abc`, cmderr.IsInvalidInput, "-c:1"},
+		{"undefined variable", "echo ok\necho $nope_undefined_var", cmderr.IsInvalidInput, "-c:2"},
+		{"failing command", "echo a\nfalse\necho b", cmderr.IsNotFound, "-c:2"},
+		{"division by zero", "x = 1 / 0", cmderr.IsInvalidInput, "division by zero"},
+		{"unknown function", "nope(1)", cmderr.IsInvalidInput, "unknown function nope"},
+		{"break outside loop", "break", cmderr.IsInvalidInput, "break outside loop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runCode(t, tt.code)
+			if err == nil {
+				t.Fatal("RunScript() succeeded, want error")
+			}
+
+			if !tt.check(err) {
+				t.Errorf("error = %v, wrong class", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestRunScriptStopsOnFailure(t *testing.T) {
+	out, _, err := runCode(t, "echo a\nnomatch\necho b")
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("exit code = %d, want 1 (err %v)", cmderr.ExitCodeFor(err), err)
+	}
+
+	if out != "a\n" {
+		t.Errorf("output = %q, want only the first echo", out)
+	}
+}
+
+func TestRunScriptExitAndFail(t *testing.T) {
+	out, _, err := runCode(t, "echo a\nexit 3\necho b")
+
+	var silent *cmderr.SilentError
+	if !errors.As(err, &silent) || silent.Code != 3 {
+		t.Errorf("exit 3: err = %v", err)
+	}
+
+	if out != "a\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	if _, _, err := runCode(t, "exit"); err != nil {
+		t.Errorf("exit: err = %v", err)
+	}
+
+	_, _, err = runCode(t, `fail "bad " + "thing"`)
+	if err == nil || !strings.Contains(err.Error(), "bad thing") || cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("fail: err = %v", err)
+	}
+}
+
+func TestRunScriptRedirectsAndCd(t *testing.T) {
+	dir := t.TempDir()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := "cd " + filepath.ToSlash(dir) + "\necho one > out.txt\necho two >> out.txt\ncat < out.txt | upper"
+
+	out, _, err := runCode(t, code)
+	if err != nil {
+		t.Fatalf("RunScript() error = %v", err)
+	}
+
+	if out != "ONE\nTWO\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	if now, _ := os.Getwd(); now != wd {
+		t.Errorf("working directory not restored: %s", now)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "out.txt")); err != nil {
+		t.Errorf("redirect target not created: %v", err)
+	}
+}
+
+func TestRunScriptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.omni")
+	if err := os.WriteFile(path, []byte("#!/usr/bin/env omni script run\necho $args\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RunScript(context.Background(), &buf, &fakeRunner{}, []string{path, "x"}, Options{}); err != nil {
+		t.Fatalf("RunScript() error = %v", err)
+	}
+
+	if buf.String() != "x\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	err := RunScript(context.Background(), &buf, &fakeRunner{}, []string{filepath.Join(t.TempDir(), "missing.omni")}, Options{})
+	if !cmderr.IsNotFound(err) {
+		t.Errorf("missing file: err = %v, want not found", err)
+	}
+}
+
+func TestRunScriptCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := RunScript(ctx, io.Discard, &fakeRunner{}, nil, Options{Code: "while true { x = 1 }"})
+	if !cmderr.IsTimeout(err) {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
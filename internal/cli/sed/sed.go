@@ -10,7 +10,9 @@ import (
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
 	"github.com/inovacc/omni/internal/cli/input"
+	"github.com/inovacc/omni/pkg/textutil"
 )
 
 // SedOptions configures the sed command behavior
@@ -20,6 +22,8 @@ type SedOptions struct {
 	InPlaceExt string   // -i extension: backup extension for in-place edit
 	Quiet      bool     // -n: suppress automatic printing of pattern space
 	Extended   bool     // -E/-r: use extended regular expressions
+
+	Confirm confirm.Options // --dry-run, --interactive, -y/--yes for -i
 }
 
 // RunSed performs stream editing on input
@@ -38,7 +42,7 @@ func RunSed(w io.Writer, r io.Reader, args []string, opts SedOptions) error {
 	var commands []sedCommand
 
 	for _, expr := range opts.Expression {
-		cmd, err := parseSedExpression(expr)
+		cmd, err := parseSedExpression(expr, opts.Extended)
 		if err != nil {
 			return fmt.Errorf("sed: %w", err)
 		}
@@ -52,12 +56,14 @@ func RunSed(w io.Writer, r io.Reader, args []string, opts SedOptions) error {
 			return cmderr.Wrap(cmderr.ErrInvalidInput, "sed: no input files for in-place editing")
 		}
 
+		c := confirm.New("sed", opts.Confirm)
+
 		for _, file := range args {
 			if file == "-" {
 				return cmderr.Wrap(cmderr.ErrInvalidInput, "sed: cannot do in-place editing on stdin")
 			}
 
-			if err := sedProcessInPlace(file, commands, opts); err != nil {
+			if err := sedProcessInPlace(file, commands, opts, c); err != nil {
 				return err
 			}
 		}
@@ -88,7 +94,7 @@ type sedCommand interface {
 // substitution command: s/pattern/replacement/flags
 type sedSubstitute struct {
 	pattern     *regexp.Regexp
-	replacement string
+	replacement *textutil.Replacement
 	global      bool
 	printOnly   bool
 	nthMatch    int
@@ -96,27 +102,14 @@ type sedSubstitute struct {
 
 func (s *sedSubstitute) execute(line string, _ int) (string, bool) {
 	var result string
-	if s.global {
-		result = s.pattern.ReplaceAllString(line, s.replacement)
-	} else if s.nthMatch > 0 {
-		// Replace nth occurrence
-		count := 0
-		result = s.pattern.ReplaceAllStringFunc(line, func(match string) string {
-			count++
-			if count == s.nthMatch {
-				return s.pattern.ReplaceAllString(match, s.replacement)
-			}
 
-			return match
-		})
-	} else {
-		// Replace first occurrence only
-		loc := s.pattern.FindStringIndex(line)
-		if loc != nil {
-			result = line[:loc[0]] + s.pattern.ReplaceAllString(line[loc[0]:loc[1]], s.replacement) + line[loc[1]:]
-		} else {
-			result = line
-		}
+	switch {
+	case s.global:
+		result = s.replacement.ReplaceAll(s.pattern, line)
+	case s.nthMatch > 0:
+		result = s.replacement.ReplaceNth(s.pattern, line, s.nthMatch)
+	default:
+		result = s.replacement.ReplaceNth(s.pattern, line, 1)
 	}
 
 	return result, !s.printOnly
@@ -171,7 +164,7 @@ func (q *sedQuit) execute(line string, _ int) (string, bool) {
 	return line, true
 }
 
-func parseSedExpression(expr string) (sedCommand, error) {
+func parseSedExpression(expr string, extended bool) (sedCommand, error) {
 	expr = strings.TrimSpace(expr)
 
 	// Check for address prefix
@@ -182,11 +175,9 @@ func parseSedExpression(expr string) (sedCommand, error) {
 			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "sed: unterminated address regex")
 		}
 
-		pattern := expr[1 : end+1]
-
-		re, err := regexp.Compile(pattern)
+		re, err := compileRegex(expr[1:end+1], extended)
 		if err != nil {
-			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sed: invalid regex: %s", err))
+			return nil, err
 		}
 
 		rest := strings.TrimSpace(expr[end+2:])
@@ -234,7 +225,7 @@ func parseSedExpression(expr string) (sedCommand, error) {
 
 	// Substitution command
 	if len(expr) > 0 && expr[0] == 's' {
-		return parseSubstitute(expr)
+		return parseSubstitute(expr, extended)
 	}
 
 	// Simple commands
@@ -253,34 +244,38 @@ func parseSedExpression(expr string) (sedCommand, error) {
 	return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sed: unknown command: %s", expr))
 }
 
-func parseSubstitute(expr string) (*sedSubstitute, error) {
+func parseSubstitute(expr string, extended bool) (*sedSubstitute, error) {
 	if len(expr) < 4 || expr[0] != 's' {
 		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "sed: invalid substitution")
 	}
 
-	delim := expr[1]
-
-	parts := strings.Split(expr[2:], string(delim))
+	parts := splitSubstitute(expr[2:], expr[1])
 	if len(parts) < 2 {
 		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "sed: invalid substitution")
 	}
 
-	pattern := parts[0]
-	replacement := parts[1]
-
 	flags := ""
 	if len(parts) > 2 {
 		flags = parts[2]
 	}
 
-	re, err := regexp.Compile(pattern)
+	re, err := compileRegex(parts[0], extended)
 	if err != nil {
-		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sed: invalid regex: %s", err))
+		return nil, err
+	}
+
+	repl, err := textutil.ParseReplacement(parts[1])
+	if err == nil {
+		err = repl.Check(re)
+	}
+
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, err.Error())
 	}
 
 	sub := &sedSubstitute{
 		pattern:     re,
-		replacement: replacement,
+		replacement: repl,
 	}
 
 	for _, f := range flags {
@@ -297,6 +292,51 @@ func parseSubstitute(expr string) (*sedSubstitute, error) {
 	return sub, nil
 }
 
+// splitSubstitute splits the body of an s command on delim. An escaped
+// delimiter loses its backslash and stays in its part; other escapes are
+// kept for the regex and replacement parsers.
+func splitSubstitute(s string, delim byte) []string {
+	var (
+		parts   []string
+		current strings.Builder
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+
+			if s[i] != delim {
+				current.WriteByte('\\')
+			}
+
+			current.WriteByte(s[i])
+		case s[i] == delim:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+// compileRegex compiles a sed regex. Without -E it is a basic regex, so
+// \( \) and the other escaped operators group and repeat as in GNU sed.
+func compileRegex(pattern string, extended bool) (*regexp.Regexp, error) {
+	if !extended {
+		pattern = textutil.TranslateBRE(pattern)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sed: invalid regex: %s", err))
+	}
+
+	return re, nil
+}
+
 func sedProcessReader(w io.Writer, r io.Reader, commands []sedCommand, opts SedOptions) error {
 	scanner := bufio.NewScanner(r)
 	lineNum := 0
@@ -331,7 +371,7 @@ func sedProcessReader(w io.Writer, r io.Reader, commands []sedCommand, opts SedO
 	return scanner.Err()
 }
 
-func sedProcessInPlace(path string, commands []sedCommand, opts SedOptions) error {
+func sedProcessInPlace(path string, commands []sedCommand, opts SedOptions, c *confirm.Confirmer) error {
 	// Stat the original file first so we can preserve its permission bits
 	// when writing the backup sidecar and the rewritten target. Falling back
 	// to 0600 (rather than a world-readable 0644) avoids widening access if
@@ -347,14 +387,6 @@ func sedProcessInPlace(path string, commands []sedCommand, opts SedOptions) erro
 		return err
 	}
 
-	// Create backup if extension specified
-	if opts.InPlaceExt != "" {
-		backupPath := path + opts.InPlaceExt
-		if err := os.WriteFile(backupPath, content, mode); err != nil {
-			return err
-		}
-	}
-
 	// Process lines
 	var output strings.Builder
 
@@ -381,6 +413,24 @@ func sedProcessInPlace(path string, commands []sedCommand, opts SedOptions) erro
 		}
 	}
 
+	// Only files the script changes are previewed or prompted for
+	if output.String() != string(content) {
+		ok, err := c.Confirm(fmt.Sprintf("edit '%s' in place", path))
+		if err != nil || !ok {
+			return err
+		}
+	} else if c.DryRun() {
+		return nil
+	}
+
+	// Create backup if extension specified
+	if opts.InPlaceExt != "" {
+		backupPath := path + opts.InPlaceExt
+		if err := os.WriteFile(backupPath, content, mode); err != nil {
+			return err
+		}
+	}
+
 	// Write back, preserving the original file's permission bits.
 	return os.WriteFile(path, []byte(output.String()), mode)
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 // TestRunSedCommands exercises the p (sedPrint.execute) and q (sedQuit.execute)
@@ -86,6 +88,38 @@ func TestRunSedCommands(t *testing.T) {
 	}
 }
 
+func TestRunSedReplacementEscapes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SedOptions
+		in   string
+		want string
+	}{
+		{"basic regex groups", SedOptions{Expression: []string{`s/\(\w*\) \(\w*\)/\2 \1/`}}, "hello world\n", "world hello\n"},
+		{"extended regex groups", SedOptions{Expression: []string{`s/(\w+) (\w+)/\2 \1/`}, Extended: true}, "hello world\n", "world hello\n"},
+		{"escaped paren with -E", SedOptions{Expression: []string{`s/\(x\)/[&]/`}, Extended: true}, "a(x)b\n", "a[(x)]b\n"},
+		{"whole match", SedOptions{Expression: []string{`s/[0-9]\+/<&>/g`}}, "a1b22\n", "a<1>b<22>\n"},
+		{"capitalize words", SedOptions{Expression: []string{`s/\w\+/\u&/g`}}, "foo bar\n", "Foo Bar\n"},
+		{"upper until end", SedOptions{Expression: []string{`s/\(\w*\) \(\w*\)/\U\1\E \2/`}}, "foo bar\n", "FOO bar\n"},
+		{"lower", SedOptions{Expression: []string{`s/.*/\L&/`}}, "MiXeD\n", "mixed\n"},
+		{"escaped delimiter", SedOptions{Expression: []string{`s/a\/b/a\/\&/`}}, "a/b\n", "a/&\n"},
+		{"nth match with group", SedOptions{Expression: []string{`s/\(o\)/[\1]/2`}}, "foo\n", "fo[o]\n"},
+		{"address in basic regex", SedOptions{Expression: []string{`/^\(a\|b\)$/d`}}, "a\nc\nb\n", "c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RunSed(&buf, strings.NewReader(tt.in), nil, tt.opts); err != nil {
+				t.Fatalf("RunSed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("RunSed output = %q want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
 func TestRunSedErrors(t *testing.T) {
 	var buf bytes.Buffer
 	if err := RunSed(&buf, strings.NewReader("x\n"), nil, SedOptions{}); err == nil {
@@ -94,4 +128,7 @@ func TestRunSedErrors(t *testing.T) {
 	if err := RunSed(&buf, strings.NewReader("x\n"), nil, SedOptions{Expression: []string{"z"}}); err == nil {
 		t.Fatal("expected error on unknown command")
 	}
+	if err := RunSed(&buf, strings.NewReader("x\n"), nil, SedOptions{Expression: []string{`s/\(x\)/\2/`}}); !cmderr.IsInvalidInput(err) {
+		t.Fatalf("reference to a missing group: err = %v, want invalid input", err)
+	}
 }
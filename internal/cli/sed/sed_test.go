@@ -3,6 +3,7 @@ package sed
 import (
 	"bytes"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -10,6 +11,8 @@ import (
 	"testing"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/confirm"
+	"github.com/inovacc/omni/pkg/textutil"
 )
 
 func TestRunSed_InPlaceUsageErrors_AreInvalidInput(t *testing.T) {
@@ -232,7 +235,7 @@ func TestParseSubstitute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.expr, func(t *testing.T) {
-			sub, err := parseSubstitute(tt.expr)
+			sub, err := parseSubstitute(tt.expr, false)
 
 			if tt.hasErr {
 				if err == nil {
@@ -257,7 +260,7 @@ func TestSedSubstitute_execute(t *testing.T) {
 	t.Run("first occurrence", func(t *testing.T) {
 		sub := &sedSubstitute{
 			pattern:     regexp.MustCompile("a"),
-			replacement: "X",
+			replacement: mustReplacement(t, "X"),
 		}
 
 		result, _ := sub.execute("aaa", 1)
@@ -269,7 +272,7 @@ func TestSedSubstitute_execute(t *testing.T) {
 	t.Run("global", func(t *testing.T) {
 		sub := &sedSubstitute{
 			pattern:     regexp.MustCompile("a"),
-			replacement: "X",
+			replacement: mustReplacement(t, "X"),
 			global:      true,
 		}
 
@@ -282,7 +285,7 @@ func TestSedSubstitute_execute(t *testing.T) {
 	t.Run("nth occurrence", func(t *testing.T) {
 		sub := &sedSubstitute{
 			pattern:     regexp.MustCompile("a"),
-			replacement: "X",
+			replacement: mustReplacement(t, "X"),
 			nthMatch:    2,
 		}
 
@@ -293,6 +296,17 @@ func TestSedSubstitute_execute(t *testing.T) {
 	})
 }
 
+func mustReplacement(t *testing.T, s string) *textutil.Replacement {
+	t.Helper()
+
+	r, err := textutil.ParseReplacement(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
 func TestSedDelete_execute(t *testing.T) {
 	t.Run("pattern match", func(t *testing.T) {
 		del := &sedDelete{pattern: regexp.MustCompile("delete")}
@@ -322,3 +336,35 @@ func TestSedDelete_execute(t *testing.T) {
 		}
 	})
 }
+
+func TestRunSedInPlaceConfirm(t *testing.T) {
+	dir := t.TempDir()
+	changed := filepath.Join(dir, "changed.txt")
+	same := filepath.Join(dir, "same.txt")
+	_ = os.WriteFile(changed, []byte("foo\n"), 0644)
+	_ = os.WriteFile(same, []byte("baz\n"), 0644)
+
+	var out bytes.Buffer
+
+	opts := SedOptions{InPlace: true, Confirm: confirm.Options{DryRun: true, Stdout: &out}}
+	if err := RunSed(io.Discard, nil, []string{"s/foo/bar/", changed, same}, opts); err != nil {
+		t.Fatalf("RunSed(dry-run) error = %v", err)
+	}
+
+	if want := "sed: would edit '" + changed + "' in place\n"; out.String() != want {
+		t.Errorf("dry-run output = %q, want %q (unchanged files are not listed)", out.String(), want)
+	}
+
+	if data, _ := os.ReadFile(changed); string(data) != "foo\n" {
+		t.Errorf("dry run rewrote the file: %q", data)
+	}
+
+	opts = SedOptions{InPlace: true, Confirm: confirm.Options{Interactive: true, Stdin: strings.NewReader("n\n"), Stderr: io.Discard, AssumeTTY: true}}
+	if err := RunSed(io.Discard, nil, []string{"s/foo/bar/", changed}, opts); err != nil {
+		t.Fatalf("RunSed(declined) error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(changed); string(data) != "foo\n" {
+		t.Errorf("declined edit rewrote the file: %q", data)
+	}
+}
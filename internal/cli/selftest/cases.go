@@ -0,0 +1,196 @@
+package selftest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// fruits is the shared input of the text cases.
+const fruits = "banana\napple\ncherry\napple\n"
+
+// Cases returns the built-in conformance cases, grouped by module. They
+// mirror the integration tests of the same commands, restricted to what
+// runs offline in a temporary directory.
+func Cases() []Case {
+	in := map[string]string{"f.txt": fruits}
+
+	return []Case{
+		// text
+		{Module: "text", Name: "cat-number", Files: in, Args: []string{"cat", "-n", "{dir}/f.txt"},
+			Want: "     1\tbanana\n     2\tapple\n     3\tcherry\n     4\tapple\n"},
+		{Module: "text", Name: "head", Files: in, Args: []string{"head", "-n", "2", "{dir}/f.txt"}, Want: "banana\napple\n"},
+		{Module: "text", Name: "tail", Files: in, Args: []string{"tail", "-n", "1", "{dir}/f.txt"}, Want: "apple\n"},
+		{Module: "text", Name: "sort-unique", Files: in, Args: []string{"sort", "-u", "{dir}/f.txt"}, Want: "apple\nbanana\ncherry\n"},
+		{Module: "text", Name: "wc-lines", Files: in, Args: []string{"wc", "-l", "{dir}/f.txt"}, Want: "      4 {dir}/f.txt\n"},
+		{Module: "text", Name: "cut", Stdin: "a,b,c\n", Args: []string{"cut", "-d", ",", "-f", "2"}, Want: "b\n"},
+		{Module: "text", Name: "tr", Stdin: "hello\n", Args: []string{"tr", "a-z", "A-Z"}, Want: "HELLO\n"},
+		{Module: "text", Name: "sed", Files: in, Args: []string{"sed", "s/apple/pear/g", "{dir}/f.txt"}, Want: "banana\npear\ncherry\npear\n"},
+		{Module: "text", Name: "grep-count", Files: in, Args: []string{"grep", "-c", "apple", "{dir}/f.txt"}, Want: "2\n"},
+		{Module: "text", Name: "rev", Stdin: "abc\n", Args: []string{"rev"}, Want: "cba\n"},
+		{Module: "text", Name: "tac", Files: in, Args: []string{"tac", "{dir}/f.txt"}, Want: "apple\ncherry\napple\nbanana\n"},
+		{Module: "text", Name: "seq", Args: []string{"seq", "3"}, Want: "1\n2\n3\n"},
+
+		// fs
+		{Module: "fs", Name: "mkdir-parents", Args: []string{"mkdir", "-p", "{dir}/a/b/c"}, Check: isDir("a/b/c")},
+		{Module: "fs", Name: "touch", Args: []string{"touch", "{dir}/new.txt"}, Check: hasFile("new.txt", "")},
+		{Module: "fs", Name: "cp", Files: in, Args: []string{"cp", "{dir}/f.txt", "{dir}/g.txt"}, Check: hasFile("g.txt", fruits)},
+		{Module: "fs", Name: "mv", Files: in, Args: []string{"mv", "{dir}/f.txt", "{dir}/g.txt"},
+			Check: all(hasFile("g.txt", fruits), missing("f.txt"))},
+		{Module: "fs", Name: "rm", Files: in, Args: []string{"rm", "{dir}/f.txt"}, Check: missing("f.txt")},
+		{Module: "fs", Name: "ls", Files: map[string]string{"b.txt": "", "a.txt": "", "sub/c.txt": ""},
+			Args: []string{"ls", "-1", "{dir}"}, Want: "a.txt\nb.txt\nsub\n"},
+		{Module: "fs", Name: "find-name", Files: map[string]string{"a.go": "", "sub/b.go": "", "sub/c.txt": ""},
+			Args: []string{"find", "{dir}", "--name", "*.go"}, Check: sameLines("{dir}/a.go\n{dir}/sub/b.go\n")},
+		{Module: "fs", Name: "basename", Args: []string{"basename", "/a/b/c.txt", ".txt"}, Want: "c\n"},
+		{Module: "fs", Name: "dirname", Args: []string{"dirname", "/a/b/c.txt"}, Want: "/a/b\n"},
+
+		// encoding
+		{Module: "encoding", Name: "base64-encode", Files: map[string]string{"in.txt": "hello"},
+			Args: []string{"base64", "{dir}/in.txt"}, Want: "aGVsbG8=\n"},
+		{Module: "encoding", Name: "base64-decode", Files: map[string]string{"in.b64": "aGVsbG8=\n"},
+			Args: []string{"base64", "-d", "{dir}/in.b64"}, Want: "hello"},
+		{Module: "encoding", Name: "hex-encode", Args: []string{"hex", "encode", "hi"}, Want: "6869\n"},
+		{Module: "encoding", Name: "url-encode", Args: []string{"url", "encode", "a b&c"}, Want: "a%20b&c\n"},
+
+		// hash
+		{Module: "hash", Name: "sha256sum", Files: in, Args: []string{"sha256sum", "{dir}/f.txt"},
+			Want: "f436c825fec3a18c8105983f22374edf4366b4fb2f525d82a3a91dadf6911fa1  {dir}/f.txt\n"},
+
+		// data
+		{Module: "data", Name: "jq-path", Files: map[string]string{"j.json": `{"a":{"b":[1,2]}}`},
+			Args: []string{"jq", "-c", ".a.b", "{dir}/j.json"}, Want: "[1,2]\n"},
+		{Module: "data", Name: "jq-raw", Files: map[string]string{"j.json": `{"a":{"b":[1,"x"]}}`},
+			Args: []string{"jq", "-r", ".a.b[1]", "{dir}/j.json"}, Want: "x\n"},
+		{Module: "data", Name: "yaml-validate", Files: map[string]string{"y.yaml": "a: 1\n"},
+			Args: []string{"yaml", "validate", "{dir}/y.yaml"}, Check: contains("valid YAML")},
+
+		// archive
+		{Module: "archive", Name: "gzip-keep", Files: in, Args: []string{"gzip", "-k", "{dir}/f.txt"},
+			Check: all(exists("f.txt.gz"), hasFile("f.txt", fruits))},
+		{Module: "archive", Name: "zcat", Files: map[string]string{"f.txt.gz": gzipString(fruits)},
+			Args: []string{"zcat", "{dir}/f.txt.gz"}, Want: fruits},
+
+		// id
+		{Module: "id", Name: "uuid-v4", Args: []string{"uuid"}, Check: matches(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\n$`)},
+		{Module: "id", Name: "uuid-v7", Args: []string{"uuid", "-v", "7"}, Check: matches(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\n$`)},
+		{Module: "id", Name: "ulid", Args: []string{"ulid"}, Check: matches(`^[0-7][0-9A-HJKMNP-TV-Z]{25}\n$`)},
+		{Module: "id", Name: "ksuid", Args: []string{"ksuid"}, Check: matches(`^[0-9A-Za-z]{27}\n$`)},
+		{Module: "id", Name: "snowflake", Args: []string{"snowflake", "-w", "7"}, Check: matches(`^[1-9][0-9]{14,18}\n$`)},
+	}
+}
+
+// gzipString returns s compressed with gzip.
+func gzipString(s string) string {
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(s))
+	_ = zw.Close()
+
+	return buf.String()
+}
+
+func isDir(name string) func(dir, out string) error {
+	return func(dir, _ string) error {
+		fi, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+
+		if !fi.IsDir() {
+			return fmt.Errorf("%s is not a directory", name)
+		}
+
+		return nil
+	}
+}
+
+func exists(name string) func(dir, out string) error {
+	return func(dir, _ string) error {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		return err
+	}
+}
+
+func hasFile(name, content string) func(dir, out string) error {
+	return func(dir, _ string) error {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+
+		if string(data) != content {
+			return fmt.Errorf("%s = %q, want %q", name, data, content)
+		}
+
+		return nil
+	}
+}
+
+func missing(name string) func(dir, out string) error {
+	return func(dir, _ string) error {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s still exists", name)
+		}
+
+		return nil
+	}
+}
+
+func contains(s string) func(dir, out string) error {
+	return func(_, out string) error {
+		if !strings.Contains(out, s) {
+			return fmt.Errorf("stdout = %q, want it to contain %q", out, s)
+		}
+
+		return nil
+	}
+}
+
+func matches(pattern string) func(dir, out string) error {
+	re := regexp.MustCompile(pattern)
+
+	return func(_, out string) error {
+		if !re.MatchString(out) {
+			return fmt.Errorf("stdout = %q, want a match for %s", out, pattern)
+		}
+
+		return nil
+	}
+}
+
+// sameLines compares stdout with want, "{dir}" expanded, ignoring line order.
+func sameLines(want string) func(dir, out string) error {
+	return func(dir, out string) error {
+		got := strings.Split(strings.TrimSpace(out), "\n")
+		exp := strings.Split(strings.TrimSpace(filepath.ToSlash(strings.ReplaceAll(want, "{dir}", dir))), "\n")
+
+		slices.Sort(got)
+		slices.Sort(exp)
+
+		if strings.Join(got, "\n") != strings.Join(exp, "\n") {
+			return fmt.Errorf("stdout lines = %q, want %q", got, exp)
+		}
+
+		return nil
+	}
+}
+
+func all(checks ...func(dir, out string) error) func(dir, out string) error {
+	return func(dir, out string) error {
+		for _, check := range checks {
+			if err := check(dir, out); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,278 @@
+// Package selftest implements `omni selftest`, which runs a curated set of
+// conformance cases against the commands of the running binary, in-process,
+// and reports pass/fail per module. Operators run it on a target machine
+// (an unusual OS, architecture or filesystem) to check that a deployed
+// binary behaves before relying on it. Cases only touch a temporary
+// directory and never use the network.
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// Executor runs one omni command line, given without the program name,
+// with stdin and stdout attached to the given reader and writer.
+type Executor func(ctx context.Context, stdin io.Reader, stdout io.Writer, args []string) error
+
+// Case is one conformance check: it writes Files into a fresh temporary
+// directory, runs Args with Stdin and compares stdout with Want, then calls
+// Check when it is set. Every "{dir}" in Files keys, Args, Want and the
+// expected files is replaced by the temporary directory.
+type Case struct {
+	Module string
+	Name   string
+	Files  map[string]string
+	Args   []string
+	Stdin  string
+	Want   string
+	Check  func(dir, out string) error
+}
+
+// Status is the outcome of a case or module.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+)
+
+// CaseResult is the outcome of one case.
+type CaseResult struct {
+	Name     string  `json:"name"`
+	Status   Status  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"durationMs"`
+}
+
+// ModuleResult groups the case results of one module.
+type ModuleResult struct {
+	Module string       `json:"module"`
+	Status Status       `json:"status"`
+	Passed int          `json:"passed"`
+	Failed int          `json:"failed"`
+	Cases  []CaseResult `json:"cases"`
+}
+
+// Report is the selftest output for JSON
+type Report struct {
+	Platform string         `json:"platform"`
+	Modules  []ModuleResult `json:"modules"`
+	Passed   int            `json:"passed"`
+	Failed   int            `json:"failed"`
+}
+
+// Options configures the selftest command behavior
+type Options struct {
+	Modules      []string      // --module: only run these modules
+	List         bool          // --list: print the cases instead of running them
+	Verbose      bool          // -v: print every case, not just failures
+	Cases        []Case        // cases to run (default: Cases())
+	Executor     Executor      // runs the commands; required unless List
+	OutputFormat output.Format // output format (text, json)
+}
+
+// RunSelftest runs the selected cases one after another and prints a report
+// per module. It exits with status 1 when a case fails.
+func RunSelftest(ctx context.Context, w io.Writer, opts Options) error {
+	cases := opts.Cases
+	if cases == nil {
+		cases = Cases()
+	}
+
+	selected, err := selectCases(cases, opts.Modules)
+	if err != nil {
+		return err
+	}
+
+	f := output.New(w, opts.OutputFormat)
+
+	if opts.List {
+		if f.IsJSON() {
+			names := make([]string, len(selected))
+			for i, c := range selected {
+				names[i] = c.Module + "/" + c.Name
+			}
+
+			return f.Print(names)
+		}
+
+		for _, c := range selected {
+			_, _ = fmt.Fprintf(w, "%s/%s\t%s\n", c.Module, c.Name, strings.Join(c.Args, " "))
+		}
+
+		return nil
+	}
+
+	if opts.Executor == nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "selftest: no executor")
+	}
+
+	root, err := os.MkdirTemp("", "omni-selftest-")
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("selftest: %v", err))
+	}
+
+	defer func() { _ = os.RemoveAll(root) }()
+
+	report := Report{Platform: runtime.GOOS + "/" + runtime.GOARCH}
+
+	for i, c := range selected {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if i == 0 || c.Module != selected[i-1].Module {
+			report.Modules = append(report.Modules, ModuleResult{Module: c.Module, Status: StatusPass})
+		}
+
+		mod := &report.Modules[len(report.Modules)-1]
+
+		start := time.Now()
+		res := CaseResult{Name: c.Name, Status: StatusPass}
+
+		if err := runCase(ctx, opts.Executor, filepath.Join(root, fmt.Sprintf("%03d", i)), c); err != nil {
+			res.Status, res.Error = StatusFail, err.Error()
+			mod.Status = StatusFail
+			mod.Failed++
+			report.Failed++
+		} else {
+			mod.Passed++
+			report.Passed++
+		}
+
+		res.Duration = float64(time.Since(start).Microseconds()) / 1000
+		mod.Cases = append(mod.Cases, res)
+	}
+
+	if f.IsJSON() {
+		if err := f.Print(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(w, report, opts.Verbose)
+	}
+
+	if report.Failed > 0 {
+		return cmderr.SilentExit(1)
+	}
+
+	return nil
+}
+
+// selectCases keeps the cases of the given modules, grouped by module in
+// the order the modules first appear.
+func selectCases(cases []Case, modules []string) ([]Case, error) {
+	var order []string
+
+	for _, c := range cases {
+		if !slices.Contains(order, c.Module) {
+			order = append(order, c.Module)
+		}
+	}
+
+	for _, m := range modules {
+		if !slices.Contains(order, m) {
+			return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("selftest: unknown module %q (have %s)", m, strings.Join(order, ", ")))
+		}
+	}
+
+	var selected []Case
+
+	for _, m := range order {
+		if len(modules) > 0 && !slices.Contains(modules, m) {
+			continue
+		}
+
+		for _, c := range cases {
+			if c.Module == m {
+				selected = append(selected, c)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+func runCase(ctx context.Context, exec Executor, dir string, c Case) (err error) {
+	// A panicking command fails its case instead of the whole run.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	expand := func(s string) string { return strings.ReplaceAll(s, "{dir}", dir) }
+
+	for name, content := range c.Files {
+		path := filepath.Join(dir, filepath.FromSlash(expand(name)))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = expand(a)
+	}
+
+	var stdout bytes.Buffer
+
+	if err := exec(ctx, strings.NewReader(c.Stdin), &stdout, args); err != nil {
+		return fmt.Errorf("omni %s: %w", strings.Join(c.Args, " "), err)
+	}
+
+	out := filepath.ToSlash(stdout.String())
+
+	if want := filepath.ToSlash(expand(c.Want)); c.Check == nil && out != want {
+		return fmt.Errorf("stdout = %q, want %q", out, want)
+	}
+
+	if c.Check != nil {
+		return c.Check(dir, out)
+	}
+
+	return nil
+}
+
+func printReport(w io.Writer, report Report, verbose bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STATUS\tMODULE\tPASSED")
+
+	for _, m := range report.Modules {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d/%d\n", m.Status, m.Module, m.Passed, m.Passed+m.Failed)
+
+		for _, c := range m.Cases {
+			switch {
+			case c.Status == StatusFail:
+				_, _ = fmt.Fprintf(tw, "\t\t%s: %s\n", c.Name, c.Error)
+			case verbose:
+				_, _ = fmt.Fprintf(tw, "\t\t%s: ok (%.1fms)\n", c.Name, c.Duration)
+			}
+		}
+	}
+
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(w, "\n%d passed, %d failed on %s\n", report.Passed, report.Failed, report.Platform)
+}
@@ -0,0 +1,164 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+// echoExecutor is a fake omni: "echo" prints its arguments, "cat" copies
+// stdin, "write" creates the file named by its argument, "boom" panics and
+// anything else fails.
+func echoExecutor(_ context.Context, stdin io.Reader, stdout io.Writer, args []string) error {
+	switch args[0] {
+	case "echo":
+		_, err := io.WriteString(stdout, strings.Join(args[1:], " ")+"\n")
+		return err
+	case "cat":
+		_, err := io.Copy(stdout, stdin)
+		return err
+	case "write":
+		return os.WriteFile(args[1], []byte("x"), 0o644)
+	case "boom":
+		panic("boom")
+	default:
+		return errors.New("unknown command")
+	}
+}
+
+func testCases() []Case {
+	return []Case{
+		{Module: "a", Name: "echo", Args: []string{"echo", "hi"}, Want: "hi\n"},
+		{Module: "b", Name: "cat", Stdin: "in\n", Args: []string{"cat"}, Want: "in\n"},
+		{Module: "a", Name: "dir", Files: map[string]string{"sub/f.txt": "x"}, Args: []string{"echo", "{dir}/sub/f.txt"},
+			Want: "{dir}/sub/f.txt\n", Check: hasFile("sub/f.txt", "x")},
+		{Module: "b", Name: "write", Args: []string{"write", "{dir}/out"}, Check: exists("out")},
+	}
+}
+
+func TestRunSelftestPass(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := RunSelftest(context.Background(), &buf, Options{Cases: testCases(), Executor: echoExecutor, Verbose: true})
+	if err != nil {
+		t.Fatalf("RunSelftest() error = %v\n%s", err, buf.String())
+	}
+
+	out := buf.String()
+	for _, want := range []string{"pass    a       2/2", "pass    b       2/2", "dir: ok", "4 passed, 0 failed on "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if strings.Index(out, "echo: ok") > strings.Index(out, "cat: ok") {
+		t.Errorf("cases not grouped by module:\n%s", out)
+	}
+}
+
+func TestRunSelftestFailures(t *testing.T) {
+	cases := append(testCases(),
+		Case{Module: "b", Name: "wrong", Args: []string{"echo", "x"}, Want: "y\n"},
+		Case{Module: "b", Name: "error", Args: []string{"nope"}},
+		Case{Module: "b", Name: "panic", Args: []string{"boom"}},
+	)
+
+	var buf bytes.Buffer
+
+	err := RunSelftest(context.Background(), &buf, Options{Cases: cases, Executor: echoExecutor})
+
+	var exit *cmderr.SilentError
+	if !errors.As(err, &exit) || exit.Code != 1 {
+		t.Fatalf("RunSelftest() error = %v, want exit status 1", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"pass    a", "fail    b       2/5", `wrong: stdout = "x\n", want "y\n"`, "error: omni nope: unknown command", "panic: panic: boom", "4 passed, 3 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "echo: ok") {
+		t.Errorf("passing case printed without -v:\n%s", out)
+	}
+}
+
+func TestRunSelftestModules(t *testing.T) {
+	var buf bytes.Buffer
+
+	opts := Options{Cases: testCases(), Executor: echoExecutor, Modules: []string{"b"}, OutputFormat: output.FormatJSON}
+	if err := RunSelftest(context.Background(), &buf, opts); err != nil {
+		t.Fatalf("RunSelftest() error = %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(report.Modules) != 1 || report.Modules[0].Module != "b" || report.Passed != 2 || len(report.Modules[0].Cases) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+
+	opts.Modules = []string{"c"}
+	if err := RunSelftest(context.Background(), &buf, opts); !cmderr.IsInvalidInput(err) {
+		t.Errorf("unknown module error = %v, want invalid input", err)
+	}
+}
+
+func TestRunSelftestList(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunSelftest(context.Background(), &buf, Options{Cases: testCases(), List: true}); err != nil {
+		t.Fatalf("RunSelftest() error = %v", err)
+	}
+
+	want := "a/echo\techo hi\na/dir\techo {dir}/sub/f.txt\nb/cat\tcat\nb/write\twrite {dir}/out\n"
+	if buf.String() != want {
+		t.Errorf("list = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunSelftestCleansUp(t *testing.T) {
+	var dirs []string
+
+	exec := func(ctx context.Context, stdin io.Reader, stdout io.Writer, args []string) error {
+		dirs = append(dirs, filepath.Dir(args[1]))
+		return echoExecutor(ctx, stdin, stdout, args)
+	}
+
+	if err := RunSelftest(context.Background(), io.Discard, Options{Cases: testCases()[3:], Executor: exec}); err != nil {
+		t.Fatalf("RunSelftest() error = %v", err)
+	}
+
+	if _, err := os.Stat(dirs[0]); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("case directory %s left behind", dirs[0])
+	}
+}
+
+func TestCasesUnique(t *testing.T) {
+	seen := map[string]bool{}
+
+	for _, c := range Cases() {
+		key := c.Module + "/" + c.Name
+		if seen[key] {
+			t.Errorf("duplicate case %s", key)
+		}
+
+		seen[key] = true
+
+		if len(c.Args) == 0 || (c.Want == "" && c.Check == nil) {
+			t.Errorf("case %s has no command or no expectation", key)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package seq
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"math"
@@ -79,41 +80,173 @@ func RunSeq(w io.Writer, args []string, opts SeqOptions) error {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "seq: increment must not be zero")
 	}
 
+	if opts.Format != "" && opts.EqualWidth {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "seq: format string may not be specified when printing equal width strings")
+	}
+
 	// Determine format
-	format := opts.Format
-	if format == "" {
-		format = determineSeqFormat(first, increment, last, opts.EqualWidth)
+	format := determineSeqFormat(first, increment, last, opts.EqualWidth)
+
+	if opts.Format != "" {
+		format, err = ParseFormat(opts.Format)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Generate sequence
 	f := output.New(w, opts.OutputFormat)
 	if f.IsJSON() {
 		var numbers []float64
-		for i := first; (increment > 0 && i <= last) || (increment < 0 && i >= last); i += increment {
-			numbers = append(numbers, i)
-		}
+
+		each(first, increment, last, func(v float64) bool {
+			numbers = append(numbers, v)
+			return true
+		})
 
 		return f.Print(SeqResult{Numbers: numbers, Count: len(numbers)})
 	}
 
+	bw := bufio.NewWriter(w)
 	isFirst := true
 
-	for i := first; (increment > 0 && i <= last) || (increment < 0 && i >= last); i += increment {
+	each(first, increment, last, func(v float64) bool {
 		if !isFirst {
-			_, _ = fmt.Fprint(w, opts.Separator)
+			_, _ = bw.WriteString(opts.Separator)
 		}
 
-		_, _ = fmt.Fprintf(w, format, i)
+		_, err = fmt.Fprintf(bw, format, v)
 		isFirst = false
+
+		return err == nil
+	})
+
+	if !isFirst && err == nil {
+		err = bw.WriteByte('\n')
+	}
+
+	if err == nil {
+		err = bw.Flush()
 	}
 
-	if !isFirst {
-		_, _ = fmt.Fprintln(w)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("seq: write: %s", err))
 	}
 
 	return nil
 }
 
+// each calls fn for every value from first to last. Values are computed as
+// first + n*increment rather than by repeated addition, so rounding errors
+// do not accumulate, and last is included when it is reached within a tiny
+// tolerance (seq 0 1.1 3.3 ends at 3.3 even though 3*1.1 > 3.3 in binary).
+func each(first, increment, last float64, fn func(float64) bool) {
+	eps := math.Abs(increment) * 1e-10
+
+	for n := 0.0; ; n++ {
+		v := first + n*increment
+		if (increment > 0 && v > last+eps) || (increment < 0 && v < last-eps) {
+			return
+		}
+
+		if math.Abs(v-last) <= eps {
+			v = last
+		}
+
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// ParseFormat validates a printf-style -f FORMAT and converts it to a Go
+// format string. Like GNU seq, FORMAT must contain exactly one floating-point
+// directive (%a, %e, %f, %g or their upper-case forms) with optional flags,
+// width and precision; %% is a literal percent sign. A %g without precision
+// keeps C's default of 6 significant digits.
+func ParseFormat(format string) (string, error) {
+	var (
+		b          strings.Builder
+		directives int
+	)
+
+	invalid := func(msg string) (string, error) {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("seq: %s in format %q", msg, format))
+	}
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+
+		if i+1 < len(format) && format[i+1] == '%' {
+			b.WriteString("%%")
+			i++
+
+			continue
+		}
+
+		j := i + 1
+		for j < len(format) && strings.IndexByte("-+ #0", format[j]) >= 0 {
+			j++
+		}
+
+		for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+			j++
+		}
+
+		hasPrecision := false
+
+		if j < len(format) && format[j] == '.' {
+			hasPrecision = true
+			j++
+
+			for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+				j++
+			}
+		}
+
+		if j >= len(format) {
+			return invalid("missing conversion specifier")
+		}
+
+		spec := format[i:j]
+		verb := format[j]
+
+		switch verb {
+		case 'e', 'E', 'f', 'F', 'g', 'G':
+		case 'a':
+			verb = 'x'
+		case 'A':
+			verb = 'X'
+		default:
+			return invalid(fmt.Sprintf("invalid conversion %%%c", format[j]))
+		}
+
+		if (verb == 'g' || verb == 'G') && !hasPrecision {
+			spec += ".6"
+		}
+
+		directives++
+
+		b.WriteString(spec)
+		b.WriteByte(verb)
+
+		i = j
+	}
+
+	switch {
+	case directives == 0:
+		return invalid("no %% directive")
+	case directives > 1:
+		return invalid("too many %% directives")
+	}
+
+	return b.String(), nil
+}
+
 func determineSeqFormat(first, increment, last float64, equalWidth bool) string {
 	// Check if any value has decimals
 	hasDecimals := hasDecimalPart(first) || hasDecimalPart(increment) || hasDecimalPart(last)
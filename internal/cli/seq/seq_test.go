@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
 func TestRunSeq(t *testing.T) {
@@ -199,6 +201,54 @@ func TestRunSeq(t *testing.T) {
 	})
 }
 
+func TestRunSeqFormatting(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		opts SeqOptions
+		want string
+	}{
+		{"format float", []string{"1", "3"}, SeqOptions{Format: "%.2f"}, "1.00\n2.00\n3.00\n"},
+		{"format literal text", []string{"2"}, SeqOptions{Format: "file%03g.txt"}, "file001.txt\nfile002.txt\n"},
+		{"format percent", []string{"1"}, SeqOptions{Format: "%g%%"}, "1%\n"},
+		{"format g default precision", []string{"1000000", "1000000"}, SeqOptions{Format: "%g"}, "1e+06\n"},
+		{"format exponent", []string{"1", "1"}, SeqOptions{Format: "%e"}, "1.000000e+00\n"},
+		{"equal width negative", []string{"-2", "1"}, SeqOptions{EqualWidth: true}, "-2\n-1\n00\n01\n"},
+		{"equal width decimals", []string{"0.5", "0.5", "10"}, SeqOptions{EqualWidth: true, Separator: " "},
+			"00.5 01.0 01.5 02.0 02.5 03.0 03.5 04.0 04.5 05.0 05.5 06.0 06.5 07.0 07.5 08.0 08.5 09.0 09.5 10.0\n"},
+		{"no drift", []string{"0", "1.1", "3.3"}, SeqOptions{}, "0.0\n1.1\n2.2\n3.3\n"},
+		{"empty range", []string{"5", "1", "3"}, SeqOptions{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := RunSeq(&buf, tt.args, tt.opts); err != nil {
+				t.Fatalf("RunSeq() error = %v", err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("RunSeq(%v) = %q, want %q", tt.args, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSeqInvalidFormat(t *testing.T) {
+	for _, format := range []string{"%d", "%s", "no directive", "%g %g", "%", "%5"} {
+		err := RunSeq(&bytes.Buffer{}, []string{"3"}, SeqOptions{Format: format})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("format %q: got %v, want invalid input", format, err)
+		}
+	}
+
+	err := RunSeq(&bytes.Buffer{}, []string{"3"}, SeqOptions{Format: "%g", EqualWidth: true})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("-f with -w: got %v, want invalid input", err)
+	}
+}
+
 func TestHasDecimalPart(t *testing.T) {
 	tests := []struct {
 		input    float64
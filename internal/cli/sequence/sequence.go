@@ -0,0 +1,186 @@
+package sequence
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+// Options configures the sequence commands
+type Options struct {
+	DB           string        // --db: counter database (default: DefaultDB)
+	Prefix       string        // --prefix: text before the number
+	Pad          int           // --pad: zero pad the number to this many digits
+	Count        int           // -n: number of values to reserve
+	OutputFormat output.Format // output format
+}
+
+// Result is the JSON form of reserved values
+type Result struct {
+	Namespace string   `json:"namespace"`
+	Values    []uint64 `json:"values"`
+	IDs       []string `json:"ids"`
+}
+
+// DefaultDB returns the counter database path: $OMNI_SEQUENCE_DB, or
+// sequences.db in the user config directory.
+func DefaultDB() string {
+	if path := os.Getenv("OMNI_SEQUENCE_DB"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "omni", "sequences.db")
+}
+
+func store(opts Options) *idgen.SequenceStore {
+	path := opts.DB
+	if path == "" {
+		path = DefaultDB()
+	}
+
+	return idgen.NewSequenceStore(path)
+}
+
+// RunNext reserves the next Count values of the namespace and prints them
+// formatted with Prefix and Pad, one per line.
+func RunNext(w io.Writer, namespace string, opts Options) error {
+	if err := checkNamespace("sequence next", namespace); err != nil {
+		return err
+	}
+
+	if opts.Count == 0 {
+		opts.Count = 1
+	}
+
+	if opts.Count < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sequence next: count must be positive, got %d", opts.Count))
+	}
+
+	if opts.Pad < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sequence next: pad must be non-negative, got %d", opts.Pad))
+	}
+
+	values, err := store(opts).NextN(namespace, opts.Count)
+	if err != nil {
+		return wrapErr("sequence next", err)
+	}
+
+	return printValues(w, namespace, values, opts)
+}
+
+// RunCurrent prints the last value handed out for the namespace without
+// advancing it (0 for an unused namespace).
+func RunCurrent(w io.Writer, namespace string, opts Options) error {
+	if err := checkNamespace("sequence current", namespace); err != nil {
+		return err
+	}
+
+	cur, err := store(opts).Current(namespace)
+	if err != nil {
+		return wrapErr("sequence current", err)
+	}
+
+	return printValues(w, namespace, []uint64{cur}, opts)
+}
+
+// RunSet sets the namespace counter so the next value is value+1.
+func RunSet(w io.Writer, namespace, value string, opts Options) error {
+	if err := checkNamespace("sequence set", namespace); err != nil {
+		return err
+	}
+
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || v == math.MaxUint64 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sequence set: invalid value %q", value))
+	}
+
+	if err := store(opts).Set(namespace, v); err != nil {
+		return wrapErr("sequence set", err)
+	}
+
+	_, _ = fmt.Fprintf(w, "%s: next value is %d\n", namespace, v+1)
+
+	return nil
+}
+
+// RunReset removes the namespace counter so it starts at 1 again.
+func RunReset(w io.Writer, namespace string, opts Options) error {
+	if err := checkNamespace("sequence reset", namespace); err != nil {
+		return err
+	}
+
+	if err := store(opts).Delete(namespace); err != nil {
+		return wrapErr("sequence reset", err)
+	}
+
+	_, _ = fmt.Fprintf(w, "%s: reset\n", namespace)
+
+	return nil
+}
+
+// RunList prints every namespace with its current value.
+func RunList(w io.Writer, opts Options) error {
+	counters, err := store(opts).List()
+	if err != nil {
+		return wrapErr("sequence list", err)
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		if counters == nil {
+			counters = []idgen.SequenceCounter{}
+		}
+
+		return f.Print(counters)
+	}
+
+	for _, c := range counters {
+		_, _ = fmt.Fprintf(w, "%s\t%d\n", c.Namespace, c.Value)
+	}
+
+	return nil
+}
+
+func printValues(w io.Writer, namespace string, values []uint64, opts Options) error {
+	ids := make([]string, len(values))
+	for i, v := range values {
+		ids[i] = idgen.FormatSequence(v, opts.Prefix, opts.Pad)
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(Result{Namespace: namespace, Values: values, IDs: ids})
+	}
+
+	for _, id := range ids {
+		if _, err := fmt.Fprintln(w, id); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("sequence: write failed: %v", err))
+		}
+	}
+
+	return nil
+}
+
+func checkNamespace(op, namespace string) error {
+	if namespace == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: empty namespace", op))
+	}
+
+	return nil
+}
+
+func wrapErr(op string, err error) error {
+	return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("%s: %s", op, err))
+}
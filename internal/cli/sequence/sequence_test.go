@@ -0,0 +1,95 @@
+package sequence
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestRunNext(t *testing.T) {
+	opts := Options{DB: filepath.Join(t.TempDir(), "seq.db"), Prefix: "INV-", Pad: 6}
+
+	var out bytes.Buffer
+	if err := RunNext(&out, "invoice", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Count = 2
+	if err := RunNext(&out, "invoice", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := out.String(), "INV-000001\nINV-000002\nINV-000003\n"; got != want {
+		t.Errorf("RunNext() = %q, want %q", got, want)
+	}
+
+	out.Reset()
+
+	if err := RunCurrent(&out, "invoice", Options{DB: opts.DB}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.String(); got != "3\n" {
+		t.Errorf("RunCurrent() = %q, want 3", got)
+	}
+}
+
+func TestRunSetResetList(t *testing.T) {
+	opts := Options{DB: filepath.Join(t.TempDir(), "seq.db")}
+
+	var out bytes.Buffer
+	if err := RunSet(&out, "ticket", "99", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunNext(&out, "ticket", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(out.String(), "\n100\n") {
+		t.Errorf("next after set 99 = %q", out.String())
+	}
+
+	out.Reset()
+
+	if err := RunList(&out, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.String(); got != "ticket\t100\n" {
+		t.Errorf("RunList() = %q", got)
+	}
+
+	if err := RunReset(&out, "ticket", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+
+	if err := RunNext(&out, "ticket", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.String(); got != "1\n" {
+		t.Errorf("next after reset = %q, want 1", got)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	opts := Options{DB: filepath.Join(t.TempDir(), "seq.db")}
+
+	if err := RunNext(&bytes.Buffer{}, "", opts); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunNext(empty namespace) error = %v", err)
+	}
+
+	if err := RunSet(&bytes.Buffer{}, "x", "-1", opts); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunSet(-1) error = %v", err)
+	}
+
+	if err := RunNext(&bytes.Buffer{}, "x", Options{DB: opts.DB, Count: -1}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunNext(count -1) error = %v", err)
+	}
+}
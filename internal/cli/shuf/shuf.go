@@ -2,11 +2,14 @@ package shuf
 
 import (
 	"bufio"
+	crand "crypto/rand"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"math/rand/v2"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -21,6 +24,9 @@ type ShufOptions struct {
 	HeadCount    int           // -n: output at most COUNT lines
 	Repeat       bool          // -r: output lines can be repeated
 	ZeroTerm     bool          // -z: line delimiter is NUL
+	Output       string        // -o: write result to FILE instead of standard output
+	Seed         string        // --seed: derive the random generator from STRING (reproducible)
+	RandomSource string        // --random-source: derive the random generator from FILE's contents
 	OutputFormat output.Format // output format
 }
 
@@ -30,126 +36,298 @@ type ShufResult struct {
 	Count int      `json:"count"`
 }
 
-// RunShuf shuffles input lines randomly
+// RunShuf shuffles input lines randomly. With -n and without -r only COUNT
+// lines are kept in memory (reservoir sampling), and -i ranges are sampled
+// without materialising every number, so shuf -n 5 -i 1-1000000000 is cheap.
 func RunShuf(w io.Writer, args []string, opts ShufOptions) error {
+	if opts.HeadCount < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("shuf: invalid line count: %d", opts.HeadCount))
+	}
+
+	if opts.Repeat && opts.HeadCount == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "shuf: --repeat requires --head-count")
+	}
+
+	rng, err := newRand(opts)
+	if err != nil {
+		return err
+	}
+
 	var lines []string
 
-	if opts.InputRange != "" {
-		// Generate range
-		parts := strings.Split(opts.InputRange, "-")
-		if len(parts) != 2 {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("shuf: invalid input range %q", opts.InputRange))
+	switch {
+	case opts.InputRange != "":
+		lo, hi, err := parseRange(opts.InputRange)
+		if err != nil {
+			return err
 		}
 
-		lo, err := strconv.Atoi(parts[0])
+		lines = sampleRange(rng, lo, hi, opts)
+	case opts.Echo:
+		// Use args as input lines
+		lines = sampleLines(rng, slices.Clone(args), opts)
+	default:
+		lines, err = readLines(rng, args, opts)
 		if err != nil {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("shuf: invalid input range %q", opts.InputRange))
+			return err
 		}
 
-		hi, err := strconv.Atoi(parts[1])
+		lines = sampleLines(rng, lines, opts)
+	}
+
+	if opts.Repeat && len(lines) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "shuf: no lines to repeat")
+	}
+
+	if opts.Output != "" {
+		out, err := os.Create(opts.Output)
 		if err != nil {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("shuf: invalid input range %q", opts.InputRange))
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("shuf: %s", err))
 		}
 
-		if lo > hi {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("shuf: invalid input range %q", opts.InputRange))
+		defer func() { _ = out.Close() }()
+
+		w = out
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(ShufResult{Lines: lines, Count: len(lines)})
+	}
+
+	outputDelim := "\n"
+	if opts.ZeroTerm {
+		outputDelim = "\x00"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for _, line := range lines {
+		_, _ = bw.WriteString(line)
+		_, _ = bw.WriteString(outputDelim)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("shuf: write: %s", err))
+	}
+
+	return nil
+}
+
+// newRand returns the generator for this run: seeded from --seed or
+// --random-source when given, so the same seed reproduces the same output,
+// and from crypto/rand otherwise.
+func newRand(opts ShufOptions) (*rand.Rand, error) {
+	var seed [32]byte
+
+	switch {
+	case opts.Seed != "" && opts.RandomSource != "":
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, "shuf: --seed and --random-source are mutually exclusive")
+	case opts.Seed != "":
+		seed = sha256.Sum256([]byte(opts.Seed))
+	case opts.RandomSource != "":
+		data, err := readRandomSource(opts.RandomSource)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("shuf: %s", err))
+			}
+
+			return nil, fmt.Errorf("shuf: %w", err)
 		}
 
-		for i := lo; i <= hi; i++ {
-			lines = append(lines, strconv.Itoa(i))
+		seed = sha256.Sum256(data)
+	default:
+		if _, err := crand.Read(seed[:]); err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("shuf: random seed: %s", err))
 		}
-	} else if opts.Echo {
-		// Use args as input lines
-		lines = args
-	} else {
-		// Read from file or stdin
-		var reader io.Reader
-
-		if len(args) == 0 || args[0] == "-" {
-			reader = os.Stdin
-		} else {
-			f, err := os.Open(args[0])
-			if err != nil {
-				if errors.Is(err, os.ErrNotExist) {
-					return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("shuf: %s", err))
-				}
-				return fmt.Errorf("shuf: %w", err)
-			}
+	}
+
+	return rand.New(rand.NewChaCha8(seed)), nil
+}
+
+// randomSourceBytes is how much of --random-source seeds the generator:
+// enough for a full seed, and a device such as /dev/urandom or a pipe is
+// never read to the end.
+const randomSourceBytes = 32
+
+// readRandomSource returns the first randomSourceBytes of path, or all of
+// it if it is shorter.
+func readRandomSource(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	buf := make([]byte, randomSourceBytes)
+
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+func parseRange(spec string) (int64, int64, error) {
+	invalid := cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("shuf: invalid input range %q", spec))
+
+	loStr, hiStr, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, invalid
+	}
 
-			defer func() { _ = f.Close() }()
+	lo, err := strconv.ParseInt(loStr, 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+
+	hi, err := strconv.ParseInt(hiStr, 10, 64)
+	if err != nil || lo > hi || hi-lo < 0 {
+		return 0, 0, invalid
+	}
+
+	return lo, hi, nil
+}
+
+// sampleRange picks from LO..HI. Without -n the whole range is shuffled;
+// with -n it uses Floyd's algorithm, which needs memory for COUNT values
+// only, and then shuffles the picks.
+func sampleRange(rng *rand.Rand, lo, hi int64, opts ShufOptions) []string {
+	n := uint64(hi-lo) + 1
+
+	if opts.Repeat {
+		lines := make([]string, opts.HeadCount)
+		for i := range lines {
+			lines[i] = strconv.FormatInt(lo+int64(rng.Uint64N(n)), 10)
+		}
+
+		return lines
+	}
+
+	k := n
+	if opts.HeadCount > 0 && uint64(opts.HeadCount) < n {
+		k = uint64(opts.HeadCount)
+	}
 
-			reader = f
+	var picks []int64
+
+	if k == n {
+		picks = make([]int64, 0, n)
+		for v := lo; v <= hi; v++ {
+			picks = append(picks, v)
 		}
+	} else {
+		chosen := make(map[uint64]struct{}, k)
+
+		for j := n - k; j < n; j++ {
+			t := rng.Uint64N(j + 1)
+			if _, dup := chosen[t]; dup {
+				t = j
+			}
 
-		delimiter := byte('\n')
-		if opts.ZeroTerm {
-			delimiter = 0
+			chosen[t] = struct{}{}
+			picks = append(picks, lo+int64(t))
 		}
+	}
 
-		scanner := bufio.NewScanner(reader)
-		scanner.Split(splitFunc(delimiter))
+	rng.Shuffle(len(picks), func(i, j int) {
+		picks[i], picks[j] = picks[j], picks[i]
+	})
 
-		for scanner.Scan() {
-			lines = append(lines, scanner.Text())
+	lines := make([]string, len(picks))
+	for i, v := range picks {
+		lines[i] = strconv.FormatInt(v, 10)
+	}
+
+	return lines
+}
+
+// sampleLines shuffles lines in place and applies -n, or draws -n lines
+// with replacement for -r.
+func sampleLines(rng *rand.Rand, lines []string, opts ShufOptions) []string {
+	if opts.Repeat {
+		if len(lines) == 0 {
+			return nil
 		}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("shuf: %w", err)
+		picks := make([]string, opts.HeadCount)
+		for i := range picks {
+			picks[i] = lines[rng.IntN(len(lines))]
 		}
+
+		return picks
 	}
 
 	// Shuffle using Fisher-Yates
-	rand.Shuffle(len(lines), func(i, j int) {
+	rng.Shuffle(len(lines), func(i, j int) {
 		lines[i], lines[j] = lines[j], lines[i]
 	})
 
-	outputDelim := "\n"
-	if opts.ZeroTerm {
-		outputDelim = "\x00"
+	if opts.HeadCount > 0 && opts.HeadCount < len(lines) {
+		lines = lines[:opts.HeadCount]
 	}
 
-	count := len(lines)
-	if opts.HeadCount > 0 && opts.HeadCount < count {
-		count = opts.HeadCount
-	}
+	return lines
+}
 
-	f := output.New(w, opts.OutputFormat)
-	if f.IsJSON() {
-		var shufOutput []string
+// readLines reads the input file or stdin. With -n (and no -r) it keeps a
+// uniform reservoir of COUNT lines instead of the whole input.
+func readLines(rng *rand.Rand, args []string, opts ShufOptions) ([]string, error) {
+	var reader io.Reader
 
-		if opts.Repeat {
-			if opts.HeadCount == 0 {
-				return cmderr.Wrap(cmderr.ErrInvalidInput, "shuf: --repeat requires --head-count")
+	if len(args) == 0 || args[0] == "-" {
+		reader = os.Stdin
+	} else {
+		f, err := os.Open(args[0])
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("shuf: %s", err))
 			}
 
-			for i := 0; i < opts.HeadCount; i++ {
-				idx := rand.Intn(len(lines))
-				shufOutput = append(shufOutput, lines[idx])
-			}
-		} else {
-			shufOutput = lines[:count]
+			return nil, fmt.Errorf("shuf: %w", err)
 		}
 
-		return f.Print(ShufResult{Lines: shufOutput, Count: len(shufOutput)})
+		defer func() { _ = f.Close() }()
+
+		reader = f
 	}
 
-	if opts.Repeat {
-		// Output with repetition allowed
-		if opts.HeadCount == 0 {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, "shuf: --repeat requires --head-count")
-		}
+	delimiter := byte('\n')
+	if opts.ZeroTerm {
+		delimiter = 0
+	}
 
-		for i := 0; i < opts.HeadCount; i++ {
-			idx := rand.Intn(len(lines))
-			_, _ = fmt.Fprint(w, lines[idx], outputDelim)
-		}
-	} else {
-		for i := 0; i < count; i++ {
-			_, _ = fmt.Fprint(w, lines[i], outputDelim)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024*1024)
+	scanner.Split(splitFunc(delimiter))
+
+	reservoir := opts.HeadCount > 0 && !opts.Repeat
+
+	var (
+		lines []string
+		seen  int
+	)
+
+	for scanner.Scan() {
+		seen++
+
+		switch {
+		case !reservoir || len(lines) < opts.HeadCount:
+			lines = append(lines, scanner.Text())
+		default:
+			if j := rng.IntN(seen); j < opts.HeadCount {
+				lines[j] = scanner.Text()
+			}
 		}
 	}
 
-	return nil
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("shuf: %w", err)
+	}
+
+	return lines, nil
 }
 
 func splitFunc(delim byte) bufio.SplitFunc {
@@ -223,3 +223,126 @@ func TestRunShuf_RepeatWithoutHeadCount_IsInvalidInput(t *testing.T) {
 		t.Fatalf("text path: want ErrInvalidInput, got %v", err)
 	}
 }
+
+func TestRunShufSeed(t *testing.T) {
+	run := func(opts ShufOptions) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		if err := RunShuf(&buf, nil, opts); err != nil {
+			t.Fatalf("RunShuf() error = %v", err)
+		}
+
+		return buf.String()
+	}
+
+	a := run(ShufOptions{InputRange: "1-100", Seed: "omni"})
+	if b := run(ShufOptions{InputRange: "1-100", Seed: "omni"}); a != b {
+		t.Error("same --seed should produce the same permutation")
+	}
+
+	if c := run(ShufOptions{InputRange: "1-100", Seed: "other"}); a == c {
+		t.Error("different seeds produced the same permutation of 100 numbers")
+	}
+
+	src := filepath.Join(t.TempDir(), "random")
+	if err := os.WriteFile(src, []byte("entropy"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if run(ShufOptions{InputRange: "1-100", RandomSource: src}) != run(ShufOptions{InputRange: "1-100", RandomSource: src}) {
+		t.Error("same --random-source should produce the same permutation")
+	}
+
+	// Only a prefix is read, so an endless source works too
+	if _, err := os.Stat("/dev/urandom"); err == nil {
+		run(ShufOptions{InputRange: "1-100", RandomSource: "/dev/urandom"})
+	}
+
+	long := filepath.Join(t.TempDir(), "long")
+	if err := os.WriteFile(long, []byte(strings.Repeat("x", randomSourceBytes)+"tail"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if run(ShufOptions{InputRange: "1-100", RandomSource: long}) != run(ShufOptions{InputRange: "1-100", Seed: strings.Repeat("x", randomSourceBytes)}) {
+		t.Error("--random-source should seed from the first bytes of the file only")
+	}
+
+	err := RunShuf(&bytes.Buffer{}, nil, ShufOptions{InputRange: "1-3", Seed: "x", RandomSource: src})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("--seed with --random-source: got %v, want invalid input", err)
+	}
+}
+
+func TestRunShufSampling(t *testing.T) {
+	t.Run("huge range", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunShuf(&buf, nil, ShufOptions{InputRange: "1-1000000000", HeadCount: 5}); err != nil {
+			t.Fatalf("RunShuf() error = %v", err)
+		}
+
+		lines := strings.Fields(buf.String())
+		if len(lines) != 5 {
+			t.Fatalf("got %d lines, want 5", len(lines))
+		}
+
+		seen := map[string]bool{}
+		for _, l := range lines {
+			if seen[l] {
+				t.Errorf("duplicate %s without -r", l)
+			}
+
+			seen[l] = true
+		}
+	})
+
+	t.Run("reservoir", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "in.txt")
+
+		var data strings.Builder
+		for i := range 1000 {
+			data.WriteString(strings.Repeat("x", i%7) + "\n")
+		}
+
+		if err := os.WriteFile(file, []byte(data.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := RunShuf(&buf, []string{file}, ShufOptions{HeadCount: 3, Seed: "s"}); err != nil {
+			t.Fatalf("RunShuf() error = %v", err)
+		}
+
+		if n := strings.Count(buf.String(), "\n"); n != 3 {
+			t.Errorf("got %d lines, want 3", n)
+		}
+	})
+
+	t.Run("repeat empty input", func(t *testing.T) {
+		err := RunShuf(&bytes.Buffer{}, nil, ShufOptions{Echo: true, Repeat: true, HeadCount: 3})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("got %v, want invalid input", err)
+		}
+	})
+}
+
+func TestRunShufOutputFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.txt")
+	if err := os.WriteFile(file, []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// -o may name the input file: it is only written after reading
+	if err := RunShuf(&bytes.Buffer{}, []string{file}, ShufOptions{Output: file}); err != nil {
+		t.Fatalf("RunShuf() error = %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Fields(string(data)); len(lines) != 3 {
+		t.Errorf("output file = %q, want 3 lines", data)
+	}
+}
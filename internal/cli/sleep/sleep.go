@@ -1,16 +1,32 @@
 package sleep
 
 import (
+	"context"
 	"fmt"
-	"strconv"
+	"io"
+	"os"
 	"strings"
 	"time"
 
+	"golang.org/x/term"
+
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/timeparse"
 )
 
-// RunSleep pauses execution for specified duration
-func RunSleep(args []string) error {
+// Options configures the sleep and until commands
+type Options struct {
+	Progress bool   // --progress: show the remaining time on stderr
+	TZ       string // until --tz: time zone of times without one (default: local)
+}
+
+// now is a variable so tests can fix the clock of RunUntil
+var now = time.Now
+
+// RunSleep pauses for the sum of args. Each argument is a number of
+// seconds or a duration such as 1h30m, 2d or "1 hour" (see package
+// timeparse). With opts.Progress the remaining time is shown on stderr.
+func RunSleep(ctx context.Context, stderr io.Writer, args []string, opts Options) error {
 	if len(args) == 0 {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, "sleep: missing operand")
 	}
@@ -19,49 +35,156 @@ func RunSleep(args []string) error {
 
 	for _, arg := range args {
 		d, err := parseSleepDuration(arg)
-		if err != nil {
+		if err != nil || d < 0 {
 			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("sleep: invalid time interval %q", arg))
 		}
 
 		totalDuration += d
 	}
 
-	time.Sleep(totalDuration)
-
-	return nil
+	return wait(ctx, stderr, totalDuration, "", opts)
 }
 
-func parseSleepDuration(s string) (time.Duration, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return 0, fmt.Errorf("empty duration")
+// RunUntil pauses until the time given by args, joined with spaces: a
+// clock time ("14:30", "2:30pm"), which means its next occurrence, a date
+// and time, or any other form timeparse.ParseTime accepts. A time that
+// has already passed returns at once.
+func RunUntil(ctx context.Context, stderr io.Writer, args []string, opts Options) error {
+	spec := strings.Join(args, " ")
+	if strings.TrimSpace(spec) == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "until: missing time")
 	}
 
-	// Check for suffix
-	suffix := s[len(s)-1]
-
-	var multiplier = time.Second // default
-
-	switch suffix {
-	case 's':
-		multiplier = time.Second
-		s = s[:len(s)-1]
-	case 'm':
-		multiplier = time.Minute
-		s = s[:len(s)-1]
-	case 'h':
-		multiplier = time.Hour
-		s = s[:len(s)-1]
-	case 'd':
-		multiplier = 24 * time.Hour
-		s = s[:len(s)-1]
+	current := now()
+
+	if opts.TZ != "" {
+		loc, err := time.LoadLocation(opts.TZ)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("until: unknown time zone %q", opts.TZ))
+		}
+
+		current = current.In(loc)
 	}
 
-	// Parse the number (supports decimals)
-	val, err := strconv.ParseFloat(s, 64)
+	target, err := timeparse.ParseTime(spec, current)
 	if err != nil {
-		return 0, err
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("until: invalid time %q", spec))
+	}
+
+	label := "until " + target.Format("2006-01-02 15:04:05 MST")
+
+	return wait(ctx, stderr, target.Sub(current), label, opts)
+}
+
+// wait sleeps for d or until ctx is done, drawing the remaining time when
+// opts.Progress is set: a self-updating bar on a terminal, otherwise a
+// line at every tenth of the wait.
+func wait(ctx context.Context, stderr io.Writer, d time.Duration, label string, opts Options) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var (
+		tick <-chan time.Time
+		bar  *progress
+	)
+
+	if opts.Progress {
+		bar = newProgress(stderr, d, label)
+
+		ticker := time.NewTicker(bar.interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+
+		bar.draw(d)
 	}
 
-	return time.Duration(val * float64(multiplier)), nil
+	start := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			bar.finish()
+
+			return ctx.Err()
+		case <-timer.C:
+			bar.finish()
+
+			return nil
+		case t := <-tick:
+			bar.draw(d - t.Sub(start))
+		}
+	}
+}
+
+// progress shows the remaining time of a wait.
+type progress struct {
+	w        io.Writer
+	total    time.Duration
+	label    string
+	isTerm   bool
+	interval time.Duration
+	step     int // last tenth reported without a terminal
+}
+
+func newProgress(w io.Writer, total time.Duration, label string) *progress {
+	p := &progress{w: w, total: total, label: label, step: -1}
+
+	if f, ok := w.(*os.File); ok {
+		p.isTerm = term.IsTerminal(int(f.Fd()))
+	}
+
+	// Redraw often enough for a short wait to move, at most once a second
+	p.interval = min(max(total/100, 50*time.Millisecond), time.Second)
+
+	return p
+}
+
+func (p *progress) draw(left time.Duration) {
+	left = max(left, 0)
+	done := p.total - left
+	pct := int(done * 100 / p.total)
+
+	remaining := timeparse.FormatDuration(left.Round(time.Second))
+	if p.total < time.Minute {
+		remaining = timeparse.FormatDuration(left.Round(100 * time.Millisecond))
+	}
+
+	prefix := ""
+	if p.label != "" {
+		prefix = p.label + "  "
+	}
+
+	if !p.isTerm {
+		if step := pct / 10; step > p.step {
+			p.step = step
+			_, _ = fmt.Fprintf(p.w, "%s%3d%%  %s left\n", prefix, pct, remaining)
+		}
+
+		return
+	}
+
+	const width = 30
+
+	filled := pct * width / 100
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+
+	_, _ = fmt.Fprintf(p.w, "\r\033[K%s[%s] %3d%%  %s left", prefix, bar, pct, remaining)
+}
+
+// finish erases the bar so later output starts on a clean line.
+func (p *progress) finish() {
+	if p != nil && p.isTerm {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+	}
+}
+
+// parseSleepDuration reads one sleep operand: a number of seconds, which
+// may be a decimal, or a duration with units.
+func parseSleepDuration(s string) (time.Duration, error) {
+	return timeparse.ParseDuration(s, time.Second)
 }
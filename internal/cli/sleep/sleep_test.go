@@ -1,6 +1,11 @@
 package sleep
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
 	"testing"
 	"time"
 )
@@ -72,6 +77,16 @@ func TestParseSleepDuration(t *testing.T) {
 			input:    "0.001",
 			expected: 1 * time.Millisecond,
 		},
+		{
+			name:     "compound",
+			input:    "1h30m",
+			expected: 90 * time.Minute,
+		},
+		{
+			name:     "spelled out",
+			input:    "2 minutes",
+			expected: 2 * time.Minute,
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,14 +107,14 @@ func TestParseSleepDuration(t *testing.T) {
 
 func TestRunSleep(t *testing.T) {
 	t.Run("no arguments", func(t *testing.T) {
-		err := RunSleep([]string{})
+		err := RunSleep(context.Background(), io.Discard, []string{}, Options{})
 		if err == nil {
 			t.Error("RunSleep() expected error with no arguments")
 		}
 	})
 
 	t.Run("invalid argument", func(t *testing.T) {
-		err := RunSleep([]string{"invalid"})
+		err := RunSleep(context.Background(), io.Discard, []string{"invalid"}, Options{})
 		if err == nil {
 			t.Error("RunSleep() expected error with invalid argument")
 		}
@@ -108,7 +123,7 @@ func TestRunSleep(t *testing.T) {
 	t.Run("very short sleep", func(t *testing.T) {
 		start := time.Now()
 
-		err := RunSleep([]string{"0.001"})
+		err := RunSleep(context.Background(), io.Discard, []string{"0.001"}, Options{})
 		if err != nil {
 			t.Fatalf("RunSleep() error = %v", err)
 		}
@@ -122,7 +137,7 @@ func TestRunSleep(t *testing.T) {
 	t.Run("multiple arguments", func(t *testing.T) {
 		start := time.Now()
 
-		err := RunSleep([]string{"0.001", "0.001"})
+		err := RunSleep(context.Background(), io.Discard, []string{"0.001", "0.001"}, Options{})
 		if err != nil {
 			t.Fatalf("RunSleep() error = %v", err)
 		}
@@ -134,9 +149,77 @@ func TestRunSleep(t *testing.T) {
 	})
 
 	t.Run("mixed valid invalid", func(t *testing.T) {
-		err := RunSleep([]string{"0.001", "invalid"})
+		err := RunSleep(context.Background(), io.Discard, []string{"0.001", "invalid"}, Options{})
 		if err == nil {
 			t.Error("RunSleep() expected error with invalid argument")
 		}
 	})
 }
+
+func TestRunSleepProgress(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunSleep(context.Background(), &buf, []string{"0.3"}, Options{Progress: true}); err != nil {
+		t.Fatalf("RunSleep() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(buf.String(), "  0%  300ms left\n") {
+		t.Errorf("progress = %q", buf.String())
+	}
+}
+
+func TestRunSleepCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := RunSleep(ctx, io.Discard, []string{"1h"}, Options{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunSleep() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	fixed := time.Date(2026, 10, 18, 23, 59, 59, 950_000_000, time.UTC)
+
+	old := now
+	t.Cleanup(func() { now = old })
+
+	now = func() time.Time { return fixed }
+
+	t.Run("next occurrence", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		start := time.Now()
+
+		if err := RunUntil(context.Background(), &buf, []string{"00:00"}, Options{TZ: "UTC", Progress: true}); err != nil {
+			t.Fatalf("RunUntil() error = %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("RunUntil() returned after %v, want 50ms", elapsed)
+		}
+
+		if !strings.HasPrefix(buf.String(), "until 2026-10-19 00:00:00 UTC") {
+			t.Errorf("progress = %q", buf.String())
+		}
+	})
+
+	t.Run("past time returns at once", func(t *testing.T) {
+		if err := RunUntil(context.Background(), io.Discard, []string{"2026-10-18", "12:00"}, Options{TZ: "UTC"}); err != nil {
+			t.Errorf("RunUntil() error = %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, args := range [][]string{nil, {"teatime"}} {
+			if err := RunUntil(context.Background(), io.Discard, args, Options{}); err == nil {
+				t.Errorf("RunUntil(%q) expected error", args)
+			}
+		}
+
+		if err := RunUntil(context.Background(), io.Discard, []string{"12:00"}, Options{TZ: "Mars/Olympus"}); err == nil {
+			t.Error("RunUntil() expected error for unknown time zone")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+// DefaultCoordinatorAddr is the address the coordinator listens on when
+// --listen is not given.
+const DefaultCoordinatorAddr = "127.0.0.1:7420"
+
+// CoordinatorOptions configures the snowflake coordinator command behavior
+type CoordinatorOptions struct {
+	Listen string // --listen: host:port or unix:PATH
+}
+
+// RunCoordinator leases worker IDs to snowflake clients until ctx is done.
+func RunCoordinator(ctx context.Context, w io.Writer, opts CoordinatorOptions) error {
+	if opts.Listen == "" {
+		opts.Listen = DefaultCoordinatorAddr
+	}
+
+	network, address := CoordinatorNetwork(opts.Listen)
+	if network == "unix" {
+		removeStaleSocket(address)
+	}
+
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("snowflake coordinator: %v", err))
+	}
+
+	_, _ = fmt.Fprintf(w, "snowflake coordinator listening on %s %s\n", network, ln.Addr())
+
+	if err := idgen.NewCoordinator().Serve(ctx, ln); err != nil {
+		return cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("snowflake coordinator: %v", err))
+	}
+
+	return nil
+}
+
+// removeStaleSocket removes a socket file left behind by a coordinator that
+// did not shut down cleanly. A socket something still answers on is kept,
+// so the listen fails instead of stealing it.
+func removeStaleSocket(path string) {
+	fi, err := os.Lstat(path)
+	if err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		_ = conn.Close()
+		return
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		_ = os.Remove(path)
+	}
+}
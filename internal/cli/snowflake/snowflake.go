@@ -1,8 +1,11 @@
 package snowflake
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
@@ -14,6 +17,9 @@ import (
 type Options struct {
 	Count        int           // -n: generate N Snowflake IDs
 	WorkerID     int64         // -w: worker ID (0-1023)
+	WorkerEnv    bool          // take the worker ID from $OMNI_SNOWFLAKE_WORKER_ID when set
+	WorkerLock   string        // --worker-lock: lease the lowest free worker ID in this directory
+	Coordinator  string        // --coordinator: lease the worker ID from a coordinator (host:port or unix:PATH)
 	OutputFormat output.Format // output format (text, json, table)
 }
 
@@ -21,8 +27,12 @@ type Options struct {
 type Result struct {
 	Snowflakes []int64 `json:"snowflakes"`
 	Count      int     `json:"count"`
+	WorkerID   int64   `json:"workerId"`
 }
 
+// leaseTimeout bounds how long RunSnowflake waits for a worker ID lease.
+const leaseTimeout = 10 * time.Second
+
 // Generator generates Snowflake IDs
 type Generator = idgen.SnowflakeGenerator
 
@@ -40,7 +50,16 @@ func RunSnowflake(w io.Writer, opts Options) error {
 		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("snowflake: worker ID must be between 0 and 1023, got %d", opts.WorkerID))
 	}
 
-	gen := idgen.NewSnowflakeGenerator(opts.WorkerID)
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+
+	gen, err := idgen.OpenSnowflakeGenerator(ctx, idgen.WithWorkerIDProvider(workerIDProvider(opts)))
+	if err != nil {
+		return leaseError(opts, err)
+	}
+
+	defer func() { _ = gen.Close() }()
+
 	f := output.New(w, opts.OutputFormat)
 
 	var snowflakes []int64
@@ -61,12 +80,55 @@ func RunSnowflake(w io.Writer, opts Options) error {
 	}
 
 	if f.IsJSON() {
-		return f.Print(Result{Snowflakes: snowflakes, Count: len(snowflakes)})
+		return f.Print(Result{Snowflakes: snowflakes, Count: len(snowflakes), WorkerID: gen.WorkerID()})
 	}
 
 	return nil
 }
 
+// workerIDProvider picks the worker ID source: the coordinator, then the
+// lock directory, then the environment, then the -w value.
+func workerIDProvider(opts Options) idgen.WorkerIDProvider {
+	switch {
+	case opts.Coordinator != "":
+		return idgen.CoordinatorWorkerID(CoordinatorNetwork(opts.Coordinator))
+	case opts.WorkerLock != "":
+		return idgen.FileLockWorkerID(opts.WorkerLock)
+	case opts.WorkerEnv:
+		return idgen.FirstWorkerID(idgen.EnvWorkerID(""), idgen.StaticWorkerID(opts.WorkerID))
+	default:
+		return idgen.StaticWorkerID(opts.WorkerID)
+	}
+}
+
+// leaseError maps a failed lease to the error kind of its source.
+func leaseError(opts Options, err error) error {
+	msg := fmt.Sprintf("snowflake: %v", err)
+
+	switch {
+	case errors.Is(err, idgen.ErrNoWorkerID):
+		return cmderr.Wrap(cmderr.ErrConflict, msg)
+	case errors.Is(err, context.DeadlineExceeded):
+		return cmderr.Wrap(cmderr.ErrTimeout, "snowflake: timed out waiting for a worker ID")
+	case opts.Coordinator != "":
+		return cmderr.Wrap(cmderr.ErrNetwork, msg)
+	case opts.WorkerLock != "":
+		return cmderr.Wrap(cmderr.ErrIO, msg)
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, msg)
+	}
+}
+
+// CoordinatorNetwork splits a coordinator address into its network and
+// address: "unix:PATH" is a unix socket, anything else a TCP host:port.
+func CoordinatorNetwork(addr string) (network, address string) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return "unix", path
+	}
+
+	return "tcp", addr
+}
+
 // NewGenerator creates a new Snowflake generator
 func NewGenerator(workerID int64) *idgen.SnowflakeGenerator {
 	return idgen.NewSnowflakeGenerator(workerID)
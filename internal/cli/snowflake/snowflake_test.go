@@ -2,12 +2,16 @@ package snowflake
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
 )
 
 func TestNew(t *testing.T) {
@@ -184,3 +188,115 @@ func TestNewString(t *testing.T) {
 		t.Error("NewString() returned empty string")
 	}
 }
+
+// runWorkerID runs RunSnowflake with JSON output and returns the worker ID
+// it used.
+func runWorkerID(t *testing.T, opts Options) int64 {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	opts.OutputFormat = output.FormatJSON
+	if err := RunSnowflake(&buf, opts); err != nil {
+		t.Fatalf("RunSnowflake() error = %v", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, worker, _ := Parse(result.Snowflakes[0]); worker != result.WorkerID {
+		t.Errorf("ID worker = %d, want %d", worker, result.WorkerID)
+	}
+
+	return result.WorkerID
+}
+
+func TestRunSnowflakeWorkerSources(t *testing.T) {
+	t.Setenv(idgen.DefaultWorkerIDEnv, "12")
+
+	if got := runWorkerID(t, Options{WorkerEnv: true, WorkerID: 3}); got != 12 {
+		t.Errorf("env worker ID = %d, want 12", got)
+	}
+
+	if got := runWorkerID(t, Options{WorkerID: 3}); got != 3 {
+		t.Errorf("-w worker ID = %d, want 3", got)
+	}
+
+	dir := t.TempDir()
+
+	held, err := idgen.FileLockWorkerID(dir).Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = held.Release() }()
+
+	if got := runWorkerID(t, Options{WorkerLock: dir}); got != 1 {
+		t.Errorf("--worker-lock worker ID = %d, want 1 while 0 is held", got)
+	}
+
+	t.Setenv(idgen.DefaultWorkerIDEnv, "5000")
+
+	err = RunSnowflake(&bytes.Buffer{}, Options{WorkerEnv: true})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunSnowflake() with bad env error = %v, want invalid input", err)
+	}
+}
+
+func TestRunCoordinator(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "sf.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	var out bytes.Buffer
+
+	go func() { done <- RunCoordinator(ctx, &out, CoordinatorOptions{Listen: "unix:" + sock}) }()
+
+	// Wait for the socket to accept connections.
+	var held *idgen.WorkerLease
+
+	provider := idgen.CoordinatorWorkerID("unix", sock)
+	for deadline := time.Now().Add(5 * time.Second); held == nil; {
+		lease, err := provider.Acquire(ctx)
+		if err == nil {
+			held = lease
+		} else if time.Now().After(deadline) {
+			t.Fatalf("coordinator not reachable: %v", err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := runWorkerID(t, Options{Coordinator: "unix:" + sock}); got != 1 {
+		t.Errorf("--coordinator worker ID = %d, want 1 while 0 is held", got)
+	}
+
+	_ = held.Release()
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("RunCoordinator() error = %v", err)
+	}
+
+	if !strings.Contains(out.String(), "listening on unix") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	err := RunSnowflake(&bytes.Buffer{}, Options{Coordinator: "unix:" + sock})
+	if !cmderr.IsNetwork(err) {
+		t.Errorf("RunSnowflake() without coordinator error = %v, want network error", err)
+	}
+}
+
+func TestCoordinatorNetwork(t *testing.T) {
+	if n, a := CoordinatorNetwork("unix:/run/sf.sock"); n != "unix" || a != "/run/sf.sock" {
+		t.Errorf("CoordinatorNetwork(unix:...) = %s, %s", n, a)
+	}
+
+	if n, a := CoordinatorNetwork("10.0.0.1:7420"); n != "tcp" || a != "10.0.0.1:7420" {
+		t.Errorf("CoordinatorNetwork(host:port) = %s, %s", n, a)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package stat
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime returns the last access time of a file (the modification time
+// where it is not available)
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
@@ -16,39 +15,6 @@ type StatOptions struct {
 	OutputFormat output.Format // output format (text, json, table)
 }
 
-// TouchOptions configures the touch command behavior
-type TouchOptions struct{}
-
-func RunTouch(args []string, _ TouchOptions) error {
-	if len(args) == 0 {
-		return cmderr.Wrap(cmderr.ErrInvalidInput, "touch: missing operand")
-	}
-
-	for _, path := range args {
-		_, err := os.Stat(path)
-		if errors.Is(err, os.ErrNotExist) {
-			f, createErr := os.Create(path)
-			if createErr != nil {
-				if errors.Is(createErr, os.ErrPermission) {
-					return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("touch: %s", createErr))
-				}
-				return fmt.Errorf("touch: %w", createErr)
-			}
-
-			_ = f.Close()
-
-			continue
-		}
-
-		now := time.Now()
-		if err := os.Chtimes(path, now, now); err != nil {
-			return fmt.Errorf("touch: %w", err)
-		}
-	}
-
-	return nil
-}
-
 type StatInfo struct {
 	Name    string      `json:"name"`
 	Size    int64       `json:"size"`
@@ -0,0 +1,268 @@
+package stat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// TouchOptions configures the touch command behavior
+type TouchOptions struct {
+	AccessOnly bool      // -a: change only the access time
+	ModifyOnly bool      // -m: change only the modification time
+	NoCreate   bool      // -c: do not create any files
+	Date       string    // -d: use DATE instead of the current time
+	Reference  string    // -r: use this file's times instead of the current time
+	Stamp      string    // -t: use [[CC]YY]MMDDhhmm[.ss] instead of the current time
+	Now        time.Time // current time; zero means time.Now()
+}
+
+// RunTouch updates the access and modification times of each file,
+// creating missing ones unless NoCreate is set.
+func RunTouch(args []string, opts TouchOptions) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "touch: missing operand")
+	}
+
+	atime, mtime, err := touchTimes(opts)
+	if err != nil {
+		return err
+	}
+
+	// A zero time leaves that timestamp unchanged
+	if opts.AccessOnly && !opts.ModifyOnly {
+		mtime = time.Time{}
+	}
+
+	if opts.ModifyOnly && !opts.AccessOnly {
+		atime = time.Time{}
+	}
+
+	for _, path := range args {
+		_, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if opts.NoCreate {
+				continue
+			}
+
+			f, createErr := os.Create(path)
+			if createErr != nil {
+				if errors.Is(createErr, os.ErrPermission) {
+					return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("touch: %s", createErr))
+				}
+				return fmt.Errorf("touch: %w", createErr)
+			}
+
+			_ = f.Close()
+		}
+
+		if err := os.Chtimes(path, atime, mtime); err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("touch: %s", err))
+			}
+			return fmt.Errorf("touch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// touchTimes resolves the access and modification times to set.
+func touchTimes(opts TouchOptions) (time.Time, time.Time, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if opts.Stamp != "" && (opts.Date != "" || opts.Reference != "") {
+		return time.Time{}, time.Time{}, cmderr.Wrap(cmderr.ErrInvalidInput, "touch: cannot specify times from more than one source")
+	}
+
+	atime, mtime := now, now
+
+	if opts.Reference != "" {
+		info, err := os.Stat(opts.Reference)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return time.Time{}, time.Time{}, cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("touch: failed to get attributes of '%s'", opts.Reference))
+			}
+			return time.Time{}, time.Time{}, fmt.Errorf("touch: %w", err)
+		}
+
+		mtime = info.ModTime()
+		atime = accessTime(info)
+	}
+
+	switch {
+	case opts.Stamp != "":
+		t, err := ParseStamp(opts.Stamp, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		atime, mtime = t, t
+	case opts.Date != "":
+		// With -r, relative dates adjust the reference file's time
+		t, err := ParseDate(opts.Date, mtime)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		atime, mtime = t, t
+	}
+
+	return atime, mtime, nil
+}
+
+// dateLayouts are the absolute formats accepted by ParseDate, tried in order.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+}
+
+// relativeDate matches "[+-]N unit[s] [ago]", e.g. "2 days ago" or "-1 hour".
+var relativeDate = regexp.MustCompile(`^([+-]?\d+)\s*(second|sec|minute|min|hour|day|week|month|year)s?(\s+ago)?$`)
+
+// ParseDate parses a touch -d style date relative to base: an absolute date
+// (RFC 3339, "2006-01-02[ 15:04[:05]]", RFC 1123 or Unix date formats) in
+// local time unless a zone is given, "@SECONDS" since the epoch, now, today,
+// yesterday, tomorrow, "N unit[s] [ago]" or a Go duration such as -90m.
+func ParseDate(s string, base time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+
+	switch lower {
+	case "now", "today":
+		return base, nil
+	case "yesterday":
+		return base.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return base.AddDate(0, 0, 1), nil
+	}
+
+	if secs, ok := strings.CutPrefix(s, "@"); ok {
+		f, err := strconv.ParseFloat(secs, 64)
+		if err != nil {
+			return time.Time{}, invalidDate(s)
+		}
+
+		sec := int64(f)
+
+		return time.Unix(sec, int64((f-float64(sec))*1e9)), nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if m := relativeDate.FindStringSubmatch(lower); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[3] != "" {
+			n = -n
+		}
+
+		switch m[2] {
+		case "second", "sec":
+			return base.Add(time.Duration(n) * time.Second), nil
+		case "minute", "min":
+			return base.Add(time.Duration(n) * time.Minute), nil
+		case "hour":
+			return base.Add(time.Duration(n) * time.Hour), nil
+		case "day":
+			return base.AddDate(0, 0, n), nil
+		case "week":
+			return base.AddDate(0, 0, 7*n), nil
+		case "month":
+			return base.AddDate(0, n, 0), nil
+		default:
+			return base.AddDate(n, 0, 0), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return base.Add(d), nil
+	}
+
+	return time.Time{}, invalidDate(s)
+}
+
+// ParseStamp parses a touch -t timestamp, [[CC]YY]MMDDhhmm[.ss], in local
+// time. A two-digit year is 1969-2068 and a missing one is now's year.
+func ParseStamp(s string, now time.Time) (time.Time, error) {
+	digits, secs, hasSecs := strings.Cut(s, ".")
+
+	if !isDigits(digits) || (hasSecs && (len(secs) != 2 || !isDigits(secs))) {
+		return time.Time{}, invalidDate(s)
+	}
+
+	year := now.Year()
+
+	switch len(digits) {
+	case 8:
+	case 10:
+		yy, _ := strconv.Atoi(digits[:2])
+		if yy < 69 {
+			year = 2000 + yy
+		} else {
+			year = 1900 + yy
+		}
+
+		digits = digits[2:]
+	case 12:
+		year, _ = strconv.Atoi(digits[:4])
+		digits = digits[4:]
+	default:
+		return time.Time{}, invalidDate(s)
+	}
+
+	field := func(i int) int {
+		n, _ := strconv.Atoi(digits[i : i+2])
+		return n
+	}
+
+	month, day, hour, minute := field(0), field(2), field(4), field(6)
+
+	sec := 0
+	if hasSecs {
+		sec, _ = strconv.Atoi(secs)
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, sec, 0, time.Local)
+
+	// time.Date normalizes out-of-range fields; reject them instead
+	if t.Month() != time.Month(month) || t.Day() != day || t.Hour() != hour || t.Minute() != minute || sec > 60 {
+		return time.Time{}, invalidDate(s)
+	}
+
+	return t, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func invalidDate(s string) error {
+	return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("touch: invalid date format '%s'", s))
+}
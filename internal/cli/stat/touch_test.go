@@ -0,0 +1,128 @@
+package stat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestParseDate(t *testing.T) {
+	base := time.Date(2024, 3, 15, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", base},
+		{"yesterday", base.AddDate(0, 0, -1)},
+		{"2 days ago", base.AddDate(0, 0, -2)},
+		{"+1 week", base.AddDate(0, 0, 7)},
+		{"-3 hours", base.Add(-3 * time.Hour)},
+		{"-90m", base.Add(-90 * time.Minute)},
+		{"@0", time.Unix(0, 0)},
+		{"2020-01-02", time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local)},
+		{"2020-01-02 03:04", time.Date(2020, 1, 2, 3, 4, 0, 0, time.Local)},
+		{"2020-01-02T03:04:05Z", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDate(tt.in, base)
+			if err != nil {
+				t.Fatalf("ParseDate() error = %v", err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseDate("next blue moon", base); !cmderr.IsInvalidInput(err) {
+		t.Errorf("ParseDate(invalid) error = %v, want invalid input", err)
+	}
+}
+
+func TestParseStamp(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"01021504", time.Date(2024, 1, 2, 15, 4, 0, 0, time.Local)},
+		{"2501021504.30", time.Date(2025, 1, 2, 15, 4, 30, 0, time.Local)},
+		{"9901021504", time.Date(1999, 1, 2, 15, 4, 0, 0, time.Local)},
+		{"202001021504", time.Date(2020, 1, 2, 15, 4, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStamp(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseStamp(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"0102", "13011200", "01321200", "01021504.5", "0102150x"} {
+		if _, err := ParseStamp(bad, now); !cmderr.IsInvalidInput(err) {
+			t.Errorf("ParseStamp(%q) error = %v, want invalid input", bad, err)
+		}
+	}
+}
+
+func TestRunTouch_Times(t *testing.T) {
+	dir := t.TempDir()
+	ref := filepath.Join(dir, "ref")
+	file := filepath.Join(dir, "file")
+
+	_ = os.WriteFile(ref, nil, 0644)
+	_ = os.WriteFile(file, nil, 0644)
+
+	old := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	if err := os.Chtimes(ref, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunTouch([]string{file}, TouchOptions{Reference: ref}); err != nil {
+		t.Fatalf("RunTouch(-r) error = %v", err)
+	}
+
+	if info, _ := os.Stat(file); !info.ModTime().Equal(old) {
+		t.Errorf("-r: mtime = %v, want %v", info.ModTime(), old)
+	}
+
+	// -a leaves the modification time alone
+	if err := RunTouch([]string{file}, TouchOptions{AccessOnly: true}); err != nil {
+		t.Fatalf("RunTouch(-a) error = %v", err)
+	}
+
+	if info, _ := os.Stat(file); !info.ModTime().Equal(old) {
+		t.Errorf("-a: mtime changed to %v", info.ModTime())
+	}
+
+	if err := RunTouch([]string{file}, TouchOptions{ModifyOnly: true, Date: "2010-05-06 07:08:09"}); err != nil {
+		t.Fatalf("RunTouch(-m -d) error = %v", err)
+	}
+
+	want := time.Date(2010, 5, 6, 7, 8, 9, 0, time.Local)
+	if info, _ := os.Stat(file); !info.ModTime().Equal(want) {
+		t.Errorf("-d: mtime = %v, want %v", info.ModTime(), want)
+	}
+
+	// -c does not create missing files
+	missing := filepath.Join(dir, "missing")
+	if err := RunTouch([]string{missing}, TouchOptions{NoCreate: true}); err != nil {
+		t.Fatalf("RunTouch(-c) error = %v", err)
+	}
+
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("-c created the file")
+	}
+
+	if err := RunTouch([]string{file}, TouchOptions{Stamp: "01021504", Date: "now"}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("RunTouch(-t -d) error = %v, want invalid input", err)
+	}
+}
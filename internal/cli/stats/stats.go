@@ -0,0 +1,374 @@
+// Package stats summarizes a stream of numbers: count, sum, mean,
+// standard deviation, extremes, percentiles and a histogram sparkline.
+package stats
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/input"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/tablewriter"
+	"github.com/inovacc/omni/pkg/timeparse"
+)
+
+// Options configures the stats command behavior
+type Options struct {
+	Field        int           // -f: 1-based field holding the number (default 1)
+	Delimiter    string        // -d: field delimiter instead of blanks
+	Header       int           // --header: skip the first N lines of each input
+	Durations    bool          // --durations: values are durations such as 12ms or 1.5s
+	Strict       bool          // --strict: fail on a value that is not a number
+	Bins         int           // --bins: histogram buckets (default 10)
+	OutputFormat output.Format // output format (text/json)
+}
+
+// Percentiles are reported for every summary, in this order.
+var Percentiles = []float64{50, 90, 95, 99}
+
+// Bucket is one histogram bucket; it holds the values in [Low, High), and
+// the last bucket also holds High.
+type Bucket struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int     `json:"count"`
+}
+
+// Summary holds the statistics of a set of values.
+type Summary struct {
+	Count       int                `json:"count"`
+	Skipped     int                `json:"skipped"`
+	Sum         float64            `json:"sum"`
+	Mean        float64            `json:"mean"`
+	Median      float64            `json:"median"`
+	StdDev      float64            `json:"stddev"`
+	Min         float64            `json:"min"`
+	Max         float64            `json:"max"`
+	Percentiles map[string]float64 `json:"percentiles"`
+	Histogram   []Bucket           `json:"histogram"`
+	Sparkline   string             `json:"sparkline"`
+	Unit        string             `json:"unit,omitempty"`
+}
+
+// Run reads numbers from the files in args, or r when there are none, and
+// writes their summary. Lines are split into fields like cut and awk do and
+// the number is taken from opts.Field. Blank lines are ignored; other lines
+// without a number are counted as skipped unless opts.Strict is set.
+func Run(w io.Writer, r io.Reader, args []string, opts Options) error {
+	if opts.Field == 0 {
+		opts.Field = 1
+	}
+
+	if opts.Field < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("stats: invalid field %d", opts.Field))
+	}
+
+	if opts.Bins == 0 {
+		opts.Bins = 10
+	}
+
+	if opts.Bins < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("stats: invalid bin count %d", opts.Bins))
+	}
+
+	sources, err := input.Open(args, r)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("stats: %s", err))
+		}
+
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("stats: %s", err))
+	}
+	defer input.CloseAll(sources)
+
+	var (
+		values  []float64
+		skipped int
+	)
+
+	for _, src := range sources {
+		scanner := bufio.NewScanner(src.Reader)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+		for line := 1; scanner.Scan(); line++ {
+			if line <= opts.Header {
+				continue
+			}
+
+			text := scanner.Text()
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+
+			v, err := parseValue(field(text, opts.Field, opts.Delimiter), opts.Durations)
+			if err != nil {
+				if opts.Strict {
+					return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("stats: %s:%d: %v", src.Name, line, err))
+				}
+
+				skipped++
+
+				continue
+			}
+
+			values = append(values, v)
+		}
+
+		if err := scanner.Err(); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("stats: %s: %s", src.Name, err))
+		}
+	}
+
+	if len(values) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "stats: no numbers in input")
+	}
+
+	s := Compute(values, opts.Bins)
+	s.Skipped = skipped
+
+	if opts.Durations {
+		s.Unit = "s"
+	}
+
+	f := output.New(w, opts.OutputFormat)
+
+	switch {
+	case f.IsJSON():
+		return f.Print(s)
+	case f.IsTable():
+		t := tablewriter.New(tablewriter.Column{Header: "STAT"}, tablewriter.Column{Header: "VALUE", Align: tablewriter.AlignRight})
+		for _, r := range rows(s, opts.Durations) {
+			t.Append(r[0], r[1])
+		}
+
+		return f.PrintTable(t)
+	}
+
+	return printText(w, s, opts.Durations)
+}
+
+// Compute summarizes values, which must not be empty, with a histogram of
+// bins equal-width buckets between the minimum and the maximum. The
+// standard deviation is the sample one (n-1) and percentiles interpolate
+// linearly between the closest ranks, so the median is the 50th
+// percentile. values is sorted in place.
+func Compute(values []float64, bins int) Summary {
+	slices.Sort(values)
+
+	n := len(values)
+	s := Summary{
+		Count:       n,
+		Min:         values[0],
+		Max:         values[n-1],
+		Percentiles: make(map[string]float64, len(Percentiles)),
+	}
+
+	for _, v := range values {
+		s.Sum += v
+	}
+
+	s.Mean = s.Sum / float64(n)
+
+	if n > 1 {
+		var sq float64
+		for _, v := range values {
+			sq += (v - s.Mean) * (v - s.Mean)
+		}
+
+		s.StdDev = math.Sqrt(sq / float64(n-1))
+	}
+
+	for _, p := range Percentiles {
+		s.Percentiles[percentileName(p)] = percentile(values, p)
+	}
+
+	s.Median = s.Percentiles["p50"]
+	s.Histogram = histogram(values, bins)
+	s.Sparkline = sparkline(s.Histogram)
+
+	return s
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// between the two closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	pos := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+func percentileName(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// histogram counts sorted values into bins equal-width buckets. When all
+// values are equal there is a single bucket.
+func histogram(sorted []float64, bins int) []Bucket {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if lo == hi {
+		return []Bucket{{Low: lo, High: hi, Count: len(sorted)}}
+	}
+
+	width := (hi - lo) / float64(bins)
+	buckets := make([]Bucket, bins)
+
+	for i := range buckets {
+		buckets[i].Low = lo + width*float64(i)
+		buckets[i].High = lo + width*float64(i+1)
+	}
+
+	buckets[bins-1].High = hi
+
+	for _, v := range sorted {
+		i := min(int((v-lo)/width), bins-1)
+		buckets[i].Count++
+	}
+
+	return buckets
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws one block per bucket, scaled to the fullest bucket.
+// Empty buckets are blank so gaps in the distribution stand out.
+func sparkline(buckets []Bucket) string {
+	peak := 0
+	for _, b := range buckets {
+		peak = max(peak, b.Count)
+	}
+
+	var sb strings.Builder
+
+	for _, b := range buckets {
+		if b.Count == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+
+		sb.WriteRune(sparks[(b.Count*len(sparks)-1)/peak])
+	}
+
+	return sb.String()
+}
+
+// field returns the n-th (1-based) field of line: split on delim when set,
+// otherwise on runs of blanks. A missing field is empty.
+func field(line string, n int, delim string) string {
+	var fields []string
+	if delim == "" {
+		fields = strings.Fields(line)
+	} else {
+		fields = strings.Split(line, delim)
+	}
+
+	if n > len(fields) {
+		return ""
+	}
+
+	return strings.TrimSpace(fields[n-1])
+}
+
+// parseValue reads a number, or a duration in seconds when durations is
+// set. A bare number is then taken as seconds.
+func parseValue(s string, durations bool) (float64, error) {
+	if s == "" {
+		return 0, errors.New("missing value")
+	}
+
+	if durations {
+		d, err := timeparse.ParseDuration(s, time.Second)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		return d.Seconds(), nil
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+
+	return v, nil
+}
+
+// rows returns the report as name/value pairs, in print order.
+func rows(s Summary, durations bool) [][2]string {
+	format := formatNumber
+	if durations {
+		format = formatSeconds
+	}
+
+	out := [][2]string{{"count", strconv.Itoa(s.Count)}}
+
+	if s.Skipped > 0 {
+		out = append(out, [2]string{"skipped", strconv.Itoa(s.Skipped)})
+	}
+
+	out = append(out,
+		[2]string{"sum", format(s.Sum)},
+		[2]string{"mean", format(s.Mean)},
+		[2]string{"stddev", format(s.StdDev)},
+		[2]string{"min", format(s.Min)})
+
+	for _, p := range Percentiles {
+		name := percentileName(p)
+		if p == 50 {
+			name = "median"
+		}
+
+		out = append(out, [2]string{name, format(s.Percentiles[percentileName(p)])})
+	}
+
+	return append(out,
+		[2]string{"max", format(s.Max)},
+		[2]string{"hist", fmt.Sprintf("%s  %s .. %s", s.Sparkline, format(s.Min), format(s.Max))})
+}
+
+func printText(w io.Writer, s Summary, durations bool) error {
+	bw := bufio.NewWriter(w)
+
+	for _, r := range rows(s, durations) {
+		_, _ = fmt.Fprintf(bw, "%-8s %s\n", r[0], r[1])
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("stats: write: %s", err))
+	}
+
+	return nil
+}
+
+// formatNumber prints whole numbers without a fraction and others with
+// six significant digits.
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// formatSeconds prints a number of seconds as a duration, to the
+// microsecond below one second.
+func formatSeconds(v float64) string {
+	d := time.Duration(v * float64(time.Second))
+	if d.Abs() < time.Second {
+		d = d.Round(time.Microsecond)
+	} else {
+		d = d.Round(time.Millisecond)
+	}
+
+	return timeparse.FormatDuration(d)
+}
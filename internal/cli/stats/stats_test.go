@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+)
+
+func TestCompute(t *testing.T) {
+	values := []float64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5}
+
+	s := Compute(values, 5)
+
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"sum", s.Sum, 55},
+		{"mean", s.Mean, 5.5},
+		{"median", s.Median, 5.5},
+		{"stddev", s.StdDev, 3.0276503540974917},
+		{"min", s.Min, 1},
+		{"max", s.Max, 10},
+		{"p90", s.Percentiles["p90"], 9.1},
+		{"p99", s.Percentiles["p99"], 9.91},
+	}
+
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	if s.Count != 10 || len(s.Histogram) != 5 {
+		t.Fatalf("count = %d, buckets = %d", s.Count, len(s.Histogram))
+	}
+
+	for i, b := range s.Histogram {
+		if b.Count != 2 {
+			t.Errorf("bucket %d = %+v, want 2 values", i, b)
+		}
+	}
+
+	if s.Sparkline != "█████" {
+		t.Errorf("sparkline = %q", s.Sparkline)
+	}
+}
+
+func TestComputeSingleValue(t *testing.T) {
+	s := Compute([]float64{42, 42}, 10)
+
+	if s.StdDev != 0 || s.Median != 42 || len(s.Histogram) != 1 || s.Histogram[0].Count != 2 {
+		t.Errorf("Compute() = %+v", s)
+	}
+}
+
+func TestSparklineGaps(t *testing.T) {
+	got := sparkline([]Bucket{{Count: 8}, {Count: 0}, {Count: 1}, {Count: 4}})
+	if got != "█ ▁▄" {
+		t.Errorf("sparkline() = %q", got)
+	}
+}
+
+func TestRunField(t *testing.T) {
+	in := "path ms\n/a 12\n/b 30\n\n/c oops\n/d 18\n"
+
+	var buf bytes.Buffer
+	if err := Run(&buf, strings.NewReader(in), nil, Options{Field: 2, Header: 1}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"count    3\n", "skipped  1\n", "sum      60\n", "median   18\n", "max      30\n", "hist "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDelimiterJSON(t *testing.T) {
+	in := "a,1.5\nb,2.5\n"
+
+	var buf bytes.Buffer
+	if err := Run(&buf, strings.NewReader(in), nil, Options{Field: 2, Delimiter: ",", OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var s Summary
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if s.Count != 2 || s.Mean != 2 || s.Percentiles["p95"] != 2.45 {
+		t.Errorf("summary = %+v", s)
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Run(&buf, strings.NewReader("1\n2\n3\n"), nil, Options{OutputFormat: output.FormatCSV}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"STAT,VALUE\n", "count,3\n", "median,2\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDurations(t *testing.T) {
+	in := "250ms\n1.5s\n750ms\n"
+
+	var buf bytes.Buffer
+	if err := Run(&buf, strings.NewReader(in), nil, Options{Durations: true}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"sum      2.5s\n", "min      250ms\n", "median   750ms\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		opts Options
+	}{
+		{"no numbers", "a\nb\n", Options{}},
+		{"empty", "", Options{}},
+		{"strict", "1\nx\n", Options{Strict: true}},
+		{"bad field", "1\n", Options{Field: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Run(&bytes.Buffer{}, strings.NewReader(tt.in), nil, tt.opts)
+			if !cmderr.IsInvalidInput(err) {
+				t.Errorf("Run() error = %v, want invalid input", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Package supervise runs a command under a restart policy: a tiny process
+// supervisor for Taskfile daemons and other long-running helpers.
+package supervise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/logger"
+)
+
+// Restart policies for Options.Restart.
+const (
+	RestartNo        = "no"         // run once
+	RestartOnFailure = "on-failure" // restart when the command exits non-zero
+	RestartAlways    = "always"     // restart whenever the command exits
+)
+
+// Defaults used when the corresponding option is zero.
+const (
+	DefaultBackoff    = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+	DefaultGrace      = 10 * time.Second
+)
+
+// Options configures the supervisor.
+type Options struct {
+	Restart    string           // --restart: no, on-failure or always
+	MaxRetries int              // --max-retries: restarts allowed (0 = unlimited)
+	Backoff    time.Duration    // --backoff: delay before the first restart, doubled after each failure
+	MaxBackoff time.Duration    // --max-backoff: cap on the delay; a run this long resets the backoff
+	Grace      time.Duration    // --grace: time between forwarding a stop signal and killing
+	Env        []string         // -e: extra KEY=VALUE pairs for the command
+	Quiet      bool             // -q: no supervisor messages on stderr
+	Stdin      io.Reader        // command standard input
+	Signals    <-chan os.Signal // stop signals to forward to the command
+	Logger     *logger.Logger   // receives start, exit and restart events
+}
+
+// Run starts args[0] with args[1:] and restarts it according to
+// opts.Restart, waiting an exponentially growing delay between attempts.
+// A signal on opts.Signals (or cancelling ctx, which sends SIGTERM) is
+// forwarded to the running command; if it has not exited after
+// opts.Grace it is killed. No restart follows a stop request.
+//
+// The command's exit status becomes the returned error's exit code.
+//
+// Sanctioned exec exception: supervising an operator-supplied command is
+// the feature. See docs/architecture/patterns.md § "No-exec invariant:
+// scope & sanctioned exceptions".
+func Run(ctx context.Context, stdout, stderr io.Writer, args []string, opts Options) error {
+	if len(args) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "env run: no command specified")
+	}
+
+	if err := normalize(&opts); err != nil {
+		return err
+	}
+
+	path, err := osexec.LookPath(args[0])
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrUnsupported, fmt.Sprintf("env run: %s: command not found", args[0]))
+	}
+
+	s := &supervisor{stdout: stdout, stderr: stderr, path: path, args: args, opts: opts}
+
+	return s.loop(ctx)
+}
+
+func normalize(opts *Options) error {
+	switch opts.Restart {
+	case "":
+		opts.Restart = RestartNo
+	case RestartNo, RestartOnFailure, RestartAlways:
+	default:
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("env run: invalid --restart %q (want no, on-failure or always)", opts.Restart))
+	}
+
+	if opts.MaxRetries < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "env run: --max-retries must not be negative")
+	}
+
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBackoff
+	}
+
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+
+	opts.MaxBackoff = max(opts.MaxBackoff, opts.Backoff)
+
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultGrace
+	}
+
+	for _, kv := range opts.Env {
+		if k, _, ok := strings.Cut(kv, "="); !ok || k == "" {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("env run: invalid -e %q (want KEY=VALUE)", kv))
+		}
+	}
+
+	return nil
+}
+
+type supervisor struct {
+	stdout, stderr io.Writer
+	path           string
+	args           []string
+	opts           Options
+}
+
+func (s *supervisor) loop(ctx context.Context) error {
+	var (
+		restarts int
+		failures int // consecutive short runs, for the backoff
+	)
+
+	for attempt := 1; ; attempt++ {
+		started := time.Now()
+		stopped, err := s.runOnce(ctx, attempt)
+		uptime := time.Since(started)
+
+		code := exitCode(err)
+		s.opts.Logger.LogRaw("supervise_exit", "cmd", s.args[0], "attempt", attempt,
+			"exit_code", code, "duration_ms", uptime.Milliseconds(), "stopped", stopped)
+
+		if stopped {
+			// Dying from the forwarded signal is a clean shutdown
+			var exitErr *osexec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() == -1 {
+				return nil
+			}
+
+			return result(s.args[0], err)
+		}
+
+		if !s.shouldRestart(code) {
+			return result(s.args[0], err)
+		}
+
+		if s.opts.MaxRetries > 0 && restarts >= s.opts.MaxRetries {
+			s.notify("%s exited with status %d; giving up after %d restarts", s.args[0], code, restarts)
+
+			return result(s.args[0], err)
+		}
+
+		// A run that outlived the longest delay was healthy: start over
+		if uptime >= s.opts.MaxBackoff {
+			failures = 0
+		}
+
+		delay := s.backoff(failures)
+		failures++
+		restarts++
+
+		s.notify("%s exited with status %d; restarting in %s (restart %s)", s.args[0], code, delay, s.restartLabel(restarts))
+		s.opts.Logger.LogRaw("supervise_restart", "cmd", s.args[0], "restart", restarts, "delay_ms", delay.Milliseconds())
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return result(s.args[0], err)
+		case <-s.opts.Signals:
+			timer.Stop()
+
+			return result(s.args[0], err)
+		}
+	}
+}
+
+// runOnce starts the command and waits for it, forwarding stop requests.
+// stopped reports whether a stop request arrived while it ran.
+func (s *supervisor) runOnce(ctx context.Context, attempt int) (stopped bool, err error) {
+	cmd := osexec.Command(s.path, s.args[1:]...)
+	cmd.Stdin = s.opts.Stdin
+	cmd.Stdout = s.stdout
+	cmd.Stderr = s.stderr
+	cmd.Env = append(os.Environ(), s.opts.Env...)
+
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	s.opts.Logger.LogRaw("supervise_start", "cmd", s.args[0], "args", s.args[1:], "attempt", attempt, "pid", cmd.Process.Pid)
+
+	done := make(chan error, 1)
+
+	go func() { done <- cmd.Wait() }()
+
+	var (
+		grace   <-chan time.Time
+		ctxDone = ctx.Done()
+		signals = s.opts.Signals
+	)
+
+	stop := func(sig os.Signal) {
+		stopped = true
+		ctxDone, signals = nil, nil
+		grace = time.After(s.opts.Grace)
+
+		s.notify("forwarding %s to %s (pid %d)", sig, s.args[0], cmd.Process.Pid)
+		s.opts.Logger.LogRaw("supervise_stop", "cmd", s.args[0], "signal", sig.String(), "pid", cmd.Process.Pid)
+
+		// Platforms that cannot deliver the signal get an immediate kill
+		if err := cmd.Process.Signal(sig); err != nil {
+			_ = cmd.Process.Kill()
+		}
+	}
+
+	for {
+		select {
+		case err := <-done:
+			return stopped, err
+		case <-ctxDone:
+			stop(syscall.SIGTERM)
+		case sig := <-signals:
+			stop(sig)
+		case <-grace:
+			s.notify("%s did not exit within %s; killing", s.args[0], s.opts.Grace)
+
+			_ = cmd.Process.Kill()
+			grace = nil
+		}
+	}
+}
+
+func (s *supervisor) shouldRestart(code int) bool {
+	switch s.opts.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return code != 0
+	default:
+		return false
+	}
+}
+
+// backoff returns Backoff doubled once per consecutive failure, capped at
+// MaxBackoff.
+func (s *supervisor) backoff(failures int) time.Duration {
+	d := s.opts.Backoff
+
+	for range failures {
+		if d >= s.opts.MaxBackoff/2 {
+			return s.opts.MaxBackoff
+		}
+
+		d *= 2
+	}
+
+	return min(d, s.opts.MaxBackoff)
+}
+
+func (s *supervisor) restartLabel(n int) string {
+	if s.opts.MaxRetries == 0 {
+		return fmt.Sprint(n)
+	}
+
+	return fmt.Sprintf("%d of %d", n, s.opts.MaxRetries)
+}
+
+func (s *supervisor) notify(format string, args ...any) {
+	if s.opts.Quiet {
+		return
+	}
+
+	_, _ = fmt.Fprintf(s.stderr, "env run: "+format+"\n", args...)
+}
+
+// exitCode returns the command's exit status: 0 for success, the process
+// exit code, or 1 when it was killed by a signal or did not start.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *osexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+
+	return 1
+}
+
+// result turns the last run's error into the command's error, keeping the
+// exit status. The command has already reported its own failure, so the
+// message only names it.
+func result(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *osexec.ExitError
+	if errors.As(err, &exitErr) {
+		return cmderr.WithExitCode(fmt.Errorf("env run: %s: %s", name, exitErr), exitCode(err))
+	}
+
+	return fmt.Errorf("env run: %s: %w", name, err)
+}
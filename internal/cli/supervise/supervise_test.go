@@ -0,0 +1,211 @@
+package supervise
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// TestHelperProcess is not a real test: the supervisor re-runs the test
+// binary with SUPERVISE_HELPER set, and it then behaves as the supervised
+// command. Each run appends a line to SUPERVISE_COUNT; the process exits
+// with SUPERVISE_EXIT until SUPERVISE_SUCCEED_AT runs have happened, or
+// sleeps when SUPERVISE_SLEEP is set.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("SUPERVISE_HELPER") != "1" {
+		return
+	}
+
+	countFile := os.Getenv("SUPERVISE_COUNT")
+
+	f, err := os.OpenFile(countFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		os.Exit(100)
+	}
+
+	_, _ = f.WriteString("run\n")
+	_ = f.Close()
+
+	data, _ := os.ReadFile(countFile)
+	runs := strings.Count(string(data), "\n")
+
+	if d := os.Getenv("SUPERVISE_SLEEP"); d != "" {
+		dur, _ := time.ParseDuration(d)
+		time.Sleep(dur)
+	}
+
+	if at, _ := strconv.Atoi(os.Getenv("SUPERVISE_SUCCEED_AT")); at > 0 && runs >= at {
+		os.Exit(0)
+	}
+
+	code, _ := strconv.Atoi(os.Getenv("SUPERVISE_EXIT"))
+	os.Exit(code)
+}
+
+func helper(t *testing.T, env ...string) ([]string, Options, func() int) {
+	t.Helper()
+
+	count := filepath.Join(t.TempDir(), "count")
+
+	opts := Options{
+		Backoff:    time.Millisecond,
+		MaxBackoff: 4 * time.Millisecond,
+		Quiet:      true,
+		Env:        append([]string{"SUPERVISE_HELPER=1", "SUPERVISE_COUNT=" + count}, env...),
+	}
+
+	runs := func() int {
+		data, _ := os.ReadFile(count)
+
+		return strings.Count(string(data), "\n")
+	}
+
+	return []string{os.Args[0], "-test.run=^TestHelperProcess$"}, opts, runs
+}
+
+func TestRunPolicies(t *testing.T) {
+	tests := []struct {
+		name       string
+		restart    string
+		maxRetries int
+		env        []string
+		wantRuns   int
+		wantCode   int // 0 = success
+	}{
+		{"no restart on success", RestartNo, 0, nil, 1, 0},
+		{"no restart on failure", RestartNo, 0, []string{"SUPERVISE_EXIT=3"}, 1, 3},
+		{"on-failure gives up", RestartOnFailure, 2, []string{"SUPERVISE_EXIT=3"}, 3, 3},
+		{"on-failure recovers", RestartOnFailure, 5, []string{"SUPERVISE_EXIT=1", "SUPERVISE_SUCCEED_AT=3"}, 3, 0},
+		{"on-failure ignores success", RestartOnFailure, 5, nil, 1, 0},
+		{"always restarts success", RestartAlways, 2, nil, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, opts, runs := helper(t, tt.env...)
+			opts.Restart = tt.restart
+			opts.MaxRetries = tt.maxRetries
+
+			err := Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, args, opts)
+
+			if got := runs(); got != tt.wantRuns {
+				t.Errorf("runs = %d, want %d", got, tt.wantRuns)
+			}
+
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+
+				return
+			}
+
+			if got := cmderr.ExitCodeFor(err); got != tt.wantCode {
+				t.Errorf("exit code = %d (%v), want %d", got, err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRunNotifies(t *testing.T) {
+	args, opts, _ := helper(t, "SUPERVISE_EXIT=2")
+	opts.Restart = RestartOnFailure
+	opts.MaxRetries = 1
+	opts.Quiet = false
+
+	var stderr bytes.Buffer
+
+	_ = Run(context.Background(), &bytes.Buffer{}, &stderr, args, opts)
+
+	for _, want := range []string{"exited with status 2; restarting in 1ms (restart 1 of 1)", "giving up after 1 restarts"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr %q does not contain %q", stderr.String(), want)
+		}
+	}
+}
+
+func TestRunForwardsStopSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be forwarded on windows")
+	}
+
+	args, opts, runs := helper(t, "SUPERVISE_SLEEP=10s")
+	opts.Restart = RestartAlways
+	opts.Grace = 5 * time.Second
+
+	signals := make(chan os.Signal, 1)
+	opts.Signals = signals
+
+	done := make(chan error, 1)
+
+	go func() { done <- Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, args, opts) }()
+
+	// Wait for the helper to start before stopping it
+	for deadline := time.Now().Add(5 * time.Second); runs() == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	signals <- syscall.SIGTERM
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v, want a clean shutdown", err)
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("Run() did not return after the stop signal")
+	}
+
+	if got := runs(); got != 1 {
+		t.Errorf("runs = %d, want 1 (no restart after stop)", got)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		opts  Options
+		check func(error) bool
+	}{
+		{"no command", nil, Options{}, cmderr.IsInvalidInput},
+		{"bad policy", []string{"x"}, Options{Restart: "sometimes"}, cmderr.IsInvalidInput},
+		{"negative retries", []string{"x"}, Options{MaxRetries: -1}, cmderr.IsInvalidInput},
+		{"bad env", []string{"x"}, Options{Env: []string{"NOEQUALS"}}, cmderr.IsInvalidInput},
+		{"missing command", []string{"omni-no-such-command-xyz"}, Options{}, cmderr.IsUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Run(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, tt.args, tt.opts)
+			if !tt.check(err) {
+				t.Errorf("Run() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	s := &supervisor{opts: Options{Backoff: time.Second, MaxBackoff: 10 * time.Second}}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+
+	for failures, w := range want {
+		if got := s.backoff(failures); got != w {
+			t.Errorf("backoff(%d) = %s, want %s", failures, got, w)
+		}
+	}
+
+	if got := s.backoff(1000); got != 10*time.Second {
+		t.Errorf("backoff(1000) = %s, want the cap", got)
+	}
+}
@@ -2,13 +2,17 @@ package tail
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/flagvalue"
 	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
@@ -17,6 +21,7 @@ import (
 type TailOptions struct {
 	Lines        int           // -n: number of lines to print
 	Bytes        int           // -c: number of bytes to print
+	FromStart    bool          // +NUM: output starting with line (or byte) NUM instead
 	Follow       bool          // -f: output appended data as file grows
 	Quiet        bool          // -q: never print headers
 	Verbose      bool          // -v: always print headers
@@ -34,29 +39,28 @@ type TailResult struct {
 const DefaultTailLines = 10
 
 // RunTail executes the tail command
-// r is the default input reader (used when args is empty or contains "-")
+// r is the default input reader (used when args is empty or contains "-").
+// Files that cannot be opened are reported on stderr and skipped; the exit
+// status is then 1.
 func RunTail(w io.Writer, r io.Reader, args []string, opts TailOptions) error {
-	if opts.Lines == 0 && opts.Bytes == 0 {
+	if opts.Lines == 0 && opts.Bytes == 0 && !opts.FromStart {
 		opts.Lines = DefaultTailLines
 	}
 
+	// tail -n -K is the same as tail -n K
+	opts.Lines = abs(opts.Lines)
+	opts.Bytes = abs(opts.Bytes)
+
 	if opts.Sleep == 0 {
 		opts.Sleep = time.Second
 	}
 
-	sources, err := input.Open(args, r)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("tail: %s", err))
-		}
-		if errors.Is(err, os.ErrPermission) {
-			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("tail: %s", err))
-		}
-		return fmt.Errorf("tail: %w", err)
+	names := args
+	if len(names) == 0 {
+		names = []string{"-"}
 	}
-	defer input.CloseAll(sources)
 
-	showHeaders := len(sources) > 1 || opts.Verbose
+	showHeaders := len(names) > 1 || opts.Verbose
 	if opts.Quiet {
 		showHeaders = false
 	}
@@ -64,92 +68,168 @@ func RunTail(w io.Writer, r io.Reader, args []string, opts TailOptions) error {
 	f := output.New(w, opts.OutputFormat)
 	jsonMode := f.IsJSON()
 
-	var results []TailResult
+	var (
+		results []TailResult
+		failed  bool
+		printed bool
+	)
 
-	for i, src := range sources {
-		if showHeaders {
-			if i > 0 {
-				_, _ = fmt.Fprintln(w)
-			}
+	for _, name := range names {
+		src, err := input.OpenOne([]string{name}, r)
+		if err != nil {
+			failed = true
 
-			_, _ = fmt.Fprintf(w, "==> %s <==\n", src.Name)
+			_, _ = fmt.Fprintf(os.Stderr, "tail: %s\n", err)
+
+			continue
 		}
 
 		if jsonMode {
-			lines, err := tailLinesJSON(src.Reader, opts.Lines)
+			var buf bytes.Buffer
+
+			err = tail(&buf, src.Reader, opts)
+			input.MustClose(&src)
+
 			if err != nil {
-				return err
+				return fmt.Errorf("tail: %s: %w", src.Name, err)
 			}
 
-			results = append(results, TailResult{File: src.Name, Lines: lines})
+			results = append(results, TailResult{File: src.Name, Lines: splitLines(buf.Bytes())})
 
 			continue
 		}
 
-		if opts.Bytes > 0 {
-			if err := tailBytes(w, src.Reader, opts.Bytes); err != nil {
-				return err
-			}
-		} else {
-			if err := tailLines(w, src.Reader, opts.Lines); err != nil {
-				return err
+		if showHeaders {
+			if printed {
+				_, _ = fmt.Fprintln(w)
 			}
+
+			_, _ = fmt.Fprintf(w, "==> %s <==\n", src.Name)
+		}
+
+		printed = true
+
+		if err := tail(w, src.Reader, opts); err != nil {
+			input.MustClose(&src)
+			return fmt.Errorf("tail: %s: %w", src.Name, err)
 		}
 
 		// Handle -f (follow) mode - only works with files, not stdin
 		if opts.Follow {
 			if f, ok := src.Reader.(*os.File); ok {
 				if err := followFile(w, f, opts.Sleep); err != nil {
+					input.MustClose(&src)
 					return err
 				}
 			}
 		}
+
+		input.MustClose(&src)
 	}
 
 	if jsonMode {
-		return f.Print(results)
+		if err := f.Print(results); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return cmderr.PartialFailure(1, "tail: some operands failed")
 	}
 
 	return nil
 }
 
-func tailLinesJSON(r io.Reader, n int) ([]string, error) {
-	scanner := bufio.NewScanner(r)
-	lines := make([]string, 0, n)
+func tail(w io.Writer, r io.Reader, opts TailOptions) error {
+	switch {
+	case opts.Bytes > 0 && opts.FromStart:
+		return tailBytesFrom(w, r, opts.Bytes)
+	case opts.Bytes > 0:
+		return tailBytes(w, r, opts.Bytes)
+	case opts.FromStart:
+		return tailLinesFrom(w, r, opts.Lines)
+	default:
+		return tailLines(w, r, opts.Lines)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// splitLines splits output into lines without their terminators.
+func splitLines(data []byte) []string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, max(len(data), bufio.MaxScanTokenSize))
+
+	lines := []string{}
 
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
-		if len(lines) > n {
-			lines = lines[1:]
-		}
 	}
 
-	return lines, scanner.Err()
+	return lines
 }
 
+// tailLines copies the last n lines, keeping their original terminators.
 func tailLines(w io.Writer, r io.Reader, n int) error {
-	// Read all lines into a circular buffer
-	scanner := bufio.NewScanner(r)
-	lines := make([]string, 0, n)
+	br := bufio.NewReader(r)
+	lines := make([][]byte, 0, n+1)
 
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-		if len(lines) > n {
-			lines = lines[1:]
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			lines = append(lines, line)
+			if len(lines) > n {
+				lines = lines[1:]
+			}
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return err
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, line := range lines {
-		_, _ = fmt.Fprintln(w, line)
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// tailLinesFrom copies everything starting with line n (1-based).
+func tailLinesFrom(w io.Writer, r io.Reader, n int) error {
+	br := bufio.NewReader(r)
+
+	for i := 1; i < n; i++ {
+		if _, err := br.ReadSlice('\n'); err != nil {
+			switch {
+			case err == io.EOF:
+				return nil
+			case errors.Is(err, bufio.ErrBufferFull):
+				// Long line: keep skipping until its terminator
+				i--
+			default:
+				return err
+			}
+		}
+	}
+
+	_, err := io.Copy(w, br)
+
+	return err
+}
+
 func tailBytes(w io.Writer, r io.Reader, n int) error {
 	// For seekable readers, seek to end and read backwards
 	if seeker, ok := r.(io.ReadSeeker); ok {
@@ -181,6 +261,54 @@ func tailBytes(w io.Writer, r io.Reader, n int) error {
 	return err
 }
 
+// tailBytesFrom copies everything starting with byte n (1-based).
+func tailBytesFrom(w io.Writer, r io.Reader, n int) error {
+	skip := int64(max(n-1, 0))
+
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		if _, err := seeker.Seek(skip, io.SeekStart); err == nil {
+			_, err = io.Copy(w, r)
+			return err
+		}
+	}
+
+	if _, err := io.CopyN(io.Discard, r, skip); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+
+		return err
+	}
+
+	_, err := io.Copy(w, r)
+
+	return err
+}
+
+// ParseCount parses a -n or -c argument: a number with an optional leading
+// '+' (start at item NUM) or '-' (same as none) and an optional size suffix
+// as read by flagvalue.ParseByteSize: b (512), K (1024), KB (1000), ...
+func ParseCount(s string) (n int, fromStart bool, err error) {
+	fromStart = strings.HasPrefix(s, "+")
+	num := strings.TrimPrefix(strings.TrimPrefix(s, "+"), "-")
+
+	n, err = parseSize(num)
+	if err != nil {
+		return 0, false, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("tail: invalid number: %q", s))
+	}
+
+	return n, fromStart, nil
+}
+
+func parseSize(s string) (int, error) {
+	n, err := flagvalue.ParseByteSize(s)
+	if err != nil || n > math.MaxInt {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	return int(n), nil
+}
+
 func followFile(w io.Writer, f *os.File, sleep time.Duration) error {
 	reader := bufio.NewReader(f)
 
@@ -11,7 +11,8 @@ import (
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
-// TestTailLinesJSONHelper checks the circular-buffer line tail used by JSON mode.
+// TestTailLinesJSONHelper checks the circular-buffer line tail as JSON mode
+// splits it.
 func TestTailLinesJSONHelper(t *testing.T) {
 	tests := []struct {
 		name string
@@ -26,10 +27,11 @@ func TestTailLinesJSONHelper(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tailLinesJSON(strings.NewReader(tt.in), tt.n)
-			if err != nil {
+			var buf bytes.Buffer
+			if err := tailLines(&buf, strings.NewReader(tt.in), tt.n); err != nil {
 				t.Fatal(err)
 			}
+			got := splitLines(buf.Bytes())
 			if len(got) != len(tt.want) {
 				t.Fatalf("got %v, want %v", got, tt.want)
 			}
@@ -161,3 +163,70 @@ func TestRunTailMissingFile(t *testing.T) {
 		t.Error("expected error for missing file")
 	}
 }
+
+// TestRunTailFromStart covers +NUM for lines and bytes.
+func TestRunTailFromStart(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(p, []byte("h\nr1\nr2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts TailOptions
+		want string
+	}{
+		{"lines", TailOptions{Lines: 2, FromStart: true}, "r1\nr2\n"},
+		{"line one", TailOptions{Lines: 1, FromStart: true}, "h\nr1\nr2\n"},
+		{"past end", TailOptions{Lines: 9, FromStart: true}, ""},
+		{"bytes", TailOptions{Bytes: 3, FromStart: true}, "r1\nr2\n"},
+		{"negative lines", TailOptions{Lines: -1}, "r2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RunTail(&buf, nil, []string{p}, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("RunTail() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	// Non-seekable input takes the discard path
+	var buf bytes.Buffer
+	if err := RunTail(&buf, strings.NewReader("abcdef"), nil, TailOptions{Bytes: 4, FromStart: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "def" {
+		t.Errorf("RunTail(stdin) = %q, want %q", buf.String(), "def")
+	}
+}
+
+// TestParseCount checks +NUM, -NUM and multiplier suffixes.
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      int
+		fromStart bool
+		wantErr   bool
+	}{
+		{"10", 10, false, false},
+		{"+5", 5, true, false},
+		{"-5", 5, false, false},
+		{"1K", 1024, false, false},
+		{"+2b", 1024, true, false},
+		{"ten", 0, false, true},
+	}
+
+	for _, tt := range tests {
+		n, fromStart, err := ParseCount(tt.in)
+		if (err != nil) != tt.wantErr || n != tt.want || fromStart != tt.fromStart {
+			t.Errorf("ParseCount(%q) = %d, %v, %v", tt.in, n, fromStart, err)
+		}
+	}
+}
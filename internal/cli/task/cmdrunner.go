@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
@@ -113,6 +114,40 @@ func (m *MockCommandRunner) SetError(cmd string, err error) {
 	m.Errors[cmd] = err
 }
 
+// envKey is the context key for task environment variables
+type envKey struct{}
+
+// WithEnv returns a context carrying environment variables that external
+// commands started by ShellCommandRunner inherit on top of the process env.
+func WithEnv(ctx context.Context, env map[string]string) context.Context {
+	if len(env) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, envKey{}, env)
+}
+
+// commandEnv returns the process environment extended with the task env
+// carried by ctx, in KEY=VALUE form.
+func commandEnv(ctx context.Context) []string {
+	env := os.Environ()
+
+	extra, _ := ctx.Value(envKey{}).(map[string]string)
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		env = append(env, k+"="+extra[k])
+	}
+
+	return env
+}
+
 // ShellCommandRunner runs commands via the system shell
 type ShellCommandRunner struct {
 	dir string // Working directory
@@ -185,7 +220,7 @@ func (r *ShellCommandRunner) Run(ctx context.Context, w io.Writer, args []string
 			// `x&calc` injected a chained command.
 			var b strings.Builder
 
-			env := os.Environ()
+			env := commandEnv(ctx)
 
 			for i, a := range args {
 				name := fmt.Sprintf("OMNI_ARG%d", i)
@@ -222,6 +257,11 @@ func (r *ShellCommandRunner) Run(ctx context.Context, w io.Writer, args []string
 		cmd.Dir = r.dir
 	}
 
+	// Inherit the task environment (Taskfile env/dotenv)
+	if cmd.Env == nil {
+		cmd.Env = commandEnv(ctx)
+	}
+
 	// Capture output
 	var stdout, stderr bytes.Buffer
 
@@ -0,0 +1,115 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/dotenv"
+)
+
+// loadDotenvFiles reads the given .env files relative to dir and returns the
+// merged variables. Paths may reference Taskfile vars ({{.VAR}}). When a key
+// appears in several files the first file wins, as in go-task. Missing files
+// are skipped.
+func loadDotenvFiles(dir string, files []string, resolver *VarResolver) (map[string]string, error) {
+	env := make(map[string]string)
+
+	for _, file := range files {
+		path := resolver.Expand(file)
+		if path == "" {
+			continue
+		}
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		vars, err := dotenv.ParseFile(path, dotenv.Options{})
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			if errors.Is(err, fs.ErrPermission) {
+				return nil, cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("dotenv %s: %s", file, err))
+			}
+
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("dotenv %s: %s", file, err))
+		}
+
+		for _, v := range vars {
+			if _, exists := env[v.Key]; !exists {
+				env[v.Key] = v.Value
+			}
+		}
+	}
+
+	return env, nil
+}
+
+// loadDotenv merges the Taskfile-level dotenv files into tf.Env.
+// Values set explicitly under env: take precedence over .env files.
+func (tf *Taskfile) loadDotenv() error {
+	if len(tf.Dotenv) == 0 {
+		return nil
+	}
+
+	loaded, err := loadDotenvFiles(tf.dir, tf.Dotenv, NewVarResolver(tf.Vars, nil, tf.Env))
+	if err != nil {
+		return err
+	}
+
+	if tf.Env == nil {
+		tf.Env = make(map[string]string)
+	}
+
+	for k, v := range loaded {
+		if _, exists := tf.Env[k]; !exists {
+			tf.Env[k] = v
+		}
+	}
+
+	return nil
+}
+
+// taskEnv returns the environment for a task. Precedence, lowest first:
+// Taskfile dotenv, Taskfile env, task dotenv, task env.
+func (e *Executor) taskEnv(task *Task) (map[string]string, error) {
+	env := maps.Clone(e.tf.Env)
+	if env == nil {
+		env = make(map[string]string)
+	}
+
+	if len(task.Dotenv) > 0 {
+		dir := task.dir
+		if dir == "" {
+			dir = e.tf.dir
+		}
+
+		if task.Dir != "" {
+			if filepath.IsAbs(task.Dir) {
+				dir = task.Dir
+			} else {
+				dir = filepath.Join(dir, task.Dir)
+			}
+		}
+
+		loaded, err := loadDotenvFiles(dir, task.Dotenv, NewVarResolver(e.tf.Vars, task.Vars, env))
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.name, err)
+		}
+
+		maps.Copy(env, loaded)
+	}
+
+	resolver := NewVarResolver(e.tf.Vars, task.Vars, env)
+	for k, v := range task.Env {
+		env[k] = resolver.Expand(v)
+	}
+
+	return env, nil
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTaskfileDotenv(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		".env":       "APP=from-dotenv\nSHARED=first\nPORT=8080\n",
+		".env.local": "SHARED=second\nLOCAL=yes\n",
+		"svc/.env":   "PORT=9090\nSVC=task-dotenv\n",
+		"Taskfile.yml": `version: '3'
+dotenv: ['.env', '.env.missing', '.env.{{.STAGE}}']
+vars:
+  STAGE: local
+env:
+  APP: from-env
+tasks:
+  show:
+    dir: svc
+    dotenv: ['.env']
+    env:
+      SVC: task-env
+    cmds:
+      - omni echo {{.APP}} {{.SHARED}} {{.LOCAL}} {{.PORT}} {{.SVC}}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tf, err := ParseTaskfile(filepath.Join(dir, "Taskfile.yml"))
+	if err != nil {
+		t.Fatalf("ParseTaskfile() error = %v", err)
+	}
+
+	// env: wins over dotenv, and the first dotenv file wins over later ones
+	if tf.Env["APP"] != "from-env" || tf.Env["SHARED"] != "first" || tf.Env["LOCAL"] != "yes" {
+		t.Errorf("Env = %v", tf.Env)
+	}
+
+	var buf bytes.Buffer
+
+	exec := NewExecutor(&buf, tf, Options{})
+	mock := NewMockCommandRunner()
+	exec.SetCommandRunner(mock)
+
+	if err := exec.RunTask(context.Background(), "show"); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+
+	want := []string{"echo", "from-env", "first", "yes", "9090", "task-env"}
+	if got := mock.Commands[0]; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("command = %v, want %v", got, want)
+	}
+}
+
+func TestShellRunnerTaskEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	var buf bytes.Buffer
+
+	ctx := WithEnv(context.Background(), map[string]string{"OMNI_TASK_DOTENV": "loaded"})
+	if err := NewShellCommandRunner("").Run(ctx, &buf, []string{"echo $OMNI_TASK_DOTENV"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if strings.TrimSpace(buf.String()) != "loaded" {
+		t.Errorf("output = %q, want %q", buf.String(), "loaded")
+	}
+}
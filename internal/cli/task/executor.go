@@ -12,7 +12,8 @@ import (
 
 // Executor handles task execution
 type Executor struct {
-	w         io.Writer
+	w         io.Writer // current task's writer
+	out       io.Writer // synchronized top-level writer
 	tf        *Taskfile
 	opts      Options
 	resolver  *DependencyResolver
@@ -30,8 +31,11 @@ type DefaultCommandRunner struct{}
 
 // NewExecutor creates a new task executor
 func NewExecutor(w io.Writer, tf *Taskfile, opts Options) *Executor {
+	out := &syncWriter{w: w}
+
 	return &Executor{
-		w:         w,
+		w:         out,
+		out:       out,
 		tf:        tf,
 		opts:      opts,
 		resolver:  NewDependencyResolver(tf),
@@ -156,14 +160,41 @@ func (e *Executor) executeTask(ctx context.Context, name string) error {
 		}
 	}
 
+	env, err := e.taskEnv(task)
+	if err != nil {
+		return err
+	}
+
+	// Create variable resolver
+	resolver := NewVarResolver(e.tf.Vars, task.Vars, env)
+
+	// Route this task's output through the configured output style
+	parent := e.w
+
+	w, flush := e.outputFor(task, name, resolver)
+	e.w = w
+
+	err = e.runTaskCmds(WithEnv(ctx, env), task, name, resolver)
+
+	flush(err != nil)
+	e.w = parent
+
+	if err != nil {
+		return err
+	}
+
+	e.executed[name] = true
+
+	return nil
+}
+
+// runTaskCmds prints the task header and runs its commands, deferred ones last
+func (e *Executor) runTaskCmds(ctx context.Context, task *Task, name string, resolver *VarResolver) error {
 	// Print task name
 	if !e.opts.Silent && !task.Silent {
 		_, _ = fmt.Fprintf(e.w, "task: %s\n", name)
 	}
 
-	// Create variable resolver
-	resolver := NewVarResolver(e.tf.Vars, task.Vars, e.tf.Env)
-
 	// Collect deferred commands
 	var deferredCmds []Command
 
@@ -187,8 +218,6 @@ func (e *Executor) executeTask(ctx context.Context, name string) error {
 	// Execute deferred commands
 	e.executeDeferredCommands(ctx, deferredCmds, resolver, task.Silent)
 
-	e.executed[name] = true
-
 	return nil
 }
 
@@ -257,7 +286,13 @@ func (e *Executor) executeCommand(ctx context.Context, cmd Command, resolver *Va
 // checkStatus checks if a task is up-to-date
 func (e *Executor) checkStatus(ctx context.Context, task *Task) (bool, error) {
 	// Status commands should all succeed for task to be up-to-date
-	resolver := NewVarResolver(e.tf.Vars, task.Vars, e.tf.Env)
+	env, err := e.taskEnv(task)
+	if err != nil {
+		return false, err
+	}
+
+	ctx = WithEnv(ctx, env)
+	resolver := NewVarResolver(e.tf.Vars, task.Vars, env)
 
 	for _, statusCmd := range task.Status {
 		cmdStr := resolver.Expand(statusCmd)
@@ -319,7 +354,7 @@ var omniCommands = map[string]bool{
 	"xz": true, "unxz": true, "xzcat": true,
 
 	// Data formats
-	"jq": true, "yq": true, "json": true, "yaml": true, "toml": true, "xml": true,
+	"jq": true, "yq": true, "json": true, "yaml": true, "toml": true, "xml": true, "convert": true,
 	"csv": true, "html": true, "css": true,
 
 	// Encoding
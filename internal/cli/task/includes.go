@@ -0,0 +1,449 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"gopkg.in/yaml.v3"
+)
+
+// Include is one entry of a Taskfile's includes: map. The short form is a
+// local path; the long form can also point at a remote Taskfile, which must
+// be pinned so the tasks it defines cannot change underneath the includer:
+//
+//	includes:
+//	  lib: ./lib
+//	  ci:
+//	    taskfile: https://example.com/ci/Taskfile.yml
+//	    checksum: sha256:9f86d081884c7d65...
+//	  go:
+//	    git: https://github.com/acme/tasks.git
+//	    commit: 3b18e512dba79e4c8300dd08aeb37f8e728b8dad
+//	    path: go/Taskfile.yml
+type Include struct {
+	Taskfile string `yaml:"taskfile"` // local path or https:// URL
+	Checksum string `yaml:"checksum"` // sha256 of an https:// Taskfile, required for one
+	Git      string `yaml:"git"`      // repository to fetch the Taskfile from
+	Commit   string `yaml:"commit"`   // full commit hash to check out, required with git
+	Path     string `yaml:"path"`     // Taskfile or directory within the repository
+}
+
+// UnmarshalYAML implements custom unmarshaling for Include
+func (i *Include) UnmarshalYAML(node *yaml.Node) error {
+	// Handle string shorthand: "./lib"
+	if node.Kind == yaml.ScalarNode {
+		i.Taskfile = node.Value
+		return nil
+	}
+
+	// Handle map form
+	type rawInclude Include
+
+	return node.Decode((*rawInclude)(i))
+}
+
+// isRemote reports whether the include is fetched rather than read from disk.
+func (i Include) isRemote() bool {
+	return i.Git != "" || isHTTPURL(i.Taskfile)
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// maxRemoteTaskfile caps the size of a Taskfile fetched over HTTPS.
+const maxRemoteTaskfile = 10 << 20
+
+// includeClient fetches https:// includes, and includeCacheDir locates the
+// cache they and git checkouts are kept in. gitFetch checks out one commit
+// of a repository into dir, and gitVerify checks that a cached checkout is
+// still that commit. They are variables so tests can replace them.
+var (
+	includeClient   = &http.Client{Timeout: 30 * time.Second}
+	includeCacheDir = defaultIncludeCacheDir
+	gitFetch        = gitFetchCommit
+	gitVerify       = gitVerifyCheckout
+)
+
+// defaultIncludeCacheDir returns <os.UserCacheDir>/omni/task-includes.
+func defaultIncludeCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "omni", "task-includes"), nil
+}
+
+// includeLoader resolves includes to local Taskfile paths, fetching remote
+// ones into the cache. Offline loaders only use what is already cached.
+type includeLoader struct {
+	ctx     context.Context
+	offline bool
+}
+
+// resolve returns the path of the Taskfile inc refers to. Local paths are
+// relative to dir, the directory of the including Taskfile.
+func (l *includeLoader) resolve(dir string, inc Include) (string, error) {
+	switch {
+	case inc.Git != "":
+		return l.resolveGit(inc)
+	case isHTTPURL(inc.Taskfile):
+		return l.resolveHTTPS(inc)
+	case inc.Taskfile == "":
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, "include has no taskfile or git source")
+	}
+
+	path := inc.Taskfile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	return taskfileIn(path)
+}
+
+// taskfileIn returns path, or the default Taskfile within it when path is
+// a directory.
+func taskfileIn(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("include %s not found: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		return path, nil
+	}
+
+	for _, name := range DefaultTaskfiles {
+		candidate := filepath.Join(path, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no taskfile found in included directory: %s", path)
+}
+
+// resolveHTTPS returns the cached copy of an https:// Taskfile, downloading
+// it first when it is not cached. The cache is keyed by checksum and every
+// copy is verified before use, so a pinned include never changes.
+func (l *includeLoader) resolveHTTPS(inc Include) (string, error) {
+	if !strings.HasPrefix(inc.Taskfile, "https://") {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: remote taskfiles must use https", inc.Taskfile))
+	}
+
+	sum, err := parseChecksum(inc.Checksum)
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: %s", inc.Taskfile, err))
+	}
+
+	cacheDir, err := includeCacheDir()
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+	}
+
+	path := filepath.Join(cacheDir, "sha256-"+sum+".yml")
+
+	if data, err := os.ReadFile(path); err == nil && sha256Hex(data) == sum {
+		return path, nil
+	}
+
+	if l.offline {
+		return "", cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("include %s: not in cache (offline)", inc.Taskfile))
+	}
+
+	data, err := l.download(inc.Taskfile)
+	if err != nil {
+		return "", err
+	}
+
+	if got := sha256Hex(data); got != sum {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: checksum mismatch: got sha256:%s, want sha256:%s", inc.Taskfile, got, sum))
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+	}
+
+	return path, nil
+}
+
+func (l *includeLoader) download(url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(l.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: %s", url, err))
+	}
+
+	resp, err := includeClient.Do(req)
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("include %s: %s", url, err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("include %s: %s", url, resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteTaskfile+1))
+	if err != nil {
+		return nil, cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("include %s: %s", url, err))
+	}
+
+	if len(data) > maxRemoteTaskfile {
+		return nil, cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: taskfile larger than %d bytes", url, maxRemoteTaskfile))
+	}
+
+	return data, nil
+}
+
+// resolveGit returns the Taskfile at inc.Path in a cached checkout of
+// inc.Commit, fetching the commit first when it is not cached. Checkouts
+// are keyed by repository and commit and are never updated in place.
+func (l *includeLoader) resolveGit(inc Include) (string, error) {
+	if strings.HasPrefix(inc.Git, "-") {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include: refusing git repository that looks like a flag: %q", inc.Git))
+	}
+
+	commit := strings.ToLower(inc.Commit)
+	if !isHex(commit, 40) && !isHex(commit, 64) {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: commit must be a full commit hash, got %q", inc.Git, inc.Commit))
+	}
+
+	if inc.Path != "" && !filepath.IsLocal(filepath.FromSlash(inc.Path)) {
+		return "", cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: path %q is outside the repository", inc.Git, inc.Path))
+	}
+
+	cacheDir, err := includeCacheDir()
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+	}
+
+	checkout := filepath.Join(cacheDir, "git-"+sha256Hex([]byte(inc.Git))[:16]+"-"+commit)
+
+	// Like the checksum of an https include, the commit of a cached
+	// checkout is verified on every use; one that was modified since is
+	// fetched again.
+	if _, err := os.Stat(checkout); err == nil {
+		if err := gitVerify(l.ctx, checkout, commit); err != nil {
+			if err := os.RemoveAll(checkout); err != nil {
+				return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+			}
+		}
+	}
+
+	if _, err := os.Stat(checkout); err != nil {
+		if l.offline {
+			return "", cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("include %s@%s: not in cache (offline)", inc.Git, commit))
+		}
+
+		if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+			return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+		}
+
+		tmp, err := os.MkdirTemp(cacheDir, ".git-*")
+		if err != nil {
+			return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+		}
+
+		if err := gitFetch(l.ctx, inc.Git, commit, tmp); err != nil {
+			_ = os.RemoveAll(tmp)
+			return "", err
+		}
+
+		// Another run may have fetched the same commit meanwhile; either
+		// checkout will do.
+		if err := os.Rename(tmp, checkout); err != nil {
+			_ = os.RemoveAll(tmp)
+
+			if _, statErr := os.Stat(checkout); statErr != nil {
+				return "", cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("include cache: %s", err))
+			}
+		}
+	}
+
+	return taskfileIn(filepath.Join(checkout, filepath.FromSlash(inc.Path)))
+}
+
+// gitFetchCommit checks out commit of repo into the empty directory dir
+// with a shallow fetch, then verifies that HEAD is that commit.
+func gitFetchCommit(ctx context.Context, repo, commit, dir string) error {
+	steps := [][]string{
+		{"init", "--quiet", dir},
+		{"-C", dir, "fetch", "--quiet", "--depth=1", "--", repo, commit},
+		{"-C", dir, "checkout", "--quiet", "--detach", commit},
+	}
+
+	for _, args := range steps {
+		if _, err := runGit(ctx, args...); err != nil {
+			if cmderr.IsNotFound(err) {
+				return err
+			}
+
+			return cmderr.Wrap(cmderr.ErrNetwork, fmt.Sprintf("include %s@%s: %s", repo, commit, err))
+		}
+	}
+
+	if err := gitVerifyCheckout(ctx, dir, commit); err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("include %s: %s", repo, err))
+	}
+
+	return nil
+}
+
+// gitVerifyCheckout checks that HEAD of the checkout in dir is commit and
+// that no tracked file was changed.
+func gitVerifyCheckout(ctx context.Context, dir, commit string) error {
+	head, err := runGit(ctx, "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	if head != commit {
+		return fmt.Errorf("checked out %s, want %s", head, commit)
+	}
+
+	status, err := runGit(ctx, "-C", dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return err
+	}
+
+	if status != "" {
+		return fmt.Errorf("checkout of %s has local changes", commit)
+	}
+
+	return nil
+}
+
+// runGit runs git with args and returns its trimmed standard output. git
+// never prompts: a private or mistyped repository fails at once rather
+// than waiting for credentials no one will type.
+func runGit(ctx context.Context, args ...string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return "", cmderr.Wrap(cmderr.ErrNotFound, "include: git not found in PATH")
+	}
+
+	cmd := exec.CommandContext(ctx, gitPath, args...) //nolint:gosec // sanctioned exec exception (task, argv only)
+	cmd.Env = gitEnv(os.Environ())
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		step := args[0]
+		if step == "-C" {
+			step = args[2]
+		}
+
+		return "", fmt.Errorf("git %s: %s", step, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitEnv returns environ with credential prompts disabled: no terminal
+// prompt, no askpass helper and, unless the user configured ssh for git,
+// ssh in batch mode. An empty GIT_ASKPASS, unlike an unset one, also keeps
+// git from falling back to core.askPass and SSH_ASKPASS.
+func gitEnv(environ []string) []string {
+	env := make([]string, 0, len(environ)+3)
+	sshSet := false
+
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+
+		switch name {
+		case "GIT_ASKPASS", "GIT_TERMINAL_PROMPT":
+			continue
+		case "GIT_SSH", "GIT_SSH_COMMAND":
+			sshSet = true
+		}
+
+		env = append(env, kv)
+	}
+
+	env = append(env, "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=")
+	if !sshSet {
+		env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+
+	return env
+}
+
+// parseChecksum accepts "sha256:<hex>" or a bare sha256 hex digest and
+// returns the lowercase digest.
+func parseChecksum(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("remote taskfiles require a sha256 checksum")
+	}
+
+	sum := strings.ToLower(strings.TrimPrefix(s, "sha256:"))
+	if !isHex(sum, 64) {
+		return "", fmt.Errorf("invalid sha256 checksum %q", s)
+	}
+
+	return sum, nil
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+
+	return err == nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, so a concurrent reader never sees a partial Taskfile.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".include-*")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
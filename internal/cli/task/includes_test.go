@@ -0,0 +1,341 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+const remoteTaskfile = `
+version: '3'
+vars:
+  REMOTE_VAR: remote
+tasks:
+  lint:
+    desc: Lint from the shared library
+    cmds:
+      - omni echo lint
+`
+
+// withIncludeCache points the include cache at a temporary directory.
+func withIncludeCache(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	orig := includeCacheDir
+	includeCacheDir = func() (string, error) { return dir, nil }
+
+	t.Cleanup(func() { includeCacheDir = orig })
+
+	return dir
+}
+
+// serveTaskfile serves body over TLS, counting requests.
+func serveTaskfile(t *testing.T, body string) (string, *int) {
+	t.Helper()
+
+	hits := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := includeClient
+	includeClient = srv.Client()
+
+	t.Cleanup(func() { includeClient = orig })
+
+	return srv.URL + "/Taskfile.yml", &hits
+}
+
+func writeMainTaskfile(t *testing.T, includes string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := "version: '3'\nincludes:\n" + includes + "tasks:\n  default:\n    cmds:\n      - omni echo hi\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRemoteIncludeHTTPS(t *testing.T) {
+	withIncludeCache(t)
+
+	url, hits := serveTaskfile(t, remoteTaskfile)
+	sum := sha256Hex([]byte(remoteTaskfile))
+	path := writeMainTaskfile(t, fmt.Sprintf("  shared:\n    taskfile: %s\n    checksum: sha256:%s\n", url, sum))
+
+	tf, err := ParseTaskfile(path)
+	if err != nil {
+		t.Fatalf("ParseTaskfile() error = %v", err)
+	}
+
+	lint := tf.GetTask("shared:lint")
+	if lint == nil {
+		t.Fatal("missing shared:lint task")
+	}
+
+	if lint.dir != filepath.Dir(path) {
+		t.Errorf("remote task dir = %s, want the including Taskfile's", lint.dir)
+	}
+
+	if tf.Vars["REMOTE_VAR"] != "remote" {
+		t.Errorf("REMOTE_VAR = %v", tf.Vars["REMOTE_VAR"])
+	}
+
+	// The second parse, online or offline, is served from the cache.
+	if _, err := ParseTaskfile(path); err != nil {
+		t.Fatalf("cached ParseTaskfile() error = %v", err)
+	}
+
+	if _, err := parseTaskfile(path, &includeLoader{ctx: context.Background(), offline: true}); err != nil {
+		t.Fatalf("offline parseTaskfile() error = %v", err)
+	}
+
+	if *hits != 1 {
+		t.Errorf("server hit %d times, want 1", *hits)
+	}
+}
+
+func TestRemoteIncludeHTTPSErrors(t *testing.T) {
+	withIncludeCache(t)
+
+	url, _ := serveTaskfile(t, remoteTaskfile)
+	wrong := strings.Repeat("0", 64)
+
+	tests := []struct {
+		name     string
+		includes string
+		check    func(error) bool
+	}{
+		{"checksum mismatch", fmt.Sprintf("  s:\n    taskfile: %s\n    checksum: %s\n", url, wrong), cmderr.IsInvalidInput},
+		{"missing checksum", fmt.Sprintf("  s: %s\n", url), cmderr.IsInvalidInput},
+		{"bad checksum", fmt.Sprintf("  s:\n    taskfile: %s\n    checksum: md5:abc\n", url), cmderr.IsInvalidInput},
+		{"plain http", fmt.Sprintf("  s:\n    taskfile: http://example.com/T.yml\n    checksum: %s\n", wrong), cmderr.IsInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTaskfile(writeMainTaskfile(t, tt.includes))
+			if !tt.check(err) {
+				t.Errorf("ParseTaskfile() error = %v", err)
+			}
+		})
+	}
+
+	t.Run("offline cache miss", func(t *testing.T) {
+		path := writeMainTaskfile(t, fmt.Sprintf("  s:\n    taskfile: %s\n    checksum: %s\n", url, sha256Hex([]byte(remoteTaskfile))))
+
+		_, err := parseTaskfile(path, &includeLoader{ctx: context.Background(), offline: true})
+		if !cmderr.IsNotFound(err) {
+			t.Errorf("offline parseTaskfile() error = %v, want not found", err)
+		}
+	})
+}
+
+func TestRemoteIncludeGit(t *testing.T) {
+	cache := withIncludeCache(t)
+
+	const commit = "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"
+
+	fetches := 0
+	orig := gitFetch
+	gitFetch = func(_ context.Context, repo, c, dir string) error {
+		fetches++
+
+		if repo != "https://example.com/tasks.git" || c != commit {
+			t.Errorf("gitFetch(%q, %q)", repo, c)
+		}
+
+		if err := os.MkdirAll(filepath.Join(dir, "go"), 0755); err != nil {
+			return err
+		}
+
+		return os.WriteFile(filepath.Join(dir, "go", "Taskfile.yml"), []byte(remoteTaskfile), 0644)
+	}
+
+	// A checkout verifies while its Taskfile is unchanged
+	origVerify := gitVerify
+	gitVerify = func(_ context.Context, dir, c string) error {
+		data, err := os.ReadFile(filepath.Join(dir, "go", "Taskfile.yml"))
+		if err != nil || string(data) != remoteTaskfile || c != commit {
+			return errors.New("modified")
+		}
+
+		return nil
+	}
+
+	t.Cleanup(func() { gitFetch, gitVerify = orig, origVerify })
+
+	includes := fmt.Sprintf("  go:\n    git: https://example.com/tasks.git\n    commit: %s\n    path: go\n", strings.ToUpper(commit))
+	path := writeMainTaskfile(t, includes)
+
+	offline := &includeLoader{ctx: context.Background(), offline: true}
+	if _, err := parseTaskfile(path, offline); !cmderr.IsNotFound(err) {
+		t.Fatalf("offline parseTaskfile() before fetch error = %v, want not found", err)
+	}
+
+	tf, err := ParseTaskfile(path)
+	if err != nil {
+		t.Fatalf("ParseTaskfile() error = %v", err)
+	}
+
+	if tf.GetTask("go:lint") == nil {
+		t.Error("missing go:lint task")
+	}
+
+	if _, err := parseTaskfile(path, offline); err != nil {
+		t.Fatalf("offline parseTaskfile() error = %v", err)
+	}
+
+	if fetches != 1 {
+		t.Errorf("fetched %d times, want 1", fetches)
+	}
+
+	// A modified checkout is not used but fetched again
+	checkouts, _ := filepath.Glob(filepath.Join(cache, "git-*"))
+	if len(checkouts) != 1 {
+		t.Fatalf("checkouts = %v", checkouts)
+	}
+
+	if err := os.WriteFile(filepath.Join(checkouts[0], "go", "Taskfile.yml"), []byte("version: '3'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseTaskfile(path, offline); !cmderr.IsNotFound(err) {
+		t.Errorf("offline parseTaskfile() of a modified checkout error = %v, want not found", err)
+	}
+
+	if tf, err := ParseTaskfile(path); err != nil || tf.GetTask("go:lint") == nil || fetches != 2 {
+		t.Errorf("ParseTaskfile() after modification: err = %v, fetches = %d", err, fetches)
+	}
+
+	if leftovers, _ := filepath.Glob(filepath.Join(cache, ".git-*")); len(leftovers) != 0 {
+		t.Errorf("temporary checkouts left behind: %v", leftovers)
+	}
+}
+
+func TestRemoteIncludeGitErrors(t *testing.T) {
+	withIncludeCache(t)
+
+	orig := gitFetch
+	gitFetch = func(context.Context, string, string, string) error {
+		t.Error("gitFetch called for an invalid include")
+		return nil
+	}
+
+	t.Cleanup(func() { gitFetch = orig })
+
+	commit := strings.Repeat("a", 40)
+
+	tests := map[string]string{
+		"short commit":    "  g:\n    git: https://example.com/t.git\n    commit: abc123\n",
+		"missing commit":  "  g:\n    git: https://example.com/t.git\n",
+		"flag repository": fmt.Sprintf("  g:\n    git: --upload-pack=evil\n    commit: %s\n", commit),
+		"escaping path":   fmt.Sprintf("  g:\n    git: https://example.com/t.git\n    commit: %s\n    path: ../outside\n", commit),
+	}
+
+	for name, includes := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseTaskfile(writeMainTaskfile(t, includes))
+			if !cmderr.IsInvalidInput(err) {
+				t.Errorf("ParseTaskfile() error = %v, want invalid input", err)
+			}
+		})
+	}
+}
+
+func TestGitFetchCommitLocal(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// A credential prompt would hang the test instead of failing it
+	t.Setenv("GIT_ASKPASS", "/nonexistent/askpass")
+	t.Setenv("GIT_TERMINAL_PROMPT", "1")
+
+	repo := t.TempDir()
+
+	for _, args := range [][]string{
+		{"init", "--quiet", repo},
+		{"-C", repo, "-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "--quiet", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "Taskfile.yml"), []byte(remoteTaskfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"-C", repo, "add", "Taskfile.yml"},
+		{"-C", repo, "-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "--quiet", "-m", "tasks"},
+		{"-C", repo, "config", "uploadpack.allowAnySHA1InWant", "true"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	out, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := strings.TrimSpace(string(out))
+	dir := filepath.Join(t.TempDir(), "checkout")
+	ctx := context.Background()
+
+	if err := gitFetchCommit(ctx, "file://"+filepath.ToSlash(repo), commit, dir); err != nil {
+		t.Fatalf("gitFetchCommit() error = %v", err)
+	}
+
+	if err := gitVerifyCheckout(ctx, dir, commit); err != nil {
+		t.Errorf("gitVerifyCheckout() error = %v", err)
+	}
+
+	if err := gitVerifyCheckout(ctx, dir, strings.Repeat("0", 40)); err == nil {
+		t.Error("gitVerifyCheckout() accepted another commit")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("version: '3'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gitVerifyCheckout(ctx, dir, commit); err == nil {
+		t.Error("gitVerifyCheckout() accepted a modified checkout")
+	}
+
+	if err := gitFetchCommit(ctx, "file://"+filepath.ToSlash(repo)+"-missing", commit, filepath.Join(t.TempDir(), "c")); !cmderr.IsNetwork(err) {
+		t.Errorf("gitFetchCommit() of a missing repository error = %v, want network error", err)
+	}
+}
+
+func TestGitEnv(t *testing.T) {
+	env := gitEnv([]string{"HOME=/h", "GIT_ASKPASS=/bin/askpass", "GIT_TERMINAL_PROMPT=1"})
+	want := []string{"HOME=/h", "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "GIT_SSH_COMMAND=ssh -o BatchMode=yes"}
+
+	if strings.Join(env, "\n") != strings.Join(want, "\n") {
+		t.Errorf("gitEnv() = %q, want %q", env, want)
+	}
+
+	if env := gitEnv([]string{"GIT_SSH_COMMAND=ssh -i key"}); strings.Count(strings.Join(env, "\n"), "GIT_SSH_COMMAND") != 1 {
+		t.Errorf("gitEnv() replaced the user's GIT_SSH_COMMAND: %q", env)
+	}
+}
@@ -0,0 +1,258 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// Output style names supported by the Taskfile "output" setting
+const (
+	OutputInterleaved = "interleaved"
+	OutputGroup       = "group"
+	OutputPrefixed    = "prefixed"
+)
+
+// OutputStyle configures how task output is written.
+//
+// It accepts the Taskfile spec forms:
+//
+//	output: prefixed
+//	output:
+//	  group:
+//	    begin: '::group::{{.TASK}}'
+//	    end: '::endgroup::'
+//	    error_only: true
+type OutputStyle struct {
+	Name  string       `yaml:"-"`
+	Group GroupOptions `yaml:"group"`
+}
+
+// GroupOptions holds options for the "group" output style
+type GroupOptions struct {
+	Begin     string `yaml:"begin"`
+	End       string `yaml:"end"`
+	ErrorOnly bool   `yaml:"error_only"`
+}
+
+// UnmarshalYAML implements custom unmarshaling for OutputStyle
+func (o *OutputStyle) UnmarshalYAML(node *yaml.Node) error {
+	// Handle string shorthand: "group"
+	if node.Kind == yaml.ScalarNode {
+		o.Name = node.Value
+		return nil
+	}
+
+	// Handle map form: {group: {begin, end, error_only}}
+	var raw map[string]GroupOptions
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	for name, group := range raw {
+		if name != OutputGroup {
+			return fmt.Errorf("output: only %q accepts options, got %q", OutputGroup, name)
+		}
+
+		o.Name = name
+		o.Group = group
+	}
+
+	return nil
+}
+
+// ValidateOutput checks that name is a supported output style
+func ValidateOutput(name string) error {
+	switch name {
+	case "", OutputInterleaved, OutputGroup, OutputPrefixed:
+		return nil
+	}
+
+	return cmderr.Wrap(cmderr.ErrInvalidInput,
+		fmt.Sprintf("task: invalid output style %q (want interleaved, group or prefixed)", name))
+}
+
+// syncWriter serializes writes so concurrent tasks never split a write
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w.Write(p)
+}
+
+// prefixWriter prefixes every complete line with the task name.
+// A partial trailing line is held until the next newline or Close.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if err != nil {
+			// Incomplete line: put it back until more data arrives
+			p.buf.Reset()
+			p.buf.Write(line)
+
+			break
+		}
+
+		if _, err := io.WriteString(p.w, p.prefix+string(line)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Close flushes any partial trailing line
+func (p *prefixWriter) Close() error {
+	if p.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(p.w, p.prefix+p.buf.String()+"\n")
+	p.buf.Reset()
+
+	return err
+}
+
+// groupWriter buffers all output of a task and writes it in one piece
+type groupWriter struct {
+	w         io.Writer
+	buf       bytes.Buffer
+	begin     string
+	end       string
+	errorOnly bool // discard output when the task succeeds
+}
+
+func (g *groupWriter) Write(b []byte) (int, error) {
+	return g.buf.Write(b)
+}
+
+// flush writes the buffered output, wrapped in the begin/end markers
+func (g *groupWriter) flush(failed bool) error {
+	if g.errorOnly && !failed {
+		return nil
+	}
+
+	if g.buf.Len() == 0 && g.begin == "" && g.end == "" {
+		return nil
+	}
+
+	var out bytes.Buffer
+
+	if g.begin != "" {
+		out.WriteString(g.begin + "\n")
+	}
+
+	out.Write(g.buf.Bytes())
+
+	if g.buf.Len() > 0 && !bytes.HasSuffix(g.buf.Bytes(), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+
+	if g.end != "" {
+		out.WriteString(g.end + "\n")
+	}
+
+	_, err := g.w.Write(out.Bytes())
+
+	return err
+}
+
+// prefixColors are cycled through by task name in prefixed mode
+var prefixColors = []string{
+	"\033[32m", "\033[33m", "\033[34m", "\033[35m", "\033[36m",
+	"\033[92m", "\033[93m", "\033[94m", "\033[95m", "\033[96m",
+}
+
+// taskPrefix returns the "[name] " line prefix, colored when w is a terminal
+func taskPrefix(w io.Writer, name string) string {
+	prefix := "[" + name + "] "
+	if !useColor(w) {
+		return prefix
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+
+	return prefixColors[h.Sum32()%uint32(len(prefixColors))] + "[" + name + "]\033[0m "
+}
+
+// useColor reports whether w is a terminal and NO_COLOR is unset
+func useColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if s, ok := w.(*syncWriter); ok {
+		w = s.w
+	}
+
+	f, ok := w.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// outputFor returns the writer a task should use and a function that must be
+// called once the task finished to flush buffered output.
+func (e *Executor) outputFor(task *Task, name string, resolver *VarResolver) (io.Writer, func(failed bool)) {
+	style := e.tf.Output
+	if e.opts.Output != "" && e.opts.Output != style.Name {
+		style = OutputStyle{Name: e.opts.Output}
+	}
+
+	switch style.Name {
+	case OutputGroup:
+		g := &groupWriter{
+			w:         e.w,
+			errorOnly: style.Group.ErrorOnly,
+			begin:     expandTaskName(resolver, style.Group.Begin, name),
+			end:       expandTaskName(resolver, style.Group.End, name),
+		}
+
+		return g, func(failed bool) { _ = g.flush(failed) }
+
+	case OutputPrefixed:
+		label := name
+		if task.Prefix != "" {
+			label = resolver.Expand(task.Prefix)
+		}
+
+		p := &prefixWriter{w: e.out, prefix: taskPrefix(e.out, label)}
+
+		return p, func(bool) { _ = p.Close() }
+	}
+
+	return e.w, func(bool) {}
+}
+
+// expandTaskName expands variables in a group marker, including {{.TASK}}
+func expandTaskName(resolver *VarResolver, s, name string) string {
+	if s == "" {
+		return ""
+	}
+
+	s = strings.ReplaceAll(s, "{{.TASK}}", name)
+
+	return resolver.Expand(s)
+}
@@ -0,0 +1,172 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+func TestOutputStyleUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    OutputStyle
+		wantErr bool
+	}{
+		{"scalar", "output: prefixed", OutputStyle{Name: OutputPrefixed}, false},
+		{
+			"group map",
+			"output:\n  group:\n    begin: '::group::{{.TASK}}'\n    end: '::endgroup::'\n    error_only: true",
+			OutputStyle{Name: OutputGroup, Group: GroupOptions{Begin: "::group::{{.TASK}}", End: "::endgroup::", ErrorOnly: true}},
+			false,
+		},
+		{"options on prefixed", "output:\n  prefixed:\n    begin: x", OutputStyle{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tf Taskfile
+
+			err := yaml.Unmarshal([]byte(tt.yaml), &tf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && tf.Output != tt.want {
+				t.Errorf("Output = %+v, want %+v", tf.Output, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTaskfileInvalidOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	if err := os.WriteFile(path, []byte("version: '3'\noutput: fancy\ntasks: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseTaskfile(path); !cmderr.IsInvalidInput(err) {
+		t.Errorf("expected invalid input, got %v", err)
+	}
+}
+
+func outputTaskfile(style OutputStyle) *Taskfile {
+	return &Taskfile{
+		Output: style,
+		Tasks: map[string]*Task{
+			"build": {
+				Cmds: []Command{{Cmd: "omni echo"}, {Task: "lint"}, {Cmd: "omni cat"}},
+			},
+			"lint": {
+				Prefix: "vet",
+				Cmds:   []Command{{Cmd: "omni wc"}},
+			},
+		},
+	}
+}
+
+func newOutputMock() *MockCommandRunner {
+	mock := NewMockCommandRunner()
+	mock.SetOutput("echo", "one\ntwo\n")
+	mock.SetOutput("wc", "lint ok\n")
+	mock.SetOutput("cat", "partial")
+
+	return mock
+}
+
+func TestExecutorOutputPrefixed(t *testing.T) {
+	var buf bytes.Buffer
+
+	exec := NewExecutor(&buf, outputTaskfile(OutputStyle{Name: OutputPrefixed}), Options{})
+	exec.SetCommandRunner(newOutputMock())
+
+	if err := exec.RunTask(context.Background(), "build"); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+
+	want := "[build] task: build\n[build] one\n[build] two\n" +
+		"[vet] task: lint\n[vet] lint ok\n" +
+		"[build] partial\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestExecutorOutputGroup(t *testing.T) {
+	style := OutputStyle{Name: OutputGroup, Group: GroupOptions{Begin: "::group::{{.TASK}}", End: "::endgroup::"}}
+
+	var buf bytes.Buffer
+
+	exec := NewExecutor(&buf, outputTaskfile(style), Options{})
+	exec.SetCommandRunner(newOutputMock())
+
+	if err := exec.RunTask(context.Background(), "lint"); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+
+	want := "::group::lint\ntask: lint\nlint ok\n::endgroup::\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestExecutorOutputGroupErrorOnly(t *testing.T) {
+	style := OutputStyle{Name: OutputGroup, Group: GroupOptions{ErrorOnly: true}}
+
+	var buf bytes.Buffer
+
+	exec := NewExecutor(&buf, outputTaskfile(style), Options{})
+	mock := newOutputMock()
+	exec.SetCommandRunner(mock)
+
+	if err := exec.RunTask(context.Background(), "lint"); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("successful task should print nothing, got %q", buf.String())
+	}
+
+	exec = NewExecutor(&buf, outputTaskfile(style), Options{})
+	mock.SetError("wc", errors.New("boom"))
+	exec.SetCommandRunner(mock)
+
+	if err := exec.RunTask(context.Background(), "lint"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if want := "task: lint\nlint ok\n"; buf.String() != want {
+		t.Errorf("failed task output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestExecutorOutputOverride(t *testing.T) {
+	var buf bytes.Buffer
+
+	exec := NewExecutor(&buf, outputTaskfile(OutputStyle{Name: OutputPrefixed}), Options{Output: OutputInterleaved})
+	exec.SetCommandRunner(newOutputMock())
+
+	if err := exec.RunTask(context.Background(), "lint"); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+
+	if want := "task: lint\nlint ok\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunInvalidOutput(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Run(context.Background(), &buf, nil, Options{Output: "fancy"})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("expected invalid input, got %v", err)
+	}
+}
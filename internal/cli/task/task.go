@@ -9,8 +9,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pick"
 )
 
 // Options configures the task runner
@@ -24,6 +26,8 @@ type Options struct {
 	Silent        bool   // Suppress output
 	Summary       bool   // Show task summary/description
 	AllowExternal bool   // Allow external (non-omni) commands
+	Output        string // Output style override: interleaved, group or prefixed
+	Offline       bool   // Resolve remote includes from the cache only
 }
 
 // DefaultTaskfiles lists the default taskfile names to search for
@@ -39,8 +43,18 @@ var DefaultTaskfiles = []string{
 // The dir parameter is the working directory for external commands
 var CommandRunnerFactory func(dir string, allowExternal bool) CommandRunner
 
+// isInteractive and runPicker are variables so tests can replace the TUI
+var (
+	isInteractive = pick.IsInteractive
+	runPicker     = pick.Run
+)
+
 // Run executes the task runner
 func Run(ctx context.Context, w io.Writer, taskNames []string, opts Options) error {
+	if err := ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	// Find taskfile
 	taskfilePath, err := findTaskfile(opts.Taskfile, opts.Dir)
 	if err != nil {
@@ -48,7 +62,7 @@ func Run(ctx context.Context, w io.Writer, taskNames []string, opts Options) err
 	}
 
 	// Parse taskfile
-	tf, err := ParseTaskfile(taskfilePath)
+	tf, err := parseTaskfile(taskfilePath, &includeLoader{ctx: ctx, offline: opts.Offline})
 	if err != nil {
 		return fmt.Errorf("task: %w", err)
 	}
@@ -76,12 +90,17 @@ func Run(ctx context.Context, w io.Writer, taskNames []string, opts Options) err
 		return exec.ShowSummary(taskNames)
 	}
 
-	// Run default task if none specified
+	// Run default task if none specified, otherwise let the user pick one
 	if len(taskNames) == 0 {
 		if tf.Tasks["default"] != nil {
 			taskNames = []string{"default"}
 		} else {
-			return cmderr.Wrap(cmderr.ErrInvalidInput, "task: no task specified and no default task found")
+			name, err := pickTask(exec, tf)
+			if err != nil || name == "" {
+				return err
+			}
+
+			taskNames = []string{name}
 		}
 	}
 
@@ -95,6 +114,71 @@ func Run(ctx context.Context, w io.Writer, taskNames []string, opts Options) err
 	return nil
 }
 
+// pickTask shows an interactive picker of the available tasks. When no
+// terminal is attached it prints the task list and returns an error.
+// An empty name with a nil error means the user canceled.
+func pickTask(exec *Executor, tf *Taskfile) (string, error) {
+	errNoTask := cmderr.Wrap(cmderr.ErrInvalidInput, "task: no task specified and no default task found")
+
+	names := tf.ListTaskNames()
+	if len(names) == 0 {
+		return "", errNoTask
+	}
+
+	if !isInteractive() {
+		_ = exec.ListTasks()
+		return "", errNoTask
+	}
+
+	sort.Strings(names)
+
+	items := make([]pick.Item, len(names))
+	for i, name := range names {
+		items[i] = pick.Item{Title: name, Description: tf.Tasks[name].Desc}
+	}
+
+	idx, err := runPicker(items, pick.Options{Prompt: "task> "})
+	if errors.Is(err, pick.ErrCanceled) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("task: %w", err)
+	}
+
+	return names[idx], nil
+}
+
+// CompleteTasks returns the tasks of the Taskfile Run would use, as
+// "name\tdescription" shell completion candidates sorted by name. Internal
+// tasks are left out, and any error yields no candidates. Remote includes
+// come from the cache only, so completion never waits on the network.
+func CompleteTasks(taskfile, dir string) []string {
+	path, err := findTaskfile(taskfile, dir)
+	if err != nil {
+		return nil
+	}
+
+	tf, err := parseTaskfile(path, &includeLoader{ctx: context.Background(), offline: true})
+	if err != nil {
+		return nil
+	}
+
+	names := tf.ListTaskNames()
+	sort.Strings(names)
+
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		if desc := tf.Tasks[name].Desc; desc != "" {
+			candidates = append(candidates, name+"\t"+desc)
+		} else {
+			candidates = append(candidates, name)
+		}
+	}
+
+	return candidates
+}
+
 // findTaskfile searches for a taskfile in the given directory
 func findTaskfile(path, dir string) (string, error) {
 	// If explicit path given, use it
@@ -12,6 +12,9 @@ import (
 	"testing"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/pick"
 )
 
 func TestParseTaskfile(t *testing.T) {
@@ -1312,3 +1315,108 @@ func TestTaskRunner_InjectionSafe(t *testing.T) {
 		}
 	}
 }
+
+func TestRunPicksTaskWithoutDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+
+	content := `
+version: '3'
+tasks:
+  build:
+    desc: Build it
+    cmds:
+      - omni echo build
+  lint:
+    cmds:
+      - omni echo lint
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origInteractive, origPicker := isInteractive, runPicker
+
+	defer func() { isInteractive, runPicker = origInteractive, origPicker }()
+
+	t.Run("not a terminal lists tasks", func(t *testing.T) {
+		isInteractive = func() bool { return false }
+
+		var buf bytes.Buffer
+
+		err := Run(context.Background(), &buf, nil, Options{Taskfile: path})
+		if !cmderr.IsInvalidInput(err) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "Available tasks:") || !strings.Contains(buf.String(), "Build it") {
+			t.Errorf("expected task list, got %q", buf.String())
+		}
+	})
+
+	t.Run("picker selection runs", func(t *testing.T) {
+		isInteractive = func() bool { return true }
+		runPicker = func(items []pick.Item, _ pick.Options) (int, error) {
+			if len(items) != 2 || items[0].Title != "build" || items[0].Description != "Build it" {
+				t.Errorf("unexpected items: %+v", items)
+			}
+
+			return 1, nil
+		}
+
+		var buf bytes.Buffer
+
+		if err := Run(context.Background(), &buf, nil, Options{Taskfile: path, DryRun: true}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "task: lint") {
+			t.Errorf("expected lint to run, got %q", buf.String())
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		isInteractive = func() bool { return true }
+		runPicker = func([]pick.Item, pick.Options) (int, error) { return -1, pick.ErrCanceled }
+
+		var buf bytes.Buffer
+
+		if err := Run(context.Background(), &buf, nil, Options{Taskfile: path}); err != nil {
+			t.Errorf("cancel should not error, got %v", err)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("cancel should print nothing, got %q", buf.String())
+		}
+	})
+}
+
+func TestCompleteTasks(t *testing.T) {
+	dir := t.TempDir()
+
+	content := `version: '3'
+tasks:
+  test:
+    desc: Run tests
+    cmds:
+      - echo test
+  build:
+    cmds:
+      - echo build
+  setup:
+    internal: true
+    cmds:
+      - echo setup
+`
+	if err := os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(CompleteTasks("", dir), ",")
+	if want := "build,test\tRun tests"; got != want {
+		t.Errorf("CompleteTasks() = %q, want %q", got, want)
+	}
+
+	if got := CompleteTasks("", t.TempDir()); got != nil {
+		t.Errorf("CompleteTasks(no taskfile) = %v, want nil", got)
+	}
+}
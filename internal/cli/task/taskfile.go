@@ -1,6 +1,7 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,11 +12,13 @@ import (
 
 // Taskfile represents the parsed Taskfile.yml
 type Taskfile struct {
-	Version  string            `yaml:"version"`
-	Vars     map[string]any    `yaml:"vars"`
-	Env      map[string]string `yaml:"env"`
-	Tasks    map[string]*Task  `yaml:"tasks"`
-	Includes map[string]string `yaml:"includes"`
+	Version  string             `yaml:"version"`
+	Vars     map[string]any     `yaml:"vars"`
+	Env      map[string]string  `yaml:"env"`
+	Dotenv   []string           `yaml:"dotenv"` // .env files, relative to the Taskfile
+	Tasks    map[string]*Task   `yaml:"tasks"`
+	Includes map[string]Include `yaml:"includes"`
+	Output   OutputStyle        `yaml:"output"`
 
 	// Internal fields
 	dir string // Directory containing this taskfile
@@ -23,22 +26,26 @@ type Taskfile struct {
 
 // Task represents a single task definition
 type Task struct {
-	Desc         string         `yaml:"desc"`
-	Summary      string         `yaml:"summary"`
-	Cmds         []Command      `yaml:"cmds"`
-	Deps         []Dependency   `yaml:"deps"`
-	Vars         map[string]any `yaml:"vars"`
-	Status       []string       `yaml:"status"` // Commands to check if task is up-to-date
-	Sources      []string       `yaml:"sources"`
-	Generates    []string       `yaml:"generates"`
-	Dir          string         `yaml:"dir"`
-	Silent       bool           `yaml:"silent"`
-	Internal     bool           `yaml:"internal"` // Hide from list
-	Precondition *Precondition  `yaml:"precondition"`
-	Aliases      []string       `yaml:"aliases"`
+	Desc         string            `yaml:"desc"`
+	Summary      string            `yaml:"summary"`
+	Cmds         []Command         `yaml:"cmds"`
+	Deps         []Dependency      `yaml:"deps"`
+	Vars         map[string]any    `yaml:"vars"`
+	Env          map[string]string `yaml:"env"`
+	Dotenv       []string          `yaml:"dotenv"` // .env files, relative to the task dir
+	Status       []string          `yaml:"status"` // Commands to check if task is up-to-date
+	Sources      []string          `yaml:"sources"`
+	Generates    []string          `yaml:"generates"`
+	Dir          string            `yaml:"dir"`
+	Silent       bool              `yaml:"silent"`
+	Internal     bool              `yaml:"internal"` // Hide from list
+	Precondition *Precondition     `yaml:"precondition"`
+	Aliases      []string          `yaml:"aliases"`
+	Prefix       string            `yaml:"prefix"` // Line prefix for "prefixed" output
 
 	// Internal fields
 	name string
+	dir  string // Directory of the Taskfile defining this task
 }
 
 // Command represents a command to execute
@@ -90,8 +97,13 @@ type Precondition struct {
 	Msg string `yaml:"msg"`
 }
 
-// ParseTaskfile parses a Taskfile.yml file
+// ParseTaskfile parses a Taskfile.yml file, fetching remote includes that
+// are not cached yet
 func ParseTaskfile(path string) (*Taskfile, error) {
+	return parseTaskfile(path, &includeLoader{ctx: context.Background()})
+}
+
+func parseTaskfile(path string, loader *includeLoader) (*Taskfile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading taskfile: %w", err)
@@ -102,6 +114,10 @@ func ParseTaskfile(path string) (*Taskfile, error) {
 		return nil, fmt.Errorf("parsing taskfile: %w", err)
 	}
 
+	if err := ValidateOutput(tf.Output.Name); err != nil {
+		return nil, err
+	}
+
 	// Set internal fields
 	tf.dir = filepath.Dir(path)
 
@@ -109,12 +125,17 @@ func ParseTaskfile(path string) (*Taskfile, error) {
 	for name, task := range tf.Tasks {
 		if task != nil {
 			task.name = name
+			task.dir = tf.dir
 		}
 	}
 
+	if err := tf.loadDotenv(); err != nil {
+		return nil, err
+	}
+
 	// Process includes
 	if len(tf.Includes) > 0 {
-		if err := tf.processIncludes(); err != nil {
+		if err := tf.processIncludes(loader); err != nil {
 			return nil, err
 		}
 	}
@@ -123,40 +144,23 @@ func ParseTaskfile(path string) (*Taskfile, error) {
 }
 
 // processIncludes loads and merges included taskfiles
-func (tf *Taskfile) processIncludes() error {
-	for namespace, includePath := range tf.Includes {
-		// Resolve relative path
-		if !filepath.IsAbs(includePath) {
-			includePath = filepath.Join(tf.dir, includePath)
-		}
-
-		// Check if it's a directory (look for Taskfile.yml)
-		info, err := os.Stat(includePath)
-		if err != nil {
-			return fmt.Errorf("include %s not found: %w", includePath, err)
-		}
-
-		if info.IsDir() {
-			// Look for taskfile in directory
-			found := false
-
-			for _, name := range DefaultTaskfiles {
-				path := filepath.Join(includePath, name)
-				if _, err := os.Stat(path); err == nil {
-					includePath = path
-					found = true
+func (tf *Taskfile) processIncludes(loader *includeLoader) error {
+	if tf.Tasks == nil {
+		tf.Tasks = make(map[string]*Task)
+	}
 
-					break
-				}
-			}
+	if tf.Vars == nil {
+		tf.Vars = make(map[string]any)
+	}
 
-			if !found {
-				return fmt.Errorf("no taskfile found in included directory: %s", includePath)
-			}
+	for namespace, inc := range tf.Includes {
+		includePath, err := loader.resolve(tf.dir, inc)
+		if err != nil {
+			return err
 		}
 
 		// Parse included taskfile
-		included, err := ParseTaskfile(includePath)
+		included, err := parseTaskfile(includePath, loader)
 		if err != nil {
 			return fmt.Errorf("parsing included taskfile %s: %w", includePath, err)
 		}
@@ -165,6 +169,13 @@ func (tf *Taskfile) processIncludes() error {
 		for name, task := range included.Tasks {
 			nsName := namespace + ":" + name
 			task.name = nsName
+
+			// A remote Taskfile lives in the cache; its tasks belong to
+			// the project that includes it.
+			if inc.isRemote() {
+				task.dir = tf.dir
+			}
+
 			tf.Tasks[nsName] = task
 		}
 
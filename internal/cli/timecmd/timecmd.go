@@ -1,11 +1,14 @@
 package timecmd
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/cli/du"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
@@ -88,6 +91,157 @@ func RunTime(w io.Writer, fn func() error) (TimeResult, error) {
 	return result, err
 }
 
+// usage is a snapshot of the CPU time and peak resident memory of the
+// current process.
+type usage struct {
+	user   time.Duration
+	sys    time.Duration
+	maxRSS int64
+}
+
+// Stage is a named step timed by the measured command itself
+type Stage struct {
+	Name    string        `json:"name"`
+	Elapsed time.Duration `json:"-"`
+	Busy    time.Duration `json:"-"`
+}
+
+// Recorder collects stage timings from a command running under Measure.
+// Commands find it with RecorderFrom; a nil Recorder ignores records, so
+// callers need no nil check.
+type Recorder struct {
+	mu     sync.Mutex
+	stages []Stage
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context carrying rec.
+func WithRecorder(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, rec)
+}
+
+// RecorderFrom returns the Recorder in ctx, or nil when the command is not
+// being timed.
+func RecorderFrom(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rec
+}
+
+// Record adds a stage timing. busy is the part of elapsed the stage spent
+// working rather than waiting; pass elapsed when the two are the same.
+func (r *Recorder) Record(name string, elapsed, busy time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stages = append(r.stages, Stage{Name: name, Elapsed: elapsed, Busy: busy})
+}
+
+// Stages returns the recorded stages in the order they were recorded.
+func (r *Recorder) Stages() []Stage {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Stage(nil), r.stages...)
+}
+
+// Report is the resource usage of one measured command
+type Report struct {
+	Command  []string
+	Real     time.Duration
+	User     time.Duration
+	Sys      time.Duration
+	MaxRSS   int64 // peak resident set size of the omni process, in bytes
+	ExitCode int
+	Stages   []Stage
+}
+
+// Measure runs fn and reports its wall-clock time, the CPU time this
+// process used while it ran, the peak RSS, and any stages fn recorded via
+// the Recorder in its context. Because the command runs in-process, MaxRSS
+// is the high-water mark of the whole omni process, not just fn. fn's error
+// is returned unchanged so its exit code propagates.
+func Measure(ctx context.Context, command []string, fn func(ctx context.Context) error) (Report, error) {
+	rec := &Recorder{}
+	before := processUsage()
+	start := time.Now()
+
+	err := fn(WithRecorder(ctx, rec))
+
+	elapsed := time.Since(start)
+	after := processUsage()
+
+	return Report{
+		Command:  command,
+		Real:     elapsed,
+		User:     after.user - before.user,
+		Sys:      after.sys - before.sys,
+		MaxRSS:   after.maxRSS,
+		ExitCode: cmderr.ExitCodeFor(err),
+		Stages:   rec.Stages(),
+	}, err
+}
+
+// WriteReport prints r in the bash time layout, followed by the peak RSS
+// and any stage timings, or as JSON.
+func WriteReport(w io.Writer, format output.Format, r Report) error {
+	f := output.New(w, format)
+	if f.IsJSON() {
+		stages := make([]map[string]any, 0, len(r.Stages))
+		for _, s := range r.Stages {
+			stages = append(stages, map[string]any{
+				"name":       s.Name,
+				"elapsed_ms": milliseconds(s.Elapsed),
+				"busy_ms":    milliseconds(s.Busy),
+			})
+		}
+
+		return f.Print(map[string]any{
+			"command":       r.Command,
+			"real_ms":       milliseconds(r.Real),
+			"user_ms":       milliseconds(r.User),
+			"sys_ms":        milliseconds(r.Sys),
+			"real":          formatDuration(r.Real),
+			"user":          formatDuration(r.User),
+			"sys":           formatDuration(r.Sys),
+			"max_rss_bytes": r.MaxRSS,
+			"exit_code":     r.ExitCode,
+			"stages":        stages,
+		})
+	}
+
+	_, err := fmt.Fprintf(w, "\nreal\t%s\nuser\t%s\nsys\t%s\nmaxrss\t%s\n",
+		formatDuration(r.Real), formatDuration(r.User), formatDuration(r.Sys), du.FormatHumanSize(r.MaxRSS))
+
+	for i, s := range r.Stages {
+		if err != nil {
+			break
+		}
+
+		_, err = fmt.Fprintf(w, "stage %d\t%s (busy %s)\t%s\n", i+1, formatDuration(s.Elapsed), formatDuration(s.Busy), s.Name)
+	}
+
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("time: write: %s", err))
+	}
+
+	return nil
+}
+
+// milliseconds converts d to fractional milliseconds with microsecond
+// precision, so sub-millisecond commands do not all report 0.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
 // formatDuration formats a duration in the style of bash's time command
 func formatDuration(d time.Duration) string {
 	minutes := int(d.Minutes())
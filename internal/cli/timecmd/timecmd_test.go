@@ -2,10 +2,16 @@ package timecmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 func TestRunTime(t *testing.T) {
@@ -177,3 +183,108 @@ func TestSleepSeconds(t *testing.T) {
 		t.Errorf("SleepSeconds() elapsed = %v, want >= 10ms", elapsed)
 	}
 }
+
+func TestMeasure(t *testing.T) {
+	t.Run("usage and stages", func(t *testing.T) {
+		report, err := Measure(context.Background(), []string{"work"}, func(ctx context.Context) error {
+			rec := RecorderFrom(ctx)
+			if rec == nil {
+				t.Fatal("RecorderFrom() = nil inside Measure")
+			}
+
+			// Burn some CPU so user time is measurable
+			deadline := time.Now().Add(20 * time.Millisecond)
+			for n := 0; time.Now().Before(deadline); n++ {
+				_ = n * n
+			}
+
+			rec.Record("first", 3*time.Millisecond, time.Millisecond)
+			rec.Record("second", 2*time.Millisecond, 2*time.Millisecond)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Measure() error = %v", err)
+		}
+
+		if report.Real < 20*time.Millisecond || report.ExitCode != 0 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+
+		if runtime.GOOS == "linux" && (report.User+report.Sys == 0 || report.MaxRSS == 0) {
+			t.Errorf("expected CPU time and max RSS on linux: %+v", report)
+		}
+
+		if len(report.Stages) != 2 || report.Stages[0].Name != "first" || report.Stages[1].Busy != 2*time.Millisecond {
+			t.Errorf("Stages = %+v", report.Stages)
+		}
+	})
+
+	t.Run("error exit code", func(t *testing.T) {
+		want := cmderr.Wrap(cmderr.ErrNotFound, "missing")
+
+		report, err := Measure(context.Background(), nil, func(context.Context) error { return want })
+		if !errors.Is(err, want) {
+			t.Errorf("Measure() error = %v, want %v", err, want)
+		}
+
+		if report.ExitCode != 1 {
+			t.Errorf("ExitCode = %d, want 1", report.ExitCode)
+		}
+	})
+
+	t.Run("nil recorder outside measure", func(t *testing.T) {
+		rec := RecorderFrom(context.Background())
+		rec.Record("ignored", time.Second, time.Second)
+
+		if rec.Stages() != nil {
+			t.Error("nil Recorder should have no stages")
+		}
+	})
+}
+
+func TestWriteReport(t *testing.T) {
+	report := Report{
+		Command:  []string{"pipeline", "sort"},
+		Real:     1500 * time.Millisecond,
+		User:     time.Second,
+		Sys:      250 * time.Microsecond,
+		MaxRSS:   10 << 20,
+		ExitCode: 0,
+		Stages:   []Stage{{Name: "sort", Elapsed: time.Second, Busy: 500 * time.Millisecond}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, output.FormatText, report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\nreal\t0m1.500s\nuser\t0m1.000s\nsys\t0m0.000s\nmaxrss\t10.0M\nstage 1\t0m1.000s (busy 0m0.500s)\tsort\n"
+	if buf.String() != want {
+		t.Errorf("WriteReport() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	if err := WriteReport(&buf, output.FormatJSON, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if got["real_ms"] != 1500.0 || got["sys_ms"] != 0.25 || got["max_rss_bytes"] != float64(10<<20) {
+		t.Errorf("unexpected JSON report: %v", got)
+	}
+
+	stages, _ := got["stages"].([]any)
+	if len(stages) != 1 {
+		t.Fatalf("stages = %v", got["stages"])
+	}
+
+	if stage := stages[0].(map[string]any); stage["name"] != "sort" || stage["busy_ms"] != 500.0 {
+		t.Errorf("stage = %v", stage)
+	}
+}
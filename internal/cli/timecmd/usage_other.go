@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package timecmd
+
+// processUsage is not available on this platform; timings fall back to
+// wall-clock only.
+func processUsage() usage {
+	return usage{}
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package timecmd
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// processUsage reads the CPU times and peak RSS of the current process.
+func processUsage() usage {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return usage{}
+	}
+
+	// ru_maxrss is in bytes on macOS and kilobytes elsewhere
+	maxRSS := int64(ru.Maxrss)
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		maxRSS *= 1024
+	}
+
+	return usage{
+		user:   time.Duration(ru.Utime.Nano()),
+		sys:    time.Duration(ru.Stime.Nano()),
+		maxRSS: maxRSS,
+	}
+}
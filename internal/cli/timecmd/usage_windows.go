@@ -0,0 +1,54 @@
+//go:build windows
+
+package timecmd
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// processMemoryCounters mirrors PROCESS_MEMORY_COUNTERS.
+type processMemoryCounters struct {
+	cb                         uint32
+	pageFaultCount             uint32
+	peakWorkingSetSize         uintptr
+	workingSetSize             uintptr
+	quotaPeakPagedPoolUsage    uintptr
+	quotaPagedPoolUsage        uintptr
+	quotaPeakNonPagedPoolUsage uintptr
+	quotaNonPagedPoolUsage     uintptr
+	pagefileUsage              uintptr
+	peakPagefileUsage          uintptr
+}
+
+var procGetProcessMemoryInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("K32GetProcessMemoryInfo")
+
+// processUsage reads the CPU times and peak working set of the current process.
+func processUsage() usage {
+	var u usage
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(windows.CurrentProcess(), &creation, &exit, &kernel, &user); err == nil {
+		u.user = filetimeDuration(user)
+		u.sys = filetimeDuration(kernel)
+	}
+
+	var pmc processMemoryCounters
+
+	pmc.cb = uint32(unsafe.Sizeof(pmc))
+
+	ret, _, _ := procGetProcessMemoryInfo.Call(uintptr(windows.CurrentProcess()), uintptr(unsafe.Pointer(&pmc)), uintptr(pmc.cb))
+	if ret != 0 {
+		u.maxRSS = int64(pmc.peakWorkingSetSize)
+	}
+
+	return u
+}
+
+// filetimeDuration converts a FILETIME interval (100ns units) to a Duration.
+func filetimeDuration(ft windows.Filetime) time.Duration {
+	return time.Duration(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) * 100
+}
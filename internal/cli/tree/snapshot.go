@@ -0,0 +1,256 @@
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/internal/logger"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	twig2 "github.com/inovacc/omni/pkg/twig"
+	"github.com/inovacc/omni/pkg/twig/comparer"
+	"github.com/inovacc/omni/pkg/twig/snapshot"
+)
+
+// SnapshotOptions configures the tree snapshot command
+type SnapshotOptions struct {
+	Store        string        // --store: snapshot directory (default: DefaultSnapshotDir)
+	Every        time.Duration // --every: run as a daemon taking a snapshot each interval (0 = once)
+	KeepLast     int           // --keep-last: keep the N most recent snapshots
+	KeepDaily    int           // --keep-daily: keep one snapshot per day for N days
+	KeepWeekly   int           // --keep-weekly: keep one snapshot per week for N weeks
+	SetBaseline  bool          // --set-baseline: pin the new snapshot as the drift baseline
+	FailOnDrift  bool          // --fail-on-drift: exit 1 when the latest snapshot drifted (one-shot only)
+	Ignore       []string      // -i: patterns to ignore
+	DetectMoves  bool          // --detect-moves: report moves in drift
+	OutputFormat output.Format // global output format
+	Logger       *logger.Logger
+}
+
+// SnapshotReport is the outcome of one snapshot cycle for one directory
+type SnapshotReport struct {
+	Root     string                  `json:"root"`
+	Snapshot string                  `json:"snapshot"`
+	Files    int                     `json:"files"`
+	Dirs     int                     `json:"dirs"`
+	Pruned   []string                `json:"pruned,omitempty"`
+	Baseline bool                    `json:"baseline_set,omitempty"`
+	Drift    *comparer.CompareResult `json:"drift,omitempty"`
+}
+
+// DefaultSnapshotDir returns <os.UserCacheDir>/omni/twig-snapshots.
+func DefaultSnapshotDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "omni", "twig-snapshots"), nil
+}
+
+// RunSnapshot snapshots each directory, prunes old snapshots by the
+// retention rules and reports drift from the baseline. With Every set it
+// repeats until ctx is cancelled; failures are then reported and the
+// daemon carries on. Every step is also written to the command log.
+func RunSnapshot(ctx context.Context, w io.Writer, args []string, opts SnapshotOptions) error {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	if opts.Store == "" {
+		dir, err := DefaultSnapshotDir()
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree snapshot: %s", err))
+		}
+
+		opts.Store = dir
+	}
+
+	if opts.Every < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "tree snapshot: --every must not be negative")
+	}
+
+	for _, dir := range args {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return classifyTreeError("tree snapshot", err)
+		}
+
+		if !info.IsDir() {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("tree snapshot: %s: not a directory", dir))
+		}
+	}
+
+	store := snapshot.NewStore(opts.Store)
+
+	if opts.Every == 0 {
+		drifted := false
+
+		for _, dir := range args {
+			report, err := snapshotOnce(ctx, store, dir, opts)
+			if err != nil {
+				return err
+			}
+
+			if err := writeSnapshotReport(w, report, opts); err != nil {
+				return err
+			}
+
+			if report.Drift != nil && len(report.Drift.Changes) > 0 {
+				drifted = true
+			}
+		}
+
+		if drifted && opts.FailOnDrift {
+			return cmderr.SilentExit(1)
+		}
+
+		return nil
+	}
+
+	ticker := time.NewTicker(opts.Every)
+	defer ticker.Stop()
+
+	for {
+		for _, dir := range args {
+			report, err := snapshotOnce(ctx, store, dir, opts)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				opts.Logger.LogRaw("twig_snapshot_error", "root", dir, "error", err.Error())
+				_, _ = fmt.Fprintln(w, err)
+
+				continue
+			}
+
+			_ = writeSnapshotReport(w, report, opts)
+		}
+
+		// The baseline is pinned by the first cycle only
+		opts.SetBaseline = false
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotOnce takes, prunes and compares one directory.
+func snapshotOnce(ctx context.Context, store *snapshot.Store, dir string, opts SnapshotOptions) (*SnapshotReport, error) {
+	var treeOpts []twig2.TreeOption
+	if len(opts.Ignore) > 0 {
+		treeOpts = append(treeOpts, twig2.WithIgnorePatterns(opts.Ignore...))
+	}
+
+	snap, err := store.Take(ctx, dir, treeOpts...)
+	if err != nil {
+		return nil, classifyTreeError("tree snapshot", err)
+	}
+
+	report := &SnapshotReport{Root: snap.Root, Snapshot: snap.Path}
+
+	if f, err := snapshot.Load(snap.Path); err == nil && f.Stats != nil {
+		report.Files, report.Dirs = f.Stats.TotalFiles, f.Stats.TotalDirs
+	}
+
+	opts.Logger.LogRaw("twig_snapshot", "root", snap.Root, "path", snap.Path,
+		"files", report.Files, "dirs", report.Dirs, "timestamp", snap.Time.Format(time.RFC3339))
+
+	if opts.SetBaseline {
+		if err := store.SetBaseline(snap); err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree snapshot: set baseline: %s", err))
+		}
+
+		report.Baseline = true
+
+		opts.Logger.LogRaw("twig_baseline", "root", snap.Root, "path", snap.Path)
+	}
+
+	policy := snapshot.Retention{KeepLast: opts.KeepLast, KeepDaily: opts.KeepDaily, KeepWeekly: opts.KeepWeekly}
+	if !policy.IsZero() {
+		removed, err := store.Prune(snap.Root, policy)
+		if err != nil {
+			return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree snapshot: prune: %s", err))
+		}
+
+		for _, r := range removed {
+			report.Pruned = append(report.Pruned, r.Path)
+		}
+
+		if len(removed) > 0 {
+			opts.Logger.LogRaw("twig_prune", "root", snap.Root, "removed", report.Pruned)
+		}
+	}
+
+	drift, err := store.Drift(snap.Root, comparer.CompareConfig{DetectMoves: opts.DetectMoves})
+	switch {
+	case errors.Is(err, snapshot.ErrNoBaseline):
+	case err != nil:
+		return nil, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree snapshot: drift: %s", err))
+	default:
+		report.Drift = drift
+
+		opts.Logger.LogRaw("twig_drift", "root", snap.Root, "baseline", drift.LeftPath, "snapshot", drift.RightPath,
+			"added", drift.Summary.Added, "removed", drift.Summary.Removed,
+			"modified", drift.Summary.Modified, "moved", drift.Summary.Moved)
+	}
+
+	return report, nil
+}
+
+// writeSnapshotReport prints a report; JSON output is one object per line
+// so a daemon's output can be consumed as a stream.
+func writeSnapshotReport(w io.Writer, r *SnapshotReport, opts SnapshotOptions) error {
+	if opts.OutputFormat == output.FormatJSON {
+		if err := json.NewEncoder(w).Encode(r); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree snapshot: write: %s", err))
+		}
+
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "snapshot %s -> %s (%d directories, %d files)\n", r.Root, r.Snapshot, r.Dirs, r.Files)
+
+	if r.Baseline {
+		_, _ = fmt.Fprintln(w, "  baseline set")
+	}
+
+	if len(r.Pruned) > 0 {
+		_, _ = fmt.Fprintf(w, "  pruned %d snapshot(s)\n", len(r.Pruned))
+	}
+
+	if r.Drift == nil {
+		return nil
+	}
+
+	for _, c := range r.Drift.Changes {
+		switch c.Type {
+		case comparer.Added:
+			_, _ = fmt.Fprintf(w, "  + %s\n", c.Path)
+		case comparer.Removed:
+			_, _ = fmt.Fprintf(w, "  - %s\n", c.Path)
+		case comparer.Modified:
+			_, _ = fmt.Fprintf(w, "  ~ %s\n", c.Path)
+		case comparer.Moved:
+			_, _ = fmt.Fprintf(w, "  > %s (from %s)\n", c.Path, c.OldPath)
+		}
+	}
+
+	s := r.Drift.Summary
+	if _, err := fmt.Fprintf(w, "  drift from baseline: %d added, %d removed, %d modified, %d moved\n",
+		s.Added, s.Removed, s.Modified, s.Moved); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree snapshot: write: %s", err))
+	}
+
+	return nil
+}
@@ -1,12 +1,14 @@
 package tree
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
@@ -36,6 +38,7 @@ type TreeOptions struct {
 	Threads      int           // -t/--threads: parallel workers
 	Compare      []string      // --compare: two JSON files to compare
 	DetectMoves  bool          // --detect-moves: detect moved files in compare
+	Null         bool          // -0/--null: print paths, each followed by NUL, instead of the tree
 }
 
 // RunTree executes the tree command
@@ -84,6 +87,14 @@ func RunTree(w io.Writer, args []string, opts TreeOptions) error {
 
 	t := twig2.NewTree(treeOpts...)
 
+	if opts.Null {
+		if useJSON || opts.JSONStream {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, "tree: --null cannot be combined with --json or --json-stream")
+		}
+
+		return printNull(w, t, path)
+	}
+
 	// Handle streaming JSON output
 	if opts.JSONStream {
 		return t.GenerateJSONStream(context.Background(), path, w)
@@ -113,6 +124,43 @@ func RunTree(w io.Writer, args []string, opts TreeOptions) error {
 	return nil
 }
 
+// printNull writes the path of every scanned entry, starting with path
+// itself, each followed by a NUL byte, in the order the tree shows them.
+// Paths are joined to path as find prints them, so the list can be fed to
+// xargs -0, rm -0 or cp -0 whatever characters the names contain.
+func printNull(w io.Writer, t *twig2.Tree, path string) error {
+	result, err := t.GenerateWithStats(context.Background(), path)
+	if err != nil {
+		return classifyTreeError("tree", err)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	var walk func(n *models.Node)
+
+	walk = func(n *models.Node) {
+		p := path
+		if rel, err := filepath.Rel(result.Root.Path, n.Path); err == nil && rel != "." {
+			p = filepath.Join(path, rel)
+		}
+
+		_, _ = bw.WriteString(p)
+		_ = bw.WriteByte(0)
+
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+
+	walk(result.Root)
+
+	if err := bw.Flush(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("tree: write: %s", err))
+	}
+
+	return nil
+}
+
 // classifyTreeError maps twig/scanner errors to cmderr sentinels at the CLI boundary.
 func classifyTreeError(cmd string, err error) error {
 	switch {
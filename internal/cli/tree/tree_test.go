@@ -2,10 +2,18 @@ package tree
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 func TestRunTree(t *testing.T) {
@@ -167,3 +175,91 @@ func TestRunTree(t *testing.T) {
 		}
 	})
 }
+
+func TestRunTreeNull(t *testing.T) {
+	dir := t.TempDir()
+
+	_ = os.Mkdir(filepath.Join(dir, "sub dir"), 0o755)
+	_ = os.WriteFile(filepath.Join(dir, "sub dir", "line\nbreak.txt"), []byte("x"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("x"), 0o644)
+
+	var buf bytes.Buffer
+	if err := RunTree(&buf, []string{dir}, TreeOptions{Depth: -1, Null: true}); err != nil {
+		t.Fatalf("RunTree() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		dir,
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "sub dir"),
+		filepath.Join(dir, "sub dir", "line\nbreak.txt"),
+	}, "\x00") + "\x00"
+
+	if buf.String() != want {
+		t.Errorf("RunTree(Null) = %q, want %q", buf.String(), want)
+	}
+
+	err := RunTree(&bytes.Buffer{}, []string{dir}, TreeOptions{Null: true, JSON: true})
+	if !cmderr.IsInvalidInput(err) {
+		t.Errorf("--null with --json error = %v, want invalid input", err)
+	}
+}
+
+func TestRunSnapshot(t *testing.T) {
+	root := t.TempDir()
+	store := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(root, "config.yaml"), []byte("a: 1\n"), 0o644)
+
+	opts := SnapshotOptions{Store: store, SetBaseline: true, FailOnDrift: true, KeepLast: 1}
+
+	var buf bytes.Buffer
+	if err := RunSnapshot(context.Background(), &buf, []string{root}, opts); err != nil {
+		t.Fatalf("RunSnapshot() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "baseline set") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	_ = os.WriteFile(filepath.Join(root, "config.yaml"), []byte("a: 2\n"), 0o644)
+
+	opts.SetBaseline = false
+	opts.OutputFormat = output.FormatJSON
+	buf.Reset()
+
+	err := RunSnapshot(context.Background(), &buf, []string{root}, opts)
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Fatalf("RunSnapshot() error = %v, want exit 1 on drift", err)
+	}
+
+	var report SnapshotReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if report.Drift == nil || report.Drift.Summary.Modified != 1 || len(report.Pruned) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	t.Run("daemon stops on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+		defer cancel()
+
+		var out bytes.Buffer
+		if err := RunSnapshot(ctx, &out, []string{root}, SnapshotOptions{Store: t.TempDir(), Every: 20 * time.Millisecond}); err != nil {
+			t.Fatalf("RunSnapshot() error = %v", err)
+		}
+
+		if n := strings.Count(out.String(), "snapshot "); n < 2 {
+			t.Errorf("daemon took %d snapshots, want several", n)
+		}
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		file := filepath.Join(root, "config.yaml")
+		if err := RunSnapshot(context.Background(), io.Discard, []string{file}, SnapshotOptions{Store: store}); !errors.Is(err, cmderr.ErrInvalidInput) {
+			t.Errorf("error = %v, want invalid input", err)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package ulid
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+// BoundsOptions configures the ulid bounds command behavior
+type BoundsOptions struct {
+	From         string        // --from: range start
+	To           string        // --to: range end (default: now)
+	Kind         string        // --kind: ulid, uuidv7 or ksuid
+	Lower        bool          // -l: output in lowercase
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// BoundsResult represents ulid bounds output for JSON
+type BoundsResult struct {
+	Kind string `json:"kind"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Min  string `json:"min"`
+	Max  string `json:"max"`
+}
+
+// RunBounds prints the smallest and largest ID generated in a time range
+func RunBounds(w io.Writer, opts BoundsOptions) error {
+	if opts.From == "" {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "ulid bounds: --from is required")
+	}
+
+	kind := idgen.KindULID
+
+	if opts.Kind != "" {
+		k, err := idgen.ParseKind(opts.Kind)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ulid bounds: %s", err))
+		}
+
+		kind = k
+	}
+
+	now := time.Now()
+
+	start, err := parseBoundTime(opts.From, false, now)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ulid bounds: --from: %s", err))
+	}
+
+	end := now
+	if opts.To != "" {
+		if end, err = parseBoundTime(opts.To, true, now); err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ulid bounds: --to: %s", err))
+		}
+	}
+
+	b, err := idgen.BoundsForRange(start, end, kind)
+	if err != nil {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("ulid bounds: %s", err))
+	}
+
+	// KSUIDs are case sensitive
+	if opts.Lower && kind != idgen.KindKSUID {
+		b.Min, b.Max = strings.ToLower(b.Min), strings.ToLower(b.Max)
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(BoundsResult{
+			Kind: string(kind),
+			From: start.UTC().Format(time.RFC3339Nano),
+			To:   end.UTC().Format(time.RFC3339Nano),
+			Min:  b.Min,
+			Max:  b.Max,
+		})
+	}
+
+	_, _ = fmt.Fprintf(w, "min\t%s\nmax\t%s\n", b.Min, b.Max)
+
+	return nil
+}
+
+// parseBoundTime parses "now", a Unix timestamp in seconds, RFC 3339 or a
+// date. A date-only range end covers the whole day.
+func parseBoundTime(s string, isEnd bool, now time.Time) (time.Time, error) {
+	if s == "now" {
+		return now, nil
+	}
+
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339, YYYY-MM-DD, Unix seconds or now)", s)
+	}
+
+	if isEnd {
+		t = t.AddDate(0, 0, 1).Add(-time.Millisecond)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,152 @@
+package ulid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
+)
+
+// TimeOptions configures the ulid, ksuid and uuid time subcommands
+type TimeOptions struct {
+	Kind         idgen.Kind    // id kind to decode; empty detects it from the length
+	Format       string        // -f: all (default), utc, local, unix or unix-ms
+	OutputFormat output.Format // output format (text, json, table)
+}
+
+// TimeResult represents the decoded timestamp of one ID for JSON
+type TimeResult struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	UTC       string `json:"utc"`
+	Local     string `json:"local"`
+	Unix      int64  `json:"unix"`
+	UnixMilli int64  `json:"unixMs"`
+}
+
+// timeFormats lists the values accepted by --format.
+var timeFormats = []string{"all", "utc", "local", "unix", "unix-ms"}
+
+// RunTime prints the timestamp embedded in each ID argument, or in each
+// line of r when no argument is given.
+func RunTime(w io.Writer, r io.Reader, args []string, opts TimeOptions) error {
+	name := commandName(opts.Kind)
+
+	format := opts.Format
+	if format == "" {
+		format = "all"
+	}
+
+	if !slices.Contains(timeFormats, format) {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: invalid format %q (use %s)", name, format, strings.Join(timeFormats, ", ")))
+	}
+
+	ids := args
+	if len(ids) == 0 {
+		var err error
+		if ids, err = readIDs(r); err != nil {
+			return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	if len(ids) == 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: missing ID", name))
+	}
+
+	results := make([]TimeResult, 0, len(ids))
+
+	for _, id := range ids {
+		t, kind, err := idgen.TimeOf(id, opts.Kind)
+		if err != nil {
+			return cmderr.Wrap(cmderr.ErrInvalidInput, fmt.Sprintf("%s: %s", name, err))
+		}
+
+		results = append(results, TimeResult{
+			ID:        id,
+			Kind:      string(kind),
+			UTC:       t.UTC().Format(time.RFC3339Nano),
+			Local:     t.Local().Format(time.RFC3339Nano),
+			Unix:      t.Unix(),
+			UnixMilli: t.UnixMilli(),
+		})
+	}
+
+	f := output.New(w, opts.OutputFormat)
+	if f.IsJSON() {
+		return f.Print(results)
+	}
+
+	// With several IDs every line starts with the ID it belongs to
+	prefix := func(res TimeResult) string {
+		if len(results) == 1 {
+			return ""
+		}
+
+		return res.ID + "\t"
+	}
+
+	for _, res := range results {
+		p := prefix(res)
+
+		switch format {
+		case "utc":
+			_, _ = fmt.Fprintf(w, "%s%s\n", p, res.UTC)
+		case "local":
+			_, _ = fmt.Fprintf(w, "%s%s\n", p, res.Local)
+		case "unix":
+			_, _ = fmt.Fprintf(w, "%s%s\n", p, unixString(res))
+		case "unix-ms":
+			_, _ = fmt.Fprintf(w, "%s%d\n", p, res.UnixMilli)
+		default:
+			_, _ = fmt.Fprintf(w, "%sutc\t%s\n%slocal\t%s\n%sunix\t%s\n", p, res.UTC, p, res.Local, p, unixString(res))
+		}
+	}
+
+	return nil
+}
+
+// unixString formats Unix seconds, with milliseconds when the ID has them.
+func unixString(res TimeResult) string {
+	ms := res.UnixMilli - res.Unix*1000
+	if ms == 0 {
+		return strconv.FormatInt(res.Unix, 10)
+	}
+
+	return fmt.Sprintf("%d.%03d", res.Unix, ms)
+}
+
+func commandName(kind idgen.Kind) string {
+	switch kind {
+	case idgen.KindKSUID:
+		return "ksuid time"
+	case idgen.KindUUIDv7:
+		return "uuid time"
+	default:
+		return "ulid time"
+	}
+}
+
+// readIDs reads one ID per non-blank line.
+func readIDs(r io.Reader) ([]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	var ids []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, scanner.Err()
+}
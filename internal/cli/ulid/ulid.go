@@ -15,6 +15,7 @@ import (
 type Options struct {
 	Count        int           // -n: generate N ULIDs
 	Lower        bool          // -l: output in lowercase
+	Monotonic    bool          // --monotonic: strictly increasing within the same millisecond
 	OutputFormat output.Format // output format (text, json, table)
 }
 
@@ -39,15 +40,24 @@ func RunULID(w io.Writer, opts Options) error {
 
 	f := output.New(w, opts.OutputFormat)
 
+	next := ulidString
+	if opts.Monotonic {
+		g, err := idgen.NewBatchGenerator(idgen.KindULID)
+		if err != nil {
+			return fmt.Errorf("ulid: %w", err)
+		}
+
+		next = g.Next
+	}
+
 	var ulids []string
 
 	for i := 0; i < opts.Count; i++ {
-		u, err := idgen.GenerateULID()
+		encoded, err := next()
 		if err != nil {
 			return fmt.Errorf("ulid: %w", err)
 		}
 
-		encoded := u.String()
 		if opts.Lower {
 			encoded = strings.ToLower(encoded)
 		}
@@ -68,6 +78,16 @@ func RunULID(w io.Writer, opts Options) error {
 	return nil
 }
 
+// ulidString generates an independent random ULID.
+func ulidString() (string, error) {
+	u, err := idgen.GenerateULID()
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
 // New generates a new ULID
 func New() (idgen.ULID, error) {
 	return idgen.GenerateULID()
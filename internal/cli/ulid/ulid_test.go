@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/idgen"
 )
 
 func TestNew(t *testing.T) {
@@ -117,6 +119,25 @@ func TestRunULIDLower(t *testing.T) {
 	}
 }
 
+func TestRunULIDMonotonic(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := RunULID(&buf, Options{Count: 1000, Monotonic: true}); err != nil {
+		t.Fatalf("RunULID() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1000 {
+		t.Fatalf("RunULID() generated %d ULIDs, want 1000", len(lines))
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if lines[i] <= lines[i-1] {
+			t.Fatalf("ULID[%d] = %s not after ULID[%d] = %s", i, lines[i], i-1, lines[i-1])
+		}
+	}
+}
+
 func TestRunULIDJSON(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -147,3 +168,163 @@ func TestNewString(t *testing.T) {
 		t.Errorf("NewString() length = %d, want 26", len(str))
 	}
 }
+
+func TestRunBounds(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := RunBounds(&buf, BoundsOptions{From: "2024-03-01", To: "2024-03-01", OutputFormat: output.FormatJSON})
+	if err != nil {
+		t.Fatalf("RunBounds() error = %v", err)
+	}
+
+	var res BoundsResult
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if res.Kind != "ulid" || res.To != "2024-03-01T23:59:59.999Z" {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	if res.Min != "01HQVMZ1000000000000000000" || res.Max != "01HQY7BQZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("bounds = [%s, %s]", res.Min, res.Max)
+	}
+
+	buf.Reset()
+
+	if err := RunBounds(&buf, BoundsOptions{From: "1709294400", To: "1709298000", Kind: "uuidv7"}); err != nil {
+		t.Fatalf("RunBounds(uuidv7) error = %v", err)
+	}
+
+	want := "min\t018df9e2-b200-7000-8000-000000000000\nmax\t018dfa19-a080-7fff-bfff-ffffffffffff\n"
+	if buf.String() != want {
+		t.Errorf("RunBounds(uuidv7) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunBoundsErrors(t *testing.T) {
+	tests := []BoundsOptions{
+		{},
+		{From: "yesterday"},
+		{From: "2024-03-02", To: "2024-03-01"},
+		{From: "2024-03-01", Kind: "nanoid"},
+	}
+
+	for _, opts := range tests {
+		if err := RunBounds(&bytes.Buffer{}, opts); !cmderr.IsInvalidInput(err) {
+			t.Errorf("RunBounds(%+v) = %v, want invalid input", opts, err)
+		}
+	}
+}
+
+func TestRunTime(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 123e6, time.UTC)
+
+	u, err := NewWithTime(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := u.String()
+
+	t.Run("single format", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunTime(&buf, nil, []string{strings.ToLower(id)}, TimeOptions{Kind: idgen.KindULID, Format: "utc"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != "2024-03-01T12:00:00.123Z\n" {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+
+	t.Run("all formats", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunTime(&buf, nil, []string{id}, TimeOptions{Kind: idgen.KindULID}); err != nil {
+			t.Fatal(err)
+		}
+
+		out := buf.String()
+		if !strings.Contains(out, "utc\t2024-03-01T12:00:00.123Z\n") || !strings.Contains(out, "unix\t1709294400.123\n") {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("stdin with several ids", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		in := strings.NewReader(id + "\n\n" + id + "\n")
+		if err := RunTime(&buf, in, nil, TimeOptions{Kind: idgen.KindULID, Format: "unix-ms"}); err != nil {
+			t.Fatal(err)
+		}
+
+		line := id + "\t1709294400123\n"
+		if buf.String() != line+line {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := RunTime(&buf, nil, []string{id}, TimeOptions{Kind: idgen.KindULID, OutputFormat: output.FormatJSON}); err != nil {
+			t.Fatal(err)
+		}
+
+		var results []TimeResult
+		if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 1 || results[0].Kind != "ulid" || results[0].UnixMilli != 1709294400123 {
+			t.Errorf("results = %+v", results)
+		}
+	})
+
+	t.Run("ksuid and uuid", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		args := []string{"aWgEPTl1tmebfsQzFP4bxwgy80V"}
+		if err := RunTime(&buf, nil, args, TimeOptions{Kind: idgen.KindKSUID, Format: "unix"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != "5694967295\n" {
+			t.Errorf("ksuid output = %q", buf.String())
+		}
+
+		buf.Reset()
+
+		args = []string{"018df9e2-b200-7000-8000-000000000000"}
+		if err := RunTime(&buf, nil, args, TimeOptions{Kind: idgen.KindUUIDv7, Format: "utc"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != "2024-03-01T12:00:00Z\n" {
+			t.Errorf("uuid output = %q", buf.String())
+		}
+	})
+}
+
+func TestRunTimeErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	tests := []struct {
+		name string
+		args []string
+		opts TimeOptions
+	}{
+		{"bad id", []string{"not-a-ulid"}, TimeOptions{Kind: idgen.KindULID}},
+		{"ksuid as ulid", []string{"aWgEPTl1tmebfsQzFP4bxwgy80V"}, TimeOptions{Kind: idgen.KindULID}},
+		{"uuid v4", []string{"9b2c6f9e-4f3a-4d2b-8c1e-2f6a7b8c9d0e"}, TimeOptions{Kind: idgen.KindUUIDv7}},
+		{"bad format", []string{"01HQ3Z5N2M8V4W7X9Y0Z1A2B3C"}, TimeOptions{Kind: idgen.KindULID, Format: "iso"}},
+		{"no ids", nil, TimeOptions{Kind: idgen.KindULID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RunTime(&buf, strings.NewReader(""), tt.args, tt.opts); !cmderr.IsInvalidInput(err) {
+				t.Errorf("RunTime() error = %v, want invalid input", err)
+			}
+		})
+	}
+}
@@ -1,16 +1,17 @@
 package wc
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"unicode/utf8"
+	"strconv"
+	"strings"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 	"github.com/inovacc/omni/internal/cli/input"
 	"github.com/inovacc/omni/pkg/cobra/helper/output"
+	"github.com/inovacc/omni/pkg/pipeline"
 )
 
 // WCOptions configures the wc command behavior
@@ -31,10 +32,13 @@ type WCResult struct {
 	Chars      int    `json:"chars"`
 	MaxLineLen int    `json:"maxLineLen"`
 	Filename   string `json:"filename,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
 // RunWC executes the wc command
-// r is the default input reader (used when args is empty or contains "-")
+// r is the default input reader (used when args is empty or contains "-").
+// Unreadable files are reported and skipped; the total covers the rest and
+// the exit status is 1.
 func RunWC(w io.Writer, r io.Reader, args []string, opts WCOptions) error {
 	// If no flags specified, default to -lwc
 	if !opts.Lines && !opts.Words && !opts.Bytes && !opts.Chars && !opts.MaxLineLen {
@@ -43,204 +47,146 @@ func RunWC(w io.Writer, r io.Reader, args []string, opts WCOptions) error {
 		opts.Bytes = true
 	}
 
-	sources, err := input.Open(args, r)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return cmderr.Wrap(cmderr.ErrNotFound, fmt.Sprintf("wc: %s", err))
-		}
-		if errors.Is(err, os.ErrPermission) {
-			return cmderr.Wrap(cmderr.ErrPermission, fmt.Sprintf("wc: %s", err))
-		}
-		return fmt.Errorf("wc: %w", err)
+	names := args
+	if len(names) == 0 {
+		names = []string{"-"}
 	}
-	defer input.CloseAll(sources)
 
 	f := output.New(w, opts.OutputFormat)
-	jsonMode := f.IsJSON()
 
 	var (
-		totals  WCResult
+		total   pipeline.Counts
 		results []WCResult
+		failed  bool
 	)
 
-	totals.Filename = "total"
-
-	for _, src := range sources {
-		result, err := countReader(src.Reader, opts)
+	for _, name := range names {
+		counts, err := countFile(name, r)
 		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "wc: %s: %v\n", src.Name, err)
+			failed = true
+
+			_, _ = fmt.Fprintf(os.Stderr, "wc: %s\n", err)
+
+			results = append(results, WCResult{Filename: name, Error: err.Error()})
+
 			continue
 		}
 
-		// For stdin, leave filename empty to match traditional wc behavior
-		if src.Name == "standard input" {
-			result.Filename = ""
-		} else {
-			result.Filename = src.Name
-		}
+		total.Add(counts)
 
-		if jsonMode {
-			results = append(results, result)
-		} else {
-			printWCResult(w, result, opts)
+		result := toResult(counts)
+
+		// For stdin, leave filename empty to match traditional wc behavior
+		if len(args) > 0 {
+			result.Filename = name
 		}
 
-		// Accumulate totals
-		totals.Lines += result.Lines
-		totals.Words += result.Words
-		totals.Bytes += result.Bytes
+		results = append(results, result)
+	}
 
-		totals.Chars += result.Chars
-		if result.MaxLineLen > totals.MaxLineLen {
-			totals.MaxLineLen = result.MaxLineLen
-		}
+	if len(names) > 1 {
+		totals := toResult(total)
+		totals.Filename = "total"
+		results = append(results, totals)
 	}
 
-	// JSON output
-	if jsonMode {
-		if len(sources) > 1 {
-			results = append(results, totals)
+	if f.IsJSON() {
+		if err := f.Print(results); err != nil {
+			return err
 		}
+	} else {
+		width := max(7, len(strconv.FormatInt(max(total.Bytes, total.Chars, total.Words, total.Lines, total.MaxLineWidth), 10)))
 
-		return f.Print(results)
+		for _, result := range results {
+			if result.Error == "" {
+				printWCResult(w, result, opts, width)
+			}
+		}
 	}
 
-	// Print totals if multiple files
-	if len(sources) > 1 {
-		printWCResult(w, totals, opts)
+	if failed {
+		return cmderr.PartialFailure(1, "wc: some operands failed")
 	}
 
 	return nil
 }
 
-func countReader(r io.Reader, opts WCOptions) (WCResult, error) {
-	var result WCResult
-
-	reader := bufio.NewReader(r)
-	inWord := false
-	lineLen := 0
-
-	for {
-		b, err := reader.ReadByte()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-
-			return result, err
+// countFile counts name, or r for "-".
+func countFile(name string, r io.Reader) (pipeline.Counts, error) {
+	src, err := input.OpenOne([]string{name}, r)
+	if err != nil {
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return pipeline.Counts{}, cmderr.Wrap(cmderr.ErrNotFound, name)
+		case errors.Is(err, os.ErrPermission):
+			return pipeline.Counts{}, cmderr.Wrap(cmderr.ErrPermission, name)
 		}
 
-		result.Bytes++
+		return pipeline.Counts{}, err
+	}
 
-		// Count characters (UTF-8 aware)
-		if opts.Chars {
-			if (b & 0xC0) != 0x80 { // Not a continuation byte
-				result.Chars++
-			}
-		}
+	defer input.MustClose(&src)
 
-		// Count lines
-		if b == '\n' {
-			result.Lines++
-			if lineLen > result.MaxLineLen {
-				result.MaxLineLen = lineLen
-			}
+	return countReader(src.Reader)
+}
 
-			lineLen = 0
-		} else {
-			lineLen++
-		}
+func countReader(r io.Reader) (pipeline.Counts, error) {
+	var c pipeline.Counter
 
-		// Count words
-		isSpace := b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f' || b == '\v'
-		if isSpace {
-			inWord = false
-		} else if !inWord {
-			inWord = true
-			result.Words++
-		}
+	if _, err := io.Copy(&c, r); err != nil {
+		return c.Counts(), err
 	}
 
-	// Handle last line without newline
-	if lineLen > result.MaxLineLen {
-		result.MaxLineLen = lineLen
-	}
+	return c.Counts(), nil
+}
 
-	return result, nil
+func toResult(c pipeline.Counts) WCResult {
+	return WCResult{
+		Lines:      int(c.Lines),
+		Words:      int(c.Words),
+		Bytes:      int(c.Bytes),
+		Chars:      int(c.Chars),
+		MaxLineLen: int(c.MaxLineWidth),
+	}
 }
 
-func printWCResult(w io.Writer, result WCResult, opts WCOptions) {
-	var (
-		fields []any
-		format string
-	)
+func printWCResult(w io.Writer, result WCResult, opts WCOptions, width int) {
+	var fields []string
 
 	if opts.Lines {
-		format += "%7d "
-
-		fields = append(fields, result.Lines)
+		fields = append(fields, fmt.Sprintf("%*d", width, result.Lines))
 	}
 
 	if opts.Words {
-		format += "%7d "
-
-		fields = append(fields, result.Words)
+		fields = append(fields, fmt.Sprintf("%*d", width, result.Words))
 	}
 
 	if opts.Chars {
-		format += "%7d "
-
-		fields = append(fields, result.Chars)
+		fields = append(fields, fmt.Sprintf("%*d", width, result.Chars))
 	}
 
 	if opts.Bytes {
-		format += "%7d "
-
-		fields = append(fields, result.Bytes)
+		fields = append(fields, fmt.Sprintf("%*d", width, result.Bytes))
 	}
 
 	if opts.MaxLineLen {
-		format += "%7d "
-
-		fields = append(fields, result.MaxLineLen)
+		fields = append(fields, fmt.Sprintf("%*d", width, result.MaxLineLen))
 	}
 
 	if result.Filename != "" {
-		format += "%s"
-
 		fields = append(fields, result.Filename)
 	}
 
-	format += "\n"
-	_, _ = fmt.Fprintf(w, format, fields...)
+	_, _ = fmt.Fprintln(w, strings.Join(fields, " "))
 }
 
 // WC counts lines, words, and bytes in data (for compatibility)
 func WC(data []byte) WCResult {
-	lines := 0
-	words := 0
-	chars := utf8.RuneCount(data)
-	inWord := false
-
-	for _, b := range data {
-		if b == '\n' {
-			lines++
-		}
+	var c pipeline.Counter
 
-		if b == ' ' || b == '\n' || b == '\t' {
-			inWord = false
-		} else if !inWord {
-			inWord = true
-			words++
-		}
-	}
+	_, _ = c.Write(data)
 
-	return WCResult{
-		Lines: lines,
-		Words: words,
-		Bytes: len(data),
-		Chars: chars,
-	}
+	return toResult(c.Counts())
 }
 
 // WCWithStats is an alias for WC (for compatibility)
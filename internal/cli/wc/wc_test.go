@@ -2,10 +2,14 @@ package wc
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+	"github.com/inovacc/omni/pkg/cobra/helper/output"
 )
 
 func TestRunWC(t *testing.T) {
@@ -518,3 +522,49 @@ func TestWCWithStats(t *testing.T) {
 		}
 	})
 }
+
+func TestRunWC_Multibyte(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "utf8.txt")
+	if err := os.WriteFile(file, []byte("héllo 世界\n\tx\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RunWC(&buf, nil, []string{file}, WCOptions{OutputFormat: output.FormatJSON}); err != nil {
+		t.Fatalf("RunWC() error = %v", err)
+	}
+
+	var results []WCResult
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("bad JSON %q: %v", buf.String(), err)
+	}
+
+	// "héllo 世界" is 9 chars, 13 bytes and 10 columns wide
+	want := WCResult{Lines: 2, Words: 3, Bytes: 17, Chars: 12, MaxLineLen: 10, Filename: file}
+	if len(results) != 1 || results[0] != want {
+		t.Errorf("RunWC() = %+v, want %+v", results, want)
+	}
+}
+
+func TestRunWC_TotalsWithMissingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	a := filepath.Join(tmpDir, "a.txt")
+	b := filepath.Join(tmpDir, "b.txt")
+	_ = os.WriteFile(a, []byte("one\ntwo\n"), 0644)
+	_ = os.WriteFile(b, []byte("three\n"), 0644)
+
+	var buf bytes.Buffer
+
+	err := RunWC(&buf, nil, []string{a, filepath.Join(tmpDir, "missing"), b}, WCOptions{Lines: true})
+	if cmderr.ExitCodeFor(err) != 1 {
+		t.Errorf("RunWC() error = %v, want exit status 1", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(strings.TrimSpace(lines[2]), "3 total") {
+		t.Errorf("RunWC() output = %q", buf.String())
+	}
+}
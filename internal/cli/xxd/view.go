@@ -0,0 +1,557 @@
+package xxd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/inovacc/omni/internal/cli/cmderr"
+)
+
+// searchChunk is how much of the input a search reads at a time.
+const searchChunk = 64 * 1024
+
+// promptKind is what the status line is reading input for.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptGoto
+	promptText
+	promptHex
+)
+
+// viewModel is the state of the interactive viewer. Only the visible rows
+// are read from the input, so files of any size open instantly.
+//
+//nolint:recvcheck // bubbletea interface requires value receivers for Init/Update/View
+type viewModel struct {
+	data   io.ReaderAt
+	start  int64 // first viewable offset (--seek)
+	end    int64 // end of the viewable range (--len or the input size)
+	name   string
+	cols   int
+	groups int
+	upper  bool
+	bits   bool
+
+	top    int64 // offset of the first visible row
+	width  int
+	height int // visible rows, excluding the status line
+
+	prompt    promptKind
+	input     string
+	pattern   []byte
+	match     int64 // offset of the current match, -1 when none
+	searching bool
+	message   string
+	quit      bool
+}
+
+// searchResultMsg reports the outcome of a background search.
+type searchResultMsg struct {
+	offset int64
+	err    error
+}
+
+// runView opens the interactive viewer on r. Regular files are read in
+// place; other input (a pipe) is buffered in memory first.
+func runView(r io.Reader, filename string, opts Options) error {
+	data, size, err := viewSource(r)
+	if err != nil {
+		return err
+	}
+
+	m := newViewModel(data, size, filename, opts)
+
+	progOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if f, ok := r.(*os.File); ok && f == os.Stdin {
+		// The data came from stdin, so keys must come from the terminal
+		progOpts = append(progOpts, tea.WithInputTTY())
+	}
+
+	if _, err := tea.NewProgram(m, progOpts...).Run(); err != nil {
+		return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("xxd: terminal: %s", err))
+	}
+
+	return nil
+}
+
+func viewSource(r io.Reader) (io.ReaderAt, int64, error) {
+	if f, ok := r.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return f, info.Size(), nil
+		}
+	}
+
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("xxd: read: %s", err))
+	}
+
+	return bytes.NewReader(buf), int64(len(buf)), nil
+}
+
+func newViewModel(data io.ReaderAt, size int64, name string, opts Options) viewModel {
+	m := viewModel{
+		data:   data,
+		end:    size,
+		name:   name,
+		cols:   opts.Columns,
+		groups: opts.Groups,
+		upper:  opts.Uppercase,
+		bits:   opts.Bits,
+		match:  -1,
+	}
+
+	if m.cols <= 0 {
+		m.cols = 16
+	}
+
+	if m.groups <= 0 {
+		m.groups = 2
+	}
+
+	m.start = min(int64(max(opts.Seek, 0)), size)
+	if opts.Length > 0 {
+		m.end = min(m.start+int64(opts.Length), size)
+	}
+
+	m.top = m.start
+
+	return m
+}
+
+func (m viewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m viewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = max(msg.Height-1, 1) // Reserve line for status
+		m.top = m.clamp(m.top)
+
+	case searchResultMsg:
+		m.searching = false
+
+		switch {
+		case msg.err != nil:
+			m.message = fmt.Sprintf("search: %s", msg.err)
+		case msg.offset < 0:
+			m.message = "Pattern not found"
+		default:
+			m.match = msg.offset
+			m.scrollTo(msg.offset)
+			m.message = fmt.Sprintf("Match at 0x%x", msg.offset)
+		}
+
+	case tea.KeyMsg:
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.quit = true
+			return m, tea.Quit
+
+		case "down", "j", "enter":
+			m.top = m.clamp(m.top + m.rowBytes())
+
+		case "up", "k":
+			m.top = m.clamp(m.top - m.rowBytes())
+
+		case "pgdown", " ", "ctrl+f":
+			m.top = m.clamp(m.top + m.page())
+
+		case "pgup", "ctrl+b":
+			m.top = m.clamp(m.top - m.page())
+
+		case "home", "g":
+			m.top = m.start
+
+		case "end", "G":
+			m.top = m.clamp(m.end)
+
+		case ":":
+			m.startPrompt(promptGoto)
+
+		case "/":
+			m.startPrompt(promptText)
+
+		case "x":
+			m.startPrompt(promptHex)
+
+		case "n", "N":
+			if !m.searching {
+				cmd := m.search(msg.String() == "n")
+				return m, cmd
+			}
+
+		case "b":
+			m.bits = !m.bits
+			m.top = m.clamp(m.top)
+			m.message = ""
+
+		case "h", "?":
+			m.message = "j/k:scroll :offset /text x:hex n/N:next/prev b:bits g/G:top/bottom q:quit"
+		}
+	}
+
+	return m, nil
+}
+
+func (m *viewModel) startPrompt(kind promptKind) {
+	m.prompt = kind
+	m.input = ""
+	m.message = ""
+}
+
+func (m viewModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		kind, input := m.prompt, m.input
+		m.prompt = promptNone
+
+		switch kind {
+		case promptGoto:
+			off, err := m.parseOffset(input)
+			if err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
+
+			m.scrollTo(off)
+		case promptText, promptHex:
+			pattern, err := parsePattern(input, kind == promptHex)
+			if err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
+
+			m.pattern = pattern
+			m.match = m.top - 1
+			cmd := m.search(true)
+
+			return m, cmd
+		}
+	case "esc", "ctrl+c":
+		m.prompt = promptNone
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.input += string(msg.Runes)
+		}
+	}
+
+	return m, nil
+}
+
+// parseOffset accepts a decimal, 0x hex or 0o octal offset relative to the
+// start of the file; a negative offset counts back from the end.
+func (m viewModel) parseOffset(s string) (int64, error) {
+	off, err := strconv.ParseInt(strings.TrimSpace(s), 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+
+	if off < 0 {
+		off += m.end
+	}
+
+	if off < m.start || off >= max(m.end, m.start+1) {
+		return 0, fmt.Errorf("offset %s is out of range", s)
+	}
+
+	return off, nil
+}
+
+// parsePattern returns the bytes to search for: the text as typed, or hex
+// digits with optional spaces ("de ad be ef").
+func parsePattern(s string, isHex bool) ([]byte, error) {
+	if !isHex {
+		if s == "" {
+			return nil, errors.New("empty pattern")
+		}
+
+		return []byte(s), nil
+	}
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid hex pattern %q", s)
+	}
+
+	return b, nil
+}
+
+// search returns a command finding the next (or previous) occurrence of
+// the pattern in the background, so a search through a large file does
+// not freeze the screen.
+func (m *viewModel) search(forward bool) tea.Cmd {
+	if len(m.pattern) == 0 {
+		m.message = "No previous search"
+		return nil
+	}
+
+	m.searching = true
+	m.message = "Searching..."
+
+	data, start, end, pattern := m.data, m.start, m.end, m.pattern
+	from := m.match + 1
+
+	if !forward {
+		from = m.match - 1
+		if m.match < 0 {
+			from = m.top - 1
+		}
+	}
+
+	return func() tea.Msg {
+		var (
+			off int64
+			err error
+		)
+
+		if forward {
+			off, err = searchForward(data, max(from, start), end, pattern)
+		} else {
+			off, err = searchBackward(data, start, from, end, pattern)
+		}
+
+		return searchResultMsg{offset: off, err: err}
+	}
+}
+
+// searchForward returns the first offset at or after from where pattern
+// occurs entirely before end, or -1.
+func searchForward(r io.ReaderAt, from, end int64, pattern []byte) (int64, error) {
+	overlap := int64(len(pattern) - 1)
+	buf := make([]byte, searchChunk+overlap)
+
+	for pos := from; pos+int64(len(pattern)) <= end; pos += searchChunk {
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), end-pos)], pos)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return -1, err
+		}
+
+		if i := bytes.Index(buf[:n], pattern); i >= 0 {
+			return pos + int64(i), nil
+		}
+	}
+
+	return -1, nil
+}
+
+// searchBackward returns the last offset at or before from, and not
+// before start, where pattern occurs entirely before end, or -1.
+func searchBackward(r io.ReaderAt, start, from, end int64, pattern []byte) (int64, error) {
+	overlap := int64(len(pattern) - 1)
+
+	for hi := from + 1; hi > start; hi -= searchChunk {
+		lo := max(hi-searchChunk, start)
+		buf := make([]byte, max(min(hi+overlap, end)-lo, 0))
+
+		n, err := r.ReadAt(buf, lo)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return -1, err
+		}
+
+		// Only matches starting before hi belong to this chunk
+		window := buf[:n]
+		for {
+			i := bytes.LastIndex(window, pattern)
+			if i < 0 {
+				break
+			}
+
+			if lo+int64(i) < hi {
+				return lo + int64(i), nil
+			}
+
+			window = window[:i+len(pattern)-1]
+		}
+	}
+
+	return -1, nil
+}
+
+// rowBytes is the number of bytes shown per row.
+func (m viewModel) rowBytes() int64 {
+	if m.bits {
+		return int64(min(m.cols, 6))
+	}
+
+	return int64(m.cols)
+}
+
+func (m viewModel) page() int64 {
+	return int64(max(m.height, 1)) * m.rowBytes()
+}
+
+// clamp aligns off to a row and keeps the last page full.
+func (m viewModel) clamp(off int64) int64 {
+	row := m.rowBytes()
+	last := m.start + max((m.end-m.start-1)/row-int64(max(m.height, 1)-1), 0)*row
+
+	off = m.start + max(off-m.start, 0)/row*row
+
+	return min(off, last)
+}
+
+// scrollTo makes off visible, keeping the view where it is if it already is.
+func (m *viewModel) scrollTo(off int64) {
+	if off >= m.top && off < m.top+m.page() {
+		return
+	}
+
+	m.top = m.clamp(off)
+}
+
+func (m viewModel) View() string {
+	if m.quit {
+		return ""
+	}
+
+	if m.height == 0 {
+		return "Loading..."
+	}
+
+	var sb strings.Builder
+
+	row := m.rowBytes()
+	buf := make([]byte, int64(m.height)*row)
+
+	// Rows that fail to read are shown as past the end
+	n, _ := m.data.ReadAt(buf[:min(int64(len(buf)), max(m.end-m.top, 0))], m.top)
+
+	for i := range m.height {
+		lo := int64(i) * row
+		if lo >= int64(n) {
+			sb.WriteString("~\n")
+			continue
+		}
+
+		sb.WriteString(m.renderRow(m.top+lo, buf[lo:min(lo+row, int64(n))]))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(m.status())
+
+	return sb.String()
+}
+
+var (
+	viewMatchStyle  = lipgloss.NewStyle().Background(lipgloss.Color("226")).Foreground(lipgloss.Color("0"))
+	viewOffsetStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	viewStatusStyle = lipgloss.NewStyle().Background(lipgloss.Color("236")).Foreground(lipgloss.Color("252"))
+)
+
+// renderRow formats one row like the dump output: offset, hex (or bits)
+// and ASCII, with the current match highlighted.
+func (m viewModel) renderRow(off int64, data []byte) string {
+	var sb strings.Builder
+
+	sb.WriteString(viewOffsetStyle.Render(fmt.Sprintf("%08x:", off)))
+
+	hexFmt := "%02x"
+	if m.upper {
+		hexFmt = "%02X"
+	}
+
+	cells := int(m.rowBytes())
+	for i := range cells {
+		switch {
+		case m.bits:
+			sb.WriteByte(' ')
+		case i%m.groups == 0:
+			sb.WriteByte(' ')
+		}
+
+		if i >= len(data) {
+			if m.bits {
+				sb.WriteString("        ")
+			} else {
+				sb.WriteString("  ")
+			}
+
+			continue
+		}
+
+		cell := fmt.Sprintf(hexFmt, data[i])
+		if m.bits {
+			cell = fmt.Sprintf("%08b", data[i])
+		}
+
+		sb.WriteString(m.highlight(off+int64(i), cell))
+	}
+
+	sb.WriteString("  ")
+
+	for i, b := range data {
+		c := "."
+		if b >= 32 && b < 127 {
+			c = string(rune(b))
+		}
+
+		sb.WriteString(m.highlight(off+int64(i), c))
+	}
+
+	return sb.String()
+}
+
+func (m viewModel) highlight(off int64, s string) string {
+	if m.match >= 0 && off >= m.match && off < m.match+int64(len(m.pattern)) {
+		return viewMatchStyle.Render(s)
+	}
+
+	return s
+}
+
+func (m viewModel) status() string {
+	var status string
+
+	switch m.prompt {
+	case promptGoto:
+		status = ":" + m.input
+	case promptText:
+		status = "/" + m.input
+	case promptHex:
+		status = "hex/" + m.input
+	default:
+		if m.message != "" {
+			status = m.message
+		} else {
+			percent := 100
+			if span := m.end - m.start; span > 0 {
+				percent = int(min((m.top-m.start+m.page())*100/span, 100))
+			}
+
+			mode := "hex"
+			if m.bits {
+				mode = "bits"
+			}
+
+			status = fmt.Sprintf(" %s  0x%08x/0x%08x  %d%%  [%s]", m.name, m.top, m.end, percent, mode)
+		}
+	}
+
+	if len(status) < m.width {
+		status += strings.Repeat(" ", m.width-len(status))
+	}
+
+	return viewStatusStyle.Render(status)
+}
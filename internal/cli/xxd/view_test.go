@@ -0,0 +1,182 @@
+package xxd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func keys(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// press feeds keys to the model, running any search command inline.
+func press(t *testing.T, m viewModel, in ...string) viewModel {
+	t.Helper()
+
+	for _, k := range in {
+		next, cmd := m.Update(keys(k))
+		m = next.(viewModel)
+
+		if cmd != nil {
+			next, _ = m.Update(cmd())
+			m = next.(viewModel)
+		}
+	}
+
+	return m
+}
+
+func newTestView(t *testing.T, data []byte, rows int, opts Options) viewModel {
+	t.Helper()
+
+	m := newViewModel(bytes.NewReader(data), int64(len(data)), "test.bin", opts)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: rows + 1})
+
+	return next.(viewModel)
+}
+
+func TestViewScroll(t *testing.T) {
+	data := make([]byte, 16*10) // 10 rows
+	m := newTestView(t, data, 4, DefaultOptions())
+
+	if m = press(t, m, "j", "j"); m.top != 32 {
+		t.Errorf("after jj top = %d, want 32", m.top)
+	}
+
+	if m = press(t, m, "G"); m.top != 16*6 {
+		t.Errorf("after G top = %d, want %d (last page)", m.top, 16*6)
+	}
+
+	if m = press(t, m, "j"); m.top != 16*6 {
+		t.Errorf("scrolled past the end: top = %d", m.top)
+	}
+
+	if m = press(t, m, "g", "k"); m.top != 0 {
+		t.Errorf("after gk top = %d, want 0", m.top)
+	}
+}
+
+func TestViewGoto(t *testing.T) {
+	data := make([]byte, 4096)
+	m := newTestView(t, data, 4, DefaultOptions())
+
+	m = press(t, m, ":", "0", "x", "8", "0", "0", "enter")
+	if m.top != 0x800 {
+		t.Errorf("goto 0x800: top = %#x", m.top)
+	}
+
+	m = press(t, m, ":", "-", "1", "enter")
+	if m.top != 4096-4*16 {
+		t.Errorf("goto -1: top = %d, want last page", m.top)
+	}
+
+	m = press(t, m, ":", "9", "9", "9", "9", "9", "enter")
+	if !strings.Contains(m.message, "out of range") {
+		t.Errorf("goto past end: message = %q", m.message)
+	}
+}
+
+func TestViewSearch(t *testing.T) {
+	data := make([]byte, 200000)
+	copy(data[1000:], "needle")
+	copy(data[searchChunk-2:], "needle") // straddles a chunk boundary
+	copy(data[150000:], []byte{0xde, 0xad, 0xbe, 0xef})
+
+	m := newTestView(t, data, 4, DefaultOptions())
+
+	m = press(t, m, "/", "n", "e", "e", "d", "l", "e", "enter")
+	if m.match != 1000 {
+		t.Fatalf("first match = %d, want 1000", m.match)
+	}
+
+	if m.top > 1000 || m.top+m.page() <= 1000 {
+		t.Errorf("match not visible: top = %d", m.top)
+	}
+
+	if m = press(t, m, "n"); m.match != searchChunk-2 {
+		t.Errorf("next match = %d, want %d", m.match, searchChunk-2)
+	}
+
+	if m = press(t, m, "n"); m.message != "Pattern not found" || m.match != searchChunk-2 {
+		t.Errorf("past last match: match = %d, message = %q", m.match, m.message)
+	}
+
+	if m = press(t, m, "N"); m.match != 1000 {
+		t.Errorf("previous match = %d, want 1000", m.match)
+	}
+
+	m = press(t, m, "x", "d", "e", " ", "a", "d", "b", "e", "e", "f", "enter")
+	if m.match != 150000 {
+		t.Errorf("hex match = %d, want 150000", m.match)
+	}
+
+	m = press(t, m, "x", "z", "z", "enter")
+	if !strings.Contains(m.message, "invalid hex pattern") {
+		t.Errorf("bad hex: message = %q", m.message)
+	}
+}
+
+func TestViewRender(t *testing.T) {
+	m := newTestView(t, []byte("Hello, hex viewer!\x00\x01"), 3, DefaultOptions())
+	out := m.View()
+
+	if !strings.Contains(out, "00000000: 4865 6c6c 6f2c 2068 6578 2076 6965 7765  Hello, hex viewe") {
+		t.Errorf("first row missing:\n%s", out)
+	}
+
+	if !strings.Contains(out, "00000010: 7221 0001") || !strings.Contains(out, "r!..") {
+		t.Errorf("second row missing:\n%s", out)
+	}
+
+	if !strings.Contains(out, "~") || !strings.Contains(out, "test.bin") {
+		t.Errorf("filler or status missing:\n%s", out)
+	}
+
+	m = press(t, m, "b")
+	if out = m.View(); !strings.Contains(out, "00000000: 01001000 01100101") || !strings.Contains(out, "[bits]") {
+		t.Errorf("bits view:\n%s", out)
+	}
+}
+
+func TestViewSeekAndLength(t *testing.T) {
+	data := make([]byte, 1024)
+	m := newTestView(t, data, 4, Options{Seek: 256, Length: 128})
+
+	if m.top != 256 {
+		t.Errorf("initial top = %d, want 256", m.top)
+	}
+
+	if m = press(t, m, "G"); m.top != 256+128-4*16 {
+		t.Errorf("end top = %d, want %d", m.top, 256+128-4*16)
+	}
+
+	m = press(t, m, ":", "1", "0", "0", "enter")
+	if !strings.Contains(m.message, "out of range") {
+		t.Errorf("goto before --seek: message = %q", m.message)
+	}
+}
+
+func TestSearchBackwardBoundary(t *testing.T) {
+	data := make([]byte, 3*searchChunk)
+	copy(data[searchChunk-1:], "ab")
+
+	off, err := searchBackward(bytes.NewReader(data), 0, int64(len(data))-1, int64(len(data)), []byte("ab"))
+	if err != nil || off != searchChunk-1 {
+		t.Errorf("searchBackward = %d, %v; want %d", off, err, searchChunk-1)
+	}
+
+	off, _ = searchForward(bytes.NewReader(data), searchChunk, int64(len(data)), []byte("ab"))
+	if off != -1 {
+		t.Errorf("searchForward after match = %d, want -1", off)
+	}
+}
@@ -25,6 +25,7 @@ type Options struct {
 	Include   bool // -i: C include file style output
 	Uppercase bool // -u: use uppercase hex letters
 	Bits      bool // -b: binary digit dump instead of hex
+	View      bool // --view: interactive viewer instead of a dump
 }
 
 // DefaultOptions returns the default options
@@ -61,6 +62,10 @@ func Run(w io.Writer, r io.Reader, args []string, opts Options) error {
 		filename = "stdin"
 	}
 
+	if opts.View {
+		return runView(input, filename, opts)
+	}
+
 	// Handle reverse mode
 	if opts.Reverse {
 		return runReverse(w, input, opts)
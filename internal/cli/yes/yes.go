@@ -2,40 +2,124 @@ package yes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 )
 
+// blockSize is how much output is built up front and written per call when
+// yes runs unthrottled.
+const blockSize = 8 * 1024
+
+// Options configures the yes command behavior
+type Options struct {
+	Count int     // -n: stop after COUNT lines, 0 = until killed
+	Rate  float64 // --rate: at most RATE lines per second, 0 = unlimited
+}
+
 // RunYes repeatedly outputs a string until killed
 func RunYes(ctx context.Context, w io.Writer, args []string) error {
-	output := "y"
+	return Run(ctx, w, args, Options{})
+}
+
+// Run outputs the joined args (or "y") until ctx is cancelled, a signal
+// arrives, the reader goes away or opts.Count lines were written. Without a
+// rate the line is repeated in large blocks; with one, line i is written at
+// start + i/rate so a slow writer does not make the output drift.
+func Run(ctx context.Context, w io.Writer, args []string, opts Options) error {
+	if opts.Count < 0 || opts.Rate < 0 {
+		return cmderr.Wrap(cmderr.ErrInvalidInput, "yes: count and rate must not be negative")
+	}
+
+	line := "y\n"
 	if len(args) > 0 {
-		output = strings.Join(args, " ")
+		line = strings.Join(args, " ") + "\n"
 	}
 
 	// Handle signals for a graceful shutdown
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGPIPE)
 
-	for {
+	defer signal.Stop(sigCh)
+
+	if opts.Rate > 0 {
+		return runThrottled(ctx, w, line, opts, sigCh)
+	}
+
+	perBlock := max(1, blockSize/len(line))
+	block := []byte(strings.Repeat(line, perBlock))
+
+	for written := 0; opts.Count == 0 || written < opts.Count; written += perBlock {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-sigCh:
 			return nil
 		default:
-			// Classify once per Pitfall 7: do not allocate per-iteration on broken pipe.
-			if _, err := fmt.Fprintln(w, output); err != nil {
-				return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("yes: write: %s", err))
+		}
+
+		chunk := block
+		if opts.Count > 0 && opts.Count-written < perBlock {
+			chunk = block[:(opts.Count-written)*len(line)]
+		}
+
+		if _, err := w.Write(chunk); err != nil {
+			return writeError(err)
+		}
+	}
+
+	return nil
+}
+
+func runThrottled(ctx context.Context, w io.Writer, line string, opts Options, sigCh <-chan os.Signal) error {
+	interval := time.Duration(float64(time.Second) / opts.Rate)
+	start := time.Now()
+
+	for i := 0; opts.Count == 0 || i < opts.Count; i++ {
+		if wait := time.Until(start.Add(time.Duration(i) * interval)); wait > 0 {
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-sigCh:
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+		} else {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-sigCh:
+				return nil
+			default:
 			}
 		}
+
+		if _, err := io.WriteString(w, line); err != nil {
+			return writeError(err)
+		}
 	}
+
+	return nil
+}
+
+// writeError treats a closed pipe (yes | head) as a normal end of output.
+func writeError(err error) error {
+	if errors.Is(err, syscall.EPIPE) {
+		return nil
+	}
+
+	return cmderr.Wrap(cmderr.ErrIO, fmt.Sprintf("yes: write: %s", err))
 }
 
 // Yes is a simple yes function for scripting
@@ -5,7 +5,9 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/inovacc/omni/internal/cli/cmderr"
 )
@@ -83,6 +85,48 @@ func (c *cancelAfterWriteWriter) Write(p []byte) (int, error) {
 	return n, err
 }
 
+func TestRunCount(t *testing.T) {
+	for _, n := range []int{1, 3, 5000} {
+		var buf bytes.Buffer
+		if err := Run(context.Background(), &buf, []string{"ok"}, Options{Count: n}); err != nil {
+			t.Fatalf("Run(count=%d) error = %v", n, err)
+		}
+
+		if got := strings.Count(buf.String(), "ok\n"); got != n || buf.Len() != n*3 {
+			t.Errorf("Run(count=%d) wrote %d lines (%d bytes)", n, got, buf.Len())
+		}
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	var buf bytes.Buffer
+
+	start := time.Now()
+	if err := Run(context.Background(), &buf, nil, Options{Count: 5, Rate: 100}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Lines go out at 0, 10, 20, 30 and 40ms
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("5 lines at 100/s took %v, want about 40ms", elapsed)
+	}
+
+	if buf.String() != strings.Repeat("y\n", 5) {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestRunBrokenPipe(t *testing.T) {
+	err := Run(context.Background(), errWriter{err: syscall.EPIPE}, nil, Options{})
+	if err != nil {
+		t.Errorf("EPIPE should end yes cleanly, got %v", err)
+	}
+
+	if err := Run(context.Background(), &bytes.Buffer{}, nil, Options{Count: -1}); !cmderr.IsInvalidInput(err) {
+		t.Errorf("negative count: got %v, want invalid input", err)
+	}
+}
+
 func TestYes(t *testing.T) {
 	t.Run("default output", func(t *testing.T) {
 		result := Yes("", 5)
@@ -0,0 +1,174 @@
+// Package config reads and writes the omni configuration file, a YAML
+// document with one top-level section per command:
+//
+//	rg:
+//	  type-add:
+//	    - web:*.html,*.css
+//
+// The file is $OMNI_CONFIG when set, otherwise config.yaml in the omni
+// directory under the user configuration directory. Sections a command
+// does not know about are kept as they are when the file is saved.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPath names the environment variable overriding the config file path.
+const EnvPath = "OMNI_CONFIG"
+
+// Path returns the location of the omni config file.
+func Path() (string, error) {
+	if p := os.Getenv(EnvPath); p != "" {
+		return p, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "omni", "config.yaml"), nil
+}
+
+// File is a loaded config file.
+type File struct {
+	path string
+	root *yaml.Node // mapping node holding the sections
+	head string     // comment above the first section
+}
+
+// Load reads the config file at Path. A missing file is not an error and
+// yields an empty config.
+func Load() (*File, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	return LoadFile(path)
+}
+
+// LoadFile reads the config file at path; a missing file yields an empty
+// config that Save creates.
+func LoadFile(path string) (*File, error) {
+	f := &File{path: path, root: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config: %s: top level must be a mapping of sections", path)
+	}
+
+	f.root, f.head = root, doc.HeadComment
+
+	return f, nil
+}
+
+// Path returns the file the config was loaded from.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Section decodes the named section into v. v is left unchanged when the
+// section is absent.
+func (f *File) Section(name string, v any) error {
+	node := f.lookup(name)
+	if node == nil {
+		return nil
+	}
+
+	if err := node.Decode(v); err != nil {
+		return fmt.Errorf("config: %s: section %s: %w", f.path, name, err)
+	}
+
+	return nil
+}
+
+// SetSection replaces the named section with v, adding it at the end when
+// it does not exist yet. The change is written by Save.
+func (f *File) SetSection(name string, v any) error {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return fmt.Errorf("config: section %s: %w", name, err)
+	}
+
+	for i := 0; i+1 < len(f.root.Content); i += 2 {
+		if f.root.Content[i].Value == name {
+			// Keep comments written next to the old value
+			node.HeadComment = f.root.Content[i+1].HeadComment
+			f.root.Content[i+1] = &node
+
+			return nil
+		}
+	}
+
+	f.root.Content = append(f.root.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name},
+		&node,
+	)
+
+	return nil
+}
+
+// Save writes the config back to its file, creating the directory when
+// needed.
+func (f *File) Save() error {
+	doc := &yaml.Node{Kind: yaml.DocumentNode, HeadComment: f.head, Content: []*yaml.Node{f.root}}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if err := os.WriteFile(f.path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	return nil
+}
+
+func (f *File) lookup(name string) *yaml.Node {
+	for i := 0; i+1 < len(f.root.Content); i += 2 {
+		if f.root.Content[i].Value == name {
+			return f.root.Content[i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type rgSection struct {
+	TypeAdd []string `yaml:"type-add"`
+}
+
+func TestLoadMissing(t *testing.T) {
+	f, err := LoadFile(filepath.Join(t.TempDir(), "nope", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s rgSection
+	if err := f.Section("rg", &s); err != nil || len(s.TypeAdd) != 0 {
+		t.Errorf("Section() = %+v, %v", s, err)
+	}
+}
+
+func TestSetSectionKeepsOthers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	initial := "# omni settings\nvideo:\n  rate: 1M # daytime limit\nrg:\n  type-add: [old:*.old]\n"
+	if err := os.WriteFile(path, []byte(initial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetSection("rg", rgSection{TypeAdd: []string{"web:*.html,*.css"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetSection("extra", map[string]int{"limit": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# omni settings\nvideo:\n  rate: 1M # daytime limit\nrg:\n  type-add:\n    - web:*.html,*.css\nextra:\n  limit: 1\n"
+	if string(data) != want {
+		t.Errorf("saved =\n%s\nwant\n%s", data, want)
+	}
+
+	f, err = LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s rgSection
+	if err := f.Section("rg", &s); err != nil || len(s.TypeAdd) != 1 || s.TypeAdd[0] != "web:*.html,*.css" {
+		t.Errorf("Section() = %+v, %v", s, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(path, []byte("- a\n- b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "mapping") {
+		t.Errorf("LoadFile() error = %v", err)
+	}
+}
+
+func TestPathEnv(t *testing.T) {
+	t.Setenv(EnvPath, "/tmp/omni-test.yaml")
+
+	if p, err := Path(); err != nil || p != "/tmp/omni-test.yaml" {
+		t.Errorf("Path() = %q, %v", p, err)
+	}
+}
@@ -216,7 +216,16 @@ func EnableFeature(feature string, data string) error {
 		return f.Close()
 	}
 
-	return os.Rename(disabled, enabled)
+	if err := os.Rename(disabled, enabled); err != nil {
+		return err
+	}
+
+	// The disabled file keeps the data it was enabled with; replace it.
+	if data != "" {
+		return os.WriteFile(enabled, []byte(data), 0o644)
+	}
+
+	return nil
 }
 
 func DisableFeature(feature string) error {
@@ -121,6 +121,31 @@ func TestEnableDisableToggle(t *testing.T) {
 	}
 }
 
+func TestEnableFeatureReplacesData(t *testing.T) {
+	feature := testFeatureName(t, "DATA")
+	t.Cleanup(func() { cleanupFeature(t, feature) })
+
+	if err := ensureInit(); err != nil {
+		t.Fatalf("ensureInit() failed: %v", err)
+	}
+
+	if err := EnableFeature(feature, "old"); err != nil {
+		t.Fatalf("EnableFeature() failed: %v", err)
+	}
+
+	if err := DisableFeature(feature); err != nil {
+		t.Fatalf("DisableFeature() failed: %v", err)
+	}
+
+	if err := EnableFeature(feature, "new"); err != nil {
+		t.Fatalf("EnableFeature() failed on re-enable: %v", err)
+	}
+
+	if got := GetFeatureData(feature); got != "new" {
+		t.Errorf("GetFeatureData() = %q, want %q", got, "new")
+	}
+}
+
 func TestLoadFeatureFlags(t *testing.T) {
 	feature1 := testFeatureName(t, "LOAD1")
 	feature2 := testFeatureName(t, "LOAD2")
@@ -0,0 +1,169 @@
+// Package i18n provides the message catalogs used to localize command
+// output. Catalogs are embedded JSON files (locales/<tag>.json) mapping
+// message keys to fmt format strings; en is the source catalog and the
+// fallback for missing keys.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Default is the source language and the fallback for missing keys.
+const Default = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+
+	mu      sync.RWMutex
+	current = Default
+)
+
+func load() {
+	catalogs = make(map[string]map[string]string)
+
+	entries, _ := localeFS.ReadDir("locales")
+	for _, e := range entries {
+		data, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			continue
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: bad catalog %s: %v", e.Name(), err))
+		}
+
+		catalogs[strings.TrimSuffix(e.Name(), ".json")] = messages
+	}
+}
+
+// Available returns the supported language tags, sorted.
+func Available() []string {
+	loadOnce.Do(load)
+
+	tags := make([]string, 0, len(catalogs))
+	for tag := range catalogs {
+		tags = append(tags, tag)
+	}
+
+	slices.Sort(tags)
+
+	return tags
+}
+
+// Normalize turns a locale such as "pt_BR.UTF-8" or "PT-br" into a BCP 47
+// style tag ("pt-BR"). "C", "POSIX" and "" normalize to "".
+func Normalize(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return ""
+	}
+
+	lang, region, ok := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+// Match returns the supported tag for a requested locale: an exact match,
+// else the first catalog with the same language ("pt-PT" → "pt-BR"). ok is
+// false when no catalog matches.
+func Match(locale string) (tag string, ok bool) {
+	loadOnce.Do(load)
+
+	want := Normalize(locale)
+	if want == "" {
+		return "", false
+	}
+
+	if _, ok := catalogs[want]; ok {
+		return want, true
+	}
+
+	lang, _, _ := strings.Cut(want, "-")
+	for _, tag := range Available() {
+		if base, _, _ := strings.Cut(tag, "-"); base == lang {
+			return tag, true
+		}
+	}
+
+	return "", false
+}
+
+// Detect returns the language from the environment, checked in POSIX
+// order: LC_ALL, LC_MESSAGES, LANG. It returns Default when none of them
+// names a supported language.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		if tag, ok := Match(value); ok {
+			return tag
+		}
+
+		// The first variable that is set wins, as in setlocale(3).
+		return Default
+	}
+
+	return Default
+}
+
+// SetLang selects the output language. An unsupported locale leaves the
+// current language unchanged and returns an error.
+func SetLang(locale string) error {
+	tag, ok := Match(locale)
+	if !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", locale, strings.Join(Available(), ", "))
+	}
+
+	mu.Lock()
+	current = tag
+	mu.Unlock()
+
+	return nil
+}
+
+// Lang returns the selected language tag.
+func Lang() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return current
+}
+
+// T returns the message for key in the selected language, formatted with
+// args. Missing keys fall back to the en catalog, then to the key itself.
+func T(key string, args ...any) string {
+	loadOnce.Do(load)
+
+	msg, ok := catalogs[Lang()][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = key
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, args...)
+}
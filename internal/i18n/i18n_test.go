@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func setLang(t *testing.T, tag string) {
+	t.Helper()
+
+	if err := SetLang(tag); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = SetLang(Default) })
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"pt_BR.UTF-8":     "pt-BR",
+		"PT-br":           "pt-BR",
+		"en_US.utf8@euro": "en-US",
+		"pt":              "pt",
+		"C":               "",
+		"POSIX":           "",
+		"":                "",
+	}
+
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+		ok     bool
+	}{
+		{"pt_BR.UTF-8", "pt-BR", true},
+		{"pt-PT", "pt-BR", true},
+		{"pt", "pt-BR", true},
+		{"en_GB", "en", true},
+		{"de_DE", "", false},
+		{"C.UTF-8", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Match(tt.locale)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Match(%q) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+
+	if got := Detect(); got != "pt-BR" {
+		t.Errorf("Detect() with LANG=pt_BR = %q", got)
+	}
+
+	// LC_ALL takes precedence even when it names an unsupported locale.
+	t.Setenv("LC_ALL", "C")
+
+	if got := Detect(); got != Default {
+		t.Errorf("Detect() with LC_ALL=C = %q, want %q", got, Default)
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("brdoc.invalid", "123"); got != "123: invalid" {
+		t.Errorf("T(en) = %q", got)
+	}
+
+	setLang(t, "pt-BR")
+
+	if got := T("brdoc.invalid", "123"); got != "123: inválido" {
+		t.Errorf("T(pt-BR) = %q", got)
+	}
+
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T(missing) = %q, want the key", got)
+	}
+
+	if err := SetLang("xx"); err == nil {
+		t.Error("SetLang(xx) expected error")
+	}
+
+	if Lang() != "pt-BR" {
+		t.Errorf("failed SetLang changed the language to %q", Lang())
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	loadOnce.Do(load)
+
+	for _, tag := range Available() {
+		for key := range catalogs[Default] {
+			if _, ok := catalogs[tag][key]; !ok {
+				t.Errorf("%s catalog is missing %q", tag, key)
+			}
+		}
+
+		for key := range catalogs[tag] {
+			if _, ok := catalogs[Default][key]; !ok && !strings.HasPrefix(key, "date.") {
+				t.Errorf("%s catalog has %q, which en does not define", tag, key)
+			}
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Date(2026, time.March, 1, 9, 5, 0, 0, time.UTC)
+	layout := "Monday, 02 January 2006 (Mon Jan) 15:04"
+
+	if got, want := FormatTime(ts, layout), ts.Format(layout); got != want {
+		t.Errorf("FormatTime(en) = %q, want %q", got, want)
+	}
+
+	setLang(t, "pt-BR")
+
+	if got, want := FormatTime(ts, layout), "domingo, 01 março 2026 (dom mar) 09:05"; got != want {
+		t.Errorf("FormatTime(pt-BR) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nameTokens are the Go layout elements that spell out names, longest
+// first so "January" is not read as "Jan" followed by "uary".
+var nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+
+// FormatTime is time.Format with month and weekday names in the selected
+// language. Numeric layout elements are unaffected.
+func FormatTime(t time.Time, layout string) string {
+	if Lang() == Default {
+		return t.Format(layout)
+	}
+
+	var b strings.Builder
+
+	start := 0
+
+	for i := 0; i < len(layout); {
+		token := ""
+
+		for _, tok := range nameTokens {
+			if strings.HasPrefix(layout[i:], tok) {
+				token = tok
+				break
+			}
+		}
+
+		if token == "" {
+			i++
+			continue
+		}
+
+		b.WriteString(t.Format(layout[start:i]))
+		b.WriteString(name(t, token))
+
+		i += len(token)
+		start = i
+	}
+
+	b.WriteString(t.Format(layout[start:]))
+
+	return b.String()
+}
+
+func name(t time.Time, token string) string {
+	var key string
+
+	switch token {
+	case "January":
+		key = "date.month." + strconv.Itoa(int(t.Month()))
+	case "Jan":
+		key = "date.month_short." + strconv.Itoa(int(t.Month()))
+	case "Monday":
+		key = "date.weekday." + strconv.Itoa(int(t.Weekday()))
+	default:
+		key = "date.weekday_short." + strconv.Itoa(int(t.Weekday()))
+	}
+
+	if msg := T(key); msg != key {
+		return msg
+	}
+
+	return t.Format(token)
+}
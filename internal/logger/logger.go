@@ -7,12 +7,14 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/inovacc/omni/internal/flags"
 	"github.com/segmentio/ksuid"
+	"github.com/spf13/pflag"
 )
 
 const (
@@ -36,6 +38,7 @@ type Logger struct {
 	active    bool
 	command   string
 	execution *CommandExecution
+	redactor  *Redactor
 	mu        sync.Mutex
 }
 
@@ -190,6 +193,17 @@ func initLogger(command string) *Logger {
 		Level: slog.LevelInfo,
 	}))
 	l.active = true
+	l.redactor = DefaultRedactor()
+
+	rules, err := LoadRedactRules()
+	if err == nil {
+		l.redactor, err = NewRedactor(rules)
+	}
+
+	if err != nil {
+		_, _ = os.Stderr.WriteString("omni: ignoring custom log redaction rules: " + err.Error() + "\n")
+		l.redactor = DefaultRedactor()
+	}
 
 	return l
 }
@@ -246,6 +260,7 @@ func New(logDir, command string) (*Logger, error) {
 		Level: slog.LevelInfo,
 	}))
 	l.active = true
+	l.redactor = DefaultRedactor()
 
 	return l, nil
 }
@@ -276,6 +291,7 @@ func NewWithExactPath(logPath string) (*Logger, error) {
 		Level: slog.LevelInfo,
 	}))
 	l.active = true
+	l.redactor = DefaultRedactor()
 
 	return l, nil
 }
@@ -286,6 +302,34 @@ func Get() *Logger {
 	return instance
 }
 
+// SetRedactor replaces the redactor applied to arguments, output, errors
+// and queries before they are logged. A nil redactor logs them as is. Call
+// it before logging starts.
+func (l *Logger) SetRedactor(r *Redactor) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.redactor = r
+}
+
+// SetFlags lets the redactor resolve short flags through fs, the flag set of
+// the command being logged, so -p is redacted wherever it means --password.
+// Call it before logging starts.
+func (l *Logger) SetFlags(fs *pflag.FlagSet) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.redactor = l.redactor.WithFlags(fs)
+}
+
 // IsActive returns true if logging is enabled.
 func (l *Logger) IsActive() bool {
 	if l == nil {
@@ -303,7 +347,7 @@ func (l *Logger) LogCommand(args []string) {
 
 	l.slog.Info("command_start",
 		"cmd", l.command,
-		"args", args,
+		"args", l.redactor.Args(args),
 		"timestamp", time.Now().Format(time.RFC3339),
 		"pid", os.Getpid(),
 	)
@@ -321,7 +365,7 @@ func (l *Logger) StartExecution(command string, args []string, stdout, stderr io
 
 	l.execution = &CommandExecution{
 		Command:   command,
-		Args:      args,
+		Args:      l.redactor.Args(args),
 		StartTime: time.Now(),
 		Stdout:    NewLoggingWriter(stdout),
 		Stderr:    NewLoggingWriter(stderr),
@@ -329,7 +373,7 @@ func (l *Logger) StartExecution(command string, args []string, stdout, stderr io
 
 	l.slog.Info("command_start",
 		"cmd", command,
-		"args", args,
+		"args", l.execution.Args,
 		"timestamp", l.execution.StartTime.Format(time.RFC3339),
 		"pid", os.Getpid(),
 	)
@@ -359,12 +403,12 @@ func (l *Logger) EndExecution(err error) {
 
 	if err != nil {
 		status = "error"
-		errMsg = err.Error()
+		errMsg = l.redactor.String(err.Error())
 	}
 
 	// Prepare output for logging
-	stdout := l.execution.Stdout.String()
-	stderr := l.execution.Stderr.String()
+	stdout := l.redactor.String(l.execution.Stdout.String())
+	stderr := l.redactor.String(l.execution.Stderr.String())
 	stdoutTruncated := l.execution.Stdout.IsTruncated()
 	stderrTruncated := l.execution.Stderr.IsTruncated()
 
@@ -437,12 +481,12 @@ func (l *Logger) LogCommandWithResult(command string, args []string, err error)
 
 	if err != nil {
 		status = "error"
-		errMsg = err.Error()
+		errMsg = l.redactor.String(err.Error())
 	}
 
 	attrs := []any{
 		"cmd", command,
-		"args", args,
+		"args", l.redactor.Args(args),
 		"status", status,
 		"timestamp", time.Now().Format(time.RFC3339),
 		"pid", os.Getpid(),
@@ -455,12 +499,26 @@ func (l *Logger) LogCommandWithResult(command string, args []string, err error)
 	l.slog.Info("command", attrs...)
 }
 
-// LogRaw logs a raw message with optional key-value pairs.
+// LogRaw logs a raw message with optional key-value pairs. String and
+// string slice values are redacted.
 func (l *Logger) LogRaw(msg string, args ...any) {
 	if l == nil || !l.active {
 		return
 	}
 
+	if l.redactor != nil {
+		args = slices.Clone(args)
+
+		for i := 1; i < len(args); i += 2 {
+			switch v := args[i].(type) {
+			case string:
+				args[i] = l.redactor.String(v)
+			case []string:
+				args[i] = l.redactor.Args(v)
+			}
+		}
+	}
+
 	l.slog.Info(msg, args...)
 }
 
@@ -472,7 +530,7 @@ func (l *Logger) LogQuery(database, query string) {
 
 	l.slog.Info("query",
 		"database", database,
-		"query", query,
+		"query", l.redactor.String(query),
 		"timestamp", time.Now().Format(time.RFC3339),
 		"pid", os.Getpid(),
 	)
@@ -490,12 +548,12 @@ func (l *Logger) LogQueryResult(database, query string, rowCount int, duration t
 
 	if err != nil {
 		status = "error"
-		errMsg = err.Error()
+		errMsg = l.redactor.String(err.Error())
 	}
 
 	attrs := []any{
 		"database", database,
-		"query", query,
+		"query", l.redactor.String(query),
 		"status", status,
 		"rows", rowCount,
 		"duration_ms", duration.Milliseconds(),
@@ -523,12 +581,12 @@ func (l *Logger) LogQueryWithData(database, query string, columns []string, rows
 
 	if err != nil {
 		status = "error"
-		errMsg = err.Error()
+		errMsg = l.redactor.String(err.Error())
 	}
 
 	attrs := []any{
 		"database", database,
-		"query", query,
+		"query", l.redactor.String(query),
 		"status", status,
 		"row_count", len(rows),
 		"columns", columns,
@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommandStats holds aggregated metrics for one command.
+type CommandStats struct {
+	Command   string  `json:"command"`
+	Count     int     `json:"count"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	P50Ms     int64   `json:"p50_ms"`
+	P95Ms     int64   `json:"p95_ms"`
+	MaxMs     int64   `json:"max_ms"`
+
+	durations []int64
+}
+
+// StatsReport is the aggregate over a set of log files.
+type StatsReport struct {
+	Files    int            `json:"files"`
+	Entries  int            `json:"entries"`
+	Skipped  int            `json:"skipped"` // lines that were not valid JSON
+	Commands []CommandStats `json:"commands"`
+}
+
+// StatsOptions filters the entries taken into account.
+type StatsOptions struct {
+	Since   time.Time // Ignore executions that started before Since (zero = all)
+	Command string    // Only aggregate this command (empty = all)
+}
+
+// logEntry is the subset of a command_end record used for metrics.
+type logEntry struct {
+	Msg        string `json:"msg"`
+	Cmd        string `json:"cmd"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	StartTime  string `json:"start_time"`
+}
+
+// Aggregator accumulates command_end records into per-command metrics.
+type Aggregator struct {
+	opts     StatsOptions
+	report   StatsReport
+	commands map[string]*CommandStats
+}
+
+// NewAggregator creates an empty aggregator.
+func NewAggregator(opts StatsOptions) *Aggregator {
+	return &Aggregator{
+		opts:     opts,
+		commands: make(map[string]*CommandStats),
+	}
+}
+
+// AddReader consumes JSON log lines from r. Only command_end records carry
+// status and duration, so other messages are ignored.
+func (a *Aggregator) AddReader(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*MaxOutputSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var e logEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			a.report.Skipped++
+			continue
+		}
+
+		if e.Msg != "command_end" || e.Cmd == "" {
+			continue
+		}
+
+		if a.opts.Command != "" && e.Cmd != a.opts.Command {
+			continue
+		}
+
+		if !a.opts.Since.IsZero() {
+			if t, err := time.Parse(time.RFC3339, e.StartTime); err == nil && t.Before(a.opts.Since) {
+				continue
+			}
+		}
+
+		a.add(e)
+	}
+
+	return scanner.Err()
+}
+
+// AddDir consumes every *.log file in dir.
+func (a *Aggregator) AddDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(files)
+
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		err = a.AddReader(f)
+		_ = f.Close()
+
+		if err != nil {
+			return err
+		}
+
+		a.report.Files++
+	}
+
+	return nil
+}
+
+func (a *Aggregator) add(e logEntry) {
+	s, ok := a.commands[e.Cmd]
+	if !ok {
+		s = &CommandStats{Command: e.Cmd}
+		a.commands[e.Cmd] = s
+	}
+
+	s.Count++
+	if e.Status == "error" {
+		s.Errors++
+	}
+
+	s.durations = append(s.durations, e.DurationMs)
+	a.report.Entries++
+}
+
+// Report computes the final metrics, sorted by count (descending) then name.
+func (a *Aggregator) Report() StatsReport {
+	report := a.report
+	report.Commands = make([]CommandStats, 0, len(a.commands))
+
+	for _, s := range a.commands {
+		sort.Slice(s.durations, func(i, j int) bool { return s.durations[i] < s.durations[j] })
+
+		out := *s
+		out.ErrorRate = float64(s.Errors) / float64(s.Count)
+		out.P50Ms = percentile(s.durations, 50)
+		out.P95Ms = percentile(s.durations, 95)
+		out.MaxMs = s.durations[len(s.durations)-1]
+		out.durations = nil
+
+		report.Commands = append(report.Commands, out)
+	}
+
+	sort.Slice(report.Commands, func(i, j int) bool {
+		ci, cj := report.Commands[i], report.Commands[j]
+		if ci.Count != cj.Count {
+			return ci.Count > cj.Count
+		}
+
+		return ci.Command < cj.Command
+	})
+
+	return report
+}
+
+// Stats aggregates all log files in dir.
+func Stats(dir string, opts StatsOptions) (StatsReport, error) {
+	a := NewAggregator(opts)
+	if err := a.AddDir(dir); err != nil {
+		return StatsReport{}, err
+	}
+
+	return a.Report(), nil
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsFromLogger(t *testing.T) {
+	dir := t.TempDir()
+
+	for i, fail := range []bool{false, true, false} {
+		log, err := New(dir, fmt.Sprintf("cat%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = log.StartExecution("cat", []string{"x"}, io.Discard, io.Discard)
+
+		var runErr error
+		if fail {
+			runErr = errors.New("boom")
+		}
+
+		log.EndExecution(runErr)
+		_ = log.Close()
+	}
+
+	report, err := Stats(dir, StatsOptions{})
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if report.Files != 3 || report.Entries != 3 || len(report.Commands) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	cat := report.Commands[0]
+	if cat.Command != "cat" || cat.Count != 3 || cat.Errors != 1 {
+		t.Errorf("unexpected cat stats: %+v", cat)
+	}
+}
+
+func TestAggregatorPercentiles(t *testing.T) {
+	var sb strings.Builder
+
+	for i := 1; i <= 20; i++ {
+		status := "success"
+		if i%5 == 0 {
+			status = "error"
+		}
+
+		fmt.Fprintf(&sb, `{"msg":"command_end","cmd":"grep","status":%q,"duration_ms":%d,"start_time":"2026-01-02T00:00:00Z"}`+"\n", status, i*10)
+	}
+
+	sb.WriteString(`{"msg":"command_start","cmd":"grep"}` + "\n")
+	sb.WriteString(`{"msg":"command_end","cmd":"ls","status":"success","duration_ms":5,"start_time":"2025-01-01T00:00:00Z"}` + "\n")
+	sb.WriteString("not json\n")
+
+	a := NewAggregator(StatsOptions{})
+	if err := a.AddReader(strings.NewReader(sb.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	report := a.Report()
+	if report.Entries != 21 || report.Skipped != 1 {
+		t.Errorf("entries=%d skipped=%d", report.Entries, report.Skipped)
+	}
+
+	grep := report.Commands[0]
+	if grep.Command != "grep" || grep.Errors != 4 || grep.ErrorRate != 0.2 {
+		t.Errorf("unexpected grep stats: %+v", grep)
+	}
+
+	if grep.P50Ms != 100 || grep.P95Ms != 190 || grep.MaxMs != 200 {
+		t.Errorf("p50=%d p95=%d max=%d", grep.P50Ms, grep.P95Ms, grep.MaxMs)
+	}
+
+	// Since and Command filters
+	a = NewAggregator(StatsOptions{Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Command: "ls"})
+	_ = a.AddReader(strings.NewReader(sb.String()))
+
+	if report := a.Report(); report.Entries != 0 {
+		t.Errorf("filters not applied: %+v", report)
+	}
+}
+
+func TestStatsMissingDir(t *testing.T) {
+	report, err := Stats(filepath.Join(t.TempDir(), "none"), StatsOptions{})
+	if err != nil || report.Files != 0 {
+		t.Errorf("Stats(missing) = %+v, %v", report, err)
+	}
+}
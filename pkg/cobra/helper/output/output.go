@@ -1,5 +1,6 @@
 // Package output provides unified output formatting for CLI commands.
-// It supports text, JSON, and table formats with consistent behavior.
+// It supports text, JSON, table, Markdown and CSV formats with consistent
+// behavior; tables are rendered by pkg/tablewriter.
 package output
 
 import (
@@ -7,7 +8,8 @@ import (
 	"fmt"
 	"io"
 	"strings"
-	"text/tabwriter"
+
+	"github.com/inovacc/omni/pkg/tablewriter"
 )
 
 // Format represents the output format type
@@ -20,6 +22,10 @@ const (
 	FormatJSON
 	// FormatTable outputs data in aligned columns
 	FormatTable
+	// FormatMarkdown outputs tables as GitHub-flavored Markdown
+	FormatMarkdown
+	// FormatCSV outputs tables as CSV
+	FormatCSV
 )
 
 // Formatter handles output in various formats
@@ -58,12 +64,41 @@ func (f *Formatter) IsJSON() bool {
 	return f.format == FormatJSON
 }
 
+// IsTable returns true if format asks for a table: aligned, Markdown or CSV
+func (f *Formatter) IsTable() bool {
+	return f.format == FormatTable || f.format == FormatMarkdown || f.format == FormatCSV
+}
+
+// PrintTable renders t in the table format matching f: Markdown or CSV
+// when asked for, aligned text otherwise. Text tables without a MaxWidth
+// are fitted to the terminal f writes to. Plain text output that does not
+// go to a terminal has no sort indicators, which scripts would read as an
+// extra field.
+func (f *Formatter) PrintTable(t *tablewriter.Table) error {
+	switch f.format {
+	case FormatMarkdown:
+		return t.Render(f.w, tablewriter.Markdown)
+	case FormatCSV:
+		return t.Render(f.w, tablewriter.CSV)
+	case FormatText:
+		if !tablewriter.IsTerminal(f.w) {
+			t.HideSort = true
+		}
+	}
+
+	if t.MaxWidth == 0 {
+		t.MaxWidth = tablewriter.TerminalWidth(f.w)
+	}
+
+	return t.Render(f.w, tablewriter.Text)
+}
+
 // Print outputs data according to the format
 func (f *Formatter) Print(data any) error {
 	switch f.format {
 	case FormatJSON:
 		return f.printJSON(data)
-	case FormatTable:
+	case FormatTable, FormatMarkdown, FormatCSV:
 		return f.printTable(data)
 	case FormatText:
 		return f.printText(data)
@@ -142,23 +177,37 @@ func (f *Formatter) printText(data any) error {
 }
 
 func (f *Formatter) printTable(data any) error {
-	// For table format, use tabwriter for aligned columns
-	tw := tabwriter.NewWriter(f.w, 0, 0, 2, ' ', 0)
+	var rows [][]string
 
 	switch v := data.(type) {
 	case [][]string:
-		for _, row := range v {
-			_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
-		}
+		rows = v
 	case []string:
-		for _, s := range v {
-			_, _ = fmt.Fprintln(tw, s)
+		rows = make([][]string, len(v))
+		for i, s := range v {
+			rows[i] = strings.Split(s, "\t")
 		}
 	default:
-		_, _ = fmt.Fprintf(tw, "%v\n", data)
+		_, err := fmt.Fprintf(f.w, "%v\n", data)
+		return err
 	}
 
-	return tw.Flush()
+	// Aligned text has no header row; Markdown and CSV take the first row
+	// as the header.
+	if f.format == FormatTable {
+		return f.PrintTable(&tablewriter.Table{Rows: rows, NoHeader: true})
+	}
+
+	t := &tablewriter.Table{}
+	if len(rows) > 0 {
+		for _, h := range rows[0] {
+			t.Columns = append(t.Columns, tablewriter.Column{Header: h})
+		}
+
+		t.Rows = rows[1:]
+	}
+
+	return f.PrintTable(t)
 }
 
 // Result represents a command result that can be formatted
@@ -232,6 +281,10 @@ func (f *Formatter) FormatName() string {
 		return "json"
 	case FormatTable:
 		return "table"
+	case FormatMarkdown:
+		return "markdown"
+	case FormatCSV:
+		return "csv"
 	default:
 		return "text"
 	}
@@ -239,8 +292,10 @@ func (f *Formatter) FormatName() string {
 
 // Options that can be embedded in command options
 type Options struct {
-	JSON  bool // --json flag
-	Table bool // --table flag
+	JSON     bool // --json flag
+	Table    bool // --table flag
+	Markdown bool // --markdown flag
+	CSV      bool // --csv flag
 }
 
 // GetFormat returns the format based on options
@@ -253,6 +308,14 @@ func (o Options) GetFormat() Format {
 		return FormatTable
 	}
 
+	if o.Markdown {
+		return FormatMarkdown
+	}
+
+	if o.CSV {
+		return FormatCSV
+	}
+
 	return FormatText
 }
 
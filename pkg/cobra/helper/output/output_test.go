@@ -157,6 +157,25 @@ func TestFormatter_Printf(t *testing.T) {
 	}
 }
 
+func TestFormatter_PrintTableWide(t *testing.T) {
+	var buf bytes.Buffer
+
+	data := [][]string{
+		{"名前", "値"},
+		{"😀", "x"},
+		{"abc", "y"},
+	}
+
+	if err := NewTable(&buf).Print(data); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	want := "名前  値\n😀    x\nabc   y\n"
+	if buf.String() != want {
+		t.Errorf("Print() = %q, want %q", buf.String(), want)
+	}
+}
+
 func TestFormatter_PrintTable(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -183,6 +202,38 @@ func TestFormatter_PrintTable(t *testing.T) {
 	}
 }
 
+func TestFormatter_PrintTableMarkdownCSV(t *testing.T) {
+	data := [][]string{
+		{"Name", "Value"},
+		{"foo", "a|b"},
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatMarkdown, "| Name | Value |\n| ---- | ----- |\n| foo  | a\\|b  |\n"},
+		{FormatCSV, "Name,Value\nfoo,a|b\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+
+		f := New(&buf, tt.format)
+		if !f.IsTable() {
+			t.Errorf("%s: IsTable() = false", f.FormatName())
+		}
+
+		if err := f.Print(data); err != nil {
+			t.Fatalf("Print() error = %v", err)
+		}
+
+		if buf.String() != tt.want {
+			t.Errorf("%s: Print() = %q, want %q", f.FormatName(), buf.String(), tt.want)
+		}
+	}
+}
+
 func TestFormatter_PrintText_Slice(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -324,6 +375,8 @@ func TestFormatter_FormatName(t *testing.T) {
 		{FormatText, "text"},
 		{FormatJSON, "json"},
 		{FormatTable, "table"},
+		{FormatMarkdown, "markdown"},
+		{FormatCSV, "csv"},
 	}
 
 	for _, tt := range tests {
@@ -362,6 +415,16 @@ func TestOptions_GetFormat(t *testing.T) {
 			opts: Options{JSON: true, Table: true},
 			want: FormatJSON,
 		},
+		{
+			name: "markdown",
+			opts: Options{Markdown: true},
+			want: FormatMarkdown,
+		},
+		{
+			name: "csv",
+			opts: Options{CSV: true},
+			want: FormatCSV,
+		},
 	}
 
 	for _, tt := range tests {
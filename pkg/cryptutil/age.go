@@ -0,0 +1,778 @@
+package cryptutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Public-key encryption in the age v1 format (age-encryption.org/v1) with
+// X25519 recipients. A random 16-byte file key encrypts the payload; the
+// header carries one stanza per recipient with the file key wrapped for
+// that recipient's public key, so any one of the matching identities can
+// decrypt. Files are interchangeable with the age and rage tools:
+//
+//	age-encryption.org/v1
+//	-> X25519 <ephemeral share>
+//	<wrapped file key>
+//	--- <header MAC>
+//	<16-byte nonce><payload in 64 KiB ChaCha20-Poly1305 chunks>
+
+const (
+	ageIntro       = "age-encryption.org/v1"
+	ageArmorBegin  = "-----BEGIN AGE ENCRYPTED FILE-----"
+	ageArmorEnd    = "-----END AGE ENCRYPTED FILE-----"
+	ageX25519Label = "age-encryption.org/v1/X25519"
+	ageRecipientHR = "age"
+	ageIdentityHRP = "age-secret-key-"
+	ageFileKeySize = 16
+	ageNonceSize   = 16
+	ageChunkSize   = 64 << 10
+	ageColumns     = 64
+	ageMaxLine     = 4096
+)
+
+var (
+	// ErrNotAge is returned for data that is not an age file.
+	ErrNotAge = errors.New("cryptutil: not an age encrypted file")
+	// ErrNoIdentityMatch is returned when none of the identities can unwrap
+	// the file key, i.e. the file was not encrypted to any of them.
+	ErrNoIdentityMatch = errors.New("cryptutil: no identity matched any of the file's recipients")
+)
+
+var ageB64 = base64.RawStdEncoding.Strict()
+
+// X25519Identity is an age private key.
+type X25519Identity struct {
+	key *ecdh.PrivateKey
+}
+
+// X25519Recipient is an age public key.
+type X25519Recipient struct {
+	key *ecdh.PublicKey
+}
+
+// GenerateX25519Identity returns a new random identity.
+func GenerateX25519Identity() (*X25519Identity, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: generate identity: %w", err)
+	}
+
+	return &X25519Identity{key: key}, nil
+}
+
+// ParseX25519Identity parses an "AGE-SECRET-KEY-1..." string.
+func ParseX25519Identity(s string) (*X25519Identity, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: malformed identity: %w", err)
+	}
+
+	if hrp != ageIdentityHRP {
+		return nil, fmt.Errorf("cryptutil: malformed identity: unexpected type %q", hrp)
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: malformed identity: %w", err)
+	}
+
+	return &X25519Identity{key: key}, nil
+}
+
+// Recipient returns the public key that encrypts to i.
+func (i *X25519Identity) Recipient() *X25519Recipient {
+	return &X25519Recipient{key: i.key.PublicKey()}
+}
+
+// String returns the identity in its "AGE-SECRET-KEY-1..." form.
+func (i *X25519Identity) String() string {
+	s, _ := bech32Encode(ageIdentityHRP, i.key.Bytes())
+	return strings.ToUpper(s)
+}
+
+// ParseX25519Recipient parses an "age1..." string.
+func ParseX25519Recipient(s string) (*X25519Recipient, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: malformed recipient %q: %w", s, err)
+	}
+
+	if hrp != ageRecipientHR {
+		return nil, fmt.Errorf("cryptutil: malformed recipient %q: unexpected type %q", s, hrp)
+	}
+
+	key, err := ecdh.X25519().NewPublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: malformed recipient %q: %w", s, err)
+	}
+
+	return &X25519Recipient{key: key}, nil
+}
+
+// String returns the recipient in its "age1..." form.
+func (r *X25519Recipient) String() string {
+	s, _ := bech32Encode(ageRecipientHR, r.key.Bytes())
+	return s
+}
+
+// FormatIdentityFile returns i in the layout of an age-keygen key file:
+// comments with the creation time and public key, then the secret key.
+func FormatIdentityFile(i *X25519Identity, created time.Time) string {
+	return fmt.Sprintf("# created: %s\n# public key: %s\n%s\n",
+		created.Format(time.RFC3339), i.Recipient(), i)
+}
+
+// ParseIdentities reads identities from an age key file: one
+// "AGE-SECRET-KEY-1..." per line, with blank lines and "#" comments ignored.
+func ParseIdentities(r io.Reader) ([]*X25519Identity, error) {
+	var ids []*X25519Identity
+
+	err := eachKeyLine(r, func(line string) error {
+		id, err := ParseX25519Identity(line)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, id)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("cryptutil: no identities found")
+	}
+
+	return ids, nil
+}
+
+// ParseRecipients reads recipients from a recipients file: one "age1..." per
+// line, with blank lines and "#" comments ignored.
+func ParseRecipients(r io.Reader) ([]*X25519Recipient, error) {
+	var recs []*X25519Recipient
+
+	err := eachKeyLine(r, func(line string) error {
+		rec, err := ParseX25519Recipient(line)
+		if err != nil {
+			return err
+		}
+
+		recs = append(recs, rec)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recs) == 0 {
+		return nil, errors.New("cryptutil: no recipients found")
+	}
+
+	return recs, nil
+}
+
+func eachKeyLine(r io.Reader, fn func(line string) error) error {
+	sc := bufio.NewScanner(r)
+
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := fn(line); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+
+	return sc.Err()
+}
+
+// IsAge reports whether data starts like an age file, binary or armored.
+func IsAge(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageIntro+"\n")) ||
+		bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte(ageArmorBegin))
+}
+
+// AgeEncrypt starts an age file on dst, encrypted to every recipient.
+// Everything written to the returned writer is encrypted; Close must be
+// called to flush the final chunk. It does not close dst.
+func AgeEncrypt(dst io.Writer, recipients ...*X25519Recipient) (io.WriteCloser, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("cryptutil: no recipients")
+	}
+
+	fileKey := make([]byte, ageFileKeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+
+	var hdr bytes.Buffer
+
+	hdr.WriteString(ageIntro + "\n")
+
+	for _, r := range recipients {
+		share, body, err := r.wrap(fileKey)
+		if err != nil {
+			return nil, err
+		}
+
+		writeStanza(&hdr, []string{"X25519", ageB64.EncodeToString(share)}, body)
+	}
+
+	hdr.WriteString("---")
+
+	mac, err := headerMAC(fileKey, hdr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	hdr.WriteString(" " + ageB64.EncodeToString(mac) + "\n")
+
+	nonce := make([]byte, ageNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	hdr.Write(nonce)
+
+	aead, err := payloadAEAD(fileKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dst.Write(hdr.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &ageWriter{aead: aead, dst: dst, buf: make([]byte, 0, ageChunkSize)}, nil
+}
+
+// AgeDecrypt reads the header of the age file in src, binary or armored,
+// and returns a reader of its plaintext. The first identity that unwraps a
+// recipient stanza is used; ErrNoIdentityMatch is returned when none does.
+// The payload is authenticated chunk by chunk as it is read.
+func AgeDecrypt(src io.Reader, identities ...*X25519Identity) (io.Reader, error) {
+	br := bufio.NewReader(src)
+
+	if peek, _ := br.Peek(len(ageArmorBegin) + 64); bytes.HasPrefix(bytes.TrimLeft(peek, " \t\r\n"), []byte(ageArmorBegin)) {
+		br = bufio.NewReader(&armorReader{br: br})
+	}
+
+	hdr, stanzas, mac, err := readAgeHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileKey []byte
+
+	for _, s := range stanzas {
+		if s.args[0] != "X25519" {
+			continue
+		}
+
+		for _, id := range identities {
+			fileKey, err = id.unwrap(s)
+			if err != nil {
+				return nil, err
+			}
+
+			if fileKey != nil {
+				break
+			}
+		}
+
+		if fileKey != nil {
+			break
+		}
+	}
+
+	if fileKey == nil {
+		return nil, ErrNoIdentityMatch
+	}
+
+	want, err := headerMAC(fileKey, hdr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(mac, want) {
+		return nil, errors.New("cryptutil: age header MAC mismatch")
+	}
+
+	nonce := make([]byte, ageNonceSize)
+	if _, err := io.ReadFull(br, nonce); err != nil {
+		return nil, fmt.Errorf("cryptutil: age payload nonce: %w", err)
+	}
+
+	aead, err := payloadAEAD(fileKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ageReader{aead: aead, src: br, buf: make([]byte, ageChunkSize+chacha20poly1305.Overhead)}, nil
+}
+
+// wrap encrypts fileKey to r with an ephemeral X25519 key, returning the
+// ephemeral public share and the wrapped key.
+func (r *X25519Recipient) wrap(fileKey []byte) (share, body []byte, err error) {
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared, err := eph.ECDH(r.key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	share = eph.PublicKey().Bytes()
+
+	wrapKey, err := hkdf.Key(sha256.New, shared, append(append([]byte{}, share...), r.key.Bytes()...), ageX25519Label, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := chacha20poly1305.New(wrapKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return share, aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil), nil
+}
+
+// unwrap returns the file key of an X25519 stanza, or nil when the stanza
+// was made for another recipient.
+func (i *X25519Identity) unwrap(s ageStanza) ([]byte, error) {
+	if len(s.args) != 2 {
+		return nil, errors.New("cryptutil: malformed X25519 stanza")
+	}
+
+	share, err := ageB64.DecodeString(s.args[1])
+	if err != nil || len(share) != 32 || len(s.body) != ageFileKeySize+chacha20poly1305.Overhead {
+		return nil, errors.New("cryptutil: malformed X25519 stanza")
+	}
+
+	pub, err := ecdh.X25519().NewPublicKey(share)
+	if err != nil {
+		return nil, errors.New("cryptutil: malformed X25519 stanza")
+	}
+
+	shared, err := i.key.ECDH(pub)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: X25519 stanza: %w", err)
+	}
+
+	wrapKey, err := hkdf.Key(sha256.New, shared, append(share, i.key.PublicKey().Bytes()...), ageX25519Label, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(wrapKey)
+	if err != nil {
+		return nil, err
+	}
+
+	fileKey, err := aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), s.body, nil)
+	if err != nil {
+		return nil, nil //nolint:nilerr // not our stanza
+	}
+
+	return fileKey, nil
+}
+
+type ageStanza struct {
+	args []string
+	body []byte
+}
+
+// writeStanza writes "-> args" and the base64 body wrapped at 64 columns;
+// the last body line is always shorter than 64 columns, possibly empty.
+func writeStanza(w *bytes.Buffer, args []string, body []byte) {
+	w.WriteString("-> " + strings.Join(args, " ") + "\n")
+
+	enc := ageB64.EncodeToString(body)
+	for len(enc) >= ageColumns {
+		w.WriteString(enc[:ageColumns] + "\n")
+		enc = enc[ageColumns:]
+	}
+
+	w.WriteString(enc + "\n")
+}
+
+// readAgeHeader parses the header up to the MAC line and returns the bytes
+// the MAC covers, the stanzas and the MAC.
+func readAgeHeader(br *bufio.Reader) (hdr []byte, stanzas []ageStanza, mac []byte, err error) {
+	var buf bytes.Buffer
+
+	line, err := readAgeLine(br, &buf)
+	if err != nil || line != ageIntro {
+		return nil, nil, nil, ErrNotAge
+	}
+
+	for {
+		line, err := readAgeLine(br, &buf)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if rest, ok := strings.CutPrefix(line, "--- "); ok {
+			mac, err := ageB64.DecodeString(rest)
+			if err != nil || len(mac) != sha256.Size {
+				return nil, nil, nil, errors.New("cryptutil: malformed age header MAC")
+			}
+
+			hdr := buf.Bytes()
+
+			return hdr[:len(hdr)-len(line)-1+len("---")], stanzas, mac, nil
+		}
+
+		rest, ok := strings.CutPrefix(line, "-> ")
+		if !ok || rest == "" {
+			return nil, nil, nil, fmt.Errorf("cryptutil: malformed age header line %q", line)
+		}
+
+		s := ageStanza{args: strings.Split(rest, " ")}
+
+		for {
+			bl, err := readAgeLine(br, &buf)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			if len(bl) > ageColumns {
+				return nil, nil, nil, errors.New("cryptutil: malformed age stanza body")
+			}
+
+			b, err := ageB64.DecodeString(bl)
+			if err != nil {
+				return nil, nil, nil, errors.New("cryptutil: malformed age stanza body")
+			}
+
+			s.body = append(s.body, b...)
+
+			if len(bl) < ageColumns {
+				break
+			}
+		}
+
+		stanzas = append(stanzas, s)
+	}
+}
+
+// readAgeLine reads one "\n"-terminated header line, records it in buf and
+// returns it without the newline.
+func readAgeLine(br *bufio.Reader, buf *bytes.Buffer) (string, error) {
+	var line []byte
+
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return "", fmt.Errorf("cryptutil: truncated age header: %w", err)
+		}
+
+		buf.WriteByte(b)
+
+		if b == '\n' {
+			return string(line), nil
+		}
+
+		if len(line) == ageMaxLine {
+			return "", errors.New("cryptutil: age header line too long")
+		}
+
+		line = append(line, b)
+	}
+}
+
+func headerMAC(fileKey, hdr []byte) ([]byte, error) {
+	key, err := hkdf.Key(sha256.New, fileKey, nil, "header", sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hmac.New(sha256.New, key)
+	h.Write(hdr)
+
+	return h.Sum(nil), nil
+}
+
+func payloadAEAD(fileKey, nonce []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, fileKey, nonce, "payload", chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	return chacha20poly1305.New(key)
+}
+
+// chunkNonce is the STREAM nonce: an 11-byte big-endian counter and a
+// final byte that is 1 for the last chunk.
+func chunkNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+
+	if last {
+		nonce[11] = 1
+	}
+
+	return nonce
+}
+
+// ageWriter encrypts the payload in 64 KiB chunks. A full chunk is held
+// back until more data arrives, since only Close knows which is last.
+type ageWriter struct {
+	aead    cipher.AEAD
+	dst     io.Writer
+	buf     []byte
+	counter uint64
+	err     error
+}
+
+func (w *ageWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	total := len(p)
+
+	for len(p) > 0 {
+		if len(w.buf) == ageChunkSize {
+			if w.err = w.flush(false); w.err != nil {
+				return total - len(p), w.err
+			}
+		}
+
+		n := min(ageChunkSize-len(w.buf), len(p))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+	}
+
+	return total, nil
+}
+
+// Close encrypts the last chunk. It does not close the underlying writer.
+func (w *ageWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.err = w.flush(true)
+	if w.err == nil {
+		w.err = errors.New("cryptutil: write to closed age writer")
+		return nil
+	}
+
+	return w.err
+}
+
+func (w *ageWriter) flush(last bool) error {
+	out := w.aead.Seal(nil, chunkNonce(w.counter, last), w.buf, nil)
+	w.counter++
+	w.buf = w.buf[:0]
+
+	_, err := w.dst.Write(out)
+
+	return err
+}
+
+// ageReader decrypts and authenticates the payload chunk by chunk.
+type ageReader struct {
+	aead    cipher.AEAD
+	src     *bufio.Reader
+	buf     []byte
+	plain   []byte
+	counter uint64
+	done    bool
+	err     error
+}
+
+func (r *ageReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if r.done {
+			return 0, io.EOF
+		}
+
+		r.err = r.next()
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+
+	return n, nil
+}
+
+func (r *ageReader) next() error {
+	n, err := io.ReadFull(r.src, r.buf)
+
+	switch {
+	case err == nil:
+		// A full chunk is the last one only if nothing follows it.
+		if _, perr := r.src.Peek(1); perr == io.EOF {
+			r.done = true
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		r.done = true
+	default:
+		return err
+	}
+
+	if n < chacha20poly1305.Overhead {
+		return errors.New("cryptutil: truncated age payload")
+	}
+
+	plain, err := r.aead.Open(r.buf[:0], chunkNonce(r.counter, r.done), r.buf[:n], nil)
+	if err != nil {
+		return errors.New("cryptutil: age payload authentication failed (truncated or modified)")
+	}
+
+	if r.done && len(plain) == 0 && r.counter > 0 {
+		return errors.New("cryptutil: age payload ends with an empty chunk")
+	}
+
+	r.counter++
+	r.plain = plain
+
+	return nil
+}
+
+// AgeArmor returns a writer that encodes everything written to it as an
+// armored age file ("-----BEGIN AGE ENCRYPTED FILE-----"). Close writes the
+// footer; it does not close dst.
+func AgeArmor(dst io.Writer) io.WriteCloser {
+	lw := &lineWrapper{dst: dst}
+	_, lw.err = io.WriteString(dst, ageArmorBegin+"\n")
+
+	return &armorWriter{lw: lw, enc: base64.NewEncoder(base64.StdEncoding, lw)}
+}
+
+type armorWriter struct {
+	lw  *lineWrapper
+	enc io.WriteCloser
+}
+
+func (a *armorWriter) Write(p []byte) (int, error) {
+	if a.lw.err != nil {
+		return 0, a.lw.err
+	}
+
+	return a.enc.Write(p)
+}
+
+func (a *armorWriter) Close() error {
+	if err := a.enc.Close(); err != nil {
+		return err
+	}
+
+	if a.lw.col > 0 {
+		_, _ = io.WriteString(a.lw.dst, "\n")
+	}
+
+	_, err := io.WriteString(a.lw.dst, ageArmorEnd+"\n")
+
+	return err
+}
+
+// lineWrapper inserts a newline every 64 bytes.
+type lineWrapper struct {
+	dst io.Writer
+	col int
+	err error
+}
+
+func (l *lineWrapper) Write(p []byte) (int, error) {
+	total := len(p)
+
+	for len(p) > 0 && l.err == nil {
+		n := min(ageColumns-l.col, len(p))
+		_, l.err = l.dst.Write(p[:n])
+		l.col += n
+		p = p[n:]
+
+		if l.col == ageColumns && l.err == nil {
+			_, l.err = io.WriteString(l.dst, "\n")
+			l.col = 0
+		}
+	}
+
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	return total, nil
+}
+
+// armorReader decodes an armored age file line by line.
+type armorReader struct {
+	br      *bufio.Reader
+	started bool
+	done    bool
+	buf     []byte
+}
+
+func (a *armorReader) Read(p []byte) (int, error) {
+	for len(a.buf) == 0 {
+		if a.done {
+			return 0, io.EOF
+		}
+
+		line, err := a.br.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return 0, fmt.Errorf("cryptutil: truncated age armor: %w", io.ErrUnexpectedEOF)
+		}
+
+		line = strings.TrimSpace(line)
+
+		switch {
+		case !a.started:
+			if line == "" {
+				continue
+			}
+
+			if line != ageArmorBegin {
+				return 0, ErrNotAge
+			}
+
+			a.started = true
+		case line == ageArmorEnd:
+			a.done = true
+		case len(line) > ageColumns:
+			return 0, errors.New("cryptutil: malformed age armor: line too long")
+		default:
+			b, err := base64.StdEncoding.Strict().DecodeString(line)
+			if err != nil {
+				return 0, fmt.Errorf("cryptutil: malformed age armor: %w", err)
+			}
+
+			a.buf = b
+		}
+	}
+
+	n := copy(p, a.buf)
+	a.buf = a.buf[n:]
+
+	return n, nil
+}
@@ -0,0 +1,270 @@
+package cryptutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ageRoundTrip(t *testing.T, plaintext []byte, armor bool, recipients []*X25519Recipient, identities ...*X25519Identity) ([]byte, []byte, error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	var dst io.Writer = &buf
+
+	var aw io.WriteCloser
+	if armor {
+		aw = AgeArmor(&buf)
+		dst = aw
+	}
+
+	w, err := AgeEncrypt(dst, recipients...)
+	if err != nil {
+		t.Fatalf("AgeEncrypt() error = %v", err)
+	}
+
+	if _, err := w.Write(plaintext); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if aw != nil {
+		if err := aw.Close(); err != nil {
+			t.Fatalf("armor Close() error = %v", err)
+		}
+	}
+
+	r, err := AgeDecrypt(bytes.NewReader(buf.Bytes()), identities...)
+	if err != nil {
+		return buf.Bytes(), nil, err
+	}
+
+	got, err := io.ReadAll(r)
+
+	return buf.Bytes(), got, err
+}
+
+func TestAgeRoundTrip(t *testing.T) {
+	id, err := GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := []int{0, 1, 1000, ageChunkSize - 1, ageChunkSize, ageChunkSize + 1, 3 * ageChunkSize}
+
+	for _, armor := range []bool{false, true} {
+		for _, n := range sizes {
+			plaintext := bytes.Repeat([]byte("0123456789abcdef"), n/16+1)[:n]
+
+			sealed, got, err := ageRoundTrip(t, plaintext, armor, []*X25519Recipient{id.Recipient()}, id)
+			if err != nil {
+				t.Fatalf("size %d armor %v: %v", n, armor, err)
+			}
+
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("size %d armor %v: plaintext mismatch (%d bytes)", n, armor, len(got))
+			}
+
+			if !IsAge(sealed) {
+				t.Errorf("size %d armor %v: IsAge() = false", n, armor)
+			}
+		}
+	}
+}
+
+func TestAgeMultipleRecipients(t *testing.T) {
+	a, _ := GenerateX25519Identity()
+	b, _ := GenerateX25519Identity()
+	c, _ := GenerateX25519Identity()
+
+	recipients := []*X25519Recipient{a.Recipient(), b.Recipient()}
+
+	for _, id := range []*X25519Identity{a, b} {
+		if _, got, err := ageRoundTrip(t, []byte("ci artifact"), false, recipients, c, id); err != nil || string(got) != "ci artifact" {
+			t.Errorf("decrypt with second identity = %q, %v", got, err)
+		}
+	}
+
+	if _, _, err := ageRoundTrip(t, []byte("x"), false, recipients, c); !errors.Is(err, ErrNoIdentityMatch) {
+		t.Errorf("decrypt with a stranger's identity error = %v, want ErrNoIdentityMatch", err)
+	}
+}
+
+func TestAgeHeaderFormat(t *testing.T) {
+	id, _ := GenerateX25519Identity()
+
+	sealed, _, err := ageRoundTrip(t, []byte("x"), false, []*X25519Recipient{id.Recipient()}, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.SplitN(string(sealed), "\n", 5)
+	if lines[0] != "age-encryption.org/v1" || !strings.HasPrefix(lines[1], "-> X25519 ") || len(lines[2]) != 43 || !strings.HasPrefix(lines[3], "--- ") {
+		t.Errorf("header = %q", lines[:4])
+	}
+
+	armored, _, err := ageRoundTrip(t, []byte("x"), true, []*X25519Recipient{id.Recipient()}, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := string(armored)
+	if !strings.HasPrefix(text, "-----BEGIN AGE ENCRYPTED FILE-----\n") || !strings.HasSuffix(text, "\n-----END AGE ENCRYPTED FILE-----\n") {
+		t.Errorf("armor = %q", text)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if len(line) > 64 {
+			t.Errorf("armor line longer than 64 columns: %q", line)
+		}
+	}
+}
+
+func TestAgeTamper(t *testing.T) {
+	id, _ := GenerateX25519Identity()
+
+	var buf bytes.Buffer
+
+	w, _ := AgeEncrypt(&buf, id.Recipient())
+	_, _ = w.Write(bytes.Repeat([]byte("a"), 2*ageChunkSize))
+	_ = w.Close()
+
+	sealed := buf.Bytes()
+	hdrEnd := bytes.Index(sealed, []byte("\n---")) + 1
+	payload := hdrEnd + bytes.IndexByte(sealed[hdrEnd:], '\n') + 1 + ageNonceSize
+
+	decrypt := func(data []byte) error {
+		r, err := AgeDecrypt(bytes.NewReader(data), id)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.ReadAll(r)
+
+		return err
+	}
+
+	cases := map[string][]byte{
+		"truncated chunk":   sealed[:len(sealed)-1],
+		"dropped last":      sealed[:payload+ageChunkSize+16],
+		"flipped payload":   flip(sealed, len(sealed)-20),
+		"flipped header":    flip(sealed, len("age-encryption.org/v1\n-> X25519 ")+2),
+		"not age":           []byte("hello"),
+		"truncated header":  sealed[:hdrEnd],
+		"appended garbage":  append(append([]byte{}, sealed...), 'x'),
+		"wrong stanza body": flip(sealed, bytes.IndexByte(sealed[len("age-encryption.org/v1\n-> X25519 "):], '\n')+len("age-encryption.org/v1\n-> X25519 ")+3),
+	}
+
+	for name, data := range cases {
+		if err := decrypt(data); err == nil {
+			t.Errorf("%s: decrypt error = nil", name)
+		}
+	}
+
+	if err := decrypt(sealed); err != nil {
+		t.Errorf("untouched: decrypt error = %v", err)
+	}
+}
+
+func flip(data []byte, i int) []byte {
+	out := append([]byte{}, data...)
+	out[i] ^= 0x01
+
+	return out
+}
+
+func TestAgeKeys(t *testing.T) {
+	id, _ := GenerateX25519Identity()
+
+	s := id.String()
+	if !strings.HasPrefix(s, "AGE-SECRET-KEY-1") || strings.ToUpper(s) != s {
+		t.Errorf("identity = %q", s)
+	}
+
+	back, err := ParseX25519Identity(s)
+	if err != nil || back.Recipient().String() != id.Recipient().String() {
+		t.Errorf("ParseX25519Identity() = %v, %v", back, err)
+	}
+
+	// The example recipient of the age README.
+	const readme = "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"
+
+	r, err := ParseX25519Recipient(readme)
+	if err != nil || r.String() != readme {
+		t.Errorf("ParseX25519Recipient(readme) = %v, %v", r, err)
+	}
+
+	for _, bad := range []string{
+		"",
+		"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8q", // checksum
+		"AGE1QL3Z7HJY54PW3HYWW5AYYFG7ZQGVC7W3J2ELW8ZMRJ2KG5SFN9aqmcac8p", // mixed case
+		s, // an identity is not a recipient
+	} {
+		if _, err := ParseX25519Recipient(bad); err == nil {
+			t.Errorf("ParseX25519Recipient(%q) error = nil", bad)
+		}
+	}
+
+	if _, err := ParseX25519Identity(readme); err == nil {
+		t.Error("ParseX25519Identity(recipient) error = nil")
+	}
+
+	file := FormatIdentityFile(id, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if !strings.HasPrefix(file, "# created: 2026-01-02T03:04:05Z\n# public key: age1") {
+		t.Errorf("identity file = %q", file)
+	}
+
+	ids, err := ParseIdentities(strings.NewReader(file + "\n# another\n" + s + "\n"))
+	if err != nil || len(ids) != 2 {
+		t.Errorf("ParseIdentities() = %d, %v", len(ids), err)
+	}
+
+	if _, err := ParseIdentities(strings.NewReader("# nothing\n")); err == nil {
+		t.Error("ParseIdentities(empty) error = nil")
+	}
+
+	recs, err := ParseRecipients(strings.NewReader("# team\n" + readme + "\n\n" + id.Recipient().String() + "\n"))
+	if err != nil || len(recs) != 2 {
+		t.Errorf("ParseRecipients() = %d, %v", len(recs), err)
+	}
+
+	if _, err := ParseRecipients(strings.NewReader("age1nope\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("ParseRecipients(bad) error = %v", err)
+	}
+}
+
+func TestBech32Vectors(t *testing.T) {
+	// Valid strings from BIP 173.
+	for _, s := range []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	} {
+		if _, _, err := bech32Decode(s); err != nil {
+			t.Errorf("bech32Decode(%q) error = %v", s, err)
+		}
+	}
+
+	for _, s := range []string{"pzry9x0s0muk", "1pzry9x0s0muk", "x1b4n0q5v", "li1dgmt3", "A1G7SGD8", "10a06t8", "1qzzfhee"} {
+		if _, _, err := bech32Decode(s); err == nil {
+			t.Errorf("bech32Decode(%q) error = nil", s)
+		}
+	}
+
+	enc, err := bech32Encode("test", []byte{0, 1, 2, 255})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hrp, data, err := bech32Decode(enc); err != nil || hrp != "test" || !bytes.Equal(data, []byte{0, 1, 2, 255}) {
+		t.Errorf("bech32 round trip = %q %v %v", hrp, data, err)
+	}
+}
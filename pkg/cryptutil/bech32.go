@@ -0,0 +1,152 @@
+package cryptutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bech32 (BIP 173) as used by age for its keys, without the 90 character
+// limit: "age1..." recipients and "AGE-SECRET-KEY-1..." identities.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Gen = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+		for i, g := range bech32Gen {
+			if (top>>i)&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+
+	for i := range len(hrp) {
+		out = append(out, hrp[i]>>5)
+	}
+
+	out = append(out, 0)
+
+	for i := range len(hrp) {
+		out = append(out, hrp[i]&31)
+	}
+
+	return out
+}
+
+// convertBits regroups data from frombits-bit to tobits-bit values.
+func convertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+
+	maxv := uint32(1)<<tobits - 1
+
+	for _, b := range data {
+		if uint32(b)>>frombits != 0 {
+			return nil, errors.New("invalid data range")
+		}
+
+		acc = acc<<frombits | uint32(b)
+		bits += frombits
+
+		for bits >= tobits {
+			bits -= tobits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(tobits-bits)&maxv))
+		}
+	} else if bits >= frombits || acc<<(tobits-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+
+	return out, nil
+}
+
+// bech32Encode encodes data under the lower-case hrp.
+func bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	chk := bech32Polymod(append(append(bech32HRPExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var b strings.Builder
+
+	b.WriteString(hrp)
+	b.WriteByte('1')
+
+	for _, v := range values {
+		b.WriteByte(bech32Charset[v])
+	}
+
+	for i := range 6 {
+		b.WriteByte(bech32Charset[chk>>(5*(5-i))&31])
+	}
+
+	return b.String(), nil
+}
+
+// bech32Decode decodes s, which must be all lower or all upper case, and
+// returns its lower-case hrp and data.
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errors.New("mixed case")
+	}
+
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, errors.New("separator '1' at invalid position")
+	}
+
+	hrp := s[:pos]
+
+	for i := range len(hrp) {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("invalid character in human-readable part: %q", hrp[i])
+		}
+	}
+
+	values := make([]byte, 0, len(s)-pos-1)
+
+	for i := pos + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid character in data part: %q", s[i])
+		}
+
+		values = append(values, byte(v))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, errors.New("invalid checksum")
+	}
+
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
@@ -0,0 +1,17 @@
+package cryptutil
+
+import "crypto/subtle"
+
+// Equal reports whether a and b are equal in constant time: how long it
+// takes depends on their lengths but never on their contents, so it is safe
+// for comparing MACs, tokens and other secrets. Comparing secrets with ==
+// or bytes.Equal returns at the first differing byte and leaks how much of
+// a guess was right.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualString is Equal for strings.
+func EqualString(a, b string) bool {
+	return Equal([]byte(a), []byte(b))
+}
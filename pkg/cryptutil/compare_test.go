@@ -0,0 +1,25 @@
+package cryptutil
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"secret", "secret", true},
+		{"secret", "Secret", false},
+		{"secret", "secret!", false},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := EqualString(tt.a, tt.b); got != tt.want {
+			t.Errorf("EqualString(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+
+		if got := Equal([]byte(tt.a), []byte(tt.b)); got != tt.want {
+			t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
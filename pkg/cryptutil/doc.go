@@ -1,4 +1,21 @@
 // Package cryptutil provides AES-256-GCM symmetric encryption and
 // decryption with PBKDF2 key derivation. It supports functional options
 // for iteration count and base64 output encoding.
+//
+// SealEnvelope implements envelope encryption: each envelope is encrypted
+// with a random data key that is wrapped by a KeyWrapper (passphrase or RSA
+// master key) and stored in the header, so RotateMasterKey can change the
+// master key without re-encrypting the payload.
+//
+// Split and Combine implement Shamir's Secret Sharing over GF(2^8), so a
+// master key can be divided among operators and recovered from any
+// threshold of their shares.
+//
+// AgeEncrypt and AgeDecrypt implement public-key encryption in the age v1
+// format with X25519 recipients: a file can be encrypted to several
+// recipients ("age1..." keys) and decrypted by any one of their identities
+// ("AGE-SECRET-KEY-1..."), and files interoperate with the age tool.
+// AgeArmor writes the PEM-style armored form, which AgeDecrypt detects.
+//
+// Equal and EqualString compare secrets in constant time.
 package cryptutil
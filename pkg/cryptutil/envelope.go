@@ -0,0 +1,326 @@
+package cryptutil
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// EnvelopeMagic starts every envelope produced by SealEnvelope.
+const EnvelopeMagic = "OMNIENV1"
+
+var (
+	// ErrNotEnvelope is returned for data that is not a sealed envelope.
+	ErrNotEnvelope = errors.New("cryptutil: not an envelope")
+	// ErrUnwrap is returned when the data key cannot be unwrapped, e.g.
+	// because the master key is wrong.
+	ErrUnwrap = errors.New("cryptutil: cannot unwrap data key (wrong master key?)")
+)
+
+// KeyWrapper protects the per-envelope data encryption key (DEK) with a
+// master key.
+type KeyWrapper interface {
+	// Scheme names the wrapping algorithm; it is stored in the envelope header.
+	Scheme() string
+	// WrapKey encrypts dek with the master key.
+	WrapKey(dek []byte) ([]byte, error)
+	// UnwrapKey recovers the dek from the output of WrapKey.
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// EnvelopeHeader describes a sealed envelope without decrypting it.
+type EnvelopeHeader struct {
+	Scheme      string `json:"scheme"`
+	WrappedKey  []byte `json:"wrapped_key"`
+	PayloadSize int    `json:"payload_size"` // nonce + ciphertext + tag
+}
+
+// SealEnvelope encrypts plaintext with a fresh random AES-256-GCM data key
+// and stores that key, wrapped by kw, in the header:
+//
+//	magic | scheme len (u16) | scheme | wrapped len (u32) | wrapped key | nonce | ciphertext
+//
+// The payload is authenticated against the magic only, so RotateMasterKey
+// can replace the wrapped key without touching the payload.
+func SealEnvelope(plaintext []byte, kw KeyWrapper) ([]byte, error) {
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("cryptutil: failed to generate data key: %w", err)
+	}
+
+	defer clear(dek)
+
+	wrapped, err := kw.WrapKey(dek)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: wrap data key: %w", err)
+	}
+
+	payload, err := sealGCM(dek, plaintext, []byte(EnvelopeMagic))
+	if err != nil {
+		return nil, err
+	}
+
+	return buildEnvelope(kw.Scheme(), wrapped, payload), nil
+}
+
+// OpenEnvelope decrypts an envelope produced by SealEnvelope.
+func OpenEnvelope(data []byte, kw KeyWrapper) ([]byte, error) {
+	hdr, payload, err := parseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dek, err := unwrapFor(hdr, kw)
+	if err != nil {
+		return nil, err
+	}
+
+	defer clear(dek)
+
+	plaintext, err := openGCM(dek, payload, []byte(EnvelopeMagic))
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: envelope payload authentication failed")
+	}
+
+	return plaintext, nil
+}
+
+// RotateMasterKey rewraps the data key of an envelope from oldKW to newKW.
+// The encrypted payload is copied unchanged, so rotating is cheap regardless
+// of the envelope size.
+func RotateMasterKey(data []byte, oldKW, newKW KeyWrapper) ([]byte, error) {
+	hdr, payload, err := parseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dek, err := unwrapFor(hdr, oldKW)
+	if err != nil {
+		return nil, err
+	}
+
+	defer clear(dek)
+
+	wrapped, err := newKW.WrapKey(dek)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: wrap data key: %w", err)
+	}
+
+	return buildEnvelope(newKW.Scheme(), wrapped, payload), nil
+}
+
+// IsEnvelope reports whether data starts with EnvelopeMagic.
+func IsEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(EnvelopeMagic))
+}
+
+// ParseEnvelopeHeader returns the header of an envelope.
+func ParseEnvelopeHeader(data []byte) (EnvelopeHeader, error) {
+	hdr, _, err := parseEnvelope(data)
+	return hdr, err
+}
+
+func unwrapFor(hdr EnvelopeHeader, kw KeyWrapper) ([]byte, error) {
+	if hdr.Scheme != kw.Scheme() {
+		return nil, fmt.Errorf("cryptutil: envelope key is wrapped with %s, not %s", hdr.Scheme, kw.Scheme())
+	}
+
+	dek, err := kw.UnwrapKey(hdr.WrappedKey)
+	if err != nil || len(dek) != KeySize {
+		return nil, ErrUnwrap
+	}
+
+	return dek, nil
+}
+
+func buildEnvelope(scheme string, wrapped, payload []byte) []byte {
+	out := make([]byte, 0, len(EnvelopeMagic)+2+len(scheme)+4+len(wrapped)+len(payload))
+	out = append(out, EnvelopeMagic...)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(scheme)))
+	out = append(out, scheme...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(wrapped)))
+	out = append(out, wrapped...)
+
+	return append(out, payload...)
+}
+
+func parseEnvelope(data []byte) (EnvelopeHeader, []byte, error) {
+	if !IsEnvelope(data) {
+		return EnvelopeHeader{}, nil, ErrNotEnvelope
+	}
+
+	rest := data[len(EnvelopeMagic):]
+
+	if len(rest) < 2 {
+		return EnvelopeHeader{}, nil, fmt.Errorf("%w: truncated header", ErrNotEnvelope)
+	}
+
+	n := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+
+	if len(rest) < n+4 {
+		return EnvelopeHeader{}, nil, fmt.Errorf("%w: truncated header", ErrNotEnvelope)
+	}
+
+	scheme := string(rest[:n])
+	rest = rest[n:]
+
+	m := int(binary.BigEndian.Uint32(rest))
+	rest = rest[4:]
+
+	if m > len(rest) || len(rest)-m < NonceSize+16 {
+		return EnvelopeHeader{}, nil, fmt.Errorf("%w: truncated payload", ErrNotEnvelope)
+	}
+
+	hdr := EnvelopeHeader{
+		Scheme:      scheme,
+		WrappedKey:  rest[:m],
+		PayloadSize: len(rest) - m,
+	}
+
+	return hdr, rest[m:], nil
+}
+
+// sealGCM returns nonce + AES-256-GCM ciphertext.
+func sealGCM(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cryptutil: failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// openGCM reverses sealGCM.
+func openGCM(key, data, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < NonceSize {
+		return nil, fmt.Errorf("cryptutil: input too short")
+	}
+
+	return gcm.Open(nil, data[:NonceSize], data[NonceSize:], aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cryptutil: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// maxWrapIter bounds the PBKDF2 cost accepted when unwrapping.
+const maxWrapIter = 10_000_000
+
+// passphraseWrapper wraps keys with a PBKDF2-derived key encryption key.
+type passphraseWrapper struct {
+	passphrase string
+	iterations int
+}
+
+// NewPassphraseWrapper returns a KeyWrapper deriving the master key from a
+// passphrase with PBKDF2-HMAC-SHA256. Unlike Encrypt, the salt and the
+// iteration count are stored with the wrapped key, so the cost can be raised
+// later without breaking existing envelopes. Counts below MinIter are clamped.
+func NewPassphraseWrapper(passphrase string, iterations int) KeyWrapper {
+	return passphraseWrapper{passphrase: passphrase, iterations: min(max(iterations, MinIter), maxWrapIter)}
+}
+
+func (passphraseWrapper) Scheme() string { return "pbkdf2-sha256+aes256gcm" }
+
+// WrapKey returns iterations (u32) | salt | nonce | sealed dek.
+func (p passphraseWrapper) WrapKey(dek []byte) ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	kek := DeriveKey(p.passphrase, salt, p.iterations, KeySize)
+	defer clear(kek)
+
+	sealed, err := sealGCM(kek, dek, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := binary.BigEndian.AppendUint32(nil, uint32(p.iterations))
+	out = append(out, salt...)
+
+	return append(out, sealed...), nil
+}
+
+func (p passphraseWrapper) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 4+SaltSize+NonceSize {
+		return nil, ErrUnwrap
+	}
+
+	// The count comes from the file: bound it so a crafted header cannot
+	// make unwrapping run for hours
+	iterations := int(binary.BigEndian.Uint32(wrapped))
+	if iterations > maxWrapIter {
+		return nil, ErrUnwrap
+	}
+
+	salt := wrapped[4 : 4+SaltSize]
+
+	kek := DeriveKey(p.passphrase, salt, iterations, KeySize)
+	defer clear(kek)
+
+	return openGCM(kek, wrapped[4+SaltSize:], nil)
+}
+
+// rsaWrapper wraps keys with RSA-OAEP (SHA-256).
+type rsaWrapper struct {
+	pub  *rsa.PublicKey
+	priv *rsa.PrivateKey
+}
+
+// NewRSAWrapper returns a KeyWrapper using RSA-OAEP with SHA-256. Sealing
+// and rotating to this key only need pub; opening needs priv. When priv is
+// set, pub may be nil.
+func NewRSAWrapper(pub *rsa.PublicKey, priv *rsa.PrivateKey) KeyWrapper {
+	if pub == nil && priv != nil {
+		pub = &priv.PublicKey
+	}
+
+	return rsaWrapper{pub: pub, priv: priv}
+}
+
+func (rsaWrapper) Scheme() string { return "rsa-oaep-sha256" }
+
+func (r rsaWrapper) WrapKey(dek []byte) ([]byte, error) {
+	if r.pub == nil {
+		return nil, errors.New("no RSA public key")
+	}
+
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, r.pub, dek, []byte(EnvelopeMagic))
+}
+
+func (r rsaWrapper) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if r.priv == nil {
+		return nil, errors.New("no RSA private key")
+	}
+
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, r.priv, wrapped, []byte(EnvelopeMagic))
+}
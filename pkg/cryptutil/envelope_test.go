@@ -0,0 +1,106 @@
+package cryptutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrappers := map[string]KeyWrapper{
+		"passphrase": NewPassphraseWrapper("correct horse", 0),
+		"rsa":        NewRSAWrapper(nil, key),
+	}
+
+	plaintext := []byte("artifact contents")
+
+	for name, kw := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			sealed, err := SealEnvelope(plaintext, kw)
+			if err != nil {
+				t.Fatalf("SealEnvelope() error = %v", err)
+			}
+
+			if !IsEnvelope(sealed) {
+				t.Error("IsEnvelope() = false for sealed data")
+			}
+
+			hdr, err := ParseEnvelopeHeader(sealed)
+			if err != nil || hdr.Scheme != kw.Scheme() {
+				t.Errorf("ParseEnvelopeHeader() = %+v, %v", hdr, err)
+			}
+
+			got, err := OpenEnvelope(sealed, kw)
+			if err != nil || !bytes.Equal(got, plaintext) {
+				t.Errorf("OpenEnvelope() = %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestEnvelopeWrongKey(t *testing.T) {
+	sealed, err := SealEnvelope([]byte("x"), NewPassphraseWrapper("right", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenEnvelope(sealed, NewPassphraseWrapper("wrong", 0)); !errors.Is(err, ErrUnwrap) {
+		t.Errorf("OpenEnvelope(wrong) error = %v, want ErrUnwrap", err)
+	}
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := OpenEnvelope(sealed, NewRSAWrapper(nil, key)); err == nil {
+		t.Error("OpenEnvelope() with a different scheme should fail")
+	}
+
+	if _, err := OpenEnvelope([]byte("plain data"), NewPassphraseWrapper("right", 0)); !errors.Is(err, ErrNotEnvelope) {
+		t.Errorf("OpenEnvelope(plain) error = %v, want ErrNotEnvelope", err)
+	}
+
+	// Tampering with the payload must be detected
+	sealed[len(sealed)-1] ^= 1
+	if _, err := OpenEnvelope(sealed, NewPassphraseWrapper("right", 0)); err == nil {
+		t.Error("OpenEnvelope() accepted a tampered payload")
+	}
+}
+
+func TestRotateMasterKey(t *testing.T) {
+	oldKW := NewPassphraseWrapper("old", 0)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := SealEnvelope([]byte("payload"), oldKW)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotating to a public key only needs the public half
+	rotated, err := RotateMasterKey(sealed, oldKW, NewRSAWrapper(&key.PublicKey, nil))
+	if err != nil {
+		t.Fatalf("RotateMasterKey() error = %v", err)
+	}
+
+	// The payload (nonce + ciphertext) is not re-encrypted
+	if !bytes.HasSuffix(rotated, sealed[len(sealed)-(NonceSize+16+len("payload")):]) {
+		t.Error("RotateMasterKey() changed the payload")
+	}
+
+	if _, err := OpenEnvelope(rotated, oldKW); err == nil {
+		t.Error("old master key still opens the rotated envelope")
+	}
+
+	got, err := OpenEnvelope(rotated, NewRSAWrapper(nil, key))
+	if err != nil || string(got) != "payload" {
+		t.Errorf("OpenEnvelope(rotated) = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,158 @@
+package cryptutil
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// MaxShares is the largest number of shares Split can produce; share
+// x-coordinates are the non-zero elements of GF(2^8).
+const MaxShares = 255
+
+// ErrInvalidShares is returned by Combine for malformed or inconsistent
+// shares.
+var ErrInvalidShares = errors.New("cryptutil: invalid shares")
+
+// Split divides secret into n shares using Shamir's Secret Sharing over
+// GF(2^8), so that any k of them recover the secret and fewer than k reveal
+// nothing about it. Each share is one x-coordinate byte followed by
+// len(secret) bytes of polynomial evaluations.
+func Split(secret []byte, n, k int) ([][]byte, error) {
+	switch {
+	case len(secret) == 0:
+		return nil, fmt.Errorf("cryptutil: empty secret")
+	case k < 2:
+		return nil, fmt.Errorf("cryptutil: threshold must be at least 2, got %d", k)
+	case n < k:
+		return nil, fmt.Errorf("cryptutil: shares (%d) must be at least the threshold (%d)", n, k)
+	case n > MaxShares:
+		return nil, fmt.Errorf("cryptutil: at most %d shares, got %d", MaxShares, n)
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+
+	// One random polynomial of degree k-1 per secret byte; coeffs[0] is the
+	// secret byte itself.
+	coeffs := make([]byte, k)
+	defer clear(coeffs)
+
+	for j, b := range secret {
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, fmt.Errorf("cryptutil: failed to generate coefficients: %w", err)
+		}
+
+		coeffs[0] = b
+
+		for i := range shares {
+			shares[i][j+1] = gfEval(coeffs, shares[i][0])
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine recovers the secret from at least threshold shares produced by
+// Split. Shares from different splits, or fewer than the threshold, cannot be
+// detected and yield a wrong secret.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("%w: need at least 2 shares, got %d", ErrInvalidShares, len(shares))
+	}
+
+	size := len(shares[0])
+	if size < 2 {
+		return nil, fmt.Errorf("%w: share too short", ErrInvalidShares)
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]bool, len(shares))
+
+	for i, s := range shares {
+		if len(s) != size {
+			return nil, fmt.Errorf("%w: shares have different lengths", ErrInvalidShares)
+		}
+
+		if s[0] == 0 {
+			return nil, fmt.Errorf("%w: share %d has x-coordinate 0", ErrInvalidShares, i+1)
+		}
+
+		if seen[s[0]] {
+			return nil, fmt.Errorf("%w: duplicate share %d", ErrInvalidShares, s[0])
+		}
+
+		seen[s[0]] = true
+		xs[i] = s[0]
+	}
+
+	// Lagrange basis polynomials evaluated at x = 0.
+	basis := make([]byte, len(shares))
+	for i, xi := range xs {
+		num, den := byte(1), byte(1)
+
+		for j, xj := range xs {
+			if i == j {
+				continue
+			}
+
+			num = gfMul(num, xj)
+			den = gfMul(den, xi^xj)
+		}
+
+		basis[i] = gfMul(num, gfInv(den))
+	}
+
+	secret := make([]byte, size-1)
+	for j := range secret {
+		var v byte
+		for i, s := range shares {
+			v ^= gfMul(s[j+1], basis[i])
+		}
+
+		secret[j] = v
+	}
+
+	return secret, nil
+}
+
+// gfEval evaluates the polynomial with the given coefficients at x using
+// Horner's rule.
+func gfEval(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coeffs[i]
+	}
+
+	return y
+}
+
+// gfMul multiplies in GF(2^8) with the AES polynomial x^8+x^4+x^3+x+1.
+// It runs in constant time so share values do not leak through timing.
+func gfMul(a, b byte) byte {
+	var p byte
+	for range 8 {
+		p ^= byte(subtle.ConstantTimeByteEq(b&1, 1)) * a
+		carry := a >> 7
+		a <<= 1
+		a ^= 0x1b * carry
+		b >>= 1
+	}
+
+	return p
+}
+
+// gfInv returns the multiplicative inverse of a non-zero a as a^254.
+func gfInv(a byte) byte {
+	r := a
+	for range 6 {
+		a = gfMul(a, a)
+		r = gfMul(r, a)
+	}
+
+	return gfMul(r, r)
+}
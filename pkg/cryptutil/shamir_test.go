@@ -0,0 +1,101 @@
+package cryptutil
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("master key material \x00\xff")
+
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+
+	if len(shares) != 5 {
+		t.Fatalf("Split() returned %d shares, want 5", len(shares))
+	}
+
+	subsets := [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}}
+	for _, idx := range subsets {
+		var picked [][]byte
+		for _, i := range idx {
+			picked = append(picked, shares[i])
+		}
+
+		got, err := Combine(picked)
+		if err != nil {
+			t.Fatalf("Combine(%v) error = %v", idx, err)
+		}
+
+		if !bytes.Equal(got, secret) {
+			t.Errorf("Combine(%v) = %q, want %q", idx, got, secret)
+		}
+	}
+
+	// Below the threshold the result must not be the secret.
+	got, err := Combine(shares[:2])
+	if err != nil {
+		t.Fatalf("Combine(2 shares) error = %v", err)
+	}
+
+	if bytes.Equal(got, secret) {
+		t.Error("Combine() with fewer than threshold shares recovered the secret")
+	}
+}
+
+func TestSplitInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret []byte
+		n, k   int
+	}{
+		{"empty secret", nil, 3, 2},
+		{"threshold 1", []byte("s"), 3, 1},
+		{"shares below threshold", []byte("s"), 2, 3},
+		{"too many shares", []byte("s"), 256, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Split(tt.secret, tt.n, tt.k); err == nil {
+				t.Error("Split() expected error")
+			}
+		})
+	}
+}
+
+func TestCombineInvalid(t *testing.T) {
+	shares, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		shares [][]byte
+	}{
+		{"single share", shares[:1]},
+		{"duplicate", [][]byte{shares[0], shares[0]}},
+		{"length mismatch", [][]byte{shares[0], shares[1][:3]}},
+		{"zero x", [][]byte{shares[0], append([]byte{0}, shares[1][1:]...)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Combine(tt.shares); !errors.Is(err, ErrInvalidShares) {
+				t.Errorf("Combine() error = %v, want ErrInvalidShares", err)
+			}
+		})
+	}
+}
+
+func TestGFInverse(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if got := gfMul(byte(a), gfInv(byte(a))); got != 1 {
+			t.Fatalf("%d * inv(%d) = %d, want 1", a, a, got)
+		}
+	}
+}
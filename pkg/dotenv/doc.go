@@ -0,0 +1,5 @@
+// Package dotenv parses .env files into ordered key/value pairs. It
+// understands comments, an optional "export" prefix, single and double
+// quoted values with escape sequences, inline comments, and optional
+// $VAR / ${VAR} expansion against earlier entries and a lookup function.
+package dotenv
@@ -0,0 +1,156 @@
+package dotenv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInvalidLine is returned by ParseLine for lines that are not KEY=VALUE.
+var ErrInvalidLine = errors.New("dotenv: invalid line")
+
+// Var is a single parsed variable.
+type Var struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Options configures parsing.
+type Options struct {
+	// Expand expands $VAR and ${VAR} in values, resolving earlier entries of
+	// the same file first and then Lookup.
+	Expand bool
+	// Lookup resolves variables not defined in the file. Defaults to
+	// os.LookupEnv.
+	Lookup func(key string) (string, bool)
+}
+
+// Parse parses .env content from r. Malformed lines are skipped.
+func Parse(r io.Reader, opts Options) ([]Var, error) {
+	var vars []Var
+
+	seen := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := ParseLine(line)
+		if err != nil {
+			continue
+		}
+
+		if opts.Expand {
+			value = expand(value, seen, opts.Lookup)
+		}
+
+		seen[key] = value
+		vars = append(vars, Var{Key: key, Value: value})
+	}
+
+	return vars, scanner.Err()
+}
+
+// ParseFile parses the .env file at path.
+func ParseFile(path string, opts Options) ([]Var, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return Parse(f, opts)
+}
+
+// ParseLine parses a single KEY=VALUE line, with an optional "export" prefix.
+func ParseLine(line string) (string, string, error) {
+	line = strings.TrimSpace(line)
+	if after, ok := strings.CutPrefix(line, "export "); ok {
+		line = strings.TrimSpace(after)
+	}
+
+	before, after, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", fmt.Errorf("%w: no '=' found", ErrInvalidLine)
+	}
+
+	key := strings.TrimSpace(before)
+	if key == "" {
+		return "", "", fmt.Errorf("%w: empty key", ErrInvalidLine)
+	}
+
+	return key, ParseValue(after), nil
+}
+
+// ParseValue unquotes a raw value. Double-quoted values have \n, \r, \t,
+// \" and \\ unescaped; unquoted values lose any trailing " # comment".
+func ParseValue(value string) string {
+	value = strings.TrimSpace(value)
+
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') ||
+			(value[0] == '\'' && value[len(value)-1] == '\'') {
+			quote := value[0]
+			value = value[1 : len(value)-1]
+
+			if quote == '"' {
+				value = unescapeDoubleQuoted(value)
+			}
+
+			return value
+		}
+	}
+
+	if idx := strings.Index(value, " #"); idx != -1 {
+		value = strings.TrimSpace(value[:idx])
+	}
+
+	return value
+}
+
+// ToMap converts vars to a map; later entries win.
+func ToMap(vars []Var) map[string]string {
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		m[v.Key] = v.Value
+	}
+
+	return m
+}
+
+func unescapeDoubleQuoted(s string) string {
+	replacer := strings.NewReplacer(
+		`\\`, `\`,
+		`\"`, `"`,
+		`\n`, "\n",
+		`\r`, "\r",
+		`\t`, "\t",
+	)
+
+	return replacer.Replace(s)
+}
+
+func expand(value string, seen map[string]string, lookup func(string) (string, bool)) string {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	return os.Expand(value, func(key string) string {
+		if v, ok := seen[key]; ok {
+			return v
+		}
+
+		v, _ := lookup(key)
+
+		return v
+	})
+}
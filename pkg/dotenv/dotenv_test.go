@@ -0,0 +1,120 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# comment
+export A=1
+B = "two words"
+C='single $A'
+D="line1\nline2"
+E=value # inline
+NOEQUALS
+=nokey
+F=${A}-$B
+`
+
+	vars, err := Parse(strings.NewReader(input), Options{
+		Expand: true,
+		Lookup: func(string) (string, bool) { return "", false },
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Var{
+		{"A", "1"},
+		{"B", "two words"},
+		{"C", "single 1"},
+		{"D", "line1\nline2"},
+		{"E", "value"},
+		{"F", "1-two words"},
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("Parse() = %#v, want %#v", vars, want)
+	}
+}
+
+func TestParseLookup(t *testing.T) {
+	lookup := func(k string) (string, bool) {
+		if k == "HOME" {
+			return "/home/x", true
+		}
+
+		return "", false
+	}
+
+	vars, _ := Parse(strings.NewReader("P=$HOME/bin\nQ=$MISSING\n"), Options{Expand: true, Lookup: lookup})
+	if vars[0].Value != "/home/x/bin" || vars[1].Value != "" {
+		t.Errorf("Parse() = %v", vars)
+	}
+
+	vars, _ = Parse(strings.NewReader("P=$HOME\n"), Options{Lookup: lookup})
+	if vars[0].Value != "$HOME" {
+		t.Errorf("Expand disabled should keep $HOME, got %q", vars[0].Value)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{"KEY=value", "KEY", "value", false},
+		{"  export KEY = 'v' ", "KEY", "v", false},
+		{"KEY=", "KEY", "", false},
+		{"KEY=a=b", "KEY", "a=b", false},
+		{"NOEQUALS", "", "", true},
+		{"=value", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			key, value, err := ParseLine(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidLine) {
+					t.Errorf("error %v is not ErrInvalidLine", err)
+				}
+
+				return
+			}
+
+			if key != tt.wantKey || value != tt.wantValue {
+				t.Errorf("ParseLine() = %q, %q; want %q, %q", key, value, tt.wantKey, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("A=1\nA=2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := ParseFile(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m := ToMap(vars); m["A"] != "2" {
+		t.Errorf("ToMap() = %v, later entry should win", m)
+	}
+
+	if _, err := ParseFile(filepath.Join(t.TempDir(), "missing"), Options{}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
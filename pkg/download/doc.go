@@ -0,0 +1,10 @@
+// Package download fetches a single file over HTTP(S) to disk with the
+// behaviour expected of a download tool rather than a plain GET: the body
+// streams into "<dest>.part" and is renamed into place only once complete
+// and verified, an interrupted transfer resumes with a Range request,
+// transient failures are retried with exponential backoff, alternative
+// mirror URLs are tried in order, and the transfer rate can be capped.
+//
+// ExpandTemplate derives an output file name from a URL using {name},
+// {stem}, {ext}, {host} and {date} placeholders.
+package download
@@ -0,0 +1,470 @@
+package download
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+// PartSuffix is appended to the destination path while a download is in
+// progress.
+const PartSuffix = ".part"
+
+const (
+	defaultBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+	bufSize        = 32 * 1024
+	userAgent      = "omni-dl/1.0"
+)
+
+// ErrInvalidInput is wrapped by errors for requests that no attempt could
+// satisfy: no URL, an unsupported URL, or a malformed checksum.
+var ErrInvalidInput = errors.New("download: invalid input")
+
+// StatusError reports an HTTP response that is neither a full nor a
+// partial body.
+type StatusError struct {
+	URL    string
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("download: %s: %s", e.URL, e.Status)
+}
+
+// Temporary reports whether retrying the same URL may succeed: server
+// errors, 408 Request Timeout and 429 Too Many Requests.
+func (e *StatusError) Temporary() bool {
+	return e.Code >= 500 || e.Code == http.StatusRequestTimeout || e.Code == http.StatusTooManyRequests
+}
+
+// Options configures a download. The zero value downloads once from each
+// URL in turn without resuming, verification or rate limiting.
+type Options struct {
+	Client    *http.Client       // nil uses a client without an overall timeout
+	Header    http.Header        // extra request headers
+	Retries   int                // extra attempts per URL after a transient failure
+	Backoff   time.Duration      // delay before the first retry, doubled each time (default 1s)
+	RateLimit int64              // maximum bytes per second, 0 for unlimited
+	Checksum  string             // expected digest, hex or "algo:hex"
+	Algorithm hashutil.Algorithm // digest algorithm (default sha256, or the Checksum prefix)
+	Resume    bool               // continue from an existing <dest>.part
+	Progress  func(done, total int64)
+	Log       func(format string, args ...any) // retry and mirror notices
+}
+
+// Result describes a completed download.
+type Result struct {
+	URL      string `json:"url"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Resumed  int64  `json:"resumed,omitempty"` // bytes already on disk when the transfer started
+	Attempts int    `json:"attempts"`
+	Digest   string `json:"digest"`
+	Verified bool   `json:"verified"`
+}
+
+// File downloads the first URL to dest, falling back to each following
+// URL (mirrors of the same file) when one fails permanently or exhausts
+// its retries. Partial data is kept across attempts and mirrors, so a
+// mirror resumes where the previous one stopped; a checksum mismatch
+// discards it. dest is replaced only after a complete, verified transfer.
+func File(ctx context.Context, urls []string, dest string, opts Options) (*Result, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("%w: no URL", ErrInvalidInput)
+	}
+
+	for _, u := range urls {
+		if err := checkURL(u); err != nil {
+			return nil, err
+		}
+	}
+
+	algo, expected, err := expectedDigest(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	part := dest + PartSuffix
+	if !opts.Resume {
+		if err := os.Remove(part); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("download: %w", err)
+		}
+	}
+
+	res := &Result{Path: dest}
+	if info, err := os.Stat(part); err == nil {
+		res.Resumed = info.Size()
+	}
+
+	var lastErr error
+
+	for i, u := range urls {
+		if i > 0 {
+			opts.logf("%v; trying mirror %s", lastErr, u)
+		}
+
+		res.URL = u
+
+		lastErr = fetchWithRetries(ctx, u, part, opts, res)
+		if lastErr == nil {
+			lastErr = verify(part, algo, expected, res)
+			if lastErr == nil {
+				break
+			}
+
+			// Corrupt data must not seed the next mirror
+			_ = os.Remove(part)
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	if lastErr != nil {
+		if len(urls) > 1 {
+			return nil, fmt.Errorf("download: all %d sources failed: %w", len(urls), lastErr)
+		}
+
+		return nil, lastErr
+	}
+
+	info, err := os.Stat(part)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+
+	res.Size = info.Size()
+
+	if err := os.Rename(part, dest); err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+
+	return res, nil
+}
+
+// checkURL rejects URLs that no attempt could fetch, so they fail at once
+// instead of being retried.
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %s: unsupported URL scheme %q", ErrInvalidInput, raw, u.Scheme)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("%w: %s: missing host", ErrInvalidInput, raw)
+	}
+
+	return nil
+}
+
+// expectedDigest resolves the digest algorithm and the normalized expected
+// digest ("" when no checksum is requested).
+func expectedDigest(opts Options) (hashutil.Algorithm, string, error) {
+	algo := opts.Algorithm
+	if algo == "" {
+		algo = hashutil.SHA256
+
+		if prefix, _, ok := strings.Cut(opts.Checksum, ":"); ok {
+			algo = hashutil.Algorithm(strings.ToLower(strings.TrimSpace(prefix)))
+		}
+	}
+
+	if !knownAlgorithm(algo) {
+		return "", "", fmt.Errorf("%w: unsupported checksum algorithm %q", ErrInvalidInput, algo)
+	}
+
+	if opts.Checksum == "" {
+		return algo, "", nil
+	}
+
+	want, err := hashutil.NormalizeDigest(opts.Checksum, algo)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	return algo, want, nil
+}
+
+func knownAlgorithm(algo hashutil.Algorithm) bool {
+	for _, a := range hashutil.Algorithms() {
+		if a == algo {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verify hashes the completed part file, recording the digest in res and
+// comparing it with expected when set.
+func verify(part string, algo hashutil.Algorithm, expected string, res *Result) error {
+	got, err := hashutil.HashFile(part, algo)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+
+	res.Digest = string(algo) + ":" + got
+
+	if expected == "" {
+		return nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(got), []byte(expected)) != 1 {
+		return fmt.Errorf("download: %s: %w: expected %s %s, got %s", res.URL, hashutil.ErrMismatch, algo, expected, got)
+	}
+
+	res.Verified = true
+
+	return nil
+}
+
+// fetchWithRetries runs attempts against u until one completes, a
+// permanent error occurs, or the retries are used up.
+func fetchWithRetries(ctx context.Context, u, part string, opts Options, res *Result) error {
+	delay := opts.Backoff
+	if delay <= 0 {
+		delay = defaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		res.Attempts++
+
+		err := fetch(ctx, u, part, opts)
+		if err == nil || !retryable(ctx, err) || attempt >= opts.Retries {
+			return err
+		}
+
+		opts.logf("%v; retrying in %s (%d/%d)", err, delay, attempt+1, opts.Retries)
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+
+		delay = min(delay*2, maxBackoff)
+	}
+}
+
+// retryable reports whether err is worth another attempt at the same URL:
+// transient HTTP statuses and transport failures, but not cancellation or
+// local file errors.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Temporary()
+	}
+
+	var pathErr *os.PathError
+
+	return !errors.As(err, &pathErr)
+}
+
+// fetch makes one request for u, appending to part when it already holds
+// data and the server honours the Range request.
+func fetch(ctx context.Context, u, part string, opts Options) error {
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+
+	for k, v := range opts.Header {
+		req.Header[k] = v
+	}
+
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	flags := os.O_WRONLY | os.O_CREATE
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Full body: the server ignored or does not support Range
+		offset = 0
+		flags |= os.O_TRUNC
+	case http.StatusPartialContent:
+		start, _, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			_ = os.Remove(part)
+			return fmt.Errorf("download: %s: unexpected Content-Range %q", u, resp.Header.Get("Content-Range"))
+		}
+
+		flags |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The part file already holds the whole resource
+		if _, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && total == offset {
+			return nil
+		}
+
+		_ = os.Remove(part)
+
+		return &StatusError{URL: u, Code: resp.StatusCode, Status: resp.Status + " (restarting)"}
+	default:
+		return &StatusError{URL: u, Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+
+	f, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+
+	n, err := copyBody(ctx, f, resp.Body, offset, total, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if total >= 0 && offset+n != total {
+		return fmt.Errorf("download: %s: %w after %d of %d bytes", u, io.ErrUnexpectedEOF, offset+n, total)
+	}
+
+	return nil
+}
+
+// copyBody streams body into f under the rate limit, reporting progress.
+// Write failures come back as *os.PathError so they are not retried.
+func copyBody(ctx context.Context, f *os.File, body io.Reader, offset, total int64, opts Options) (int64, error) {
+	size := bufSize
+	if opts.RateLimit > 0 && opts.RateLimit < int64(size) {
+		size = int(opts.RateLimit)
+	}
+
+	buf := make([]byte, size)
+	limit := newLimiter(opts.RateLimit)
+
+	var written int64
+
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, werr := f.Write(buf[:n]); werr != nil {
+				return written, werr
+			}
+
+			written += int64(n)
+
+			if opts.Progress != nil {
+				opts.Progress(offset+written, total)
+			}
+
+			if lerr := limit.wait(ctx, n); lerr != nil {
+				return written, lerr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return written, nil
+		}
+
+		if err != nil {
+			return written, fmt.Errorf("download: %w", err)
+		}
+	}
+}
+
+// parseContentRange parses "bytes START-END/TOTAL" or "bytes */TOTAL".
+// start is -1 for the unsatisfied form and total is -1 when unknown.
+func parseContentRange(v string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(v), "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	total = -1
+	if size != "*" {
+		t, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+
+		total = t
+	}
+
+	if rng == "*" {
+		return -1, total, true
+	}
+
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, total, true
+}
+
+func (o Options) logf(format string, args ...any) {
+	if o.Log != nil {
+		o.Log(format, args...)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
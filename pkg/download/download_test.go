@@ -0,0 +1,350 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/pkg/hashutil"
+)
+
+var payload = bytes.Repeat([]byte("0123456789abcdef"), 4096)
+
+func payloadDigest() string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func serveFile(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(payload))
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(serveFile))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	res, err := File(context.Background(), []string{srv.URL + "/file.bin"}, dest, Options{Checksum: payloadDigest()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(readFile(t, dest), payload) {
+		t.Error("content mismatch")
+	}
+
+	if res.Size != int64(len(payload)) || !res.Verified || res.Attempts != 1 {
+		t.Errorf("result = %+v", res)
+	}
+
+	if res.Digest != "sha256:"+payloadDigest() {
+		t.Errorf("digest = %s", res.Digest)
+	}
+
+	if _, err := os.Stat(dest + PartSuffix); !os.IsNotExist(err) {
+		t.Error("part file left behind")
+	}
+}
+
+func TestFileResume(t *testing.T) {
+	var ranges []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		serveFile(w, r)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(dest+PartSuffix, payload[:1000], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := File(context.Background(), []string{srv.URL}, dest, Options{Resume: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Resumed != 1000 {
+		t.Errorf("Resumed = %d, want 1000", res.Resumed)
+	}
+
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("ranges = %q", ranges)
+	}
+
+	if !bytes.Equal(readFile(t, dest), payload) {
+		t.Error("content mismatch after resume")
+	}
+}
+
+func TestFileNoResumeDiscardsPart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(serveFile))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(dest+PartSuffix, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := File(context.Background(), []string{srv.URL}, dest, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(readFile(t, dest), payload) {
+		t.Error("stale part data was kept")
+	}
+}
+
+func TestFileCompletePart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(serveFile))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(dest+PartSuffix, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := File(context.Background(), []string{srv.URL}, dest, Options{Resume: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(readFile(t, dest), payload) {
+		t.Error("content mismatch")
+	}
+}
+
+func TestFileRetriesTruncatedBody(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Promise the whole body but stop half way
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write(payload[:len(payload)/2])
+
+			return
+		}
+
+		serveFile(w, r)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	res, err := File(context.Background(), []string{srv.URL}, dest, Options{Retries: 2, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", res.Attempts)
+	}
+
+	if !bytes.Equal(readFile(t, dest), payload) {
+		t.Error("content mismatch after retry")
+	}
+}
+
+func TestFileRetryStatus(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+
+		serveFile(w, r)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	_, err := File(context.Background(), []string{srv.URL}, dest, Options{Retries: 1, Backoff: time.Millisecond})
+
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want 503 StatusError", err)
+	}
+
+	calls.Store(0)
+
+	res, err := File(context.Background(), []string{srv.URL}, dest, Options{Retries: 2, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", res.Attempts)
+	}
+}
+
+func TestFileMirrorFallback(t *testing.T) {
+	var missingCalls atomic.Int32
+
+	missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		missingCalls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer missing.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(serveFile))
+	defer good.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	var logged []string
+
+	opts := Options{
+		Retries: 3,
+		Backoff: time.Millisecond,
+		Log:     func(format string, args ...any) { logged = append(logged, format) },
+	}
+
+	res, err := File(context.Background(), []string{missing.URL, good.URL}, dest, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.URL != good.URL {
+		t.Errorf("URL = %s, want mirror %s", res.URL, good.URL)
+	}
+
+	// 404 is permanent: no retries against the first URL
+	if missingCalls.Load() != 1 {
+		t.Errorf("404 URL requested %d times, want 1", missingCalls.Load())
+	}
+
+	if len(logged) != 1 {
+		t.Errorf("log = %q", logged)
+	}
+}
+
+func TestFileChecksumMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(serveFile))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	wrong := hashutil.HashString("other", hashutil.SHA256)
+
+	_, err := File(context.Background(), []string{srv.URL}, dest, Options{Checksum: "sha256:" + wrong})
+	if !errors.Is(err, hashutil.ErrMismatch) {
+		t.Fatalf("err = %v, want ErrMismatch", err)
+	}
+
+	for _, p := range []string{dest, dest + PartSuffix} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s exists after a mismatch", p)
+		}
+	}
+}
+
+func TestFileChecksumAlgorithmPrefix(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(serveFile))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	md5 := hashutil.HashBytes(payload, hashutil.MD5)
+
+	res, err := File(context.Background(), []string{srv.URL}, dest, Options{Checksum: "MD5:" + md5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Verified || res.Digest != "md5:"+md5 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestFileInvalidInput(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	tests := []struct {
+		name string
+		urls []string
+		opts Options
+	}{
+		{"no URL", nil, Options{}},
+		{"scheme", []string{"ftp://example.com/f"}, Options{}},
+		{"no host", []string{"http:///f"}, Options{}},
+		{"bad digest", []string{"http://example.com/f"}, Options{Checksum: "xyz"}},
+		{"bad algorithm", []string{"http://example.com/f"}, Options{Checksum: "rot13:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := File(context.Background(), tt.urls, dest, tt.opts); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("err = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		in           string
+		start, total int64
+		ok           bool
+	}{
+		{"bytes 100-199/1000", 100, 1000, true},
+		{"bytes 0-9/*", 0, -1, true},
+		{"bytes */1000", -1, 1000, true},
+		{"items 0-9/10", 0, 0, false},
+		{"bytes 0-9", 0, 0, false},
+		{"bytes x-9/10", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.in)
+		if start != tt.start || total != tt.total || ok != tt.ok {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v", tt.in, start, total, ok)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	if err := newLimiter(0).wait(context.Background(), 1<<20); err != nil {
+		t.Fatal(err)
+	}
+
+	l := newLimiter(10_000)
+	start := time.Now()
+
+	if err := l.wait(context.Background(), 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("1000 bytes at 10000 B/s took %s, want about 100ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.wait(ctx, 1_000_000); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,41 @@
+package download
+
+import (
+	"context"
+	"time"
+)
+
+// limiter paces a transfer to an average of rate bytes per second measured
+// from its start, sleeping whenever the transfer gets ahead of schedule.
+type limiter struct {
+	rate  int64
+	start time.Time
+	n     int64
+}
+
+// newLimiter returns a limiter for rate bytes per second, or nil (no
+// limit) when rate is not positive.
+func newLimiter(rate int64) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+
+	return &limiter{rate: rate, start: time.Now()}
+}
+
+// wait accounts for n transferred bytes and blocks until the average rate
+// is back under the limit.
+func (l *limiter) wait(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.n += int64(n)
+
+	due := time.Duration(float64(l.n) / float64(l.rate) * float64(time.Second))
+	if ahead := due - time.Since(l.start); ahead > 0 {
+		return sleep(ctx, ahead)
+	}
+
+	return nil
+}
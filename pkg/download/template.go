@@ -0,0 +1,64 @@
+package download
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultName is used for {name} when the URL path has no file name.
+const DefaultName = "index.html"
+
+// ExpandTemplate builds an output path from tmpl by replacing:
+//
+//	{name}  file name from the URL path (DefaultName when empty)
+//	{stem}  {name} without its extension
+//	{ext}   extension of {name}, without the dot
+//	{host}  URL host name
+//	{date}  now as YYYY-MM-DD
+//
+// Path separators and ".." in substituted values are neutralized, so a
+// URL cannot steer the output outside the directory the template names.
+func ExpandTemplate(tmpl, rawURL string, now time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	name := path.Base(u.Path)
+	if name == "." || name == ".." || name == "/" || name == "" {
+		name = DefaultName
+	}
+
+	name = sanitize(name)
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	stem := strings.TrimSuffix(name, path.Ext(name))
+
+	r := strings.NewReplacer(
+		"{name}", name,
+		"{stem}", stem,
+		"{ext}", ext,
+		"{host}", sanitize(u.Hostname()),
+		"{date}", now.Format(time.DateOnly),
+	)
+
+	return r.Replace(tmpl), nil
+}
+
+// sanitize makes a URL-derived value safe to use as one path element.
+func sanitize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+
+		return r
+	}, s)
+
+	if s == "." || s == ".." {
+		return "_"
+	}
+
+	return s
+}
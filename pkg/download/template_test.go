@@ -0,0 +1,33 @@
+package download
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		tmpl, url, want string
+	}{
+		{"{name}", "https://example.com/dist/tool-1.2.tar.gz", "tool-1.2.tar.gz"},
+		{"{stem}-{date}.{ext}", "https://example.com/a/report.pdf?x=1", "report-2026-10-18.pdf"},
+		{"downloads/{host}/{name}", "http://mirror.example.org:8080/f.iso", "downloads/mirror.example.org/f.iso"},
+		{"{name}", "https://example.com/", DefaultName},
+		{"{name}", "https://example.com", DefaultName},
+		{"out/{name}", "https://example.com/a%2F..%2Fb", "out/b"},
+		{"{name}", "https://example.com/..", DefaultName},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandTemplate(tt.tmpl, tt.url, now)
+		if err != nil {
+			t.Fatalf("ExpandTemplate(%q, %q): %v", tt.tmpl, tt.url, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("ExpandTemplate(%q, %q) = %q, want %q", tt.tmpl, tt.url, got, tt.want)
+		}
+	}
+}
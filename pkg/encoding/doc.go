@@ -1,4 +1,9 @@
 // Package encoding provides Base64, Base32, and Base58 encoding and
 // decoding functions, plus a WrapString helper for line wrapping
 // encoded output at a specified column width.
+//
+// For mail tooling it also provides streaming codecs: NewBase64Encoder
+// with MIME (76-column, CRLF) line wrapping, quoted-printable
+// (NewQPEncoder, NewQPDecoder), and uuencode in its traditional and
+// base64 forms (NewUUEncoder, NewUUDecoder).
 package encoding
@@ -0,0 +1,127 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime/quotedprintable"
+)
+
+// MIMELineLength is the maximum encoded line length for MIME base64 and
+// quoted-printable bodies (RFC 2045).
+const MIMELineLength = 76
+
+// NewBase64Encoder returns a streaming base64 encoder that breaks its
+// output into lines of width characters ended by eol ("\n", or "\r\n" for
+// MIME). A width of 0 writes a single line. Close flushes the final
+// partial block and line ending; it does not close w.
+func NewBase64Encoder(w io.Writer, width int, eol string) io.WriteCloser {
+	lw := &lineWriter{w: w, width: width, eol: []byte(eol)}
+	return &base64Encoder{enc: base64.NewEncoder(base64.StdEncoding, lw), lw: lw}
+}
+
+// NewBase64Decoder returns a streaming base64 decoder. Line breaks in the
+// input are ignored, so wrapped and MIME-formatted bodies decode as is.
+func NewBase64Decoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.StdEncoding, r)
+}
+
+// NewQPEncoder returns a streaming quoted-printable encoder (RFC 2045)
+// with soft line breaks at 76 characters. With binary set, line breaks in
+// the input are encoded as =0D=0A instead of being kept as text line
+// breaks. Close flushes; it does not close w.
+func NewQPEncoder(w io.Writer, binary bool) io.WriteCloser {
+	qw := quotedprintable.NewWriter(w)
+	qw.Binary = binary
+
+	return qw
+}
+
+// NewQPDecoder returns a streaming quoted-printable decoder.
+func NewQPDecoder(r io.Reader) io.Reader {
+	return quotedprintable.NewReader(r)
+}
+
+// QPEncode encodes data as quoted-printable text.
+func QPEncode(data []byte) string {
+	var buf bytes.Buffer
+
+	qw := NewQPEncoder(&buf, false)
+	_, _ = qw.Write(data)
+	_ = qw.Close()
+
+	return buf.String()
+}
+
+// QPDecode decodes quoted-printable text.
+func QPDecode(s string) ([]byte, error) {
+	return io.ReadAll(NewQPDecoder(bytes.NewReader([]byte(s))))
+}
+
+type base64Encoder struct {
+	enc io.WriteCloser
+	lw  *lineWriter
+}
+
+func (e *base64Encoder) Write(p []byte) (int, error) { return e.enc.Write(p) }
+
+func (e *base64Encoder) Close() error {
+	if err := e.enc.Close(); err != nil {
+		return err
+	}
+
+	return e.lw.finish()
+}
+
+// lineWriter inserts eol after every width bytes written through it.
+type lineWriter struct {
+	w     io.Writer
+	width int
+	eol   []byte
+	col   int
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	if l.width <= 0 {
+		l.col += len(p)
+		return l.w.Write(p)
+	}
+
+	written := 0
+
+	for len(p) > 0 {
+		if l.col == l.width {
+			if _, err := l.w.Write(l.eol); err != nil {
+				return written, err
+			}
+
+			l.col = 0
+		}
+
+		n := min(len(p), l.width-l.col)
+
+		m, err := l.w.Write(p[:n])
+		written += m
+		l.col += m
+
+		if err != nil {
+			return written, err
+		}
+
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+// finish ends the last line, if any output was written.
+func (l *lineWriter) finish() error {
+	if l.col == 0 {
+		return nil
+	}
+
+	l.col = 0
+	_, err := l.w.Write(l.eol)
+
+	return err
+}
@@ -0,0 +1,111 @@
+package encoding
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBase64EncoderWrap(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 12))
+
+	tests := []struct {
+		name  string
+		width int
+		eol   string
+	}{
+		{"mime", MIMELineLength, "\r\n"},
+		{"unix", 20, "\n"},
+		{"single line", 0, "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			e := NewBase64Encoder(&buf, tt.width, tt.eol)
+
+			// Write in small pieces to exercise buffering
+			for i := 0; i < len(data); i += 7 {
+				if _, err := e.Write(data[i:min(i+7, len(data))]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := e.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			out := buf.String()
+			if !strings.HasSuffix(out, tt.eol) {
+				t.Errorf("output %q does not end with %q", out, tt.eol)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, tt.eol), tt.eol)
+			for i, line := range lines {
+				if tt.width > 0 && len(line) > tt.width {
+					t.Errorf("line %d is %d characters", i, len(line))
+				}
+			}
+
+			if got := strings.Join(lines, ""); got != Base64Encode(data) {
+				t.Errorf("joined output %q differs from Base64Encode", got)
+			}
+
+			decoded, err := io.ReadAll(NewBase64Decoder(&buf))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(decoded, data) {
+				t.Error("round trip mismatch")
+			}
+		})
+	}
+}
+
+func TestQPEncodeDecode(t *testing.T) {
+	inputs := []string{
+		"plain ascii",
+		"café = 100%",
+		strings.Repeat("long line ", 20),
+		"trailing space \nnext",
+	}
+
+	for _, in := range inputs {
+		encoded := QPEncode([]byte(in))
+
+		for i, line := range strings.Split(encoded, "\r\n") {
+			if len(line) > MIMELineLength {
+				t.Errorf("%q: line %d is %d characters", in, i, len(line))
+			}
+		}
+
+		decoded, err := QPDecode(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Text line breaks come back as CRLF
+		if want := strings.ReplaceAll(in, "\n", "\r\n"); string(decoded) != want {
+			t.Errorf("QPDecode(QPEncode(%q)) = %q", in, decoded)
+		}
+	}
+
+	if got := QPEncode([]byte("café")); got != "caf=C3=A9" {
+		t.Errorf("QPEncode = %q", got)
+	}
+}
+
+func TestQPEncoderBinary(t *testing.T) {
+	var buf bytes.Buffer
+
+	e := NewQPEncoder(&buf, true)
+	_, _ = e.Write([]byte("a\r\nb"))
+	_ = e.Close()
+
+	if got := buf.String(); got != "a=0D=0Ab" {
+		t.Errorf("binary QP = %q", got)
+	}
+}
@@ -0,0 +1,291 @@
+package encoding
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// uuLineBytes is the number of input bytes per encoded line, in both the
+// traditional and the base64 form (60 output characters).
+const uuLineBytes = 45
+
+var (
+	// ErrUUNoBegin is returned when the input has no "begin" line.
+	ErrUUNoBegin = errors.New("uudecode: no begin line")
+	// ErrUUNoEnd is returned when the input ends before the "end" line.
+	ErrUUNoEnd = errors.New("uudecode: missing end line")
+)
+
+// UUHeader is the "begin" line of a uuencoded file.
+type UUHeader struct {
+	Name   string      // file name recorded by the encoder
+	Mode   fs.FileMode // permission bits
+	Base64 bool        // "begin-base64" form (uuencode -m)
+}
+
+// UUEncoder streams data in uuencode format. Close must be called to
+// flush the last line and write the trailer.
+type UUEncoder struct {
+	w      io.Writer
+	base64 bool
+	buf    [uuLineBytes]byte
+	n      int
+	line   []byte
+	err    error
+}
+
+// NewUUEncoder writes h's begin line to w and returns an encoder for the
+// file content.
+func NewUUEncoder(w io.Writer, h UUHeader) *UUEncoder {
+	e := &UUEncoder{w: w, base64: h.Base64}
+
+	begin := "begin"
+	if h.Base64 {
+		begin = "begin-base64"
+	}
+
+	_, e.err = fmt.Fprintf(w, "%s %03o %s\n", begin, h.Mode.Perm(), h.Name)
+
+	return e
+}
+
+// Write encodes p, emitting a line for every 45 bytes.
+func (e *UUEncoder) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	written := 0
+
+	for len(p) > 0 {
+		c := copy(e.buf[e.n:], p)
+		e.n += c
+		p = p[c:]
+		written += c
+
+		if e.n == uuLineBytes {
+			if err := e.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Close writes any buffered bytes and the trailer. It does not close the
+// underlying writer.
+func (e *UUEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if e.n > 0 {
+		if err := e.flush(); err != nil {
+			return err
+		}
+	}
+
+	trailer := "`\nend\n"
+	if e.base64 {
+		trailer = "====\n"
+	}
+
+	_, e.err = io.WriteString(e.w, trailer)
+
+	return e.err
+}
+
+func (e *UUEncoder) flush() error {
+	data := e.buf[:e.n]
+	e.n = 0
+
+	if e.base64 {
+		e.line = base64.StdEncoding.AppendEncode(e.line[:0], data)
+	} else {
+		e.line = append(e.line[:0], uuChar(byte(len(data))))
+
+		for i := 0; i < len(data); i += 3 {
+			var g [3]byte
+			copy(g[:], data[i:])
+
+			e.line = append(e.line,
+				uuChar(g[0]>>2),
+				uuChar(g[0]<<4|g[1]>>4),
+				uuChar(g[1]<<2|g[2]>>6),
+				uuChar(g[2]),
+			)
+		}
+	}
+
+	e.line = append(e.line, '\n')
+	_, e.err = e.w.Write(e.line)
+
+	return e.err
+}
+
+// uuChar maps a 6-bit value to its character; zero is written as '`'
+// rather than space so lines survive whitespace trimming.
+func uuChar(v byte) byte {
+	v &= 0x3f
+	if v == 0 {
+		return '`'
+	}
+
+	return v + ' '
+}
+
+// UUDecoder reads the content of a uuencoded file.
+type UUDecoder struct {
+	Header UUHeader
+
+	r    *bufio.Reader
+	buf  []byte
+	done bool
+}
+
+// NewUUDecoder skips to the first begin line in r (anything before it,
+// such as mail headers, is ignored) and returns a decoder positioned at
+// the content.
+func NewUUDecoder(r io.Reader) (*UUDecoder, error) {
+	d := &UUDecoder{r: bufio.NewReader(r)}
+
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, ErrUUNoBegin
+			}
+
+			return nil, err
+		}
+
+		if h, ok := parseUUBegin(line); ok {
+			d.Header = h
+			return d, nil
+		}
+	}
+}
+
+// Read decodes the next bytes of content, returning io.EOF after the end
+// line and ErrUUNoEnd when the input stops before it.
+func (d *UUDecoder) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+
+		line, err := d.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, ErrUUNoEnd
+			}
+
+			return 0, err
+		}
+
+		if err := d.decodeLine(line); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+
+	return n, nil
+}
+
+func (d *UUDecoder) decodeLine(line string) error {
+	if d.Header.Base64 {
+		if line == "====" {
+			d.done = true
+			return nil
+		}
+
+		out, err := base64.StdEncoding.AppendDecode(d.buf[:0], []byte(line))
+		if err != nil {
+			return fmt.Errorf("uudecode: %w", err)
+		}
+
+		d.buf = out
+
+		return nil
+	}
+
+	if line == "end" {
+		d.done = true
+		return nil
+	}
+
+	if line == "" {
+		return nil
+	}
+
+	n := int(uuValue(line[0]))
+	if n == 0 {
+		// The "`" line before "end"
+		return nil
+	}
+
+	if want := 1 + (n+2)/3*4; len(line) < want {
+		// Some encoders trim trailing spaces; treat them as zero
+		line += strings.Repeat(" ", want-len(line))
+	}
+
+	out := d.buf[:0]
+
+	for i := 1; len(out) < n; i += 4 {
+		a, b, c, e := uuValue(line[i]), uuValue(line[i+1]), uuValue(line[i+2]), uuValue(line[i+3])
+		out = append(out, a<<2|b>>4, b<<4|c>>2, c<<6|e)
+	}
+
+	d.buf = out[:n]
+
+	return nil
+}
+
+func (d *UUDecoder) readLine() (string, error) {
+	line, err := d.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func uuValue(c byte) byte {
+	return (c - ' ') & 0x3f
+}
+
+// parseUUBegin parses "begin MODE NAME" or "begin-base64 MODE NAME".
+func parseUUBegin(line string) (UUHeader, bool) {
+	var h UUHeader
+
+	rest, ok := strings.CutPrefix(line, "begin-base64 ")
+	if ok {
+		h.Base64 = true
+	} else if rest, ok = strings.CutPrefix(line, "begin "); !ok {
+		return h, false
+	}
+
+	mode, name, ok := strings.Cut(strings.TrimLeft(rest, " "), " ")
+	if !ok || name == "" {
+		return h, false
+	}
+
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return h, false
+	}
+
+	h.Mode = fs.FileMode(perm).Perm()
+	h.Name = name
+
+	return h, true
+}
@@ -0,0 +1,116 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func uuEncode(t *testing.T, h UUHeader, data []byte) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	e := NewUUEncoder(&buf, h)
+	if _, err := e.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestUUEncodeKnown(t *testing.T) {
+	got := uuEncode(t, UUHeader{Name: "cat.txt", Mode: 0o644}, []byte("Cat"))
+
+	want := "begin 644 cat.txt\n#0V%T\n`\nend\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = uuEncode(t, UUHeader{Name: "cat.txt", Mode: 0o600, Base64: true}, []byte("Cat"))
+
+	want = "begin-base64 600 cat.txt\nQ2F0\n====\n"
+	if got != want {
+		t.Errorf("base64 form: got %q, want %q", got, want)
+	}
+}
+
+func TestUURoundTrip(t *testing.T) {
+	binary := make([]byte, 1000)
+	for i := range binary {
+		binary[i] = byte(i * 7)
+	}
+
+	inputs := map[string][]byte{
+		"empty":      {},
+		"one line":   []byte(strings.Repeat("x", 45)),
+		"multi line": binary,
+		"zeros":      make([]byte, 50),
+	}
+
+	for name, data := range inputs {
+		for _, b64 := range []bool{false, true} {
+			h := UUHeader{Name: "my file.bin", Mode: 0o755, Base64: b64}
+			encoded := uuEncode(t, h, data)
+
+			d, err := NewUUDecoder(strings.NewReader(encoded))
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+
+			if d.Header != h {
+				t.Errorf("%s: header = %+v, want %+v", name, d.Header, h)
+			}
+
+			got, err := io.ReadAll(d)
+			if err != nil {
+				t.Fatalf("%s (base64=%v): %v", name, b64, err)
+			}
+
+			if !bytes.Equal(got, data) {
+				t.Errorf("%s (base64=%v): round trip mismatch", name, b64)
+			}
+		}
+	}
+}
+
+func TestUUDecodeLenient(t *testing.T) {
+	// Leading mail headers, CRLF line endings, and trailing spaces trimmed
+	// from a line whose last group encodes zeros
+	in := "From: a@example.com\r\nSubject: x\r\n\r\nbegin 644 z\r\n#0V%T\r\n\"\r\n`\r\nend\r\n"
+
+	d, err := NewUUDecoder(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "Cat\x00\x00" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestUUDecodeErrors(t *testing.T) {
+	if _, err := NewUUDecoder(strings.NewReader("hello\n")); !errors.Is(err, ErrUUNoBegin) {
+		t.Errorf("no begin: err = %v", err)
+	}
+
+	d, err := NewUUDecoder(strings.NewReader("begin 644 f\n#0V%T\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := io.ReadAll(d); !errors.Is(err, ErrUUNoEnd) {
+		t.Errorf("no end: err = %v", err)
+	}
+}
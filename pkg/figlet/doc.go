@@ -1,4 +1,5 @@
 // Package figlet provides a FIGlet font parser and ASCII art text renderer.
 // It supports the FIGlet 2.2 font format and includes several embedded fonts.
-// All rendering is pure Go with no external dependencies.
+// Rendered lines can also be written as SVG (WriteSVG) or PNG (WritePNG)
+// images. All rendering is pure Go with no external dependencies.
 package figlet
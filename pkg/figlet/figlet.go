@@ -5,6 +5,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/inovacc/omni/pkg/textwidth"
 )
 
 // Font represents a parsed FIGlet font.
@@ -299,9 +301,7 @@ func renderText(f *Font, text string, maxWidth int) []string {
 	// Apply width limit if set
 	if maxWidth > 0 {
 		for i, line := range result {
-			if len(line) > maxWidth {
-				result[i] = line[:maxWidth]
-			}
+			result[i] = textwidth.Truncate(line, maxWidth)
 		}
 	}
 
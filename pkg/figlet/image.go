@@ -0,0 +1,323 @@
+package figlet
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// imageConfig holds SVG and PNG rendering options.
+type imageConfig struct {
+	fontSize   int
+	foreground color.RGBA
+	background color.RGBA
+	padding    int
+}
+
+// ImageOption configures the SVG and PNG renderers.
+type ImageOption func(*imageConfig)
+
+// WithFontSize sets the height of one character cell in pixels (default
+// 16). Cells are 0.6 times as wide as they are high.
+func WithFontSize(px int) ImageOption {
+	return func(c *imageConfig) { c.fontSize = px }
+}
+
+// WithColor sets the text color (default black).
+func WithColor(fg color.Color) ImageOption {
+	return func(c *imageConfig) { c.foreground = toRGBA(fg) }
+}
+
+// WithBackground sets the background color (default transparent).
+func WithBackground(bg color.Color) ImageOption {
+	return func(c *imageConfig) { c.background = toRGBA(bg) }
+}
+
+// WithPadding sets the margin around the art in pixels (default one
+// cell height / 2).
+func WithPadding(px int) ImageOption {
+	return func(c *imageConfig) { c.padding = px }
+}
+
+func newImageConfig(opts []ImageOption) imageConfig {
+	cfg := imageConfig{fontSize: 16, foreground: color.RGBA{A: 0xff}, padding: -1}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	cfg.fontSize = max(cfg.fontSize, 4)
+	if cfg.padding < 0 {
+		cfg.padding = cfg.fontSize / 2
+	}
+
+	return cfg
+}
+
+// cell returns the width and height of one character cell.
+func (c imageConfig) cell() (int, int) {
+	return max(c.fontSize*3/5, 2), c.fontSize
+}
+
+// size returns the image size needed for lines.
+func (c imageConfig) size(lines []string) (int, int) {
+	cw, ch := c.cell()
+
+	cols := 0
+	for _, l := range lines {
+		cols = max(cols, utf8.RuneCountInString(l))
+	}
+
+	return 2*c.padding + cols*cw, 2*c.padding + len(lines)*ch
+}
+
+// WriteSVG writes lines (as returned by RenderLines) as an SVG image. Each
+// line is a monospace text element stretched to the cell grid, so the art
+// keeps its alignment whatever monospace font the viewer picks.
+func WriteSVG(w io.Writer, lines []string, opts ...ImageOption) error {
+	cfg := newImageConfig(opts)
+	cw, ch := cfg.cell()
+	width, height := cfg.size(lines)
+
+	bw := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+
+	if cfg.background.A > 0 {
+		_, _ = fmt.Fprintf(bw, `  <rect width="100%%" height="100%%" fill="%s"%s/>`+"\n",
+			svgColor(cfg.background), svgOpacity("fill-opacity", cfg.background))
+	}
+
+	_, _ = fmt.Fprintf(bw, `  <text font-family="monospace" font-size="%d" fill="%s"%s xml:space="preserve">`+"\n",
+		cfg.fontSize, svgColor(cfg.foreground), svgOpacity("fill-opacity", cfg.foreground))
+
+	for i, l := range lines {
+		trimmed := strings.TrimRight(l, " ")
+		if trimmed == "" {
+			continue
+		}
+
+		// The baseline sits at 80% of the cell so descenders such as "_"
+		// stay inside it
+		_, _ = fmt.Fprintf(bw, `    <tspan x="%d" y="%d" textLength="%d" lengthAdjust="spacingAndGlyphs">%s</tspan>`+"\n",
+			cfg.padding, cfg.padding+i*ch+ch*4/5, utf8.RuneCountInString(trimmed)*cw, xmlEscape(trimmed))
+	}
+
+	_, _ = fmt.Fprintln(bw, "  </text>\n</svg>")
+
+	return bw.Flush()
+}
+
+// WritePNG writes lines (as returned by RenderLines) as a PNG image. The
+// standard library has no text rendering, so each character is drawn as
+// the strokes it stands for in FIGlet art: "_" a floor, "|" a post, "/"
+// and "\" diagonals, and so on; other characters become solid blocks.
+func WritePNG(w io.Writer, lines []string, opts ...ImageOption) error {
+	return png.Encode(w, RenderImage(lines, opts...))
+}
+
+// RenderImage draws lines (as returned by RenderLines) into an image, as
+// WritePNG encodes it.
+func RenderImage(lines []string, opts ...ImageOption) *image.RGBA {
+	cfg := newImageConfig(opts)
+	cw, ch := cfg.cell()
+	width, height := cfg.size(lines)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: cfg.background}, image.Point{}, draw.Src)
+
+	p := pen{img: img, c: cfg.foreground, t: max(cfg.fontSize/8, 1)}
+
+	for row, l := range lines {
+		col := 0
+
+		for _, r := range l {
+			p.glyph(r, cfg.padding+col*cw, cfg.padding+row*ch, cw, ch)
+			col++
+		}
+	}
+
+	return img
+}
+
+// pen draws strokes of thickness t.
+type pen struct {
+	img *image.RGBA
+	c   color.RGBA
+	t   int
+}
+
+// glyph draws r in the cell at (x, y) of size w×h.
+func (p pen) glyph(r rune, x, y, w, h int) {
+	right, bottom := x+w-1, y+h-1
+	midX, midY := x+w/2, y+h/2
+
+	switch r {
+	case ' ':
+	case '_':
+		p.line(x, bottom-p.t+1, right, bottom-p.t+1)
+	case '-', '~':
+		p.line(x, midY, right, midY)
+	case '=':
+		p.line(x, y+h/3, right, y+h/3)
+		p.line(x, y+2*h/3, right, y+2*h/3)
+	case '|', '!', 'I', 'l', '1', 'i':
+		p.line(midX, y, midX, bottom)
+	case '/':
+		p.line(x, bottom, right, y)
+	case '\\':
+		p.line(x, y, right, bottom)
+	case '(', '<', '{':
+		p.line(right, y, x+w/4, midY)
+		p.line(x+w/4, midY, right, bottom)
+	case ')', '>', '}':
+		p.line(x, y, right-w/4, midY)
+		p.line(right-w/4, midY, x, bottom)
+	case '[':
+		p.line(midX, y, midX, bottom)
+		p.line(midX, y, right, y)
+		p.line(midX, bottom, right, bottom)
+	case ']':
+		p.line(midX, y, midX, bottom)
+		p.line(x, y, midX, y)
+		p.line(x, bottom, midX, bottom)
+	case '^':
+		p.line(x, midY, midX, y)
+		p.line(midX, y, right, midY)
+	case 'v', 'V':
+		p.line(x, midY, midX, bottom)
+		p.line(midX, bottom, right, midY)
+	case '.', ',':
+		p.dot(midX, bottom-p.t)
+	case '\'', '`', '"':
+		p.dot(midX, y+p.t)
+	case ':', ';':
+		p.dot(midX, y+h/3)
+		p.dot(midX, y+2*h/3)
+	default:
+		inset := max(w/8, 1)
+		p.fill(x+inset, y+inset, right-inset, bottom-inset)
+	}
+}
+
+// line draws a straight stroke between two points.
+func (p pen) line(x0, y0, x1, y1 int) {
+	steps := max(abs(x1-x0), abs(y1-y0), 1)
+
+	for i := 0; i <= steps; i++ {
+		x := x0 + (x1-x0)*i/steps
+		y := y0 + (y1-y0)*i/steps
+		p.fill(x-p.t/2, y-p.t/2, x-p.t/2+p.t-1, y-p.t/2+p.t-1)
+	}
+}
+
+func (p pen) dot(x, y int) {
+	size := max(p.t, 2)
+	p.fill(x-size/2, y-size/2, x-size/2+size-1, y-size/2+size-1)
+}
+
+// fill paints the inclusive rectangle, blending over the background.
+func (p pen) fill(x0, y0, x1, y1 int) {
+	r := image.Rect(x0, y0, x1+1, y1+1).Intersect(p.img.Bounds())
+	draw.Draw(p.img, r, &image.Uniform{C: p.c}, image.Point{}, draw.Over)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// colorNames are the color names ParseColor accepts besides hex values.
+var colorNames = map[string]color.RGBA{
+	"black":       {0x00, 0x00, 0x00, 0xff},
+	"white":       {0xff, 0xff, 0xff, 0xff},
+	"red":         {0xff, 0x00, 0x00, 0xff},
+	"green":       {0x00, 0x80, 0x00, 0xff},
+	"blue":        {0x00, 0x00, 0xff, 0xff},
+	"yellow":      {0xff, 0xff, 0x00, 0xff},
+	"cyan":        {0x00, 0xff, 0xff, 0xff},
+	"magenta":     {0xff, 0x00, 0xff, 0xff},
+	"gray":        {0x80, 0x80, 0x80, 0xff},
+	"grey":        {0x80, 0x80, 0x80, 0xff},
+	"orange":      {0xff, 0xa5, 0x00, 0xff},
+	"purple":      {0x80, 0x00, 0x80, 0xff},
+	"transparent": {},
+}
+
+// ParseColor parses "#rgb", "#rrggbb", "#rrggbbaa" (the "#" is optional)
+// or a basic color name such as "white" or "transparent".
+func ParseColor(s string) (color.RGBA, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := colorNames[s]; ok {
+		return c, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	var c color.RGBA
+	if len(hex) != 8 {
+		return c, fmt.Errorf("invalid color %q", s)
+	}
+
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A); err != nil {
+		return c, fmt.Errorf("invalid color %q", s)
+	}
+
+	return c, nil
+}
+
+// toRGBA converts c to non-premultiplied RGBA.
+func toRGBA(c color.Color) color.RGBA {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	return color.RGBA{R: n.R, G: n.G, B: n.B, A: n.A}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func svgOpacity(attr string, c color.RGBA) string {
+	if c.A == 0xff {
+		return ""
+	}
+
+	return fmt.Sprintf(` %s="%.3g"`, attr, float64(c.A)/0xff)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package figlet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteSVG(t *testing.T) {
+	lines, err := RenderLines("A<&")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, lines, WithFontSize(20), WithColor(color.RGBA{R: 0xff, A: 0xff}), WithBackground(color.White)); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+
+	out := buf.String()
+
+	// Must be well-formed XML
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("invalid XML: %v\n%s", err, out)
+			}
+
+			break
+		}
+	}
+
+	for _, want := range []string{`font-size="20"`, `fill="#ff0000"`, `<rect`, `fill="#ffffff"`, `&lt;`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	_ = WriteSVG(&buf, lines)
+
+	if strings.Contains(buf.String(), "<rect") {
+		t.Error("default SVG should have a transparent background")
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	lines := []string{"_|", "/ "}
+
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, lines, WithFontSize(10), WithPadding(0), WithBackground(color.White)); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+
+	// Cells are 6x10, so the image is 2 cells wide and 2 high
+	if b := img.Bounds(); b.Dx() != 12 || b.Dy() != 20 {
+		t.Fatalf("size = %v, want 12x20", b.Size())
+	}
+
+	isInk := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+
+	if !isInk(2, 9) {
+		t.Error("'_' should draw along the bottom of its cell")
+	}
+
+	if isInk(2, 2) {
+		t.Error("'_' should leave the top of its cell empty")
+	}
+
+	if !isInk(9, 4) {
+		t.Error("'|' should draw down the middle of its cell")
+	}
+
+	if isInk(8, 15) {
+		t.Error("a space should stay blank")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{"#fff", color.RGBA{0xff, 0xff, 0xff, 0xff}, false},
+		{"#0366d6", color.RGBA{0x03, 0x66, 0xd6, 0xff}, false},
+		{"0366d680", color.RGBA{0x03, 0x66, 0xd6, 0x80}, false},
+		{"Red", color.RGBA{0xff, 0, 0, 0xff}, false},
+		{"transparent", color.RGBA{}, false},
+		{"#12", color.RGBA{}, true},
+		{"#gggggg", color.RGBA{}, true},
+		{"chartreuse", color.RGBA{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseColor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
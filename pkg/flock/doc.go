@@ -0,0 +1,14 @@
+// Package flock provides cross-platform advisory file locks: flock(2) on
+// Unix and LockFileEx on Windows.
+//
+// A Lock holds an open descriptor on its file for as long as the lock is
+// held; the operating system releases it when the descriptor is closed,
+// including when the process dies, so a crashed holder never leaves a stale
+// lock behind. The lock file itself is created if needed and never removed,
+// since deleting it would let a second process lock a new file of the same
+// name while the first still holds the old one.
+//
+// Locks are advisory: they coordinate processes that use them and do not
+// stop anything else from reading or writing the file. Two Locks on the
+// same path conflict even within one process.
+package flock
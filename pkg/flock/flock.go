@@ -0,0 +1,173 @@
+package flock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms without file locking.
+var ErrUnsupported = errors.New("flock: file locking is not supported on this platform")
+
+// Polling intervals used by Lock and RLock while the lock is busy.
+const (
+	minPoll = 10 * time.Millisecond
+	maxPoll = 250 * time.Millisecond
+)
+
+// Lock is an advisory lock on a file. The zero value is not usable; create
+// one with New. A Lock is safe for concurrent use, but it is a single lock:
+// it is either unlocked or held once, exclusively or shared.
+type Lock struct {
+	path string
+
+	mu     sync.Mutex
+	f      *os.File
+	shared bool
+}
+
+// New returns an unlocked Lock on path. The file is created when the lock
+// is first acquired.
+func New(path string) *Lock {
+	return &Lock{path: path}
+}
+
+// Path returns the lock file's path.
+func (l *Lock) Path() string { return l.path }
+
+// Locked reports whether l is held.
+func (l *Lock) Locked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f != nil
+}
+
+// Shared reports whether l is held as a shared lock.
+func (l *Lock) Shared() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f != nil && l.shared
+}
+
+// TryLock tries to take an exclusive lock without waiting. It reports
+// false, with a nil error, when another holder has the file locked.
+func (l *Lock) TryLock() (bool, error) { return l.try(false) }
+
+// TryRLock tries to take a shared lock without waiting. Any number of
+// shared holders may coexist; an exclusive holder excludes them all.
+func (l *Lock) TryRLock() (bool, error) { return l.try(true) }
+
+// Lock takes an exclusive lock, waiting until it is free or ctx is done.
+// On cancellation it returns ctx.Err().
+func (l *Lock) Lock(ctx context.Context) error { return l.wait(ctx, false) }
+
+// RLock takes a shared lock, waiting until it is available or ctx is done.
+func (l *Lock) RLock(ctx context.Context) error { return l.wait(ctx, true) }
+
+// Unlock releases the lock. Unlocking a Lock that is not held is a no-op.
+func (l *Lock) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return nil
+	}
+
+	err := unlock(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+
+	l.f = nil
+
+	if err != nil {
+		return fmt.Errorf("flock: unlock %s: %w", l.path, err)
+	}
+
+	return nil
+}
+
+func (l *Lock) try(shared bool) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f != nil {
+		if l.shared == shared {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("flock: %s is already held in another mode", l.path)
+	}
+
+	f, err := open(l.path)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := tryLock(f, shared)
+	if err != nil || !ok {
+		_ = f.Close()
+
+		if err != nil {
+			if errors.Is(err, ErrUnsupported) {
+				return false, err
+			}
+
+			return false, fmt.Errorf("flock: lock %s: %w", l.path, err)
+		}
+
+		return false, nil
+	}
+
+	l.f, l.shared = f, shared
+
+	return true, nil
+}
+
+// wait polls try with a growing interval. flock(2) and LockFileEx can
+// block, but neither can be interrupted by a context, so polling keeps
+// cancellation and timeouts exact.
+func (l *Lock) wait(ctx context.Context, shared bool) error {
+	for delay := minPoll; ; delay = min(delay*2, maxPoll) {
+		ok, err := l.try(shared)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// open opens the lock file, creating it if needed. A file that cannot be
+// opened for writing is still lockable read-only.
+func open(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil && errors.Is(err, os.ErrPermission) {
+		if ro, rerr := os.Open(path); rerr == nil {
+			return ro, nil
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("flock: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package flock
+
+import "os"
+
+func tryLock(*os.File, bool) (bool, error) { return false, ErrUnsupported }
+
+func unlock(*os.File) error { return ErrUnsupported }
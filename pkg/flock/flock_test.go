@@ -0,0 +1,154 @@
+package flock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func lockPath(t *testing.T) string {
+	t.Helper()
+
+	return filepath.Join(t.TempDir(), "test.lock")
+}
+
+func TestTryLockExclusive(t *testing.T) {
+	path := lockPath(t)
+	a, b := New(path), New(path)
+
+	if ok, err := a.TryLock(); !ok || err != nil {
+		t.Fatalf("a.TryLock() = %v, %v; want true, nil", ok, err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("lock file not created: %v", err)
+	}
+
+	if ok, err := b.TryLock(); ok || err != nil {
+		t.Fatalf("b.TryLock() while held = %v, %v; want false, nil", ok, err)
+	}
+
+	if ok, err := b.TryRLock(); ok || err != nil {
+		t.Fatalf("b.TryRLock() while held = %v, %v; want false, nil", ok, err)
+	}
+
+	if err := a.Unlock(); err != nil {
+		t.Fatalf("a.Unlock() = %v", err)
+	}
+
+	if ok, err := b.TryLock(); !ok || err != nil {
+		t.Fatalf("b.TryLock() after unlock = %v, %v; want true, nil", ok, err)
+	}
+
+	_ = b.Unlock()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("lock file removed on unlock: %v", err)
+	}
+}
+
+func TestTryRLockShared(t *testing.T) {
+	path := lockPath(t)
+	a, b, c := New(path), New(path), New(path)
+
+	for _, l := range []*Lock{a, b} {
+		if ok, err := l.TryRLock(); !ok || err != nil {
+			t.Fatalf("TryRLock() = %v, %v; want true, nil", ok, err)
+		}
+
+		defer func() { _ = l.Unlock() }()
+	}
+
+	if !a.Shared() || !a.Locked() {
+		t.Error("a should report a held shared lock")
+	}
+
+	if ok, err := c.TryLock(); ok || err != nil {
+		t.Errorf("exclusive TryLock() with shared holders = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func TestTryLockModes(t *testing.T) {
+	l := New(lockPath(t))
+	defer func() { _ = l.Unlock() }()
+
+	if ok, _ := l.TryLock(); !ok {
+		t.Fatal("TryLock() failed")
+	}
+
+	if ok, err := l.TryLock(); !ok || err != nil {
+		t.Errorf("relocking in the same mode = %v, %v; want true, nil", ok, err)
+	}
+
+	if _, err := l.TryRLock(); err == nil {
+		t.Error("TryRLock() on an exclusive lock should fail")
+	}
+}
+
+func TestLockWaits(t *testing.T) {
+	path := lockPath(t)
+	a, b := New(path), New(path)
+
+	if ok, _ := a.TryLock(); !ok {
+		t.Fatal("a.TryLock() failed")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		_ = a.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := b.Lock(ctx); err != nil {
+		t.Fatalf("b.Lock() = %v; want the lock once a releases it", err)
+	}
+
+	_ = b.Unlock()
+}
+
+func TestLockTimeout(t *testing.T) {
+	path := lockPath(t)
+	a, b := New(path), New(path)
+
+	if ok, _ := a.TryLock(); !ok {
+		t.Fatal("a.TryLock() failed")
+	}
+	defer func() { _ = a.Unlock() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	if err := b.RLock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("b.RLock() = %v; want context.DeadlineExceeded", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("RLock() took %s to time out", elapsed)
+	}
+
+	if b.Locked() {
+		t.Error("b should not be locked after a timeout")
+	}
+}
+
+func TestUnlockNotHeld(t *testing.T) {
+	if err := New(lockPath(t)).Unlock(); err != nil {
+		t.Errorf("Unlock() on an unheld lock = %v", err)
+	}
+}
+
+func TestOpenMissingDir(t *testing.T) {
+	l := New(filepath.Join(t.TempDir(), "missing", "test.lock"))
+
+	if _, err := l.TryLock(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("TryLock() in a missing directory = %v; want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,62 @@
+package hashutil
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"os"
+)
+
+// EqualDigest reports whether two hex digests for algo are the same digest.
+// Both are validated and normalized as by NormalizeDigest, so case and an
+// "algo:" prefix do not matter, and they are compared in constant time.
+func EqualDigest(a, b string, algo Algorithm) (bool, error) {
+	na, err := NormalizeDigest(a, algo)
+	if err != nil {
+		return false, err
+	}
+
+	nb, err := NormalizeDigest(b, algo)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(na), []byte(nb)) == 1, nil
+}
+
+// CompareReaders hashes a and b with algo and reports whether their
+// digests are equal. Both are streamed, so inputs of any size compare in
+// constant memory. Unlike a byte-by-byte comparison, the time taken does
+// not reveal where the inputs first differ.
+func CompareReaders(a, b io.Reader, algo Algorithm) (bool, error) {
+	sa, err := HashReader(a, algo)
+	if err != nil {
+		return false, err
+	}
+
+	sb, err := HashReader(b, algo)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(sa), []byte(sb)) == 1, nil
+}
+
+// CompareFiles is CompareReaders for the files at pathA and pathB.
+func CompareFiles(pathA, pathB string, algo Algorithm) (bool, error) {
+	fa, err := os.Open(pathA)
+	if err != nil {
+		return false, fmt.Errorf("hashutil: %w", err)
+	}
+
+	defer func() { _ = fa.Close() }()
+
+	fb, err := os.Open(pathB)
+	if err != nil {
+		return false, fmt.Errorf("hashutil: %w", err)
+	}
+
+	defer func() { _ = fb.Close() }()
+
+	return CompareReaders(fa, fb, algo)
+}
@@ -0,0 +1,58 @@
+package hashutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEqualDigest(t *testing.T) {
+	upper := "sha256:" + strings.ToUpper(helloSHA256)
+
+	if ok, err := EqualDigest(helloSHA256, upper, SHA256); err != nil || !ok {
+		t.Errorf("EqualDigest(same digest) = %v, %v", ok, err)
+	}
+
+	other := HashString("world", SHA256)
+	if ok, err := EqualDigest(helloSHA256, other, SHA256); err != nil || ok {
+		t.Errorf("EqualDigest(different digests) = %v, %v", ok, err)
+	}
+
+	if _, err := EqualDigest(helloSHA256, "abc", SHA256); err == nil {
+		t.Error("EqualDigest(short digest) should fail")
+	}
+
+	if _, err := EqualDigest(helloSHA256, "md5:"+helloSHA256, SHA256); err == nil {
+		t.Error("EqualDigest(wrong algorithm prefix) should fail")
+	}
+}
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	a := write("a", "hello")
+	b := write("b", "hello")
+	c := write("c", "hellO")
+
+	if ok, err := CompareFiles(a, b, SHA256); err != nil || !ok {
+		t.Errorf("CompareFiles(a, b) = %v, %v", ok, err)
+	}
+
+	if ok, err := CompareFiles(a, c, BLAKE2B); err != nil || ok {
+		t.Errorf("CompareFiles(a, c) = %v, %v", ok, err)
+	}
+
+	if _, err := CompareFiles(a, filepath.Join(dir, "missing"), SHA256); err == nil {
+		t.Error("CompareFiles(missing) should fail")
+	}
+}
@@ -1,4 +1,15 @@
 // Package hashutil provides hash computation for files, strings, byte slices,
-// and io.Reader streams. Supported algorithms include MD5, SHA-1, SHA-256,
-// SHA-512, CRC32, and CRC64.
+// and io.Reader streams. Supported algorithms include MD5, SHA-1, the SHA-2
+// family, CRC32, CRC64, BLAKE2b-256/512 and BLAKE3, and the non-cryptographic
+// xxHash64 and XXH3 for fast checksums of large files.
+//
+// TeeHasher verifies content while it streams to its destination, and
+// ParseSidecar reads the expected digest from sha256sum-style sidecar files.
+// EqualDigest and CompareReaders compare digests and streams in constant
+// time.
+//
+// CreateManifest hashes directory trees on parallel workers into a
+// SHA256SUMS-style manifest, which WriteManifest writes in the GNU or the
+// tagged BSD format; ParseManifest reads either, and VerifyManifest checks
+// every file of a manifest with a per-file status.
 package hashutil
@@ -14,7 +14,10 @@ import (
 	"os"
 	"strings"
 
+	"github.com/cespare/xxhash/v2"
+	"github.com/zeebo/xxh3"
 	"golang.org/x/crypto/blake2b"
+	"lukechampine.com/blake3"
 )
 
 // Algorithm represents a hash algorithm.
@@ -29,7 +32,13 @@ const (
 	SHA512  Algorithm = "sha512"
 	CRC32   Algorithm = "crc32"
 	CRC64   Algorithm = "crc64"
-	BLAKE2B Algorithm = "blake2b"
+	BLAKE2B Algorithm = "blake2b" // BLAKE2b-256, the same as BLAKE2B256
+
+	BLAKE2B256 Algorithm = "blake2b-256"
+	BLAKE2B512 Algorithm = "blake2b-512"
+	BLAKE3     Algorithm = "blake3" // 256-bit digest
+	XXH64      Algorithm = "xxh64"  // xxHash64, seed 0; not cryptographic
+	XXH3       Algorithm = "xxh3"   // XXH3-64, seed 0; not cryptographic
 )
 
 // HashFile computes the hash of a file at the given path.
@@ -70,6 +79,11 @@ func HashBytes(data []byte, algo Algorithm) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// Algorithms returns the supported algorithms.
+func Algorithms() []Algorithm {
+	return []Algorithm{MD5, SHA1, SHA224, SHA256, SHA384, SHA512, CRC32, CRC64, BLAKE2B, BLAKE2B256, BLAKE2B512, BLAKE3, XXH64, XXH3}
+}
+
 func newHasher(algo Algorithm) hash.Hash {
 	switch Algorithm(strings.ToLower(string(algo))) {
 	case MD5:
@@ -88,9 +102,18 @@ func newHasher(algo Algorithm) hash.Hash {
 		return crc32.NewIEEE()
 	case CRC64:
 		return crc64.New(crc64.MakeTable(crc64.ECMA))
-	case BLAKE2B:
+	case BLAKE2B, BLAKE2B256:
 		h, _ := blake2b.New256(nil) // 256-bit; nil key => unkeyed digest, never errors
 		return h
+	case BLAKE2B512:
+		h, _ := blake2b.New512(nil)
+		return h
+	case BLAKE3:
+		return blake3.New(32, nil)
+	case XXH64:
+		return xxhash.New()
+	case XXH3:
+		return xxh3.New()
 	default:
 		return sha256.New()
 	}
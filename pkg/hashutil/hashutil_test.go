@@ -20,6 +20,15 @@ func TestHashString(t *testing.T) {
 		{"sha256 empty", "", SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
 		{"blake2b empty", "", BLAKE2B, "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8"},
 		{"blake2b test", "test", BLAKE2B, "928b20366943e2afd11ebc0eae2e53a93bf177a4fcf35bcc64d503704e65e202"},
+		{"blake2b-256 test", "test", BLAKE2B256, "928b20366943e2afd11ebc0eae2e53a93bf177a4fcf35bcc64d503704e65e202"},
+		{"blake2b-512 empty", "", BLAKE2B512, "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		{"blake3 empty", "", BLAKE3, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{"blake3 abc", "abc", BLAKE3, "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+		{"xxh64 empty", "", XXH64, "ef46db3751d8e999"},
+		{"xxh64 abc", "abc", XXH64, "44bc2cf5ad770999"},
+		{"xxh3 empty", "", XXH3, "2d06800538d394c2"},
+		{"xxh3 abc", "abc", XXH3, "78af5f94892f3950"},
+		{"case-insensitive", "", "BLAKE3", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,354 @@
+package hashutil
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ManifestStyle is the line format of a checksum manifest.
+type ManifestStyle int
+
+const (
+	// StyleGNU is the sha256sum format: "HASH  path", or "HASH *path" for
+	// files hashed in binary mode.
+	StyleGNU ManifestStyle = iota
+	// StyleBSD is the tagged format of "sha256sum --tag" and BSD sha256:
+	// "SHA256 (path) = HASH". Each line names its algorithm.
+	StyleBSD
+)
+
+// ManifestEntry is one file of a manifest.
+type ManifestEntry struct {
+	Path      string
+	Digest    string
+	Algorithm Algorithm
+	Size      int64 // -1 when read from a manifest file
+}
+
+// Manifest is a list of files and their digests, as in a SHA256SUMS file.
+type Manifest struct {
+	Entries []ManifestEntry
+	// Malformed holds the line numbers of the lines ParseManifest could
+	// not read as checksum lines.
+	Malformed []int
+}
+
+// ManifestOptions configures CreateManifest and VerifyManifest.
+type ManifestOptions struct {
+	Algorithm Algorithm // default SHA256; for VerifyManifest, used for GNU-style lines
+	Workers   int       // files hashed in parallel; default runtime.NumCPU()
+	Dir       string    // VerifyManifest: directory relative paths are resolved from
+}
+
+// Status is the outcome of verifying one manifest entry.
+type Status string
+
+const (
+	StatusOK      Status = "OK"
+	StatusFailed  Status = "FAILED"  // the digest does not match
+	StatusMissing Status = "MISSING" // the file could not be opened or read
+)
+
+// VerifyResult is the outcome of verifying one manifest entry.
+type VerifyResult struct {
+	ManifestEntry
+	Actual string // the digest computed, if the file could be read
+	Status Status
+	Err    error // why the file could not be read
+}
+
+// CreateManifest hashes every regular file in paths, walking directories
+// recursively, and returns their entries sorted by path. Entry paths are
+// the paths walked, slash-separated, so the manifest verifies from the
+// same working directory. Files that cannot be read are left out and their
+// errors returned, joined, along with the manifest of the rest.
+func CreateManifest(ctx context.Context, paths []string, opts ManifestOptions) (*Manifest, error) {
+	algo := opts.algorithm()
+
+	var (
+		files []string
+		errs  []error
+	)
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				errs = append(errs, err)
+				return nil
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if d.Type().IsRegular() {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]ManifestEntry, len(files))
+	fileErrs := make([]error, len(files))
+
+	err := forEachParallel(ctx, len(files), opts.Workers, func(i int) {
+		digest, size, err := hashFileSize(files[i], algo)
+		entries[i] = ManifestEntry{Path: filepath.ToSlash(files[i]), Digest: digest, Algorithm: algo, Size: size}
+		fileErrs[i] = err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+
+	for i, e := range entries {
+		if fileErrs[i] != nil {
+			errs = append(errs, fileErrs[i])
+			continue
+		}
+
+		m.Entries = append(m.Entries, e)
+	}
+
+	slices.SortFunc(m.Entries, func(a, b ManifestEntry) int { return strings.Compare(a.Path, b.Path) })
+
+	return m, errors.Join(errs...)
+}
+
+// VerifyManifest hashes the file of every entry of m and compares it with
+// the entry's digest. Results are in the order of m.Entries.
+func VerifyManifest(ctx context.Context, m *Manifest, opts ManifestOptions) ([]VerifyResult, error) {
+	results := make([]VerifyResult, len(m.Entries))
+
+	err := forEachParallel(ctx, len(m.Entries), opts.Workers, func(i int) {
+		e := m.Entries[i]
+		res := VerifyResult{ManifestEntry: e}
+
+		path := filepath.FromSlash(e.Path)
+		if opts.Dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(opts.Dir, path)
+		}
+
+		algo := e.Algorithm
+		if algo == "" {
+			algo = opts.algorithm()
+			res.Algorithm = algo
+		}
+
+		actual, size, err := hashFileSize(path, algo)
+
+		switch {
+		case err != nil:
+			res.Status, res.Err = StatusMissing, err
+		default:
+			res.Actual, res.Size = actual, size
+
+			if ok, _ := EqualDigest(actual, e.Digest, algo); ok {
+				res.Status = StatusOK
+			} else {
+				res.Status = StatusFailed
+			}
+		}
+
+		results[i] = res
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ParseManifest reads a manifest in either style; the two may be mixed.
+// GNU-style entries have no Algorithm, since the line does not say; BSD
+// entries carry the algorithm of their tag. Blank lines and # comments are
+// skipped, and other unreadable lines are recorded in Malformed.
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	m := &Manifest{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		e, ok := parseManifestLine(line)
+		if !ok {
+			m.Malformed = append(m.Malformed, n)
+			continue
+		}
+
+		m.Entries = append(m.Entries, e)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("hashutil: %w", err)
+	}
+
+	return m, nil
+}
+
+// parseManifestLine parses "TAG (path) = HASH" or "HASH  path".
+func parseManifestLine(line string) (ManifestEntry, bool) {
+	if tag, rest, ok := strings.Cut(line, " ("); ok {
+		if i := strings.LastIndex(rest, ") = "); i > 0 {
+			if algo, ok := algorithmForTag(tag); ok {
+				return ManifestEntry{Path: rest[:i], Digest: strings.TrimSpace(rest[i+4:]), Algorithm: algo, Size: -1}, true
+			}
+		}
+	}
+
+	digest, path, ok := strings.Cut(strings.TrimLeft(line, " \t"), " ")
+	if !ok || digest == "" {
+		return ManifestEntry{}, false
+	}
+
+	// One space and a mode character: ' ' for text, '*' for binary.
+	if strings.HasPrefix(path, " ") || strings.HasPrefix(path, "*") {
+		path = path[1:]
+	}
+
+	if path == "" || !isHex(digest) {
+		return ManifestEntry{}, false
+	}
+
+	return ManifestEntry{Path: path, Digest: digest, Size: -1}, true
+}
+
+// WriteManifest writes m in the given style, one line per entry.
+func WriteManifest(w io.Writer, m *Manifest, style ManifestStyle) error {
+	bw := bufio.NewWriter(w)
+
+	for _, e := range m.Entries {
+		if style == StyleBSD {
+			_, _ = fmt.Fprintf(bw, "%s (%s) = %s\n", ManifestTag(e.Algorithm), e.Path, e.Digest)
+		} else {
+			_, _ = fmt.Fprintf(bw, "%s  %s\n", e.Digest, e.Path)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ManifestTag returns the BSD-style tag of algo, as written by the
+// coreutils, b2sum and xxhsum --tag options: SHA256, BLAKE2b, XXH64...
+func ManifestTag(algo Algorithm) string {
+	switch Algorithm(strings.ToLower(string(algo))) {
+	case BLAKE2B, BLAKE2B256:
+		return "BLAKE2b-256"
+	case BLAKE2B512:
+		return "BLAKE2b"
+	default:
+		return strings.ToUpper(string(algo))
+	}
+}
+
+// algorithmForTag is the inverse of ManifestTag, ignoring case.
+func algorithmForTag(tag string) (Algorithm, bool) {
+	if strings.EqualFold(tag, "BLAKE2b") {
+		return BLAKE2B512, true
+	}
+
+	for _, a := range Algorithms() {
+		if a != BLAKE2B && strings.EqualFold(tag, ManifestTag(a)) {
+			return a, true
+		}
+	}
+
+	return "", false
+}
+
+func (o ManifestOptions) algorithm() Algorithm {
+	if o.Algorithm == "" {
+		return SHA256
+	}
+
+	return Algorithm(strings.ToLower(string(o.Algorithm)))
+}
+
+// hashFileSize hashes the file at path and returns its size too.
+func hashFileSize(path string, algo Algorithm) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := newHasher(algo)
+
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, &fs.PathError{Op: "read", Path: path, Err: err}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// forEachParallel calls fn for 0..n-1 on up to workers goroutines. It
+// stops handing out work once ctx is done and then returns ctx.Err().
+func forEachParallel(ctx context.Context, n, workers int, fn func(i int)) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	workers = min(workers, n)
+
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Go(func() {
+			for i := range next {
+				fn(i)
+			}
+		})
+	}
+
+	var err error
+
+feed:
+	for i := range n {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+
+	close(next)
+	wg.Wait()
+
+	return err
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,169 @@
+package hashutil
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCreateManifest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"b": "bee", "a/x": "ex", "a/y": "why"}
+
+	for i := range 20 {
+		files["many/"+string(rune('a'+i))] = strings.Repeat("z", i)
+	}
+
+	writeFiles(t, dir, files)
+
+	m, err := CreateManifest(context.Background(), []string{dir}, ManifestOptions{Workers: 4})
+	if err != nil {
+		t.Fatalf("CreateManifest() error = %v", err)
+	}
+
+	if len(m.Entries) != len(files) {
+		t.Fatalf("got %d entries, want %d", len(m.Entries), len(files))
+	}
+
+	if !slices.IsSortedFunc(m.Entries, func(a, b ManifestEntry) int { return strings.Compare(a.Path, b.Path) }) {
+		t.Error("entries are not sorted")
+	}
+
+	for _, e := range m.Entries {
+		rel := strings.TrimPrefix(e.Path, filepath.ToSlash(dir)+"/")
+		if e.Digest != HashString(files[rel], SHA256) || e.Size != int64(len(files[rel])) || e.Algorithm != SHA256 {
+			t.Errorf("entry %+v does not match %q", e, files[rel])
+		}
+	}
+
+	m, err = CreateManifest(context.Background(), []string{filepath.Join(dir, "b"), filepath.Join(dir, "missing")}, ManifestOptions{Algorithm: MD5})
+	if err == nil || m == nil || len(m.Entries) != 1 || m.Entries[0].Digest != HashString("bee", MD5) {
+		t.Errorf("CreateManifest() with a missing path = %+v, %v", m, err)
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"one": "1", "sub/two": "2"})
+
+	for _, style := range []ManifestStyle{StyleGNU, StyleBSD} {
+		m, err := CreateManifest(context.Background(), []string{dir}, ManifestOptions{Algorithm: BLAKE2B512})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteManifest(&buf, m, style); err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := ParseManifest(&buf)
+		if err != nil {
+			t.Fatalf("ParseManifest() error = %v", err)
+		}
+
+		results, err := VerifyManifest(context.Background(), parsed, ManifestOptions{Algorithm: BLAKE2B512})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range results {
+			if r.Status != StatusOK || r.Algorithm != BLAKE2B512 {
+				t.Errorf("style %d: %s: %s (%s)", style, r.Path, r.Status, r.Algorithm)
+			}
+		}
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	sha := HashString("x", SHA256)
+	input := "# comment\n\n" +
+		sha + "  plain name\n" +
+		sha + " *binary\n" +
+		"MD5 (with (parens).txt) = " + HashString("x", MD5) + "\n" +
+		"BLAKE2b (b2) = 00\r\n" +
+		"not a checksum line\n" +
+		"XYZ (unknown) = 00\n"
+
+	m, err := ParseManifest(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ManifestEntry{
+		{Path: "plain name", Digest: sha, Size: -1},
+		{Path: "binary", Digest: sha, Size: -1},
+		{Path: "with (parens).txt", Digest: HashString("x", MD5), Algorithm: MD5, Size: -1},
+		{Path: "b2", Digest: "00", Algorithm: BLAKE2B512, Size: -1},
+	}
+
+	if !slices.Equal(m.Entries, want) {
+		t.Errorf("Entries = %+v, want %+v", m.Entries, want)
+	}
+
+	if !slices.Equal(m.Malformed, []int{7, 8}) {
+		t.Errorf("Malformed = %v, want [7 8]", m.Malformed)
+	}
+}
+
+func TestVerifyManifestStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"good": "g", "bad": "changed"})
+
+	m := &Manifest{Entries: []ManifestEntry{
+		{Path: "good", Digest: HashString("g", SHA256)},
+		{Path: "bad", Digest: HashString("original", SHA256)},
+		{Path: "gone", Digest: HashString("g", SHA256)},
+		{Path: "good", Digest: HashString("g", XXH3), Algorithm: XXH3},
+	}}
+
+	results, err := VerifyManifest(context.Background(), m, ManifestOptions{Dir: dir, Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []Status
+	for _, r := range results {
+		got = append(got, r.Status)
+	}
+
+	if want := []Status{StatusOK, StatusFailed, StatusMissing, StatusOK}; !slices.Equal(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+
+	if results[2].Err == nil || results[1].Actual != HashString("changed", SHA256) {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestManifestTag(t *testing.T) {
+	for _, a := range Algorithms() {
+		got, ok := algorithmForTag(ManifestTag(a))
+		if !ok || (got != a && a != BLAKE2B) {
+			t.Errorf("algorithmForTag(ManifestTag(%s)) = %s, %v", a, got, ok)
+		}
+	}
+
+	if ManifestTag(SHA256) != "SHA256" || ManifestTag(BLAKE2B512) != "BLAKE2b" {
+		t.Errorf("unexpected tags %s, %s", ManifestTag(SHA256), ManifestTag(BLAKE2B512))
+	}
+}
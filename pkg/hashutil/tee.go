@@ -0,0 +1,146 @@
+package hashutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrMismatch is returned by TeeHasher.Verify when the digest differs from
+// the expected one.
+var ErrMismatch = errors.New("hashutil: checksum mismatch")
+
+// TeeHasher hashes everything written through it, so content can be
+// verified while it streams to its destination instead of in a second pass.
+type TeeHasher struct {
+	w    io.Writer
+	h    hash.Hash
+	algo Algorithm
+	n    int64
+}
+
+// NewTeeHasher returns a TeeHasher that forwards writes to w (which may be
+// nil to only hash) while computing the algo digest.
+func NewTeeHasher(w io.Writer, algo Algorithm) *TeeHasher {
+	return &TeeHasher{w: w, h: newHasher(algo), algo: algo}
+}
+
+// Write hashes p and forwards it to the underlying writer.
+func (t *TeeHasher) Write(p []byte) (int, error) {
+	if t.w != nil {
+		n, err := t.w.Write(p)
+		_, _ = t.h.Write(p[:n])
+		t.n += int64(n)
+
+		return n, err
+	}
+
+	_, _ = t.h.Write(p)
+	t.n += int64(len(p))
+
+	return len(p), nil
+}
+
+// Reader returns a reader that hashes r's content as it is read.
+func (t *TeeHasher) Reader(r io.Reader) io.Reader {
+	return io.TeeReader(r, t)
+}
+
+// Size returns the number of bytes hashed so far.
+func (t *TeeHasher) Size() int64 { return t.n }
+
+// Sum returns the hex digest of the bytes written so far.
+func (t *TeeHasher) Sum() string {
+	return hex.EncodeToString(t.h.Sum(nil))
+}
+
+// Verify compares the digest with expected (hex, case-insensitive,
+// optionally prefixed "algo:") in constant time.
+func (t *TeeHasher) Verify(expected string) error {
+	want, err := NormalizeDigest(expected, t.algo)
+	if err != nil {
+		return err
+	}
+
+	got := t.Sum()
+	if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+		return fmt.Errorf("%w: expected %s %s, got %s", ErrMismatch, t.algo, want, got)
+	}
+
+	return nil
+}
+
+// NormalizeDigest validates a hex digest for algo and returns it in lower
+// case. An "algo:" prefix (e.g. "sha256:…", as in OCI digests) is accepted.
+func NormalizeDigest(digest string, algo Algorithm) (string, error) {
+	d := strings.TrimSpace(digest)
+
+	if prefix, rest, ok := strings.Cut(d, ":"); ok {
+		if !strings.EqualFold(prefix, string(algo)) {
+			return "", fmt.Errorf("hashutil: digest is %s, want %s", prefix, algo)
+		}
+
+		d = rest
+	}
+
+	d = strings.ToLower(d)
+
+	raw, err := hex.DecodeString(d)
+	if err != nil {
+		return "", fmt.Errorf("hashutil: invalid hex digest %q", digest)
+	}
+
+	if size := newHasher(algo).Size(); len(raw) != size {
+		return "", fmt.Errorf("hashutil: %s digest must be %d hex characters, got %d", algo, size*2, len(d))
+	}
+
+	return d, nil
+}
+
+// ParseSidecar extracts the digest for name from a checksum sidecar file
+// such as foo.tar.gz.sha256 or SHA256SUMS. It accepts a bare digest, or
+// "<digest>  <file>" lines as written by sha256sum (a leading '*' marks
+// binary mode). When several lines are present the one whose file name, or
+// its base name, equals name is used.
+func ParseSidecar(data []byte, name string) (string, error) {
+	var (
+		bare    []string
+		matched string
+	)
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 1 {
+			bare = append(bare, fields[0])
+			continue
+		}
+
+		file := strings.TrimPrefix(strings.TrimSpace(line[len(fields[0]):]), "*")
+		if file == name || path.Base(file) == path.Base(name) {
+			matched = fields[0]
+			break
+		}
+	}
+
+	switch {
+	case matched != "":
+		return matched, nil
+	case len(bare) == 1:
+		return bare[0], nil
+	default:
+		return "", fmt.Errorf("hashutil: no checksum for %s in sidecar", name)
+	}
+}
@@ -0,0 +1,83 @@
+package hashutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestTeeHasher(t *testing.T) {
+	var out bytes.Buffer
+
+	th := NewTeeHasher(&out, SHA256)
+	if _, err := io.Copy(th, strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "hello" || th.Size() != 5 {
+		t.Errorf("forwarded %q (%d bytes)", out.String(), th.Size())
+	}
+
+	if th.Sum() != helloSHA256 {
+		t.Errorf("Sum() = %s", th.Sum())
+	}
+
+	for _, d := range []string{helloSHA256, strings.ToUpper(helloSHA256), "sha256:" + helloSHA256, " " + helloSHA256 + "\n"} {
+		if err := th.Verify(d); err != nil {
+			t.Errorf("Verify(%q) = %v", d, err)
+		}
+	}
+
+	if err := th.Verify(strings.Repeat("0", 64)); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Verify(wrong) = %v, want ErrMismatch", err)
+	}
+
+	for _, d := range []string{"xyz", "abcd", "md5:" + helloSHA256} {
+		if err := th.Verify(d); err == nil || errors.Is(err, ErrMismatch) {
+			t.Errorf("Verify(%q) = %v, want a format error", d, err)
+		}
+	}
+}
+
+func TestTeeHasherReader(t *testing.T) {
+	th := NewTeeHasher(nil, SHA256)
+
+	data, err := io.ReadAll(th.Reader(strings.NewReader("hello")))
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("ReadAll = %q, %v", data, err)
+	}
+
+	if th.Sum() != helloSHA256 {
+		t.Errorf("Sum() = %s", th.Sum())
+	}
+}
+
+func TestParseSidecar(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"bare digest", helloSHA256 + "\n", "tool.tar.gz", helloSHA256, false},
+		{"sha256sum line", helloSHA256 + "  tool.tar.gz\n", "tool.tar.gz", helloSHA256, false},
+		{"binary marker", helloSHA256 + " *tool.tar.gz\n", "tool.tar.gz", helloSHA256, false},
+		{"SHA256SUMS", "aaaa  other.zip\n" + helloSHA256 + "  dist/tool.tar.gz\n", "tool.tar.gz", helloSHA256, false},
+		{"no match", "aaaa  other.zip\n", "tool.tar.gz", "", true},
+		{"empty", "# nothing\n", "tool.tar.gz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSidecar([]byte(tt.data), tt.file)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseSidecar() = %q, %v", got, err)
+			}
+		})
+	}
+}
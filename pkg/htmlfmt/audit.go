@@ -0,0 +1,309 @@
+package htmlfmt
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Severity is how seriously an audit finding is reported.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off" // the rule is not checked
+)
+
+// Rank orders severities: error > warning > info > off.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseSeverity parses "error", "warning" (or "warn"), "info" or "off".
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "error":
+		return SeverityError, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	case "off", "none":
+		return SeverityOff, nil
+	default:
+		return "", fmt.Errorf("unknown severity %q (want error, warning, info or off)", s)
+	}
+}
+
+// Audit rule names.
+const (
+	RuleImgAlt          = "img-alt"
+	RuleHeadingOrder    = "heading-order"
+	RuleHTMLLang        = "html-lang"
+	RuleMetaDescription = "meta-description"
+	RuleDocumentTitle   = "document-title"
+	RuleDuplicateID     = "duplicate-id"
+)
+
+// AuditRule describes one audit check.
+type AuditRule struct {
+	Name        string   `json:"name"`
+	Severity    Severity `json:"severity"`
+	Description string   `json:"description"`
+}
+
+// AuditRules lists the checks Audit runs with their default severities.
+// The document rules (html-lang, meta-description, document-title) only
+// apply to full documents, not to fragments.
+var AuditRules = []AuditRule{
+	{RuleImgAlt, SeverityError, "images and image inputs/areas must have an alt attribute"},
+	{RuleHeadingOrder, SeverityWarning, "heading levels must not skip (h2 followed by h4)"},
+	{RuleHTMLLang, SeverityError, "the html element must declare a lang"},
+	{RuleMetaDescription, SeverityWarning, "the document should have a non-empty meta description"},
+	{RuleDocumentTitle, SeverityError, "the document must have a non-empty title"},
+	{RuleDuplicateID, SeverityError, "id attributes must be unique"},
+}
+
+// AuditIssue is one audit finding. Line is 1-based; document-level
+// findings report the line of the element they concern, or 1.
+type AuditIssue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
+}
+
+// AuditReport is the result of Audit.
+type AuditReport struct {
+	Issues   []AuditIssue `json:"issues"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+	Infos    int          `json:"infos"`
+}
+
+// Max returns the highest severity in the report, or SeverityOff when
+// there are no issues.
+func (r AuditReport) Max() Severity {
+	switch {
+	case r.Errors > 0:
+		return SeverityError
+	case r.Warnings > 0:
+		return SeverityWarning
+	case r.Infos > 0:
+		return SeverityInfo
+	default:
+		return SeverityOff
+	}
+}
+
+// AuditOption configures Audit.
+type AuditOption func(map[string]Severity)
+
+// WithRuleSeverity overrides the severity of a rule; SeverityOff disables
+// it.
+func WithRuleSeverity(rule string, s Severity) AuditOption {
+	return func(m map[string]Severity) { m[rule] = s }
+}
+
+// IsAuditRule reports whether name is one of AuditRules.
+func IsAuditRule(name string) bool {
+	for _, r := range AuditRules {
+		if r.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Audit runs accessibility and SEO checks on HTML: missing alt text,
+// skipped heading levels, duplicate ids and, for full documents, a missing
+// lang, title or meta description. It works on the token stream, so it
+// needs no browser and reports source line numbers.
+func Audit(input string, opts ...AuditOption) AuditReport {
+	severity := make(map[string]Severity, len(AuditRules))
+	for _, r := range AuditRules {
+		severity[r.Name] = r.Severity
+	}
+
+	for _, o := range opts {
+		o(severity)
+	}
+
+	a := &auditor{severity: severity, ids: make(map[string]int)}
+	a.run(input)
+
+	report := AuditReport{Issues: a.issues}
+	if report.Issues == nil {
+		report.Issues = []AuditIssue{}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Line < report.Issues[j].Line
+	})
+
+	for _, is := range report.Issues {
+		switch is.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		case SeverityInfo:
+			report.Infos++
+		}
+	}
+
+	return report
+}
+
+type auditor struct {
+	severity map[string]Severity
+	issues   []AuditIssue
+
+	ids         map[string]int // id -> line of first use
+	lastHeading int
+
+	document    bool // saw <!DOCTYPE>, <html> or <head>
+	htmlLine    int
+	hasLang     bool
+	hasTitle    bool
+	inTitle     bool
+	titleText   strings.Builder
+	description bool
+}
+
+func (a *auditor) report(rule string, line int, format string, args ...any) {
+	s := a.severity[rule]
+	if s == SeverityOff || s == "" {
+		return
+	}
+
+	a.issues = append(a.issues, AuditIssue{Rule: rule, Severity: s, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (a *auditor) run(input string) {
+	z := html.NewTokenizer(strings.NewReader(input))
+	line := 1
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF or a read error; the input is a string, so only EOF
+			break
+		}
+
+		// The tag's own line is where it starts; count its newlines after
+		start := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+
+		tok := z.Token()
+
+		switch tt {
+		case html.DoctypeToken:
+			a.document = true
+		case html.StartTagToken, html.SelfClosingTagToken:
+			a.startTag(tok, start)
+		case html.EndTagToken:
+			if tok.Data == "title" && a.inTitle {
+				a.inTitle = false
+				if strings.TrimSpace(a.titleText.String()) != "" {
+					a.hasTitle = true
+				}
+			}
+		case html.TextToken:
+			if a.inTitle {
+				a.titleText.WriteString(tok.Data)
+			}
+		}
+	}
+
+	if !a.document {
+		return
+	}
+
+	docLine := max(a.htmlLine, 1)
+
+	if !a.hasLang {
+		a.report(RuleHTMLLang, docLine, "<html> has no lang attribute")
+	}
+
+	if !a.hasTitle {
+		a.report(RuleDocumentTitle, docLine, "document has no <title>")
+	}
+
+	if !a.description {
+		a.report(RuleMetaDescription, docLine, `document has no <meta name="description">`)
+	}
+}
+
+func (a *auditor) startTag(tok html.Token, line int) {
+	attrs := make(map[string]string, len(tok.Attr))
+	for _, at := range tok.Attr {
+		if _, ok := attrs[at.Key]; !ok {
+			attrs[at.Key] = at.Val
+		}
+	}
+
+	if id, ok := attrs["id"]; ok && id != "" {
+		if first, dup := a.ids[id]; dup {
+			a.report(RuleDuplicateID, line, "duplicate id %q (first used on line %d)", id, first)
+		} else {
+			a.ids[id] = line
+		}
+	}
+
+	switch tok.Data {
+	case "html":
+		a.document = true
+		a.htmlLine = line
+		a.hasLang = strings.TrimSpace(attrs["lang"]) != ""
+	case "head":
+		a.document = true
+	case "title":
+		a.inTitle = true
+		a.titleText.Reset()
+	case "meta":
+		if strings.EqualFold(attrs["name"], "description") && strings.TrimSpace(attrs["content"]) != "" {
+			a.description = true
+		}
+	case "img", "area":
+		if _, ok := attrs["alt"]; !ok {
+			a.report(RuleImgAlt, line, "<%s> has no alt attribute", tok.Data)
+		}
+	case "input":
+		if _, ok := attrs["alt"]; !ok && strings.EqualFold(attrs["type"], "image") {
+			a.report(RuleImgAlt, line, `<input type="image"> has no alt attribute`)
+		}
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(tok.Data[1] - '0')
+
+		// A fragment may legitimately start below h1
+		if a.lastHeading == 0 && !a.document {
+			a.lastHeading = level - 1
+		}
+
+		if level > a.lastHeading+1 {
+			if a.lastHeading == 0 {
+				a.report(RuleHeadingOrder, line, "first heading is <%s>, expected <h1>", tok.Data)
+			} else {
+				a.report(RuleHeadingOrder, line, "<%s> follows <h%d>, skipping a level", tok.Data, a.lastHeading)
+			}
+		}
+
+		a.lastHeading = level
+	}
+}
@@ -0,0 +1,104 @@
+package htmlfmt
+
+import (
+	"testing"
+)
+
+const auditDoc = `<!DOCTYPE html>
+<html>
+<head>
+<title> </title>
+</head>
+<body>
+<h1 id="top">Title</h1>
+<img src="a.png">
+<img src="b.png" alt="">
+<h3 id="top">Skipped</h3>
+<input type="image" src="go.png">
+<map><area href="/x"></map>
+</body>
+</html>`
+
+func TestAudit(t *testing.T) {
+	report := Audit(auditDoc)
+
+	want := []struct {
+		rule string
+		line int
+	}{
+		{RuleHTMLLang, 2},
+		{RuleDocumentTitle, 2},
+		{RuleMetaDescription, 2},
+		{RuleImgAlt, 8},
+		{RuleDuplicateID, 10},
+		{RuleHeadingOrder, 10},
+		{RuleImgAlt, 11},
+		{RuleImgAlt, 12},
+	}
+
+	if len(report.Issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(report.Issues), len(want), report.Issues)
+	}
+
+	for i, w := range want {
+		if got := report.Issues[i]; got.Rule != w.rule || got.Line != w.line {
+			t.Errorf("issue %d = %s line %d, want %s line %d", i, got.Rule, got.Line, w.rule, w.line)
+		}
+	}
+
+	if report.Errors != 6 || report.Warnings != 2 || report.Max() != SeverityError {
+		t.Errorf("counts = %d errors, %d warnings, max %s", report.Errors, report.Warnings, report.Max())
+	}
+}
+
+func TestAuditCleanDocument(t *testing.T) {
+	doc := `<!doctype html><html lang="en"><head><title>Home</title>
+<meta name="description" content="The home page"></head>
+<body><h1>Home</h1><h2>A</h2><h3>B</h3><h2>C</h2><img src="x.png" alt="x"></body></html>`
+
+	if report := Audit(doc); len(report.Issues) != 0 {
+		t.Errorf("expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestAuditFragment(t *testing.T) {
+	// Fragments skip document rules and may start below h1
+	report := Audit("<h2>Section</h2>\n<h3>Sub</h3>\n<h5>Deep</h5>")
+
+	if len(report.Issues) != 1 || report.Issues[0].Rule != RuleHeadingOrder || report.Issues[0].Line != 3 {
+		t.Errorf("unexpected issues: %+v", report.Issues)
+	}
+}
+
+func TestAuditSeverity(t *testing.T) {
+	report := Audit(auditDoc,
+		WithRuleSeverity(RuleImgAlt, SeverityOff),
+		WithRuleSeverity(RuleDuplicateID, SeverityInfo),
+	)
+
+	for _, is := range report.Issues {
+		if is.Rule == RuleImgAlt {
+			t.Errorf("img-alt should be off: %+v", is)
+		}
+
+		if is.Rule == RuleDuplicateID && is.Severity != SeverityInfo {
+			t.Errorf("duplicate-id severity = %s, want info", is.Severity)
+		}
+	}
+
+	if report.Infos != 1 {
+		t.Errorf("infos = %d, want 1", report.Infos)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for in, want := range map[string]Severity{"error": SeverityError, "WARN": SeverityWarning, "info": SeverityInfo, "off": SeverityOff} {
+		if got, err := ParseSeverity(in); err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %q, %v", in, got, err)
+		}
+	}
+
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(fatal) should fail")
+	}
+}
@@ -1,4 +1,8 @@
 // Package htmlfmt provides HTML formatting, minification, and validation.
 // It supports configurable indentation, attribute sorting, self-closing
-// tag detection, and whitespace collapsing.
+// tag detection, and whitespace collapsing, plus a URL rewriting pass that
+// makes resource URLs absolute, adds CDN prefixes or inlines small assets.
+// Audit runs lightweight accessibility and SEO checks (alt text, heading
+// order, lang, title, meta description, duplicate ids) with configurable
+// severities.
 package htmlfmt
@@ -0,0 +1,230 @@
+package htmlfmt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/inovacc/omni/pkg/mimetype"
+)
+
+// URLRef identifies a resource URL found in a document.
+type URLRef struct {
+	Tag  string // Element name, e.g. "img"
+	Attr string // Attribute name, e.g. "src"
+	URL  string // URL as written in the document (one candidate for srcset)
+}
+
+// RewriteFunc returns the replacement for ref. Returning ref.URL unchanged
+// leaves the attribute untouched.
+type RewriteFunc func(ref URLRef) (string, error)
+
+// urlAttrs lists the attributes holding resource URLs.
+var urlAttrs = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"srcset": true,
+	"poster": true,
+	"action": true,
+}
+
+// RewriteURLs calls fn for every resource URL (href, src, srcset, poster and
+// action attributes) and substitutes the result. Unlike Format, the document
+// is not re-rendered: only tags whose URLs changed are re-serialized, so all
+// other markup, whitespace and comments are preserved byte for byte.
+func RewriteURLs(input string, fn RewriteFunc) (string, error) {
+	z := html.NewTokenizer(strings.NewReader(input))
+
+	var buf bytes.Buffer
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return "", err
+			}
+
+			return buf.String(), nil
+		}
+
+		raw := z.Raw()
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			buf.Write(raw)
+			continue
+		}
+
+		// Raw is only valid until the next call, so copy it before Token()
+		raw = append([]byte(nil), raw...)
+		tok := z.Token()
+
+		changed := false
+
+		for i, a := range tok.Attr {
+			if a.Namespace != "" || !urlAttrs[a.Key] {
+				continue
+			}
+
+			val, err := rewriteAttr(tok.Data, a.Key, a.Val, fn)
+			if err != nil {
+				return "", err
+			}
+
+			if val != a.Val {
+				tok.Attr[i].Val = val
+				changed = true
+			}
+		}
+
+		if changed {
+			buf.WriteString(tok.String())
+		} else {
+			buf.Write(raw)
+		}
+	}
+}
+
+func rewriteAttr(tag, attr, val string, fn RewriteFunc) (string, error) {
+	if attr != "srcset" {
+		return fn(URLRef{Tag: tag, Attr: attr, URL: strings.TrimSpace(val)})
+	}
+
+	// srcset: comma-separated candidates of "URL [descriptor]"
+	candidates := strings.Split(val, ",")
+	for i, c := range candidates {
+		fields := strings.Fields(c)
+		if len(fields) == 0 {
+			continue
+		}
+
+		u, err := fn(URLRef{Tag: tag, Attr: attr, URL: fields[0]})
+		if err != nil {
+			return "", err
+		}
+
+		fields[0] = u
+		candidates[i] = strings.Join(fields, " ")
+	}
+
+	return strings.Join(candidates, ", "), nil
+}
+
+// ChainRewrites applies fns in order, each seeing the previous result.
+func ChainRewrites(fns ...RewriteFunc) RewriteFunc {
+	return func(ref URLRef) (string, error) {
+		for _, fn := range fns {
+			u, err := fn(ref)
+			if err != nil {
+				return "", err
+			}
+
+			ref.URL = u
+		}
+
+		return ref.URL, nil
+	}
+}
+
+// isRelativeURL reports whether u points at a resource of the document's own
+// site, i.e. it is not absolute, protocol-relative, a fragment or a data URI.
+func isRelativeURL(u string) bool {
+	if u == "" || strings.HasPrefix(u, "#") || strings.HasPrefix(u, "//") {
+		return false
+	}
+
+	parsed, err := url.Parse(u)
+
+	return err == nil && parsed.Scheme == ""
+}
+
+// AbsoluteURLs resolves relative URLs against base, e.g. "img/a.png" against
+// "https://example.com/docs/" becomes "https://example.com/docs/img/a.png".
+func AbsoluteURLs(base string) (RewriteFunc, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if !b.IsAbs() {
+		return nil, fmt.Errorf("base URL %q is not absolute", base)
+	}
+
+	return func(ref URLRef) (string, error) {
+		if !isRelativeURL(ref.URL) {
+			return ref.URL, nil
+		}
+
+		u, err := url.Parse(ref.URL)
+		if err != nil {
+			return ref.URL, nil
+		}
+
+		return b.ResolveReference(u).String(), nil
+	}, nil
+}
+
+// PrefixURLs prepends prefix to relative URLs. Unlike AbsoluteURLs,
+// root-relative paths keep the prefix: "/img/a.png" with prefix
+// "https://cdn.example.com/site" becomes "https://cdn.example.com/site/img/a.png".
+// Links between pages (a and area hrefs) are left alone.
+func PrefixURLs(prefix string) RewriteFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return func(ref URLRef) (string, error) {
+		if !isRelativeURL(ref.URL) || ref.Tag == "a" || ref.Tag == "area" || ref.Attr == "action" {
+			return ref.URL, nil
+		}
+
+		return prefix + "/" + strings.TrimPrefix(strings.TrimPrefix(ref.URL, "./"), "/"), nil
+	}
+}
+
+// InlineAssets replaces relative asset URLs (src, srcset, poster and
+// <link href>) by data URIs when the file below root is at most maxSize
+// bytes. Missing files and larger assets are left as they are.
+func InlineAssets(root string, maxSize int64) RewriteFunc {
+	return func(ref URLRef) (string, error) {
+		isAsset := ref.Attr == "src" || ref.Attr == "srcset" || ref.Attr == "poster" ||
+			(ref.Tag == "link" && ref.Attr == "href")
+		if !isAsset || !isRelativeURL(ref.URL) {
+			return ref.URL, nil
+		}
+
+		u, err := url.Parse(ref.URL)
+		if err != nil || u.Path == "" {
+			return ref.URL, nil
+		}
+
+		// Root-relative paths are relative to root too; Join also keeps
+		// "../" from escaping it
+		file := filepath.Join(root, filepath.FromSlash(path.Clean("/"+u.Path)))
+
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() || info.Size() > maxSize {
+			return ref.URL, nil
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		return "data:" + assetMediaType(file, data) + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+	}
+}
+
+func assetMediaType(path string, data []byte) string {
+	if t, ok := mimetype.ByExtension(filepath.Ext(path)); ok && t != mimetype.OctetStream {
+		return t.MIME
+	}
+
+	return mimetype.Detect(data).MIME
+}
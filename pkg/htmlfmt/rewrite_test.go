@@ -0,0 +1,113 @@
+package htmlfmt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewriteURLs(t *testing.T) {
+	input := `<!DOCTYPE html>
+<!-- keep   me -->
+<html><head><link rel="stylesheet" href="css/site.css"></head>
+<body class=x>
+  <a href="/about">About</a> <a href="#top">Top</a>
+  <img src="img/a.png" srcset="img/a.png 1x,img/a@2x.png 2x" alt="A">
+  <script src="https://example.org/lib.js"></script>
+</body></html>`
+
+	abs, err := AbsoluteURLs("https://cdn.example.com/site/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := RewriteURLs(input, abs)
+	if err != nil {
+		t.Fatalf("RewriteURLs() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"<!-- keep   me -->",
+		"<body class=x>",
+		`href="https://cdn.example.com/site/css/site.css"`,
+		`href="https://cdn.example.com/about"`,
+		`<a href="#top">`,
+		`srcset="https://cdn.example.com/site/img/a.png 1x, https://cdn.example.com/site/img/a@2x.png 2x"`,
+		`<script src="https://example.org/lib.js">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRewriteURLsUnchanged(t *testing.T) {
+	input := "<p  class='a'>text</p>\n<img src=x.png>"
+
+	got, err := RewriteURLs(input, func(ref URLRef) (string, error) { return ref.URL, nil })
+	if err != nil || got != input {
+		t.Errorf("RewriteURLs(identity) = %q, %v; want input unchanged", got, err)
+	}
+
+	wantErr := errors.New("boom")
+	if _, err := RewriteURLs(input, func(URLRef) (string, error) { return "", wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("RewriteURLs() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestAbsoluteURLsInvalidBase(t *testing.T) {
+	if _, err := AbsoluteURLs("relative/path"); err == nil {
+		t.Error("AbsoluteURLs() should reject a relative base")
+	}
+}
+
+func TestPrefixURLs(t *testing.T) {
+	fn := PrefixURLs("https://cdn.example.com/site/")
+
+	tests := []struct {
+		ref  URLRef
+		want string
+	}{
+		{URLRef{Tag: "img", Attr: "src", URL: "/img/a.png"}, "https://cdn.example.com/site/img/a.png"},
+		{URLRef{Tag: "script", Attr: "src", URL: "./app.js"}, "https://cdn.example.com/site/app.js"},
+		{URLRef{Tag: "a", Attr: "href", URL: "/about"}, "/about"},
+		{URLRef{Tag: "img", Attr: "src", URL: "data:image/png;base64,AA=="}, "data:image/png;base64,AA=="},
+		{URLRef{Tag: "img", Attr: "src", URL: "//other.example.com/x.png"}, "//other.example.com/x.png"},
+	}
+
+	for _, tt := range tests {
+		if got, _ := fn(tt.ref); got != tt.want {
+			t.Errorf("PrefixURLs(%q) = %q, want %q", tt.ref.URL, got, tt.want)
+		}
+	}
+}
+
+func TestInlineAssets(t *testing.T) {
+	root := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(root, "img"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = os.WriteFile(filepath.Join(root, "img", "dot.svg"), []byte("<svg/>"), 0o644)
+	_ = os.WriteFile(filepath.Join(root, "big.css"), []byte(strings.Repeat("a", 100)), 0o644)
+
+	fn := ChainRewrites(InlineAssets(root, 64), PrefixURLs("https://cdn.example.com"))
+
+	got, err := RewriteURLs(`<img src="img/dot.svg"><link href="/big.css"><a href="img/dot.svg">x</a>`, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<img src="data:image/svg+xml;base64,PHN2Zy8+">`,
+		`<link href="https://cdn.example.com/big.css">`,
+		`<a href="img/dot.svg">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchGenerator produces time-ordered identifiers (ULID, UUIDv7, KSUID)
+// that strictly increase in generation order, including IDs created within
+// the same timestamp tick. The first ID of a tick gets fresh random bits;
+// every further ID in that tick is the previous one with its random bits
+// incremented by one, as described by the ULID monotonic specification and
+// RFC 9562 section 6.2 (method 2).
+//
+// If the clock moves backwards the generator keeps counting from the last
+// tick it used, and if the random bits of a tick are exhausted it moves on
+// to the next tick, so ordering is never broken. A BatchGenerator is safe
+// for concurrent use.
+type BatchGenerator struct {
+	mu   sync.Mutex
+	kind Kind
+	now  func() time.Time
+
+	tick int64 // timestamp of last, in the kind's resolution; -1 before the first ID
+	last [ksuidTotalSize]byte
+}
+
+// NewBatchGenerator returns a monotonic generator for kind.
+func NewBatchGenerator(kind Kind) (*BatchGenerator, error) {
+	switch kind {
+	case KindULID, KindUUIDv7, KindKSUID:
+	default:
+		return nil, fmt.Errorf("idgen: unknown id kind %q (use ulid, uuidv7 or ksuid)", kind)
+	}
+
+	return &BatchGenerator{kind: kind, now: time.Now, tick: -1}, nil
+}
+
+// Kind returns the kind of identifier the generator produces.
+func (g *BatchGenerator) Kind() Kind {
+	return g.kind
+}
+
+// Next returns the next identifier in its canonical string form: uppercase
+// Crockford base32 for ULID, dashed lowercase hex for UUIDv7 and base62 for
+// KSUID. Each string sorts after every string previously returned by g.
+func (g *BatchGenerator) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.next()
+}
+
+// nextBytes is Next for ULID and UUIDv7, returning the raw 16 bytes.
+func (g *BatchGenerator) nextBytes() ([16]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, err := g.next(); err != nil {
+		return [16]byte{}, err
+	}
+
+	return [16]byte(g.last[:16]), nil
+}
+
+// Generate returns n identifiers in strictly increasing order.
+func (g *BatchGenerator) Generate(n int) ([]string, error) {
+	if n <= 0 {
+		n = 1
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ids := make([]string, 0, n)
+
+	for range n {
+		id, err := g.next()
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (g *BatchGenerator) next() (string, error) {
+	tick, err := g.currentTick()
+	if err != nil {
+		return "", err
+	}
+
+	if tick <= g.tick && g.increment() {
+		return g.encode(), nil
+	}
+
+	// A new tick, or the random bits of the last one ran out.
+	tick = max(tick, g.tick+1)
+	if tick > g.maxTick() {
+		return "", fmt.Errorf("idgen: %s timestamp overflow", g.kind)
+	}
+
+	if err := g.fill(tick); err != nil {
+		return "", fmt.Errorf("idgen: %w", err)
+	}
+
+	g.tick = tick
+
+	return g.encode(), nil
+}
+
+// currentTick returns the clock reading in the resolution of the kind.
+func (g *BatchGenerator) currentTick() (int64, error) {
+	now := g.now()
+
+	if g.kind == KindKSUID {
+		s, err := ksuidSecondsFor(now)
+		return int64(s), err
+	}
+
+	ms, err := millisFor(now)
+
+	return int64(ms), err
+}
+
+func (g *BatchGenerator) maxTick() int64 {
+	if g.kind == KindKSUID {
+		return 1<<32 - 1
+	}
+
+	return maxMillis
+}
+
+// fill writes tick and fresh random bits into last.
+func (g *BatchGenerator) fill(tick int64) error {
+	b := g.last[:g.size()]
+
+	if g.kind == KindKSUID {
+		binary.BigEndian.PutUint32(b[:ksuidTimestampLen], uint32(tick))
+	} else {
+		var ts [8]byte
+
+		binary.BigEndian.PutUint64(ts[:], uint64(tick)<<16)
+		copy(b[:ulidTimestampSize], ts[:ulidTimestampSize])
+	}
+
+	if _, err := rand.Read(b[g.randomStart():]); err != nil {
+		return err
+	}
+
+	if g.kind == KindUUIDv7 {
+		b[6] = (b[6] & 0x0f) | 0x70
+		b[8] = (b[8] & 0x3f) | 0x80
+	}
+
+	return nil
+}
+
+// increment adds one to the random bits of last, skipping the fixed UUID
+// version and variant bits. It reports false when every random bit is set.
+func (g *BatchGenerator) increment() bool {
+	b := g.last[:g.size()]
+
+	for i := len(b) - 1; i >= g.randomStart(); i-- {
+		mask := g.randomMask(i)
+		if v := b[i] & mask; v != mask {
+			b[i] = b[i]&^mask | (v + 1)
+			return true
+		}
+
+		b[i] &^= mask
+	}
+
+	return false
+}
+
+// randomMask returns the bits of byte i that hold random data.
+func (g *BatchGenerator) randomMask(i int) byte {
+	if g.kind == KindUUIDv7 {
+		switch i {
+		case 6:
+			return 0x0f
+		case 8:
+			return 0x3f
+		}
+	}
+
+	return 0xff
+}
+
+func (g *BatchGenerator) randomStart() int {
+	if g.kind == KindKSUID {
+		return ksuidTimestampLen
+	}
+
+	return ulidTimestampSize
+}
+
+func (g *BatchGenerator) size() int {
+	if g.kind == KindKSUID {
+		return ksuidTotalSize
+	}
+
+	return 16
+}
+
+func (g *BatchGenerator) encode() string {
+	switch g.kind {
+	case KindKSUID:
+		return KSUID(g.last).String()
+	case KindUUIDv7:
+		return formatUUID([16]byte(g.last[:16]))
+	default:
+		return ULID(g.last[:16]).String()
+	}
+}
@@ -0,0 +1,168 @@
+package idgen
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchGeneratorMonotonic(t *testing.T) {
+	fixed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, kind := range Kinds {
+		t.Run(string(kind), func(t *testing.T) {
+			g, err := NewBatchGenerator(kind)
+			if err != nil {
+				t.Fatalf("NewBatchGenerator() error = %v", err)
+			}
+
+			// Every ID shares one tick, so ordering relies on the increment.
+			g.now = func() time.Time { return fixed }
+
+			ids, err := g.Generate(1000)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			if len(ids) != 1000 {
+				t.Fatalf("Generate() returned %d IDs, want 1000", len(ids))
+			}
+
+			for i := 1; i < len(ids); i++ {
+				if ids[i] <= ids[i-1] {
+					t.Fatalf("ids[%d] = %s not after ids[%d] = %s", i, ids[i], i-1, ids[i-1])
+				}
+			}
+
+			for _, id := range []string{ids[0], ids[len(ids)-1]} {
+				ts, _, err := TimeOf(id, kind)
+				if err != nil {
+					t.Fatalf("TimeOf(%s) error = %v", id, err)
+				}
+
+				if !ts.Equal(fixed) {
+					t.Errorf("TimeOf(%s) = %v, want %v", id, ts, fixed)
+				}
+			}
+
+			if kind == KindUUIDv7 {
+				for _, id := range ids {
+					if id[14] != '7' || !strings.ContainsRune("89ab", rune(id[19])) {
+						t.Fatalf("%s has wrong version or variant", id)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBatchGeneratorClockBackwards(t *testing.T) {
+	g, err := NewBatchGenerator(KindULID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	first, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(-time.Second)
+
+	second, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second <= first {
+		t.Errorf("Next() after clock step back = %s, not after %s", second, first)
+	}
+}
+
+func TestBatchGeneratorExhausted(t *testing.T) {
+	g, err := NewBatchGenerator(KindUUIDv7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.UnixMilli(1709294400000)
+	g.now = func() time.Time { return now }
+
+	first, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set every random bit so the next increment overflows.
+	for i := 6; i < 16; i++ {
+		g.last[i] |= g.randomMask(i)
+	}
+
+	second, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second <= first {
+		t.Errorf("Next() after overflow = %s, not after %s", second, first)
+	}
+
+	ts, err := UUIDTime(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := now.Add(time.Millisecond); !ts.Equal(want) {
+		t.Errorf("timestamp after overflow = %v, want %v", ts, want)
+	}
+}
+
+func TestBatchGeneratorConcurrent(t *testing.T) {
+	g, err := NewBatchGenerator(KindKSUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu  sync.Mutex
+		all []string
+		wg  sync.WaitGroup
+	)
+
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ids, err := g.Generate(250)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			mu.Lock()
+			all = append(all, ids...)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	slices.Sort(all)
+
+	if len(slices.Compact(all)) != 1000 {
+		t.Error("concurrent batches produced duplicate IDs")
+	}
+}
+
+func TestNewBatchGeneratorInvalidKind(t *testing.T) {
+	if _, err := NewBatchGenerator("snowflake"); err == nil {
+		t.Error("NewBatchGenerator(snowflake) error = nil")
+	}
+}
@@ -0,0 +1,209 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind names a time-ordered identifier format.
+type Kind string
+
+const (
+	// KindULID is a ULID (48-bit millisecond timestamp).
+	KindULID Kind = "ulid"
+	// KindUUIDv7 is an RFC 9562 version 7 UUID (48-bit millisecond timestamp).
+	KindUUIDv7 Kind = "uuidv7"
+	// KindKSUID is a KSUID (32-bit second timestamp).
+	KindKSUID Kind = "ksuid"
+)
+
+// Kinds lists the identifier kinds supported by BoundsForRange.
+var Kinds = []Kind{KindULID, KindUUIDv7, KindKSUID}
+
+// ParseKind parses an identifier kind name. "uuid" and "v7" are accepted as
+// aliases of KindUUIDv7.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(s)); k {
+	case KindULID, KindUUIDv7, KindKSUID:
+		return k, nil
+	case "uuid", "v7", "uuid7":
+		return KindUUIDv7, nil
+	default:
+		return "", fmt.Errorf("idgen: unknown id kind %q (use ulid, uuidv7 or ksuid)", s)
+	}
+}
+
+// Bounds holds the smallest and largest identifiers of a kind whose
+// timestamp falls within a time range.
+type Bounds struct {
+	Kind Kind
+	Min  string
+	Max  string
+}
+
+// BoundsForRange returns the minimum and maximum identifier of kind for the
+// inclusive time range [start, end]. Since identifiers of each kind sort in
+// timestamp order, every ID generated in that range satisfies
+// Min <= id <= Max, which makes the bounds usable for database range scans:
+//
+//	WHERE id BETWEEN :min AND :max
+//
+// Timestamps are truncated to the resolution of the kind (milliseconds for
+// ULID and UUIDv7, seconds for KSUID), so IDs created earlier in the same
+// tick as start are included as well.
+func BoundsForRange(start, end time.Time, kind Kind) (Bounds, error) {
+	if end.Before(start) {
+		return Bounds{}, fmt.Errorf("idgen: range end %s is before start %s", end.Format(time.RFC3339Nano), start.Format(time.RFC3339Nano))
+	}
+
+	b := Bounds{Kind: kind}
+
+	switch kind {
+	case KindULID, KindUUIDv7:
+		lo, err := millisFor(start)
+		if err != nil {
+			return Bounds{}, err
+		}
+
+		hi, err := millisFor(end)
+		if err != nil {
+			return Bounds{}, err
+		}
+
+		if kind == KindULID {
+			b.Min, b.Max = ulidBound(lo, 0x00).String(), ulidBound(hi, 0xff).String()
+		} else {
+			b.Min, b.Max = uuidV7Bound(lo, 0x00), uuidV7Bound(hi, 0xff)
+		}
+	case KindKSUID:
+		lo, err := ksuidSecondsFor(start)
+		if err != nil {
+			return Bounds{}, err
+		}
+
+		hi, err := ksuidSecondsFor(end)
+		if err != nil {
+			return Bounds{}, err
+		}
+
+		b.Min, b.Max = ksuidBound(lo, 0x00).String(), ksuidBound(hi, 0xff).String()
+	default:
+		return Bounds{}, fmt.Errorf("idgen: unknown id kind %q (use ulid, uuidv7 or ksuid)", kind)
+	}
+
+	return b, nil
+}
+
+// maxMillis is the largest 48-bit millisecond timestamp.
+const maxMillis = 1<<48 - 1
+
+func millisFor(t time.Time) (uint64, error) {
+	ms := t.UnixMilli()
+	if ms < 0 || ms > maxMillis {
+		return 0, fmt.Errorf("idgen: time %s outside the 48-bit millisecond range", t.Format(time.RFC3339Nano))
+	}
+
+	return uint64(ms), nil
+}
+
+func ksuidSecondsFor(t time.Time) (uint32, error) {
+	s := t.Unix() - ksuidEpoch
+	if s < 0 || s > 1<<32-1 {
+		return 0, fmt.Errorf("idgen: time %s outside the KSUID range", t.Format(time.RFC3339))
+	}
+
+	return uint32(s), nil
+}
+
+// ulidBound returns the ULID with timestamp ms and every random byte set to fill.
+func ulidBound(ms uint64, fill byte) ULID {
+	var u ULID
+
+	binary.BigEndian.PutUint64(u[:8], ms<<16)
+
+	for i := ulidTimestampSize; i < len(u); i++ {
+		u[i] = fill
+	}
+
+	return u
+}
+
+// uuidV7Bound returns the v7 UUID with timestamp ms and every random bit set
+// to fill. The version and variant bits keep their fixed values.
+func uuidV7Bound(ms uint64, fill byte) string {
+	var u [16]byte
+
+	binary.BigEndian.PutUint64(u[:8], ms<<16)
+
+	for i := 6; i < len(u); i++ {
+		u[i] = fill
+	}
+
+	u[6] = (u[6] & 0x0f) | 0x70
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return formatUUID(u)
+}
+
+// ksuidBound returns the KSUID with timestamp s and every payload byte set to fill.
+func ksuidBound(s uint32, fill byte) KSUID {
+	var k KSUID
+
+	binary.BigEndian.PutUint32(k[:ksuidTimestampLen], s)
+
+	for i := ksuidTimestampLen; i < len(k); i++ {
+		k[i] = fill
+	}
+
+	return k
+}
+
+func formatUUID(u [16]byte) string {
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// uuidGregorianOffset is the number of 100ns intervals between the Gregorian
+// epoch (1582-10-15) used by UUID v1 and v6 and the Unix epoch.
+const uuidGregorianOffset = 122192928000000000
+
+// UUIDTime extracts the creation time embedded in a time-based UUID
+// (version 1, 6 or 7). Dashes are optional.
+func UUIDTime(s string) (time.Time, error) {
+	if !IsValidUUID(s) {
+		return time.Time{}, fmt.Errorf("idgen: invalid UUID %q", s)
+	}
+
+	raw, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("idgen: invalid UUID %q", s)
+	}
+
+	switch version := raw[6] >> 4; version {
+	case 7:
+		ms := binary.BigEndian.Uint64(raw[:8]) >> 16
+		return time.UnixMilli(int64(ms)), nil
+	case 1:
+		// time_low | time_mid | version + time_hi
+		ticks := uint64(binary.BigEndian.Uint16(raw[6:8])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(raw[4:6]))<<32 |
+			uint64(binary.BigEndian.Uint32(raw[0:4]))
+
+		return gregorianTime(ticks), nil
+	case 6:
+		// time_high | time_mid | version + time_low
+		ticks := binary.BigEndian.Uint64(raw[:8])>>16<<12 |
+			uint64(binary.BigEndian.Uint16(raw[6:8])&0x0fff)
+
+		return gregorianTime(ticks), nil
+	default:
+		return time.Time{}, fmt.Errorf("idgen: UUID version %d carries no timestamp", version)
+	}
+}
+
+func gregorianTime(ticks uint64) time.Time {
+	unix100ns := int64(ticks) - uuidGregorianOffset
+	return time.Unix(unix100ns/1e7, unix100ns%1e7*100)
+}
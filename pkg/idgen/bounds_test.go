@@ -0,0 +1,124 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBoundsForRange(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	for _, kind := range Kinds {
+		t.Run(string(kind), func(t *testing.T) {
+			b, err := BoundsForRange(start, end, kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if b.Min >= b.Max {
+				t.Fatalf("Min %s not below Max %s", b.Min, b.Max)
+			}
+
+			inside := []time.Time{start, start.Add(30 * time.Minute), end}
+			for _, ts := range inside {
+				id := idAt(t, kind, ts)
+				if id < b.Min || id > b.Max {
+					t.Errorf("%s (%s) outside [%s, %s]", id, ts, b.Min, b.Max)
+				}
+			}
+
+			outside := []time.Time{start.Add(-time.Second), end.Add(time.Second)}
+			for _, ts := range outside {
+				id := idAt(t, kind, ts)
+				if id >= b.Min && id <= b.Max {
+					t.Errorf("%s (%s) inside [%s, %s]", id, ts, b.Min, b.Max)
+				}
+			}
+		})
+	}
+}
+
+// idAt returns an ID of kind created at ts, in its sortable text form.
+func idAt(t *testing.T, kind Kind, ts time.Time) string {
+	t.Helper()
+
+	switch kind {
+	case KindULID:
+		u, err := GenerateULIDWithTime(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return u.String()
+	case KindUUIDv7:
+		ms, _ := millisFor(ts)
+		return uuidV7Bound(ms, 0x5a)
+	default:
+		s, _ := ksuidSecondsFor(ts)
+		return ksuidBound(s, 0x5a).String()
+	}
+}
+
+func TestBoundsForRangeErrors(t *testing.T) {
+	now := time.Now()
+
+	if _, err := BoundsForRange(now, now.Add(-time.Second), KindULID); err == nil {
+		t.Error("expected error for reversed range")
+	}
+
+	if _, err := BoundsForRange(time.Unix(0, 0), now, KindKSUID); err == nil {
+		t.Error("expected error for time before the KSUID epoch")
+	}
+
+	if _, err := BoundsForRange(now, now, Kind("snowflake")); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for in, want := range map[string]Kind{"ULID": KindULID, "uuid": KindUUIDv7, "v7": KindUUIDv7, "ksuid": KindKSUID} {
+		got, err := ParseKind(in)
+		if err != nil || got != want {
+			t.Errorf("ParseKind(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseKind("nanoid"); err == nil {
+		t.Error("ParseKind(nanoid) expected error")
+	}
+}
+
+func TestUUIDTime(t *testing.T) {
+	tests := []struct {
+		uuid string
+		want time.Time
+	}{
+		// RFC 9562 appendix A test vectors
+		{"C232AB00-9414-11EC-B3C8-9F6BDECED846", time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"1EC9414C-232A-6B00-B3C8-9F6BDECED846", time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"017F22E2-79B0-7CC3-98C4-DC0C0C07398F", time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := UUIDTime(tt.uuid)
+		if err != nil {
+			t.Fatalf("UUIDTime(%s): %v", tt.uuid, err)
+		}
+
+		if !got.Equal(tt.want) {
+			t.Errorf("UUIDTime(%s) = %s, want %s", tt.uuid, got.UTC(), tt.want)
+		}
+	}
+
+	v4, _ := GenerateUUID()
+	if _, err := UUIDTime(v4); err == nil || !strings.Contains(err.Error(), "version 4") {
+		t.Errorf("UUIDTime(v4) error = %v", err)
+	}
+
+	v7, _ := GenerateUUID(WithUUIDVersion(V7))
+	if got, err := UUIDTime(v7); err != nil || time.Since(got) > time.Minute {
+		t.Errorf("UUIDTime(v7) = %s, %v", got, err)
+	}
+}
@@ -0,0 +1,222 @@
+package idgen
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// coordinatorRequestTimeout bounds how long a Coordinator waits for a
+// client's request line.
+const coordinatorRequestTimeout = 10 * time.Second
+
+// Coordinator leases worker IDs to processes over stream connections, TCP
+// or a unix socket, for hosts or clusters where a shared lock directory is
+// not an option.
+//
+// The protocol is one line each way: the client sends "ACQUIRE" and the
+// coordinator answers "OK <id>" or "ERR <message>". The ID stays leased
+// for as long as the client keeps the connection open and is freed when it
+// closes, including when the client process dies. Leases live in memory
+// only: a restarted coordinator starts with every ID free, so clients must
+// not outlive the coordinator they leased from.
+type Coordinator struct {
+	mu     sync.Mutex
+	leased map[int64]bool
+	conns  map[net.Conn]struct{}
+}
+
+// NewCoordinator returns a Coordinator with every worker ID free.
+func NewCoordinator() *Coordinator {
+	return &Coordinator{
+		leased: make(map[int64]bool),
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+// Leased returns the worker IDs currently leased, in ascending order.
+func (c *Coordinator) Leased() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := make([]int64, 0, len(c.leased))
+	for id := range c.leased {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+// Serve accepts connections on ln until ctx is done, then closes ln and
+// every client connection and returns nil. Any other accept error is
+// returned.
+func (c *Coordinator) Serve(ctx context.Context, ln net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { _ = ln.Close() })
+	defer stop()
+
+	var wg sync.WaitGroup
+
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			c.closeAll()
+
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		c.mu.Lock()
+		c.conns[conn] = struct{}{}
+		c.mu.Unlock()
+
+		wg.Go(func() { c.handle(conn) })
+	}
+}
+
+func (c *Coordinator) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for conn := range c.conns {
+		_ = conn.Close()
+	}
+}
+
+func (c *Coordinator) handle(conn net.Conn) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.conns, conn)
+		c.mu.Unlock()
+
+		_ = conn.Close()
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(coordinatorRequestTimeout))
+
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	if strings.TrimSpace(line) != "ACQUIRE" {
+		_, _ = fmt.Fprintf(conn, "ERR unknown request %q\n", strings.TrimSpace(line))
+		return
+	}
+
+	id, ok := c.lease()
+	if !ok {
+		_, _ = fmt.Fprintf(conn, "ERR all %d worker IDs are leased\n", MaxWorkerID+1)
+		return
+	}
+
+	defer c.free(id)
+
+	if _, err := fmt.Fprintf(conn, "OK %d\n", id); err != nil {
+		return
+	}
+
+	// Hold the lease until the client hangs up.
+	_ = conn.SetReadDeadline(time.Time{})
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// lease marks the lowest free worker ID as leased.
+func (c *Coordinator) lease() (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id := int64(0); id <= MaxWorkerID; id++ {
+		if !c.leased[id] {
+			c.leased[id] = true
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+func (c *Coordinator) free(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.leased, id)
+}
+
+// CoordinatorWorkerID returns a provider that leases worker IDs from the
+// Coordinator listening at address on network, "tcp" or "unix". Each lease
+// holds its own connection, which Release closes.
+func CoordinatorWorkerID(network, address string) WorkerIDProvider {
+	return coordinatorProvider{network: network, address: address}
+}
+
+type coordinatorProvider struct {
+	network, address string
+}
+
+func (p coordinatorProvider) Acquire(ctx context.Context) (*WorkerLease, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, p.network, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("idgen: coordinator: %w", err)
+	}
+
+	// Unblock the exchange below when ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+
+	id, err := p.exchange(conn)
+	if !stop() || err != nil {
+		_ = conn.Close()
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return nil, err
+	}
+
+	return NewWorkerLease(id, conn.Close), nil
+}
+
+func (p coordinatorProvider) exchange(conn net.Conn) (int64, error) {
+	if _, err := io.WriteString(conn, "ACQUIRE\n"); err != nil {
+		return 0, fmt.Errorf("idgen: coordinator: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("idgen: coordinator: %w", err)
+	}
+
+	reply, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+
+	switch reply {
+	case "OK":
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("idgen: coordinator: invalid reply %q", strings.TrimSpace(line))
+		}
+
+		return id, nil
+	case "ERR":
+		return 0, fmt.Errorf("%w: coordinator: %s", ErrNoWorkerID, arg)
+	default:
+		return 0, fmt.Errorf("idgen: coordinator: invalid reply %q", strings.TrimSpace(line))
+	}
+}
@@ -1,4 +1,34 @@
 // Package idgen provides unique identifier generation including UUID v4/v7,
 // ULID, KSUID, Nanoid, and Snowflake IDs. All generators use
 // crypto/rand for secure random bytes and support functional options.
+//
+// BoundsForRange computes the smallest and largest time-ordered ID (ULID,
+// UUIDv7, KSUID) for a time range, for range scans over ID-keyed tables;
+// UUIDTime extracts the timestamp of v1, v6 and v7 UUIDs, ParseULID and
+// ParseKSUID decode the string forms, and TimeOf reads the timestamp of any
+// of them, detecting the kind from the length when it is not given.
+// Parse goes further: it recognizes UUIDs of every version, ULIDs, KSUIDs,
+// Snowflake IDs and NanoIDs by shape and decodes their timestamp, node and
+// clock sequence, worker and sequence, and random bits into an Info.
+//
+// BatchGenerator produces time-ordered IDs that strictly increase in
+// generation order even within one millisecond (or second, for KSUID), by
+// incrementing the random bits of the previous ID instead of drawing new
+// ones, for bulk inserts that must sort by insertion order.
+//
+// OpenSnowflakeGenerator leases its worker ID from a WorkerIDProvider so
+// processes never generate with the same one: StaticWorkerID and
+// EnvWorkerID take it from configuration, FileLockWorkerID locks the lowest
+// free worker-NNNN.lock file in a directory shared by the processes of one
+// host, and CoordinatorWorkerID asks a Coordinator server over TCP or a
+// unix socket, for several hosts. FirstWorkerID chains providers.
+//
+// TypedIDGenerator makes Stripe-style public IDs that carry a type prefix,
+// cus_01h89d1yg0dpkcwar9g3reha97, with a ULID or UUIDv7 body and an
+// optional checksum; its Parse checks the prefix and checksum, and
+// ParseTypedID reads any typed ID and its timestamp.
+//
+// SequenceStore hands out per-namespace sequential numbers persisted in a
+// bbolt file that is locked for each call, so concurrent processes never
+// share a value; FormatSequence renders them as INV-000123.
 package idgen
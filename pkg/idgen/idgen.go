@@ -416,6 +416,7 @@ type SnowflakeGenerator struct {
 	workerID int64
 	sequence int64
 	lastTime int64
+	lease    *WorkerLease // set by OpenSnowflakeGenerator
 }
 
 // NewSnowflakeGenerator creates a new Snowflake generator with the given worker ID (0-1023).
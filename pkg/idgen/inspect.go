@@ -0,0 +1,328 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type names an identifier format recognized by Parse.
+type Type string
+
+const (
+	// TypeUUID is a UUID of any version.
+	TypeUUID Type = "uuid"
+	// TypeULID is a ULID.
+	TypeULID Type = "ulid"
+	// TypeKSUID is a KSUID.
+	TypeKSUID Type = "ksuid"
+	// TypeSnowflake is a 64-bit Snowflake ID in decimal.
+	TypeSnowflake Type = "snowflake"
+	// TypeNanoid is a NanoID over the default URL-safe alphabet.
+	TypeNanoid Type = "nanoid"
+	// TypeTyped is a typed ID such as cus_01h89d1yg0dpkcwar9g3reha97.
+	TypeTyped Type = "typed"
+)
+
+// Types lists the identifier formats recognized by Parse, in detection order.
+var Types = []Type{TypeUUID, TypeULID, TypeKSUID, TypeSnowflake, TypeNanoid, TypeTyped}
+
+// ParseType parses an identifier format name.
+func ParseType(s string) (Type, error) {
+	switch t := Type(strings.ToLower(s)); t {
+	case TypeUUID, TypeULID, TypeKSUID, TypeSnowflake, TypeNanoid, TypeTyped:
+		return t, nil
+	default:
+		return "", fmt.Errorf("idgen: unknown id type %q (use uuid, ulid, ksuid, snowflake, nanoid or typed)", s)
+	}
+}
+
+// Info holds the components decoded from an identifier. Fields that do not
+// apply to the identifier's type are left at their zero value.
+type Info struct {
+	ID      string
+	Type    Type
+	Version int    // UUID version (0 for the nil UUID, 15 for the max UUID)
+	Variant string // UUID variant: "RFC 9562", "NCS", "Microsoft" or "reserved"
+
+	Time      time.Time     // embedded creation time; zero when there is none
+	Precision time.Duration // resolution of Time
+
+	Node     string // UUID v1/v6 node, formatted as a MAC address
+	ClockSeq int    // UUID v1/v6 clock sequence
+	Worker   int64  // Snowflake machine bits (datacenter and worker)
+	Sequence int64  // Snowflake per-millisecond sequence
+
+	Random     string // random bits in hex, version and variant bits removed
+	RandomBits int    // number of random bits
+
+	Prefix   string // typed ID type prefix
+	Body     Kind   // typed ID body: ulid or uuidv7
+	Checksum bool   // typed ID carries a checksum
+}
+
+// HasTime reports whether the identifier embeds a creation time.
+func (i Info) HasTime() bool {
+	return !i.Time.IsZero()
+}
+
+// ParseOption configures Parse.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	typ            Type
+	snowflakeEpoch int64 // Unix milliseconds
+}
+
+// WithType skips detection and decodes the identifier as t, for formats
+// that cannot be told apart, such as a NanoID of non-default length.
+func WithType(t Type) ParseOption {
+	return func(c *parseConfig) { c.typ = t }
+}
+
+// WithSnowflakeEpoch sets the epoch of Snowflake IDs. The default is the
+// epoch of GenerateSnowflake, 2020-01-01 UTC; Twitter uses 2010-11-04
+// 01:42:54.657 UTC and Discord 2015-01-01 UTC.
+func WithSnowflakeEpoch(t time.Time) ParseOption {
+	return func(c *parseConfig) { c.snowflakeEpoch = t.UnixMilli() }
+}
+
+// Parse detects the format of id and decodes its components. Detection
+// goes by shape: 36 characters with dashes or 32 hex digits is a UUID, 26
+// Crockford base32 characters a ULID, 27 base62 characters a KSUID, up to
+// 19 decimal digits a Snowflake, 21 URL-safe characters a NanoID, and
+// PREFIX_ followed by 26 or 30 Crockford base32 characters a typed ID.
+func Parse(id string, opts ...ParseOption) (Info, error) {
+	cfg := parseConfig{snowflakeEpoch: snowflakeEpoch}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	typ := cfg.typ
+	if typ == "" {
+		typ = detectType(id)
+		if typ == "" {
+			return Info{}, fmt.Errorf("idgen: cannot tell the type of %q", id)
+		}
+	}
+
+	switch typ {
+	case TypeUUID:
+		return parseUUIDInfo(id)
+	case TypeULID:
+		u, err := ParseULID(id)
+		if err != nil {
+			return Info{}, err
+		}
+
+		return Info{
+			ID:         id,
+			Type:       TypeULID,
+			Time:       u.Timestamp(),
+			Precision:  time.Millisecond,
+			Random:     hex.EncodeToString(u[ulidTimestampSize:]),
+			RandomBits: 80,
+		}, nil
+	case TypeKSUID:
+		k, err := ParseKSUID(id)
+		if err != nil {
+			return Info{}, err
+		}
+
+		return Info{
+			ID:         id,
+			Type:       TypeKSUID,
+			Time:       k.Timestamp(),
+			Precision:  time.Second,
+			Random:     hex.EncodeToString(k[ksuidTimestampLen:]),
+			RandomBits: 128,
+		}, nil
+	case TypeSnowflake:
+		return parseSnowflakeInfo(id, cfg.snowflakeEpoch)
+	case TypeNanoid:
+		if id == "" || strings.Trim(id, defaultNanoidAlphabet) != "" {
+			return Info{}, fmt.Errorf("idgen: invalid NanoID %q: want characters from %s", id, defaultNanoidAlphabet)
+		}
+
+		return Info{ID: id, Type: TypeNanoid, RandomBits: 6 * len(id)}, nil
+	case TypeTyped:
+		return parseTypedInfo(id)
+	default:
+		return Info{}, fmt.Errorf("idgen: unknown id type %q (use uuid, ulid, ksuid, snowflake, nanoid or typed)", typ)
+	}
+}
+
+// detectType returns the format id is shaped like, or "" when none fits.
+func detectType(id string) Type {
+	switch {
+	case len(id) == 36 && IsValidUUID(id) && id[8] == '-' && id[13] == '-' && id[18] == '-' && id[23] == '-':
+		return TypeUUID
+	case len(id) == 32 && IsValidUUID(id):
+		return TypeUUID
+	case len(id) == ulidEncodedSize && isCrockford(id) && crockfordDecode[id[0]] <= 7:
+		return TypeULID
+	case len(id) == ksuidEncodedSize && strings.Trim(id, base62Chars) == "":
+		return TypeKSUID
+	case len(id) > 0 && len(id) <= 19 && strings.Trim(id, "0123456789") == "":
+		return TypeSnowflake
+	case len(id) == defaultNanoidLength && strings.Trim(id, defaultNanoidAlphabet) == "":
+		return TypeNanoid
+	case strings.Contains(id, "_"):
+		if _, err := ParseTypedID(id); err == nil {
+			return TypeTyped
+		}
+	}
+
+	return ""
+}
+
+func isCrockford(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if crockfordDecode[s[i]] == 0xff {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseUUIDInfo(id string) (Info, error) {
+	if !IsValidUUID(id) {
+		return Info{}, fmt.Errorf("idgen: invalid UUID %q", id)
+	}
+
+	raw, err := hex.DecodeString(strings.ReplaceAll(id, "-", ""))
+	if err != nil || len(raw) != 16 {
+		return Info{}, fmt.Errorf("idgen: invalid UUID %q", id)
+	}
+
+	info := Info{ID: id, Type: TypeUUID, Version: int(raw[6] >> 4), Variant: uuidVariant(raw[8])}
+
+	// The nil and max UUIDs carry neither a variant nor any data.
+	if allBytes(raw, 0x00) || allBytes(raw, 0xff) {
+		info.Variant = ""
+		return info, nil
+	}
+
+	if info.Variant != "RFC 9562" {
+		return info, nil
+	}
+
+	switch info.Version {
+	case 1, 6:
+		if info.Time, err = UUIDTime(id); err != nil {
+			return Info{}, err
+		}
+
+		info.Precision = 100 * time.Nanosecond
+		info.ClockSeq = int(binary.BigEndian.Uint16(raw[8:10]) & 0x3fff)
+		info.Node = net.HardwareAddr(raw[10:16]).String()
+	case 7:
+		info.Time = time.UnixMilli(int64(binary.BigEndian.Uint64(raw[:8]) >> 16))
+		info.Precision = time.Millisecond
+		info.Random, info.RandomBits = maskedHex(raw[6:], 0x0f, 0xff, 0x3f)
+	case 4:
+		info.Random, info.RandomBits = maskedHex(raw, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x0f, 0xff, 0x3f)
+	}
+
+	return info, nil
+}
+
+// uuidVariant names the variant encoded in the top bits of octet 8.
+func uuidVariant(b byte) string {
+	switch {
+	case b&0x80 == 0:
+		return "NCS"
+	case b&0xc0 == 0x80:
+		return "RFC 9562"
+	case b&0xe0 == 0xc0:
+		return "Microsoft"
+	default:
+		return "reserved"
+	}
+}
+
+func allBytes(b []byte, v byte) bool {
+	for _, c := range b {
+		if c != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// maskedHex concatenates the bits of b selected by masks (one per leading
+// byte; bytes past the masks are taken whole) and returns them as hex,
+// together with the number of bits.
+func maskedHex(b []byte, masks ...byte) (string, int) {
+	v := new(big.Int)
+	n := 0
+
+	for i, c := range b {
+		mask := byte(0xff)
+		if i < len(masks) {
+			mask = masks[i]
+		}
+
+		for bit := 7; bit >= 0; bit-- {
+			if mask&(1<<bit) == 0 {
+				continue
+			}
+
+			v.Lsh(v, 1)
+			v.SetBit(v, 0, uint(c>>bit&1))
+
+			n++
+		}
+	}
+
+	return fmt.Sprintf("%0*x", (n+3)/4, v), n
+}
+
+func parseTypedInfo(id string) (Info, error) {
+	t, err := ParseTypedID(id)
+	if err != nil {
+		return Info{}, err
+	}
+
+	info := Info{
+		ID:        id,
+		Type:      TypeTyped,
+		Time:      t.Time(),
+		Precision: time.Millisecond,
+		Prefix:    t.Prefix,
+		Body:      t.Kind,
+		Checksum:  t.Checksum,
+	}
+
+	if t.Kind == KindUUIDv7 {
+		info.Version, info.Variant = 7, "RFC 9562"
+		info.Random, info.RandomBits = maskedHex(t.Bytes[6:], 0x0f, 0xff, 0x3f)
+	} else {
+		info.Random, info.RandomBits = hex.EncodeToString(t.Bytes[ulidTimestampSize:]), 80
+	}
+
+	return info, nil
+}
+
+func parseSnowflakeInfo(id string, epoch int64) (Info, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n < 0 {
+		return Info{}, fmt.Errorf("idgen: invalid Snowflake %q: want a non-negative 64-bit integer", id)
+	}
+
+	return Info{
+		ID:        id,
+		Type:      TypeSnowflake,
+		Time:      time.UnixMilli((n >> snowflakeTimestampShift) + epoch),
+		Precision: time.Millisecond,
+		Worker:    (n >> snowflakeWorkerIDShift) & snowflakeMaxWorkerID,
+		Sequence:  n & snowflakeMaxSequence,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ulid := ulidBound(1709294400000, 0xab).String()
+
+	tests := []struct {
+		name  string
+		id    string
+		check func(t *testing.T, info Info)
+	}{
+		{"uuid v7", "018df9e2-b200-7abc-8def-0123456789ab", func(t *testing.T, info Info) {
+			if info.Type != TypeUUID || info.Version != 7 || info.Variant != "RFC 9562" {
+				t.Errorf("got %s v%d %s", info.Type, info.Version, info.Variant)
+			}
+
+			if want := time.UnixMilli(0x018df9e2b200); !info.Time.Equal(want) {
+				t.Errorf("Time = %v, want %v", info.Time, want)
+			}
+
+			// rand_a abc, then rand_b with the variant bits removed
+			if info.RandomBits != 74 || info.Random != "2af0def0123456789ab" {
+				t.Errorf("Random = %s (%d bits)", info.Random, info.RandomBits)
+			}
+		}},
+		{"uuid v4 without dashes", "0f8fad5bd9cb469fa16570867728950e", func(t *testing.T, info Info) {
+			if info.Version != 4 || info.RandomBits != 122 || info.HasTime() {
+				t.Errorf("got v%d, %d bits, time %v", info.Version, info.RandomBits, info.Time)
+			}
+		}},
+		{"uuid v1", "c232ab00-9414-11ec-b3c8-9f6bdeced846", func(t *testing.T, info Info) {
+			want := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+			if info.Version != 1 || !info.Time.Equal(want) {
+				t.Errorf("got v%d at %v, want %v", info.Version, info.Time, want)
+			}
+
+			if info.ClockSeq != 0x33c8 || info.Node != "9f:6b:de:ce:d8:46" {
+				t.Errorf("ClockSeq = %#x, Node = %s", info.ClockSeq, info.Node)
+			}
+		}},
+		{"nil uuid", "00000000-0000-0000-0000-000000000000", func(t *testing.T, info Info) {
+			if info.Version != 0 || info.Variant != "" || info.HasTime() {
+				t.Errorf("got v%d %q", info.Version, info.Variant)
+			}
+		}},
+		{"ulid", ulid, func(t *testing.T, info Info) {
+			if info.Type != TypeULID || !info.Time.Equal(time.UnixMilli(1709294400000)) {
+				t.Errorf("got %s at %v", info.Type, info.Time)
+			}
+
+			if info.Random != strings.Repeat("ab", 10) || info.RandomBits != 80 {
+				t.Errorf("Random = %s (%d bits)", info.Random, info.RandomBits)
+			}
+		}},
+		{"ksuid", "0ujtsYcgvSTl8PAuAdqWYSMnLOv", func(t *testing.T, info Info) {
+			want := time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC)
+			if info.Type != TypeKSUID || !info.Time.Equal(want) || info.Precision != time.Second {
+				t.Errorf("got %s at %v, want %v", info.Type, info.Time, want)
+			}
+
+			if info.Random != "b5a1cd34b5f99d1154fb6853345c9735" {
+				t.Errorf("Random = %s", info.Random)
+			}
+		}},
+		{"snowflake", "5382242279129089", func(t *testing.T, info Info) {
+			ts, worker, seq := ParseSnowflake(5382242279129089)
+			if info.Type != TypeSnowflake || !info.Time.Equal(ts) || info.Worker != worker || info.Sequence != seq {
+				t.Errorf("got %s %v/%d/%d, want %v/%d/%d", info.Type, info.Time, info.Worker, info.Sequence, ts, worker, seq)
+			}
+		}},
+		{"nanoid", "V1StGXR8_Z5jdHi6B-myT", func(t *testing.T, info Info) {
+			if info.Type != TypeNanoid || info.RandomBits != 126 || info.HasTime() {
+				t.Errorf("got %s, %d bits", info.Type, info.RandomBits)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Parse(tt.id)
+			if err != nil {
+				t.Fatalf("Parse(%s) error = %v", tt.id, err)
+			}
+
+			if info.ID != tt.id {
+				t.Errorf("ID = %s, want %s", info.ID, tt.id)
+			}
+
+			tt.check(t, info)
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	// A tweet ID, created 2022-02-08T12:42:27.353Z
+	twitter := time.UnixMilli(1288834974657)
+
+	info, err := Parse("1491029658998247427", WithSnowflakeEpoch(twitter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.UnixMilli(1644324147353); !info.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", info.Time, want)
+	}
+
+	info, err = Parse("abc", WithType(TypeNanoid))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Type != TypeNanoid || info.RandomBits != 18 {
+		t.Errorf("got %s, %d bits", info.Type, info.RandomBits)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		id   string
+		opts []ParseOption
+	}{
+		{"", nil},
+		{"not an id", nil},
+		{"018df9e2-b200-7abc-8def-0123456789", nil},
+		{"99999999999999999999999", nil},
+		{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", []ParseOption{WithType(TypeULID)}},
+		{"abc!", []ParseOption{WithType(TypeNanoid)}},
+		{"9999999999999999999", nil},
+		{"abc", []ParseOption{WithType("guid")}},
+	}
+
+	for _, tt := range tests {
+		if info, err := Parse(tt.id, tt.opts...); err == nil {
+			t.Errorf("Parse(%q) = %+v, want error", tt.id, info)
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for _, typ := range Types {
+		if got, err := ParseType(strings.ToUpper(string(typ))); err != nil || got != typ {
+			t.Errorf("ParseType(%s) = %s, %v", typ, got, err)
+		}
+	}
+
+	if _, err := ParseType("guid"); err == nil {
+		t.Error("ParseType(guid) error = nil")
+	}
+}
@@ -0,0 +1,132 @@
+package idgen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// crockfordDecode maps Crockford base32 characters (either case, with the
+// I/L/O aliases) to their values; invalid characters map to 0xff.
+var crockfordDecode = func() [256]byte {
+	var t [256]byte
+	for i := range t {
+		t[i] = 0xff
+	}
+
+	for i, c := range crockfordAlphabet {
+		t[c] = byte(i)
+		t[c|0x20] = byte(i)
+	}
+
+	t['I'], t['i'], t['L'], t['l'] = 1, 1, 1, 1
+	t['O'], t['o'] = 0, 0
+
+	return t
+}()
+
+// ParseULID decodes the 26-character Crockford base32 form of a ULID.
+// Decoding is case insensitive.
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+
+	if len(s) != ulidEncodedSize {
+		return u, fmt.Errorf("idgen: invalid ULID %q: want %d characters, got %d", s, ulidEncodedSize, len(s))
+	}
+
+	// 26 characters hold 130 bits; the top two must be zero
+	var (
+		acc  uint64
+		bits uint
+		pos  int
+	)
+
+	for i := 0; i < len(s); i++ {
+		v := crockfordDecode[s[i]]
+		if v == 0xff {
+			return ULID{}, fmt.Errorf("idgen: invalid ULID %q: bad character %q", s, s[i])
+		}
+
+		if i == 0 && v > 7 {
+			return ULID{}, fmt.Errorf("idgen: invalid ULID %q: timestamp overflows 48 bits", s)
+		}
+
+		acc = acc<<5 | uint64(v)
+		bits += 5
+
+		if i == 0 {
+			// Drop the two padding bits of the first character
+			bits -= 2
+		}
+
+		for bits >= 8 {
+			bits -= 8
+			u[pos] = byte(acc >> bits)
+			pos++
+		}
+	}
+
+	return u, nil
+}
+
+// ParseKSUID decodes the 27-character base62 form of a KSUID.
+func ParseKSUID(s string) (KSUID, error) {
+	var k KSUID
+
+	if len(s) != ksuidEncodedSize {
+		return k, fmt.Errorf("idgen: invalid KSUID %q: want %d characters, got %d", s, ksuidEncodedSize, len(s))
+	}
+
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(base62Chars, s[i])
+		if v < 0 {
+			return KSUID{}, fmt.Errorf("idgen: invalid KSUID %q: bad character %q", s, s[i])
+		}
+
+		carry := v
+		for j := len(k) - 1; j >= 0; j-- {
+			carry += int(k[j]) * 62
+			k[j] = byte(carry)
+			carry >>= 8
+		}
+
+		if carry != 0 {
+			return KSUID{}, fmt.Errorf("idgen: invalid KSUID %q: value overflows 160 bits", s)
+		}
+	}
+
+	return k, nil
+}
+
+// TimeOf extracts the timestamp embedded in id. KindUUIDv7 accepts any
+// time-based UUID (version 1, 6 or 7). An empty kind is detected from the
+// length of id: 26 characters for a ULID, 27 for a KSUID, 32 or 36 for a
+// UUID.
+func TimeOf(id string, kind Kind) (time.Time, Kind, error) {
+	if kind == "" {
+		switch len(id) {
+		case ulidEncodedSize:
+			kind = KindULID
+		case ksuidEncodedSize:
+			kind = KindKSUID
+		case 32, 36:
+			kind = KindUUIDv7
+		default:
+			return time.Time{}, "", fmt.Errorf("idgen: cannot tell the kind of %q from its length", id)
+		}
+	}
+
+	switch kind {
+	case KindULID:
+		u, err := ParseULID(id)
+		return u.Timestamp(), kind, err
+	case KindKSUID:
+		k, err := ParseKSUID(id)
+		return k.Timestamp(), kind, err
+	case KindUUIDv7:
+		t, err := UUIDTime(id)
+		return t, kind, err
+	default:
+		return time.Time{}, "", fmt.Errorf("idgen: unknown id kind %q (use ulid, uuidv7 or ksuid)", kind)
+	}
+}
@@ -0,0 +1,97 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseULID(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 123e6, time.UTC)
+
+	u, err := GenerateULIDWithTime(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{u.String(), strings.ToLower(u.String())} {
+		got, err := ParseULID(s)
+		if err != nil {
+			t.Fatalf("ParseULID(%s): %v", s, err)
+		}
+
+		if got != u {
+			t.Errorf("ParseULID(%s) = %s, want %s", s, got, u)
+		}
+
+		if !got.Timestamp().Equal(ts) {
+			t.Errorf("Timestamp() = %s, want %s", got.Timestamp(), ts)
+		}
+	}
+
+	// Crockford aliases decode like the digits they stand for
+	alias, err := ParseULID("0IL0000000000000000000000O")
+	if err != nil || alias.String() != "01100000000000000000000000" {
+		t.Errorf("ParseULID(aliases) = %s, %v", alias, err)
+	}
+
+	for _, bad := range []string{"", "01H", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "01HQ0000000000000000000U00"} {
+		if _, err := ParseULID(bad); err == nil {
+			t.Errorf("ParseULID(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestParseKSUID(t *testing.T) {
+	for range 20 {
+		k, err := GenerateKSUID()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ParseKSUID(k.String())
+		if err != nil {
+			t.Fatalf("ParseKSUID(%s): %v", k, err)
+		}
+
+		if got != k {
+			t.Errorf("ParseKSUID(%s) = %x, want %x", k, got, k)
+		}
+	}
+
+	// The largest valid KSUID
+	if _, err := ParseKSUID("aWgEPTl1tmebfsQzFP4bxwgy80V"); err != nil {
+		t.Errorf("ParseKSUID(max): %v", err)
+	}
+
+	for _, bad := range []string{"", "0ujtsYcgvSTl8PAuAdqWYSMnLO", "zzzzzzzzzzzzzzzzzzzzzzzzzzz", "0ujtsYcgvSTl8PAuAdqWYSMnLO!"} {
+		if _, err := ParseKSUID(bad); err == nil {
+			t.Errorf("ParseKSUID(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestTimeOf(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, kind := range Kinds {
+		id := idAt(t, kind, ts)
+
+		got, detected, err := TimeOf(id, "")
+		if err != nil {
+			t.Fatalf("TimeOf(%s): %v", id, err)
+		}
+
+		if detected != kind || !got.Equal(ts) {
+			t.Errorf("TimeOf(%s) = %s, %s, want %s, %s", id, got.UTC(), detected, ts, kind)
+		}
+	}
+
+	if _, _, err := TimeOf("abc", ""); err == nil {
+		t.Error("TimeOf(abc) succeeded")
+	}
+
+	if _, _, err := TimeOf(idAt(t, KindKSUID, ts), KindULID); err == nil {
+		t.Error("TimeOf(ksuid as ulid) succeeded")
+	}
+}
@@ -0,0 +1,245 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultLockTimeout is how long a SequenceStore waits for another process
+// holding the counter database lock.
+const DefaultLockTimeout = 10 * time.Second
+
+var sequenceBucket = []byte("sequences")
+
+// ErrSequenceLocked is returned when the counter database stays locked by
+// another process for longer than the lock timeout.
+var ErrSequenceLocked = errors.New("idgen: sequence database is locked")
+
+// SequenceStore hands out per-namespace sequential numbers persisted in a
+// bbolt database. Every call opens the database with an exclusive file lock
+// and closes it again, so concurrent processes sharing the file never hand
+// out the same number.
+type SequenceStore struct {
+	path    string
+	timeout time.Duration
+}
+
+// SequenceOption configures a SequenceStore.
+type SequenceOption func(*SequenceStore)
+
+// WithLockTimeout sets how long to wait for the database lock.
+func WithLockTimeout(d time.Duration) SequenceOption {
+	return func(s *SequenceStore) { s.timeout = d }
+}
+
+// SequenceCounter is the current value of one namespace.
+type SequenceCounter struct {
+	Namespace string `json:"namespace"`
+	Value     uint64 `json:"value"`
+}
+
+// NewSequenceStore returns a store backed by the database at path, which is
+// created on first use.
+func NewSequenceStore(path string, opts ...SequenceOption) *SequenceStore {
+	s := &SequenceStore{path: path, timeout: DefaultLockTimeout}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// Path returns the database file path.
+func (s *SequenceStore) Path() string {
+	return s.path
+}
+
+// Next increments the namespace counter and returns the new value. The first
+// value of a new namespace is 1.
+func (s *SequenceStore) Next(namespace string) (uint64, error) {
+	values, err := s.NextN(namespace, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return values[0], nil
+}
+
+// NextN reserves n consecutive values of the namespace counter in one
+// transaction and returns them in order.
+func (s *SequenceStore) NextN(namespace string, n int) ([]uint64, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("idgen: sequence count must be positive, got %d", n)
+	}
+
+	var values []uint64
+
+	err := s.update(namespace, func(b *bolt.Bucket) error {
+		cur := decodeCounter(b.Get([]byte(namespace)))
+		if cur > math.MaxUint64-uint64(n) {
+			return fmt.Errorf("idgen: sequence %q overflows", namespace)
+		}
+
+		values = make([]uint64, n)
+		for i := range values {
+			values[i] = cur + uint64(i) + 1
+		}
+
+		return b.Put([]byte(namespace), encodeCounter(values[n-1]))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Current returns the last value handed out for the namespace, or 0 when the
+// namespace has not been used.
+func (s *SequenceStore) Current(namespace string) (uint64, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return 0, err
+	}
+
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	var cur uint64
+
+	err := s.view(func(b *bolt.Bucket) error {
+		cur = decodeCounter(b.Get([]byte(namespace)))
+		return nil
+	})
+
+	return cur, err
+}
+
+// Set sets the namespace counter so that the next value is value+1.
+func (s *SequenceStore) Set(namespace string, value uint64) error {
+	return s.update(namespace, func(b *bolt.Bucket) error {
+		return b.Put([]byte(namespace), encodeCounter(value))
+	})
+}
+
+// Delete removes the namespace counter; the next value starts at 1 again.
+func (s *SequenceStore) Delete(namespace string) error {
+	return s.update(namespace, func(b *bolt.Bucket) error {
+		return b.Delete([]byte(namespace))
+	})
+}
+
+// List returns every namespace counter sorted by namespace.
+func (s *SequenceStore) List() ([]SequenceCounter, error) {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var counters []SequenceCounter
+
+	err := s.view(func(b *bolt.Bucket) error {
+		return b.ForEach(func(k, v []byte) error {
+			counters = append(counters, SequenceCounter{Namespace: string(k), Value: decodeCounter(v)})
+			return nil
+		})
+	})
+
+	return counters, err
+}
+
+func (s *SequenceStore) update(namespace string, fn func(*bolt.Bucket) error) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("idgen: %w", err)
+	}
+
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(sequenceBucket)
+		if err != nil {
+			return err
+		}
+
+		return fn(b)
+	})
+}
+
+func (s *SequenceStore) view(fn func(*bolt.Bucket) error) error {
+	db, err := s.open(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(sequenceBucket)
+		if b == nil {
+			return nil
+		}
+
+		return fn(b)
+	})
+}
+
+func (s *SequenceStore) open(readOnly bool) (*bolt.DB, error) {
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: s.timeout, ReadOnly: readOnly})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, fmt.Errorf("%w: %s", ErrSequenceLocked, s.path)
+		}
+
+		return nil, fmt.Errorf("idgen: open %s: %w", s.path, err)
+	}
+
+	return db, nil
+}
+
+func validateNamespace(namespace string) error {
+	if namespace == "" {
+		return fmt.Errorf("idgen: empty sequence namespace")
+	}
+
+	return nil
+}
+
+func encodeCounter(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func decodeCounter(b []byte) uint64 {
+	if len(b) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(b)
+}
+
+// FormatSequence renders n with prefix, zero padding the number to width
+// digits: FormatSequence(123, "INV-", 6) is "INV-000123".
+func FormatSequence(n uint64, prefix string, width int) string {
+	digits := strconv.FormatUint(n, 10)
+	if pad := width - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	return prefix + digits
+}
@@ -0,0 +1,132 @@
+package idgen
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSequenceStore(t *testing.T) {
+	s := NewSequenceStore(filepath.Join(t.TempDir(), "state", "seq.db"))
+
+	if cur, err := s.Current("inv"); err != nil || cur != 0 {
+		t.Fatalf("Current(new) = %d, %v", cur, err)
+	}
+
+	for want := uint64(1); want <= 3; want++ {
+		got, err := s.Next("inv")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want {
+			t.Errorf("Next() = %d, want %d", got, want)
+		}
+	}
+
+	if got, _ := s.Next("po"); got != 1 {
+		t.Errorf("Next(po) = %d, want 1 (namespaces are independent)", got)
+	}
+
+	values, err := s.NextN("inv", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 3 || values[0] != 4 || values[2] != 6 {
+		t.Errorf("NextN() = %v, want [4 5 6]", values)
+	}
+
+	if err := s.Set("inv", 122); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := s.Next("inv"); got != 123 {
+		t.Errorf("Next() after Set(122) = %d, want 123", got)
+	}
+
+	counters, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(counters) != 2 || counters[0] != (SequenceCounter{"inv", 123}) || counters[1] != (SequenceCounter{"po", 1}) {
+		t.Errorf("List() = %v", counters)
+	}
+
+	if err := s.Delete("inv"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := s.Next("inv"); got != 1 {
+		t.Errorf("Next() after Delete = %d, want 1", got)
+	}
+
+	if _, err := s.Next(""); err == nil {
+		t.Error("Next(\"\") expected error")
+	}
+
+	if _, err := s.NextN("inv", 0); err == nil {
+		t.Error("NextN(0) expected error")
+	}
+}
+
+func TestSequenceStoreConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seq.db")
+
+	const workers, per = 8, 10
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+		wg   sync.WaitGroup
+	)
+
+	for range workers {
+		wg.Go(func() {
+			// Separate stores open the file independently, like separate processes.
+			s := NewSequenceStore(path)
+
+			for range per {
+				v, err := s.Next("job")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				if seen[v] {
+					t.Errorf("value %d handed out twice", v)
+				}
+
+				seen[v] = true
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if len(seen) != workers*per {
+		t.Errorf("got %d distinct values, want %d", len(seen), workers*per)
+	}
+}
+
+func TestFormatSequence(t *testing.T) {
+	tests := []struct {
+		n      uint64
+		prefix string
+		width  int
+		want   string
+	}{
+		{123, "INV-", 6, "INV-000123"},
+		{1234567, "INV-", 6, "INV-1234567"},
+		{7, "", 0, "7"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSequence(tt.n, tt.prefix, tt.width); got != tt.want {
+			t.Errorf("FormatSequence(%d, %q, %d) = %q, want %q", tt.n, tt.prefix, tt.width, got, tt.want)
+		}
+	}
+}
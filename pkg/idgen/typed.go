@@ -0,0 +1,253 @@
+package idgen
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+)
+
+// --- Typed IDs ---
+
+const (
+	typedChecksumSize = 4  // Crockford base32 characters, 20 bits
+	maxTypedPrefix    = 63 // as in the TypeID specification
+)
+
+// TypedID is a public identifier that names what it identifies, like
+// Stripe's cus_ and pi_ IDs: PREFIX_BODY, where PREFIX is a lowercase type
+// tag and BODY is a ULID or UUIDv7 in 26 lowercase Crockford base32
+// characters, optionally followed by a 4-character checksum. Without the
+// checksum the format is that of TypeID, so a UUIDv7 body converts to and
+// from the UUID stored in a database.
+//
+//	cus_01h89d1yg0dpkcwar9g3reha97
+//	inv_01h89d1yg0dpkcwar9g3reha975mwn (with checksum)
+type TypedID struct {
+	Prefix   string
+	Kind     Kind // KindULID or KindUUIDv7
+	Bytes    [16]byte
+	Checksum bool
+}
+
+// String returns the ID in its canonical lowercase form.
+func (t TypedID) String() string {
+	s := t.Prefix + "_" + strings.ToLower(ULID(t.Bytes).String())
+	if t.Checksum {
+		s += typedChecksum(s)
+	}
+
+	return s
+}
+
+// Time returns the creation time embedded in the body.
+func (t TypedID) Time() time.Time {
+	return ULID(t.Bytes).Timestamp()
+}
+
+// UUID returns the body in the 8-4-4-4-12 form of a UUID.
+func (t TypedID) UUID() string {
+	return formatUUID(t.Bytes)
+}
+
+// TypedIDOption configures a TypedIDGenerator.
+type TypedIDOption func(*TypedIDGenerator)
+
+// WithBodyKind sets the kind of the ID body: KindULID (the default) or
+// KindUUIDv7.
+func WithBodyKind(k Kind) TypedIDOption {
+	return func(g *TypedIDGenerator) { g.kind = k }
+}
+
+// WithChecksum appends a checksum to generated IDs and makes Parse require
+// one, so mistyped or truncated IDs are rejected without a lookup.
+func WithChecksum() TypedIDOption {
+	return func(g *TypedIDGenerator) { g.checksum = true }
+}
+
+// WithMonotonic makes IDs strictly increase in generation order, even
+// within one millisecond, as with BatchGenerator.
+func WithMonotonic() TypedIDOption {
+	return func(g *TypedIDGenerator) { g.monotonic = true }
+}
+
+// TypedIDGenerator generates and parses the typed IDs of one prefix. It is
+// safe for concurrent use.
+type TypedIDGenerator struct {
+	prefix    string
+	kind      Kind
+	checksum  bool
+	monotonic bool
+	batch     *BatchGenerator
+}
+
+// NewTypedIDGenerator returns a generator of IDs with the type prefix
+// prefix: 1 to 63 lowercase letters, digits and underscores, starting with
+// a letter and not ending with an underscore.
+func NewTypedIDGenerator(prefix string, opts ...TypedIDOption) (*TypedIDGenerator, error) {
+	if err := validateTypedPrefix(prefix); err != nil {
+		return nil, err
+	}
+
+	g := &TypedIDGenerator{prefix: prefix, kind: KindULID}
+	for _, o := range opts {
+		o(g)
+	}
+
+	if g.kind != KindULID && g.kind != KindUUIDv7 {
+		return nil, fmt.Errorf("idgen: typed ID body must be ulid or uuidv7, not %q", g.kind)
+	}
+
+	if g.monotonic {
+		g.batch, _ = NewBatchGenerator(g.kind)
+	}
+
+	return g, nil
+}
+
+// Prefix returns the type prefix of the generator.
+func (g *TypedIDGenerator) Prefix() string {
+	return g.prefix
+}
+
+// Next returns a new ID.
+func (g *TypedIDGenerator) Next() (TypedID, error) {
+	if g.batch != nil {
+		b, err := g.batch.nextBytes()
+		if err != nil {
+			return TypedID{}, err
+		}
+
+		return g.typedID(b), nil
+	}
+
+	return g.NextWithTime(time.Now())
+}
+
+// NextWithTime returns a new ID with the timestamp t. It ignores
+// WithMonotonic.
+func (g *TypedIDGenerator) NextWithTime(t time.Time) (TypedID, error) {
+	u, err := GenerateULIDWithTime(t)
+	if err != nil {
+		return TypedID{}, err
+	}
+
+	if g.kind == KindUUIDv7 {
+		u[6] = (u[6] & 0x0f) | 0x70 // Version 7
+		u[8] = (u[8] & 0x3f) | 0x80 // Variant RFC 9562
+	}
+
+	return g.typedID(u), nil
+}
+
+// Parse parses s as an ID of this generator: it must have the generator's
+// prefix, a UUIDv7 body if the generator makes those and, if the generator
+// adds checksums, a valid checksum.
+func (g *TypedIDGenerator) Parse(s string) (TypedID, error) {
+	id, err := ParseTypedID(s)
+	if err != nil {
+		return TypedID{}, err
+	}
+
+	if id.Prefix != g.prefix {
+		return TypedID{}, fmt.Errorf("idgen: typed ID %q: prefix %q, want %q", s, id.Prefix, g.prefix)
+	}
+
+	if g.checksum && !id.Checksum {
+		return TypedID{}, fmt.Errorf("idgen: typed ID %q: missing checksum", s)
+	}
+
+	if g.kind == KindUUIDv7 && id.Kind != KindUUIDv7 {
+		return TypedID{}, fmt.Errorf("idgen: typed ID %q: body is not a UUIDv7", s)
+	}
+
+	id.Kind = g.kind
+
+	return id, nil
+}
+
+func (g *TypedIDGenerator) typedID(b [16]byte) TypedID {
+	return TypedID{Prefix: g.prefix, Kind: g.kind, Bytes: b, Checksum: g.checksum}
+}
+
+// ParseTypedID parses any typed ID. The body is decoded case-insensitively
+// and a checksum, if present, is verified. Kind is KindUUIDv7 when the body
+// carries the version and variant bits of a UUIDv7 and KindULID otherwise;
+// a ULID has a 1 in 64 chance of looking like one, which does not change
+// its time.
+func ParseTypedID(s string) (TypedID, error) {
+	i := strings.LastIndexByte(s, '_')
+	if i < 0 {
+		return TypedID{}, fmt.Errorf("idgen: invalid typed ID %q: missing PREFIX_", s)
+	}
+
+	id := TypedID{Prefix: s[:i]}
+	if err := validateTypedPrefix(id.Prefix); err != nil {
+		return TypedID{}, fmt.Errorf("idgen: invalid typed ID %q: %w", s, err)
+	}
+
+	rest := s[i+1:]
+
+	switch len(rest) {
+	case ulidEncodedSize:
+	case ulidEncodedSize + typedChecksumSize:
+		id.Checksum = true
+	default:
+		return TypedID{}, fmt.Errorf("idgen: invalid typed ID %q: body has %d characters, want %d or %d with checksum",
+			s, len(rest), ulidEncodedSize, ulidEncodedSize+typedChecksumSize)
+	}
+
+	u, err := ParseULID(rest[:ulidEncodedSize])
+	if err != nil {
+		return TypedID{}, fmt.Errorf("idgen: invalid typed ID %q: %w", s, err)
+	}
+
+	id.Bytes = u
+
+	if id.Checksum {
+		canonical := id.Prefix + "_" + strings.ToLower(u.String())
+		if !strings.EqualFold(rest[ulidEncodedSize:], typedChecksum(canonical)) {
+			return TypedID{}, fmt.Errorf("idgen: invalid typed ID %q: checksum mismatch", s)
+		}
+	}
+
+	id.Kind = KindULID
+	if u[6]>>4 == 7 && u[8]&0xc0 == 0x80 {
+		id.Kind = KindUUIDv7
+	}
+
+	return id, nil
+}
+
+// typedChecksum returns the top 20 bits of the CRC-32 of s in lowercase
+// Crockford base32.
+func typedChecksum(s string) string {
+	sum := crc32.ChecksumIEEE([]byte(s)) >> 12
+
+	b := make([]byte, typedChecksumSize)
+	for i := typedChecksumSize - 1; i >= 0; i-- {
+		b[i] = crockfordAlphabet[sum&31] | 0x20
+		sum >>= 5
+	}
+
+	return string(b)
+}
+
+func validateTypedPrefix(p string) error {
+	switch {
+	case p == "":
+		return fmt.Errorf("idgen: empty type prefix")
+	case len(p) > maxTypedPrefix:
+		return fmt.Errorf("idgen: type prefix %q is longer than %d characters", p, maxTypedPrefix)
+	case p[0] < 'a' || p[0] > 'z' || p[len(p)-1] == '_':
+		return fmt.Errorf("idgen: type prefix %q must start with a lowercase letter and not end with _", p)
+	}
+
+	for _, c := range p {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return fmt.Errorf("idgen: type prefix %q may only hold lowercase letters, digits and _", p)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedIDRoundTrip(t *testing.T) {
+	at := time.Date(2023, 8, 20, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		kind     Kind
+		checksum bool
+	}{
+		{KindULID, false},
+		{KindULID, true},
+		{KindUUIDv7, false},
+		{KindUUIDv7, true},
+	} {
+		opts := []TypedIDOption{WithBodyKind(tt.kind)}
+		if tt.checksum {
+			opts = append(opts, WithChecksum())
+		}
+
+		g, err := NewTypedIDGenerator("sk_live", opts...)
+		if err != nil {
+			t.Fatalf("NewTypedIDGenerator() error = %v", err)
+		}
+
+		id, err := g.NextWithTime(at)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := id.String()
+
+		wantLen := len("sk_live_") + 26
+		if tt.checksum {
+			wantLen += 4
+		}
+
+		if len(s) != wantLen || s != strings.ToLower(s) || !strings.HasPrefix(s, "sk_live_") {
+			t.Errorf("%s/%v: String() = %q", tt.kind, tt.checksum, s)
+		}
+
+		got, err := g.Parse(s)
+		if err != nil {
+			t.Fatalf("%s/%v: Parse(%q) error = %v", tt.kind, tt.checksum, s, err)
+		}
+
+		if got != id || !got.Time().Equal(at) {
+			t.Errorf("%s/%v: Parse(%q) = %+v, want %+v", tt.kind, tt.checksum, s, got, id)
+		}
+
+		// Any case parses to the same ID
+		if upper, err := ParseTypedID("sk_live_" + strings.ToUpper(s[8:])); err != nil || upper.Bytes != id.Bytes {
+			t.Errorf("%s/%v: uppercase body: %+v, %v", tt.kind, tt.checksum, upper, err)
+		}
+	}
+}
+
+func TestTypedIDUUID(t *testing.T) {
+	// The TypeID specification example
+	id, err := ParseTypedID("prefix_01h455vb4pex5vsknk084sn02q")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id.Kind != KindUUIDv7 || id.UUID() != "01890a5d-ac96-774b-bcce-b302099a8057" {
+		t.Errorf("ParseTypedID() = %+v (%s)", id, id.UUID())
+	}
+}
+
+func TestTypedIDGeneratorParseErrors(t *testing.T) {
+	g, err := NewTypedIDGenerator("inv", WithChecksum())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := "inv_01h89d1yg0dpkcwar9g3reha975mwn"
+	if _, err := g.Parse(s); err != nil {
+		t.Fatalf("Parse(%q) error = %v", s, err)
+	}
+
+	plain := s[:len(s)-4]
+
+	typo := []byte(s)
+	if typo[10] == '0' {
+		typo[10] = '1'
+	} else {
+		typo[10] = '0'
+	}
+
+	for name, bad := range map[string]string{
+		"other prefix":     "cus" + s[3:],
+		"missing checksum": plain,
+		"typo":             string(typo),
+		"truncated":        s[:len(s)-1],
+		"no prefix":        s[4:],
+		"bad character":    plain[:len(plain)-1] + "u",
+	} {
+		if _, err := g.Parse(bad); err == nil {
+			t.Errorf("%s: Parse(%q) succeeded", name, bad)
+		}
+	}
+
+	v7, _ := NewTypedIDGenerator("cus", WithBodyKind(KindUUIDv7))
+	if _, err := v7.Parse("cus_01h455vb4pex5vsknk084sn02q"); err != nil {
+		t.Errorf("UUIDv7 body rejected: %v", err)
+	}
+
+	if _, err := v7.Parse("cus_00000000000000000000000000"); err == nil {
+		t.Error("ULID body accepted by a UUIDv7 generator")
+	}
+}
+
+func TestTypedPrefix(t *testing.T) {
+	for _, p := range []string{"", "Cus", "1cus", "_cus", "cus_", "cus-x", strings.Repeat("a", 64)} {
+		if _, err := NewTypedIDGenerator(p); err == nil {
+			t.Errorf("NewTypedIDGenerator(%q) succeeded", p)
+		}
+	}
+
+	if _, err := NewTypedIDGenerator("cus", WithBodyKind(KindKSUID)); err == nil {
+		t.Error("KSUID body accepted")
+	}
+}
+
+func TestTypedIDMonotonic(t *testing.T) {
+	g, err := NewTypedIDGenerator("evt", WithMonotonic(), WithBodyKind(KindUUIDv7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prev := ""
+
+	for range 1000 {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := id.String()
+		if s <= prev {
+			t.Fatalf("%q does not sort after %q", s, prev)
+		}
+
+		if _, err := g.Parse(s); err != nil {
+			t.Fatal(err)
+		}
+
+		prev = s
+	}
+}
+
+func TestParseDetectsTyped(t *testing.T) {
+	info, err := Parse("inv_01h89d1yg0dpkcwar9g3reha975mwn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Type != TypeTyped || info.Prefix != "inv" || !info.Checksum || info.Body != KindULID || info.RandomBits != 80 {
+		t.Errorf("Parse() = %+v", info)
+	}
+
+	if !info.Time.Equal(time.Date(2023, 8, 20, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("time = %s", info.Time)
+	}
+
+	// A NanoID may hold an underscore too
+	if info, err := Parse("V1StGXR8_Z5jdHi6B-myT"); err != nil || info.Type != TypeNanoid {
+		t.Errorf("Parse(nanoid) = %+v, %v", info, err)
+	}
+}
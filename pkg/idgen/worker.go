@@ -0,0 +1,237 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/inovacc/omni/pkg/flock"
+)
+
+// MaxWorkerID is the largest Snowflake worker ID.
+const MaxWorkerID = snowflakeMaxWorkerID
+
+// DefaultWorkerIDEnv is the environment variable read by EnvWorkerID when
+// no name is given.
+const DefaultWorkerIDEnv = "OMNI_SNOWFLAKE_WORKER_ID"
+
+// ErrNoWorkerID is returned when a provider has no worker ID to hand out:
+// every ID is leased, or the environment variable is unset.
+var ErrNoWorkerID = errors.New("idgen: no worker ID available")
+
+// WorkerIDProvider hands out Snowflake worker IDs. Two leases that are held
+// at the same time never carry the same ID, as far as the provider can
+// tell: a file lock provider covers the processes sharing its directory, a
+// coordinator covers its clients, and a static or environment provider
+// trusts its configuration.
+type WorkerIDProvider interface {
+	Acquire(ctx context.Context) (*WorkerLease, error)
+}
+
+// WorkerLease is a worker ID held until Release.
+type WorkerLease struct {
+	ID int64
+
+	once    sync.Once
+	release func() error
+	err     error
+}
+
+// NewWorkerLease returns a lease on id that calls release, which may be
+// nil, the first time it is released. It is meant for WorkerIDProvider
+// implementations.
+func NewWorkerLease(id int64, release func() error) *WorkerLease {
+	return &WorkerLease{ID: id, release: release}
+}
+
+// Release gives the worker ID back. Calling it again is a no-op that
+// returns the first result.
+func (l *WorkerLease) Release() error {
+	l.once.Do(func() {
+		if l.release != nil {
+			l.err = l.release()
+		}
+	})
+
+	return l.err
+}
+
+func checkWorkerID(id int64) error {
+	if id < 0 || id > MaxWorkerID {
+		return fmt.Errorf("idgen: worker ID %d out of range 0-%d", id, MaxWorkerID)
+	}
+
+	return nil
+}
+
+// StaticWorkerID returns a provider that always hands out id.
+func StaticWorkerID(id int64) WorkerIDProvider {
+	return staticProvider(id)
+}
+
+type staticProvider int64
+
+func (p staticProvider) Acquire(context.Context) (*WorkerLease, error) {
+	if err := checkWorkerID(int64(p)); err != nil {
+		return nil, err
+	}
+
+	return NewWorkerLease(int64(p), nil), nil
+}
+
+// EnvWorkerID returns a provider that reads the worker ID from the
+// environment variable name, or DefaultWorkerIDEnv when name is empty. It
+// returns ErrNoWorkerID when the variable is unset or empty, so it can sit
+// in front of another provider in FirstWorkerID.
+func EnvWorkerID(name string) WorkerIDProvider {
+	if name == "" {
+		name = DefaultWorkerIDEnv
+	}
+
+	return envProvider(name)
+}
+
+type envProvider string
+
+func (p envProvider) Acquire(context.Context) (*WorkerLease, error) {
+	s := strings.TrimSpace(os.Getenv(string(p)))
+	if s == "" {
+		return nil, fmt.Errorf("%w: $%s is not set", ErrNoWorkerID, string(p))
+	}
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("idgen: $%s: invalid worker ID %q", string(p), s)
+	}
+
+	if id < 0 || id > MaxWorkerID {
+		return nil, fmt.Errorf("idgen: $%s: worker ID %d out of range 0-%d", string(p), id, MaxWorkerID)
+	}
+
+	return NewWorkerLease(id, nil), nil
+}
+
+// FileLockWorkerID returns a provider that leases the lowest worker ID
+// whose lock file, dir/worker-NNNN.lock, no other process holds. The lock
+// is held until the lease is released or the process exits, so processes
+// on one host that share dir never run with the same ID. The directory is
+// created if needed.
+func FileLockWorkerID(dir string) WorkerIDProvider {
+	return fileLockProvider(dir)
+}
+
+type fileLockProvider string
+
+func (p fileLockProvider) Acquire(ctx context.Context) (*WorkerLease, error) {
+	dir := string(p)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("idgen: worker lock directory: %w", err)
+	}
+
+	for id := int64(0); id <= MaxWorkerID; id++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		lock := flock.New(filepath.Join(dir, fmt.Sprintf("worker-%04d.lock", id)))
+
+		ok, err := lock.TryLock()
+		if err != nil {
+			return nil, fmt.Errorf("idgen: worker lock: %w", err)
+		}
+
+		if ok {
+			return NewWorkerLease(id, lock.Unlock), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: all %d IDs in %s are locked", ErrNoWorkerID, MaxWorkerID+1, dir)
+}
+
+// FirstWorkerID returns a provider that tries each provider in turn and
+// uses the first lease it gets. A provider failing with ErrNoWorkerID
+// passes on to the next; any other error stops the search.
+func FirstWorkerID(providers ...WorkerIDProvider) WorkerIDProvider {
+	return firstProvider(providers)
+}
+
+type firstProvider []WorkerIDProvider
+
+func (p firstProvider) Acquire(ctx context.Context) (*WorkerLease, error) {
+	err := ErrNoWorkerID
+
+	for _, provider := range p {
+		var lease *WorkerLease
+		if lease, err = provider.Acquire(ctx); err == nil {
+			return lease, nil
+		}
+
+		if !errors.Is(err, ErrNoWorkerID) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// SnowflakeOption configures OpenSnowflakeGenerator.
+type SnowflakeOption func(*snowflakeConfig)
+
+type snowflakeConfig struct {
+	provider WorkerIDProvider
+}
+
+// WithWorkerID uses the fixed worker ID id.
+func WithWorkerID(id int64) SnowflakeOption {
+	return func(c *snowflakeConfig) { c.provider = StaticWorkerID(id) }
+}
+
+// WithWorkerIDProvider leases the worker ID from p.
+func WithWorkerIDProvider(p WorkerIDProvider) SnowflakeOption {
+	return func(c *snowflakeConfig) { c.provider = p }
+}
+
+// OpenSnowflakeGenerator returns a generator whose worker ID is leased from
+// the configured provider, worker 0 when none is given. Close releases the
+// lease; the generator must not be used afterwards.
+func OpenSnowflakeGenerator(ctx context.Context, opts ...SnowflakeOption) (*SnowflakeGenerator, error) {
+	cfg := snowflakeConfig{provider: StaticWorkerID(0)}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	lease, err := cfg.provider.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWorkerID(lease.ID); err != nil {
+		_ = lease.Release()
+		return nil, err
+	}
+
+	g := NewSnowflakeGenerator(lease.ID)
+	g.lease = lease
+
+	return g, nil
+}
+
+// WorkerID returns the generator's worker ID.
+func (g *SnowflakeGenerator) WorkerID() int64 {
+	return g.workerID
+}
+
+// Close releases the worker ID lease of a generator opened with
+// OpenSnowflakeGenerator. It is a no-op for other generators.
+func (g *SnowflakeGenerator) Close() error {
+	if g.lease == nil {
+		return nil
+	}
+
+	return g.lease.Release()
+}
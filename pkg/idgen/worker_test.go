@@ -0,0 +1,198 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStaticAndEnvWorkerID(t *testing.T) {
+	ctx := context.Background()
+
+	lease, err := StaticWorkerID(42).Acquire(ctx)
+	if err != nil || lease.ID != 42 {
+		t.Fatalf("StaticWorkerID(42) = %v, %v", lease, err)
+	}
+
+	if _, err := StaticWorkerID(MaxWorkerID + 1).Acquire(ctx); err == nil {
+		t.Error("StaticWorkerID(1024) error = nil")
+	}
+
+	t.Setenv(DefaultWorkerIDEnv, " 7 ")
+
+	if lease, err := EnvWorkerID("").Acquire(ctx); err != nil || lease.ID != 7 {
+		t.Errorf("EnvWorkerID() = %v, %v", lease, err)
+	}
+
+	for _, v := range []string{"seven", "-1", "4096"} {
+		t.Setenv(DefaultWorkerIDEnv, v)
+
+		if _, err := EnvWorkerID("").Acquire(ctx); err == nil || errors.Is(err, ErrNoWorkerID) {
+			t.Errorf("EnvWorkerID() with %q error = %v, want invalid value", v, err)
+		}
+	}
+
+	if _, err := EnvWorkerID("OMNI_TEST_UNSET_WORKER").Acquire(ctx); !errors.Is(err, ErrNoWorkerID) {
+		t.Errorf("EnvWorkerID() unset error = %v, want ErrNoWorkerID", err)
+	}
+}
+
+func TestFileLockWorkerID(t *testing.T) {
+	ctx := context.Background()
+	p := FileLockWorkerID(filepath.Join(t.TempDir(), "workers"))
+
+	first, err := p.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := p.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.ID != 0 || second.ID != 1 {
+		t.Errorf("IDs = %d, %d, want 0, 1", first.ID, second.ID)
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	third, err := p.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if third.ID != 0 {
+		t.Errorf("ID after release = %d, want 0", third.ID)
+	}
+
+	for _, l := range []*WorkerLease{first, second, third} {
+		if err := l.Release(); err != nil {
+			t.Errorf("Release() error = %v", err)
+		}
+	}
+}
+
+func TestFirstWorkerID(t *testing.T) {
+	ctx := context.Background()
+	p := FirstWorkerID(EnvWorkerID("OMNI_TEST_UNSET_WORKER"), StaticWorkerID(3))
+
+	if lease, err := p.Acquire(ctx); err != nil || lease.ID != 3 {
+		t.Errorf("FirstWorkerID() = %v, %v", lease, err)
+	}
+
+	t.Setenv("OMNI_TEST_BAD_WORKER", "x")
+
+	p = FirstWorkerID(EnvWorkerID("OMNI_TEST_BAD_WORKER"), StaticWorkerID(3))
+	if _, err := p.Acquire(ctx); err == nil {
+		t.Error("FirstWorkerID() error = nil, want the invalid variable reported")
+	}
+
+	if _, err := FirstWorkerID().Acquire(ctx); !errors.Is(err, ErrNoWorkerID) {
+		t.Errorf("FirstWorkerID() error = %v, want ErrNoWorkerID", err)
+	}
+}
+
+func TestCoordinator(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewCoordinator()
+	done := make(chan error, 1)
+
+	go func() { done <- c.Serve(ctx, ln) }()
+
+	p := CoordinatorWorkerID("tcp", ln.Addr().String())
+
+	a, err := p.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := p.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.ID != 0 || b.ID != 1 {
+		t.Errorf("IDs = %d, %d, want 0, 1", a.ID, b.ID)
+	}
+
+	if err := a.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The coordinator frees the ID once it sees the connection close.
+	deadline := time.Now().Add(5 * time.Second)
+	for !reflect.DeepEqual(c.Leased(), []int64{1}) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Leased() = %v, want [1]", c.Leased())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+
+	if _, err := p.Acquire(context.Background()); err == nil {
+		t.Error("Acquire() after shutdown error = nil")
+	}
+
+	_ = b.Release()
+}
+
+func TestOpenSnowflakeGenerator(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	g1, err := OpenSnowflakeGenerator(ctx, WithWorkerIDProvider(FileLockWorkerID(dir)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g2, err := OpenSnowflakeGenerator(ctx, WithWorkerIDProvider(FileLockWorkerID(dir)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g1.WorkerID() == g2.WorkerID() {
+		t.Errorf("both generators got worker ID %d", g1.WorkerID())
+	}
+
+	id, err := g2.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, worker, _ := ParseSnowflake(id); worker != g2.WorkerID() {
+		t.Errorf("ID worker = %d, want %d", worker, g2.WorkerID())
+	}
+
+	for _, g := range []*SnowflakeGenerator{g1, g2, NewSnowflakeGenerator(5)} {
+		if err := g.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	}
+
+	g, err := OpenSnowflakeGenerator(ctx, WithWorkerID(9))
+	if err != nil || g.WorkerID() != 9 {
+		t.Errorf("OpenSnowflakeGenerator(WithWorkerID(9)) = %v, %v", g, err)
+	}
+
+	if _, err := OpenSnowflakeGenerator(ctx, WithWorkerID(-1)); err == nil {
+		t.Error("OpenSnowflakeGenerator(WithWorkerID(-1)) error = nil")
+	}
+}
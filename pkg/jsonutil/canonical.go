@@ -0,0 +1,233 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonicalize returns the RFC 8785 JSON Canonicalization Scheme (JCS)
+// form of data: no insignificant whitespace, object members sorted by the
+// UTF-16 code units of their names, numbers serialized as ECMAScript
+// doubles and strings with minimal escaping. Equal JSON values always
+// produce identical bytes, which makes the output suitable for hashing
+// and signing. Duplicate object names are rejected, as I-JSON requires.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	if err := canonicalValue(dec, &buf); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid JSON: unexpected data after top-level value")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func canonicalValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("invalid JSON: unexpected end of input")
+		}
+
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			return canonicalArray(dec, buf)
+		}
+
+		return canonicalObject(dec, buf)
+	case string:
+		writeCanonicalString(buf, t)
+	case json.Number:
+		s, err := CanonicalNumber(t.String())
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(s)
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case nil:
+		buf.WriteString("null")
+	}
+
+	return nil
+}
+
+func canonicalArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := canonicalValue(dec, buf); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	buf.WriteByte(']')
+
+	return nil
+}
+
+func canonicalObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	type member struct {
+		key   []uint16
+		name  string
+		value []byte
+	}
+
+	var members []member
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		name := tok.(string)
+
+		var value bytes.Buffer
+		if err := canonicalValue(dec, &value); err != nil {
+			return err
+		}
+
+		members = append(members, member{key: utf16.Encode([]rune(name)), name: name, value: value.Bytes()})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	slices.SortStableFunc(members, func(a, b member) int { return slices.Compare(a.key, b.key) })
+
+	buf.WriteByte('{')
+
+	for i, m := range members {
+		if i > 0 {
+			if m.name == members[i-1].name {
+				return fmt.Errorf("duplicate object member %q", m.name)
+			}
+
+			buf.WriteByte(',')
+		}
+
+		writeCanonicalString(buf, m.name)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// CanonicalNumber formats a JSON number literal the way ECMAScript's
+// Number.prototype.toString does for the nearest IEEE 754 double, as JCS
+// requires: 1E30 becomes 1e+30, 4.50 becomes 4.5 and -0 becomes 0.
+func CanonicalNumber(literal string) (string, error) {
+	f, err := strconv.ParseFloat(literal, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("number %s is not representable as an IEEE 754 double", literal)
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// Shortest round-trip digits d1.d2...dk and decimal exponent
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	k, n := len(digits), e+1
+
+	var s string
+
+	switch {
+	case k <= n && n <= 21:
+		s = digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		s = digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		s = "0." + strings.Repeat("0", -n) + digits
+	default:
+		s = digits[:1]
+		if k > 1 {
+			s += "." + digits[1:]
+		}
+
+		if n-1 >= 0 {
+			s += "e+" + strconv.Itoa(n-1)
+		} else {
+			s += "e-" + strconv.Itoa(1-n)
+		}
+	}
+
+	return sign + s, nil
+}
+
+// writeCanonicalString writes s as a JSON string, escaping only what JCS
+// requires: quote, backslash and control characters.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
@@ -0,0 +1,91 @@
+package jsonutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"whitespace", "{ \"b\" : [ 1 , true , null ] ,\n \"a\" : \"x\" }", `{"a":"x","b":[1,true,null]}`},
+		{"nested", `{"z":{"y":1,"x":[{"b":0,"a":0}]}}`, `{"z":{"x":[{"a":0,"b":0}],"y":1}}`},
+		// RFC 8785 section 3.2.3 sorting example
+		{"utf16 order", `{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`,
+			"{\"\\r\":2,\"1\":4,\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001F600\":5,\"\ufb33\":3}"},
+		{"escapes", `"\u0041\u000f\u2028<>&\/\""`, "\"A\\u000f\u2028<>&/\\\"\""},
+		{"scalar", ` 4.50 `, `4.5`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("Canonicalize() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Canonicalize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{`{"a":1,"a":2}`, `{"a":1} x`, `[1,`, `1e400`, ``} {
+		if _, err := Canonicalize([]byte(bad)); err == nil {
+			t.Errorf("Canonicalize(%q) expected error", bad)
+		}
+	}
+}
+
+func TestCanonicalNumber(t *testing.T) {
+	// Values from RFC 8785 appendix B
+	tests := map[string]string{
+		"0":                              "0",
+		"-0":                             "0",
+		"333333333.33333329":             "333333333.3333333",
+		"1E30":                           "1e+30",
+		"4.50":                           "4.5",
+		"2e-3":                           "0.002",
+		"0.000000000000000000000000001":  "1e-27",
+		"1e21":                           "1e+21",
+		"1e20":                           "100000000000000000000",
+		"0.000001":                       "0.000001",
+		"1e-7":                           "1e-7",
+		"-1.5e-7":                        "-1.5e-7",
+		"9007199254740993":               "9007199254740992",
+		"295147905179352830000":          "295147905179352830000",
+		"1.7976931348623157e308":         "1.7976931348623157e+308",
+		"5e-324":                         "5e-324",
+		"123456789012345680000000000000": "1.2345678901234568e+29",
+	}
+
+	for in, want := range tests {
+		got, err := CanonicalNumber(in)
+		if err != nil || got != want {
+			t.Errorf("CanonicalNumber(%s) = %s, %v; want %s", in, got, err, want)
+		}
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	in := "{\n  // comment, with comma\n  \"url\": \"http://x/*y*/\", /* block\n  */\n  \"list\": [1, 2,],\n}\n"
+
+	got := string(StripJSONC([]byte(in)))
+	want := "{\n" + strings.Repeat(" ", 24) + "\n  \"url\": \"http://x/*y*/\",         \n    \n  \"list\": [1, 2 ] \n}\n"
+
+	if got != want {
+		t.Errorf("StripJSONC() = %q, want %q", got, want)
+	}
+
+	if _, err := Canonicalize([]byte(got)); err != nil {
+		t.Errorf("stripped JSONC does not parse: %v", err)
+	}
+
+	// Escaped quotes inside strings do not end the string
+	if got := string(StripJSONC([]byte(`["a\"//b",]`))); got != `["a\"//b" ]` {
+		t.Errorf("StripJSONC(escaped quote) = %q", got)
+	}
+}
@@ -1,4 +1,8 @@
 // Package jsonutil provides a jq-style JSON query engine. It supports
 // dot-notation field access, array indexing, wildcards, recursive
-// descent, and pipe-based filter chaining on JSON data.
+// descent, and pipe-based filter chaining on JSON data. JSONPath
+// expressions and RFC 6901 JSON Pointers are evaluated on the same parsed
+// values and selected with a Syntax. Canonicalize produces the RFC 8785
+// canonical form used for hashing and signing, and StripJSONC turns JSON
+// with comments and trailing commas into plain JSON.
 package jsonutil
@@ -0,0 +1,60 @@
+package jsonutil
+
+// StripJSONC turns JSONC (JSON with comments, as used by VS Code and
+// tsconfig files) into plain JSON: // line comments, /* block */ comments
+// and trailing commas before } or ] are replaced with spaces. Line breaks
+// are kept, so parse errors still point at the original line and column.
+// String contents are never changed.
+func StripJSONC(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	// Index of the last comma outside strings and comments that has only
+	// whitespace and comments after it, or -1
+	pendingComma := -1
+
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			pendingComma = -1
+
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+
+			for i += 2; i < len(out); i++ {
+				if out[i] == '*' && i+1 < len(out) && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+
+					break
+				}
+
+				if out[i] != '\n' && out[i] != '\r' {
+					out[i] = ' '
+				}
+			}
+		case c == ',':
+			pendingComma = i
+		case c == '}' || c == ']':
+			if pendingComma >= 0 {
+				out[pendingComma] = ' '
+			}
+
+			pendingComma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			pendingComma = -1
+		}
+	}
+
+	return out
+}
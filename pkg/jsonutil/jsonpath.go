@@ -0,0 +1,702 @@
+package jsonutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JSONPath evaluates a JSONPath expression (RFC 9535 / Goessner syntax) on
+// parsed JSON and returns the matched values in document order. Object
+// members are visited in key order so results are deterministic.
+//
+// Supported: $ root, .name, ['name'], [index], [start:end:step], [*], .*,
+// ..descendants, unions ([0,2] or ['a','b']) and filters such as
+// [?(@.price < 10 && @.category == 'fiction')] or [?@.isbn].
+func JSONPath(input any, path string) ([]any, error) {
+	p := &pathParser{src: path}
+
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+
+	return q.nodes(input, input), nil
+}
+
+// pathQuery is a sequence of segments evaluated from the root ($) or, inside
+// filters, from the current node (@).
+type pathQuery struct {
+	relative bool
+	segments []pathSegment
+}
+
+func (q pathQuery) nodes(current, root any) []any {
+	nodes := []any{root}
+	if q.relative {
+		nodes = []any{current}
+	}
+
+	for _, seg := range q.segments {
+		nodes = seg.apply(nodes, root)
+	}
+
+	return nodes
+}
+
+// pathSegment applies its selectors to every input node (child segment) or
+// to every input node and all of its descendants (descendant segment).
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+func (s pathSegment) apply(nodes []any, root any) []any {
+	var out []any
+
+	for _, n := range nodes {
+		targets := []any{n}
+		if s.descendant {
+			targets = descendants(n, targets[:0])
+		}
+
+		for _, t := range targets {
+			for _, sel := range s.selectors {
+				out = sel.apply(t, root, out)
+			}
+		}
+	}
+
+	return out
+}
+
+// descendants appends n and everything below it in document order.
+func descendants(n any, out []any) []any {
+	out = append(out, n)
+
+	for _, child := range children(n) {
+		out = descendants(child, out)
+	}
+
+	return out
+}
+
+// children returns array elements or object values (in key order).
+func children(n any) []any {
+	switch v := n.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = v[k]
+		}
+
+		return out
+	}
+
+	return nil
+}
+
+type selectorKind int
+
+const (
+	selName selectorKind = iota
+	selWildcard
+	selIndex
+	selSlice
+	selFilter
+)
+
+type pathSelector struct {
+	kind   selectorKind
+	name   string
+	index  int
+	slice  [3]*int // start, end, step
+	filter filterExpr
+}
+
+func (s pathSelector) apply(n, root any, out []any) []any {
+	switch s.kind {
+	case selName:
+		if obj, ok := n.(map[string]any); ok {
+			if v, ok := obj[s.name]; ok {
+				out = append(out, v)
+			}
+		}
+	case selWildcard:
+		out = append(out, children(n)...)
+	case selIndex:
+		if arr, ok := n.([]any); ok {
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+
+			if i >= 0 && i < len(arr) {
+				out = append(out, arr[i])
+			}
+		}
+	case selSlice:
+		if arr, ok := n.([]any); ok {
+			out = appendSlice(arr, s.slice, out)
+		}
+	case selFilter:
+		for _, child := range children(n) {
+			if s.filter.test(child, root) {
+				out = append(out, child)
+			}
+		}
+	}
+
+	return out
+}
+
+// appendSlice implements the RFC 9535 array slice selector.
+func appendSlice(arr []any, bounds [3]*int, out []any) []any {
+	n := len(arr)
+
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+
+	if step == 0 {
+		return out
+	}
+
+	normalize := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+
+		if *p < 0 {
+			return n + *p
+		}
+
+		return *p
+	}
+
+	if step > 0 {
+		lower := min(max(normalize(bounds[0], 0), 0), n)
+		upper := min(max(normalize(bounds[1], n), 0), n)
+
+		for i := lower; i < upper; i += step {
+			out = append(out, arr[i])
+		}
+
+		return out
+	}
+
+	upper := min(max(normalize(bounds[0], n-1), -1), n-1)
+	lower := min(max(normalize(bounds[1], -n-1), -1), n-1)
+
+	for i := upper; lower < i; i += step {
+		out = append(out, arr[i])
+	}
+
+	return out
+}
+
+// filterExpr is a logical expression inside [?...].
+type filterExpr interface {
+	test(current, root any) bool
+}
+
+type orExpr struct{ l, r filterExpr }
+
+func (e orExpr) test(c, r any) bool { return e.l.test(c, r) || e.r.test(c, r) }
+
+type andExpr struct{ l, r filterExpr }
+
+func (e andExpr) test(c, r any) bool { return e.l.test(c, r) && e.r.test(c, r) }
+
+type notExpr struct{ e filterExpr }
+
+func (e notExpr) test(c, r any) bool { return !e.e.test(c, r) }
+
+// existsExpr is true when the query selects at least one node.
+type existsExpr struct{ q pathQuery }
+
+func (e existsExpr) test(c, r any) bool { return len(e.q.nodes(c, r)) > 0 }
+
+// operand is a comparable value; ok is false for "Nothing" (a query that
+// does not select exactly one node).
+type operand interface {
+	value(current, root any) (v any, ok bool)
+}
+
+type literalOperand struct{ v any }
+
+func (l literalOperand) value(any, any) (any, bool) { return l.v, true }
+
+type queryOperand struct{ q pathQuery }
+
+func (o queryOperand) value(c, r any) (any, bool) {
+	nodes := o.q.nodes(c, r)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+
+	return nodes[0], true
+}
+
+type cmpExpr struct {
+	op   string
+	l, r operand
+}
+
+func (e cmpExpr) test(c, r any) bool {
+	lv, lok := e.l.value(c, r)
+	rv, rok := e.r.value(c, r)
+
+	switch e.op {
+	case "==":
+		return valuesEqual(lv, lok, rv, rok)
+	case "!=":
+		return !valuesEqual(lv, lok, rv, rok)
+	}
+
+	if !lok || !rok {
+		return false
+	}
+
+	switch l := lv.(type) {
+	case float64:
+		if r, ok := rv.(float64); ok {
+			return compareOrdered(e.op, l, r)
+		}
+	case string:
+		if r, ok := rv.(string); ok {
+			return compareOrdered(e.op, l, r)
+		}
+	}
+
+	return false
+}
+
+func valuesEqual(l any, lok bool, r any, rok bool) bool {
+	if !lok || !rok {
+		return lok == rok
+	}
+
+	return reflect.DeepEqual(l, r)
+}
+
+func compareOrdered[T float64 | string](op string, l, r T) bool {
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+
+	return false
+}
+
+// pathParser is a recursive-descent parser for JSONPath expressions.
+type pathParser struct {
+	src string
+	pos int
+}
+
+func (p *pathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("jsonpath: at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *pathParser) peek(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *pathParser) consume(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+
+	return false
+}
+
+// parseQuery parses "$" or "@" followed by segments.
+func (p *pathParser) parseQuery() (pathQuery, error) {
+	p.skipSpace()
+
+	var q pathQuery
+
+	switch {
+	case p.consume("$"):
+	case p.consume("@"):
+		q.relative = true
+	default:
+		return q, p.errorf("expected '$' or '@'")
+	}
+
+	for {
+		seg, ok, err := p.parseSegment()
+		if err != nil {
+			return q, err
+		}
+
+		if !ok {
+			return q, nil
+		}
+
+		q.segments = append(q.segments, seg)
+	}
+}
+
+func (p *pathParser) parseSegment() (pathSegment, bool, error) {
+	switch {
+	case p.consume(".."):
+		seg := pathSegment{descendant: true}
+
+		switch {
+		case p.peek("["):
+			sels, err := p.parseBracket()
+			if err != nil {
+				return seg, false, err
+			}
+
+			seg.selectors = sels
+		case p.consume("*"):
+			seg.selectors = []pathSelector{{kind: selWildcard}}
+		default:
+			name, err := p.parseMemberName()
+			if err != nil {
+				return seg, false, err
+			}
+
+			seg.selectors = []pathSelector{{kind: selName, name: name}}
+		}
+
+		return seg, true, nil
+	case p.consume("."):
+		if p.consume("*") {
+			return pathSegment{selectors: []pathSelector{{kind: selWildcard}}}, true, nil
+		}
+
+		name, err := p.parseMemberName()
+		if err != nil {
+			return pathSegment{}, false, err
+		}
+
+		return pathSegment{selectors: []pathSelector{{kind: selName, name: name}}}, true, nil
+	case p.peek("["):
+		sels, err := p.parseBracket()
+		if err != nil {
+			return pathSegment{}, false, err
+		}
+
+		return pathSegment{selectors: sels}, true, nil
+	}
+
+	return pathSegment{}, false, nil
+}
+
+func (p *pathParser) parseMemberName() (string, error) {
+	start := p.pos
+
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if r != '_' && r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+
+		p.pos += size
+	}
+
+	if p.pos == start {
+		return "", p.errorf("expected member name")
+	}
+
+	return p.src[start:p.pos], nil
+}
+
+// parseBracket parses "[" selector ("," selector)* "]".
+func (p *pathParser) parseBracket() ([]pathSelector, error) {
+	p.consume("[")
+
+	var sels []pathSelector
+
+	for {
+		p.skipSpace()
+
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+
+		sels = append(sels, sel)
+
+		p.skipSpace()
+
+		if p.consume("]") {
+			return sels, nil
+		}
+
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *pathParser) parseSelector() (pathSelector, error) {
+	switch {
+	case p.consume("*"):
+		return pathSelector{kind: selWildcard}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.parseString()
+		return pathSelector{kind: selName, name: s}, err
+	case p.consume("?"):
+		expr, err := p.parseOr()
+		return pathSelector{kind: selFilter, filter: expr}, err
+	}
+
+	var bounds [3]*int
+
+	for i := range bounds {
+		p.skipSpace()
+
+		if n, ok := p.parseInt(); ok {
+			bounds[i] = &n
+		}
+
+		p.skipSpace()
+
+		if i == 2 || !p.consume(":") {
+			if i == 0 {
+				if bounds[0] == nil {
+					return pathSelector{}, p.errorf("expected selector")
+				}
+
+				return pathSelector{kind: selIndex, index: *bounds[0]}, nil
+			}
+
+			break
+		}
+	}
+
+	return pathSelector{kind: selSlice, slice: bounds}, nil
+}
+
+func (p *pathParser) parseInt() (int, bool) {
+	start := p.pos
+	if p.peek("-") {
+		p.pos++
+	}
+
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+
+	return n, true
+}
+
+func (p *pathParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var b strings.Builder
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.src):
+			esc := p.src[p.pos]
+			p.pos++
+
+			switch esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					return "", p.errorf("invalid \\u escape")
+				}
+
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid \\u escape")
+				}
+
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				b.WriteByte(esc)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *pathParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.skipSpace(); p.consume("||"); p.skipSpace() {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orExpr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *pathParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.skipSpace(); p.consume("&&"); p.skipSpace() {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andExpr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *pathParser) parseUnary() (filterExpr, error) {
+	p.skipSpace()
+
+	if p.peek("!") && !p.peek("!=") {
+		p.pos++
+
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{e}, nil
+	}
+
+	if p.consume("(") {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.skipSpace(); !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+
+		return e, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *pathParser) parseComparison() (filterExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return cmpExpr{op: op, l: left, r: right}, nil
+	}
+
+	q, ok := left.(queryOperand)
+	if !ok {
+		return nil, p.errorf("literal must be compared")
+	}
+
+	return existsExpr{q.q}, nil
+}
+
+func (p *pathParser) parseOperand() (operand, error) {
+	p.skipSpace()
+
+	switch {
+	case p.peek("@") || p.peek("$"):
+		q, err := p.parseQuery()
+		return queryOperand{q}, err
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.parseString()
+		return literalOperand{s}, err
+	case p.consume("true"):
+		return literalOperand{true}, nil
+	case p.consume("false"):
+		return literalOperand{false}, nil
+	case p.consume("null"):
+		return literalOperand{nil}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && strings.ContainsRune("+-.0123456789eE", rune(p.src[p.pos])) {
+		p.pos++
+	}
+
+	f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("expected value")
+	}
+
+	return literalOperand{f}, nil
+}
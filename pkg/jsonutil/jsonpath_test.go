@@ -0,0 +1,109 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const storeJSON = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+      {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 399}
+  }
+}`
+
+func TestJSONPath(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(storeJSON), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$.store.book[0].title", `["Sayings of the Century"]`},
+		{"$['store']['bicycle'].color", `["red"]`},
+		{"$.store.book[-1].author", `["J. R. R. Tolkien"]`},
+		{"$.store.book[*].price", `[8.95,12.99,8.99,22.99]`},
+		{"$.store.book[1:3].price", `[12.99,8.99]`},
+		{"$.store.book[::-2].price", `[22.99,12.99]`},
+		{"$.store.book[0,2].price", `[8.95,8.99]`},
+		{"$..author", `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{"$.store..price", `[399,8.95,12.99,8.99,22.99]`},
+		{"$.store.book[?(@.price<10)].title", `["Sayings of the Century","Moby Dick"]`},
+		{"$.store.book[?@.isbn].price", `[8.99,22.99]`},
+		{"$.store.book[?(@.category == 'fiction' && !(@.price > 20))].title", `["Sword of Honour","Moby Dick"]`},
+		{"$.store.book[?(@.price > $.store.bicycle.price || @.author == \"Nigel Rees\")].price", `[8.95]`},
+		{"$.store.book[9]", `[]`},
+		{"$.store.missing", `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := JSONPath(doc, tt.path)
+			if err != nil {
+				t.Fatalf("JSONPath() error = %v", err)
+			}
+
+			if got == nil {
+				got = []any{}
+			}
+
+			b, _ := json.Marshal(got)
+			if string(b) != tt.want {
+				t.Errorf("JSONPath(%s) = %s, want %s", tt.path, b, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, path := range []string{"store", "$.", "$[", "$['a'", "$[?(@.a <)]", "$[?(1)]", "$.a b"} {
+		if _, err := JSONPath(map[string]any{}, path); err == nil {
+			t.Errorf("JSONPath(%q) should fail", path)
+		}
+	}
+}
+
+func TestApplySyntax(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(storeJSON), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		syntax Syntax
+		query  string
+		want   any
+	}{
+		{SyntaxJQ, ".store.bicycle.color", "red"},
+		{SyntaxJSONPath, "$.store.bicycle.color", "red"},
+		{SyntaxPointer, "/store/bicycle/color", "red"},
+	}
+
+	for _, tt := range tests {
+		got, err := Apply(doc, tt.syntax, tt.query)
+		if err != nil || len(got) != 1 || got[0] != tt.want {
+			t.Errorf("Apply(%s, %q) = %v, %v", tt.syntax, tt.query, got, err)
+		}
+	}
+
+	if _, err := ParseSyntax("xpath"); err == nil {
+		t.Error("ParseSyntax(xpath) should fail")
+	}
+
+	if s, err := ParseSyntax(""); err != nil || s != SyntaxJQ {
+		t.Errorf("ParseSyntax(\"\") = %q, %v", s, err)
+	}
+
+	out, err := QuerySyntax([]byte(storeJSON), SyntaxPointer, "/store/book/2/isbn")
+	if err != nil || string(out) != `"0-553-21311-3"` {
+		t.Errorf("QuerySyntax() = %s, %v", out, err)
+	}
+}
@@ -8,14 +8,60 @@ import (
 	"strings"
 )
 
+// Syntax selects the query language used by Apply and QuerySyntax.
+type Syntax string
+
+const (
+	SyntaxJQ       Syntax = "jq"       // jq-like filters (.a.b[0] | keys)
+	SyntaxJSONPath Syntax = "jsonpath" // JSONPath ($.a.b[?(@.x < 1)])
+	SyntaxPointer  Syntax = "pointer"  // RFC 6901 JSON Pointer (/a/b/0)
+)
+
+// ParseSyntax validates a syntax name; the empty string selects jq.
+func ParseSyntax(name string) (Syntax, error) {
+	switch s := Syntax(strings.ToLower(name)); s {
+	case "":
+		return SyntaxJQ, nil
+	case SyntaxJQ, SyntaxJSONPath, SyntaxPointer:
+		return s, nil
+	}
+
+	return "", fmt.Errorf("unknown query syntax %q (want jq, jsonpath or pointer)", name)
+}
+
+// Apply evaluates query in the given syntax against parsed JSON data. All
+// syntaxes share the result model of ApplyFilter: a list of values.
+func Apply(input any, syntax Syntax, query string) ([]any, error) {
+	switch syntax {
+	case SyntaxJQ, "":
+		return ApplyFilter(input, query)
+	case SyntaxJSONPath:
+		return JSONPath(input, query)
+	case SyntaxPointer:
+		v, err := ResolvePointer(input, query)
+		if err != nil {
+			return nil, err
+		}
+
+		return []any{v}, nil
+	}
+
+	return nil, fmt.Errorf("unknown query syntax %q", syntax)
+}
+
 // Query applies a jq-like filter to raw JSON data and returns the result as JSON bytes.
 func Query(data []byte, filter string) ([]byte, error) {
+	return QuerySyntax(data, SyntaxJQ, filter)
+}
+
+// QuerySyntax is like Query but evaluates query in the given syntax.
+func QuerySyntax(data []byte, syntax Syntax, query string) ([]byte, error) {
 	var v any
 	if err := json.Unmarshal(data, &v); err != nil {
 		return nil, fmt.Errorf("jsonutil: parse error: %w", err)
 	}
 
-	results, err := ApplyFilter(v, filter)
+	results, err := Apply(v, syntax, query)
 	if err != nil {
 		return nil, fmt.Errorf("jsonutil: %w", err)
 	}
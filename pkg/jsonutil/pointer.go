@@ -0,0 +1,102 @@
+package jsonutil
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrPointerNotFound is returned when a JSON Pointer does not resolve.
+var ErrPointerNotFound = errors.New("pointer not found")
+
+// ParsePointer splits an RFC 6901 JSON Pointer into its unescaped reference
+// tokens. Both the string form ("/a/b") and the URI fragment form ("#/a/b")
+// are accepted; "" and "#" refer to the whole document.
+func ParsePointer(ptr string) ([]string, error) {
+	if rest, ok := strings.CutPrefix(ptr, "#"); ok {
+		unescaped, err := url.PathUnescape(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pointer %q: %w", ptr, err)
+		}
+
+		ptr = unescaped
+	}
+
+	if ptr == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid pointer %q: must start with '/'", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		// Order matters: "~01" must become "~1", not "/"
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// ResolvePointer returns the value doc refers to at ptr. Unlike jq paths, a
+// missing member or out-of-range index is an error wrapping
+// ErrPointerNotFound rather than null.
+func ResolvePointer(doc any, ptr string) (any, error) {
+	tokens, err := ParsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+
+	for i, tok := range tokens {
+		at := "/" + strings.Join(tokens[:i+1], "/")
+
+		switch v := current.(type) {
+		case map[string]any:
+			val, ok := v[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPointerNotFound, at)
+			}
+
+			current = val
+		case []any:
+			idx, err := pointerIndex(tok)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", at, err)
+			}
+
+			if idx >= len(v) {
+				return nil, fmt.Errorf("%w: %s (index out of range)", ErrPointerNotFound, at)
+			}
+
+			current = v[idx]
+		default:
+			return nil, fmt.Errorf("%w: %s (cannot index %s)", ErrPointerNotFound, at, typeName(current))
+		}
+	}
+
+	return current, nil
+}
+
+// pointerIndex parses an array reference token: a non-negative decimal
+// without leading zeros. "-" (past the end) never resolves when reading.
+func pointerIndex(tok string) (int, error) {
+	if tok == "-" {
+		return 0, fmt.Errorf("%w: '-' refers past the end of the array", ErrPointerNotFound)
+	}
+
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') || strings.TrimLeft(tok, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	return strconv.Atoi(tok)
+}
+
+func typeName(v any) string {
+	t, _ := filterType(v)
+	return t[0].(string)
+}
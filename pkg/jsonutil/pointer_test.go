@@ -0,0 +1,89 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// rfc6901Doc is the example document from RFC 6901 section 5.
+const rfc6901Doc = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "c%d": 2,
+  "e^f": 3,
+  "g|h": 4,
+  "i\\j": 5,
+  "k\"l": 6,
+  " ": 7,
+  "m~n": 8
+}`
+
+func TestResolvePointerRFC6901(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(rfc6901Doc), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ptr  string
+		want any
+	}{
+		{"", doc},
+		{"/foo", []any{"bar", "baz"}},
+		{"/foo/0", "bar"},
+		{"/", float64(0)},
+		{"/a~1b", float64(1)},
+		{"/c%d", float64(2)},
+		{"/e^f", float64(3)},
+		{"/g|h", float64(4)},
+		{`/i\j`, float64(5)},
+		{`/k"l`, float64(6)},
+		{"/ ", float64(7)},
+		{"/m~0n", float64(8)},
+		{"#/foo/1", "baz"},
+		{"#/c%25d", float64(2)},
+		{"#/%20", float64(7)},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolvePointer(doc, tt.ptr)
+		if err != nil {
+			t.Errorf("ResolvePointer(%q) error = %v", tt.ptr, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ResolvePointer(%q) = %v, want %v", tt.ptr, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePointerErrors(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(rfc6901Doc), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	notFound := []string{"/missing", "/foo/2", "/foo/-", "/foo/0/x"}
+	for _, ptr := range notFound {
+		if _, err := ResolvePointer(doc, ptr); !errors.Is(err, ErrPointerNotFound) {
+			t.Errorf("ResolvePointer(%q) error = %v, want ErrPointerNotFound", ptr, err)
+		}
+	}
+
+	invalid := []string{"foo", "/foo/01", "/foo/x", "#%zz"}
+	for _, ptr := range invalid {
+		_, err := ResolvePointer(doc, ptr)
+		if err == nil || errors.Is(err, ErrPointerNotFound) {
+			t.Errorf("ResolvePointer(%q) error = %v, want syntax error", ptr, err)
+		}
+	}
+
+	tokens, _ := ParsePointer("/~01")
+	if len(tokens) != 1 || tokens[0] != "~1" {
+		t.Errorf("ParsePointer(/~01) = %q, want [~1]", tokens)
+	}
+}
@@ -0,0 +1,17 @@
+// Package mimetype identifies file formats from their content, the way the
+// Unix file command does, and maps file extensions to MIME types.
+//
+// Detect matches the leading bytes of a file against a table of magic
+// signatures (images, audio, video, archives, documents, executables,
+// fonts, databases and more), looks inside zip containers for the formats
+// built on them (docx, xlsx, jar, apk, epub, OpenDocument), and sniffs
+// text for markup, scripts and well-known text formats. Every result has
+// a MIME type and a short human-readable description.
+//
+// ByExtension maps a file extension to its type from a built-in table, so
+// results do not depend on the host's mime.types. DetectFile combines the
+// two, refining plain text and generic zip results by extension.
+//
+// IsBinary reports whether content should be treated as binary: it holds
+// a NUL byte or starts with the signature of a non-text format.
+package mimetype
@@ -0,0 +1,372 @@
+package mimetype
+
+import "strings"
+
+// ByExtension returns the type for a file extension, with or without the
+// leading dot and in any case. It reports false for unknown extensions.
+func ByExtension(ext string) (Type, bool) {
+	if ext == "" {
+		return Type{}, false
+	}
+
+	if ext[0] != '.' {
+		ext = "." + ext
+	}
+
+	found, ok := extensions[strings.ToLower(ext)]
+
+	return found, ok
+}
+
+var extensions = map[string]Type{
+	// Text and markup
+	".txt":         PlainText,
+	".text":        PlainText,
+	".log":         typ("text/plain", "log file", ".log"),
+	".md":          typ("text/markdown", "Markdown document", ".md"),
+	".markdown":    typ("text/markdown", "Markdown document", ".md"),
+	".rst":         typ("text/x-rst", "reStructuredText document", ".rst"),
+	".adoc":        typ("text/asciidoc", "AsciiDoc document", ".adoc"),
+	".org":         typ("text/x-org", "Org-mode document", ".org"),
+	".tex":         typ("application/x-tex", "TeX document", ".tex"),
+	".bib":         typ("text/x-bibtex", "BibTeX bibliography", ".bib"),
+	".html":        typ("text/html", "HTML document", ".html"),
+	".htm":         typ("text/html", "HTML document", ".html"),
+	".xhtml":       typ("application/xhtml+xml", "XHTML document", ".xhtml"),
+	".css":         typ("text/css", "CSS stylesheet", ".css"),
+	".scss":        typ("text/x-scss", "Sass stylesheet", ".scss"),
+	".sass":        typ("text/x-sass", "Sass stylesheet", ".sass"),
+	".less":        typ("text/x-less", "Less stylesheet", ".less"),
+	".xml":         typ("application/xml", "XML document", ".xml"),
+	".xsd":         typ("application/xml", "XML Schema", ".xsd"),
+	".xsl":         typ("application/xslt+xml", "XSLT stylesheet", ".xsl"),
+	".xslt":        typ("application/xslt+xml", "XSLT stylesheet", ".xsl"),
+	".rss":         typ("application/rss+xml", "RSS feed", ".rss"),
+	".atom":        typ("application/atom+xml", "Atom feed", ".atom"),
+	".svg":         typ("image/svg+xml", "SVG Scalable Vector Graphics image", ".svg"),
+	".kml":         typ("application/vnd.google-earth.kml+xml", "KML geographic data", ".kml"),
+	".gpx":         typ("application/gpx+xml", "GPS Exchange Format data", ".gpx"),
+	".plist":       typ("application/x-plist", "property list", ".plist"),
+	".csv":         typ("text/csv", "CSV text", ".csv"),
+	".tsv":         typ("text/tab-separated-values", "TSV text", ".tsv"),
+	".json":        typ("application/json", "JSON data", ".json"),
+	".jsonl":       typ("application/x-ndjson", "JSON Lines text data", ".jsonl"),
+	".ndjson":      typ("application/x-ndjson", "JSON Lines text data", ".jsonl"),
+	".geojson":     typ("application/geo+json", "GeoJSON data", ".geojson"),
+	".ipynb":       typ("application/x-ipynb+json", "Jupyter notebook", ".ipynb"),
+	".webmanifest": typ("application/manifest+json", "Web app manifest", ".webmanifest"),
+	".yaml":        typ("application/yaml", "YAML document", ".yaml"),
+	".yml":         typ("application/yaml", "YAML document", ".yaml"),
+	".toml":        typ("application/toml", "TOML document", ".toml"),
+	".ini":         typ("text/plain", "INI configuration", ".ini"),
+	".cfg":         typ("text/plain", "configuration file", ".cfg"),
+	".conf":        typ("text/plain", "configuration file", ".conf"),
+	".env":         typ("text/plain", "environment file", ".env"),
+	".properties":  typ("text/x-java-properties", "Java properties", ".properties"),
+	".vcf":         typ("text/vcard", "vCard visiting card", ".vcf"),
+	".ics":         typ("text/calendar", "vCalendar calendar file", ".ics"),
+	".vtt":         typ("text/vtt", "WebVTT subtitles", ".vtt"),
+	".srt":         typ("application/x-subrip", "SubRip subtitles", ".srt"),
+	".eml":         typ("message/rfc822", "SMTP mail", ".eml"),
+	".mbox":        typ("application/mbox", "mbox mailbox", ".mbox"),
+	".diff":        typ("text/x-diff", "unified diff output", ".diff"),
+	".patch":       typ("text/x-diff", "unified diff output", ".patch"),
+	".sql":         typ("application/sql", "SQL script", ".sql"),
+	".graphql":     typ("application/graphql", "GraphQL document", ".graphql"),
+	".gql":         typ("application/graphql", "GraphQL document", ".graphql"),
+	".proto":       typ("text/x-protobuf", "Protocol Buffers source", ".proto"),
+	".pem":         typ("application/x-pem-file", "PEM certificate", ".pem"),
+	".crt":         typ("application/x-x509-ca-cert", "X.509 certificate", ".crt"),
+	".cer":         typ("application/pkix-cert", "X.509 certificate", ".cer"),
+	".der":         typ("application/x-x509-ca-cert", "X.509 certificate (DER)", ".der"),
+	".csr":         typ("application/pkcs10", "certificate request", ".csr"),
+	".key":         typ("application/x-pem-file", "private key", ".key"),
+	".p12":         typ("application/x-pkcs12", "PKCS#12 key store", ".p12"),
+	".pfx":         typ("application/x-pkcs12", "PKCS#12 key store", ".p12"),
+	".asc":         typ("application/pgp-keys", "PGP armored data", ".asc"),
+	".gpg":         typ("application/pgp-encrypted", "PGP encrypted data", ".gpg"),
+	".sig":         typ("application/pgp-signature", "PGP signature", ".sig"),
+	".pub":         typ("application/x-ssh-public-key", "public key", ".pub"),
+
+	// Source code
+	".go":         typ("text/x-go", "Go source", ".go"),
+	".mod":        typ("text/plain", "Go module file", ".mod"),
+	".c":          typ("text/x-c", "C source", ".c"),
+	".h":          typ("text/x-c", "C header", ".h"),
+	".cpp":        typ("text/x-c++", "C++ source", ".cpp"),
+	".cc":         typ("text/x-c++", "C++ source", ".cpp"),
+	".cxx":        typ("text/x-c++", "C++ source", ".cpp"),
+	".hpp":        typ("text/x-c++", "C++ header", ".hpp"),
+	".hh":         typ("text/x-c++", "C++ header", ".hpp"),
+	".m":          typ("text/x-objcsrc", "Objective-C source", ".m"),
+	".mm":         typ("text/x-objcsrc", "Objective-C++ source", ".mm"),
+	".cs":         typ("text/x-csharp", "C# source", ".cs"),
+	".java":       typ("text/x-java", "Java source", ".java"),
+	".kt":         typ("text/x-kotlin", "Kotlin source", ".kt"),
+	".kts":        typ("text/x-kotlin", "Kotlin script", ".kts"),
+	".scala":      typ("text/x-scala", "Scala source", ".scala"),
+	".groovy":     typ("text/x-groovy", "Groovy source", ".groovy"),
+	".gradle":     typ("text/x-groovy", "Gradle build script", ".gradle"),
+	".clj":        typ("text/x-clojure", "Clojure source", ".clj"),
+	".rs":         typ("text/x-rust", "Rust source", ".rs"),
+	".swift":      typ("text/x-swift", "Swift source", ".swift"),
+	".zig":        typ("text/x-zig", "Zig source", ".zig"),
+	".nim":        typ("text/x-nim", "Nim source", ".nim"),
+	".d":          typ("text/x-d", "D source", ".d"),
+	".py":         typ("text/x-python", "Python script", ".py"),
+	".pyi":        typ("text/x-python", "Python stub", ".pyi"),
+	".rb":         typ("text/x-ruby", "Ruby script", ".rb"),
+	".pl":         typ("text/x-perl", "Perl script", ".pl"),
+	".pm":         typ("text/x-perl", "Perl module", ".pm"),
+	".php":        typ("application/x-php", "PHP script", ".php"),
+	".lua":        typ("text/x-lua", "Lua script", ".lua"),
+	".r":          typ("text/x-r", "R script", ".r"),
+	".jl":         typ("text/x-julia", "Julia source", ".jl"),
+	".js":         typ("text/javascript", "JavaScript source", ".js"),
+	".mjs":        typ("text/javascript", "JavaScript module", ".mjs"),
+	".cjs":        typ("text/javascript", "CommonJS module", ".cjs"),
+	".jsx":        typ("text/jsx", "JSX source", ".jsx"),
+	".ts":         typ("text/typescript", "TypeScript source", ".ts"),
+	".tsx":        typ("text/tsx", "TSX source", ".tsx"),
+	".vue":        typ("text/x-vue", "Vue component", ".vue"),
+	".svelte":     typ("text/x-svelte", "Svelte component", ".svelte"),
+	".dart":       typ("text/x-dart", "Dart source", ".dart"),
+	".ex":         typ("text/x-elixir", "Elixir source", ".ex"),
+	".exs":        typ("text/x-elixir", "Elixir script", ".exs"),
+	".erl":        typ("text/x-erlang", "Erlang source", ".erl"),
+	".hs":         typ("text/x-haskell", "Haskell source", ".hs"),
+	".ml":         typ("text/x-ocaml", "OCaml source", ".ml"),
+	".fs":         typ("text/x-fsharp", "F# source", ".fs"),
+	".elm":        typ("text/x-elm", "Elm source", ".elm"),
+	".lisp":       typ("text/x-common-lisp", "Lisp source", ".lisp"),
+	".el":         typ("text/x-emacs-lisp", "Emacs Lisp source", ".el"),
+	".scm":        typ("text/x-scheme", "Scheme source", ".scm"),
+	".vb":         typ("text/x-vb", "Visual Basic source", ".vb"),
+	".pas":        typ("text/x-pascal", "Pascal source", ".pas"),
+	".f90":        typ("text/x-fortran", "Fortran source", ".f90"),
+	".asm":        typ("text/x-asm", "assembler source", ".asm"),
+	".s":          typ("text/x-asm", "assembler source", ".s"),
+	".sh":         typ("text/x-shellscript", "Bourne-Again shell script", ".sh"),
+	".bash":       typ("text/x-shellscript", "Bourne-Again shell script", ".sh"),
+	".zsh":        typ("text/x-shellscript", "Paul Falstad's zsh script", ".zsh"),
+	".fish":       typ("text/x-shellscript", "fish shell script", ".fish"),
+	".ps1":        typ("text/x-powershell", "PowerShell script", ".ps1"),
+	".bat":        typ("text/x-msdos-batch", "DOS batch file", ".bat"),
+	".cmd":        typ("text/x-msdos-batch", "DOS batch file", ".bat"),
+	".awk":        typ("text/x-awk", "awk script", ".awk"),
+	".tcl":        typ("text/x-tcl", "Tcl script", ".tcl"),
+	".vim":        typ("text/x-vim", "Vim script", ".vim"),
+	".mk":         typ("text/x-makefile", "makefile script", ".mk"),
+	".cmake":      typ("text/x-cmake", "CMake script", ".cmake"),
+	".tf":         typ("text/x-hcl", "Terraform configuration", ".tf"),
+	".hcl":        typ("text/x-hcl", "HCL configuration", ".hcl"),
+	".nix":        typ("text/x-nix", "Nix expression", ".nix"),
+	".dockerfile": typ("text/x-dockerfile", "Dockerfile", ".dockerfile"),
+	".tmpl":       typ("text/plain", "template", ".tmpl"),
+
+	// Images
+	".png":  typ("image/png", "PNG image data", ".png"),
+	".jpg":  typ("image/jpeg", "JPEG image data", ".jpg"),
+	".jpeg": typ("image/jpeg", "JPEG image data", ".jpg"),
+	".jpe":  typ("image/jpeg", "JPEG image data", ".jpg"),
+	".gif":  typ("image/gif", "GIF image data", ".gif"),
+	".webp": typ("image/webp", "Web/P image", ".webp"),
+	".bmp":  typ("image/bmp", "PC bitmap", ".bmp"),
+	".tif":  typ("image/tiff", "TIFF image data", ".tif"),
+	".tiff": typ("image/tiff", "TIFF image data", ".tif"),
+	".ico":  typ("image/vnd.microsoft.icon", "MS Windows icon resource", ".ico"),
+	".cur":  typ("image/x-win-bitmap", "MS Windows cursor resource", ".cur"),
+	".icns": typ("image/x-icns", "Mac OS X icon", ".icns"),
+	".psd":  typ("image/vnd.adobe.photoshop", "Adobe Photoshop Image", ".psd"),
+	".xcf":  typ("image/x-xcf", "GIMP XCF image data", ".xcf"),
+	".avif": typ("image/avif", "ISO Media, AVIF Image", ".avif"),
+	".heic": typ("image/heic", "ISO Media, HEIF Image", ".heic"),
+	".heif": typ("image/heif", "ISO Media, HEIF Image", ".heif"),
+	".jp2":  typ("image/jp2", "JPEG 2000 image", ".jp2"),
+	".jxl":  typ("image/jxl", "JPEG XL image", ".jxl"),
+	".djvu": typ("image/vnd.djvu", "DjVu image or document", ".djvu"),
+	".exr":  typ("image/x-exr", "OpenEXR image data", ".exr"),
+	".hdr":  typ("image/vnd.radiance", "Radiance HDR image data", ".hdr"),
+	".qoi":  typ("image/x-qoi", "QOI image data", ".qoi"),
+	".pbm":  typ("image/x-portable-bitmap", "Netpbm image data, bitmap", ".pbm"),
+	".pgm":  typ("image/x-portable-graymap", "Netpbm image data, greymap", ".pgm"),
+	".ppm":  typ("image/x-portable-pixmap", "Netpbm image data, pixmap", ".ppm"),
+	".pam":  typ("image/x-portable-arbitrarymap", "Netpbm PAM image file", ".pam"),
+	".wmf":  typ("image/wmf", "Windows metafile", ".wmf"),
+	".emf":  typ("image/emf", "Windows Enhanced Metafile (EMF) image data", ".emf"),
+	".cr2":  typ("image/x-canon-cr2", "Canon CR2 raw image", ".cr2"),
+	".cr3":  typ("image/x-canon-cr3", "Canon CR3 raw image", ".cr3"),
+	".nef":  typ("image/x-nikon-nef", "Nikon NEF raw image", ".nef"),
+	".dng":  typ("image/x-adobe-dng", "Adobe DNG raw image", ".dng"),
+	".fits": typ("image/fits", "FITS image data", ".fits"),
+
+	// Audio
+	".mp3":  typ("audio/mpeg", "MPEG ADTS, layer III", ".mp3"),
+	".flac": typ("audio/flac", "FLAC audio bitstream data", ".flac"),
+	".wav":  typ("audio/wav", "RIFF (little-endian) data, WAVE audio", ".wav"),
+	".ogg":  typ("audio/ogg", "Ogg data, Vorbis audio", ".ogg"),
+	".oga":  typ("audio/ogg", "Ogg data", ".oga"),
+	".opus": typ("audio/opus", "Ogg data, Opus audio", ".opus"),
+	".spx":  typ("audio/ogg", "Ogg data, Speex audio", ".spx"),
+	".m4a":  typ("audio/mp4", "ISO Media, MPEG-4 audio", ".m4a"),
+	".m4b":  typ("audio/mp4", "ISO Media, MPEG-4 audiobook", ".m4b"),
+	".aac":  typ("audio/aac", "MPEG ADTS, AAC", ".aac"),
+	".aiff": typ("audio/aiff", "IFF data, AIFF audio", ".aiff"),
+	".aif":  typ("audio/aiff", "IFF data, AIFF audio", ".aiff"),
+	".aifc": typ("audio/x-aifc", "IFF data, AIFF-C compressed audio", ".aifc"),
+	".mid":  typ("audio/midi", "Standard MIDI data", ".mid"),
+	".midi": typ("audio/midi", "Standard MIDI data", ".mid"),
+	".amr":  typ("audio/amr", "Adaptive Multi-Rate Codec (GSM telephony)", ".amr"),
+	".au":   typ("audio/basic", "Sun/NeXT audio data", ".au"),
+	".ape":  typ("audio/x-ape", "Monkey's Audio compressed format", ".ape"),
+	".wv":   typ("audio/x-wavpack", "WavPack lossless audio", ".wv"),
+	".mpc":  typ("audio/x-musepack", "Musepack audio", ".mpc"),
+	".dsf":  typ("audio/x-dsf", "DSD Stream File", ".dsf"),
+	".caf":  typ("audio/x-caf", "CoreAudio Format audio file", ".caf"),
+	".voc":  typ("audio/x-voc", "Creative Labs voice data", ".voc"),
+	".wma":  typ("audio/x-ms-wma", "Microsoft ASF audio", ".wma"),
+
+	// Video
+	".mp4":  typ("video/mp4", "ISO Media, MP4 v2", ".mp4"),
+	".m4v":  typ("video/x-m4v", "ISO Media, Apple iTunes Video", ".m4v"),
+	".mov":  typ("video/quicktime", "ISO Media, Apple QuickTime movie", ".mov"),
+	".3gp":  typ("video/3gpp", "ISO Media, MPEG v4 system, 3GPP", ".3gp"),
+	".3g2":  typ("video/3gpp2", "ISO Media, MPEG v4 system, 3GPP2", ".3g2"),
+	".mkv":  typ("video/x-matroska", "Matroska data", ".mkv"),
+	".mka":  typ("audio/x-matroska", "Matroska audio", ".mka"),
+	".webm": typ("video/webm", "WebM", ".webm"),
+	".avi":  typ("video/x-msvideo", "RIFF (little-endian) data, AVI", ".avi"),
+	".flv":  typ("video/x-flv", "Macromedia Flash Video", ".flv"),
+	".mpg":  typ("video/mpeg", "MPEG sequence", ".mpg"),
+	".mpeg": typ("video/mpeg", "MPEG sequence", ".mpg"),
+	".m2ts": typ("video/mp2t", "MPEG transport stream data", ".m2ts"),
+	".mts":  typ("video/mp2t", "MPEG transport stream data", ".m2ts"),
+	".ogv":  typ("video/ogg", "Ogg data, Theora video", ".ogv"),
+	".ogx":  typ("application/ogg", "Ogg data", ".ogx"),
+	".wmv":  typ("video/x-ms-wmv", "Microsoft ASF video", ".wmv"),
+	".asf":  typ("video/x-ms-asf", "Microsoft ASF", ".asf"),
+	".rm":   typ("application/vnd.rn-realmedia", "RealMedia file", ".rm"),
+	".swf":  typ("application/x-shockwave-flash", "Macromedia Flash data", ".swf"),
+
+	// Archives and compression
+	".zip":   Zip,
+	".gz":    typ("application/gzip", "gzip compressed data", ".gz"),
+	".tgz":   typ("application/gzip", "gzip compressed data", ".tgz"),
+	".bz2":   typ("application/x-bzip2", "bzip2 compressed data", ".bz2"),
+	".tbz2":  typ("application/x-bzip2", "bzip2 compressed data", ".tbz2"),
+	".xz":    typ("application/x-xz", "XZ compressed data", ".xz"),
+	".txz":   typ("application/x-xz", "XZ compressed data", ".txz"),
+	".zst":   typ("application/zstd", "Zstandard compressed data", ".zst"),
+	".lz4":   typ("application/x-lz4", "LZ4 compressed data", ".lz4"),
+	".lz":    typ("application/x-lzip", "lzip compressed data", ".lz"),
+	".lzma":  typ("application/x-lzma", "LZMA compressed data", ".lzma"),
+	".z":     typ("application/x-compress", "compress'd data", ".Z"),
+	".7z":    typ("application/x-7z-compressed", "7-zip archive data", ".7z"),
+	".rar":   typ("application/vnd.rar", "RAR archive data", ".rar"),
+	".tar":   typ("application/x-tar", "POSIX tar archive", ".tar"),
+	".cpio":  typ("application/x-cpio", "cpio archive", ".cpio"),
+	".a":     typ("application/x-archive", "current ar archive", ".a"),
+	".ar":    typ("application/x-archive", "current ar archive", ".a"),
+	".deb":   child("application/x-archive", "application/vnd.debian.binary-package", "Debian binary package", ".deb"),
+	".rpm":   typ("application/x-rpm", "RPM", ".rpm"),
+	".cab":   typ("application/vnd.ms-cab-compressed", "Microsoft Cabinet archive data", ".cab"),
+	".xar":   typ("application/x-xar", "xar archive", ".xar"),
+	".pkg":   typ("application/x-xar", "xar archive", ".pkg"),
+	".sqfs":  typ("application/x-squashfs", "Squashfs filesystem", ".sqfs"),
+	".arj":   typ("application/x-arj", "ARJ archive data", ".arj"),
+	".lzh":   typ("application/x-lzh-compressed", "LHarc archive data", ".lzh"),
+	".warc":  typ("application/warc", "WARC Archive", ".warc"),
+	".iso":   typ("application/x-iso9660-image", "ISO 9660 CD-ROM filesystem data", ".iso"),
+	".dmg":   typ("application/x-apple-diskimage", "Apple disk image", ".dmg"),
+	".jar":   JAR,
+	".war":   JAR,
+	".ear":   JAR,
+	".apk":   APK,
+	".aab":   APK,
+	".xpi":   XPI,
+	".kmz":   KMZ,
+	".3mf":   ThreeMF,
+	".vsix":  VSIX,
+	".whl":   child("application/zip", "application/x-wheel+zip", "Python wheel", ".whl"),
+	".nupkg": child("application/zip", "application/x-nupkg", "NuGet package", ".nupkg"),
+	".ipa":   child("application/zip", "application/x-ios-app", "iOS application archive", ".ipa"),
+	".crx":   typ("application/x-chrome-extension", "Google Chrome extension", ".crx"),
+
+	// Documents
+	".pdf":     typ("application/pdf", "PDF document", ".pdf"),
+	".ps":      typ("application/postscript", "PostScript document text", ".ps"),
+	".eps":     typ("application/postscript", "PostScript document text", ".eps"),
+	".rtf":     typ("text/rtf", "Rich Text Format data", ".rtf"),
+	".doc":     child("application/x-ole-storage", "application/msword", "Microsoft Word document", ".doc"),
+	".xls":     child("application/x-ole-storage", "application/vnd.ms-excel", "Microsoft Excel spreadsheet", ".xls"),
+	".ppt":     child("application/x-ole-storage", "application/vnd.ms-powerpoint", "Microsoft PowerPoint presentation", ".ppt"),
+	".msg":     child("application/x-ole-storage", "application/vnd.ms-outlook", "Microsoft Outlook message", ".msg"),
+	".msi":     child("application/x-ole-storage", "application/x-msi", "Windows Installer package", ".msi"),
+	".docx":    DOCX,
+	".xlsx":    XLSX,
+	".pptx":    PPTX,
+	".docm":    child("application/zip", "application/vnd.ms-word.document.macroEnabled.12", "Microsoft Word 2007+ (macro-enabled)", ".docm"),
+	".xlsm":    child("application/zip", "application/vnd.ms-excel.sheet.macroEnabled.12", "Microsoft Excel 2007+ (macro-enabled)", ".xlsm"),
+	".epub":    EPUB,
+	".odt":     openDocument["application/vnd.oasis.opendocument.text"],
+	".ods":     openDocument["application/vnd.oasis.opendocument.spreadsheet"],
+	".odp":     openDocument["application/vnd.oasis.opendocument.presentation"],
+	".odg":     openDocument["application/vnd.oasis.opendocument.graphics"],
+	".odf":     openDocument["application/vnd.oasis.opendocument.formula"],
+	".chm":     typ("application/vnd.ms-htmlhelp", "MS Windows HtmlHelp Data", ".chm"),
+	".mobi":    typ("application/x-mobipocket-ebook", "Mobipocket E-book", ".mobi"),
+	".azw3":    typ("application/vnd.amazon.ebook", "Kindle E-book", ".azw3"),
+	".pst":     typ("application/vnd.ms-outlook", "Microsoft Outlook email folder", ".pst"),
+	".lnk":     typ("application/x-ms-shortcut", "MS Windows shortcut", ".lnk"),
+	".torrent": typ("application/x-bittorrent", "BitTorrent file", ".torrent"),
+
+	// Executables and bytecode
+	".exe":   typ("application/vnd.microsoft.portable-executable", "PE32 executable", ".exe"),
+	".dll":   typ("application/vnd.microsoft.portable-executable", "PE32 executable (DLL)", ".dll"),
+	".sys":   typ("application/vnd.microsoft.portable-executable", "PE32 executable (native)", ".sys"),
+	".so":    typ("application/x-sharedlib", "ELF shared object", ".so"),
+	".o":     typ("application/x-object", "ELF relocatable", ".o"),
+	".dylib": typ("application/x-mach-binary", "Mach-O dynamically linked shared library", ".dylib"),
+	".class": typ("application/java-vm", "compiled Java class data", ".class"),
+	".wasm":  typ("application/wasm", "WebAssembly (wasm) binary module", ".wasm"),
+	".dex":   typ("application/vnd.android.dex", "Dalvik dex file", ".dex"),
+	".bc":    typ("application/x-llvm-bitcode", "LLVM IR bitcode", ".bc"),
+	".luac":  typ("application/x-lua-bytecode", "Lua bytecode", ".luac"),
+	".pyc":   typ("application/x-python-code", "Python byte-compiled", ".pyc"),
+
+	// Fonts
+	".ttf":   typ("font/ttf", "TrueType Font data", ".ttf"),
+	".otf":   typ("font/otf", "OpenType font data", ".otf"),
+	".woff":  typ("font/woff", "Web Open Font Format", ".woff"),
+	".woff2": typ("font/woff2", "Web Open Font Format (Version 2)", ".woff2"),
+	".ttc":   typ("font/collection", "TrueType font collection data", ".ttc"),
+	".eot":   typ("application/vnd.ms-fontobject", "Embedded OpenType font", ".eot"),
+
+	// Databases, data and disk images
+	".sqlite":  typ("application/vnd.sqlite3", "SQLite 3.x database", ".sqlite"),
+	".sqlite3": typ("application/vnd.sqlite3", "SQLite 3.x database", ".sqlite"),
+	".db":      typ("application/vnd.sqlite3", "SQLite 3.x database", ".sqlite"),
+	".mdb":     typ("application/x-msaccess", "Microsoft Access Database", ".mdb"),
+	".accdb":   typ("application/x-msaccess", "Microsoft Access Database", ".accdb"),
+	".parquet": typ("application/vnd.apache.parquet", "Apache Parquet", ".parquet"),
+	".avro":    typ("application/avro", "Apache Avro", ".avro"),
+	".arrow":   typ("application/vnd.apache.arrow.file", "Apache Arrow columnar file", ".arrow"),
+	".h5":      typ("application/x-hdf5", "Hierarchical Data Format (version 5) data", ".h5"),
+	".hdf5":    typ("application/x-hdf5", "Hierarchical Data Format (version 5) data", ".h5"),
+	".nc":      typ("application/x-netcdf", "NetCDF Data Format data", ".nc"),
+	".pcap":    typ("application/vnd.tcpdump.pcap", "pcap capture file", ".pcap"),
+	".pcapng":  typ("application/x-pcapng", "pcapng capture file", ".pcapng"),
+	".icc":     typ("application/vnd.iccprofile", "ICC color profile", ".icc"),
+	".blend":   typ("application/x-blender", "Blender3D", ".blend"),
+	".glb":     typ("model/gltf-binary", "glTF binary model", ".glb"),
+	".gltf":    typ("model/gltf+json", "glTF model", ".gltf"),
+	".stl":     typ("model/stl", "STL model", ".stl"),
+	".obj":     typ("model/obj", "Wavefront OBJ model", ".obj"),
+	".vmdk":    typ("application/x-vmdk", "VMware4 disk image", ".vmdk"),
+	".qcow2":   typ("application/x-qemu-disk", "QEMU QCOW2 Image", ".qcow2"),
+	".vhdx":    typ("application/x-vhdx", "Microsoft Disk Image eXtended", ".vhdx"),
+	".evtx":    typ("application/x-ms-evtx", "MS Windows Vista Event Log", ".evtx"),
+	".jks":     typ("application/x-java-keystore", "Java KeyStore", ".jks"),
+	".bin":     OctetStream,
+	".dat":     OctetStream,
+}
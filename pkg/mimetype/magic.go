@@ -0,0 +1,471 @@
+package mimetype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// signature matches one format. Entries are tried in order, so a more
+// specific signature must come before a shorter one it overlaps with.
+type signature struct {
+	t     Type
+	match func(data []byte) bool
+}
+
+func prefix(sigs ...string) func([]byte) bool {
+	return func(data []byte) bool {
+		for _, s := range sigs {
+			if bytes.HasPrefix(data, []byte(s)) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func at(offset int, sig string) func([]byte) bool {
+	return func(data []byte) bool {
+		return len(data) >= offset+len(sig) && string(data[offset:offset+len(sig)]) == sig
+	}
+}
+
+func both(a, b func([]byte) bool) func([]byte) bool {
+	return func(data []byte) bool { return a(data) && b(data) }
+}
+
+func typ(mime, desc, ext string) Type {
+	return Type{MIME: mime, Description: desc, Extension: ext}
+}
+
+func child(parent, mime, desc, ext string) Type {
+	return Type{MIME: mime, Description: desc, Extension: ext, Parent: parent}
+}
+
+// detectMagic matches data against the signature table, refining
+// containers (zip, RIFF, ISO media, Ogg, Matroska, ELF, PE) by their
+// contents.
+func detectMagic(data []byte) (Type, bool) {
+	for _, s := range signatures {
+		if s.match(data) {
+			return refine(s.t, data), true
+		}
+	}
+
+	return Type{}, false
+}
+
+func refine(found Type, data []byte) Type {
+	switch found.MIME {
+	case "application/zip":
+		return zipSubtype(data)
+	case "application/x-executable":
+		return elfType(data)
+	case "application/x-dosexec":
+		return peType(data)
+	case "video/mp4":
+		return isoMediaType(data)
+	case "application/ogg":
+		return oggType(data)
+	case "video/x-matroska":
+		if bytes.Contains(data[:min(len(data), 64)], []byte("webm")) {
+			return typ("video/webm", "WebM", ".webm")
+		}
+	case "application/x-archive":
+		if bytes.HasPrefix(data[8:], []byte("debian-binary")) {
+			return child("application/x-archive", "application/vnd.debian.binary-package", "Debian binary package", ".deb")
+		}
+	case "application/xml":
+		return xmlType(data)
+	}
+
+	return found
+}
+
+var signatures = []signature{
+	// Images
+	{typ("image/png", "PNG image data", ".png"), prefix("\x89PNG\r\n\x1a\n")},
+	{typ("image/jpeg", "JPEG image data", ".jpg"), prefix("\xff\xd8\xff")},
+	{typ("image/gif", "GIF image data, version 87a", ".gif"), prefix("GIF87a")},
+	{typ("image/gif", "GIF image data, version 89a", ".gif"), prefix("GIF89a")},
+	{typ("image/webp", "RIFF (little-endian) data, Web/P image", ".webp"), both(prefix("RIFF"), at(8, "WEBP"))},
+	{typ("image/bmp", "PC bitmap", ".bmp"), isBMP},
+	{typ("image/tiff", "TIFF image data, little-endian", ".tif"), prefix("II*\x00")},
+	{typ("image/tiff", "TIFF image data, big-endian", ".tif"), prefix("MM\x00*")},
+	{typ("image/vnd.microsoft.icon", "MS Windows icon resource", ".ico"), both(prefix("\x00\x00\x01\x00"), iconCount)},
+	{typ("image/x-win-bitmap", "MS Windows cursor resource", ".cur"), both(prefix("\x00\x00\x02\x00"), iconCount)},
+	{typ("image/vnd.adobe.photoshop", "Adobe Photoshop Image", ".psd"), prefix("8BPS\x00\x01", "8BPS\x00\x02")},
+	{typ("image/jp2", "JPEG 2000 image", ".jp2"), prefix("\x00\x00\x00\x0cjP  \r\n\x87\n")},
+	{typ("image/jxl", "JPEG XL image", ".jxl"), prefix("\xff\x0a", "\x00\x00\x00\x0cJXL \r\n\x87\n")},
+	{typ("image/x-xcf", "GIMP XCF image data", ".xcf"), prefix("gimp xcf ")},
+	{typ("image/x-icns", "Mac OS X icon", ".icns"), prefix("icns")},
+	{typ("image/vnd.djvu", "DjVu image or document", ".djvu"), both(prefix("AT&TFORM"), at(12, "DJV"))},
+	{typ("image/x-exr", "OpenEXR image data", ".exr"), prefix("\x76\x2f\x31\x01")},
+	{typ("image/bpg", "BPG image data", ".bpg"), prefix("BPG\xfb")},
+	{typ("image/x-dpx", "DPX image data", ".dpx"), prefix("SDPX", "XPDS")},
+	{typ("image/fits", "FITS image data", ".fits"), prefix("SIMPLE  =")},
+	{typ("image/vnd.radiance", "Radiance HDR image data", ".hdr"), prefix("#?RADIANCE\n", "#?RGBE\n")},
+	{typ("image/x-qoi", "QOI image data", ".qoi"), prefix("qoif")},
+	{typ("image/wmf", "Windows metafile", ".wmf"), prefix("\xd7\xcd\xc6\x9a")},
+	{typ("image/emf", "Windows Enhanced Metafile (EMF) image data", ".emf"), both(prefix("\x01\x00\x00\x00"), at(40, " EMF"))},
+	{typ("image/x-portable-bitmap", "Netpbm image data, bitmap", ".pbm"), netpbm('4')},
+	{typ("image/x-portable-graymap", "Netpbm image data, greymap", ".pgm"), netpbm('5')},
+	{typ("image/x-portable-pixmap", "Netpbm image data, pixmap", ".ppm"), netpbm('6')},
+	{typ("image/x-portable-arbitrarymap", "Netpbm PAM image file", ".pam"), prefix("P7\n")},
+
+	// Audio
+	{typ("audio/mpeg", "Audio file with ID3 version 2", ".mp3"), prefix("ID3")},
+	{typ("audio/flac", "FLAC audio bitstream data", ".flac"), prefix("fLaC")},
+	{typ("audio/wav", "RIFF (little-endian) data, WAVE audio", ".wav"), both(prefix("RIFF"), at(8, "WAVE"))},
+	{typ("video/x-msvideo", "RIFF (little-endian) data, AVI", ".avi"), both(prefix("RIFF"), at(8, "AVI "))},
+	{typ("audio/midi", "Standard MIDI data", ".mid"), prefix("MThd\x00\x00\x00\x06")},
+	{typ("audio/aiff", "IFF data, AIFF audio", ".aiff"), both(prefix("FORM"), at(8, "AIFF"))},
+	{typ("audio/x-aifc", "IFF data, AIFF-C compressed audio", ".aifc"), both(prefix("FORM"), at(8, "AIFC"))},
+	{typ("audio/amr", "Adaptive Multi-Rate Codec (GSM telephony)", ".amr"), prefix("#!AMR")},
+	{typ("audio/basic", "Sun/NeXT audio data", ".au"), prefix(".snd\x00\x00\x00")},
+	{typ("audio/x-ape", "Monkey's Audio compressed format", ".ape"), both(prefix("MAC "), func(d []byte) bool { return len(d) > 5 && d[5] < 0x20 })},
+	{typ("audio/x-wavpack", "WavPack lossless audio", ".wv"), prefix("wvpk")},
+	{typ("audio/x-musepack", "Musepack audio", ".mpc"), prefix("MPCK")},
+	{typ("audio/x-dsf", "DSD Stream File", ".dsf"), prefix("DSD \x1c\x00\x00\x00")},
+	{typ("audio/x-caf", "CoreAudio Format audio file", ".caf"), prefix("caff\x00\x01")},
+	{typ("audio/x-voc", "Creative Labs voice data", ".voc"), prefix("Creative Voice File\x1a")},
+	{typ("audio/aac", "MPEG ADTS, AAC", ".aac"), prefix("\xff\xf1", "\xff\xf9")},
+	{typ("audio/mpeg", "MPEG ADTS, layer III", ".mp3"), prefix("\xff\xfb", "\xff\xf3", "\xff\xf2", "\xff\xfa")},
+	{typ("application/ogg", "Ogg data", ".ogx"), prefix("OggS\x00")},
+
+	// Video
+	{typ("video/mp4", "ISO Media", ".mp4"), at(4, "ftyp")},
+	{typ("video/x-matroska", "Matroska data", ".mkv"), prefix("\x1a\x45\xdf\xa3")},
+	{typ("video/x-flv", "Macromedia Flash Video", ".flv"), prefix("FLV\x01")},
+	{typ("video/mpeg", "MPEG sequence", ".mpg"), prefix("\x00\x00\x01\xba", "\x00\x00\x01\xb3")},
+	{typ("video/mp2t", "MPEG transport stream data", ".ts"), isMPEGTS},
+	{typ("video/x-ms-asf", "Microsoft ASF", ".asf"), prefix("\x30\x26\xb2\x75\x8e\x66\xcf\x11")},
+	{typ("application/vnd.rn-realmedia", "RealMedia file", ".rm"), prefix(".RMF\x00")},
+	{typ("application/x-shockwave-flash", "Macromedia Flash data", ".swf"), prefix("FWS", "CWS", "ZWS")},
+
+	// Archives and compression
+	{typ("application/zip", "Zip archive data", ".zip"), prefix("PK\x03\x04", "PK\x05\x06", "PK\x07\x08")},
+	{typ("application/gzip", "gzip compressed data", ".gz"), prefix("\x1f\x8b")},
+	{typ("application/x-bzip2", "bzip2 compressed data", ".bz2"), isBzip2},
+	{typ("application/x-xz", "XZ compressed data", ".xz"), prefix("\xfd7zXZ\x00")},
+	{typ("application/zstd", "Zstandard compressed data", ".zst"), prefix("\x28\xb5\x2f\xfd")},
+	{typ("application/x-lz4", "LZ4 compressed data", ".lz4"), prefix("\x04\x22\x4d\x18")},
+	{typ("application/x-lzip", "lzip compressed data", ".lz"), prefix("LZIP")},
+	{typ("application/x-lzma", "LZMA compressed data", ".lzma"), isLZMA},
+	{typ("application/x-compress", "compress'd data", ".Z"), prefix("\x1f\x9d")},
+	{typ("application/x-7z-compressed", "7-zip archive data", ".7z"), prefix("7z\xbc\xaf\x27\x1c")},
+	{typ("application/vnd.rar", "RAR archive data, v5", ".rar"), prefix("Rar!\x1a\x07\x01\x00")},
+	{typ("application/vnd.rar", "RAR archive data", ".rar"), prefix("Rar!\x1a\x07")},
+	{typ("application/x-tar", "POSIX tar archive", ".tar"), at(257, "ustar")},
+	{typ("application/x-cpio", "ASCII cpio archive", ".cpio"), isASCIICPIO},
+	{typ("application/x-cpio", "cpio archive", ".cpio"), prefix("\xc7\x71", "\x71\xc7")},
+	{typ("application/x-archive", "current ar archive", ".a"), prefix("!<arch>\n")},
+	{typ("application/x-rpm", "RPM", ".rpm"), prefix("\xed\xab\xee\xdb")},
+	{typ("application/vnd.ms-cab-compressed", "Microsoft Cabinet archive data", ".cab"), prefix("MSCF\x00\x00\x00\x00")},
+	{typ("application/x-xar", "xar archive", ".xar"), prefix("xar!")},
+	{typ("application/x-squashfs", "Squashfs filesystem", ".sqfs"), prefix("hsqs", "sqsh")},
+	{typ("application/x-snappy-framed", "snappy framed data", ".sz"), prefix("\xff\x06\x00\x00sNaPpY")},
+	{typ("application/x-arj", "ARJ archive data", ".arj"), both(prefix("\x60\xea"), func(d []byte) bool { return len(d) > 10 && d[10] == 2 })},
+	{typ("application/x-lzh-compressed", "LHarc archive data", ".lzh"), both(at(2, "-lh"), func(d []byte) bool { return len(d) > 6 && d[6] == '-' })},
+	{typ("application/warc", "WARC Archive", ".warc"), prefix("WARC/1.")},
+
+	// Documents
+	{typ("application/pdf", "PDF document", ".pdf"), prefix("%PDF")},
+	{typ("application/postscript", "PostScript document text", ".ps"), prefix("%!PS")},
+	{typ("application/postscript", "DOS EPS Binary File", ".eps"), prefix("\xc5\xd0\xd3\xc6")},
+	{typ("application/x-ole-storage", "Composite Document File V2 Document (Microsoft Office)", ".doc"), prefix("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")},
+	{typ("text/rtf", "Rich Text Format data", ".rtf"), prefix("{\\rtf")},
+	{typ("application/vnd.ms-htmlhelp", "MS Windows HtmlHelp Data", ".chm"), prefix("ITSF\x03\x00\x00\x00")},
+	{typ("application/x-mobipocket-ebook", "Mobipocket E-book", ".mobi"), at(60, "BOOKMOBI")},
+	{typ("application/vnd.ms-outlook", "Microsoft Outlook email folder", ".pst"), prefix("!BDN")},
+	{typ("application/x-ms-shortcut", "MS Windows shortcut", ".lnk"), prefix("L\x00\x00\x00\x01\x14\x02\x00")},
+	{typ("application/x-bittorrent", "BitTorrent file", ".torrent"), prefix("d8:announce", "d13:announce-list", "d4:info")},
+	{typ("application/x-bplist", "Apple binary property list", ".plist"), prefix("bplist00")},
+
+	// Executables and bytecode
+	{typ("application/x-executable", "ELF", ""), prefix("\x7fELF")},
+	{typ("application/x-dosexec", "MS-DOS executable", ".exe"), prefix("MZ")},
+	{typ("application/x-mach-binary", "Mach-O 64-bit executable", ""), prefix("\xcf\xfa\xed\xfe", "\xfe\xed\xfa\xcf")},
+	{typ("application/x-mach-binary", "Mach-O 32-bit executable", ""), prefix("\xce\xfa\xed\xfe", "\xfe\xed\xfa\xce")},
+	{typ("application/x-mach-binary", "Mach-O universal binary", ""), both(prefix("\xca\xfe\xba\xbe"), isFatMachO)},
+	{typ("application/java-vm", "compiled Java class data", ".class"), prefix("\xca\xfe\xba\xbe")},
+	{typ("application/wasm", "WebAssembly (wasm) binary module", ".wasm"), prefix("\x00asm")},
+	{typ("application/vnd.android.dex", "Dalvik dex file", ".dex"), prefix("dex\n0")},
+	{typ("application/x-llvm-bitcode", "LLVM IR bitcode", ".bc"), prefix("BC\xc0\xde")},
+	{typ("application/x-lua-bytecode", "Lua bytecode", ".luac"), prefix("\x1bLua")},
+
+	// Fonts
+	{typ("font/ttf", "TrueType Font data", ".ttf"), prefix("\x00\x01\x00\x00\x00", "true\x00")},
+	{typ("font/otf", "OpenType font data", ".otf"), prefix("OTTO\x00")},
+	{typ("font/woff", "Web Open Font Format", ".woff"), prefix("wOFF")},
+	{typ("font/woff2", "Web Open Font Format (Version 2)", ".woff2"), prefix("wOF2")},
+	{typ("font/collection", "TrueType font collection data", ".ttc"), prefix("ttcf\x00")},
+
+	// Databases, data and disk images
+	{typ("application/vnd.sqlite3", "SQLite 3.x database", ".sqlite"), prefix("SQLite format 3\x00")},
+	{typ("application/x-msaccess", "Microsoft Access Database", ".mdb"), prefix("\x00\x01\x00\x00Standard Jet DB", "\x00\x01\x00\x00Standard ACE DB")},
+	{typ("application/vnd.apache.parquet", "Apache Parquet", ".parquet"), prefix("PAR1")},
+	{typ("application/avro", "Apache Avro", ".avro"), prefix("Obj\x01")},
+	{typ("application/vnd.apache.arrow.file", "Apache Arrow columnar file", ".arrow"), prefix("ARROW1")},
+	{typ("application/x-hdf5", "Hierarchical Data Format (version 5) data", ".h5"), prefix("\x89HDF\r\n\x1a\n")},
+	{typ("application/x-netcdf", "NetCDF Data Format data", ".nc"), prefix("CDF\x01", "CDF\x02")},
+	{typ("application/vnd.tcpdump.pcap", "pcap capture file", ".pcap"), prefix("\xd4\xc3\xb2\xa1", "\xa1\xb2\xc3\xd4", "\x4d\x3c\xb2\xa1", "\xa1\xb2\x3c\x4d")},
+	{typ("application/x-pcapng", "pcapng capture file", ".pcapng"), prefix("\x0a\x0d\x0d\x0a")},
+	{typ("application/x-git", "Git pack", ".pack"), prefix("PACK\x00\x00\x00\x02", "PACK\x00\x00\x00\x03")},
+	{typ("application/vnd.iccprofile", "ICC color profile", ".icc"), at(36, "acsp")},
+	{typ("application/x-blender", "Blender3D", ".blend"), prefix("BLENDER")},
+	{typ("model/gltf-binary", "glTF binary model", ".glb"), prefix("glTF\x02\x00\x00\x00")},
+	{typ("application/x-vmdk", "VMware4 disk image", ".vmdk"), prefix("KDMV")},
+	{typ("application/x-qemu-disk", "QEMU QCOW2 Image", ".qcow2"), prefix("QFI\xfb")},
+	{typ("application/x-vhdx", "Microsoft Disk Image eXtended", ".vhdx"), prefix("vhdxfile")},
+	{typ("application/x-ms-evtx", "MS Windows Vista Event Log", ".evtx"), prefix("ElfFile\x00")},
+	{typ("application/x-ms-registry", "MS Windows registry file", ""), prefix("regf")},
+	{typ("application/x-java-keystore", "Java KeyStore", ".jks"), prefix("\xfe\xed\xfe\xed")},
+	{typ("application/x-bzip", "bzip compressed data (obsolete)", ".bz"), prefix("BZ0")},
+	{typ("application/xml", "XML document", ".xml"), isXML},
+}
+
+func isBMP(data []byte) bool {
+	// "BM", file size, then two reserved zero words
+	return len(data) >= 14 && data[0] == 'B' && data[1] == 'M' && binary.LittleEndian.Uint32(data[6:10]) == 0
+}
+
+func iconCount(data []byte) bool {
+	return len(data) >= 6 && data[4] != 0 || len(data) >= 6 && data[5] != 0
+}
+
+func netpbm(kind byte) func([]byte) bool {
+	return func(data []byte) bool {
+		return len(data) >= 3 && data[0] == 'P' && data[1] == kind && (data[2] == '\n' || data[2] == ' ' || data[2] == '\r' || data[2] == '\t')
+	}
+}
+
+func isMPEGTS(data []byte) bool {
+	return len(data) > 376 && data[0] == 0x47 && data[188] == 0x47 && data[376] == 0x47
+}
+
+// isASCIICPIO checks the octal (070707) or hex (070701, 070702) header
+// fields after the magic, so text that happens to start with the digits
+// is not taken for an archive.
+func isASCIICPIO(data []byte) bool {
+	var digits int
+
+	switch {
+	case bytes.HasPrefix(data, []byte("070707")):
+		digits = 70
+	case bytes.HasPrefix(data, []byte("070701")), bytes.HasPrefix(data, []byte("070702")):
+		digits = 104
+	default:
+		return false
+	}
+
+	if len(data) < 6+digits {
+		return false
+	}
+
+	for _, c := range data[6 : 6+digits] {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'F' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isBzip2(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("BZh")) && (len(data) == 3 || data[3] >= '1' && data[3] <= '9')
+}
+
+func isLZMA(data []byte) bool {
+	// Properties byte 0x5d and a power-of-two dictionary size, then an
+	// uncompressed size that is unknown (all ones) or small
+	if len(data) < 13 || data[0] != 0x5d {
+		return false
+	}
+
+	dict := binary.LittleEndian.Uint32(data[1:5])
+
+	return dict != 0 && dict&(dict-1) == 0 && (data[12] == 0 || data[12] == 0xff)
+}
+
+func isFatMachO(data []byte) bool {
+	// Java class files share the magic; their version makes the
+	// architecture count implausibly large
+	return len(data) >= 8 && binary.BigEndian.Uint32(data[4:8]) < 20
+}
+
+func isXML(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	return bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("<?xml"))
+}
+
+// elfType refines an ELF file by its object type, describing it like
+// file(1): "ELF 64-bit LSB executable".
+func elfType(data []byte) Type {
+	found := typ("application/x-executable", "ELF", "")
+	if len(data) < 20 {
+		return found
+	}
+
+	var b strings.Builder
+
+	b.WriteString("ELF ")
+
+	switch data[4] {
+	case 1:
+		b.WriteString("32-bit ")
+	case 2:
+		b.WriteString("64-bit ")
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+
+	switch data[5] {
+	case 1:
+		b.WriteString("LSB ")
+	case 2:
+		b.WriteString("MSB ")
+
+		order = binary.BigEndian
+	}
+
+	switch order.Uint16(data[16:18]) {
+	case 1:
+		b.WriteString("relocatable")
+
+		found.MIME = "application/x-object"
+	case 2:
+		b.WriteString("executable")
+	case 3:
+		b.WriteString("shared object")
+
+		found.MIME = "application/x-sharedlib"
+	case 4:
+		b.WriteString("core file")
+
+		found.MIME = "application/x-coredump"
+	}
+
+	found.Description = b.String()
+
+	return found
+}
+
+// peType distinguishes PE executables and DLLs from plain MS-DOS
+// programs by following the e_lfanew pointer to the "PE\0\0" header.
+func peType(data []byte) Type {
+	found := typ("application/x-dosexec", "MS-DOS executable", ".exe")
+	if len(data) < 64 {
+		return found
+	}
+
+	off := int(binary.LittleEndian.Uint32(data[0x3c:0x40]))
+	if off <= 0 || off+24+2 > len(data) || string(data[off:off+4]) != "PE\x00\x00" {
+		return found
+	}
+
+	found.MIME = "application/vnd.microsoft.portable-executable"
+
+	desc := "PE32 executable"
+	if binary.LittleEndian.Uint16(data[off+24:off+26]) == 0x20b {
+		desc = "PE32+ executable"
+	}
+
+	characteristics := binary.LittleEndian.Uint16(data[off+22 : off+24])
+	if characteristics&0x2000 != 0 {
+		desc += " (DLL)"
+		found.Extension = ".dll"
+	}
+
+	found.Description = desc
+
+	return found
+}
+
+// isoMediaType refines an ISO base media file (MP4 family) by its major
+// brand.
+func isoMediaType(data []byte) Type {
+	if len(data) < 12 {
+		return typ("video/mp4", "ISO Media", ".mp4")
+	}
+
+	brand := string(data[8:12])
+
+	switch {
+	case brand == "avif" || brand == "avis":
+		return typ("image/avif", "ISO Media, AVIF Image", ".avif")
+	case brand == "heic" || brand == "heix" || brand == "heim" || brand == "heis" || brand == "hevc" || brand == "hevx":
+		return typ("image/heic", "ISO Media, HEIF Image HEVC Main or Main Still Picture Profile", ".heic")
+	case brand == "mif1" || brand == "msf1":
+		return typ("image/heif", "ISO Media, HEIF Image", ".heif")
+	case brand == "qt  ":
+		return typ("video/quicktime", "ISO Media, Apple QuickTime movie", ".mov")
+	case brand == "M4A " || brand == "M4B ":
+		return typ("audio/mp4", "ISO Media, Apple iTunes ALAC/AAC-LC (.M4A) Audio", ".m4a")
+	case brand == "M4V " || brand == "M4VH" || brand == "M4VP":
+		return typ("video/x-m4v", "ISO Media, Apple iTunes Video (.M4V) Video", ".m4v")
+	case strings.HasPrefix(brand, "3gp"):
+		return typ("video/3gpp", "ISO Media, MPEG v4 system, 3GPP", ".3gp")
+	case strings.HasPrefix(brand, "3g2"):
+		return typ("video/3gpp2", "ISO Media, MPEG v4 system, 3GPP2", ".3g2")
+	case brand == "crx ":
+		return typ("image/x-canon-cr3", "Canon CR3 raw image", ".cr3")
+	case brand == "jp2 ":
+		return typ("image/jp2", "JPEG 2000 image", ".jp2")
+	default:
+		return typ("video/mp4", "ISO Media, MP4 v2", ".mp4")
+	}
+}
+
+// oggType identifies the codec of the first Ogg logical stream.
+func oggType(data []byte) Type {
+	codec := data[min(len(data), 28):]
+
+	switch {
+	case bytes.HasPrefix(codec, []byte("\x01vorbis")):
+		return typ("audio/ogg", "Ogg data, Vorbis audio", ".ogg")
+	case bytes.HasPrefix(codec, []byte("OpusHead")):
+		return typ("audio/opus", "Ogg data, Opus audio", ".opus")
+	case bytes.HasPrefix(codec, []byte("\x7fFLAC")):
+		return typ("audio/ogg", "Ogg data, FLAC audio", ".oga")
+	case bytes.HasPrefix(codec, []byte("Speex   ")):
+		return typ("audio/ogg", "Ogg data, Speex audio", ".spx")
+	case bytes.HasPrefix(codec, []byte("\x80theora")):
+		return typ("video/ogg", "Ogg data, Theora video", ".ogv")
+	default:
+		return typ("application/ogg", "Ogg data", ".ogx")
+	}
+}
+
+// xmlType refines an XML document by its root element.
+func xmlType(data []byte) Type {
+	s := string(data)
+
+	for _, r := range xmlRoots {
+		if strings.Contains(s, r.tag) {
+			return r.t
+		}
+	}
+
+	return typ("application/xml", "XML document", ".xml")
+}
+
+var xmlRoots = []struct {
+	tag string
+	t   Type
+}{
+	{"<svg", child("application/xml", "image/svg+xml", "SVG Scalable Vector Graphics image", ".svg")},
+	{"<rss", child("application/xml", "application/rss+xml", "RSS feed", ".rss")},
+	{"<feed", child("application/xml", "application/atom+xml", "Atom feed", ".atom")},
+	{"<!DOCTYPE plist", child("application/xml", "application/x-plist", "XML property list", ".plist")},
+	{"<plist", child("application/xml", "application/x-plist", "XML property list", ".plist")},
+	{"<kml", child("application/xml", "application/vnd.google-earth.kml+xml", "KML geographic data", ".kml")},
+	{"<gpx", child("application/xml", "application/gpx+xml", "GPS Exchange Format data", ".gpx")},
+	{"<xsl:stylesheet", child("application/xml", "application/xslt+xml", "XSLT stylesheet", ".xsl")},
+	{"<html", child("application/xml", "application/xhtml+xml", "XHTML document", ".xhtml")},
+	{"<x3d", child("application/xml", "model/x3d+xml", "X3D scene", ".x3d")},
+	{"<COLLADA", child("application/xml", "model/vnd.collada+xml", "COLLADA model", ".dae")},
+	{"<math", child("application/xml", "application/mathml+xml", "MathML document", ".mml")},
+}
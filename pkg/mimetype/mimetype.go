@@ -0,0 +1,152 @@
+package mimetype
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadLimit is the number of leading bytes Detect needs for a reliable
+// result; DetectReader and DetectFile read no more than this.
+const ReadLimit = 4096
+
+// Type is a detected or looked-up file format.
+type Type struct {
+	MIME        string `json:"mime"`
+	Description string `json:"description"`
+	Extension   string `json:"extension,omitempty"` // canonical extension, with the dot
+	Parent      string `json:"parent,omitempty"`    // container MIME type, e.g. application/zip for a .docx
+}
+
+// Generic results.
+var (
+	OctetStream = Type{MIME: "application/octet-stream", Description: "data"}
+	Empty       = Type{MIME: "inode/x-empty", Description: "empty"}
+	PlainText   = Type{MIME: "text/plain", Description: "ASCII text", Extension: ".txt"}
+	UTF8Text    = Type{MIME: "text/plain", Description: "UTF-8 Unicode text", Extension: ".txt"}
+)
+
+// Is reports whether t is the MIME type mime, or a format built on it.
+func (t Type) Is(mime string) bool {
+	return t.MIME == mime || t.Parent == mime
+}
+
+// IsText reports whether t is a textual format: text/*, XML, JSON and
+// the other application types that are human-readable source.
+func (t Type) IsText() bool {
+	if strings.HasPrefix(t.MIME, "text/") || strings.HasSuffix(t.MIME, "+xml") || strings.HasSuffix(t.MIME, "+json") {
+		return true
+	}
+
+	return textualApplication[t.MIME]
+}
+
+var textualApplication = map[string]bool{
+	"application/json":             true,
+	"application/xml":              true,
+	"application/javascript":       true,
+	"application/x-sh":             true,
+	"application/x-php":            true,
+	"application/x-pem-file":       true,
+	"application/pgp-keys":         true,
+	"application/pgp-signature":    true,
+	"application/pgp-encrypted":    true,
+	"application/postscript":       true,
+	"application/x-ndjson":         true,
+	"application/toml":             true,
+	"application/yaml":             true,
+	"application/x-subrip":         true,
+	"application/x-tex":            true,
+	"application/x-latex":          true,
+	"application/x-ipynb+json":     true,
+	"application/sql":              true,
+	"application/graphql":          true,
+	"application/x-ssh-public-key": true,
+	"message/rfc822":               true,
+	"image/svg+xml":                true,
+}
+
+// Detect identifies the format of data, normally the first ReadLimit bytes
+// of a file. Unrecognized binary content is OctetStream and empty input is
+// Empty.
+func Detect(data []byte) Type {
+	if len(data) == 0 {
+		return Empty
+	}
+
+	if t, ok := detectMagic(data); ok {
+		return t
+	}
+
+	if t, ok := detectText(data); ok {
+		return t
+	}
+
+	return OctetStream
+}
+
+// DetectReader reads up to ReadLimit bytes from r and detects their
+// format.
+func DetectReader(r io.Reader) (Type, error) {
+	buf := make([]byte, ReadLimit)
+
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return OctetStream, fmt.Errorf("mimetype: %w", err)
+	}
+
+	return Detect(buf[:n]), nil
+}
+
+// DetectFile detects the format of the file at path from its content.
+// When the content is only identified as plain text, or as a generic zip
+// archive, a more specific type for the file's extension is preferred
+// (a .go file is Go source, a .docx whose entries could not be inspected
+// is still a Word document).
+func DetectFile(path string) (Type, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return OctetStream, fmt.Errorf("mimetype: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	t, err := DetectReader(f)
+	if err != nil {
+		return t, err
+	}
+
+	byExt, ok := ByExtension(filepath.Ext(path))
+	if !ok {
+		return t, nil
+	}
+
+	switch {
+	case t.MIME == "text/plain" && byExt.IsText():
+		return byExt, nil
+	case t.MIME == "application/zip" && byExt.Parent == "application/zip":
+		return byExt, nil
+	case t.MIME == "application/xml" && strings.HasSuffix(byExt.MIME, "+xml"):
+		return byExt, nil
+	}
+
+	return t, nil
+}
+
+// IsBinary reports whether data, the leading bytes of a file, should be
+// treated as binary: it contains a NUL byte or carries the signature of a
+// non-text format (a PDF or an MP3 need not contain NUL early on). Text
+// with stray control characters is not binary.
+func IsBinary(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+
+	t, ok := detectMagic(data)
+
+	return ok && !t.IsText()
+}
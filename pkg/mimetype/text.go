@@ -0,0 +1,261 @@
+package mimetype
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// detectText reports whether data is text and, if so, what kind. The
+// description names the kind and the encoding the way file(1) does:
+// "HTML document, ASCII text".
+func detectText(data []byte) (Type, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xfe\x00\x00")):
+		return Type{MIME: "text/plain", Description: "Unicode text, UTF-32, little-endian", Extension: ".txt"}, true
+	case bytes.HasPrefix(data, []byte("\x00\x00\xfe\xff")):
+		return Type{MIME: "text/plain", Description: "Unicode text, UTF-32, big-endian", Extension: ".txt"}, true
+	case bytes.HasPrefix(data, []byte("\xff\xfe")):
+		return Type{MIME: "text/plain", Description: "Unicode text, UTF-16, little-endian", Extension: ".txt"}, true
+	case bytes.HasPrefix(data, []byte("\xfe\xff")):
+		return Type{MIME: "text/plain", Description: "Unicode text, UTF-16, big-endian", Extension: ".txt"}, true
+	}
+
+	encoding, ok := textEncoding(data)
+	if !ok {
+		return Type{}, false
+	}
+
+	body := bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	kind, ok := textKind(body)
+	if !ok {
+		return Type{MIME: "text/plain", Description: encoding, Extension: ".txt"}, true
+	}
+
+	switch {
+	case kind.Description == "":
+		kind.Description = encoding
+	case strings.HasSuffix(kind.Description, "script"):
+		kind.Description += ", " + encoding + " executable"
+	case strings.HasPrefix(kind.MIME, "text/"):
+		kind.Description += ", " + encoding
+	}
+
+	return kind, true
+}
+
+// textEncoding classifies data as ASCII, UTF-8, ISO-8859 or extended
+// ASCII text, or reports false when it holds control characters that do
+// not occur in text.
+func textEncoding(data []byte) (string, bool) {
+	bom := bytes.HasPrefix(data, []byte("\xef\xbb\xbf"))
+	body := bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	ascii, c1 := true, false
+
+	for _, c := range body {
+		switch {
+		case c >= 0x80:
+			ascii = false
+
+			if c < 0xa0 {
+				c1 = true
+			}
+		case c < 0x20 && !textControl[c], c == 0x7f:
+			return "", false
+		}
+	}
+
+	switch {
+	case ascii && !bom:
+		return "ASCII text", true
+	case validUTF8(body):
+		if bom {
+			return "UTF-8 Unicode (with BOM) text", true
+		}
+
+		return "UTF-8 Unicode text", true
+	case !c1:
+		return "ISO-8859 text", true
+	default:
+		return "Non-ISO extended-ASCII text", true
+	}
+}
+
+// textControl holds the control characters allowed in text: BEL, BS,
+// TAB, LF, VT, FF, CR and ESC.
+var textControl = [0x20]bool{7: true, 8: true, 9: true, 10: true, 11: true, 12: true, 13: true, 27: true}
+
+// validUTF8 is utf8.Valid, tolerating a sequence cut off at the end of a
+// ReadLimit-sized sample.
+func validUTF8(data []byte) bool {
+	for i := 0; i < utf8.UTFMax && i < len(data); i++ {
+		if utf8.Valid(data[:len(data)-i]) {
+			return i == 0 || !utf8.FullRune(data[len(data)-i:])
+		}
+	}
+
+	return false
+}
+
+// textKind recognizes well-known text formats by their opening.
+func textKind(data []byte) (Type, bool) {
+	if bytes.HasPrefix(data, []byte("#!")) {
+		return shebangType(data), true
+	}
+
+	s := string(bytes.TrimLeft(data[:min(len(data), 512)], " \t\r\n"))
+	lower := strings.ToLower(s)
+
+	for _, p := range textPrefixes {
+		if strings.HasPrefix(lower, p.prefix) {
+			return p.t, true
+		}
+	}
+
+	if strings.HasPrefix(s, "-----BEGIN ") {
+		return pemType(s), true
+	}
+
+	if strings.HasPrefix(s, "diff ") || strings.HasPrefix(s, "--- ") && strings.Contains(s, "\n+++ ") {
+		return Type{MIME: "text/x-diff", Description: "unified diff output", Extension: ".diff"}, true
+	}
+
+	if (s != "" && (s[0] == '{' || s[0] == '[')) && json.Valid(data) {
+		return Type{MIME: "application/json", Description: "JSON data", Extension: ".json"}, true
+	}
+
+	return Type{}, false
+}
+
+// textPrefixes are matched, lowercased, against the start of the text
+// after leading white space.
+var textPrefixes = []struct {
+	prefix string
+	t      Type
+}{
+	{"<!doctype html", Type{MIME: "text/html", Description: "HTML document", Extension: ".html"}},
+	{"<html", Type{MIME: "text/html", Description: "HTML document", Extension: ".html"}},
+	{"<head", Type{MIME: "text/html", Description: "HTML document", Extension: ".html"}},
+	{"<body", Type{MIME: "text/html", Description: "HTML document", Extension: ".html"}},
+	{"<svg", Type{MIME: "image/svg+xml", Description: "SVG Scalable Vector Graphics image", Extension: ".svg"}},
+	{"<?php", Type{MIME: "application/x-php", Description: "PHP script", Extension: ".php"}},
+	{"begin:vcard", Type{MIME: "text/vcard", Description: "vCard visiting card", Extension: ".vcf"}},
+	{"begin:vcalendar", Type{MIME: "text/calendar", Description: "vCalendar calendar file", Extension: ".ics"}},
+	{"webvtt", Type{MIME: "text/vtt", Description: "WebVTT subtitles", Extension: ".vtt"}},
+	{"\\documentclass", Type{MIME: "application/x-latex", Description: "LaTeX 2e document", Extension: ".tex"}},
+	{"ssh-rsa ", Type{MIME: "application/x-ssh-public-key", Description: "OpenSSH RSA public key", Extension: ".pub"}},
+	{"ssh-ed25519 ", Type{MIME: "application/x-ssh-public-key", Description: "OpenSSH ED25519 public key", Extension: ".pub"}},
+	{"ecdsa-sha2-", Type{MIME: "application/x-ssh-public-key", Description: "OpenSSH ECDSA public key", Extension: ".pub"}},
+	{"return-path:", Type{MIME: "message/rfc822", Description: "SMTP mail", Extension: ".eml"}},
+	{"received:", Type{MIME: "message/rfc822", Description: "SMTP mail", Extension: ".eml"}},
+}
+
+// pemType names a PEM or ASCII-armored block by its BEGIN label.
+func pemType(s string) Type {
+	label, _, _ := strings.Cut(strings.TrimPrefix(s, "-----BEGIN "), "-----")
+
+	switch {
+	case label == "CERTIFICATE" || label == "TRUSTED CERTIFICATE":
+		return Type{MIME: "application/x-pem-file", Description: "PEM certificate", Extension: ".pem"}
+	case label == "CERTIFICATE REQUEST" || label == "NEW CERTIFICATE REQUEST":
+		return Type{MIME: "application/x-pem-file", Description: "PEM certificate request", Extension: ".csr"}
+	case label == "OPENSSH PRIVATE KEY":
+		return Type{MIME: "application/x-pem-file", Description: "OpenSSH private key", Extension: ""}
+	case strings.HasSuffix(label, "PRIVATE KEY"):
+		return Type{MIME: "application/x-pem-file", Description: pemKey(label, "PRIVATE KEY"), Extension: ".key"}
+	case strings.HasSuffix(label, "PUBLIC KEY") && !strings.HasPrefix(label, "PGP"):
+		return Type{MIME: "application/x-pem-file", Description: pemKey(label, "PUBLIC KEY"), Extension: ".pem"}
+	case label == "PGP PUBLIC KEY BLOCK":
+		return Type{MIME: "application/pgp-keys", Description: "PGP public key block", Extension: ".asc"}
+	case label == "PGP PRIVATE KEY BLOCK":
+		return Type{MIME: "application/pgp-keys", Description: "PGP private key block", Extension: ".asc"}
+	case label == "PGP SIGNATURE":
+		return Type{MIME: "application/pgp-signature", Description: "PGP signature", Extension: ".sig"}
+	case label == "PGP SIGNED MESSAGE":
+		return Type{MIME: "application/pgp-signature", Description: "PGP signed message", Extension: ".asc"}
+	case label == "PGP MESSAGE":
+		return Type{MIME: "application/pgp-encrypted", Description: "PGP message", Extension: ".asc"}
+	default:
+		return Type{MIME: "application/x-pem-file", Description: "PEM " + strings.ToLower(label), Extension: ".pem"}
+	}
+}
+
+// pemKey describes a key label such as "RSA PRIVATE KEY" as
+// "PEM RSA private key".
+func pemKey(label, suffix string) string {
+	algo := strings.TrimSpace(strings.TrimSuffix(label, suffix))
+	if algo == "" {
+		return "PEM " + strings.ToLower(suffix)
+	}
+
+	return "PEM " + algo + " " + strings.ToLower(suffix)
+}
+
+// shebangType names a script by the interpreter on its #! line,
+// following "/usr/bin/env [-S] NAME".
+func shebangType(data []byte) Type {
+	line, _, _ := bytes.Cut(data[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+
+	if len(fields) == 0 {
+		return Type{MIME: "text/plain", Description: "script"}
+	}
+
+	interp := path.Base(fields[0])
+	if interp == "env" {
+		interp = ""
+
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interp = path.Base(f)
+
+				break
+			}
+		}
+	}
+
+	name := strings.TrimRight(interp, "0123456789.")
+
+	for _, s := range interpreters {
+		if name == s.name {
+			return s.t
+		}
+	}
+
+	return Type{MIME: "text/plain", Description: "a " + interp + " script"}
+}
+
+var interpreters = []struct {
+	name string
+	t    Type
+}{
+	{"sh", Type{MIME: "text/x-shellscript", Description: "POSIX shell script", Extension: ".sh"}},
+	{"dash", Type{MIME: "text/x-shellscript", Description: "POSIX shell script", Extension: ".sh"}},
+	{"ash", Type{MIME: "text/x-shellscript", Description: "POSIX shell script", Extension: ".sh"}},
+	{"bash", Type{MIME: "text/x-shellscript", Description: "Bourne-Again shell script", Extension: ".sh"}},
+	{"zsh", Type{MIME: "text/x-shellscript", Description: "Paul Falstad's zsh script", Extension: ".zsh"}},
+	{"ksh", Type{MIME: "text/x-shellscript", Description: "Korn shell script", Extension: ".ksh"}},
+	{"fish", Type{MIME: "text/x-shellscript", Description: "fish shell script", Extension: ".fish"}},
+	{"python", Type{MIME: "text/x-python", Description: "Python script", Extension: ".py"}},
+	{"pypy", Type{MIME: "text/x-python", Description: "Python script", Extension: ".py"}},
+	{"perl", Type{MIME: "text/x-perl", Description: "Perl script", Extension: ".pl"}},
+	{"ruby", Type{MIME: "text/x-ruby", Description: "Ruby script", Extension: ".rb"}},
+	{"node", Type{MIME: "text/javascript", Description: "Node.js script", Extension: ".js"}},
+	{"nodejs", Type{MIME: "text/javascript", Description: "Node.js script", Extension: ".js"}},
+	{"deno", Type{MIME: "text/javascript", Description: "Deno script", Extension: ".js"}},
+	{"bun", Type{MIME: "text/javascript", Description: "Bun script", Extension: ".js"}},
+	{"php", Type{MIME: "application/x-php", Description: "PHP script", Extension: ".php"}},
+	{"lua", Type{MIME: "text/x-lua", Description: "Lua script", Extension: ".lua"}},
+	{"tclsh", Type{MIME: "text/x-tcl", Description: "Tcl script", Extension: ".tcl"}},
+	{"wish", Type{MIME: "text/x-tcl", Description: "Tcl/Tk script", Extension: ".tcl"}},
+	{"awk", Type{MIME: "text/x-awk", Description: "awk script", Extension: ".awk"}},
+	{"gawk", Type{MIME: "text/x-awk", Description: "GNU awk script", Extension: ".awk"}},
+	{"make", Type{MIME: "text/x-makefile", Description: "makefile script", Extension: ".mk"}},
+	{"pwsh", Type{MIME: "text/x-powershell", Description: "PowerShell script", Extension: ".ps1"}},
+	{"Rscript", Type{MIME: "text/x-r", Description: "R script", Extension: ".r"}},
+	{"osascript", Type{MIME: "text/x-applescript", Description: "AppleScript script", Extension: ".applescript"}},
+}
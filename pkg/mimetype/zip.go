@@ -0,0 +1,116 @@
+package mimetype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// Formats built on zip. Their Parent is application/zip.
+var (
+	Zip     = typ("application/zip", "Zip archive data", ".zip")
+	DOCX    = child("application/zip", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "Microsoft Word 2007+", ".docx")
+	XLSX    = child("application/zip", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "Microsoft Excel 2007+", ".xlsx")
+	PPTX    = child("application/zip", "application/vnd.openxmlformats-officedocument.presentationml.presentation", "Microsoft PowerPoint 2007+", ".pptx")
+	OOXML   = child("application/zip", "application/vnd.openxmlformats-officedocument", "Microsoft OOXML", "")
+	JAR     = child("application/zip", "application/java-archive", "Java archive data (JAR)", ".jar")
+	APK     = child("application/zip", "application/vnd.android.package-archive", "Android package (APK)", ".apk")
+	EPUB    = child("application/zip", "application/epub+zip", "EPUB document", ".epub")
+	XPI     = child("application/zip", "application/x-xpinstall", "Mozilla XPInstall archive", ".xpi")
+	KMZ     = child("application/zip", "application/vnd.google-earth.kmz", "KMZ geographic data", ".kmz")
+	ThreeMF = child("application/zip", "model/3mf", "3D Manufacturing Format", ".3mf")
+	VSIX    = child("application/zip", "application/vsix", "Visual Studio extension", ".vsix")
+)
+
+// openDocument maps the content of an OpenDocument "mimetype" entry to
+// its type.
+var openDocument = map[string]Type{
+	"application/epub+zip":                            EPUB,
+	"application/vnd.oasis.opendocument.text":         child("application/zip", "application/vnd.oasis.opendocument.text", "OpenDocument Text", ".odt"),
+	"application/vnd.oasis.opendocument.spreadsheet":  child("application/zip", "application/vnd.oasis.opendocument.spreadsheet", "OpenDocument Spreadsheet", ".ods"),
+	"application/vnd.oasis.opendocument.presentation": child("application/zip", "application/vnd.oasis.opendocument.presentation", "OpenDocument Presentation", ".odp"),
+	"application/vnd.oasis.opendocument.graphics":     child("application/zip", "application/vnd.oasis.opendocument.graphics", "OpenDocument Drawing", ".odg"),
+	"application/vnd.oasis.opendocument.formula":      child("application/zip", "application/vnd.oasis.opendocument.formula", "OpenDocument Formula", ".odf"),
+	"application/vnd.oasis.opendocument.chart":        child("application/zip", "application/vnd.oasis.opendocument.chart", "OpenDocument Chart", ".odc"),
+	"application/vnd.sun.xml.writer":                  child("application/zip", "application/vnd.sun.xml.writer", "OpenOffice.org 1.x Writer document", ".sxw"),
+	"application/vnd.sun.xml.calc":                    child("application/zip", "application/vnd.sun.xml.calc", "OpenOffice.org 1.x Calc spreadsheet", ".sxc"),
+}
+
+// zipSubtype looks at the names of the entries whose local headers fall
+// within data to tell formats built on zip from a plain archive.
+func zipSubtype(data []byte) Type {
+	sig := []byte("PK\x03\x04")
+	ooxml := false
+
+	for off := 0; ; {
+		i := bytes.Index(data[off:], sig)
+		if i < 0 {
+			break
+		}
+
+		off += i
+
+		h := data[off:]
+		if len(h) < 30 {
+			break
+		}
+
+		nameLen := int(binary.LittleEndian.Uint16(h[26:28]))
+		extraLen := int(binary.LittleEndian.Uint16(h[28:30]))
+
+		if 30+nameLen > len(h) {
+			break
+		}
+
+		name := string(h[30 : 30+nameLen])
+
+		// An OpenDocument or EPUB file stores its MIME type, uncompressed,
+		// as the first entry
+		if name == "mimetype" && binary.LittleEndian.Uint16(h[8:10]) == 0 && 30+nameLen+extraLen <= len(h) {
+			content := h[30+nameLen+extraLen:]
+
+			// Writers that stream the entry leave the size to a trailing
+			// data descriptor; the content then ends at the next signature
+			if size := int(binary.LittleEndian.Uint32(h[18:22])); size > 0 && size <= len(content) {
+				content = content[:size]
+			} else if end := bytes.Index(content, []byte("PK")); end >= 0 {
+				content = content[:end]
+			}
+
+			if found, ok := openDocument[strings.TrimSpace(string(content))]; ok {
+				return found
+			}
+		}
+
+		switch {
+		case name == "[Content_Types].xml" || name == "_rels/.rels":
+			ooxml = true
+		case strings.HasPrefix(name, "word/"):
+			return DOCX
+		case strings.HasPrefix(name, "xl/"):
+			return XLSX
+		case strings.HasPrefix(name, "ppt/"):
+			return PPTX
+		case name == "AndroidManifest.xml" || name == "classes.dex":
+			return APK
+		case name == "META-INF/MANIFEST.MF" || strings.HasSuffix(name, ".class"):
+			return JAR
+		case name == "install.rdf" || name == "META-INF/mozilla.rsa":
+			return XPI
+		case name == "doc.kml":
+			return KMZ
+		case name == "3D/3dmodel.model":
+			return ThreeMF
+		case name == "extension.vsixmanifest":
+			return VSIX
+		}
+
+		off += 30 + nameLen
+	}
+
+	if ooxml {
+		return OOXML
+	}
+
+	return Zip
+}
@@ -0,0 +1,169 @@
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// --- Compression stages (byte streams, not line-oriented) ---
+
+// Format names a compression format.
+type Format string
+
+const (
+	FormatNone  Format = ""
+	FormatGzip  Format = "gzip"
+	FormatBzip2 Format = "bzip2"
+	FormatZstd  Format = "zstd"
+	FormatXz    Format = "xz"
+)
+
+// ErrUnsupportedFormat is returned for a compression format that is
+// recognized but has no implementation in this build (zstd and xz, which
+// the standard library does not provide).
+var ErrUnsupportedFormat = errors.New("unsupported compression format")
+
+// DetectFormat identifies the compression format of a stream from its
+// leading bytes, returning FormatNone for anything unrecognized.
+func DetectFormat(magic []byte) Format {
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return FormatGzip
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return FormatBzip2
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return FormatZstd
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return FormatXz
+	default:
+		return FormatNone
+	}
+}
+
+// Compress compresses its whole input into a single gzip stream. Level is
+// a gzip level from 1 (fastest) to 9 (best); 0 selects the default.
+type Compress struct {
+	Format Format
+	Level  int
+}
+
+func (s *Compress) Name() string {
+	if s.Format == FormatNone {
+		return string(FormatGzip)
+	}
+
+	return string(s.Format)
+}
+
+func (s *Compress) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	if s.Format != FormatNone && s.Format != FormatGzip {
+		return fmt.Errorf("%s: %w", s.Format, ErrUnsupportedFormat)
+	}
+
+	level := s.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	zw, err := gzip.NewWriterLevel(out, level)
+	if err != nil {
+		return fmt.Errorf("gzip: %w", err)
+	}
+
+	if err := copyStream(ctx, zw, in); err != nil {
+		return err
+	}
+
+	// A failed Close means downstream stopped reading
+	_ = zw.Close()
+
+	return nil
+}
+
+// Decompress decompresses its input. With Format unset the format is
+// detected from the leading bytes and uncompressed input passes through
+// unchanged, like "zcat -f"; with Format set, other input is an error.
+// Concatenated gzip members are read as one stream.
+type Decompress struct {
+	Format Format
+}
+
+func (s *Decompress) Name() string {
+	if s.Format == FormatGzip {
+		return "gunzip"
+	}
+
+	return "decompress"
+}
+
+func (s *Decompress) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	br := bufio.NewReader(in)
+
+	// A short or empty input is simply not compressed
+	magic, _ := br.Peek(6)
+	format := DetectFormat(magic)
+
+	if s.Format != FormatNone && format != s.Format && len(magic) > 0 {
+		return fmt.Errorf("%s: input is not in %s format", s.Name(), s.Format)
+	}
+
+	var r io.Reader
+
+	switch format {
+	case FormatGzip:
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name(), err)
+		}
+
+		defer func() { _ = zr.Close() }()
+
+		r = zr
+	case FormatBzip2:
+		r = bzip2.NewReader(br)
+	case FormatZstd, FormatXz:
+		return fmt.Errorf("%s: %s input: %w", s.Name(), format, ErrUnsupportedFormat)
+	default:
+		r = br
+	}
+
+	if err := copyStream(ctx, out, r); err != nil {
+		return fmt.Errorf("%s: %w", s.Name(), err)
+	}
+
+	return nil
+}
+
+// copyStream copies src to dst in chunks, checking ctx between them. A
+// failed write means downstream closed and ends the copy without error;
+// read errors are returned.
+func copyStream(ctx context.Context, dst io.Writer, src io.Reader) error {
+	buf := make([]byte, 32*1024)
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return nil // downstream closed
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package pipeline
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		magic []byte
+		want  Format
+	}{
+		{[]byte{0x1f, 0x8b, 0x08}, FormatGzip},
+		{[]byte("BZh91AY"), FormatBzip2},
+		{[]byte{0x28, 0xb5, 0x2f, 0xfd}, FormatZstd},
+		{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, FormatXz},
+		{[]byte("plain text"), FormatNone},
+		{nil, FormatNone},
+	}
+	for _, tc := range tests {
+		if got := DetectFormat(tc.magic); got != tc.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tc.magic, got, tc.want)
+		}
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	input := strings.Repeat("error: disk full\n", 100)
+
+	var out bytes.Buffer
+	if err := (&Compress{Level: 9}).Process(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+	if DetectFormat(out.Bytes()) != FormatGzip {
+		t.Fatalf("output is not gzip: %q", out.Bytes()[:4])
+	}
+
+	zr, err := gzip.NewReader(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(zr)
+	if string(got) != input {
+		t.Errorf("round trip mismatch: %d bytes, want %d", len(got), len(input))
+	}
+}
+
+func TestDecompressDetects(t *testing.T) {
+	gz := gzipBytes(t, "a\nb\n")
+
+	tests := []struct {
+		name  string
+		stage *Decompress
+		in    []byte
+		want  string
+	}{
+		{"auto gzip", &Decompress{}, gz, "a\nb\n"},
+		{"auto plain passthrough", &Decompress{}, []byte("plain\n"), "plain\n"},
+		{"auto empty", &Decompress{}, nil, ""},
+		{"gunzip", &Decompress{Format: FormatGzip}, gz, "a\nb\n"},
+		{"concatenated members", &Decompress{}, append(gzipBytes(t, "one\n"), gzipBytes(t, "two\n")...), "one\ntwo\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tc.stage.Process(context.Background(), bytes.NewReader(tc.in), &out); err != nil {
+				t.Fatalf("Process: %v", err)
+			}
+			if out.String() != tc.want {
+				t.Errorf("got %q, want %q", out.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	var out bytes.Buffer
+
+	err := (&Decompress{Format: FormatGzip}).Process(context.Background(), strings.NewReader("plain\n"), &out)
+	if err == nil || !strings.Contains(err.Error(), "not in gzip format") {
+		t.Errorf("gunzip on plain input: err = %v", err)
+	}
+
+	zstd := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x00}
+	err = (&Decompress{}).Process(context.Background(), bytes.NewReader(zstd), &out)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("zstd input: err = %v, want ErrUnsupportedFormat", err)
+	}
+
+	if _, err := Parse("zstd -3"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Parse(zstd): err = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestPipelineGzipRoundTrip(t *testing.T) {
+	input := gzipBytes(t, "error b\ninfo\nerror a\n")
+
+	stages, err := ParseAll([]string{"decompress", "grep error", "sort", "gzip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := New(stages...).Run(context.Background(), bytes.NewReader(input), &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var plain bytes.Buffer
+	if err := (&Decompress{}).Process(context.Background(), &out, &plain); err != nil {
+		t.Fatal(err)
+	}
+	if plain.String() != "error a\nerror b\n" {
+		t.Errorf("got %q", plain.String())
+	}
+}
@@ -0,0 +1,145 @@
+package pipeline
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/inovacc/omni/pkg/textwidth"
+)
+
+// Counts holds wc-style statistics for a stream.
+type Counts struct {
+	Lines        int64 `json:"lines"`
+	Words        int64 `json:"words"`
+	Chars        int64 `json:"chars"`
+	Bytes        int64 `json:"bytes"`
+	MaxLineWidth int64 `json:"max_line_width"`
+}
+
+// Add accumulates o into c; the maximum line width is the larger of both.
+func (c *Counts) Add(o Counts) {
+	c.Lines += o.Lines
+	c.Words += o.Words
+	c.Chars += o.Chars
+	c.Bytes += o.Bytes
+	c.MaxLineWidth = max(c.MaxLineWidth, o.MaxLineWidth)
+}
+
+// Counter is an io.Writer that counts what is written to it, so commands
+// can gather wc statistics while streaming (e.g. through io.TeeReader)
+// instead of in a second pass. Characters are UTF-8 sequences, including
+// ones split across writes; invalid bytes count as bytes only. Words are
+// separated by Unicode white space other than no-break spaces. Line width
+// is the display width as measured by textwidth: tabs advance to the next
+// multiple of 8, East Asian wide characters and emoji take two columns,
+// combining marks none.
+type Counter struct {
+	counts    Counts
+	inWord    bool
+	lineWidth int64
+	width     textwidth.State
+	pending   [utf8.UTFMax]byte
+	npending  int
+}
+
+// Write counts p. It never fails.
+func (c *Counter) Write(p []byte) (int, error) {
+	n := len(p)
+	c.counts.Bytes += int64(n)
+
+	// Complete a sequence split by the previous write
+	for c.npending > 0 && len(p) > 0 {
+		c.pending[c.npending] = p[0]
+		c.npending++
+		p = p[1:]
+
+		if utf8.FullRune(c.pending[:c.npending]) {
+			r, size := utf8.DecodeRune(c.pending[:c.npending])
+			c.rune(r, size)
+
+			// Bytes after an invalid sequence are re-scanned
+			rest := append([]byte(nil), c.pending[size:c.npending]...)
+			c.npending = 0
+
+			c.scan(rest)
+		}
+	}
+
+	c.scan(p)
+
+	return n, nil
+}
+
+func (c *Counter) scan(p []byte) {
+	for len(p) > 0 {
+		if b := p[0]; b < utf8.RuneSelf {
+			c.rune(rune(b), 1)
+			p = p[1:]
+
+			continue
+		}
+
+		if !utf8.FullRune(p) {
+			c.npending = copy(c.pending[:], p)
+			return
+		}
+
+		r, size := utf8.DecodeRune(p)
+		c.rune(r, size)
+		p = p[size:]
+	}
+}
+
+func (c *Counter) rune(r rune, size int) {
+	if r == utf8.RuneError && size == 1 {
+		// An invalid byte is not a character but still part of a word
+		if !c.inWord {
+			c.inWord = true
+			c.counts.Words++
+		}
+
+		return
+	}
+
+	c.counts.Chars++
+
+	switch r {
+	case '\n':
+		c.counts.Lines++
+		c.endLine()
+	case '\r', '\f':
+		c.endLine()
+	case '\t':
+		c.lineWidth += 8 - c.lineWidth%8
+		c.width.Reset()
+	default:
+		c.lineWidth += int64(c.width.Next(r))
+	}
+
+	if unicode.IsSpace(r) && !isNoBreakSpace(r) {
+		c.inWord = false
+	} else if !c.inWord {
+		c.inWord = true
+		c.counts.Words++
+	}
+}
+
+// isNoBreakSpace reports whether r is a space that does not separate words.
+func isNoBreakSpace(r rune) bool {
+	return r == '\u00a0' || r == '\u2007' || r == '\u202f'
+}
+
+func (c *Counter) endLine() {
+	c.counts.MaxLineWidth = max(c.counts.MaxLineWidth, c.lineWidth)
+	c.lineWidth = 0
+	c.width.Reset()
+}
+
+// Counts returns the statistics so far, including a final line without a
+// trailing newline.
+func (c *Counter) Counts() Counts {
+	counts := c.counts
+	counts.MaxLineWidth = max(counts.MaxLineWidth, c.lineWidth)
+
+	return counts
+}
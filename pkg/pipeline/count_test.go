@@ -0,0 +1,54 @@
+package pipeline
+
+import "testing"
+
+func TestCounter(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Counts
+	}{
+		{"empty", "", Counts{}},
+		{"ascii", "a b\nc\n", Counts{Lines: 2, Words: 3, Chars: 6, Bytes: 6, MaxLineWidth: 3}},
+		{"no trailing newline", "ab\ncdef", Counts{Lines: 1, Words: 2, Chars: 7, Bytes: 7, MaxLineWidth: 4}},
+		{"multibyte", "héllo wörld\n", Counts{Lines: 1, Words: 2, Chars: 12, Bytes: 14, MaxLineWidth: 11}},
+		{"wide", "日本語\n", Counts{Lines: 1, Words: 1, Chars: 4, Bytes: 10, MaxLineWidth: 6}},
+		{"combining", "e\u0301\n", Counts{Lines: 1, Words: 1, Chars: 3, Bytes: 4, MaxLineWidth: 1}},
+		{"emoji zwj sequence", "👨\u200d👩\u200d👧\n", Counts{Lines: 1, Words: 1, Chars: 6, Bytes: 19, MaxLineWidth: 2}},
+		{"tab", "ab\tc\n", Counts{Lines: 1, Words: 2, Chars: 5, Bytes: 5, MaxLineWidth: 9}},
+		{"no-break space", "a\u00a0b c\n", Counts{Lines: 1, Words: 2, Chars: 6, Bytes: 7, MaxLineWidth: 5}},
+		{"invalid byte", "a\xffb\n", Counts{Lines: 1, Words: 1, Chars: 3, Bytes: 4, MaxLineWidth: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var whole Counter
+
+			_, _ = whole.Write([]byte(tt.input))
+
+			if got := whole.Counts(); got != tt.want {
+				t.Errorf("Counts() = %+v, want %+v", got, tt.want)
+			}
+
+			// Byte-at-a-time writes split every multibyte sequence
+			var split Counter
+			for i := range len(tt.input) {
+				_, _ = split.Write([]byte{tt.input[i]})
+			}
+
+			if got := split.Counts(); got != tt.want {
+				t.Errorf("split Counts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountsAdd(t *testing.T) {
+	c := Counts{Lines: 1, Words: 2, Chars: 3, Bytes: 4, MaxLineWidth: 10}
+	c.Add(Counts{Lines: 1, Words: 1, Chars: 1, Bytes: 1, MaxLineWidth: 5})
+
+	want := Counts{Lines: 2, Words: 3, Chars: 4, Bytes: 5, MaxLineWidth: 10}
+	if c != want {
+		t.Errorf("Add() = %+v, want %+v", c, want)
+	}
+}
@@ -0,0 +1,166 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/inovacc/omni/pkg/cryptutil"
+)
+
+// --- Encryption stages (byte streams, not line-oriented) ---
+
+// Encrypt encrypts its whole input into one age file for the recipients,
+// chunk by chunk, so the plaintext never has to be held in full or written
+// to disk. With Armor set the age file is PEM-armored.
+type Encrypt struct {
+	Recipients []*cryptutil.X25519Recipient
+	Armor      bool
+}
+
+func (s *Encrypt) Name() string { return "encrypt" }
+
+func (s *Encrypt) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	dst := out
+
+	var armor io.WriteCloser
+	if s.Armor {
+		armor = cryptutil.AgeArmor(out)
+		dst = armor
+	}
+
+	ew, err := cryptutil.AgeEncrypt(dst, s.Recipients...)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if err := copyStream(ctx, ew, in); err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	// A failed Close means downstream stopped reading
+	_ = ew.Close()
+
+	if armor != nil {
+		_ = armor.Close()
+	}
+
+	return nil
+}
+
+// Decrypt decrypts an age file, binary or armored, with the first identity
+// that matches one of its recipients. Each chunk is authenticated before
+// its plaintext is passed on; a corrupted or truncated file is an error.
+type Decrypt struct {
+	Identities []*cryptutil.X25519Identity
+}
+
+func (s *Decrypt) Name() string { return "decrypt" }
+
+func (s *Decrypt) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	r, err := cryptutil.AgeDecrypt(in, s.Identities...)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	if err := copyStream(ctx, out, r); err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	return nil
+}
+
+// parseEncrypt accepts -r RECIPIENT, -R FILE (a recipients file) and -a,
+// each -r and -R as often as needed.
+func parseEncrypt(args []string) (Stage, error) {
+	e := &Encrypt{}
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "-a", "--armor":
+			e.Armor = true
+		case "-r", "--recipient", "-R", "--recipients-file":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("encrypt: %s requires a value", arg)
+			}
+
+			i++
+
+			if arg == "-r" || arg == "--recipient" {
+				r, err := cryptutil.ParseX25519Recipient(args[i])
+				if err != nil {
+					return nil, fmt.Errorf("encrypt: %w", err)
+				}
+
+				e.Recipients = append(e.Recipients, r)
+
+				continue
+			}
+
+			rs, err := readKeyFile(args[i], cryptutil.ParseRecipients)
+			if err != nil {
+				return nil, fmt.Errorf("encrypt: %w", err)
+			}
+
+			e.Recipients = append(e.Recipients, rs...)
+		default:
+			return nil, fmt.Errorf("encrypt: unknown option %q", arg)
+		}
+	}
+
+	if len(e.Recipients) == 0 {
+		return nil, errors.New("encrypt: missing recipient (-r KEY or -R FILE)")
+	}
+
+	return e, nil
+}
+
+// parseDecrypt accepts -i FILE, an identity file, as often as needed.
+func parseDecrypt(args []string) (Stage, error) {
+	d := &Decrypt{}
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "-i", "--identity":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("decrypt: %s requires a value", arg)
+			}
+
+			i++
+
+			ids, err := readKeyFile(args[i], cryptutil.ParseIdentities)
+			if err != nil {
+				return nil, fmt.Errorf("decrypt: %w", err)
+			}
+
+			d.Identities = append(d.Identities, ids...)
+		default:
+			return nil, fmt.Errorf("decrypt: unknown option %q", arg)
+		}
+	}
+
+	if len(d.Identities) == 0 {
+		return nil, errors.New("decrypt: missing identity (-i FILE)")
+	}
+
+	return d, nil
+}
+
+// readKeyFile parses the identity or recipients file at path.
+func readKeyFile[T any](path string, parse func(io.Reader) ([]T, error)) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	keys, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,158 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/pkg/cryptutil"
+)
+
+func testIdentity(t *testing.T) (*cryptutil.X25519Identity, string) {
+	t.Helper()
+
+	id, err := cryptutil.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(path, []byte(cryptutil.FormatIdentityFile(id, time.Now())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return id, path
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	id, _ := testIdentity(t)
+
+	// Larger than one 64 KiB age chunk
+	input := strings.Repeat("line of plaintext\n", 10000)
+
+	for _, armor := range []bool{false, true} {
+		var enc bytes.Buffer
+
+		e := &Encrypt{Recipients: []*cryptutil.X25519Recipient{id.Recipient()}, Armor: armor}
+		if err := e.Process(context.Background(), strings.NewReader(input), &enc); err != nil {
+			t.Fatalf("Encrypt(armor=%v): %v", armor, err)
+		}
+
+		if !cryptutil.IsAge(enc.Bytes()) {
+			t.Fatalf("Encrypt(armor=%v): output is not an age file", armor)
+		}
+
+		var dec bytes.Buffer
+		if err := (&Decrypt{Identities: []*cryptutil.X25519Identity{id}}).Process(context.Background(), &enc, &dec); err != nil {
+			t.Fatalf("Decrypt(armor=%v): %v", armor, err)
+		}
+
+		if dec.String() != input {
+			t.Errorf("round trip (armor=%v) changed the data", armor)
+		}
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	id, _ := testIdentity(t)
+	other, _ := testIdentity(t)
+
+	var enc bytes.Buffer
+	if err := (&Encrypt{Recipients: []*cryptutil.X25519Recipient{id.Recipient()}}).Process(context.Background(), strings.NewReader("secret\n"), &enc); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+
+	err := (&Decrypt{Identities: []*cryptutil.X25519Identity{other}}).Process(context.Background(), bytes.NewReader(enc.Bytes()), &out)
+	if !errors.Is(err, cryptutil.ErrNoIdentityMatch) {
+		t.Errorf("wrong identity: err = %v, want ErrNoIdentityMatch", err)
+	}
+
+	truncated := enc.Bytes()[:enc.Len()-1]
+	if err := (&Decrypt{Identities: []*cryptutil.X25519Identity{id}}).Process(context.Background(), bytes.NewReader(truncated), &out); err == nil {
+		t.Error("truncated input: expected error")
+	}
+
+	if out.Len() != 0 {
+		t.Errorf("unauthenticated plaintext passed on: %q", out.String())
+	}
+}
+
+func TestParseCrypt(t *testing.T) {
+	id, keyFile := testIdentity(t)
+
+	recipients := filepath.Join(t.TempDir(), "recipients.txt")
+	if err := os.WriteFile(recipients, []byte("# team\n"+id.Recipient().String()+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Parse("encrypt -a -r " + id.Recipient().String() + " -R " + recipients)
+	if err != nil {
+		t.Fatalf("Parse(encrypt): %v", err)
+	}
+
+	if e := s.(*Encrypt); !e.Armor || len(e.Recipients) != 2 {
+		t.Errorf("Parse(encrypt) = %+v", e)
+	}
+
+	s, err = Parse("decrypt -i " + keyFile)
+	if err != nil {
+		t.Fatalf("Parse(decrypt): %v", err)
+	}
+
+	if d := s.(*Decrypt); len(d.Identities) != 1 {
+		t.Errorf("Parse(decrypt) = %+v", d)
+	}
+
+	for _, bad := range []string{
+		"encrypt",
+		"encrypt -r",
+		"encrypt -r age1bogus",
+		"encrypt -x",
+		"encrypt -R " + filepath.Join(t.TempDir(), "missing"),
+		"decrypt",
+		"decrypt -i " + recipients,
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): expected error", bad)
+		}
+	}
+}
+
+func TestPipelineDecryptEncrypt(t *testing.T) {
+	id, keyFile := testIdentity(t)
+
+	var input bytes.Buffer
+	if err := (&Encrypt{Recipients: []*cryptutil.X25519Recipient{id.Recipient()}}).Process(context.Background(), strings.NewReader("prod b\ndev\nprod a\n"), &input); err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := ParseAll([]string{"decrypt -i " + keyFile, "grep prod", "sort", "encrypt -a -r " + id.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := New(stages...).Run(context.Background(), &input, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if strings.Contains(out.String(), "prod") {
+		t.Fatal("output contains plaintext")
+	}
+
+	var plain bytes.Buffer
+	if err := (&Decrypt{Identities: []*cryptutil.X25519Identity{id}}).Process(context.Background(), &out, &plain); err != nil {
+		t.Fatal(err)
+	}
+
+	if plain.String() != "prod a\nprod b\n" {
+		t.Errorf("got %q", plain.String())
+	}
+}
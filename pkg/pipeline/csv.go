@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// --- CSV stages (record-by-record, quote-aware) ---
+
+// CSVCut extracts fields from CSV records. Unlike Cut it honors quoting,
+// so a delimiter or newline inside a quoted field does not split it.
+// Fields are 1-based; missing fields are output empty.
+type CSVCut struct {
+	Delimiter rune // default ','
+	Fields    []int
+}
+
+func (s *CSVCut) Name() string { return "csvcut" }
+
+func (s *CSVCut) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	r := newCSVReader(in, s.Delimiter)
+	w := newCSVWriter(out, s.Delimiter)
+	selected := make([]string, len(s.Fields))
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("csvcut: %w", err)
+		}
+
+		for i, f := range s.Fields {
+			selected[i] = ""
+			if f > 0 && f <= len(record) {
+				selected[i] = record[f-1]
+			}
+		}
+
+		if err := w.Write(selected); err != nil {
+			return nil // downstream closed
+		}
+	}
+
+	w.Flush()
+
+	return nil
+}
+
+// CSVFilter keeps CSV records whose Field matches Pattern. With Header the
+// first record is always kept.
+type CSVFilter struct {
+	Delimiter  rune // default ','
+	Field      int  // 1-based
+	Pattern    string
+	IgnoreCase bool
+	Invert     bool
+	Header     bool
+}
+
+func (s *CSVFilter) Name() string { return "csvfilter" }
+
+func (s *CSVFilter) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	pattern := s.Pattern
+	if s.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("csvfilter: invalid pattern %q: %w", s.Pattern, err)
+	}
+
+	r := newCSVReader(in, s.Delimiter)
+	w := newCSVWriter(out, s.Delimiter)
+	first := true
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("csvfilter: %w", err)
+		}
+
+		keep := first && s.Header
+		if !keep {
+			var value string
+			if s.Field > 0 && s.Field <= len(record) {
+				value = record[s.Field-1]
+			}
+
+			keep = re.MatchString(value) != s.Invert
+		}
+
+		first = false
+
+		if keep {
+			if err := w.Write(record); err != nil {
+				return nil // downstream closed
+			}
+		}
+	}
+
+	w.Flush()
+
+	return nil
+}
+
+// newCSVReader returns a reader tolerant of ragged rows and stray quotes,
+// as found in real-world exports.
+func newCSVReader(in io.Reader, delim rune) *csv.Reader {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.ReuseRecord = true
+
+	if delim != 0 {
+		r.Comma = delim
+	}
+
+	return r
+}
+
+func newCSVWriter(out io.Writer, delim rune) *csv.Writer {
+	w := csv.NewWriter(out)
+	if delim != 0 {
+		w.Comma = delim
+	}
+
+	return w
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSVCut(t *testing.T) {
+	in := "id,name,city\n1,\"Smith, John\",\"São Paulo\"\n2,\"say \"\"hi\"\"\",Rio\n"
+
+	tests := []struct {
+		name  string
+		stage *CSVCut
+		in    string
+		want  string
+	}{
+		{"quoted comma", &CSVCut{Fields: []int{2}}, in, "name\n\"Smith, John\"\n\"say \"\"hi\"\"\"\n"},
+		{"reorder", &CSVCut{Fields: []int{3, 1}}, in, "city,id\nSão Paulo,1\nRio,2\n"},
+		{"missing field", &CSVCut{Fields: []int{1, 9}}, "a,b\n", "a,\n"},
+		{"semicolon", &CSVCut{Delimiter: ';', Fields: []int{2}}, "a;\"b;c\"\n", "\"b;c\"\n"},
+		{"multiline field", &CSVCut{Fields: []int{2}}, "1,\"line1\nline2\"\n", "\"line1\nline2\"\n"},
+		{"ragged rows", &CSVCut{Fields: []int{2}}, "a\nb,c,d\n", "\nc\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := run(t, tc.stage, tc.in); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCSVFilter(t *testing.T) {
+	in := "id,status,msg\n1,ok,\"fine, really\"\n2,ERROR,\"disk, full\"\n3,error,timeout\n"
+
+	tests := []struct {
+		name  string
+		stage *CSVFilter
+		want  string
+	}{
+		{"match field", &CSVFilter{Field: 2, Pattern: "^error$"}, "3,error,timeout\n"},
+		{"ignore case with header", &CSVFilter{Field: 2, Pattern: "^error$", IgnoreCase: true, Header: true},
+			"id,status,msg\n2,ERROR,\"disk, full\"\n3,error,timeout\n"},
+		{"invert", &CSVFilter{Field: 2, Pattern: "(?i)rror", Invert: true}, "id,status,msg\n1,ok,\"fine, really\"\n"},
+		{"quoted field not split", &CSVFilter{Field: 3, Pattern: "^disk, full$"}, "2,ERROR,\"disk, full\"\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := run(t, tc.stage, in); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVStages(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantErr string
+	}{
+		{"csvcut -f 1,3", ""},
+		{"csvcut -f2 -d;", ""},
+		{"csvcut -d tab -f 1", ""},
+		{"csvcut", "missing field"},
+		{"csvcut -f", "requires a value"},
+		{"csvcut -d ab -f 1", "invalid delimiter"},
+		{"csvcut -x 1", "unknown option"},
+		{"csvfilter -f 2 -i -H error", ""},
+		{"csvfilter -f2 -v ok", ""},
+		{"csvfilter error", "missing field"},
+		{"csvfilter -f 0 error", "invalid field"},
+		{"csvfilter -f 2", "missing pattern"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.line, func(t *testing.T) {
+			_, err := Parse(tc.line)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse(%q): %v", tc.line, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %q", tc.line, err, tc.wantErr)
+			}
+		})
+	}
+}
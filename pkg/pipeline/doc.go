@@ -1,5 +1,11 @@
 // Package pipeline provides a streaming text processing engine with built-in
 // transform stages connected via io.Pipe goroutines. It supports grep, sort,
 // uniq, head, tail, cut, tr, sed, and other stages with constant memory usage
-// for streaming operations.
+// for streaming operations. CSVCut and CSVFilter handle quoted CSV fields
+// that the plain cut stage would split. Join merges the stream with a file
+// on a key field like join(1), without requiring sorted input. Compress and
+// Decompress stages (de)compress gzip inline, detecting the input format from
+// its leading bytes. Encrypt and Decrypt read and write age files chunk by
+// chunk, so a pipeline can start from an encrypted file and end in one
+// without plaintext touching disk.
 package pipeline
@@ -0,0 +1,265 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Join merges the stream with a second input on a key field, like
+// coreutils join: for every pair of lines with equal keys it prints the
+// key, then the other fields of the stream line, then the other fields of
+// the second line. Unlike coreutils neither input has to be sorted: the
+// second input is read into memory first and the stream is matched
+// against it line by line, so joined lines come out in stream order.
+//
+// Unpaired1 also prints stream lines without a match, as they are, and
+// Unpaired2 prints the lines of the second input no stream line matched,
+// after the stream ends; with OnlyUnpaired the joined lines are left out,
+// like join -v.
+type Join struct {
+	Path         string    // second input ("file 2")
+	Reader       io.Reader // second input, used instead of Path when set
+	Field1       int       // 1-based key field of the stream (default 1)
+	Field2       int       // 1-based key field of the second input (default 1)
+	Delimiter    string    // field delimiter; "" splits on runs of blanks and joins with a space
+	Unpaired1    bool      // -a 1: print unpaired stream lines
+	Unpaired2    bool      // -a 2: print unpaired lines of the second input
+	OnlyUnpaired bool      // -v: print only unpaired lines
+	IgnoreCase   bool      // -i: compare keys case-insensitively
+}
+
+func (s *Join) Name() string { return "join" }
+
+// joinLine is one line of the second input.
+type joinLine struct {
+	line    string
+	fields  []string
+	matched bool
+}
+
+func (s *Join) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	right := s.Reader
+	if right == nil {
+		f, err := os.Open(s.Path)
+		if err != nil {
+			return fmt.Errorf("join: %w", err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		right = f
+	}
+
+	f1, f2 := max(s.Field1, 1), max(s.Field2, 1)
+
+	// Keep every line in input order for the unpaired lines printed at the
+	// end, and the lines of each key for matching.
+	var (
+		lines []*joinLine
+		table = map[string][]*joinLine{}
+	)
+
+	scanner := bufio.NewScanner(right)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		jl := &joinLine{line: scanner.Text()}
+		jl.fields = s.split(jl.line)
+
+		lines = append(lines, jl)
+
+		if key, ok := s.key(jl.fields, f2); ok {
+			table[key] = append(table[key], jl)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("join: %w", err)
+	}
+
+	outDelim := s.Delimiter
+	if outDelim == "" {
+		outDelim = " "
+	}
+
+	scanner = bufio.NewScanner(in)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line := scanner.Text()
+		fields := s.split(line)
+		key, ok := s.key(fields, f1)
+
+		var matches []*joinLine
+		if ok {
+			matches = table[key]
+		}
+
+		if len(matches) == 0 {
+			if s.Unpaired1 {
+				if _, err := fmt.Fprintln(out, line); err != nil {
+					return nil
+				}
+			}
+
+			continue
+		}
+
+		for _, m := range matches {
+			m.matched = true
+
+			if s.OnlyUnpaired {
+				continue
+			}
+
+			joined := append([]string{fields[f1-1]}, without(fields, f1)...)
+			joined = append(joined, without(m.fields, f2)...)
+
+			if _, err := fmt.Fprintln(out, strings.Join(joined, outDelim)); err != nil {
+				return nil
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if !s.Unpaired2 {
+		return nil
+	}
+
+	for _, jl := range lines {
+		if jl.matched {
+			continue
+		}
+
+		if _, err := fmt.Fprintln(out, jl.line); err != nil {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// split splits line into fields on the delimiter, or on blanks.
+func (s *Join) split(line string) []string {
+	if s.Delimiter == "" {
+		return strings.Fields(line)
+	}
+
+	return strings.Split(line, s.Delimiter)
+}
+
+// key returns the join key of fields, and false when there is no field n;
+// such lines never pair.
+func (s *Join) key(fields []string, n int) (string, bool) {
+	if n > len(fields) {
+		return "", false
+	}
+
+	if s.IgnoreCase {
+		return strings.ToLower(fields[n-1]), true
+	}
+
+	return fields[n-1], true
+}
+
+// without returns fields without the 1-based field n.
+func without(fields []string, n int) []string {
+	rest := make([]string, 0, len(fields))
+	rest = append(rest, fields[:n-1]...)
+
+	return append(rest, fields[n:]...)
+}
+
+// parseJoin accepts join [-t DELIM] [-1 N] [-2 N] [-j N] [-a 1|2] [-v 1|2]
+// [-i] FILE, where FILE is the second input and DELIM may be "tab".
+func parseJoin(args []string) (Stage, error) {
+	j := &Join{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch arg {
+		case "-i", "--ignore-case":
+			j.IgnoreCase = true
+			continue
+		case "-t", "-1", "-2", "-j", "-a", "-v":
+		default:
+			if strings.HasPrefix(arg, "-") && arg != "-" {
+				return nil, fmt.Errorf("join: unknown option %q", arg)
+			}
+
+			if j.Path != "" {
+				return nil, fmt.Errorf("join: extra operand %q", arg)
+			}
+
+			j.Path = arg
+
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("join: %s requires a value", arg)
+		}
+
+		i++
+		val := args[i]
+
+		if arg == "-t" {
+			switch val {
+			case "":
+				return nil, fmt.Errorf("join: empty delimiter")
+			case "tab": // the stage parser has no escape sequences
+				val = "\t"
+			}
+
+			j.Delimiter = val
+
+			continue
+		}
+
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("join: invalid value %q for %s", val, arg)
+		}
+
+		switch arg {
+		case "-1":
+			j.Field1 = n
+		case "-2":
+			j.Field2 = n
+		case "-j":
+			j.Field1, j.Field2 = n, n
+		default: // -a, -v
+			switch n {
+			case 1:
+				j.Unpaired1 = true
+			case 2:
+				j.Unpaired2 = true
+			default:
+				return nil, fmt.Errorf("join: invalid file number %q for %s", val, arg)
+			}
+
+			if arg == "-v" {
+				j.OnlyUnpaired = true
+			}
+		}
+	}
+
+	if j.Path == "" {
+		return nil, fmt.Errorf("join: missing file operand")
+	}
+
+	return j, nil
+}
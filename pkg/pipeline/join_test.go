@@ -0,0 +1,98 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	left := "1 alice\n2 bob\n3 carol\n2 bobby\n"
+	right := "2 admin\n4 guest\n1 staff\n2 ops\n"
+
+	tests := []struct {
+		name  string
+		stage Join
+		in    string
+		right string
+		want  string
+	}{
+		{"inner, stream order", Join{}, left, right, "1 alice staff\n2 bob admin\n2 bob ops\n2 bobby admin\n2 bobby ops\n"},
+		{"unpaired stream lines", Join{Unpaired1: true}, "3 carol\n1 alice\n", right, "3 carol\n1 alice staff\n"},
+		{"unpaired file lines", Join{Unpaired2: true}, "1 alice\n", right, "1 alice staff\n2 admin\n4 guest\n2 ops\n"},
+		{"only unpaired", Join{Unpaired1: true, Unpaired2: true, OnlyUnpaired: true}, left, right, "3 carol\n4 guest\n"},
+		{"key fields", Join{Field1: 2, Field2: 3, Delimiter: ","}, "7,ab,x\n8,cd,y\n", "q,r,cd\n", "cd,8,y,q,r\n"},
+		{"blank runs", Join{}, "k   a\tb\n", "k  c\n", "k a b c\n"},
+		{"empty fields kept", Join{Delimiter: ","}, "k,,a\n", "k,b,\n", "k,,a,b,\n"},
+		{"ignore case", Join{IgnoreCase: true}, "Key a\n", "kEY b\n", "Key a b\n"},
+		{"missing key field", Join{Field1: 2, Unpaired1: true}, "short\n", "x y\n", "short\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.stage
+			s.Reader = strings.NewReader(tc.right)
+
+			if got := run(t, &s, tc.in); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJoinFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customers.csv")
+	if err := os.WriteFile(path, []byte("c1,Acme\nc2,Globex\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Parse("join -t , -1 2 -a 1 " + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := run(t, s, "o1,c2,10\no2,c9,5\no3,c1,7\n")
+	if want := "c2,o1,10,Globex\no2,c9,5\nc1,o3,7,Acme\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	missing := &Join{Path: filepath.Join(t.TempDir(), "nope")}
+	if err := missing.Process(t.Context(), strings.NewReader("x\n"), &strings.Builder{}); err == nil {
+		t.Error("missing file: no error")
+	}
+}
+
+func TestParseJoin(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantErr string
+	}{
+		{"join -j 2 -v 2 -i file", ""},
+		{"join -t tab -2 3 file", ""},
+		{"join", "missing file"},
+		{"join a b", "extra operand"},
+		{"join -a 3 file", "invalid file number"},
+		{"join -1 0 file", "invalid value"},
+		{"join -t", "requires a value"},
+		{"join -x file", "unknown option"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.line, func(t *testing.T) {
+			_, err := Parse(tc.line)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse(%q): %v", tc.line, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %q", tc.line, err, tc.wantErr)
+			}
+		})
+	}
+
+	s, _ := Parse("join -j 2 -v 2 file")
+	if j := s.(*Join); j.Field1 != 2 || j.Field2 != 2 || j.Unpaired1 || !j.Unpaired2 || !j.OnlyUnpaired || j.Path != "file" {
+		t.Errorf("Parse() = %+v", j)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/inovacc/omni/pkg/units"
 )
 
 // Parse converts a CLI string like "grep -i error" into a Stage.
@@ -51,6 +53,26 @@ func Parse(cmdLine string) (Stage, error) {
 		return &Tac{}, nil
 	case "wc":
 		return parseWc(args)
+	case "numfmt":
+		return parseNumFmt(args)
+	case "csvcut":
+		return parseCSVCut(args)
+	case "csvfilter":
+		return parseCSVFilter(args)
+	case "gzip":
+		return parseGzip(args)
+	case "gunzip":
+		return &Decompress{Format: FormatGzip}, nil
+	case "decompress", "zcat":
+		return &Decompress{}, nil
+	case "join":
+		return parseJoin(args)
+	case "encrypt":
+		return parseEncrypt(args)
+	case "decrypt":
+		return parseDecrypt(args)
+	case "zstd", "unzstd":
+		return nil, fmt.Errorf("%s: not supported by this build: %w", cmd, ErrUnsupportedFormat)
 	default:
 		return nil, fmt.Errorf("pipeline: unknown stage %q", cmd)
 	}
@@ -396,15 +418,226 @@ func parseWc(args []string) (Stage, error) {
 			w.Lines = true
 		case "-w":
 			w.Words = true
-		case "-c", "-m":
+		case "-c":
+			w.Bytes = true
+		case "-m":
 			w.Chars = true
+		case "-L":
+			w.MaxLineLength = true
 		}
 	}
 
 	return w, nil
 }
 
+func parseCSVCut(args []string) (Stage, error) {
+	c := &CSVCut{}
+
+	for i := 0; i < len(args); i++ {
+		name, value, err := optionValue(args, &i)
+		if err != nil {
+			return nil, fmt.Errorf("csvcut: %w", err)
+		}
+
+		switch name {
+		case "-d":
+			if c.Delimiter, err = parseCSVDelimiter(value); err != nil {
+				return nil, fmt.Errorf("csvcut: %w", err)
+			}
+		case "-f":
+			if c.Fields, err = parseFieldSpec(value); err != nil {
+				return nil, fmt.Errorf("csvcut: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("csvcut: unknown option %q", args[i])
+		}
+	}
+
+	if len(c.Fields) == 0 {
+		return nil, fmt.Errorf("csvcut: missing field specification (-f)")
+	}
+
+	return c, nil
+}
+
+func parseCSVFilter(args []string) (Stage, error) {
+	c := &CSVFilter{}
+
+	var hasPattern bool
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-i":
+			c.IgnoreCase = true
+			continue
+		case "-v":
+			c.Invert = true
+			continue
+		case "-H", "--header":
+			c.Header = true
+			continue
+		}
+
+		if !strings.HasPrefix(args[i], "-d") && !strings.HasPrefix(args[i], "-f") {
+			c.Pattern, hasPattern = args[i], true
+			continue
+		}
+
+		name, value, err := optionValue(args, &i)
+		if err != nil {
+			return nil, fmt.Errorf("csvfilter: %w", err)
+		}
+
+		switch name {
+		case "-d":
+			if c.Delimiter, err = parseCSVDelimiter(value); err != nil {
+				return nil, fmt.Errorf("csvfilter: %w", err)
+			}
+		case "-f":
+			if c.Field, err = strconv.Atoi(value); err != nil || c.Field < 1 {
+				return nil, fmt.Errorf("csvfilter: invalid field %q", value)
+			}
+		}
+	}
+
+	if c.Field == 0 {
+		return nil, fmt.Errorf("csvfilter: missing field (-f)")
+	}
+
+	if !hasPattern {
+		return nil, fmt.Errorf("csvfilter: missing pattern")
+	}
+
+	return c, nil
+}
+
+// optionValue splits a short option at args[*i] into its name and value,
+// accepting both "-fVALUE" and "-f VALUE"; the latter advances *i.
+func optionValue(args []string, i *int) (string, string, error) {
+	arg := args[*i]
+	if len(arg) > 2 {
+		return arg[:2], arg[2:], nil
+	}
+
+	if *i+1 >= len(args) {
+		return "", "", fmt.Errorf("option %s requires a value", arg)
+	}
+
+	*i++
+
+	return arg, args[*i], nil
+}
+
+// parseCSVDelimiter accepts a single character, or "tab" for '\t' since
+// the stage parser has no escape sequences.
+func parseCSVDelimiter(s string) (rune, error) {
+	if s == "tab" {
+		return '\t', nil
+	}
+
+	r := []rune(s)
+	if len(r) != 1 || r[0] == '"' || r[0] == '\r' || r[0] == '\n' {
+		return 0, fmt.Errorf("invalid delimiter %q", s)
+	}
+
+	return r[0], nil
+}
+
+func parseGzip(args []string) (Stage, error) {
+	c := &Compress{Format: FormatGzip}
+
+	for _, arg := range args {
+		switch {
+		case arg == "-d" || arg == "--decompress":
+			return &Decompress{Format: FormatGzip}, nil
+		case len(arg) == 2 && arg[0] == '-' && arg[1] >= '1' && arg[1] <= '9':
+			c.Level = int(arg[1] - '0')
+		case arg == "--fast":
+			c.Level = 1
+		case arg == "--best":
+			c.Level = 9
+		default:
+			return nil, fmt.Errorf("gzip: unknown option %q", arg)
+		}
+	}
+
+	return c, nil
+}
+
+// parseNumFmt accepts the numfmt options that make sense in a stream:
+// --from, --to, --from-unit, --to-unit, --round, --suffix, --padding,
+// --field and -d, each as "--opt=value" or "--opt value".
+func parseNumFmt(args []string) (Stage, error) {
+	n := &NumFmt{}
+
+	var (
+		conv  units.Converter
+		field string
+		err   error
+	)
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+
+		if strings.HasPrefix(name, "-d") && len(name) > 2 {
+			name, value, hasValue = "-d", name[2:], true
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("numfmt: option %s requires a value", name)
+			}
+
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--from":
+			conv.From, err = units.ParseScale(value)
+		case "--to":
+			conv.To, err = units.ParseScale(value)
+		case "--from-unit":
+			conv.FromUnit, err = strconv.ParseFloat(value, 64)
+		case "--to-unit":
+			conv.ToUnit, err = strconv.ParseFloat(value, 64)
+		case "--round":
+			conv.Round, err = units.ParseRoundMode(value)
+		case "--suffix":
+			conv.Suffix = value
+		case "--padding":
+			conv.Padding, err = strconv.Atoi(value)
+		case "--field":
+			field = value
+		case "-d", "--delimiter":
+			n.Delimiter = value
+		default:
+			return nil, fmt.Errorf("numfmt: unknown option %q", args[i])
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("numfmt: %w", err)
+		}
+	}
+
+	if err := conv.Validate(); err != nil {
+		return nil, fmt.Errorf("numfmt: %w", err)
+	}
+
+	if n.Fields, err = units.ParseFields(field); err != nil {
+		return nil, fmt.Errorf("numfmt: %w", err)
+	}
+
+	n.Converter = conv
+
+	return n, nil
+}
+
 // parseCommandLine splits a command string into parts, respecting quotes.
+// Single quotes keep everything literally. Elsewhere a backslash escapes
+// a quote, a backslash or (outside quotes) whitespace, and is kept before
+// any other character, so regex escapes and sed backreferences such as
+// \d or \1 reach the stage unchanged.
 func parseCommandLine(cmdLine string) []string {
 	var (
 		parts   []string
@@ -415,6 +648,11 @@ func parseCommandLine(cmdLine string) []string {
 
 	for _, r := range cmdLine {
 		if escaped {
+			special := r == '\\' || r == '"' || (inQuote == 0 && (r == '\'' || r == ' ' || r == '\t'))
+			if !special {
+				current.WriteRune('\\')
+			}
+
 			current.WriteRune(r)
 
 			escaped = false
@@ -422,7 +660,7 @@ func parseCommandLine(cmdLine string) []string {
 			continue
 		}
 
-		if r == '\\' {
+		if r == '\\' && inQuote != '\'' {
 			escaped = true
 			continue
 		}
@@ -454,6 +692,10 @@ func parseCommandLine(cmdLine string) []string {
 		current.WriteRune(r)
 	}
 
+	if escaped {
+		current.WriteRune('\\')
+	}
+
 	if current.Len() > 0 {
 		parts = append(parts, current.String())
 	}
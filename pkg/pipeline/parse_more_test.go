@@ -57,6 +57,15 @@ func TestParse_AllStageTypes(t *testing.T) {
 		{"wc -l", false, "wc"},
 		{"wc -w -c", false, "wc"},
 		{"wc -m", false, "wc"},
+		{"gzip", false, "gzip"},
+		{"gzip -9", false, "gzip"},
+		{"gzip --fast", false, "gzip"},
+		{"gzip -d", false, "gunzip"},
+		{"gzip -x", true, ""},
+		{"gunzip", false, "gunzip"},
+		{"zcat", false, "decompress"},
+		{"decompress", false, "decompress"},
+		{"zstd", true, ""}, // no zstd implementation
 		{"boguscmd", true, ""},
 		{"", true, ""}, // empty
 	}
@@ -113,6 +122,10 @@ func TestParseCommandLine_Quoting(t *testing.T) {
 		{`a\ b`, []string{"a b"}},
 		{"a\tb", []string{"a", "b"}},
 		{`grep ""`, []string{"grep"}},
+		{`sed s/(\w+)/\u\1/`, []string{"sed", `s/(\w+)/\u\1/`}},
+		{`sed 's/a b/\U&/'`, []string{"sed", `s/a b/\U&/`}},
+		{`grep "say \"hi\" \d"`, []string{"grep", `say "hi" \d`}},
+		{`a\\b`, []string{`a\b`}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.in, func(t *testing.T) {
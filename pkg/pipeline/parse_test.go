@@ -41,6 +41,8 @@ func TestParse(t *testing.T) {
 		{"tac", "tac", "tac", false},
 		{"wc", "wc", "wc", false},
 		{"wc -l", "wc -l", "wc", false},
+		{"numfmt", "numfmt --to=iec --field 3", "numfmt", false},
+		{"numfmt delimiter", "numfmt --from iec -d, --field 2-", "numfmt", false},
 
 		// Errors
 		{"empty", "", "", true},
@@ -51,6 +53,9 @@ func TestParse(t *testing.T) {
 		{"tr one arg", "tr abc", "", true},
 		{"sed no arg", "sed", "", true},
 		{"cut no field", "cut -d:", "", true},
+		{"numfmt bad scale", "numfmt --to=metric", "", true},
+		{"numfmt to auto", "numfmt --to=auto", "", true},
+		{"numfmt missing value", "numfmt --field", "", true},
 	}
 
 	for _, tt := range tests {
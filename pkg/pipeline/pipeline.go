@@ -6,11 +6,24 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 )
 
 // Pipeline chains multiple stages together, connecting them via io.Pipe.
 type Pipeline struct {
-	stages []Stage
+	stages  []Stage
+	timed   bool
+	timings []StageTiming
+}
+
+// StageTiming describes how long one stage ran during the last Run.
+// Elapsed is the stage's lifetime; Busy excludes the time it spent blocked
+// reading its input or writing to the next stage, which is what identifies
+// the slow stage in a streaming pipeline where all stages overlap.
+type StageTiming struct {
+	Name    string
+	Elapsed time.Duration
+	Busy    time.Duration
 }
 
 // New creates a pipeline with the given stages.
@@ -29,6 +42,20 @@ func (p *Pipeline) Stages() []Stage {
 	return p.stages
 }
 
+// WithTimings makes Run record per-stage timings, available from Timings
+// afterwards. It adds a clock read around every Read and Write, so it is
+// off by default.
+func (p *Pipeline) WithTimings() *Pipeline {
+	p.timed = true
+	return p
+}
+
+// Timings returns the stage timings of the last Run, in stage order, or
+// nil when timings were not enabled.
+func (p *Pipeline) Timings() []StageTiming {
+	return p.timings
+}
+
 // Run executes the pipeline, reading from in and writing to out.
 // Each stage runs in its own goroutine, connected by io.Pipe.
 func (p *Pipeline) Run(ctx context.Context, in io.Reader, out io.Writer) error {
@@ -38,8 +65,12 @@ func (p *Pipeline) Run(ctx context.Context, in io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if p.timed {
+		p.timings = make([]StageTiming, len(p.stages))
+	}
+
 	if len(p.stages) == 1 {
-		return p.stages[0].Process(ctx, in, out)
+		return p.process(ctx, 0, in, out)
 	}
 
 	// Create pipes between stages
@@ -59,10 +90,10 @@ func (p *Pipeline) Run(ctx context.Context, in io.Reader, out io.Writer) error {
 	)
 
 	// Launch all stages
-	for i, stage := range p.stages {
+	for i := range p.stages {
 		wg.Add(1)
 
-		go func(idx int, s Stage) {
+		go func(idx int) {
 			defer wg.Done()
 
 			var (
@@ -82,7 +113,7 @@ func (p *Pipeline) Run(ctx context.Context, in io.Reader, out io.Writer) error {
 				w = pipes[idx]
 			}
 
-			err := s.Process(ctx, r, w)
+			err := p.process(ctx, idx, r, w)
 
 			// Close the write end of pipe when done
 			if idx < len(p.stages)-1 {
@@ -99,7 +130,7 @@ func (p *Pipeline) Run(ctx context.Context, in io.Reader, out io.Writer) error {
 			}
 
 			errs[idx] = err
-		}(i, stage)
+		}(i)
 	}
 
 	wg.Wait()
@@ -113,3 +144,55 @@ func (p *Pipeline) Run(ctx context.Context, in io.Reader, out io.Writer) error {
 
 	return nil
 }
+
+// process runs stage idx, recording its timing when enabled. Each stage
+// writes only its own timings slot, so no locking is needed.
+func (p *Pipeline) process(ctx context.Context, idx int, in io.Reader, out io.Writer) error {
+	s := p.stages[idx]
+	if !p.timed {
+		return s.Process(ctx, in, out)
+	}
+
+	r := &timedReader{r: in}
+	w := &timedWriter{w: out}
+	start := time.Now()
+
+	err := s.Process(ctx, r, w)
+
+	elapsed := time.Since(start)
+	p.timings[idx] = StageTiming{
+		Name:    s.Name(),
+		Elapsed: elapsed,
+		Busy:    max(elapsed-r.wait-w.wait, 0),
+	}
+
+	return err
+}
+
+// timedReader accumulates the time spent inside Read.
+type timedReader struct {
+	r    io.Reader
+	wait time.Duration
+}
+
+func (t *timedReader) Read(b []byte) (int, error) {
+	start := time.Now()
+	n, err := t.r.Read(b)
+	t.wait += time.Since(start)
+
+	return n, err
+}
+
+// timedWriter accumulates the time spent inside Write.
+type timedWriter struct {
+	w    io.Writer
+	wait time.Duration
+}
+
+func (t *timedWriter) Write(b []byte) (int, error) {
+	start := time.Now()
+	n, err := t.w.Write(b)
+	t.wait += time.Since(start)
+
+	return n, err
+}
@@ -242,6 +242,38 @@ func TestPipelineAdd(t *testing.T) {
 	}
 }
 
+func TestPipelineTimings(t *testing.T) {
+	p := New(&Grep{Pattern: "a"}, &Sort{})
+
+	if err := p.Run(context.Background(), strings.NewReader("b\na\nab\n"), &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Timings() != nil {
+		t.Error("Timings() should be nil unless WithTimings was called")
+	}
+
+	var buf bytes.Buffer
+	if err := p.WithTimings().Run(context.Background(), strings.NewReader("b\na\nab\n"), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "a\nab\n" {
+		t.Errorf("timed run changed output: %q", buf.String())
+	}
+
+	timings := p.Timings()
+	if len(timings) != 2 || timings[0].Name != p.Stages()[0].Name() || timings[1].Name != "sort" {
+		t.Fatalf("Timings() = %+v", timings)
+	}
+
+	for _, tm := range timings {
+		if tm.Busy > tm.Elapsed {
+			t.Errorf("%s: busy %v exceeds elapsed %v", tm.Name, tm.Busy, tm.Elapsed)
+		}
+	}
+}
+
 func TestPipelineContextCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
@@ -10,6 +10,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/inovacc/omni/pkg/textutil"
+	"github.com/inovacc/omni/pkg/units"
 )
 
 // --- Streaming stages (line-by-line, constant memory) ---
@@ -324,7 +327,9 @@ func translateChars(s, from, to string) string {
 	return result.String()
 }
 
-// Sed performs regex substitution on each line.
+// Sed performs regex substitution on each line. Replacement uses sed
+// syntax: & and \1..\9 refer to the match and its groups, and \U, \L, \u,
+// \l and \E convert case (see textutil.Replacement).
 type Sed struct {
 	Pattern     string
 	Replacement string
@@ -339,6 +344,15 @@ func (s *Sed) Process(ctx context.Context, in io.Reader, out io.Writer) error {
 		return fmt.Errorf("sed: invalid pattern %q: %w", s.Pattern, err)
 	}
 
+	repl, err := textutil.ParseReplacement(s.Replacement)
+	if err == nil {
+		err = repl.Check(re)
+	}
+
+	if err != nil {
+		return fmt.Errorf("sed: %w", err)
+	}
+
 	scanner := bufio.NewScanner(in)
 	for scanner.Scan() {
 		if ctx.Err() != nil {
@@ -347,12 +361,9 @@ func (s *Sed) Process(ctx context.Context, in io.Reader, out io.Writer) error {
 
 		line := scanner.Text()
 		if s.Global {
-			line = re.ReplaceAllString(line, s.Replacement)
+			line = repl.ReplaceAll(re, line)
 		} else {
-			loc := re.FindStringIndex(line)
-			if loc != nil {
-				line = line[:loc[0]] + re.ReplaceAllString(line[loc[0]:loc[1]], s.Replacement) + line[loc[1]:]
-			}
+			line = repl.ReplaceNth(re, line, 1)
 		}
 
 		if _, err := fmt.Fprintln(out, line); err != nil {
@@ -628,44 +639,50 @@ func (s *Tac) Process(ctx context.Context, in io.Reader, out io.Writer) error {
 	return nil
 }
 
-// Wc counts lines, words, and characters.
+// Wc counts lines, words, characters, bytes and the maximum line width
+// with constant memory. With no field selected it prints lines, words and
+// bytes.
 type Wc struct {
-	Lines bool
-	Words bool
-	Chars bool
+	Lines         bool
+	Words         bool
+	Chars         bool
+	Bytes         bool
+	MaxLineLength bool
 }
 
 func (s *Wc) Name() string { return "wc" }
 
 func (s *Wc) Process(ctx context.Context, in io.Reader, out io.Writer) error {
-	lines, err := readAllLines(in)
-	if err != nil {
+	var c Counter
+
+	if _, err := io.Copy(&c, in); err != nil {
 		return fmt.Errorf("wc: %w", err)
 	}
 
-	lineCount := len(lines)
-	wordCount := 0
-	charCount := 0
+	counts := c.Counts()
 
-	for _, line := range lines {
-		wordCount += len(strings.Fields(line))
-		charCount += len(line) + 1 // +1 for newline
-	}
-
-	// If no flags set, show all
-	showAll := !s.Lines && !s.Words && !s.Chars
+	// If no flags set, show lines, words and bytes
+	showAll := !s.Lines && !s.Words && !s.Chars && !s.Bytes && !s.MaxLineLength
 
 	var parts []string
 	if showAll || s.Lines {
-		parts = append(parts, strconv.Itoa(lineCount))
+		parts = append(parts, strconv.FormatInt(counts.Lines, 10))
 	}
 
 	if showAll || s.Words {
-		parts = append(parts, strconv.Itoa(wordCount))
+		parts = append(parts, strconv.FormatInt(counts.Words, 10))
+	}
+
+	if s.Chars {
+		parts = append(parts, strconv.FormatInt(counts.Chars, 10))
 	}
 
-	if showAll || s.Chars {
-		parts = append(parts, strconv.Itoa(charCount))
+	if showAll || s.Bytes {
+		parts = append(parts, strconv.FormatInt(counts.Bytes, 10))
+	}
+
+	if s.MaxLineLength {
+		parts = append(parts, strconv.FormatInt(counts.MaxLineWidth, 10))
 	}
 
 	_, _ = fmt.Fprintln(out, strings.Join(parts, "\t"))
@@ -673,6 +690,39 @@ func (s *Wc) Process(ctx context.Context, in io.Reader, out io.Writer) error {
 	return nil
 }
 
+// NumFmt converts numbers in selected fields of each line between plain
+// and human-readable sizes. Fields that are not numbers are left as they
+// are, so header lines pass through untouched.
+type NumFmt struct {
+	Converter units.Converter
+	Delimiter string // empty means runs of blanks
+	Fields    func(n int) bool
+}
+
+func (s *NumFmt) Name() string { return "numfmt" }
+
+func (s *NumFmt) Process(ctx context.Context, in io.Reader, out io.Writer) error {
+	fields := s.Fields
+	if fields == nil {
+		fields = func(n int) bool { return n == 1 }
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line, _ := s.Converter.ConvertFields(scanner.Text(), s.Delimiter, fields)
+
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
 // readAllLines reads all lines from a reader.
 func readAllLines(r io.Reader) ([]string, error) {
 	var lines []string
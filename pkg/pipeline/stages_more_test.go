@@ -46,6 +46,9 @@ func TestStageProcess_Table(t *testing.T) {
 		{"sed global", &Sed{Pattern: "a", Replacement: "X", Global: true}, "aaa\n", "XXX\n"},
 		{"sed first only", &Sed{Pattern: "a", Replacement: "X"}, "aaa\n", "Xaa\n"},
 		{"sed no match", &Sed{Pattern: "z", Replacement: "X"}, "aaa\n", "aaa\n"},
+		{"sed backrefs", &Sed{Pattern: `(\w+)=(\w+)`, Replacement: `\2=\1`}, "k=v\n", "v=k\n"},
+		{"sed case escapes", &Sed{Pattern: `\w+`, Replacement: `\u&`, Global: true}, "ab cd\n", "Ab Cd\n"},
+		{"sed anchored first", &Sed{Pattern: `^a`, Replacement: `[&]`}, "aa\n", "[a]a\n"},
 		{"rev", &Rev{}, "abc\n", "cba\n"},
 		{"nl", &Nl{Start: 1}, "x\ny\n", "     1\tx\n     2\ty\n"},
 		{"nl default start", &Nl{}, "x\n", "     1\tx\n"},
@@ -71,6 +74,18 @@ func TestStageProcess_Table(t *testing.T) {
 	}
 }
 
+func TestNumFmtStage(t *testing.T) {
+	stage, err := Parse("numfmt --to=iec --field 1")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	got := run(t, stage, "SIZE NAME\n1536 a.bin\n  1048576 b.iso\n")
+	if want := "SIZE NAME\n1.5K a.bin\n  1.0M b.iso\n"; got != want {
+		t.Errorf("numfmt = %q, want %q", got, want)
+	}
+}
+
 func TestFilterAndMapStages(t *testing.T) {
 	f := &Filter{Fn: func(s string) bool { return strings.HasPrefix(s, "a") }, Desc: "starts-a"}
 	if f.Name() != "filter(starts-a)" {
@@ -144,6 +159,14 @@ func TestSedInvalidPattern(t *testing.T) {
 	}
 }
 
+func TestSedInvalidReference(t *testing.T) {
+	s := &Sed{Pattern: "(a)", Replacement: `\2`}
+	var out bytes.Buffer
+	if err := s.Process(context.Background(), strings.NewReader("a\n"), &out); err == nil {
+		t.Error("sed with a reference to a missing group should error")
+	}
+}
+
 func TestStageContextCanceled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -0,0 +1,258 @@
+package ahocorasick
+
+import "strings"
+
+// Options configures a Matcher.
+type Options struct {
+	IgnoreCase bool // Match regardless of case
+}
+
+// Option is a functional option for New.
+type Option func(*Options)
+
+// WithIgnoreCase enables case-insensitive matching. ASCII patterns fold in
+// the automaton itself; other patterns lower-case the input before
+// scanning, so offsets refer to the lower-cased text when lower-casing
+// changes its length.
+func WithIgnoreCase() Option {
+	return func(o *Options) { o.IgnoreCase = true }
+}
+
+// Matcher is a compiled set of fixed-string patterns. It is safe for
+// concurrent use.
+type Matcher struct {
+	classes    [256]byte // input byte -> alphabet class
+	nclass     int
+	next       []int32 // state*nclass+class -> state, failure links resolved
+	depth      []int32
+	longest    []int32 // length of the longest pattern ending in a state, -1 for none
+	lowerInput bool
+	count      int
+}
+
+// New compiles patterns into a Matcher. An empty pattern matches
+// everywhere, like an empty grep pattern.
+func New(patterns []string, opts ...Option) *Matcher {
+	o := Options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m := &Matcher{count: len(patterns)}
+
+	foldASCII := false
+
+	if o.IgnoreCase {
+		foldASCII = true
+
+		for _, p := range patterns {
+			if !isASCII(p) {
+				foldASCII = false
+				m.lowerInput = true
+
+				break
+			}
+		}
+
+		lowered := make([]string, len(patterns))
+		for i, p := range patterns {
+			lowered[i] = strings.ToLower(p)
+		}
+
+		patterns = lowered
+	}
+
+	// Class 0 stands for every byte that appears in no pattern
+	m.nclass = 1
+
+	for _, p := range patterns {
+		for i := 0; i < len(p); i++ {
+			if m.classes[p[i]] == 0 {
+				m.classes[p[i]] = byte(m.nclass)
+				m.nclass++
+			}
+		}
+	}
+
+	if foldASCII {
+		for b := 'a'; b <= 'z'; b++ {
+			m.classes[b-'a'+'A'] = m.classes[b]
+		}
+	}
+
+	m.addState(0)
+
+	for _, p := range patterns {
+		s := 0
+
+		for i := 0; i < len(p); i++ {
+			idx := s*m.nclass + int(m.classes[p[i]])
+			if m.next[idx] < 0 {
+				m.next[idx] = int32(m.addState(i + 1))
+			}
+
+			s = int(m.next[idx])
+		}
+
+		m.longest[s] = int32(len(p))
+	}
+
+	m.link()
+
+	return m
+}
+
+// Len returns the number of patterns.
+func (m *Matcher) Len() int {
+	return m.count
+}
+
+// Match reports whether s contains any of the patterns.
+func (m *Matcher) Match(s string) bool {
+	if m.longest[0] >= 0 {
+		return true
+	}
+
+	if m.lowerInput {
+		s = strings.ToLower(s)
+	}
+
+	state := 0
+
+	for i := 0; i < len(s); i++ {
+		state = int(m.next[state*m.nclass+int(m.classes[s[i]])])
+		if m.longest[state] >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindIndex returns the start and end of the leftmost match in s, the
+// longest one when several patterns match there, or nil.
+func (m *Matcher) FindIndex(s string) []int {
+	if m.lowerInput {
+		s = strings.ToLower(s)
+	}
+
+	start, end, ok := m.find(s, 0)
+	if !ok {
+		return nil
+	}
+
+	return []int{start, end}
+}
+
+// FindAllIndex returns successive non-overlapping matches in s, as
+// FindIndex would find them; n < 0 means all matches.
+func (m *Matcher) FindAllIndex(s string, n int) [][]int {
+	if m.lowerInput {
+		s = strings.ToLower(s)
+	}
+
+	var out [][]int
+
+	for pos := 0; pos <= len(s) && (n < 0 || len(out) < n); {
+		start, end, ok := m.find(s, pos)
+		if !ok {
+			break
+		}
+
+		out = append(out, []int{start, end})
+
+		if end > start {
+			pos = end
+		} else {
+			pos = end + 1
+		}
+	}
+
+	return out
+}
+
+// find scans s from offset from for the leftmost-longest match. It stops
+// as soon as no later match can start at or before the best one found.
+func (m *Matcher) find(s string, from int) (int, int, bool) {
+	bestStart, bestEnd := -1, -1
+	if m.longest[0] >= 0 {
+		bestStart, bestEnd = from, from
+	}
+
+	state := 0
+
+	for i := from; i < len(s); i++ {
+		if bestStart >= 0 && i-int(m.depth[state]) > bestStart {
+			break
+		}
+
+		state = int(m.next[state*m.nclass+int(m.classes[s[i]])])
+
+		if l := int(m.longest[state]); l >= 0 {
+			start := i + 1 - l
+			if bestStart < 0 || start < bestStart || (start == bestStart && i+1 > bestEnd) {
+				bestStart, bestEnd = start, i+1
+			}
+		}
+	}
+
+	return bestStart, bestEnd, bestStart >= 0
+}
+
+func (m *Matcher) addState(depth int) int {
+	for range m.nclass {
+		m.next = append(m.next, -1)
+	}
+
+	m.depth = append(m.depth, int32(depth))
+	m.longest = append(m.longest, -1)
+
+	return len(m.depth) - 1
+}
+
+// link computes failure links breadth-first and folds them into the
+// transition table, turning the trie into a DFA.
+func (m *Matcher) link() {
+	fail := make([]int32, len(m.depth))
+	queue := make([]int, 0, len(m.depth))
+
+	for c := range m.nclass {
+		if t := m.next[c]; t < 0 {
+			m.next[c] = 0
+		} else {
+			queue = append(queue, int(t))
+		}
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		f := int(fail[s])
+		if m.longest[s] < 0 {
+			m.longest[s] = m.longest[f]
+		}
+
+		for c := range m.nclass {
+			idx := s*m.nclass + c
+			fallback := m.next[f*m.nclass+c]
+
+			if t := m.next[idx]; t < 0 {
+				m.next[idx] = fallback
+			} else {
+				fail[t] = fallback
+				queue = append(queue, int(t))
+			}
+		}
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,122 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFindIndex(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		input    string
+		want     []int
+	}{
+		{[]string{"he", "she", "his", "hers"}, "ushers", []int{1, 4}},
+		{[]string{"abc", "bcd"}, "xbcdabc", []int{1, 4}},
+		{[]string{"a", "abc"}, "zabcd", []int{1, 4}},
+		{[]string{"abcd", "bc"}, "abce", []int{1, 3}},
+		{[]string{"foo"}, "bar", nil},
+		{[]string{}, "anything", nil},
+		{[]string{"", "x"}, "ax", []int{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.patterns, tt.input), func(t *testing.T) {
+			got := New(tt.patterns).FindIndex(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindIndex(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreCase(t *testing.T) {
+	m := New([]string{"Error", "WARN"}, WithIgnoreCase())
+
+	if !m.Match("an ERROR here") || !m.Match("warning") {
+		t.Error("ASCII patterns should match regardless of case")
+	}
+
+	if got := m.FindAllIndex("error Warn", -1); !reflect.DeepEqual(got, [][]int{{0, 5}, {6, 10}}) {
+		t.Errorf("FindAllIndex() = %v", got)
+	}
+
+	u := New([]string{"ÉTÉ"}, WithIgnoreCase())
+	if !u.Match("un été chaud") {
+		t.Error("non-ASCII pattern should match regardless of case")
+	}
+
+	if New([]string{"Error"}).Match("error") {
+		t.Error("matching is case-sensitive by default")
+	}
+}
+
+func TestFindAllIndexLimit(t *testing.T) {
+	m := New([]string{"ab"})
+
+	if got := m.FindAllIndex("ababab", 2); len(got) != 2 {
+		t.Errorf("FindAllIndex(n=2) returned %d matches", len(got))
+	}
+
+	if got := New([]string{""}).FindAllIndex("ab", -1); len(got) != 3 {
+		t.Errorf("empty pattern should match at every position, got %v", got)
+	}
+}
+
+// TestAgainstRegexp compares random pattern sets with the leftmost-longest
+// alternation regexp.
+func TestAgainstRegexp(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	word := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abcd"[rng.IntN(4)]
+		}
+
+		return string(b)
+	}
+
+	for range 500 {
+		patterns := make([]string, 1+rng.IntN(8))
+		quoted := make([]string, len(patterns))
+
+		for i := range patterns {
+			patterns[i] = word(1 + rng.IntN(4))
+			quoted[i] = regexp.QuoteMeta(patterns[i])
+		}
+
+		re := regexp.MustCompile(strings.Join(quoted, "|"))
+		re.Longest()
+
+		m := New(patterns)
+		input := word(rng.IntN(30))
+
+		if got, want := m.FindAllIndex(input, -1), re.FindAllStringIndex(input, -1); !reflect.DeepEqual(got, want) {
+			t.Fatalf("patterns %q input %q: got %v, want %v", patterns, input, got, want)
+		}
+
+		if got, want := m.Match(input), re.MatchString(input); got != want {
+			t.Fatalf("patterns %q input %q: Match = %v, want %v", patterns, input, got, want)
+		}
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	patterns := make([]string, 500)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("indicator-%04d.example", i)
+	}
+
+	m := New(patterns)
+	line := strings.Repeat("the quick brown fox jumps over the lazy dog ", 4)
+
+	b.ResetTimer()
+
+	for b.Loop() {
+		m.Match(line)
+	}
+}
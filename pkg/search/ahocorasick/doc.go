@@ -0,0 +1,6 @@
+// Package ahocorasick finds many fixed strings in one pass over the input.
+// The patterns are compiled into a deterministic Aho-Corasick automaton
+// over a compressed byte alphabet, so scanning costs one table lookup per
+// input byte regardless of how many patterns there are. Matches follow
+// leftmost-longest semantics, with optional case-insensitive matching.
+package ahocorasick
@@ -1,2 +1,2 @@
-// Package search provides text search engines including grep-style pattern matching and ripgrep-compatible file searching.
+// Package search provides text search engines including grep-style pattern matching, Aho-Corasick multi-pattern matching, structural Go code matching and ripgrep-compatible file searching.
 package search
@@ -0,0 +1,20 @@
+// Package gopattern searches Go source for code with a given structure
+// rather than given text.
+//
+// A pattern is a Go expression or statement list in which $-prefixed names
+// are wildcards:
+//
+//	$_      any single expression, statement or identifier
+//	$x      like $_, but every $x in the pattern must match the same code
+//	$*_     zero or more elements of a list (arguments, statements, ...)
+//	$*x     like $*_, with the same consistency rule as $x
+//
+// So "fmt.Errorf($_)" matches calls with exactly one argument,
+// "fmt.Errorf($*_)" matches every call, and "$x = $x" finds
+// self-assignments. Matching ignores positions, comments and formatting;
+// everything else must be identical.
+//
+// Compile parses a pattern once; Pattern.MatchSource and Pattern.MatchFile
+// report every match in a file with its start and end positions, and
+// Pattern.MatchNode tests a single AST node.
+package gopattern
@@ -0,0 +1,375 @@
+package gopattern
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Wildcards are rewritten into identifiers with these prefixes before the
+// pattern is parsed, since $ is not valid Go.
+const (
+	singlePrefix = "gopattern_1_"
+	listPrefix   = "gopattern_n_"
+)
+
+// kind is the syntactic class of a pattern, which is also the class of
+// node it can match.
+type kind int
+
+const (
+	kindExpr kind = iota
+	kindStmts
+	kindDecl
+)
+
+// Pattern is a compiled structural pattern.
+type Pattern struct {
+	src   string
+	kind  kind
+	expr  ast.Expr
+	stmts []ast.Stmt
+	decl  ast.Decl
+}
+
+// Match is one occurrence of a pattern.
+type Match struct {
+	Node  ast.Node          // matched node; the first statement of a matched sequence
+	Pos   token.Position    // start of the match
+	End   token.Position    // position just after the match
+	Text  string            // source text of the match
+	Binds map[string]string // source text bound to each named wildcard
+}
+
+// Compile parses a pattern: a Go expression, a statement list or a single
+// declaration in which $name and $*name are wildcards.
+func Compile(src string) (*Pattern, error) {
+	rewritten, err := rewriteWildcards(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pattern{src: src}
+
+	expr, exprErr := parser.ParseExpr(rewritten)
+	if exprErr == nil {
+		p.kind, p.expr = kindExpr, expr
+
+		return p, p.check()
+	}
+
+	if stmts, err := parseStmts(rewritten); err == nil && len(stmts) > 0 {
+		p.kind, p.stmts = kindStmts, stmts
+
+		return p, p.check()
+	}
+
+	if decl, err := parseDecl(rewritten); err == nil {
+		p.kind, p.decl = kindDecl, decl
+
+		return p, p.check()
+	}
+
+	return nil, fmt.Errorf("gopattern: invalid pattern %q: %w", src, exprErr)
+}
+
+// String returns the pattern as written.
+func (p *Pattern) String() string { return p.src }
+
+// MatchSource parses a Go source file and returns the pattern's matches in
+// it, in source order.
+func (p *Pattern) MatchSource(filename string, src []byte) ([]Match, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.MatchFile(fset, file, src), nil
+}
+
+// MatchFile returns the pattern's matches in a parsed file, in source
+// order. src is the file's source, used for Match.Text and Match.Binds;
+// when nil the matched nodes are printed instead.
+func (p *Pattern) MatchFile(fset *token.FileSet, file *ast.File, src []byte) []Match {
+	var matches []Match
+
+	add := func(node ast.Node, from, to token.Pos, m *matcher) {
+		match := Match{
+			Node:  node,
+			Pos:   fset.Position(from),
+			End:   fset.Position(to),
+			Text:  nodeText(fset, src, from, to, node),
+			Binds: map[string]string{},
+		}
+
+		for name, b := range m.binds {
+			match.Binds[name] = b.text(fset, src)
+		}
+
+		matches = append(matches, match)
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		switch p.kind {
+		case kindExpr:
+			if expr, ok := n.(ast.Expr); ok {
+				if m := newMatcher(); m.match(p.expr, expr) {
+					add(n, n.Pos(), n.End(), m)
+				}
+			}
+		case kindDecl:
+			if decl, ok := n.(ast.Decl); ok {
+				if m := newMatcher(); m.match(p.decl, decl) {
+					add(n, n.Pos(), n.End(), m)
+				}
+			}
+		case kindStmts:
+			if list := stmtList(n); list != nil {
+				p.matchSequences(list, add)
+			}
+		}
+
+		return true
+	})
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if a.Pos.Offset != b.Pos.Offset {
+			return a.Pos.Offset - b.Pos.Offset
+		}
+
+		return b.End.Offset - a.End.Offset
+	})
+
+	return matches
+}
+
+// MatchNode reports whether n matches the pattern and, if so, the nodes
+// bound to its named single wildcards. A statement-list pattern only
+// matches a statement when it consists of one statement.
+func (p *Pattern) MatchNode(n ast.Node) (map[string]ast.Node, bool) {
+	var root ast.Node
+
+	switch p.kind {
+	case kindExpr:
+		root = p.expr
+	case kindDecl:
+		root = p.decl
+	case kindStmts:
+		if len(p.stmts) != 1 {
+			return nil, false
+		}
+
+		root = p.stmts[0]
+	}
+
+	m := newMatcher()
+	if !m.match(root, n) {
+		return nil, false
+	}
+
+	binds := map[string]ast.Node{}
+
+	for name, b := range m.binds {
+		if !b.isList {
+			binds[name] = b.node
+		}
+	}
+
+	return binds, true
+}
+
+// matchSequences reports the shortest run of statements matching the
+// pattern that starts at each statement of list.
+func (p *Pattern) matchSequences(list []ast.Stmt, add func(ast.Node, token.Pos, token.Pos, *matcher)) {
+	for i := range list {
+		for j := i + 1; j <= len(list); j++ {
+			m := newMatcher()
+			if m.stmts(p.stmts, list[i:j]) {
+				add(list[i], list[i].Pos(), list[j-1].End(), m)
+
+				break
+			}
+		}
+	}
+}
+
+// check rejects a wildcard name used both as $name and $*name.
+func (p *Pattern) check() error {
+	var root ast.Node = p.expr
+
+	switch p.kind {
+	case kindStmts:
+		root = &ast.BlockStmt{List: p.stmts}
+	case kindDecl:
+		root = p.decl
+	}
+
+	seen := map[string]bool{}
+
+	var err error
+
+	ast.Inspect(root, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || err != nil {
+			return err == nil
+		}
+
+		name, list, ok := wildcardName(id)
+		if !ok || name == "_" {
+			return true
+		}
+
+		if prev, dup := seen[name]; dup && prev != list {
+			err = fmt.Errorf("gopattern: invalid pattern %q: $%s is used both as a single and a list wildcard", p.src, name)
+		}
+
+		seen[name] = list
+
+		return true
+	})
+
+	return err
+}
+
+// stmtList returns the statements directly held by n, if it holds any.
+func stmtList(n ast.Node) []ast.Stmt {
+	switch n := n.(type) {
+	case *ast.BlockStmt:
+		return n.List
+	case *ast.CaseClause:
+		return n.Body
+	case *ast.CommClause:
+		return n.Body
+	}
+
+	return nil
+}
+
+// rewriteWildcards replaces $name and $*name outside literals with
+// identifiers the parser accepts.
+func rewriteWildcards(src string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+
+		switch c {
+		case '"', '\'', '`':
+			end := literalEnd(src, i)
+			b.WriteString(src[i:end])
+			i = end - 1
+		case '$':
+			prefix, j := singlePrefix, i+1
+			if j < len(src) && src[j] == '*' {
+				prefix, j = listPrefix, j+1
+			}
+
+			k := j
+			for k < len(src) && isIdentByte(src[k], k == j) {
+				k++
+			}
+
+			if k == j {
+				return "", fmt.Errorf("gopattern: invalid pattern %q: $ at offset %d is not followed by a name", src, i)
+			}
+
+			b.WriteString(prefix + src[j:k])
+			i = k - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// literalEnd returns the offset just past the string, rune or raw string
+// literal starting at src[start].
+func literalEnd(src string, start int) int {
+	quote := src[start]
+
+	for i := start + 1; i < len(src); i++ {
+		switch {
+		case src[i] == '\\' && quote != '`':
+			i++
+		case src[i] == quote:
+			return i + 1
+		}
+	}
+
+	return len(src)
+}
+
+func isIdentByte(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+// wildcardName returns the name of a wildcard identifier and whether it is
+// a list wildcard.
+func wildcardName(id *ast.Ident) (name string, list, ok bool) {
+	if id == nil {
+		return "", false, false
+	}
+
+	if name, ok := strings.CutPrefix(id.Name, singlePrefix); ok {
+		return name, false, true
+	}
+
+	if name, ok := strings.CutPrefix(id.Name, listPrefix); ok {
+		return name, true, true
+	}
+
+	return "", false, false
+}
+
+func parseStmts(src string) ([]ast.Stmt, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p; func _() {\n"+src+"\n}", parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Decls[0].(*ast.FuncDecl).Body.List, nil
+}
+
+func parseDecl(src string) (ast.Decl, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file.Decls) != 1 {
+		return nil, errors.New("not a single declaration")
+	}
+
+	return file.Decls[0], nil
+}
+
+// nodeText returns the source between from and to, or the printed node
+// when the source is not available.
+func nodeText(fset *token.FileSet, src []byte, from, to token.Pos, node ast.Node) string {
+	if src != nil {
+		start, end := fset.Position(from).Offset, fset.Position(to).Offset
+		if start >= 0 && start <= end && end <= len(src) {
+			return string(src[start:end])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,179 @@
+package gopattern
+
+import (
+	"go/ast"
+	"go/parser"
+	"strings"
+	"testing"
+)
+
+const source = `package demo
+
+import (
+	"errors"
+	"fmt"
+)
+
+func load(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+
+	err := open(name)
+	if err != nil {
+		return fmt.Errorf("load %s: %w", name, err)
+	}
+
+	x := 1
+	x = x
+	fmt.Println("loaded", name)
+	fmt.Errorf("unused")
+
+	return nil
+}
+
+func open(string) error { return nil }
+
+func wrap(args ...any) error { return fmt.Errorf("%v", args...) }
+`
+
+func matchTexts(t *testing.T, pattern string) []string {
+	t.Helper()
+
+	p, err := Compile(pattern)
+	if err != nil {
+		t.Fatalf("Compile(%q) = %v", pattern, err)
+	}
+
+	matches, err := p.MatchSource("demo.go", []byte(source))
+	if err != nil {
+		t.Fatalf("MatchSource() = %v", err)
+	}
+
+	texts := make([]string, len(matches))
+	for i, m := range matches {
+		texts[i] = m.Text
+	}
+
+	return texts
+}
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{`fmt.Errorf($_)`, []string{`fmt.Errorf("unused")`}},
+		{`fmt.Errorf($*_)`, []string{`fmt.Errorf("load %s: %w", name, err)`, `fmt.Errorf("unused")`, `fmt.Errorf("%v", args...)`}},
+		{`fmt.Errorf($_, $*_, err)`, []string{`fmt.Errorf("load %s: %w", name, err)`}},
+		{`fmt.Errorf($f, $x...)`, []string{`fmt.Errorf("%v", args...)`}},
+		{`errors.New("empty name")`, []string{`errors.New("empty name")`}},
+		{`errors.New("other")`, nil},
+		{`$x = $x`, []string{`x = x`}},
+		{`$x == ""`, []string{`name == ""`}},
+		{"if $err != nil {\n\treturn $*_\n}", []string{"if err != nil {\n\t\treturn fmt.Errorf(\"load %s: %w\", name, err)\n\t}"}},
+		{"$err := $f($*_); if $err != nil { $*_ }", []string{"err := open(name)\n\tif err != nil {\n\t\treturn fmt.Errorf(\"load %s: %w\", name, err)\n\t}"}},
+		{`func $name($*_) error { return nil }`, []string{`func open(string) error { return nil }`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := matchTexts(t, tt.pattern)
+
+			if strings.Join(got, "\n|\n") != strings.Join(tt.want, "\n|\n") {
+				t.Errorf("matches = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPositionsAndBinds(t *testing.T) {
+	p, err := Compile(`fmt.Errorf($msg, $*args)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := p.MatchSource("demo.go", []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 3 {
+		t.Fatalf("got %d matches, want 3", len(matches))
+	}
+
+	m := matches[0]
+
+	if m.Pos.Filename != "demo.go" || m.Pos.Line != 15 || m.Pos.Column != 10 {
+		t.Errorf("Pos = %v, want demo.go:15:10", m.Pos)
+	}
+
+	if m.End.Line != 15 || m.End.Column != 46 {
+		t.Errorf("End = %v, want line 15 column 46", m.End)
+	}
+
+	if m.Binds["msg"] != `"load %s: %w"` || m.Binds["args"] != "name, err" {
+		t.Errorf("Binds = %q", m.Binds)
+	}
+
+	if m.Binds = matches[1].Binds; m.Binds["args"] != "" {
+		t.Errorf("an empty list should bind to \"\", got %q", m.Binds["args"])
+	}
+}
+
+func TestMatchNode(t *testing.T) {
+	p, err := Compile(`$a + $a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for src, want := range map[string]bool{"x + x": true, "f(1) + f(1)": true, "x + y": false, "x - x": false} {
+		expr, err := parser.ParseExpr(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		binds, ok := p.MatchNode(expr)
+		if ok != want {
+			t.Errorf("MatchNode(%s) = %v, want %v", src, ok, want)
+		}
+
+		if ok {
+			if _, isNode := binds["a"].(ast.Expr); !isNode {
+				t.Errorf("MatchNode(%s) binds = %v, want $a bound", src, binds)
+			}
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, pattern := range []string{`fmt.Errorf(`, `$`, `f($x, $*x)`, `$*`} {
+		if _, err := Compile(pattern); err == nil {
+			t.Errorf("Compile(%q) succeeded, want an error", pattern)
+		}
+	}
+}
+
+func TestWildcardInsideLiteral(t *testing.T) {
+	p, err := Compile(`fmt.Sprintf("$x")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := p.MatchSource("a.go", []byte("package a\nvar _ = fmt.Sprintf(\"$x\")\nvar _ = fmt.Sprintf(\"y\")\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 {
+		t.Errorf("got %d matches, want only the literal \"$x\"", len(matches))
+	}
+}
+
+func TestMatchSourceParseError(t *testing.T) {
+	p, _ := Compile(`$_`)
+
+	if _, err := p.MatchSource("bad.go", []byte("package\n")); err == nil {
+		t.Error("MatchSource() on invalid Go should fail")
+	}
+}
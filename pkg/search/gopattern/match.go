@@ -0,0 +1,264 @@
+package gopattern
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"reflect"
+)
+
+var (
+	posType      = reflect.TypeFor[token.Pos]()
+	objectType   = reflect.TypeFor[*ast.Object]()
+	scopeType    = reflect.TypeFor[*ast.Scope]()
+	commentsType = reflect.TypeFor[*ast.CommentGroup]()
+	callExprType = reflect.TypeFor[ast.CallExpr]()
+)
+
+// binding is what a named wildcard matched: one node, or a run of list
+// elements.
+type binding struct {
+	node   ast.Node
+	list   reflect.Value
+	isList bool
+}
+
+// text returns the source of the bound code.
+func (b binding) text(fset *token.FileSet, src []byte) string {
+	if !b.isList {
+		return nodeText(fset, src, b.node.Pos(), b.node.End(), b.node)
+	}
+
+	if b.list.Len() == 0 {
+		return ""
+	}
+
+	first, _ := b.list.Index(0).Interface().(ast.Node)
+	last, _ := b.list.Index(b.list.Len() - 1).Interface().(ast.Node)
+
+	if first == nil || last == nil || src == nil {
+		return ""
+	}
+
+	return nodeText(fset, src, first.Pos(), last.End(), first)
+}
+
+// matcher compares a pattern tree with a source tree, recording what the
+// named wildcards matched.
+type matcher struct {
+	binds map[string]binding
+}
+
+func newMatcher() *matcher {
+	return &matcher{binds: map[string]binding{}}
+}
+
+// match reports whether the source node n matches the pattern node p.
+func (m *matcher) match(p, n ast.Node) bool {
+	if isNil(p) || isNil(n) {
+		return isNil(p) && isNil(n)
+	}
+
+	if id, ok := p.(*ast.Ident); ok {
+		if name, list, ok := wildcardName(id); ok && !list {
+			return m.bind(name, binding{node: n})
+		}
+	}
+
+	// A wildcard on its own line stands for any statement
+	if es, ok := p.(*ast.ExprStmt); ok {
+		if id, ok := es.X.(*ast.Ident); ok {
+			if name, list, ok := wildcardName(id); ok && !list {
+				if _, isStmt := n.(ast.Stmt); isStmt {
+					return m.bind(name, binding{node: n})
+				}
+			}
+		}
+	}
+
+	pv, nv := reflect.ValueOf(p), reflect.ValueOf(n)
+	if pv.Type() != nv.Type() {
+		return false
+	}
+
+	return m.value(pv.Elem(), nv.Elem())
+}
+
+// value compares two values of the same type field by field, ignoring
+// positions, comments and resolver objects.
+func (m *matcher) value(p, n reflect.Value) bool {
+	switch p.Kind() {
+	case reflect.Struct:
+		for i := range p.NumField() {
+			field := p.Type().Field(i)
+
+			switch field.Type {
+			case posType:
+				// f(x...) and f(x) differ only in the Ellipsis position
+				if p.Type() == callExprType && field.Name == "Ellipsis" && !ellipsisMatches(p, n) {
+					return false
+				}
+
+				continue
+			case objectType, scopeType, commentsType:
+				continue
+			}
+
+			if !m.value(p.Field(i), n.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Interface, reflect.Pointer:
+		if p.IsNil() || n.IsNil() {
+			return p.IsNil() && n.IsNil()
+		}
+
+		pn, pok := p.Interface().(ast.Node)
+		nn, nok := n.Interface().(ast.Node)
+
+		if pok && nok {
+			return m.match(pn, nn)
+		}
+
+		if p.Elem().Type() != n.Elem().Type() {
+			return false
+		}
+
+		return m.value(p.Elem(), n.Elem())
+	case reflect.Slice:
+		return m.list(p, n)
+	case reflect.String:
+		return p.String() == n.String()
+	case reflect.Bool:
+		return p.Bool() == n.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return p.Int() == n.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return p.Uint() == n.Uint()
+	}
+
+	return false
+}
+
+// list matches a slice of pattern elements against a slice of source
+// elements, letting each list wildcard absorb any number of elements.
+func (m *matcher) list(p, n reflect.Value) bool {
+	if p.Len() == 0 {
+		return n.Len() == 0
+	}
+
+	rest := p.Slice(1, p.Len())
+
+	if name, ok := listWildcard(p.Index(0)); ok {
+		for k := 0; k <= n.Len(); k++ {
+			saved := maps.Clone(m.binds)
+
+			if m.bind(name, binding{list: n.Slice(0, k), isList: true}) && m.list(rest, n.Slice(k, n.Len())) {
+				return true
+			}
+
+			m.binds = saved
+		}
+
+		return false
+	}
+
+	if n.Len() == 0 {
+		return false
+	}
+
+	saved := maps.Clone(m.binds)
+
+	if m.value(p.Index(0), n.Index(0)) && m.list(rest, n.Slice(1, n.Len())) {
+		return true
+	}
+
+	m.binds = saved
+
+	return false
+}
+
+// stmts matches a statement sequence.
+func (m *matcher) stmts(p, n []ast.Stmt) bool {
+	return m.list(reflect.ValueOf(p), reflect.ValueOf(n))
+}
+
+// bind records what a named wildcard matched; a name seen before must
+// match the same code again. $_ and $*_ are never recorded.
+func (m *matcher) bind(name string, b binding) bool {
+	if name == "_" {
+		return true
+	}
+
+	prev, ok := m.binds[name]
+	if !ok {
+		m.binds[name] = b
+
+		return true
+	}
+
+	// The bound code holds no wildcards, so it is compared literally
+	if prev.isList {
+		return b.isList && newMatcher().list(prev.list, b.list)
+	}
+
+	return !b.isList && newMatcher().match(prev.node, b.node)
+}
+
+// listWildcard reports whether a list element is a $*name wildcard: an
+// expression, a statement of one, or a parameter or field of that type.
+func listWildcard(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", false
+		}
+	}
+
+	var id *ast.Ident
+
+	switch e := v.Interface().(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.ExprStmt:
+		id, _ = e.X.(*ast.Ident)
+	case *ast.Field:
+		if len(e.Names) == 0 {
+			id, _ = e.Type.(*ast.Ident)
+		}
+	}
+
+	name, list, ok := wildcardName(id)
+
+	return name, ok && list
+}
+
+// ellipsisMatches compares the ... of two calls. A pattern without one
+// that ends in a list wildcard, as in f($*_), accepts either form.
+func ellipsisMatches(p, n reflect.Value) bool {
+	pe := p.FieldByName("Ellipsis").Interface().(token.Pos).IsValid()
+	ne := n.FieldByName("Ellipsis").Interface().(token.Pos).IsValid()
+
+	if pe == ne {
+		return true
+	}
+
+	if args := p.FieldByName("Args"); !pe && args.Len() > 0 {
+		_, ok := listWildcard(args.Index(args.Len() - 1))
+
+		return ok
+	}
+
+	return false
+}
+
+func isNil(n ast.Node) bool {
+	if n == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(n)
+
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
@@ -2,7 +2,11 @@
 package grep
 
 import (
+	"bufio"
+	"io"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -77,6 +81,77 @@ func CompilePattern(pattern string, opts Options) (*regexp.Regexp, error) {
 	return compilePattern(pattern, opts)
 }
 
+// CompilePatterns compiles several grep patterns into one regexp that
+// matches wherever any of them does. Each pattern gets the same treatment
+// as in CompilePattern (quoting, BRE conversion, word and line anchors).
+// An empty set matches nothing, like grep -f on an empty file.
+func CompilePatterns(patterns []string, opts Options) (*regexp.Regexp, error) {
+	switch len(patterns) {
+	case 0:
+		return regexp.Compile(`[^\x00-\x{10FFFF}]`)
+	case 1:
+		return compilePattern(patterns[0], opts)
+	}
+
+	parts := make([]string, len(patterns))
+	for i, p := range patterns {
+		parts[i] = "(?:" + expandPattern(p, opts) + ")"
+	}
+
+	flags := ""
+	if opts.IgnoreCase {
+		flags = "(?i)"
+	}
+
+	return regexp.Compile(flags + strings.Join(parts, "|"))
+}
+
+// ReadPatterns reads one pattern per line, as grep -f does. A trailing
+// carriage return is dropped, and an empty line is an empty pattern that
+// matches every line.
+func ReadPatterns(r io.Reader) ([]string, error) {
+	var patterns []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		patterns = append(patterns, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+
+	return patterns, scanner.Err()
+}
+
+// ReadPatternFiles reads the patterns of every file in paths, in order.
+// The path "-" reads stdin.
+func ReadPatternFiles(paths []string, stdin io.Reader) ([]string, error) {
+	var patterns []string
+
+	for _, path := range paths {
+		r := stdin
+
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+
+			defer func() { _ = f.Close() }()
+
+			r = f
+		}
+
+		p, err := ReadPatterns(r)
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, p...)
+	}
+
+	return patterns, nil
+}
+
 func searchWithOptions(lines []string, pattern string, opt Options) []string {
 	out := []string{}
 
@@ -149,6 +224,16 @@ func convertBREtoERE(pattern string) string {
 }
 
 func compilePattern(pattern string, opts Options) (*regexp.Regexp, error) {
+	flags := ""
+	if opts.IgnoreCase {
+		flags = "(?i)"
+	}
+
+	return regexp.Compile(flags + expandPattern(pattern, opts))
+}
+
+// expandPattern turns a grep pattern into Go regexp syntax.
+func expandPattern(pattern string, opts Options) string {
 	if opts.FixedStrings {
 		pattern = regexp.QuoteMeta(pattern)
 	} else if !opts.ExtendedRegexp {
@@ -164,10 +249,88 @@ func compilePattern(pattern string, opts Options) (*regexp.Regexp, error) {
 		pattern = "^" + pattern + "$"
 	}
 
-	flags := ""
-	if opts.IgnoreCase {
-		flags = "(?i)"
+	return pattern
+}
+
+// Counts holds the result of counting a pattern.
+type Counts struct {
+	Lines   int // Lines with at least one match (-c)
+	Matches int // Individual, non-overlapping matches (--count-matches)
+}
+
+// Count counts the lines of lines matching pattern and the total number of
+// matches within them. With InvertMatch, Lines is the number of
+// non-matching lines and every such line counts as a single match.
+func Count(lines []string, pattern string, opt Options) (Counts, error) {
+	re, err := compilePattern(pattern, opt)
+	if err != nil {
+		return Counts{}, err
+	}
+
+	var c Counts
+
+	for _, l := range lines {
+		n := len(re.FindAllStringIndex(l, -1))
+
+		switch {
+		case opt.InvertMatch && n == 0:
+			c.Lines++
+			c.Matches++
+		case !opt.InvertMatch && n > 0:
+			c.Lines++
+			c.Matches += n
+		}
+	}
+
+	return c, nil
+}
+
+// CountMultiline counts re over data as a single buffer, so a match may span
+// several lines. Lines is the number of distinct lines touched by any match.
+// With invert, Lines is the number of lines not touched by any match and
+// every such line counts as a single match.
+func CountMultiline(re *regexp.Regexp, data []byte, invert bool) Counts {
+	// Offsets at which each line starts
+	starts := []int{0}
+
+	for i, b := range data {
+		if b == '\n' && i+1 < len(data) {
+			starts = append(starts, i+1)
+		}
+	}
+
+	lineOf := func(off int) int {
+		return sort.Search(len(starts), func(i int) bool { return starts[i] > off }) - 1
+	}
+
+	var c Counts
+
+	last := -1
+
+	for _, loc := range re.FindAllIndex(data, -1) {
+		c.Matches++
+
+		first, end := lineOf(loc[0]), lineOf(max(loc[1]-1, loc[0]))
+		if first <= last {
+			first = last + 1
+		}
+
+		if end >= first {
+			c.Lines += end - first + 1
+			last = end
+		}
+	}
+
+	if invert {
+		total := len(starts)
+		if len(data) == 0 {
+			total = 0
+		}
+
+		untouched := total - c.Lines
+
+		return Counts{Lines: untouched, Matches: untouched}
 	}
 
-	return regexp.Compile(flags + pattern)
+	return c
 }
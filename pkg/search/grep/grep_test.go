@@ -1,6 +1,9 @@
 package grep
 
 import (
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -236,3 +239,103 @@ func TestCompilePattern(t *testing.T) {
 		}
 	})
 }
+
+func TestCount(t *testing.T) {
+	lines := []string{"foo foo", "bar", "foo", "baz"}
+
+	tests := []struct {
+		name string
+		opt  Options
+		want Counts
+	}{
+		{"lines and matches", Options{}, Counts{Lines: 2, Matches: 3}},
+		{"invert", Options{InvertMatch: true}, Counts{Lines: 2, Matches: 2}},
+		{"ignore case", Options{IgnoreCase: true}, Counts{Lines: 2, Matches: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Count(lines, "foo", tt.opt)
+			if err != nil {
+				t.Fatalf("Count() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Count() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Count(lines, "[bad", Options{ExtendedRegexp: true}); err == nil {
+		t.Error("Count() should error on invalid regex")
+	}
+}
+
+func TestCountMultiline(t *testing.T) {
+	data := []byte("start\nmiddle\nend\nstart\nend\nother\n")
+	re := regexp.MustCompile(`(?s)start.*?end`)
+
+	got := CountMultiline(re, data, false)
+	if want := (Counts{Lines: 5, Matches: 2}); got != want {
+		t.Errorf("CountMultiline() = %+v, want %+v", got, want)
+	}
+
+	got = CountMultiline(re, data, true)
+	if want := (Counts{Lines: 1, Matches: 1}); got != want {
+		t.Errorf("CountMultiline(invert) = %+v, want %+v", got, want)
+	}
+
+	got = CountMultiline(regexp.MustCompile(`o`), []byte("foo\nbar\nbo"), false)
+	if want := (Counts{Lines: 2, Matches: 3}); got != want {
+		t.Errorf("CountMultiline(single line) = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompilePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		opts     Options
+		match    []string
+		noMatch  []string
+	}{
+		{"alternation", []string{"foo", "ba+r"}, Options{ExtendedRegexp: true}, []string{"a foo", "baar"}, []string{"fo", "bz"}},
+		{"fixed", []string{"a.b", "(x)"}, Options{FixedStrings: true}, []string{"a.b", "(x)"}, []string{"axb", "x"}},
+		{"word", []string{"cat", "dog"}, Options{WordRegexp: true}, []string{"a cat", "dog!"}, []string{"cats", "hotdog"}},
+		{"line", []string{"a", "b"}, Options{LineRegexp: true}, []string{"a", "b"}, []string{"ab"}},
+		{"ignore case", []string{"x", "Y"}, Options{IgnoreCase: true}, []string{"X", "y"}, []string{"z"}},
+		{"bre", []string{`a\|b`, "c"}, Options{}, []string{"b", "c"}, []string{"d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := CompilePatterns(tt.patterns, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, s := range tt.match {
+				if !re.MatchString(s) {
+					t.Errorf("%q should match %q", re, s)
+				}
+			}
+
+			for _, s := range tt.noMatch {
+				if re.MatchString(s) {
+					t.Errorf("%q should not match %q", re, s)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPatterns(t *testing.T) {
+	got, err := ReadPatterns(strings.NewReader("one\r\n\ntwo\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"one", "", "two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadPatterns() = %q, want %q", got, want)
+	}
+}
@@ -1,5 +1,8 @@
 // Package rg provides gitignore pattern parsing and matching, file type
-// extension filtering, glob matching, and binary file detection. It
-// implements the full gitignore specification including negation patterns,
-// directory-only patterns, and double-glob (**) matching.
+// filtering (with ripgrep-style custom type definitions through TypeSet),
+// glob matching, and binary file detection. It implements the full
+// gitignore specification including negation patterns, directory-only
+// patterns, and double-glob (**) matching. IgnoreMatcher is a compiled
+// rule set that can be built incrementally and serialized, and
+// LoadIgnoreMatcher caches it keyed by the hashes of the ignore files.
 package rg
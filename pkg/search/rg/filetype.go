@@ -1,9 +1,10 @@
 package rg
 
 import (
-	"bytes"
 	"path/filepath"
 	"strings"
+
+	"github.com/inovacc/omni/pkg/mimetype"
 )
 
 // FileTypeExtensions maps file type names to extensions
@@ -116,7 +117,8 @@ func MatchesGlob(path string, patterns []string) bool {
 	return allNegations
 }
 
-// IsBinary checks if data contains null bytes, indicating it's binary content.
+// IsBinary reports whether data is binary content: it contains a null byte
+// or starts with the signature of a non-text format (see mimetype.IsBinary).
 func IsBinary(data []byte) bool {
-	return bytes.Contains(data, []byte{0})
+	return mimetype.IsBinary(data)
 }
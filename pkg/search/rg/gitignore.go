@@ -72,6 +72,25 @@ func ParseGitignore(content, basePath string) *Gitignore {
 		BasePath: basePath,
 	}
 
+	for _, line := range patternLines(content) {
+		pattern := ParsePattern(line)
+		if pattern != nil {
+			gi.Patterns = append(gi.Patterns, *pattern)
+		}
+	}
+
+	if len(gi.Patterns) == 0 {
+		return nil
+	}
+
+	return gi
+}
+
+// patternLines returns the pattern lines of gitignore content, without
+// blank lines and comments.
+func patternLines(content string) []string {
+	var lines []string
+
 	for line := range strings.SplitSeq(content, "\n") {
 		line = strings.TrimRight(line, "\r")
 
@@ -85,17 +104,10 @@ func ParseGitignore(content, basePath string) *Gitignore {
 			line = line[1:]
 		}
 
-		pattern := ParsePattern(line)
-		if pattern != nil {
-			gi.Patterns = append(gi.Patterns, *pattern)
-		}
+		lines = append(lines, line)
 	}
 
-	if len(gi.Patterns) == 0 {
-		return nil
-	}
-
-	return gi
+	return lines
 }
 
 // ParsePattern parses a gitignore pattern string into a Pattern
@@ -331,22 +343,23 @@ func (gs *GitignoreSet) ShouldIgnore(path string, isDir bool) bool {
 	return gs.Match(path, isDir) == Ignore
 }
 
+// commonIgnores are directories that are never worth searching.
+var commonIgnores = []string{
+	".git",
+	"node_modules",
+	"__pycache__",
+	".idea",
+	".vscode",
+}
+
 // AddCommonIgnores adds common patterns that should always be ignored
 func (gs *GitignoreSet) AddCommonIgnores() {
-	commonPatterns := []string{
-		".git",
-		"node_modules",
-		"__pycache__",
-		".idea",
-		".vscode",
-	}
-
 	gi := &Gitignore{
-		Patterns: make([]Pattern, 0, len(commonPatterns)),
+		Patterns: make([]Pattern, 0, len(commonIgnores)),
 		BasePath: gs.BasePath,
 	}
 
-	for _, pat := range commonPatterns {
+	for _, pat := range commonIgnores {
 		if p := ParsePattern(pat); p != nil {
 			gi.Patterns = append(gi.Patterns, *p)
 		}
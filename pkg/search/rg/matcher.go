@@ -0,0 +1,563 @@
+package rg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxIgnoreCacheBytes bounds a LoadIgnoreMatcher cache directory; the
+// least recently used entries are removed when a new one takes it over the
+// limit. It is a var only so tests can lower it.
+var maxIgnoreCacheBytes int64 = 64 << 20
+
+// ignoreFormatVersion changes whenever the serialized form or the rule
+// semantics change, so stale cache entries are never reused.
+const ignoreFormatVersion = 1
+
+// IgnoreFile names an ignore file and the directory its patterns are
+// relative to.
+type IgnoreFile struct {
+	Path     string
+	BasePath string
+}
+
+// IgnoreSource records an ignore file compiled into an IgnoreMatcher,
+// with the SHA-256 of its content.
+type IgnoreSource struct {
+	Path     string
+	BasePath string
+	Hash     string
+}
+
+// IgnoreMatcher is a compiled GitignoreSet. Literal and "*.ext" rules are
+// matched without regular expressions, the remaining expressions compile
+// on first use, and the whole matcher serializes with MarshalBinary so a
+// run can reuse the rules compiled by an earlier one (see
+// LoadIgnoreMatcher). Rules are added incrementally with Add and AddFile;
+// later rules take precedence. It is safe for concurrent matching once
+// fully built.
+type IgnoreMatcher struct {
+	basePath string
+	groups   []*ruleGroup
+	sources  []IgnoreSource
+}
+
+type ruleGroup struct {
+	basePath string
+	rules    []*rule
+}
+
+type ruleKind uint8
+
+const (
+	ruleLiteral ruleKind = iota // text must equal the name
+	ruleSuffix                  // a name without "/" ending in text
+	ruleRegex                   // text is a regular expression
+)
+
+type rule struct {
+	kind       ruleKind
+	text       string
+	negation   bool
+	dirOnly    bool
+	anchored   bool
+	components bool   // also try every path component
+	need       string // literal every match contains, checked before the regexp
+
+	once sync.Once
+	re   *regexp.Regexp
+}
+
+// NewIgnoreMatcher creates an empty matcher for paths under basePath.
+func NewIgnoreMatcher(basePath string) *IgnoreMatcher {
+	absPath, _ := filepath.Abs(basePath)
+
+	return &IgnoreMatcher{basePath: absPath}
+}
+
+// Compile converts gs into an IgnoreMatcher with the same results.
+func (gs *GitignoreSet) Compile() *IgnoreMatcher {
+	m := &IgnoreMatcher{basePath: gs.BasePath}
+
+	for _, gi := range gs.Gitignores {
+		g := &ruleGroup{basePath: gi.BasePath}
+		for _, p := range gi.Patterns {
+			g.rules = append(g.rules, compileRule(p.Original))
+		}
+
+		m.groups = append(m.groups, g)
+	}
+
+	return m
+}
+
+// Add parses gitignore content relative to basePath and adds its rules
+// with a higher precedence than every rule already added.
+func (m *IgnoreMatcher) Add(content, basePath string) {
+	lines := patternLines(content)
+	if len(lines) == 0 {
+		return
+	}
+
+	g := &ruleGroup{basePath: basePath}
+	for _, line := range lines {
+		g.rules = append(g.rules, compileRule(line))
+	}
+
+	m.groups = append(m.groups, g)
+}
+
+// AddFile adds the rules of an ignore file and records it as a source. A
+// missing file is skipped.
+func (m *IgnoreMatcher) AddFile(path, basePath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	m.addSource(path, basePath, data)
+
+	return nil
+}
+
+func (m *IgnoreMatcher) addSource(path, basePath string, data []byte) {
+	sum := sha256.Sum256(data)
+	m.sources = append(m.sources, IgnoreSource{Path: path, BasePath: basePath, Hash: hex.EncodeToString(sum[:])})
+	m.Add(string(data), basePath)
+}
+
+// AddCommonIgnores adds the directories GitignoreSet.AddCommonIgnores
+// ignores, with the lowest precedence.
+func (m *IgnoreMatcher) AddCommonIgnores() {
+	g := &ruleGroup{basePath: m.basePath}
+	for _, pat := range commonIgnores {
+		g.rules = append(g.rules, compileRule(pat))
+	}
+
+	m.groups = append([]*ruleGroup{g}, m.groups...)
+}
+
+// Sources returns the ignore files compiled into the matcher, in order.
+func (m *IgnoreMatcher) Sources() []IgnoreSource {
+	return slices.Clone(m.sources)
+}
+
+// Len returns the number of rules.
+func (m *IgnoreMatcher) Len() int {
+	n := 0
+	for _, g := range m.groups {
+		n += len(g.rules)
+	}
+
+	return n
+}
+
+// Match checks if a path should be ignored, exactly like
+// GitignoreSet.Match. Returns Ignore, Include (negation), or NoMatch.
+func (m *IgnoreMatcher) Match(path string, isDir bool) MatchResult {
+	if filepath.IsAbs(path) && m.basePath != "" {
+		if rel, err := filepath.Rel(m.basePath, path); err == nil {
+			path = rel
+		}
+	}
+
+	path = filepath.ToSlash(path)
+	path = strings.TrimPrefix(path, "./")
+
+	base := filepath.Base(path)
+	result := NoMatch
+
+	for _, g := range m.groups {
+		var parts []string
+
+		relPath := path
+		if g.basePath != "" && filepath.IsAbs(path) {
+			if absBase, err := filepath.Abs(g.basePath); err == nil {
+				if rel, err := filepath.Rel(absBase, path); err == nil {
+					relPath = filepath.ToSlash(rel)
+				}
+			}
+		}
+
+		for _, r := range g.rules {
+			if r.dirOnly && !isDir {
+				continue
+			}
+
+			matched := r.match(relPath) || (!r.anchored && r.match(base))
+			if !matched && r.components {
+				if parts == nil {
+					parts = strings.Split(relPath, "/")
+				}
+
+				matched = slices.ContainsFunc(parts, r.match)
+			}
+
+			if matched {
+				if r.negation {
+					result = Include
+				} else {
+					result = Ignore
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// ShouldIgnore is a convenience method that returns true if the path should be ignored
+func (m *IgnoreMatcher) ShouldIgnore(path string, isDir bool) bool {
+	return m.Match(path, isDir) == Ignore
+}
+
+// compileRule classifies one gitignore line, mirroring ParsePattern.
+func compileRule(line string) *rule {
+	r := &rule{}
+	pattern := line
+
+	if strings.HasPrefix(pattern, "!") {
+		r.negation = true
+		pattern = pattern[1:]
+	}
+
+	if strings.HasPrefix(pattern, "\\!") {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	if strings.HasPrefix(pattern, "/") {
+		r.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else if strings.Contains(pattern, "/") {
+		r.anchored = true
+	}
+
+	r.components = !r.anchored && !strings.Contains(line, "/")
+
+	switch {
+	case !strings.ContainsAny(pattern, `*?[\`):
+		r.kind, r.text = ruleLiteral, pattern
+	case strings.HasPrefix(pattern, "*") && !strings.ContainsAny(pattern[1:], `*?[\/`):
+		r.kind, r.text = ruleSuffix, pattern[1:]
+	default:
+		r.kind, r.text, r.need = ruleRegex, PatternToRegex(pattern), requiredLiteral(pattern)
+	}
+
+	return r
+}
+
+// requiredLiteral returns the longest run of plain characters in a glob
+// pattern. Any name the pattern matches contains it, so a name without it
+// is rejected without compiling the expression. The "/" after "*" is left
+// out since "**/" may match nothing.
+func requiredLiteral(pattern string) string {
+	var best, cur string
+
+	flush := func() {
+		if len(cur) > len(best) {
+			best = cur
+		}
+
+		cur = ""
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			flush()
+
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				i++
+			}
+		case '?', '\\':
+			flush()
+
+			if c == '\\' {
+				i++
+			}
+		case '[':
+			flush()
+
+			// Skip the class the way PatternToRegex reads it
+			j := i + 1
+			if j < len(pattern) && pattern[j] == '!' {
+				j++
+			}
+
+			if j < len(pattern) && pattern[j] == ']' {
+				j++
+			}
+
+			if end := strings.IndexByte(pattern[j:], ']'); end >= 0 {
+				i = j + end
+			}
+		default:
+			cur += string(c)
+		}
+	}
+
+	flush()
+
+	return best
+}
+
+func (r *rule) match(s string) bool {
+	switch r.kind {
+	case ruleLiteral:
+		return s == r.text
+	case ruleSuffix:
+		return strings.HasSuffix(s, r.text) && !strings.Contains(s, "/")
+	}
+
+	if !strings.Contains(s, r.need) {
+		return false
+	}
+
+	r.once.Do(func() {
+		// An expression that fails to compile never matches, like the
+		// patterns ParsePattern drops
+		r.re, _ = regexp.Compile(r.text)
+	})
+
+	return r.re != nil && r.re.MatchString(s)
+}
+
+type ignoreSnapshot struct {
+	Version  int
+	BasePath string
+	Sources  []IgnoreSource
+	Groups   []groupSnapshot
+}
+
+type groupSnapshot struct {
+	BasePath string
+	Rules    []ruleSnapshot
+}
+
+type ruleSnapshot struct {
+	Kind       ruleKind
+	Text       string
+	Negation   bool
+	DirOnly    bool
+	Anchored   bool
+	Components bool
+	Need       string
+}
+
+// MarshalBinary serializes the compiled rules and their sources.
+func (m *IgnoreMatcher) MarshalBinary() ([]byte, error) {
+	snap := ignoreSnapshot{Version: ignoreFormatVersion, BasePath: m.basePath, Sources: m.sources}
+
+	for _, g := range m.groups {
+		gs := groupSnapshot{BasePath: g.basePath}
+		for _, r := range g.rules {
+			gs.Rules = append(gs.Rules, ruleSnapshot{
+				Kind:       r.kind,
+				Text:       r.text,
+				Negation:   r.negation,
+				DirOnly:    r.dirOnly,
+				Anchored:   r.anchored,
+				Components: r.components,
+				Need:       r.need,
+			})
+		}
+
+		snap.Groups = append(snap.Groups, gs)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snap); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a matcher serialized by MarshalBinary.
+func (m *IgnoreMatcher) UnmarshalBinary(data []byte) error {
+	var snap ignoreSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snap); err != nil {
+		return err
+	}
+
+	if snap.Version != ignoreFormatVersion {
+		return fmt.Errorf("ignore matcher format %d, want %d", snap.Version, ignoreFormatVersion)
+	}
+
+	m.basePath, m.sources, m.groups = snap.BasePath, snap.Sources, nil
+
+	for _, gs := range snap.Groups {
+		g := &ruleGroup{basePath: gs.BasePath}
+		for _, r := range gs.Rules {
+			g.rules = append(g.rules, &rule{
+				kind:       r.Kind,
+				text:       r.Text,
+				negation:   r.Negation,
+				dirOnly:    r.DirOnly,
+				anchored:   r.Anchored,
+				components: r.Components,
+				need:       r.Need,
+			})
+		}
+
+		m.groups = append(m.groups, g)
+	}
+
+	return nil
+}
+
+// LoadIgnoreMatcher compiles files (later files take precedence) into a
+// matcher for paths under basePath. With a cacheDir, the compiled matcher
+// is stored there keyed by the base path and the hashes of the files, and
+// reused while none of them changes. The directory is kept under 64 MiB
+// by evicting the least recently used entries. Missing files are skipped;
+// cache failures fall back to compiling.
+func LoadIgnoreMatcher(basePath string, files []IgnoreFile, cacheDir string) (*IgnoreMatcher, error) {
+	m := NewIgnoreMatcher(basePath)
+
+	contents := make([][]byte, 0, len(files))
+	present := make([]IgnoreFile, 0, len(files))
+
+	key := sha256.New()
+	_, _ = fmt.Fprintf(key, "v%d\x00%s\x00", ignoreFormatVersion, m.basePath)
+
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		sum := sha256.Sum256(data)
+		_, _ = fmt.Fprintf(key, "%s\x00%s\x00%x\x00", f.Path, f.BasePath, sum)
+
+		contents = append(contents, data)
+		present = append(present, f)
+	}
+
+	cachePath := ""
+	if cacheDir != "" {
+		cachePath = filepath.Join(cacheDir, hex.EncodeToString(key.Sum(nil))+".gob")
+
+		if data, err := os.ReadFile(cachePath); err == nil {
+			cached := &IgnoreMatcher{}
+			if cached.UnmarshalBinary(data) == nil {
+				now := time.Now()
+				_ = os.Chtimes(cachePath, now, now)
+
+				return cached, nil
+			}
+		}
+	}
+
+	for i, f := range present {
+		m.addSource(f.Path, f.BasePath, contents[i])
+	}
+
+	if cachePath != "" && writeCacheFile(cachePath, m) == nil {
+		pruneCache(cacheDir, maxIgnoreCacheBytes)
+	}
+
+	return m, nil
+}
+
+// writeCacheFile stores m atomically so concurrent runs never read a
+// partial entry.
+func writeCacheFile(path string, m *IgnoreMatcher) error {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ignore-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// pruneCache removes the least recently used entries of dir until they
+// take at most limit bytes. Errors are ignored: another run may be pruning
+// the same directory.
+func pruneCache(dir string, limit int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var (
+		infos []os.FileInfo
+		total int64
+	)
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !strings.HasSuffix(e.Name(), ".gob") {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, info)
+		total += info.Size()
+	}
+
+	slices.SortFunc(infos, func(a, b os.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+
+	for _, info := range infos {
+		if total <= limit {
+			return
+		}
+
+		if os.Remove(filepath.Join(dir, info.Name())) == nil {
+			total -= info.Size()
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package rg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const matcherRules = `# build output
+*.log
+!keep.log
+build/
+/dist
+docs/*.md
+**/generated/**
+tmp?
+[Tt]humbs.db
+\#notes
+node_modules
+a/**/z
+x[]y]z
+**/cache/*.bin
+`
+
+var matcherPaths = []struct {
+	path  string
+	isDir bool
+}{
+	{"app.log", false},
+	{"sub/app.log", false},
+	{"keep.log", false},
+	{"sub/keep.log", false},
+	{"build", true},
+	{"build", false},
+	{"src/build", true},
+	{"dist", true},
+	{"src/dist", true},
+	{"docs/readme.md", false},
+	{"docs/sub/readme.md", false},
+	{"x/generated/file.go", false},
+	{"tmp1", false},
+	{"tmp12", false},
+	{"Thumbs.db", false},
+	{"thumbs.db", false},
+	{"#notes", false},
+	{"web/node_modules/pkg/index.js", false},
+	{"a/b/c/z", false},
+	{"main.go", false},
+	{"./app.log", false},
+	{"x]z", false},
+	{"xyz", false},
+	{"x]yz", false},
+	{"cache/a.bin", false},
+	{"p/cache/a.bin", false},
+	{"p/cache/sub/a.bin", false},
+}
+
+// TestIgnoreMatcherParity checks the compiled matcher against
+// GitignoreSet, after a serialization round trip too.
+func TestIgnoreMatcherParity(t *testing.T) {
+	base := t.TempDir()
+
+	gs := NewGitignoreSet(base)
+	gs.AddGitignore(ParseGitignore(matcherRules, base))
+	gs.AddGitignore(ParseGitignore("!sub/app.log\n", base))
+	gs.AddCommonIgnores()
+
+	m := NewIgnoreMatcher(base)
+	m.Add(matcherRules, base)
+	m.Add("!sub/app.log\n", base)
+	m.AddCommonIgnores()
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &IgnoreMatcher{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	matchers := map[string]*IgnoreMatcher{"incremental": m, "compiled": gs.Compile(), "restored": restored}
+
+	for name, cm := range matchers {
+		if cm.Len() != 19 {
+			t.Errorf("%s: Len() = %d, want 19", name, cm.Len())
+		}
+
+		for _, p := range matcherPaths {
+			for _, path := range []string{p.path, filepath.Join(base, p.path)} {
+				if got, want := cm.Match(path, p.isDir), gs.Match(path, p.isDir); got != want {
+					t.Errorf("%s: Match(%q, %v) = %v, want %v", name, path, p.isDir, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRequiredLiteral(t *testing.T) {
+	tests := map[string]string{
+		"gen/**/out*.tmp": "gen/",
+		"**/cache/*.bin":  "cache/",
+		"x[]y]zz":         "zz",
+		"ab[!c]d":         "ab",
+		`a\*bcd`:          "bcd",
+		"*?*":             "",
+	}
+
+	for pattern, want := range tests {
+		if got := requiredLiteral(pattern); got != want {
+			t.Errorf("requiredLiteral(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestLoadIgnoreMatcherCache(t *testing.T) {
+	base := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	gitignore := filepath.Join(base, ".gitignore")
+	if err := os.WriteFile(gitignore, []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := []IgnoreFile{{Path: gitignore, BasePath: base}, {Path: filepath.Join(base, ".ignore"), BasePath: base}}
+
+	m, err := LoadIgnoreMatcher(base, files, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.ShouldIgnore("a.log", false) || len(m.Sources()) != 1 {
+		t.Fatalf("unexpected matcher: sources %v", m.Sources())
+	}
+
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %d", len(entries))
+	}
+
+	// A corrupted entry for unchanged files is rebuilt rather than trusted
+	entry := filepath.Join(cacheDir, entries[0].Name())
+	if err := os.WriteFile(entry, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if m, err = LoadIgnoreMatcher(base, files, cacheDir); err != nil || !m.ShouldIgnore("a.log", false) {
+		t.Fatalf("rebuild after corruption: %v", err)
+	}
+
+	// The cached copy is used while the files are unchanged
+	if m, err = LoadIgnoreMatcher(base, files, cacheDir); err != nil || !m.ShouldIgnore("a.log", false) {
+		t.Fatalf("cached load: %v", err)
+	}
+
+	// Changing a file changes the key
+	if err := os.WriteFile(gitignore, []byte("*.tmp\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = LoadIgnoreMatcher(base, files, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.ShouldIgnore("a.log", false) || !m.ShouldIgnore("a.tmp", false) {
+		t.Error("matcher should reflect the edited ignore file")
+	}
+
+	if entries, _ = os.ReadDir(cacheDir); len(entries) != 2 {
+		t.Errorf("expected a second cache entry, got %d", len(entries))
+	}
+}
+
+func TestLoadIgnoreMatcherCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	base := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	load := func(rules string) {
+		t.Helper()
+
+		path := filepath.Join(base, ".gitignore")
+		if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadIgnoreMatcher(base, []IgnoreFile{{Path: path, BasePath: base}}, cacheDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	load("a\n")
+
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %d", len(entries))
+	}
+
+	info, err := entries[0].Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Room for two entries of about this size
+	old := maxIgnoreCacheBytes
+	maxIgnoreCacheBytes = 2*info.Size() + 1
+
+	t.Cleanup(func() { maxIgnoreCacheBytes = old })
+
+	keep := filepath.Join(cacheDir, entries[0].Name())
+	past := time.Now().Add(-time.Hour)
+
+	load("b\n")
+
+	// Make "a" older than "b", then use it again so "b" is the one evicted
+	if err := os.Chtimes(keep, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range mustReadDir(t, cacheDir) {
+		if p := filepath.Join(cacheDir, e.Name()); p != keep {
+			if err := os.Chtimes(p, past.Add(time.Minute), past.Add(time.Minute)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	load("a\n")
+	load("c\n")
+
+	entries = mustReadDir(t, cacheDir)
+	if len(entries) != 2 {
+		t.Fatalf("expected two cache entries after eviction, got %d", len(entries))
+	}
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("recently used entry was evicted: %v", err)
+	}
+}
+
+func mustReadDir(t *testing.T, dir string) []os.DirEntry {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entries
+}
+
+// BenchmarkLoadIgnoreMatcher compares compiling a monorepo-sized rule set
+// with loading it from the cache. Both read and hash the ignore file, so
+// the difference is what the cache saves.
+func BenchmarkLoadIgnoreMatcher(b *testing.B) {
+	base := b.TempDir()
+	cacheDir := filepath.Join(b.TempDir(), "cache")
+
+	var rules []byte
+	for i := range 5000 {
+		rules = fmt.Appendf(rules, "gen/**/out%d*.tmp\n/pkg%d/build/\n*.gen%d\n!pkg%d/keep.log\n", i, i, i, i)
+	}
+
+	gitignore := filepath.Join(base, ".gitignore")
+	if err := os.WriteFile(gitignore, rules, 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	files := []IgnoreFile{{Path: gitignore, BasePath: base}}
+
+	for _, bc := range []struct {
+		name  string
+		cache string
+	}{{"uncached", ""}, {"cached", cacheDir}} {
+		b.Run(bc.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := LoadIgnoreMatcher(base, files, bc.cache); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -228,3 +228,58 @@ func TestIsBinary(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeSet(t *testing.T) {
+	s := DefaultTypes()
+
+	if got := s.Globs("go"); len(got) != 1 || got[0] != "*.go" {
+		t.Errorf("Globs(go) = %v", got)
+	}
+
+	if err := s.Add("web:*.html, *.css"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Add("front:include:web,js"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Add("tmpl:templates/*.tmpl"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		include []string
+		exclude []string
+		want    bool
+	}{
+		{"site/index.html", []string{"web"}, nil, true},
+		{"site/STYLE.CSS", []string{"web"}, nil, true},
+		{"site/app.js", []string{"web"}, nil, false},
+		{"site/app.js", []string{"front"}, nil, true},
+		{"site/index.html", nil, []string{"front"}, false},
+		{"templates/page.tmpl", []string{"tmpl"}, nil, true},
+		{"other/page.tmpl", []string{"tmpl"}, nil, false},
+		{"Dockerfile", []string{"dockerfile"}, nil, true},
+		{"main.go", []string{"go"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		if got := s.Matches(tt.path, tt.include, tt.exclude); got != tt.want {
+			t.Errorf("Matches(%q, %v, %v) = %v, want %v", tt.path, tt.include, tt.exclude, got, tt.want)
+		}
+	}
+
+	s.Clear("web")
+
+	if s.Has("web") || !s.Has("front") {
+		t.Error("Clear(web) should remove web only")
+	}
+
+	for _, bad := range []string{"web", ":*.x", "a b:*.x", "x:", "x:[", "x:include:nope"} {
+		if err := s.Add(bad); err == nil {
+			t.Errorf("Add(%q) succeeded", bad)
+		}
+	}
+}
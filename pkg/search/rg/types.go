@@ -0,0 +1,164 @@
+package rg
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TypeSet maps file type names to the globs selecting them. It starts from
+// the built-in FileTypeExtensions and can be extended with ripgrep-style
+// --type-add definitions.
+type TypeSet struct {
+	globs map[string][]string
+}
+
+// DefaultTypes returns a TypeSet holding the built-in file types, with
+// every extension turned into a "*.ext" glob.
+func DefaultTypes() *TypeSet {
+	s := &TypeSet{globs: make(map[string][]string, len(FileTypeExtensions))}
+
+	for name, exts := range FileTypeExtensions {
+		globs := make([]string, len(exts))
+		for i, e := range exts {
+			if strings.HasPrefix(e, ".") {
+				e = "*" + e
+			}
+
+			globs[i] = e
+		}
+
+		s.globs[name] = globs
+	}
+
+	return s
+}
+
+// Add applies a type definition: "NAME:GLOB[,GLOB...]" appends globs to
+// NAME (creating it), and "NAME:include:TYPE[,TYPE...]" appends the globs
+// of existing types.
+func (s *TypeSet) Add(spec string) error {
+	name, def, ok := strings.Cut(spec, ":")
+	name = strings.TrimSpace(name)
+
+	if !ok || name == "" || strings.ContainsAny(name, " \t,") {
+		return fmt.Errorf("invalid type definition %q (want NAME:GLOB[,GLOB...])", spec)
+	}
+
+	if others, ok := strings.CutPrefix(def, "include:"); ok {
+		for _, other := range strings.Split(others, ",") {
+			globs, found := s.globs[strings.TrimSpace(other)]
+			if !found {
+				return fmt.Errorf("invalid type definition %q: unrecognized file type %q", spec, other)
+			}
+
+			s.appendGlobs(name, globs)
+		}
+
+		return nil
+	}
+
+	var globs []string
+
+	for _, g := range strings.Split(def, ",") {
+		g = strings.TrimSpace(g)
+		if g == "" {
+			continue
+		}
+
+		if _, err := filepath.Match(g, ""); err != nil {
+			return fmt.Errorf("invalid type definition %q: bad glob %q", spec, g)
+		}
+
+		globs = append(globs, g)
+	}
+
+	if len(globs) == 0 {
+		return fmt.Errorf("invalid type definition %q: no globs", spec)
+	}
+
+	s.appendGlobs(name, globs)
+
+	return nil
+}
+
+func (s *TypeSet) appendGlobs(name string, globs []string) {
+	for _, g := range globs {
+		if !slices.Contains(s.globs[name], g) {
+			s.globs[name] = append(s.globs[name], g)
+		}
+	}
+}
+
+// Clear removes all globs of a type, so a following Add redefines it.
+func (s *TypeSet) Clear(name string) {
+	delete(s.globs, name)
+}
+
+// Has reports whether name is a defined type.
+func (s *TypeSet) Has(name string) bool {
+	_, ok := s.globs[name]
+	return ok
+}
+
+// Names returns the defined type names, sorted.
+func (s *TypeSet) Names() []string {
+	return slices.Sorted(maps.Keys(s.globs))
+}
+
+// Globs returns the globs of a type, sorted.
+func (s *TypeSet) Globs(name string) []string {
+	return slices.Sorted(slices.Values(s.globs[name]))
+}
+
+// Matches reports whether path passes the include and exclude type
+// filters. Globs match the file name, or the slash-separated path when
+// they contain a slash; as with MatchesFileType, extensions also match
+// case-insensitively.
+func (s *TypeSet) Matches(path string, include, exclude []string) bool {
+	if len(include) == 0 && len(exclude) == 0 {
+		return true
+	}
+
+	for _, t := range exclude {
+		if s.matchesType(path, t) {
+			return false
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, t := range include {
+		if s.matchesType(path, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *TypeSet) matchesType(path, name string) bool {
+	base := filepath.Base(path)
+	slashed := filepath.ToSlash(path)
+
+	for _, g := range s.globs[name] {
+		target := base
+		if strings.Contains(g, "/") {
+			target = slashed
+		}
+
+		if ok, _ := filepath.Match(g, target); ok {
+			return true
+		}
+
+		if ok, _ := filepath.Match(g, strings.ToLower(target)); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+// Package sysinfo reports system-wide memory and network interface counters
+// via gopsutil, so callers get the same numbers on Linux, macOS, the BSDs and
+// Windows without parsing /proc or calling platform APIs themselves.
+//
+// Experimental: this package's API may change before a stable release and is
+// not covered by the v1.0 compatibility guarantee.
+package sysinfo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	gnet "github.com/shirou/gopsutil/v3/net"
+)
+
+// Memory is a snapshot of physical memory and swap usage, in bytes.
+type Memory struct {
+	Total     uint64 `json:"total"`
+	Used      uint64 `json:"used"`
+	Free      uint64 `json:"free"`
+	Shared    uint64 `json:"shared"`
+	Buffers   uint64 `json:"buffers"`
+	Cached    uint64 `json:"cached"`
+	Available uint64 `json:"available"`
+	SwapTotal uint64 `json:"swapTotal"`
+	SwapUsed  uint64 `json:"swapUsed"`
+	SwapFree  uint64 `json:"swapFree"`
+}
+
+// GetMemory returns the current memory and swap usage. Used follows
+// procps-ng: total minus available, falling back to total minus free,
+// buffers and cache on platforms that do not report available memory.
+// Swap is reported as zero when the platform exposes none.
+func GetMemory(ctx context.Context) (Memory, error) {
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return Memory{}, fmt.Errorf("sysinfo: memory: %w", err)
+	}
+
+	m := Memory{
+		Total:     vm.Total,
+		Free:      vm.Free,
+		Shared:    vm.Shared,
+		Buffers:   vm.Buffers,
+		Cached:    vm.Cached + vm.Sreclaimable,
+		Available: vm.Available,
+	}
+
+	if m.Available > 0 {
+		m.Used = subSat(m.Total, m.Available)
+	} else {
+		m.Used = subSat(subSat(subSat(m.Total, m.Free), m.Buffers), m.Cached)
+	}
+
+	if sw, err := mem.SwapMemoryWithContext(ctx); err == nil {
+		m.SwapTotal = sw.Total
+		m.SwapFree = sw.Free
+		m.SwapUsed = subSat(sw.Total, sw.Free)
+	}
+
+	return m, nil
+}
+
+// Interface holds the cumulative traffic counters of one network interface
+// since boot.
+type Interface struct {
+	Name      string `json:"name"`
+	RxBytes   uint64 `json:"rxBytes"`
+	TxBytes   uint64 `json:"txBytes"`
+	RxPackets uint64 `json:"rxPackets"`
+	TxPackets uint64 `json:"txPackets"`
+	RxErrors  uint64 `json:"rxErrors"`
+	TxErrors  uint64 `json:"txErrors"`
+	RxDropped uint64 `json:"rxDropped"`
+	TxDropped uint64 `json:"txDropped"`
+}
+
+// GetInterfaces returns the counters of every network interface, sorted by
+// name.
+func GetInterfaces(ctx context.Context) ([]Interface, error) {
+	counters, err := gnet.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("sysinfo: interfaces: %w", err)
+	}
+
+	ifaces := make([]Interface, 0, len(counters))
+	for _, c := range counters {
+		ifaces = append(ifaces, Interface{
+			Name:      c.Name,
+			RxBytes:   c.BytesRecv,
+			TxBytes:   c.BytesSent,
+			RxPackets: c.PacketsRecv,
+			TxPackets: c.PacketsSent,
+			RxErrors:  c.Errin,
+			TxErrors:  c.Errout,
+			RxDropped: c.Dropin,
+			TxDropped: c.Dropout,
+		})
+	}
+
+	sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].Name < ifaces[j].Name })
+
+	return ifaces, nil
+}
+
+// Rate is the per-second traffic of one interface between two samples.
+type Rate struct {
+	Name          string  `json:"name"`
+	RxBytesPerSec float64 `json:"rxBytesPerSec"`
+	TxBytesPerSec float64 `json:"txBytesPerSec"`
+	RxPktsPerSec  float64 `json:"rxPacketsPerSec"`
+	TxPktsPerSec  float64 `json:"txPacketsPerSec"`
+}
+
+// Rates computes per-second rates for the interfaces present in both
+// samples, in the order of cur. A counter that went backwards (interface
+// reset or 32-bit wrap) counts as zero traffic rather than a huge spike.
+func Rates(prev, cur []Interface, elapsed time.Duration) []Rate {
+	secs := elapsed.Seconds()
+	if secs <= 0 {
+		return nil
+	}
+
+	before := make(map[string]Interface, len(prev))
+	for _, p := range prev {
+		before[p.Name] = p
+	}
+
+	rates := make([]Rate, 0, len(cur))
+
+	for _, c := range cur {
+		p, ok := before[c.Name]
+		if !ok {
+			continue
+		}
+
+		rates = append(rates, Rate{
+			Name:          c.Name,
+			RxBytesPerSec: float64(subSat(c.RxBytes, p.RxBytes)) / secs,
+			TxBytesPerSec: float64(subSat(c.TxBytes, p.TxBytes)) / secs,
+			RxPktsPerSec:  float64(subSat(c.RxPackets, p.RxPackets)) / secs,
+			TxPktsPerSec:  float64(subSat(c.TxPackets, p.TxPackets)) / secs,
+		})
+	}
+
+	return rates
+}
+
+// subSat returns a-b, saturating at zero instead of wrapping around.
+func subSat(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+
+	return a - b
+}
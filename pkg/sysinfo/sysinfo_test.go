@@ -0,0 +1,72 @@
+package sysinfo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetMemory(t *testing.T) {
+	m, err := GetMemory(context.Background())
+	if err != nil {
+		t.Fatalf("GetMemory() error = %v", err)
+	}
+
+	if m.Total == 0 {
+		t.Error("Total should not be 0")
+	}
+
+	if m.Used > m.Total || m.Free > m.Total || m.Available > m.Total {
+		t.Errorf("inconsistent memory snapshot: %+v", m)
+	}
+
+	if m.SwapUsed+m.SwapFree != m.SwapTotal {
+		t.Errorf("swap used+free = %d, want %d", m.SwapUsed+m.SwapFree, m.SwapTotal)
+	}
+}
+
+func TestGetInterfaces(t *testing.T) {
+	ifaces, err := GetInterfaces(context.Background())
+	if err != nil {
+		t.Skipf("interface counters unavailable: %v", err)
+	}
+
+	for i := 1; i < len(ifaces); i++ {
+		if ifaces[i-1].Name > ifaces[i].Name {
+			t.Fatalf("interfaces not sorted: %q before %q", ifaces[i-1].Name, ifaces[i].Name)
+		}
+	}
+}
+
+func TestRates(t *testing.T) {
+	prev := []Interface{
+		{Name: "eth0", RxBytes: 1000, TxBytes: 500, RxPackets: 10, TxPackets: 5},
+		{Name: "wlan0", RxBytes: 9000, TxBytes: 9000},
+		{Name: "gone0", RxBytes: 1},
+	}
+	cur := []Interface{
+		{Name: "eth0", RxBytes: 3000, TxBytes: 1500, RxPackets: 30, TxPackets: 9},
+		{Name: "wlan0", RxBytes: 100, TxBytes: 9400},
+		{Name: "new0", RxBytes: 5},
+	}
+
+	rates := Rates(prev, cur, 2*time.Second)
+	if len(rates) != 2 {
+		t.Fatalf("Rates() returned %d entries, want 2: %+v", len(rates), rates)
+	}
+
+	eth := rates[0]
+	if eth.Name != "eth0" || eth.RxBytesPerSec != 1000 || eth.TxBytesPerSec != 500 ||
+		eth.RxPktsPerSec != 10 || eth.TxPktsPerSec != 2 {
+		t.Errorf("eth0 rate = %+v", eth)
+	}
+
+	wlan := rates[1]
+	if wlan.RxBytesPerSec != 0 || wlan.TxBytesPerSec != 200 {
+		t.Errorf("wlan0 rate = %+v, want rx clamped to 0 and tx 200", wlan)
+	}
+
+	if Rates(prev, cur, 0) != nil {
+		t.Error("Rates() with zero elapsed time should return nil")
+	}
+}
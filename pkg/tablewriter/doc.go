@@ -0,0 +1,17 @@
+// Package tablewriter renders rows of cells as a table, the one renderer
+// behind the tabular output of ls -l, ps, df, stats and logs stats.
+//
+// Text tables size each column to its widest cell, measured in display
+// columns so CJK and emoji content lines up. Given a MaxWidth (see
+// TerminalWidth) the widest columns are shrunk until the table fits, and
+// cells cut short end in an ellipsis. Columns are aligned left or right and
+// a column the rows are sorted by shows an ▲ or ▼ after its header, unless
+// HideSort is set. Gap and per-column widths reproduce fixed layouts such
+// as the single-spaced columns of ls -l and ps.
+//
+// The same table renders as a GitHub-flavored Markdown table or as CSV
+// (RFC 4180), for pasting into documents or loading into spreadsheets.
+//
+// Experimental: this package's API may change before a stable release and is
+// not covered by the v1.0 compatibility guarantee.
+package tablewriter
@@ -0,0 +1,419 @@
+package tablewriter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/inovacc/omni/pkg/textwidth"
+	"golang.org/x/term"
+)
+
+// Align is the horizontal alignment of a column.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+)
+
+// Sort marks the column rows are ordered by.
+type Sort int
+
+const (
+	Unsorted Sort = iota
+	Ascending
+	Descending
+)
+
+// Format selects how a table is emitted.
+type Format int
+
+const (
+	// Text is a plain table with columns two spaces apart, or Gap apart.
+	Text Format = iota
+	// Markdown is a GitHub-flavored Markdown table.
+	Markdown
+	// CSV is RFC 4180 comma-separated values, header first.
+	CSV
+)
+
+// ParseFormat parses "text", "markdown" (or "md") and "csv".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "text":
+		return Text, nil
+	case "markdown", "md":
+		return Markdown, nil
+	case "csv":
+		return CSV, nil
+	}
+
+	return Text, fmt.Errorf("tablewriter: unknown format %q (text, markdown, csv)", s)
+}
+
+// Column describes one column of a table.
+type Column struct {
+	Header string
+	Align  Align
+	Sort   Sort
+
+	// Width is the narrowest a Text column is laid out, like the width of
+	// a %8s verb; wider cells widen it.
+	Width int
+
+	// MinWidth is the narrowest a column is shrunk to when the table does
+	// not fit in MaxWidth. Zero means minShrinkWidth.
+	MinWidth int
+
+	// NoShrink keeps the column at its natural width, for numbers and
+	// other cells that are useless cut short.
+	NoShrink bool
+}
+
+// minShrinkWidth is the default MinWidth: room for a few characters and
+// the ellipsis.
+const minShrinkWidth = 4
+
+// ellipsis ends a cell that was cut short.
+const ellipsis = "…"
+
+// Table is a set of rows under a header of columns. Rows may have more
+// cells than there are columns; the extra columns have no header and
+// default settings.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+
+	// MaxWidth is the widest a Text line may be, in display columns. Zero
+	// means no limit. It does not apply to Markdown and CSV.
+	MaxWidth int
+
+	// NoHeader leaves the header row out.
+	NoHeader bool
+
+	// HideSort leaves the sort indicators out of the header row, so each
+	// header is one word for programs that split the output on spaces.
+	HideSort bool
+
+	// Gap separates Text columns. Empty means two spaces.
+	Gap string
+}
+
+// New returns an empty table with the given columns.
+func New(columns ...Column) *Table {
+	return &Table{Columns: columns}
+}
+
+// Append adds a row.
+func (t *Table) Append(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Render writes the table to w in format f.
+func (t *Table) Render(w io.Writer, f Format) error {
+	switch f {
+	case Markdown:
+		return t.renderMarkdown(w)
+	case CSV:
+		return t.renderCSV(w)
+	}
+
+	return t.renderText(w)
+}
+
+// IsTerminal reports whether w writes to a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd())) //nolint:gosec // file descriptors fit in an int
+}
+
+// TerminalWidth returns the width of the terminal w writes to, or the
+// value of $COLUMNS when w is not a terminal and it is set, or 0.
+func TerminalWidth(w io.Writer) int {
+	if IsTerminal(w) {
+		if width, _, err := term.GetSize(int(w.(*os.File).Fd())); err == nil && width > 0 { //nolint:gosec // file descriptors fit in an int
+			return width
+		}
+	}
+
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+
+	return 0
+}
+
+// columns returns the table's columns, extended to cover the widest row.
+func (t *Table) columns() []Column {
+	n := len(t.Columns)
+	for _, row := range t.Rows {
+		n = max(n, len(row))
+	}
+
+	cols := make([]Column, n)
+	copy(cols, t.Columns)
+
+	return cols
+}
+
+// header returns the header cell of c, with the sort indicator unless the
+// table hides them.
+func (t *Table) header(c Column) string {
+	if t.HideSort {
+		return c.Header
+	}
+
+	switch c.Sort {
+	case Ascending:
+		return c.Header + " ▲"
+	case Descending:
+		return c.Header + " ▼"
+	}
+
+	return c.Header
+}
+
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+
+	return ""
+}
+
+// defaultGap is the space between two text columns when Gap is empty.
+const defaultGap = "  "
+
+func (t *Table) gap() string {
+	if t.Gap == "" {
+		return defaultGap
+	}
+
+	return t.Gap
+}
+
+func (t *Table) renderText(w io.Writer) error {
+	cols := t.columns()
+	rows := t.Rows
+
+	if !t.NoHeader && len(t.Columns) > 0 {
+		head := make([]string, len(cols))
+		for i, c := range cols {
+			head[i] = t.header(c)
+		}
+
+		rows = append([][]string{head}, rows...)
+	}
+
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = c.Width
+	}
+
+	for _, row := range rows {
+		for i, s := range row {
+			widths[i] = max(widths[i], textwidth.String(s))
+		}
+	}
+
+	gap := t.gap()
+
+	if t.MaxWidth > 0 {
+		shrink(cols, widths, textwidth.String(gap), t.MaxWidth)
+	}
+
+	var b strings.Builder
+
+	for _, row := range rows {
+		// Trailing empty left-aligned cells would only add spaces.
+		last := len(cols) - 1
+		for last > 0 && cell(row, last) == "" && cols[last].Align == AlignLeft {
+			last--
+		}
+
+		for i := 0; i <= last; i++ {
+			s := fit(cell(row, i), widths[i])
+
+			switch {
+			case cols[i].Align == AlignRight:
+				b.WriteString(textwidth.PadLeft(s, widths[i]))
+			case i == last:
+				b.WriteString(s)
+			default:
+				b.WriteString(textwidth.PadRight(s, widths[i]))
+			}
+
+			if i < last {
+				b.WriteString(gap)
+			}
+		}
+
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// shrink narrows the widest shrinkable columns, one column at a time,
+// until the table fits in maxWidth or no column can give up more.
+func shrink(cols []Column, widths []int, gap, maxWidth int) {
+	total := gap * (len(widths) - 1)
+	for _, n := range widths {
+		total += n
+	}
+
+	for total > maxWidth {
+		widest := -1
+
+		for i, c := range cols {
+			floor := c.MinWidth
+			if floor == 0 {
+				floor = minShrinkWidth
+			}
+
+			if c.NoShrink || widths[i] <= floor {
+				continue
+			}
+
+			if widest < 0 || widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+
+		if widest < 0 {
+			return
+		}
+
+		widths[widest]--
+		total--
+	}
+}
+
+// fit cuts s to width display columns, ending it in an ellipsis when it
+// was cut.
+func fit(s string, width int) string {
+	if textwidth.String(s) <= width {
+		return s
+	}
+
+	return textwidth.Truncate(s, width-textwidth.String(ellipsis)) + ellipsis
+}
+
+func (t *Table) renderMarkdown(w io.Writer) error {
+	cols := t.columns()
+	if len(cols) == 0 {
+		return nil
+	}
+
+	// Markdown needs a header row; a headerless table gets an empty one.
+	head := make([]string, len(cols))
+	if !t.NoHeader {
+		for i, c := range cols {
+			head[i] = escapeMarkdown(t.header(c))
+		}
+	}
+
+	rows := make([][]string, len(t.Rows))
+	for r, row := range t.Rows {
+		rows[r] = make([]string, len(cols))
+		for i := range cols {
+			rows[r][i] = escapeMarkdown(cell(row, i))
+		}
+	}
+
+	widths := make([]int, len(cols))
+	for i := range cols {
+		widths[i] = max(3, textwidth.String(head[i]))
+		for _, row := range rows {
+			widths[i] = max(widths[i], textwidth.String(row[i]))
+		}
+	}
+
+	var b strings.Builder
+
+	line := func(cells []string) {
+		b.WriteByte('|')
+
+		for i, s := range cells {
+			b.WriteByte(' ')
+
+			if cols[i].Align == AlignRight {
+				b.WriteString(textwidth.PadLeft(s, widths[i]))
+			} else {
+				b.WriteString(textwidth.PadRight(s, widths[i]))
+			}
+
+			b.WriteString(" |")
+		}
+
+		b.WriteByte('\n')
+	}
+
+	line(head)
+
+	b.WriteByte('|')
+
+	for i, c := range cols {
+		if c.Align == AlignRight {
+			b.WriteString(" " + strings.Repeat("-", widths[i]-1) + ": |")
+		} else {
+			b.WriteString(" " + strings.Repeat("-", widths[i]) + " |")
+		}
+	}
+
+	b.WriteByte('\n')
+
+	for _, row := range rows {
+		line(row)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// escapeMarkdown keeps a cell inside its table cell: pipes are escaped
+// and line breaks become spaces.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+func (t *Table) renderCSV(w io.Writer) error {
+	cols := t.columns()
+	cw := csv.NewWriter(w)
+
+	if !t.NoHeader && len(t.Columns) > 0 {
+		head := make([]string, len(cols))
+		for i, c := range cols {
+			head[i] = c.Header
+		}
+
+		if err := cw.Write(head); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, len(cols))
+		for i := range cols {
+			record[i] = cell(row, i)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,164 @@
+package tablewriter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sample() *Table {
+	t := New(
+		Column{Header: "PID", Align: AlignRight, Sort: Ascending},
+		Column{Header: "USER"},
+		Column{Header: "COMMAND"},
+	)
+	t.Append("1", "root", "/sbin/init splash")
+	t.Append("4242", "ana", "omni ps | grep x")
+
+	return t
+}
+
+func render(t *testing.T, tbl *Table, f Format) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := tbl.Render(&buf, f); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestRenderText(t *testing.T) {
+	want := "" +
+		"PID ▲  USER  COMMAND\n" +
+		"    1  root  /sbin/init splash\n" +
+		" 4242  ana   omni ps | grep x\n"
+
+	if got := render(t, sample(), Text); got != want {
+		t.Errorf("Render(Text) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTextPlain(t *testing.T) {
+	tbl := sample()
+	tbl.Columns[0].Width = 5
+	tbl.HideSort = true
+	tbl.Gap = " "
+
+	want := "" +
+		"  PID USER COMMAND\n" +
+		"    1 root /sbin/init splash\n" +
+		" 4242 ana  omni ps | grep x\n"
+
+	if got := render(t, tbl, Text); got != want {
+		t.Errorf("Render(Text) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTextShrink(t *testing.T) {
+	tbl := sample()
+	tbl.MaxWidth = 24
+
+	got := render(t, tbl, Text)
+	want := "" +
+		"PID ▲  USER  COMMAND\n" +
+		"    1  root  /sbin/init…\n" +
+		" 4242  ana   omni ps | …\n"
+
+	if got != want {
+		t.Errorf("Render(Text, MaxWidth=24) =\n%s\nwant\n%s", got, want)
+	}
+
+	for line := range strings.Lines(got) {
+		if n := len([]rune(strings.TrimSuffix(line, "\n"))); n > 24 {
+			t.Errorf("line %q is %d columns wide", line, n)
+		}
+	}
+}
+
+func TestRenderTextShrinkFloor(t *testing.T) {
+	tbl := New(Column{Header: "N", NoShrink: true}, Column{Header: "NAME", MinWidth: 6})
+	tbl.Append("123456789", "abcdefghijkl")
+	tbl.MaxWidth = 5
+
+	want := "N          NAME\n123456789  abcde…\n"
+	if got := render(t, tbl, Text); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTextWide(t *testing.T) {
+	tbl := New(Column{Header: "NAME"}, Column{Header: "N", Align: AlignRight})
+	tbl.Append("日本語", "1")
+	tbl.Append("ab", "22")
+
+	want := "NAME     N\n日本語   1\nab      22\n"
+	if got := render(t, tbl, Text); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTextNoHeader(t *testing.T) {
+	tbl := &Table{NoHeader: true}
+	tbl.Append("a", "bb", "c")
+	tbl.Append("ccc", "d")
+
+	want := "a    bb  c\nccc  d\n"
+	if got := render(t, tbl, Text); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	want := "" +
+		"| PID ▲ | USER | COMMAND           |\n" +
+		"| ----: | ---- | ----------------- |\n" +
+		"|     1 | root | /sbin/init splash |\n" +
+		"|  4242 | ana  | omni ps \\| grep x |\n"
+
+	if got := render(t, sample(), Markdown); got != want {
+		t.Errorf("Render(Markdown) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	tbl := sample()
+	tbl.MaxWidth = 10 // ignored
+	tbl.Append("7", "bob", `say "hi", then leave`)
+
+	want := "PID,USER,COMMAND\n" +
+		"1,root,/sbin/init splash\n" +
+		"4242,ana,omni ps | grep x\n" +
+		"7,bob,\"say \"\"hi\"\", then leave\"\n"
+
+	if got := render(t, tbl, CSV); got != want {
+		t.Errorf("Render(CSV) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": Text, "text": Text, "MD": Markdown, "markdown": Markdown, "csv": CSV} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v", in, got, err)
+		}
+	}
+
+	if _, err := ParseFormat("html"); err == nil {
+		t.Error("ParseFormat(html) should fail")
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "91")
+
+	if got := TerminalWidth(&bytes.Buffer{}); got != 91 {
+		t.Errorf("TerminalWidth() = %d, want 91 from $COLUMNS", got)
+	}
+
+	t.Setenv("COLUMNS", "")
+
+	if got := TerminalWidth(&bytes.Buffer{}); got != 0 {
+		t.Errorf("TerminalWidth() = %d, want 0", got)
+	}
+}
@@ -1,4 +1,6 @@
 // Package diff provides line-based diff computation using the LCS
 // algorithm, unified format output, and JSON structure comparison.
-// It supports configurable context lines around each change hunk.
+// It supports configurable context lines around each change hunk. JSON
+// differences can also be expressed as RFC 6902 JSON Patch or RFC 7396
+// JSON Merge Patch documents, and both kinds of patch can be applied.
 package diff
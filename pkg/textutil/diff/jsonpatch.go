@@ -0,0 +1,538 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/inovacc/omni/pkg/jsonutil"
+)
+
+// PatchOp is one RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON always writes "value" for add, replace and test, even when
+// it is null, and never for the other operations.
+func (p PatchOp) MarshalJSON() ([]byte, error) {
+	type op struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+		From string `json:"from,omitempty"`
+	}
+
+	type valueOp struct {
+		op
+		Value any `json:"value"`
+	}
+
+	var v any = op{Op: p.Op, Path: p.Path, From: p.From}
+
+	switch p.Op {
+	case "add", "replace", "test":
+		v = valueOp{op: v.(op), Value: p.Value}
+	}
+
+	// Leave "<", ">" and "&" alone; encoders that want them escaped will
+	// escape the returned JSON themselves
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// JSONPatch returns the RFC 6902 operations that turn v1 into v2. Objects
+// are compared member by member and arrays element by element, so a
+// change deep in a document is a single replace at its pointer. Keys are
+// visited in sorted order, making the patch deterministic.
+func JSONPatch(v1, v2 any) []PatchOp {
+	return jsonPatch(nil, "", v1, v2)
+}
+
+func jsonPatch(ops []PatchOp, path string, v1, v2 any) []PatchOp {
+	switch a := v1.(type) {
+	case map[string]any:
+		b, ok := v2.(map[string]any)
+		if !ok {
+			break
+		}
+
+		for _, k := range sortedKeys(a) {
+			if _, in2 := b[k]; !in2 {
+				ops = append(ops, PatchOp{Op: "remove", Path: path + "/" + escapePointer(k)})
+			}
+		}
+
+		for _, k := range sortedKeys(b) {
+			p := path + "/" + escapePointer(k)
+			if old, in1 := a[k]; in1 {
+				ops = jsonPatch(ops, p, old, b[k])
+			} else {
+				ops = append(ops, PatchOp{Op: "add", Path: p, Value: b[k]})
+			}
+		}
+
+		return ops
+	case []any:
+		b, ok := v2.([]any)
+		if !ok {
+			break
+		}
+
+		common := min(len(a), len(b))
+		for i := range common {
+			ops = jsonPatch(ops, path+"/"+strconv.Itoa(i), a[i], b[i])
+		}
+
+		// Remove from the end so earlier indexes stay valid
+		for i := len(a) - 1; i >= common; i-- {
+			ops = append(ops, PatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+
+		for i := common; i < len(b); i++ {
+			ops = append(ops, PatchOp{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: b[i]})
+		}
+
+		return ops
+	}
+
+	if !jsonEqual(v1, v2) {
+		ops = append(ops, PatchOp{Op: "replace", Path: path, Value: v2})
+	}
+
+	return ops
+}
+
+// MergePatch returns the RFC 7396 JSON Merge Patch that turns v1 into v2.
+// Merge patches cannot express array edits or null values, so arrays are
+// replaced whole and a member set to null in v2 is removed instead.
+func MergePatch(v1, v2 any) any {
+	a, ok1 := v1.(map[string]any)
+	b, ok2 := v2.(map[string]any)
+
+	if !ok1 || !ok2 {
+		return v2
+	}
+
+	patch := map[string]any{}
+
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			patch[k] = nil
+		}
+	}
+
+	for k, bv := range b {
+		av, ok := a[k]
+		if !ok {
+			patch[k] = bv
+			continue
+		}
+
+		if jsonEqual(av, bv) {
+			continue
+		}
+
+		_, aObj := av.(map[string]any)
+		_, bObj := bv.(map[string]any)
+
+		if aObj && bObj {
+			patch[k] = MergePatch(av, bv)
+		} else {
+			patch[k] = bv
+		}
+	}
+
+	return patch
+}
+
+// ParseJSONPatch decodes an RFC 6902 patch document.
+func ParseJSONPatch(data []byte) ([]PatchOp, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON Patch: %w", err)
+	}
+
+	ops := make([]PatchOp, 0, len(raw))
+
+	for i, m := range raw {
+		var op PatchOp
+
+		if err := unmarshalField(m, "op", &op.Op); err != nil || op.Op == "" {
+			return nil, fmt.Errorf("invalid JSON Patch: operation %d: missing op", i)
+		}
+
+		if err := unmarshalField(m, "path", &op.Path); err != nil {
+			return nil, fmt.Errorf("invalid JSON Patch: operation %d: missing path", i)
+		}
+
+		switch op.Op {
+		case "add", "replace", "test":
+			v, ok := m["value"]
+			if !ok {
+				return nil, fmt.Errorf("invalid JSON Patch: operation %d (%s): missing value", i, op.Op)
+			}
+
+			if err := decodeJSON(v, &op.Value); err != nil {
+				return nil, fmt.Errorf("invalid JSON Patch: operation %d: %w", i, err)
+			}
+		case "move", "copy":
+			if err := unmarshalField(m, "from", &op.From); err != nil {
+				return nil, fmt.Errorf("invalid JSON Patch: operation %d (%s): missing from", i, op.Op)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("invalid JSON Patch: operation %d: unknown op %q", i, op.Op)
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+func unmarshalField(m map[string]json.RawMessage, key string, dst *string) error {
+	v, ok := m[key]
+	if !ok {
+		return errors.New("missing")
+	}
+
+	return json.Unmarshal(v, dst)
+}
+
+// decodeJSON keeps numbers as json.Number, as the CLI decodes documents.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+// ErrPatchTestFailed is returned by ApplyJSONPatch when a test operation
+// does not match.
+var ErrPatchTestFailed = errors.New("test operation failed")
+
+// ApplyJSONPatch applies RFC 6902 operations to doc and returns the
+// result. The operations apply atomically: on error doc is unchanged and
+// no partial result is returned.
+func ApplyJSONPatch(doc any, ops []PatchOp) (any, error) {
+	doc = deepCopy(doc)
+
+	for i, op := range ops {
+		var err error
+
+		switch op.Op {
+		case "add":
+			doc, err = pointerAdd(doc, op.Path, deepCopy(op.Value))
+		case "remove":
+			doc, _, err = pointerRemove(doc, op.Path)
+		case "replace":
+			if _, err = jsonutil.ResolvePointer(doc, op.Path); err == nil {
+				doc, _, err = pointerRemove(doc, op.Path)
+				if err == nil {
+					doc, err = pointerAdd(doc, op.Path, deepCopy(op.Value))
+				}
+			}
+		case "move":
+			if op.Path == op.From || strings.HasPrefix(op.Path, op.From+"/") {
+				if op.Path != op.From {
+					err = fmt.Errorf("cannot move %s into itself", op.From)
+				}
+
+				break
+			}
+
+			var v any
+
+			doc, v, err = pointerRemove(doc, op.From)
+			if err == nil {
+				doc, err = pointerAdd(doc, op.Path, v)
+			}
+		case "copy":
+			var v any
+
+			v, err = jsonutil.ResolvePointer(doc, op.From)
+			if err == nil {
+				doc, err = pointerAdd(doc, op.Path, deepCopy(v))
+			}
+		case "test":
+			var v any
+
+			v, err = jsonutil.ResolvePointer(doc, op.Path)
+			if err == nil && !jsonEqual(v, op.Value) {
+				err = fmt.Errorf("%w at %q", ErrPatchTestFailed, op.Path)
+			}
+		default:
+			err = fmt.Errorf("unknown op %q", op.Op)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// ApplyMergePatch applies an RFC 7396 merge patch to doc.
+func ApplyMergePatch(doc, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return deepCopy(patch)
+	}
+
+	target, ok := doc.(map[string]any)
+	if !ok {
+		target = map[string]any{}
+	} else {
+		target = deepCopy(target).(map[string]any)
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(target, k)
+		} else {
+			target[k] = ApplyMergePatch(target[k], v)
+		}
+	}
+
+	return target
+}
+
+// pointerAdd inserts v at ptr: it sets an object member, inserts into an
+// array at an index, appends for "-", or replaces the whole document for "".
+func pointerAdd(doc any, ptr string, v any) (any, error) {
+	tokens, err := jsonutil.ParsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return v, nil
+	}
+
+	parentPtr, last := splitPointer(ptr, tokens)
+
+	parent, err := jsonutil.ResolvePointer(doc, parentPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = v
+		return doc, nil
+	case []any:
+		i := len(p)
+		if last != "-" {
+			if i, err = arrayIndex(last, len(p)); err != nil {
+				return nil, err
+			}
+		}
+
+		return setAt(doc, parentPtr, slices.Insert(p, i, v))
+	default:
+		return nil, fmt.Errorf("%w: %s is not a container", jsonutil.ErrPointerNotFound, parentPtr)
+	}
+}
+
+// pointerRemove removes the value at ptr and returns it.
+func pointerRemove(doc any, ptr string) (any, any, error) {
+	tokens, err := jsonutil.ParsePointer(ptr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+
+	parentPtr, last := splitPointer(ptr, tokens)
+
+	parent, err := jsonutil.ResolvePointer(doc, parentPtr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		v, ok := p[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", jsonutil.ErrPointerNotFound, ptr)
+		}
+
+		delete(p, last)
+
+		return doc, v, nil
+	case []any:
+		i, err := arrayIndex(last, len(p)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		v := p[i]
+		doc, err = setAt(doc, parentPtr, slices.Delete(p, i, i+1))
+
+		return doc, v, err
+	default:
+		return nil, nil, fmt.Errorf("%w: %s is not a container", jsonutil.ErrPointerNotFound, parentPtr)
+	}
+}
+
+// setAt stores a resized array back at ptr, since growing or shrinking a
+// slice may produce a new one.
+func setAt(doc any, ptr string, v any) (any, error) {
+	tokens, _ := jsonutil.ParsePointer(ptr)
+	if len(tokens) == 0 {
+		return v, nil
+	}
+
+	parentPtr, last := splitPointer(ptr, tokens)
+
+	parent, err := jsonutil.ResolvePointer(doc, parentPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = v
+	case []any:
+		i, _ := strconv.Atoi(last)
+		p[i] = v
+	}
+
+	return doc, nil
+}
+
+// splitPointer returns the parent pointer and the last unescaped token.
+func splitPointer(ptr string, tokens []string) (string, string) {
+	parent := ""
+	for _, t := range tokens[:len(tokens)-1] {
+		parent += "/" + escapePointer(t)
+	}
+
+	return parent, tokens[len(tokens)-1]
+}
+
+// arrayIndex parses an array index token no larger than maxIndex.
+func arrayIndex(tok string, maxIndex int) (int, error) {
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	if i > maxIndex {
+		return 0, fmt.Errorf("%w: index %d out of range", jsonutil.ErrPointerNotFound, i)
+	}
+
+	return i, nil
+}
+
+func escapePointer(tok string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// jsonEqual compares decoded values, treating numbers as equal when they
+// denote the same value whether they are float64 or json.Number.
+func jsonEqual(a, b any) bool {
+	if na, ok := number(a); ok {
+		nb, ok := number(b)
+		return ok && na == nb
+	}
+
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func deepCopy(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for k, e := range x {
+			m[k] = deepCopy(e)
+		}
+
+		return m
+	case []any:
+		s := make([]any, len(x))
+		for i, e := range x {
+			s[i] = deepCopy(e)
+		}
+
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustDecode(t *testing.T, s string) any {
+	t.Helper()
+
+	var v any
+	if err := decodeJSON([]byte(s), &v); err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+
+	return v
+}
+
+func TestJSONPatchRoundTrip(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{`{"a":1,"b":{"c":[1,2,3]}}`, `{"a":2,"b":{"c":[1,3]},"d":null}`},
+		{`{"list":[1]}`, `{"list":[1,{"x":true},"s"]}`},
+		{`{"a/b":{"m~n":1}}`, `{"a/b":{"m~n":2}}`},
+		{`[1,2]`, `{"now":"object"}`},
+		{`{"same":[1,{"x":1}]}`, `{"same":[1,{"x":1}]}`},
+		{`{"n":1}`, `{"n":1.0}`},
+	}
+
+	for _, tt := range tests {
+		a, b := mustDecode(t, tt.a), mustDecode(t, tt.b)
+		ops := JSONPatch(a, b)
+
+		got, err := ApplyJSONPatch(a, ops)
+		if err != nil {
+			t.Fatalf("ApplyJSONPatch(%s, %+v) error = %v", tt.a, ops, err)
+		}
+
+		if !jsonEqual(got, b) {
+			t.Errorf("patch %+v turned %s into %v, want %s", ops, tt.a, got, tt.b)
+		}
+
+		if !jsonEqual(a, mustDecode(t, tt.a)) {
+			t.Errorf("ApplyJSONPatch modified its input")
+		}
+
+		merged := ApplyMergePatch(a, MergePatch(a, b))
+		if !strings.Contains(tt.b, "null") && !jsonEqual(merged, b) {
+			t.Errorf("merge patch turned %s into %v, want %s", tt.a, merged, tt.b)
+		}
+	}
+}
+
+func TestJSONPatchOps(t *testing.T) {
+	ops := JSONPatch(mustDecode(t, `{"a":1,"b":[1,2,3],"c":true}`), mustDecode(t, `{"a":1,"b":[1,9],"d":null}`))
+
+	data, _ := json.Marshal(ops)
+
+	// Removed members first, then the rest in key order
+	want := `[{"op":"remove","path":"/c"},{"op":"replace","path":"/b/1","value":9},{"op":"remove","path":"/b/2"},{"op":"add","path":"/d","value":null}]`
+	if string(data) != want {
+		t.Errorf("JSONPatch = %s\nwant       %s", data, want)
+	}
+}
+
+// Examples from RFC 6902 appendix A.
+func TestApplyJSONPatchRFC(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"remove element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"append", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"copy", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"}]`, `{"a":{"b":1},"c":{"b":1}}`},
+		{"test then add", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{"escaped", `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"replace","path":"/~1","value":1}]`, `{"/":1,"~1":10}`},
+		{"add null", `{}`, `[{"op":"add","path":"/x","value":null}]`, `{"x":null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := ParseJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParseJSONPatch() error = %v", err)
+			}
+
+			got, err := ApplyJSONPatch(mustDecode(t, tt.doc), ops)
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+
+			if !jsonEqual(got, mustDecode(t, tt.want)) {
+				t.Errorf("got %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	tests := []struct{ name, doc, patch string }{
+		{"remove missing", `{"a":1}`, `[{"op":"remove","path":"/b"}]`},
+		{"replace missing", `{"a":1}`, `[{"op":"replace","path":"/b","value":1}]`},
+		{"add past end", `{"a":[1]}`, `[{"op":"add","path":"/a/5","value":1}]`},
+		{"add to missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`},
+		{"leading zero", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`},
+		{"move into child", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := ParseJSONPatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParseJSONPatch() error = %v", err)
+			}
+
+			if _, err := ApplyJSONPatch(mustDecode(t, tt.doc), ops); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	ops, _ := ParseJSONPatch([]byte(`[{"op":"test","path":"/a","value":2}]`))
+	if _, err := ApplyJSONPatch(mustDecode(t, `{"a":1}`), ops); !errors.Is(err, ErrPatchTestFailed) {
+		t.Errorf("failed test op error = %v, want ErrPatchTestFailed", err)
+	}
+
+	for _, bad := range []string{`{}`, `[{"path":"/a"}]`, `[{"op":"add","path":"/a"}]`, `[{"op":"move","path":"/a"}]`, `[{"op":"frob","path":"/a"}]`} {
+		if _, err := ParseJSONPatch([]byte(bad)); err == nil {
+			t.Errorf("ParseJSONPatch(%s) should fail", bad)
+		}
+	}
+}
+
+// Examples from RFC 7396 appendix A.
+func TestApplyMergePatchRFC(t *testing.T) {
+	tests := []struct{ doc, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		got := ApplyMergePatch(mustDecode(t, tt.doc), mustDecode(t, tt.patch))
+		if !jsonEqual(got, mustDecode(t, tt.want)) {
+			t.Errorf("ApplyMergePatch(%s, %s) = %v, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	patch := MergePatch(mustDecode(t, `{"a":1,"b":{"c":1,"d":2},"e":[1]}`), mustDecode(t, `{"a":1,"b":{"c":3},"e":[1,2]}`))
+
+	data, _ := json.Marshal(patch)
+	if want := `{"b":{"c":3,"d":null},"e":[1,2]}`; string(data) != want {
+		t.Errorf("MergePatch = %s, want %s", data, want)
+	}
+}
@@ -1,4 +1,8 @@
 // Package textutil provides text processing functions including sorting,
 // deduplication, and trimming of string slices. Sort supports functional
 // options for reverse, numeric, case-insensitive, and stable ordering.
+//
+// Replacement implements the right-hand side of a sed s command, with &,
+// \1..\9 and the \U, \L, \u, \l and \E case escapes, and TranslateBRE turns
+// a sed basic regex into Go syntax.
 package textutil
@@ -0,0 +1,269 @@
+package textutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Replacement is the parsed right-hand side of a sed s command. It
+// understands the escapes of GNU sed:
+//
+//	&        the whole match (\& is a literal ampersand)
+//	\0..\9   the whole match (\0) or a capture group
+//	\U \L    upper- or lower-case the rest of the replacement, until \E
+//	\u \l    upper- or lower-case the next character only
+//	\E       stop a \U or \L conversion
+//	\n \t    newline and tab
+//
+// Any other escaped character stands for itself, so \\ is a backslash and
+// \/ the delimiter. A "$" has no special meaning.
+type Replacement struct {
+	parts    []replPart
+	maxGroup int
+}
+
+type replKind int
+
+const (
+	replLiteral replKind = iota
+	replGroup
+	replCase
+)
+
+type replPart struct {
+	kind  replKind
+	text  string // literal text
+	group int    // capture group, 0 for the whole match
+	conv  byte   // case escape: 'U', 'L', 'u', 'l' or 'E'
+}
+
+// ParseReplacement parses a sed replacement string.
+func ParseReplacement(s string) (*Replacement, error) {
+	r := &Replacement{}
+
+	var lit strings.Builder
+
+	flush := func() {
+		if lit.Len() > 0 {
+			r.parts = append(r.parts, replPart{kind: replLiteral, text: lit.String()})
+			lit.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '&':
+			flush()
+			r.parts = append(r.parts, replPart{kind: replGroup})
+		case c != '\\':
+			lit.WriteByte(c)
+		case i+1 == len(s):
+			return nil, fmt.Errorf("trailing backslash in replacement %q", s)
+		default:
+			i++
+
+			switch e := s[i]; {
+			case e >= '0' && e <= '9':
+				flush()
+
+				n := int(e - '0')
+				r.parts = append(r.parts, replPart{kind: replGroup, group: n})
+				r.maxGroup = max(r.maxGroup, n)
+			case strings.IndexByte("ULulE", e) >= 0:
+				flush()
+				r.parts = append(r.parts, replPart{kind: replCase, conv: e})
+			case e == 'n':
+				lit.WriteByte('\n')
+			case e == 't':
+				lit.WriteByte('\t')
+			default:
+				lit.WriteByte(e)
+			}
+		}
+	}
+
+	flush()
+
+	return r, nil
+}
+
+// Check reports an error when the replacement refers to a capture group
+// that re does not have.
+func (r *Replacement) Check(re *regexp.Regexp) error {
+	if r.maxGroup > re.NumSubexp() {
+		return fmt.Errorf("invalid reference \\%d on s command's RHS", r.maxGroup)
+	}
+
+	return nil
+}
+
+// Expand returns the replacement for one match of src, where match holds
+// the submatch indexes as returned by FindStringSubmatchIndex. A group that
+// did not take part in the match expands to nothing.
+func (r *Replacement) Expand(src string, match []int) string {
+	var (
+		b    strings.Builder
+		mode byte // 'U', 'L' or 0
+		next byte // 'u', 'l' or 0, applied to the next character written
+	)
+
+	write := func(s string) {
+		if mode == 0 && next == 0 {
+			b.WriteString(s)
+			return
+		}
+
+		for _, c := range s {
+			switch {
+			case next == 'u':
+				c, next = unicode.ToUpper(c), 0
+			case next == 'l':
+				c, next = unicode.ToLower(c), 0
+			case mode == 'U':
+				c = unicode.ToUpper(c)
+			case mode == 'L':
+				c = unicode.ToLower(c)
+			}
+
+			b.WriteRune(c)
+		}
+	}
+
+	for _, p := range r.parts {
+		switch p.kind {
+		case replLiteral:
+			write(p.text)
+		case replGroup:
+			if 2*p.group+1 < len(match) && match[2*p.group] >= 0 {
+				write(src[match[2*p.group]:match[2*p.group+1]])
+			}
+		case replCase:
+			switch p.conv {
+			case 'u', 'l':
+				next = p.conv
+			case 'E':
+				mode, next = 0, 0
+			default:
+				mode = p.conv
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// ReplaceAll replaces every match of re in s.
+func (r *Replacement) ReplaceAll(re *regexp.Regexp, s string) string {
+	return r.replace(re, s, 0)
+}
+
+// ReplaceNth replaces only the nth match of re in s, counting from 1.
+func (r *Replacement) ReplaceNth(re *regexp.Regexp, s string, n int) string {
+	if n < 1 {
+		return s
+	}
+
+	return r.replace(re, s, n)
+}
+
+// replace substitutes the nth match, or every match when n is 0. Matches
+// are found on the whole line, so anchors and group references see the
+// same text sed would.
+func (r *Replacement) replace(re *regexp.Regexp, s string, n int) string {
+	var (
+		b        strings.Builder
+		last     int
+		replaced bool
+	)
+
+	for i, m := range re.FindAllStringSubmatchIndex(s, -1) {
+		if n > 0 && i+1 != n {
+			continue
+		}
+
+		b.WriteString(s[last:m[0]])
+		b.WriteString(r.Expand(s, m))
+		last = m[1]
+		replaced = true
+
+		if n > 0 {
+			break
+		}
+	}
+
+	if !replaced {
+		return s
+	}
+
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// TranslateBRE rewrites the escaped operators of a POSIX basic regular
+// expression into Go syntax: \( \) \{ \} \| \+ and \? become the operators
+// they stand for in GNU sed. Other text, including bracket expressions, is
+// copied as is, so the unescaped operators keep working too.
+func TranslateBRE(pattern string) string {
+	var b strings.Builder
+
+	inBracket := false
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case inBracket && c == '[' && i+1 < len(pattern) && pattern[i+1] == ':':
+			// A character class such as [:alpha:] ends at its own ":]".
+			end := strings.Index(pattern[i+2:], ":]")
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteString(pattern[i : i+2+end+2])
+			i += 2 + end + 1
+		case inBracket:
+			b.WriteByte(c)
+
+			if c == ']' {
+				inBracket = false
+			}
+		case c == '[':
+			b.WriteByte(c)
+
+			inBracket = true
+
+			// A ] right after [ or [^ is a member, not the end.
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+				b.WriteByte('^')
+			}
+
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+				b.WriteByte(']')
+			}
+		case c == '\\' && i+1 < len(pattern):
+			i++
+
+			if strings.IndexByte("(){}|+?", pattern[i]) >= 0 {
+				b.WriteByte(pattern[i])
+			} else {
+				b.WriteByte('\\')
+				_, size := utf8.DecodeRuneInString(pattern[i:])
+				b.WriteString(pattern[i : i+size])
+				i += size - 1
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package textutil
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestReplacement(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		repl    string
+		in      string
+		want    string
+	}{
+		{"literal", `o`, `0`, "foo", "f00"},
+		{"whole match", `o+`, `[&]`, "foo", "f[oo]"},
+		{"escaped ampersand", `o`, `\&`, "fo", "f&"},
+		{"group zero", `o+`, `<\0>`, "foo", "f<oo>"},
+		{"swap groups", `(\w+) (\w+)`, `\2 \1`, "hello world", "world hello"},
+		{"unmatched group", `(a)|(b)`, `[\1\2]`, "b", "[b]"},
+		{"upper rest", `\w+`, `\U&`, "abc def", "ABC DEF"},
+		{"lower rest", `\w+`, `\L&`, "ABC", "abc"},
+		{"upper next", `\w+`, `\u&`, "abc def", "Abc Def"},
+		{"lower next", `\w+`, `\l&`, "ABC", "aBC"},
+		{"end conversion", `(\w+) (\w+)`, `\U\1\E \2`, "ab cd", "AB cd"},
+		{"next within mode", `\w+`, `\L\u&`, "hELLO", "Hello"},
+		{"next skips empty group", `(x?)(\w+)`, `\u\1\2`, "abc", "Abc"},
+		{"newline and tab", `,`, `\n\t`, "a,b", "a\n\tb"},
+		{"backslash and dollar", `b`, `\\$1`, "abc", `a\$1c`},
+		{"unicode", `\pL+`, `\U&`, "ärger", "ÄRGER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := regexp.MustCompile(tt.pattern)
+
+			r, err := ParseReplacement(tt.repl)
+			if err != nil {
+				t.Fatalf("ParseReplacement(%q) error = %v", tt.repl, err)
+			}
+
+			if err := r.Check(re); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if got := r.ReplaceAll(re, tt.in); got != tt.want {
+				t.Errorf("ReplaceAll(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceNth(t *testing.T) {
+	re := regexp.MustCompile(`a`)
+
+	r, _ := ParseReplacement(`<&>`)
+
+	for n, want := range map[int]string{0: "aaa", 1: "<a>aa", 2: "a<a>a", 3: "aa<a>", 4: "aaa"} {
+		if got := r.ReplaceNth(re, "aaa", n); got != want {
+			t.Errorf("ReplaceNth(%d) = %q, want %q", n, got, want)
+		}
+	}
+
+	// The match is found on the whole line, so anchors keep their meaning.
+	anchored := regexp.MustCompile(`^a`)
+	if got := r.ReplaceNth(anchored, "aa", 1); got != "<a>a" {
+		t.Errorf("ReplaceNth(^a) = %q", got)
+	}
+
+	empty, _ := ParseReplacement("")
+	if got := empty.ReplaceNth(re, "abc", 1); got != "bc" {
+		t.Errorf("ReplaceNth() with empty replacement = %q, want %q", got, "bc")
+	}
+}
+
+func TestReplacementErrors(t *testing.T) {
+	if _, err := ParseReplacement(`abc\`); err == nil {
+		t.Error("ParseReplacement() with a trailing backslash error = nil")
+	}
+
+	r, _ := ParseReplacement(`\2`)
+	if err := r.Check(regexp.MustCompile(`(a)`)); err == nil {
+		t.Error("Check() with a missing group error = nil")
+	}
+}
+
+func TestTranslateBRE(t *testing.T) {
+	tests := map[string]string{
+		`\(ab\)*`:        `(ab)*`,
+		`a\{2,3\}`:       `a{2,3}`,
+		`a\|b`:           `a|b`,
+		`a\+b\?`:         `a+b?`,
+		`(a)+`:           `(a)+`,
+		`\.\w\\`:         `\.\w\\`,
+		`[\(]\(x\)`:      `[\(](x)`,
+		`[]\(]`:          `[]\(]`,
+		`[^]a]\(b\)`:     `[^]a](b)`,
+		`[[:alpha:]\(]x`: `[[:alpha:]\(]x`,
+		`ü\(x\)`:         `ü(x)`,
+	}
+
+	for in, want := range tests {
+		if got := TranslateBRE(in); got != want {
+			t.Errorf("TranslateBRE(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
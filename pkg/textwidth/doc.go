@@ -0,0 +1,7 @@
+// Package textwidth measures the terminal display width of Unicode text.
+// East Asian wide and fullwidth characters (CJK, most emoji) take two
+// columns; combining marks, format characters and the parts of an emoji
+// ZWJ sequence after the first take none. It also splits text into display
+// clusters and pads or truncates strings to a column width, so tables and
+// column math stay aligned on CJK and emoji content.
+package textwidth
@@ -0,0 +1,167 @@
+package textwidth
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+const (
+	zwj  = '\u200d' // zero width joiner
+	vs16 = '\ufe0f' // variation selector-16, emoji presentation
+)
+
+// RuneWidth returns the number of columns r occupies on its own: 0 for
+// control characters, combining marks and format characters, 2 for East
+// Asian wide and fullwidth characters, 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), isModifier(r):
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// String returns the display width of s.
+func String(s string) int {
+	var (
+		st State
+		n  int
+	)
+
+	for _, r := range s {
+		n += st.Next(r)
+	}
+
+	return n
+}
+
+// State measures a rune stream incrementally, for callers that cannot hold
+// a whole line. The zero value is ready to use; Reset it at line breaks.
+type State struct {
+	prev      rune
+	baseWidth int
+}
+
+// Next returns the columns r adds after the runes already seen. A rune
+// joined to the previous one by ZWJ adds nothing, and VS16 widens a
+// preceding narrow symbol to its two-column emoji presentation.
+func (s *State) Next(r rune) int {
+	defer func() { s.prev = r }()
+
+	switch {
+	case s.prev == zwj:
+		return 0
+	case r == vs16:
+		if s.baseWidth == 1 {
+			s.baseWidth = 2
+			return 1
+		}
+
+		return 0
+	}
+
+	w := RuneWidth(r)
+	if w > 0 {
+		s.baseWidth = w
+	}
+
+	return w
+}
+
+// Reset forgets the runes seen so far.
+func (s *State) Reset() {
+	*s = State{}
+}
+
+// Clusters splits s into display clusters: a base rune together with the
+// combining marks, variation selectors, skin-tone modifiers and ZWJ-joined
+// runes that follow it, and regional indicator pairs (flags). Cutting text
+// at cluster boundaries never separates an accent from its letter or
+// splits an emoji.
+func Clusters(s string) []string {
+	var (
+		clusters []string
+		start    int
+		prev     rune = -1
+		pairedRI bool
+	)
+
+	for i, r := range s {
+		joined := prev == zwj || extends(r) ||
+			(isRegional(prev) && isRegional(r) && !pairedRI)
+
+		if i > 0 && !joined {
+			clusters = append(clusters, s[start:i])
+			start = i
+		}
+
+		pairedRI = joined && isRegional(r)
+		prev = r
+	}
+
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+
+	return clusters
+}
+
+// Truncate returns the longest prefix of s, cut at a cluster boundary,
+// that fits in w columns.
+func Truncate(s string, w int) string {
+	if String(s) <= w {
+		return s
+	}
+
+	var (
+		b strings.Builder
+		n int
+	)
+
+	for _, c := range Clusters(s) {
+		cw := String(c)
+		if n+cw > w {
+			break
+		}
+
+		b.WriteString(c)
+
+		n += cw
+	}
+
+	return b.String()
+}
+
+// PadRight left-aligns s in w columns, appending spaces.
+func PadRight(s string, w int) string {
+	return s + strings.Repeat(" ", max(w-String(s), 0))
+}
+
+// PadLeft right-aligns s in w columns, prepending spaces.
+func PadLeft(s string, w int) string {
+	return strings.Repeat(" ", max(w-String(s), 0)) + s
+}
+
+// extends reports whether r attaches to the preceding rune.
+func extends(r rune) bool {
+	return r == zwj || isModifier(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Variation_Selector)
+}
+
+// isModifier reports whether r is an emoji skin-tone modifier.
+func isModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
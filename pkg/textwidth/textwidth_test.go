@@ -0,0 +1,94 @@
+package textwidth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"日本語", 6},
+		{"ｆｕｌｌ", 8},
+		{"café", 4},
+		{"cafe\u0301", 4}, // combining acute
+		{"😀", 2},
+		{"👍🏽", 2},              // skin-tone modifier
+		{"👨\u200d👩\u200d👧", 2}, // ZWJ family
+		{"❤\ufe0f", 2},         // VS16 emoji presentation
+		{"❤", 1},
+		{"🇧🇷", 2},     // flag
+		{"a\x1bb", 2}, // control character
+		{"한국어 text", 11},
+	}
+	for _, tc := range tests {
+		if got := String(tc.in); got != tc.want {
+			t.Errorf("String(%q) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"abc", []string{"a", "b", "c"}},
+		{"e\u0301x", []string{"e\u0301", "x"}},
+		{"a👨\u200d👩\u200d👧b", []string{"a", "👨\u200d👩\u200d👧", "b"}},
+		{"👍🏽!", []string{"👍🏽", "!"}},
+		{"🇧🇷🇵🇹", []string{"🇧🇷", "🇵🇹"}},
+		{"日本", []string{"日", "本"}},
+	}
+	for _, tc := range tests {
+		if got := Clusters(tc.in); !slices.Equal(got, tc.want) {
+			t.Errorf("Clusters(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"日本語", 5, "日本"},
+		{"日本語", 1, ""},
+		{"cafe\u0301s", 4, "cafe\u0301"},
+		{"a😀b", 2, "a"},
+	}
+	for _, tc := range tests {
+		if got := Truncate(tc.in, tc.w); got != tc.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tc.in, tc.w, got, tc.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got := PadRight("日本", 6); got != "日本  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadLeft("😀", 4); got != "  😀" {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := PadRight("toolong", 3); got != "toolong" {
+		t.Errorf("PadRight overflow = %q", got)
+	}
+}
+
+func TestStateReset(t *testing.T) {
+	var st State
+	st.Next('\u200d')
+	st.Reset()
+	if got := st.Next('日'); got != 2 {
+		t.Errorf("Next after Reset = %d, want 2", got)
+	}
+}
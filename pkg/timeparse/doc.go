@@ -0,0 +1,21 @@
+// Package timeparse reads the durations and wall-clock times people type
+// on a command line, and writes durations back in the same style.
+//
+// ParseDuration accepts Go duration syntax ("1h30m", "250ms") extended with
+// days and weeks ("1d12h", "2w"), spelled-out units ("1 hour 30 minutes"),
+// spaces between parts and a bare number in a caller-chosen unit, so
+// "sleep 90" and "sleep 1h30m" both work.
+//
+// ParseTime accepts clock times ("14:30", "2:30pm", "noon"), dates
+// ("2026-10-20 09:00", RFC 3339), "today" and "tomorrow" prefixes,
+// offsets from now ("+20m", "in 2h") and an optional trailing time zone
+// ("14:30 UTC", "9am America/New_York"). A clock time without a date
+// means its next occurrence, so "until 08:00" at 23:00 waits for the
+// morning.
+//
+// FormatDuration prints a duration compactly, to the second above one
+// minute ("1h29m5s", "2d3h0m0s"), for progress and countdown displays.
+//
+// Experimental: this package's API may change before a stable release and is
+// not covered by the v1.0 compatibility guarantee.
+package timeparse
@@ -0,0 +1,327 @@
+package timeparse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Day and Week are the units ParseDuration adds to Go's.
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// ErrSyntax is wrapped by every parse error.
+var ErrSyntax = errors.New("invalid syntax")
+
+// units maps every accepted unit spelling to its length.
+var units = map[string]time.Duration{
+	"ns": time.Nanosecond, "nanosecond": time.Nanosecond, "nanoseconds": time.Nanosecond,
+	"us": time.Microsecond, "µs": time.Microsecond, "μs": time.Microsecond,
+	"microsecond": time.Microsecond, "microseconds": time.Microsecond,
+	"ms": time.Millisecond, "millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": Day, "day": Day, "days": Day,
+	"w": Week, "wk": Week, "week": Week, "weeks": Week,
+}
+
+// ParseDuration parses a human duration. A number without a unit is in
+// defaultUnit; when defaultUnit is 0 a unit is required. Parts may be
+// separated by spaces or "and", and a leading "-" negates the whole
+// duration. Fractions are allowed in every part ("1.5h").
+func ParseDuration(s string, defaultUnit time.Duration) (time.Duration, error) {
+	in := strings.TrimSpace(s)
+	if in == "" {
+		return 0, fmt.Errorf("timeparse: empty duration: %w", ErrSyntax)
+	}
+
+	neg := false
+
+	switch in[0] {
+	case '-':
+		neg, in = true, in[1:]
+	case '+':
+		in = in[1:]
+	}
+
+	var (
+		total float64
+		parts int
+	)
+
+	rest := strings.ToLower(in)
+
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		rest = strings.TrimPrefix(rest, ",")
+
+		if after, ok := strings.CutPrefix(rest, "and "); ok && parts > 0 {
+			rest = after
+		}
+
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			break
+		}
+
+		n := 0
+		for n < len(rest) && (rest[n] >= '0' && rest[n] <= '9' || rest[n] == '.') {
+			n++
+		}
+
+		if n == 0 {
+			return 0, fmt.Errorf("timeparse: invalid duration %q: %w", s, ErrSyntax)
+		}
+
+		value, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			return 0, fmt.Errorf("timeparse: invalid duration %q: %w", s, ErrSyntax)
+		}
+
+		rest = strings.TrimLeftFunc(rest[n:], unicode.IsSpace)
+
+		// Units are ASCII letters, or µ/μ, which are not ASCII
+		u := 0
+		for u < len(rest) && (rest[u] >= 'a' && rest[u] <= 'z' || rest[u] >= 0x80) {
+			u++
+		}
+
+		unit := defaultUnit
+
+		if u > 0 {
+			var ok bool
+			if unit, ok = units[rest[:u]]; !ok {
+				return 0, fmt.Errorf("timeparse: unknown unit %q in duration %q: %w", rest[:u], s, ErrSyntax)
+			}
+		} else if unit == 0 || parts > 0 {
+			return 0, fmt.Errorf("timeparse: missing unit in duration %q: %w", s, ErrSyntax)
+		}
+
+		rest = rest[u:]
+		total += value * float64(unit)
+		parts++
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("timeparse: duration %q out of range: %w", s, ErrSyntax)
+	}
+
+	d := time.Duration(math.Round(total))
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// FormatDuration formats d like time.Duration.String, but with days for
+// durations of a day or more and rounded to the second from one minute
+// up.
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + FormatDuration(-d)
+	}
+
+	if d < time.Minute {
+		return d.String()
+	}
+
+	d = d.Round(time.Second)
+	if d < Day {
+		return d.String()
+	}
+
+	days := d / Day
+
+	return fmt.Sprintf("%dd%s", days, (d - days*Day).String())
+}
+
+// clockLayouts are the accepted time-of-day forms, after normalization to
+// lower case without spaces or dots.
+var clockLayouts = []string{"15:04:05", "15:04", "3:04:05pm", "3:04pm", "3pm", "1504"}
+
+// dateLayouts are the accepted date and date-time forms.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses a point in time relative to now. Without a time zone
+// the time is in now's location. A clock time without a date is its next
+// occurrence after now (today or tomorrow); "today" and "tomorrow" pin
+// the day, and a bare "today" or "tomorrow" means its midnight. "+D" and
+// "in D" are now plus the duration D.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	in := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if in == "" {
+		return time.Time{}, fmt.Errorf("timeparse: empty time: %w", ErrSyntax)
+	}
+
+	if after, ok := strings.CutPrefix(in, "in "); ok {
+		in = "+" + after
+	}
+
+	if strings.HasPrefix(in, "+") {
+		d, err := ParseDuration(in[1:], 0)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("timeparse: invalid time %q: %w", s, ErrSyntax)
+		}
+
+		return now.Add(d), nil
+	}
+
+	if in == "now" {
+		return now, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, strings.ToUpper(strings.TrimSpace(s))); err == nil {
+		return t, nil
+	}
+
+	in, loc, err := cutZone(in, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timeparse: invalid time %q: %w", s, err)
+	}
+
+	now = now.In(loc)
+
+	// Day words
+	day, explicitDay := now, false
+
+	for _, w := range []struct {
+		word string
+		add  int
+	}{{"today", 0}, {"tomorrow", 1}} {
+		if in == w.word {
+			y, m, d := now.AddDate(0, 0, w.add).Date()
+
+			return time.Date(y, m, d, 0, 0, 0, 0, loc), nil
+		}
+
+		if after, ok := strings.CutPrefix(in, w.word+" "); ok {
+			in, day, explicitDay = strings.TrimPrefix(after, "at "), now.AddDate(0, 0, w.add), true
+		}
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(in), loc); err == nil && !explicitDay {
+			return t, nil
+		}
+	}
+
+	h, m, sec, ok := parseClock(in)
+	if !ok {
+		return time.Time{}, fmt.Errorf("timeparse: invalid time %q: %w", s, ErrSyntax)
+	}
+
+	y, mo, d := day.Date()
+	t := time.Date(y, mo, d, h, m, sec, 0, loc)
+
+	if !explicitDay && !t.After(now) {
+		t = time.Date(y, mo, d+1, h, m, sec, 0, loc)
+	}
+
+	return t, nil
+}
+
+// parseClock parses a time of day.
+func parseClock(s string) (hour, minute, second int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, 0, true
+	case "midnight":
+		return 0, 0, 0, true
+	}
+
+	// "2:30 p.m." becomes "2:30pm"
+	s = strings.NewReplacer(" ", "", ".", "").Replace(s)
+
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Hour(), t.Minute(), t.Second(), true
+		}
+	}
+
+	return 0, 0, 0, false
+}
+
+// cutZone removes a trailing time zone from s: UTC, Z, a numeric offset or
+// an IANA name. It returns def when there is none.
+func cutZone(s string, def *time.Location) (string, *time.Location, error) {
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		if before, ok := strings.CutSuffix(s, "z"); ok && strings.Contains(before, ":") {
+			return before, time.UTC, nil
+		}
+
+		return s, def, nil
+	}
+
+	head, zone := s[:i], s[i+1:]
+
+	switch {
+	case zone == "utc" || zone == "gmt" || zone == "z":
+		return head, time.UTC, nil
+	case len(zone) >= 3 && (zone[0] == '+' || zone[0] == '-') && zone[1] >= '0' && zone[1] <= '9':
+		for _, layout := range []string{"-07:00", "-0700", "-07"} {
+			if t, err := time.Parse(layout, zone); err == nil {
+				_, offset := t.Zone()
+
+				return head, time.FixedZone(strings.ToUpper(zone), offset), nil
+			}
+		}
+
+		return "", nil, fmt.Errorf("invalid offset %q: %w", zone, ErrSyntax)
+	case strings.Contains(zone, "/"):
+		loc, err := loadLocation(zone)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return head, loc, nil
+	}
+
+	return s, def, nil
+}
+
+// loadLocation loads an IANA zone, whose name is case-sensitive, from a
+// lower-cased name by trying the usual capitalizations.
+func loadLocation(name string) (*time.Location, error) {
+	candidates := []string{name, titleZone(name), strings.ToUpper(name)}
+
+	for _, c := range candidates {
+		if loc, err := time.LoadLocation(c); err == nil {
+			return loc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown time zone %q: %w", name, ErrSyntax)
+}
+
+// titleZone capitalizes each word of a zone name: "america/new_york"
+// becomes "America/New_York".
+func titleZone(name string) string {
+	b := []byte(name)
+	start := true
+
+	for i, c := range b {
+		if start && c >= 'a' && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+
+		start = c == '/' || c == '_' || c == '-'
+	}
+
+	return string(b)
+}
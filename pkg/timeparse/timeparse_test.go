@@ -0,0 +1,144 @@
+package timeparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		unit    time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "90", unit: time.Second, want: 90 * time.Second},
+		{in: "0.5", unit: time.Second, want: 500 * time.Millisecond},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "1.5h", want: 90 * time.Minute},
+		{in: "250ms", want: 250 * time.Millisecond},
+		{in: "3µs", want: 3 * time.Microsecond},
+		{in: "1d12h", want: 36 * time.Hour},
+		{in: "2w", want: 14 * Day},
+		{in: "1h 30m", want: 90 * time.Minute},
+		{in: "1 hour and 30 minutes", want: 90 * time.Minute},
+		{in: "2 Days, 3 hrs", want: 51 * time.Hour},
+		{in: "-5s", want: -5 * time.Second},
+		{in: "+5s", want: 5 * time.Second},
+		{in: "90", wantErr: true},
+		{in: "1h30", unit: time.Second, wantErr: true},
+		{in: "5 fortnights", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "1..5s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in, tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrSyntax) {
+				t.Errorf("error %v does not wrap ErrSyntax", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{1500 * time.Millisecond, "1.5s"},
+		{90*time.Minute + 400*time.Millisecond, "1h30m0s"},
+		{51*time.Hour + 5*time.Second, "2d3h0m5s"},
+		{-90 * time.Second, "-1m30s"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.in); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*3600)
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, berlin)
+
+	at := func(day, hour, minute int, loc *time.Location) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "16:30", want: at(18, 16, 30, berlin)},
+		{in: "14:30", want: at(19, 14, 30, berlin)},
+		{in: "15:00", want: at(19, 15, 0, berlin)},
+		{in: "4pm", want: at(18, 16, 0, berlin)},
+		{in: "4:15 PM", want: at(18, 16, 15, berlin)},
+		{in: "9 a.m.", want: at(19, 9, 0, berlin)},
+		{in: "noon", want: at(19, 12, 0, berlin)},
+		{in: "midnight", want: at(19, 0, 0, berlin)},
+		{in: "16:30:10", want: time.Date(2026, 10, 18, 16, 30, 10, 0, berlin)},
+		{in: "today 9:00", want: at(18, 9, 0, berlin)},
+		{in: "tomorrow at 9am", want: at(19, 9, 0, berlin)},
+		{in: "tomorrow", want: at(19, 0, 0, berlin)},
+		{in: "2026-10-20 09:00", want: at(20, 9, 0, berlin)},
+		{in: "2026-10-20T09:00", want: at(20, 9, 0, berlin)},
+		{in: "2026-10-20", want: at(20, 0, 0, berlin)},
+		{in: "2026-10-20T09:00:00Z", want: at(20, 9, 0, time.UTC)},
+		{in: "14:30 UTC", want: at(18, 14, 30, time.UTC)},
+		{in: "12:00 utc", want: at(19, 12, 0, time.UTC)},
+		{in: "17:00 +03:00", want: at(18, 17, 0, time.FixedZone("", 3*3600))},
+		{in: "+20m", want: now.Add(20 * time.Minute)},
+		{in: "in 1h30m", want: now.Add(90 * time.Minute)},
+		{in: "now", want: now},
+		{in: "25:00", wantErr: true},
+		{in: "teatime", wantErr: true},
+		{in: "in a while", wantErr: true},
+		{in: "9:00 Mars/Olympus", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTime(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeZoneName(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skip("no time zone database")
+	}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	got, err := ParseTime("9am america/new_york", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Location().String() != "America/New_York" || got.Hour() != 9 || !got.After(now) {
+		t.Errorf("ParseTime() = %v", got)
+	}
+}
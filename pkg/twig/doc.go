@@ -1,4 +1,6 @@
 // Package twig provides directory tree scanning, formatting, and comparison
 // with support for parallel scanning, streaming output, and JSON snapshot
 // comparison for detecting added, removed, modified, and moved files.
+// The snapshot subpackage stores snapshots with retention rules and
+// reports drift from a baseline.
 package twig
@@ -0,0 +1,5 @@
+// Package snapshot stores timestamped JSON tree snapshots of directories,
+// prunes them with keep-last/daily/weekly retention rules, and reports
+// drift between the latest snapshot and a pinned baseline using the
+// comparer package.
+package snapshot
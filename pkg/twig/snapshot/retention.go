@@ -0,0 +1,75 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retention decides which snapshots to keep. Each rule keeps the newest
+// snapshot of its most recent periods: KeepDaily 7 keeps the newest
+// snapshot of each of the last 7 days that have one. A snapshot kept by
+// any rule is kept. The zero Retention keeps everything.
+type Retention struct {
+	KeepLast   int // Keep the N most recent snapshots
+	KeepDaily  int // Keep the newest snapshot of each of the last N days
+	KeepWeekly int // Keep the newest snapshot of each of the last N ISO weeks
+}
+
+// IsZero reports whether no rule is set.
+func (r Retention) IsZero() bool {
+	return r.KeepLast <= 0 && r.KeepDaily <= 0 && r.KeepWeekly <= 0
+}
+
+// Apply splits snaps (newest first, as List returns them) into those to
+// keep and those to remove. The newest snapshot is always kept. Days and
+// weeks are taken in the local time zone.
+func (r Retention) Apply(snaps []Snapshot) (keep, remove []Snapshot) {
+	if r.IsZero() {
+		return snaps, nil
+	}
+
+	daily := bucketRule{limit: r.KeepDaily, key: func(t time.Time) string { return t.Local().Format("2006-01-02") }}
+	weekly := bucketRule{limit: r.KeepWeekly, key: func(t time.Time) string {
+		y, w := t.Local().ISOWeek()
+		return fmt.Sprintf("%d-W%02d", y, w)
+	}}
+
+	for i, snap := range snaps {
+		// Every rule must see every snapshot, so evaluate them all
+		byDaily := daily.take(snap.Time)
+		byWeekly := weekly.take(snap.Time)
+
+		if i == 0 || i < r.KeepLast || byDaily || byWeekly {
+			keep = append(keep, snap)
+		} else {
+			remove = append(remove, snap)
+		}
+	}
+
+	return keep, remove
+}
+
+// bucketRule keeps the first (newest) snapshot of each new period until
+// limit periods have been kept.
+type bucketRule struct {
+	limit int
+	key   func(time.Time) string
+	last  string
+	kept  int
+}
+
+func (b *bucketRule) take(t time.Time) bool {
+	if b.limit <= 0 || b.kept >= b.limit {
+		return false
+	}
+
+	k := b.key(t)
+	if k == b.last {
+		return false
+	}
+
+	b.last = k
+	b.kept++
+
+	return true
+}
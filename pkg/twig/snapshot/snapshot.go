@@ -0,0 +1,298 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/inovacc/omni/pkg/twig"
+	"github.com/inovacc/omni/pkg/twig/comparer"
+	"github.com/inovacc/omni/pkg/twig/models"
+)
+
+// timeLayout names snapshot files; it sorts chronologically as a string.
+const timeLayout = "20060102T150405.000000000Z"
+
+const baselineFile = "baseline.json"
+
+// ErrNoBaseline is returned by Drift when no baseline has been set.
+var ErrNoBaseline = errors.New("no baseline snapshot")
+
+// Snapshot identifies one stored snapshot.
+type Snapshot struct {
+	Root string    `json:"root"`
+	Time time.Time `json:"time"`
+	Path string    `json:"path"`
+}
+
+// File is the on-disk snapshot format. It embeds the "tree" field of
+// models.JSONOutput, so snapshots can be compared with "omni tree
+// --compare" as well.
+type File struct {
+	Root string    `json:"root"`
+	Time time.Time `json:"time"`
+	models.JSONOutput
+}
+
+// Store keeps snapshots under a directory, one subdirectory per snapshot
+// root.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store rooted at dir; it is created on first write.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the store directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// rootDir returns the directory holding the snapshots of root. The name
+// keeps the base name readable and adds a hash of the absolute path so two
+// roots with the same base name do not collide.
+func (s *Store) rootDir(root string) (string, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(abs))
+
+	base := filepath.Base(abs)
+	if base == string(filepath.Separator) || base == "." || base == "" {
+		base = "root"
+	}
+
+	return filepath.Join(s.dir, base+"-"+hex.EncodeToString(sum[:6])), nil
+}
+
+// Take scans root (with file hashes, so modifications can be detected)
+// and stores the snapshot. Extra tree options, such as ignore patterns,
+// are applied after the defaults.
+func (s *Store) Take(ctx context.Context, root string, opts ...twig.TreeOption) (Snapshot, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	treeOpts := append([]twig.TreeOption{twig.WithShowHash(true), twig.WithShowHidden(true)}, opts...)
+
+	node, err := twig.NewTree(treeOpts...).Scan(ctx, abs)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	dir, err := s.rootDir(abs)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Snapshot{}, err
+	}
+
+	now := time.Now().UTC()
+	snap := Snapshot{Root: abs, Time: now, Path: filepath.Join(dir, now.Format(timeLayout)+".json")}
+
+	f := File{Root: abs, Time: now, JSONOutput: models.JSONOutput{
+		Tree:  node.ToJSON(),
+		Stats: models.CalculateStats(node).ToJSONStats(),
+	}}
+
+	if err := writeJSON(snap.Path, f); err != nil {
+		return Snapshot{}, err
+	}
+
+	return snap, nil
+}
+
+// List returns the snapshots of root, newest first. The baseline is not
+// included.
+func (s *Store) List(root string) ([]Snapshot, error) {
+	dir, err := s.rootDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	abs, _ := filepath.Abs(root)
+
+	var snaps []Snapshot
+
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+
+		t, err := time.Parse(timeLayout, name)
+		if err != nil {
+			continue // baseline.json and foreign files
+		}
+
+		snaps = append(snaps, Snapshot{Root: abs, Time: t, Path: filepath.Join(dir, e.Name())})
+	}
+
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Time.After(snaps[j].Time) })
+
+	return snaps, nil
+}
+
+// Load reads a snapshot file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if f.Tree == nil {
+		return nil, fmt.Errorf("%s: missing 'tree' field", path)
+	}
+
+	return &f, nil
+}
+
+// SetBaseline pins a copy of snap as the baseline of its root. Pruning
+// never removes the baseline.
+func (s *Store) SetBaseline(snap Snapshot) error {
+	data, err := os.ReadFile(snap.Path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := s.rootDir(snap.Root)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(dir, baselineFile), data)
+}
+
+// BaselinePath returns where the baseline of root is kept.
+func (s *Store) BaselinePath(root string) (string, error) {
+	dir, err := s.rootDir(root)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, baselineFile), nil
+}
+
+// Drift compares the baseline of root with its latest snapshot. It returns
+// ErrNoBaseline when no baseline was set and an error wrapping
+// os.ErrNotExist when there is no snapshot yet.
+func (s *Store) Drift(root string, cfg comparer.CompareConfig) (*comparer.CompareResult, error) {
+	basePath, err := s.BaselinePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := Load(basePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoBaseline
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	snaps, err := s.List(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("no snapshots of %s: %w", root, os.ErrNotExist)
+	}
+
+	latest, err := Load(snaps[0].Path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := comparer.Compare(base.Tree, latest.Tree, cfg)
+	result.LeftPath = basePath
+	result.RightPath = snaps[0].Path
+
+	return result, nil
+}
+
+// Prune deletes the snapshots of root that policy does not keep and
+// returns them.
+func (s *Store) Prune(root string, policy Retention) ([]Snapshot, error) {
+	snaps, err := s.List(root)
+	if err != nil {
+		return nil, err
+	}
+
+	_, remove := policy.Apply(snaps)
+
+	for _, snap := range remove {
+		if err := os.Remove(snap.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return remove, nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, append(data, '\n'))
+}
+
+// writeFile writes through a temporary file so a crash never leaves a
+// truncated snapshot behind.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
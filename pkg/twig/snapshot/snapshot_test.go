@@ -0,0 +1,133 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inovacc/omni/pkg/twig/comparer"
+)
+
+func TestRetentionApply(t *testing.T) {
+	// Hourly snapshots over 20 days, newest first
+	start := time.Date(2026, 3, 1, 0, 30, 0, 0, time.Local)
+
+	var snaps []Snapshot
+	for h := 20*24 - 1; h >= 0; h-- {
+		snaps = append(snaps, Snapshot{Time: start.Add(time.Duration(h) * time.Hour)})
+	}
+
+	tests := []struct {
+		name   string
+		policy Retention
+		want   int
+	}{
+		{"zero keeps all", Retention{}, len(snaps)},
+		{"last", Retention{KeepLast: 5}, 5},
+		{"daily", Retention{KeepDaily: 7}, 7},
+		{"daily beyond history", Retention{KeepDaily: 100}, 20},
+		// The 3 newest hourly snapshots share a day, so daily adds 6 more
+		{"last and daily overlap", Retention{KeepLast: 3, KeepDaily: 7}, 9},
+		{"weekly", Retention{KeepWeekly: 2}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, remove := tt.policy.Apply(snaps)
+			if len(keep) != tt.want || len(keep)+len(remove) != len(snaps) {
+				t.Fatalf("kept %d, removed %d; want %d kept", len(keep), len(remove), tt.want)
+			}
+
+			if !keep[0].Time.Equal(snaps[0].Time) {
+				t.Error("the newest snapshot must be kept")
+			}
+		})
+	}
+
+	// Daily keeps the newest snapshot of each day
+	keep, _ := Retention{KeepDaily: 2}.Apply(snaps)
+	if h := keep[1].Time.Hour(); h != 23 {
+		t.Errorf("second daily snapshot at %v, want the last hour of the previous day", keep[1].Time)
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store := NewStore(t.TempDir())
+
+	write := func(name, content string) {
+		t.Helper()
+
+		path := filepath.Join(root, name)
+		_ = os.MkdirAll(filepath.Dir(path), 0o755)
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("a.txt", "a")
+	write("dir/b.txt", "b")
+
+	first, err := store.Take(ctx, root)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+
+	if _, err := store.Drift(root, comparer.CompareConfig{}); !errors.Is(err, ErrNoBaseline) {
+		t.Fatalf("Drift() without baseline error = %v", err)
+	}
+
+	if err := store.SetBaseline(first); err != nil {
+		t.Fatal(err)
+	}
+
+	write("a.txt", "changed")
+	write("c.txt", "c")
+
+	for range 3 {
+		if _, err := store.Take(ctx, root); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snaps, err := store.List(root)
+	if err != nil || len(snaps) != 4 {
+		t.Fatalf("List() = %d snapshots, %v; want 4", len(snaps), err)
+	}
+
+	for i := 1; i < len(snaps); i++ {
+		if !snaps[i-1].Time.After(snaps[i].Time) {
+			t.Fatal("List() must return newest first")
+		}
+	}
+
+	drift, err := store.Drift(root, comparer.CompareConfig{})
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+
+	if drift.Summary.Added != 1 || drift.Summary.Modified != 1 || drift.Summary.Removed != 0 {
+		t.Errorf("drift summary = %+v, want 1 added and 1 modified", drift.Summary)
+	}
+
+	removed, err := store.Prune(root, Retention{KeepLast: 2})
+	if err != nil || len(removed) != 2 {
+		t.Fatalf("Prune() removed %d, %v; want 2", len(removed), err)
+	}
+
+	// The baseline survives pruning its source snapshot
+	if _, err := store.Drift(root, comparer.CompareConfig{}); err != nil {
+		t.Errorf("Drift() after prune error = %v", err)
+	}
+
+	// Snapshots of other roots are kept apart
+	other, _ := store.List(t.TempDir())
+	if len(other) != 0 {
+		t.Errorf("unrelated root has %d snapshots", len(other))
+	}
+}
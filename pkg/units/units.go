@@ -0,0 +1,448 @@
+// Package units converts numbers to and from their human-readable scaled
+// form ("1.5K", "3Mi", "12G") the way GNU numfmt does: SI scales by 1000,
+// IEC by 1024, and IEC-I additionally writes the "i" suffix. Converter
+// applies a conversion to selected fields of a delimited line, so commands
+// and pipeline stages post-process du/df-like output identically.
+//
+// Experimental: this package's API may change before a stable release and is
+// not covered by the v1.0 compatibility guarantee.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scale selects how suffixes are read and written.
+type Scale int
+
+const (
+	// None accepts and writes plain numbers only.
+	None Scale = iota
+	// SI uses powers of 1000: k, M, G, T, P, E, Z, Y.
+	SI
+	// IEC uses powers of 1024 with the same letters: K, M, G, ...
+	IEC
+	// IECI uses powers of 1024 with an "i" suffix: Ki, Mi, Gi, ...
+	IECI
+	// Auto reads "K" as 1000 and "Ki" as 1024. Valid for parsing only.
+	Auto
+)
+
+// ParseScale parses none, si, iec, iec-i or auto.
+func ParseScale(s string) (Scale, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return None, nil
+	case "si":
+		return SI, nil
+	case "iec":
+		return IEC, nil
+	case "iec-i":
+		return IECI, nil
+	case "auto":
+		return Auto, nil
+	}
+
+	return None, fmt.Errorf("units: invalid scale %q (want none, si, iec, iec-i or auto)", s)
+}
+
+// String returns the name accepted by ParseScale.
+func (s Scale) String() string {
+	switch s {
+	case SI:
+		return "si"
+	case IEC:
+		return "iec"
+	case IECI:
+		return "iec-i"
+	case Auto:
+		return "auto"
+	default:
+		return "none"
+	}
+}
+
+func (s Scale) base() float64 {
+	if s == SI {
+		return 1000
+	}
+
+	return 1024
+}
+
+// RoundMode selects how scaled values are rounded.
+type RoundMode int
+
+const (
+	// FromZero rounds away from zero (the numfmt default), so a size is
+	// never understated.
+	FromZero RoundMode = iota
+	// TowardsZero truncates.
+	TowardsZero
+	// Up rounds towards positive infinity.
+	Up
+	// Down rounds towards negative infinity.
+	Down
+	// Nearest rounds half away from zero.
+	Nearest
+)
+
+// ParseRoundMode parses up, down, from-zero, towards-zero or nearest.
+func ParseRoundMode(s string) (RoundMode, error) {
+	switch strings.ToLower(s) {
+	case "", "from-zero":
+		return FromZero, nil
+	case "towards-zero":
+		return TowardsZero, nil
+	case "up":
+		return Up, nil
+	case "down":
+		return Down, nil
+	case "nearest":
+		return Nearest, nil
+	}
+
+	return FromZero, fmt.Errorf("units: invalid rounding mode %q", s)
+}
+
+func (m RoundMode) round(v float64) float64 {
+	switch m {
+	case TowardsZero:
+		return math.Trunc(v)
+	case Up:
+		return math.Ceil(v)
+	case Down:
+		return math.Floor(v)
+	case Nearest:
+		return math.Round(v)
+	default:
+		if v < 0 {
+			return math.Floor(v)
+		}
+
+		return math.Ceil(v)
+	}
+}
+
+// suffixes are the power-of-base letters, index 0 being K (or k for SI).
+const suffixes = "KMGTPEZY"
+
+// ErrInvalidNumber is returned (wrapped) for input that is not a number in
+// the requested scale.
+var ErrInvalidNumber = errors.New("invalid number")
+
+// Parse reads a number with an optional scale suffix: Parse("1.5K", IEC) is
+// 1536. A suffix is rejected when scale is None.
+func Parse(s string, scale Scale) (float64, error) {
+	v, _, err := parse(s, scale)
+	return v, err
+}
+
+// parse also reports the number of fractional digits of the input, used to
+// echo unscaled numbers back at their original precision.
+func parse(s string, scale Scale) (float64, int, error) {
+	num := strings.TrimSpace(s)
+
+	end := len(num)
+	for end > 0 && unicode.IsLetter(rune(num[end-1])) {
+		end--
+	}
+
+	digits, suffix := num[:end], num[end:]
+
+	v, err := strconv.ParseFloat(digits, 64)
+	if err != nil || digits == "" || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+
+	prec := 0
+	if _, frac, ok := strings.Cut(digits, "."); ok {
+		prec = len(frac)
+	}
+
+	if suffix == "" {
+		return v, prec, nil
+	}
+
+	if scale == None {
+		return 0, 0, fmt.Errorf("%w: %q: suffix not allowed without a scale", ErrInvalidNumber, s)
+	}
+
+	letter, rest := strings.ToUpper(suffix[:1]), suffix[1:]
+
+	exp := strings.Index(suffixes, letter)
+	if exp < 0 || (rest != "" && rest != "i") {
+		return 0, 0, fmt.Errorf("%w: %q: unknown suffix %q", ErrInvalidNumber, s, suffix)
+	}
+
+	base := scale.base()
+
+	switch {
+	case scale == Auto && rest == "":
+		base = 1000
+	case scale == IECI && rest == "":
+		return 0, 0, fmt.Errorf("%w: %q: missing 'i' suffix for iec-i", ErrInvalidNumber, s)
+	case (scale == SI || scale == IEC) && rest != "":
+		return 0, 0, fmt.Errorf("%w: %q: unexpected 'i' suffix for %s", ErrInvalidNumber, s, scale)
+	}
+
+	return v * math.Pow(base, float64(exp+1)), 0, nil
+}
+
+// Format writes v in the given scale. Scaled values below 10 keep one
+// decimal ("1.5K"), larger ones are whole numbers ("15K"); with None, or
+// when v is below the base, v is rounded to a whole number. Auto formats
+// like None.
+func Format(v float64, scale Scale, round RoundMode) string {
+	return format(v, scale, round, 0)
+}
+
+func format(v float64, scale Scale, round RoundMode, prec int) string {
+	if scale == None || scale == Auto {
+		if prec > 0 {
+			p := math.Pow(10, float64(prec))
+			return strconv.FormatFloat(round.round(v*p)/p, 'f', prec, 64)
+		}
+
+		return strconv.FormatFloat(round.round(v), 'f', 0, 64)
+	}
+
+	base := scale.base()
+
+	exp := 0
+	for math.Abs(v) >= math.Pow(base, float64(exp+1)) && exp < len(suffixes) {
+		exp++
+	}
+
+	for {
+		x := v / math.Pow(base, float64(exp))
+
+		var r float64
+
+		decimals := 0
+		if exp > 0 && math.Abs(x) < 10 {
+			r = round.round(x*10) / 10
+			decimals = 1
+		} else {
+			r = round.round(x)
+		}
+
+		// 1023.9K rounds to 1024K: move up to 1.0M instead
+		if math.Abs(r) >= base && exp < len(suffixes) {
+			exp++
+			continue
+		}
+
+		out := strconv.FormatFloat(r, 'f', decimals, 64)
+		if exp == 0 {
+			return out
+		}
+
+		suffix := string(suffixes[exp-1])
+
+		switch scale {
+		case SI:
+			if suffix == "K" {
+				suffix = "k"
+			}
+		case IECI:
+			suffix += "i"
+		}
+
+		return out + suffix
+	}
+}
+
+// Converter converts one number from one representation to another.
+type Converter struct {
+	From     Scale     // scale of the input suffixes
+	To       Scale     // scale to write
+	FromUnit float64   // size of one input unit (0 means 1)
+	ToUnit   float64   // size of one output unit (0 means 1)
+	Round    RoundMode // rounding of the output
+	Suffix   string    // suffix stripped from the input and appended to the output
+	Padding  int       // pad the output to this width: > 0 right-aligns, < 0 left-aligns
+}
+
+// Validate reports an unusable configuration, such as writing to Auto.
+func (c Converter) Validate() error {
+	if c.To == Auto {
+		return errors.New("units: auto is only valid as an input scale")
+	}
+
+	if c.FromUnit < 0 || c.ToUnit < 0 {
+		return errors.New("units: unit size must be positive")
+	}
+
+	return nil
+}
+
+// Convert converts a single number. Surrounding whitespace is dropped.
+func (c Converter) Convert(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if c.Suffix != "" {
+		s = strings.TrimSuffix(s, c.Suffix)
+	}
+
+	v, prec, err := parse(s, c.From)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep the input's precision only when the value is passed through as is
+	scaled := c.From != None && s != "" && unicode.IsLetter(rune(s[len(s)-1]))
+	if scaled || c.To != None || (c.FromUnit != 0 && c.FromUnit != 1) || (c.ToUnit != 0 && c.ToUnit != 1) {
+		prec = 0
+	}
+
+	if c.FromUnit > 0 {
+		v *= c.FromUnit
+	}
+
+	if c.ToUnit > 0 {
+		v /= c.ToUnit
+	}
+
+	out := format(v, c.To, c.Round, prec) + c.Suffix
+
+	switch {
+	case c.Padding > 0:
+		out = fmt.Sprintf("%*s", c.Padding, out)
+	case c.Padding < 0:
+		out = fmt.Sprintf("%-*s", -c.Padding, out)
+	}
+
+	return out, nil
+}
+
+// ConvertFields converts the fields of line for which selected(n) is true,
+// n counting from 1, and leaves every other byte of the line untouched. With
+// an empty delim, fields are runs of non-blank characters. On error the line
+// is returned unchanged along with the first failure.
+func (c Converter) ConvertFields(line, delim string, selected func(n int) bool) (string, error) {
+	var (
+		b     strings.Builder
+		field int
+	)
+
+	convert := func(f string) error {
+		field++
+		if !selected(field) {
+			b.WriteString(f)
+			return nil
+		}
+
+		out, err := c.Convert(f)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString(out)
+
+		return nil
+	}
+
+	if delim != "" {
+		for i, f := range strings.Split(line, delim) {
+			if i > 0 {
+				b.WriteString(delim)
+			}
+
+			if err := convert(f); err != nil {
+				return line, err
+			}
+		}
+
+		return b.String(), nil
+	}
+
+	rest := line
+	for rest != "" {
+		start := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsSpace(r) })
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+
+		b.WriteString(rest[:start])
+		rest = rest[start:]
+
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+
+		if err := convert(rest[:end]); err != nil {
+			return line, err
+		}
+
+		rest = rest[end:]
+	}
+
+	return b.String(), nil
+}
+
+// ParseFields parses a field list such as "3", "1,3-5", "2-", "-2" or "-"
+// (all fields) into a predicate over 1-based field numbers, for use with
+// ConvertFields. An empty spec selects field 1.
+func ParseFields(spec string) (func(n int) bool, error) {
+	if spec == "" {
+		spec = "1"
+	}
+
+	if spec == "-" {
+		return func(int) bool { return true }, nil
+	}
+
+	type span struct{ lo, hi int }
+
+	var spans []span
+
+	for part := range strings.SplitSeq(spec, ",") {
+		bad := fmt.Errorf("units: invalid field value %q", part)
+
+		loStr, hiStr, isRange := strings.Cut(strings.TrimSpace(part), "-")
+
+		lo, hi := 1, math.MaxInt
+
+		if loStr != "" {
+			n, err := strconv.Atoi(loStr)
+			if err != nil || n < 1 {
+				return nil, bad
+			}
+
+			lo = n
+			if !isRange {
+				hi = n
+			}
+		} else if !isRange {
+			return nil, bad
+		}
+
+		if hiStr != "" {
+			n, err := strconv.Atoi(hiStr)
+			if err != nil || n < lo {
+				return nil, bad
+			}
+
+			hi = n
+		}
+
+		spans = append(spans, span{lo, hi})
+	}
+
+	return func(n int) bool {
+		for _, s := range spans {
+			if n >= s.lo && n <= s.hi {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
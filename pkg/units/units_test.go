@@ -0,0 +1,159 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale Scale
+		want  float64
+	}{
+		{"1536", None, 1536},
+		{"1.5K", IEC, 1536},
+		{"1.5k", SI, 1500},
+		{"2M", SI, 2e6},
+		{"1Ki", IECI, 1024},
+		{"1K", Auto, 1000},
+		{"1Ki", Auto, 1024},
+		{"-3G", IEC, -3 * 1 << 30},
+		{" 42 ", None, 42},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.scale)
+		if err != nil {
+			t.Errorf("Parse(%q, %s) error = %v", tt.in, tt.scale, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("Parse(%q, %s) = %v, want %v", tt.in, tt.scale, got, tt.want)
+		}
+	}
+
+	for _, bad := range []struct {
+		in    string
+		scale Scale
+	}{
+		{"1K", None},
+		{"1K", IECI},
+		{"1Ki", IEC},
+		{"1X", SI},
+		{"abc", None},
+		{"", SI},
+	} {
+		if _, err := Parse(bad.in, bad.scale); !errors.Is(err, ErrInvalidNumber) {
+			t.Errorf("Parse(%q, %s) error = %v, want ErrInvalidNumber", bad.in, bad.scale, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		v     float64
+		scale Scale
+		round RoundMode
+		want  string
+	}{
+		{512, IEC, FromZero, "512"},
+		{1024, IEC, FromZero, "1.0K"},
+		{1536, IEC, FromZero, "1.5K"},
+		{1025, IEC, FromZero, "1.1K"},
+		{1025, IEC, Nearest, "1.0K"},
+		{12345, SI, FromZero, "13k"},
+		{12345, SI, TowardsZero, "12k"},
+		{999999, SI, FromZero, "1.0M"},
+		{1 << 30, IECI, FromZero, "1.0Gi"},
+		{-1536, IEC, FromZero, "-1.5K"},
+		{1536.4, None, FromZero, "1537"},
+		{1536.4, None, Down, "1536"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.v, tt.scale, tt.round); got != tt.want {
+			t.Errorf("Format(%v, %s, %d) = %q, want %q", tt.v, tt.scale, tt.round, got, tt.want)
+		}
+	}
+}
+
+func TestConverter(t *testing.T) {
+	c := Converter{From: IEC, To: SI, Suffix: "B", Padding: 6}
+
+	got, err := c.Convert("1MB")
+	if err != nil || got != " 1.1MB" {
+		t.Errorf("Convert(1MB) = %q, %v", got, err)
+	}
+
+	plain := Converter{}
+	if got, _ := plain.Convert("3.25"); got != "3.25" {
+		t.Errorf("unscaled input should keep its precision, got %q", got)
+	}
+
+	units := Converter{FromUnit: 1024, To: IEC}
+	if got, _ := units.Convert("2048"); got != "2.0M" {
+		t.Errorf("Convert with --from-unit = %q, want 2.0M", got)
+	}
+
+	if err := (Converter{To: Auto}).Validate(); err == nil {
+		t.Error("Validate() should reject writing auto")
+	}
+}
+
+func TestConvertFields(t *testing.T) {
+	c := Converter{To: IEC}
+	third := func(n int) bool { return n == 3 }
+
+	got, err := c.ConvertFields("  /dev/sda1  ext4   1048576  /", "", third)
+	if err != nil || got != "  /dev/sda1  ext4   1.0M  /" {
+		t.Errorf("blank-separated: got %q, %v", got, err)
+	}
+
+	got, err = c.ConvertFields("a,b,2048,d", ",", third)
+	if err != nil || got != "a,b,2.0K,d" {
+		t.Errorf("comma-separated: got %q, %v", got, err)
+	}
+
+	line := "a b size"
+
+	got, err = c.ConvertFields(line, "", third)
+	if err == nil || got != line {
+		t.Errorf("invalid field: got %q, %v; want unchanged line and an error", got, err)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		spec string
+		in   []int
+		out  []int
+	}{
+		{"", []int{1}, []int{2}},
+		{"3", []int{3}, []int{2, 4}},
+		{"1,3-4", []int{1, 3, 4}, []int{2, 5}},
+		{"-2", []int{1, 2}, []int{3}},
+		{"4-", []int{4, 99}, []int{3}},
+		{"-", []int{1, 50}, nil},
+	}
+
+	for _, tt := range tests {
+		sel, err := ParseFields(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseFields(%q) error = %v", tt.spec, err)
+		}
+
+		for _, n := range tt.in {
+			if !sel(n) {
+				t.Errorf("ParseFields(%q) should select %d", tt.spec, n)
+			}
+		}
+
+		for _, n := range tt.out {
+			if sel(n) {
+				t.Errorf("ParseFields(%q) should not select %d", tt.spec, n)
+			}
+		}
+	}
+}